@@ -0,0 +1,1267 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: api/proto/calculator/v1/calculator.proto
+
+package calculatorv1
+
+import (
+	v1 "github.com/Vodeneev/vodeneevbet/api/proto/matches/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ListQuery pages, sorts and filters a list call, as the query of the HTTP list endpoints.
+type ListQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Page size; 0 = default (5 value bets, 10 line movements), at most 50.
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page; empty = first page.
+	Cursor string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// value_percent, expected_value or start_time for value bets; change_percent, prob_shift_pp or start_time for line movements.
+	Sort string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	// asc or desc; empty = the default of the sort key.
+	Order string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	// Sport, e.g. football; esports = every esports discipline.
+	Sport      string   `protobuf:"bytes,5,opt,name=sport,proto3" json:"sport,omitempty"`
+	Bookmakers []string `protobuf:"bytes,6,rep,name=bookmakers,proto3" json:"bookmakers,omitempty"`
+	// Tournament name substring.
+	League  string  `protobuf:"bytes,7,opt,name=league,proto3" json:"league,omitempty"`
+	MinOdds float64 `protobuf:"fixed64,8,opt,name=min_odds,json=minOdds,proto3" json:"min_odds,omitempty"`
+	// 0 = no limit.
+	MaxOdds float64 `protobuf:"fixed64,9,opt,name=max_odds,json=maxOdds,proto3" json:"max_odds,omitempty"`
+}
+
+func (x *ListQuery) Reset() {
+	*x = ListQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuery) ProtoMessage() {}
+
+func (x *ListQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuery.ProtoReflect.Descriptor instead.
+func (*ListQuery) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListQuery) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListQuery) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListQuery) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListQuery) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+func (x *ListQuery) GetBookmakers() []string {
+	if x != nil {
+		return x.Bookmakers
+	}
+	return nil
+}
+
+func (x *ListQuery) GetLeague() string {
+	if x != nil {
+		return x.League
+	}
+	return ""
+}
+
+func (x *ListQuery) GetMinOdds() float64 {
+	if x != nil {
+		return x.MinOdds
+	}
+	return 0
+}
+
+func (x *ListQuery) GetMaxOdds() float64 {
+	if x != nil {
+		return x.MaxOdds
+	}
+	return 0
+}
+
+type ListValueBetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *ListQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// live or upcoming; empty = all.
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Margin removal method; empty = value_calculator.fair_odds_method.
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+}
+
+func (x *ListValueBetsRequest) Reset() {
+	*x = ListValueBetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListValueBetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListValueBetsRequest) ProtoMessage() {}
+
+func (x *ListValueBetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListValueBetsRequest.ProtoReflect.Descriptor instead.
+func (*ListValueBetsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListValueBetsRequest) GetQuery() *ListQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ListValueBetsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListValueBetsRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type ListValueBetsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValueBets []*ValueBet `protobuf:"bytes,1,rep,name=value_bets,json=valueBets,proto3" json:"value_bets,omitempty"`
+	// Cursor of the next page; empty on the last one.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// Value bets passing the filters, all pages.
+	Total int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *ListValueBetsResponse) Reset() {
+	*x = ListValueBetsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListValueBetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListValueBetsResponse) ProtoMessage() {}
+
+func (x *ListValueBetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListValueBetsResponse.ProtoReflect.Descriptor instead.
+func (*ListValueBetsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListValueBetsResponse) GetValueBets() []*ValueBet {
+	if x != nil {
+		return x.ValueBets
+	}
+	return nil
+}
+
+func (x *ListValueBetsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListValueBetsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ListLineMovementsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *ListQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *ListLineMovementsRequest) Reset() {
+	*x = ListLineMovementsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLineMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLineMovementsRequest) ProtoMessage() {}
+
+func (x *ListLineMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLineMovementsRequest.ProtoReflect.Descriptor instead.
+func (*ListLineMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListLineMovementsRequest) GetQuery() *ListQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type ListLineMovementsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LineMovements []*LineMovement `protobuf:"bytes,1,rep,name=line_movements,json=lineMovements,proto3" json:"line_movements,omitempty"`
+	// Cursor of the next page; empty on the last one.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// Line movements passing the filters, all pages.
+	Total int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *ListLineMovementsResponse) Reset() {
+	*x = ListLineMovementsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLineMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLineMovementsResponse) ProtoMessage() {}
+
+func (x *ListLineMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLineMovementsResponse.ProtoReflect.Descriptor instead.
+func (*ListLineMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLineMovementsResponse) GetLineMovements() []*LineMovement {
+	if x != nil {
+		return x.LineMovements
+	}
+	return nil
+}
+
+func (x *ListLineMovementsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListLineMovementsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetMatchOddsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Match group key (sport|home|away|date), as match_group_key of value bets and line movements.
+	MatchGroupKey string `protobuf:"bytes,1,opt,name=match_group_key,json=matchGroupKey,proto3" json:"match_group_key,omitempty"`
+}
+
+func (x *GetMatchOddsRequest) Reset() {
+	*x = GetMatchOddsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchOddsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchOddsRequest) ProtoMessage() {}
+
+func (x *GetMatchOddsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchOddsRequest.ProtoReflect.Descriptor instead.
+func (*GetMatchOddsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMatchOddsRequest) GetMatchGroupKey() string {
+	if x != nil {
+		return x.MatchGroupKey
+	}
+	return ""
+}
+
+type GetMatchOddsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*v1.Match `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+}
+
+func (x *GetMatchOddsResponse) Reset() {
+	*x = GetMatchOddsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchOddsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchOddsResponse) ProtoMessage() {}
+
+func (x *GetMatchOddsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchOddsResponse.ProtoReflect.Descriptor instead.
+func (*GetMatchOddsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMatchOddsResponse) GetMatches() []*v1.Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+// ValueBet is a bookmaker odd above the fair odd of the weighted consensus.
+type ValueBet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Stable id of (match, bet, bookmaker), as for POST /bets.
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MatchGroupKey string                 `protobuf:"bytes,2,opt,name=match_group_key,json=matchGroupKey,proto3" json:"match_group_key,omitempty"`
+	MatchName     string                 `protobuf:"bytes,3,opt,name=match_name,json=matchName,proto3" json:"match_name,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Sport         string                 `protobuf:"bytes,5,opt,name=sport,proto3" json:"sport,omitempty"`
+	Tournament    string                 `protobuf:"bytes,6,opt,name=tournament,proto3" json:"tournament,omitempty"`
+	EventType     string                 `protobuf:"bytes,7,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	OutcomeType   string                 `protobuf:"bytes,8,opt,name=outcome_type,json=outcomeType,proto3" json:"outcome_type,omitempty"`
+	Parameter     string                 `protobuf:"bytes,9,opt,name=parameter,proto3" json:"parameter,omitempty"`
+	// event_type|outcome_type|parameter
+	BetKey string `protobuf:"bytes,10,opt,name=bet_key,json=betKey,proto3" json:"bet_key,omitempty"`
+	// Odds of every bookmaker quoting the outcome.
+	AllBookmakerOdds map[string]float64 `protobuf:"bytes,11,rep,name=all_bookmaker_odds,json=allBookmakerOdds,proto3" json:"all_bookmaker_odds,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	FairOdd          float64            `protobuf:"fixed64,12,opt,name=fair_odd,json=fairOdd,proto3" json:"fair_odd,omitempty"`
+	FairProbability  float64            `protobuf:"fixed64,13,opt,name=fair_probability,json=fairProbability,proto3" json:"fair_probability,omitempty"`
+	Bookmaker        string             `protobuf:"bytes,14,opt,name=bookmaker,proto3" json:"bookmaker,omitempty"`
+	// Event page at the bookmaker; empty = unknown.
+	BookmakerUrl string  `protobuf:"bytes,15,opt,name=bookmaker_url,json=bookmakerUrl,proto3" json:"bookmaker_url,omitempty"`
+	BookmakerOdd float64 `protobuf:"fixed64,16,opt,name=bookmaker_odd,json=bookmakerOdd,proto3" json:"bookmaker_odd,omitempty"`
+	// (bookmaker_odd / fair_odd - 1) * 100
+	ValuePercent float64 `protobuf:"fixed64,17,opt,name=value_percent,json=valuePercent,proto3" json:"value_percent,omitempty"`
+	// bookmaker_odd * fair_probability - 1
+	ExpectedValue float64 `protobuf:"fixed64,18,opt,name=expected_value,json=expectedValue,proto3" json:"expected_value,omitempty"`
+	// Recommended stakes in percent of bankroll (value_calculator.staking).
+	KellyPercent           float64                `protobuf:"fixed64,19,opt,name=kelly_percent,json=kellyPercent,proto3" json:"kelly_percent,omitempty"`
+	FractionalKellyPercent float64                `protobuf:"fixed64,20,opt,name=fractional_kelly_percent,json=fractionalKellyPercent,proto3" json:"fractional_kelly_percent,omitempty"`
+	FlatStakePercent       float64                `protobuf:"fixed64,21,opt,name=flat_stake_percent,json=flatStakePercent,proto3" json:"flat_stake_percent,omitempty"`
+	CalculatedAt           *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=calculated_at,json=calculatedAt,proto3" json:"calculated_at,omitempty"`
+}
+
+func (x *ValueBet) Reset() {
+	*x = ValueBet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValueBet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueBet) ProtoMessage() {}
+
+func (x *ValueBet) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueBet.ProtoReflect.Descriptor instead.
+func (*ValueBet) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValueBet) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ValueBet) GetMatchGroupKey() string {
+	if x != nil {
+		return x.MatchGroupKey
+	}
+	return ""
+}
+
+func (x *ValueBet) GetMatchName() string {
+	if x != nil {
+		return x.MatchName
+	}
+	return ""
+}
+
+func (x *ValueBet) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ValueBet) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+func (x *ValueBet) GetTournament() string {
+	if x != nil {
+		return x.Tournament
+	}
+	return ""
+}
+
+func (x *ValueBet) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *ValueBet) GetOutcomeType() string {
+	if x != nil {
+		return x.OutcomeType
+	}
+	return ""
+}
+
+func (x *ValueBet) GetParameter() string {
+	if x != nil {
+		return x.Parameter
+	}
+	return ""
+}
+
+func (x *ValueBet) GetBetKey() string {
+	if x != nil {
+		return x.BetKey
+	}
+	return ""
+}
+
+func (x *ValueBet) GetAllBookmakerOdds() map[string]float64 {
+	if x != nil {
+		return x.AllBookmakerOdds
+	}
+	return nil
+}
+
+func (x *ValueBet) GetFairOdd() float64 {
+	if x != nil {
+		return x.FairOdd
+	}
+	return 0
+}
+
+func (x *ValueBet) GetFairProbability() float64 {
+	if x != nil {
+		return x.FairProbability
+	}
+	return 0
+}
+
+func (x *ValueBet) GetBookmaker() string {
+	if x != nil {
+		return x.Bookmaker
+	}
+	return ""
+}
+
+func (x *ValueBet) GetBookmakerUrl() string {
+	if x != nil {
+		return x.BookmakerUrl
+	}
+	return ""
+}
+
+func (x *ValueBet) GetBookmakerOdd() float64 {
+	if x != nil {
+		return x.BookmakerOdd
+	}
+	return 0
+}
+
+func (x *ValueBet) GetValuePercent() float64 {
+	if x != nil {
+		return x.ValuePercent
+	}
+	return 0
+}
+
+func (x *ValueBet) GetExpectedValue() float64 {
+	if x != nil {
+		return x.ExpectedValue
+	}
+	return 0
+}
+
+func (x *ValueBet) GetKellyPercent() float64 {
+	if x != nil {
+		return x.KellyPercent
+	}
+	return 0
+}
+
+func (x *ValueBet) GetFractionalKellyPercent() float64 {
+	if x != nil {
+		return x.FractionalKellyPercent
+	}
+	return 0
+}
+
+func (x *ValueBet) GetFlatStakePercent() float64 {
+	if x != nil {
+		return x.FlatStakePercent
+	}
+	return 0
+}
+
+func (x *ValueBet) GetCalculatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CalculatedAt
+	}
+	return nil
+}
+
+// LineMovement is a significant odds change of one bet at one bookmaker.
+type LineMovement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MatchGroupKey string                 `protobuf:"bytes,1,opt,name=match_group_key,json=matchGroupKey,proto3" json:"match_group_key,omitempty"`
+	MatchName     string                 `protobuf:"bytes,2,opt,name=match_name,json=matchName,proto3" json:"match_name,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Sport         string                 `protobuf:"bytes,4,opt,name=sport,proto3" json:"sport,omitempty"`
+	Tournament    string                 `protobuf:"bytes,5,opt,name=tournament,proto3" json:"tournament,omitempty"`
+	EventType     string                 `protobuf:"bytes,6,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	OutcomeType   string                 `protobuf:"bytes,7,opt,name=outcome_type,json=outcomeType,proto3" json:"outcome_type,omitempty"`
+	Parameter     string                 `protobuf:"bytes,8,opt,name=parameter,proto3" json:"parameter,omitempty"`
+	BetKey        string                 `protobuf:"bytes,9,opt,name=bet_key,json=betKey,proto3" json:"bet_key,omitempty"`
+	Bookmaker     string                 `protobuf:"bytes,10,opt,name=bookmaker,proto3" json:"bookmaker,omitempty"`
+	// Event page at the bookmaker; empty = unknown.
+	BookmakerUrl string  `protobuf:"bytes,11,opt,name=bookmaker_url,json=bookmakerUrl,proto3" json:"bookmaker_url,omitempty"`
+	PreviousOdd  float64 `protobuf:"fixed64,12,opt,name=previous_odd,json=previousOdd,proto3" json:"previous_odd,omitempty"`
+	CurrentOdd   float64 `protobuf:"fixed64,13,opt,name=current_odd,json=currentOdd,proto3" json:"current_odd,omitempty"`
+	// current_odd - previous_odd
+	ChangeAbs float64 `protobuf:"fixed64,14,opt,name=change_abs,json=changeAbs,proto3" json:"change_abs,omitempty"`
+	// (current_odd - previous_odd) / previous_odd * 100
+	ChangePercent float64 `protobuf:"fixed64,15,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	// Implied probability change in percentage points: (1/current_odd - 1/previous_odd) * 100
+	ProbShiftPp float64                `protobuf:"fixed64,16,opt,name=prob_shift_pp,json=probShiftPp,proto3" json:"prob_shift_pp,omitempty"`
+	RecordedAt  *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+}
+
+func (x *LineMovement) Reset() {
+	*x = LineMovement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LineMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineMovement) ProtoMessage() {}
+
+func (x *LineMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_calculator_v1_calculator_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineMovement.ProtoReflect.Descriptor instead.
+func (*LineMovement) Descriptor() ([]byte, []int) {
+	return file_api_proto_calculator_v1_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LineMovement) GetMatchGroupKey() string {
+	if x != nil {
+		return x.MatchGroupKey
+	}
+	return ""
+}
+
+func (x *LineMovement) GetMatchName() string {
+	if x != nil {
+		return x.MatchName
+	}
+	return ""
+}
+
+func (x *LineMovement) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *LineMovement) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+func (x *LineMovement) GetTournament() string {
+	if x != nil {
+		return x.Tournament
+	}
+	return ""
+}
+
+func (x *LineMovement) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *LineMovement) GetOutcomeType() string {
+	if x != nil {
+		return x.OutcomeType
+	}
+	return ""
+}
+
+func (x *LineMovement) GetParameter() string {
+	if x != nil {
+		return x.Parameter
+	}
+	return ""
+}
+
+func (x *LineMovement) GetBetKey() string {
+	if x != nil {
+		return x.BetKey
+	}
+	return ""
+}
+
+func (x *LineMovement) GetBookmaker() string {
+	if x != nil {
+		return x.Bookmaker
+	}
+	return ""
+}
+
+func (x *LineMovement) GetBookmakerUrl() string {
+	if x != nil {
+		return x.BookmakerUrl
+	}
+	return ""
+}
+
+func (x *LineMovement) GetPreviousOdd() float64 {
+	if x != nil {
+		return x.PreviousOdd
+	}
+	return 0
+}
+
+func (x *LineMovement) GetCurrentOdd() float64 {
+	if x != nil {
+		return x.CurrentOdd
+	}
+	return 0
+}
+
+func (x *LineMovement) GetChangeAbs() float64 {
+	if x != nil {
+		return x.ChangeAbs
+	}
+	return 0
+}
+
+func (x *LineMovement) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *LineMovement) GetProbShiftPp() float64 {
+	if x != nil {
+		return x.ProbShiftPp
+	}
+	return 0
+}
+
+func (x *LineMovement) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+var File_api_proto_calculator_v1_calculator_proto protoreflect.FileDescriptor
+
+var file_api_proto_calculator_v1_calculator_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x6c, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x19, 0x76, 0x6f, 0x64, 0x65,
+	0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x22, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe7, 0x01, 0x0a, 0x09, 0x4c,
+	0x69, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61,
+	0x6b, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x6b,
+	0x6d, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x5f, 0x6f, 0x64, 0x64, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x07, 0x6d, 0x69, 0x6e, 0x4f, 0x64, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78,
+	0x5f, 0x6f, 0x64, 0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6d, 0x61, 0x78,
+	0x4f, 0x64, 0x64, 0x73, 0x22, 0x82, 0x01, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x42, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a,
+	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x76,
+	0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75,
+	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x92, 0x01, 0x0a, 0x15, 0x4c, 0x69,
+	0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x62, 0x65, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65,
+	0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x52, 0x09, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f,
+	0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65,
+	0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x56,
+	0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x05, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x76, 0x6f, 0x64, 0x65,
+	0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0xa2, 0x01, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0e, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x6d, 0x6f, 0x76,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x76,
+	0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75,
+	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0d, 0x6c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72,
+	0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x3d, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x64, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x22, 0x4f, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x64, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x37, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65,
+	0x74, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0xc1, 0x07, 0x0a, 0x08,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72,
+	0x12, 0x17, 0x0a, 0x07, 0x62, 0x65, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x62, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x67, 0x0a, 0x12, 0x61, 0x6c, 0x6c,
+	0x5f, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x6f, 0x64, 0x64, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76,
+	0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x2e, 0x41, 0x6c, 0x6c, 0x42, 0x6f,
+	0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x4f, 0x64, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x10, 0x61, 0x6c, 0x6c, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x4f, 0x64,
+	0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x72, 0x5f, 0x6f, 0x64, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x66, 0x61, 0x69, 0x72, 0x4f, 0x64, 0x64, 0x12, 0x29, 0x0a,
+	0x10, 0x66, 0x61, 0x69, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x66, 0x61, 0x69, 0x72, 0x50, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x6b,
+	0x6d, 0x61, 0x6b, 0x65, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6f, 0x6f,
+	0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61,
+	0x6b, 0x65, 0x72, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62,
+	0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x55, 0x72, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x62,
+	0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x6f, 0x64, 0x64, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0c, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x4f, 0x64, 0x64,
+	0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x65,
+	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x6b, 0x65, 0x6c, 0x6c, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0c, 0x6b, 0x65, 0x6c, 0x6c, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x12, 0x38, 0x0a, 0x18, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f,
+	0x6b, 0x65, 0x6c, 0x6c, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x14, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x16, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4b,
+	0x65, 0x6c, 0x6c, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x66,
+	0x6c, 0x61, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x66, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61,
+	0x6b, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x0d, 0x63, 0x61, 0x6c,
+	0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x63, 0x61,
+	0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x1a, 0x43, 0x0a, 0x15, 0x41, 0x6c,
+	0x6c, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x4f, 0x64, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xed, 0x04, 0x0a, 0x0c, 0x4c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x75, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f,
+	0x75, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x63, 0x6f,
+	0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x65, 0x74, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x65, 0x74, 0x4b, 0x65,
+	0x79, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x12,
+	0x23, 0x0a, 0x0d, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65,
+	0x72, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x5f, 0x6f, 0x64, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x76,
+	0x69, 0x6f, 0x75, 0x73, 0x4f, 0x64, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x6f, 0x64, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x4f, 0x64, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x5f, 0x61, 0x62, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x41, 0x62, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x22,
+	0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x62, 0x5f, 0x73, 0x68, 0x69, 0x66, 0x74, 0x5f, 0x70, 0x70, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x53, 0x68, 0x69, 0x66, 0x74,
+	0x50, 0x70, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x64, 0x41, 0x74, 0x32,
+	0xf8, 0x02, 0x0a, 0x11, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x72, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x42, 0x65, 0x74, 0x73, 0x12, 0x2f, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65,
+	0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65,
+	0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x65, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7e, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x4c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x33,
+	0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c,
+	0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65,
+	0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x0c, 0x47, 0x65, 0x74,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x64, 0x64, 0x73, 0x12, 0x2e, 0x2e, 0x76, 0x6f, 0x64, 0x65,
+	0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x64,
+	0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x76, 0x6f, 0x64, 0x65,
+	0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x64,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x46, 0x5a, 0x44, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x56, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65,
+	0x76, 0x2f, 0x76, 0x6f, 0x64, 0x65, 0x6e, 0x65, 0x65, 0x76, 0x62, 0x65, 0x74, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
+	0x6f, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
+	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_proto_calculator_v1_calculator_proto_rawDescOnce sync.Once
+	file_api_proto_calculator_v1_calculator_proto_rawDescData = file_api_proto_calculator_v1_calculator_proto_rawDesc
+)
+
+func file_api_proto_calculator_v1_calculator_proto_rawDescGZIP() []byte {
+	file_api_proto_calculator_v1_calculator_proto_rawDescOnce.Do(func() {
+		file_api_proto_calculator_v1_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_proto_calculator_v1_calculator_proto_rawDescData)
+	})
+	return file_api_proto_calculator_v1_calculator_proto_rawDescData
+}
+
+var file_api_proto_calculator_v1_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_proto_calculator_v1_calculator_proto_goTypes = []any{
+	(*ListQuery)(nil),                 // 0: vodeneevbet.calculator.v1.ListQuery
+	(*ListValueBetsRequest)(nil),      // 1: vodeneevbet.calculator.v1.ListValueBetsRequest
+	(*ListValueBetsResponse)(nil),     // 2: vodeneevbet.calculator.v1.ListValueBetsResponse
+	(*ListLineMovementsRequest)(nil),  // 3: vodeneevbet.calculator.v1.ListLineMovementsRequest
+	(*ListLineMovementsResponse)(nil), // 4: vodeneevbet.calculator.v1.ListLineMovementsResponse
+	(*GetMatchOddsRequest)(nil),       // 5: vodeneevbet.calculator.v1.GetMatchOddsRequest
+	(*GetMatchOddsResponse)(nil),      // 6: vodeneevbet.calculator.v1.GetMatchOddsResponse
+	(*ValueBet)(nil),                  // 7: vodeneevbet.calculator.v1.ValueBet
+	(*LineMovement)(nil),              // 8: vodeneevbet.calculator.v1.LineMovement
+	nil,                               // 9: vodeneevbet.calculator.v1.ValueBet.AllBookmakerOddsEntry
+	(*v1.Match)(nil),                  // 10: vodeneevbet.matches.v1.Match
+	(*timestamppb.Timestamp)(nil),     // 11: google.protobuf.Timestamp
+}
+var file_api_proto_calculator_v1_calculator_proto_depIdxs = []int32{
+	0,  // 0: vodeneevbet.calculator.v1.ListValueBetsRequest.query:type_name -> vodeneevbet.calculator.v1.ListQuery
+	7,  // 1: vodeneevbet.calculator.v1.ListValueBetsResponse.value_bets:type_name -> vodeneevbet.calculator.v1.ValueBet
+	0,  // 2: vodeneevbet.calculator.v1.ListLineMovementsRequest.query:type_name -> vodeneevbet.calculator.v1.ListQuery
+	8,  // 3: vodeneevbet.calculator.v1.ListLineMovementsResponse.line_movements:type_name -> vodeneevbet.calculator.v1.LineMovement
+	10, // 4: vodeneevbet.calculator.v1.GetMatchOddsResponse.matches:type_name -> vodeneevbet.matches.v1.Match
+	11, // 5: vodeneevbet.calculator.v1.ValueBet.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 6: vodeneevbet.calculator.v1.ValueBet.all_bookmaker_odds:type_name -> vodeneevbet.calculator.v1.ValueBet.AllBookmakerOddsEntry
+	11, // 7: vodeneevbet.calculator.v1.ValueBet.calculated_at:type_name -> google.protobuf.Timestamp
+	11, // 8: vodeneevbet.calculator.v1.LineMovement.start_time:type_name -> google.protobuf.Timestamp
+	11, // 9: vodeneevbet.calculator.v1.LineMovement.recorded_at:type_name -> google.protobuf.Timestamp
+	1,  // 10: vodeneevbet.calculator.v1.CalculatorService.ListValueBets:input_type -> vodeneevbet.calculator.v1.ListValueBetsRequest
+	3,  // 11: vodeneevbet.calculator.v1.CalculatorService.ListLineMovements:input_type -> vodeneevbet.calculator.v1.ListLineMovementsRequest
+	5,  // 12: vodeneevbet.calculator.v1.CalculatorService.GetMatchOdds:input_type -> vodeneevbet.calculator.v1.GetMatchOddsRequest
+	2,  // 13: vodeneevbet.calculator.v1.CalculatorService.ListValueBets:output_type -> vodeneevbet.calculator.v1.ListValueBetsResponse
+	4,  // 14: vodeneevbet.calculator.v1.CalculatorService.ListLineMovements:output_type -> vodeneevbet.calculator.v1.ListLineMovementsResponse
+	6,  // 15: vodeneevbet.calculator.v1.CalculatorService.GetMatchOdds:output_type -> vodeneevbet.calculator.v1.GetMatchOddsResponse
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_calculator_v1_calculator_proto_init() }
+func file_api_proto_calculator_v1_calculator_proto_init() {
+	if File_api_proto_calculator_v1_calculator_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ListQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ListValueBetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListValueBetsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLineMovementsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLineMovementsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetMatchOddsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*GetMatchOddsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ValueBet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_calculator_v1_calculator_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*LineMovement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_proto_calculator_v1_calculator_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_calculator_v1_calculator_proto_goTypes,
+		DependencyIndexes: file_api_proto_calculator_v1_calculator_proto_depIdxs,
+		MessageInfos:      file_api_proto_calculator_v1_calculator_proto_msgTypes,
+	}.Build()
+	File_api_proto_calculator_v1_calculator_proto = out.File
+	file_api_proto_calculator_v1_calculator_proto_rawDesc = nil
+	file_api_proto_calculator_v1_calculator_proto_goTypes = nil
+	file_api_proto_calculator_v1_calculator_proto_depIdxs = nil
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: api/proto/calculator/v1/calculator.proto
+
+package calculatorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CalculatorService_ListValueBets_FullMethodName     = "/vodeneevbet.calculator.v1.CalculatorService/ListValueBets"
+	CalculatorService_ListLineMovements_FullMethodName = "/vodeneevbet.calculator.v1.CalculatorService/ListLineMovements"
+	CalculatorService_GetMatchOdds_FullMethodName      = "/vodeneevbet.calculator.v1.CalculatorService/GetMatchOdds"
+)
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CalculatorService is the read API of the calculator over gRPC (value_calculator.grpc.port), the typed
+// counterpart of GET /value-bets/top, /line-movements/top and the current odds of a match.
+type CalculatorServiceClient interface {
+	// ListValueBets returns a page of the current value bets, highest value first unless sorted otherwise.
+	ListValueBets(ctx context.Context, in *ListValueBetsRequest, opts ...grpc.CallOption) (*ListValueBetsResponse, error)
+	// ListLineMovements returns a page of the current line movements, largest first unless sorted otherwise.
+	ListLineMovements(ctx context.Context, in *ListLineMovementsRequest, opts ...grpc.CallOption) (*ListLineMovementsResponse, error)
+	// GetMatchOdds returns the current matches of a match group, one per bookmaker, with all their odds.
+	GetMatchOdds(ctx context.Context, in *GetMatchOddsRequest, opts ...grpc.CallOption) (*GetMatchOddsResponse, error)
+}
+
+type calculatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalculatorServiceClient(cc grpc.ClientConnInterface) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) ListValueBets(ctx context.Context, in *ListValueBetsRequest, opts ...grpc.CallOption) (*ListValueBetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListValueBetsResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ListValueBets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ListLineMovements(ctx context.Context, in *ListLineMovementsRequest, opts ...grpc.CallOption) (*ListLineMovementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLineMovementsResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ListLineMovements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) GetMatchOdds(ctx context.Context, in *GetMatchOddsRequest, opts ...grpc.CallOption) (*GetMatchOddsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMatchOddsResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_GetMatchOdds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorServiceServer is the server API for CalculatorService service.
+// All implementations must embed UnimplementedCalculatorServiceServer
+// for forward compatibility.
+//
+// CalculatorService is the read API of the calculator over gRPC (value_calculator.grpc.port), the typed
+// counterpart of GET /value-bets/top, /line-movements/top and the current odds of a match.
+type CalculatorServiceServer interface {
+	// ListValueBets returns a page of the current value bets, highest value first unless sorted otherwise.
+	ListValueBets(context.Context, *ListValueBetsRequest) (*ListValueBetsResponse, error)
+	// ListLineMovements returns a page of the current line movements, largest first unless sorted otherwise.
+	ListLineMovements(context.Context, *ListLineMovementsRequest) (*ListLineMovementsResponse, error)
+	// GetMatchOdds returns the current matches of a match group, one per bookmaker, with all their odds.
+	GetMatchOdds(context.Context, *GetMatchOddsRequest) (*GetMatchOddsResponse, error)
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+// UnimplementedCalculatorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCalculatorServiceServer struct{}
+
+func (UnimplementedCalculatorServiceServer) ListValueBets(context.Context, *ListValueBetsRequest) (*ListValueBetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListValueBets not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ListLineMovements(context.Context, *ListLineMovementsRequest) (*ListLineMovementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLineMovements not implemented")
+}
+func (UnimplementedCalculatorServiceServer) GetMatchOdds(context.Context, *GetMatchOddsRequest) (*GetMatchOddsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatchOdds not implemented")
+}
+func (UnimplementedCalculatorServiceServer) mustEmbedUnimplementedCalculatorServiceServer() {}
+func (UnimplementedCalculatorServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCalculatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalculatorServiceServer will
+// result in compilation errors.
+type UnsafeCalculatorServiceServer interface {
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+func RegisterCalculatorServiceServer(s grpc.ServiceRegistrar, srv CalculatorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCalculatorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CalculatorService_ServiceDesc, srv)
+}
+
+func _CalculatorService_ListValueBets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListValueBetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListValueBets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ListValueBets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListValueBets(ctx, req.(*ListValueBetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ListLineMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLineMovementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListLineMovements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ListLineMovements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListLineMovements(ctx, req.(*ListLineMovementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_GetMatchOdds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchOddsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).GetMatchOdds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_GetMatchOdds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).GetMatchOdds(ctx, req.(*GetMatchOddsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CalculatorService_ServiceDesc is the grpc.ServiceDesc for CalculatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalculatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vodeneevbet.calculator.v1.CalculatorService",
+	HandlerType: (*CalculatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListValueBets",
+			Handler:    _CalculatorService_ListValueBets_Handler,
+		},
+		{
+			MethodName: "ListLineMovements",
+			Handler:    _CalculatorService_ListLineMovements_Handler,
+		},
+		{
+			MethodName: "GetMatchOdds",
+			Handler:    _CalculatorService_GetMatchOdds_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/calculator/v1/calculator.proto",
+}
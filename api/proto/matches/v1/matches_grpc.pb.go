@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: api/proto/matches/v1/matches.proto
+
+package matchesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MatchesService_Subscribe_FullMethodName = "/vodeneevbet.matches.v1.MatchesService/Subscribe"
+)
+
+// MatchesServiceClient is the client API for MatchesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MatchesService streams the in-memory matches of a bookmaker-service to the parser orchestrator
+// (gRPC transport, parser.grpc.enabled; HTTP GET /matches stays as fallback).
+type MatchesServiceClient interface {
+	// Subscribe sends the full match set first (or the delta after since), then an update
+	// every time odds change: only changed matches and IDs of removed ones.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchesUpdate], error)
+}
+
+type matchesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchesServiceClient(cc grpc.ClientConnInterface) MatchesServiceClient {
+	return &matchesServiceClient{cc}
+}
+
+func (c *matchesServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchesUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MatchesService_ServiceDesc.Streams[0], MatchesService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, MatchesUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchesService_SubscribeClient = grpc.ServerStreamingClient[MatchesUpdate]
+
+// MatchesServiceServer is the server API for MatchesService service.
+// All implementations must embed UnimplementedMatchesServiceServer
+// for forward compatibility.
+//
+// MatchesService streams the in-memory matches of a bookmaker-service to the parser orchestrator
+// (gRPC transport, parser.grpc.enabled; HTTP GET /matches stays as fallback).
+type MatchesServiceServer interface {
+	// Subscribe sends the full match set first (or the delta after since), then an update
+	// every time odds change: only changed matches and IDs of removed ones.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MatchesUpdate]) error
+	mustEmbedUnimplementedMatchesServiceServer()
+}
+
+// UnimplementedMatchesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMatchesServiceServer struct{}
+
+func (UnimplementedMatchesServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MatchesUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMatchesServiceServer) mustEmbedUnimplementedMatchesServiceServer() {}
+func (UnimplementedMatchesServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeMatchesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchesServiceServer will
+// result in compilation errors.
+type UnsafeMatchesServiceServer interface {
+	mustEmbedUnimplementedMatchesServiceServer()
+}
+
+func RegisterMatchesServiceServer(s grpc.ServiceRegistrar, srv MatchesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMatchesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MatchesService_ServiceDesc, srv)
+}
+
+func _MatchesService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchesServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, MatchesUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchesService_SubscribeServer = grpc.ServerStreamingServer[MatchesUpdate]
+
+// MatchesService_ServiceDesc is the grpc.ServiceDesc for MatchesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vodeneevbet.matches.v1.MatchesService",
+	HandlerType: (*MatchesServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MatchesService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/proto/matches/v1/matches.proto",
+}
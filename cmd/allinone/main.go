@@ -0,0 +1,496 @@
+// Command allinone runs the local parsers, their aggregated /matches, the value calculator and the Telegram
+// bot in one process (profile configs/profiles/allinone.yaml, config all_in_one). Matches go from the parsers
+// to the calculator over an in-memory bus; the calculator and the bot keep their API clients, served in
+// process instead of over the network.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/calculator/calculator"
+	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/bus"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/chaos"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/inprocess"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/logging"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/teaminfo"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
+	"github.com/Vodeneev/vodeneevbet/internal/telegrambot"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+
+	// Register all supported parsers via init().
+	_ "github.com/Vodeneev/vodeneevbet/internal/parser/parsers/all"
+)
+
+const (
+	defaultConfigPath = "configs/production.yaml"
+	defaultProfile    = "allinone"
+	defaultSQLitePath = "data/vodeneevbet.db"
+	// defaultCalculatorPort applies when all_in_one.calculator_port is not set
+	defaultCalculatorPort = 8080
+	// defaultShutdownGracePeriod applies when parser.shutdown_grace_period is not set
+	defaultShutdownGracePeriod = 30 * time.Second
+	// calculatorClientTimeout bounds a request of the bot to the calculator (value bets are computed on request)
+	calculatorClientTimeout = 60 * time.Second
+)
+
+type config struct {
+	configPath string
+	profile    string
+	runFor     time.Duration
+	parser     string // Override enabled_parsers from config (e.g. "fonbet" or "fonbet,leon")
+}
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("All-in-one failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	slog.Info("Starting all-in-one...")
+
+	cfg := parseFlags()
+	slog.Info("Loading config", "path", cfg.configPath, "profile", cfg.profile)
+	appConfig, err := pkgconfig.LoadProfile(cfg.configPath, cfg.profile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(appConfig.Parser.BookmakerServices) > 0 || appConfig.Parser.Discovery.Mode != "" {
+		return fmt.Errorf("all-in-one runs the parsers in process: parser.bookmaker_services and parser.discovery must be empty (profile %q)", cfg.profile)
+	}
+
+	if _, err := logging.SetupLogger(&appConfig.Logging, "allinone"); err != nil {
+		slog.Warn("Failed to setup logging, continuing with default logger", "error", err)
+	} else {
+		slog.Info("Logging initialized", "service", "allinone")
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), &appConfig.Tracing, "allinone")
+	if err != nil {
+		slog.Warn("Failed to setup tracing, continuing without it", "error", err)
+	}
+	defer shutdownTracing(context.Background()) //nolint:errcheck // best-effort flush on exit
+
+	circuitbreaker.Configure(appConfig.Parser.CircuitBreaker)
+	proxypool.Configure(appConfig.Parser.ProxyPool)
+	browserpool.Configure(appConfig.Parser.BrowserPool)
+	defer browserpool.Default().Close()
+	mirrors.Configure(appConfig.Parser.MirrorRegistry)
+	teaminfo.Configure(appConfig.Parser.TeamInfo)
+	if n, err := models.ConfigureTeamAliases(appConfig.TeamAliases.File); err != nil {
+		slog.Error("Team aliases: failed to load file, using embedded seed only", "file", appConfig.TeamAliases.File, "error", err)
+	} else {
+		slog.Info("Team aliases configured", "aliases", n, "file", appConfig.TeamAliases.File)
+	}
+	if n, err := models.ConfigureLeagueAliases(appConfig.LeagueAliases.File); err != nil {
+		slog.Error("League aliases: failed to load file, using embedded seed only", "file", appConfig.LeagueAliases.File, "error", err)
+	} else {
+		slog.Info("League aliases configured", "aliases", n, "file", appConfig.LeagueAliases.File)
+	}
+	models.SetStartTimeTolerance(appConfig.Parser.StartTimeTolerance)
+	models.SetOddsPrecision(appConfig.Odds.Epsilon, appConfig.Odds.Decimals)
+
+	if cfg.parser != "" {
+		appConfig.Parser.EnabledParsers = strings.Split(cfg.parser, ",")
+	}
+	ps, byName, err := selectParsers(appConfig)
+	if err != nil {
+		return err
+	}
+
+	// One event log, chaos and api_auth setting for the process; the listening APIs are guarded as the calculator's
+	eventlog.Configure("allinone", appConfig.EventLog)
+	chaos.Configure("allinone", appConfig.Chaos)
+	if err := apiauth.Configure("calculator", appConfig.APIAuth); err != nil {
+		return fmt.Errorf("invalid api_auth: %w", err)
+	}
+
+	// The calculator and the bot reach the parser and the calculator in process, through their own
+	// transport: http.DefaultTransport is left as is.
+	parserURL, calculatorURL := inprocess.BaseURL("parser"), inprocess.BaseURL("calculator")
+	calculatorMux := http.NewServeMux()
+	transport := &inprocess.Transport{Handlers: map[string]http.Handler{
+		parserURL:     health.Handler("allinone"),
+		calculatorURL: calculatorMux,
+	}}
+
+	// Matches of the parsers go to the calculator over an in-memory bus instead of GET /matches
+	local := bus.NewLocal(appConfig.AllInOne.MatchBuffer)
+	defer local.Close()
+	health.SetMatchPublisher(local)
+	health.RegisterParsers(ps)
+
+	vc := &appConfig.ValueCalculator
+	vc.ParserURL = parserURL
+	applyTelegramEnv(vc)
+	diffStorage, closeStorage, err := openDiffStorage(appConfig)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	templates, err := tgformat.New(&appConfig.Telegram)
+	if err != nil {
+		return fmt.Errorf("failed to load Telegram templates: %w", err)
+	}
+	valueCalculator := calculator.NewValueCalculator(vc, diffStorage, nil)
+	valueCalculator.SetParserTransport(transport)
+	valueCalculator.SetTelegramTemplates(templates)
+	valueCalculator.SetTelegramDelivery(appConfig.Telegram.Delivery)
+	matches := bus.NewMatchCache(appConfig.Bus.MatchTTL)
+	if err := local.SubscribeMatches(matches.Add); err != nil {
+		return fmt.Errorf("failed to subscribe to matches: %w", err)
+	}
+	valueCalculator.SetBusMatches(matches)
+
+	ctx, cancel := createContext(cfg.runFor)
+	defer cancel()
+	grace := appConfig.Parser.ShutdownGracePeriod
+	if grace <= 0 {
+		grace = defaultShutdownGracePeriod
+	}
+	setupSignalHandler(ctx, cancel, grace)
+
+	// Parser health server: /matches, /stats, /healthz of the parsers for the dashboard and curl
+	port := appConfig.Health.Port
+	if port <= 0 {
+		return fmt.Errorf("health.port must be specified in config")
+	}
+	asyncParsingTimeout := appConfig.Health.AsyncParsingTimeout
+	if asyncParsingTimeout <= 0 {
+		asyncParsingTimeout = 60 * time.Second
+	}
+	ms := appConfig.Health.MatchStore
+	health.StartMatchEviction(ctx, ms.MaxMatches, ms.Retention, ms.EvictInterval)
+	health.Run(ctx, health.AddrFor(port), "allinone", nil, appConfig.Health.ReadHeaderTimeout, asyncParsingTimeout)
+
+	// Calculator API: /healthz, /readyz (database, Telegram token) and /health/dependencies (also the parser)
+	checks := healthcheck.New("calculator")
+	valueCalculator.RegisterHealthChecks(checks)
+	checks.Register(calculatorMux)
+	valueCalculator.RegisterHTTP(calculatorMux)
+	calculatorPort := appConfig.AllInOne.CalculatorPort
+	if calculatorPort <= 0 {
+		calculatorPort = defaultCalculatorPort
+	}
+	serveCalculator(ctx, health.AddrFor(calculatorPort), calculatorMux)
+	if grpcPort := vc.GRPC.Port; grpcPort > 0 {
+		if err := valueCalculator.RunGRPC(ctx, health.AddrFor(grpcPort)); err != nil {
+			return fmt.Errorf("failed to start gRPC server: %w", err)
+		}
+	}
+
+	if appConfig.AllInOne.Bot {
+		startBot(ctx, appConfig, templates, transport, parserURL, calculatorURL)
+	}
+
+	slog.Info("Starting parsers...")
+	runParsers(ctx, ps, byName, appConfig, asyncParsingTimeout)
+
+	slog.Info("Starting Value Bet Calculator...")
+	if err := valueCalculator.Start(ctx); err != nil {
+		return fmt.Errorf("calculator failed: %w", err)
+	}
+	<-ctx.Done()
+	slog.Info("All-in-one stopped gracefully")
+	return nil
+}
+
+func parseFlags() config {
+	var cfg config
+	defaultConfig := os.Getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = defaultConfigPath
+	}
+	defaultProfileName := os.Getenv("CONFIG_PROFILE")
+	if defaultProfileName == "" {
+		defaultProfileName = defaultProfile
+	}
+	flag.StringVar(&cfg.configPath, "config", defaultConfig, "Path to config file (can be set via CONFIG_PATH env var)")
+	flag.StringVar(&cfg.profile, "profile", defaultProfileName, "Config profile applied on top of -config: profiles/<profile>.yaml next to it (or set CONFIG_PROFILE)")
+	flag.DurationVar(&cfg.runFor, "run-for", 0, "Auto-stop after duration (e.g. 10s, 1m). 0 = run until SIGINT/SIGTERM")
+	flag.StringVar(&cfg.parser, "parser", "", "Override enabled_parsers: parser name or comma list (e.g. 'fonbet,leon'). Empty = use config")
+	flag.Parse()
+	return cfg
+}
+
+// selectParsers creates the parsers of parser.enabled_parsers (all registered ones when empty), sorted by name.
+func selectParsers(cfg *pkgconfig.Config) ([]interfaces.Parser, map[string]interfaces.Parser, error) {
+	available := parsers.Available()
+	enabledSet := make(map[string]bool)
+	for _, name := range cfg.Parser.EnabledParsers {
+		if n := strings.ToLower(strings.TrimSpace(name)); n != "" {
+			enabledSet[n] = true
+		}
+	}
+	var unknown []string
+	for name := range enabledSet {
+		if _, ok := available[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, nil, fmt.Errorf("unknown parsers in parser.enabled_parsers: %v (available: %v)", unknown, parsers.AvailableNames())
+	}
+
+	keys := make([]string, 0, len(available))
+	for key := range available {
+		if len(enabledSet) == 0 || enabledSet[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("no parsers selected to run (parser.enabled_parsers=%v)", cfg.Parser.EnabledParsers)
+	}
+	sort.Strings(keys)
+	list := make([]interfaces.Parser, 0, len(keys))
+	byName := make(map[string]interfaces.Parser, len(keys))
+	for _, key := range keys {
+		p := available[key](cfg)
+		list = append(list, p)
+		byName[key] = p
+	}
+	slog.Info("Using parsers", "parsers", strings.Join(keys, ", "))
+	return list, byName, nil
+}
+
+// applyTelegramEnv takes the bot token, chat and WebApp users from the environment, like the calculator.
+func applyTelegramEnv(vc *pkgconfig.ValueCalculatorConfig) {
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		vc.TelegramBotToken = token
+	}
+	if chatID, err := strconv.ParseInt(os.Getenv("TELEGRAM_CHAT_ID"), 10, 64); err == nil {
+		vc.TelegramChatID = chatID
+	}
+	if allowed := userIDs(os.Getenv("ALLOWED_USERS")); len(allowed) > 0 {
+		vc.WebApp.AllowedUserIDs = allowed
+	}
+}
+
+// userIDs parses a comma-separated list of Telegram user IDs, skipping invalid entries.
+func userIDs(s string) []int64 {
+	var ids []int64
+	for _, idStr := range strings.Split(s, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// openDiffStorage opens the diff_bets storage of storage.backend when async processing is enabled; the
+// other storages of the calculator are off in all-in-one mode.
+func openDiffStorage(cfg *pkgconfig.Config) (storage.DiffBetStorage, func(), error) {
+	if !cfg.ValueCalculator.AsyncEnabled {
+		return nil, func() {}, nil
+	}
+	var diffs interface {
+		storage.DiffBetStorage
+		CleanDiffBets(ctx context.Context) error
+		Close() error
+	}
+	if cfg.Storage.Backend == pkgconfig.StorageBackendSQLite {
+		path := cfg.Storage.SQLitePath
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		slog.Info("Initializing SQLite diff storage...", "path", path)
+		s, err := storage.NewSQLiteDiffStorage(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize SQLite storage: %w", err)
+		}
+		diffs = s
+	} else {
+		pgConfig := cfg.Postgres
+		if envDSN := os.Getenv("POSTGRES_DSN"); envDSN != "" {
+			pgConfig.DSN = envDSN
+		}
+		if pgConfig.DSN == "" {
+			return nil, nil, fmt.Errorf("postgres DSN is required when async is enabled. Set it in config or POSTGRES_DSN env var")
+		}
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 10*time.Minute)
+		applied, err := storage.MigratePostgres(migrateCtx, &pgConfig)
+		cancelMigrate()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to apply PostgreSQL migrations: %w", err)
+		}
+		slog.Info("PostgreSQL schema is up to date", "migrations_applied", len(applied))
+		s, err := storage.NewPostgresDiffStorage(&pgConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize PostgreSQL storage: %w", err)
+		}
+		diffs = s
+	}
+	slog.Warn("All-in-one mode: only diff_bets are stored; odds history, line movements, ignores, subscriptions, bets, value history and the warehouse are off")
+
+	cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := diffs.CleanDiffBets(cleanCtx); err != nil {
+		slog.Warn("Failed to clean diff_bets table", "error", err)
+	}
+	cleanCancel()
+	return diffs, func() {
+		if err := diffs.Close(); err != nil {
+			slog.Error("Error closing diff storage", "error", err)
+		}
+	}, nil
+}
+
+// serveCalculator serves the calculator API on addr until ctx is done.
+func serveCalculator(ctx context.Context, addr string, mux *http.ServeMux) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           tracing.Handler(apiauth.Middleware(chaos.Middleware(mux)), "calculator"),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		slog.Info("Calculator HTTP server listening", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Calculator HTTP server error", "error", err)
+		}
+	}()
+}
+
+// startBot runs the Telegram bot against the in-process calculator and parser. Without a token, or when
+// Telegram rejects it, the rest of the process keeps running.
+func startBot(ctx context.Context, cfg *pkgconfig.Config, templates *tgformat.Templates, transport http.RoundTripper, parserURL, calculatorURL string) {
+	token := cfg.ValueCalculator.TelegramBotToken
+	if token == "" {
+		slog.Warn("all_in_one.bot is set but there is no bot token (TELEGRAM_BOT_TOKEN), running without the bot")
+		return
+	}
+	botConfig := telegrambot.BotConfig{
+		Token:          token,
+		CalculatorURL:  calculatorURL,
+		Calculator:     client.NewCalculator(calculatorURL, client.WithHTTPClient(&http.Client{Timeout: calculatorClientTimeout, Transport: transport})),
+		UpdateTimeout:  60,
+		AllowedUserIDs: userIDs(os.Getenv("ALLOWED_USERS")),
+		AdminUserIDs:   userIDs(os.Getenv("ADMIN_USERS")),
+		ParserURL:      parserURL,
+		WebAppURL:      os.Getenv("WEBAPP_URL"),
+		Templates:      templates,
+		Delivery:       cfg.Telegram.Delivery,
+		Transport:      transport,
+	}
+	go func() {
+		if err := telegrambot.Run(ctx, botConfig); err != nil {
+			slog.Error("Telegram bot failed, running without the bot", "error", err)
+		}
+	}()
+}
+
+// runParsers starts the parsers and polls them every parser.interval, or per sport with parser.sports.
+func runParsers(ctx context.Context, ps []interfaces.Parser, byName map[string]interfaces.Parser, cfg *pkgconfig.Config, timeout time.Duration) {
+	opts := parserutil.AsyncRunOptions()
+	opts.LogStart = true
+	opts.OnError = func(p interfaces.Parser, err error) {
+		slog.Error("Parser failed", "parser", p.GetName(), "error", err)
+	}
+	_ = parserutil.RunParsers(ctx, ps, func(ctx context.Context, p interfaces.Parser) error {
+		return p.Start(ctx)
+	}, opts)
+
+	interval := cfg.Parser.Interval
+	if interval <= 0 {
+		interval = 2 * time.Minute
+		slog.Info("parser.interval not set, using default", "interval", interval)
+	}
+	if len(cfg.Parser.Sports) > 0 {
+		parserutil.RunSportSchedules(ctx, parserutil.BuildSportSchedules(byName, cfg.Parser.Sports, interval), timeout)
+		return
+	}
+
+	slog.Info("Starting periodic parsing", "interval", interval)
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if parserutil.Draining() {
+					continue
+				}
+				parseCtx, cancel := context.WithTimeout(context.Background(), timeout)
+				popts := parserutil.AsyncRunOptions()
+				popts.WaitForCompletion = true
+				popts.OnError = func(p interfaces.Parser, err error) {
+					slog.Error("Periodic parsing failed", "parser", p.GetName(), "error", err)
+				}
+				_ = parserutil.RunParsers(parseCtx, ps, func(ctx context.Context, p interfaces.Parser) error {
+					_, err := parserutil.ParseOnceReport(ctx, p)
+					return err
+				}, popts)
+				cancel()
+			}
+		}
+	}()
+}
+
+func createContext(runFor time.Duration) (context.Context, context.CancelFunc) {
+	if runFor > 0 {
+		return context.WithTimeout(context.Background(), runFor)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// setupSignalHandler drains the parsers on SIGINT/SIGTERM (up to grace, like bookmaker-service) before
+// canceling ctx, so the leagues in progress still reach the calculator. A second signal cancels at once.
+func setupSignalHandler(ctx context.Context, cancel context.CancelFunc, grace time.Duration) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigChan:
+			slog.Info("Received shutdown signal, draining parsers", "signal", sig.String(), "grace_period", grace)
+			drained := make(chan bool, 1)
+			go func() { drained <- parserutil.Drain(grace) }()
+			select {
+			case ok := <-drained:
+				if !ok {
+					slog.Warn("Shutdown grace period expired, canceling cycles in progress", "active_cycles", parserutil.ActiveCycles())
+				}
+			case sig := <-sigChan:
+				slog.Warn("Received second shutdown signal, stopping without draining", "signal", sig.String())
+			case <-ctx.Done():
+			}
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(sigChan)
+			close(sigChan)
+		}
+	}()
+}
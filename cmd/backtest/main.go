@@ -0,0 +1,196 @@
+// backtest replays recorded odds history through the value bet calculation, settles the value bets
+// found with the configured results source (value_calculator.results) and prints ROI, yield and
+// drawdown per staking plan. Run from the repo root with POSTGRES_DSN set (or postgres.dsn in config):
+//
+//	go run ./cmd/backtest -from 2026-03-01 -to 2026-04-01                      # research.bets_wide
+//	go run ./cmd/backtest -from 2026-03-01 -to 2026-04-01 -method shin -min-value 3
+//	go run ./cmd/backtest -source history -from 2026-03-01T10:00:00Z -to 2026-03-01T18:00:00Z
+//	go run ./cmd/backtest -from 2026-03-01 -to 2026-04-01 -staking kelly -kelly-fraction 0.5 -json
+//
+// Thresholds, weights, reference bookmakers, fair odds method and staking default to the
+// value_calculator section of the config. The warehouse (research.bets_wide) keeps history of
+// finished matches; odds_snapshot_history only keeps value_calculator.odds_history.retention.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/calculator/calculator"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+const defaultConfigPath = "configs/production.yaml"
+
+func main() {
+	defaultConfig := os.Getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = defaultConfigPath
+	}
+
+	configPath := flag.String("config", defaultConfig, "Path to config file (can be set via CONFIG_PATH env var)")
+	fromFlag := flag.String("from", "", "Start of the replay: RFC3339 or YYYY-MM-DD (default: 30 days ago)")
+	toFlag := flag.String("to", "", "End of the replay, exclusive: RFC3339 or YYYY-MM-DD (default: now)")
+	source := flag.String("source", "warehouse", "Odds history: warehouse (research.bets_wide) or history (odds_snapshot_history)")
+	step := flag.Duration("step", 5*time.Minute, "How often value bets are recomputed along the history")
+	minValue := flag.Float64("min-value", 0, "Minimum value percent (default: value_calculator.min_value_percent)")
+	maxOdds := flag.Float64("max-odds", -1, "Max bookmaker odd, 0 = no limit (default: value_calculator.max_odds)")
+	method := flag.String("method", "", "Fair odds method: none, equal, proportional, shin, power, logarithmic (default: value_calculator.fair_odds_method)")
+	plans := flag.String("staking", "flat,kelly", "Comma-separated staking plans: flat, kelly")
+	bankroll := flag.Float64("bankroll", 1000, "Starting bankroll of every plan")
+	kellyFraction := flag.Float64("kelly-fraction", 0, "Fraction of full Kelly (default: value_calculator.staking.kelly_fraction)")
+	flatPercent := flag.Float64("flat-percent", 0, "Flat stake in percent of the starting bankroll (default: value_calculator.staking.flat_percent)")
+	asJSON := flag.Bool("json", false, "Print the full result (reports and bets) as JSON")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal("Failed to load config", err)
+	}
+	vc := cfg.ValueCalculator
+
+	now := time.Now().UTC()
+	from, err := parseTime(*fromFlag, now.AddDate(0, 0, -30))
+	if err != nil {
+		fatal("Invalid -from", err)
+	}
+	to, err := parseTime(*toFlag, now)
+	if err != nil {
+		fatal("Invalid -to", err)
+	}
+
+	btCfg := calculator.BacktestConfig{
+		From:                from,
+		To:                  to,
+		Step:                *step,
+		MinValuePercent:     vc.MinValuePercent,
+		MaxOdds:             vc.MaxOdds,
+		BookmakerWeights:    vc.BookmakerWeights,
+		ReferenceBookmakers: vc.ReferenceBookmakers,
+		FairOddsMethod:      vc.FairOddsMethod,
+		Rules:               vc.Rules,
+		MinBookmakers:       vc.MinBookmakers,
+		MinSharpBookmakers:  vc.MinSharpBookmakers,
+		SharpBookmakers:     vc.SharpBookmakers,
+		Bankroll:            *bankroll,
+		Staking:             vc.Staking,
+	}
+	if *minValue > 0 {
+		btCfg.MinValuePercent = *minValue
+	}
+	if *maxOdds >= 0 {
+		btCfg.MaxOdds = *maxOdds
+	}
+	if *method != "" {
+		btCfg.FairOddsMethod = *method
+	}
+	if *kellyFraction > 0 {
+		btCfg.Staking.KellyFraction = *kellyFraction
+	}
+	if *flatPercent > 0 {
+		btCfg.Staking.FlatPercent = *flatPercent
+	}
+	for _, p := range strings.Split(*plans, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			btCfg.Plans = append(btCfg.Plans, p)
+		}
+	}
+
+	pgConfig := cfg.Postgres
+	if envDSN := os.Getenv("POSTGRES_DSN"); envDSN != "" {
+		pgConfig.DSN = envDSN
+	}
+	if pgConfig.DSN == "" {
+		fatal("Postgres DSN is required", fmt.Errorf("set postgres.dsn in config or POSTGRES_DSN env var"))
+	}
+
+	var reader storage.OddsHistoryReader
+	switch *source {
+	case "warehouse":
+		s, err := storage.NewPostgresWarehouseStorage(&pgConfig)
+		if err != nil {
+			fatal("Failed to connect to warehouse", err)
+		}
+		defer s.Close()
+		reader = s
+	case "history":
+		s, err := storage.NewPostgresOddsSnapshotStorage(&pgConfig)
+		if err != nil {
+			fatal("Failed to connect to odds snapshot storage", err)
+		}
+		defer s.Close()
+		reader = s
+	default:
+		fatal("Invalid -source", fmt.Errorf("unknown source %q (want warehouse or history)", *source))
+	}
+
+	if apiKey := os.Getenv("FOOTBALL_DATA_API_KEY"); apiKey != "" {
+		vc.Results.APIKey = apiKey
+	}
+	provider, err := results.New(vc.Results)
+	if err != nil {
+		fatal("Failed to create results provider", err)
+	}
+	if provider == nil {
+		slog.Warn("No results provider configured (value_calculator.results), value bets will not be settled")
+	} else if vc.Results.Cache {
+		resultPg, err := storage.NewPostgresResultStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Results cache disabled", "error", err)
+		} else {
+			defer resultPg.Close()
+			provider = results.NewCachedProvider(provider, resultPg)
+		}
+	}
+
+	slog.Info("Running backtest", "source", *source, "from", from.Format(time.RFC3339), "to", to.Format(time.RFC3339),
+		"min_value", btCfg.MinValuePercent, "method", btCfg.FairOddsMethod, "plans", btCfg.Plans)
+	res, err := calculator.RunBacktest(context.Background(), reader, provider, btCfg)
+	if err != nil {
+		fatal("Backtest failed", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			fatal("Failed to encode result", err)
+		}
+		return
+	}
+	fmt.Printf("Replayed %d odds records, %d value bets (%d unsettled)\n\n", res.Records, len(res.Bets), res.Unsettled)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "plan\tbets\twon\tlost\tvoid\tturnover\tprofit\tyield %\tROI %\tbankroll\tmax DD\tmax DD %\tavg odd\tavg value %\t")
+	for _, r := range res.Reports {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			r.Plan, r.Bets, r.Won, r.Lost, r.Void, r.Turnover, r.Profit, r.YieldPercent, r.ROIPercent,
+			r.FinalBankroll, r.MaxDrawdown, r.MaxDrawdownPercent, r.AvgOdd, r.AvgValuePercent)
+	}
+	_ = tw.Flush()
+}
+
+// parseTime parses RFC3339 or a UTC date (YYYY-MM-DD); empty returns def.
+func parseTime(s string, def time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -7,29 +7,48 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/bus"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/chaos"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
 	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/dryrun"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/logging"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/teaminfo"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 
 	_ "github.com/Vodeneev/vodeneevbet/internal/parser/parsers/all"
 )
 
 const (
 	defaultConfigPath = "configs/production.yaml"
+	// defaultShutdownGracePeriod applies when parser.shutdown_grace_period is not set
+	defaultShutdownGracePeriod = 30 * time.Second
 )
 
 type config struct {
-	configPath string
-	runFor     time.Duration
-	parser     string // Required: single parser name (e.g. "fonbet", "pinnacle", "pinnacle888")
+	configPath   string
+	runFor       time.Duration
+	parser       string // Required: parser name or comma list run in one process (e.g. "fonbet", "fonbet,leon")
+	dryRun       bool   // Parse once and write matches to dryRunOutput instead of serving them
+	dryRunOutput string // Dry-run JSON file ("" = parser.dry_run.output, "-" = stdout)
+
+	debugEndpoints bool // pprof and /debug/runtime on the health server
 }
 
 func main() {
@@ -46,10 +65,13 @@ func run() error {
 	if cfg.parser == "" {
 		cfg.parser = os.Getenv("BOOKMAKER_PARSER")
 	}
-	if cfg.parser == "" {
-		return fmt.Errorf("parser name is required: use -parser=<name> or BOOKMAKER_PARSER env (e.g. fonbet, pinnacle, pinnacle888)")
+	names := parseParserList(cfg.parser)
+	if len(names) == 0 {
+		return fmt.Errorf("parser name is required: use -parser=<name>[,<name>...] or BOOKMAKER_PARSER env (e.g. fonbet, pinnacle888, fonbet,leon)")
 	}
-	cfg.parser = strings.ToLower(strings.TrimSpace(cfg.parser))
+	// One service name for logs, event log, chaos and snapshot: "fonbet" or "fonbet+leon"
+	cfg.parser = strings.Join(names, "+")
+	serviceName := "bookmaker-service-" + cfg.parser
 
 	slog.Info("Loading config", "path", cfg.configPath)
 	appConfig, err := pkgconfig.Load(cfg.configPath)
@@ -57,34 +79,94 @@ func run() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	_, err = logging.SetupLogger(&appConfig.Logging, "bookmaker-service")
-	if err != nil {
-		slog.Warn("Failed to setup logging, continuing with default logger", "error", err)
+	dryRun := dryRunConfig(appConfig.Parser.DryRun, cfg)
+	if dryRun.Enabled {
+		dryrun.LogToStderr()
+	} else {
+		_, err = logging.SetupLogger(&appConfig.Logging, "bookmaker-service")
+		if err != nil {
+			slog.Warn("Failed to setup logging, continuing with default logger", "error", err)
+		} else {
+			slog.Info("Logging initialized", "service", "bookmaker-service", "parser", cfg.parser)
+		}
+		shutdownTracing, err := tracing.Setup(context.Background(), &appConfig.Tracing, "bookmaker-service")
+		if err != nil {
+			slog.Warn("Failed to setup tracing, continuing without it", "error", err)
+		}
+		defer shutdownTracing(context.Background()) //nolint:errcheck // best-effort flush on exit
+	}
+
+	circuitbreaker.Configure(appConfig.Parser.CircuitBreaker)
+	proxypool.Configure(appConfig.Parser.ProxyPool)
+	browserpool.Configure(appConfig.Parser.BrowserPool)
+	defer browserpool.Default().Close()
+	mirrors.Configure(appConfig.Parser.MirrorRegistry)
+	teaminfo.Configure(appConfig.Parser.TeamInfo)
+	if n, err := models.ConfigureTeamAliases(appConfig.TeamAliases.File); err != nil {
+		slog.Error("Team aliases: failed to load file, using embedded seed only", "file", appConfig.TeamAliases.File, "error", err)
 	} else {
-		slog.Info("Logging initialized", "service", "bookmaker-service", "parser", cfg.parser)
+		slog.Info("Team aliases configured", "aliases", n, "file", appConfig.TeamAliases.File)
 	}
+	if n, err := models.ConfigureLeagueAliases(appConfig.LeagueAliases.File); err != nil {
+		slog.Error("League aliases: failed to load file, using embedded seed only", "file", appConfig.LeagueAliases.File, "error", err)
+	} else {
+		slog.Info("League aliases configured", "aliases", n, "file", appConfig.LeagueAliases.File)
+	}
+	models.SetStartTimeTolerance(appConfig.Parser.StartTimeTolerance)
 
-	// Run only this parser (ignore bookmaker_services and enabled_parsers)
+	// Run only these parsers (ignore bookmaker_services and enabled_parsers)
 	appConfig.Parser.BookmakerServices = nil
-	appConfig.Parser.EnabledParsers = []string{cfg.parser}
+	appConfig.Parser.EnabledParsers = names
 
 	ps, err := selectParsers(appConfig)
 	if err != nil {
 		return err
 	}
-	if len(ps) != 1 {
-		return fmt.Errorf("expected exactly one parser for %q, got %d (available: %v)", cfg.parser, len(ps), parsers.AvailableNames())
+	if len(ps) != len(names) {
+		return fmt.Errorf("expected %d parsers for %q, got %d (available: %v)", len(names), cfg.parser, len(ps), parsers.AvailableNames())
+	}
+	parserNames := make([]string, 0, len(ps))
+	for _, p := range ps {
+		parserNames = append(parserNames, p.GetName())
 	}
-	slog.Info("Using parser", "parser", ps[0].GetName())
+	slog.Info("Using parsers", "parsers", strings.Join(parserNames, ", "))
 	// Маркер для логов: по этой строке в Yandex Logging видно, что лог с VM контор (158.160.159.73)
 	slog.Info("Bookmaker service running on separate VM (single-converter)", "parser", cfg.parser)
 
 	ctx, cancel := createContext(cfg.runFor)
 	defer cancel()
-	setupSignalHandler(ctx, cancel)
+	grace := appConfig.Parser.ShutdownGracePeriod
+	if grace <= 0 {
+		grace = defaultShutdownGracePeriod
+	}
+	// After the drain gRPC subscribers get one more push with the matches of the last leagues
+	var flushWait time.Duration
+	if appConfig.Parser.GRPC.Port > 0 {
+		flushWait = health.GRPCPushInterval(appConfig.Parser.GRPC.PushInterval)
+	}
+	setupSignalHandler(ctx, cancel, grace, flushWait)
 
-	interfaceParsers := []interfaces.Parser{ps[0]}
+	interfaceParsers := make([]interfaces.Parser, 0, len(ps))
+	for _, p := range ps {
+		interfaceParsers = append(interfaceParsers, p)
+	}
+	if dryRun.Enabled {
+		return dryrun.Run(ctx, interfaceParsers, dryRun)
+	}
+	eventlog.Configure(serviceName, appConfig.EventLog)
+	chaos.Configure(serviceName, appConfig.Chaos)
+	if err := apiauth.Configure(serviceName, appConfig.APIAuth); err != nil {
+		return fmt.Errorf("invalid api_auth: %w", err)
+	}
 	health.RegisterParsers(interfaceParsers)
+	if appConfig.Bus.Publish {
+		b, err := bus.Connect(appConfig.Bus, serviceName)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the bus: %w", err)
+		}
+		defer b.Close()
+		health.SetMatchPublisher(b)
+	}
 
 	port := appConfig.Health.Port
 	if port <= 0 {
@@ -98,10 +180,81 @@ func run() error {
 		asyncParsingTimeout = 60 * time.Second
 	}
 
-	health.Run(ctx, healthAddr, "bookmaker-service-"+cfg.parser, nil, appConfig.Health.ReadHeaderTimeout, asyncParsingTimeout)
+	ms := appConfig.Health.MatchStore
+	health.StartMatchEviction(ctx, ms.MaxMatches, ms.Retention, ms.EvictInterval)
+	snapshotPath := loadSnapshot(appConfig.Parser.Snapshot, cfg.parser)
+	if snapshotPath != "" {
+		// Stale snapshot matches stay until every parser of the process finishes a cycle
+		health.ExpectFreshCycles(parserNames)
+	}
+
+	if cfg.debugEndpoints {
+		health.EnableDebugEndpoints()
+	}
+	health.Run(ctx, healthAddr, serviceName, nil, appConfig.Health.ReadHeaderTimeout, asyncParsingTimeout)
+	if grpcPort := appConfig.Parser.GRPC.Port; grpcPort > 0 {
+		if err := health.RunGRPC(ctx, health.AddrFor(grpcPort), appConfig.Parser.GRPC.PushInterval); err != nil {
+			return fmt.Errorf("failed to start gRPC matches server: %w", err)
+		}
+	}
+
+	if snapshotPath != "" {
+		startSnapshotSaving(ctx, snapshotPath, appConfig.Parser.Snapshot.SaveInterval)
+	}
 
 	slog.Info("Starting parser...")
-	return runParsers(ctx, interfaceParsers, appConfig, asyncParsingTimeout)
+	err = runParsers(ctx, names, interfaceParsers, appConfig, asyncParsingTimeout)
+	if snapshotPath != "" {
+		saveSnapshot(snapshotPath)
+	}
+	return err
+}
+
+// loadSnapshot serves the last saved matches (marked stale) until the first cycle completes.
+// Returns the snapshot path, or "" when parser.snapshot.dir is not set.
+func loadSnapshot(cfg pkgconfig.SnapshotConfig, parser string) string {
+	if cfg.Dir == "" {
+		return ""
+	}
+	path := filepath.Join(cfg.Dir, parser+".json")
+	maxAge := cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = 6 * time.Hour
+	}
+	n, err := health.LoadSnapshot(path, maxAge)
+	if err != nil {
+		slog.Warn("Failed to load snapshot, starting empty", "path", path, "error", err)
+	} else if n > 0 {
+		slog.Info("Loaded warm-up snapshot, serving stale matches until first cycle completes", "path", path, "matches", n)
+	}
+	return path
+}
+
+func startSnapshotSaving(ctx context.Context, path string, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				saveSnapshot(path)
+			}
+		}
+	}()
+}
+
+func saveSnapshot(path string) {
+	n, err := health.SaveSnapshot(path)
+	if err != nil {
+		slog.Error("Failed to save snapshot", "path", path, "error", err)
+		return
+	}
+	slog.Debug("Snapshot saved", "path", path, "matches", n)
 }
 
 func parseFlags() config {
@@ -112,11 +265,29 @@ func parseFlags() config {
 	}
 	flag.StringVar(&cfg.configPath, "config", defaultConfig, "Path to config file")
 	flag.DurationVar(&cfg.runFor, "run-for", 0, "Auto-stop after duration. 0 = run until SIGINT/SIGTERM")
-	flag.StringVar(&cfg.parser, "parser", "", "Parser name (e.g. fonbet, pinnacle, pinnacle888). Can also set BOOKMAKER_PARSER")
+	flag.StringVar(&cfg.parser, "parser", "", "Parser name or comma list run in one process (e.g. fonbet, pinnacle888, fonbet,leon). Can also set BOOKMAKER_PARSER")
+	flag.BoolVar(&cfg.dryRun, "dry-run", false, "Run ParseOnce once and write matches as JSON instead of serving them (parser.dry_run.enabled)")
+	flag.StringVar(&cfg.dryRunOutput, "dry-run-output", "", "Dry-run JSON file ('-' = stdout). Empty = parser.dry_run.output")
+	flag.BoolVar(&cfg.debugEndpoints, "debug-endpoints", os.Getenv("DEBUG_ENDPOINTS") == "true", "Serve /debug/pprof/ and /debug/runtime on the health server (or set DEBUG_ENDPOINTS=true)")
 	flag.Parse()
 	return cfg
 }
 
+// parseParserList splits -parser / BOOKMAKER_PARSER ("fonbet, Leon") into unique lowercase names, sorted.
+func parseParserList(s string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func selectParsers(cfg *pkgconfig.Config) ([]parsers.Parser, error) {
 	available := parsers.Available()
 	enabledSet := make(map[string]bool)
@@ -139,15 +310,29 @@ func selectParsers(cfg *pkgconfig.Config) ([]parsers.Parser, error) {
 		sort.Strings(unknown)
 		return nil, fmt.Errorf("unknown parsers: %v (available: %v)", unknown, parsers.AvailableNames())
 	}
-	var ps []parsers.Parser
-	for key, ctor := range available {
-		if enabledSet[key] {
-			ps = append(ps, ctor(cfg))
-		}
+	keys := make([]string, 0, len(enabledSet))
+	for key := range enabledSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	ps := make([]parsers.Parser, 0, len(keys))
+	for _, key := range keys {
+		ps = append(ps, available[key](cfg))
 	}
 	return ps, nil
 }
 
+// dryRunConfig applies the -dry-run and -dry-run-output flags on top of parser.dry_run.
+func dryRunConfig(dr pkgconfig.DryRunConfig, cfg config) pkgconfig.DryRunConfig {
+	if cfg.dryRun {
+		dr.Enabled = true
+	}
+	if cfg.dryRunOutput != "" {
+		dr.Output = cfg.dryRunOutput
+	}
+	return dr
+}
+
 func createContext(runFor time.Duration) (context.Context, context.CancelFunc) {
 	if runFor > 0 {
 		return context.WithTimeout(context.Background(), runFor)
@@ -155,13 +340,30 @@ func createContext(runFor time.Duration) (context.Context, context.CancelFunc) {
 	return context.WithCancel(context.Background())
 }
 
-func setupSignalHandler(ctx context.Context, cancel context.CancelFunc) {
+// setupSignalHandler drains the parsers on SIGINT/SIGTERM before canceling ctx: no new league or cycle
+// starts and the ones in progress get up to grace to finish and store their matches, which are then
+// served for flushWait more. A second signal cancels at once.
+func setupSignalHandler(ctx context.Context, cancel context.CancelFunc, grace, flushWait time.Duration) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		select {
 		case sig := <-sigChan:
-			slog.Info("Received shutdown signal", "signal", sig.String())
+			slog.Info("Received shutdown signal, draining parsers", "signal", sig.String(), "grace_period", grace, "active_cycles", parserutil.ActiveCycles())
+			drained := make(chan bool, 1)
+			go func() { drained <- parserutil.Drain(grace) }()
+			select {
+			case ok := <-drained:
+				if ok {
+					slog.Info("Parsers drained", "flush_wait", flushWait)
+					time.Sleep(flushWait)
+				} else {
+					slog.Warn("Shutdown grace period expired, canceling cycles in progress", "active_cycles", parserutil.ActiveCycles())
+				}
+			case sig := <-sigChan:
+				slog.Warn("Received second shutdown signal, stopping without draining", "signal", sig.String())
+			case <-ctx.Done():
+			}
 			cancel()
 		case <-ctx.Done():
 			signal.Stop(sigChan)
@@ -170,67 +372,40 @@ func setupSignalHandler(ctx context.Context, cancel context.CancelFunc) {
 	}()
 }
 
-func runParsers(ctx context.Context, interfaceParsers []interfaces.Parser, appConfig *pkgconfig.Config, asyncParsingTimeout time.Duration) error {
-	// Check if incremental parsing is enabled
+func runParsers(ctx context.Context, keys []string, interfaceParsers []interfaces.Parser, appConfig *pkgconfig.Config, asyncParsingTimeout time.Duration) error {
 	incConfig := appConfig.Parser.IncrementalParsing
 	if incConfig.Enabled {
-		timeout := incConfig.Timeout
-		if timeout <= 0 {
+		if incConfig.Timeout <= 0 {
 			slog.Info("Incremental parsing mode enabled without timeout - will process all leagues", "timeout", "unlimited")
 		} else {
-			slog.Info("Incremental parsing mode enabled with timeout", "timeout", timeout)
-		}
-		// Try to use incremental parsing if parser supports it
-		incrementalFound := false
-		for _, p := range interfaceParsers {
-			if incParser, ok := p.(interfaces.IncrementalParser); ok {
-				incrementalFound = true
-				slog.Info("Starting incremental parsing", "parser", p.GetName(), "timeout", timeout)
-				opts := parserutil.AsyncRunOptions()
-				opts.LogStart = true
-				opts.OnError = func(p interfaces.Parser, err error) {
-					slog.Error("Incremental parser failed", "parser", p.GetName(), "error", err)
-				}
-				_ = parserutil.RunParsers(ctx, []interfaces.Parser{p}, func(ctx context.Context, p interfaces.Parser) error {
-					slog.Info("Calling StartIncremental", "parser", p.GetName(), "timeout", timeout)
-					return incParser.StartIncremental(ctx, timeout)
-				}, opts)
-				continue
-			} else {
-				slog.Info("Parser does not support incremental mode, will use regular mode", "parser", p.GetName())
-			}
-		}
-		if !incrementalFound {
-			// If no incremental parsers found, fall back to regular mode
-			slog.Warn("Incremental parsing enabled but no parsers support it, falling back to regular mode")
+			slog.Info("Incremental parsing mode enabled with timeout", "timeout", incConfig.Timeout)
 		}
 	} else {
 		slog.Info("Incremental parsing disabled, using regular parsing mode")
 	}
 
-	// Regular mode: start parsers and periodic parsing
-	opts := parserutil.AsyncRunOptions()
-	opts.LogStart = true
-	opts.OnError = func(p interfaces.Parser, err error) {
-		slog.Error("Parser failed", "parser", p.GetName(), "error", err)
+	// Each parser runs under its own context so POST /admin/restart-parser can restart it alone
+	sup := newSupervisor(ctx, appConfig)
+	for i, p := range interfaceParsers {
+		sup.start(keys[i], p)
 	}
-	_ = parserutil.RunParsers(ctx, interfaceParsers, func(ctx context.Context, p interfaces.Parser) error {
-		return p.Start(ctx)
-	}, opts)
+	health.SetParserRestarter(sup.restart)
 
 	parseInterval := appConfig.Parser.Interval
 	if parseInterval <= 0 {
 		parseInterval = 2 * time.Minute
 		slog.Info("parser.interval not set, using default", "interval", parseInterval)
 	}
-	startPeriodicParsing(ctx, interfaceParsers, parseInterval, asyncParsingTimeout)
+	startPeriodicParsing(ctx, parseInterval, asyncParsingTimeout)
 
 	<-ctx.Done()
 	slog.Info("Bookmaker service stopped gracefully")
 	return nil
 }
 
-func startPeriodicParsing(ctx context.Context, parsers []interfaces.Parser, interval time.Duration, timeout time.Duration) {
+// startPeriodicParsing polls the registered parsers (health.GetParsers, so restarted instances are
+// picked up) every interval.
+func startPeriodicParsing(ctx context.Context, interval time.Duration, timeout time.Duration) {
 	opts := parserutil.AsyncRunOptions()
 	opts.OnError = func(p interfaces.Parser, err error) {
 		slog.Error("Periodic parsing failed", "parser", p.GetName(), "error", err)
@@ -245,10 +420,13 @@ func startPeriodicParsing(ctx context.Context, parsers []interfaces.Parser, inte
 				slog.Info("Periodic parsing stopped")
 				return
 			case <-ticker.C:
+				if parserutil.Draining() {
+					continue
+				}
 				slog.Info("Periodic parsing tick triggered")
 				// For incremental parsers, just trigger new cycle (non-blocking)
 				// For regular parsers, run full ParseOnce
-				for _, p := range parsers {
+				for _, p := range health.GetParsers() {
 					if incParser, ok := p.(interfaces.IncrementalParser); ok {
 						// Trigger new cycle without blocking
 						slog.Info("Triggering new incremental cycle", "parser", p.GetName())
@@ -263,9 +441,11 @@ func startPeriodicParsing(ctx context.Context, parsers []interfaces.Parser, inte
 						parseCtx, cancel := context.WithTimeout(context.Background(), timeout)
 						opts.WaitForCompletion = true
 						_ = parserutil.RunParsers(parseCtx, []interfaces.Parser{p}, func(ctx context.Context, p interfaces.Parser) error {
-							return p.ParseOnce(ctx)
+							_, err := parserutil.ParseOnceReport(ctx, p)
+							return err
 						}, opts)
 						cancel()
+						health.ParserCycleDone(p.GetName())
 					}
 				}
 			}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers"
+	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+)
+
+// supervisor runs each parser of the process under its own context, so one parser can be
+// restarted (POST /admin/restart-parser) while the others keep running.
+type supervisor struct {
+	ctx       context.Context
+	appConfig *pkgconfig.Config
+
+	mu      sync.Mutex
+	running []*supervisedParser
+}
+
+// supervisedParser is one running parser instance.
+type supervisedParser struct {
+	key    string // registry name (e.g. "xbet1"), also the mirror registry key
+	parser interfaces.Parser
+	cancel context.CancelFunc
+}
+
+func newSupervisor(ctx context.Context, appConfig *pkgconfig.Config) *supervisor {
+	return &supervisor{ctx: ctx, appConfig: appConfig}
+}
+
+// start runs p (registered as key) under a child context of the service context.
+func (s *supervisor) start(key string, p interfaces.Parser) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.running = append(s.running, &supervisedParser{key: key, parser: p, cancel: cancel})
+	s.mu.Unlock()
+	runParser(ctx, p, s.appConfig)
+}
+
+// restart stops the named parser (registry key or GetName), drops its resolved mirror and starts
+// a fresh instance from the factory, so no client state (sessions, cookies, mirror) survives.
+func (s *supervisor) restart(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sp *supervisedParser
+	for _, r := range s.running {
+		if r.key == name || strings.ToLower(r.parser.GetName()) == name {
+			sp = r
+			break
+		}
+	}
+	if sp == nil {
+		return fmt.Errorf("parser %q is not running in this service", name)
+	}
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("service is shutting down")
+	}
+	factory, ok := parsers.FactoryByName(sp.key)
+	if !ok {
+		return fmt.Errorf("parser %q is not registered", sp.key)
+	}
+
+	started := time.Now()
+	slog.Info("Restarting parser", "parser", sp.parser.GetName())
+	sp.cancel()
+	if err := sp.parser.Stop(); err != nil {
+		slog.Warn("Parser stop failed during restart, starting a new instance anyway", "parser", sp.parser.GetName(), "error", err)
+	}
+	mirrors.Invalidate(sp.key, "parser restarted")
+
+	old := sp.parser
+	sp.parser = factory(s.appConfig)
+	health.ReplaceParser(old, sp.parser)
+	ctx, cancel := context.WithCancel(s.ctx)
+	sp.cancel = cancel
+	runParser(ctx, sp.parser, s.appConfig)
+
+	eventlog.Record(eventlog.Event{
+		Type:   eventlog.TypeParserRestart,
+		Parser: sp.parser.GetName(),
+		Fields: map[string]interface{}{"took": time.Since(started).Round(time.Millisecond).String()},
+	})
+	slog.Info("Parser restarted", "parser", sp.parser.GetName())
+	return nil
+}
+
+// runParser starts p in the background: the incremental loop when parser.incremental_parsing is
+// enabled and p supports it, and Start (initial run, then wait for ctx) in every case.
+func runParser(ctx context.Context, p interfaces.Parser, appConfig *pkgconfig.Config) {
+	incConfig := appConfig.Parser.IncrementalParsing
+	if incParser, ok := p.(interfaces.IncrementalParser); ok && incConfig.Enabled {
+		timeout := incConfig.Timeout
+		slog.Info("Starting incremental parsing", "parser", p.GetName(), "timeout", timeout)
+		opts := parserutil.AsyncRunOptions()
+		opts.LogStart = true
+		opts.OnError = func(p interfaces.Parser, err error) {
+			slog.Error("Incremental parser failed", "parser", p.GetName(), "error", err)
+		}
+		_ = parserutil.RunParsers(ctx, []interfaces.Parser{p}, func(ctx context.Context, p interfaces.Parser) error {
+			slog.Info("Calling StartIncremental", "parser", p.GetName(), "timeout", timeout)
+			return incParser.StartIncremental(ctx, timeout)
+		}, opts)
+	} else if incConfig.Enabled {
+		slog.Info("Parser does not support incremental mode, will use regular mode", "parser", p.GetName())
+	}
+
+	opts := parserutil.AsyncRunOptions()
+	opts.LogStart = true
+	opts.OnError = func(p interfaces.Parser, err error) {
+		slog.Error("Parser failed", "parser", p.GetName(), "error", err)
+	}
+	_ = parserutil.RunParsers(ctx, []interfaces.Parser{p}, func(ctx context.Context, p interfaces.Parser) error {
+		return p.Start(ctx)
+	}, opts)
+}
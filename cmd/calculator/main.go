@@ -8,17 +8,29 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/calculator/calculator"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/bus"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/chaos"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/logging"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 const (
 	defaultConfigPath = "configs/production.yaml"
+	defaultSQLitePath = "data/vodeneevbet.db"
 )
 
 func main() {
@@ -51,6 +63,11 @@ func main() {
 	} else {
 		slog.Info("Logging initialized", "service", "calculator")
 	}
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, "calculator")
+	if err != nil {
+		slog.Warn("Failed to setup tracing, continuing without it", "error", err)
+	}
+	defer shutdownTracing(context.Background()) //nolint:errcheck // best-effort flush on exit
 
 	slog.Info("Config loaded successfully")
 
@@ -59,6 +76,8 @@ func main() {
 		os.Exit(1)
 	}
 	slog.Info("Using parser URL", "url", cfg.ValueCalculator.ParserURL)
+	// Key for the parser API when it requires one (api_auth)
+	apiauth.UseKey(os.Getenv(apiauth.KeyEnv), cfg.ValueCalculator.ParserURL)
 
 	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
 		cfg.ValueCalculator.TelegramBotToken = token
@@ -71,10 +90,61 @@ func main() {
 		}
 	}
 
+	// WebApp access: same ALLOWED_USERS list as the telegram-bot
+	if allowedUsers := os.Getenv("ALLOWED_USERS"); allowedUsers != "" {
+		cfg.ValueCalculator.WebApp.AllowedUserIDs = nil
+		for _, idStr := range strings.Split(allowedUsers, ",") {
+			if id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64); err == nil {
+				cfg.ValueCalculator.WebApp.AllowedUserIDs = append(cfg.ValueCalculator.WebApp.AllowedUserIDs, id)
+			}
+		}
+		slog.Info("Using WebApp allowed users from environment", "allowed_count", len(cfg.ValueCalculator.WebApp.AllowedUserIDs))
+	}
+
 	// Initialize PostgreSQL storage for diffs if async is enabled
 	var diffStorage storage.DiffBetStorage
 	var oddsSnapshotStorage storage.OddsSnapshotStorage
-	if cfg.ValueCalculator.AsyncEnabled {
+	var ignoreStorage storage.IgnoreStorage
+	var teamAliasStorage storage.TeamAliasStorage
+	var leagueAliasStorage storage.LeagueAliasStorage
+	var subscriptionStorage storage.SubscriptionStorage
+	var chatLanguageStorage storage.ChatLanguageStorage
+	var warehouseStorage storage.WarehouseStorage
+	var arbitrageStorage storage.ArbitrageStorage
+	var betStorage storage.BetStorage
+	var valueHistoryStorage storage.ValueHistoryStorage
+	var decisionStorage storage.AlertDecisionStorage
+	var resultStorage storage.ResultStorage
+	var leaderLock storage.LeaderLock
+	var cycleStateStorage storage.CycleStateStorage
+	if cfg.ValueCalculator.AsyncEnabled && cfg.Storage.Backend == config.StorageBackendSQLite {
+		// Single-host deployment: diffs in a SQLite file, the features needing Postgres stay off
+		sqlitePath := cfg.Storage.SQLitePath
+		if sqlitePath == "" {
+			sqlitePath = defaultSQLitePath
+		}
+		slog.Info("Initializing SQLite diff storage...", "path", sqlitePath)
+		sqliteStorage, err := storage.NewSQLiteDiffStorage(sqlitePath)
+		if err != nil {
+			slog.Error("Failed to initialize SQLite storage", "error", err)
+			os.Exit(1)
+		}
+		diffStorage = sqliteStorage
+		defer func() {
+			if err := sqliteStorage.Close(); err != nil {
+				slog.Error("Error closing SQLite storage", "error", err)
+			}
+		}()
+		cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := sqliteStorage.CleanDiffBets(cleanCtx); err != nil {
+			slog.Warn("Failed to clean diff_bets table", "error", err)
+		}
+		cleanCancel()
+		if cfg.ValueCalculator.LeaderElection.Enabled {
+			slog.Warn("leader_election requires postgres, ignored with storage.backend sqlite")
+		}
+		slog.Warn("storage.backend is sqlite: odds history, line movements, ignores, subscriptions, bets, value history and the warehouse are not stored")
+	} else if cfg.ValueCalculator.AsyncEnabled {
 		// Allow DSN override via environment variable
 		postgresDSN := cfg.Postgres.DSN
 		if envDSN := os.Getenv("POSTGRES_DSN"); envDSN != "" {
@@ -90,6 +160,16 @@ func main() {
 		pgConfig := cfg.Postgres
 		pgConfig.DSN = postgresDSN
 
+		// The storages expect the schema of the embedded migrations (also: go run ./cmd/migrate)
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 10*time.Minute)
+		applied, err := storage.MigratePostgres(migrateCtx, &pgConfig)
+		cancelMigrate()
+		if err != nil {
+			slog.Error("Failed to apply PostgreSQL migrations", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("PostgreSQL schema is up to date", "migrations_applied", len(applied))
+
 		slog.Info("Initializing PostgreSQL diff storage...")
 		pgStorage, err := storage.NewPostgresDiffStorage(&pgConfig)
 		if err != nil {
@@ -104,14 +184,176 @@ func main() {
 		}()
 		slog.Info("PostgreSQL diff storage initialized")
 
-		// Clean diff_bets table on startup to prevent stale data from blocking alerts
-		slog.Info("Cleaning diff_bets table on startup...")
-		cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cleanCancel()
-		if err := pgStorage.CleanDiffBets(cleanCtx); err != nil {
-			slog.Warn("Failed to clean diff_bets table", "error", err)
+		// Clean diff_bets table on startup to prevent stale data from blocking alerts.
+		// Not with leader election: the running leader restores alerts from it after a failover.
+		if !cfg.ValueCalculator.LeaderElection.Enabled {
+			slog.Info("Cleaning diff_bets table on startup...")
+			cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cleanCancel()
+			if err := pgStorage.CleanDiffBets(cleanCtx); err != nil {
+				slog.Warn("Failed to clean diff_bets table", "error", err)
+			} else {
+				slog.Info("diff_bets table cleaned successfully")
+			}
+		}
+
+		// Several replicas on one database: only the lock holder runs the async cycle (value_calculator.leader_election)
+		if cfg.ValueCalculator.LeaderElection.Enabled {
+			lockPg, err := storage.NewPostgresLeaderLock(&pgConfig, cfg.ValueCalculator.LeaderElection.LockKey)
+			if err != nil {
+				slog.Error("Failed to initialize leader lock", "error", err)
+				os.Exit(1)
+			}
+			leaderLock = lockPg
+			defer func() {
+				_ = lockPg.Close()
+			}()
+			statePg, err := storage.NewPostgresCycleStateStorage(&pgConfig)
+			if err != nil {
+				slog.Error("Failed to initialize cycle state storage", "error", err)
+				os.Exit(1)
+			}
+			cycleStateStorage = statePg
+			defer func() {
+				_ = statePg.Close()
+			}()
+		}
+
+		// Research warehouse: denormalized copy of odds history for notebooks (value_calculator.warehouse)
+		if cfg.ValueCalculator.Warehouse.Enabled {
+			warehousePg, err := storage.NewPostgresWarehouseStorage(&pgConfig)
+			if err != nil {
+				slog.Error("Failed to initialize research warehouse", "error", err)
+				os.Exit(1)
+			}
+			warehouseStorage = warehousePg
+			defer func() {
+				_ = warehousePg.Close()
+			}()
+		}
+
+		// Surebets found on every async cycle (value_calculator.arbitrage)
+		if cfg.ValueCalculator.Arbitrage.Enabled {
+			arbPg, err := storage.NewPostgresArbitrageStorage(&pgConfig)
+			if err != nil {
+				slog.Error("Failed to initialize arbitrage storage", "error", err)
+				os.Exit(1)
+			}
+			arbitrageStorage = arbPg
+			defer func() {
+				_ = arbPg.Close()
+			}()
+		}
+
+		// Placed bets and their settlement (value_calculator.bets)
+		if cfg.ValueCalculator.Bets.Enabled {
+			betPg, err := storage.NewPostgresBetStorage(&pgConfig)
+			if err != nil {
+				slog.Error("Failed to initialize bet storage", "error", err)
+				os.Exit(1)
+			}
+			betStorage = betPg
+			defer func() {
+				_ = betPg.Close()
+			}()
+		}
+
+		// Value bet lifecycles (value_calculator.value_history)
+		if cfg.ValueCalculator.ValueHistory.Enabled {
+			historyPg, err := storage.NewPostgresValueHistoryStorage(&pgConfig)
+			if err != nil {
+				slog.Error("Failed to initialize value history storage", "error", err)
+				os.Exit(1)
+			}
+			valueHistoryStorage = historyPg
+			defer func() {
+				_ = historyPg.Close()
+			}()
+		}
+
+		// Alert decision log (value_calculator.decision_log): Postgres or JSONL files
+		if dl := cfg.ValueCalculator.DecisionLog; dl.Enabled {
+			var err error
+			if dl.Backend == "file" {
+				decisionStorage, err = storage.NewFileAlertDecisionStorage(dl.Dir)
+			} else {
+				decisionStorage, err = storage.NewPostgresAlertDecisionStorage(&pgConfig)
+			}
+			if err != nil {
+				slog.Warn("Failed to initialize alert decision log, decisions are not recorded", "backend", dl.Backend, "error", err)
+				decisionStorage = nil
+			} else {
+				defer func() {
+					_ = decisionStorage.Close()
+				}()
+			}
+		}
+
+		// Final match results fetched once per match (value_calculator.results.cache)
+		if cfg.ValueCalculator.Results.Cache {
+			resultPg, err := storage.NewPostgresResultStorage(&pgConfig)
+			if err != nil {
+				slog.Warn("Failed to initialize result cache, results are fetched on every lookup", "error", err)
+			} else {
+				resultStorage = resultPg
+				defer func() {
+					_ = resultPg.Close()
+				}()
+			}
+		}
+
+		// Ignored matches survive restarts (POST /ignores, bot button)
+		ignorePg, err := storage.NewPostgresIgnoreStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Failed to initialize ignore storage, ignore list is in memory only", "error", err)
 		} else {
-			slog.Info("diff_bets table cleaned successfully")
+			ignoreStorage = ignorePg
+			defer func() {
+				_ = ignorePg.Close()
+			}()
+		}
+
+		// Team and league aliases added through POST /admin/team-aliases and /admin/league-aliases survive restarts
+		teamAliasPg, err := storage.NewPostgresTeamAliasStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Failed to initialize team alias storage, added aliases are in memory only", "error", err)
+		} else {
+			teamAliasStorage = teamAliasPg
+			defer func() {
+				_ = teamAliasPg.Close()
+			}()
+		}
+
+		leagueAliasPg, err := storage.NewPostgresLeagueAliasStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Failed to initialize league alias storage, added aliases are in memory only", "error", err)
+		} else {
+			leagueAliasStorage = leagueAliasPg
+			defer func() {
+				_ = leagueAliasPg.Close()
+			}()
+		}
+
+		// Alert subscriptions survive restarts (POST /subscriptions)
+		subscriptionPg, err := storage.NewPostgresSubscriptionStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Failed to initialize subscription storage, subscriptions are in memory only", "error", err)
+		} else {
+			subscriptionStorage = subscriptionPg
+			defer func() {
+				_ = subscriptionPg.Close()
+			}()
+		}
+
+		// Chat languages survive restarts (POST /chat-language, /lang in the bot)
+		chatLanguagePg, err := storage.NewPostgresChatLanguageStorage(&pgConfig)
+		if err != nil {
+			slog.Warn("Failed to initialize chat language storage, chat languages are in memory only", "error", err)
+		} else {
+			chatLanguageStorage = chatLanguagePg
+			defer func() {
+				_ = chatLanguagePg.Close()
+			}()
 		}
 
 		// Odds snapshot storage for line movement (прогрузы) tracking
@@ -130,7 +372,140 @@ func main() {
 		}
 	}
 
+	// Event log: the calculator's events merged with the parser's (/events)
+	eventlog.Configure("calculator", cfg.EventLog)
+	eventlog.SetRemotes(map[string]string{"parser": cfg.ValueCalculator.ParserURL})
+
+	// Fault injection into the HTTP server (chaos.enabled, test only)
+	chaos.Configure("calculator", cfg.Chaos)
+
+	// API keys and roles of the HTTP API (api_auth)
+	if err := apiauth.Configure("calculator", cfg.APIAuth); err != nil {
+		slog.Error("Invalid api_auth", "error", err)
+		os.Exit(1)
+	}
+
+	// Match groups bucket start times as the parsers' match IDs do (parser.start_time_tolerance)
+	models.SetStartTimeTolerance(cfg.Parser.StartTimeTolerance)
+	// Odds precision policy: comparison epsilon and decimals in alerts (odds.*)
+	models.SetOddsPrecision(cfg.Odds.Epsilon, cfg.Odds.Decimals)
+	if n, err := models.ConfigureTeamAliases(cfg.TeamAliases.File); err != nil {
+		slog.Error("Team aliases: failed to load file, using embedded seed only", "file", cfg.TeamAliases.File, "error", err)
+	} else {
+		slog.Info("Team aliases configured", "aliases", n, "file", cfg.TeamAliases.File)
+	}
+	if n, err := models.ConfigureLeagueAliases(cfg.LeagueAliases.File); err != nil {
+		slog.Error("League aliases: failed to load file, using embedded seed only", "file", cfg.LeagueAliases.File, "error", err)
+	} else {
+		slog.Info("League aliases configured", "aliases", n, "file", cfg.LeagueAliases.File)
+	}
+
+	// Telegram message templates (telegram.*): built-in ones unless replaced in the config
+	templates, err := tgformat.New(&cfg.Telegram)
+	if err != nil {
+		slog.Error("Failed to load Telegram templates", "error", err)
+		os.Exit(1)
+	}
+
 	valueCalculator := calculator.NewValueCalculator(&cfg.ValueCalculator, diffStorage, oddsSnapshotStorage)
+	valueCalculator.SetTelegramTemplates(templates)
+	valueCalculator.SetTelegramDelivery(cfg.Telegram.Delivery)
+	if warehouseStorage != nil {
+		valueCalculator.SetWarehouse(warehouseStorage)
+	}
+	if arbitrageStorage != nil {
+		valueCalculator.SetArbitrageStorage(arbitrageStorage)
+	}
+	if betStorage != nil {
+		valueCalculator.SetBetStorage(betStorage)
+	}
+	if decisionStorage != nil {
+		valueCalculator.SetAlertDecisionStorage(decisionStorage)
+	}
+	if valueHistoryStorage != nil {
+		valueCalculator.SetValueHistoryStorage(valueHistoryStorage)
+	}
+	if leaderLock != nil {
+		valueCalculator.SetLeaderElection(leaderLock, cycleStateStorage)
+	}
+	// Long-term archive of every odds snapshot (storage.clickhouse)
+	if ch := cfg.Storage.ClickHouse; ch.Enabled {
+		if password := os.Getenv("CLICKHOUSE_PASSWORD"); password != "" {
+			ch.Password = password
+		}
+		archive, err := storage.NewClickHouseOddsArchive(&ch)
+		if err != nil {
+			slog.Warn("ClickHouse odds archive disabled", "error", err)
+		} else {
+			valueCalculator.SetOddsArchive(archive, &ch)
+		}
+	}
+	// Results source for bet settlement and calibration (value_calculator.results)
+	if apiKey := os.Getenv("FOOTBALL_DATA_API_KEY"); apiKey != "" {
+		cfg.ValueCalculator.Results.APIKey = apiKey
+	}
+	resultsProvider, err := results.New(cfg.ValueCalculator.Results)
+	if err != nil {
+		slog.Warn("Results provider disabled", "error", err)
+	}
+	if resultsProvider != nil {
+		if resultStorage != nil {
+			resultsProvider = results.NewCachedProvider(resultsProvider, resultStorage)
+		}
+		valueCalculator.SetResultsProvider(resultsProvider)
+	} else if betStorage != nil {
+		slog.Warn("No results provider configured (value_calculator.results), bets will not be settled automatically")
+	}
+	// Matches from the message bus instead of polling parser_url/matches (bus.consume)
+	if cfg.Bus.Consume {
+		b, err := bus.Connect(cfg.Bus, "calculator")
+		if err != nil {
+			slog.Error("Failed to connect to the bus", "error", err)
+			os.Exit(1)
+		}
+		defer b.Close()
+		cache := bus.NewMatchCache(cfg.Bus.MatchTTL)
+		if err := b.SubscribeMatches(cache.Add); err != nil {
+			slog.Error("Failed to subscribe to matches on the bus", "error", err)
+			os.Exit(1)
+		}
+		valueCalculator.SetBusMatches(cache)
+	}
+	if ignoreStorage != nil {
+		loadCtx, loadCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := valueCalculator.SetIgnoreStorage(loadCtx, ignoreStorage); err != nil {
+			slog.Warn("Failed to load ignored matches, ignore list is in memory only", "error", err)
+		}
+		loadCancel()
+	}
+	if teamAliasStorage != nil {
+		loadCtx, loadCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := valueCalculator.SetTeamAliasStorage(loadCtx, teamAliasStorage); err != nil {
+			slog.Warn("Failed to load team aliases, added aliases are in memory only", "error", err)
+		}
+		loadCancel()
+	}
+	if leagueAliasStorage != nil {
+		loadCtx, loadCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := valueCalculator.SetLeagueAliasStorage(loadCtx, leagueAliasStorage); err != nil {
+			slog.Warn("Failed to load league aliases, added aliases are in memory only", "error", err)
+		}
+		loadCancel()
+	}
+	if subscriptionStorage != nil {
+		loadCtx, loadCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := valueCalculator.SetSubscriptionStorage(loadCtx, subscriptionStorage); err != nil {
+			slog.Warn("Failed to load subscriptions, subscriptions are in memory only", "error", err)
+		}
+		loadCancel()
+	}
+	if chatLanguageStorage != nil {
+		loadCtx, loadCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := valueCalculator.SetChatLanguageStorage(loadCtx, chatLanguageStorage); err != nil {
+			slog.Warn("Failed to load chat languages, chat languages are in memory only", "error", err)
+		}
+		loadCancel()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -145,19 +520,15 @@ func main() {
 	}()
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		_, _ = w.Write([]byte("pong\n"))
-	})
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		_, _ = w.Write([]byte("ok\n"))
-	})
+	// /healthz, /readyz (database, Telegram token) and /health/dependencies (also the parser)
+	checks := healthcheck.New("calculator")
+	valueCalculator.RegisterHealthChecks(checks)
+	checks.Register(mux)
 	valueCalculator.RegisterHTTP(mux)
 
 	srv := &http.Server{
 		Addr:              healthAddr,
-		Handler:           mux,
+		Handler:           tracing.Handler(apiauth.Middleware(chaos.Middleware(mux)), "calculator"),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
@@ -173,6 +544,12 @@ func main() {
 			slog.Error("HTTP server error", "error", err)
 		}
 	}()
+	if grpcPort := cfg.ValueCalculator.GRPC.Port; grpcPort > 0 {
+		if err := valueCalculator.RunGRPC(ctx, health.AddrFor(grpcPort)); err != nil {
+			slog.Error("Failed to start gRPC server", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	slog.Info("Starting Value Bet Calculator...")
 	if err := valueCalculator.Start(ctx); err != nil {
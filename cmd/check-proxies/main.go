@@ -1,9 +1,13 @@
 // check-proxies loads proxy_list from config and tests each proxy (connectivity + auth).
 // Use to verify proxies work and payment has not expired.
+//
+// With -service it instead reports the live proxy pool of running services (GET /proxies):
+// success rate, latency and bans as seen by the parsers.
 package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
@@ -14,6 +18,7 @@ import (
 	"time"
 
 	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
 )
 
 const (
@@ -23,8 +28,16 @@ const (
 
 func main() {
 	configPath := flag.String("config", "configs/production.yaml", "Path to YAML config with proxy_list")
+	services := flag.String("service", "", "Comma-separated base URLs of running services (e.g. http://localhost:8080) to report live pool state")
 	flag.Parse()
 
+	if *services != "" {
+		if !reportPools(strings.Split(*services, ",")) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	cfg, err := pkgconfig.Load(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
@@ -131,3 +144,49 @@ func maskProxy(proxyURL string) string {
 	}
 	return u.String()
 }
+
+// reportPools prints the live proxy pool of each service. Returns false if any service could not be queried.
+func reportPools(services []string) bool {
+	client := &http.Client{Timeout: timeoutSec * time.Second}
+	ok := true
+	for _, base := range services {
+		base = strings.TrimSuffix(strings.TrimSpace(base), "/")
+		if base == "" {
+			continue
+		}
+		resp, err := client.Get(base + "/proxies")
+		if err != nil {
+			fmt.Printf("[FAIL] %s -> %v\n", base, err)
+			ok = false
+			continue
+		}
+		var pool handlers.ProxiesResponse
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(&pool)
+		}
+		resp.Body.Close()
+		if err != nil {
+			fmt.Printf("[FAIL] %s -> %v\n", base, err)
+			ok = false
+			continue
+		}
+
+		fmt.Printf("%s: %d proxies, %d banned\n", base, pool.Count, pool.Banned)
+		for _, p := range pool.Proxies {
+			state := "OK"
+			if p.Banned {
+				state = "BANNED until " + p.BannedUntil.Local().Format("15:04:05")
+			}
+			fmt.Printf("  [%s] %s pools=%s success=%d fail=%d rate=%.0f%% latency=%dms",
+				state, p.Proxy, strings.Join(p.Pools, ","), p.Successes, p.Failures, p.SuccessRate*100, p.AvgLatencyMs)
+			if p.LastError != "" {
+				fmt.Printf(" last_error=%q", p.LastError)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+	return ok
+}
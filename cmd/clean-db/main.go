@@ -1,4 +1,5 @@
-// clean-db truncates calculator PostgreSQL tables to free space.
+// clean-db truncates calculator PostgreSQL tables to free space. odds_snapshot_history is not touched:
+// the calculator drops its day partitions after value_calculator.odds_history.retention.
 // Usage: set POSTGRES_DSN (same as for calculator), then run:
 //
 //	go run ./cmd/clean-db
@@ -45,7 +46,7 @@ func main() {
 		}
 	}()
 
-	tables := []string{"diff_bets", "odds_snapshots", "odds_snapshot_history"}
+	tables := []string{"diff_bets", "odds_snapshots"}
 	for _, table := range tables {
 		_, err = tx.ExecContext(ctx, fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY", table))
 		if err != nil {
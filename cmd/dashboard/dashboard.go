@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+)
+
+// static is the dashboard page; it has no data of its own and polls /api/overview.
+//
+//go:embed static
+var static embed.FS
+
+// overviewTimeout bounds one /api/overview: value bets are computed by the calculator on request.
+const overviewTimeout = 30 * time.Second
+
+// dashboard serves the page and /api/overview, collected from the calculator and parser APIs.
+type dashboard struct {
+	calculator upstream
+	parser     upstream // parser orchestrator health server (nil = no parser sections)
+}
+
+// upstream is the part of the API clients the dashboard uses: the JSON of an endpoint as is, and
+// whether the service is up.
+type upstream interface {
+	GetJSON(ctx context.Context, path string, out interface{}) error
+	Ping(ctx context.Context) error
+}
+
+// section is one block of the overview: the upstream JSON as is, or why it is missing.
+type section struct {
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// overview is the JSON of /api/overview.
+type overview struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	Stats         section   `json:"stats"`          // parser /stats: runs and the last cycle of every parser
+	Parsers       section   `json:"parsers"`        // parser /parsers: pause state
+	Calculator    section   `json:"calculator"`     // calculator /diffs/status: async processing, Telegram delivery
+	ValueBets     section   `json:"value_bets"`     // calculator /value-bets/top
+	LineMovements section   `json:"line_movements"` // calculator /line-movements/top
+	Subscriptions section   `json:"subscriptions"`  // calculator /subscriptions
+}
+
+func (d *dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	assets, _ := fs.Sub(static, "static")
+	mux.Handle("GET /", http.FileServer(http.FS(assets)))
+	mux.HandleFunc("GET /api/overview", d.handleOverview)
+	d.healthChecks().Register(mux)
+	return mux
+}
+
+// healthChecks observes the upstreams: the overview only loses their sections when they are down, so
+// the dashboard stays ready.
+func (d *dashboard) healthChecks() *healthcheck.Checker {
+	checks := healthcheck.New("dashboard")
+	if d.calculator != nil {
+		checks.Observe("calculator", d.calculator.Ping)
+	}
+	if d.parser != nil {
+		checks.Observe("parser", d.parser.Ping)
+	}
+	return checks
+}
+
+// handleOverview fetches every section in parallel; a failing upstream only fails its sections.
+// GET /api/overview
+func (d *dashboard) handleOverview(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), overviewTimeout)
+	defer cancel()
+
+	resp := overview{UpdatedAt: time.Now().UTC()}
+	sources := []struct {
+		api  upstream
+		path string
+		out  *section
+	}{
+		{d.parser, "/stats", &resp.Stats},
+		{d.parser, "/parsers", &resp.Parsers},
+		{d.calculator, "/diffs/status", &resp.Calculator},
+		{d.calculator, "/value-bets/top?limit=20", &resp.ValueBets},
+		{d.calculator, "/line-movements/top?limit=20", &resp.LineMovements},
+		{d.calculator, "/subscriptions", &resp.Subscriptions},
+	}
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		if src.api == nil {
+			src.out.Error = "not configured"
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var data json.RawMessage
+			if err := src.api.GetJSON(ctx, src.path, &data); err != nil {
+				slog.Warn("Dashboard: failed to fetch", "path", src.path, "error", err)
+				src.out.Error = err.Error()
+				return
+			}
+			src.out.Data = data
+		}()
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode dashboard overview", "error", err)
+	}
+}
+
+// basicAuth protects h with HTTP basic auth of credentials ("user:password"; "" = open).
+func basicAuth(h http.Handler, credentials string) http.Handler {
+	if credentials == "" {
+		return h
+	}
+	wantUser, wantPassword, _ := strings.Cut(credentials, ":")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(wantUser)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(wantPassword)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="vodeneevbet dashboard"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,109 @@
+// Command dashboard serves the admin web UI: parsers and their last cycles, the calculator's top value bets
+// and line movements, Telegram delivery and alert subscriptions, live from the calculator and parser APIs.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/logging"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+)
+
+const (
+	defaultAddr          = ":8090"
+	defaultCalculatorURL = "http://localhost:8080"
+)
+
+func main() {
+	var addr string
+	var calculatorURL string
+	var parserURL string
+	var auth string
+	var configPath string
+	var apiKey string
+
+	flag.StringVar(&addr, "addr", defaultAddr, "Listen address (or set DASHBOARD_ADDR env var)")
+	flag.StringVar(&calculatorURL, "calculator-url", defaultCalculatorURL, "Calculator service URL (or set CALCULATOR_URL env var)")
+	flag.StringVar(&parserURL, "parser-url", "", "Parser (orchestrator) health server URL (or set PARSER_URL env var; default: value_calculator.parser_url of -config)")
+	flag.StringVar(&auth, "auth", "", "Basic auth credentials user:password (or set DASHBOARD_AUTH env var; empty = no auth)")
+	flag.StringVar(&configPath, "config", "", "Path to config file (optional, for logging setup and parser_url)")
+	flag.StringVar(&apiKey, "api-key", "", "API key (role read) for the calculator and parser when api_auth is enabled (or set API_KEY env var)")
+	flag.Parse()
+
+	if configPath != "" {
+		if cfg, err := config.Load(configPath); err == nil {
+			_, _ = logging.SetupLogger(&cfg.Logging, "dashboard")
+			if parserURL == "" {
+				parserURL = cfg.ValueCalculator.ParserURL
+			}
+		} else {
+			slog.Warn("Failed to load config, continuing without it", "path", configPath, "error", err)
+		}
+	}
+	if addr == defaultAddr {
+		if env := os.Getenv("DASHBOARD_ADDR"); env != "" {
+			addr = env
+		}
+	}
+	if calculatorURL == defaultCalculatorURL {
+		if env := os.Getenv("CALCULATOR_URL"); env != "" {
+			calculatorURL = env
+		}
+	}
+	if env := os.Getenv("PARSER_URL"); env != "" && parserURL == "" {
+		parserURL = env
+	}
+	if auth == "" {
+		auth = os.Getenv("DASHBOARD_AUTH")
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv(apiauth.KeyEnv)
+	}
+	apiauth.UseKey(apiKey, calculatorURL, parserURL)
+	if auth == "" {
+		slog.Warn("Dashboard has no auth (-auth / DASHBOARD_AUTH): keep it on a private network")
+	} else if !strings.Contains(auth, ":") {
+		slog.Error("-auth must be user:password")
+		os.Exit(1)
+	}
+
+	hc := client.WithHTTPClient(&http.Client{Timeout: overviewTimeout})
+	d := &dashboard{}
+	if calculatorURL != "" {
+		d.calculator = client.NewCalculator(calculatorURL, hc)
+	}
+	if parserURL != "" {
+		d.parser = client.NewOrchestrator(parserURL, hc)
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           basicAuth(d.handler(), auth),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Starting dashboard", "addr", addr, "calculator_url", calculatorURL, "parser_url", parserURL)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Dashboard server failed", "error", err)
+		os.Exit(1)
+	}
+}
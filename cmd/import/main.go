@@ -0,0 +1,140 @@
+// import loads a saved hierarchical match JSON into the in-memory match store and serves it on the
+// parser HTTP API (/matches, /esports/matches, /outrights), so a local calculator with
+// value_calculator.parser_url pointing at it computes on a production state. Accepted files are
+// anything with a top-level "matches" array: a saved GET /matches response, a bookmaker-service
+// snapshot (parser.snapshot) or a dry-run result (parser.dry_run, parser-replay expected.json).
+// Run from the repo root:
+//
+//	curl -s prod-parser:8080/matches > /tmp/matches.json
+//	go run ./cmd/import -file /tmp/matches.json -port 8090 -rebase
+//
+// Matches are not written to Postgres: the repo has no match storage (interfaces.Storage is not
+// implemented), the parser services keep matches in memory only.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+// exportFile is the common part of the match JSON documents the tool reads.
+type exportFile struct {
+	Matches        []models.Match        `json:"matches"`
+	EsportsMatches []models.EsportsMatch `json:"esports_matches"`
+	Outrights      []models.Outright     `json:"outrights"`
+}
+
+func main() {
+	file := flag.String("file", "", "match JSON to import (required)")
+	port := flag.Int("port", 8090, "port of the parser HTTP API serving the imported matches")
+	rebase := flag.Bool("rebase", false, "shift all times so the newest odds update is now (start times move too)")
+	flag.Parse()
+
+	if err := run(*file, *port, *rebase); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, port int, rebase bool) error {
+	if file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var doc exportFile
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	if len(doc.Matches) == 0 && len(doc.EsportsMatches) == 0 && len(doc.Outrights) == 0 {
+		return fmt.Errorf("%s has no matches", file)
+	}
+
+	if rebase {
+		if latest := latestUpdate(doc.Matches); !latest.IsZero() {
+			shift := time.Since(latest)
+			shiftMatches(doc.Matches, shift)
+			slog.Info("Rebased match times", "shift", shift.Round(time.Second))
+		}
+	}
+
+	ptrs := make([]*models.Match, len(doc.Matches))
+	for i := range doc.Matches {
+		doc.Matches[i].Stale = false
+		ptrs[i] = &doc.Matches[i]
+	}
+	health.AddMatches(ptrs)
+	for i := range doc.EsportsMatches {
+		health.AddEsportsMatch(&doc.EsportsMatches[i])
+	}
+	for i := range doc.Outrights {
+		health.AddOutright(&doc.Outrights[i])
+	}
+	slog.Info("Imported matches", "file", file, "matches", len(health.GetMatches()),
+		"esports_matches", len(doc.EsportsMatches), "outrights", len(doc.Outrights))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	health.Run(ctx, health.AddrFor(port), "import", nil, readHeaderTimeout, 0)
+	fmt.Printf("Serving %s on http://localhost:%d/matches (value_calculator.parser_url), Ctrl+C to stop\n", file, port)
+	<-ctx.Done()
+	return nil
+}
+
+// latestUpdate returns the newest UpdatedAt of the matches, their events and outcomes.
+func latestUpdate(matches []models.Match) time.Time {
+	var latest time.Time
+	later := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	for _, m := range matches {
+		later(m.UpdatedAt)
+		for _, ev := range m.Events {
+			later(ev.UpdatedAt)
+			for _, out := range ev.Outcomes {
+				later(out.UpdatedAt)
+			}
+		}
+	}
+	return latest
+}
+
+// shiftMatches moves the start and update times of matches by d, keeping zero times zero.
+func shiftMatches(matches []models.Match, d time.Duration) {
+	shift := func(t *time.Time) {
+		if !t.IsZero() {
+			*t = t.Add(d)
+		}
+	}
+	for i := range matches {
+		m := &matches[i]
+		shift(&m.StartTime)
+		shift(&m.CreatedAt)
+		shift(&m.UpdatedAt)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			shift(&ev.CreatedAt)
+			shift(&ev.UpdatedAt)
+			for k := range ev.Outcomes {
+				shift(&ev.Outcomes[k].CreatedAt)
+				shift(&ev.Outcomes[k].UpdatedAt)
+			}
+		}
+	}
+}
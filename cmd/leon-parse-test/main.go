@@ -35,7 +35,7 @@ func run(leagueID, eventID int64, verbose bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	client := leon.NewClient("", 30*time.Second)
+	client := leon.NewClient("", 30*time.Second, nil, nil)
 
 	// 1) Получить все лиги (sports)
 	slog.Info("Fetching sports...")
@@ -0,0 +1,85 @@
+// migrate applies the embedded PostgreSQL schema migrations (internal/pkg/storage/migrations) or shows
+// which are applied. The calculator applies them on startup too; run this before tools that use the
+// database without a calculator (backtest) or to migrate ahead of a deploy:
+//
+//	go run ./cmd/migrate            # apply pending migrations
+//	go run ./cmd/migrate -status    # list migrations and when they were applied
+//	POSTGRES_DSN='host=... port=5432 user=... password=... dbname=... sslmode=require' ./migrate
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+const defaultConfigPath = "configs/production.yaml"
+
+func main() {
+	defaultConfig := os.Getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = defaultConfigPath
+	}
+	configPath := flag.String("config", defaultConfig, "Path to config file (can be set via CONFIG_PATH env var)")
+	status := flag.Bool("status", false, "List the migrations and when they were applied instead of applying them")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Timeout of the whole run")
+	flag.Parse()
+
+	pgConfig := config.PostgresConfig{}
+	if cfg, err := config.Load(*configPath); err == nil {
+		pgConfig = cfg.Postgres
+	} else if os.Getenv("POSTGRES_DSN") == "" {
+		fatal("Failed to load config", err)
+	}
+	if envDSN := os.Getenv("POSTGRES_DSN"); envDSN != "" {
+		pgConfig.DSN = envDSN
+	}
+	if pgConfig.DSN == "" {
+		fatal("Postgres DSN is required", fmt.Errorf("set postgres.dsn in config or POSTGRES_DSN env var"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if *status {
+		states, err := storage.PostgresMigrationStatus(ctx, &pgConfig)
+		if err != nil {
+			fatal("Failed to read migration status", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range states {
+			applied := "pending"
+			if !s.AppliedAt.IsZero() {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+		return
+	}
+
+	applied, err := storage.MigratePostgres(ctx, &pgConfig)
+	if err != nil {
+		fatal("Migration failed", err)
+	}
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date.")
+		return
+	}
+	for _, m := range applied {
+		fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,247 @@
+// parser-replay records the HTTP traffic of one parser's ParseOnce into a fixtures directory and
+// replays ParseOnce against it offline, comparing the matches with the recorded ones
+// (internal/pkg/replay). Run from the repo root:
+//
+//	go run ./cmd/parser-replay -parser zenit -record                 # live run, saves fixtures + expected.json
+//	go run ./cmd/parser-replay -parser zenit                         # offline replay, exit 1 on regressions
+//	go run ./cmd/parser-replay -parser zenit -update                 # accept the replay result as expected.json
+//	go run ./cmd/parser-replay -parser leon -fixtures /tmp/leon-2026-03-01
+//
+// Fixtures default to testdata/replay/<parser>. Expected matches that have started since the
+// recording are not reported as missing (parsers skip started matches).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/dryrun"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/replay"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/teaminfo"
+
+	// Register all supported parsers via init().
+	_ "github.com/Vodeneev/vodeneevbet/internal/parser/parsers/all"
+)
+
+const (
+	expectedFile = "expected.json"
+	mirrorsDir   = "mirrors"
+)
+
+// errRegression is returned when the replay differs from the recording.
+var errRegression = errors.New("replay differs from the recording")
+
+type options struct {
+	configPath   string
+	parser       string
+	fixtures     string
+	record       bool
+	update       bool
+	timeout      time.Duration
+	ignoreParams string
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.configPath, "config", "configs/production.yaml", "path to config yaml")
+	flag.StringVar(&opts.parser, "parser", "", "parser name (required), e.g. zenit, leon, fonbet")
+	flag.StringVar(&opts.fixtures, "fixtures", "", "fixtures directory (default: testdata/replay/<parser>)")
+	flag.BoolVar(&opts.record, "record", false, "run the parser live and record its HTTP traffic")
+	flag.BoolVar(&opts.update, "update", false, "replay and save the result as expected.json")
+	flag.DurationVar(&opts.timeout, "timeout", 0, "max ParseOnce duration (default: parser.dry_run.timeout or 10m)")
+	flag.StringVar(&opts.ignoreParams, "ignore-params", "", "comma-separated query params ignored when matching requests (default: "+strings.Join(replay.DefaultIgnoreParams, ",")+")")
+	flag.Parse()
+
+	dryrun.LogToStderr()
+	if err := run(opts); err != nil {
+		if !errors.Is(err, errRegression) {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(opts options) error {
+	opts.parser = strings.ToLower(strings.TrimSpace(opts.parser))
+	if opts.parser == "" {
+		return fmt.Errorf("-parser is required (available: %v)", parsers.AvailableNames())
+	}
+	factory, ok := parsers.Available()[opts.parser]
+	if !ok {
+		return fmt.Errorf("unknown parser %q (available: %v)", opts.parser, parsers.AvailableNames())
+	}
+	if opts.fixtures == "" {
+		opts.fixtures = filepath.Join("testdata", "replay", opts.parser)
+	}
+
+	cfg, err := pkgconfig.Load(opts.configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	cfg.Parser.EnabledParsers = []string{opts.parser}
+	dr := cfg.Parser.DryRun
+	if opts.timeout > 0 {
+		dr.Timeout = opts.timeout
+	}
+
+	sessionOpts := replay.Options{Parser: opts.parser}
+	if opts.ignoreParams != "" {
+		sessionOpts.IgnoreParams = strings.Split(opts.ignoreParams, ",")
+	}
+
+	circuitbreaker.Configure(cfg.Parser.CircuitBreaker)
+	teaminfo.Configure(cfg.Parser.TeamInfo)
+	if n, err := models.ConfigureTeamAliases(cfg.TeamAliases.File); err != nil {
+		slog.Error("Team aliases: failed to load file, using embedded seed only", "file", cfg.TeamAliases.File, "error", err)
+	} else {
+		slog.Info("Team aliases configured", "aliases", n, "file", cfg.TeamAliases.File)
+	}
+	if n, err := models.ConfigureLeagueAliases(cfg.LeagueAliases.File); err != nil {
+		slog.Error("League aliases: failed to load file, using embedded seed only", "file", cfg.LeagueAliases.File, "error", err)
+	} else {
+		slog.Info("League aliases configured", "aliases", n, "file", cfg.LeagueAliases.File)
+	}
+	models.SetStartTimeTolerance(cfg.Parser.StartTimeTolerance)
+	models.SetOddsPrecision(cfg.Odds.Epsilon, cfg.Odds.Decimals)
+	// Resolved mirrors live in the fixtures, so the replay never needs headless Chrome
+	registry := cfg.Parser.MirrorRegistry
+	registry.Dir = filepath.Join(opts.fixtures, mirrorsDir)
+
+	ctx := context.Background()
+	if opts.record {
+		proxypool.Configure(cfg.Parser.ProxyPool)
+		browserpool.Configure(cfg.Parser.BrowserPool)
+		defer browserpool.Default().Close()
+		mirrors.Configure(registry)
+		return record(ctx, factory(cfg), opts.fixtures, sessionOpts, dr)
+	}
+	registry.MaxAge = 100 * 365 * 24 * time.Hour
+	mirrors.Configure(registry)
+	return replayAndCompare(ctx, factory(cfg), opts.fixtures, sessionOpts, dr, opts.update)
+}
+
+func record(ctx context.Context, p interfaces.Parser, dir string, sessionOpts replay.Options, dr pkgconfig.DryRunConfig) error {
+	if err := replay.StartRecording(dir, sessionOpts); err != nil {
+		return err
+	}
+	dr.Output = filepath.Join(dir, expectedFile)
+	runErr := dryrun.Run(ctx, []interfaces.Parser{p}, dr)
+	st, err := replay.Stop()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recorded %d HTTP exchanges into %s (expected matches: %s)\n", st.Exchanges, dir, dr.Output)
+	if runErr != nil {
+		fmt.Println("Warning: the parser reported errors during recording; they will be replayed too:", runErr)
+	}
+	return nil
+}
+
+func replayAndCompare(ctx context.Context, p interfaces.Parser, dir string, sessionOpts replay.Options, dr pkgconfig.DryRunConfig, update bool) error {
+	expected, err := readResult(filepath.Join(dir, expectedFile))
+	if err != nil {
+		return fmt.Errorf("%w (record fixtures first with -record)", err)
+	}
+
+	tmp, err := os.CreateTemp("", "parser-replay-*.json")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := replay.StartReplay(dir, sessionOpts); err != nil {
+		return err
+	}
+	dr.Output = tmp.Name()
+	runErr := dryrun.Run(ctx, []interfaces.Parser{p}, dr)
+	st, err := replay.Stop()
+	if err != nil {
+		return err
+	}
+	actual, err := readResult(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replayed %s: %d requests served from %d fixtures, %d unused, %d without fixture\n",
+		dir, st.Served, st.Exchanges, st.Unused, len(st.Misses))
+	for _, m := range st.Misses {
+		fmt.Println("  no fixture:", m)
+	}
+	if runErr != nil {
+		slog.Warn("Parser reported errors during replay", "error", runErr)
+	}
+
+	if update {
+		data, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, expectedFile), data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Updated %s: %d matches\n", filepath.Join(dir, expectedFile), len(actual.Matches))
+		return nil
+	}
+
+	diff := replay.Compare(expected.Matches, actual.Matches, time.Now())
+	fmt.Printf("Matches: expected %d, replayed %d (%d skipped: started since recording)\n",
+		len(expected.Matches), len(actual.Matches), diff.SkippedStarted)
+	if diff.Empty() {
+		fmt.Println("OK: replay matches the recording")
+		return nil
+	}
+	printDiff(diff)
+	return errRegression
+}
+
+func readResult(path string) (*dryrun.Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res dryrun.Result
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &res, nil
+}
+
+func printDiff(d replay.Diff) {
+	for _, id := range d.Missing {
+		fmt.Println("- missing match:", id)
+	}
+	for _, id := range d.Extra {
+		fmt.Println("+ extra match:", id)
+	}
+	for _, c := range d.Changed {
+		fmt.Printf("~ %s (%s)\n", c.Name, c.MatchID)
+		for _, k := range c.Missing {
+			fmt.Println("    - outcome:", k)
+		}
+		for _, k := range c.Extra {
+			fmt.Println("    + outcome:", k)
+		}
+		for _, o := range c.Odds {
+			fmt.Println("    ~ odds:", o)
+		}
+	}
+	fmt.Printf("FAIL: %d missing, %d extra, %d changed matches\n", len(d.Missing), len(d.Extra), len(d.Changed))
+}
@@ -13,11 +13,23 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/bus"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/chaos"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
 	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/dryrun"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/logging"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/teaminfo"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 
 	// Register all supported parsers via init().
 	_ "github.com/Vodeneev/vodeneevbet/internal/parser/parsers/all"
@@ -28,9 +40,13 @@ const (
 )
 
 type config struct {
-	configPath string
-	runFor     time.Duration
-	parser     string // Override enabled_parsers from config (e.g. "fonbet" or "pinnacle")
+	configPath   string
+	runFor       time.Duration
+	parser       string // Override enabled_parsers from config (e.g. "fonbet" or "pinnacle")
+	dryRun       bool   // Parse once and write matches to dryRunOutput instead of serving them
+	dryRunOutput string // Dry-run JSON file ("" = parser.dry_run.output, "-" = stdout)
+
+	debugEndpoints bool // pprof and /debug/runtime on the health server
 }
 
 func main() {
@@ -51,13 +67,41 @@ func run() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Настраиваем логирование с поддержкой Yandex Cloud Logging
-	_, err = logging.SetupLogger(&appConfig.Logging, "parser")
-	if err != nil {
-		slog.Warn("Failed to setup logging, continuing with default logger", "error", err)
+	dryRun := dryRunConfig(appConfig.Parser.DryRun, cfg)
+	if dryRun.Enabled {
+		dryrun.LogToStderr()
+	} else {
+		// Настраиваем логирование с поддержкой Yandex Cloud Logging
+		_, err = logging.SetupLogger(&appConfig.Logging, "parser")
+		if err != nil {
+			slog.Warn("Failed to setup logging, continuing with default logger", "error", err)
+		} else {
+			slog.Info("Logging initialized", "service", "parser")
+		}
+		shutdownTracing, err := tracing.Setup(context.Background(), &appConfig.Tracing, "parser")
+		if err != nil {
+			slog.Warn("Failed to setup tracing, continuing without it", "error", err)
+		}
+		defer shutdownTracing(context.Background()) //nolint:errcheck // best-effort flush on exit
+	}
+
+	circuitbreaker.Configure(appConfig.Parser.CircuitBreaker)
+	proxypool.Configure(appConfig.Parser.ProxyPool)
+	browserpool.Configure(appConfig.Parser.BrowserPool)
+	defer browserpool.Default().Close()
+	mirrors.Configure(appConfig.Parser.MirrorRegistry)
+	teaminfo.Configure(appConfig.Parser.TeamInfo)
+	if n, err := models.ConfigureTeamAliases(appConfig.TeamAliases.File); err != nil {
+		slog.Error("Team aliases: failed to load file, using embedded seed only", "file", appConfig.TeamAliases.File, "error", err)
+	} else {
+		slog.Info("Team aliases configured", "aliases", n, "file", appConfig.TeamAliases.File)
+	}
+	if n, err := models.ConfigureLeagueAliases(appConfig.LeagueAliases.File); err != nil {
+		slog.Error("League aliases: failed to load file, using embedded seed only", "file", appConfig.LeagueAliases.File, "error", err)
 	} else {
-		slog.Info("Logging initialized", "service", "parser")
+		slog.Info("League aliases configured", "aliases", n, "file", appConfig.LeagueAliases.File)
 	}
+	models.SetStartTimeTolerance(appConfig.Parser.StartTimeTolerance)
 
 	slog.Info("Config loaded successfully")
 
@@ -67,16 +111,34 @@ func run() error {
 	}
 
 	var interfaceParsers []interfaces.Parser
-	if len(appConfig.Parser.BookmakerServices) > 0 {
-		// Orchestrator mode: no local parsers, aggregate from bookmaker services
-		interfaceParsers = health.RemoteParsers(appConfig.Parser.BookmakerServices, asyncParsingTimeout)
-		health.SetMatchesAggregator(appConfig.Parser.BookmakerServices, 90*time.Second)
-		names := make([]string, 0, len(interfaceParsers))
-		for _, p := range interfaceParsers {
-			names = append(names, p.GetName())
+	// byName keys parsers like parser.sports: registered parser names (remote services schedule themselves)
+	byName := make(map[string]interfaces.Parser)
+	discoverer, err := serviceDiscoverer(appConfig.Parser.Discovery)
+	if err != nil {
+		return err
+	}
+	orchestrator := len(appConfig.Parser.BookmakerServices) > 0 || discoverer != nil
+	if dryRun.Enabled && orchestrator {
+		return fmt.Errorf("dry-run needs local parsers: parser.bookmaker_services and parser.discovery must be empty")
+	}
+	var remotes *remoteServices
+	if orchestrator {
+		// Orchestrator mode: no local parsers, aggregate from bookmaker services.
+		// The key goes to every bookmaker service the directory lists (remoteServices adds them).
+		apiauth.UseKey(os.Getenv(apiauth.KeyEnv))
+		dir := health.NewServiceDirectory(appConfig.Parser.BookmakerServices)
+		if discoverer != nil {
+			if err := dir.Refresh(context.Background(), discoverer); err != nil {
+				slog.Warn("Bookmaker service discovery failed, starting with bookmaker_services only", "error", err)
+			}
+		}
+		remotes = newRemoteServices(appConfig, dir, asyncParsingTimeout)
+		names := make([]string, 0, len(dir.Services()))
+		for name := range dir.Services() {
+			names = append(names, name)
 		}
 		sort.Strings(names)
-		slog.Info("Parser orchestrator mode: aggregating from bookmaker services", "services", strings.Join(names, ", "))
+		slog.Info("Parser orchestrator mode: aggregating from bookmaker services", "services", strings.Join(names, ", "), "discovery", appConfig.Parser.Discovery.Mode)
 	} else {
 		// Local mode: run parsers in process
 		if cfg.parser != "" {
@@ -87,9 +149,14 @@ func run() error {
 			return err
 		}
 		printSelectedParsers(ps)
-		interfaceParsers = make([]interfaces.Parser, len(ps))
-		for i, p := range ps {
-			interfaceParsers[i] = p
+		keys := make([]string, 0, len(ps))
+		for key := range ps {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			interfaceParsers = append(interfaceParsers, ps[key])
+			byName[key] = ps[key]
 		}
 	}
 
@@ -97,7 +164,33 @@ func run() error {
 	defer cancel()
 	setupSignalHandler(ctx, cancel)
 
-	health.RegisterParsers(interfaceParsers)
+	if dryRun.Enabled {
+		return dryrun.Run(ctx, interfaceParsers, dryRun)
+	}
+
+	eventlog.Configure("parser", appConfig.EventLog)
+	chaos.Configure("parser", appConfig.Chaos)
+	if err := apiauth.Configure("parser", appConfig.APIAuth); err != nil {
+		return fmt.Errorf("invalid api_auth: %w", err)
+	}
+	if remotes != nil {
+		// Registers remote parsers and event log remotes, keeps them in line with discovery
+		remotes.start(ctx)
+		if discoverer != nil {
+			go remotes.dir.Watch(ctx, discoverer, appConfig.Parser.Discovery.Interval)
+		}
+	} else {
+		health.RegisterParsers(interfaceParsers)
+	}
+	// Orchestrator mode stores nothing itself: bookmaker services publish their own matches
+	if appConfig.Bus.Publish && !orchestrator {
+		b, err := bus.Connect(appConfig.Bus, "parser")
+		if err != nil {
+			return fmt.Errorf("failed to connect to the bus: %w", err)
+		}
+		defer b.Close()
+		health.SetMatchPublisher(b)
+	}
 
 	port := appConfig.Health.Port
 	if port <= 0 {
@@ -106,10 +199,17 @@ func run() error {
 	}
 	healthAddr := health.AddrFor(port)
 
+	if !orchestrator {
+		ms := appConfig.Health.MatchStore
+		health.StartMatchEviction(ctx, ms.MaxMatches, ms.Retention, ms.EvictInterval)
+	}
+	if cfg.debugEndpoints {
+		health.EnableDebugEndpoints()
+	}
 	health.Run(ctx, healthAddr, "parser", nil, appConfig.Health.ReadHeaderTimeout, asyncParsingTimeout)
 
 	slog.Info("Starting parsers...")
-	return runParsers(ctx, interfaceParsers, appConfig, asyncParsingTimeout)
+	return runParsers(ctx, interfaceParsers, byName, appConfig, asyncParsingTimeout)
 }
 
 func parseFlags() config {
@@ -123,11 +223,14 @@ func parseFlags() config {
 	flag.StringVar(&cfg.configPath, "config", defaultConfig, "Path to config file (can be set via CONFIG_PATH env var)")
 	flag.DurationVar(&cfg.runFor, "run-for", 0, "Auto-stop after duration (e.g. 10s, 1m). 0 = run until SIGINT/SIGTERM")
 	flag.StringVar(&cfg.parser, "parser", "", "Override enabled_parsers: specify parser name (e.g. 'fonbet' or 'pinnacle'). Empty = use config")
+	flag.BoolVar(&cfg.dryRun, "dry-run", false, "Run ParseOnce once and write matches as JSON instead of serving them (parser.dry_run.enabled)")
+	flag.StringVar(&cfg.dryRunOutput, "dry-run-output", "", "Dry-run JSON file ('-' = stdout). Empty = parser.dry_run.output")
+	flag.BoolVar(&cfg.debugEndpoints, "debug-endpoints", os.Getenv("DEBUG_ENDPOINTS") == "true", "Serve /debug/pprof/ and /debug/runtime on the health server (or set DEBUG_ENDPOINTS=true)")
 	flag.Parse()
 	return cfg
 }
 
-func selectParsers(cfg *pkgconfig.Config) ([]parsers.Parser, error) {
+func selectParsers(cfg *pkgconfig.Config) (map[string]parsers.Parser, error) {
 	available := parsers.Available()
 
 	// If enabled_parsers is not specified in config, run all available parsers
@@ -177,17 +280,17 @@ func validateEnabledParsers(enabledSet map[string]bool, available map[string]par
 	return nil
 }
 
-func createParsers(available map[string]parsers.Factory, enabledSet map[string]bool, cfg *pkgconfig.Config) []parsers.Parser {
-	var ps []parsers.Parser
+func createParsers(available map[string]parsers.Factory, enabledSet map[string]bool, cfg *pkgconfig.Config) map[string]parsers.Parser {
+	ps := make(map[string]parsers.Parser)
 	for key, ctor := range available {
 		if len(enabledSet) == 0 || enabledSet[key] {
-			ps = append(ps, ctor(cfg))
+			ps[key] = ctor(cfg)
 		}
 	}
 	return ps
 }
 
-func printSelectedParsers(ps []parsers.Parser) {
+func printSelectedParsers(ps map[string]parsers.Parser) {
 	names := make([]string, 0, len(ps))
 	for _, p := range ps {
 		names = append(names, p.GetName())
@@ -196,6 +299,17 @@ func printSelectedParsers(ps []parsers.Parser) {
 	slog.Info("Using parsers", "parsers", strings.Join(names, ", "))
 }
 
+// dryRunConfig applies the -dry-run and -dry-run-output flags on top of parser.dry_run.
+func dryRunConfig(dr pkgconfig.DryRunConfig, cfg config) pkgconfig.DryRunConfig {
+	if cfg.dryRun {
+		dr.Enabled = true
+	}
+	if cfg.dryRunOutput != "" {
+		dr.Output = cfg.dryRunOutput
+	}
+	return dr
+}
+
 func createContext(runFor time.Duration) (context.Context, context.CancelFunc) {
 	if runFor > 0 {
 		return context.WithTimeout(context.Background(), runFor)
@@ -220,7 +334,7 @@ func setupSignalHandler(ctx context.Context, cancel context.CancelFunc) {
 	}()
 }
 
-func runParsers(ctx context.Context, interfaceParsers []interfaces.Parser, appConfig *pkgconfig.Config, asyncParsingTimeout time.Duration) error {
+func runParsers(ctx context.Context, interfaceParsers []interfaces.Parser, byName map[string]interfaces.Parser, appConfig *pkgconfig.Config, asyncParsingTimeout time.Duration) error {
 	// Start parsers in background (local parsers wait for context; remote parsers no-op Start)
 	opts := parserutil.AsyncRunOptions()
 	opts.LogStart = true
@@ -231,22 +345,34 @@ func runParsers(ctx context.Context, interfaceParsers []interfaces.Parser, appCo
 		return p.Start(ctx)
 	}, opts)
 
-	parseInterval := appConfig.Parser.Interval
-	if parseInterval <= 0 {
-		parseInterval = 2 * time.Minute
+	parseInterval := parsingInterval(appConfig)
+	if appConfig.Parser.Interval <= 0 {
 		slog.Info("parser.interval not set, using default", "interval", parseInterval)
+	}
+
+	if len(appConfig.Parser.Sports) > 0 {
+		startSportParsing(ctx, parserutil.BuildSportSchedules(byName, appConfig.Parser.Sports, parseInterval), asyncParsingTimeout)
 	} else {
 		slog.Info("Starting periodic parsing", "interval", parseInterval)
+		startPeriodicParsing(ctx, parseInterval, asyncParsingTimeout)
 	}
 
-	startPeriodicParsing(ctx, interfaceParsers, parseInterval, asyncParsingTimeout)
-
 	<-ctx.Done()
 	slog.Info("Parser stopped gracefully")
 	return nil
 }
 
-func startPeriodicParsing(ctx context.Context, parsers []interfaces.Parser, interval time.Duration, timeout time.Duration) {
+// parsingInterval returns parser.interval (default 2m).
+func parsingInterval(appConfig *pkgconfig.Config) time.Duration {
+	if appConfig.Parser.Interval > 0 {
+		return appConfig.Parser.Interval
+	}
+	return 2 * time.Minute
+}
+
+// startPeriodicParsing runs every registered parser each interval; the registry is re-read on every
+// tick, so bookmaker services found by discovery join the next cycle.
+func startPeriodicParsing(ctx context.Context, interval time.Duration, timeout time.Duration) {
 	// Helper function to create async parsing options with error handling
 	createAsyncOpts := func() parserutil.RunOptions {
 		opts := parserutil.AsyncRunOptions()
@@ -267,18 +393,35 @@ func startPeriodicParsing(ctx context.Context, parsers []interfaces.Parser, inte
 				slog.Info("Stopping periodic parsing...")
 				return
 			case <-ticker.C:
-				runParsingOnce(parsers, timeout, createAsyncOpts())
+				runParsingOnce(health.GetParsers(), timeout, createAsyncOpts())
 			}
 		}
 	}()
 }
 
+// startSportParsing polls each (parser, sport) pair of parser.sports on its own ticker.
+func startSportParsing(ctx context.Context, schedules []parserutil.SportSchedule, timeout time.Duration) {
+	for _, s := range schedules {
+		sport := s.Sport
+		if sport == "" {
+			sport = "all"
+		}
+		slog.Info("Starting periodic parsing", "parser", s.Name, "sport", sport, "interval", s.Interval)
+	}
+	wg := parserutil.RunSportSchedules(ctx, schedules, timeout)
+	go func() {
+		wg.Wait()
+		slog.Info("Stopping periodic parsing...")
+	}()
+}
+
 func runParsingOnce(parsers []interfaces.Parser, timeout time.Duration, opts parserutil.RunOptions) {
 	parseCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	opts.WaitForCompletion = true // wait for all parsers so context stays valid for full timeout
 	_ = parserutil.RunParsers(parseCtx, parsers, func(ctx context.Context, p interfaces.Parser) error {
-		return p.ParseOnce(ctx)
+		_, err := parserutil.ParseOnceReport(ctx, p)
+		return err
 	}, opts)
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	pkgconfig "github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+)
+
+// serviceDiscoverer returns the discoverer of parser.discovery, nil when discovery is off.
+func serviceDiscoverer(cfg pkgconfig.DiscoveryConfig) (health.ServiceDiscoverer, error) {
+	switch cfg.Mode {
+	case "":
+		return nil, nil
+	case "dns":
+		if cfg.SRV == "" {
+			return nil, fmt.Errorf("parser.discovery.srv is required for mode dns")
+		}
+		return health.SRVDiscoverer{Name: cfg.SRV, Scheme: cfg.Scheme}, nil
+	case "file":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("parser.discovery.file is required for mode file")
+		}
+		return health.FileDiscoverer{Path: cfg.File}, nil
+	default:
+		return nil, fmt.Errorf("unknown parser.discovery.mode %q (use dns or file)", cfg.Mode)
+	}
+}
+
+// remoteServices keeps the orchestrator in line with its service directory: remote parsers,
+// per-service sport schedules (parser.sports), gRPC streams (parser.grpc) and event log remotes.
+type remoteServices struct {
+	mu      sync.Mutex
+	cfg     *pkgconfig.Config
+	dir     *health.ServiceDirectory
+	cache   *health.MatchesDeltaCache
+	timeout time.Duration
+	parsers map[string]interfaces.Parser
+	cancels map[string]context.CancelFunc // stops the schedules and the gRPC stream of a service
+}
+
+func newRemoteServices(cfg *pkgconfig.Config, dir *health.ServiceDirectory, timeout time.Duration) *remoteServices {
+	return &remoteServices{
+		cfg:     cfg,
+		dir:     dir,
+		cache:   health.SetMatchesAggregator(dir, 90*time.Second),
+		timeout: timeout,
+		parsers: map[string]interfaces.Parser{},
+		cancels: map[string]context.CancelFunc{},
+	}
+}
+
+// start serves the current services and follows directory changes until ctx is done.
+func (r *remoteServices) start(ctx context.Context) {
+	r.dir.OnChange(func(added, removed map[string]string) {
+		r.apply(ctx, added, removed)
+	})
+	r.apply(ctx, r.dir.Services(), nil)
+}
+
+func (r *remoteServices) apply(ctx context.Context, added, removed map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := range removed {
+		if cancel, ok := r.cancels[name]; ok {
+			cancel()
+		}
+		delete(r.cancels, name)
+		delete(r.parsers, name)
+	}
+
+	grpcCfg := r.cfg.Parser.GRPC
+	for name, baseURL := range added {
+		apiauth.AddKeyTargets(baseURL)
+		p := health.NewRemoteParser(name, baseURL, r.timeout)
+		r.parsers[name] = p
+		svcCtx, cancel := context.WithCancel(ctx)
+		r.cancels[name] = cancel
+
+		if len(r.cfg.Parser.Sports) > 0 {
+			schedules := parserutil.BuildSportSchedules(map[string]interfaces.Parser{name: p}, r.cfg.Parser.Sports, parsingInterval(r.cfg))
+			startSportParsing(svcCtx, schedules, r.timeout)
+		}
+		if grpcCfg.Enabled {
+			targets := health.GRPCTargets(map[string]string{name: baseURL}, grpcCfg.Services, grpcCfg.Port)
+			if err := r.cache.SubscribeGRPC(svcCtx, targets); err != nil {
+				slog.Error("Failed to subscribe to bookmaker service over gRPC, using HTTP", "name", name, "error", err)
+			} else if len(targets) > 0 {
+				slog.Info("Subscribed to bookmaker service over gRPC, HTTP /matches is the fallback", "name", name, "target", targets[name])
+			}
+		}
+	}
+
+	names := make([]string, 0, len(r.parsers))
+	for name := range r.parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]interfaces.Parser, 0, len(names))
+	for _, name := range names {
+		list = append(list, r.parsers[name])
+	}
+	health.RegisterParsers(list)
+	eventlog.SetRemotes(r.dir.Services())
+}
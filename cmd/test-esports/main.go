@@ -167,7 +167,7 @@ func testXbet(ctx context.Context, baseURL string, save bool) error {
 		baseURL = "https://1xlite-6173396.bar"
 		fmt.Println("Using -xbet-url=" + baseURL + " (pass -xbet-url to override)")
 	}
-	client := xbet1.NewClient(baseURL, "", 30*time.Second, nil)
+	client := xbet1.NewClient(baseURL, "", 30*time.Second, nil, nil, nil)
 
 	const sportID = 40 // киберспорт
 	countryID := 1
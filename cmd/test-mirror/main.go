@@ -0,0 +1,94 @@
+// test-mirror resolves bookmaker mirror URLs through the shared headless Chrome pool
+// (internal/pkg/browserpool) and prints the pool metrics. Use -n/-c to check queuing and browser reuse.
+//
+//	go run ./cmd/test-mirror -parser pinnacle888 -url https://bit.ly/pinnacle_mirror
+//	go run ./cmd/test-mirror -parser xbet1 -n 4 -c 2 -pool 1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers/pinnacle888"
+	"github.com/Vodeneev/vodeneevbet/internal/parser/parsers/xbet1"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+var defaultMirrors = map[string]string{
+	"pinnacle888": "https://bit.ly/pinnacle_mirror",
+	"xbet1":       "https://1xbet-skwu.top/link",
+}
+
+func main() {
+	parser := flag.String("parser", "pinnacle888", "mirror-based parser: pinnacle888 or xbet1")
+	mirrorURL := flag.String("url", "", "mirror URL (default: the parser's production mirror_url)")
+	n := flag.Int("n", 1, "number of resolutions")
+	concurrency := flag.Int("c", 1, "resolutions in parallel")
+	poolSize := flag.Int("pool", 1, "browser pool size")
+	timeout := flag.Duration("timeout", 60*time.Second, "timeout per resolution")
+	flag.Parse()
+
+	if err := run(*parser, *mirrorURL, *n, *concurrency, *poolSize, *timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(parser, mirrorURL string, n, concurrency, poolSize int, timeout time.Duration) error {
+	var resolve func(string, time.Duration) (string, error)
+	switch parser {
+	case "pinnacle888":
+		resolve = pinnacle888.ResolveMirror
+	case "xbet1":
+		resolve = xbet1.ResolveMirrorToBaseURL
+	default:
+		return fmt.Errorf("unknown parser %q (pinnacle888 or xbet1)", parser)
+	}
+	if mirrorURL == "" {
+		mirrorURL = defaultMirrors[parser]
+	}
+
+	browserpool.Configure(config.BrowserPoolConfig{Size: poolSize})
+	defer browserpool.Default().Close()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var failed int
+	var mu sync.Mutex
+	for i := 1; i <= n; i++ {
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			start := time.Now()
+			resolved, err := resolve(mirrorURL, timeout)
+			took := time.Since(start).Round(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				fmt.Printf("#%d %s -> error: %v (%s)\n", i, mirrorURL, err, took)
+				return
+			}
+			fmt.Printf("#%d %s -> %s (%s)\n", i, mirrorURL, resolved, took)
+		}()
+	}
+	wg.Wait()
+
+	s := browserpool.CurrentStats()
+	fmt.Printf("\nBrowser pool: size=%d launched=%d jobs=%d failures=%d avg_wait=%.0fms avg_duration=%.0fms\n",
+		s.Size, s.Launched, s.Jobs, s.Failures, s.AvgWaitMs, s.AvgDurationMs)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d resolutions failed", failed, n)
+	}
+	return nil
+}
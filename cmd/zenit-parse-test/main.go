@@ -75,7 +75,7 @@ func run(configPath string, saveRaw bool, fromFile string) error {
 		if timeout <= 0 {
 			timeout = 30 * time.Second
 		}
-		client := zenit.NewClient(z.BaseURL, z.ImprintHash, z.FrontVersion, z.SportID, timeout, z.ProxyList)
+		client := zenit.NewClient(z.BaseURL, z.ImprintHash, z.FrontVersion, z.SportID, timeout, z.ProxyList, nil, nil)
 		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
 		defer cancel()
 
@@ -0,0 +1,278 @@
+package calculator
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// apiPrefix is the versioned mount point of the calculator API. The unversioned paths stay registered as
+// aliases for existing clients.
+const apiPrefix = "/api/v1"
+
+// apiParam is one documented parameter of an endpoint (in: query unless the path has it as {name}).
+type apiParam struct {
+	Name        string
+	Type        string // OpenAPI schema type: string, integer, number, boolean
+	Description string
+	Required    bool
+}
+
+// apiRoute is one endpoint of the calculator API: registered at its legacy path and under apiPrefix, and
+// described in the OpenAPI document served at /api/v1/openapi.json.
+type apiRoute struct {
+	Pattern string   // ServeMux pattern of the legacy path, e.g. "GET /value-bets/{id}/odds"
+	Methods []string // documented methods when Pattern has none (the handler checks them); default GET
+	Tag     string
+	Summary string
+	Params  []apiParam
+	Handler http.HandlerFunc
+}
+
+// method and path split Pattern.
+func (r apiRoute) method() string {
+	if m, _, ok := strings.Cut(r.Pattern, " "); ok {
+		return m
+	}
+	return ""
+}
+
+func (r apiRoute) path() string {
+	if _, p, ok := strings.Cut(r.Pattern, " "); ok {
+		return p
+	}
+	return r.Pattern
+}
+
+func (r apiRoute) documentedMethods() []string {
+	if m := r.method(); m != "" {
+		return []string{m}
+	}
+	if len(r.Methods) > 0 {
+		return r.Methods
+	}
+	return []string{http.MethodGet}
+}
+
+func queryParam(name, typ, description string) apiParam {
+	return apiParam{Name: name, Type: typ, Description: description}
+}
+
+// Parameters shared by several endpoints.
+var (
+	limitParam     = queryParam("limit", "integer", "maximum number of items")
+	sportParam     = queryParam("sport", "string", "only this sport, e.g. football")
+	statusParam    = queryParam("status", "string", "live (started within 3h) or upcoming")
+	methodParam    = queryParam("method", "string", "margin removal: none, equal, proportional, shin, power or logarithmic")
+	chatIDParam    = queryParam("chat_id", "integer", "Telegram chat id")
+	userIDParam    = queryParam("user_id", "integer", "Telegram user id")
+	sinceParam     = queryParam("since", "string", "only the last duration, e.g. 24h")
+	fromParam      = queryParam("from", "string", "range start, RFC3339")
+	toParam        = queryParam("to", "string", "range end (exclusive), RFC3339")
+	offsetParam    = queryParam("offset", "integer", "items to skip")
+	groupKeyParam  = queryParam("match_group_key", "string", "match group key (sport|home|away|date)")
+	betKeyParam    = queryParam("bet_key", "string", "event_type|outcome_type|parameter")
+	bookmakerParam = queryParam("bookmaker", "string", "bookmaker name")
+)
+
+// listParams are the paging and filters of /value-bets/top and /line-movements/top; the next page's cursor
+// comes in the X-Next-Cursor header, the number of matching items in X-Total-Count.
+func listParams(sorts string) []apiParam {
+	return []apiParam{
+		limitParam,
+		queryParam("cursor", "string", "X-Next-Cursor of the previous page"),
+		queryParam("sort", "string", sorts),
+		queryParam("order", "string", "asc or desc (default depends on sort)"),
+		sportParam,
+		queryParam("bookmaker", "string", "comma-separated bookmakers"),
+		queryParam("league", "string", "tournament name substring"),
+		queryParam("min_odds", "number", "minimum odd"),
+		queryParam("max_odds", "number", "maximum odd"),
+	}
+}
+
+// apiRoutes lists the calculator API.
+func (c *ValueCalculator) apiRoutes() []apiRoute {
+	post := []string{http.MethodPost}
+	return []apiRoute{
+		{Pattern: "/diffs/top", Tag: "diffs", Summary: "Largest odds differences between bookmakers",
+			Params: []apiParam{limitParam, statusParam, sportParam}, Handler: c.handleTopDiffs},
+		{Pattern: "/diffs/status", Tag: "diffs", Summary: "Calculator status", Handler: c.handleStatus},
+		{Pattern: "/value-bets/top", Tag: "value-bets", Summary: "Top value bets against the weighted fair odds",
+			Params:  append(listParams("value_percent, expected_value or start_time"), statusParam, methodParam),
+			Handler: c.cache.wrap("value_bets_top", c.handleTopValueBets)},
+		{Pattern: "/value-bets/history", Tag: "value-bets", Summary: "Value bet lifecycles, newest first",
+			Params: []apiParam{limitParam, offsetParam, sportParam, bookmakerParam, groupKeyParam,
+				queryParam("status", "string", "active or ended"), queryParam("end_reason", "string", "match_started, value_gone or quote_removed"),
+				queryParam("min_value", "number", "minimum max_value_percent"), sinceParam, fromParam, toParam},
+			Handler: c.handleValueBetHistory},
+		{Pattern: "GET /value-bets/{id}/odds", Tag: "value-bets", Summary: "One value bet with the odds of every bookmaker",
+			Handler: c.handleValueBetOdds},
+		{Pattern: "GET /stream/value-bets", Tag: "value-bets", Summary: "Server-Sent Events of new value bets and line movements",
+			Params: []apiParam{queryParam("types", "string", "comma-separated event types: value_bet, line_movement"), sportParam,
+				queryParam("min_value", "number", "minimum value_percent of value bets"),
+				queryParam("min_change", "number", "minimum absolute change_percent of line movements")},
+			Handler: c.handleValueBetStream},
+		{Pattern: "/outrights/value-bets/top", Tag: "value-bets", Summary: "Top value bets on outright markets",
+			Params: []apiParam{limitParam}, Handler: c.handleTopOutrightValueBets},
+		{Pattern: "/line-movements/top", Tag: "line-movements", Summary: "Largest odds changes within a bookmaker",
+			Params: append(listParams("change_percent, prob_shift_pp or start_time"),
+				queryParam("unit", "string", "pp ranks by implied probability shift")),
+			Handler: c.cache.wrap("line_movements_top", c.handleTopLineMovements)},
+		{Pattern: "/line-movements/steam", Tag: "line-movements", Summary: "Same outcome moving at several bookmakers",
+			Params: []apiParam{limitParam, queryParam("window", "string", "duration, e.g. 10m"),
+				queryParam("min_bookmakers", "integer", "minimum bookmakers moving"), queryParam("min_shift_pp", "number", "minimum shift, percentage points")},
+			Handler: c.handleSteamLineMovements},
+		{Pattern: "GET /line-movements/chart", Tag: "line-movements", Summary: "PNG chart of a bet's odds history",
+			Params: []apiParam{requiredParam(groupKeyParam), requiredParam(betKeyParam),
+				requiredParam(queryParam("bookmaker", "string", "comma-separated bookmakers, at most 8")), queryParam("limit", "integer", "last points per bookmaker")},
+			Handler: c.handleLineMovementChart},
+		{Pattern: "GET /odds/history", Tag: "line-movements", Summary: "Stored odds timeline of a bet, oldest first",
+			Params: []apiParam{requiredParam(groupKeyParam), requiredParam(betKeyParam), bookmakerParam, sinceParam, fromParam, toParam,
+				queryParam("interval", "string", "downsample to the last point per bookmaker per duration, e.g. 5m"), limitParam, offsetParam},
+			Handler: c.handleOddsHistory},
+		{Pattern: "GET /matches/{group_key}/probabilities", Tag: "matches", Summary: "De-margined probabilities of one match",
+			Params: []apiParam{methodParam}, Handler: c.handleMatchProbabilities},
+		{Pattern: "GET /matches/search", Tag: "matches", Summary: "Current matches by team name with all odds",
+			Params: []apiParam{requiredParam(queryParam("q", "string", "team name")), limitParam}, Handler: c.handleMatchSearch},
+		{Pattern: "GET /team-aliases", Tag: "matches", Summary: "Team alias dictionary",
+			Params: []apiParam{queryParam("q", "string", "alias or canonical name substring")}, Handler: c.handleTeamAliases},
+		{Pattern: "GET /team-aliases/unmatched", Tag: "matches", Summary: "Team name pairs likely missing an alias",
+			Params: []apiParam{sportParam, limitParam}, Handler: c.handleUnmatchedTeams},
+		{Pattern: "/admin/team-aliases", Methods: []string{http.MethodPost, http.MethodDelete}, Tag: "matches",
+			Summary: "Add a team alias (POST) or remove an added one (DELETE ?alias=)",
+			Params:  []apiParam{queryParam("alias", "string", "alias to remove")}, Handler: c.handleAdminTeamAliases},
+		{Pattern: "GET /league-aliases", Tag: "matches", Summary: "Tournament alias dictionary",
+			Params: []apiParam{queryParam("q", "string", "alias or canonical name substring")}, Handler: c.handleLeagueAliases},
+		{Pattern: "GET /league-aliases/unmatched", Tag: "matches", Summary: "Tournament names bookmakers use for the same fixtures",
+			Params: []apiParam{sportParam, limitParam}, Handler: c.handleUnmatchedLeagues},
+		{Pattern: "/admin/league-aliases", Methods: []string{http.MethodPost, http.MethodDelete}, Tag: "matches",
+			Summary: "Add a tournament alias (POST) or remove an added one (DELETE ?alias=)",
+			Params:  []apiParam{queryParam("alias", "string", "alias to remove")}, Handler: c.handleAdminLeagueAliases},
+		{Pattern: "/arbs/top", Tag: "arbs", Summary: "Best arbitrages in fresh matches",
+			Params: []apiParam{limitParam, sportParam, statusParam}, Handler: c.arbs.handleTopArbitrages},
+		{Pattern: "/diagnostics/inconsistencies", Tag: "diagnostics", Summary: "Bookmaker lines contradicting their own 1X2",
+			Params:  []apiParam{bookmakerParam, sportParam, queryParam("check", "string", "double_chance or draw_no_bet")},
+			Handler: c.handleInconsistencies},
+		{Pattern: "GET /diagnostics/near-duplicates", Tag: "diagnostics", Summary: "Fixtures split into several match groups by start time",
+			Params: []apiParam{queryParam("window", "string", "largest start time gap, e.g. 2h"), sportParam}, Handler: c.handleNearDuplicates},
+		{Pattern: "/diagnostics/decisions", Tag: "diagnostics", Summary: "Alert decision log, newest first",
+			Params: []apiParam{betKeyParam, groupKeyParam, queryParam("match", "string", "match name substring"), chatIDParam,
+				queryParam("decision", "string", "decision, e.g. alert_queued"), sinceParam, limitParam},
+			Handler: c.handleAlertDecisions},
+		{Pattern: "/async/start", Methods: post, Tag: "async", Summary: "Start async processing", Handler: c.handleStartAsync},
+		{Pattern: "/async/stop", Methods: post, Tag: "async", Summary: "Stop async processing", Handler: c.handleStopAsync},
+		{Pattern: "/async/stop_values", Methods: post, Tag: "async", Summary: "Disable value bet alerts", Handler: c.handleStopAsyncValues},
+		{Pattern: "/async/stop_overlays", Methods: post, Tag: "async", Summary: "Disable line movement alerts",
+			Handler: c.handleStopAsyncLineMovements},
+		{Pattern: "/notifications/clear", Methods: post, Tag: "async", Summary: "Clear the notification queue",
+			Handler: c.handleClearNotificationQueue},
+		{Pattern: "/db/clear", Methods: post, Tag: "async", Summary: "Truncate the operational tables", Handler: c.handleClearDB},
+		{Pattern: "/ignores", Methods: []string{http.MethodGet, http.MethodPost, http.MethodDelete}, Tag: "chats",
+			Summary: "Ignore list (DELETE by match_group_key or token)",
+			Params:  []apiParam{groupKeyParam, queryParam("token", "string", "ignore token")}, Handler: c.handleIgnores},
+		{Pattern: "/subscriptions", Methods: []string{http.MethodGet, http.MethodPost, http.MethodDelete}, Tag: "chats",
+			Summary: "Alert subscriptions of chats", Params: []apiParam{chatIDParam}, Handler: c.handleSubscriptions},
+		{Pattern: "/chat-language", Methods: []string{http.MethodGet, http.MethodPost}, Tag: "chats",
+			Summary: "Message language of a chat", Params: []apiParam{chatIDParam}, Handler: c.handleChatLanguage},
+		{Pattern: "/bets", Methods: []string{http.MethodGet, http.MethodPost}, Tag: "bets", Summary: "Tracked bets with P/L",
+			Params: []apiParam{userIDParam, queryParam("status", "string", "open or settled")}, Handler: c.handleBets},
+		{Pattern: "/bets/calibration", Tag: "bets", Summary: "Fair probabilities at placement against results",
+			Params: []apiParam{userIDParam}, Handler: c.handleBetsCalibration},
+	}
+}
+
+func requiredParam(p apiParam) apiParam {
+	p.Required = true
+	return p
+}
+
+// registerAPI registers routes at their legacy paths and under apiPrefix, and the OpenAPI document.
+func registerAPI(mux *http.ServeMux, routes []apiRoute) {
+	for _, r := range routes {
+		mux.HandleFunc(r.Pattern, r.Handler)
+		versioned := apiPrefix + r.path()
+		if m := r.method(); m != "" {
+			versioned = m + " " + versioned
+		}
+		mux.HandleFunc(versioned, r.Handler)
+	}
+	spec := openAPISpec(routes)
+	mux.HandleFunc("GET "+apiPrefix+"/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		writeWebAppJSON(w, http.StatusOK, spec)
+	})
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z_]+)\}`)
+
+// openAPISpec builds the OpenAPI 3 document of routes.
+func openAPISpec(routes []apiRoute) map[string]interface{} {
+	paths := map[string]interface{}{}
+	tags := map[string]bool{}
+	for _, r := range routes {
+		var params []map[string]interface{}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(r.path(), -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]string{"type": "string"},
+			})
+		}
+		for _, p := range r.Params {
+			param := map[string]interface{}{
+				"name": p.Name, "in": "query", "schema": map[string]string{"type": p.Type}, "description": p.Description,
+			}
+			if p.Required {
+				param["required"] = true
+			}
+			params = append(params, param)
+		}
+
+		item := map[string]interface{}{}
+		for _, m := range r.documentedMethods() {
+			op := map[string]interface{}{
+				"summary":     r.Summary,
+				"operationId": operationID(m, r.path()),
+				"tags":        []string{r.Tag},
+				"responses": map[string]interface{}{
+					"200":     map[string]interface{}{"description": "OK"},
+					"default": map[string]interface{}{"description": "Error: {\"error\": \"...\"}"},
+				},
+			}
+			if len(params) > 0 {
+				op["parameters"] = params
+			}
+			item[strings.ToLower(m)] = op
+		}
+		paths[r.path()] = item
+		tags[r.Tag] = true
+	}
+
+	names := make([]string, 0, len(tags))
+	for t := range tags {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	tagList := make([]map[string]string, 0, len(names))
+	for _, t := range names {
+		tagList = append(tagList, map[string]string{"name": t})
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": "vodeneevbet calculator API", "version": "v1"},
+		"servers": []map[string]string{{"url": apiPrefix}},
+		"tags":    tagList,
+		"paths":   paths,
+	}
+}
+
+// operationID derives a camelCase id from the method and path: GET /value-bets/{id}/odds -> getValueBetsIdOdds.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
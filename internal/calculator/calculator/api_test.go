@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterAPI(t *testing.T) {
+	c := &ValueCalculator{}
+	mux := http.NewServeMux()
+	c.RegisterHTTP(mux)
+
+	// Legacy and versioned paths reach the same handler
+	for _, path := range []string{"/odds/history", "/api/v1/odds/history"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?match_group_key=a&bet_key=b", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status %d, want 503 (no odds storage)", path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("openapi.json: status %d", rec.Code)
+	}
+	var spec struct {
+		OpenAPI string                                       `json:"openapi"`
+		Paths   map[string]map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.OpenAPI != "3.0.3" || len(spec.Paths) != len(c.apiRoutes()) {
+		t.Fatalf("openapi %q with %d paths, want %d", spec.OpenAPI, len(spec.Paths), len(c.apiRoutes()))
+	}
+	if _, ok := spec.Paths["/async/start"]["post"]; !ok {
+		t.Errorf("/async/start is not documented as POST: %v", spec.Paths["/async/start"])
+	}
+	odds := spec.Paths["/value-bets/{id}/odds"]["get"]
+	if odds["operationId"] != "getValueBetsIdOdds" {
+		t.Errorf("operationId = %v", odds["operationId"])
+	}
+	params, _ := odds["parameters"].([]interface{})
+	if len(params) != 1 || params[0].(map[string]interface{})["in"] != "path" {
+		t.Errorf("path parameter of /value-bets/{id}/odds: %v", params)
+	}
+}
@@ -0,0 +1,352 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// defaultMaxArbProfitPercent is value_calculator.arbitrage.max_profit_percent when unset.
+const defaultMaxArbProfitPercent = 15.0
+
+// ArbitrageCalculator finds surebets in merged matches: complementary outcomes of one market
+// (1X2, over/under, Asian handicap, esports winner) whose best odds across bookmakers sum to an
+// implied probability below 1. It shares the matches client of ValueCalculator.
+type ArbitrageCalculator struct {
+	httpClient *HTTPMatchesClient
+	cfg        config.ArbitrageConfig
+	store      storage.ArbitrageStorage // nil = arbitrages are not stored
+}
+
+func newArbitrageCalculator(httpClient *HTTPMatchesClient, cfg *config.ValueCalculatorConfig) *ArbitrageCalculator {
+	a := &ArbitrageCalculator{httpClient: httpClient}
+	if cfg != nil {
+		a.cfg = cfg.Arbitrage
+	}
+	return a
+}
+
+// SetArbitrageStorage makes the async cycle store found arbitrages (value_calculator.arbitrage.enabled).
+func (c *ValueCalculator) SetArbitrageStorage(s storage.ArbitrageStorage) {
+	c.arbs.store = s
+}
+
+// enabled reports whether the async cycle should scan and store arbitrages.
+func (a *ArbitrageCalculator) enabled() bool {
+	return a.cfg.Enabled && a.store != nil && a.httpClient != nil
+}
+
+// profitRange returns the min and max profit (max 0 = no limit) from config.
+func (a *ArbitrageCalculator) profitRange() (minProfit, maxProfit float64) {
+	minProfit = a.cfg.MinProfitPercent
+	maxProfit = a.cfg.MaxProfitPercent
+	if maxProfit == 0 {
+		maxProfit = defaultMaxArbProfitPercent
+	} else if maxProfit < 0 {
+		maxProfit = 0
+	}
+	return minProfit, maxProfit
+}
+
+// process finds arbitrages in the current matches and stores them.
+func (a *ArbitrageCalculator) process(ctx context.Context) {
+	started := time.Now()
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	matches, err := a.httpClient.GetMatchesAll(reqCtx)
+	if err != nil {
+		slog.Error("Failed to fetch matches for arbitrage scan", "error", err)
+		return
+	}
+	minProfit, maxProfit := a.profitRange()
+	arbs := computeArbitrages(matches, minProfit, maxProfit, 1000)
+	if err := a.store.StoreArbitrages(ctx, arbs); err != nil {
+		slog.Error("Failed to store arbitrages", "count", len(arbs), "error", err)
+		return
+	}
+	slog.Info("Arbitrage scan complete", "matches", len(matches), "arbitrages", len(arbs), "duration_sec", time.Since(started).Seconds())
+}
+
+// arbMarket is one set of complementary outcomes: exactly one of them wins (or all are refunded).
+type arbMarket struct {
+	key     string   // models.BetKey with the market as outcome type: "main_match|1x2|", "corners|total|9.5"
+	betKeys []string // one per outcome
+	// open: the outcomes don't cover every result (correct score lists leave out unlikely scores), so the
+	// market is no arbitrage and is de-vigged over the outcomes each bookmaker quotes (devigOpenMarket).
+	open bool
+}
+
+// arbitrageMarkets returns the complete markets among the bet keys of one match (also used to
+// remove the margin per bookmaker, see fairProbabilitiesByBookmaker).
+// Whole-number handicap lines are skipped: some bookmakers price them as European three-way handicaps.
+func arbitrageMarkets(betKeys map[string]bool, sport string) []arbMarket {
+	var markets []arbMarket
+	type handicapLine struct {
+		key  string
+		line float64
+	}
+	awayHandicaps := map[string][]handicapLine{} // market without the line (handicap prefix) -> handicap_*_away lines
+	for bk := range betKeys {
+		k, err := models.ParseBetKey(bk)
+		if err != nil {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(k.OutcomeType, "_away"); ok && strings.HasPrefix(k.OutcomeType, "handicap") {
+			if line, err := parseLine(k.Parameter); err == nil {
+				k.Parameter = ""
+				group := k.WithOutcome(prefix).String()
+				awayHandicaps[group] = append(awayHandicaps[group], handicapLine{key: bk, line: line})
+			}
+		}
+	}
+
+	// Complementary outcomes keep the qualifiers of the key (period, participant): a team total is
+	// completed only by the same team's total.
+	correctScores := map[string][]string{} // market key -> correct score bet keys
+	for bk := range betKeys {
+		k, err := models.ParseBetKey(bk)
+		if err != nil {
+			continue
+		}
+		outType, param := k.OutcomeType, k.Parameter
+		switch {
+		case outType == string(models.OutcomeTypeHomeWin) && param == "":
+			away := k.WithOutcome(string(models.OutcomeTypeAwayWin)).String()
+			draw := k.WithOutcome(string(models.OutcomeTypeDraw)).String()
+			if !betKeys[away] {
+				continue
+			}
+			if betKeys[draw] {
+				markets = append(markets, arbMarket{key: k.WithOutcome("1x2").String(), betKeys: []string{bk, draw, away}})
+			} else if enums.Sport(strings.ToLower(sport)).IsEsports() {
+				// No draw in esports winner markets; in football a missing draw is just not parsed
+				markets = append(markets, arbMarket{key: k.WithOutcome("winner").String(), betKeys: []string{bk, away}})
+			}
+		case outType == string(models.OutcomeTypeBTTSYes) && param == "":
+			no := k.WithOutcome(string(models.OutcomeTypeBTTSNo)).String()
+			if betKeys[no] {
+				markets = append(markets, arbMarket{key: k.WithOutcome("btts").String(), betKeys: []string{bk, no}})
+			}
+		case outType == string(models.OutcomeTypeCorrectScore):
+			k.Parameter = ""
+			market := k.String()
+			correctScores[market] = append(correctScores[market], bk)
+		case strings.HasSuffix(outType, "_over"):
+			market := strings.TrimSuffix(outType, "_over")
+			under := k.WithOutcome(market + "_under").String()
+			if betKeys[under] {
+				markets = append(markets, arbMarket{key: k.WithOutcome(market).String(), betKeys: []string{bk, under}})
+			}
+		case strings.HasPrefix(outType, "handicap") && strings.HasSuffix(outType, "_home"):
+			prefix := strings.TrimSuffix(outType, "_home")
+			line, err := parseLine(param)
+			if err != nil || line == math.Trunc(line) {
+				continue
+			}
+			group := k
+			group.Parameter = ""
+			for _, away := range awayHandicaps[group.WithOutcome(prefix).String()] {
+				if math.Abs(away.line+line) < 1e-9 {
+					markets = append(markets, arbMarket{key: k.WithOutcome(prefix).String(), betKeys: []string{bk, away.key}})
+					break
+				}
+			}
+		}
+	}
+	for market, keys := range correctScores {
+		if len(keys) >= minOpenMarketOutcomes {
+			sort.Strings(keys)
+			markets = append(markets, arbMarket{key: market, betKeys: keys, open: true})
+		}
+	}
+	return markets
+}
+
+// computeArbitrages finds arbitrages with profit in [minProfit, maxProfit] (maxProfit 0 = no limit),
+// sorted by profit descending. Every leg takes the best odd across bookmakers; arbitrages whose legs
+// all come from one bookmaker are skipped (that is a pricing error, not a surebet).
+func computeArbitrages(matches []models.Match, minProfit, maxProfit float64, keepTop int) []storage.Arbitrage {
+	if keepTop <= 0 {
+		keepTop = 100
+	}
+	now := time.Now()
+
+	type bestOdd struct {
+		bookmaker string
+		odd       float64
+	}
+	type groupMeta struct {
+		name      string
+		startTime time.Time
+		sport     string
+	}
+	// matchGroupKey -> betKey -> best odd across bookmakers
+	groups := map[string]map[string]bestOdd{}
+	meta := map[string]groupMeta{}
+
+	for i := range matches {
+		m := matches[i]
+		gk := matchGroupKey(m)
+		if gk == "" {
+			continue
+		}
+		if _, ok := meta[gk]; !ok {
+			meta[gk] = groupMeta{
+				name:      strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam),
+				startTime: m.StartTime,
+				sport:     m.Sport,
+			}
+			groups[gk] = map[string]bestOdd{}
+		}
+		for _, ev := range m.Events {
+			for _, out := range ev.Outcomes {
+				bk := strings.TrimSpace(out.Bookmaker)
+				if bk == "" {
+					bk = strings.TrimSpace(ev.Bookmaker)
+				}
+				if bk == "" {
+					bk = strings.TrimSpace(m.Bookmaker)
+				}
+				eventType := strings.TrimSpace(ev.EventType)
+				outcomeType := strings.TrimSpace(out.OutcomeType)
+				if bk == "" || eventType == "" || outcomeType == "" || !isFinitePositiveOdd(out.Odds) {
+					continue
+				}
+				betKey := models.OutcomeBetKey(ev, out).String()
+				if prev, ok := groups[gk][betKey]; !ok || out.Odds > prev.odd {
+					groups[gk][betKey] = bestOdd{bookmaker: strings.ToLower(bk), odd: out.Odds}
+				}
+			}
+		}
+	}
+
+	var arbs []storage.Arbitrage
+	for gk, bets := range groups {
+		gm := meta[gk]
+		betKeys := make(map[string]bool, len(bets))
+		for bk := range bets {
+			betKeys[bk] = true
+		}
+		for _, market := range arbitrageMarkets(betKeys, gm.sport) {
+			if market.open {
+				continue
+			}
+			var impliedSum float64
+			bookmakers := map[string]bool{}
+			for _, bk := range market.betKeys {
+				impliedSum += 1 / bets[bk].odd
+				bookmakers[bets[bk].bookmaker] = true
+			}
+			if impliedSum >= 1 || len(bookmakers) < 2 {
+				continue
+			}
+			profit := (1/impliedSum - 1) * 100
+			if profit < minProfit || (maxProfit > 0 && profit > maxProfit) {
+				continue
+			}
+
+			legs := make([]storage.ArbitrageLeg, 0, len(market.betKeys))
+			for _, bk := range market.betKeys {
+				_, outType, param := models.SplitBetKey(bk)
+				best := bets[bk]
+				legs = append(legs, storage.ArbitrageLeg{
+					OutcomeType:  outType,
+					Parameter:    param,
+					BetKey:       bk,
+					Bookmaker:    best.bookmaker,
+					Odd:          best.odd,
+					StakePercent: (1 / best.odd) / impliedSum * 100,
+				})
+			}
+			evType, _, _ := models.SplitBetKey(market.betKeys[0])
+			arbs = append(arbs, storage.Arbitrage{
+				MatchGroupKey: gk,
+				MatchName:     gm.name,
+				StartTime:     gm.startTime,
+				Sport:         gm.sport,
+				EventType:     evType,
+				MarketKey:     market.key,
+				Legs:          legs,
+				ImpliedSum:    impliedSum,
+				ProfitPercent: profit,
+				CalculatedAt:  now,
+			})
+		}
+	}
+
+	sort.Slice(arbs, func(i, j int) bool {
+		if arbs[i].ProfitPercent != arbs[j].ProfitPercent {
+			return arbs[i].ProfitPercent > arbs[j].ProfitPercent
+		}
+		return arbs[i].MatchGroupKey+arbs[i].MarketKey < arbs[j].MatchGroupKey+arbs[j].MarketKey
+	})
+	if len(arbs) > keepTop {
+		arbs = arbs[:keepTop]
+	}
+	return arbs
+}
+
+// parseLine parses a total or handicap line: "2.5", "+1.5", "-0.75".
+func parseLine(param string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(param), "+"), 64)
+}
+
+// handleTopArbitrages returns the best arbitrages in fresh matches.
+// GET /arbs/top?limit=10[&sport=football][&status=live|upcoming]
+func (a *ArbitrageCalculator) handleTopArbitrages(w http.ResponseWriter, r *http.Request) {
+	limit := 5
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			if n > 50 {
+				n = 50
+			}
+			limit = n
+		}
+	}
+	statusFilter := r.URL.Query().Get("status")
+	sportFilter := r.URL.Query().Get("sport")
+
+	w.Header().Set("Content-Type", "application/json")
+	if a.httpClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	matches, err := a.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleTopArbitrages", "error", err)
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+
+	minProfit, maxProfit := a.profitRange()
+	now := time.Now().UTC()
+	arbs := make([]storage.Arbitrage, 0, limit)
+	for _, arb := range computeArbitrages(matches, minProfit, maxProfit, 1000) {
+		if len(arbs) == limit {
+			break
+		}
+		if !sportMatchesFilter(arb.Sport, sportFilter) || !statusMatchesFilter(arb.StartTime, statusFilter, now) {
+			continue
+		}
+		arbs = append(arbs, arb)
+	}
+	_ = json.NewEncoder(w).Encode(arbs)
+}
@@ -0,0 +1,177 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestComputeArbitrages(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	fonbet := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "Fonbet",
+		Events: []models.Event{{EventType: "main_match", Bookmaker: "Fonbet", Outcomes: []models.Outcome{
+			{OutcomeType: "home_win", Odds: 2.6},
+			{OutcomeType: "draw", Odds: 3.2},
+			{OutcomeType: "away_win", Odds: 3.0},
+			{OutcomeType: "total_over", Parameter: "2.5", Odds: 2.2},
+			{OutcomeType: "total_under", Parameter: "2.5", Odds: 1.75},
+			{OutcomeType: "handicap_home", Parameter: "-1", Odds: 3.5},
+		}}}}
+	pinnacle := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "Pinnacle",
+		Events: []models.Event{{EventType: "main_match", Bookmaker: "Pinnacle", Outcomes: []models.Outcome{
+			{OutcomeType: "home_win", Odds: 2.2},
+			{OutcomeType: "draw", Odds: 3.6},
+			{OutcomeType: "away_win", Odds: 3.4},
+			{OutcomeType: "total_over", Parameter: "2.5", Odds: 1.8},
+			{OutcomeType: "total_under", Parameter: "2.5", Odds: 2.05},
+			{OutcomeType: "handicap_away", Parameter: "+1", Odds: 1.9},
+		}}}}
+
+	arbs := computeArbitrages([]models.Match{fonbet, pinnacle}, 0, 0, 10)
+	if len(arbs) != 2 {
+		t.Fatalf("expected 1X2 and total arbitrages, got %d: %+v", len(arbs), arbs)
+	}
+	byMarket := map[string]int{}
+	for i, a := range arbs {
+		byMarket[a.MarketKey] = i
+	}
+	i, ok := byMarket["main_match|1x2|"]
+	if !ok {
+		t.Fatalf("expected a 1X2 arbitrage, got %+v", arbs)
+	}
+	oneXTwo := arbs[i]
+	wantSum := 1/2.6 + 1/3.6 + 1/3.4
+	if math.Abs(oneXTwo.ImpliedSum-wantSum) > 1e-9 || math.Abs(oneXTwo.ProfitPercent-(1/wantSum-1)*100) > 1e-9 {
+		t.Errorf("unexpected 1X2 sums: %+v", oneXTwo)
+	}
+	var stakes float64
+	for _, leg := range oneXTwo.Legs {
+		stakes += leg.StakePercent
+		// every leg returns the same payout
+		if payout := leg.StakePercent * leg.Odd; math.Abs(payout-100/wantSum) > 1e-6 {
+			t.Errorf("leg %s pays %.4f, want %.4f", leg.OutcomeType, payout, 100/wantSum)
+		}
+	}
+	if math.Abs(stakes-100) > 1e-9 {
+		t.Errorf("stakes sum to %.6f, want 100", stakes)
+	}
+	if oneXTwo.Legs[0].Bookmaker != "fonbet" || oneXTwo.Legs[1].Bookmaker != "pinnacle" {
+		t.Errorf("unexpected legs: %+v", oneXTwo.Legs)
+	}
+	if _, ok := byMarket["main_match|total|2.5"]; !ok {
+		t.Errorf("expected a total 2.5 arbitrage, got %+v", arbs)
+	}
+
+	// Profit range filters
+	if got := computeArbitrages([]models.Match{fonbet, pinnacle}, 5, 0, 10); len(got) != 1 {
+		t.Errorf("min profit 5%%: expected only the total arbitrage, got %+v", got)
+	}
+	if got := computeArbitrages([]models.Match{fonbet, pinnacle}, 0, 1, 10); len(got) != 0 {
+		t.Errorf("max profit 1%%: expected nothing, got %+v", got)
+	}
+}
+
+func TestArbitrageMarkets(t *testing.T) {
+	keys := map[string]bool{
+		"main_match|home_win|":        true,
+		"main_match|away_win|":        true,
+		"map_1|handicap_home|-1.5":    true,
+		"map_1|handicap_away|+1.5":    true,
+		"map_1|handicap_home|-2":      true,
+		"map_1|handicap_away|+2":      true,
+		"corners|total_over|9.5":      true,
+		"corners|total_under|10.5":    true,
+		"corners|alt_total_over|8.5":  true,
+		"corners|alt_total_under|8.5": true,
+	}
+	got := map[string]bool{}
+	for _, m := range arbitrageMarkets(keys, "dota2") {
+		got[m.key] = true
+	}
+	for _, want := range []string{"main_match|winner|", "map_1|handicap|-1.5", "corners|alt_total|8.5"} {
+		if !got[want] {
+			t.Errorf("market %q not found in %v", want, got)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("expected 3 markets (whole handicap line and mismatched totals skipped), got %v", got)
+	}
+
+	// Football: home/away without draw is an incomplete 1X2
+	if markets := arbitrageMarkets(map[string]bool{"main_match|home_win|": true, "main_match|away_win|": true}, "football"); len(markets) != 0 {
+		t.Errorf("football winner without draw is not a market: %+v", markets)
+	}
+}
+
+func TestArbitrageMarkets_Participants(t *testing.T) {
+	keys := map[string]bool{
+		"corners|total_over|4.5|participant=home":  true,
+		"corners|total_under|4.5|participant=away": true,
+		"corners|total_under|4.5|participant=home": true,
+		"corners|total_under|9.5":                  true,
+	}
+	markets := arbitrageMarkets(keys, "football")
+	if len(markets) != 1 || markets[0].key != "corners|total|4.5|participant=home" {
+		t.Fatalf("expected only the home team total, got %+v", markets)
+	}
+}
+
+func TestComputeArbitrages_PlayerProps(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	props := func(bookmaker string, outcomes ...models.Outcome) models.Match {
+		return models.Match{HomeTeam: "Real Madrid", AwayTeam: "Barcelona", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: string(models.StandardEventPlayerShots), Bookmaker: bookmaker, Outcomes: outcomes}}}
+	}
+	fonbet := props("Fonbet",
+		models.Outcome{OutcomeType: "total_over", Parameter: "2.5", Participant: "Kylian Mbappe", Odds: 2.3},
+		models.Outcome{OutcomeType: "total_over", Parameter: "1.5", Participant: "Lamine Yamal", Odds: 2.4})
+	pinnacle := props("Pinnacle",
+		models.Outcome{OutcomeType: "total_under", Parameter: "2.5", Participant: "Mbappe, Kylian", Odds: 2.2},
+		models.Outcome{OutcomeType: "total_under", Parameter: "1.5", Participant: "Robert Lewandowski", Odds: 2.5})
+
+	arbs := computeArbitrages([]models.Match{fonbet, pinnacle}, 0, 0, 10)
+	if len(arbs) != 1 {
+		t.Fatalf("expected one arbitrage on the same player, got %+v", arbs)
+	}
+	if want := "player_shots|total|2.5|participant=k mbappe"; arbs[0].MarketKey != want {
+		t.Errorf("market key = %q, want %q", arbs[0].MarketKey, want)
+	}
+}
+
+func TestArbitrageMarkets_BTTSAndCorrectScore(t *testing.T) {
+	keys := map[string]bool{
+		"both_teams_to_score|btts_yes|": true,
+		"both_teams_to_score|btts_no|":  true,
+	}
+	for _, score := range []string{"0:0", "1:0", "0:1", "1:1", "2:0", "0:2", "2:1"} {
+		keys["correct_score|correct_score|"+score] = true
+	}
+	markets := map[string]arbMarket{}
+	for _, m := range arbitrageMarkets(keys, "football") {
+		markets[m.key] = m
+	}
+	if m, ok := markets["both_teams_to_score|btts|"]; !ok || m.open || len(m.betKeys) != 2 {
+		t.Errorf("btts market = %+v, want two closed outcomes", m)
+	}
+	if m, ok := markets["correct_score|correct_score|"]; !ok || !m.open || len(m.betKeys) != 7 {
+		t.Errorf("correct score market = %+v, want seven open outcomes", m)
+	}
+
+	// Correct score outcomes never cover every result: no arbitrage however high the odds
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	var outcomes []models.Outcome
+	for score := range keys {
+		if _, out, param := models.SplitBetKey(score); out == string(models.OutcomeTypeCorrectScore) {
+			outcomes = append(outcomes, models.Outcome{OutcomeType: out, Parameter: param, Odds: 50})
+		}
+	}
+	match := func(bookmaker string) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: string(models.StandardEventCorrectScore), Bookmaker: bookmaker, Outcomes: outcomes}}}
+	}
+	if arbs := computeArbitrages([]models.Match{match("Fonbet"), match("Pinnacle")}, 0, 0, 10); len(arbs) != 0 {
+		t.Errorf("correct score is not an arbitrage: %+v", arbs)
+	}
+}
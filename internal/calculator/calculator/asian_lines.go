@@ -0,0 +1,224 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// normalizeAsianLines makes total and handicap lines comparable across bookmakers that quote different
+// ladders, before consensus:
+//   - parameters are rewritten canonically, so "2.0" and "2", "0.5" and "+0.5" (handicaps) are one bet key;
+//     split notation ("2, 2.5", "0/-0.5") becomes the quarter line (2.25, -0.25) and one-decimal renderings
+//     of quarter lines ("2.2", "-0.8", see xbet1 formatLine) snap back to the quarter;
+//   - a quarter line is half the stake on each neighbouring line (2.25 = 2 + 2.5), so a bookmaker quoting
+//     both neighbours gets the quarter line synthesized (quarterLineOdd) when another bookmaker quotes it.
+//
+// Whole-number handicaps other than 0 don't form quarter lines: some bookmakers price them as European
+// three-way handicaps (see arbitrageMarkets).
+func normalizeAsianLines(matches []models.Match) []models.Match {
+	type scope struct{ group, eventType string }
+	// scope -> outcome type -> quarter line -> bookmakers quoting it
+	quarters := map[scope]map[string]map[float64]map[string]bool{}
+	// scope -> outcome type -> bookmaker -> line -> odd
+	ladders := map[scope]map[string]map[string]map[float64]float64{}
+
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			sc := scope{gk, strings.TrimSpace(ev.EventType)}
+			for k := range ev.Outcomes {
+				out := &ev.Outcomes[k]
+				handicap := isHandicapOutcome(out.OutcomeType)
+				if !handicap && !isTotalOutcome(out.OutcomeType) {
+					continue
+				}
+				line, ok := parseAsianLine(out.Parameter)
+				if !ok {
+					continue
+				}
+				out.Parameter = formatAsianLine(line, handicap)
+				if !isFinitePositiveOdd(out.Odds) {
+					continue
+				}
+				bk := outcomeBookmaker(*m, *ev, *out)
+				if isQuarterLine(line) {
+					if quarters[sc] == nil {
+						quarters[sc] = map[string]map[float64]map[string]bool{}
+					}
+					if quarters[sc][out.OutcomeType] == nil {
+						quarters[sc][out.OutcomeType] = map[float64]map[string]bool{}
+					}
+					if quarters[sc][out.OutcomeType][line] == nil {
+						quarters[sc][out.OutcomeType][line] = map[string]bool{}
+					}
+					quarters[sc][out.OutcomeType][line][bk] = true
+					continue
+				}
+				if ladders[sc] == nil {
+					ladders[sc] = map[string]map[string]map[float64]float64{}
+				}
+				if ladders[sc][out.OutcomeType] == nil {
+					ladders[sc][out.OutcomeType] = map[string]map[float64]float64{}
+				}
+				if ladders[sc][out.OutcomeType][bk] == nil {
+					ladders[sc][out.OutcomeType][bk] = map[float64]float64{}
+				}
+				if prev, ok := ladders[sc][out.OutcomeType][bk][line]; !ok || out.Odds > prev {
+					ladders[sc][out.OutcomeType][bk][line] = out.Odds
+				}
+			}
+		}
+	}
+	if len(quarters) == 0 {
+		return matches
+	}
+
+	// Synthesize quoted quarter lines for bookmakers quoting both neighbours.
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			sc := scope{gk, strings.TrimSpace(ev.EventType)}
+			if len(quarters[sc]) == 0 {
+				continue
+			}
+			bk := outcomeBookmaker(*m, *ev, models.Outcome{})
+			var added []models.Outcome
+			for outcomeType, lines := range quarters[sc] {
+				ladder := ladders[sc][outcomeType][bk]
+				if len(ladder) == 0 {
+					continue
+				}
+				handicap := isHandicapOutcome(outcomeType)
+				// Over is won on higher counts, so the higher line is harder; under and handicaps the other way round
+				higherIsEasier := !strings.HasSuffix(outcomeType, "_over")
+				for line, books := range lines {
+					if books[bk] {
+						continue
+					}
+					whole, half := math.Round(line), line-0.25
+					if whole == half {
+						half = line + 0.25
+					}
+					if handicap && whole != 0 {
+						continue
+					}
+					wholeOdd, ok1 := ladder[whole]
+					halfOdd, ok2 := ladder[half]
+					if !ok1 || !ok2 {
+						continue
+					}
+					odd, ok := quarterLineOdd(wholeOdd, halfOdd, (half > whole) == higherIsEasier)
+					if !ok {
+						continue
+					}
+					param := formatAsianLine(line, handicap)
+					added = append(added, models.Outcome{
+						ID:          ev.ID + "_" + outcomeType + "_" + param,
+						EventID:     ev.ID,
+						OutcomeType: outcomeType,
+						Parameter:   param,
+						Odds:        odd,
+						Bookmaker:   bk,
+						CreatedAt:   ev.UpdatedAt,
+						UpdatedAt:   ev.UpdatedAt,
+					})
+					books[bk] = true
+				}
+			}
+			sort.Slice(added, func(a, b int) bool { return added[a].ID < added[b].ID })
+			ev.Outcomes = append(ev.Outcomes, added...)
+		}
+	}
+	return matches
+}
+
+// quarterLineOdd returns the odd of a quarter line from the bookmaker's odds of its neighbouring whole and
+// half lines. At a result exactly on the whole line its half is refunded; the other half wins when
+// halfWinsAtPush (Under 2.25 at 2 goals) and is lost otherwise (Over 2.25 at 2 goals).
+//
+// When it is lost, the quarter line at odd q and half stakes on both neighbours return the same in every
+// result for q = (whole + half) / 2. Otherwise the returns differ at the push, and q is the odd of equal
+// expected return under the probabilities implied by the two odds: P(both win) = p1, P(push) = p2 with
+// p1 + p2 = 1/half and p1·whole + p2 = 1.
+func quarterLineOdd(wholeOdd, halfOdd float64, halfWinsAtPush bool) (float64, bool) {
+	if wholeOdd <= 1 || halfOdd <= 1 {
+		return 0, false
+	}
+	avg := (wholeOdd + halfOdd) / 2
+	if !halfWinsAtPush {
+		return avg, true
+	}
+	p2 := (wholeOdd - halfOdd) / (halfOdd * (wholeOdd - 1))
+	p1 := 1/halfOdd - p2
+	if p2 < 0 || p1 <= 0 {
+		return 0, false // the whole line can't be priced below the half line it also wins with
+	}
+	return (p1*avg + p2*halfOdd/2) / (p1 + p2/2), true
+}
+
+// isTotalOutcome reports whether outcomeType is an over/under line (total_over, alt_total_under, ...).
+func isTotalOutcome(outcomeType string) bool {
+	return strings.HasSuffix(outcomeType, "_over") || strings.HasSuffix(outcomeType, "_under")
+}
+
+// isHandicapOutcome reports whether outcomeType is a handicap line (handicap_home, handicap_away).
+func isHandicapOutcome(outcomeType string) bool {
+	return strings.HasPrefix(outcomeType, "handicap") && (strings.HasSuffix(outcomeType, "_home") || strings.HasSuffix(outcomeType, "_away"))
+}
+
+// isQuarterLine reports whether line is a quarter line (x.25 or x.75).
+func isQuarterLine(line float64) bool {
+	q := math.Round(line * 4)
+	return math.Abs(line*4-q) < 1e-9 && int(q)%2 != 0
+}
+
+// parseAsianLine parses a total or handicap line: "2.5", "+0.75", "-0,25", split "2, 2.5" or "0/-0.5"
+// (the quarter line between them). One-decimal renderings of quarter lines ("2.2", "0.8") snap to the
+// quarter; other lines that aren't a multiple of 0.25 are returned as is.
+func parseAsianLine(param string) (float64, bool) {
+	param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+	if param == "" {
+		return 0, false
+	}
+	for _, sep := range []string{"/", ","} {
+		a, b, found := strings.Cut(param, sep)
+		if !found {
+			continue
+		}
+		x, err1 := parseLine(a)
+		y, err2 := parseLine(b)
+		if err1 == nil && err2 == nil && math.Abs(math.Abs(x-y)-0.5) < 1e-9 {
+			return (x + y) / 2, true
+		}
+	}
+	v, err := parseLine(strings.ReplaceAll(param, ",", "."))
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	if tenths := math.Round(math.Abs(v) * 10); math.Abs(math.Abs(v)*10-tenths) < 1e-9 {
+		if d := int(tenths) % 10; d == 2 || d == 8 {
+			v = math.Round(v*4) / 4
+		}
+	}
+	return v, true
+}
+
+// formatAsianLine formats a line canonically: "2.5", "3", "2.25"; handicaps signed ("+0.5", "-0.25", "0").
+func formatAsianLine(line float64, signed bool) string {
+	if line == 0 {
+		return "0"
+	}
+	s := strconv.FormatFloat(line, 'f', -1, 64)
+	if signed && line > 0 {
+		return "+" + s
+	}
+	return s
+}
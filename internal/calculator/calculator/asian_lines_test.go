@@ -0,0 +1,115 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestParseAsianLine(t *testing.T) {
+	tests := []struct {
+		param    string
+		handicap bool
+		want     string
+	}{
+		{"2.5", false, "2.5"},
+		{"3.0", false, "3"},
+		{"2.25", false, "2.25"},
+		{"2, 2.5", false, "2.25"},
+		{"3/2.5", false, "2.75"},
+		{"2,5", false, "2.5"},
+		{"2.2", false, "2.25"}, // one-decimal rendering of 2.25
+		{"2.8", false, "2.75"},
+		{"0.5", true, "+0.5"},
+		{"+1.0", true, "+1"},
+		{"-0", true, "0"},
+		{"0/-0.5", true, "-0.25"},
+		{"-0.5, -1", true, "-0.75"},
+		{"-0,25", true, "-0.25"},
+		{"-0.2", true, "-0.25"},
+		{"-1.5", true, "-1.5"},
+	}
+	for _, tt := range tests {
+		line, ok := parseAsianLine(tt.param)
+		if !ok {
+			t.Errorf("parseAsianLine(%q) failed", tt.param)
+			continue
+		}
+		if got := formatAsianLine(line, tt.handicap); got != tt.want {
+			t.Errorf("parseAsianLine(%q) = %s, want %s", tt.param, got, tt.want)
+		}
+	}
+	if _, ok := parseAsianLine("abc"); ok {
+		t.Error("parseAsianLine(abc) should fail")
+	}
+}
+
+func TestQuarterLineOdd(t *testing.T) {
+	// Over 2.25 = half on Over 2 and half on Over 2.5: same return in every result at the average odd
+	if odd, ok := quarterLineOdd(1.8, 2.2, false); !ok || math.Abs(odd-2.0) > 1e-9 {
+		t.Errorf("Over 2.25 from 1.8 / 2.2: got %v (ok=%v), want 2.0", odd, ok)
+	}
+
+	// Under 2.25 from Under 2 @2.2 and Under 2.5 @1.8: equal expected return to the split under the implied probabilities
+	whole, half := 2.2, 1.8
+	odd, ok := quarterLineOdd(whole, half, true)
+	if !ok || odd <= half || odd >= (whole+half)/2 {
+		t.Fatalf("Under 2.25 from %v / %v: got %v (ok=%v), want between %v and %v", whole, half, odd, ok, half, (whole+half)/2)
+	}
+	p2 := (whole - half) / (half * (whole - 1))
+	p1 := 1/half - p2
+	split := p1*(whole+half)/2 + p2*(0.5+half/2)
+	quarter := p1*odd + p2*(0.5+odd/2)
+	if math.Abs(split-quarter) > 1e-9 {
+		t.Errorf("expected returns differ: split %v, quarter %v", split, quarter)
+	}
+
+	if _, ok := quarterLineOdd(1.7, 1.8, true); ok {
+		t.Error("whole line priced below the half line must not be converted")
+	}
+}
+
+func TestNormalizeAsianLines(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	match := func(bookmaker string, outcomes ...models.Outcome) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{ID: bookmaker, EventType: "main_match", Bookmaker: bookmaker, Outcomes: outcomes}}}
+	}
+	pinnacle := match("Pinnacle",
+		models.Outcome{OutcomeType: "total_over", Parameter: "2.25", Odds: 1.95},
+		models.Outcome{OutcomeType: "handicap_home", Parameter: "-0.25", Odds: 2.05},
+	)
+	xbet := match("1xbet",
+		models.Outcome{OutcomeType: "total_over", Parameter: "2.0", Odds: 1.7},
+		models.Outcome{OutcomeType: "total_over", Parameter: "2.5", Odds: 2.3},
+		models.Outcome{OutcomeType: "handicap_home", Parameter: "0", Odds: 1.5},
+		models.Outcome{OutcomeType: "handicap_home", Parameter: "-0.5", Odds: 2.5},
+		models.Outcome{OutcomeType: "handicap_home", Parameter: "-1.0", Odds: 3.2},
+	)
+
+	out := normalizeAsianLines([]models.Match{pinnacle, xbet})
+
+	got := map[string]float64{}
+	for _, m := range out {
+		for _, o := range m.Events[0].Outcomes {
+			got[m.Bookmaker+"|"+o.OutcomeType+"|"+o.Parameter] = o.Odds
+		}
+	}
+	if odd, ok := got["1xbet|total_over|2.25"]; !ok || math.Abs(odd-2.0) > 1e-9 {
+		t.Errorf("expected synthesized 1xbet Over 2.25 @2.0, got %v", got)
+	}
+	if odd, ok := got["1xbet|handicap_home|-0.25"]; !ok || math.Abs(odd-2.0) > 1e-9 {
+		t.Errorf("expected synthesized 1xbet home -0.25 @2.0, got %v", got)
+	}
+	if _, ok := got["1xbet|total_over|2"]; !ok {
+		t.Errorf("2.0 should be rewritten as 2, got %v", got)
+	}
+	if _, ok := got["1xbet|handicap_home|-1"]; !ok {
+		t.Errorf("-1.0 should be rewritten as -1, got %v", got)
+	}
+	if len(out[0].Events[0].Outcomes) != 2 {
+		t.Errorf("a bookmaker quoting the quarter line gets nothing synthesized: %+v", out[0].Events[0].Outcomes)
+	}
+}
@@ -153,7 +153,8 @@ func (c *ValueCalculator) handleClearNotificationQueue(w http.ResponseWriter, r
 	})
 }
 
-// handleClearDB truncates diff_bets, odds_snapshots, odds_snapshot_history (full DB cleanup).
+// handleClearDB truncates diff_bets, odds_snapshots, arbitrages (full DB cleanup). The odds history is
+// kept: it is bounded by value_calculator.odds_history.retention.
 func (c *ValueCalculator) handleClearDB(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.Header().Set("Content-Type", "application/json")
@@ -175,6 +176,10 @@ func (c *ValueCalculator) handleClearDB(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+
+	// Export odds history to the research warehouse while odds_snapshots still has the match names
+	c.exportWarehouse(r.Context(), "before_cleanup")
+
 	if err := c.diffStorage.CleanDiffBets(ctx); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -191,10 +196,19 @@ func (c *ValueCalculator) handleClearDB(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if c.arbs.store != nil {
+		if err := c.arbs.store.CleanArbitrages(ctx); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "message": "Failed to clear arbitrages"})
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"status":  "ok",
-		"message": "Database tables cleared (diff_bets, odds_snapshots, odds_snapshot_history)",
+		"message": "Database tables cleared (diff_bets, odds_snapshots, arbitrages)",
 	})
 }
@@ -0,0 +1,351 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// Staking plans of a backtest (cmd/backtest -staking).
+const (
+	BacktestPlanFlat  = "flat"  // flat_percent of the starting bankroll per bet
+	BacktestPlanKelly = "kelly" // kelly_fraction of full Kelly of the current bankroll, capped by max_stake_percent
+)
+
+// Backtest defaults.
+const (
+	defaultBacktestStep     = 5 * time.Minute
+	defaultBacktestBankroll = 1000.0
+)
+
+// BacktestConfig configures a replay of odds history through the value bet calculation.
+// Zero values fall back to the calculator defaults (min value 5%, no max odd, raw 1/odd fair line).
+type BacktestConfig struct {
+	From time.Time
+	To   time.Time
+	Step time.Duration // value bets are recomputed at every step of recorded_at (default: 5m)
+
+	MinValuePercent     float64
+	MaxOdds             float64
+	BookmakerWeights    map[string]float64
+	ReferenceBookmakers []string
+	FairOddsMethod      string
+	Rules               []config.ValueRule // per-market / per-bookmaker thresholds (value_calculator.rules)
+	MinBookmakers       int
+	MinSharpBookmakers  int
+	SharpBookmakers     []string // default: ReferenceBookmakers
+
+	Plans    []string             // staking plans (default: flat and kelly)
+	Bankroll float64              // starting bankroll of every plan (default: 1000)
+	Staking  config.StakingConfig // kelly_fraction, flat_percent, max_stake_percent
+}
+
+// BacktestBet is a value bet found during the replay with its settlement.
+type BacktestBet struct {
+	ValueBet
+	Status string `json:"status"` // storage.BetStatus*, "" = not settled (no result or unsupported market)
+}
+
+// BacktestReport is the performance of one staking plan.
+type BacktestReport struct {
+	Plan               string  `json:"plan"`
+	Bets               int     `json:"bets"` // settled bets
+	Won                int     `json:"won"`  // half wins included
+	Lost               int     `json:"lost"` // half losses included
+	Void               int     `json:"void"`
+	Turnover           float64 `json:"turnover"`
+	Profit             float64 `json:"profit"`
+	YieldPercent       float64 `json:"yield_percent"` // profit / turnover
+	ROIPercent         float64 `json:"roi_percent"`   // profit / starting bankroll
+	StartBankroll      float64 `json:"start_bankroll"`
+	FinalBankroll      float64 `json:"final_bankroll"`
+	MaxDrawdown        float64 `json:"max_drawdown"`         // largest fall of the bankroll from a previous peak
+	MaxDrawdownPercent float64 `json:"max_drawdown_percent"` // ... in percent of that peak
+	AvgOdd             float64 `json:"avg_odd"`
+	AvgValuePercent    float64 `json:"avg_value_percent"`
+}
+
+// BacktestResult is the outcome of RunBacktest.
+type BacktestResult struct {
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Records   int              `json:"records"`   // odds history rows replayed
+	Unsettled int              `json:"unsettled"` // value bets without a result
+	Reports   []BacktestReport `json:"reports"`
+	Bets      []BacktestBet    `json:"bets"`
+}
+
+// replayMatch is the replayed state of one match group: the latest odd of every bookmaker.
+type replayMatch struct {
+	name      string
+	sport     string
+	startTime time.Time
+	odds      map[string]map[string]float64 // betKey -> bookmaker -> odd
+}
+
+// RunBacktest replays odds history from reader through computeValueBets and settles the value
+// bets found with provider. Every step the latest odds of matches that have not started are
+// recomputed; a value bet is taken once, at the first step it appears (its odd then), as a bettor
+// following the calculator would. Odds that a bookmaker stopped quoting stay in the replay until
+// kick-off: history only records changes.
+func RunBacktest(ctx context.Context, reader storage.OddsHistoryReader, provider results.Provider, cfg BacktestConfig) (*BacktestResult, error) {
+	if !cfg.To.After(cfg.From) {
+		return nil, fmt.Errorf("backtest: to (%s) must be after from (%s)", cfg.To.Format(time.RFC3339), cfg.From.Format(time.RFC3339))
+	}
+	if cfg.Step <= 0 {
+		cfg.Step = defaultBacktestStep
+	}
+	if cfg.Bankroll <= 0 {
+		cfg.Bankroll = defaultBacktestBankroll
+	}
+	if len(cfg.Plans) == 0 {
+		cfg.Plans = []string{BacktestPlanFlat, BacktestPlanKelly}
+	}
+	for _, plan := range cfg.Plans {
+		if plan != BacktestPlanFlat && plan != BacktestPlanKelly {
+			return nil, fmt.Errorf("backtest: unknown staking plan %q (want %s or %s)", plan, BacktestPlanFlat, BacktestPlanKelly)
+		}
+	}
+	method, err := fairOddsMethodByName(cfg.FairOddsMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	sharp := cfg.SharpBookmakers
+	if len(sharp) == 0 {
+		sharp = cfg.ReferenceBookmakers
+	}
+	limits := valueLimits{
+		minValuePercent:    cfg.MinValuePercent,
+		maxOdds:            cfg.MaxOdds,
+		rules:              cfg.Rules,
+		minBookmakers:      cfg.MinBookmakers,
+		minSharpBookmakers: cfg.MinSharpBookmakers,
+		sharpBookmakers:    referenceBookmakerSet(sharp),
+	}
+
+	res := &BacktestResult{From: cfg.From, To: cfg.To}
+	state := map[string]*replayMatch{}
+	taken := map[string]ValueBet{}
+
+	evaluate := func(at time.Time) {
+		var matches []models.Match
+		for gk, rm := range state {
+			if !rm.startTime.After(at) {
+				delete(state, gk) // started: odds recorded after kick-off are live odds
+				continue
+			}
+			matches = append(matches, rm.matches()...)
+		}
+		if len(matches) == 0 {
+			return
+		}
+		for _, vb := range computeValueBets(matches, cfg.BookmakerWeights, cfg.ReferenceBookmakers, limits, math.MaxInt32, method) {
+			if _, ok := taken[vb.ID]; ok {
+				continue
+			}
+			vb.CalculatedAt = at
+			taken[vb.ID] = vb
+		}
+	}
+
+	next := cfg.From.Add(cfg.Step)
+	err = reader.ScanOddsHistory(ctx, cfg.From, cfg.To, func(r storage.OddsHistoryRecord) error {
+		if !r.RecordedAt.Before(next) {
+			evaluate(next)
+			for !r.RecordedAt.Before(next) {
+				next = next.Add(cfg.Step)
+			}
+		}
+		res.Records++
+		rm, ok := state[r.MatchGroupKey]
+		if !ok {
+			rm = &replayMatch{sport: r.Sport, startTime: r.StartTime, odds: map[string]map[string]float64{}}
+			state[r.MatchGroupKey] = rm
+		}
+		if rm.name == "" {
+			rm.name = replayMatchName(r.MatchName, r.MatchGroupKey)
+		}
+		if !r.StartTime.IsZero() {
+			rm.startTime = r.StartTime
+		}
+		if _, ok := rm.odds[r.BetKey]; !ok {
+			rm.odds[r.BetKey] = map[string]float64{}
+		}
+		rm.odds[r.BetKey][strings.ToLower(r.Bookmaker)] = r.Odd
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	evaluate(next)
+
+	res.Bets = make([]BacktestBet, 0, len(taken))
+	for _, vb := range taken {
+		res.Bets = append(res.Bets, BacktestBet{ValueBet: vb})
+	}
+	sort.Slice(res.Bets, func(i, j int) bool {
+		if !res.Bets[i].StartTime.Equal(res.Bets[j].StartTime) {
+			return res.Bets[i].StartTime.Before(res.Bets[j].StartTime)
+		}
+		return res.Bets[i].ID < res.Bets[j].ID
+	})
+	settleBacktestBets(ctx, provider, res.Bets)
+	for _, b := range res.Bets {
+		if b.Status == "" {
+			res.Unsettled++
+		}
+	}
+	for _, plan := range cfg.Plans {
+		res.Reports = append(res.Reports, backtestReport(plan, res.Bets, cfg))
+	}
+	return res, nil
+}
+
+// matches builds one models.Match per bookmaker from the replayed odds.
+func (rm *replayMatch) matches() []models.Match {
+	home, away, _ := splitTeamsFromName(rm.name)
+	byBookmaker := map[string]map[string][]models.Outcome{} // bookmaker -> eventType -> outcomes
+	for betKey, byBook := range rm.odds {
+		key, err := models.ParseBetKey(betKey)
+		if err != nil {
+			continue
+		}
+		for bk, odd := range byBook {
+			if _, ok := byBookmaker[bk]; !ok {
+				byBookmaker[bk] = map[string][]models.Outcome{}
+			}
+			byBookmaker[bk][key.EventType] = append(byBookmaker[bk][key.EventType], models.Outcome{OutcomeType: key.OutcomeType, Parameter: key.Parameter, Odds: odd})
+		}
+	}
+	matches := make([]models.Match, 0, len(byBookmaker))
+	for bk, events := range byBookmaker {
+		m := models.Match{Name: rm.name, HomeTeam: home, AwayTeam: away, StartTime: rm.startTime, Sport: rm.sport, Bookmaker: bk}
+		for eventType, outcomes := range events {
+			m.Events = append(m.Events, models.Event{EventType: eventType, Bookmaker: bk, Outcomes: outcomes})
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// replayMatchName returns "Home vs Away": the stored match name, or the normalized teams of the
+// group key (sport|home|away|start) when the name was not recorded.
+func replayMatchName(name, matchGroupKey string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	parts := strings.Split(matchGroupKey, "|")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1] + " vs " + parts[2]
+}
+
+// settleBacktestBets sets the status of bets from provider (one lookup per match).
+func settleBacktestBets(ctx context.Context, provider results.Provider, bets []BacktestBet) {
+	if provider == nil {
+		return
+	}
+	type lookup struct {
+		result *results.Result
+		err    error
+	}
+	byMatch := map[string]lookup{}
+	for i := range bets {
+		b := &bets[i]
+		l, ok := byMatch[b.MatchGroupKey]
+		if !ok {
+			home, away, _ := splitTeamsFromName(b.MatchName)
+			l.result, l.err = provider.GetResult(ctx, results.Fixture{Sport: b.Sport, HomeTeam: home, AwayTeam: away, StartTime: b.StartTime})
+			if l.err != nil {
+				slog.Warn("Backtest: failed to get match result", "match", b.MatchName, "error", l.err)
+			}
+			byMatch[b.MatchGroupKey] = l
+		}
+		if l.result == nil {
+			continue
+		}
+		if status, ok := settleBetStatus(storage.Bet{EventType: b.EventType, OutcomeType: b.OutcomeType, Parameter: b.Parameter}, *l.result); ok {
+			b.Status = status
+		}
+	}
+}
+
+// backtestReport stakes the settled bets in kick-off order with one plan.
+func backtestReport(plan string, bets []BacktestBet, cfg BacktestConfig) BacktestReport {
+	fraction := cfg.Staking.KellyFraction
+	if fraction <= 0 {
+		fraction = defaultKellyFraction
+	}
+	flat := cfg.Staking.FlatPercent
+	if flat <= 0 {
+		flat = defaultFlatStakePercent
+	}
+
+	r := BacktestReport{Plan: plan, StartBankroll: cfg.Bankroll}
+	bankroll, peak := cfg.Bankroll, cfg.Bankroll
+	var oddSum, valueSum float64
+	for _, b := range bets {
+		if b.Status == "" {
+			continue
+		}
+		var stake float64
+		switch plan {
+		case BacktestPlanFlat:
+			stake = cfg.Bankroll * flat / 100
+		case BacktestPlanKelly:
+			pct := b.KellyPercent * fraction
+			if cfg.Staking.MaxStakePercent > 0 && pct > cfg.Staking.MaxStakePercent {
+				pct = cfg.Staking.MaxStakePercent
+			}
+			stake = math.Max(bankroll, 0) * pct / 100
+		}
+		if stake <= 0 {
+			continue
+		}
+
+		r.Bets++
+		switch b.Status {
+		case storage.BetStatusWon, storage.BetStatusHalfWon:
+			r.Won++
+		case storage.BetStatusLost, storage.BetStatusHalfLost:
+			r.Lost++
+		default:
+			r.Void++
+		}
+		profit := betProfit(b.Status, stake, b.BookmakerOdd)
+		r.Turnover += stake
+		r.Profit += profit
+		oddSum += b.BookmakerOdd
+		valueSum += b.ValuePercent
+
+		bankroll += profit
+		if bankroll > peak {
+			peak = bankroll
+		}
+		if dd := peak - bankroll; dd > r.MaxDrawdown {
+			r.MaxDrawdown = dd
+			r.MaxDrawdownPercent = dd / peak * 100
+		}
+	}
+	r.FinalBankroll = bankroll
+	if r.Turnover > 0 {
+		r.YieldPercent = r.Profit / r.Turnover * 100
+	}
+	r.ROIPercent = r.Profit / cfg.Bankroll * 100
+	if r.Bets > 0 {
+		r.AvgOdd = oddSum / float64(r.Bets)
+		r.AvgValuePercent = valueSum / float64(r.Bets)
+	}
+	return r
+}
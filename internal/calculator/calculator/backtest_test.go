@@ -0,0 +1,106 @@
+package calculator
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+type fakeOddsHistory []storage.OddsHistoryRecord
+
+func (h fakeOddsHistory) ScanOddsHistory(_ context.Context, from, to time.Time, fn func(storage.OddsHistoryRecord) error) error {
+	for _, r := range h {
+		if r.RecordedAt.Before(from) || !r.RecordedAt.Before(to) {
+			continue
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeResults map[string]results.Result // home team -> result
+
+func (f fakeResults) GetResult(_ context.Context, fx results.Fixture) (*results.Result, error) {
+	r, ok := f[fx.HomeTeam]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func TestRunBacktest(t *testing.T) {
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var history fakeOddsHistory
+	add := func(home string, start time.Time, at time.Duration, bookmaker, betKey string, odd float64) {
+		history = append(history, storage.OddsHistoryRecord{
+			MatchGroupKey: "football|" + home + "|chelsea|" + start.Format(time.RFC3339), MatchName: home + " vs Chelsea", Sport: "football",
+			BetKey: betKey, Bookmaker: bookmaker, Odd: odd, StartTime: start, RecordedAt: from.Add(at),
+		})
+	}
+	// Two matches with the same prices: under 2.5 at Pinnacle is +4.2% against the raw average
+	for _, m := range []struct {
+		home  string
+		start time.Time
+	}{{"Arsenal", from.Add(2 * time.Hour)}, {"Everton", from.Add(3 * time.Hour)}} {
+		add(m.home, m.start, time.Minute, "Fonbet", "main_match|total_over|2.5", 2.0)
+		add(m.home, m.start, time.Minute, "Fonbet", "main_match|total_under|2.5", 1.8)
+		add(m.home, m.start, time.Minute, "Pinnacle", "main_match|total_over|2.5", 1.95)
+		add(m.home, m.start, time.Minute, "Pinnacle", "main_match|total_under|2.5", 1.95)
+	}
+	// The bet is taken at its first odd; in-play odds after kick-off are ignored
+	add("Arsenal", from.Add(2*time.Hour), 30*time.Minute, "Pinnacle", "main_match|total_under|2.5", 1.97)
+	add("Arsenal", from.Add(2*time.Hour), 150*time.Minute, "Pinnacle", "main_match|home_win|", 50)
+	add("Arsenal", from.Add(2*time.Hour), 150*time.Minute, "Fonbet", "main_match|home_win|", 1.5)
+
+	provider := fakeResults{
+		"Arsenal": {Status: results.StatusFinished, HomeScore: 1, AwayScore: 0}, // under wins
+		"Everton": {Status: results.StatusFinished, HomeScore: 2, AwayScore: 1}, // under loses
+	}
+	res, err := RunBacktest(context.Background(), history, provider, BacktestConfig{
+		From: from, To: from.Add(6 * time.Hour), MinValuePercent: 3, Bankroll: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Records != len(history) || len(res.Bets) != 2 || res.Unsettled != 0 {
+		t.Fatalf("expected 2 settled bets from %d records, got %+v", len(history), res)
+	}
+	if b := res.Bets[0]; b.MatchName != "Arsenal vs Chelsea" || b.BookmakerOdd != 1.95 || b.Status != storage.BetStatusWon {
+		t.Errorf("unexpected first bet: %+v", b)
+	}
+	if b := res.Bets[1]; b.Status != storage.BetStatusLost {
+		t.Errorf("unexpected second bet: %+v", b)
+	}
+
+	if len(res.Reports) != 2 || res.Reports[0].Plan != BacktestPlanFlat || res.Reports[1].Plan != BacktestPlanKelly {
+		t.Fatalf("expected flat and kelly reports, got %+v", res.Reports)
+	}
+	// Flat 1%: +9.5, then -10
+	flat := res.Reports[0]
+	if flat.Bets != 2 || flat.Won != 1 || flat.Lost != 1 || math.Abs(flat.Turnover-20) > 1e-9 || math.Abs(flat.Profit+0.5) > 1e-9 {
+		t.Errorf("unexpected flat report: %+v", flat)
+	}
+	if math.Abs(flat.YieldPercent+2.5) > 1e-9 || math.Abs(flat.ROIPercent+0.05) > 1e-9 || math.Abs(flat.FinalBankroll-999.5) > 1e-9 {
+		t.Errorf("unexpected flat yield/ROI: %+v", flat)
+	}
+	if math.Abs(flat.MaxDrawdown-10) > 1e-9 || math.Abs(flat.MaxDrawdownPercent-10/1009.5*100) > 1e-9 {
+		t.Errorf("unexpected flat drawdown: %+v", flat)
+	}
+	kelly := res.Reports[1]
+	if kelly.Bets != 2 || kelly.Turnover <= 0 || kelly.FinalBankroll >= 1000 {
+		t.Errorf("unexpected kelly report: %+v", kelly)
+	}
+
+	if _, err := RunBacktest(context.Background(), history, provider, BacktestConfig{From: from, To: from}); err == nil {
+		t.Error("empty range should fail")
+	}
+	if _, err := RunBacktest(context.Background(), history, provider, BacktestConfig{From: from, To: from.Add(time.Hour), Plans: []string{"martingale"}}); err == nil {
+		t.Error("unknown staking plan should fail")
+	}
+}
@@ -0,0 +1,419 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+)
+
+// Bet settlement defaults (value_calculator.bets).
+const (
+	defaultSettleInterval = 15 * time.Minute
+	defaultSettleDelay    = 2 * time.Hour
+)
+
+// maxRecentValueBets bounds the id → value bet map used to record bets (POST /bets).
+const maxRecentValueBets = 20000
+
+// betTracker records placed bets (POST /bets) and settles them from match results.
+type betTracker struct {
+	mu      sync.RWMutex
+	recent  map[string]ValueBet // value bet id -> last computed value bet
+	store   storage.BetStorage  // nil = /bets disabled
+	results results.Provider    // nil = bets are not settled automatically
+}
+
+func newBetTracker() *betTracker {
+	return &betTracker{recent: map[string]ValueBet{}}
+}
+
+// valueBetID returns a short stable id of a value bet (match, bet, bookmaker) for POST /bets and bot /bet.
+func valueBetID(matchGroupKey, betKey, bookmaker string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(matchGroupKey + "|" + betKey + "|" + strings.ToLower(bookmaker)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// SetBetStorage enables bet tracking (value_calculator.bets.enabled).
+func (c *ValueCalculator) SetBetStorage(s storage.BetStorage) {
+	c.bets.store = s
+}
+
+// SetResultsProvider makes the calculator settle open bets from p (value_calculator.results).
+func (c *ValueCalculator) SetResultsProvider(p results.Provider) {
+	c.bets.results = p
+}
+
+// remember keeps computed value bets so a bet can be recorded on them by id.
+func (t *betTracker) remember(valueBets []ValueBet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.recent)+len(valueBets) > maxRecentValueBets {
+		t.recent = map[string]ValueBet{}
+	}
+	for _, vb := range valueBets {
+		t.recent[vb.ID] = vb
+	}
+}
+
+// resolveValueBet finds a value bet by id: recently served value bets first, then current matches.
+func (c *ValueCalculator) resolveValueBet(ctx context.Context, id string) (ValueBet, bool) {
+	c.bets.mu.RLock()
+	vb, ok := c.bets.recent[id]
+	c.bets.mu.RUnlock()
+	if ok {
+		return vb, true
+	}
+	if c.httpClient == nil {
+		return ValueBet{}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Warn("Failed to fetch matches to resolve value bet", "value_bet_id", id, "error", err)
+		return ValueBet{}, false
+	}
+	// Any positive value: the bet may have been placed after the value shrank
+	for _, vb := range computeValueBets(matches, c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers, valueLimits{minValuePercent: 0.01}, math.MaxInt32, c.fairOdds) {
+		if vb.ID == id {
+			return vb, true
+		}
+	}
+	return ValueBet{}, false
+}
+
+// placeBetRequest is the body of POST /bets. Odd defaults to the value bet's bookmaker odd.
+type placeBetRequest struct {
+	ValueBetID string  `json:"value_bet_id"`
+	Stake      float64 `json:"stake"`
+	Odd        float64 `json:"odd"`
+	UserID     int64   `json:"user_id"`
+}
+
+// betsSummary is the P/L of a list of bets.
+type betsSummary = client.BetsSummary
+
+func summarizeBets(bets []storage.Bet) betsSummary {
+	var s betsSummary
+	s.Count = len(bets)
+	for _, b := range bets {
+		switch b.Status {
+		case storage.BetStatusOpen:
+			s.Open++
+			continue
+		case storage.BetStatusWon, storage.BetStatusHalfWon:
+			s.Won++
+		case storage.BetStatusLost, storage.BetStatusHalfLost:
+			s.Lost++
+		case storage.BetStatusVoid:
+			s.Void++
+		}
+		s.Staked += b.Stake
+		s.Profit += b.Profit
+	}
+	if s.Staked > 0 {
+		s.ROIPercent = s.Profit / s.Staked * 100
+	}
+	return s
+}
+
+// handleBets serves bet tracking: POST records a bet on a value bet, GET lists bets with P/L.
+// GET /bets?user_id=...&status=open|settled
+func (c *ValueCalculator) handleBets(w http.ResponseWriter, r *http.Request) {
+	if c.bets.store == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "bet tracking is disabled (value_calculator.bets.enabled)"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		var userID int64
+		if v := r.URL.Query().Get("user_id"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid user_id"})
+				return
+			}
+			userID = n
+		}
+		bets, err := c.bets.store.GetBets(r.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get bets", "error", err)
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get bets", "details": err.Error()})
+			return
+		}
+		bets = filterBetsByStatus(bets, r.URL.Query().Get("status"))
+		if bets == nil {
+			bets = []storage.Bet{}
+		}
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"bets": bets, "summary": summarizeBets(bets)})
+	case http.MethodPost:
+		var req placeBetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		bet, err := c.newBet(r.Context(), req, time.Now())
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := c.bets.store.StoreBet(r.Context(), &bet); err != nil {
+			slog.Error("Failed to store bet", "value_bet_id", bet.ValueBetID, "error", err)
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store bet", "details": err.Error()})
+			return
+		}
+		slog.Info("Bet recorded", "id", bet.ID, "user_id", bet.UserID, "match", bet.MatchName, "bet_key", bet.BetKey, "bookmaker", bet.Bookmaker, "odd", bet.Odd, "stake", bet.Stake)
+		writeWebAppJSON(w, http.StatusOK, bet)
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET or POST"})
+	}
+}
+
+// newBet validates req and builds the bet to store from its value bet.
+func (c *ValueCalculator) newBet(ctx context.Context, req placeBetRequest, now time.Time) (storage.Bet, error) {
+	id := strings.TrimSpace(req.ValueBetID)
+	if id == "" {
+		return storage.Bet{}, fmt.Errorf("value_bet_id is required")
+	}
+	if req.Stake <= 0 || math.IsNaN(req.Stake) || math.IsInf(req.Stake, 0) {
+		return storage.Bet{}, fmt.Errorf("stake must be positive")
+	}
+	vb, ok := c.resolveValueBet(ctx, id)
+	if !ok {
+		return storage.Bet{}, fmt.Errorf("value bet %q not found (expired?)", id)
+	}
+	odd := vb.BookmakerOdd
+	if req.Odd != 0 {
+		if req.Odd <= 1 || !isFinitePositiveOdd(req.Odd) {
+			return storage.Bet{}, fmt.Errorf("invalid odd %v", req.Odd)
+		}
+		odd = req.Odd
+	}
+	home, away, _ := strings.Cut(vb.MatchName, " vs ")
+	return storage.Bet{
+		ValueBetID:      vb.ID,
+		UserID:          req.UserID,
+		MatchGroupKey:   vb.MatchGroupKey,
+		MatchName:       vb.MatchName,
+		HomeTeam:        home,
+		AwayTeam:        away,
+		StartTime:       vb.StartTime,
+		Sport:           vb.Sport,
+		EventType:       vb.EventType,
+		OutcomeType:     vb.OutcomeType,
+		Parameter:       vb.Parameter,
+		BetKey:          vb.BetKey,
+		Bookmaker:       vb.Bookmaker,
+		Odd:             odd,
+		Stake:           req.Stake,
+		FairProbability: vb.FairProbability,
+		ValuePercent:    (odd*vb.FairProbability - 1) * 100,
+		Status:          storage.BetStatusOpen,
+		PlacedAt:        now.UTC(),
+	}, nil
+}
+
+// filterBetsByStatus keeps "open" or "settled" bets; any other status keeps all.
+func filterBetsByStatus(bets []storage.Bet, status string) []storage.Bet {
+	switch status {
+	case "open", "settled":
+	default:
+		return bets
+	}
+	var out []storage.Bet
+	for _, b := range bets {
+		if (b.Status == storage.BetStatusOpen) == (status == "open") {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// runBetSettlement settles open bets every value_calculator.bets.settle_interval until ctx is done.
+func (c *ValueCalculator) runBetSettlement(ctx context.Context) {
+	interval := defaultSettleInterval
+	if c.cfg != nil && c.cfg.Bets.SettleInterval > 0 {
+		interval = c.cfg.Bets.SettleInterval
+	}
+	slog.Info("Bet settlement started", "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if c.isLeader() {
+			c.settleOpenBets(ctx, time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// settleOpenBets settles open bets whose match started more than settle_delay ago and whose result is known.
+func (c *ValueCalculator) settleOpenBets(ctx context.Context, now time.Time) {
+	delay := defaultSettleDelay
+	if c.cfg != nil && c.cfg.Bets.SettleDelay > 0 {
+		delay = c.cfg.Bets.SettleDelay
+	}
+	open, err := c.bets.store.GetOpenBets(ctx, now.Add(-delay))
+	if err != nil {
+		slog.Error("Failed to get open bets for settlement", "error", err)
+		return
+	}
+
+	// One lookup per match
+	type lookup struct {
+		result *results.Result
+		err    error
+	}
+	lookups := map[string]lookup{}
+	settled := 0
+	for _, b := range open {
+		l, ok := lookups[b.MatchGroupKey]
+		if !ok {
+			l.result, l.err = c.bets.results.GetResult(ctx, results.Fixture{Sport: b.Sport, HomeTeam: b.HomeTeam, AwayTeam: b.AwayTeam, StartTime: b.StartTime})
+			lookups[b.MatchGroupKey] = l
+			if l.err != nil {
+				slog.Warn("Failed to get match result", "match", b.MatchName, "error", l.err)
+			}
+		}
+		if l.err != nil || l.result == nil {
+			continue
+		}
+		status, ok := settleBetStatus(b, *l.result)
+		if !ok {
+			continue
+		}
+		b.Status = status
+		b.Profit = betProfit(status, b.Stake, b.Odd)
+		settledAt := now.UTC()
+		b.SettledAt = &settledAt
+		if l.result.Status == results.StatusFinished {
+			home, away := l.result.HomeScore, l.result.AwayScore
+			b.HomeScore, b.AwayScore = &home, &away
+		}
+		if err := c.bets.store.SettleBet(ctx, b); err != nil {
+			slog.Error("Failed to settle bet", "id", b.ID, "error", err)
+			continue
+		}
+		settled++
+		slog.Info("Bet settled", "id", b.ID, "user_id", b.UserID, "match", b.MatchName, "bet_key", b.BetKey, "status", b.Status, "profit", b.Profit)
+	}
+	if len(open) > 0 {
+		slog.Info("Bet settlement complete", "open", len(open), "settled", settled)
+	}
+}
+
+// settleBetStatus returns the status of b given the match result; ok=false while the match is not
+// finished or when the market cannot be settled from the full-time score (e.g. corners).
+func settleBetStatus(b storage.Bet, r results.Result) (string, bool) {
+	switch r.Status {
+	case results.StatusCancelled:
+		return storage.BetStatusVoid, true
+	case results.StatusFinished:
+	default:
+		return "", false
+	}
+	if b.EventType != "main_match" {
+		return "", false
+	}
+	home, away := float64(r.HomeScore), float64(r.AwayScore)
+
+	switch b.OutcomeType {
+	case "home_win":
+		return winOrLose(home > away), true
+	case "draw":
+		return winOrLose(home == away), true
+	case "away_win":
+		return winOrLose(away > home), true
+	}
+
+	line, err := parseLine(b.Parameter)
+	if err != nil {
+		return "", false
+	}
+	var margin func(line float64) float64
+	switch b.OutcomeType {
+	case "total_over", "alt_total_over":
+		margin = func(line float64) float64 { return home + away - line }
+	case "total_under", "alt_total_under":
+		margin = func(line float64) float64 { return line - home - away }
+	case "handicap_home":
+		margin = func(line float64) float64 { return home + line - away }
+	case "handicap_away":
+		margin = func(line float64) float64 { return away + line - home }
+	default:
+		return "", false
+	}
+	return settleAsianLine(margin, line), true
+}
+
+func winOrLose(won bool) string {
+	if won {
+		return storage.BetStatusWon
+	}
+	return storage.BetStatusLost
+}
+
+// settleAsianLine settles a line bet given its margin at a line (>0 win, 0 push, <0 loss).
+// Quarter lines (e.g. 2.25) are half on each neighbouring line (2 and 2.5).
+func settleAsianLine(margin func(line float64) float64, line float64) string {
+	sign := func(x float64) int {
+		switch {
+		case x > 1e-9:
+			return 1
+		case x < -1e-9:
+			return -1
+		}
+		return 0
+	}
+	if q := line * 4; math.Abs(q-math.Round(q)) < 1e-9 && int(math.Round(q))%2 != 0 {
+		switch sign(margin(line-0.25)) + sign(margin(line+0.25)) {
+		case 2:
+			return storage.BetStatusWon
+		case 1:
+			return storage.BetStatusHalfWon
+		case -1:
+			return storage.BetStatusHalfLost
+		case -2:
+			return storage.BetStatusLost
+		}
+		return storage.BetStatusVoid
+	}
+	switch sign(margin(line)) {
+	case 1:
+		return storage.BetStatusWon
+	case -1:
+		return storage.BetStatusLost
+	}
+	return storage.BetStatusVoid
+}
+
+// betProfit is the P/L of a settled bet in stake units.
+func betProfit(status string, stake, odd float64) float64 {
+	switch status {
+	case storage.BetStatusWon:
+		return stake * (odd - 1)
+	case storage.BetStatusHalfWon:
+		return stake * (odd - 1) / 2
+	case storage.BetStatusHalfLost:
+		return -stake / 2
+	case storage.BetStatusLost:
+		return -stake
+	}
+	return 0
+}
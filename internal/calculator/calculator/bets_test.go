@@ -0,0 +1,102 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/results"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestSettleBetStatus(t *testing.T) {
+	finished := func(home, away int) results.Result {
+		return results.Result{Status: results.StatusFinished, HomeScore: home, AwayScore: away}
+	}
+	tests := []struct {
+		outcome, param string
+		result         results.Result
+		want           string
+	}{
+		{"home_win", "", finished(2, 1), storage.BetStatusWon},
+		{"draw", "", finished(2, 1), storage.BetStatusLost},
+		{"draw", "", finished(1, 1), storage.BetStatusWon},
+		{"away_win", "", finished(0, 3), storage.BetStatusWon},
+		{"total_over", "2.5", finished(2, 1), storage.BetStatusWon},
+		{"total_under", "2.5", finished(2, 1), storage.BetStatusLost},
+		{"total_over", "3", finished(2, 1), storage.BetStatusVoid},
+		{"total_over", "2.75", finished(2, 1), storage.BetStatusHalfWon},   // 2.5 won, 3 void
+		{"total_under", "3.25", finished(2, 1), storage.BetStatusHalfWon},  // 3 void, 3.5 won
+		{"total_under", "2.75", finished(2, 1), storage.BetStatusHalfLost}, // 2.5 lost, 3 void
+		{"handicap_home", "-1.5", finished(2, 1), storage.BetStatusLost},
+		{"handicap_home", "-1", finished(2, 1), storage.BetStatusVoid},
+		{"handicap_away", "+1.5", finished(2, 1), storage.BetStatusWon},
+		{"handicap_away", "+0.25", finished(2, 1), storage.BetStatusLost},
+		{"handicap_home", "-0.25", finished(1, 1), storage.BetStatusHalfLost},
+		{"home_win", "", results.Result{Status: results.StatusCancelled}, storage.BetStatusVoid},
+	}
+	for _, tt := range tests {
+		b := storage.Bet{EventType: "main_match", OutcomeType: tt.outcome, Parameter: tt.param}
+		got, ok := settleBetStatus(b, tt.result)
+		if !ok || got != tt.want {
+			t.Errorf("%s %s at %d:%d: got %q (ok=%v), want %q", tt.outcome, tt.param, tt.result.HomeScore, tt.result.AwayScore, got, ok, tt.want)
+		}
+	}
+
+	// Not settled: match not finished, or market not derivable from the score
+	if _, ok := settleBetStatus(storage.Bet{EventType: "main_match", OutcomeType: "home_win"}, results.Result{Status: results.StatusScheduled}); ok {
+		t.Error("scheduled match must not be settled")
+	}
+	if _, ok := settleBetStatus(storage.Bet{EventType: "corners", OutcomeType: "total_over", Parameter: "9.5"}, finished(2, 1)); ok {
+		t.Error("corners must not be settled from the score")
+	}
+}
+
+func TestBetsSummary(t *testing.T) {
+	bets := []storage.Bet{
+		{Stake: 100, Odd: 2.5, Status: storage.BetStatusWon},
+		{Stake: 100, Odd: 1.9, Status: storage.BetStatusLost},
+		{Stake: 100, Odd: 2.0, Status: storage.BetStatusHalfWon},
+		{Stake: 100, Odd: 2.0, Status: storage.BetStatusOpen},
+	}
+	for i := range bets {
+		bets[i].Profit = betProfit(bets[i].Status, bets[i].Stake, bets[i].Odd)
+	}
+	s := summarizeBets(bets)
+	if s.Open != 1 || s.Won != 2 || s.Lost != 1 || s.Staked != 300 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if want := 150.0 - 100 + 50; math.Abs(s.Profit-want) > 1e-9 || math.Abs(s.ROIPercent-want/3) > 1e-9 {
+		t.Errorf("profit %.2f roi %.2f, want %.2f and %.2f", s.Profit, s.ROIPercent, want, want/3)
+	}
+	if got := filterBetsByStatus(bets, "open"); len(got) != 1 {
+		t.Errorf("open bets: got %d, want 1", len(got))
+	}
+	if got := filterBetsByStatus(bets, "settled"); len(got) != 3 {
+		t.Errorf("settled bets: got %d, want 3", len(got))
+	}
+}
+
+func TestComputeCalibration(t *testing.T) {
+	bets := []storage.Bet{
+		{FairProbability: 0.55, Odd: 2.0, Status: storage.BetStatusWon},
+		{FairProbability: 0.52, Odd: 2.0, Status: storage.BetStatusLost},
+		{FairProbability: 0.25, Odd: 4.5, Status: storage.BetStatusLost},
+		{FairProbability: 0.5, Odd: 2.1, Status: storage.BetStatusVoid},    // not a binary outcome
+		{FairProbability: 0.5, Odd: 2.1, Status: storage.BetStatusHalfWon}, // not a binary outcome
+		{FairProbability: 0.5, Odd: 2.1, Status: storage.BetStatusOpen},
+	}
+	r := computeCalibration(bets)
+	if r.Samples != 3 {
+		t.Fatalf("samples = %d, want 3", r.Samples)
+	}
+	if r.Bins[5].Count != 2 || math.Abs(r.Bins[5].AvgPredicted-0.535) > 1e-9 || r.Bins[5].ObservedRate != 0.5 {
+		t.Errorf("bin 0.5-0.6: %+v", r.Bins[5])
+	}
+	if r.Bins[2].Count != 1 || r.Bins[2].ObservedRate != 0 || r.Bins[2].ProfitPerUnit != -1 {
+		t.Errorf("bin 0.2-0.3: %+v", r.Bins[2])
+	}
+	wantBrier := ((0.55-1)*(0.55-1) + 0.52*0.52 + 0.25*0.25) / 3
+	if math.Abs(r.BrierScore-wantBrier) > 1e-9 {
+		t.Errorf("brier = %.6f, want %.6f", r.BrierScore, wantBrier)
+	}
+}
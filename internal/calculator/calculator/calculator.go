@@ -8,8 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 // ValueCalculator reads odds from HTTP endpoint and calculates top diffs between bookmakers.
@@ -29,25 +32,92 @@ type ValueCalculator struct {
 	alertsLineMovementEnabled bool // алерты по прогрузам
 	asyncCtx                 context.Context
 	asyncCancel              context.CancelFunc
+	events                   *eventTracker // value_detected / alert_retracted / match_started for the event log
+	ignores                  *ignoreList   // matches excluded from calculation (/ignores, bot button)
+	teamAliases              storage.TeamAliasStorage // aliases added through /admin/team-aliases (nil = in memory only)
+	leagueAliases            storage.LeagueAliasStorage // aliases added through /admin/league-aliases (nil = in memory only)
+	warehouse                storage.WarehouseStorage // research schema ETL (nil = disabled)
+	mainPipeline             *valuePipeline // every sport except cyber football
+	cyberPipeline            *valuePipeline // cyber football on its own cycle (nil = value_calculator.cyber disabled)
+	arbs                     *ArbitrageCalculator // surebets: GET /arbs/top, stored on every async cycle when enabled
+	fairOdds                 FairOddsMethod       // margin removal for value bets (value_calculator.fair_odds_method)
+	bets                     *betTracker          // placed bets (/bets) and their settlement
+	history                  *valueHistoryTracker // value bet lifecycles (/value-bets/history)
+	subscriptions            *subscriptionList    // per-chat alert streams (/subscriptions)
+	languages                *chatLanguageList    // per-chat message language (/chat-language)
+	channels                 *channelPublisher    // Telegram channels and groups alerts are published to (nil = none)
+	steamAlerted             map[string]time.Time // chat_id|steam alert key -> last alert (line movement goroutine only)
+	leader                   *leaderElection      // replicas sharing a database (value_calculator.leader_election)
+	decisions                *decisionLog         // why each diff was or wasn't alerted (/diagnostics/decisions)
+	oddsHistory              *oddsHistoryPolicy   // keyframes and retention of odds_snapshot_history
+	cache                    *responseCache       // Redis cache of hot queries (value_calculator.redis_cache; nil = off)
+	oddsArchive              *oddsArchiver        // long-term archive of odds snapshots (storage.clickhouse; nil = off)
+	stream                   *valueStream         // clients of GET /stream/value-bets
 }
 
 func NewValueCalculator(cfg *config.ValueCalculatorConfig, diffStorage storage.DiffBetStorage, oddsSnapshotStorage storage.OddsSnapshotStorage) *ValueCalculator {
+	ignores := newIgnoreList()
+	cache := newResponseCache(cfg)
 	var httpClient *HTTPMatchesClient
 	if cfg != nil && cfg.ParserURL != "" {
 		httpClient = NewHTTPMatchesClient(cfg.ParserURL)
+		httpClient.ignores = ignores
+		httpClient.consistency = newConsistencyValidator(cfg)
+		httpClient.cache = cache
 	}
 
 	var notifier *TelegramNotifier
-	if cfg != nil && cfg.AsyncEnabled && cfg.TelegramBotToken != "" && cfg.TelegramChatID != 0 {
+	// telegram_chat_id may be unset when alerts only go to subscriptions
+	if cfg != nil && cfg.AsyncEnabled && cfg.TelegramBotToken != "" {
 		notifier = NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID)
 	}
+	languages := newChatLanguageList()
+	if notifier != nil {
+		notifier.ignores = ignores
+		notifier.languages = languages
+	}
+	channels := newChannelPublisher(cfg)
+	if notifier != nil {
+		notifier.channels = channels
+		notifier.charts = cfg.LineMovementCharts
+		notifier.dedup = newDeliveryDedup(cfg, botChatMember(notifier.bot))
+	}
+
+	fairOdds, err := fairOddsMethodByName("")
+	if cfg != nil {
+		if fairOdds, err = fairOddsMethodByName(cfg.FairOddsMethod); err != nil {
+			slog.Warn("Invalid fair_odds_method, margin is not removed", "error", err)
+			fairOdds, _ = fairOddsMethodByName("")
+		}
+	}
+	if cfg != nil && cfg.MinSharpBookmakers > 0 && len(cfg.SharpBookmakers) == 0 && len(cfg.ReferenceBookmakers) == 0 {
+		slog.Warn("min_sharp_bookmakers is set but neither sharp_bookmakers nor reference_bookmakers are, no value bets will pass")
+	}
 
+	events := newEventTracker()
 	return &ValueCalculator{
 		httpClient:          httpClient,
 		cfg:                  cfg,
 		diffStorage:         diffStorage,
 		oddsSnapshotStorage: oddsSnapshotStorage,
 		notifier:            notifier,
+		events:              events,
+		ignores:             ignores,
+		mainPipeline:        newMainPipeline(cfg, events),
+		cyberPipeline:       newCyberPipeline(cfg),
+		arbs:                newArbitrageCalculator(httpClient, cfg),
+		fairOdds:            fairOdds,
+		bets:                newBetTracker(),
+		history:             newValueHistoryTracker(),
+		subscriptions:       newSubscriptionList(),
+		languages:           languages,
+		channels:            channels,
+		steamAlerted:        map[string]time.Time{},
+		leader:              &leaderElection{},
+		decisions:           newDecisionLog(cfg),
+		oddsHistory:         newOddsHistoryPolicy(cfg),
+		cache:               cache,
+		stream:              newValueStream(),
 	}
 }
 
@@ -58,8 +128,39 @@ func (c *ValueCalculator) Start(ctx context.Context) error {
 		c.asyncCtx, c.asyncCancel = context.WithCancel(ctx)
 		c.asyncMu.Unlock()
 
+		// Only the replica holding the leader lock runs the cycle; try to take it before the first one
+		if c.leader.lock != nil {
+			c.campaign(ctx)
+			go c.runLeaderElection(ctx)
+		}
+
 		c.StartAsync()
 
+		// Nightly export of odds history into the research warehouse
+		if c.warehouse != nil {
+			go c.runNightlyWarehouseETL(ctx)
+		}
+
+		// Daily/weekly digests to telegram_chat_id and subscriptions
+		if c.digestsEnabled() {
+			go c.runDigests(ctx)
+		}
+
+		// Daily pinned summaries of the Telegram channels
+		if c.notifier != nil && c.channels.hasSummaries() {
+			go c.runChannelSummaries(ctx)
+		}
+
+		// Day partitions of the odds history: created ahead, dropped after value_calculator.odds_history.retention
+		if c.oddsSnapshotStorage != nil {
+			go c.runOddsHistoryRetention(ctx)
+		}
+
+		// Batched inserts of the odds snapshots into the long-term archive
+		if c.oddsArchive != nil {
+			go c.oddsArchive.run(ctx)
+		}
+
 		// Periodic full DB cleanup (interval from config; default 2h; empty = disabled)
 		if c.diffStorage != nil {
 			interval := parseDBFullCleanupInterval(c.cfg)
@@ -71,10 +172,18 @@ func (c *ValueCalculator) Start(ctx context.Context) error {
 		slog.Info("Async processing disabled, running in on-demand mode")
 	}
 
+	// Settle placed bets from match results (value_calculator.bets + results)
+	if c.bets.store != nil && c.bets.results != nil {
+		go c.runBetSettlement(ctx)
+	}
+
 	// Wait for context cancellation
 	<-ctx.Done()
 
 	c.StopAsync(true) // true = shutdown, stop notifier too
+	if c.oddsArchive != nil {
+		c.oddsArchive.close()
+	}
 
 	return nil
 }
@@ -106,6 +215,11 @@ func (c *ValueCalculator) runPeriodicDBCleanup(ctx context.Context, interval tim
 			slog.Info("Periodic DB cleanup stopped")
 			return
 		case <-ticker.C:
+			if !c.isLeader() {
+				continue
+			}
+			// Export odds history to the research warehouse while odds_snapshots still has the match names
+			c.exportWarehouse(ctx, "before_cleanup")
 			cleanCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 			if err := c.diffStorage.CleanDiffBets(cleanCtx); err != nil {
 				slog.Error("Periodic cleanup: CleanDiffBets failed", "error", err)
@@ -116,7 +230,12 @@ func (c *ValueCalculator) runPeriodicDBCleanup(ctx context.Context, interval tim
 				if err := c.oddsSnapshotStorage.CleanAll(cleanCtx); err != nil {
 					slog.Error("Periodic cleanup: odds CleanAll failed", "error", err)
 				} else {
-					slog.Info("Periodic cleanup: odds_snapshots and odds_snapshot_history cleared")
+					slog.Info("Periodic cleanup: odds_snapshots cleared")
+				}
+			}
+			if c.arbs.store != nil {
+				if err := c.arbs.store.CleanArbitrages(cleanCtx); err != nil {
+					slog.Error("Periodic cleanup: CleanArbitrages failed", "error", err)
 				}
 			}
 			cancel()
@@ -174,6 +293,11 @@ func (c *ValueCalculator) StartAsync() error {
 
 	slog.Info("Starting async processing", "interval", interval)
 	go c.runAsyncProcessing(c.asyncCtx)
+	if c.cyberPipeline != nil {
+		every := cyberInterval(c.cfg)
+		slog.Info("Starting cyber football processing", "interval", every, "threshold", c.cyberPipeline.alertThreshold)
+		go c.runCyberProcessing(c.asyncCtx, every)
+	}
 
 	return nil
 }
@@ -211,13 +335,28 @@ func (c *ValueCalculator) runAsyncProcessing(ctx context.Context) {
 	}
 }
 
-// runAsyncIteration runs value/diff processing and line movement in parallel
+// runAsyncIteration runs value/diff processing, line movement, the arbitrage scan and value history in parallel.
+// Followers skip it; the first iteration of a new leader restores the cycle state the previous one saved.
 func (c *ValueCalculator) runAsyncIteration(ctx context.Context) {
+	run, restore := c.leaderCycle(&c.leader.mainTerm)
+	if !run {
+		slog.Debug("Not the calculator leader, skipping async iteration")
+		return
+	}
+	if restore {
+		c.restoreValueAlerts(ctx, c.mainPipeline)
+		c.restoreSteamAlerts(ctx)
+		c.history.reset()
+	}
+	// One trace per iteration: fetching /matches, the DB queries and the alerts it queues
+	ctx, span := tracing.Start(ctx, "calculator.iteration")
+	defer span.End()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		c.processMatchesAsync(ctx)
+		c.processMatchesAsync(ctx, c.mainPipeline)
 	}()
 	if c.cfg != nil && c.cfg.LineMovementEnabled && c.oddsSnapshotStorage != nil {
 		wg.Add(1)
@@ -226,7 +365,31 @@ func (c *ValueCalculator) runAsyncIteration(ctx context.Context) {
 			c.processLineMovementsAsync(ctx)
 		}()
 	}
+	if c.arbs.enabled() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.arbs.process(ctx)
+		}()
+	}
+	if c.valueHistoryEnabled() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.processValueHistory(ctx)
+		}()
+	}
+	if c.httpClient != nil && c.stream.active() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.processValueStream(ctx)
+		}()
+	}
 	wg.Wait()
+
+	c.saveValueAlerts(ctx, c.mainPipeline)
+	c.saveSteamAlerts(ctx)
 }
 
 // runTestAlerts sends test alerts every 5 minutes to verify notification system
@@ -263,8 +426,8 @@ func (c *ValueCalculator) runTestAlerts(ctx context.Context) {
 	}
 }
 
-// processMatchesAsync processes matches asynchronously and sends alerts for new high-value diffs
-func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
+// processMatchesAsync processes the matches of pipeline p and sends alerts for new high-value diffs
+func (c *ValueCalculator) processMatchesAsync(ctx context.Context, p *valuePipeline) {
 	if c.httpClient == nil {
 		slog.Debug("Parser URL not configured, skipping async processing")
 		return
@@ -275,22 +438,13 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 		return
 	}
 
-	alertThreshold := 0.0
-	if c.cfg != nil {
-		// Preferred single threshold
-		if c.cfg.AlertThreshold > 0 {
-			alertThreshold = c.cfg.AlertThreshold
-		} else if c.cfg.AlertThreshold20 > 0 {
-			// Backward compatibility
-			alertThreshold = c.cfg.AlertThreshold20
-		} else if c.cfg.AlertThreshold10 > 0 {
-			// Backward compatibility
-			alertThreshold = c.cfg.AlertThreshold10
-		}
-	}
+	alertThreshold := p.alertThreshold
+
+	ctx, span := tracing.Start(ctx, "value.cycle", attribute.String("pipeline", p.name))
+	defer span.End()
 
 	iterationStartedAt := time.Now()
-	slog.Info("Async value iteration started", "started_at", iterationStartedAt.UTC().Format(time.RFC3339))
+	slog.Info("Async value iteration started", "pipeline", p.name, "started_at", iterationStartedAt.UTC().Format(time.RFC3339))
 
 	slog.Debug("Fetching matches for async processing...")
 
@@ -300,9 +454,10 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 
 	matches, err := c.httpClient.GetMatchesAll(reqCtx)
 	if err != nil {
-		slog.Error("Failed to fetch matches for async processing", "error", err.Error())
+		slog.Error("Failed to fetch matches for async processing", "pipeline", p.name, "error", err.Error())
 		return
 	}
+	matches = p.filter(matches)
 
 	// Log merged match counts by sport (football vs esports)
 	matchesBySport := make(map[string]int)
@@ -313,7 +468,7 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 		}
 		matchesBySport[s]++
 	}
-	slog.Info("Merged matches by sport", "total", len(matches), "by_sport", matchesBySport)
+	slog.Info("Merged matches by sport", "pipeline", p.name, "total", len(matches), "by_sport", matchesBySport)
 
 	// Calculate all diffs
 	diffs := computeTopDiffs(matches, 1000) // Get more diffs for async processing
@@ -327,7 +482,7 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 		}
 		diffsBySport[s]++
 	}
-	slog.Info("Diffs by sport", "total", len(diffs), "by_sport", diffsBySport)
+	slog.Info("Diffs by sport", "pipeline", p.name, "total", len(diffs), "by_sport", diffsBySport)
 
 	logStatisticalEventsSummary(matches)
 
@@ -335,67 +490,36 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 
 	// Store diffs and check for new high-value ones
 	alertCount := 0
-	// Time window to prevent duplicate alerts
-	// This prevents sending the same alert repeatedly for unchanged diffs
-	alertCooldownMinutes := 60 // Default: 60 minutes
-	if c.cfg != nil && c.cfg.AlertCooldownMinutes > 0 {
-		alertCooldownMinutes = c.cfg.AlertCooldownMinutes
-	}
-	// Minimum increase in diff_percent to send alert again even if already sent recently
-	alertMinIncrease := 5.0 // Default: 5% increase
-	if c.cfg != nil && c.cfg.AlertMinIncrease > 0 {
-		alertMinIncrease = c.cfg.AlertMinIncrease
-	}
 
-	maxOdds := 0.0
-	if c.cfg != nil && c.cfg.MaxOdds > 0 {
-		maxOdds = c.cfg.MaxOdds
+	maxOdds := p.maxOdds
+
+	aboveThreshold := make(map[string]bool)
+	alertAbove := make(map[string]bool)
+	diffsByKey := make(map[string]*DiffBet, len(diffs))
+	diffsByAlertKey := make(map[string]*DiffBet, len(diffs))
+	for i := range diffs {
+		diffsByKey[diffEventKey(&diffs[i])] = &diffs[i]
+		diffsByAlertKey[valueAlertKey(&diffs[i])] = &diffs[i]
 	}
+	restoreAlerts := p.alerts.firstCycle()
+	recipients := c.alertRecipients(storage.AlertTypeValue)
+	limits := valueLimitsFromConfig(c.cfg)
+	decisions := c.decisions.cycle(p.name, iterationStartedAt)
 
 	for _, diff := range diffs {
+		isValue := alertThreshold > 0 && diff.DiffPercent > alertThreshold
+		if isValue {
+			aboveThreshold[diffEventKey(&diff)] = true
+		}
+
 		// Skip high-odds diffs: variance is higher, value is less reliable
 		if maxOdds > 0 && diff.MaxOdd > maxOdds {
 			_, _ = c.diffStorage.StoreDiffBet(ctx, &diff)
-			continue
-		}
-
-		// Check if we should send an alert for this diff
-		shouldSendAlert := false
-		if alertThreshold > 0 && diff.DiffPercent > alertThreshold && c.notifier != nil {
-			// Get the last diff for this match+bet combination (excluding current one)
-			lastDiffPercent, lastCalculatedAt, err := c.diffStorage.GetLastDiffBet(ctx, diff.MatchGroupKey, diff.BetKey, diff.CalculatedAt)
-			if err != nil {
-				slog.Warn("Failed to get last diff", "error", err.Error())
-				// Continue anyway - better to send duplicate than miss an alert
-				shouldSendAlert = true
-			} else if lastDiffPercent == 0 || lastCalculatedAt.IsZero() {
-				// No previous diff found - this is a new diff, send alert
-				shouldSendAlert = true
-			} else if lastDiffPercent < alertThreshold {
-				// Previous diff was below threshold, so no alert was sent
-				// This is the first time diff exceeds threshold, send alert
-				shouldSendAlert = true
-				slog.Info("Diff crossed threshold, sending alert", "match", diff.MatchName, "from", lastDiffPercent, "to", diff.DiffPercent)
-			} else {
-				// Previous diff was also above threshold - check if alert was sent recently
-				timeSinceLastAlert := time.Since(lastCalculatedAt)
-				if timeSinceLastAlert > time.Duration(alertCooldownMinutes)*time.Minute {
-					// Last alert was sent more than cooldown minutes ago, send alert
-					shouldSendAlert = true
-					slog.Info("Cooldown expired, sending alert", "match", diff.MatchName, "diff_percent", diff.DiffPercent)
-				} else {
-					// Last alert was sent recently - check if diff increased significantly
-					diffIncrease := diff.DiffPercent - lastDiffPercent
-					if diffIncrease >= alertMinIncrease {
-						// Diff increased significantly, send alert again
-						shouldSendAlert = true
-						slog.Info("Diff increased significantly, sending alert", "match", diff.MatchName, "from", lastDiffPercent, "to", diff.DiffPercent, "increase", diffIncrease)
-					} else {
-						// Diff didn't increase significantly, skip
-						slog.Debug("Skipping duplicate alert", "match", diff.MatchName, "from", lastDiffPercent, "to", diff.DiffPercent, "increase", diffIncrease, "minutes_since_last", timeSinceLastAlert.Minutes(), "min_increase", alertMinIncrease)
-					}
-				}
+			if isValue {
+				p.events.valueDecision(&diff, decisionMaxOdds, alertThreshold)
 			}
+			decisions.add(&diff, 0, alertThreshold, decisionMaxOdds, fmt.Sprintf("max odd %.2f above max_odds %.2f", diff.MaxOdd, maxOdds))
+			continue
 		}
 
 		// Store the diff (pass as interface{} to match interface)
@@ -406,32 +530,132 @@ func (c *ValueCalculator) processMatchesAsync(ctx context.Context) {
 			// Continue even if storage fails
 		}
 
-		// Send Telegram alert if needed (and value alerts are enabled)
-		c.asyncMu.RLock()
-		valueAlertsOn := c.alertsValueEnabled
-		c.asyncMu.RUnlock()
-		if shouldSendAlert && valueAlertsOn {
-			thresholdInt := int(math.Round(alertThreshold))
-			queuedAt := time.Now()
-			if err := c.notifier.SendDiffAlert(ctx, &diff, thresholdInt); err != nil {
-				slog.Error("Failed to queue value alert", "match", diff.MatchName, "threshold", alertThreshold, "error", err.Error())
-			} else {
+		// Alert every chat whose threshold the diff passes (the alert state machine dedups cycles)
+		decision := decisionDuplicate
+		switch {
+		case c.notifier == nil:
+			decision = decisionNoNotifier
+		case len(recipients) == 0:
+			decision = decisionAlertsDisabled
+		}
+		if decision != decisionDuplicate {
+			decisions.add(&diff, 0, alertThreshold, decision, "")
+		}
+		// A stale best odd keeps its alert state (no "closed" notification) but isn't alerted
+		stale := staleReason(&diff, limits, iterationStartedAt)
+		for _, rc := range recipients {
+			threshold := rc.valueThreshold(alertThreshold)
+			if threshold <= 0 || diff.DiffPercent <= threshold {
+				decisions.add(&diff, rc.chatID, threshold, decisionBelowThreshold, "")
+				continue
+			}
+			if filter := rc.rejectsDiff(&diff); filter != "" {
+				decisions.add(&diff, rc.chatID, threshold, decisionFiltered, filter)
+				continue
+			}
+			alertAbove[valueAlertStateKey(rc.chatID, &diff)] = true
+			if stale != "" {
+				decisions.add(&diff, rc.chatID, threshold, decisionStale, stale)
+				if decision == decisionDuplicate {
+					decision = decisionStale
+				}
+				continue
+			}
+			chatDecision, reason := c.alertValue(ctx, p, &diff, rc.chatID, threshold, restoreAlerts)
+			decisions.add(&diff, rc.chatID, threshold, chatDecision, reason)
+			switch chatDecision {
+			case decisionAlertQueued:
+				decision = decisionAlertQueued
 				alertCount++
-				delaySinceCalc := queuedAt.Sub(diff.CalculatedAt)
-				slog.Info("Value alert queued",
-					"match", diff.MatchName,
-					"diff_percent", diff.DiffPercent,
-					"threshold", alertThreshold,
-					"calculated_at", diff.CalculatedAt.UTC().Format(time.RFC3339),
-					"queued_at", queuedAt.UTC().Format(time.RFC3339),
-					"delay_since_calculation_sec", delaySinceCalc.Seconds(),
-					"queue_length", c.notifier.QueueLen())
+			case decisionQueueFailed:
+				if decision != decisionAlertQueued {
+					decision = decisionQueueFailed
+				}
 			}
 		}
+		if isValue {
+			p.events.valueDecision(&diff, decision, alertThreshold)
+		}
 	}
+	c.decisions.save(ctx, decisions)
+	p.events.finishCycle(aboveThreshold, diffsByKey, matches, time.Now())
+	closedCount := c.notifyValuesClosed(ctx, p.alerts.finishCycle(alertAbove, diffsByAlertKey, time.Now()))
 
 	iterationDuration := time.Since(iterationStartedAt)
-	slog.Info("Async value iteration complete", "alerts_queued", alertCount, "threshold", alertThreshold, "duration_sec", iterationDuration.Seconds())
+	slog.Info("Async value iteration complete", "pipeline", p.name, "alerts_queued", alertCount, "closed_queued", closedCount, "threshold", alertThreshold, "duration_sec", iterationDuration.Seconds())
+}
+
+// alertValue queues a value alert for diff to chatID unless the alert state machine has already alerted it
+// there, and returns the decision (decisionAlertQueued, decisionQueueFailed or decisionDuplicate) and its details.
+// restore: first cycle after a restart, the previous run may have alerted the diff already.
+func (c *ValueCalculator) alertValue(ctx context.Context, p *valuePipeline, diff *DiffBet, chatID int64, threshold float64, restore bool) (string, string) {
+	kind, prevAlertPercent := p.alerts.check(chatID, diff, time.Now())
+	if kind == valueAlertNew && restore {
+		lastDiffPercent, lastCalculatedAt, err := c.diffStorage.GetLastDiffBet(ctx, diff.MatchGroupKey, diff.BetKey, diff.CalculatedAt)
+		if err != nil {
+			slog.Warn("Failed to get last diff", "error", err.Error())
+		} else if lastDiffPercent > threshold && time.Since(lastCalculatedAt) < p.alerts.cooldown {
+			p.alerts.seed(chatID, diff, lastDiffPercent, lastCalculatedAt)
+			kind, prevAlertPercent = p.alerts.check(chatID, diff, time.Now())
+		}
+	}
+	switch kind {
+	case valueAlertNone:
+		slog.Debug("Skipping duplicate alert", "match", diff.MatchName, "chat_id", chatID, "bookmaker", diff.MaxBookmaker, "last_alert", prevAlertPercent, "diff_percent", diff.DiffPercent, "min_increase", p.alerts.minIncrease)
+		return decisionDuplicate, fmt.Sprintf("alerted at %.2f%% within the %s cooldown, grew less than %.1f", prevAlertPercent, p.alerts.cooldown, p.alerts.minIncrease)
+	case valueAlertIncreased:
+		slog.Info("Value increased, sending alert", "match", diff.MatchName, "chat_id", chatID, "bookmaker", diff.MaxBookmaker, "from", prevAlertPercent, "to", diff.DiffPercent)
+	case valueAlertRepeat:
+		slog.Info("Cooldown expired, sending alert", "match", diff.MatchName, "chat_id", chatID, "bookmaker", diff.MaxBookmaker, "diff_percent", diff.DiffPercent)
+	}
+
+	thresholdInt := int(math.Round(threshold))
+	queuedAt := time.Now()
+	var err error
+	if kind == valueAlertIncreased {
+		err = c.notifier.SendValueIncreasedAlert(ctx, chatID, diff, thresholdInt, prevAlertPercent)
+	} else {
+		err = c.notifier.SendDiffAlert(ctx, chatID, diff, thresholdInt)
+	}
+	if err != nil {
+		slog.Error("Failed to queue value alert", "match", diff.MatchName, "chat_id", chatID, "threshold", threshold, "error", err.Error())
+		return decisionQueueFailed, err.Error()
+	}
+	p.alerts.alerted(chatID, diff, queuedAt)
+	slog.Info("Value alert queued",
+		"match", diff.MatchName,
+		"chat_id", chatID,
+		"diff_percent", diff.DiffPercent,
+		"threshold", threshold,
+		"calculated_at", diff.CalculatedAt.UTC().Format(time.RFC3339),
+		"queued_at", queuedAt.UTC().Format(time.RFC3339),
+		"delay_since_calculation_sec", queuedAt.Sub(diff.CalculatedAt).Seconds(),
+		"queue_length", c.notifier.QueueLen())
+	return decisionAlertQueued, kind
+}
+
+// notifyValuesClosed queues "closed" notifications for alerted values that disappeared to the chats
+// that receive them and returns the number queued.
+func (c *ValueCalculator) notifyValuesClosed(ctx context.Context, closed []valueClosed) int {
+	if len(closed) == 0 {
+		return 0
+	}
+	wanted := map[int64]bool{}
+	for _, rc := range c.alertRecipients(storage.AlertTypeValueClosed) {
+		wanted[rc.chatID] = true
+	}
+	queued := 0
+	for i := range closed {
+		if !wanted[closed[i].chatID] {
+			continue
+		}
+		if err := c.notifier.SendValueClosedAlert(ctx, &closed[i]); err != nil {
+			slog.Error("Failed to queue value closed alert", "match", closed[i].alerted.MatchName, "chat_id", closed[i].chatID, "error", err.Error())
+			continue
+		}
+		queued++
+	}
+	return queued
 }
 
 // processLineMovementsAsync tracks odds drops (прогрузы) in the same bookmaker, stores snapshots,
@@ -440,10 +664,10 @@ func (c *ValueCalculator) processLineMovementsAsync(ctx context.Context) {
 	if c.httpClient == nil || c.oddsSnapshotStorage == nil {
 		return
 	}
-	threshold := 0.0
-	if c.cfg != nil && c.cfg.LineMovementAlertThreshold > 0 {
-		threshold = c.cfg.LineMovementAlertThreshold
-	}
+	threshold := lineMovementThresholdFromConfig(c.cfg)
+
+	ctx, span := tracing.Start(ctx, "line_movement.cycle")
+	defer span.End()
 
 	// Clean snapshots for matches that already started so DB doesn't grow
 	if err := c.oddsSnapshotStorage.CleanSnapshotsForStartedMatches(ctx); err != nil {
@@ -462,19 +686,18 @@ func (c *ValueCalculator) processLineMovementsAsync(ctx context.Context) {
 	lmIterationStartedAt := time.Now()
 	slog.Info("Line movement iteration started", "started_at", lmIterationStartedAt.UTC().Format(time.RFC3339), "matches_count", len(matches))
 
-	movements, err := computeAndStoreLineMovements(ctx, matches, c.oddsSnapshotStorage, threshold)
+	keyframe := c.oddsHistory.keyframeDue(time.Now())
+	movements, err := computeAndStoreLineMovements(ctx, matches, c.oddsSnapshotStorage, threshold, keyframe)
 	if err != nil {
 		slog.Error("computeAndStoreLineMovements failed", "error", err)
 		return
 	}
+	c.stream.publishLineMovements(movements)
 
 	now := time.Now()
 	alertCount := 0
-	// Only send line movement alerts to Telegram if enabled in config and not disabled by user
-	c.asyncMu.RLock()
-	lineMovementAlertsOn := c.alertsLineMovementEnabled
-	c.asyncMu.RUnlock()
-	sendLineMovementToTelegram := c.cfg != nil && c.cfg.LineMovementTelegramAlerts && lineMovementAlertsOn
+	// Line movement alerts go to telegram_chat_id if enabled in config and not disabled by user, and to subscriptions
+	recipients := c.alertRecipients(storage.AlertTypeLineMovement)
 	// Note: No delay needed here - messages are queued asynchronously and rate-limited in the background worker
 	const maxOddForLineMovementAlert = 5.0 // don't send line movement alerts when current odd > 5 (high odds = noisy)
 	for i := range movements {
@@ -484,18 +707,26 @@ func (c *ValueCalculator) processLineMovementsAsync(ctx context.Context) {
 		}
 		// Reset extremes first so we don't re-detect after restart and send a late duplicate (e.g. 105 min later).
 		_ = c.oddsSnapshotStorage.ResetExtremesAfterAlert(ctx, lm.MatchGroupKey, lm.BetKey, lm.Bookmaker)
-		if sendLineMovementToTelegram && c.notifier != nil {
-			history, _ := c.oddsSnapshotStorage.GetOddsHistory(ctx, lm.MatchGroupKey, lm.BetKey, lm.Bookmaker, 30)
+		var history []storage.OddsHistoryPoint
+		for _, rc := range recipients {
+			if !rc.acceptsLineMovement(lm) {
+				continue
+			}
+			if history == nil {
+				history, _ = c.oddsSnapshotStorage.GetOddsHistory(ctx, lm.MatchGroupKey, lm.BetKey, lm.Bookmaker, 30)
+			}
 			queuedAt := time.Now()
-			if err := c.notifier.SendLineMovementAlert(ctx, lm, threshold, now, history); err != nil {
-				slog.Error("Failed to queue line movement alert", "match", lm.MatchName, "error", err)
+			if err := c.notifier.SendLineMovementAlert(ctx, rc.chatID, lm, threshold, now, history); err != nil {
+				slog.Error("Failed to queue line movement alert", "match", lm.MatchName, "chat_id", rc.chatID, "error", err)
 			} else {
 				alertCount++
 				delaySinceDetect := queuedAt.Sub(lm.RecordedAt)
 				slog.Info("Line movement alert queued",
 					"match", lm.MatchName,
+					"chat_id", rc.chatID,
 					"bookmaker", lm.Bookmaker,
 					"change_percent", lm.ChangePercent,
+					"prob_shift_pp", lm.ProbShiftPP,
 					"detected_at", lm.RecordedAt.UTC().Format(time.RFC3339),
 					"queued_at", queuedAt.UTC().Format(time.RFC3339),
 					"delay_since_detection_sec", delaySinceDetect.Seconds(),
@@ -503,10 +734,47 @@ func (c *ValueCalculator) processLineMovementsAsync(ctx context.Context) {
 			}
 		}
 	}
+	if recipients := c.alertRecipients(storage.AlertTypeSteam); len(recipients) > 0 {
+		alertCount += c.alertSteamMoves(ctx, now, recipients)
+	}
 	lmDuration := time.Since(lmIterationStartedAt)
 	slog.Info("Line movement iteration complete", "movements_detected", len(movements), "alerts_queued", alertCount, "duration_sec", lmDuration.Seconds())
 }
 
+// alertSteamMoves queues Telegram alerts for new steam moves to recipients (each match, bet and direction
+// at most once per steamAlertCooldown in a chat) and returns the number queued.
+func (c *ValueCalculator) alertSteamMoves(ctx context.Context, now time.Time, recipients []alertRecipient) int {
+	moves, err := c.detectSteamMoves(ctx, now, steamParamsFromConfig(c.cfg))
+	if err != nil {
+		slog.Error("Steam detection failed", "error", err)
+		return 0
+	}
+	for key, at := range c.steamAlerted {
+		if now.Sub(at) > steamAlertCooldown {
+			delete(c.steamAlerted, key)
+		}
+	}
+	queued := 0
+	for i := range moves {
+		m := &moves[i]
+		for _, rc := range recipients {
+			key := fmt.Sprintf("%d|%s", rc.chatID, steamAlertKey(*m))
+			if _, ok := c.steamAlerted[key]; ok || !rc.acceptsSteam(m) {
+				continue
+			}
+			if err := c.notifier.SendSteamAlert(ctx, rc.chatID, m); err != nil {
+				slog.Error("Failed to queue steam alert", "match", m.MatchName, "chat_id", rc.chatID, "error", err)
+				continue
+			}
+			c.steamAlerted[key] = now
+			queued++
+			slog.Info("Steam alert queued", "match", m.MatchName, "chat_id", rc.chatID, "bet_key", m.BetKey, "direction", m.Direction,
+				"bookmakers", m.Bookmakers, "avg_prob_shift_pp", m.AvgProbShiftPP)
+		}
+	}
+	return queued
+}
+
 // StopAsync stops the asynchronous processing.
 // shutdown: if true, also stops the Telegram notifier (use on app exit);
 // if false, only stops the ticker so /start can resume alerts.
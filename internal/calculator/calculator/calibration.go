@@ -0,0 +1,116 @@
+package calculator
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// calibrationBins is the number of fair probability buckets in GET /bets/calibration.
+const calibrationBins = 10
+
+// calibrationBin compares predicted and observed win rates of the bets in one probability bucket.
+type calibrationBin struct {
+	From          float64 `json:"from"`
+	To            float64 `json:"to"`
+	Count         int     `json:"count"`
+	AvgPredicted  float64 `json:"avg_predicted"` // mean fair probability at placement
+	ObservedRate  float64 `json:"observed_rate"` // share of bets won
+	AvgOdd        float64 `json:"avg_odd"`
+	ProfitPerUnit float64 `json:"profit_per_unit"` // P/L per unit staked
+}
+
+// calibrationReport checks whether fair probabilities were calibrated against settled results.
+type calibrationReport struct {
+	Samples      int              `json:"samples"`
+	AvgPredicted float64          `json:"avg_predicted"`
+	ObservedRate float64          `json:"observed_rate"`
+	BrierScore   float64          `json:"brier_score"`
+	LogLoss      float64          `json:"log_loss"`
+	Bins         []calibrationBin `json:"bins"`
+}
+
+// computeCalibration builds the calibration report of settled won/lost bets. Void and half results
+// (quarter lines) are left out: they are not a binary outcome of the predicted probability.
+func computeCalibration(bets []storage.Bet) calibrationReport {
+	report := calibrationReport{Bins: make([]calibrationBin, calibrationBins)}
+	var odds [calibrationBins]float64
+	var profit [calibrationBins]float64
+	for i := range report.Bins {
+		report.Bins[i].From = float64(i) / calibrationBins
+		report.Bins[i].To = float64(i+1) / calibrationBins
+	}
+	for _, b := range bets {
+		var won float64
+		switch b.Status {
+		case storage.BetStatusWon:
+			won = 1
+		case storage.BetStatusLost:
+		default:
+			continue
+		}
+		p := b.FairProbability
+		if p <= 0 || p >= 1 {
+			continue
+		}
+		i := int(p * calibrationBins)
+		if i >= calibrationBins {
+			i = calibrationBins - 1
+		}
+		bin := &report.Bins[i]
+		bin.Count++
+		bin.AvgPredicted += p
+		bin.ObservedRate += won
+		odds[i] += b.Odd
+		profit[i] += won*b.Odd - 1
+
+		report.Samples++
+		report.AvgPredicted += p
+		report.ObservedRate += won
+		report.BrierScore += (p - won) * (p - won)
+		report.LogLoss -= won*math.Log(p) + (1-won)*math.Log(1-p)
+	}
+	for i := range report.Bins {
+		if n := float64(report.Bins[i].Count); n > 0 {
+			report.Bins[i].AvgPredicted /= n
+			report.Bins[i].ObservedRate /= n
+			report.Bins[i].AvgOdd = odds[i] / n
+			report.Bins[i].ProfitPerUnit = profit[i] / n
+		}
+	}
+	if n := float64(report.Samples); n > 0 {
+		report.AvgPredicted /= n
+		report.ObservedRate /= n
+		report.BrierScore /= n
+		report.LogLoss /= n
+	}
+	return report
+}
+
+// handleBetsCalibration compares fair probabilities at placement with settled results.
+// GET /bets/calibration?user_id=... (default: all users)
+func (c *ValueCalculator) handleBetsCalibration(w http.ResponseWriter, r *http.Request) {
+	if c.bets.store == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "bet tracking is disabled (value_calculator.bets.enabled)"})
+		return
+	}
+	var userID int64
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid user_id"})
+			return
+		}
+		userID = n
+	}
+	bets, err := c.bets.store.GetBets(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get bets for calibration", "error", err)
+		writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get bets", "details": err.Error()})
+		return
+	}
+	writeWebAppJSON(w, http.StatusOK, computeCalibration(bets))
+}
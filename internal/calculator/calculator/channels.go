@@ -0,0 +1,330 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+)
+
+// defaultChannelSummaryAt is the daily summary time (UTC) of a channel without summary_at.
+const defaultChannelSummaryAt = "23:00"
+
+// channelSummaryTop is the number of best values listed in a daily summary.
+const channelSummaryTop = 5
+
+// channelPublisher publishes alerts to the channels and groups of value_calculator.telegram.channels: every
+// chat gets the alert types it is configured for, in its forum topic, and once a day a pinned summary of
+// what was posted there. A nil *channelPublisher publishes nothing.
+type channelPublisher struct {
+	channels []config.TelegramChannelConfig
+	subs     []storage.AlertSubscription // filters of each channel, same index
+
+	mu     sync.Mutex
+	days   []channelDay // alerts posted since the last summary, per channel
+	pinned []int        // message id of the pinned summary per channel (0 = none yet)
+}
+
+// channelDay counts the alerts posted to a channel since its last summary.
+type channelDay struct {
+	values, valuesClosed, lineMovements, steam int
+	top                                        []DiffBet // best value alerts by diff percent, one per bet and bookmaker
+}
+
+// channelSummary is a daily summary of a channel, the data of the channel_summary template.
+type channelSummary struct {
+	channel int // index in value_calculator.telegram.channels
+
+	Name                                       string
+	Date                                       string // 2006-01-02, UTC
+	Values, ValuesClosed, LineMovements, Steam int
+	Top                                        []DiffBet
+}
+
+// newChannelPublisher returns the publisher of cfg.Telegram.Channels (nil when there are none).
+// Channels without chat_id are skipped; an invalid summary_at is replaced by the default.
+func newChannelPublisher(cfg *config.ValueCalculatorConfig) *channelPublisher {
+	if cfg == nil {
+		return nil
+	}
+	p := &channelPublisher{}
+	for _, ch := range cfg.Telegram.Channels {
+		if ch.ChatID == 0 {
+			slog.Warn("Telegram channel without chat_id, skipped", "name", ch.Name)
+			continue
+		}
+		if ch.SummaryAt == "" {
+			ch.SummaryAt = defaultChannelSummaryAt
+		}
+		if _, err := nextDailyRun(time.Now(), ch.SummaryAt); err != nil {
+			slog.Warn("Telegram channel: invalid summary_at, using default", "name", ch.Name, "error", err, "default", defaultChannelSummaryAt)
+			ch.SummaryAt = defaultChannelSummaryAt
+		}
+		p.channels = append(p.channels, ch)
+		p.subs = append(p.subs, storage.AlertSubscription{
+			ChatID:     ch.ChatID,
+			Name:       ch.Name,
+			Enabled:    true,
+			AlertTypes: ch.AlertTypes,
+			Filters: storage.SubscriptionFilters{
+				Sports:     ch.Sports,
+				Leagues:    ch.Leagues,
+				Bookmakers: ch.Bookmakers,
+				MinOdds:    ch.MinOdds,
+				MaxOdds:    ch.MaxOdds,
+			},
+			MinDiffPercent:         ch.MinDiffPercent,
+			MinLineMovementPercent: ch.MinLineMovementPercent,
+		})
+	}
+	if len(p.channels) == 0 {
+		return nil
+	}
+	p.days = make([]channelDay, len(p.channels))
+	p.pinned = make([]int, len(p.channels))
+	slog.Info("Telegram channels configured", "channels", len(p.channels))
+	return p
+}
+
+// recipients returns the channels that receive alertType.
+func (p *channelPublisher) recipients(alertType string) []alertRecipient {
+	if p == nil {
+		return nil
+	}
+	var out []alertRecipient
+	for i := range p.subs {
+		if subscriptionWants(p.subs[i], alertType) {
+			out = append(out, alertRecipient{chatID: p.subs[i].ChatID, sub: &p.subs[i]})
+		}
+	}
+	return out
+}
+
+// channelOf returns the index of the channel of chatID that receives alertType (-1 = none).
+func (p *channelPublisher) channelOf(chatID int64, alertType string) int {
+	if p == nil || alertType == "" {
+		return -1
+	}
+	for i := range p.subs {
+		if p.subs[i].ChatID == chatID && subscriptionWants(p.subs[i], alertType) {
+			return i
+		}
+	}
+	return -1
+}
+
+// has reports whether chatID is a configured channel.
+func (p *channelPublisher) has(chatID int64) bool {
+	if p == nil {
+		return false
+	}
+	for i := range p.channels {
+		if p.channels[i].ChatID == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// threadOf returns the forum topic msg goes to in chatID (0 = none).
+func (p *channelPublisher) threadOf(chatID int64, msg queuedMessage) int {
+	if msg.summary != nil {
+		return p.channels[msg.summary.channel].ThreadID
+	}
+	if i := p.channelOf(chatID, alertTypeOf(msg.msgType)); i >= 0 {
+		return p.channels[i].ThreadID
+	}
+	return 0
+}
+
+// record counts an alert posted to chatID for the channel's daily summary.
+func (p *channelPublisher) record(chatID int64, msg queuedMessage) {
+	i := p.channelOf(chatID, alertTypeOf(msg.msgType))
+	if i < 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	day := &p.days[i]
+	switch msg.msgType {
+	case messageTypeDiff:
+		if msg.diff == nil {
+			return
+		}
+		day.values++
+		key := valueAlertKey(msg.diff)
+		found := false
+		for j := range day.top {
+			if valueAlertKey(&day.top[j]) == key {
+				if msg.diff.DiffPercent > day.top[j].DiffPercent {
+					day.top[j] = *msg.diff
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			day.top = append(day.top, *msg.diff)
+		}
+		sort.Slice(day.top, func(a, b int) bool { return day.top[a].DiffPercent > day.top[b].DiffPercent })
+		if len(day.top) > channelSummaryTop {
+			day.top = day.top[:channelSummaryTop]
+		}
+	case messageTypeValueClosed:
+		day.valuesClosed++
+	case messageTypeLineMovement:
+		day.lineMovements++
+	case messageTypeSteam:
+		day.steam++
+	}
+}
+
+// takeSummary returns the summary of channel i for date and starts counting the next day.
+func (p *channelPublisher) takeSummary(i int, date time.Time) channelSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	day := p.days[i]
+	p.days[i] = channelDay{}
+	return channelSummary{
+		channel:       i,
+		Name:          p.channels[i].Name,
+		Date:          date.UTC().Format("2006-01-02"),
+		Values:        day.values,
+		ValuesClosed:  day.valuesClosed,
+		LineMovements: day.lineMovements,
+		Steam:         day.steam,
+		Top:           day.top,
+	}
+}
+
+// setPinned remembers the pinned summary of channel i and returns the previous one (0 = none).
+func (p *channelPublisher) setPinned(i, messageID int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prev := p.pinned[i]
+	p.pinned[i] = messageID
+	return prev
+}
+
+// hasSummaries reports whether a channel wants the daily summary.
+func (p *channelPublisher) hasSummaries() bool {
+	if p == nil {
+		return false
+	}
+	for i := range p.channels {
+		if p.channels[i].DailySummary {
+			return true
+		}
+	}
+	return false
+}
+
+// nextSummary returns the time of the next daily summary after now and the channels it is for.
+func (p *channelPublisher) nextSummary(now time.Time) (time.Time, []int) {
+	var next time.Time
+	var due []int
+	for i := range p.channels {
+		if !p.channels[i].DailySummary {
+			continue
+		}
+		t, err := nextDailyRun(now, p.channels[i].SummaryAt)
+		switch {
+		case err != nil:
+			continue
+		case next.IsZero() || t.Before(next):
+			next, due = t, []int{i}
+		case t.Equal(next):
+			due = append(due, i)
+		}
+	}
+	return next, due
+}
+
+// alertTypeOf returns the alert type of a message type ("" for other messages).
+func alertTypeOf(t messageType) string {
+	switch t {
+	case messageTypeDiff:
+		return storage.AlertTypeValue
+	case messageTypeValueClosed:
+		return storage.AlertTypeValueClosed
+	case messageTypeLineMovement:
+		return storage.AlertTypeLineMovement
+	case messageTypeSteam:
+		return storage.AlertTypeSteam
+	case messageTypeDigest:
+		return storage.AlertTypeDigest
+	}
+	return ""
+}
+
+// runChannelSummaries posts the daily summaries of the channels at their summary_at until ctx is done.
+func (c *ValueCalculator) runChannelSummaries(ctx context.Context) {
+	for {
+		next, due := c.channels.nextSummary(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		// Only the replica that sent the alerts has counted them
+		if !c.isLeader() {
+			continue
+		}
+		for _, i := range due {
+			summary := c.channels.takeSummary(i, next)
+			if err := c.notifier.SendChannelSummary(ctx, &summary); err != nil {
+				slog.Error("Failed to queue channel summary", "channel", summary.Name, "error", err)
+			}
+		}
+	}
+}
+
+// SendChannelSummary queues the daily summary of a channel (non-blocking); it is pinned once sent.
+func (n *TelegramNotifier) SendChannelSummary(ctx context.Context, summary *channelSummary) error {
+	if n == nil || n.bot == nil || n.channels == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	msg := queuedMessage{msgType: messageTypeChannelSummary, chatID: n.channels.channels[summary.channel].ChatID, summary: summary}
+	select {
+	case <-n.ctx.Done():
+		return fmt.Errorf("notifier stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case n.queue <- msg:
+		return nil
+	default:
+		slog.Warn("Telegram message queue is full, dropping message", "channel", summary.Name)
+		return fmt.Errorf("message queue is full")
+	}
+}
+
+func (n *TelegramNotifier) formatChannelSummary(lang string, summary *channelSummary) string {
+	return n.templates.Render(lang, tgformat.ChannelSummary, summary)
+}
+
+// pinChannelSummary pins a posted summary without a notification and unpins the previous one.
+func (n *TelegramNotifier) pinChannelSummary(summary *channelSummary, messageID int) {
+	chatID := n.channels.channels[summary.channel].ChatID
+	if _, err := n.bot.Request(tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: messageID, DisableNotification: true}); err != nil {
+		slog.Warn("Failed to pin channel summary", "channel", summary.Name, "chat_id", chatID, "error", err)
+		return
+	}
+	if prev := n.channels.setPinned(summary.channel, messageID); prev != 0 {
+		if _, err := n.bot.Request(tgbotapi.UnpinChatMessageConfig{ChatID: chatID, MessageID: prev}); err != nil {
+			slog.Warn("Failed to unpin previous channel summary", "channel", summary.Name, "chat_id", chatID, "error", err)
+		}
+	}
+}
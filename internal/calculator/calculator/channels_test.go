@@ -0,0 +1,116 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+)
+
+func TestChannelPublisher(t *testing.T) {
+	cfg := &config.ValueCalculatorConfig{Telegram: config.CalculatorTelegramConfig{Channels: []config.TelegramChannelConfig{
+		{Name: "values", ChatID: -100, ThreadID: 11, AlertTypes: []string{storage.AlertTypeValue, storage.AlertTypeValueClosed}, MinDiffPercent: 8, Sports: []string{"football"}, DailySummary: true},
+		{Name: "moves", ChatID: -100, ThreadID: 12, AlertTypes: []string{storage.AlertTypeLineMovement}, DailySummary: true, SummaryAt: "25:00"},
+		{Name: "no chat", AlertTypes: []string{storage.AlertTypeValue}},
+		{Name: "steam", ChatID: -200, AlertTypes: []string{storage.AlertTypeSteam}},
+	}}}
+	p := newChannelPublisher(cfg)
+	if p == nil || len(p.channels) != 3 || p.channels[0].SummaryAt != defaultChannelSummaryAt || p.channels[1].SummaryAt != defaultChannelSummaryAt {
+		t.Fatalf("unexpected channels: %+v", p)
+	}
+	if newChannelPublisher(&config.ValueCalculatorConfig{}) != nil {
+		t.Error("publisher without channels")
+	}
+
+	c := &ValueCalculator{
+		notifier:           &TelegramNotifier{chatID: 1},
+		subscriptions:      newSubscriptionList(),
+		alertsValueEnabled: true,
+		channels:           p,
+	}
+	rcs := c.alertRecipients(storage.AlertTypeValue)
+	// Channels first, so the private chats of their members are deduplicated
+	if len(rcs) != 2 || rcs[0].chatID != -100 || rcs[1].chatID != 1 {
+		t.Fatalf("value recipients: %+v", rcs)
+	}
+	if rc := rcs[0]; rc.valueThreshold(5) != 8 || rc.acceptsDiff(&DiffBet{Sport: "tennis"}) || !rc.acceptsDiff(&DiffBet{Sport: "football"}) {
+		t.Errorf("channel threshold or filter not applied: %+v", rc.sub)
+	}
+	if rcs := c.alertRecipients(storage.AlertTypeSteam); len(rcs) != 1 || rcs[0].chatID != -200 {
+		t.Errorf("steam recipients: %+v", rcs)
+	}
+
+	// Each alert type goes to its topic
+	for _, tc := range []struct {
+		msgType messageType
+		want    int
+	}{{messageTypeDiff, 11}, {messageTypeValueClosed, 11}, {messageTypeLineMovement, 12}, {messageTypeSteam, 0}, {messageTypeTest, 0}} {
+		if got := p.threadOf(-100, queuedMessage{msgType: tc.msgType}); got != tc.want {
+			t.Errorf("thread of %v: got %d, want %d", tc.msgType, got, tc.want)
+		}
+	}
+	if got := p.threadOf(-100, queuedMessage{msgType: messageTypeChannelSummary, summary: &channelSummary{channel: 1}}); got != 12 {
+		t.Errorf("summary thread: got %d, want 12", got)
+	}
+
+	// The summary counts the alerts posted to the channel and keeps the best value of each bet
+	for _, d := range []DiffBet{
+		{MatchGroupKey: "a", BetKey: "home", MaxBookmaker: "fonbet", DiffPercent: 9},
+		{MatchGroupKey: "a", BetKey: "home", MaxBookmaker: "fonbet", DiffPercent: 12},
+		{MatchGroupKey: "b", BetKey: "away", MaxBookmaker: "pinnacle", DiffPercent: 10},
+	} {
+		p.record(-100, queuedMessage{msgType: messageTypeDiff, diff: &d})
+	}
+	p.record(-100, queuedMessage{msgType: messageTypeLineMovement})
+	p.record(1, queuedMessage{msgType: messageTypeLineMovement})
+	p.record(-100, queuedMessage{msgType: messageTypeSteam})
+
+	date := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	s := p.takeSummary(0, date)
+	if s.Name != "values" || s.Date != "2026-03-01" || s.Values != 3 || s.LineMovements != 0 || len(s.Top) != 2 || s.Top[0].DiffPercent != 12 || s.Top[1].BetKey != "away" {
+		t.Errorf("values summary: %+v", s)
+	}
+	if s := p.takeSummary(1, date); s.LineMovements != 1 || s.Steam != 0 {
+		t.Errorf("moves summary: %+v", s)
+	}
+	if s := p.takeSummary(0, date); s.Values != 0 || len(s.Top) != 0 {
+		t.Errorf("summary not reset: %+v", s)
+	}
+
+	next, due := p.nextSummary(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	if !next.Equal(date) || len(due) != 2 {
+		t.Errorf("next summary %v for %v", next, due)
+	}
+	if prev := p.setPinned(0, 5); prev != 0 {
+		t.Errorf("first pin replaced %d", prev)
+	}
+	if prev := p.setPinned(0, 6); prev != 5 {
+		t.Errorf("second pin replaced %d, want 5", prev)
+	}
+}
+
+func TestChannelSummaryTemplate(t *testing.T) {
+	s := channelSummary{Name: "Values <EU>", Date: "2026-03-01", Values: 3, ValuesClosed: 1, LineMovements: 2, Top: []DiffBet{
+		{MatchName: "A vs B", EventType: "main_match", OutcomeType: "home_win", DiffPercent: 12.345, MaxBookmaker: "Fonbet", MaxBookmakerURL: "https://fon.bet/e/1", MaxOdd: 2.5},
+	}}
+	got := tgformat.Builtin().Render(tgformat.English, tgformat.ChannelSummary, &s)
+	for _, want := range []string{
+		"<b>Daily summary: Values &lt;EU&gt;, 2026-03-01</b>",
+		"Value alerts: <b>3</b> (closed: 1)",
+		"Line movements: <b>2</b>",
+		`• A vs B: Main Match | Home Win, <b>12.35%</b> at <a href="https://fon.bet/e/1">Fonbet</a> 2.50`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary %q does not contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Steam") {
+		t.Errorf("summary without steam moves: %q", got)
+	}
+	if got := tgformat.Builtin().Render(tgformat.Russian, tgformat.ChannelSummary, &s); !strings.Contains(got, "2026-03-01") {
+		t.Errorf("ru summary: %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/oddschart"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+const (
+	defaultChartPoints = 200
+	maxChartPoints     = 1000
+	maxChartBookmakers = 8
+)
+
+// chartSeries converts the odds history of a bookmaker to a chart line.
+func chartSeries(bookmaker string, history []storage.OddsHistoryPoint) oddschart.Series {
+	s := oddschart.Series{Name: bookmaker, Points: make([]oddschart.Point, 0, len(history))}
+	for _, p := range history {
+		s.Points = append(s.Points, oddschart.Point{At: p.RecordedAt, Odd: p.Odd})
+	}
+	return s
+}
+
+// handleLineMovementChart returns the odds of a bet over time as a PNG chart, one line per bookmaker, from the
+// odds history in Postgres. GET /line-movements/chart?match_group_key=...&bet_key=...&bookmaker=fonbet,pinnacle&limit=200
+// (limit = last points per bookmaker).
+func (c *ValueCalculator) handleLineMovementChart(w http.ResponseWriter, r *http.Request) {
+	writeError := func(status int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	}
+	if c.oddsSnapshotStorage == nil {
+		writeError(http.StatusServiceUnavailable, "line movement storage is not configured (enable line_movement_enabled)")
+		return
+	}
+	q := r.URL.Query()
+	groupKey, betKey := q.Get("match_group_key"), q.Get("bet_key")
+	var bookmakers []string
+	for _, bk := range strings.Split(q.Get("bookmaker"), ",") {
+		if bk = strings.TrimSpace(bk); bk != "" {
+			bookmakers = append(bookmakers, bk)
+		}
+	}
+	if groupKey == "" || betKey == "" || len(bookmakers) == 0 {
+		writeError(http.StatusBadRequest, "match_group_key, bet_key and bookmaker are required")
+		return
+	}
+	if len(bookmakers) > maxChartBookmakers {
+		writeError(http.StatusBadRequest, "at most 8 bookmakers")
+		return
+	}
+	limit := defaultChartPoints
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+		limit = min(n, maxChartPoints)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	series := make([]oddschart.Series, 0, len(bookmakers))
+	for _, bk := range bookmakers {
+		history, err := c.oddsSnapshotStorage.GetOddsHistory(ctx, groupKey, betKey, bk, limit)
+		if err != nil {
+			slog.Error("Failed to get odds history for chart", "match_group_key", groupKey, "bet_key", betKey, "bookmaker", bk, "error", err)
+			writeError(http.StatusInternalServerError, "failed to get odds history")
+			return
+		}
+		series = append(series, chartSeries(bk, history))
+	}
+	png, err := oddschart.Render(betKey, series)
+	if errors.Is(err, oddschart.ErrNoData) {
+		writeError(http.StatusNotFound, "no odds history for the bet")
+		return
+	}
+	if err != nil {
+		writeError(http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(png)
+}
+
+// sendLineMovementChart replies to a sent line movement alert with the chart of its odds history.
+func (n *TelegramNotifier) sendLineMovementChart(chatID int64, msg queuedMessage, replyTo int) {
+	lm := msg.lineMovement
+	if lm == nil || len(msg.history) < 2 {
+		return
+	}
+	png, err := oddschart.Render(lm.BetKey, []oddschart.Series{chartSeries(lm.Bookmaker, msg.history)})
+	if err != nil {
+		slog.Warn("Failed to render line movement chart", "match", lm.MatchName, "error", err)
+		return
+	}
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "chart.png", Bytes: png})
+	photo.ReplyToMessageID = replyTo
+	photo.DisableNotification = true
+	if _, err := n.sender.Send(n.ctx, photo); err != nil {
+		slog.Warn("Failed to send line movement chart", "match", lm.MatchName, "chat_id", chatID, "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package calculator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// chartHistoryStorage serves the odds history of a few bookmakers; other methods are not used.
+type chartHistoryStorage struct {
+	storage.OddsSnapshotStorage
+	history map[string][]storage.OddsHistoryPoint // bookmaker -> points
+}
+
+func (s chartHistoryStorage) GetOddsHistory(_ context.Context, _, _, bookmaker string, limit int) ([]storage.OddsHistoryPoint, error) {
+	points := s.history[bookmaker]
+	if len(points) > limit {
+		points = points[len(points)-limit:]
+	}
+	return points, nil
+}
+
+func TestHandleLineMovementChart(t *testing.T) {
+	now := time.Now()
+	c := &ValueCalculator{oddsSnapshotStorage: chartHistoryStorage{history: map[string][]storage.OddsHistoryPoint{
+		"fonbet": {{Odd: 2.1, RecordedAt: now.Add(-time.Hour)}, {Odd: 1.9, RecordedAt: now}},
+	}}}
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c.handleLineMovementChart(rec, httptest.NewRequest(http.MethodGet, "/line-movements/chart?"+query, nil))
+		return rec
+	}
+
+	rec := get("match_group_key=a&bet_key=main_match|home_win|&bookmaker=fonbet,pinnacle")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Body.Len() == 0 {
+		t.Fatalf("status %d, content type %q, %d bytes", rec.Code, rec.Header().Get("Content-Type"), rec.Body.Len())
+	}
+	if rec := get("match_group_key=a&bet_key=b&bookmaker=pinnacle"); rec.Code != http.StatusNotFound {
+		t.Errorf("no history: status %d, want 404", rec.Code)
+	}
+	if rec := get("match_group_key=a&bookmaker=fonbet"); rec.Code != http.StatusBadRequest {
+		t.Errorf("no bet_key: status %d, want 400", rec.Code)
+	}
+}
@@ -21,12 +21,19 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 	// matchGroupKey -> betKey -> bookmaker -> odd
 	type betMap map[string]map[string]float64
 	groups := map[string]betMap{}
+	urls := map[string]string{} // matchGroupKey|betKey|bookmaker -> bookmaker page of the kept odd
+	type quoteState struct {
+		seenAt time.Time
+		stale  bool
+	}
+	quotes := map[string]quoteState{} // matchGroupKey|betKey|bookmaker -> freshness of the kept odd
 
 	// Some metadata for group: choose "best" human-readable match fields (first seen is fine).
 	type groupMeta struct {
-		name      string
-		startTime time.Time
-		sport     string
+		name       string
+		startTime  time.Time
+		sport      string
+		tournament string
 	}
 	meta := map[string]groupMeta{}
 
@@ -43,6 +50,10 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 				sport:     m.Sport,
 			}
 		}
+		if gm := meta[gk]; gm.tournament == "" && strings.TrimSpace(m.Tournament) != "" {
+			gm.tournament = strings.TrimSpace(m.Tournament)
+			meta[gk] = gm
+		}
 		if _, ok := groups[gk]; !ok {
 			groups[gk] = betMap{}
 		}
@@ -87,12 +98,11 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 
 				eventType := strings.TrimSpace(ev.EventType)
 				outcomeType := strings.TrimSpace(out.OutcomeType)
-				param := strings.TrimSpace(out.Parameter)
 				if eventType == "" || outcomeType == "" {
 					continue
 				}
 
-				betKey := eventType + "|" + outcomeType + "|" + param
+				betKey := models.OutcomeBetKey(ev, out).String()
 				if _, ok := groups[gk][betKey]; !ok {
 					groups[gk][betKey] = map[string]float64{}
 				}
@@ -100,6 +110,8 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 				// Keep latest/maximum? For diffs we just keep the best (max) seen per bookmaker+bet.
 				if prev, ok := groups[gk][betKey][bk]; !ok || odd > prev {
 					groups[gk][betKey][bk] = odd
+					urls[gk+"|"+betKey+"|"+bk] = m.OutcomeURL(out)
+					quotes[gk+"|"+betKey+"|"+bk] = quoteState{seenAt: quoteSeenAt(m, ev, out), stale: m.Stale}
 				}
 			}
 		}
@@ -113,17 +125,8 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 				continue
 			}
 			
-			parts := strings.SplitN(betKey, "|", 3)
-			evType, outType, param := "", "", ""
-			if len(parts) >= 1 {
-				evType = parts[0]
-			}
-			if len(parts) >= 2 {
-				outType = parts[1]
-			}
-			if len(parts) >= 3 {
-				param = parts[2]
-			}
+			key, _ := models.ParseBetKey(betKey)
+			evType, outType, param := key.EventType, key.OutcomeType, key.Parameter
 
 			minOdd := math.MaxFloat64
 			maxOdd := -math.MaxFloat64
@@ -138,7 +141,7 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 					maxBk = bk
 				}
 			}
-			if minOdd <= 0 || maxOdd <= 0 || maxOdd <= minOdd {
+			if minOdd <= 0 || maxOdd <= 0 || maxOdd <= minOdd || models.OddsEqual(maxOdd, minOdd) {
 				continue
 			}
 
@@ -146,22 +149,27 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 			diffPct := (maxOdd/minOdd - 1.0) * 100.0
 
 			diffs = append(diffs, DiffBet{
-				MatchGroupKey: gk,
-				MatchName:     gm.name,
-				StartTime:     gm.startTime,
-				Sport:         gm.sport,
-				EventType:     evType,
-				OutcomeType:   outType,
-				Parameter:     param,
-				BetKey:        betKey,
-				Bookmakers:    len(byBook),
-				MinBookmaker:  minBk,
-				MinOdd:        minOdd,
-				MaxBookmaker:  maxBk,
-				MaxOdd:        maxOdd,
-				DiffAbs:       diffAbs,
-				DiffPercent:   diffPct,
-				CalculatedAt:  now,
+				MatchGroupKey:   gk,
+				MatchName:       gm.name,
+				StartTime:       gm.startTime,
+				Sport:           gm.sport,
+				Tournament:      gm.tournament,
+				EventType:       evType,
+				OutcomeType:     outType,
+				Parameter:       param,
+				Participant:     key.Participant,
+				BetKey:          betKey,
+				Bookmakers:      len(byBook),
+				MinBookmaker:    minBk,
+				MinOdd:          minOdd,
+				MaxBookmaker:    maxBk,
+				MaxBookmakerURL: urls[gk+"|"+betKey+"|"+maxBk],
+				MaxOdd:          maxOdd,
+				MaxSeenAt:       quotes[gk+"|"+betKey+"|"+maxBk].seenAt,
+				MaxStale:        quotes[gk+"|"+betKey+"|"+maxBk].stale,
+				DiffAbs:         diffAbs,
+				DiffPercent:     diffPct,
+				CalculatedAt:    now,
 			})
 		}
 	}
@@ -179,36 +187,38 @@ func computeTopDiffs(matches []models.Match, keepTop int) []DiffBet {
 // computeValueBets calculates value bets using weighted average of ALL bookmakers.
 // For each bet, it calculates fair probability from all bookmakers (weighted average),
 // then finds value bets where bookmaker odds are higher than fair odds.
-// maxOdds: exclude value bets with bookmaker odd above this (0 = no limit).
-func computeValueBets(matches []models.Match, bookmakerWeights map[string]float64, minValuePercent float64, maxOdds float64, keepTop int) []ValueBet {
+// referenceBookmakers: only these bookmakers form the fair line (empty = all); every bookmaker is still compared against it.
+// limits: min value, max odd and their per-market rules (first match wins), the bookmaker coverage a bet key needs
+// and the freshness window of quotes.
+// method removes each bookmaker's margin before averaging (nil or "none" = raw 1/odd).
+func computeValueBets(matches []models.Match, bookmakerWeights map[string]float64, referenceBookmakers []string, limits valueLimits, keepTop int, method FairOddsMethod) []ValueBet {
 	if keepTop <= 0 {
 		keepTop = 100
 	}
-	if minValuePercent <= 0 {
-		minValuePercent = 5.0 // Default: 5% minimum value
+	if limits.minValuePercent <= 0 {
+		limits.minValuePercent = 5.0 // Default: 5% minimum value
 	}
 
-	// Default weight is 1.0 if not specified
-	getWeight := func(bookmaker string) float64 {
-		if bookmakerWeights != nil {
-			if w, ok := bookmakerWeights[strings.ToLower(bookmaker)]; ok && w > 0 {
-				return w
-			}
-		}
-		return 1.0 // Default weight
-	}
+	weights := lowerBookmakerKeys(bookmakerWeights)
+	reference := referenceBookmakerSet(referenceBookmakers)
 
 	now := time.Now()
 
 	// matchGroupKey -> betKey -> bookmaker -> odd
 	type betMap map[string]map[string]float64
 	groups := map[string]betMap{}
+	urls := map[string]string{} // matchGroupKey|betKey|bookmaker -> bookmaker page of the kept odd
 
 	// Metadata for group
 	type groupMeta struct {
-		name      string
-		startTime time.Time
-		sport     string
+		name       string
+		startTime  time.Time
+		sport      string
+		tournament string
+
+		homeMeta   *models.TeamMeta
+		awayMeta   *models.TeamMeta
+		leagueMeta *models.LeagueMeta
 	}
 	meta := map[string]groupMeta{}
 
@@ -226,6 +236,19 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 				sport:     m.Sport,
 			}
 		}
+		if gm := meta[gk]; gm.tournament == "" && strings.TrimSpace(m.Tournament) != "" {
+			gm.tournament = strings.TrimSpace(m.Tournament)
+			meta[gk] = gm
+		}
+		// Team metadata comes from the bookmaker services (teaminfo); take the first one known
+		if gm := meta[gk]; gm.homeMeta == nil || gm.awayMeta == nil || gm.leagueMeta == nil {
+			gm.homeMeta = firstTeamMeta(gm.homeMeta, m.HomeMeta)
+			gm.awayMeta = firstTeamMeta(gm.awayMeta, m.AwayMeta)
+			if gm.leagueMeta == nil {
+				gm.leagueMeta = m.LeagueMeta
+			}
+			meta[gk] = gm
+		}
 		if _, ok := groups[gk]; !ok {
 			groups[gk] = betMap{}
 		}
@@ -250,20 +273,25 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 
 				eventType := strings.TrimSpace(ev.EventType)
 				outcomeType := strings.TrimSpace(out.OutcomeType)
-				param := strings.TrimSpace(out.Parameter)
 				if eventType == "" || outcomeType == "" {
 					continue
 				}
 
-				betKey := eventType + "|" + outcomeType + "|" + param
+				// Stale quotes (value_calculator.freshness) form neither the fair line nor value bets
+				bkLower := strings.ToLower(bk)
+				if !limits.fresh(bkLower, quoteSeenAt(m, ev, out), now) {
+					continue
+				}
+
+				betKey := models.OutcomeBetKey(ev, out).String()
 				if _, ok := groups[gk][betKey]; !ok {
 					groups[gk][betKey] = map[string]float64{}
 				}
 
 				// Keep best (max) odd per bookmaker+bet
-				bkLower := strings.ToLower(bk)
 				if prev, ok := groups[gk][betKey][bkLower]; !ok || odd > prev {
 					groups[gk][betKey][bkLower] = odd
+					urls[gk+"|"+betKey+"|"+bkLower] = m.OutcomeURL(out)
 				}
 			}
 		}
@@ -274,50 +302,30 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 	// For each match group and bet
 	for gk, bets := range groups {
 		gm := meta[gk]
+		// Margin-free probabilities per bookmaker for outcomes of complete markets
+		fair := fairProbabilitiesByBookmaker(bets, gm.sport, method)
 		for betKey, byBook := range bets {
-			// Need at least 2 bookmakers to calculate fair probability
-			if len(byBook) < 2 {
+			// Need at least 2 bookmakers to calculate fair probability (value_calculator.min_bookmakers, min_sharp_bookmakers):
+			// a single stale outlier line must not make a value bet
+			if !limits.covers(byBook) {
 				continue
 			}
 
-			parts := strings.SplitN(betKey, "|", 3)
-			evType, outType, param := "", "", ""
-			if len(parts) >= 1 {
-				evType = parts[0]
-			}
-			if len(parts) >= 2 {
-				outType = parts[1]
-			}
-			if len(parts) >= 3 {
-				param = parts[2]
-			}
+			key, _ := models.ParseBetKey(betKey)
+			evType, outType, param := key.EventType, key.OutcomeType, key.Parameter
 
-			// Calculate fair probability using weighted average of ALL bookmakers
-			// Convert odds to probabilities: prob = 1 / odd
-			var totalWeightedProb float64
-			var totalWeight float64
+			// Fair probability: weighted average of the (reference) bookmakers
+			fairProb, ok := consensusFairProbability(byBook, fair[betKey], reference, weights)
+			if !ok {
+				continue // no reference bookmaker quotes this bet
+			}
 			var allBookmakers []string
 			var allOdds []float64
-
 			for bk, odd := range byBook {
-				prob := 1.0 / odd
-				weight := getWeight(bk)
-				totalWeightedProb += prob * weight
-				totalWeight += weight
 				allBookmakers = append(allBookmakers, bk)
 				allOdds = append(allOdds, odd)
 			}
 
-			if totalWeight <= 0 {
-				continue
-			}
-
-			// Fair probability (weighted average from all bookmakers)
-			fairProb := totalWeightedProb / totalWeight
-			if fairProb <= 0 || fairProb >= 1 {
-				continue // Invalid probability
-			}
-
 			// Fair odd
 			fairOdd := 1.0 / fairProb
 
@@ -328,13 +336,9 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 				// Calculate value: (bookmaker_odd / fair_odd - 1) * 100
 				valuePercent := (odd/fairOdd - 1.0) * 100.0
 
-				// Only include if value is positive and above threshold
-				if valuePercent < minValuePercent {
-					continue
-				}
-
-				// Skip high odds: variance is higher, value is less reliable
-				if maxOdds > 0 && odd > maxOdds {
+				// Only include if value is above the threshold of its market and bookmaker (value_calculator.rules);
+				// high odds are skipped: variance is higher, value is less reliable
+				if !limits.thresholds(gm.sport, evType, bk, odd).allows(odd, fairProb, valuePercent) {
 					continue
 				}
 
@@ -348,22 +352,30 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 				}
 
 				valueBets = append(valueBets, ValueBet{
+					ID:               valueBetID(gk, betKey, bk),
 					MatchGroupKey:    gk,
 					MatchName:        gm.name,
 					StartTime:        gm.startTime,
 					Sport:            gm.sport,
+					Tournament:       gm.tournament,
 					EventType:        evType,
 					OutcomeType:      outType,
 					Parameter:        param,
+					Participant:      key.Participant,
 					BetKey:           betKey,
 					AllBookmakerOdds: allOddsMap, // Все коэффициенты от всех контор для этого исхода
 					FairOdd:          fairOdd,
 					FairProbability:  fairProb,
 					Bookmaker:        bk,
+					BookmakerURL:     urls[gk+"|"+betKey+"|"+bk],
 					BookmakerOdd:     odd,
 					ValuePercent:     valuePercent,
 					ExpectedValue:    expectedValue,
+					KellyPercent:     kellyPercent(fairProb, odd),
 					CalculatedAt:     now,
+					HomeMeta:         clientTeamMeta(gm.homeMeta),
+					AwayMeta:         clientTeamMeta(gm.awayMeta),
+					LeagueMeta:       clientLeagueMeta(gm.leagueMeta),
 				})
 			}
 		}
@@ -381,6 +393,73 @@ func computeValueBets(matches []models.Match, bookmakerWeights map[string]float6
 	return valueBets
 }
 
+// quoteSeenAt returns when the parser last saw an outcome: its updated_at, else its event's or match's.
+func quoteSeenAt(m models.Match, ev models.Event, out models.Outcome) time.Time {
+	switch {
+	case !out.UpdatedAt.IsZero():
+		return out.UpdatedAt
+	case !ev.UpdatedAt.IsZero():
+		return ev.UpdatedAt
+	}
+	return m.UpdatedAt
+}
+
+// consensusFairProbability is the fair probability of one outcome: the weighted average of the bookmakers'
+// probabilities (de-vigged from fair when the bookmaker quotes the whole market, raw 1/odd otherwise).
+// byBook is bookmaker -> odd; only reference bookmakers count when reference is set; weights are lowercased
+// bookmaker_weights (default 1.0). ok=false when no bookmaker counts or the probability is invalid.
+func consensusFairProbability(byBook, fair map[string]float64, reference map[string]bool, weights map[string]float64) (float64, bool) {
+	var totalWeightedProb, totalWeight float64
+	for bk, odd := range byBook {
+		if reference != nil && !reference[bk] {
+			continue // soft book: compared against the fair line, not part of it
+		}
+		prob := 1.0 / odd
+		if p, ok := fair[bk]; ok {
+			prob = p
+		}
+		weight := bookmakerWeight(weights, bk)
+		totalWeightedProb += prob * weight
+		totalWeight += weight
+	}
+	if totalWeight <= 0 {
+		return 0, false
+	}
+	fairProb := totalWeightedProb / totalWeight
+	return fairProb, fairProb > 0 && fairProb < 1
+}
+
+// bookmakerWeight returns the weight of a bookmaker in the consensus (default 1.0).
+func bookmakerWeight(weights map[string]float64, bookmaker string) float64 {
+	if w, ok := weights[strings.ToLower(bookmaker)]; ok && w > 0 {
+		return w
+	}
+	return 1.0
+}
+
+// lowerBookmakerKeys returns bookmaker_weights with lowercased keys (bookmaker names are compared lowercased).
+func lowerBookmakerKeys(weights map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(weights))
+	for bk, w := range weights {
+		out[strings.ToLower(strings.TrimSpace(bk))] = w
+	}
+	return out
+}
+
+// referenceBookmakerSet returns value_calculator.reference_bookmakers as a lowercased set (nil = every bookmaker is a reference).
+func referenceBookmakerSet(names []string) map[string]bool {
+	var set map[string]bool
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			if set == nil {
+				set = map[string]bool{}
+			}
+			set[name] = true
+		}
+	}
+	return set
+}
+
 // logStatisticalEventsSummary logs how many matches have statistical events:
 // total, how many where all bookmakers have stat events, and per-bookmaker counts.
 func logStatisticalEventsSummary(matches []models.Match) {
@@ -458,3 +537,10 @@ func logStatisticalEventsSummary(matches []models.Match) {
 		"matches_where_all_bookmakers", whereAllBookmakers,
 		"per_bookmaker", perBookmaker)
 }
+
+func firstTeamMeta(current, candidate *models.TeamMeta) *models.TeamMeta {
+	if current != nil {
+		return current
+	}
+	return candidate
+}
@@ -0,0 +1,314 @@
+package calculator
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// defaultMaxInconsistencyPercent is value_calculator.consistency.max_deviation_percent when unset.
+const defaultMaxInconsistencyPercent = 10.0
+
+// Consistency checks (LineInconsistency.Check).
+const (
+	consistencyDoubleChance = "double_chance" // double chance vs 1/(1/a + 1/b) of its two 1X2 outcomes
+	consistencyDrawNoBet    = "draw_no_bet"   // Asian handicap 0 vs home·(1 - 1/draw), away·(1 - 1/draw)
+)
+
+// consistencyValidator checks every bookmaker's double chance and Asian 0 lines against the same bookmaker's
+// 1X2 in GetMatchesAll. Both derived prices are exact replications (stakes on the 1X2 outcomes returning the
+// same in every result), so a large deviation is a parsing bug rather than a margin: the lines are left out
+// before they become fake value bets, and the last check is kept for /diagnostics/inconsistencies.
+type consistencyValidator struct {
+	maxDeviationPercent float64
+	drop                bool
+
+	mu        sync.RWMutex
+	found     []LineInconsistency // of the last check
+	checkedAt time.Time
+}
+
+func newConsistencyValidator(cfg *config.ValueCalculatorConfig) *consistencyValidator {
+	v := &consistencyValidator{maxDeviationPercent: defaultMaxInconsistencyPercent, drop: true}
+	if cfg == nil {
+		return v
+	}
+	if cfg.Consistency.MaxDeviationPercent > 0 {
+		v.maxDeviationPercent = cfg.Consistency.MaxDeviationPercent
+	}
+	if cfg.Consistency.Drop != nil {
+		v.drop = *cfg.Consistency.Drop
+	}
+	return v
+}
+
+// apply finds inconsistent lines in matches, records them and, when drop is on, removes the 1X2, double
+// chance and Asian 0 lines of each inconsistent bookmaker and match. A nil validator returns matches as is.
+func (v *consistencyValidator) apply(matches []models.Match, now time.Time) []models.Match {
+	if v == nil {
+		return matches
+	}
+	found := findLineInconsistencies(matches, v.maxDeviationPercent, now)
+	if v.drop && len(found) > 0 {
+		flagged := make(map[string]bool, len(found))
+		for i := range found {
+			found[i].Dropped = true
+			flagged[found[i].MatchGroupKey+"|"+found[i].Bookmaker+"|"+found[i].EventType] = true
+		}
+		matches = dropConsistencyLines(matches, flagged)
+	}
+	if len(found) > 0 {
+		byBookmaker := map[string]int{}
+		for _, f := range found {
+			byBookmaker[f.Bookmaker]++
+		}
+		slog.Warn("Inconsistent bookmaker lines", "count", len(found), "by_bookmaker", byBookmaker, "dropped", v.drop)
+	}
+
+	v.mu.Lock()
+	v.found, v.checkedAt = found, now
+	v.mu.Unlock()
+	return matches
+}
+
+// last returns the inconsistencies of the last check and when it ran.
+func (v *consistencyValidator) last() ([]LineInconsistency, time.Time) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return append([]LineInconsistency(nil), v.found...), v.checkedAt
+}
+
+// consistencyLines are the lines of one bookmaker in one event type of a match.
+type consistencyLines struct {
+	matchName string
+	startTime time.Time
+	sport     string
+
+	home, draw, away float64
+	doubleChance     map[string]float64 // 1x, 12, x2 -> odd
+	dcOutcomeType    map[string]string  // 1x, 12, x2 -> outcome type as quoted
+	ah0Home, ah0Away float64
+}
+
+// consistencyScope returns the event type whose 1X2 a double chance or Asian 0 outcome is checked against.
+// Double chance comes under its own event type (double_chance) from some parsers.
+func consistencyScope(eventType string) string {
+	if eventType == "double_chance" {
+		return string(models.StandardEventMainMatch)
+	}
+	return eventType
+}
+
+// doubleChanceKey returns 1x, 12 or x2 for a double chance outcome type (double_chance_1x, double_chance_2x, ...).
+func doubleChanceKey(outcomeType string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.ToLower(outcomeType), "double_chance_")
+	if !ok {
+		return "", false
+	}
+	switch rest {
+	case "1x", "x1":
+		return "1x", true
+	case "12", "21":
+		return "12", true
+	case "x2", "2x":
+		return "x2", true
+	}
+	return "", false
+}
+
+// isAsianZero reports whether out is a handicap 0 (draw no bet) outcome; returns its side.
+func isAsianZero(out models.Outcome) (home bool, ok bool) {
+	if out.OutcomeType != "handicap_home" && out.OutcomeType != "handicap_away" {
+		return false, false
+	}
+	line, err := parseLine(out.Parameter)
+	if err != nil || line != 0 {
+		return false, false
+	}
+	return out.OutcomeType == "handicap_home", true
+}
+
+// isConsistencyOutcome reports whether out is one of the lines checked for consistency.
+func isConsistencyOutcome(out models.Outcome) bool {
+	if _, ok := isAsianZero(out); ok {
+		return true
+	}
+	if _, ok := doubleChanceKey(out.OutcomeType); ok {
+		return true
+	}
+	switch out.OutcomeType {
+	case string(models.OutcomeTypeHomeWin), string(models.OutcomeTypeDraw), string(models.OutcomeTypeAwayWin):
+		return strings.TrimSpace(out.Parameter) == ""
+	}
+	return false
+}
+
+// findLineInconsistencies derives double chance and draw no bet prices from each bookmaker's 1X2 and returns
+// the quoted ones deviating more than maxDeviationPercent, largest deviation first. Markets without a draw
+// (esports winner) are not checked.
+func findLineInconsistencies(matches []models.Match, maxDeviationPercent float64, now time.Time) []LineInconsistency {
+	type key struct{ group, bookmaker, eventType string }
+	lines := map[key]*consistencyLines{}
+	keepMax := func(dst *float64, odd float64) {
+		if odd > *dst {
+			*dst = odd
+		}
+	}
+	for i := range matches {
+		m := matches[i]
+		gk := matchGroupKey(m)
+		if gk == "" {
+			continue
+		}
+		for _, ev := range m.Events {
+			for _, out := range ev.Outcomes {
+				if !isFinitePositiveOdd(out.Odds) || !isConsistencyOutcome(out) {
+					continue
+				}
+				k := key{gk, outcomeBookmaker(m, ev, out), consistencyScope(strings.TrimSpace(ev.EventType))}
+				l, ok := lines[k]
+				if !ok {
+					l = &consistencyLines{
+						matchName:     strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam),
+						startTime:     m.StartTime,
+						sport:         m.Sport,
+						doubleChance:  map[string]float64{},
+						dcOutcomeType: map[string]string{},
+					}
+					lines[k] = l
+				}
+				if home, ok := isAsianZero(out); ok {
+					if home {
+						keepMax(&l.ah0Home, out.Odds)
+					} else {
+						keepMax(&l.ah0Away, out.Odds)
+					}
+					continue
+				}
+				if dc, ok := doubleChanceKey(out.OutcomeType); ok {
+					if out.Odds > l.doubleChance[dc] {
+						l.doubleChance[dc], l.dcOutcomeType[dc] = out.Odds, out.OutcomeType
+					}
+					continue
+				}
+				switch out.OutcomeType {
+				case string(models.OutcomeTypeHomeWin):
+					keepMax(&l.home, out.Odds)
+				case string(models.OutcomeTypeDraw):
+					keepMax(&l.draw, out.Odds)
+				case string(models.OutcomeTypeAwayWin):
+					keepMax(&l.away, out.Odds)
+				}
+			}
+		}
+	}
+
+	var found []LineInconsistency
+	for k, l := range lines {
+		if l.home <= 1 || l.draw <= 1 || l.away <= 1 {
+			continue
+		}
+		check := func(name, outcomeType string, quoted, derived float64) {
+			if quoted <= 0 || derived <= 0 {
+				return
+			}
+			deviation := (quoted/derived - 1) * 100
+			if math.Abs(deviation) <= maxDeviationPercent {
+				return
+			}
+			found = append(found, LineInconsistency{
+				MatchGroupKey:    k.group,
+				MatchName:        l.matchName,
+				StartTime:        l.startTime,
+				Sport:            l.sport,
+				Bookmaker:        k.bookmaker,
+				EventType:        k.eventType,
+				Check:            name,
+				OutcomeType:      outcomeType,
+				QuotedOdd:        quoted,
+				DerivedOdd:       derived,
+				DeviationPercent: deviation,
+				HomeOdd:          l.home,
+				DrawOdd:          l.draw,
+				AwayOdd:          l.away,
+				DetectedAt:       now,
+			})
+		}
+		h, d, a := 1/l.home, 1/l.draw, 1/l.away
+		derivedDC := map[string]float64{"1x": 1 / (h + d), "12": 1 / (h + a), "x2": 1 / (d + a)}
+		for _, dc := range []string{"1x", "12", "x2"} {
+			check(consistencyDoubleChance, l.dcOutcomeType[dc], l.doubleChance[dc], derivedDC[dc])
+		}
+		check(consistencyDrawNoBet, "handicap_home", l.ah0Home, l.home*(1-d))
+		check(consistencyDrawNoBet, "handicap_away", l.ah0Away, l.away*(1-d))
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return math.Abs(found[i].DeviationPercent) > math.Abs(found[j].DeviationPercent)
+	})
+	return found
+}
+
+// dropConsistencyLines removes the 1X2, double chance and Asian 0 outcomes of the flagged
+// (match group key|bookmaker|event type) lines.
+func dropConsistencyLines(matches []models.Match, flagged map[string]bool) []models.Match {
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			eventType := consistencyScope(strings.TrimSpace(ev.EventType))
+			kept := ev.Outcomes[:0]
+			for _, out := range ev.Outcomes {
+				if isConsistencyOutcome(out) && flagged[gk+"|"+outcomeBookmaker(*m, *ev, out)+"|"+eventType] {
+					continue
+				}
+				kept = append(kept, out)
+			}
+			ev.Outcomes = kept
+		}
+	}
+	return matches
+}
+
+// handleInconsistencies returns the bookmaker lines contradicting their own 1X2 in fresh matches.
+// GET /diagnostics/inconsistencies[?bookmaker=fonbet][&sport=football][&check=double_chance|draw_no_bet]
+func (c *ValueCalculator) handleInconsistencies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET"})
+		return
+	}
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+	if _, err := c.httpClient.GetMatchesAll(r.Context()); err != nil {
+		slog.Error("Failed to load matches in handleInconsistencies", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+	found, checkedAt := c.httpClient.consistency.last()
+
+	q := r.URL.Query()
+	bookmaker, sport, checkName := strings.ToLower(q.Get("bookmaker")), q.Get("sport"), q.Get("check")
+	filtered := make([]LineInconsistency, 0, len(found))
+	for _, f := range found {
+		if (bookmaker != "" && f.Bookmaker != bookmaker) || (sport != "" && !strings.EqualFold(f.Sport, sport)) || (checkName != "" && f.Check != checkName) {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{
+		"checked_at":            checkedAt,
+		"max_deviation_percent": c.httpClient.consistency.maxDeviationPercent,
+		"dropped":               c.httpClient.consistency.drop,
+		"count":                 len(filtered),
+		"inconsistencies":       filtered,
+	})
+}
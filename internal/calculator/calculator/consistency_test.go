@@ -0,0 +1,80 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestFindLineInconsistencies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := now.Add(6 * time.Hour)
+	match := func(bookmaker string, events ...models.Event) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker, Events: events}
+	}
+	oneXTwo := models.Event{EventType: "main_match", Outcomes: []models.Outcome{
+		{OutcomeType: "home_win", Odds: 2.0},
+		{OutcomeType: "draw", Odds: 3.5},
+		{OutcomeType: "away_win", Odds: 4.0},
+		{OutcomeType: "handicap_home", Parameter: "0", Odds: 2.0 * (1 - 1/3.5)}, // 1.43, consistent
+		{OutcomeType: "handicap_away", Parameter: "0", Odds: 2.9},               // derived 2.86, within 10%
+	}}
+	// Double chance swapped by the parser: 1X quoted at the X2 price
+	marathon := match("Marathonbet", oneXTwo, models.Event{EventType: "double_chance", Outcomes: []models.Outcome{
+		{OutcomeType: "double_chance_1x", Odds: 1.85},
+		{OutcomeType: "double_chance_12", Odds: 1.3},
+		{OutcomeType: "double_chance_x2", Odds: 1.25},
+	}})
+	// Consistent double chance from another bookmaker
+	leon := match("Leon", oneXTwo, models.Event{EventType: "main_match", Outcomes: []models.Outcome{
+		{OutcomeType: "double_chance_1x", Odds: 1.27},
+		{OutcomeType: "double_chance_2x", Odds: 1.85},
+	}})
+
+	found := findLineInconsistencies([]models.Match{marathon, leon}, 10, now)
+	if len(found) != 2 {
+		t.Fatalf("got %d inconsistencies, want 2: %+v", len(found), found)
+	}
+	for _, f := range found {
+		if f.Bookmaker != "marathonbet" || f.Check != consistencyDoubleChance || f.EventType != "main_match" {
+			t.Errorf("unexpected inconsistency: %+v", f)
+		}
+	}
+	if f := found[0]; f.OutcomeType != "double_chance_1x" || math.Abs(f.DerivedOdd-1/(1/2.0+1/3.5)) > 1e-9 || f.DeviationPercent < 10 {
+		t.Errorf("largest deviation first: %+v", f)
+	}
+}
+
+func TestConsistencyValidatorDrops(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: now.Add(time.Hour), Sport: "football", Bookmaker: "Fonbet",
+		Events: []models.Event{{EventType: "main_match", Outcomes: []models.Outcome{
+			{OutcomeType: "home_win", Odds: 2.0},
+			{OutcomeType: "draw", Odds: 3.5},
+			{OutcomeType: "away_win", Odds: 4.0},
+			{OutcomeType: "handicap_home", Parameter: "0", Odds: 2.9}, // home and away swapped
+			{OutcomeType: "total_over", Parameter: "2.5", Odds: 1.9},
+		}}}}
+
+	v := newConsistencyValidator(&config.ValueCalculatorConfig{})
+	out := v.apply([]models.Match{m}, now)
+	if got := out[0].Events[0].Outcomes; len(got) != 1 || got[0].OutcomeType != "total_over" {
+		t.Errorf("1X2 and Asian 0 should be dropped, other lines kept: %+v", got)
+	}
+	found, checkedAt := v.last()
+	if len(found) != 1 || !found[0].Dropped || found[0].Check != consistencyDrawNoBet || !checkedAt.Equal(now) {
+		t.Errorf("unexpected last check: %+v at %v", found, checkedAt)
+	}
+
+	keep := false
+	v = newConsistencyValidator(&config.ValueCalculatorConfig{Consistency: config.ConsistencyConfig{Drop: &keep}})
+	m.Events[0].Outcomes = append(m.Events[0].Outcomes[:0:0], models.Outcome{OutcomeType: "home_win", Odds: 2.0},
+		models.Outcome{OutcomeType: "draw", Odds: 3.5}, models.Outcome{OutcomeType: "away_win", Odds: 4.0},
+		models.Outcome{OutcomeType: "handicap_home", Parameter: "0", Odds: 2.9})
+	if out := v.apply([]models.Match{m}, now); len(out[0].Events[0].Outcomes) != 4 {
+		t.Errorf("drop: false must keep the lines: %+v", out[0].Events[0].Outcomes)
+	}
+}
@@ -0,0 +1,142 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// defaultCyberInterval is the cycle of the cyber football pipeline: its matches last ~8-12 minutes,
+// so the main async_interval is too slow for them.
+const defaultCyberInterval = 10 * time.Second
+
+// valuePipeline is one value/diff alert loop over a subset of the merged matches. The main pipeline
+// takes everything but cyber football; the opt-in cyber pipeline (value_calculator.cyber) takes only
+// cyber football with its own interval and thresholds.
+type valuePipeline struct {
+	name           string
+	keep           func(m models.Match) bool
+	alertThreshold float64
+	maxOdds        float64
+	events         *eventTracker // retractions are tracked per pipeline: each cycle only sees its own diffs
+	alerts         *valueAlertTracker
+}
+
+// filter returns the matches of the pipeline.
+func (p *valuePipeline) filter(matches []models.Match) []models.Match {
+	kept := make([]models.Match, 0, len(matches))
+	for _, m := range matches {
+		if p.keep(m) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+func isCyberFootball(m models.Match) bool {
+	return matchSport(m) == string(enums.CyberFootball)
+}
+
+// alertThresholdFromConfig returns alert_threshold, falling back to the legacy alert_threshold_20/10.
+func alertThresholdFromConfig(cfg *config.ValueCalculatorConfig) float64 {
+	switch {
+	case cfg == nil:
+		return 0
+	case cfg.AlertThreshold > 0:
+		return cfg.AlertThreshold
+	case cfg.AlertThreshold20 > 0:
+		return cfg.AlertThreshold20
+	default:
+		return cfg.AlertThreshold10
+	}
+}
+
+// newMainPipeline returns the pipeline of every sport except cyber football.
+func newMainPipeline(cfg *config.ValueCalculatorConfig, events *eventTracker) *valuePipeline {
+	p := &valuePipeline{
+		name:           "main",
+		keep:           func(m models.Match) bool { return !isCyberFootball(m) },
+		alertThreshold: alertThresholdFromConfig(cfg),
+		events:         events,
+		alerts:         newValueAlertTracker(cfg),
+	}
+	if cfg != nil {
+		p.maxOdds = cfg.MaxOdds
+	}
+	return p
+}
+
+// newCyberPipeline returns the cyber football pipeline, nil when value_calculator.cyber is disabled.
+// Unset thresholds fall back to the main ones.
+func newCyberPipeline(cfg *config.ValueCalculatorConfig) *valuePipeline {
+	if cfg == nil || !cfg.Cyber.Enabled {
+		return nil
+	}
+	p := &valuePipeline{
+		name:           "cyber",
+		keep:           isCyberFootball,
+		alertThreshold: cfg.Cyber.AlertThreshold,
+		maxOdds:        cfg.Cyber.MaxOdds,
+		events:         newEventTracker(),
+		alerts:         newValueAlertTracker(cfg),
+	}
+	if p.alertThreshold <= 0 {
+		p.alertThreshold = alertThresholdFromConfig(cfg)
+	}
+	if p.maxOdds <= 0 {
+		p.maxOdds = cfg.MaxOdds
+	}
+	return p
+}
+
+// cyberInterval returns value_calculator.cyber.interval (default 10s).
+func cyberInterval(cfg *config.ValueCalculatorConfig) time.Duration {
+	if cfg == nil || cfg.Cyber.Interval == "" {
+		return defaultCyberInterval
+	}
+	d, err := time.ParseDuration(cfg.Cyber.Interval)
+	if err != nil || d <= 0 {
+		slog.Warn("Invalid value_calculator.cyber.interval, using default", "interval", cfg.Cyber.Interval, "default", defaultCyberInterval)
+		return defaultCyberInterval
+	}
+	return d
+}
+
+// runCyberProcessing runs the cyber football pipeline on its own ticker until ctx is done or async is stopped.
+func (c *ValueCalculator) runCyberProcessing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	c.runCyberIteration(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopping cyber football processing")
+			return
+		case <-ticker.C:
+			c.asyncMu.RLock()
+			stopped := c.asyncStopped
+			c.asyncMu.RUnlock()
+			if stopped {
+				return
+			}
+			c.runCyberIteration(ctx)
+		}
+	}
+}
+
+// runCyberIteration runs one cycle of the cyber football pipeline on the leader (see runAsyncIteration).
+func (c *ValueCalculator) runCyberIteration(ctx context.Context) {
+	run, restore := c.leaderCycle(&c.leader.cyberTerm)
+	if !run {
+		return
+	}
+	if restore {
+		c.restoreValueAlerts(ctx, c.cyberPipeline)
+	}
+	c.processMatchesAsync(ctx, c.cyberPipeline)
+	c.saveValueAlerts(ctx, c.cyberPipeline)
+}
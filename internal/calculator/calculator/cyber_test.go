@@ -0,0 +1,49 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestValuePipelines(t *testing.T) {
+	matches := []models.Match{
+		{HomeTeam: "Arsenal", AwayTeam: "Chelsea", Sport: "football"},
+		{HomeTeam: "Arsenal (Kray)", AwayTeam: "Chelsea (Boss)", Sport: "cyber_football"},
+		{HomeTeam: "Spirit", AwayTeam: "NAVI", Sport: "dota2"},
+	}
+	cfg := &config.ValueCalculatorConfig{AlertThreshold: 10, MaxOdds: 5}
+
+	main := newMainPipeline(cfg, newEventTracker())
+	if got := main.filter(matches); len(got) != 2 || got[0].Sport != "football" || got[1].Sport != "dota2" {
+		t.Errorf("main pipeline kept %+v, want football and dota2", got)
+	}
+	if main.alertThreshold != 10 || main.maxOdds != 5 {
+		t.Errorf("main thresholds = %v / %v, want 10 / 5", main.alertThreshold, main.maxOdds)
+	}
+
+	if newCyberPipeline(cfg) != nil {
+		t.Fatal("cyber pipeline must be opt-in")
+	}
+	cfg.Cyber = config.CyberConfig{Enabled: true, AlertThreshold: 15}
+	cyber := newCyberPipeline(cfg)
+	if got := cyber.filter(matches); len(got) != 1 || got[0].Sport != "cyber_football" {
+		t.Errorf("cyber pipeline kept %+v, want cyber football only", got)
+	}
+	if cyber.alertThreshold != 15 || cyber.maxOdds != 5 {
+		t.Errorf("cyber thresholds = %v / %v, want 15 / 5 (max_odds from main)", cyber.alertThreshold, cyber.maxOdds)
+	}
+	if cyber.events == main.events {
+		t.Error("pipelines must not share the event tracker")
+	}
+
+	if d := cyberInterval(cfg); d != defaultCyberInterval {
+		t.Errorf("default interval = %v", d)
+	}
+	cfg.Cyber.Interval = "5s"
+	if d := cyberInterval(cfg); d != 5*time.Second {
+		t.Errorf("interval = %v, want 5s", d)
+	}
+}
@@ -0,0 +1,189 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// Alert decision log defaults (value_calculator.decision_log).
+const (
+	defaultDecisionRetention = 72 * time.Hour
+	decisionCleanupInterval  = time.Hour
+	defaultDecisionLimit     = 200
+	maxDecisionLimit         = 5000
+)
+
+// decisionLog stores what every async value cycle decided for each diff and chat
+// (value_calculator.decision_log): the answer to "why didn't I get this alert".
+type decisionLog struct {
+	store          storage.AlertDecisionStorage // nil = disabled
+	retention      time.Duration
+	minDiffPercent float64
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func newDecisionLog(cfg *config.ValueCalculatorConfig) *decisionLog {
+	l := &decisionLog{retention: defaultDecisionRetention}
+	if cfg != nil {
+		if cfg.DecisionLog.Retention > 0 {
+			l.retention = cfg.DecisionLog.Retention
+		}
+		l.minDiffPercent = cfg.DecisionLog.MinDiffPercent
+	}
+	return l
+}
+
+// SetAlertDecisionStorage enables the alert decision log (value_calculator.decision_log.enabled).
+func (c *ValueCalculator) SetAlertDecisionStorage(s storage.AlertDecisionStorage) {
+	c.decisions.store = s
+}
+
+// cycleDecisions collects the decisions of one value cycle; nil when the log is disabled.
+type cycleDecisions struct {
+	pipeline       string
+	at             time.Time
+	minDiffPercent float64
+	list           []storage.AlertDecision
+}
+
+// cycle starts collecting the decisions of a cycle of pipeline.
+func (l *decisionLog) cycle(pipeline string, now time.Time) *cycleDecisions {
+	if l.store == nil {
+		return nil
+	}
+	return &cycleDecisions{pipeline: pipeline, at: now.UTC(), minDiffPercent: l.minDiffPercent}
+}
+
+// add records the decision for d in chatID (0 = for every chat) at the chat's threshold.
+func (cd *cycleDecisions) add(d *DiffBet, chatID int64, threshold float64, decision, reason string) {
+	if cd == nil || d.DiffPercent < cd.minDiffPercent {
+		return
+	}
+	cd.list = append(cd.list, storage.AlertDecision{
+		At:            cd.at,
+		Pipeline:      cd.pipeline,
+		ChatID:        chatID,
+		MatchGroupKey: d.MatchGroupKey,
+		MatchName:     d.MatchName,
+		BetKey:        d.BetKey,
+		Bookmaker:     d.MaxBookmaker,
+		MaxOdd:        d.MaxOdd,
+		DiffPercent:   round2(d.DiffPercent),
+		Threshold:     threshold,
+		Decision:      decision,
+		Reason:        reason,
+	})
+}
+
+// save stores the decisions of a cycle and, once an hour, deletes the ones past the retention.
+func (l *decisionLog) save(ctx context.Context, cd *cycleDecisions) {
+	if cd == nil || l.store == nil {
+		return
+	}
+	if err := l.store.StoreAlertDecisions(ctx, cd.list); err != nil {
+		slog.Error("Failed to store alert decisions", "pipeline", cd.pipeline, "count", len(cd.list), "error", err)
+	}
+
+	l.mu.Lock()
+	due := cd.at.Sub(l.lastCleanup) >= decisionCleanupInterval
+	if due {
+		l.lastCleanup = cd.at
+	}
+	l.mu.Unlock()
+	if !due {
+		return
+	}
+	deleted, err := l.store.DeleteAlertDecisionsBefore(ctx, cd.at.Add(-l.retention))
+	if err != nil {
+		slog.Warn("Failed to delete old alert decisions", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Old alert decisions deleted", "deleted", deleted, "retention", l.retention)
+	}
+}
+
+// staleReason says why the best odd of d is too old to alert ("" = fresh): served from a bookmaker-service
+// startup snapshot or not seen by the parser within value_calculator.freshness.
+func staleReason(d *DiffBet, limits valueLimits, now time.Time) string {
+	if d.MaxStale {
+		return "max odd from the startup snapshot, not refreshed yet"
+	}
+	if !limits.fresh(strings.ToLower(d.MaxBookmaker), d.MaxSeenAt, now) {
+		return fmt.Sprintf("max odd last seen %s ago", now.Sub(d.MaxSeenAt).Round(time.Second))
+	}
+	return ""
+}
+
+// handleAlertDecisions returns the alert decision log, newest first.
+// GET /diagnostics/decisions?bet_key=main_match|total_over|2.5[&match_group_key=][&match=][&chat_id=][&decision=][&since=24h][&limit=200]
+func (c *ValueCalculator) handleAlertDecisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET"})
+		return
+	}
+	if c.decisions.store == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "decision log is not enabled (value_calculator.decision_log)"})
+		return
+	}
+	f, err := parseAlertDecisionFilter(r, time.Now())
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	decisions, err := c.decisions.store.GetAlertDecisions(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to get alert decisions", "error", err)
+		writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get alert decisions"})
+		return
+	}
+	if decisions == nil {
+		decisions = []storage.AlertDecision{}
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{
+		"count":     len(decisions),
+		"decisions": decisions,
+	})
+}
+
+func parseAlertDecisionFilter(r *http.Request, now time.Time) (storage.AlertDecisionFilter, error) {
+	q := r.URL.Query()
+	f := storage.AlertDecisionFilter{
+		BetKey:        strings.TrimSpace(q.Get("bet_key")),
+		MatchGroupKey: strings.TrimSpace(q.Get("match_group_key")),
+		Match:         strings.TrimSpace(q.Get("match")),
+		Decision:      strings.TrimSpace(q.Get("decision")),
+		Limit:         defaultDecisionLimit,
+	}
+	if v := q.Get("chat_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return f, fmt.Errorf("invalid chat_id")
+		}
+		f.ChatID = id
+	}
+	if v := q.Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return f, fmt.Errorf("invalid since, use a duration like 24h")
+		}
+		f.Since = now.Add(-d)
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return f, fmt.Errorf("invalid limit")
+		}
+		f.Limit = min(n, maxDecisionLimit)
+	}
+	return f, nil
+}
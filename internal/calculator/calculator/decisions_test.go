@@ -0,0 +1,113 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestStaleReason(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limits := valueLimitsFromConfig(&config.ValueCalculatorConfig{
+		Freshness: config.FreshnessConfig{MaxAge: 5 * time.Minute, Bookmakers: map[string]time.Duration{"Marathonbet": 15 * time.Minute}},
+	})
+	tests := []struct {
+		name  string
+		d     DiffBet
+		stale bool
+	}{
+		{"fresh", DiffBet{MaxBookmaker: "fonbet", MaxSeenAt: now.Add(-time.Minute)}, false},
+		{"old", DiffBet{MaxBookmaker: "fonbet", MaxSeenAt: now.Add(-10 * time.Minute)}, true},
+		{"slow bookmaker window", DiffBet{MaxBookmaker: "Marathonbet", MaxSeenAt: now.Add(-10 * time.Minute)}, false},
+		{"no timestamp", DiffBet{MaxBookmaker: "fonbet"}, false},
+		{"snapshot", DiffBet{MaxBookmaker: "fonbet", MaxSeenAt: now, MaxStale: true}, true},
+	}
+	for _, tt := range tests {
+		if got := staleReason(&tt.d, limits, now) != ""; got != tt.stale {
+			t.Errorf("%s: stale = %v, want %v", tt.name, got, tt.stale)
+		}
+	}
+}
+
+func TestRejectsDiff(t *testing.T) {
+	d := &DiffBet{Sport: "football", Tournament: "Premier League", MinBookmaker: "leon", MaxBookmaker: "fonbet", MaxOdd: 2.1, DiffPercent: 12}
+	tests := []struct {
+		filters storage.SubscriptionFilters
+		want    string
+	}{
+		{storage.SubscriptionFilters{}, ""},
+		{storage.SubscriptionFilters{Sports: []string{"hockey"}}, "sports"},
+		{storage.SubscriptionFilters{Leagues: []string{"la liga"}}, "leagues"},
+		{storage.SubscriptionFilters{MinOdds: 2.5}, "odds"},
+		{storage.SubscriptionFilters{Bookmakers: []string{"fonbet", "pinnacle"}}, "bookmakers"},
+		{storage.SubscriptionFilters{MaxDiffPercent: 10}, "max_diff_percent"},
+	}
+	for _, tt := range tests {
+		rc := alertRecipient{chatID: 1, sub: &storage.AlertSubscription{ChatID: 1, Filters: tt.filters}}
+		if got := rc.rejectsDiff(d); got != tt.want {
+			t.Errorf("rejectsDiff(%+v) = %q, want %q", tt.filters, got, tt.want)
+		}
+	}
+}
+
+func TestDecisionLog(t *testing.T) {
+	store, err := storage.NewFileAlertDecisionStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ValueCalculator{decisions: newDecisionLog(&config.ValueCalculatorConfig{
+		DecisionLog: config.DecisionLogConfig{MinDiffPercent: 3, Retention: 48 * time.Hour},
+	})}
+	if c.decisions.cycle("main", time.Now()) != nil {
+		t.Fatal("cycle collects decisions without a storage")
+	}
+	c.SetAlertDecisionStorage(store)
+
+	over := &DiffBet{MatchGroupKey: "g1", MatchName: "Arsenal vs Chelsea", BetKey: "main_match|home_win|", MaxBookmaker: "fonbet", DiffPercent: 12.345}
+	small := &DiffBet{MatchGroupKey: "g1", BetKey: "main_match|draw|", DiffPercent: 1}
+
+	old := c.decisions.cycle("main", time.Now().Add(-72*time.Hour))
+	old.add(over, 1, 10, decisionAlertQueued, valueAlertNew)
+	c.decisions.save(context.Background(), old)
+
+	cd := c.decisions.cycle("main", time.Now())
+	cd.add(over, 1, 10, decisionDuplicate, "cooldown")
+	cd.add(over, 2, 15, decisionBelowThreshold, "")
+	cd.add(small, 1, 10, decisionBelowThreshold, "")
+	c.decisions.save(context.Background(), cd)
+
+	rec := httptest.NewRecorder()
+	c.handleAlertDecisions(rec, httptest.NewRequest(http.MethodGet, "/diagnostics/decisions?bet_key=main_match|home_win|", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Count     int                     `json:"count"`
+		Decisions []storage.AlertDecision `json:"decisions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// The 72h old cycle is past the retention; the diff below min_diff_percent isn't logged
+	if resp.Count != 2 {
+		t.Fatalf("count = %d, want 2: %+v", resp.Count, resp.Decisions)
+	}
+	if d := resp.Decisions[0]; d.ChatID != 2 || d.Decision != decisionBelowThreshold || d.Threshold != 15 {
+		t.Errorf("newest decision = %+v", d)
+	}
+	if d := resp.Decisions[1]; d.Decision != decisionDuplicate || d.Reason != "cooldown" || d.DiffPercent != 12.35 {
+		t.Errorf("second decision = %+v", d)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleAlertDecisions(rec, httptest.NewRequest(http.MethodGet, "/diagnostics/decisions?limit=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit: status = %d", rec.Code)
+	}
+}
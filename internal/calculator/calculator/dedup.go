@@ -0,0 +1,170 @@
+package calculator
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+const (
+	// defaultDedupWindow is value_calculator.telegram.dedup_window when unset.
+	defaultDedupWindow = 30 * time.Minute
+	// membershipTTL is how long a channel membership lookup (getChatMember) is reused.
+	membershipTTL = time.Hour
+)
+
+// deliveryDedup drops alerts of a private chat the user already got in a channel or group they are a member
+// of: an alert of the same bet and type within the window (e.g. the broadcast channel and a personal
+// subscription). Channels and groups always get their alerts; alertRecipients lists them first, so their
+// delivery is known when the private chats are served. Repeated alerts of one chat (value increased) are
+// left to the alert trackers. A nil *deliveryDedup drops nothing.
+type deliveryDedup struct {
+	window time.Duration
+	// isMember reports whether userID is a member of the channel or group chatID
+	isMember func(chatID, userID int64) (bool, error)
+
+	mu        sync.Mutex
+	sent      map[string]time.Time // dedupKey -> last delivery
+	members   map[[2]int64]membership
+	lastPrune time.Time
+}
+
+type membership struct {
+	member    bool
+	checkedAt time.Time
+}
+
+// newDeliveryDedup returns the deduplication of cfg.Telegram.DedupWindow (nil when turned off).
+func newDeliveryDedup(cfg *config.ValueCalculatorConfig, isMember func(chatID, userID int64) (bool, error)) *deliveryDedup {
+	window := defaultDedupWindow
+	if cfg != nil && cfg.Telegram.DedupWindow != 0 {
+		window = cfg.Telegram.DedupWindow
+	}
+	if window < 0 {
+		return nil
+	}
+	return &deliveryDedup{
+		window:   window,
+		isMember: isMember,
+		sent:     map[string]time.Time{},
+		members:  map[[2]int64]membership{},
+	}
+}
+
+// botChatMember returns isMember of deliveryDedup for bot. The bot must be an admin of a channel to see
+// its members; a failed lookup counts as not a member, so the alert is delivered.
+func botChatMember(bot *tgbotapi.BotAPI) func(chatID, userID int64) (bool, error) {
+	return func(chatID, userID int64) (bool, error) {
+		m, err := bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+			ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+		})
+		if err != nil {
+			return false, err
+		}
+		return m.IsCreator() || m.IsAdministrator() || m.Status == "member" || (m.Status == "restricted" && m.IsMember), nil
+	}
+}
+
+func dedupKey(chatID int64, alertType, betKey string) string {
+	return fmt.Sprintf("%d|%s|%s", chatID, alertType, betKey)
+}
+
+// duplicate reports whether the user of the private chat chatID got the alert through one of broadcasts
+// (channels and groups) within the window, and which chat that was.
+func (d *deliveryDedup) duplicate(chatID int64, broadcasts []int64, alertType, betKey string, now time.Time) (int64, bool) {
+	if d == nil || chatID <= 0 || alertType == "" || betKey == "" {
+		return 0, false
+	}
+	for _, b := range broadcasts {
+		if d.recent(b, alertType, betKey, now) && d.member(b, chatID, now) {
+			return b, true
+		}
+	}
+	return 0, false
+}
+
+func (d *deliveryDedup) recent(chatID int64, alertType, betKey string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	at, ok := d.sent[dedupKey(chatID, alertType, betKey)]
+	return ok && now.Sub(at) < d.window
+}
+
+// member reports whether the user of a private chat is a member of chatID, cached for membershipTTL.
+func (d *deliveryDedup) member(chatID, userID int64, now time.Time) bool {
+	key := [2]int64{chatID, userID}
+	d.mu.Lock()
+	m, ok := d.members[key]
+	d.mu.Unlock()
+	if ok && now.Sub(m.checkedAt) < membershipTTL {
+		return m.member
+	}
+	member, err := d.isMember(chatID, userID)
+	if err != nil {
+		slog.Debug("Failed to check chat membership for alert deduplication", "chat_id", chatID, "user_id", userID, "error", err)
+	}
+	d.mu.Lock()
+	d.members[key] = membership{member: member, checkedAt: now}
+	d.mu.Unlock()
+	return member
+}
+
+// record remembers an alert delivered to a channel or group; entries older than the window are dropped
+// once per window.
+func (d *deliveryDedup) record(chatID int64, alertType, betKey string, now time.Time) {
+	if d == nil || chatID >= 0 || alertType == "" || betKey == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sent[dedupKey(chatID, alertType, betKey)] = now
+	if now.Sub(d.lastPrune) < d.window {
+		return
+	}
+	d.lastPrune = now
+	for k, at := range d.sent {
+		if now.Sub(at) >= d.window {
+			delete(d.sent, k)
+		}
+	}
+	for k, m := range d.members {
+		if now.Sub(m.checkedAt) >= membershipTTL {
+			delete(d.members, k)
+		}
+	}
+}
+
+// alertBetKey identifies the bet of an alert for deduplication ("" for messages that are not deduplicated).
+func alertBetKey(msg queuedMessage) string {
+	switch {
+	case msg.diff != nil:
+		return valueAlertKey(msg.diff)
+	case msg.closed != nil:
+		return valueAlertKey(&msg.closed.alerted)
+	case msg.lineMovement != nil:
+		return msg.lineMovement.MatchGroupKey + "|" + msg.lineMovement.BetKey + "|" + msg.lineMovement.Bookmaker
+	case msg.steam != nil:
+		return msg.steam.MatchGroupKey + "|" + msg.steam.BetKey
+	}
+	return ""
+}
+
+// broadcastChats returns the chats alerts are broadcast to: the channels and groups, and telegram_chat_id
+// when it is one.
+func (n *TelegramNotifier) broadcastChats() []int64 {
+	var out []int64
+	if n.chatID < 0 {
+		out = append(out, n.chatID)
+	}
+	if n.channels != nil {
+		for _, ch := range n.channels.channels {
+			out = append(out, ch.ChatID)
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestDeliveryDedup(t *testing.T) {
+	const channel, member, other = int64(-100123), int64(42), int64(43)
+	lookups := 0
+	d := newDeliveryDedup(&config.ValueCalculatorConfig{}, func(chatID, userID int64) (bool, error) {
+		lookups++
+		return chatID == channel && userID == member, nil
+	})
+	now := time.Now()
+	broadcasts := []int64{channel}
+	value, bet := storage.AlertTypeValue, "g|main_match|home_win||fonbet"
+
+	if _, dup := d.duplicate(member, broadcasts, value, bet, now); dup {
+		t.Fatal("duplicate before the channel got the alert")
+	}
+	d.record(channel, value, bet, now)
+	if via, dup := d.duplicate(member, broadcasts, value, bet, now.Add(time.Minute)); !dup || via != channel {
+		t.Errorf("member: got (%d, %v), want the channel's delivery", via, dup)
+	}
+	if _, dup := d.duplicate(other, broadcasts, value, bet, now.Add(time.Minute)); dup {
+		t.Error("a user outside the channel is deduplicated")
+	}
+	if _, dup := d.duplicate(member, broadcasts, storage.AlertTypeLineMovement, bet, now.Add(time.Minute)); dup {
+		t.Error("another alert type is deduplicated")
+	}
+	if _, dup := d.duplicate(member, broadcasts, value, bet, now.Add(defaultDedupWindow)); dup {
+		t.Error("deduplicated after the window")
+	}
+	if lookups != 2 {
+		t.Errorf("%d membership lookups, want 2 (cached per user)", lookups)
+	}
+
+	// Private chats are never a source of duplicates, and negative windows turn deduplication off
+	d.record(member, value, bet, now)
+	if _, dup := d.duplicate(member, nil, value, bet, now); dup {
+		t.Error("private chat deduplicated against itself")
+	}
+	off := newDeliveryDedup(&config.ValueCalculatorConfig{Telegram: config.CalculatorTelegramConfig{DedupWindow: -1}}, nil)
+	if off != nil {
+		t.Error("negative dedup_window: want nil")
+	}
+	if _, dup := off.duplicate(member, broadcasts, value, bet, now); dup {
+		t.Error("nil dedup deduplicates")
+	}
+}
@@ -24,6 +24,8 @@ func (c *ValueCalculator) handleTopDiffs(w http.ResponseWriter, r *http.Request)
 
 	// Filter by match status: "live" (started), "upcoming" (not started), or empty (all)
 	statusFilter := r.URL.Query().Get("status")
+	// Filter by sport: "football", "dota2", "cs", ..., "esports" (any esports discipline) or "cyber_football" (hidden otherwise)
+	sportFilter := r.URL.Query().Get("sport")
 
 	// Fetch fresh data from parser on each request
 	var diffs []DiffBet
@@ -50,6 +52,13 @@ func (c *ValueCalculator) handleTopDiffs(w http.ResponseWriter, r *http.Request)
 	// Calculate diffs from fresh data
 	diffs = computeTopDiffs(matches, 100)
 	logStatisticalEventsSummary(matches)
+	bySport := make([]DiffBet, 0, len(diffs))
+	for _, diff := range diffs {
+		if sportMatchesFilter(diff.Sport, sportFilter) {
+			bySport = append(bySport, diff)
+		}
+	}
+	diffs = bySport
 
 	// Filter by status if specified
 	// Use UTC for comparison to handle timezones correctly (StartTime is stored in UTC)
@@ -112,6 +121,9 @@ func (c *ValueCalculator) handleStatus(w http.ResponseWriter, r *http.Request) {
 	if c.httpClient == nil {
 		status["error"] = "parser URL is not configured"
 	}
+	if c.notifier != nil {
+		status["telegram_delivery"] = c.notifier.DeliveryStats()
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(status)
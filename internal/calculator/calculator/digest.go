@@ -0,0 +1,192 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+)
+
+const (
+	defaultDigestAt = "09:00"
+	digestTop       = 5
+	digestTimeout   = time.Minute
+
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+)
+
+// digest is a daily or weekly digest, the data of the digest template.
+type digest struct {
+	Period   string // digestDaily or digestWeekly
+	From, To time.Time
+
+	ValueBets int                         // value bets first seen in [From, To)
+	Top       []storage.ValueBetLifecycle // best of them by max value percent
+	AvgCLV    float64                     // average closing line value, percent: max value odd against the fair odd at kick-off
+	CLVBets   int                         // value bets that lasted until kick-off, AvgCLV is over them
+
+	Alerts            int              // alerts sent to all chats
+	AlertsByBookmaker []bookmakerCount // alerts of a bookmaker's odds, most first
+}
+
+type bookmakerCount struct {
+	Bookmaker string
+	Count     int
+}
+
+// buildDigest summarizes the value bet lifecycles and alert_sent events of [from, to).
+func buildDigest(period string, from, to time.Time, lifecycles []storage.ValueBetLifecycle, alerts []eventlog.Event) digest {
+	d := digest{Period: period, From: from, To: to, ValueBets: len(lifecycles)}
+
+	var clvSum float64
+	for _, l := range lifecycles {
+		if l.EndReason == storage.ValueEndMatchStarted && l.LastFairOdd > 1 && l.MaxValueOdd > 1 {
+			clvSum += (l.MaxValueOdd/l.LastFairOdd - 1) * 100
+			d.CLVBets++
+		}
+	}
+	if d.CLVBets > 0 {
+		d.AvgCLV = clvSum / float64(d.CLVBets)
+	}
+	d.Top = append([]storage.ValueBetLifecycle(nil), lifecycles...)
+	sort.SliceStable(d.Top, func(i, j int) bool { return d.Top[i].MaxValuePercent > d.Top[j].MaxValuePercent })
+	if len(d.Top) > digestTop {
+		d.Top = d.Top[:digestTop]
+	}
+
+	counts := map[string]int{}
+	for _, e := range alerts {
+		if e.Type != eventlog.TypeAlertSent || e.At.Before(from) || !e.At.Before(to) {
+			continue
+		}
+		d.Alerts++
+		if e.Bookmaker != "" {
+			counts[strings.ToLower(e.Bookmaker)]++
+		}
+	}
+	for bk, n := range counts {
+		d.AlertsByBookmaker = append(d.AlertsByBookmaker, bookmakerCount{Bookmaker: bk, Count: n})
+	}
+	sort.Slice(d.AlertsByBookmaker, func(i, j int) bool {
+		a, b := d.AlertsByBookmaker[i], d.AlertsByBookmaker[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Bookmaker < b.Bookmaker
+	})
+	return d
+}
+
+// digestsEnabled reports whether value_calculator.digest schedules any digest it can build.
+func (c *ValueCalculator) digestsEnabled() bool {
+	if c.cfg == nil || (!c.cfg.Digest.Daily && !c.cfg.Digest.Weekly) {
+		return false
+	}
+	if c.history.store == nil || !c.cfg.ValueHistory.Enabled {
+		slog.Warn("Digests need value_history, not scheduled")
+		return false
+	}
+	return c.notifier != nil
+}
+
+// runDigests sends the daily and weekly digests at digest.at until ctx is done.
+func (c *ValueCalculator) runDigests(ctx context.Context) {
+	cfg := c.cfg.Digest
+	at := defaultDigestAt
+	if cfg.At != "" {
+		at = cfg.At
+	}
+	if _, err := nextDailyRun(time.Now(), at); err != nil {
+		slog.Warn("Digest: invalid at, using default", "error", err, "default", defaultDigestAt)
+		at = defaultDigestAt
+	}
+	weekday, err := parseWeekday(cfg.WeeklyDay)
+	if err != nil {
+		slog.Warn("Digest: invalid weekly_day, using monday", "error", err)
+	}
+	for {
+		next, _ := nextDailyRun(time.Now(), at)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if !c.isLeader() {
+			continue
+		}
+		if cfg.Daily {
+			c.sendDigest(ctx, digestDaily, next.AddDate(0, 0, -1), next)
+		}
+		if cfg.Weekly && next.Weekday() == weekday {
+			c.sendDigest(ctx, digestWeekly, next.AddDate(0, 0, -7), next)
+		}
+	}
+}
+
+// parseWeekday parses digest.weekly_day ("" = monday).
+func parseWeekday(s string) (time.Weekday, error) {
+	if s == "" {
+		return time.Monday, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return time.Monday, fmt.Errorf("unknown day %q", s)
+}
+
+// sendDigest builds the digest of [from, to) and queues it to every chat receiving digests.
+func (c *ValueCalculator) sendDigest(ctx context.Context, period string, from, to time.Time) {
+	recipients := c.alertRecipients(storage.AlertTypeDigest)
+	if len(recipients) == 0 {
+		return
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, digestTimeout)
+	defer cancel()
+	lifecycles, _, err := c.history.store.GetValueBetHistory(reqCtx, storage.ValueBetHistoryFilter{From: from, To: to})
+	if err != nil {
+		slog.Error("Digest: failed to get value bet history", "period", period, "error", err)
+		return
+	}
+	alerts, _ := eventlog.Query(eventlog.Filter{Since: from, Types: map[string]bool{eventlog.TypeAlertSent: true}})
+	d := buildDigest(period, from, to, lifecycles, alerts)
+
+	for _, rc := range recipients {
+		if err := c.notifier.SendDigest(ctx, rc.chatID, &d); err != nil {
+			slog.Error("Failed to queue digest", "period", period, "chat_id", rc.chatID, "error", err)
+		}
+	}
+	slog.Info("Digest queued", "period", period, "chats", len(recipients), "value_bets", d.ValueBets, "alerts", d.Alerts)
+}
+
+// SendDigest queues a digest to chatID (non-blocking).
+func (n *TelegramNotifier) SendDigest(ctx context.Context, chatID int64, d *digest) error {
+	if n == nil || n.bot == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	select {
+	case <-n.ctx.Done():
+		return fmt.Errorf("notifier stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case n.queue <- queuedMessage{msgType: messageTypeDigest, chatID: chatID, digest: d}:
+		return nil
+	default:
+		slog.Warn("Telegram message queue is full, dropping message", "digest", d.Period)
+		return fmt.Errorf("message queue is full")
+	}
+}
+
+func (n *TelegramNotifier) formatDigest(lang string, d *digest) string {
+	return n.templates.Render(lang, tgformat.Digest, d)
+}
@@ -0,0 +1,72 @@
+package calculator
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+)
+
+func TestBuildDigest(t *testing.T) {
+	to := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -1)
+	var ls []storage.ValueBetLifecycle
+	for i, v := range []float64{4, 12, 7, 3, 9, 5} {
+		ls = append(ls, storage.ValueBetLifecycle{ValueBetID: string(rune('a' + i)), MaxValuePercent: v})
+	}
+	// Closed at kick-off: 2.2 against a fair 2.0 (+10%), 1.9 against 2.0 (-5%)
+	ls[0].EndReason, ls[0].MaxValueOdd, ls[0].LastFairOdd = storage.ValueEndMatchStarted, 2.2, 2.0
+	ls[1].EndReason, ls[1].MaxValueOdd, ls[1].LastFairOdd = storage.ValueEndMatchStarted, 1.9, 2.0
+	ls[2].EndReason, ls[2].MaxValueOdd, ls[2].LastFairOdd = storage.ValueEndValueGone, 3, 2
+
+	alerts := []eventlog.Event{
+		{Type: eventlog.TypeAlertSent, At: from.Add(time.Hour), Bookmaker: "Fonbet"},
+		{Type: eventlog.TypeAlertSent, At: from.Add(2 * time.Hour), Bookmaker: "fonbet"},
+		{Type: eventlog.TypeAlertSent, At: from.Add(3 * time.Hour), Bookmaker: "pinnacle"},
+		{Type: eventlog.TypeAlertSent, At: from.Add(4 * time.Hour)}, // steam: no bookmaker
+		{Type: eventlog.TypeAlertFailed, At: from.Add(time.Hour), Bookmaker: "fonbet"},
+		{Type: eventlog.TypeAlertSent, At: to, Bookmaker: "fonbet"},
+	}
+
+	d := buildDigest(digestDaily, from, to, ls, alerts)
+	if d.ValueBets != 6 || d.CLVBets != 2 || math.Abs(d.AvgCLV-2.5) > 1e-9 {
+		t.Errorf("value bets %d, CLV %.4f over %d, want 6, 2.5 over 2", d.ValueBets, d.AvgCLV, d.CLVBets)
+	}
+	if len(d.Top) != digestTop || d.Top[0].MaxValuePercent != 12 || d.Top[4].MaxValuePercent != 4 {
+		t.Errorf("unexpected top: %+v", d.Top)
+	}
+	if d.Alerts != 4 || len(d.AlertsByBookmaker) != 2 || d.AlertsByBookmaker[0] != (bookmakerCount{"fonbet", 2}) {
+		t.Errorf("alerts %d by bookmaker %+v", d.Alerts, d.AlertsByBookmaker)
+	}
+
+	got := tgformat.Builtin().Render(tgformat.English, tgformat.Digest, &d)
+	for _, want := range []string{
+		"🗞 <b>Daily digest</b>\n2026-03-01 09:00 UTC — 2026-03-02 09:00 UTC",
+		"Value bets: <b>6</b>",
+		"Average CLV: <b>+2.50%</b> (2 until kick-off)",
+		"• fonbet: 2\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("digest %q does not contain %q", got, want)
+		}
+	}
+	d.Period = digestWeekly
+	if got := tgformat.Builtin().Render(tgformat.Russian, tgformat.Digest, &d); !strings.Contains(got, "Итоги недели") {
+		t.Errorf("ru weekly digest: %q", got)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	for s, want := range map[string]time.Weekday{"": time.Monday, "Sunday": time.Sunday, "friday": time.Friday} {
+		if got, err := parseWeekday(s); err != nil || got != want {
+			t.Errorf("parseWeekday(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := parseWeekday("mon"); err == nil {
+		t.Error("expected an error for mon")
+	}
+}
@@ -84,6 +84,8 @@ func esportsMatchToMatch(e *models.EsportsMatch) models.Match {
 		Events:     events,
 		CreatedAt:  e.CreatedAt,
 		UpdatedAt:  e.UpdatedAt,
+		HomeMeta:   e.HomeMeta,
+		AwayMeta:   e.AwayMeta,
 	}
 }
 
@@ -0,0 +1,218 @@
+package calculator
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Alert decisions for a diff above alert_threshold (value_detected events).
+const (
+	decisionAlertQueued    = "alert_queued"
+	decisionDuplicate      = "skipped_duplicate" // alerted at this bookmaker within the cooldown and the diff didn't grow by alert_min_increase
+	decisionMaxOdds        = "skipped_max_odds"
+	decisionAlertsDisabled = "alerts_disabled" // /async/stop_values
+	decisionNoNotifier     = "no_notifier"
+	decisionQueueFailed    = "queue_failed"
+	decisionStale          = "skipped_stale" // the best odd is from a startup snapshot or older than value_calculator.freshness
+
+	// Decisions of one chat, only in the alert decision log (value_calculator.decision_log)
+	decisionBelowThreshold = "below_threshold" // under the chat's threshold
+	decisionFiltered       = "filtered"        // rejected by the subscription's filters
+)
+
+// eventTracker turns the async value cycles into event log records without flooding it:
+// value_detected is recorded when the decision for a bet changes (and for every queued alert),
+// alert_retracted when an alerted diff falls below the threshold before the match starts,
+// match_started once per match.
+type eventTracker struct {
+	mu        sync.Mutex
+	decisions map[string]string  // matchGroupKey|betKey -> last recorded decision
+	alerted   map[string]DiffBet // matchGroupKey|betKey -> last alerted diff
+	upcoming  map[string]upcomingMatch
+}
+
+type upcomingMatch struct {
+	name      string
+	sport     string
+	startTime time.Time
+}
+
+func newEventTracker() *eventTracker {
+	return &eventTracker{
+		decisions: map[string]string{},
+		alerted:   map[string]DiffBet{},
+		upcoming:  map[string]upcomingMatch{},
+	}
+}
+
+func diffEventKey(d *DiffBet) string {
+	return d.MatchGroupKey + "|" + d.BetKey
+}
+
+// valueDecision records what happened to a diff above the alert threshold.
+func (t *eventTracker) valueDecision(d *DiffBet, decision string, threshold float64) {
+	key := diffEventKey(d)
+	t.mu.Lock()
+	prev := t.decisions[key]
+	t.decisions[key] = decision
+	if decision == decisionAlertQueued {
+		t.alerted[key] = *d
+	}
+	t.mu.Unlock()
+	if prev == "" {
+		metrics.ValueBetsDetected.WithLabelValues(d.Sport).Inc()
+	}
+	if prev == decision && decision != decisionAlertQueued {
+		return
+	}
+	eventlog.Record(eventlog.Event{
+		Type:      eventlog.TypeValueDetected,
+		MatchKey:  d.MatchGroupKey,
+		Match:     d.MatchName,
+		BetKey:    d.BetKey,
+		Bookmaker: d.MaxBookmaker,
+		Message:   decision,
+		Fields: map[string]interface{}{
+			"diff_percent":  round2(d.DiffPercent),
+			"threshold":     threshold,
+			"max_odd":       d.MaxOdd,
+			"min_odd":       d.MinOdd,
+			"min_bookmaker": d.MinBookmaker,
+			"bookmakers":    d.Bookmakers,
+			"start_time":    d.StartTime,
+		},
+	})
+}
+
+// finishCycle forgets bets no longer above the threshold (recording alert_retracted for alerted
+// ones) and records match_started for matches whose start time has passed.
+func (t *eventTracker) finishCycle(above map[string]bool, currentDiffs map[string]*DiffBet, matches []models.Match, now time.Time) {
+	var events []eventlog.Event
+
+	t.mu.Lock()
+	for key := range t.decisions {
+		if above[key] {
+			continue
+		}
+		delete(t.decisions, key)
+		last, ok := t.alerted[key]
+		if !ok {
+			continue
+		}
+		delete(t.alerted, key)
+		if !last.StartTime.IsZero() && !last.StartTime.After(now) {
+			continue // the match started: match_started explains why the value is gone
+		}
+		fields := map[string]interface{}{"alerted_diff_percent": round2(last.DiffPercent)}
+		reason := "match_gone"
+		if cur, ok := currentDiffs[key]; ok {
+			fields["diff_percent"] = round2(cur.DiffPercent)
+			reason = "below_threshold"
+		}
+		events = append(events, eventlog.Event{
+			Type:      eventlog.TypeAlertRetracted,
+			MatchKey:  last.MatchGroupKey,
+			Match:     last.MatchName,
+			BetKey:    last.BetKey,
+			Bookmaker: last.MaxBookmaker,
+			Message:   reason,
+			Fields:    fields,
+		})
+	}
+
+	for i := range matches {
+		m := &matches[i]
+		if m.StartTime.IsZero() || !m.StartTime.After(now) {
+			continue
+		}
+		gk := matchGroupKey(*m)
+		if gk == "" {
+			continue
+		}
+		t.upcoming[gk] = upcomingMatch{
+			name:      strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam),
+			sport:     m.Sport,
+			startTime: m.StartTime,
+		}
+	}
+	for gk, um := range t.upcoming {
+		if um.startTime.After(now) {
+			continue
+		}
+		delete(t.upcoming, gk)
+		events = append(events, eventlog.Event{
+			At:       um.startTime.UTC(),
+			Type:     eventlog.TypeMatchStarted,
+			MatchKey: gk,
+			Match:    um.name,
+			Fields:   map[string]interface{}{"sport": um.sport},
+		})
+	}
+	t.mu.Unlock()
+
+	for _, e := range events {
+		eventlog.Record(e)
+	}
+}
+
+// recordAlertSent records the result of a Telegram send (alert_sent / alert_failed).
+func recordAlertSent(msg queuedMessage, err error) {
+	e := eventlog.Event{Type: eventlog.TypeAlertSent, Fields: map[string]interface{}{}}
+	if err != nil {
+		e.Type = eventlog.TypeAlertFailed
+		e.Message = err.Error()
+	}
+	switch msg.msgType {
+	case messageTypeDiff:
+		if msg.diff == nil {
+			return
+		}
+		e.MatchKey, e.Match, e.BetKey, e.Bookmaker = msg.diff.MatchGroupKey, msg.diff.MatchName, msg.diff.BetKey, msg.diff.MaxBookmaker
+		e.Fields["kind"] = "value"
+		e.Fields["diff_percent"] = round2(msg.diff.DiffPercent)
+		if msg.prevDiffPercent > 0 {
+			e.Fields["kind"] = "value_increased"
+			e.Fields["prev_diff_percent"] = round2(msg.prevDiffPercent)
+		}
+		e.Fields["calculated_at"] = msg.diff.CalculatedAt
+	case messageTypeLineMovement:
+		if msg.lineMovement == nil {
+			return
+		}
+		lm := msg.lineMovement
+		e.MatchKey, e.Match, e.BetKey, e.Bookmaker = lm.MatchGroupKey, lm.MatchName, lm.BetKey, lm.Bookmaker
+		e.Fields["kind"] = "line_movement"
+		e.Fields["change_percent"] = round2(lm.ChangePercent)
+		e.Fields["prob_shift_pp"] = round2(lm.ProbShiftPP)
+	case messageTypeSteam:
+		if msg.steam == nil {
+			return
+		}
+		e.MatchKey, e.Match, e.BetKey = msg.steam.MatchGroupKey, msg.steam.MatchName, msg.steam.BetKey
+		e.Fields["kind"] = "steam"
+		e.Fields["direction"] = msg.steam.Direction
+		e.Fields["bookmakers"] = msg.steam.Bookmakers
+		e.Fields["avg_prob_shift_pp"] = round2(msg.steam.AvgProbShiftPP)
+	case messageTypeValueClosed:
+		if msg.closed == nil {
+			return
+		}
+		last := msg.closed.alerted
+		e.MatchKey, e.Match, e.BetKey, e.Bookmaker = last.MatchGroupKey, last.MatchName, last.BetKey, last.MaxBookmaker
+		e.Fields["kind"] = "value_closed"
+		e.Fields["alerted_diff_percent"] = round2(last.DiffPercent)
+	default:
+		return
+	}
+	eventlog.Record(e)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,88 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func eventTypes(events []eventlog.Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.Type + ":" + e.Message
+	}
+	return out
+}
+
+func TestEventTrackerValueAndRetract(t *testing.T) {
+	eventlog.Configure("calculator", config.EventLogConfig{})
+	now := time.Now()
+	tr := newEventTracker()
+	d := DiffBet{MatchGroupKey: "football|a|b", MatchName: "A vs B", BetKey: "main|total|2.5", DiffPercent: 12.345, StartTime: now.Add(2 * time.Hour)}
+	key := diffEventKey(&d)
+
+	// Same decision on consecutive cycles is recorded once; queued alerts always are.
+	tr.valueDecision(&d, decisionDuplicate, 10)
+	tr.valueDecision(&d, decisionDuplicate, 10)
+	tr.valueDecision(&d, decisionAlertQueued, 10)
+	tr.valueDecision(&d, decisionAlertQueued, 10)
+	tr.finishCycle(map[string]bool{key: true}, nil, nil, now)
+
+	// Next cycle the diff is below the threshold: the alert is retracted.
+	below := d
+	below.DiffPercent = 4
+	tr.finishCycle(map[string]bool{}, map[string]*DiffBet{key: &below}, nil, now)
+	// Retraction is recorded once.
+	tr.finishCycle(map[string]bool{}, map[string]*DiffBet{key: &below}, nil, now)
+
+	events, _ := eventlog.Query(eventlog.Filter{})
+	got := eventTypes(events)
+	want := []string{
+		"value_detected:" + decisionDuplicate,
+		"value_detected:" + decisionAlertQueued,
+		"value_detected:" + decisionAlertQueued,
+		"alert_retracted:below_threshold",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+	if v := events[0].Fields["diff_percent"]; v != 12.35 {
+		t.Errorf("diff_percent = %v, want 12.35", v)
+	}
+	if v := events[3].Fields["diff_percent"]; v != 4.0 {
+		t.Errorf("retracted diff_percent = %v, want 4", v)
+	}
+}
+
+func TestEventTrackerMatchStarted(t *testing.T) {
+	eventlog.Configure("calculator", config.EventLogConfig{})
+	now := time.Now()
+	tr := newEventTracker()
+	start := now.Add(time.Minute)
+	m := models.Match{HomeTeam: "Home", AwayTeam: "Away", Sport: "football", StartTime: start}
+	started := models.Match{HomeTeam: "Old", AwayTeam: "Match", Sport: "football", StartTime: now.Add(-time.Hour)}
+
+	// An alerted value of a match that then starts is not reported as retracted.
+	d := DiffBet{MatchGroupKey: matchGroupKey(m), MatchName: "Home vs Away", BetKey: "main|outcome|home", StartTime: start}
+	tr.valueDecision(&d, decisionAlertQueued, 10)
+
+	tr.finishCycle(map[string]bool{diffEventKey(&d): true}, nil, []models.Match{m, started}, now)
+	tr.finishCycle(nil, nil, []models.Match{m}, start.Add(time.Second))
+	tr.finishCycle(nil, nil, nil, start.Add(time.Minute))
+
+	events, _ := eventlog.Query(eventlog.Filter{Types: map[string]bool{eventlog.TypeMatchStarted: true, eventlog.TypeAlertRetracted: true}})
+	if len(events) != 1 {
+		t.Fatalf("events = %v, want one match_started", eventTypes(events))
+	}
+	if events[0].Type != eventlog.TypeMatchStarted || events[0].Match != "Home vs Away" || !events[0].At.Equal(start.UTC()) {
+		t.Errorf("match_started = %+v", events[0])
+	}
+}
@@ -0,0 +1,295 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Fair odds methods (value_calculator.fair_odds_method).
+const (
+	FairOddsNone         = "none"         // raw implied probability 1/odd, margin kept (default)
+	FairOddsEqual        = "equal"        // the margin is split equally between outcomes
+	FairOddsProportional = "proportional" // probabilities are scaled by the overround
+	FairOddsShin         = "shin"         // Shin's insider-trading model
+	FairOddsPower        = "power"        // p = (1/odd)^k
+	FairOddsLogarithmic  = "logarithmic"  // equal shift of every outcome's log-odds
+)
+
+// FairOddsMethod removes the bookmaker margin from one complete market of one bookmaker.
+// Methods differ mostly on longshots: proportional keeps the favourite-longshot bias,
+// Shin, power and logarithmic put more of the margin on the longshot.
+type FairOddsMethod interface {
+	Name() string
+	// FairProbabilities returns the fair probability of every outcome (same order as odds),
+	// or nil when the market cannot be de-vigged by this method.
+	FairProbabilities(odds []float64) []float64
+}
+
+// fairOddsMethodByName returns the method for value_calculator.fair_odds_method ("" = none).
+func fairOddsMethodByName(name string) (FairOddsMethod, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", FairOddsNone:
+		return noMarginRemoval{}, nil
+	case FairOddsEqual:
+		return equalMargin{}, nil
+	case FairOddsProportional:
+		return proportionalMargin{}, nil
+	case FairOddsShin:
+		return shinMethod{}, nil
+	case FairOddsPower:
+		return powerMethod{}, nil
+	case FairOddsLogarithmic:
+		return logarithmicMethod{}, nil
+	}
+	return nil, fmt.Errorf("unknown fair odds method %q (want one of none, equal, proportional, shin, power, logarithmic)", name)
+}
+
+// impliedProbabilities returns 1/odd per outcome and their sum, ok=false on an invalid odd.
+func impliedProbabilities(odds []float64) (probs []float64, sum float64, ok bool) {
+	probs = make([]float64, len(odds))
+	for i, odd := range odds {
+		if !isFinitePositiveOdd(odd) || odd <= 1 {
+			return nil, 0, false
+		}
+		probs[i] = 1.0 / odd
+		sum += probs[i]
+	}
+	return probs, sum, len(odds) >= 2
+}
+
+type noMarginRemoval struct{}
+
+func (noMarginRemoval) Name() string { return FairOddsNone }
+
+func (noMarginRemoval) FairProbabilities(odds []float64) []float64 {
+	probs, _, ok := impliedProbabilities(odds)
+	if !ok {
+		return nil
+	}
+	return probs
+}
+
+type equalMargin struct{}
+
+func (equalMargin) Name() string { return FairOddsEqual }
+
+func (equalMargin) FairProbabilities(odds []float64) []float64 {
+	probs, sum, ok := impliedProbabilities(odds)
+	if !ok {
+		return nil
+	}
+	share := (sum - 1) / float64(len(probs))
+	for i := range probs {
+		probs[i] -= share
+		if probs[i] <= 0 {
+			return nil // longshot priced below its share of the margin
+		}
+	}
+	return probs
+}
+
+type proportionalMargin struct{}
+
+func (proportionalMargin) Name() string { return FairOddsProportional }
+
+func (proportionalMargin) FairProbabilities(odds []float64) []float64 {
+	probs, sum, ok := impliedProbabilities(odds)
+	if !ok {
+		return nil
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+type shinMethod struct{}
+
+func (shinMethod) Name() string { return FairOddsShin }
+
+// FairProbabilities solves for the share of insider money z so that the Shin probabilities sum to 1.
+func (shinMethod) FairProbabilities(odds []float64) []float64 {
+	implied, sum, ok := impliedProbabilities(odds)
+	if !ok || sum <= 1 {
+		// Shin's model needs an overround; without one there is nothing to remove
+		return proportionalMargin{}.FairProbabilities(odds)
+	}
+	shin := func(z float64) []float64 {
+		probs := make([]float64, len(implied))
+		for i, p := range implied {
+			probs[i] = (math.Sqrt(z*z+4*(1-z)*p*p/sum) - z) / (2 * (1 - z))
+		}
+		return probs
+	}
+	z, ok := solveDecreasing(func(z float64) float64 { return sumOf(shin(z)) }, 0, 0.99)
+	if !ok {
+		return nil
+	}
+	return shin(z)
+}
+
+type powerMethod struct{}
+
+func (powerMethod) Name() string { return FairOddsPower }
+
+func (powerMethod) FairProbabilities(odds []float64) []float64 {
+	implied, _, ok := impliedProbabilities(odds)
+	if !ok {
+		return nil
+	}
+	power := func(k float64) []float64 {
+		probs := make([]float64, len(implied))
+		for i, p := range implied {
+			probs[i] = math.Pow(p, k)
+		}
+		return probs
+	}
+	k, ok := solveDecreasing(func(k float64) float64 { return sumOf(power(k)) }, 0.01, 100)
+	if !ok {
+		return nil
+	}
+	return power(k)
+}
+
+type logarithmicMethod struct{}
+
+func (logarithmicMethod) Name() string { return FairOddsLogarithmic }
+
+// FairProbabilities shifts logit(1/odd) of every outcome by the same c so that the probabilities sum to 1.
+func (logarithmicMethod) FairProbabilities(odds []float64) []float64 {
+	implied, _, ok := impliedProbabilities(odds)
+	if !ok {
+		return nil
+	}
+	shifted := func(c float64) []float64 {
+		probs := make([]float64, len(implied))
+		for i, p := range implied {
+			probs[i] = p / (p + (1-p)*math.Exp(c))
+		}
+		return probs
+	}
+	c, ok := solveDecreasing(func(c float64) float64 { return sumOf(shifted(c)) }, -20, 20)
+	if !ok {
+		return nil
+	}
+	return shifted(c)
+}
+
+func sumOf(xs []float64) float64 {
+	var s float64
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+// solveDecreasing finds x in [lo, hi] with f(x) = 1 for a decreasing f by bisection.
+func solveDecreasing(f func(float64) float64, lo, hi float64) (float64, bool) {
+	if f(lo) < 1 || f(hi) > 1 {
+		return 0, false
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if f(mid) > 1 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, true
+}
+
+// fairProbabilitiesByBookmaker de-vigs every complete market of one match group per bookmaker.
+// Returns betKey -> bookmaker -> fair probability; bookmakers that do not quote every outcome of a
+// market are left out (computeValueBets falls back to their raw 1/odd).
+func fairProbabilitiesByBookmaker(bets map[string]map[string]float64, sport string, method FairOddsMethod) map[string]map[string]float64 {
+	if method == nil || method.Name() == FairOddsNone {
+		return nil
+	}
+	betKeys := make(map[string]bool, len(bets))
+	for bk := range bets {
+		betKeys[bk] = true
+	}
+	fair := map[string]map[string]float64{}
+	for _, market := range arbitrageMarkets(betKeys, sport) {
+		if market.open {
+			devigOpenMarket(fair, bets, market.betKeys, method)
+			continue
+		}
+		for bookmaker := range bets[market.betKeys[0]] {
+			odds := make([]float64, 0, len(market.betKeys))
+			for _, betKey := range market.betKeys {
+				odd, ok := bets[betKey][bookmaker]
+				if !ok {
+					break
+				}
+				odds = append(odds, odd)
+			}
+			if len(odds) != len(market.betKeys) {
+				continue
+			}
+			probs := method.FairProbabilities(odds)
+			if probs == nil {
+				continue
+			}
+			for i, betKey := range market.betKeys {
+				if _, ok := fair[betKey]; !ok {
+					fair[betKey] = map[string]float64{}
+				}
+				if _, done := fair[betKey][bookmaker]; !done {
+					fair[betKey][bookmaker] = probs[i]
+				}
+			}
+		}
+	}
+	return fair
+}
+
+// minOpenMarketOutcomes is the fewest outcomes of an open market (correct score) a bookmaker must quote
+// to be de-vigged: a handful of scores doesn't show the bookmaker's margin.
+const minOpenMarketOutcomes = 6
+
+// devigOpenMarket de-vigs an open market (correct score) per bookmaker over the outcomes it quotes, into
+// fair. The list leaves out unlikely scores, so only a list whose implied probabilities sum to at least 1
+// has a margin to remove; normalizing it to 1 slightly overstates the listed scores, which errs against
+// value. The equal split is replaced by the proportional one: a dozen longshots can't each carry an equal
+// share of a 20-30% margin.
+func devigOpenMarket(fair, bets map[string]map[string]float64, betKeys []string, method FairOddsMethod) {
+	if method.Name() == FairOddsEqual {
+		method = proportionalMargin{}
+	}
+	bookmakers := map[string]bool{}
+	for _, betKey := range betKeys {
+		for bookmaker := range bets[betKey] {
+			bookmakers[bookmaker] = true
+		}
+	}
+	for bookmaker := range bookmakers {
+		var quoted []string
+		var odds []float64
+		var implied float64
+		for _, betKey := range betKeys {
+			if odd, ok := bets[betKey][bookmaker]; ok && odd > 1 {
+				quoted = append(quoted, betKey)
+				odds = append(odds, odd)
+				implied += 1 / odd
+			}
+		}
+		if len(odds) < minOpenMarketOutcomes || implied < 1 {
+			continue
+		}
+		probs := method.FairProbabilities(odds)
+		if probs == nil {
+			continue
+		}
+		for i, betKey := range quoted {
+			if _, ok := fair[betKey]; !ok {
+				fair[betKey] = map[string]float64{}
+			}
+			if _, done := fair[betKey][bookmaker]; !done {
+				fair[betKey][bookmaker] = probs[i]
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestFairOddsMethods(t *testing.T) {
+	// Favourite 1.25, longshot 4.0: overround 1.05
+	odds := []float64{1.25, 4.0}
+	longshot := map[string]float64{}
+	for _, name := range []string{FairOddsEqual, FairOddsProportional, FairOddsShin, FairOddsPower, FairOddsLogarithmic} {
+		m, err := fairOddsMethodByName(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		probs := m.FairProbabilities(odds)
+		if len(probs) != 2 {
+			t.Fatalf("%s: got %v", name, probs)
+		}
+		if s := probs[0] + probs[1]; math.Abs(s-1) > 1e-6 {
+			t.Errorf("%s: probabilities sum to %.8f, want 1", name, s)
+		}
+		longshot[name] = probs[1]
+	}
+
+	if want := 0.25 / 1.05; math.Abs(longshot[FairOddsProportional]-want) > 1e-9 {
+		t.Errorf("proportional longshot = %.6f, want %.6f", longshot[FairOddsProportional], want)
+	}
+	if want := 0.25 - 0.025; math.Abs(longshot[FairOddsEqual]-want) > 1e-9 {
+		t.Errorf("equal longshot = %.6f, want %.6f", longshot[FairOddsEqual], want)
+	}
+	// Shin and power put more of the margin on the longshot than proportional
+	for _, name := range []string{FairOddsShin, FairOddsPower} {
+		if longshot[name] >= longshot[FairOddsProportional] {
+			t.Errorf("%s longshot %.6f should be below proportional %.6f", name, longshot[name], longshot[FairOddsProportional])
+		}
+	}
+
+	none, _ := fairOddsMethodByName("")
+	if none.Name() != FairOddsNone {
+		t.Errorf("empty method = %q, want none", none.Name())
+	}
+	if _, err := fairOddsMethodByName("magic"); err == nil {
+		t.Error("unknown method should fail")
+	}
+	// Equal margin cannot price a longshot below its share of the margin
+	if probs := (equalMargin{}).FairProbabilities([]float64{1.2, 4.5, 80}); probs != nil {
+		t.Errorf("equal margin on extreme longshots = %v, want nil", probs)
+	}
+}
+
+func TestComputeValueBetsWithMarginRemoval(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(bookmaker string, over, under float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "total_over", Parameter: "2.5", Odds: over},
+				{OutcomeType: "total_under", Parameter: "2.5", Odds: under},
+			}}}}
+	}
+	matches := []models.Match{match("Fonbet", 2.0, 1.8), match("Pinnacle", 1.95, 1.95)}
+
+	// Raw implied probabilities keep the margin, so fair odds are too low and value is overstated:
+	// under 2.5 at pinnacle looks like +4.2%
+	bets := computeValueBets(matches, nil, nil, valueLimits{minValuePercent: 3}, 10, nil)
+	if len(bets) != 1 || bets[0].OutcomeType != "total_under" || bets[0].Bookmaker != "pinnacle" {
+		t.Fatalf("without margin removal expected under 2.5 at pinnacle, got %+v", bets)
+	}
+
+	proportional, _ := fairOddsMethodByName(FairOddsProportional)
+	if bets := computeValueBets(matches, nil, nil, valueLimits{minValuePercent: 3}, 10, proportional); len(bets) != 0 {
+		t.Errorf("with proportional margin removal expected no value bets, got %+v", bets)
+	}
+	bets = computeValueBets(matches, nil, nil, valueLimits{minValuePercent: 0.01}, 10, proportional)
+	if len(bets) != 1 || bets[0].OutcomeType != "total_under" {
+		t.Fatalf("expected only under 2.5 above 0.01%%, got %+v", bets)
+	}
+	wantProb := ((1/1.8)/(1/2.0+1/1.8) + 0.5) / 2
+	if math.Abs(bets[0].FairProbability-wantProb) > 1e-9 {
+		t.Errorf("fair probability = %.6f, want %.6f", bets[0].FairProbability, wantProb)
+	}
+}
+
+func TestFairProbabilities_CorrectScore(t *testing.T) {
+	scores := map[string]float64{"1:0": 5.5, "0:0": 7, "1:1": 5.5, "2:0": 7.5, "2:1": 7.5, "0:1": 8, "1:2": 10, "0:2": 13}
+	bets := map[string]map[string]float64{}
+	var implied float64
+	for score, odd := range scores {
+		bets["correct_score|correct_score|"+score] = map[string]float64{"fonbet": odd}
+		implied += 1 / odd
+	}
+	// A short list whose implied probabilities don't reach 1 shows no margin
+	bets["correct_score|correct_score|3:3"] = map[string]float64{"fonbet": 60, "leon": 55}
+	bets["correct_score|correct_score|0:0"]["leon"] = 8
+	implied += 1.0 / 60
+
+	for _, name := range []string{FairOddsEqual, FairOddsProportional} {
+		method, _ := fairOddsMethodByName(name)
+		fair := fairProbabilitiesByBookmaker(bets, "football", method)
+		if _, ok := fair["correct_score|correct_score|0:0"]["leon"]; ok {
+			t.Errorf("%s: leon quotes two scores and must not be de-vigged", name)
+		}
+		var sum float64
+		for betKey := range bets {
+			p, ok := fair[betKey]["fonbet"]
+			if !ok {
+				t.Fatalf("%s: %s not de-vigged for fonbet", name, betKey)
+			}
+			if p <= 0 {
+				t.Errorf("%s: %s fair probability %.6f", name, betKey, p)
+			}
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-6 {
+			t.Errorf("%s: fair probabilities sum to %.8f, want 1", name, sum)
+		}
+		// Equal falls back to proportional on the long list
+		if got, want := fair["correct_score|correct_score|3:3"]["fonbet"], (1.0/60)/implied; math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: 3:3 = %.6f, want %.6f", name, got, want)
+		}
+	}
+}
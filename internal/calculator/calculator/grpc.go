@@ -0,0 +1,221 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	calculatorv1 "github.com/Vodeneev/vodeneevbet/api/proto/calculator/v1"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// maxGRPCResponseSize fits GetMatchOdds of a match with many markets at every bookmaker.
+const maxGRPCResponseSize = 64 << 20
+
+// calculatorGRPCServer implements CalculatorService on top of the handlers of the HTTP list endpoints.
+type calculatorGRPCServer struct {
+	calculatorv1.UnimplementedCalculatorServiceServer
+	c *ValueCalculator
+}
+
+// RunGRPC starts serving CalculatorService on addr until ctx is done (value_calculator.grpc.port).
+func (c *ValueCalculator) RunGRPC(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+	srv := grpc.NewServer(grpc.MaxSendMsgSize(maxGRPCResponseSize), grpc.ChainUnaryInterceptor(apiauth.UnaryServerInterceptor()))
+	calculatorv1.RegisterCalculatorServiceServer(srv, &calculatorGRPCServer{c: c})
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+	go func() {
+		slog.Info("gRPC calculator server listening", "addr", addr)
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("gRPC calculator server error", "addr", addr, "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *calculatorGRPCServer) ListValueBets(ctx context.Context, req *calculatorv1.ListValueBetsRequest) (*calculatorv1.ListValueBetsResponse, error) {
+	lq, err := parseListValues(listQueryValues(req.GetQuery()), 5, valueBetSorts, "value_percent")
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fairOdds := s.c.fairOdds
+	if req.GetMethod() != "" {
+		if fairOdds, err = fairOddsMethodByName(req.GetMethod()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	matches, err := s.c.grpcMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page, next, total := s.c.pageValueBets(matches, lq, req.GetStatus(), fairOdds)
+	resp := &calculatorv1.ListValueBetsResponse{NextCursor: next, Total: int32(total)}
+	resp.ValueBets = make([]*calculatorv1.ValueBet, 0, len(page))
+	for i := range page {
+		resp.ValueBets = append(resp.ValueBets, toProtoValueBet(&page[i]))
+	}
+	return resp, nil
+}
+
+func (s *calculatorGRPCServer) ListLineMovements(ctx context.Context, req *calculatorv1.ListLineMovementsRequest) (*calculatorv1.ListLineMovementsResponse, error) {
+	lq, err := parseListValues(listQueryValues(req.GetQuery()), 10, lineMovementSorts, "change_percent")
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.c.oddsSnapshotStorage == nil {
+		return nil, status.Error(codes.FailedPrecondition, "line movement storage is not configured (enable line_movement_enabled)")
+	}
+	matches, err := s.c.grpcMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := getLineMovementsForTop(ctx, matches, s.c.oddsSnapshotStorage)
+	if err != nil {
+		slog.Error("getLineMovementsForTop failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to compute line movements: %v", err)
+	}
+	page, next, total := pageLineMovements(movements, lq)
+	resp := &calculatorv1.ListLineMovementsResponse{NextCursor: next, Total: int32(total)}
+	resp.LineMovements = make([]*calculatorv1.LineMovement, 0, len(page))
+	for i := range page {
+		resp.LineMovements = append(resp.LineMovements, toProtoLineMovement(&page[i]))
+	}
+	return resp, nil
+}
+
+func (s *calculatorGRPCServer) GetMatchOdds(ctx context.Context, req *calculatorv1.GetMatchOddsRequest) (*calculatorv1.GetMatchOddsResponse, error) {
+	key := strings.TrimSpace(req.GetMatchGroupKey())
+	if key == "" {
+		return nil, status.Error(codes.InvalidArgument, "match_group_key is required")
+	}
+	matches, err := s.c.grpcMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &calculatorv1.GetMatchOddsResponse{}
+	for i := range matches {
+		if matchGroupKey(matches[i]) == key {
+			resp.Matches = append(resp.Matches, health.ToProtoMatch(&matches[i]))
+		}
+	}
+	if len(resp.Matches) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no current match with group key %q", key)
+	}
+	return resp, nil
+}
+
+// grpcMatches fetches the current matches from the parser, with the errors as gRPC statuses.
+func (c *ValueCalculator) grpcMatches(ctx context.Context) ([]models.Match, error) {
+	if c.httpClient == nil {
+		return nil, status.Error(codes.Unavailable, "parser URL is not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches for gRPC call", "error", err)
+		return nil, status.Errorf(codes.Unavailable, "failed to fetch matches from parser: %v", err)
+	}
+	return matches, nil
+}
+
+// listQueryValues turns a ListQuery into the query parameters of the HTTP list endpoints.
+func listQueryValues(q *calculatorv1.ListQuery) url.Values {
+	v := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			v.Set(name, value)
+		}
+	}
+	if q.GetLimit() > 0 {
+		v.Set("limit", strconv.Itoa(int(q.GetLimit())))
+	}
+	set("cursor", q.GetCursor())
+	set("sort", q.GetSort())
+	set("order", q.GetOrder())
+	set("sport", q.GetSport())
+	set("bookmaker", strings.Join(q.GetBookmakers(), ","))
+	set("league", q.GetLeague())
+	if q.GetMinOdds() != 0 {
+		v.Set("min_odds", strconv.FormatFloat(q.GetMinOdds(), 'f', -1, 64))
+	}
+	if q.GetMaxOdds() != 0 {
+		v.Set("max_odds", strconv.FormatFloat(q.GetMaxOdds(), 'f', -1, 64))
+	}
+	return v
+}
+
+func toProtoValueBet(vb *ValueBet) *calculatorv1.ValueBet {
+	return &calculatorv1.ValueBet{
+		Id:                     vb.ID,
+		MatchGroupKey:          vb.MatchGroupKey,
+		MatchName:              vb.MatchName,
+		StartTime:              protoTimestamp(vb.StartTime),
+		Sport:                  vb.Sport,
+		Tournament:             valueBetLeague(vb),
+		EventType:              vb.EventType,
+		OutcomeType:            vb.OutcomeType,
+		Parameter:              vb.Parameter,
+		BetKey:                 vb.BetKey,
+		AllBookmakerOdds:       vb.AllBookmakerOdds,
+		FairOdd:                vb.FairOdd,
+		FairProbability:        vb.FairProbability,
+		Bookmaker:              vb.Bookmaker,
+		BookmakerUrl:           vb.BookmakerURL,
+		BookmakerOdd:           vb.BookmakerOdd,
+		ValuePercent:           vb.ValuePercent,
+		ExpectedValue:          vb.ExpectedValue,
+		KellyPercent:           vb.KellyPercent,
+		FractionalKellyPercent: vb.FractionalKellyPercent,
+		FlatStakePercent:       vb.FlatStakePercent,
+		CalculatedAt:           protoTimestamp(vb.CalculatedAt),
+	}
+}
+
+func toProtoLineMovement(lm *LineMovement) *calculatorv1.LineMovement {
+	return &calculatorv1.LineMovement{
+		MatchGroupKey: lm.MatchGroupKey,
+		MatchName:     lm.MatchName,
+		StartTime:     protoTimestamp(lm.StartTime),
+		Sport:         lm.Sport,
+		Tournament:    lm.Tournament,
+		EventType:     lm.EventType,
+		OutcomeType:   lm.OutcomeType,
+		Parameter:     lm.Parameter,
+		BetKey:        lm.BetKey,
+		Bookmaker:     lm.Bookmaker,
+		BookmakerUrl:  lm.BookmakerURL,
+		PreviousOdd:   lm.PreviousOdd,
+		CurrentOdd:    lm.CurrentOdd,
+		ChangeAbs:     lm.ChangeAbs,
+		ChangePercent: lm.ChangePercent,
+		ProbShiftPp:   lm.ProbShiftPP,
+		RecordedAt:    protoTimestamp(lm.RecordedAt),
+	}
+}
+
+// protoTimestamp keeps zero times unset.
+func protoTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
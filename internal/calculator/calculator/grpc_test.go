@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	calculatorv1 "github.com/Vodeneev/vodeneevbet/api/proto/calculator/v1"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestCalculatorGRPC(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	match := func(bookmaker string, odd float64) models.Match {
+		return models.Match{
+			ID: bookmaker + "_1", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football",
+			Tournament: "Premier League", Bookmaker: bookmaker, UpdatedAt: time.Now(),
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: odd, Bookmaker: bookmaker, UpdatedAt: time.Now()},
+			}}},
+		}
+	}
+	parser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{"matches": []models.Match{}}
+		if r.URL.Path == "/matches" {
+			resp["matches"] = []models.Match{match("fonbet", 2.0), match("pinnacle", 2.0), match("betcity", 2.4)}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer parser.Close()
+
+	c := NewValueCalculator(&config.ValueCalculatorConfig{ParserURL: parser.URL}, nil, nil)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	calculatorv1.RegisterCalculatorServiceServer(srv, &calculatorGRPCServer{c: c})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := calculatorv1.NewCalculatorServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	vbs, err := client.ListValueBets(ctx, &calculatorv1.ListValueBetsRequest{Query: &calculatorv1.ListQuery{League: "premier"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vbs.GetTotal() != 1 || len(vbs.GetValueBets()) != 1 {
+		t.Fatalf("value bets = %v, want the betcity home win", vbs)
+	}
+	vb := vbs.GetValueBets()[0]
+	if vb.GetBookmaker() != "betcity" || vb.GetBookmakerOdd() != 2.4 || vb.GetTournament() != "Premier League" ||
+		!vb.GetStartTime().AsTime().Equal(start) || len(vb.GetAllBookmakerOdds()) != 3 {
+		t.Errorf("unexpected value bet: %v", vb)
+	}
+
+	odds, err := client.GetMatchOdds(ctx, &calculatorv1.GetMatchOddsRequest{MatchGroupKey: vb.GetMatchGroupKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(odds.GetMatches()) != 3 {
+		t.Errorf("match odds: %d matches, want 3", len(odds.GetMatches()))
+	}
+
+	for name, call := range map[string]func() (codes.Code, error){
+		"unknown match": func() (codes.Code, error) {
+			_, err := client.GetMatchOdds(ctx, &calculatorv1.GetMatchOddsRequest{MatchGroupKey: "football|a|b|2026-01-01"})
+			return codes.NotFound, err
+		},
+		"bad sort": func() (codes.Code, error) {
+			_, err := client.ListValueBets(ctx, &calculatorv1.ListValueBetsRequest{Query: &calculatorv1.ListQuery{Sort: "change_percent"}})
+			return codes.InvalidArgument, err
+		},
+		"no snapshot storage": func() (codes.Code, error) {
+			_, err := client.ListLineMovements(ctx, &calculatorv1.ListLineMovementsRequest{})
+			return codes.FailedPrecondition, err
+		},
+	} {
+		if want, err := call(); status.Code(err) != want {
+			t.Errorf("%s: %v, want %s", name, err, want)
+		}
+	}
+}
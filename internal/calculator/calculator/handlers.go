@@ -1,17 +1,19 @@
 package calculator
 
-import "net/http"
+import (
+	"net/http"
 
-// RegisterHTTP registers calculator endpoints onto mux.
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+)
+
+// RegisterHTTP registers calculator endpoints onto mux: the API (apiRoutes) under /api/v1 and at the
+// unversioned legacy paths.
 func (c *ValueCalculator) RegisterHTTP(mux *http.ServeMux) {
-	mux.HandleFunc("/diffs/top", c.handleTopDiffs)
-	mux.HandleFunc("/value-bets/top", c.handleTopValueBets)
-	mux.HandleFunc("/line-movements/top", c.handleTopLineMovements)
-	mux.HandleFunc("/diffs/status", c.handleStatus)
-	mux.HandleFunc("/async/stop", c.handleStopAsync)
-	mux.HandleFunc("/async/stop_values", c.handleStopAsyncValues)
-	mux.HandleFunc("/async/stop_overlays", c.handleStopAsyncLineMovements)
-	mux.HandleFunc("/async/start", c.handleStartAsync)
-	mux.HandleFunc("/notifications/clear", c.handleClearNotificationQueue)
-	mux.HandleFunc("/db/clear", c.handleClearDB)
+	registerAPI(mux, c.apiRoutes())
+	mux.HandleFunc("/events", eventlog.Handle)
+	mux.Handle("/metrics", metrics.Handler())
+	if c.cfg != nil && c.cfg.WebApp.Enabled {
+		c.registerWebApp(mux)
+	}
 }
@@ -0,0 +1,59 @@
+package calculator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+)
+
+// pinger is a storage that can check its database (Postgres and SQLite diff storages).
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// RegisterHealthChecks adds the dependencies of the calculator to hc. The database and, when alerts are
+// configured, the Telegram bot token gate readiness; the parser is only observed, the API still serves
+// stored data without it.
+func (c *ValueCalculator) RegisterHealthChecks(hc *healthcheck.Checker) {
+	if db, ok := c.diffStorage.(pinger); ok {
+		hc.Require("database", db.Ping)
+	}
+	if c.cfg != nil && c.cfg.AsyncEnabled && c.cfg.TelegramBotToken != "" {
+		hc.Require("telegram", c.checkTelegram)
+	}
+	if c.httpClient != nil {
+		hc.Observe("parser", c.httpClient.Ping)
+	}
+}
+
+// checkTelegram checks the bot token with getMe; a token rejected at startup left the calculator
+// without a notifier.
+func (c *ValueCalculator) checkTelegram(context.Context) error {
+	if c.notifier == nil {
+		return errors.New("telegram bot token rejected at startup")
+	}
+	if _, err := c.notifier.bot.GetMe(); err != nil {
+		return fmt.Errorf("getMe: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the parser is up (GET /healthz).
+func (c *HTTPMatchesClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/healthz", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
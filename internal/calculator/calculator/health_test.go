@@ -0,0 +1,48 @@
+package calculator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/inprocess"
+)
+
+func TestRegisterHealthChecks(t *testing.T) {
+	parser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer parser.Close()
+
+	c := NewValueCalculator(&config.ValueCalculatorConfig{ParserURL: parser.URL}, nil, nil)
+	hc := healthcheck.New("calculator")
+	c.RegisterHealthChecks(hc)
+	if r := hc.Run(context.Background()); r.Status != healthcheck.StatusDegraded || !r.Ready() || len(r.Checks) != 1 || r.Checks[0].Error != "status 503" {
+		t.Errorf("parser down: %+v", r)
+	}
+
+	// Alerts configured but the token was rejected at startup: not ready
+	c.cfg.AsyncEnabled, c.cfg.TelegramBotToken = true, "bad-token"
+	hc = healthcheck.New("calculator")
+	c.RegisterHealthChecks(hc)
+	r := hc.Run(context.Background())
+	if r.Ready() || r.Checks[0].Name != "telegram" || !r.Checks[0].Required {
+		t.Errorf("telegram without notifier: %+v", r)
+	}
+}
+
+func TestSetParserTransport(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	c := NewValueCalculator(&config.ValueCalculatorConfig{ParserURL: inprocess.BaseURL("parser")}, nil, nil)
+	c.SetParserTransport(&inprocess.Transport{Handlers: map[string]http.Handler{inprocess.BaseURL("parser"): mux}})
+	if err := c.httpClient.Ping(context.Background()); err != nil {
+		t.Errorf("in-process parser: %v", err)
+	}
+}
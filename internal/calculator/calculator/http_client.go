@@ -12,13 +12,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/bus"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 // HTTPMatchesClient fetches matches from parser's /matches endpoint
 type HTTPMatchesClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	ignores     *ignoreList           // matches dropped from GetMatchesAll (nil = none)
+	consistency *consistencyValidator // lines contradicting the bookmaker's 1X2 (nil = not checked)
+	busMatches  *bus.MatchCache       // football matches consumed from the bus (nil = GET /matches)
+	cache       *responseCache        // merged snapshot shared through Redis (nil = not cached)
 }
 
 // NewHTTPMatchesClient creates a new HTTP client for fetching matches
@@ -33,11 +39,28 @@ func NewHTTPMatchesClient(baseURL string) *HTTPMatchesClient {
 	return &HTTPMatchesClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
 
+// SetBusMatches makes the calculator take football matches from the bus consumer cache (bus.consume)
+// instead of GET parser_url/matches; until the cache receives matches, /matches is still polled.
+func (c *ValueCalculator) SetBusMatches(cache *bus.MatchCache) {
+	if c.httpClient != nil {
+		c.httpClient.busMatches = cache
+	}
+}
+
+// SetParserTransport sends the requests to parser_url through rt instead of http.DefaultTransport
+// (cmd/allinone serves them in process).
+func (c *ValueCalculator) SetParserTransport(rt http.RoundTripper) {
+	if c.httpClient != nil {
+		c.httpClient.httpClient.Transport = tracing.Transport(rt)
+	}
+}
+
 // matchesResponse represents the response from /matches endpoint
 type matchesResponse struct {
 	Matches []models.Match `json:"matches"`
@@ -48,9 +71,13 @@ type matchesResponse struct {
 	} `json:"meta"`
 }
 
-// GetMatches fetches all matches from the parser's /matches endpoint.
+// GetMatches fetches all matches from the parser's /matches endpoint, or takes them from the bus
+// consumer cache (bus.consume) once it has received matches.
 // Retries up to 3 times on transient errors (EOF, connection reset) with 2s backoff.
 func (c *HTTPMatchesClient) GetMatches(ctx context.Context) ([]models.Match, error) {
+	if c != nil && c.busMatches != nil && c.busMatches.Len() > 0 {
+		return c.busMatches.Matches(), nil
+	}
 	const maxAttempts = 3
 	const backoff = 2 * time.Second
 	var lastErr error
@@ -169,12 +196,66 @@ func (c *HTTPMatchesClient) GetEsportsMatches(ctx context.Context) ([]models.Esp
 	return mr.Matches, nil
 }
 
+// outrightsResponse represents the response from /outrights endpoint
+type outrightsResponse struct {
+	Outrights []models.Outright `json:"outrights"`
+}
+
+// GetOutrights fetches outright (futures) markets from the parser's /outrights endpoint (тот же baseURL).
+func (c *HTTPMatchesClient) GetOutrights(ctx context.Context) ([]models.Outright, error) {
+	if c == nil {
+		return nil, fmt.Errorf("HTTP client is not configured")
+	}
+	u, err := url.Parse(c.baseURL + "/outrights")
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outrights: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	var or outrightsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&or); err != nil {
+		return nil, fmt.Errorf("failed to decode outrights response: %w", err)
+	}
+	return or.Outrights, nil
+}
+
 // GetMatchesAll fetches football matches and esports matches, converts esports to models.Match,
-// filters out finished matches (started more than 3 hours ago), and returns a single slice.
+// resolves fixtures reported under conflicting sports (see dedupeCrossSportMatches),
+// replaces tournament names with the canonical ones (see canonicalizeTournaments),
+// makes interval totals and quarter lines comparable across bookmakers (see normalizeIntervalOutcomes,
+// normalizeAsianLines), drops lines contradicting the bookmaker's own 1X2 (see consistencyValidator),
+// filters out finished matches (started more than 3 hours ago) and ignored matches (/ignores),
+// and returns a single slice. With value_calculator.redis_cache the snapshot is shared for matches_ttl.
 func (c *HTTPMatchesClient) GetMatchesAll(ctx context.Context) ([]models.Match, error) {
 	if c == nil {
 		return nil, fmt.Errorf("HTTP client is not configured")
 	}
+	// Ignores are applied again: a match ignored after the snapshot was cached drops out at once
+	if cached, ok := c.cache.cachedMatches(ctx); ok {
+		return c.ignores.filter(cached, time.Now()), nil
+	}
+	matches, err := c.mergeMatchesAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.storeMatches(ctx, matches)
+	return matches, nil
+}
+
+// mergeMatchesAll fetches football and esports and merges them into one filtered snapshot.
+func (c *HTTPMatchesClient) mergeMatchesAll(ctx context.Context) ([]models.Match, error) {
 	football, err := c.GetMatches(ctx)
 	if err != nil {
 		return nil, err
@@ -183,14 +264,25 @@ func (c *HTTPMatchesClient) GetMatchesAll(ctx context.Context) ([]models.Match,
 	if errEsports != nil {
 		// Only football is still returned; esports fetch failure is non-fatal
 		slog.Warn("Failed to fetch esports matches, using football only", "error", errEsports)
-		return c.filterFinishedMatches(football), nil
+		return c.ignores.filter(c.filterFinishedMatches(c.consistency.apply(normalizeAsianLines(normalizeIntervalOutcomes(canonicalizeTournaments(football))), time.Now())), time.Now()), nil
 	}
 	var esportsSummary EsportsConversionSummary
 	converted := EsportsMatchesToMatches(esports, &esportsSummary)
 	allMatches := append(football, converted...)
+
+	// Same fixture may come under two sports (e.g. 1xbet football vs "esports"); keep one to avoid double counting
+	allMatches, conflicts := dedupeCrossSportMatches(allMatches)
+	// One tournament name per league for filters, value bet groups and alerts ("EPL" = "Англия. Премьер-лига")
+	allMatches = canonicalizeTournaments(allMatches)
+	// Interval totals (corners "9-11") only count when another bookmaker quotes the same bet
+	allMatches = normalizeIntervalOutcomes(allMatches)
+	// Quarter lines (2.25, -0.75) compared across bookmakers quoting different ladders
+	allMatches = normalizeAsianLines(allMatches)
+	// Double chance and Asian 0 lines contradicting the bookmaker's own 1X2 are parsing bugs, not values
+	allMatches = c.consistency.apply(allMatches, time.Now())
 	
 	// Filter out finished matches before returning
-	filtered := c.filterFinishedMatches(allMatches)
+	filtered := c.ignores.filter(c.filterFinishedMatches(allMatches), time.Now())
 	
 	total := len(football) + len(converted)
 	slog.Info("Fetched matches for calculator",
@@ -199,7 +291,8 @@ func (c *HTTPMatchesClient) GetMatchesAll(ctx context.Context) ([]models.Match,
 		"esports_converted", len(converted),
 		"total_merged", total,
 		"after_filtering", len(filtered),
-		"filtered_out", total-len(filtered))
+		"filtered_out", total-len(filtered),
+		"cross_sport_conflicts", len(conflicts))
 	if esportsSummary.MatchCount > 0 {
 		slog.Info("Esports merge summary",
 			"match_count", esportsSummary.MatchCount,
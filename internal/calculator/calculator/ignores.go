@@ -0,0 +1,303 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// defaultIgnoreTTL is how long a match stays ignored when the request has neither expires_at nor ttl
+// (value_calculator.ignore_ttl overrides it).
+const defaultIgnoreTTL = 24 * time.Hour
+
+// ignoreCallbackPrefix prefixes callback data of the "🚫 Ignore match" button under alerts: "ignore:<token>".
+// Telegram limits callback data to 64 bytes, so the button carries ignoreToken(match_group_key).
+const ignoreCallbackPrefix = "ignore:"
+
+// maxRecentAlerts bounds the token → match map of sent alerts.
+const maxRecentAlerts = 5000
+
+// ignoredMatchRef is a match an alert was sent for (resolves the token of its ignore button).
+type ignoredMatchRef struct {
+	key  string
+	name string
+}
+
+// ignoreList is the list of matches excluded from calculation until expiry (POST /ignores, bot button).
+// Entries are kept in memory and persisted to store when Postgres is configured.
+type ignoreList struct {
+	mu      sync.RWMutex
+	entries map[string]storage.IgnoredMatch // match_group_key -> entry
+	recent  map[string]ignoredMatchRef      // ignore token -> match of a sent alert
+	store   storage.IgnoreStorage           // nil = in memory only
+}
+
+func newIgnoreList() *ignoreList {
+	return &ignoreList{entries: map[string]storage.IgnoredMatch{}, recent: map[string]ignoredMatchRef{}}
+}
+
+// ignoreToken returns a short stable token of a match group key for the bot button.
+func ignoreToken(matchGroupKey string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(matchGroupKey))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// load attaches store and loads its active entries.
+func (l *ignoreList) load(ctx context.Context, store storage.IgnoreStorage) error {
+	entries, err := store.GetActiveIgnores(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+	for _, e := range entries {
+		l.entries[e.MatchGroupKey] = e
+	}
+	return nil
+}
+
+// add ignores m.MatchGroupKey until m.ExpiresAt (replacing an existing entry).
+func (l *ignoreList) add(ctx context.Context, m storage.IgnoredMatch) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store != nil {
+		if err := l.store.StoreIgnore(ctx, m); err != nil {
+			return err
+		}
+	}
+	l.entries[m.MatchGroupKey] = m
+	return nil
+}
+
+// remove deletes the entry of matchGroupKey; reports whether it existed.
+func (l *ignoreList) remove(ctx context.Context, matchGroupKey string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store != nil {
+		if err := l.store.DeleteIgnore(ctx, matchGroupKey); err != nil {
+			return false, err
+		}
+	}
+	_, ok := l.entries[matchGroupKey]
+	delete(l.entries, matchGroupKey)
+	return ok, nil
+}
+
+// active returns entries not expired at now, soonest expiry first.
+func (l *ignoreList) active(now time.Time) []storage.IgnoredMatch {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]storage.IgnoredMatch, 0, len(l.entries))
+	for _, e := range l.entries {
+		if e.ExpiresAt.After(now) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(out[j].ExpiresAt) })
+	return out
+}
+
+// filter drops matches whose group key is ignored at now.
+func (l *ignoreList) filter(matches []models.Match, now time.Time) []models.Match {
+	if l == nil {
+		return matches
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.entries) == 0 {
+		return matches
+	}
+	kept := make([]models.Match, 0, len(matches))
+	for _, m := range matches {
+		if e, ok := l.entries[matchGroupKey(m)]; ok && e.ExpiresAt.After(now) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	if dropped := len(matches) - len(kept); dropped > 0 {
+		slog.Info("Ignored matches excluded from calculation", "matches", dropped, "ignore_entries", len(l.entries))
+	}
+	return kept
+}
+
+// remember records the match of a sent alert and returns the token for its ignore button.
+func (l *ignoreList) remember(matchGroupKey, matchName string) string {
+	token := ignoreToken(matchGroupKey)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.recent) >= maxRecentAlerts {
+		l.recent = map[string]ignoredMatchRef{}
+	}
+	l.recent[token] = ignoredMatchRef{key: matchGroupKey, name: matchName}
+	return token
+}
+
+// resolve finds the match of an ignore token among sent alerts and existing entries.
+func (l *ignoreList) resolve(token string) (ignoredMatchRef, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if ref, ok := l.recent[token]; ok {
+		return ref, true
+	}
+	for key, e := range l.entries {
+		if ignoreToken(key) == token {
+			return ignoredMatchRef{key: key, name: e.MatchName}, true
+		}
+	}
+	return ignoredMatchRef{}, false
+}
+
+// ignoreKeyboard is the inline "🚫 Ignore match" button (label) under alerts; the bot turns it into POST /ignores.
+func ignoreKeyboard(token, label string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(label, ignoreCallbackPrefix+token),
+	))
+}
+
+// SetIgnoreStorage persists the ignore list to store and loads its active entries.
+func (c *ValueCalculator) SetIgnoreStorage(ctx context.Context, store storage.IgnoreStorage) error {
+	return c.ignores.load(ctx, store)
+}
+
+// ignoreRequest is the body of POST /ignores. The match is given by match_group_key or by the token
+// of an alert's ignore button; expiry by expires_at or ttl ("6h"), default value_calculator.ignore_ttl.
+type ignoreRequest struct {
+	MatchGroupKey string     `json:"match_group_key"`
+	Token         string     `json:"token"`
+	MatchName     string     `json:"match_name"`
+	Reason        string     `json:"reason"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	TTL           string     `json:"ttl"`
+}
+
+// handleIgnores serves the ignore list: GET lists active entries, POST adds one,
+// DELETE ?match_group_key=... (or ?token=...) removes one.
+func (c *ValueCalculator) handleIgnores(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	switch r.Method {
+	case http.MethodGet:
+		ignores := c.ignores.active(now)
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"ignores": ignores, "count": len(ignores)})
+	case http.MethodPost:
+		var req ignoreRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		entry, err := c.ignoreEntry(r.Context(), req, now)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := c.ignores.add(r.Context(), entry); err != nil {
+			slog.Error("Failed to store ignored match", "match_group_key", entry.MatchGroupKey, "error", err)
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store ignored match", "details": err.Error()})
+			return
+		}
+		slog.Info("Match ignored", "match_group_key", entry.MatchGroupKey, "match", entry.MatchName, "reason", entry.Reason, "expires_at", entry.ExpiresAt)
+		writeWebAppJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		key := strings.TrimSpace(r.URL.Query().Get("match_group_key"))
+		if token := r.URL.Query().Get("token"); key == "" && token != "" {
+			ref, _ := c.ignores.resolve(token)
+			key = ref.key
+		}
+		if key == "" {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "match_group_key or token is required"})
+			return
+		}
+		removed, err := c.ignores.remove(r.Context(), key)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete ignored match", "details": err.Error()})
+			return
+		}
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"match_group_key": key, "removed": removed})
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET, POST or DELETE"})
+	}
+}
+
+// ignoreEntry validates req and builds the entry to store.
+func (c *ValueCalculator) ignoreEntry(ctx context.Context, req ignoreRequest, now time.Time) (storage.IgnoredMatch, error) {
+	entry := storage.IgnoredMatch{
+		MatchGroupKey: strings.TrimSpace(req.MatchGroupKey),
+		MatchName:     strings.TrimSpace(req.MatchName),
+		Reason:        strings.TrimSpace(req.Reason),
+		CreatedAt:     now.UTC(),
+	}
+	if entry.MatchGroupKey == "" && req.Token != "" {
+		ref, ok := c.resolveIgnoreToken(ctx, req.Token)
+		if !ok {
+			return entry, fmt.Errorf("match of token %q not found (alert too old?)", req.Token)
+		}
+		entry.MatchGroupKey = ref.key
+		if entry.MatchName == "" {
+			entry.MatchName = ref.name
+		}
+	}
+	if entry.MatchGroupKey == "" {
+		return entry, fmt.Errorf("match_group_key or token is required")
+	}
+
+	ttl := defaultIgnoreTTL
+	if c.cfg != nil && c.cfg.IgnoreTTL > 0 {
+		ttl = c.cfg.IgnoreTTL
+	}
+	switch {
+	case req.ExpiresAt != nil:
+		entry.ExpiresAt = req.ExpiresAt.UTC()
+	case req.TTL != "":
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil || d <= 0 {
+			return entry, fmt.Errorf("invalid ttl %q", req.TTL)
+		}
+		entry.ExpiresAt = now.Add(d).UTC()
+	default:
+		entry.ExpiresAt = now.Add(ttl).UTC()
+	}
+	if !entry.ExpiresAt.After(now) {
+		return entry, fmt.Errorf("expires_at is in the past")
+	}
+	return entry, nil
+}
+
+// resolveIgnoreToken finds the match of a bot button token: sent alerts first, then current matches
+// (the calculator may have restarted since the alert).
+func (c *ValueCalculator) resolveIgnoreToken(ctx context.Context, token string) (ignoredMatchRef, bool) {
+	if ref, ok := c.ignores.resolve(token); ok {
+		return ref, true
+	}
+	if c.httpClient == nil {
+		return ignoredMatchRef{}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Warn("Failed to fetch matches to resolve ignore token", "token", token, "error", err)
+		return ignoredMatchRef{}, false
+	}
+	for _, m := range matches {
+		if key := matchGroupKey(m); key != "" && ignoreToken(key) == token {
+			return ignoredMatchRef{key: key, name: strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam)}, true
+		}
+	}
+	return ignoredMatchRef{}, false
+}
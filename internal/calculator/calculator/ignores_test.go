@@ -0,0 +1,104 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestIgnoreListFilter(t *testing.T) {
+	now := time.Now()
+	arsenal := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", Sport: "football", StartTime: now.Add(time.Hour)}
+	derby := models.Match{HomeTeam: "Roma", AwayTeam: "Lazio", Sport: "football", StartTime: now.Add(time.Hour)}
+	expired := models.Match{HomeTeam: "Milan", AwayTeam: "Inter", Sport: "football", StartTime: now.Add(time.Hour)}
+
+	l := newIgnoreList()
+	ctx := context.Background()
+	if err := l.add(ctx, storage.IgnoredMatch{MatchGroupKey: matchGroupKey(arsenal), ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.add(ctx, storage.IgnoredMatch{MatchGroupKey: matchGroupKey(expired), ExpiresAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := l.filter([]models.Match{arsenal, derby, expired}, now)
+	if len(got) != 2 || got[0].HomeTeam != "Roma" || got[1].HomeTeam != "Milan" {
+		t.Fatalf("filter kept %+v, want Roma and Milan", got)
+	}
+	if active := l.active(now); len(active) != 1 || active[0].MatchGroupKey != matchGroupKey(arsenal) {
+		t.Errorf("active = %+v, want only the Arsenal entry", active)
+	}
+
+	if removed, err := l.remove(ctx, matchGroupKey(arsenal)); err != nil || !removed {
+		t.Fatalf("remove = %v, %v", removed, err)
+	}
+	if got := l.filter([]models.Match{arsenal}, now); len(got) != 1 {
+		t.Error("removed entry still filters the match")
+	}
+	var nilList *ignoreList
+	if got := nilList.filter([]models.Match{arsenal}, now); len(got) != 1 {
+		t.Error("nil list must keep every match")
+	}
+}
+
+func TestHandleIgnores_Token(t *testing.T) {
+	c := &ValueCalculator{cfg: &config.ValueCalculatorConfig{IgnoreTTL: 2 * time.Hour}, ignores: newIgnoreList()}
+	key := "football|arsenal|chelsea|2026-10-16T19:00:00Z"
+	token := c.ignores.remember(key, "Arsenal vs Chelsea")
+	if len(ignoreCallbackPrefix+token) > 64 {
+		t.Fatalf("callback data %q exceeds Telegram's 64 bytes", ignoreCallbackPrefix+token)
+	}
+
+	rec := httptest.NewRecorder()
+	body := `{"token":"` + token + `","reason":"wrong team mapping"}`
+	c.handleIgnores(rec, httptest.NewRequest(http.MethodPost, "/ignores", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status %d: %s", rec.Code, rec.Body)
+	}
+	var entry storage.IgnoredMatch
+	if err := json.NewDecoder(rec.Body).Decode(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.MatchGroupKey != key || entry.MatchName != "Arsenal vs Chelsea" || entry.Reason != "wrong team mapping" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if ttl := time.Until(entry.ExpiresAt); ttl < time.Hour || ttl > 2*time.Hour {
+		t.Errorf("expires in %v, want ignore_ttl 2h", ttl)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleIgnores(rec, httptest.NewRequest(http.MethodGet, "/ignores", nil))
+	if !strings.Contains(rec.Body.String(), `"count":1`) {
+		t.Errorf("GET = %s, want one entry", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleIgnores(rec, httptest.NewRequest(http.MethodDelete, "/ignores?token="+token, nil))
+	if rec.Code != http.StatusOK || len(c.ignores.active(time.Now())) != 0 {
+		t.Errorf("DELETE status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandleIgnores_Validation(t *testing.T) {
+	c := &ValueCalculator{ignores: newIgnoreList()}
+	for _, body := range []string{
+		`{"reason":"no match"}`,
+		`{"match_group_key":"football|a|b","ttl":"soon"}`,
+		`{"match_group_key":"football|a|b","expires_at":"2000-01-01T00:00:00Z"}`,
+		`{"token":"0000000000000000"}`,
+	} {
+		rec := httptest.NewRecorder()
+		c.handleIgnores(rec, httptest.NewRequest(http.MethodPost, "/ignores", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status %d, want 400", body, rec.Code)
+		}
+	}
+}
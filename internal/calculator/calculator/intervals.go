@@ -0,0 +1,171 @@
+package calculator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// normalizeIntervalOutcomes makes interval totals (total_interval, e.g. Fonbet corners "9-11") comparable
+// with other bookmakers before consensus:
+//   - open-ended "N+" is the same bet as total_over (N-0.5), and "0-N" as total_under (N+0.5), so they are re-keyed;
+//   - for a bounded "a-b" interval, a bookmaker quoting exact counts for every k in [a,b] gets an equivalent
+//     interval outcome with odd 1 / Σ(1/odd_k);
+//   - intervals still quoted by a single bookmaker are removed, so they never reach consensus as noise.
+func normalizeIntervalOutcomes(matches []models.Match) []models.Match {
+	type scope struct{ group, eventType string }
+	// scope -> interval param -> bookmakers quoting it
+	intervals := map[scope]map[string]map[string]bool{}
+	// scope -> bookmaker -> exact count -> odd
+	exact := map[scope]map[string]map[int]float64{}
+
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			sc := scope{gk, strings.TrimSpace(ev.EventType)}
+			for k := range ev.Outcomes {
+				out := &ev.Outcomes[k]
+				bk := outcomeBookmaker(*m, *ev, *out)
+				switch out.OutcomeType {
+				case string(models.OutcomeTypeTotalInterval):
+					lo, hi, ok := models.ParseIntervalParam(out.Parameter)
+					if !ok {
+						continue
+					}
+					switch {
+					case hi < 0:
+						out.OutcomeType = string(models.OutcomeTypeTotalOver)
+						out.Parameter = formatHalfLine(float64(lo) - 0.5)
+					case lo == 0:
+						out.OutcomeType = string(models.OutcomeTypeTotalUnder)
+						out.Parameter = formatHalfLine(float64(hi) + 0.5)
+					default:
+						out.Parameter = fmt.Sprintf("%d-%d", lo, hi)
+						if intervals[sc] == nil {
+							intervals[sc] = map[string]map[string]bool{}
+						}
+						if intervals[sc][out.Parameter] == nil {
+							intervals[sc][out.Parameter] = map[string]bool{}
+						}
+						intervals[sc][out.Parameter][bk] = true
+					}
+				case string(models.OutcomeTypeExactCount):
+					n, ok := parseExactCount(out.Parameter)
+					if !ok || !isFinitePositiveOdd(out.Odds) {
+						continue
+					}
+					if exact[sc] == nil {
+						exact[sc] = map[string]map[int]float64{}
+					}
+					if exact[sc][bk] == nil {
+						exact[sc][bk] = map[int]float64{}
+					}
+					exact[sc][bk][n] = out.Odds
+				}
+			}
+		}
+	}
+	if len(intervals) == 0 {
+		return matches
+	}
+
+	// Synthesize equivalent intervals from exact counts for bookmakers that don't quote the interval.
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			sc := scope{gk, strings.TrimSpace(ev.EventType)}
+			if len(intervals[sc]) == 0 {
+				continue
+			}
+			bk := outcomeBookmaker(*m, *ev, models.Outcome{})
+			counts := exact[sc][bk]
+			if len(counts) == 0 {
+				continue
+			}
+			for param, books := range intervals[sc] {
+				if books[bk] {
+					continue
+				}
+				lo, hi, _ := models.ParseIntervalParam(param)
+				var prob float64
+				complete := true
+				for n := lo; n <= hi; n++ {
+					odd, ok := counts[n]
+					if !ok {
+						complete = false
+						break
+					}
+					prob += 1.0 / odd
+				}
+				if !complete || prob <= 0 || prob >= 1 {
+					continue
+				}
+				ev.Outcomes = append(ev.Outcomes, models.Outcome{
+					ID:          ev.ID + "_total_interval_" + param,
+					EventID:     ev.ID,
+					OutcomeType: string(models.OutcomeTypeTotalInterval),
+					Parameter:   param,
+					Odds:        1.0 / prob,
+					Bookmaker:   bk,
+					CreatedAt:   ev.UpdatedAt,
+					UpdatedAt:   ev.UpdatedAt,
+				})
+				books[bk] = true
+			}
+		}
+	}
+
+	// Drop intervals nobody else quotes (directly or via exact counts).
+	for i := range matches {
+		m := &matches[i]
+		gk := matchGroupKey(*m)
+		for j := range m.Events {
+			ev := &m.Events[j]
+			sc := scope{gk, strings.TrimSpace(ev.EventType)}
+			kept := ev.Outcomes[:0]
+			for _, out := range ev.Outcomes {
+				if out.OutcomeType == string(models.OutcomeTypeTotalInterval) && len(intervals[sc][out.Parameter]) < 2 {
+					continue
+				}
+				kept = append(kept, out)
+			}
+			ev.Outcomes = kept
+		}
+	}
+	return matches
+}
+
+// outcomeBookmaker returns the lower-cased bookmaker of an outcome, falling back to its event and match.
+func outcomeBookmaker(m models.Match, ev models.Event, out models.Outcome) string {
+	for _, bk := range []string{out.Bookmaker, ev.Bookmaker, m.Bookmaker} {
+		if bk = strings.TrimSpace(bk); bk != "" {
+			return strings.ToLower(bk)
+		}
+	}
+	return ""
+}
+
+// parseExactCount accepts unsigned integer parameters only: signed ones are handicaps
+// that some parsers store under exact_count.
+func parseExactCount(param string) (int, bool) {
+	param = strings.TrimSpace(param)
+	if param == "" || param[0] == '+' || param[0] == '-' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// formatHalfLine formats a total line the way parsers do ("8.5").
+func formatHalfLine(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
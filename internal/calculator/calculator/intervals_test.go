@@ -0,0 +1,42 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestNormalizeIntervalOutcomes(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	fonbet := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "Fonbet",
+		Events: []models.Event{{EventType: "corners", Bookmaker: "Fonbet", Outcomes: []models.Outcome{
+			{OutcomeType: "total_interval", Parameter: "9-10", Odds: 3.0},
+			{OutcomeType: "total_interval", Parameter: "12+", Odds: 2.2},
+			{OutcomeType: "total_interval", Parameter: "5-6", Odds: 9.0},
+		}}}}
+	leon := models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "Leon",
+		Events: []models.Event{{EventType: "corners", Bookmaker: "Leon", Outcomes: []models.Outcome{
+			{OutcomeType: "exact_count", Parameter: "9", Odds: 6.0},
+			{OutcomeType: "exact_count", Parameter: "10", Odds: 6.0},
+		}}}}
+
+	out := normalizeIntervalOutcomes([]models.Match{fonbet, leon})
+
+	got := map[string]float64{}
+	for _, m := range out {
+		for _, o := range m.Events[0].Outcomes {
+			got[m.Bookmaker+"|"+o.OutcomeType+"|"+o.Parameter] = o.Odds
+		}
+	}
+	if _, ok := got["Fonbet|total_over|11.5"]; !ok {
+		t.Errorf("open-ended interval should become total_over 11.5, got %v", got)
+	}
+	if odd, ok := got["Leon|total_interval|9-10"]; !ok || math.Abs(odd-3.0) > 1e-9 {
+		t.Errorf("expected synthesized Leon interval 9-10 @3.0, got %v", got)
+	}
+	if _, ok := got["Fonbet|total_interval|5-6"]; ok {
+		t.Errorf("single-book interval 5-6 should be dropped, got %v", got)
+	}
+}
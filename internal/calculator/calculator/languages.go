@@ -0,0 +1,115 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+)
+
+// chatLanguageList is the message language by chat (/lang in the bot, POST /chat-language). Chats without
+// one get telegram.language. Entries are kept in memory and persisted to store when Postgres is configured.
+type chatLanguageList struct {
+	mu      sync.RWMutex
+	entries map[int64]string            // chat_id -> language
+	store   storage.ChatLanguageStorage // nil = in memory only
+}
+
+func newChatLanguageList() *chatLanguageList {
+	return &chatLanguageList{entries: map[int64]string{}}
+}
+
+// load attaches store and loads its languages.
+func (l *chatLanguageList) load(ctx context.Context, store storage.ChatLanguageStorage) error {
+	languages, err := store.GetChatLanguages(ctx)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+	for chatID, language := range languages {
+		l.entries[chatID] = language
+	}
+	return nil
+}
+
+// set sets the language of chatID.
+func (l *chatLanguageList) set(ctx context.Context, chatID int64, language string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store != nil {
+		if err := l.store.StoreChatLanguage(ctx, chatID, language); err != nil {
+			return err
+		}
+	}
+	l.entries[chatID] = language
+	return nil
+}
+
+// get returns the language of chatID ("" = not chosen). Safe to call on a nil list.
+func (l *chatLanguageList) get(chatID int64) string {
+	if l == nil {
+		return ""
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.entries[chatID]
+}
+
+// SetChatLanguageStorage persists chat languages to store and loads them.
+func (c *ValueCalculator) SetChatLanguageStorage(ctx context.Context, store storage.ChatLanguageStorage) error {
+	return c.languages.load(ctx, store)
+}
+
+// chatLanguageResponse is the language of a chat; Language is "" while the chat hasn't chosen one.
+type chatLanguageResponse struct {
+	ChatID   int64  `json:"chat_id"`
+	Language string `json:"language"`
+}
+
+// handleChatLanguage serves the message language of a chat: GET ?chat_id=... returns it, POST
+// {"chat_id": ..., "language": "ru"} sets it.
+func (c *ValueCalculator) handleChatLanguage(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v := r.URL.Query().Get("chat_id")
+		chatID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || chatID == 0 {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid chat_id %q", v)})
+			return
+		}
+		writeWebAppJSON(w, http.StatusOK, chatLanguageResponse{ChatID: chatID, Language: c.languages.get(chatID)})
+	case http.MethodPost:
+		var req chatLanguageResponse
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		if req.ChatID == 0 {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "chat_id is required"})
+			return
+		}
+		language, ok := tgformat.ParseLanguage(req.Language)
+		if !ok {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unsupported language %q (want %s)", req.Language, strings.Join(tgformat.Languages, ", "))})
+			return
+		}
+		if err := c.languages.set(r.Context(), req.ChatID, language); err != nil {
+			slog.Error("Failed to store chat language", "chat_id", req.ChatID, "error", err)
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store chat language", "details": err.Error()})
+			return
+		}
+		slog.Info("Chat language set", "chat_id", req.ChatID, "language", language)
+		writeWebAppJSON(w, http.StatusOK, chatLanguageResponse{ChatID: req.ChatID, Language: language})
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET or POST"})
+	}
+}
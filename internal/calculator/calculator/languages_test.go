@@ -0,0 +1,57 @@
+package calculator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleChatLanguage(t *testing.T) {
+	languages := newChatLanguageList()
+	c := &ValueCalculator{languages: languages}
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c.handleChatLanguage(rec, httptest.NewRequest(http.MethodPost, "/chat-language", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := post(`{"chat_id": 42, "language": "RU"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST: status %d: %s", rec.Code, rec.Body)
+	}
+	for _, body := range []string{`{"language": "ru"}`, `{"chat_id": 42, "language": "de"}`} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status %d, want 400", body, rec.Code)
+		}
+	}
+
+	get := func(chatID string) chatLanguageResponse {
+		rec := httptest.NewRecorder()
+		c.handleChatLanguage(rec, httptest.NewRequest(http.MethodGet, "/chat-language?chat_id="+chatID, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", chatID, rec.Code)
+		}
+		var resp chatLanguageResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if got := get("42"); got.Language != "ru" {
+		t.Errorf("chat 42: got %+v", got)
+	}
+	if got := get("7"); got.Language != "" {
+		t.Errorf("chat without a language: got %+v", got)
+	}
+
+	// Alerts to the chat are rendered in its language
+	n := &TelegramNotifier{languages: languages}
+	last := DiffBet{MatchName: "Arsenal vs Chelsea", EventType: "main_match", OutcomeType: "home_win", MaxBookmaker: "Fonbet", MaxOdd: 2.6}
+	msg := n.formatValueClosedAlert(n.languages.get(42), &valueClosed{alerted: last})
+	for _, want := range []string{"✅ <b>Валуй закрылся</b>", "Исход матча | П1", "📊 Сейчас: валуя в Fonbet нет"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("ru closed alert %q does not contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// defaultLeaderRenewInterval is value_calculator.leader_election.renew_interval when unset.
+const defaultLeaderRenewInterval = 10 * time.Second
+
+// Cycle state names in storage.CycleStateStorage.
+const (
+	cycleStateValueAlerts = "value_alerts_" // + pipeline name
+	cycleStateSteamAlerts = "steam_alerts"
+)
+
+// leaderElection lets several calculator replicas share one database (value_calculator.leader_election).
+// Only the replica holding the leader lock runs the async cycle and the background jobs; at the end of each
+// cycle the leader saves the alert state machines and steam alert cooldowns, and the first cycle of a
+// replica taking the lock over restores them, so a failover neither repeats nor loses alerts. Followers
+// keep serving the HTTP API.
+//
+// Leadership is checked every renew_interval: after the leader loses its database session, both replicas
+// may run a cycle within that window.
+type leaderElection struct {
+	lock  storage.LeaderLock        // nil = single instance, always the leader
+	state storage.CycleStateStorage // nil = cycle state is not saved
+
+	mu     sync.RWMutex
+	leader bool
+	term   uint64 // incremented on every takeover
+
+	// term whose state the main / cyber loop restored (each owned by its loop)
+	mainTerm, cyberTerm uint64
+}
+
+// SetLeaderElection makes the replica run the async cycle only while it holds lock, sharing the cycle
+// state through state (value_calculator.leader_election.enabled).
+func (c *ValueCalculator) SetLeaderElection(lock storage.LeaderLock, state storage.CycleStateStorage) {
+	c.leader.lock = lock
+	c.leader.state = state
+}
+
+// isLeader reports whether this replica runs the async cycle and the background jobs.
+func (c *ValueCalculator) isLeader() bool {
+	if c.leader.lock == nil {
+		return true
+	}
+	c.leader.mu.RLock()
+	defer c.leader.mu.RUnlock()
+	return c.leader.leader
+}
+
+// leaderCycle reports whether the loop whose restored term is *restored runs this cycle, and whether it
+// must restore the cycle state first (first cycle of a new term); *restored is advanced.
+func (c *ValueCalculator) leaderCycle(restored *uint64) (run, restore bool) {
+	if c.leader.lock == nil {
+		return true, false
+	}
+	c.leader.mu.RLock()
+	leader, term := c.leader.leader, c.leader.term
+	c.leader.mu.RUnlock()
+	if !leader {
+		return false, false
+	}
+	restore = term != *restored
+	*restored = term
+	return true, restore
+}
+
+// runLeaderElection tries to take the leader lock or checks it is still held every renew_interval until
+// ctx is done, then releases it.
+func (c *ValueCalculator) runLeaderElection(ctx context.Context) {
+	interval := defaultLeaderRenewInterval
+	if c.cfg != nil && c.cfg.LeaderElection.RenewInterval > 0 {
+		interval = c.cfg.LeaderElection.RenewInterval
+	}
+	slog.Info("Leader election started", "renew_interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := c.leader.lock.Release(releaseCtx); err != nil {
+				slog.Warn("Failed to release leader lock", "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			c.campaign(ctx)
+		}
+	}
+}
+
+// campaign takes the leader lock if free, or checks that it is still held, and logs leadership changes.
+func (c *ValueCalculator) campaign(ctx context.Context) {
+	lockCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	held, err := c.leader.lock.TryAcquire(lockCtx)
+	if err != nil {
+		slog.Error("Leader lock check failed", "error", err)
+	}
+
+	c.leader.mu.Lock()
+	was := c.leader.leader
+	c.leader.leader = held
+	if held && !was {
+		c.leader.term++
+	}
+	term := c.leader.term
+	c.leader.mu.Unlock()
+
+	switch {
+	case held && !was:
+		slog.Info("Became calculator leader, running async cycle", "term", term)
+	case !held && was:
+		slog.Warn("Lost calculator leadership, async cycle paused", "term", term)
+	}
+}
+
+// restoreValueAlerts loads the alert state of pipeline p saved by the previous leader. State older than the
+// alert cooldown is dropped: the alerts are then restored from diff storage as after a restart.
+func (c *ValueCalculator) restoreValueAlerts(ctx context.Context, p *valuePipeline) {
+	var data []byte
+	if c.leader.state != nil {
+		saved, savedAt, err := c.leader.state.LoadCycleState(ctx, cycleStateValueAlerts+p.name)
+		if err != nil {
+			slog.Error("Failed to load value alert state", "pipeline", p.name, "error", err)
+		} else if saved != nil && time.Since(savedAt) < p.alerts.cooldown {
+			data = saved
+		}
+	}
+	if err := p.alerts.load(data); err != nil {
+		slog.Error("Failed to restore value alert state", "pipeline", p.name, "error", err)
+		_ = p.alerts.load(nil)
+		return
+	}
+	slog.Info("Value alert state restored", "pipeline", p.name, "saved", data != nil)
+}
+
+// saveValueAlerts saves the alert state of pipeline p for the next leader.
+func (c *ValueCalculator) saveValueAlerts(ctx context.Context, p *valuePipeline) {
+	if c.leader.state == nil {
+		return
+	}
+	data, err := p.alerts.snapshot()
+	if err == nil {
+		err = c.leader.state.SaveCycleState(ctx, cycleStateValueAlerts+p.name, data)
+	}
+	if err != nil {
+		slog.Error("Failed to save value alert state", "pipeline", p.name, "error", err)
+	}
+}
+
+// restoreSteamAlerts loads the steam alert cooldowns saved by the previous leader.
+func (c *ValueCalculator) restoreSteamAlerts(ctx context.Context) {
+	c.steamAlerted = map[string]time.Time{}
+	if c.leader.state == nil {
+		return
+	}
+	data, _, err := c.leader.state.LoadCycleState(ctx, cycleStateSteamAlerts)
+	if err == nil && data != nil {
+		err = json.Unmarshal(data, &c.steamAlerted)
+	}
+	if err != nil {
+		slog.Error("Failed to restore steam alert state", "error", err)
+		c.steamAlerted = map[string]time.Time{}
+	}
+}
+
+// saveSteamAlerts saves the steam alert cooldowns for the next leader.
+func (c *ValueCalculator) saveSteamAlerts(ctx context.Context) {
+	if c.leader.state == nil {
+		return
+	}
+	data, err := json.Marshal(c.steamAlerted)
+	if err == nil {
+		err = c.leader.state.SaveCycleState(ctx, cycleStateSteamAlerts, data)
+	}
+	if err != nil {
+		slog.Error("Failed to save steam alert state", "error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+type fakeLeaderLock struct{ free bool }
+
+func (l *fakeLeaderLock) TryAcquire(context.Context) (bool, error) { return l.free, nil }
+func (l *fakeLeaderLock) Release(context.Context) error            { return nil }
+func (l *fakeLeaderLock) Close() error                             { return nil }
+
+type fakeCycleState struct {
+	data    map[string][]byte
+	savedAt time.Time
+}
+
+func (s *fakeCycleState) SaveCycleState(_ context.Context, name string, data []byte) error {
+	s.data[name] = data
+	return nil
+}
+
+func (s *fakeCycleState) LoadCycleState(_ context.Context, name string) ([]byte, time.Time, error) {
+	return s.data[name], s.savedAt, nil
+}
+
+func (s *fakeCycleState) Close() error { return nil }
+
+func TestLeaderTakeoverRestoresAlertState(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	state := &fakeCycleState{data: map[string][]byte{}, savedAt: now}
+	diff := &DiffBet{MatchGroupKey: "football|arsenal|chelsea", BetKey: "main_match|home_win|", MaxBookmaker: "Fonbet",
+		DiffPercent: 12, StartTime: now.Add(3 * time.Hour)}
+
+	// The leader alerts a value and saves its state at the end of the cycle
+	old := NewValueCalculator(&config.ValueCalculatorConfig{}, nil, nil)
+	old.SetLeaderElection(&fakeLeaderLock{free: true}, state)
+	old.campaign(ctx)
+	if run, restore := old.leaderCycle(&old.leader.mainTerm); !run || !restore {
+		t.Fatalf("first cycle of the leader: run=%v restore=%v", run, restore)
+	}
+	old.mainPipeline.alerts.alerted(42, diff, now)
+	old.mainPipeline.alerts.finishCycle(map[string]bool{valueAlertStateKey(42, diff): true}, nil, now)
+	old.steamAlerted["42|steam"] = now
+	old.saveValueAlerts(ctx, old.mainPipeline)
+	old.saveSteamAlerts(ctx)
+
+	// A follower doesn't run the cycle
+	lock := &fakeLeaderLock{}
+	next := NewValueCalculator(&config.ValueCalculatorConfig{}, nil, nil)
+	next.SetLeaderElection(lock, state)
+	next.campaign(ctx)
+	if run, _ := next.leaderCycle(&next.leader.mainTerm); run || next.isLeader() {
+		t.Fatal("follower must not run the async cycle")
+	}
+
+	// It takes over and continues the saved state: no second "new" alert
+	lock.free = true
+	next.campaign(ctx)
+	run, restore := next.leaderCycle(&next.leader.mainTerm)
+	if !run || !restore {
+		t.Fatalf("first cycle after takeover: run=%v restore=%v", run, restore)
+	}
+	next.restoreValueAlerts(ctx, next.mainPipeline)
+	next.restoreSteamAlerts(ctx)
+	if kind, _ := next.mainPipeline.alerts.check(42, diff, now.Add(time.Minute)); kind != valueAlertNone {
+		t.Errorf("value alerted by the previous leader alerted again: %q", kind)
+	}
+	if next.mainPipeline.alerts.firstCycle() {
+		t.Error("restored state should not fall back to the diff storage restore")
+	}
+	if _, ok := next.steamAlerted["42|steam"]; !ok {
+		t.Errorf("steam alert cooldowns not restored: %v", next.steamAlerted)
+	}
+	if _, restore := next.leaderCycle(&next.leader.mainTerm); restore {
+		t.Error("state is restored once per term")
+	}
+
+	// State older than the cooldown is dropped
+	state.savedAt = now.Add(-2 * defaultAlertCooldown)
+	next.restoreValueAlerts(ctx, next.mainPipeline)
+	if !next.mainPipeline.alerts.firstCycle() {
+		t.Error("stale state should reset the tracker")
+	}
+}
@@ -0,0 +1,230 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// canonicalizeTournaments replaces the tournament names of the bookmakers with the canonical ones
+// (models.CanonicalLeagueName), so filters, value bet groups and alerts see one name per league.
+func canonicalizeTournaments(matches []models.Match) []models.Match {
+	for i := range matches {
+		if t := strings.TrimSpace(matches[i].Tournament); t != "" {
+			matches[i].Tournament = models.CanonicalLeagueName(t)
+		}
+	}
+	return matches
+}
+
+// leagueFilterAccepts reports whether tournament contains the ?league= / subscription filter (lower case),
+// as typed or as the canonical name of an alias: "epl" finds "England. Premier League".
+func leagueFilterAccepts(tournament, filter string) bool {
+	tournament = strings.ToLower(tournament)
+	if strings.Contains(tournament, filter) {
+		return true
+	}
+	canonical := strings.ToLower(models.CanonicalLeagueName(filter))
+	return canonical != filter && strings.Contains(tournament, canonical)
+}
+
+// SetLeagueAliasStorage persists the tournament aliases added through the API to store and loads its
+// aliases into the dictionary (models.AddLeagueAlias).
+func (c *ValueCalculator) SetLeagueAliasStorage(ctx context.Context, store storage.LeagueAliasStorage) error {
+	aliases, err := store.GetLeagueAliases(ctx)
+	if err != nil {
+		return err
+	}
+	c.leagueAliases = store
+	for _, a := range aliases {
+		if err := models.AddLeagueAlias(a.Alias, a.Canonical); err != nil {
+			slog.Warn("Skipping invalid stored league alias", "alias", a.Alias, "canonical", a.Canonical, "error", err)
+		}
+	}
+	slog.Info("League aliases loaded", "stored", len(aliases), "total", len(models.LeagueAliases()))
+	return nil
+}
+
+// handleLeagueAliases lists the tournament alias dictionary. GET /league-aliases[?q=premier]
+func (c *ValueCalculator) handleLeagueAliases(w http.ResponseWriter, r *http.Request) {
+	q := models.AliasKey(r.URL.Query().Get("q"))
+	aliases := models.LeagueAliases()
+	if q != "" {
+		filtered := aliases[:0]
+		for _, a := range aliases {
+			if strings.Contains(models.AliasKey(a.Alias), q) || strings.Contains(models.AliasKey(a.Canonical), q) {
+				filtered = append(filtered, a)
+			}
+		}
+		aliases = filtered
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"aliases": aliases, "count": len(aliases)})
+}
+
+// handleAdminLeagueAliases changes the tournament dictionary: POST adds an alias (body as for
+// /admin/team-aliases), DELETE ?alias=... removes an added one.
+func (c *ValueCalculator) handleAdminLeagueAliases(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req teamAliasRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		entry := storage.LeagueAlias{Alias: strings.TrimSpace(req.Alias), Canonical: strings.TrimSpace(req.Canonical), CreatedAt: time.Now().UTC()}
+		if err := models.AddLeagueAlias(entry.Alias, entry.Canonical); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if c.leagueAliases != nil {
+			if err := c.leagueAliases.StoreLeagueAlias(r.Context(), entry); err != nil {
+				slog.Error("Failed to store league alias", "alias", entry.Alias, "error", err)
+				writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store league alias", "details": err.Error()})
+				return
+			}
+		}
+		slog.Info("League alias added", "alias", entry.Alias, "canonical", entry.Canonical)
+		writeWebAppJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		alias := strings.TrimSpace(r.URL.Query().Get("alias"))
+		if alias == "" {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "alias is required"})
+			return
+		}
+		if c.leagueAliases != nil {
+			if err := c.leagueAliases.DeleteLeagueAlias(r.Context(), alias); err != nil {
+				writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete league alias", "details": err.Error()})
+				return
+			}
+		}
+		removed := models.RemoveLeagueAlias(alias)
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"alias": alias, "removed": removed})
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use POST or DELETE"})
+	}
+}
+
+// unmatchedLeague is a set of tournament names the bookmakers use for the same fixtures: one of them is
+// probably missing an alias.
+type unmatchedLeague struct {
+	Sport       string   `json:"sport"`
+	Tournaments []string `json:"tournaments"`
+	Bookmakers  []string `json:"bookmakers"`
+	Matches     int      `json:"matches"`
+	Example     string   `json:"example"` // one of the fixtures
+}
+
+// unmatchedLeagues finds the match groups whose bookmakers name the tournament differently (after
+// aliases), one entry per set of names, most matches first.
+func unmatchedLeagues(matches []models.Match) []unmatchedLeague {
+	type group struct {
+		m           models.Match
+		tournaments map[string]string // alias key -> name
+		bookmakers  map[string]bool
+	}
+	groups := map[string]*group{}
+	for _, m := range matches {
+		t, gk := strings.TrimSpace(m.Tournament), matchGroupKey(m)
+		if t == "" || gk == "" {
+			continue
+		}
+		g := groups[gk]
+		if g == nil {
+			g = &group{m: m, tournaments: map[string]string{}, bookmakers: map[string]bool{}}
+			groups[gk] = g
+		}
+		g.tournaments[models.AliasKey(t)] = t
+		if bk := matchBookmaker(m); bk != "" {
+			g.bookmakers[bk] = true
+		}
+	}
+
+	bySet := map[string]*unmatchedLeague{}
+	bookmakers := map[string]map[string]bool{} // set key -> bookmakers
+	for _, g := range groups {
+		if len(g.tournaments) < 2 {
+			continue
+		}
+		keys := make([]string, 0, len(g.tournaments))
+		for k := range g.tournaments {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		setKey := matchSport(g.m) + "|" + strings.Join(keys, "|")
+		u := bySet[setKey]
+		if u == nil {
+			u = &unmatchedLeague{Sport: matchSport(g.m), Example: strings.TrimSpace(g.m.HomeTeam) + " vs " + strings.TrimSpace(g.m.AwayTeam)}
+			for _, k := range keys {
+				u.Tournaments = append(u.Tournaments, g.tournaments[k])
+			}
+			bySet[setKey] = u
+			bookmakers[setKey] = map[string]bool{}
+		}
+		u.Matches++
+		for bk := range g.bookmakers {
+			bookmakers[setKey][bk] = true
+		}
+	}
+
+	out := make([]unmatchedLeague, 0, len(bySet))
+	for setKey, u := range bySet {
+		for bk := range bookmakers[setKey] {
+			u.Bookmakers = append(u.Bookmakers, bk)
+		}
+		sort.Strings(u.Bookmakers)
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Matches != out[j].Matches {
+			return out[i].Matches > out[j].Matches
+		}
+		return out[i].Tournaments[0] < out[j].Tournaments[0]
+	})
+	return out
+}
+
+// handleUnmatchedLeagues reports the tournament names likely missing an alias in current matches.
+// GET /league-aliases/unmatched[?sport=football][&limit=100]
+func (c *ValueCalculator) handleUnmatchedLeagues(w http.ResponseWriter, r *http.Request) {
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+	limit := defaultUnmatchedLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	sport := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sport")))
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleUnmatchedLeagues", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+	leagues := unmatchedLeagues(matches)
+	if sport != "" {
+		filtered := leagues[:0]
+		for _, l := range leagues {
+			if l.Sport == sport {
+				filtered = append(filtered, l)
+			}
+		}
+		leagues = filtered
+	}
+	total := len(leagues)
+	if len(leagues) > limit {
+		leagues = leagues[:limit]
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"leagues": leagues, "count": len(leagues), "total": total})
+}
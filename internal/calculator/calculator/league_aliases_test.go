@@ -0,0 +1,46 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestCanonicalizeTournaments(t *testing.T) {
+	matches := canonicalizeTournaments([]models.Match{{Tournament: "Англия. Премьер-лига"}, {Tournament: "EPL"}, {Tournament: ""}})
+	if matches[0].Tournament != "England. Premier League" || matches[1].Tournament != "England. Premier League" || matches[2].Tournament != "" {
+		t.Errorf("tournaments = %q, %q, %q", matches[0].Tournament, matches[1].Tournament, matches[2].Tournament)
+	}
+
+	for _, tt := range []struct {
+		filter string
+		want   bool
+	}{{"premier", true}, {"epl", true}, {"апл", true}, {"la liga", false}} {
+		if got := leagueFilterAccepts("England. Premier League", tt.filter); got != tt.want {
+			t.Errorf("leagueFilterAccepts(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestUnmatchedLeagues(t *testing.T) {
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	match := func(bookmaker, tournament, home string) models.Match {
+		return models.Match{HomeTeam: home, AwayTeam: "Celtic", Sport: "football", Bookmaker: bookmaker, Tournament: tournament, StartTime: start}
+	}
+	matches := canonicalizeTournaments([]models.Match{
+		match("Pinnacle", "Scotland - Premiership", "Rangers"),
+		match("Fonbet", "Шотландия. Премьершип", "Rangers"),
+		match("Pinnacle", "Scotland - Premiership", "Hearts"),
+		match("Fonbet", "Шотландия. Премьершип", "Hearts"),
+		match("Pinnacle", "EPL", "Arsenal"),
+		match("Fonbet", "Англия. Премьер-лига", "Arsenal"), // same league after aliases
+	})
+	got := unmatchedLeagues(matches)
+	if len(got) != 1 {
+		t.Fatalf("unmatched = %+v, want one", got)
+	}
+	if got[0].Matches != 2 || len(got[0].Tournaments) != 2 || len(got[0].Bookmakers) != 2 {
+		t.Errorf("unmatched = %+v", got[0])
+	}
+}
@@ -7,16 +7,58 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
 )
 
+// lineMovementThreshold is the min drop that makes a line movement, in % of the odd
+// (line_movement_alert_threshold) and in implied probability points (line_movement_alert_threshold_pp).
+// Reaching either is enough; a zero value disables that unit. Percent exaggerates moves on high odds
+// (10→8 is 20% but only 2.5 pp), so pp suits mixed-odds markets better.
+type lineMovementThreshold struct {
+	Percent float64
+	PP      float64
+}
+
+func lineMovementThresholdFromConfig(cfg *config.ValueCalculatorConfig) lineMovementThreshold {
+	if cfg == nil {
+		return lineMovementThreshold{}
+	}
+	return lineMovementThreshold{Percent: max(cfg.LineMovementAlertThreshold, 0), PP: max(cfg.LineMovementAlertThresholdPP, 0)}
+}
+
+func (t lineMovementThreshold) enabled() bool {
+	return t.Percent > 0 || t.PP > 0
+}
+
+// reached reports whether a drop from prev to cur meets the threshold in either unit.
+func (t lineMovementThreshold) reached(prev, cur float64) bool {
+	if t.Percent > 0 && -models.OddsChangePercent(prev, cur) >= t.Percent {
+		return true
+	}
+	return t.PP > 0 && models.ProbShiftPP(prev, cur) >= t.PP
+}
+
+// String formats the threshold for alerts: "≥20.0%", "≥3.0 pp" or "≥20.0% or ≥3.0 pp".
+func (t lineMovementThreshold) String() string {
+	var parts []string
+	if t.Percent > 0 {
+		parts = append(parts, fmt.Sprintf("≥%.1f%%", t.Percent))
+	}
+	if t.PP > 0 {
+		parts = append(parts, fmt.Sprintf("≥%.1f pp", t.PP))
+	}
+	return strings.Join(parts, " or ")
+}
+
 // computeAndStoreLineMovements builds current odds per (match, bet, bookmaker), compares current
 // with stored max_odd and min_odd (so gradual moves like 4.15→4.0→3.45 are caught as 4.15→3.45),
-// stores current snapshot (updating max/min), and returns line movements. Threshold is in percent
-// (e.g. 5.0 = 5%) so 1.9→1.5 (~21%) matters more than 9.5→9.1 (~4%).
-func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, snapshotStorage storage.OddsSnapshotStorage, thresholdPercent float64) ([]LineMovement, error) {
-	if snapshotStorage == nil || thresholdPercent <= 0 {
+// stores current snapshot (updating max/min), and returns line movements that reach threshold
+// (in percent, 1.9→1.5 ~21% matters more than 9.5→9.1 ~4%, and/or in probability points).
+// History gets the changed odds only; with keyframe every unchanged odd is written too, flagged as a keyframe.
+func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, snapshotStorage storage.OddsSnapshotStorage, threshold lineMovementThreshold, keyframe bool) ([]LineMovement, error) {
+	if snapshotStorage == nil || !threshold.enabled() {
 		return nil, nil
 	}
 
@@ -26,6 +68,7 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 	// matchGroupKey -> betKey -> bookmaker -> odd
 	type betMap map[string]map[string]float64
 	groups := map[string]betMap{}
+	urls := map[string]string{} // matchGroupKey|betKey|bookmaker -> bookmaker page of the kept odd
 	type groupMeta struct {
 		name       string
 		startTime  time.Time
@@ -70,16 +113,16 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 				}
 				eventType := strings.TrimSpace(ev.EventType)
 				outcomeType := strings.TrimSpace(out.OutcomeType)
-				param := strings.TrimSpace(out.Parameter)
 				if eventType == "" || outcomeType == "" {
 					continue
 				}
-				betKey := eventType + "|" + outcomeType + "|" + param
+				betKey := models.OutcomeBetKey(ev, out).String()
 				if _, ok := groups[gk][betKey]; !ok {
 					groups[gk][betKey] = map[string]float64{}
 				}
 				if prev, ok := groups[gk][betKey][bk]; !ok || odd > prev {
 					groups[gk][betKey][bk] = odd
+					urls[gk+"|"+betKey+"|"+bk] = m.OutcomeURL(out)
 				}
 			}
 		}
@@ -114,17 +157,7 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 	for gk, bets := range groups {
 		gm := meta[gk]
 		for betKey, byBook := range bets {
-			parts := strings.SplitN(betKey, "|", 3)
-			evType, outType, param := "", "", ""
-			if len(parts) >= 1 {
-				evType = parts[0]
-			}
-			if len(parts) >= 2 {
-				outType = parts[1]
-			}
-			if len(parts) >= 3 {
-				param = parts[2]
-			}
+			evType, outType, param := models.SplitBetKey(betKey)
 
 			for bookmaker, currentOdd := range byBook {
 				key := storage.OddsSnapshotKey{MatchGroupKey: gk, BetKey: betKey, Bookmaker: bookmaker}
@@ -134,11 +167,10 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 					maxOdd = row.MaxOdd
 				}
 
-				// Compare with extremes in percent: (current - ref) / ref * 100
+				// Compare with extremes in percent and probability points
 				// Only track drops (falling odds), not rises
-				if maxOdd > 0 && currentOdd < maxOdd {
-					dropPercent := (maxOdd - currentOdd) / maxOdd * 100
-					if dropPercent >= thresholdPercent {
+				if maxOdd > 0 && currentOdd < maxOdd && !models.OddsEqual(currentOdd, maxOdd) {
+					if threshold.reached(maxOdd, currentOdd) {
 						changeAbs := currentOdd - maxOdd
 						movements = append(movements, LineMovement{
 							MatchGroupKey:   gk,
@@ -151,10 +183,12 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 							Parameter:       param,
 							BetKey:          betKey,
 							Bookmaker:       bookmaker,
+							BookmakerURL:    urls[gk+"|"+betKey+"|"+bookmaker],
 							PreviousOdd:     maxOdd,
 							CurrentOdd:      currentOdd,
 							ChangeAbs:       changeAbs,
-							ChangePercent:   changeAbs / maxOdd * 100,
+							ChangePercent:   models.OddsChangePercent(maxOdd, currentOdd),
+							ProbShiftPP:     models.ProbShiftPP(maxOdd, currentOdd),
 							RecordedAt:      now,
 						})
 					}
@@ -174,6 +208,11 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 					Odd:           currentOdd,
 					RecordedAt:    now,
 				})
+				// History gets a point only when the odd moved beyond the odds epsilon (1.952 after 1.95 is not a change)
+				unchanged := ok && models.OddsEqual(row.Odd, currentOdd)
+				if unchanged && !keyframe {
+					continue
+				}
 				historyToAppend = append(historyToAppend, storage.OddsHistoryToAppend{
 					MatchGroupKey: gk,
 					BetKey:        betKey,
@@ -181,6 +220,7 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 					StartTime:     gm.startTime,
 					Odd:           currentOdd,
 					RecordedAt:    now,
+					Keyframe:      unchanged,
 				})
 			}
 		}
@@ -219,7 +259,7 @@ func computeAndStoreLineMovements(ctx context.Context, matches []models.Match, s
 }
 
 // getLineMovementsForTop returns line movements for current odds vs stored snapshots (read-only, no store).
-// Used by API to return top N "прогрузов" sorted by change percent.
+// Used by API to return top N "прогрузов" sorted by change percent or probability shift.
 func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapshotStorage storage.OddsSnapshotStorage) ([]LineMovement, error) {
 	if snapshotStorage == nil {
 		return nil, nil
@@ -230,6 +270,7 @@ func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapsho
 	// matchGroupKey -> betKey -> bookmaker -> odd (same structure as computeAndStoreLineMovements)
 	type betMap map[string]map[string]float64
 	groups := map[string]betMap{}
+	urls := map[string]string{} // matchGroupKey|betKey|bookmaker -> bookmaker page of the kept odd
 	type groupMeta struct {
 		name       string
 		startTime  time.Time
@@ -274,16 +315,16 @@ func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapsho
 				}
 				eventType := strings.TrimSpace(ev.EventType)
 				outcomeType := strings.TrimSpace(out.OutcomeType)
-				param := strings.TrimSpace(out.Parameter)
 				if eventType == "" || outcomeType == "" {
 					continue
 				}
-				betKey := eventType + "|" + outcomeType + "|" + param
+				betKey := models.OutcomeBetKey(ev, out).String()
 				if _, ok := groups[gk][betKey]; !ok {
 					groups[gk][betKey] = map[string]float64{}
 				}
 				if prev, ok := groups[gk][betKey][bk]; !ok || odd > prev {
 					groups[gk][betKey][bk] = odd
+					urls[gk+"|"+betKey+"|"+bk] = m.OutcomeURL(out)
 				}
 			}
 		}
@@ -307,17 +348,7 @@ func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapsho
 	for gk, bets := range groups {
 		gm := meta[gk]
 		for betKey, byBook := range bets {
-			parts := strings.SplitN(betKey, "|", 3)
-			evType, outType, param := "", "", ""
-			if len(parts) >= 1 {
-				evType = parts[0]
-			}
-			if len(parts) >= 2 {
-				outType = parts[1]
-			}
-			if len(parts) >= 3 {
-				param = parts[2]
-			}
+			evType, outType, param := models.SplitBetKey(betKey)
 
 			for bookmaker, currentOdd := range byBook {
 				key := storage.OddsSnapshotKey{MatchGroupKey: gk, BetKey: betKey, Bookmaker: bookmaker}
@@ -328,9 +359,8 @@ func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapsho
 				maxOdd := row.MaxOdd
 
 				// Only track drops (falling odds), not rises
-				if maxOdd > 0 && currentOdd < maxOdd {
+				if maxOdd > 0 && currentOdd < maxOdd && !models.OddsEqual(currentOdd, maxOdd) {
 					changeAbs := currentOdd - maxOdd
-					changePercent := changeAbs / maxOdd * 100
 					movements = append(movements, LineMovement{
 						MatchGroupKey:   gk,
 						MatchName:       gm.name,
@@ -342,10 +372,12 @@ func getLineMovementsForTop(ctx context.Context, matches []models.Match, snapsho
 						Parameter:       param,
 						BetKey:          betKey,
 						Bookmaker:       bookmaker,
+						BookmakerURL:    urls[gk+"|"+betKey+"|"+bookmaker],
 						PreviousOdd:     maxOdd,
 						CurrentOdd:      currentOdd,
 						ChangeAbs:       changeAbs,
-						ChangePercent:   changePercent,
+						ChangePercent:   models.OddsChangePercent(maxOdd, currentOdd),
+						ProbShiftPP:     models.ProbShiftPP(maxOdd, currentOdd),
 						RecordedAt:      now,
 					})
 				}
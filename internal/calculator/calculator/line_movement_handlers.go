@@ -4,22 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"math"
 	"net/http"
-	"sort"
 	"strconv"
 	"time"
 )
 
 // handleTopLineMovements returns top line movements (прогрузы) — largest odds changes in the same bookmaker.
+// ?unit=pp ranks them by implied probability shift instead of percent change (same as ?sort=prob_shift_pp).
 func (c *ValueCalculator) handleTopLineMovements(w http.ResponseWriter, r *http.Request) {
-	limit := 10
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			if n > 50 {
-				n = 50
-			}
-			limit = n
-		}
+	defaultSort := "change_percent"
+	if r.URL.Query().Get("unit") == "pp" {
+		defaultSort = "prob_shift_pp"
+	}
+	// Page, sort and filters: ?limit=&cursor=&sort=&order=&sport=&bookmaker=&league=&min_odds=&max_odds=
+	lq, err := parseListQuery(r, 10, lineMovementSorts, defaultSort)
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
 	}
 
 	if c.httpClient == nil {
@@ -56,37 +58,95 @@ func (c *ValueCalculator) handleTopLineMovements(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Exclude movements where current odds > 8 (high odds прогрузы not needed)
+	page, next, total := pageLineMovements(movements, lq)
+	setPageHeaders(w, total, next)
+	writeWebAppJSON(w, http.StatusOK, page)
+}
+
+// pageLineMovements returns the page of lq of movements, the cursor of the next page and the number passing
+// the filters (GET /line-movements/top, gRPC ListLineMovements).
+func pageLineMovements(movements []LineMovement, lq listQuery) ([]LineMovement, string, int) {
+	// Exclude movements where current odds > 8 (high odds прогрузы not needed), then apply the filters
 	const maxCurrentOdd = 8.0
 	filtered := movements[:0]
 	for _, m := range movements {
-		if m.CurrentOdd <= maxCurrentOdd {
+		if m.CurrentOdd > maxCurrentOdd {
+			continue
+		}
+		if lq.sport != "" && !sportMatchesFilter(m.Sport, lq.sport) {
+			continue
+		}
+		if lq.accepts(m.Bookmaker, m.Tournament, m.CurrentOdd) {
 			filtered = append(filtered, m)
 		}
 	}
-	movements = filtered
 
-	// Sort by absolute change percent descending (largest movements first)
-	sort.Slice(movements, func(i, j int) bool {
-		absI := movements[i].ChangePercent
-		if absI < 0 {
-			absI = -absI
-		}
-		absJ := movements[j].ChangePercent
-		if absJ < 0 {
-			absJ = -absJ
+	// Largest absolute movements first unless ?sort= / ?order= say otherwise
+	page, next := pageList(filtered, lq, lineMovementSortKey(lq.sort))
+	return page, next, len(filtered)
+}
+
+// lineMovementSorts are the ?sort= keys of /line-movements/top and whether they order descending by default.
+// change_percent and prob_shift_pp rank by absolute value.
+var lineMovementSorts = map[string]bool{"change_percent": true, "prob_shift_pp": true, "start_time": false}
+
+// lineMovementSortKey returns the sort value and id of a line movement for ?sort=.
+func lineMovementSortKey(key string) func(*LineMovement) (float64, string) {
+	return func(lm *LineMovement) (float64, string) {
+		id := valueBetID(lm.MatchGroupKey, lm.BetKey, lm.Bookmaker)
+		switch key {
+		case "prob_shift_pp":
+			return math.Abs(lm.ProbShiftPP), id
+		case "start_time":
+			return float64(lm.StartTime.Unix()), id
 		}
-		return absI > absJ
-	})
+		return math.Abs(lm.ChangePercent), id
+	}
+}
+
+// handleSteamLineMovements returns steam moves: the same outcome moving the same way at several bookmakers
+// within the window, strongest first. GET /line-movements/steam?limit=20&window=10m&min_bookmakers=2&min_shift_pp=2
+// (defaults from value_calculator.steam).
+func (c *ValueCalculator) handleSteamLineMovements(w http.ResponseWriter, r *http.Request) {
+	if c.oddsSnapshotStorage == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "line movement storage is not configured (enable line_movement_enabled)"})
+		return
+	}
 
-	if limit > len(movements) {
-		limit = len(movements)
+	q := r.URL.Query()
+	limit := 20
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+		limit = min(n, 100)
+	}
+	params := steamParamsFromConfig(c.cfg)
+	if d, err := time.ParseDuration(q.Get("window")); err == nil && d > 0 {
+		params.window = d
+	}
+	if n, err := strconv.Atoi(q.Get("min_bookmakers")); err == nil && n > 0 {
+		params.minBookmakers = n
+	}
+	if v, err := strconv.ParseFloat(q.Get("min_shift_pp"), 64); err == nil && v > 0 {
+		params.minShiftPP = v
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if len(movements) > 0 {
-		_ = json.NewEncoder(w).Encode(movements[:limit])
-	} else {
-		_ = json.NewEncoder(w).Encode([]LineMovement{})
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	moves, err := c.detectSteamMoves(ctx, time.Now(), params)
+	if err != nil {
+		slog.Error("detectSteamMoves failed", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to detect steam", "details": err.Error()})
+		return
+	}
+	if len(moves) > limit {
+		moves = moves[:limit]
 	}
+	if moves == nil {
+		moves = []SteamMove{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(moves)
 }
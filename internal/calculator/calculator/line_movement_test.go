@@ -0,0 +1,136 @@
+package calculator
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestLineMovementThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold lineMovementThreshold
+		prev, cur float64
+		want      bool
+	}{
+		{"percent reached", lineMovementThreshold{Percent: 15}, 10, 8, true}, // -20%
+		{"percent not reached", lineMovementThreshold{Percent: 15}, 2.0, 1.8, false},
+		{"pp not reached on high odd", lineMovementThreshold{PP: 5}, 10, 8, false}, // +2.5 pp
+		{"pp reached on low odd", lineMovementThreshold{PP: 5}, 2.0, 1.8, true},    // +5.56 pp
+		{"either unit", lineMovementThreshold{Percent: 15, PP: 5}, 2.0, 1.8, true},
+		{"rise is not a drop", lineMovementThreshold{Percent: 5, PP: 1}, 1.8, 2.0, false},
+		{"disabled", lineMovementThreshold{}, 10, 2, false},
+	}
+	for _, tt := range tests {
+		if got := tt.threshold.reached(tt.prev, tt.cur); got != tt.want {
+			t.Errorf("%s: reached(%v, %v) = %v, want %v", tt.name, tt.prev, tt.cur, got, tt.want)
+		}
+	}
+
+	th := lineMovementThresholdFromConfig(&config.ValueCalculatorConfig{LineMovementAlertThreshold: 20, LineMovementAlertThresholdPP: 3})
+	if got := th.String(); got != "≥20.0% or ≥3.0 pp" {
+		t.Errorf("String() = %q", got)
+	}
+	if !th.enabled() || lineMovementThresholdFromConfig(nil).enabled() {
+		t.Error("threshold from config: wrong enabled state")
+	}
+}
+
+func TestFormatLineMovementAlert_ShowsBothUnits(t *testing.T) {
+	lm := &LineMovement{MatchName: "A vs B", EventType: "main_match", OutcomeType: "home_win", Bookmaker: "fonbet",
+		PreviousOdd: 2.0, CurrentOdd: 1.8, ChangePercent: -10, ProbShiftPP: 5.56}
+	text := (&TelegramNotifier{}).formatLineMovementAlert("", lm, lineMovementThreshold{PP: 5}, time.Now(), nil)
+	if !strings.Contains(text, "≥5.0 pp") || !strings.Contains(text, "-10.0%, +5.6 pp") {
+		t.Fatalf("alert must show the threshold and both units:\n%s", text)
+	}
+}
+
+func TestDetectSteam(t *testing.T) {
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	start := now.Add(3 * time.Hour)
+	var history []storage.OddsHistoryRecord
+	add := func(ago time.Duration, bookmaker, betKey string, odd float64) {
+		history = append(history, storage.OddsHistoryRecord{MatchGroupKey: "football|arsenal|chelsea|x", MatchName: "Arsenal vs Chelsea",
+			Sport: "football", BetKey: betKey, Bookmaker: bookmaker, Odd: odd, StartTime: start, RecordedAt: now.Add(-ago)})
+	}
+	// Home win shortens at Fonbet and Pinnacle within 10 minutes; Leon doesn't move
+	add(2*time.Hour, "Fonbet", "main_match|home_win|", 2.2)
+	add(2*time.Hour, "Pinnacle", "main_match|home_win|", 2.15)
+	add(2*time.Hour, "Leon", "main_match|home_win|", 2.2)
+	add(6*time.Minute, "Fonbet", "main_match|home_win|", 2.0)
+	add(3*time.Minute, "Pinnacle", "main_match|home_win|", 1.95)
+	// Away win: one bookmaker drifts, the other opened 5 minutes ago
+	add(2*time.Hour, "Fonbet", "main_match|away_win|", 3.2)
+	add(4*time.Minute, "Fonbet", "main_match|away_win|", 3.8)
+	add(5*time.Minute, "Pinnacle", "main_match|away_win|", 3.9)
+
+	p := steamParamsFromConfig(nil)
+	moves := detectSteam(history, now, p)
+	if len(moves) != 1 {
+		t.Fatalf("expected one steam move, got %+v", moves)
+	}
+	m := moves[0]
+	if m.BetKey != "main_match|home_win|" || m.Direction != LineClassShortening || m.Bookmakers != 2 || m.MatchName != "Arsenal vs Chelsea" {
+		t.Errorf("unexpected steam: %+v", m)
+	}
+	if math.Abs(m.Consensus-2.0/3) > 1e-9 {
+		t.Errorf("consensus = %.3f, want 2/3 (leon quotes but doesn't move)", m.Consensus)
+	}
+	leg := m.Legs[0]
+	if leg.Bookmaker != "fonbet" || leg.FromOdd != 2.2 || leg.ToOdd != 2.0 || leg.Class != LineClassShortening {
+		t.Errorf("unexpected first leg: %+v", leg)
+	}
+	// Fonbet moved 6 minutes ago: 1/2.0 - 1/2.2 = 4.55 pp within 15 minutes, nothing within 5
+	if v := leg.Velocity["15m"]; math.Abs(v-(1/2.0-1/2.2)*100/15) > 1e-9 {
+		t.Errorf("15m velocity = %.4f pp/min", v)
+	}
+	if v := leg.Velocity["5m"]; v != 0 {
+		t.Errorf("5m velocity = %.4f pp/min, want 0", v)
+	}
+	if v := leg.Velocity["1h"]; math.Abs(v-(1/2.0-1/2.2)*100/60) > 1e-9 {
+		t.Errorf("1h velocity = %.4f pp/min", v)
+	}
+
+	// Three bookmakers required: no steam; a wider window doesn't make Pinnacle's opener a move
+	p.minBookmakers = 3
+	if moves := detectSteam(history, now, p); len(moves) != 0 {
+		t.Errorf("min 3 bookmakers: expected nothing, got %+v", moves)
+	}
+	// Started matches are skipped
+	if moves := detectSteam(history, start.Add(time.Minute), steamParamsFromConfig(nil)); len(moves) != 0 {
+		t.Errorf("started match: expected nothing, got %+v", moves)
+	}
+}
+
+func TestClassifyLine(t *testing.T) {
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	points := func(odds ...float64) []storage.OddsHistoryPoint {
+		out := make([]storage.OddsHistoryPoint, len(odds))
+		for i, o := range odds {
+			out[i] = storage.OddsHistoryPoint{Odd: o, RecordedAt: now.Add(-time.Hour + time.Duration(i)*time.Minute)}
+		}
+		return out
+	}
+	tests := []struct {
+		points []storage.OddsHistoryPoint
+		age    time.Duration
+		want   string
+	}{
+		{points(2.0, 1.8), 30 * time.Minute, LineClassShortening},
+		{points(2.0, 2.3), 30 * time.Minute, LineClassDrifting},
+		{points(2.0, 2.3, 2.0), 30 * time.Minute, LineClassStable},
+		{points(2.0, 1.8), 2 * time.Hour, LineClassOpener},
+	}
+	for _, tt := range tests {
+		if got := classifyLine(tt.points, now, tt.age); got != tt.want {
+			t.Errorf("classifyLine(%v) = %s, want %s", tt.points, got, tt.want)
+		}
+	}
+	if got := durationLabel(90 * time.Second); got != "1m30s" {
+		t.Errorf("durationLabel = %q", got)
+	}
+}
@@ -0,0 +1,189 @@
+package calculator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Paging headers of the list endpoints: the body stays a JSON array for the existing clients.
+const (
+	nextCursorHeader = "X-Next-Cursor" // ?cursor= of the next page, absent on the last one
+	totalCountHeader = "X-Total-Count" // items passing the filters, all pages
+)
+
+// maxListLimit caps ?limit= of /value-bets/top and /line-movements/top; larger lists are paged with ?cursor=.
+const maxListLimit = 50
+
+// listQuery is the paging, sorting and filtering of /value-bets/top and /line-movements/top.
+type listQuery struct {
+	limit  int
+	sort   string
+	desc   bool
+	cursor *listCursor // nil = first page
+
+	sport     string
+	bookmaker map[string]bool // lower-case names, empty = all
+	league    string          // lower-case tournament substring
+	minOdds   float64
+	maxOdds   float64 // 0 = no limit
+}
+
+// listCursor is the position after the last item of a page: its sort value and id. It is opaque to clients
+// (base64 JSON) and only valid with the sort and order it was issued for. Items appearing or disappearing
+// between requests don't shift the following pages.
+type listCursor struct {
+	Sort  string  `json:"s"`
+	Desc  bool    `json:"d"`
+	Value float64 `json:"v"`
+	ID    string  `json:"id"`
+}
+
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(s string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
+
+// parseListQuery reads ?limit= (defaultLimit, at most maxListLimit; invalid values keep the default as before),
+// ?sort= (one of sorts, whose value tells whether it orders descending by default), ?order=asc|desc, ?cursor=,
+// ?sport=, ?bookmaker= (comma-separated), ?league=, ?min_odds= and ?max_odds=.
+func parseListQuery(r *http.Request, defaultLimit int, sorts map[string]bool, defaultSort string) (listQuery, error) {
+	return parseListValues(r.URL.Query(), defaultLimit, sorts, defaultSort)
+}
+
+// parseListValues is parseListQuery on query values (the gRPC list calls pass theirs the same way).
+func parseListValues(q url.Values, defaultLimit int, sorts map[string]bool, defaultSort string) (listQuery, error) {
+	lq := listQuery{limit: defaultLimit, sort: defaultSort, sport: q.Get("sport"), league: strings.ToLower(strings.TrimSpace(q.Get("league")))}
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+		lq.limit = min(n, maxListLimit)
+	}
+
+	if v := q.Get("sort"); v != "" {
+		if _, ok := sorts[v]; !ok {
+			keys := make([]string, 0, len(sorts))
+			for k := range sorts {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return lq, fmt.Errorf("unknown sort %q (want %s)", v, strings.Join(keys, ", "))
+		}
+		lq.sort = v
+	}
+	lq.desc = sorts[lq.sort]
+	switch v := q.Get("order"); v {
+	case "":
+	case "asc", "desc":
+		lq.desc = v == "desc"
+	default:
+		return lq, fmt.Errorf("invalid order %q (want asc or desc)", v)
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		c, err := decodeListCursor(v)
+		if err != nil {
+			return lq, err
+		}
+		if c.Sort != lq.sort || c.Desc != lq.desc {
+			return lq, fmt.Errorf("cursor was issued for another sort or order")
+		}
+		lq.cursor = c
+	}
+
+	if v := q.Get("bookmaker"); v != "" {
+		lq.bookmaker = map[string]bool{}
+		for _, b := range strings.Split(v, ",") {
+			if b = strings.ToLower(strings.TrimSpace(b)); b != "" {
+				lq.bookmaker[b] = true
+			}
+		}
+	}
+	for name, dst := range map[string]*float64{"min_odds": &lq.minOdds, "max_odds": &lq.maxOdds} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || n < 0 {
+				return lq, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = n
+		}
+	}
+	if lq.maxOdds > 0 && lq.minOdds > lq.maxOdds {
+		return lq, fmt.Errorf("min_odds is above max_odds")
+	}
+	return lq, nil
+}
+
+// accepts applies the bookmaker, league and odds filters (?sport= is applied by the callers, which differ
+// on the default).
+func (q listQuery) accepts(bookmaker, league string, odd float64) bool {
+	if len(q.bookmaker) > 0 && !q.bookmaker[strings.ToLower(bookmaker)] {
+		return false
+	}
+	if q.league != "" && !leagueFilterAccepts(league, q.league) {
+		return false
+	}
+	return odd >= q.minOdds && (q.maxOdds <= 0 || odd <= q.maxOdds)
+}
+
+// pageList sorts items by q (ties by id), skips them up to q.cursor and returns one page and the cursor of
+// the next one ("" on the last page). key returns the sort value of an item for q.sort and its id.
+func pageList[T any](items []T, q listQuery, key func(*T) (float64, string)) ([]T, string) {
+	type keyed struct {
+		value float64
+		id    string
+	}
+	keys := make([]keyed, len(items))
+	idx := make([]int, len(items))
+	for i := range items {
+		keys[i].value, keys[i].id = key(&items[i])
+		idx[i] = i
+	}
+	before := func(a, b keyed) bool {
+		if a.value != b.value {
+			return (a.value > b.value) == q.desc
+		}
+		return a.id < b.id
+	}
+	sort.Slice(idx, func(i, j int) bool { return before(keys[idx[i]], keys[idx[j]]) })
+
+	start := 0
+	if q.cursor != nil {
+		at := keyed{value: q.cursor.Value, id: q.cursor.ID}
+		start = sort.Search(len(idx), func(i int) bool { return before(at, keys[idx[i]]) })
+	}
+	end := min(start+q.limit, len(idx))
+	page := make([]T, 0, end-start)
+	for _, i := range idx[start:end] {
+		page = append(page, items[i])
+	}
+	next := ""
+	if end < len(idx) {
+		last := keys[idx[end-1]]
+		next = listCursor{Sort: q.sort, Desc: q.desc, Value: last.value, ID: last.id}.encode()
+	}
+	return page, next
+}
+
+// setPageHeaders sets X-Total-Count and, unless this is the last page, X-Next-Cursor.
+func setPageHeaders(w http.ResponseWriter, total int, next string) {
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if next != "" {
+		w.Header().Set(nextCursorHeader, next)
+	}
+}
@@ -0,0 +1,73 @@
+package calculator
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseListQuery(t *testing.T) {
+	q, err := parseListQuery(httptest.NewRequest("GET", "/value-bets/top?limit=500&sort=start_time&bookmaker=Fonbet,%20pinnacle&league=Premier&min_odds=1.5&max_odds=3", nil),
+		5, valueBetSorts, "value_percent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.limit != maxListLimit || q.sort != "start_time" || q.desc || !q.bookmaker["fonbet"] || !q.bookmaker["pinnacle"] ||
+		q.league != "premier" || q.minOdds != 1.5 || q.maxOdds != 3 {
+		t.Errorf("unexpected query: %+v", q)
+	}
+	if !q.accepts("FONBET", "England. Premier League", 2) || q.accepts("fonbet", "La Liga", 2) || q.accepts("fonbet", "Premier League", 3.5) ||
+		q.accepts("betcity", "Premier League", 2) {
+		t.Error("filters applied wrongly")
+	}
+	if q, _ := parseListQuery(httptest.NewRequest("GET", "/value-bets/top?limit=x", nil), 5, valueBetSorts, "value_percent"); q.limit != 5 || !q.desc {
+		t.Errorf("defaults: %+v", q)
+	}
+
+	cursor := listCursor{Sort: "value_percent", Desc: true, Value: 7, ID: "a"}.encode()
+	for _, qs := range []string{"sort=league", "order=up", "min_odds=x", "min_odds=3&max_odds=2", "cursor=!!", "cursor=" + cursor + "&order=asc"} {
+		if _, err := parseListQuery(httptest.NewRequest("GET", "/value-bets/top?"+qs, nil), 5, valueBetSorts, "value_percent"); err == nil {
+			t.Errorf("%s: expected an error", qs)
+		}
+	}
+}
+
+func TestPageList(t *testing.T) {
+	bets := []ValueBet{
+		{ID: "a", ValuePercent: 6}, {ID: "b", ValuePercent: 9}, {ID: "c", ValuePercent: 6}, {ID: "d", ValuePercent: 12}, {ID: "e", ValuePercent: 5},
+	}
+	q := listQuery{limit: 2, sort: "value_percent", desc: true}
+
+	var got []string
+	for page := 0; ; page++ {
+		items, next := pageList(bets, q, valueBetSortKey(q.sort))
+		for _, vb := range items {
+			got = append(got, vb.ID)
+		}
+		if next == "" {
+			break
+		}
+		if page == 0 {
+			// A value bet appearing before the cursor doesn't shift the next page
+			bets = append(bets, ValueBet{ID: "f", ValuePercent: 20})
+		}
+		c, err := decodeListCursor(next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.cursor = c
+	}
+	want := []string{"d", "b", "a", "c", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("pages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pages = %v, want %v", got, want)
+		}
+	}
+
+	q = listQuery{limit: 10, sort: "value_percent"}
+	if items, next := pageList(bets, q, valueBetSortKey(q.sort)); next != "" || items[0].ID != "e" || items[len(items)-1].ID != "f" {
+		t.Errorf("ascending: %v, next %q", items, next)
+	}
+}
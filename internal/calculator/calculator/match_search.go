@@ -0,0 +1,84 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// searchMatches returns the odds matrices of the match groups whose team names contain every word of query
+// (case-insensitive, club prefixes like "FC" ignored), soonest first, at most limit.
+func searchMatches(matches []models.Match, query string, limit int) []OddsMatrix {
+	words := strings.Fields(normalizeTeam(query))
+	if len(words) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range matches {
+		key := matchGroupKey(m)
+		if seen[key] {
+			continue
+		}
+		teams := normalizeTeam(m.HomeTeam) + " " + normalizeTeam(m.AwayTeam)
+		found := true
+		for _, w := range words {
+			if !strings.Contains(teams, w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	out := make([]OddsMatrix, 0, len(keys))
+	for _, key := range keys {
+		if matrix := computeOddsMatrix(matches, key); matrix != nil && len(matrix.Rows) > 0 {
+			out = append(out, *matrix)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartTime.Before(out[j].StartTime) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// handleMatchSearch finds current matches by team name and returns the odds of all bookmakers for every
+// market of each (same matrix as the WebApp). GET /matches/search?q=arsenal&limit=3
+func (c *ValueCalculator) handleMatchSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len([]rune(query)) < 2 {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "q must be at least 2 characters"})
+		return
+	}
+	limit := 3
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = min(n, 10)
+	}
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleMatchSearch", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+
+	found := searchMatches(matches, query, limit)
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"query": query, "matches": found, "count": len(found)})
+}
@@ -0,0 +1,44 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestSearchMatches(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	match := func(home, away, bookmaker string, start time.Time) models.Match {
+		return models.Match{
+			HomeTeam: home, AwayTeam: away, StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{
+				EventType: "main_match",
+				Outcomes:  []models.Outcome{{OutcomeType: "home_win", Odds: 2.1}},
+			}},
+		}
+	}
+	matches := []models.Match{
+		match("Arsenal", "Chelsea", "Fonbet", start.Add(time.Hour)),
+		match("FC Arsenal", "Chelsea", "Pinnacle", start.Add(time.Hour)),
+		match("Arsenal Tula", "Spartak", "Fonbet", start),
+		match("Liverpool", "Everton", "Fonbet", start),
+	}
+
+	got := searchMatches(matches, "arsenal", 5)
+	if len(got) != 2 || got[0].MatchName != "Arsenal Tula vs Spartak" || got[1].MatchName != "Arsenal vs Chelsea" {
+		t.Fatalf("arsenal: %+v", got)
+	}
+	if len(got[1].Bookmakers) != 2 {
+		t.Errorf("bookmakers of the same match are not merged: %v", got[1].Bookmakers)
+	}
+	if got := searchMatches(matches, "FC ARSENAL chel", 5); len(got) != 1 || got[0].MatchName != "Arsenal vs Chelsea" {
+		t.Errorf("every word must match: %+v", got)
+	}
+	if got := searchMatches(matches, "arsenal", 1); len(got) != 1 {
+		t.Errorf("limit: got %d matches", len(got))
+	}
+	if got := searchMatches(matches, "real", 5); len(got) != 0 {
+		t.Errorf("unknown team: %+v", got)
+	}
+}
@@ -11,6 +11,25 @@ import (
 // matchGroupKey creates a unique key for grouping matches from different bookmakers.
 // Format: "sport|team1|team2|start_time"
 func matchGroupKey(m models.Match) string {
+	fk := fixtureKey(m)
+	if fk == "" {
+		return ""
+	}
+	return matchSport(m) + "|" + fk
+}
+
+// matchSport returns the normalized sport tag used in group keys ("unknown" if empty).
+func matchSport(m models.Match) string {
+	sport := strings.ToLower(strings.TrimSpace(m.Sport))
+	if sport == "" {
+		sport = "unknown"
+	}
+	return sport
+}
+
+// fixtureKey identifies a fixture by teams and start time only (no sport).
+// Format: "team1|team2|start_time" or "team1|team2" when start time is unknown.
+func fixtureKey(m models.Match) string {
 	home := normalizeTeam(m.HomeTeam)
 	away := normalizeTeam(m.AwayTeam)
 	if home == "" || away == "" {
@@ -27,18 +46,12 @@ func matchGroupKey(m models.Match) string {
 		return ""
 	}
 
-	sport := strings.ToLower(strings.TrimSpace(m.Sport))
-	if sport == "" {
-		sport = "unknown"
-	}
-
-	// Time rounding to tolerate small differences between APIs.
-	t := m.StartTime.UTC().Truncate(30 * time.Minute)
-	if t.IsZero() {
+	// Same start time bucket as the match IDs of the parsers (parser.start_time_tolerance)
+	if m.StartTime.IsZero() {
 		// If no start time, group only by teams.
-		return sport + "|" + home + "|" + away
+		return home + "|" + away
 	}
-	return sport + "|" + home + "|" + away + "|" + t.Format(time.RFC3339)
+	return home + "|" + away + "|" + models.StartTimeBucket(m.StartTime).Format(time.RFC3339)
 }
 
 // teamNamePrefixes are stripped for grouping so "RC Hades" and "Hades" match the same match.
@@ -50,8 +63,9 @@ var teamNamePrefixes = []string{
 
 // normalizeTeam normalizes team name for comparison and grouping.
 // Strips common club prefixes (RC, K.S.K., FC, etc.) so "RC Hades" and "Hades" get the same key.
+// Team aliases are resolved first (models.CanonicalTeamName): "Man Utd" groups with "Manchester United".
 func normalizeTeam(s string) string {
-	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ToLower(strings.TrimSpace(models.CanonicalTeamName(s)))
 	if s == "" {
 		return ""
 	}
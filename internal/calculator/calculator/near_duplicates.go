@@ -0,0 +1,153 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// defaultNearDuplicateWindow is the largest start time gap between two groups of the same teams reported by
+// GET /diagnostics/near-duplicates without ?window=: covers a bucket boundary and a time zone off by an hour.
+const defaultNearDuplicateWindow = 2 * time.Hour
+
+// Reasons of a near-duplicate (NearDuplicateGroup.Reason).
+const (
+	nearDuplicateTimeZone     = "time_zone"     // start times differ by whole hours: a parser's time zone or DST
+	nearDuplicateTimeMismatch = "time_mismatch" // start times differ by minutes: a moved kickoff or a bucket boundary
+)
+
+// nearDuplicateEntry is one match group of a near-duplicate.
+type nearDuplicateEntry struct {
+	MatchGroupKey string    `json:"match_group_key"`
+	StartTime     time.Time `json:"start_time"`
+	Bookmakers    []string  `json:"bookmakers"`
+}
+
+// NearDuplicateGroup is a fixture split into several match groups because the bookmakers report start
+// times that fall into different buckets: its value bets and arbitrages are computed per group.
+type NearDuplicateGroup struct {
+	Sport    string               `json:"sport"`
+	HomeTeam string               `json:"home_team"`
+	AwayTeam string               `json:"away_team"`
+	Gap      string               `json:"gap"` // between the earliest and the latest start time
+	Reason   string               `json:"reason"`
+	Groups   []nearDuplicateEntry `json:"groups"`
+}
+
+// findNearDuplicateGroups finds the fixtures (sport and normalized teams) present in several match groups
+// whose start times are at most window apart, largest gap first.
+func findNearDuplicateGroups(matches []models.Match, window time.Duration) []NearDuplicateGroup {
+	type fixture struct {
+		sport      string
+		home, away string
+		groups     map[string]*nearDuplicateEntry
+		bookmakers map[string]map[string]bool // group key -> bookmakers
+	}
+	fixtures := map[string]*fixture{}
+	for _, m := range matches {
+		gk := matchGroupKey(m)
+		home, away := normalizeTeam(m.HomeTeam), normalizeTeam(m.AwayTeam)
+		if gk == "" || home == "" || away == "" || m.StartTime.IsZero() {
+			continue
+		}
+		key := matchSport(m) + "|" + home + "|" + away
+		f := fixtures[key]
+		if f == nil {
+			f = &fixture{sport: matchSport(m), home: strings.TrimSpace(m.HomeTeam), away: strings.TrimSpace(m.AwayTeam),
+				groups: map[string]*nearDuplicateEntry{}, bookmakers: map[string]map[string]bool{}}
+			fixtures[key] = f
+		}
+		if f.groups[gk] == nil {
+			f.groups[gk] = &nearDuplicateEntry{MatchGroupKey: gk, StartTime: m.StartTime.UTC()}
+			f.bookmakers[gk] = map[string]bool{}
+		}
+		if bk := matchBookmaker(m); bk != "" {
+			f.bookmakers[gk][bk] = true
+		}
+	}
+
+	var out []NearDuplicateGroup
+	for _, f := range fixtures {
+		if len(f.groups) < 2 {
+			continue
+		}
+		entries := make([]nearDuplicateEntry, 0, len(f.groups))
+		for gk, e := range f.groups {
+			for bk := range f.bookmakers[gk] {
+				e.Bookmakers = append(e.Bookmakers, bk)
+			}
+			sort.Strings(e.Bookmakers)
+			entries = append(entries, *e)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].StartTime.Before(entries[j].StartTime) })
+		gap := entries[len(entries)-1].StartTime.Sub(entries[0].StartTime)
+		if gap > window {
+			continue
+		}
+		reason := nearDuplicateTimeMismatch
+		if gap >= time.Hour && gap%time.Hour == 0 {
+			reason = nearDuplicateTimeZone
+		}
+		out = append(out, NearDuplicateGroup{
+			Sport:    f.sport,
+			HomeTeam: f.home,
+			AwayTeam: f.away,
+			Gap:      gap.String(),
+			Reason:   reason,
+			Groups:   entries,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		gi := out[i].Groups[len(out[i].Groups)-1].StartTime.Sub(out[i].Groups[0].StartTime)
+		gj := out[j].Groups[len(out[j].Groups)-1].StartTime.Sub(out[j].Groups[0].StartTime)
+		if gi != gj {
+			return gi > gj
+		}
+		return out[i].HomeTeam < out[j].HomeTeam
+	})
+	return out
+}
+
+// handleNearDuplicates reports the fixtures split into several match groups by their start times.
+// GET /diagnostics/near-duplicates[?window=2h][&sport=football]
+func (c *ValueCalculator) handleNearDuplicates(w http.ResponseWriter, r *http.Request) {
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+	window := defaultNearDuplicateWindow
+	if s := r.URL.Query().Get("window"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid window, e.g. 2h"})
+			return
+		}
+		window = d
+	}
+	sport := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sport")))
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleNearDuplicates", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+	found := findNearDuplicateGroups(matches, window)
+	if sport != "" {
+		filtered := found[:0]
+		for _, d := range found {
+			if d.Sport == sport {
+				filtered = append(filtered, d)
+			}
+		}
+		found = filtered
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"near_duplicates": found, "count": len(found), "window": window.String()})
+}
@@ -0,0 +1,36 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestFindNearDuplicateGroups(t *testing.T) {
+	start := time.Date(2026, 10, 25, 18, 0, 0, 0, time.UTC)
+	match := func(bookmaker, home, away string, at time.Time) models.Match {
+		return models.Match{HomeTeam: home, AwayTeam: away, Sport: "football", Bookmaker: bookmaker, StartTime: at}
+	}
+	matches := []models.Match{
+		match("Pinnacle", "Arsenal", "Chelsea", start),
+		match("Fonbet", "Arsenal", "Chelsea", start.Add(time.Hour)), // DST off by an hour
+		match("Pinnacle", "Roma", "Lazio", start.Add(10*time.Minute)),
+		match("Zenit", "Roma", "Lazio", start.Add(20*time.Minute)), // either side of 18:15, between the 30m buckets
+		match("Pinnacle", "Milan", "Inter", start),
+		match("Fonbet", "Milan", "Inter", start.Add(-time.Minute)), // same group across the hour
+		match("Pinnacle", "Ajax", "PSV", start),
+		match("Fonbet", "Ajax", "PSV", start.Add(24*time.Hour)), // next round, not a duplicate
+	}
+	found := findNearDuplicateGroups(matches, 2*time.Hour)
+	if len(found) != 2 {
+		t.Fatalf("found %+v, want Arsenal and Roma", found)
+	}
+	if found[0].HomeTeam != "Arsenal" || found[0].Reason != nearDuplicateTimeZone || found[0].Gap != "1h0m0s" {
+		t.Errorf("first = %+v", found[0])
+	}
+	if found[1].HomeTeam != "Roma" || found[1].Reason != nearDuplicateTimeMismatch || len(found[1].Groups) != 2 ||
+		found[1].Groups[0].Bookmakers[0] != "pinnacle" {
+		t.Errorf("second = %+v", found[1])
+	}
+}
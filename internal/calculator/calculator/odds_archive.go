@@ -0,0 +1,146 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// Odds archive defaults (storage.clickhouse).
+const (
+	defaultArchiveBatchSize     = 50000
+	defaultArchiveFlushInterval = 30 * time.Second
+	archiveFlushTimeout         = time.Minute
+)
+
+// oddsArchiver buffers the odds snapshots of the line movement cycles and writes them to the archive in
+// batches. While the archive is unreachable up to maxBuffered rows wait for the next flush; older ones
+// are dropped so an outage can't exhaust memory.
+type oddsArchiver struct {
+	archive       storage.OddsArchive
+	batchSize     int
+	flushInterval time.Duration
+	maxBuffered   int
+
+	mu      sync.Mutex
+	pending []storage.OddsSnapshotToStore
+	full    chan struct{} // a batch is ready
+}
+
+func newOddsArchiver(archive storage.OddsArchive, cfg *config.ClickHouseConfig) *oddsArchiver {
+	a := &oddsArchiver{
+		archive:       archive,
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		maxBuffered:   cfg.MaxBuffered,
+		full:          make(chan struct{}, 1),
+	}
+	if a.batchSize <= 0 {
+		a.batchSize = defaultArchiveBatchSize
+	}
+	if a.flushInterval <= 0 {
+		a.flushInterval = defaultArchiveFlushInterval
+	}
+	if a.maxBuffered < a.batchSize {
+		a.maxBuffered = 10 * a.batchSize
+	}
+	return a
+}
+
+// SetOddsArchive streams every odds snapshot stored by the line movement cycle into archive as well
+// (storage.clickhouse). Call before Start.
+func (c *ValueCalculator) SetOddsArchive(archive storage.OddsArchive, cfg *config.ClickHouseConfig) {
+	if c.oddsSnapshotStorage == nil {
+		slog.Warn("Odds archive needs line_movement_enabled, nothing will be archived")
+		_ = archive.Close()
+		return
+	}
+	c.oddsArchive = newOddsArchiver(archive, cfg)
+	c.oddsSnapshotStorage = &archivingSnapshotStorage{OddsSnapshotStorage: c.oddsSnapshotStorage, archiver: c.oddsArchive}
+}
+
+// archivingSnapshotStorage copies the snapshots stored in the operational storage into the archiver.
+type archivingSnapshotStorage struct {
+	storage.OddsSnapshotStorage
+	archiver *oddsArchiver
+}
+
+func (s *archivingSnapshotStorage) StoreOddsSnapshotsBatch(ctx context.Context, snapshots []storage.OddsSnapshotToStore) error {
+	s.archiver.add(snapshots)
+	return s.OddsSnapshotStorage.StoreOddsSnapshotsBatch(ctx, snapshots)
+}
+
+// add buffers rows, dropping the oldest above maxBuffered.
+func (a *oddsArchiver) add(rows []storage.OddsSnapshotToStore) {
+	a.mu.Lock()
+	a.pending = append(a.pending, rows...)
+	if over := len(a.pending) - a.maxBuffered; over > 0 {
+		a.pending = append(a.pending[:0], a.pending[over:]...)
+		slog.Warn("Odds archive buffer is full, dropped the oldest snapshots", "dropped", over)
+	}
+	ready := len(a.pending) >= a.batchSize
+	a.mu.Unlock()
+	if ready {
+		select {
+		case a.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run flushes a batch whenever one is ready and everything buffered every flushInterval.
+func (a *oddsArchiver) run(ctx context.Context) {
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.full:
+			a.flush(ctx)
+		case <-ticker.C:
+			a.flush(ctx)
+		}
+	}
+}
+
+// flush writes the buffered rows batch by batch. Rows of a failed batch go back to the buffer.
+func (a *oddsArchiver) flush(ctx context.Context) {
+	for {
+		a.mu.Lock()
+		n := min(len(a.pending), a.batchSize)
+		batch := append([]storage.OddsSnapshotToStore(nil), a.pending[:n]...)
+		a.pending = a.pending[n:]
+		a.mu.Unlock()
+		if n == 0 {
+			return
+		}
+
+		flushCtx, cancel := context.WithTimeout(ctx, archiveFlushTimeout)
+		err := a.archive.ArchiveOddsSnapshots(flushCtx, batch)
+		cancel()
+		if err != nil {
+			slog.Warn("Failed to archive odds snapshots, retrying on the next flush", "rows", n, "error", err)
+			a.mu.Lock()
+			a.pending = append(batch, a.pending...)
+			if over := len(a.pending) - a.maxBuffered; over > 0 {
+				a.pending = a.pending[over:]
+			}
+			a.mu.Unlock()
+			return
+		}
+		slog.Debug("Archived odds snapshots", "rows", n)
+	}
+}
+
+// close writes what is still buffered and closes the archive.
+func (a *oddsArchiver) close() {
+	a.flush(context.Background())
+	if err := a.archive.Close(); err != nil {
+		slog.Warn("Failed to close odds archive", "error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+type fakeOddsArchive struct {
+	mu      sync.Mutex
+	fail    bool
+	batches [][]storage.OddsSnapshotToStore
+}
+
+func (a *fakeOddsArchive) ArchiveOddsSnapshots(_ context.Context, rows []storage.OddsSnapshotToStore) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.fail {
+		return errors.New("unreachable")
+	}
+	a.batches = append(a.batches, rows)
+	return nil
+}
+
+func (a *fakeOddsArchive) Close() error { return nil }
+
+func snapshotRows(from, n int) []storage.OddsSnapshotToStore {
+	rows := make([]storage.OddsSnapshotToStore, n)
+	for i := range rows {
+		rows[i] = storage.OddsSnapshotToStore{Odd: float64(from + i)}
+	}
+	return rows
+}
+
+func TestOddsArchiver(t *testing.T) {
+	archive := &fakeOddsArchive{}
+	a := newOddsArchiver(archive, &config.ClickHouseConfig{BatchSize: 3, MaxBuffered: 5})
+
+	a.add(snapshotRows(0, 2))
+	select {
+	case <-a.full:
+		t.Fatal("batch signalled before batch_size rows")
+	default:
+	}
+	a.add(snapshotRows(2, 2))
+	select {
+	case <-a.full:
+	default:
+		t.Fatal("full batch not signalled")
+	}
+
+	a.flush(context.Background())
+	if len(archive.batches) != 2 || len(archive.batches[0]) != 3 || len(archive.batches[1]) != 1 {
+		t.Fatalf("batches = %v, want 3 rows then 1", archive.batches)
+	}
+
+	// A failed flush keeps the rows, the oldest are dropped above max_buffered
+	archive.fail = true
+	a.add(snapshotRows(10, 4))
+	a.flush(context.Background())
+	a.add(snapshotRows(20, 3))
+	if len(a.pending) != 5 || a.pending[0].Odd != 12 {
+		t.Fatalf("pending = %v, want 5 rows from 12", a.pending)
+	}
+
+	archive.fail = false
+	a.close()
+	if len(a.pending) != 0 {
+		t.Errorf("%d rows left after close", len(a.pending))
+	}
+	var got []float64
+	for _, b := range archive.batches[2:] {
+		for _, r := range b {
+			got = append(got, r.Odd)
+		}
+	}
+	want := []float64{12, 13, 20, 21, 22}
+	if len(got) != len(want) {
+		t.Fatalf("archived %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("archived %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Odds history defaults (value_calculator.odds_history).
+const (
+	defaultOddsHistoryRetention = 7 * 24 * time.Hour
+	defaultKeyframeInterval     = time.Hour
+	oddsHistoryRetentionEvery   = time.Hour
+	oddsHistoryRetentionTimeout = 5 * time.Minute
+)
+
+// oddsHistoryPolicy decides when a line movement cycle writes a full snapshot (keyframe) into the odds
+// history and how long the history is kept.
+type oddsHistoryPolicy struct {
+	retention        time.Duration // <0 = keep all
+	keyframeInterval time.Duration // <0 = only changes
+
+	mu           sync.Mutex
+	lastKeyframe time.Time
+}
+
+func newOddsHistoryPolicy(cfg *config.ValueCalculatorConfig) *oddsHistoryPolicy {
+	p := &oddsHistoryPolicy{retention: defaultOddsHistoryRetention, keyframeInterval: defaultKeyframeInterval}
+	if cfg != nil {
+		if cfg.OddsHistory.Retention != 0 {
+			p.retention = cfg.OddsHistory.Retention
+		}
+		if cfg.OddsHistory.KeyframeInterval != 0 {
+			p.keyframeInterval = cfg.OddsHistory.KeyframeInterval
+		}
+	}
+	return p
+}
+
+// keyframeDue reports whether the cycle at now writes a keyframe; the first cycle after a start always does.
+func (p *oddsHistoryPolicy) keyframeDue(now time.Time) bool {
+	if p.keyframeInterval < 0 {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.lastKeyframe.IsZero() && now.Sub(p.lastKeyframe) < p.keyframeInterval {
+		return false
+	}
+	p.lastKeyframe = now
+	return true
+}
+
+// runOddsHistoryRetention creates the upcoming day partitions of the odds history and drops the expired
+// ones, at start and then hourly (leader only).
+func (c *ValueCalculator) runOddsHistoryRetention(ctx context.Context) {
+	ticker := time.NewTicker(oddsHistoryRetentionEvery)
+	defer ticker.Stop()
+	for {
+		if c.isLeader() {
+			c.applyOddsHistoryRetention(ctx, time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ValueCalculator) applyOddsHistoryRetention(ctx context.Context, now time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, oddsHistoryRetentionTimeout)
+	defer cancel()
+	dropped, err := c.oddsSnapshotStorage.ApplyOddsHistoryRetention(ctx, now, c.oddsHistory.retention)
+	if err != nil {
+		slog.Error("Odds history retention failed", "error", err)
+		return
+	}
+	if dropped > 0 {
+		slog.Info("Odds history: expired day partitions dropped", "dropped", dropped, "retention", c.oddsHistory.retention)
+	}
+}
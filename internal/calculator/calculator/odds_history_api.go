@@ -0,0 +1,98 @@
+package calculator
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// GET /odds/history page size.
+const (
+	defaultOddsHistoryLimit = 500
+	maxOddsHistoryLimit     = 5000
+)
+
+// GET /odds/history?match_group_key=...&bet_key=...[&bookmaker=fonbet][&since=24h | &from=RFC3339&to=RFC3339]
+// [&interval=5m][&limit=500&offset=0]: the stored odds timeline of a bet, oldest first; with interval only the
+// last point of each bookmaker per interval.
+func (c *ValueCalculator) handleOddsHistory(w http.ResponseWriter, r *http.Request) {
+	if c.oddsSnapshotStorage == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "line movement storage is not configured (enable line_movement_enabled)"})
+		return
+	}
+	f, err := parseOddsHistoryFilter(r, time.Now().UTC())
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	points, total, err := c.oddsSnapshotStorage.GetOddsHistoryRange(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to get odds history", "match_group_key", f.MatchGroupKey, "bet_key", f.BetKey, "error", err)
+		writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get odds history", "details": err.Error()})
+		return
+	}
+	if points == nil {
+		points = []storage.OddsHistorySample{}
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"points": points, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// parseOddsHistoryFilter reads the query of GET /odds/history.
+func parseOddsHistoryFilter(r *http.Request, now time.Time) (storage.OddsHistoryRangeFilter, error) {
+	q := r.URL.Query()
+	f := storage.OddsHistoryRangeFilter{
+		MatchGroupKey: strings.TrimSpace(q.Get("match_group_key")),
+		BetKey:        strings.TrimSpace(q.Get("bet_key")),
+		Bookmaker:     strings.TrimSpace(q.Get("bookmaker")),
+		Limit:         defaultOddsHistoryLimit,
+	}
+	if f.MatchGroupKey == "" || f.BetKey == "" {
+		return f, fmt.Errorf("match_group_key and bet_key are required")
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return f, fmt.Errorf("invalid limit")
+		}
+		f.Limit = min(n, maxOddsHistoryLimit)
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("invalid offset")
+		}
+		f.Offset = n
+	}
+	if v := q.Get("interval"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Second {
+			return f, fmt.Errorf("invalid interval, use a duration of at least 1s like 5m")
+		}
+		f.Interval = d
+	}
+	if v := q.Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return f, fmt.Errorf("invalid since, use a duration like 24h")
+		}
+		f.From = now.Add(-d)
+	}
+	for name, dst := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s, use RFC3339", name)
+			}
+			*dst = t
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return f, fmt.Errorf("from must be before to")
+	}
+	return f, nil
+}
@@ -0,0 +1,32 @@
+package calculator
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseOddsHistoryFilter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := "match_group_key=" + url.QueryEscape("football|arsenal|chelsea|2026-03-01") + "&bet_key=" + url.QueryEscape("main_match|home_win|")
+	f, err := parseOddsHistoryFilter(httptest.NewRequest("GET", "/odds/history?"+key+"&bookmaker=fonbet&from=2026-02-28T12:00:00Z&interval=5m&limit=10000&offset=20", nil), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.BetKey != "main_match|home_win|" || f.Bookmaker != "fonbet" || !f.From.Equal(now.Add(-24*time.Hour)) || !f.To.IsZero() ||
+		f.Interval != 5*time.Minute || f.Limit != maxOddsHistoryLimit || f.Offset != 20 {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+	if f, _ := parseOddsHistoryFilter(httptest.NewRequest("GET", "/odds/history?"+key+"&since=6h", nil), now); f.Limit != defaultOddsHistoryLimit || !f.From.Equal(now.Add(-6*time.Hour)) {
+		t.Errorf("defaults: %+v", f)
+	}
+	if _, err := parseOddsHistoryFilter(httptest.NewRequest("GET", "/odds/history?bet_key=x", nil), now); err == nil {
+		t.Error("missing match_group_key: expected an error")
+	}
+	for _, q := range []string{"interval=100ms", "interval=x", "limit=0", "offset=-1", "since=x", "to=tomorrow", "from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z"} {
+		if _, err := parseOddsHistoryFilter(httptest.NewRequest("GET", "/odds/history?"+key+"&"+q, nil), now); err == nil {
+			t.Errorf("%s: expected an error", q)
+		}
+	}
+}
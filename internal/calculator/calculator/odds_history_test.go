@@ -0,0 +1,98 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// historyRecorder keeps the last snapshot per key and the appended history; other methods are not used.
+type historyRecorder struct {
+	storage.OddsSnapshotStorage
+	last    map[storage.OddsSnapshotKey]storage.OddsSnapshotRow
+	history []storage.OddsHistoryToAppend
+}
+
+func (s *historyRecorder) GetLastOddsSnapshotsBatch(_ context.Context, keys []storage.OddsSnapshotKey) (map[storage.OddsSnapshotKey]storage.OddsSnapshotRow, error) {
+	out := map[storage.OddsSnapshotKey]storage.OddsSnapshotRow{}
+	for _, k := range keys {
+		if row, ok := s.last[k]; ok {
+			out[k] = row
+		}
+	}
+	return out, nil
+}
+
+func (s *historyRecorder) StoreOddsSnapshotsBatch(_ context.Context, snaps []storage.OddsSnapshotToStore) error {
+	for _, sn := range snaps {
+		s.last[storage.OddsSnapshotKey{MatchGroupKey: sn.MatchGroupKey, BetKey: sn.BetKey, Bookmaker: sn.Bookmaker}] = storage.OddsSnapshotRow{Odd: sn.Odd, MaxOdd: sn.Odd, MinOdd: sn.Odd}
+	}
+	return nil
+}
+
+func (s *historyRecorder) AppendOddsHistoryBatch(_ context.Context, h []storage.OddsHistoryToAppend) error {
+	s.history = append(s.history, h...)
+	return nil
+}
+
+func TestComputeAndStoreLineMovements_Keyframes(t *testing.T) {
+	match := func(home, draw float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", Sport: "football", StartTime: time.Now().Add(3 * time.Hour),
+			Events: []models.Event{{EventType: "main_match", Bookmaker: "fonbet", Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: home}, {OutcomeType: "draw", Odds: draw},
+			}}}}
+	}
+	s := &historyRecorder{last: map[storage.OddsSnapshotKey]storage.OddsSnapshotRow{}}
+	th := lineMovementThreshold{Percent: 10}
+	ctx := context.Background()
+
+	if _, err := computeAndStoreLineMovements(ctx, []models.Match{match(2.0, 3.4)}, s, th, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.history) != 2 {
+		t.Fatalf("first cycle: %d history points, want 2", len(s.history))
+	}
+
+	// Only the home win moved: a delta cycle writes just that
+	s.history = nil
+	computeAndStoreLineMovements(ctx, []models.Match{match(1.9, 3.4)}, s, th, false)
+	if len(s.history) != 1 || s.history[0].BetKey != "main_match|home_win|" || s.history[0].Keyframe {
+		t.Fatalf("delta cycle: %+v", s.history)
+	}
+
+	// A keyframe cycle repeats the unchanged draw, flagged as a keyframe
+	s.history = nil
+	computeAndStoreLineMovements(ctx, []models.Match{match(1.8, 3.4)}, s, th, true)
+	if len(s.history) != 2 {
+		t.Fatalf("keyframe cycle: %d history points, want 2", len(s.history))
+	}
+	for _, h := range s.history {
+		if want := h.BetKey == "main_match|draw|"; h.Keyframe != want {
+			t.Errorf("%s: keyframe = %v, want %v", h.BetKey, h.Keyframe, want)
+		}
+	}
+}
+
+func TestOddsHistoryPolicy_KeyframeDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	p := newOddsHistoryPolicy(&config.ValueCalculatorConfig{OddsHistory: config.OddsHistoryConfig{KeyframeInterval: 30 * time.Minute}})
+	if p.retention != defaultOddsHistoryRetention {
+		t.Errorf("retention = %v, want default", p.retention)
+	}
+	for _, tt := range []struct {
+		at   time.Duration
+		want bool
+	}{{0, true}, {10 * time.Minute, false}, {30 * time.Minute, true}, {50 * time.Minute, false}, {time.Hour, true}} {
+		if got := p.keyframeDue(now.Add(tt.at)); got != tt.want {
+			t.Errorf("keyframeDue(+%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	off := newOddsHistoryPolicy(&config.ValueCalculatorConfig{OddsHistory: config.OddsHistoryConfig{KeyframeInterval: -1}})
+	if off.keyframeDue(now) {
+		t.Error("keyframes disabled, but one is due")
+	}
+}
@@ -0,0 +1,253 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// outrightMarketAliases maps bookmaker-specific market names to one canonical name.
+var outrightMarketAliases = map[string]string{
+	"winner":             "winner",
+	"outright winner":    "winner",
+	"tournament winner":  "winner",
+	"league winner":      "winner",
+	"to win":             "winner",
+	"победитель":         "winner",
+	"победитель турнира": "winner",
+	"чемпион":            "winner",
+}
+
+// tournamentStopWords are dropped from tournament names before grouping
+// ("England. Premier League" and "Premier League" end up in one group).
+var tournamentStopWords = map[string]bool{
+	"the": true, "england": true, "english": true, "spain": true, "spanish": true,
+	"italy": true, "italian": true, "germany": true, "german": true, "france": true, "french": true,
+	"outrights": true, "outright": true, "futures": true,
+}
+
+// normalizeTournament resolves league aliases, lower-cases, strips punctuation and stop words, and sorts tokens.
+func normalizeTournament(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(models.CanonicalLeagueName(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !tournamentStopWords[f] {
+			tokens = append(tokens, f)
+		}
+	}
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// normalizeOutrightMarket maps a market name to its canonical form (unknown names are just normalized).
+func normalizeOutrightMarket(s string) string {
+	n := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if alias, ok := outrightMarketAliases[n]; ok {
+		return alias
+	}
+	return n
+}
+
+// outrightGroupKey groups outrights from different bookmakers: sport|tournament|market.
+// Returns "" when the tournament is unknown.
+func outrightGroupKey(o models.Outright) string {
+	t := normalizeTournament(o.Tournament)
+	if t == "" {
+		return ""
+	}
+	sport := strings.ToLower(strings.TrimSpace(o.Sport))
+	if sport == "" {
+		sport = "unknown"
+	}
+	return sport + "|" + t + "|" + normalizeOutrightMarket(o.MarketName)
+}
+
+// computeOutrightValueBets finds value in outright markets using the same weighted fair-probability
+// approach as computeValueBets (including reference bookmakers). Participants are matched by normalizeTeam within a tournament group.
+func computeOutrightValueBets(outrights []models.Outright, bookmakerWeights map[string]float64, referenceBookmakers []string, minValuePercent float64, maxOdds float64, keepTop int) []OutrightValueBet {
+	if keepTop <= 0 {
+		keepTop = 100
+	}
+	if minValuePercent <= 0 {
+		minValuePercent = 5.0
+	}
+	weights := lowerBookmakerKeys(bookmakerWeights)
+	getWeight := func(bookmaker string) float64 {
+		if w, ok := weights[bookmaker]; ok && w > 0 {
+			return w
+		}
+		return 1.0
+	}
+	reference := referenceBookmakerSet(referenceBookmakers)
+
+	type groupMeta struct {
+		sport, tournament, market string
+		closeTime                 time.Time
+	}
+	// groupKey -> participant key -> bookmaker -> odd
+	groups := map[string]map[string]map[string]float64{}
+	meta := map[string]groupMeta{}
+	participantNames := map[string]string{}
+
+	for _, o := range outrights {
+		gk := outrightGroupKey(o)
+		if gk == "" {
+			continue
+		}
+		if _, ok := meta[gk]; !ok {
+			meta[gk] = groupMeta{sport: o.Sport, tournament: o.Tournament, market: o.MarketName, closeTime: o.CloseTime}
+			groups[gk] = map[string]map[string]float64{}
+		}
+		for _, oc := range o.Outcomes {
+			pk := normalizeTeam(oc.Participant)
+			if pk == "" || !isFinitePositiveOdd(oc.Odds) {
+				continue
+			}
+			bk := strings.TrimSpace(oc.Bookmaker)
+			if bk == "" {
+				bk = strings.TrimSpace(o.Bookmaker)
+			}
+			if bk == "" {
+				continue
+			}
+			bk = strings.ToLower(bk)
+			if _, ok := participantNames[gk+"|"+pk]; !ok {
+				participantNames[gk+"|"+pk] = strings.TrimSpace(oc.Participant)
+			}
+			if groups[gk][pk] == nil {
+				groups[gk][pk] = map[string]float64{}
+			}
+			if prev, ok := groups[gk][pk][bk]; !ok || oc.Odds > prev {
+				groups[gk][pk][bk] = oc.Odds
+			}
+		}
+	}
+
+	now := time.Now()
+	var out []OutrightValueBet
+	for gk, participants := range groups {
+		gm := meta[gk]
+		for pk, byBook := range participants {
+			if len(byBook) < 2 {
+				continue
+			}
+			var totalWeightedProb, totalWeight float64
+			for bk, odd := range byBook {
+				if reference != nil && !reference[bk] {
+					continue
+				}
+				w := getWeight(bk)
+				totalWeightedProb += w / odd
+				totalWeight += w
+			}
+			if totalWeight <= 0 {
+				continue
+			}
+			fairProb := totalWeightedProb / totalWeight
+			if fairProb <= 0 || fairProb >= 1 {
+				continue
+			}
+			fairOdd := 1.0 / fairProb
+			for bk, odd := range byBook {
+				valuePercent := (odd/fairOdd - 1.0) * 100.0
+				if valuePercent < minValuePercent {
+					continue
+				}
+				if maxOdds > 0 && odd > maxOdds {
+					continue
+				}
+				allOdds := make(map[string]float64, len(byBook))
+				for b, o := range byBook {
+					allOdds[b] = o
+				}
+				out = append(out, OutrightValueBet{
+					GroupKey:         gk,
+					Sport:            gm.sport,
+					Tournament:       gm.tournament,
+					MarketName:       gm.market,
+					Participant:      participantNames[gk+"|"+pk],
+					CloseTime:        gm.closeTime,
+					AllBookmakerOdds: allOdds,
+					FairOdd:          fairOdd,
+					FairProbability:  fairProb,
+					Bookmaker:        bk,
+					BookmakerOdd:     odd,
+					ValuePercent:     valuePercent,
+					ExpectedValue:    odd*fairProb - 1.0,
+					CalculatedAt:     now,
+				})
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ValuePercent > out[j].ValuePercent
+	})
+	if len(out) > keepTop {
+		out = out[:keepTop]
+	}
+	return out
+}
+
+// handleTopOutrightValueBets returns top value bets on outright (futures) markets.
+// Outrights usually carry a much larger margin than matches, so max_odds from config is not applied.
+func (c *ValueCalculator) handleTopOutrightValueBets(w http.ResponseWriter, r *http.Request) {
+	limit := 5
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			if n > 50 {
+				n = 50
+			}
+			limit = n
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if c.httpClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+
+	var bookmakerWeights map[string]float64
+	var referenceBookmakers []string
+	minValuePercent := 5.0
+	if c.cfg != nil {
+		bookmakerWeights = c.cfg.BookmakerWeights
+		referenceBookmakers = c.cfg.ReferenceBookmakers
+		if c.cfg.MinValuePercent > 0 {
+			minValuePercent = c.cfg.MinValuePercent
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	outrights, err := c.httpClient.GetOutrights(ctx)
+	if err != nil {
+		slog.Error("Failed to load outrights in handleTopOutrightValueBets", "error", err)
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed to fetch outrights from parser", "details": err.Error()})
+		return
+	}
+
+	valueBets := computeOutrightValueBets(outrights, bookmakerWeights, referenceBookmakers, minValuePercent, 0, 100)
+	if limit > len(valueBets) {
+		limit = len(valueBets)
+	}
+	if len(valueBets) == 0 {
+		_ = json.NewEncoder(w).Encode([]OutrightValueBet{})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(valueBets[:limit])
+}
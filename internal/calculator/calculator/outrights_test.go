@@ -0,0 +1,28 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestComputeOutrightValueBets_GroupsByTournament(t *testing.T) {
+	outrights := []models.Outright{
+		{Sport: "football", Tournament: "English Premier League", MarketName: "Winner", Bookmaker: "Pinnacle",
+			Outcomes: []models.OutrightOutcome{{Participant: "Arsenal", Odds: 3.0}, {Participant: "Liverpool", Odds: 2.5}}},
+		{Sport: "football", Tournament: "Premier League", MarketName: "Outright Winner", Bookmaker: "Fonbet",
+			Outcomes: []models.OutrightOutcome{{Participant: "FC Arsenal", Odds: 4.0}, {Participant: "Liverpool", Odds: 2.5}}},
+	}
+
+	bets := computeOutrightValueBets(outrights, nil, nil, 5, 0, 10)
+	if len(bets) != 1 {
+		t.Fatalf("expected 1 value bet, got %d: %+v", len(bets), bets)
+	}
+	vb := bets[0]
+	if vb.Bookmaker != "fonbet" || vb.BookmakerOdd != 4.0 || vb.GroupKey != "football|league premier|winner" {
+		t.Errorf("unexpected value bet: %+v", vb)
+	}
+	if len(vb.AllBookmakerOdds) != 2 {
+		t.Errorf("expected odds from 2 bookmakers, got %v", vb.AllBookmakerOdds)
+	}
+}
@@ -0,0 +1,152 @@
+package calculator
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// computeMatchProbabilities returns the de-margined probability of every outcome of the match group per
+// bookmaker and the consensus fair probability, computed as for value bets (same weights, reference
+// bookmakers, margin removal method and freshness). Returns nil if no match has that group key.
+func computeMatchProbabilities(matches []models.Match, groupKey string, bookmakerWeights map[string]float64, referenceBookmakers []string, limits valueLimits, method FairOddsMethod) *MatchProbabilities {
+	var result *MatchProbabilities
+	bets := map[string]map[string]float64{} // betKey -> bookmaker -> best odd
+	now := time.Now()
+
+	for i := range matches {
+		m := matches[i]
+		if matchGroupKey(m) != groupKey {
+			continue
+		}
+		if result == nil {
+			result = &MatchProbabilities{
+				MatchGroupKey: groupKey,
+				MatchName:     strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam),
+				StartTime:     m.StartTime,
+				Sport:         m.Sport,
+				Tournament:    m.Tournament,
+			}
+		}
+		for _, ev := range m.Events {
+			for _, out := range ev.Outcomes {
+				bk := strings.TrimSpace(out.Bookmaker)
+				if bk == "" {
+					bk = strings.TrimSpace(ev.Bookmaker)
+				}
+				if bk == "" {
+					bk = strings.TrimSpace(m.Bookmaker)
+				}
+				eventType := strings.TrimSpace(ev.EventType)
+				outcomeType := strings.TrimSpace(out.OutcomeType)
+				if bk == "" || eventType == "" || outcomeType == "" || !isFinitePositiveOdd(out.Odds) {
+					continue
+				}
+				bkLower := strings.ToLower(bk)
+				if !limits.fresh(bkLower, quoteSeenAt(m, ev, out), now) {
+					continue
+				}
+				betKey := models.OutcomeBetKey(ev, out).String()
+				if _, ok := bets[betKey]; !ok {
+					bets[betKey] = map[string]float64{}
+				}
+				if prev, ok := bets[betKey][bkLower]; !ok || out.Odds > prev {
+					bets[betKey][bkLower] = out.Odds
+				}
+			}
+		}
+	}
+	if result == nil {
+		return nil
+	}
+
+	if method == nil {
+		method = noMarginRemoval{}
+	}
+	result.Method = method.Name()
+	result.ReferenceBookmakers = referenceBookmakers
+	weights := lowerBookmakerKeys(bookmakerWeights)
+	reference := referenceBookmakerSet(referenceBookmakers)
+	fair := fairProbabilitiesByBookmaker(bets, result.Sport, method)
+
+	result.Outcomes = make([]OutcomeProbability, 0, len(bets))
+	for betKey, byBook := range bets {
+		evType, outType, param := models.SplitBetKey(betKey)
+		op := OutcomeProbability{
+			BetKey:      betKey,
+			EventType:   evType,
+			OutcomeType: outType,
+			Parameter:   param,
+			Bookmakers:  make(map[string]BookmakerProbability, len(byBook)),
+		}
+		if p, ok := consensusFairProbability(byBook, fair[betKey], reference, weights); ok {
+			op.FairProbability = p
+			op.FairOdd = 1 / p
+		}
+		for bk, odd := range byBook {
+			bp := BookmakerProbability{
+				Odd:                odd,
+				ImpliedProbability: 1 / odd,
+				FairProbability:    1 / odd,
+				Reference:          reference == nil || reference[bk],
+			}
+			if p, ok := fair[betKey][bk]; ok {
+				bp.FairProbability, bp.Devigged = p, true
+			}
+			op.Bookmakers[bk] = bp
+		}
+		result.Outcomes = append(result.Outcomes, op)
+	}
+	sort.Slice(result.Outcomes, func(i, j int) bool { return result.Outcomes[i].BetKey < result.Outcomes[j].BetKey })
+	return result
+}
+
+// handleMatchProbabilities returns de-margined probabilities per outcome per bookmaker and the consensus fair
+// probability of one match, so external tools can use the model without re-implementing it.
+// GET /matches/{group_key}/probabilities?method=shin (group_key URL-encoded, e.g. football%7Carsenal%7Cchelsea%7C...)
+func (c *ValueCalculator) handleMatchProbabilities(w http.ResponseWriter, r *http.Request) {
+	groupKey := r.PathValue("group_key")
+	if groupKey == "" {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "match group key is required"})
+		return
+	}
+	method := c.fairOdds
+	if v := r.URL.Query().Get("method"); v != "" {
+		m, err := fairOddsMethodByName(v)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		method = m
+	}
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleMatchProbabilities", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+
+	var weights map[string]float64
+	var reference []string
+	if c.cfg != nil {
+		weights, reference = c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers
+	}
+	probs := computeMatchProbabilities(matches, groupKey, weights, reference, valueLimitsFromConfig(c.cfg), method)
+	if probs == nil {
+		writeWebAppJSON(w, http.StatusNotFound, map[string]string{"error": "match not found"})
+		return
+	}
+	writeWebAppJSON(w, http.StatusOK, probs)
+}
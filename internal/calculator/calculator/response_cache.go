@@ -0,0 +1,198 @@
+package calculator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/rediscache"
+)
+
+// Redis cache defaults (value_calculator.redis_cache).
+const (
+	defaultCacheKeyPrefix  = "vodeneevbet:"
+	defaultCacheTTL        = 10 * time.Second
+	defaultMatchesCacheTTL = 5 * time.Second
+)
+
+// matchesCacheKey holds the merged match snapshot of GetMatchesAll.
+const matchesCacheKey = "matches:all"
+
+// responseCache caches hot query results in Redis so that replicas and frequent pollers share them.
+// A nil cache is disabled; Redis errors make the query run uncached.
+type responseCache struct {
+	client     *rediscache.Client
+	ttl        time.Duration
+	matchesTTL time.Duration
+	failing    atomic.Bool // last Redis command failed (logged once until it recovers)
+}
+
+func newResponseCache(cfg *config.ValueCalculatorConfig) *responseCache {
+	if cfg == nil || cfg.RedisCache.Addr == "" {
+		return nil
+	}
+	rc := cfg.RedisCache
+	prefix := rc.KeyPrefix
+	if prefix == "" {
+		prefix = defaultCacheKeyPrefix
+	}
+	cache := &responseCache{
+		client:     rediscache.New(rc.Addr, rc.Password, rc.DB, prefix),
+		ttl:        rc.TTL,
+		matchesTTL: rc.MatchesTTL,
+	}
+	if cache.ttl <= 0 {
+		cache.ttl = defaultCacheTTL
+	}
+	if cache.matchesTTL == 0 {
+		cache.matchesTTL = defaultMatchesCacheTTL
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := cache.client.Ping(ctx); err != nil {
+		slog.Warn("Redis cache is unreachable, queries run uncached until it is", "addr", rc.Addr, "error", err)
+	} else {
+		slog.Info("Redis cache enabled", "addr", rc.Addr, "ttl", cache.ttl, "matches_ttl", cache.matchesTTL)
+	}
+	return cache
+}
+
+func (rc *responseCache) get(ctx context.Context, name, key string) ([]byte, bool) {
+	value, ok, err := rc.client.Get(ctx, key)
+	switch {
+	case err != nil:
+		rc.fail(err)
+		metrics.CacheRequests.WithLabelValues(name, "error").Inc()
+	case ok:
+		rc.recover()
+		metrics.CacheRequests.WithLabelValues(name, "hit").Inc()
+	default:
+		rc.recover()
+		metrics.CacheRequests.WithLabelValues(name, "miss").Inc()
+	}
+	return value, ok && err == nil
+}
+
+func (rc *responseCache) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if err := rc.client.Set(ctx, key, value, ttl); err != nil {
+		rc.fail(err)
+	}
+}
+
+func (rc *responseCache) fail(err error) {
+	if !rc.failing.Swap(true) {
+		slog.Warn("Redis cache failed, serving uncached", "error", err)
+	}
+}
+
+func (rc *responseCache) recover() {
+	if rc.failing.Swap(false) {
+		slog.Info("Redis cache recovered")
+	}
+}
+
+// cachedHeaders are the response headers stored with a cached body (paging of the list endpoints).
+var cachedHeaders = []string{nextCursorHeader, totalCountHeader}
+
+// cachedResponse is a cached response: its body and cachedHeaders.
+type cachedResponse struct {
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body"`
+}
+
+// wrap serves successful GET responses of h from the cache, keyed by path and query string.
+// Responses carry X-Cache: HIT or MISS.
+func (rc *responseCache) wrap(name string, h http.HandlerFunc) http.HandlerFunc {
+	if rc == nil {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			h(w, r)
+			return
+		}
+		// Query().Encode sorts the parameters: ?limit=5&status=live and ?status=live&limit=5 share an entry
+		key := "http:" + r.URL.Path + "?" + r.URL.Query().Encode()
+		var cached cachedResponse
+		if data, ok := rc.get(r.Context(), name, key); ok && json.Unmarshal(data, &cached) == nil && len(cached.Body) > 0 {
+			for k, v := range cached.Header {
+				w.Header().Set(k, v)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Cache", "HIT")
+			_, _ = w.Write(cached.Body)
+			return
+		}
+		w.Header().Set("X-Cache", "MISS")
+		rec := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h(rec, r)
+		if rec.status == http.StatusOK && rec.body.Len() > 0 {
+			cached = cachedResponse{Body: bytes.TrimSpace(rec.body.Bytes())}
+			for _, k := range cachedHeaders {
+				if v := w.Header().Get(k); v != "" {
+					if cached.Header == nil {
+						cached.Header = map[string]string{}
+					}
+					cached.Header[k] = v
+				}
+			}
+			if data, err := json.Marshal(cached); err == nil {
+				rc.set(context.WithoutCancel(r.Context()), key, data, rc.ttl)
+			}
+		}
+	}
+}
+
+// recordingResponseWriter passes a response through and keeps a copy of its status and body.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// cachedMatches returns the merged match snapshot cached by another request or replica.
+func (rc *responseCache) cachedMatches(ctx context.Context) ([]models.Match, bool) {
+	if rc == nil || rc.matchesTTL < 0 {
+		return nil, false
+	}
+	data, ok := rc.get(ctx, "matches", matchesCacheKey)
+	if !ok {
+		return nil, false
+	}
+	var matches []models.Match
+	if err := json.Unmarshal(data, &matches); err != nil {
+		slog.Warn("Failed to decode cached matches", "error", err)
+		return nil, false
+	}
+	return matches, true
+}
+
+// storeMatches caches the merged match snapshot for matches_ttl.
+func (rc *responseCache) storeMatches(ctx context.Context, matches []models.Match) {
+	if rc == nil || rc.matchesTTL < 0 {
+		return
+	}
+	data, err := json.Marshal(matches)
+	if err != nil {
+		slog.Warn("Failed to encode matches for the cache", "error", err)
+		return
+	}
+	rc.set(context.WithoutCancel(ctx), matchesCacheKey, data, rc.matchesTTL)
+}
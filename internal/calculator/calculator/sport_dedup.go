@@ -0,0 +1,142 @@
+package calculator
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// genericSportTags are catch-all tags some bookmakers use for "cyber" sections (e.g. 1xbet sport_id=40).
+// When a fixture conflicts between a generic tag and a concrete sport, the concrete sport wins ties.
+var genericSportTags = map[string]bool{
+	"esports": true,
+	"cyber":   true,
+	"unknown": true,
+}
+
+// SportConflict describes one fixture reported under more than one sport.
+type SportConflict struct {
+	FixtureKey string
+	Sports     map[string][]string // sport -> bookmakers that reported the fixture under it
+	Resolved   string              // sport kept after resolution
+	Dropped    int                 // matches removed as same-bookmaker duplicates
+	Retagged   int                 // matches moved to the resolved sport
+}
+
+// dedupeCrossSportMatches detects fixtures (same teams, same start time) reported under conflicting sports
+// and resolves them to a single sport so the consensus doesn't count the same bookmaker twice.
+//
+// Resolution rule:
+//   - the sport reported by the most distinct bookmakers wins;
+//   - on a tie a concrete sport (football, dota2, ...) beats a generic tag (esports, cyber);
+//   - remaining ties are broken alphabetically for determinism.
+//
+// Matches under a losing sport are dropped if the same bookmaker already has the fixture under the winner,
+// otherwise they are re-tagged to the winning sport.
+func dedupeCrossSportMatches(matches []models.Match) ([]models.Match, []SportConflict) {
+	// fixtureKey -> sport -> bookmaker set
+	bySport := make(map[string]map[string]map[string]struct{})
+	for i := range matches {
+		fk := fixtureKey(matches[i])
+		if fk == "" {
+			continue
+		}
+		sport := matchSport(matches[i])
+		if _, ok := bySport[fk]; !ok {
+			bySport[fk] = make(map[string]map[string]struct{})
+		}
+		if _, ok := bySport[fk][sport]; !ok {
+			bySport[fk][sport] = make(map[string]struct{})
+		}
+		bySport[fk][sport][matchBookmaker(matches[i])] = struct{}{}
+	}
+
+	winners := make(map[string]string)
+	conflicts := make(map[string]*SportConflict)
+	for fk, sports := range bySport {
+		if len(sports) < 2 {
+			continue
+		}
+		winner := resolveSport(sports)
+		winners[fk] = winner
+		c := &SportConflict{FixtureKey: fk, Sports: make(map[string][]string, len(sports)), Resolved: winner}
+		for sport, bks := range sports {
+			list := make([]string, 0, len(bks))
+			for bk := range bks {
+				list = append(list, bk)
+			}
+			sort.Strings(list)
+			c.Sports[sport] = list
+		}
+		conflicts[fk] = c
+	}
+	if len(winners) == 0 {
+		return matches, nil
+	}
+
+	out := make([]models.Match, 0, len(matches))
+	for i := range matches {
+		m := matches[i]
+		fk := fixtureKey(m)
+		winner, conflicted := winners[fk]
+		if !conflicted || matchSport(m) == winner {
+			out = append(out, m)
+			continue
+		}
+		if _, dup := bySport[fk][winner][matchBookmaker(m)]; dup {
+			conflicts[fk].Dropped++
+			continue
+		}
+		m.Sport = winner
+		conflicts[fk].Retagged++
+		out = append(out, m)
+	}
+
+	result := make([]SportConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		slog.Info("Cross-sport duplicate resolved",
+			"fixture", c.FixtureKey,
+			"sports", c.Sports,
+			"resolved_sport", c.Resolved,
+			"dropped", c.Dropped,
+			"retagged", c.Retagged)
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].FixtureKey < result[j].FixtureKey })
+	return out, result
+}
+
+// resolveSport picks the winning sport for a conflicted fixture (see dedupeCrossSportMatches).
+func resolveSport(sports map[string]map[string]struct{}) string {
+	names := make([]string, 0, len(sports))
+	for s := range sports {
+		names = append(names, s)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if len(sports[a]) != len(sports[b]) {
+			return len(sports[a]) > len(sports[b])
+		}
+		if genericSportTags[a] != genericSportTags[b] {
+			return !genericSportTags[a]
+		}
+		return a < b
+	})
+	return names[0]
+}
+
+// matchBookmaker returns the lower-cased bookmaker of a match (from the match or its first event).
+func matchBookmaker(m models.Match) string {
+	bk := strings.TrimSpace(m.Bookmaker)
+	if bk == "" {
+		for _, ev := range m.Events {
+			if b := strings.TrimSpace(ev.Bookmaker); b != "" {
+				bk = b
+				break
+			}
+		}
+	}
+	return strings.ToLower(bk)
+}
@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestDedupeCrossSportMatches_DropsSameBookmakerDuplicate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	matches := []models.Match{
+		{ID: "a", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "1xbet"},
+		{ID: "b", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "esports", Bookmaker: "1xbet"},
+		{ID: "c", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "fonbet"},
+	}
+
+	out, conflicts := dedupeCrossSportMatches(matches)
+	if len(out) != 2 {
+		t.Fatalf("expected 2 matches after dedup, got %d", len(out))
+	}
+	for _, m := range out {
+		if m.Sport != "football" {
+			t.Errorf("match %s: sport = %q, want football", m.ID, m.Sport)
+		}
+	}
+	if len(conflicts) != 1 || conflicts[0].Resolved != "football" || conflicts[0].Dropped != 1 {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+}
+
+func TestDedupeCrossSportMatches_RetagsAndPrefersConcreteSportOnTie(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	matches := []models.Match{
+		{ID: "a", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "esports", Bookmaker: "1xbet"},
+		{ID: "b", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: "fonbet"},
+		{ID: "c", HomeTeam: "Liverpool", AwayTeam: "Everton", StartTime: start, Sport: "football", Bookmaker: "fonbet"},
+	}
+
+	out, conflicts := dedupeCrossSportMatches(matches)
+	if len(out) != 3 {
+		t.Fatalf("expected 3 matches, got %d", len(out))
+	}
+	if matchGroupKey(out[0]) != matchGroupKey(out[1]) {
+		t.Errorf("re-tagged match should share group key: %q vs %q", matchGroupKey(out[0]), matchGroupKey(out[1]))
+	}
+	if len(conflicts) != 1 || conflicts[0].Retagged != 1 {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+}
@@ -0,0 +1,46 @@
+package calculator
+
+import "math"
+
+// Staking defaults (value_calculator.staking).
+const (
+	defaultKellyFraction    = 0.25
+	defaultFlatStakePercent = 1.0
+)
+
+// kellyPercent is the full Kelly stake in percent of bankroll: (p*odd - 1) / (odd - 1), 0 without an edge.
+func kellyPercent(fairProb, odd float64) float64 {
+	if odd <= 1 {
+		return 0
+	}
+	f := (fairProb*odd - 1) / (odd - 1)
+	if f <= 0 || math.IsNaN(f) {
+		return 0
+	}
+	return f * 100
+}
+
+// sizeStakes fills the fractional Kelly and flat stakes of value bets from value_calculator.staking.
+// KellyPercent is set by computeValueBets.
+func (c *ValueCalculator) sizeStakes(valueBets []ValueBet) {
+	fraction := defaultKellyFraction
+	flat := defaultFlatStakePercent
+	maxStake := 0.0
+	if c.cfg != nil {
+		if c.cfg.Staking.KellyFraction > 0 {
+			fraction = c.cfg.Staking.KellyFraction
+		}
+		if c.cfg.Staking.FlatPercent > 0 {
+			flat = c.cfg.Staking.FlatPercent
+		}
+		maxStake = c.cfg.Staking.MaxStakePercent
+	}
+	for i := range valueBets {
+		stake := valueBets[i].KellyPercent * fraction
+		if maxStake > 0 && stake > maxStake {
+			stake = maxStake
+		}
+		valueBets[i].FractionalKellyPercent = stake
+		valueBets[i].FlatStakePercent = flat
+	}
+}
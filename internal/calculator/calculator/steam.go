@@ -0,0 +1,255 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// Steam defaults (value_calculator.steam).
+const (
+	defaultSteamWindow        = 10 * time.Minute
+	defaultSteamMinBookmakers = 2
+	defaultSteamMinShiftPP    = 2.0
+	defaultOpenerAge          = 30 * time.Minute
+
+	// steamHistoryLookback bounds the history read for steam: the price at the start of the window may be
+	// hours old (started matches are skipped anyway)
+	steamHistoryLookback = 24 * time.Hour
+	// steamAlertCooldown: the same steam (match, bet, direction) is alerted at most once per cooldown
+	steamAlertCooldown = time.Hour
+)
+
+var defaultVelocityWindows = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// Line classes (SteamLeg.Class): how a bookmaker's line moved since its first quote.
+const (
+	LineClassOpener     = "opener"     // first quoted less than opener_age ago
+	LineClassShortening = "shortening" // odds fell
+	LineClassDrifting   = "drifting"   // odds rose
+	LineClassStable     = "stable"     // back at the opening odd
+)
+
+type steamParams struct {
+	window          time.Duration
+	minBookmakers   int
+	minShiftPP      float64
+	velocityWindows []time.Duration
+	openerAge       time.Duration
+}
+
+func steamParamsFromConfig(cfg *config.ValueCalculatorConfig) steamParams {
+	p := steamParams{
+		window:          defaultSteamWindow,
+		minBookmakers:   defaultSteamMinBookmakers,
+		minShiftPP:      defaultSteamMinShiftPP,
+		velocityWindows: defaultVelocityWindows,
+		openerAge:       defaultOpenerAge,
+	}
+	if cfg == nil {
+		return p
+	}
+	s := cfg.Steam
+	if s.Window > 0 {
+		p.window = s.Window
+	}
+	if s.MinBookmakers > 0 {
+		p.minBookmakers = s.MinBookmakers
+	}
+	if s.MinShiftPP > 0 {
+		p.minShiftPP = s.MinShiftPP
+	}
+	if len(s.VelocityWindows) > 0 {
+		p.velocityWindows = s.VelocityWindows
+	}
+	if s.OpenerAge > 0 {
+		p.openerAge = s.OpenerAge
+	}
+	return p
+}
+
+// oddAt returns the odd in effect at t: the last point recorded at or before t (points are oldest first).
+func oddAt(points []storage.OddsHistoryPoint, t time.Time) (float64, bool) {
+	i := sort.Search(len(points), func(i int) bool { return points[i].RecordedAt.After(t) })
+	if i == 0 {
+		return 0, false
+	}
+	return points[i-1].Odd, true
+}
+
+// lineVelocity is the implied probability change over the last window in pp per minute
+// (since the first quote when the line is younger than the window).
+func lineVelocity(points []storage.OddsHistoryPoint, now time.Time, window time.Duration) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	from, since := points[0].Odd, points[0].RecordedAt
+	if odd, ok := oddAt(points, now.Add(-window)); ok {
+		from, since = odd, now.Add(-window)
+	}
+	mins := now.Sub(since).Minutes()
+	if mins <= 0 {
+		return 0
+	}
+	return models.ProbShiftPP(from, points[len(points)-1].Odd) / mins
+}
+
+// classifyLine returns the line class of a bookmaker's history (oldest first).
+func classifyLine(points []storage.OddsHistoryPoint, now time.Time, openerAge time.Duration) string {
+	first, last := points[0], points[len(points)-1]
+	switch {
+	case now.Sub(first.RecordedAt) < openerAge:
+		return LineClassOpener
+	case models.OddsEqual(first.Odd, last.Odd):
+		return LineClassStable
+	case last.Odd < first.Odd:
+		return LineClassShortening
+	}
+	return LineClassDrifting
+}
+
+// durationLabel formats a velocity window as "5m", "1h" or "90s".
+func durationLabel(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// detectSteam finds outcomes whose implied probability moved by min_shift_pp or more in the same direction
+// at min_bookmakers or more bookmakers within the window before now. records are odds history points
+// (oldest first); lines first quoted inside the window are openers, not moves. Started matches are skipped.
+func detectSteam(records []storage.OddsHistoryRecord, now time.Time, p steamParams) []SteamMove {
+	type groupMeta struct {
+		name      string
+		sport     string
+		startTime time.Time
+	}
+	// matchGroupKey -> betKey -> bookmaker -> history
+	lines := map[string]map[string]map[string][]storage.OddsHistoryPoint{}
+	meta := map[string]groupMeta{}
+	for _, r := range records {
+		if r.RecordedAt.After(now) {
+			continue
+		}
+		if _, ok := lines[r.MatchGroupKey]; !ok {
+			lines[r.MatchGroupKey] = map[string]map[string][]storage.OddsHistoryPoint{}
+			meta[r.MatchGroupKey] = groupMeta{name: r.MatchName, sport: r.Sport, startTime: r.StartTime}
+		}
+		if gm := meta[r.MatchGroupKey]; gm.name == "" && r.MatchName != "" {
+			gm.name = r.MatchName
+			meta[r.MatchGroupKey] = gm
+		}
+		byBook, ok := lines[r.MatchGroupKey][r.BetKey]
+		if !ok {
+			byBook = map[string][]storage.OddsHistoryPoint{}
+			lines[r.MatchGroupKey][r.BetKey] = byBook
+		}
+		bk := strings.ToLower(r.Bookmaker)
+		byBook[bk] = append(byBook[bk], storage.OddsHistoryPoint{Odd: r.Odd, RecordedAt: r.RecordedAt})
+	}
+
+	windowStart := now.Add(-p.window)
+	var moves []SteamMove
+	for gk, bets := range lines {
+		gm := meta[gk]
+		if !gm.startTime.IsZero() && !gm.startTime.After(now) {
+			continue
+		}
+		for betKey, byBook := range bets {
+			quoting := 0
+			legs := map[string][]SteamLeg{}
+			for bk, points := range byBook {
+				from, ok := oddAt(points, windowStart)
+				if !ok {
+					continue // opened inside the window
+				}
+				quoting++
+				last := points[len(points)-1]
+				shift := models.ProbShiftPP(from, last.Odd)
+				if math.Abs(shift) < p.minShiftPP {
+					continue
+				}
+				direction := LineClassShortening
+				if shift < 0 {
+					direction = LineClassDrifting
+				}
+				velocity := make(map[string]float64, len(p.velocityWindows))
+				for _, w := range p.velocityWindows {
+					velocity[durationLabel(w)] = lineVelocity(points, now, w)
+				}
+				legs[direction] = append(legs[direction], SteamLeg{
+					Bookmaker:   bk,
+					FromOdd:     from,
+					ToOdd:       last.Odd,
+					ProbShiftPP: shift,
+					MovedAt:     last.RecordedAt,
+					Class:       classifyLine(points, now, p.openerAge),
+					Velocity:    velocity,
+				})
+			}
+			for direction, dirLegs := range legs {
+				if len(dirLegs) < p.minBookmakers {
+					continue
+				}
+				sort.Slice(dirLegs, func(i, j int) bool { return dirLegs[i].MovedAt.Before(dirLegs[j].MovedAt) })
+				var shiftSum float64
+				for _, l := range dirLegs {
+					shiftSum += l.ProbShiftPP
+				}
+				evType, outType, param := models.SplitBetKey(betKey)
+				moves = append(moves, SteamMove{
+					MatchGroupKey:  gk,
+					MatchName:      gm.name,
+					StartTime:      gm.startTime,
+					Sport:          gm.sport,
+					EventType:      evType,
+					OutcomeType:    outType,
+					Parameter:      param,
+					BetKey:         betKey,
+					Direction:      direction,
+					Bookmakers:     len(dirLegs),
+					Consensus:      float64(len(dirLegs)) / float64(quoting),
+					AvgProbShiftPP: shiftSum / float64(len(dirLegs)),
+					Legs:           dirLegs,
+					DetectedAt:     now,
+				})
+			}
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].Bookmakers != moves[j].Bookmakers {
+			return moves[i].Bookmakers > moves[j].Bookmakers
+		}
+		return math.Abs(moves[i].AvgProbShiftPP) > math.Abs(moves[j].AvgProbShiftPP)
+	})
+	return moves
+}
+
+// detectSteamMoves reads recent odds history and returns the current steam moves.
+func (c *ValueCalculator) detectSteamMoves(ctx context.Context, now time.Time, p steamParams) ([]SteamMove, error) {
+	var records []storage.OddsHistoryRecord
+	err := c.oddsSnapshotStorage.ScanOddsHistory(ctx, now.Add(-steamHistoryLookback), now.Add(time.Second), func(r storage.OddsHistoryRecord) error {
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detectSteam(records, now, p), nil
+}
+
+// steamAlertKey identifies a steam for alert cooldown.
+func steamAlertKey(m SteamMove) string {
+	return m.MatchGroupKey + "|" + m.BetKey + "|" + m.Direction
+}
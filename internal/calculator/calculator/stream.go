@@ -0,0 +1,274 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GET /stream/value-bets pushes what the async cycles find as Server-Sent Events.
+const (
+	streamHeartbeat    = 15 * time.Second // comment line keeping proxies from closing an idle stream
+	streamRetry        = 5 * time.Second  // EventSource reconnect delay
+	streamClientBuffer = 256              // events waiting for a client; a client that falls further behind is disconnected
+	maxStreamClients   = 100
+)
+
+// Stream event types (the SSE "event:" field).
+const (
+	streamEventValueBet     = "value_bet"     // a value bet that wasn't in the previous cycle, as in /value-bets/top
+	streamEventLineMovement = "line_movement" // a line movement detected by the line movement cycle, as in /line-movements/top
+)
+
+// streamEvent is one encoded event with the fields the client filters look at.
+type streamEvent struct {
+	typ   string
+	sport string
+	value float64 // value_percent of a value bet, |change_percent| of a line movement
+	data  []byte
+}
+
+// streamFilter is what a client asked for: ?types=, ?sport=, ?min_value= and ?min_change=.
+type streamFilter struct {
+	types     map[string]bool // empty = all
+	sport     string
+	minValue  float64
+	minChange float64
+}
+
+func (f streamFilter) accepts(ev streamEvent) bool {
+	if len(f.types) > 0 && !f.types[ev.typ] {
+		return false
+	}
+	if !sportMatchesFilter(ev.sport, f.sport) {
+		return false
+	}
+	switch ev.typ {
+	case streamEventValueBet:
+		return ev.value >= f.minValue
+	case streamEventLineMovement:
+		return ev.value >= f.minChange
+	}
+	return true
+}
+
+type streamClient struct {
+	filter streamFilter
+	events chan streamEvent // closed when the client is disconnected for falling behind
+}
+
+// valueStream fans the events of the async cycles out to the connected clients. Value bets are computed
+// only while someone is connected.
+type valueStream struct {
+	mu      sync.Mutex
+	clients map[*streamClient]struct{}
+	seen    map[string]bool // ids of the value bets pushed by the previous cycle
+}
+
+func newValueStream() *valueStream {
+	return &valueStream{clients: map[*streamClient]struct{}{}, seen: map[string]bool{}}
+}
+
+// active reports whether any client is connected.
+func (s *valueStream) active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients) > 0
+}
+
+// subscribe connects a client, false when maxStreamClients are already connected.
+func (s *valueStream) subscribe(f streamFilter) (*streamClient, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.clients) >= maxStreamClients {
+		return nil, false
+	}
+	cl := &streamClient{filter: f, events: make(chan streamEvent, streamClientBuffer)}
+	s.clients[cl] = struct{}{}
+	return cl, true
+}
+
+func (s *valueStream) unsubscribe(cl *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[cl]; ok {
+		delete(s.clients, cl)
+		close(cl.events)
+	}
+}
+
+// publish sends events to the clients whose filters accept them.
+func (s *valueStream) publish(events []streamEvent) {
+	if len(events) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for cl := range s.clients {
+		for _, ev := range events {
+			if !cl.filter.accepts(ev) {
+				continue
+			}
+			select {
+			case cl.events <- ev:
+			default:
+				slog.Warn("Value bet stream client is too slow, disconnecting", "buffered", streamClientBuffer)
+				delete(s.clients, cl)
+				close(cl.events)
+			}
+			if _, ok := s.clients[cl]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// publishValueBets pushes the value bets of a cycle that weren't in the previous one.
+func (s *valueStream) publishValueBets(valueBets []ValueBet) {
+	current := make(map[string]bool, len(valueBets))
+	var events []streamEvent
+	s.mu.Lock()
+	for i := range valueBets {
+		vb := &valueBets[i]
+		current[vb.ID] = true
+		if s.seen[vb.ID] {
+			continue
+		}
+		if ev, ok := encodeStreamEvent(streamEventValueBet, vb.Sport, vb.ValuePercent, vb); ok {
+			events = append(events, ev)
+		}
+	}
+	s.seen = current
+	s.mu.Unlock()
+	s.publish(events)
+}
+
+// publishLineMovements pushes the line movements detected by a cycle.
+func (s *valueStream) publishLineMovements(movements []LineMovement) {
+	if !s.active() {
+		return
+	}
+	events := make([]streamEvent, 0, len(movements))
+	for i := range movements {
+		lm := &movements[i]
+		if ev, ok := encodeStreamEvent(streamEventLineMovement, lm.Sport, math.Abs(lm.ChangePercent), lm); ok {
+			events = append(events, ev)
+		}
+	}
+	s.publish(events)
+}
+
+func encodeStreamEvent(typ, sport string, value float64, v interface{}) (streamEvent, bool) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("Failed to encode stream event", "type", typ, "error", err)
+		return streamEvent{}, false
+	}
+	return streamEvent{typ: typ, sport: sport, value: value, data: data}, true
+}
+
+// processValueStream computes the current value bets (as GET /value-bets/top) for the stream clients.
+func (c *ValueCalculator) processValueStream(ctx context.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(reqCtx)
+	if err != nil {
+		slog.Error("Failed to fetch matches for value bet stream", "error", err)
+		return
+	}
+	var weights map[string]float64
+	var reference []string
+	if c.cfg != nil {
+		weights, reference = c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers
+	}
+	valueBets := computeValueBets(matches, weights, reference, valueLimitsFromConfig(c.cfg), math.MaxInt32, c.fairOdds)
+	c.bets.remember(valueBets)
+	c.sizeStakes(valueBets)
+	c.stream.publishValueBets(valueBets)
+}
+
+// parseStreamFilter reads ?types= (comma-separated value_bet, line_movement), ?sport=, ?min_value= and ?min_change=.
+func parseStreamFilter(r *http.Request) (streamFilter, error) {
+	q := r.URL.Query()
+	f := streamFilter{sport: q.Get("sport")}
+	if v := q.Get("types"); v != "" {
+		f.types = map[string]bool{}
+		for _, t := range strings.Split(v, ",") {
+			switch t = strings.TrimSpace(t); t {
+			case streamEventValueBet, streamEventLineMovement:
+				f.types[t] = true
+			case "":
+			default:
+				return f, fmt.Errorf("unknown event type %q (want value_bet or line_movement)", t)
+			}
+		}
+	}
+	for name, dst := range map[string]*float64{"min_value": &f.minValue, "min_change": &f.minChange} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || n < 0 {
+				return f, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = n
+		}
+	}
+	return f, nil
+}
+
+// handleValueBetStream streams new value bets and line movements as Server-Sent Events until the client
+// disconnects. Nothing is sent on connect: the current state is in /value-bets/top and /line-movements/top.
+// GET /stream/value-bets
+func (c *ValueCalculator) handleValueBetStream(w http.ResponseWriter, r *http.Request) {
+	f, err := parseStreamFilter(r)
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming is not supported"})
+		return
+	}
+	cl, ok := c.stream.subscribe(f)
+	if !ok {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "too many stream clients"})
+		return
+	}
+	defer c.stream.unsubscribe(cl)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-cl.events:
+			if !ok {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.typ, ev.data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package calculator
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValueStreamPublish(t *testing.T) {
+	s := newValueStream()
+	all, _ := s.subscribe(streamFilter{})
+	values, _ := s.subscribe(streamFilter{types: map[string]bool{streamEventValueBet: true}, sport: "football", minValue: 5})
+
+	s.publishValueBets([]ValueBet{
+		{ID: "a", Sport: "football", ValuePercent: 7},
+		{ID: "b", Sport: "dota2", ValuePercent: 9},
+		{ID: "c", Sport: "football", ValuePercent: 3},
+	})
+	// Only "d" is new; "a" stays, "b" and "c" are gone
+	s.publishValueBets([]ValueBet{{ID: "a", Sport: "football", ValuePercent: 8}, {ID: "d", Sport: "football", ValuePercent: 6}})
+	s.publishLineMovements([]LineMovement{{Sport: "football", ChangePercent: -12}})
+
+	if got := drain(all); len(got) != 5 {
+		t.Errorf("unfiltered client got %d events, want 5", len(got))
+	}
+	got := drain(values)
+	if len(got) != 2 || !strings.Contains(string(got[0].data), `"id":"a"`) || !strings.Contains(string(got[1].data), `"id":"d"`) {
+		t.Errorf("filtered client got %v, want value bets a and d", got)
+	}
+
+	// A client that falls streamClientBuffer events behind is disconnected
+	slow, _ := s.subscribe(streamFilter{})
+	for i := 0; i <= streamClientBuffer; i++ {
+		s.publishLineMovements([]LineMovement{{Sport: "football"}})
+	}
+	n := 0
+	for range slow.events {
+		n++
+	}
+	if n != streamClientBuffer {
+		t.Errorf("slow client got %d events before disconnect, want %d", n, streamClientBuffer)
+	}
+	s.unsubscribe(slow)
+}
+
+func drain(cl *streamClient) []streamEvent {
+	var events []streamEvent
+	for {
+		select {
+		case ev := <-cl.events:
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
+
+func TestParseStreamFilter(t *testing.T) {
+	f, err := parseStreamFilter(httptest.NewRequest("GET", "/stream/value-bets?types=line_movement&sport=cs&min_change=10", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.types[streamEventLineMovement] || f.types[streamEventValueBet] || f.sport != "cs" || f.minChange != 10 {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+	for _, q := range []string{"types=arbs", "min_value=x", "min_change=-1"} {
+		if _, err := parseStreamFilter(httptest.NewRequest("GET", "/stream/value-bets?"+q, nil)); err == nil {
+			t.Errorf("%s: expected an error", q)
+		}
+	}
+}
+
+func TestHandleValueBetStream(t *testing.T) {
+	c := &ValueCalculator{stream: newValueStream()}
+	srv := httptest.NewServer(http.HandlerFunc(c.handleValueBetStream))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"?types=value_bet", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if !c.stream.active() {
+		t.Fatal("client not subscribed")
+	}
+
+	c.stream.publishLineMovements([]LineMovement{{MatchName: "skipped"}})
+	c.stream.publishValueBets([]ValueBet{{ID: "x", MatchName: "Arsenal vs Chelsea", ValuePercent: 4}})
+	sc := bufio.NewScanner(resp.Body)
+	var lines []string
+	for sc.Scan() && !strings.HasPrefix(sc.Text(), "data: ") {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) == 0 || lines[len(lines)-1] != "event: value_bet" || !strings.Contains(sc.Text(), `"match_name":"Arsenal vs Chelsea"`) {
+		t.Errorf("stream: %q then %q", lines, sc.Text())
+	}
+}
@@ -0,0 +1,388 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// subscriptionList is the alert subscriptions by chat (POST /subscriptions). Entries are kept in memory
+// and persisted to store when Postgres is configured.
+type subscriptionList struct {
+	mu      sync.RWMutex
+	entries map[int64]storage.AlertSubscription // chat_id -> subscription
+	store   storage.SubscriptionStorage         // nil = in memory only
+}
+
+func newSubscriptionList() *subscriptionList {
+	return &subscriptionList{entries: map[int64]storage.AlertSubscription{}}
+}
+
+// load attaches store and loads its subscriptions.
+func (l *subscriptionList) load(ctx context.Context, store storage.SubscriptionStorage) error {
+	subs, err := store.GetSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+	for _, s := range subs {
+		l.entries[s.ChatID] = s
+	}
+	return nil
+}
+
+// put adds or replaces the subscription of s.ChatID.
+func (l *subscriptionList) put(ctx context.Context, s storage.AlertSubscription) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store != nil {
+		if err := l.store.StoreSubscription(ctx, s); err != nil {
+			return err
+		}
+	}
+	l.entries[s.ChatID] = s
+	return nil
+}
+
+// remove deletes the subscription of chatID; reports whether it existed.
+func (l *subscriptionList) remove(ctx context.Context, chatID int64) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.store != nil {
+		if err := l.store.DeleteSubscription(ctx, chatID); err != nil {
+			return false, err
+		}
+	}
+	_, ok := l.entries[chatID]
+	delete(l.entries, chatID)
+	return ok, nil
+}
+
+// get returns the subscription of chatID.
+func (l *subscriptionList) get(chatID int64) (storage.AlertSubscription, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	s, ok := l.entries[chatID]
+	return s, ok
+}
+
+// list returns all subscriptions ordered by chat ID.
+func (l *subscriptionList) list() []storage.AlertSubscription {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]storage.AlertSubscription, 0, len(l.entries))
+	for _, s := range l.entries {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChatID < out[j].ChatID })
+	return out
+}
+
+// subscriptionWants reports whether s receives alertType (no alert types = all).
+func subscriptionWants(s storage.AlertSubscription, alertType string) bool {
+	if len(s.AlertTypes) == 0 {
+		return true
+	}
+	for _, t := range s.AlertTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// alertRecipient is a chat an alert is sent to.
+type alertRecipient struct {
+	chatID int64
+	sub    *storage.AlertSubscription // nil = telegram_chat_id, switched by /async/start and /async/stop_*
+}
+
+// valueThreshold returns the diff percent value alerts start from (base = the pipeline's alert_threshold).
+func (r alertRecipient) valueThreshold(base float64) float64 {
+	if r.sub != nil && r.sub.MinDiffPercent > 0 {
+		return r.sub.MinDiffPercent
+	}
+	return base
+}
+
+// acceptsSport reports whether the subscription's sport filter lets sport through.
+func (r alertRecipient) acceptsSport(sport string) bool {
+	if r.sub == nil || len(r.sub.Filters.Sports) == 0 {
+		return true
+	}
+	return containsFold(r.sub.Filters.Sports, sport)
+}
+
+// acceptsLeague reports whether the subscription's league filter lets tournament through.
+func (r alertRecipient) acceptsLeague(tournament string) bool {
+	if r.sub == nil || len(r.sub.Filters.Leagues) == 0 {
+		return true
+	}
+	for _, l := range r.sub.Filters.Leagues {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" && leagueFilterAccepts(tournament, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsBookmaker reports whether the subscription's bookmaker filter lets bookmaker through.
+func (r alertRecipient) acceptsBookmaker(bookmaker string) bool {
+	if r.sub == nil || len(r.sub.Filters.Bookmakers) == 0 {
+		return true
+	}
+	return containsFold(r.sub.Filters.Bookmakers, bookmaker)
+}
+
+// acceptsOdd reports whether odd is within the subscription's odds range.
+func (r alertRecipient) acceptsOdd(odd float64) bool {
+	if r.sub == nil {
+		return true
+	}
+	f := r.sub.Filters
+	return (f.MinOdds <= 0 || odd >= f.MinOdds) && (f.MaxOdds <= 0 || odd <= f.MaxOdds)
+}
+
+// acceptsDiff reports whether a value alert for d goes to the recipient (threshold aside).
+func (r alertRecipient) acceptsDiff(d *DiffBet) bool {
+	return r.rejectsDiff(d) == ""
+}
+
+// rejectsDiff returns the subscription filter a value alert for d fails ("" = none).
+func (r alertRecipient) rejectsDiff(d *DiffBet) string {
+	switch {
+	case r.sub != nil && r.sub.Filters.MaxDiffPercent > 0 && d.DiffPercent > r.sub.Filters.MaxDiffPercent:
+		return "max_diff_percent"
+	case !r.acceptsSport(d.Sport):
+		return "sports"
+	case !r.acceptsLeague(d.Tournament):
+		return "leagues"
+	case !r.acceptsOdd(d.MaxOdd):
+		return "odds"
+	case !r.acceptsBookmaker(d.MinBookmaker) || !r.acceptsBookmaker(d.MaxBookmaker):
+		return "bookmakers"
+	}
+	return ""
+}
+
+// acceptsLineMovement reports whether a line movement alert goes to the recipient: filters and the
+// subscription's thresholds (either is enough; they can only raise line_movement_alert_threshold*).
+func (r alertRecipient) acceptsLineMovement(lm *LineMovement) bool {
+	if !r.acceptsSport(lm.Sport) || !r.acceptsLeague(lm.Tournament) || !r.acceptsBookmaker(lm.Bookmaker) || !r.acceptsOdd(lm.CurrentOdd) {
+		return false
+	}
+	if r.sub == nil || (r.sub.MinLineMovementPercent <= 0 && r.sub.MinLineMovementPP <= 0) {
+		return true
+	}
+	return (r.sub.MinLineMovementPercent > 0 && math.Abs(lm.ChangePercent) >= r.sub.MinLineMovementPercent) ||
+		(r.sub.MinLineMovementPP > 0 && math.Abs(lm.ProbShiftPP) >= r.sub.MinLineMovementPP)
+}
+
+// acceptsSteam reports whether a steam alert goes to the recipient: sport and one of the moving bookmakers.
+func (r alertRecipient) acceptsSteam(m *SteamMove) bool {
+	if !r.acceptsSport(m.Sport) {
+		return false
+	}
+	for _, leg := range m.Legs {
+		if r.acceptsBookmaker(leg.Bookmaker) {
+			return true
+		}
+	}
+	return len(m.Legs) == 0
+}
+
+// containsFold reports whether list contains s, ignoring case and surrounding spaces.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// alertRecipients returns the chats that receive alertType: telegram_chat_id while its global switch is on
+// (unless it has a subscription of its own) and every enabled subscription of that type.
+func (c *ValueCalculator) alertRecipients(alertType string) []alertRecipient {
+	if c.notifier == nil {
+		return nil
+	}
+	var out []alertRecipient
+	if chatID := c.notifier.chatID; chatID != 0 && c.defaultChatAlertsOn(alertType) {
+		if _, ok := c.subscriptions.get(chatID); !ok {
+			out = append(out, alertRecipient{chatID: chatID})
+		}
+	}
+	subs := c.subscriptions.list()
+	for i := range subs {
+		if subs[i].Enabled && subscriptionWants(subs[i], alertType) {
+			out = append(out, alertRecipient{chatID: subs[i].ChatID, sub: &subs[i]})
+		}
+	}
+	// Configured channels, unless the chat already gets the alert
+	var channels []alertRecipient
+	for _, rc := range c.channels.recipients(alertType) {
+		if !hasRecipient(out, rc.chatID) {
+			channels = append(channels, rc)
+		}
+	}
+	// Channels and groups first: the private chats of their members are then deduplicated (deliveryDedup)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].chatID < 0 && out[j].chatID >= 0 })
+	return append(channels, out...)
+}
+
+func hasRecipient(recipients []alertRecipient, chatID int64) bool {
+	for _, rc := range recipients {
+		if rc.chatID == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultChatAlertsOn reports whether telegram_chat_id receives alertType: the global switches and config.
+func (c *ValueCalculator) defaultChatAlertsOn(alertType string) bool {
+	c.asyncMu.RLock()
+	valueOn, lineMovementOn := c.alertsValueEnabled, c.alertsLineMovementEnabled
+	c.asyncMu.RUnlock()
+	switch alertType {
+	case storage.AlertTypeValue:
+		return valueOn
+	case storage.AlertTypeValueClosed:
+		return valueOn && (c.cfg == nil || c.cfg.AlertClosedNotifications == nil || *c.cfg.AlertClosedNotifications)
+	case storage.AlertTypeLineMovement:
+		return lineMovementOn && c.cfg != nil && c.cfg.LineMovementTelegramAlerts
+	case storage.AlertTypeSteam:
+		return lineMovementOn && c.cfg != nil && c.cfg.Steam.TelegramAlerts
+	case storage.AlertTypeDigest:
+		return c.cfg != nil && (c.cfg.Digest.Daily || c.cfg.Digest.Weekly)
+	}
+	return false
+}
+
+// SetSubscriptionStorage persists alert subscriptions to store and loads them.
+func (c *ValueCalculator) SetSubscriptionStorage(ctx context.Context, store storage.SubscriptionStorage) error {
+	return c.subscriptions.load(ctx, store)
+}
+
+// subscriptionRequest is the body of POST /subscriptions. Enabled defaults to true; alert types to all.
+type subscriptionRequest struct {
+	ChatID                 int64                       `json:"chat_id"`
+	Name                   string                      `json:"name"`
+	Enabled                *bool                       `json:"enabled"`
+	AlertTypes             []string                    `json:"alert_types"`
+	Filters                storage.SubscriptionFilters `json:"filters"`
+	MinDiffPercent         float64                     `json:"min_diff_percent"`
+	MinLineMovementPercent float64                     `json:"min_line_movement_percent"`
+	MinLineMovementPP      float64                     `json:"min_line_movement_pp"`
+}
+
+// subscriptionEntry validates req and builds the subscription to store (keeping created_at of an existing one).
+func (c *ValueCalculator) subscriptionEntry(req subscriptionRequest, now time.Time) (storage.AlertSubscription, error) {
+	sub := storage.AlertSubscription{
+		ChatID:                 req.ChatID,
+		Name:                   strings.TrimSpace(req.Name),
+		Enabled:                req.Enabled == nil || *req.Enabled,
+		Filters:                req.Filters,
+		MinDiffPercent:         req.MinDiffPercent,
+		MinLineMovementPercent: req.MinLineMovementPercent,
+		MinLineMovementPP:      req.MinLineMovementPP,
+		CreatedAt:              now.UTC(),
+		UpdatedAt:              now.UTC(),
+	}
+	if sub.ChatID == 0 {
+		return sub, fmt.Errorf("chat_id is required")
+	}
+	f := sub.Filters
+	if sub.MinDiffPercent < 0 || sub.MinLineMovementPercent < 0 || sub.MinLineMovementPP < 0 || f.MinOdds < 0 || f.MaxOdds < 0 || f.MaxDiffPercent < 0 {
+		return sub, fmt.Errorf("thresholds and filters must not be negative")
+	}
+	if f.MaxOdds > 0 && f.MinOdds > f.MaxOdds {
+		return sub, fmt.Errorf("filters.min_odds is above filters.max_odds")
+	}
+	if f.MaxDiffPercent > 0 && sub.MinDiffPercent > f.MaxDiffPercent {
+		return sub, fmt.Errorf("min_diff_percent is above filters.max_diff_percent")
+	}
+	for _, t := range req.AlertTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !subscriptionWants(storage.AlertSubscription{AlertTypes: storage.AlertTypes}, t) {
+			return sub, fmt.Errorf("unknown alert type %q (want %s)", t, strings.Join(storage.AlertTypes, ", "))
+		}
+		sub.AlertTypes = append(sub.AlertTypes, t)
+	}
+	if prev, ok := c.subscriptions.get(sub.ChatID); ok {
+		sub.CreatedAt = prev.CreatedAt
+	}
+	return sub, nil
+}
+
+// handleSubscriptions serves alert subscriptions: GET lists them (?chat_id=... returns one), POST adds or
+// replaces the subscription of a chat, DELETE ?chat_id=... removes one.
+func (c *ValueCalculator) handleSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var chatID int64
+	if v := r.URL.Query().Get("chat_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid chat_id %q", v)})
+			return
+		}
+		chatID = id
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if chatID != 0 {
+			sub, ok := c.subscriptions.get(chatID)
+			if !ok {
+				writeWebAppJSON(w, http.StatusNotFound, map[string]string{"error": "subscription not found"})
+				return
+			}
+			writeWebAppJSON(w, http.StatusOK, sub)
+			return
+		}
+		subs := c.subscriptions.list()
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"subscriptions": subs, "count": len(subs)})
+	case http.MethodPost:
+		var req subscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		sub, err := c.subscriptionEntry(req, time.Now())
+		if err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := c.subscriptions.put(r.Context(), sub); err != nil {
+			slog.Error("Failed to store subscription", "chat_id", sub.ChatID, "error", err)
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store subscription", "details": err.Error()})
+			return
+		}
+		slog.Info("Subscription stored", "chat_id", sub.ChatID, "enabled", sub.Enabled, "alert_types", sub.AlertTypes)
+		writeWebAppJSON(w, http.StatusOK, sub)
+	case http.MethodDelete:
+		if chatID == 0 {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "chat_id is required"})
+			return
+		}
+		removed, err := c.subscriptions.remove(r.Context(), chatID)
+		if err != nil {
+			writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete subscription", "details": err.Error()})
+			return
+		}
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"chat_id": chatID, "removed": removed})
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET, POST or DELETE"})
+	}
+}
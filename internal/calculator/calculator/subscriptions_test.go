@@ -0,0 +1,160 @@
+package calculator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestHandleSubscriptions(t *testing.T) {
+	c := &ValueCalculator{subscriptions: newSubscriptionList()}
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c.handleSubscriptions(rec, httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"chat_id": 42, "alert_types": ["Value", "steam"], "filters": {"sports": ["football"]}, "min_diff_percent": 8}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: status %d: %s", rec.Code, rec.Body)
+	}
+	var sub storage.AlertSubscription
+	if err := json.NewDecoder(rec.Body).Decode(&sub); err != nil {
+		t.Fatal(err)
+	}
+	if sub.ChatID != 42 || !sub.Enabled || strings.Join(sub.AlertTypes, ",") != "value,steam" || sub.MinDiffPercent != 8 {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+
+	for _, body := range []string{
+		`{"alert_types": ["value"]}`,
+		`{"chat_id": 1, "alert_types": ["arbs"]}`,
+		`{"chat_id": 1, "min_diff_percent": -1}`,
+		`{"chat_id": 1, "filters": {"min_odds": 3, "max_odds": 1.5}}`,
+		`{"chat_id": 1, "min_diff_percent": 10, "filters": {"max_diff_percent": 5}}`,
+	} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status %d, want 400", body, rec.Code)
+		}
+	}
+
+	// Replacing keeps created_at
+	if rec := post(`{"chat_id": 42, "enabled": false}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST replace: status %d", rec.Code)
+	}
+	if got, _ := c.subscriptions.get(42); got.Enabled || len(got.AlertTypes) != 0 || !got.CreatedAt.Equal(sub.CreatedAt) {
+		t.Errorf("unexpected replaced subscription: %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleSubscriptions(rec, httptest.NewRequest(http.MethodGet, "/subscriptions?chat_id=7", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET unknown chat: status %d, want 404", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	c.handleSubscriptions(rec, httptest.NewRequest(http.MethodDelete, "/subscriptions?chat_id=42", nil))
+	if rec.Code != http.StatusOK || len(c.subscriptions.list()) != 0 {
+		t.Errorf("DELETE: status %d, %d subscriptions left", rec.Code, len(c.subscriptions.list()))
+	}
+}
+
+func TestAlertRecipients(t *testing.T) {
+	c := &ValueCalculator{
+		cfg:                &config.ValueCalculatorConfig{LineMovementTelegramAlerts: true},
+		notifier:           &TelegramNotifier{chatID: 1},
+		subscriptions:      newSubscriptionList(),
+		alertsValueEnabled: true,
+	}
+	chats := func(alertType string) []int64 {
+		var out []int64
+		for _, rc := range c.alertRecipients(alertType) {
+			out = append(out, rc.chatID)
+		}
+		return out
+	}
+	if got := chats(storage.AlertTypeValue); len(got) != 1 || got[0] != 1 {
+		t.Errorf("default chat: got %v", got)
+	}
+	if got := chats(storage.AlertTypeLineMovement); len(got) != 0 {
+		t.Errorf("line movement alerts are stopped for the default chat: got %v", got)
+	}
+
+	ctx := t.Context()
+	_ = c.subscriptions.put(ctx, storage.AlertSubscription{ChatID: 2, Enabled: true, AlertTypes: []string{storage.AlertTypeLineMovement}})
+	_ = c.subscriptions.put(ctx, storage.AlertSubscription{ChatID: 3, Enabled: false})
+	_ = c.subscriptions.put(ctx, storage.AlertSubscription{ChatID: 4, Enabled: true, Filters: storage.SubscriptionFilters{Sports: []string{"dota2"}}, MinDiffPercent: 3})
+	if got := chats(storage.AlertTypeValue); len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Errorf("value recipients: got %v, want [1 4]", got)
+	}
+	if got := chats(storage.AlertTypeLineMovement); len(got) != 2 || got[0] != 2 || got[1] != 4 {
+		t.Errorf("line movement recipients: got %v, want [2 4]", got)
+	}
+
+	// The default chat's own subscription replaces its global stream
+	_ = c.subscriptions.put(ctx, storage.AlertSubscription{ChatID: 1, Enabled: true, AlertTypes: []string{storage.AlertTypeSteam}})
+	if got := chats(storage.AlertTypeValue); len(got) != 1 || got[0] != 4 {
+		t.Errorf("value recipients with a default chat subscription: got %v, want [4]", got)
+	}
+
+	rc := c.alertRecipients(storage.AlertTypeValue)[0]
+	if rc.valueThreshold(10) != 3 || rc.acceptsDiff(&DiffBet{Sport: "football"}) || !rc.acceptsDiff(&DiffBet{Sport: "Dota2"}) {
+		t.Errorf("unexpected subscription threshold or filter: %+v", rc.sub)
+	}
+	lm := alertRecipient{sub: &storage.AlertSubscription{MinLineMovementPP: 5}}
+	if lm.acceptsLineMovement(&LineMovement{ProbShiftPP: 3, ChangePercent: 20}) || !lm.acceptsLineMovement(&LineMovement{ProbShiftPP: -6}) {
+		t.Error("line movement pp threshold not applied")
+	}
+}
+
+func TestSubscriptionFilters(t *testing.T) {
+	rc := alertRecipient{chatID: 42, sub: &storage.AlertSubscription{Filters: storage.SubscriptionFilters{
+		Leagues:        []string{"Premier League", "la liga"},
+		Bookmakers:     []string{"Pinnacle", "Fonbet"},
+		MinOdds:        1.5,
+		MaxOdds:        3,
+		MaxDiffPercent: 20,
+	}}}
+	base := DiffBet{Sport: "football", Tournament: "England. Premier League", MinBookmaker: "Pinnacle", MaxBookmaker: "fonbet", MaxOdd: 2.1, DiffPercent: 12}
+	tests := []struct {
+		name   string
+		modify func(d *DiffBet)
+		want   bool
+	}{
+		{"matching", func(d *DiffBet) {}, true},
+		{"other league", func(d *DiffBet) { d.Tournament = "Serie A" }, false},
+		{"league case", func(d *DiffBet) { d.Tournament = "Spain. LA LIGA" }, true},
+		{"other bookmaker", func(d *DiffBet) { d.MinBookmaker = "Leon" }, false},
+		{"odd too low", func(d *DiffBet) { d.MaxOdd = 1.4 }, false},
+		{"odd too high", func(d *DiffBet) { d.MaxOdd = 3.2 }, false},
+		{"value too high", func(d *DiffBet) { d.DiffPercent = 25 }, false},
+	}
+	for _, tt := range tests {
+		d := base
+		tt.modify(&d)
+		if got := rc.acceptsDiff(&d); got != tt.want {
+			t.Errorf("%s: acceptsDiff = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	lm := &LineMovement{Sport: "football", Tournament: "Premier League", Bookmaker: "Fonbet", CurrentOdd: 2}
+	if !rc.acceptsLineMovement(lm) {
+		t.Error("line movement should pass the filters")
+	}
+	lm.Bookmaker = "Leon"
+	if rc.acceptsLineMovement(lm) {
+		t.Error("line movement at a filtered out bookmaker should not pass")
+	}
+	steam := &SteamMove{Sport: "football", Legs: []SteamLeg{{Bookmaker: "leon"}, {Bookmaker: "pinnacle"}}}
+	if !rc.acceptsSteam(steam) {
+		t.Error("steam with a Pinnacle leg should pass")
+	}
+	steam.Legs = steam.Legs[:1]
+	if rc.acceptsSteam(steam) {
+		t.Error("steam without a filtered bookmaker should not pass")
+	}
+}
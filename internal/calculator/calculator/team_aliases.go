@@ -0,0 +1,216 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// defaultUnmatchedLimit bounds GET /team-aliases/unmatched without ?limit.
+const defaultUnmatchedLimit = 100
+
+// SetTeamAliasStorage persists the team aliases added through the API to store and loads its aliases
+// into the dictionary (models.AddTeamAlias).
+func (c *ValueCalculator) SetTeamAliasStorage(ctx context.Context, store storage.TeamAliasStorage) error {
+	aliases, err := store.GetTeamAliases(ctx)
+	if err != nil {
+		return err
+	}
+	c.teamAliases = store
+	for _, a := range aliases {
+		if err := models.AddTeamAlias(a.Alias, a.Canonical); err != nil {
+			slog.Warn("Skipping invalid stored team alias", "alias", a.Alias, "canonical", a.Canonical, "error", err)
+		}
+	}
+	slog.Info("Team aliases loaded", "stored", len(aliases), "total", len(models.TeamAliases()))
+	return nil
+}
+
+// handleTeamAliases lists the alias dictionary (seed and added aliases). GET /team-aliases[?q=utd]
+func (c *ValueCalculator) handleTeamAliases(w http.ResponseWriter, r *http.Request) {
+	q := models.AliasKey(r.URL.Query().Get("q"))
+	aliases := models.TeamAliases()
+	if q != "" {
+		filtered := aliases[:0]
+		for _, a := range aliases {
+			if strings.Contains(models.AliasKey(a.Alias), q) || strings.Contains(models.AliasKey(a.Canonical), q) {
+				filtered = append(filtered, a)
+			}
+		}
+		aliases = filtered
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"aliases": aliases, "count": len(aliases)})
+}
+
+// teamAliasRequest is the body of POST /admin/team-aliases.
+type teamAliasRequest struct {
+	Alias     string `json:"alias"`
+	Canonical string `json:"canonical"`
+}
+
+// handleAdminTeamAliases changes the dictionary: POST adds an alias (applied from the next cycle),
+// DELETE ?alias=... removes an added one. Seed aliases are changed in team_aliases.file.
+func (c *ValueCalculator) handleAdminTeamAliases(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req teamAliasRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body", "details": err.Error()})
+			return
+		}
+		entry := storage.TeamAlias{Alias: strings.TrimSpace(req.Alias), Canonical: strings.TrimSpace(req.Canonical), CreatedAt: time.Now().UTC()}
+		if err := models.AddTeamAlias(entry.Alias, entry.Canonical); err != nil {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if c.teamAliases != nil {
+			if err := c.teamAliases.StoreTeamAlias(r.Context(), entry); err != nil {
+				slog.Error("Failed to store team alias", "alias", entry.Alias, "error", err)
+				writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to store team alias", "details": err.Error()})
+				return
+			}
+		}
+		slog.Info("Team alias added", "alias", entry.Alias, "canonical", entry.Canonical)
+		writeWebAppJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		alias := strings.TrimSpace(r.URL.Query().Get("alias"))
+		if alias == "" {
+			writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "alias is required"})
+			return
+		}
+		if c.teamAliases != nil {
+			if err := c.teamAliases.DeleteTeamAlias(r.Context(), alias); err != nil {
+				writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete team alias", "details": err.Error()})
+				return
+			}
+		}
+		removed := models.RemoveTeamAlias(alias)
+		writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"alias": alias, "removed": removed})
+	default:
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use POST or DELETE"})
+	}
+}
+
+// unmatchedTeamPair is a likely missing alias: two bookmakers list a fixture of one sport at the same
+// start time with one team in common and the other team named differently.
+type unmatchedTeamPair struct {
+	Sport          string    `json:"sport"`
+	StartTime      time.Time `json:"start_time"`
+	CommonTeam     string    `json:"common_team"`
+	Team           string    `json:"team"`
+	Bookmaker      string    `json:"bookmaker"`
+	OtherTeam      string    `json:"other_team"`
+	OtherBookmaker string    `json:"other_bookmaker"`
+}
+
+// unmatchedTeamPairs finds the fixtures that stay split between bookmakers because one team name
+// differs (after aliases and normalization), one entry per pair of names.
+func unmatchedTeamPairs(matches []models.Match) []unmatchedTeamPair {
+	type side struct {
+		m         models.Match
+		bookmaker string
+		home      string
+		away      string
+	}
+	buckets := map[string][]side{}
+	for _, m := range matches {
+		home, away, bk := normalizeTeam(m.HomeTeam), normalizeTeam(m.AwayTeam), matchBookmaker(m)
+		if home == "" || away == "" || bk == "" || m.StartTime.IsZero() {
+			continue
+		}
+		key := matchSport(m) + "|" + m.StartTime.UTC().Truncate(30*time.Minute).Format(time.RFC3339)
+		buckets[key] = append(buckets[key], side{m: m, bookmaker: bk, home: home, away: away})
+	}
+
+	seen := map[string]bool{}
+	var out []unmatchedTeamPair
+	for _, sides := range buckets {
+		for i := range sides {
+			for j := i + 1; j < len(sides); j++ {
+				a, b := sides[i], sides[j]
+				if a.bookmaker == b.bookmaker {
+					continue
+				}
+				var common, team, other, teamKey, otherKey string
+				switch {
+				case a.home == b.home && a.away != b.away:
+					common, team, other, teamKey, otherKey = a.m.HomeTeam, a.m.AwayTeam, b.m.AwayTeam, a.away, b.away
+				case a.away == b.away && a.home != b.home:
+					common, team, other, teamKey, otherKey = a.m.AwayTeam, a.m.HomeTeam, b.m.HomeTeam, a.home, b.home
+				default:
+					continue
+				}
+				pairKey := teamKey + "|" + otherKey
+				if otherKey < teamKey {
+					pairKey = otherKey + "|" + teamKey
+				}
+				if seen[pairKey] {
+					continue
+				}
+				seen[pairKey] = true
+				out = append(out, unmatchedTeamPair{
+					Sport:          matchSport(a.m),
+					StartTime:      a.m.StartTime.UTC(),
+					CommonTeam:     strings.TrimSpace(common),
+					Team:           strings.TrimSpace(team),
+					Bookmaker:      a.bookmaker,
+					OtherTeam:      strings.TrimSpace(other),
+					OtherBookmaker: b.bookmaker,
+				})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartTime.Equal(out[j].StartTime) {
+			return out[i].StartTime.Before(out[j].StartTime)
+		}
+		return out[i].CommonTeam < out[j].CommonTeam
+	})
+	return out
+}
+
+// handleUnmatchedTeams reports the team name pairs likely missing an alias in current matches.
+// GET /team-aliases/unmatched[?sport=football][&limit=100]
+func (c *ValueCalculator) handleUnmatchedTeams(w http.ResponseWriter, r *http.Request) {
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return
+	}
+	limit := defaultUnmatchedLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	sport := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sport")))
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches in handleUnmatchedTeams", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return
+	}
+	pairs := unmatchedTeamPairs(matches)
+	if sport != "" {
+		filtered := pairs[:0]
+		for _, p := range pairs {
+			if p.Sport == sport {
+				filtered = append(filtered, p)
+			}
+		}
+		pairs = filtered
+	}
+	total := len(pairs)
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"pairs": pairs, "count": len(pairs), "total": total})
+}
@@ -0,0 +1,67 @@
+package calculator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestMatchGroupKey_TeamAliases(t *testing.T) {
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	pinnacle := models.Match{HomeTeam: "Manchester United", AwayTeam: "Chelsea", Sport: "football", StartTime: start}
+	fonbet := models.Match{HomeTeam: "Манчестер Юнайтед", AwayTeam: "Челси", Sport: "football", StartTime: start}
+	if matchGroupKey(pinnacle) != matchGroupKey(fonbet) {
+		t.Errorf("group keys differ: %q vs %q", matchGroupKey(pinnacle), matchGroupKey(fonbet))
+	}
+}
+
+func TestUnmatchedTeamPairs(t *testing.T) {
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	matches := []models.Match{
+		{HomeTeam: "Sheffield Wednesday", AwayTeam: "Hull City", Sport: "football", Bookmaker: "Pinnacle", StartTime: start},
+		{HomeTeam: "Sheffield Wed", AwayTeam: "Hull City", Sport: "football", Bookmaker: "Fonbet", StartTime: start},
+		{HomeTeam: "Sheff Wed", AwayTeam: "Hull", Sport: "football", Bookmaker: "Zenit", StartTime: start},             // no common team
+		{HomeTeam: "Sheffield Wed", AwayTeam: "Hull City", Sport: "football", Bookmaker: "Pinnacle", StartTime: start}, // same pair again
+		{HomeTeam: "Arsenal", AwayTeam: "Chelsea", Sport: "football", Bookmaker: "Pinnacle", StartTime: start},
+		{HomeTeam: "Арсенал", AwayTeam: "Челси", Sport: "football", Bookmaker: "Fonbet", StartTime: start}, // matched by aliases
+	}
+	pairs := unmatchedTeamPairs(matches)
+	if len(pairs) != 1 {
+		t.Fatalf("pairs = %+v, want one", pairs)
+	}
+	p := pairs[0]
+	if p.CommonTeam != "Hull City" || p.Team != "Sheffield Wednesday" || p.OtherTeam != "Sheffield Wed" || p.OtherBookmaker != "fonbet" {
+		t.Errorf("pair = %+v", p)
+	}
+}
+
+func TestHandleAdminTeamAliases(t *testing.T) {
+	defer models.RemoveTeamAlias("Sheffield Wed")
+	c := &ValueCalculator{}
+
+	rec := httptest.NewRecorder()
+	c.handleAdminTeamAliases(rec, httptest.NewRequest(http.MethodPost, "/admin/team-aliases",
+		strings.NewReader(`{"alias": "Sheffield Wed", "canonical": "Sheffield Wednesday"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST = %d %s", rec.Code, rec.Body)
+	}
+	if got := models.CanonicalTeamName("sheffield wed"); got != "Sheffield Wednesday" {
+		t.Errorf("alias not applied: %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleTeamAliases(rec, httptest.NewRequest(http.MethodGet, "/team-aliases?q=sheffield", nil))
+	if !strings.Contains(rec.Body.String(), `"source":"api"`) {
+		t.Errorf("GET = %s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	c.handleAdminTeamAliases(rec, httptest.NewRequest(http.MethodDelete, "/admin/team-aliases?alias=Sheffield+Wed", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"removed":true`) {
+		t.Errorf("DELETE = %d %s", rec.Code, rec.Body)
+	}
+}
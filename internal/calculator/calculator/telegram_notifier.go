@@ -9,41 +9,59 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
-// Min interval between any two Telegram messages to the same chat to avoid 429 Too Many Requests (~30/min limit).
-const telegramSendInterval = 2 * time.Second
-
 // messageType represents the type of message to send
 type messageType int
 
 const (
 	messageTypeDiff messageType = iota
 	messageTypeLineMovement
+	messageTypeSteam
+	messageTypeValueClosed
 	messageTypeTest
+	messageTypeChannelSummary
+	messageTypeDigest
 )
 
 // queuedMessage represents a message queued for sending
 type queuedMessage struct {
 	msgType         messageType
+	chatID          int64 // 0 = telegram_chat_id
 	text            string
 	diff            *DiffBet
 	threshold       int
+	prevDiffPercent float64 // value increased: diff percent of the previous alert (0 = first alert)
+	closed          *valueClosed
 	lineMovement    *LineMovement
-	thresholdPercent float64
+	lmThreshold     lineMovementThreshold
 	now             time.Time
 	history         []storage.OddsHistoryPoint
+	steam           *SteamMove
 	testMessage     string // For test alerts
+	summary         *channelSummary
+	digest          *digest
+	trace           trace.SpanContext // span of the cycle that queued the alert (telegram.send is its child)
 }
 
 // TelegramNotifier sends Telegram notifications for high-value diffs
 type TelegramNotifier struct {
-	bot      *tgbotapi.BotAPI
-	chatID   int64
-	mu       sync.Mutex
-	lastSend time.Time
+	bot    *tgbotapi.BotAPI
+	chatID int64
+
+	// sender keeps the messages within the Telegram rate limits and retries them (telegram.delivery)
+	sender *tgsend.Sender
 
 	// Async queue for sending messages
 	queue     chan queuedMessage
@@ -54,6 +72,24 @@ type TelegramNotifier struct {
 
 	// clearCh: send a channel here; messageSender drains queue then sends dropped count and closes
 	clearCh chan chan int
+
+	// ignores gets the match of every alert so its "🚫 Ignore match" button can be resolved (nil = no button)
+	ignores *ignoreList
+
+	// templates render the messages (nil = built-in templates)
+	templates *tgformat.Templates
+
+	// languages are the languages chats chose for their alerts (nil = telegram.language for all)
+	languages *chatLanguageList
+
+	// channels are the channels and groups alerts are published to (value_calculator.telegram.channels)
+	channels *channelPublisher
+
+	// charts: line movement alerts get their odds chart as a reply (value_calculator.line_movement_charts)
+	charts bool
+
+	// dedup drops alerts a user already got in their chat or a channel they are in (nil = no deduplication)
+	dedup *deliveryDedup
 }
 
 // NewTelegramNotifier creates a new Telegram notifier
@@ -78,6 +114,7 @@ func NewTelegramNotifier(token string, chatID int64) *TelegramNotifier {
 	notifier := &TelegramNotifier{
 		bot:       bot,
 		chatID:    chatID,
+		sender:    tgsend.New(bot, config.TelegramDeliveryConfig{}),
 		queue:     make(chan queuedMessage, 100), // Buffer up to 100 messages
 		queueDone: make(chan struct{}),
 		ctx:       ctx,
@@ -94,6 +131,28 @@ func NewTelegramNotifier(token string, chatID int64) *TelegramNotifier {
 	return notifier
 }
 
+// SetTelegramTemplates makes the alerts render with templates (telegram.templates in the config).
+func (c *ValueCalculator) SetTelegramTemplates(templates *tgformat.Templates) {
+	if c.notifier != nil {
+		c.notifier.templates = templates
+	}
+}
+
+// SetTelegramDelivery sets the rate limits and retries of the alerts (telegram.delivery in the config).
+func (c *ValueCalculator) SetTelegramDelivery(cfg config.TelegramDeliveryConfig) {
+	if c.notifier != nil {
+		c.notifier.sender = tgsend.New(c.notifier.bot, cfg)
+	}
+}
+
+// DeliveryStats returns the delivery counters of the alerts.
+func (n *TelegramNotifier) DeliveryStats() tgsend.Stats {
+	if n == nil {
+		return tgsend.Stats{}
+	}
+	return n.sender.Stats()
+}
+
 // QueueLen returns current number of messages in the send queue (for logging).
 func (n *TelegramNotifier) QueueLen() int {
 	if n == nil || n.queue == nil {
@@ -165,25 +224,57 @@ outer:
 // sendQueuedMessage sends a queued message with proper rate limiting
 func (n *TelegramNotifier) sendQueuedMessage(msg queuedMessage) {
 	var messageText string
+	chatID := msg.chatID
+	if chatID == 0 {
+		chatID = n.chatID
+	}
+	lang := n.languages.get(chatID)
+
+	alertType, betKey := alertTypeOf(msg.msgType), alertBetKey(msg)
+	if via, dup := n.dedup.duplicate(chatID, n.broadcastChats(), alertType, betKey, time.Now()); dup {
+		slog.Info("Telegram send: skipped duplicate alert", "chat_id", chatID, "type", msg.msgType, "bet", betKey, "delivered_to", via)
+		return
+	}
 	
 	switch msg.msgType {
 	case messageTypeDiff:
-		messageText = n.formatDiffAlert(msg.diff, msg.threshold)
+		messageText = n.formatDiffAlert(lang, msg.diff, msg.threshold, msg.prevDiffPercent)
+	case messageTypeValueClosed:
+		messageText = n.formatValueClosedAlert(lang, msg.closed)
 	case messageTypeLineMovement:
-		messageText = n.formatLineMovementAlert(msg.lineMovement, msg.thresholdPercent, msg.now, msg.history)
+		messageText = n.formatLineMovementAlert(lang, msg.lineMovement, msg.lmThreshold, msg.now, msg.history)
+	case messageTypeSteam:
+		messageText = n.formatSteamAlert(lang, msg.steam)
 	case messageTypeTest:
 		messageText = msg.testMessage
+	case messageTypeChannelSummary:
+		messageText = n.formatChannelSummary(lang, msg.summary)
+	case messageTypeDigest:
+		messageText = n.formatDigest(lang, msg.digest)
 	default:
 		slog.Error("Unknown message type", "type", msg.msgType)
 		return
 	}
 	
-	tgMsg := tgbotapi.NewMessage(n.chatID, messageText)
-	tgMsg.ParseMode = tgbotapi.ModeMarkdown
+	tgMsg := tgbotapi.NewMessage(chatID, messageText)
+	tgMsg.ParseMode = tgbotapi.ModeHTML
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	if url, bookmaker := alertURL(msg); url != "" {
+		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(n.templates.Text(lang, tgformat.TextOpenBookmaker, bookmaker), url),
+		))
+	}
+	// Channel readers don't get the ignore button: it would mute the match for every chat
+	if key, name := alertMatch(msg); key != "" && n.ignores != nil && !n.channels.has(chatID) {
+		keyboard = append(keyboard, ignoreKeyboard(n.ignores.remember(key, name), n.templates.Text(lang, tgformat.TextIgnoreMatch)).InlineKeyboard...)
+	}
+	if len(keyboard) > 0 {
+		tgMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	}
 	
 	// Log before waiting for interval
 	queueTime := time.Now()
-	prepLogArgs := []interface{}{"type", msg.msgType, "queue_time", queueTime.UTC().Format(time.RFC3339), "message_preview", truncateString(messageText, 50)}
+	prepLogArgs := []interface{}{"type", msg.msgType, "chat_id", chatID, "queue_time", queueTime.UTC().Format(time.RFC3339), "message_preview", truncateString(messageText, 50)}
 	switch msg.msgType {
 	case messageTypeDiff:
 		if msg.diff != nil {
@@ -193,40 +284,28 @@ func (n *TelegramNotifier) sendQueuedMessage(msg queuedMessage) {
 		if msg.lineMovement != nil {
 			prepLogArgs = append(prepLogArgs, "match", msg.lineMovement.MatchName, "detected_at", msg.now.UTC().Format(time.RFC3339), "change_percent", msg.lineMovement.ChangePercent)
 		}
-	}
-	slog.Info("Telegram send: preparing to send message", prepLogArgs...)
-	
-	// Wait for proper interval
-	n.mu.Lock()
-	elapsed := time.Since(n.lastSend)
-	waitStart := time.Now()
-	if elapsed < telegramSendInterval {
-		waitTime := telegramSendInterval - elapsed
-		slog.Info("Telegram send: waiting for rate limit", 
-			"elapsed_since_last", elapsed,
-			"wait_time", waitTime,
-			"type", msg.msgType)
-		n.mu.Unlock()
-		select {
-		case <-n.ctx.Done():
-			slog.Warn("Telegram send: cancelled during wait", "type", msg.msgType)
-			return
-		case <-time.After(waitTime):
+	case messageTypeSteam:
+		if msg.steam != nil {
+			prepLogArgs = append(prepLogArgs, "match", msg.steam.MatchName, "detected_at", msg.steam.DetectedAt.UTC().Format(time.RFC3339), "bookmakers", msg.steam.Bookmakers)
+		}
+	case messageTypeValueClosed:
+		if msg.closed != nil {
+			prepLogArgs = append(prepLogArgs, "match", msg.closed.alerted.MatchName, "alerted_diff_percent", msg.closed.alerted.DiffPercent)
 		}
-		n.mu.Lock()
 	}
-	actualWait := time.Since(waitStart)
+	slog.Info("Telegram send: preparing to send message", prepLogArgs...)
 	
+	// Waits for the global and per-chat limits (telegram.delivery), retries 429 and network errors
+	sendCtx, span := tracing.Start(trace.ContextWithSpanContext(n.ctx, msg.trace), "telegram.send",
+		attribute.Int64("chat_id", chatID), attribute.String("alert_type", alertType))
 	sendStart := time.Now()
-	timeBeforeSend := n.lastSend
-	n.lastSend = time.Now()
-	_, err := n.bot.Send(tgMsg)
+	sent, err := n.sender.SendTopicMessage(sendCtx, tgMsg, n.channels.threadOf(chatID, msg))
 	sendDuration := time.Since(sendStart)
+	tracing.End(span, err)
 	totalDuration := time.Since(queueTime)
-	timeSinceLast := time.Since(timeBeforeSend)
-	n.mu.Unlock()
 	
 	sentAt := time.Now()
+	recordAlertSent(msg, err)
 	extra := n.logSentExtraFields(msg, sentAt)
 	if err != nil {
 		args := append([]interface{}{
@@ -234,9 +313,7 @@ func (n *TelegramNotifier) sendQueuedMessage(msg queuedMessage) {
 			"type", msg.msgType,
 			"sent_at", sentAt.UTC().Format(time.RFC3339),
 			"total_duration", totalDuration,
-			"wait_duration", actualWait,
 			"send_duration", sendDuration,
-			"time_since_last_send", timeSinceLast,
 		}, extra...)
 		slog.Error("Telegram send: failed", args...)
 	} else {
@@ -244,13 +321,61 @@ func (n *TelegramNotifier) sendQueuedMessage(msg queuedMessage) {
 			"type", msg.msgType,
 			"sent_at", sentAt.UTC().Format(time.RFC3339),
 			"total_duration", totalDuration,
-			"wait_duration", actualWait,
 			"send_duration", sendDuration,
-			"time_since_last_send", timeSinceLast,
 			"queue_length", len(n.queue),
 		}, extra...)
 		slog.Info("Telegram send: success", args...)
+		n.channels.record(chatID, msg)
+		n.dedup.record(chatID, alertType, betKey, sentAt)
+		if alertType != "" {
+			metrics.AlertsSent.WithLabelValues(alertType).Inc()
+		}
+		if msg.summary != nil {
+			n.pinChannelSummary(msg.summary, sent.MessageID)
+		}
+		if msg.msgType == messageTypeLineMovement && n.charts {
+			n.sendLineMovementChart(chatID, msg, sent.MessageID)
+		}
+	}
+}
+
+// alertMatch returns the match group key and name of a value/line-movement/steam alert ("" for other messages).
+func alertMatch(msg queuedMessage) (key, name string) {
+	switch msg.msgType {
+	case messageTypeDiff:
+		if msg.diff != nil {
+			return msg.diff.MatchGroupKey, msg.diff.MatchName
+		}
+	case messageTypeLineMovement:
+		if msg.lineMovement != nil {
+			return msg.lineMovement.MatchGroupKey, msg.lineMovement.MatchName
+		}
+	case messageTypeSteam:
+		if msg.steam != nil {
+			return msg.steam.MatchGroupKey, msg.steam.MatchName
+		}
+	case messageTypeValueClosed:
+		if msg.closed != nil {
+			return msg.closed.alerted.MatchGroupKey, msg.closed.alerted.MatchName
+		}
 	}
+	return "", ""
+}
+
+// alertURL returns the event page at the bookmaker to bet on of a value/line-movement alert and the
+// bookmaker ("" when the parser gave no page or for other messages).
+func alertURL(msg queuedMessage) (url, bookmaker string) {
+	switch msg.msgType {
+	case messageTypeDiff:
+		if msg.diff != nil {
+			return msg.diff.MaxBookmakerURL, msg.diff.MaxBookmaker
+		}
+	case messageTypeLineMovement:
+		if msg.lineMovement != nil {
+			return msg.lineMovement.BookmakerURL, msg.lineMovement.Bookmaker
+		}
+	}
+	return "", ""
 }
 
 // logSentExtraFields returns extra log fields for value/line-movement alerts (when the message was calculated/detected vs sent).
@@ -274,6 +399,14 @@ func (n *TelegramNotifier) logSentExtraFields(msg queuedMessage, sentAt time.Tim
 				"delay_since_detection_sec", delay.Seconds(),
 			}
 		}
+	case messageTypeSteam:
+		if msg.steam != nil {
+			return []interface{}{
+				"match", msg.steam.MatchName,
+				"detected_at", msg.steam.DetectedAt.UTC().Format(time.RFC3339),
+				"delay_since_detection_sec", sentAt.Sub(msg.steam.DetectedAt).Seconds(),
+			}
+		}
 	}
 	return nil
 }
@@ -291,8 +424,14 @@ func (n *TelegramNotifier) SendTestAlert(ctx context.Context, message string) er
 	if n == nil || n.bot == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
+	if n.chatID == 0 {
+		return fmt.Errorf("telegram_chat_id is not set")
+	}
 
-	testMsg := fmt.Sprintf("🧪 *Test Alert*\n\n%s\n\n_Time: %s_", message, time.Now().UTC().Format("2006-01-02 15:04:05 UTC"))
+	testMsg := n.templates.Render(n.languages.get(n.chatID), tgformat.TestAlert, struct {
+		Message string
+		Time    time.Time
+	}{message, time.Now()})
 
 	select {
 	case <-n.ctx.Done():
@@ -321,8 +460,8 @@ func (n *TelegramNotifier) Stop() {
 	n.wg.Wait()
 }
 
-// SendDiffAlert queues an alert for a high-value diff (non-blocking)
-func (n *TelegramNotifier) SendDiffAlert(ctx context.Context, diff *DiffBet, threshold int) error {
+// SendDiffAlert queues an alert for a high-value diff to chatID (non-blocking)
+func (n *TelegramNotifier) SendDiffAlert(ctx context.Context, chatID int64, diff *DiffBet, threshold int) error {
 	if n == nil || n.bot == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
@@ -334,8 +473,10 @@ func (n *TelegramNotifier) SendDiffAlert(ctx context.Context, diff *DiffBet, thr
 		return ctx.Err()
 	case n.queue <- queuedMessage{
 		msgType:   messageTypeDiff,
+		chatID:    chatID,
 		diff:      diff,
 		threshold: threshold,
+		trace:     trace.SpanContextFromContext(ctx),
 	}:
 		return nil
 	default:
@@ -345,10 +486,48 @@ func (n *TelegramNotifier) SendDiffAlert(ctx context.Context, diff *DiffBet, thr
 	}
 }
 
-// SendLineMovementAlert queues an alert for a significant odds change in the same bookmaker (non-blocking).
+// SendValueIncreasedAlert queues a repeated alert for a value that grew since its previous alert (non-blocking).
+func (n *TelegramNotifier) SendValueIncreasedAlert(ctx context.Context, chatID int64, diff *DiffBet, threshold int, prevDiffPercent float64) error {
+	if n == nil || n.bot == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	select {
+	case <-n.ctx.Done():
+		return fmt.Errorf("notifier stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case n.queue <- queuedMessage{msgType: messageTypeDiff, chatID: chatID, diff: diff, threshold: threshold, prevDiffPercent: prevDiffPercent, trace: trace.SpanContextFromContext(ctx)}:
+		return nil
+	default:
+		slog.Warn("Telegram message queue is full, dropping message", "match", diff.MatchName)
+		return fmt.Errorf("message queue is full")
+	}
+}
+
+// SendValueClosedAlert queues a notification that an alerted value disappeared to the chat it was alerted to (non-blocking).
+func (n *TelegramNotifier) SendValueClosedAlert(ctx context.Context, closed *valueClosed) error {
+	if n == nil || n.bot == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	select {
+	case <-n.ctx.Done():
+		return fmt.Errorf("notifier stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case n.queue <- queuedMessage{msgType: messageTypeValueClosed, chatID: closed.chatID, closed: closed, trace: trace.SpanContextFromContext(ctx)}:
+		return nil
+	default:
+		slog.Warn("Telegram message queue is full, dropping value closed message", "match", closed.alerted.MatchName)
+		return fmt.Errorf("message queue is full")
+	}
+}
+
+// SendLineMovementAlert queues an alert for a significant odds change in the same bookmaker to chatID (non-blocking).
 // history is used to show timeline (e.g. "6.70 (12 min ago) → 7.10 (now)").
-// thresholdPercent is the min change in % that triggered the alert (e.g. 5.0 for 5%).
-func (n *TelegramNotifier) SendLineMovementAlert(ctx context.Context, lm *LineMovement, thresholdPercent float64, now time.Time, history []storage.OddsHistoryPoint) error {
+// threshold is the min change (in % and/or pp) that triggered the alert.
+func (n *TelegramNotifier) SendLineMovementAlert(ctx context.Context, chatID int64, lm *LineMovement, threshold lineMovementThreshold, now time.Time, history []storage.OddsHistoryPoint) error {
 	if n == nil || n.bot == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
@@ -364,10 +543,12 @@ func (n *TelegramNotifier) SendLineMovementAlert(ctx context.Context, lm *LineMo
 		return ctx.Err()
 	case n.queue <- queuedMessage{
 		msgType:         messageTypeLineMovement,
+		chatID:          chatID,
 		lineMovement:    lm,
-		thresholdPercent: thresholdPercent,
+		lmThreshold:     threshold,
 		now:             now,
 		history:         historyCopy,
+		trace:           trace.SpanContextFromContext(ctx),
 	}:
 		return nil
 	default:
@@ -377,104 +558,78 @@ func (n *TelegramNotifier) SendLineMovementAlert(ctx context.Context, lm *LineMo
 	}
 }
 
-func (n *TelegramNotifier) formatLineMovementAlert(lm *LineMovement, thresholdPercent float64, now time.Time, history []storage.OddsHistoryPoint) string {
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("📊 *Line movement (≥%.1f%%)*\n\n", thresholdPercent))
-	builder.WriteString(fmt.Sprintf("*%s*\n", escapeMarkdown(lm.MatchName)))
-	builder.WriteString(fmt.Sprintf("📌 %s | %s", formatEventType(lm.EventType), formatOutcomeType(lm.OutcomeType)))
-	if lm.Parameter != "" {
-		builder.WriteString(fmt.Sprintf(" (%s)", lm.Parameter))
-	}
-	builder.WriteString("\n\n")
-	bookmakerLabel := strings.TrimSpace(lm.Bookmaker)
-	if bookmakerLabel == "" {
-		bookmakerLabel = "—"
-	}
-	builder.WriteString(fmt.Sprintf("🏠 *%s*\n", escapeMarkdown(bookmakerLabel)))
-	changeStr := fmt.Sprintf("%+.1f%%", lm.ChangePercent)
-	builder.WriteString(fmt.Sprintf("Was: *%.2f* → now: *%.2f* (%s)\n", lm.PreviousOdd, lm.CurrentOdd, changeStr))
-	// Timeline: collapse consecutive same odds, e.g. "6.70 (12 min ago) → 6.85 (5 min ago) → 7.10 (now)"
+// lineMovementPoint is a point of the line movement alert timeline.
+type lineMovementPoint struct {
+	Odd        float64
+	MinutesAgo int // 0 = now
+}
+
+func (n *TelegramNotifier) formatLineMovementAlert(lang string, lm *LineMovement, threshold lineMovementThreshold, now time.Time, history []storage.OddsHistoryPoint) string {
+	data := struct {
+		LineMovement
+		Threshold string
+		// Timeline collapses consecutive same odds, e.g. "6.70 (12 min ago) → 6.85 (5 min ago) → 7.10 (now)"
+		Timeline []lineMovementPoint
+		// FirstSeenMinutesAgo is when the movement was first recorded (last history point), so the user sees data age
+		FirstSeenMinutesAgo int
+	}{LineMovement: *lm, Threshold: threshold.String()}
+	data.Bookmaker = strings.TrimSpace(lm.Bookmaker)
 	if len(history) > 0 {
-		timeline := collapseConsecutiveOdds(history)
-		builder.WriteString("Timeline: ")
-		for i, p := range timeline {
-			if i > 0 {
-				builder.WriteString(" → ")
-			}
-			mins := int(now.Sub(p.RecordedAt).Minutes())
-			if mins <= 0 {
-				builder.WriteString(fmt.Sprintf("*%.2f* (now)", p.Odd))
-			} else {
-				builder.WriteString(fmt.Sprintf("*%.2f* (%d min ago)", p.Odd, mins))
-			}
-		}
-		builder.WriteString("\n")
-		// Show when this movement was first recorded (last history point) so user sees data age
-		lastRecorded := history[len(history)-1].RecordedAt
-		dataMins := int(now.Sub(lastRecorded).Minutes())
-		if dataMins > 0 {
-			builder.WriteString(fmt.Sprintf("📅 _Movement first seen %d min ago_\n", dataMins))
+		for _, p := range collapseConsecutiveOdds(history) {
+			data.Timeline = append(data.Timeline, lineMovementPoint{Odd: p.Odd, MinutesAgo: int(now.Sub(p.RecordedAt).Minutes())})
 		}
+		data.FirstSeenMinutesAgo = int(now.Sub(history[len(history)-1].RecordedAt).Minutes())
 	}
-	if !lm.StartTime.IsZero() {
-		builder.WriteString(fmt.Sprintf("🕐 Kick-off: %s\n", formatTime(lm.StartTime)))
-	}
-	if lm.Sport != "" {
-		builder.WriteString(fmt.Sprintf("🏆 %s\n", lm.Sport))
-	}
-	return builder.String()
+	return n.templates.Render(lang, tgformat.LineMovementAlert, data)
 }
 
-// formatDiffAlert formats a diff bet as a Telegram message (English).
-func (n *TelegramNotifier) formatDiffAlert(diff *DiffBet, threshold int) string {
-	var builder strings.Builder
-
-	builder.WriteString(fmt.Sprintf("🚨 *Value Bet Alert (%d%%+)*\n\n", threshold))
-	builder.WriteString(fmt.Sprintf("*%s*\n", escapeMarkdown(diff.MatchName)))
-	builder.WriteString(fmt.Sprintf("⚽ %s | %s", formatEventType(diff.EventType), formatOutcomeType(diff.OutcomeType)))
-	if diff.Parameter != "" {
-		builder.WriteString(fmt.Sprintf(" (%s)", diff.Parameter))
-	}
-	builder.WriteString("\n\n")
-	builder.WriteString(fmt.Sprintf("📈 *Difference: %.2f%%*\n", diff.DiffPercent))
-	builder.WriteString(fmt.Sprintf("💰 %s: %.2f | %s: %.2f\n", diff.MinBookmaker, diff.MinOdd, diff.MaxBookmaker, diff.MaxOdd))
-	if !diff.StartTime.IsZero() {
-		builder.WriteString(fmt.Sprintf("🕐 Kick-off: %s\n", formatTime(diff.StartTime)))
+// SendSteamAlert queues an alert for an outcome moving at several bookmakers at once to chatID (non-blocking).
+func (n *TelegramNotifier) SendSteamAlert(ctx context.Context, chatID int64, steam *SteamMove) error {
+	if n == nil || n.bot == nil {
+		return fmt.Errorf("telegram notifier not initialized")
 	}
-	if diff.Sport != "" {
-		builder.WriteString(fmt.Sprintf("🏆 %s\n", diff.Sport))
+
+	select {
+	case <-n.ctx.Done():
+		return fmt.Errorf("notifier stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case n.queue <- queuedMessage{msgType: messageTypeSteam, chatID: chatID, steam: steam, trace: trace.SpanContextFromContext(ctx)}:
+		return nil
+	default:
+		slog.Warn("Telegram message queue is full, dropping steam message", "match", steam.MatchName)
+		return fmt.Errorf("message queue is full")
 	}
-	return builder.String()
 }
 
-func formatTime(t time.Time) string {
-	if t.IsZero() {
-		return "N/A"
-	}
-	return t.Format("2006-01-02 15:04 UTC")
+func (n *TelegramNotifier) formatSteamAlert(lang string, steam *SteamMove) string {
+	return n.templates.Render(lang, tgformat.SteamAlert, struct {
+		*SteamMove
+		ConsensusPercent float64
+	}{steam, steam.Consensus * 100})
 }
 
-func formatEventType(eventType string) string {
-	parts := strings.Split(eventType, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
-		}
-	}
-	return strings.Join(parts, " ")
+// formatDiffAlert formats a diff bet as a Telegram message in lang. prevDiffPercent > 0 is a repeated
+// alert for a value that grew since the previous one.
+func (n *TelegramNotifier) formatDiffAlert(lang string, diff *DiffBet, threshold int, prevDiffPercent float64) string {
+	return n.templates.Render(lang, tgformat.ValueAlert, struct {
+		*DiffBet
+		Threshold       int
+		Cyber           bool
+		PrevDiffPercent float64
+		Increase        float64
+	}{diff, threshold, strings.EqualFold(diff.Sport, string(enums.CyberFootball)), prevDiffPercent, diff.DiffPercent - prevDiffPercent})
 }
 
-func formatOutcomeType(outcomeType string) string {
-	parts := strings.Split(outcomeType, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
-		}
-	}
-	return strings.Join(parts, " ")
+// formatValueClosedAlert formats a notification that an alerted value disappeared.
+func (n *TelegramNotifier) formatValueClosedAlert(lang string, closed *valueClosed) string {
+	return n.templates.Render(lang, tgformat.ValueClosed, struct {
+		Alerted DiffBet
+		Current *DiffBet // nil = no value at the bookmaker anymore
+	}{closed.alerted, closed.current})
 }
 
-// collapseConsecutiveOdds keeps first, last, and points where odd changed (shorter timeline).
+// collapseConsecutiveOdds keeps first, last, and points where odd changed beyond the odds epsilon (shorter timeline).
 func collapseConsecutiveOdds(history []storage.OddsHistoryPoint) []storage.OddsHistoryPoint {
 	if len(history) <= 2 {
 		return history
@@ -482,34 +637,10 @@ func collapseConsecutiveOdds(history []storage.OddsHistoryPoint) []storage.OddsH
 	var out []storage.OddsHistoryPoint
 	out = append(out, history[0])
 	for i := 1; i < len(history)-1; i++ {
-		if history[i].Odd != history[i-1].Odd || history[i].Odd != history[i+1].Odd {
+		if !models.OddsEqual(history[i].Odd, history[i-1].Odd) || !models.OddsEqual(history[i].Odd, history[i+1].Odd) {
 			out = append(out, history[i])
 		}
 	}
 	out = append(out, history[len(history)-1])
 	return out
 }
-
-func escapeMarkdown(text string) string {
-	replacer := strings.NewReplacer(
-		"_", "\\_",
-		"*", "\\*",
-		"[", "\\[",
-		"]", "\\]",
-		"(", "\\(",
-		")", "\\)",
-		"~", "\\~",
-		"`", "\\`",
-		">", "\\>",
-		"#", "\\#",
-		"+", "\\+",
-		"-", "\\-",
-		"=", "\\=",
-		"|", "\\|",
-		"{", "\\{",
-		"}", "\\}",
-		".", "\\.",
-		"!", "\\!",
-	)
-	return replacer.Replace(text)
-}
@@ -1,6 +1,11 @@
 package calculator
 
-import "time"
+import (
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+)
 
 // DiffBet represents a "same bet" odds diff between bookmakers.
 type DiffBet struct {
@@ -8,17 +13,22 @@ type DiffBet struct {
 	MatchName     string    `json:"match_name"`
 	StartTime     time.Time `json:"start_time"`
 	Sport         string    `json:"sport"`
+	Tournament    string    `json:"tournament,omitempty"`
 
 	EventType    string `json:"event_type"`   // e.g. main_match, corners
 	OutcomeType  string `json:"outcome_type"` // e.g. total_over, home_win
 	Parameter    string `json:"parameter"`    // e.g. 2.5, +1.5
-	BetKey       string `json:"bet_key"`      // eventType|outcomeType|parameter
+	Participant  string `json:"participant,omitempty"` // team or player of a team/player market (models.ParticipantKey)
+	BetKey       string `json:"bet_key"`      // models.BetKey
 	Bookmakers   int    `json:"bookmakers"`   // number of bookmakers contributing
 
-	MinBookmaker string  `json:"min_bookmaker"`
-	MinOdd       float64 `json:"min_odd"`
-	MaxBookmaker string  `json:"max_bookmaker"`
-	MaxOdd       float64 `json:"max_odd"`
+	MinBookmaker    string  `json:"min_bookmaker"`
+	MinOdd          float64 `json:"min_odd"`
+	MaxBookmaker    string  `json:"max_bookmaker"`
+	MaxBookmakerURL string  `json:"max_bookmaker_url,omitempty"` // event page at the max bookmaker, "" if unknown
+	MaxOdd          float64 `json:"max_odd"`
+	MaxSeenAt       time.Time `json:"max_seen_at,omitempty"` // when the parser last saw the max odd (zero = unknown)
+	MaxStale        bool      `json:"max_stale,omitempty"`   // max odd served from a bookmaker-service startup snapshot
 
 	DiffAbs     float64 `json:"diff_abs"`     // max - min
 	DiffPercent float64 `json:"diff_percent"` // (max/min - 1) * 100
@@ -26,49 +36,142 @@ type DiffBet struct {
 	CalculatedAt time.Time `json:"calculated_at"`
 }
 
-// ValueBet represents a value bet found using weighted average of reference bookmakers.
-type ValueBet struct {
+// ValueBet is the value bet of the API, shared with its Go client.
+type ValueBet = client.ValueBet
+
+// OutrightValueBet represents a value bet on an outright (futures) market.
+// Outrights are grouped by tournament and market rather than by team pair.
+type OutrightValueBet struct {
+	GroupKey    string    `json:"group_key"` // sport|tournament|market (normalized)
+	Sport       string    `json:"sport"`
+	Tournament  string    `json:"tournament"`
+	MarketName  string    `json:"market_name"`
+	Participant string    `json:"participant"`
+	CloseTime   time.Time `json:"close_time"`
+
+	AllBookmakerOdds map[string]float64 `json:"all_bookmaker_odds"`
+	FairOdd          float64            `json:"fair_odd"`
+	FairProbability  float64            `json:"fair_probability"`
+
+	Bookmaker     string  `json:"bookmaker"`
+	BookmakerOdd  float64 `json:"bookmaker_odd"`
+	ValuePercent  float64 `json:"value_percent"`
+	ExpectedValue float64 `json:"expected_value"`
+
+	CalculatedAt time.Time `json:"calculated_at"`
+}
+
+// LineMovement is the line movement of the API, shared with its Go client.
+type LineMovement = client.LineMovement
+
+// SteamMove is one outcome moving the same way at several bookmakers within the steam window.
+type SteamMove struct {
 	MatchGroupKey string    `json:"match_group_key"`
 	MatchName     string    `json:"match_name"`
 	StartTime     time.Time `json:"start_time"`
 	Sport         string    `json:"sport"`
 
-	EventType   string `json:"event_type"`   // e.g. main_match, corners
-	OutcomeType string `json:"outcome_type"` // e.g. total_over, home_win
-	Parameter   string `json:"parameter"`   // e.g. 2.5, +1.5
-	BetKey      string `json:"bet_key"`      // eventType|outcomeType|parameter
+	EventType   string `json:"event_type"`
+	OutcomeType string `json:"outcome_type"`
+	Parameter   string `json:"parameter"`
+	BetKey      string `json:"bet_key"`
 
-	// Reference data (средневзвешенное от всех контор)
-	AllBookmakerOdds map[string]float64 `json:"all_bookmaker_odds"` // все коэффициенты от всех контор для этого исхода
-	FairOdd          float64            `json:"fair_odd"`            // справедливый коэффициент (1 / avg_probability)
-	FairProbability  float64            `json:"fair_probability"`   // справедливая вероятность (средневзвешенная)
+	Direction      string     `json:"direction"`         // shortening (odds falling, money in) or drifting
+	Bookmakers     int        `json:"bookmakers"`        // bookmakers moving in the direction
+	Consensus      float64    `json:"consensus"`         // share of bookmakers quoting the outcome that moved in the direction
+	AvgProbShiftPP float64    `json:"avg_prob_shift_pp"` // mean implied probability shift of the legs
+	Legs           []SteamLeg `json:"legs"`
+	DetectedAt     time.Time  `json:"detected_at"`
+}
 
-	// Value bet data
-	Bookmaker    string  `json:"bookmaker"`     // контора с валуем
-	BookmakerOdd float64 `json:"bookmaker_odd"` // её коэффициент
-	ValuePercent float64 `json:"value_percent"`  // процент валуя: (bookmaker_odd / fair_odd - 1) * 100
-	ExpectedValue float64 `json:"expected_value"` // математическое ожидание: (bookmaker_odd * fair_probability) - 1
+// SteamLeg is the move of one bookmaker within a steam.
+type SteamLeg struct {
+	Bookmaker   string             `json:"bookmaker"`
+	FromOdd     float64            `json:"from_odd"` // odd at the start of the window
+	ToOdd       float64            `json:"to_odd"`
+	ProbShiftPP float64            `json:"prob_shift_pp"`
+	MovedAt     time.Time          `json:"moved_at"` // last change
+	Class       string             `json:"class"`    // opener, shortening, drifting or stable (since the first quote)
+	Velocity    map[string]float64 `json:"velocity"` // window ("5m", "1h") -> implied probability change in pp per minute
+}
 
-	CalculatedAt time.Time `json:"calculated_at"`
+// MatchProbabilities is the de-vigged model of one match (GET /matches/{group_key}/probabilities).
+type MatchProbabilities struct {
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	Tournament    string    `json:"tournament"`
+
+	Method              string               `json:"method"`                         // fair odds method (value_calculator.fair_odds_method or ?method=)
+	ReferenceBookmakers []string             `json:"reference_bookmakers,omitempty"` // empty = every bookmaker forms the consensus
+	Outcomes            []OutcomeProbability `json:"outcomes"`                       // sorted by bet key
+}
+
+// OutcomeProbability is the consensus and per-bookmaker probabilities of one outcome.
+type OutcomeProbability struct {
+	BetKey      string `json:"bet_key"`
+	EventType   string `json:"event_type"`
+	OutcomeType string `json:"outcome_type"`
+	Parameter   string `json:"parameter"`
+
+	FairProbability float64 `json:"fair_probability"` // consensus (weighted average, as for value bets); 0 = no reference quote
+	FairOdd         float64 `json:"fair_odd"`
+
+	Bookmakers map[string]BookmakerProbability `json:"bookmakers"`
+}
+
+// BookmakerProbability is one bookmaker's price of an outcome.
+type BookmakerProbability struct {
+	Odd                float64 `json:"odd"`
+	ImpliedProbability float64 `json:"implied_probability"` // 1/odd, margin included
+	FairProbability    float64 `json:"fair_probability"`    // margin removed; = implied when the bookmaker doesn't quote the whole market
+	Devigged           bool    `json:"devigged"`            // fair_probability is de-vigged
+	Reference          bool    `json:"reference"`           // part of the consensus
 }
 
-// LineMovement represents a significant odds change in the same bookmaker for the same bet.
-type LineMovement struct {
+// OddsMatrix is the odds of all bookmakers for every bet of one match (Telegram WebApp, /matches/search).
+type OddsMatrix = client.OddsMatrix
+
+// OddsMatrixRow is one bet of OddsMatrix.
+type OddsMatrixRow = client.OddsMatrixRow
+
+// LineInconsistency is a bookmaker price that contradicts the same bookmaker's 1X2: double chance and draw no
+// bet (Asian handicap 0) are derived from it (GET /diagnostics/inconsistencies).
+type LineInconsistency struct {
 	MatchGroupKey string    `json:"match_group_key"`
 	MatchName     string    `json:"match_name"`
 	StartTime     time.Time `json:"start_time"`
 	Sport         string    `json:"sport"`
-	Tournament    string    `json:"tournament"` // league/championship name for identification (e.g. when match is "Home vs Away")
-
-	EventType   string    `json:"event_type"`
-	OutcomeType string    `json:"outcome_type"`
-	Parameter   string    `json:"parameter"`
-	BetKey      string    `json:"bet_key"`
-	Bookmaker   string    `json:"bookmaker"`
-	PreviousOdd   float64   `json:"previous_odd"`
-	CurrentOdd    float64   `json:"current_odd"`
-	ChangeAbs     float64   `json:"change_abs"`     // current - previous (signed)
-	ChangePercent float64   `json:"change_percent"` // (current - previous) / previous * 100
-	RecordedAt    time.Time `json:"recorded_at"`
+	Bookmaker     string    `json:"bookmaker"`
+	EventType     string    `json:"event_type"` // event type of the 1X2, e.g. main_match, corners
+
+	Check            string  `json:"check"`        // double_chance or draw_no_bet
+	OutcomeType      string  `json:"outcome_type"` // quoted outcome, e.g. double_chance_1x, handicap_home
+	QuotedOdd        float64 `json:"quoted_odd"`
+	DerivedOdd       float64 `json:"derived_odd"`       // from the bookmaker's 1X2
+	DeviationPercent float64 `json:"deviation_percent"` // (quoted_odd / derived_odd - 1) * 100
+
+	HomeOdd float64 `json:"home_odd"`
+	DrawOdd float64 `json:"draw_odd"`
+	AwayOdd float64 `json:"away_odd"`
+
+	Dropped    bool      `json:"dropped"` // the bookmaker's 1X2, double chance and Asian 0 lines were left out of calculation
+	DetectedAt time.Time `json:"detected_at"`
 }
 
+// clientTeamMeta converts team metadata of a match to the API's (nil stays nil).
+func clientTeamMeta(m *models.TeamMeta) *client.TeamMeta {
+	if m == nil {
+		return nil
+	}
+	return &client.TeamMeta{Name: m.Name, Country: m.Country, League: m.League, LogoURL: m.LogoURL}
+}
+
+// clientLeagueMeta converts league metadata of a match to the API's (nil stays nil).
+func clientLeagueMeta(m *models.LeagueMeta) *client.LeagueMeta {
+	if m == nil {
+		return nil
+	}
+	return &client.LeagueMeta{Name: m.Name, Country: m.Country, LogoURL: m.LogoURL}
+}
@@ -0,0 +1,209 @@
+package calculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Value alert defaults (value_calculator.alert_cooldown_minutes / alert_min_increase).
+const (
+	defaultAlertCooldown    = 60 * time.Minute
+	defaultAlertMinIncrease = 5.0
+)
+
+// Value alert kinds returned by valueAlertTracker.check.
+const (
+	valueAlertNone      = ""          // already alerted: cooldown not over and the value didn't grow enough
+	valueAlertNew       = "new"       // first alert for the bet at this bookmaker
+	valueAlertIncreased = "increased" // grew by alert_min_increase since the last alert
+	valueAlertRepeat    = "repeat"    // still there after the cooldown
+)
+
+// valueAlertTracker is the alert state machine of a value pipeline, one state per chat, bet key and
+// bookmaker (the bookmaker with the best odd):
+//
+//	(none) --value--> active (alert "new")
+//	active --grew by alert_min_increase--> active (alert "increased")
+//	active --cooldown over--> active (alert "repeat")
+//	active --value gone--> closed (notification "closed", once per alert)
+//	closed --value back within cooldown--> active (silent unless it grew)
+//	closed --cooldown over--> (none)
+//
+// Each pipeline has its own tracker: a cycle only sees its own diffs.
+type valueAlertTracker struct {
+	mu          sync.Mutex
+	cooldown    time.Duration
+	minIncrease float64
+	states      map[string]*valueAlertState
+	cycles      int // finished cycles; alerts of the previous run are restored from diff storage in the first one
+}
+
+type valueAlertState struct {
+	chatID        int64
+	alerted       DiffBet   // diff of the last alert
+	alertedAt     time.Time // when it was queued
+	active        bool      // value seen in the last cycle
+	closeNotified bool      // "closed" already sent for the last alert
+}
+
+// valueClosed is an alerted value that disappeared.
+type valueClosed struct {
+	chatID  int64
+	alerted DiffBet  // diff of the last alert
+	current *DiffBet // current diff below the threshold, nil when the bet is no longer quoted
+}
+
+func newValueAlertTracker(cfg *config.ValueCalculatorConfig) *valueAlertTracker {
+	t := &valueAlertTracker{
+		cooldown:    defaultAlertCooldown,
+		minIncrease: defaultAlertMinIncrease,
+		states:      map[string]*valueAlertState{},
+	}
+	if cfg != nil && cfg.AlertCooldownMinutes > 0 {
+		t.cooldown = time.Duration(cfg.AlertCooldownMinutes) * time.Minute
+	}
+	if cfg != nil && cfg.AlertMinIncrease > 0 {
+		t.minIncrease = cfg.AlertMinIncrease
+	}
+	return t
+}
+
+// valueAlertKey identifies a value alert: bet key and the bookmaker with the best odd.
+func valueAlertKey(d *DiffBet) string {
+	return d.MatchGroupKey + "|" + d.BetKey + "|" + strings.ToLower(d.MaxBookmaker)
+}
+
+// valueAlertStateKey identifies the alert state of a value in a chat.
+func valueAlertStateKey(chatID int64, d *DiffBet) string {
+	return fmt.Sprintf("%d|%s", chatID, valueAlertKey(d))
+}
+
+// check marks the diff as above the chat's threshold in this cycle and returns the alert to send for it
+// (valueAlertNone = none) and the diff percent of the previous alert.
+func (t *valueAlertTracker) check(chatID int64, d *DiffBet, now time.Time) (kind string, prevPercent float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.states[valueAlertStateKey(chatID, d)]
+	if !ok {
+		return valueAlertNew, 0
+	}
+	wasActive := s.active
+	s.active = true
+	prevPercent = s.alerted.DiffPercent
+	switch {
+	case d.DiffPercent-prevPercent >= t.minIncrease:
+		return valueAlertIncreased, prevPercent
+	case now.Sub(s.alertedAt) < t.cooldown:
+		return valueAlertNone, prevPercent
+	case wasActive:
+		return valueAlertRepeat, prevPercent
+	}
+	return valueAlertNew, prevPercent
+}
+
+// seed records a value alerted before this tracker existed (previous run), so it isn't alerted again
+// within the cooldown. Ignored when the bet already has a state.
+func (t *valueAlertTracker) seed(chatID int64, d *DiffBet, diffPercent float64, alertedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := valueAlertStateKey(chatID, d)
+	if _, ok := t.states[key]; ok {
+		return
+	}
+	alerted := *d
+	alerted.DiffPercent = diffPercent
+	t.states[key] = &valueAlertState{chatID: chatID, alerted: alerted, alertedAt: alertedAt, active: true, closeNotified: true}
+}
+
+// firstCycle reports whether no cycle has finished yet.
+func (t *valueAlertTracker) firstCycle() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cycles == 0
+}
+
+// alerted records a queued alert.
+func (t *valueAlertTracker) alerted(chatID int64, d *DiffBet, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.states[valueAlertStateKey(chatID, d)] = &valueAlertState{chatID: chatID, alerted: *d, alertedAt: now, active: true}
+}
+
+// finishCycle closes the states whose value wasn't seen above the chat's threshold in this cycle (above is
+// keyed by valueAlertStateKey, current by valueAlertKey) and forgets closed states past the cooldown.
+// Returns the values to send a "closed" notification for; values of started matches are closed silently.
+func (t *valueAlertTracker) finishCycle(above map[string]bool, current map[string]*DiffBet, now time.Time) []valueClosed {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cycles++
+	var closed []valueClosed
+	for key, s := range t.states {
+		if above[key] {
+			continue
+		}
+		started := !s.alerted.StartTime.IsZero() && !s.alerted.StartTime.After(now)
+		if started || (!s.active && now.Sub(s.alertedAt) >= t.cooldown) {
+			delete(t.states, key)
+			continue
+		}
+		if !s.active {
+			continue
+		}
+		s.active = false
+		if s.closeNotified {
+			continue
+		}
+		s.closeNotified = true
+		closed = append(closed, valueClosed{chatID: s.chatID, alerted: s.alerted, current: current[valueAlertKey(&s.alerted)]})
+	}
+	return closed
+}
+
+// savedValueAlerts is a valueAlertTracker in the cycle state storage (value_calculator.leader_election).
+type savedValueAlerts struct {
+	Cycles int                             `json:"cycles"`
+	States map[string]savedValueAlertState `json:"states"`
+}
+
+type savedValueAlertState struct {
+	ChatID        int64     `json:"chat_id"`
+	Alerted       DiffBet   `json:"alerted"`
+	AlertedAt     time.Time `json:"alerted_at"`
+	Active        bool      `json:"active"`
+	CloseNotified bool      `json:"close_notified"`
+}
+
+// snapshot returns the tracker state as JSON.
+func (t *valueAlertTracker) snapshot() ([]byte, error) {
+	t.mu.Lock()
+	saved := savedValueAlerts{Cycles: t.cycles, States: make(map[string]savedValueAlertState, len(t.states))}
+	for key, s := range t.states {
+		saved.States[key] = savedValueAlertState{ChatID: s.chatID, Alerted: s.alerted, AlertedAt: s.alertedAt, Active: s.active, CloseNotified: s.closeNotified}
+	}
+	t.mu.Unlock()
+	return json.Marshal(saved)
+}
+
+// load replaces the tracker state with a snapshot. Nil data resets it: the next cycle is a first cycle again
+// and restores alerts from diff storage.
+func (t *valueAlertTracker) load(data []byte) error {
+	var saved savedValueAlerts
+	if data != nil {
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("failed to decode value alert state: %w", err)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cycles = saved.Cycles
+	t.states = make(map[string]*valueAlertState, len(saved.States))
+	for key, s := range saved.States {
+		t.states[key] = &valueAlertState{chatID: s.ChatID, alerted: s.Alerted, alertedAt: s.AlertedAt, active: s.Active, closeNotified: s.CloseNotified}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValueAlertTracker(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const chatID = 42
+	tr := &valueAlertTracker{cooldown: time.Hour, minIncrease: 5, states: map[string]*valueAlertState{}}
+	diff := func(percent float64, bookmaker string) *DiffBet {
+		return &DiffBet{MatchGroupKey: "football|arsenal|chelsea", BetKey: "main_match|home_win|", MaxBookmaker: bookmaker,
+			DiffPercent: percent, StartTime: now.Add(3 * time.Hour)}
+	}
+	cycle := func(at time.Time, diffs ...*DiffBet) (kinds []string, closed []valueClosed) {
+		above := map[string]bool{}
+		for _, d := range diffs {
+			above[valueAlertStateKey(chatID, d)] = true
+			kind, _ := tr.check(chatID, d, at)
+			if kind != valueAlertNone {
+				tr.alerted(chatID, d, at)
+			}
+			kinds = append(kinds, kind)
+		}
+		return kinds, tr.finishCycle(above, nil, at)
+	}
+
+	steps := []struct {
+		name       string
+		at         time.Duration
+		diffs      []*DiffBet
+		wantKinds  []string
+		wantClosed int
+	}{
+		{"first alert", 0, []*DiffBet{diff(12, "Fonbet")}, []string{valueAlertNew}, 0},
+		{"persists", time.Minute, []*DiffBet{diff(14, "Fonbet")}, []string{valueAlertNone}, 0},
+		{"other bookmaker", 2 * time.Minute, []*DiffBet{diff(14, "Fonbet"), diff(13, "Leon")}, []string{valueAlertNone, valueAlertNew}, 0},
+		{"increased", 3 * time.Minute, []*DiffBet{diff(17.5, "Fonbet"), diff(13, "Leon")}, []string{valueAlertIncreased, valueAlertNone}, 0},
+		{"gone at Leon", 4 * time.Minute, []*DiffBet{diff(17, "Fonbet")}, []string{valueAlertNone}, 1},
+		{"still gone", 5 * time.Minute, []*DiffBet{diff(17, "Fonbet")}, []string{valueAlertNone}, 0},
+		{"back at Leon within cooldown", 6 * time.Minute, []*DiffBet{diff(17, "Fonbet"), diff(14, "Leon")}, []string{valueAlertNone, valueAlertNone}, 0},
+		{"gone again, already notified", 7 * time.Minute, []*DiffBet{diff(17, "Fonbet")}, []string{valueAlertNone}, 0},
+		{"cooldown over", 64 * time.Minute, []*DiffBet{diff(17, "Fonbet")}, []string{valueAlertRepeat}, 0},
+		{"back at Leon after cooldown", 70 * time.Minute, []*DiffBet{diff(17, "Fonbet"), diff(14, "Leon")}, []string{valueAlertNone, valueAlertNew}, 0},
+	}
+	for _, s := range steps {
+		kinds, closed := cycle(now.Add(s.at), s.diffs...)
+		if strings.Join(kinds, ",") != strings.Join(s.wantKinds, ",") || len(closed) != s.wantClosed {
+			t.Errorf("%s: got alerts %q and %d closed, want %q and %d", s.name, kinds, len(closed), s.wantKinds, s.wantClosed)
+		}
+	}
+
+	// Started matches are closed silently
+	if _, closed := cycle(now.Add(4 * time.Hour)); len(closed) != 0 || len(tr.states) != 0 {
+		t.Errorf("started match: got %d closed and %d states", len(closed), len(tr.states))
+	}
+
+	// A value alerted by the previous run is not alerted again within the cooldown
+	tr.seed(chatID, diff(12, "Fonbet"), 12, now.Add(-10*time.Minute))
+	if kind, prev := tr.check(chatID, diff(13, "Fonbet"), now); kind != valueAlertNone || prev != 12 {
+		t.Errorf("seeded value: got %q (prev %v)", kind, prev)
+	}
+	// ...in that chat only
+	if kind, _ := tr.check(7, diff(13, "Fonbet"), now); kind != valueAlertNew {
+		t.Errorf("other chat: got %q, want %q", kind, valueAlertNew)
+	}
+}
+
+func TestValueAlertFormatting(t *testing.T) {
+	n := &TelegramNotifier{}
+	last := DiffBet{MatchName: "Arsenal vs Chelsea", EventType: "main_match", OutcomeType: "home_win", Sport: "football",
+		DiffPercent: 12.5, MaxBookmaker: "Fonbet", MaxOdd: 2.6}
+	msg := n.formatValueClosedAlert("", &valueClosed{alerted: last})
+	for _, want := range []string{"✅ <b>Value closed</b>", "Alerted: 12.50% at Fonbet 2.6", "Now: no value at Fonbet"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("closed alert %q does not contain %q", msg, want)
+		}
+	}
+	cur := last
+	cur.DiffPercent = 4
+	if msg := n.formatValueClosedAlert("", &valueClosed{alerted: last, current: &cur}); !strings.Contains(msg, "Now: 4.00% at Fonbet") {
+		t.Errorf("closed alert %q does not show the current diff", msg)
+	}
+	if got := n.formatDiffAlert("", &cur, 3, 1.5); !strings.Contains(got, "Value increased by 2.50%</b> (1.50% → 4.00%)") {
+		t.Errorf("unexpected increase header %q", got)
+	}
+}
@@ -4,29 +4,40 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"math"
 	"net/http"
-	"sort"
-	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
 // handleTopValueBets returns top value bets calculated using weighted average of all bookmakers
 func (c *ValueCalculator) handleTopValueBets(w http.ResponseWriter, r *http.Request) {
-	limit := 5
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			if n > 50 {
-				n = 50
-			}
-			limit = n
-		}
+	// Page, sort and filters: ?limit=&cursor=&sort=&order=&sport=&bookmaker=&league=&min_odds=&max_odds=
+	lq, err := parseListQuery(r, 5, valueBetSorts, "value_percent")
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
 	}
 
 	// Filter by match status: "live" (started), "upcoming" (not started), or empty (all)
 	statusFilter := r.URL.Query().Get("status")
+	// Margin removal method, to compare methods on the same matches (default: value_calculator.fair_odds_method)
+	fairOdds := c.fairOdds
+	if v := r.URL.Query().Get("method"); v != "" {
+		m, err := fairOddsMethodByName(v)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		fairOdds = m
+	}
 
 	// Fetch fresh data from parser on each request
-	var valueBets []ValueBet
 	if c.httpClient == nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -34,23 +45,6 @@ func (c *ValueCalculator) handleTopValueBets(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Get bookmaker weights from config (optional - defaults to 1.0 for all)
-	// We use ALL bookmakers with weighted average
-	var bookmakerWeights map[string]float64
-	if c.cfg != nil && c.cfg.BookmakerWeights != nil {
-		bookmakerWeights = c.cfg.BookmakerWeights
-	}
-
-	minValuePercent := 5.0 // Default
-	if c.cfg != nil && c.cfg.MinValuePercent > 0 {
-		minValuePercent = c.cfg.MinValuePercent
-	}
-
-	maxOdds := 0.0
-	if c.cfg != nil && c.cfg.MaxOdds > 0 {
-		maxOdds = c.cfg.MaxOdds
-	}
-
 	// Create context with timeout for the request
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
@@ -65,47 +59,136 @@ func (c *ValueCalculator) handleTopValueBets(w http.ResponseWriter, r *http.Requ
 	}
 	logStatisticalEventsSummary(matches)
 
-	// Calculate value bets using weighted average
-	valueBets = computeValueBets(matches, bookmakerWeights, minValuePercent, maxOdds, 100)
+	page, next, total := c.pageValueBets(matches, lq, statusFilter, fairOdds)
+	setPageHeaders(w, total, next)
+	writeWebAppJSON(w, http.StatusOK, page)
+}
 
-	// Filter by status if specified
-	now := time.Now().UTC()
-	maxLiveAge := 3 * time.Hour
-	if statusFilter != "" {
-		filtered := make([]ValueBet, 0, len(valueBets))
-		for _, vb := range valueBets {
-			hasStarted := !vb.StartTime.IsZero() && (vb.StartTime.Before(now) || vb.StartTime.Equal(now))
-			notTooOld := !vb.StartTime.IsZero() && now.Sub(vb.StartTime) <= maxLiveAge
-			isLive := hasStarted && notTooOld
-			switch statusFilter {
-			case "live":
-				if isLive {
-					filtered = append(filtered, vb)
-				}
-			case "upcoming":
-				if !hasStarted {
-					filtered = append(filtered, vb)
-				}
-			default:
-				filtered = append(filtered, vb)
-			}
+// pageValueBets calculates the value bets of matches using weighted average and returns the page of lq, the
+// cursor of the next page and the number passing the filters (GET /value-bets/top, gRPC ListValueBets).
+func (c *ValueCalculator) pageValueBets(matches []models.Match, lq listQuery, status string, fairOdds FairOddsMethod) ([]ValueBet, string, int) {
+	// Bookmaker weights from config (optional - defaults to 1.0 for all): ALL bookmakers with weighted
+	// average, or only reference_bookmakers when set
+	var bookmakerWeights map[string]float64
+	var referenceBookmakers []string
+	if c.cfg != nil {
+		bookmakerWeights, referenceBookmakers = c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers
+	}
+	// Min value, max odds, per-market rules and bookmaker coverage
+	valueBets := computeValueBets(matches, bookmakerWeights, referenceBookmakers, valueLimitsFromConfig(c.cfg), math.MaxInt32, fairOdds)
+
+	// Filter by status, sport ("football", "dota2", ..., "esports" for any esports discipline or "cyber_football",
+	// hidden otherwise), bookmaker, league and odds
+	valueBets = filterValueBetsByStatus(valueBets, status, time.Now().UTC())
+	valueBets = filterValueBetsBySport(valueBets, lq.sport)
+	filtered := valueBets[:0]
+	for _, vb := range valueBets {
+		if lq.accepts(vb.Bookmaker, valueBetLeague(&vb), vb.BookmakerOdd) {
+			filtered = append(filtered, vb)
+		}
+	}
+
+	page, next := pageList(filtered, lq, valueBetSortKey(lq.sort))
+	c.bets.remember(page)
+	c.sizeStakes(page)
+	return page, next, len(filtered)
+}
+
+// valueBetSorts are the ?sort= keys of /value-bets/top and whether they order descending by default.
+var valueBetSorts = map[string]bool{"value_percent": true, "expected_value": true, "start_time": false}
+
+// valueBetSortKey returns the sort value and id of a value bet for ?sort=.
+func valueBetSortKey(key string) func(*ValueBet) (float64, string) {
+	return func(vb *ValueBet) (float64, string) {
+		switch key {
+		case "expected_value":
+			return vb.ExpectedValue, vb.ID
+		case "start_time":
+			return float64(vb.StartTime.Unix()), vb.ID
+		}
+		return vb.ValuePercent, vb.ID
+	}
+}
+
+// valueBetLeague is the tournament of a value bet for ?league=, from the league metadata when the parsers
+// didn't set it.
+func valueBetLeague(vb *ValueBet) string {
+	if vb.Tournament == "" && vb.LeagueMeta != nil {
+		return vb.LeagueMeta.Name
+	}
+	return vb.Tournament
+}
+
+// handleValueBetOdds returns one value bet by id with the odds of every bookmaker quoting it: as last served
+// by /value-bets/top, or from current matches. Used by the bot's buttons under value bets.
+// GET /value-bets/{id}/odds
+func (c *ValueCalculator) handleValueBetOdds(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.PathValue("id"))
+	if id == "" {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "value bet id is required"})
+		return
+	}
+	vb, ok := c.resolveValueBet(r.Context(), id)
+	if !ok {
+		writeWebAppJSON(w, http.StatusNotFound, map[string]string{"error": "value bet not found (gone or match started?)"})
+		return
+	}
+	writeWebAppJSON(w, http.StatusOK, vb)
+}
+
+// filterValueBetsByStatus keeps value bets of "live" (started within the last 3h) or "upcoming"
+// (not started) matches; any other status keeps all.
+func filterValueBetsByStatus(valueBets []ValueBet, status string, now time.Time) []ValueBet {
+	if status == "" {
+		return valueBets
+	}
+	filtered := make([]ValueBet, 0, len(valueBets))
+	for _, vb := range valueBets {
+		if statusMatchesFilter(vb.StartTime, status, now) {
+			filtered = append(filtered, vb)
 		}
-		valueBets = filtered
 	}
+	return filtered
+}
 
-	// Re-sort after filtering
-	sort.Slice(valueBets, func(i, j int) bool {
-		return valueBets[i].ValuePercent > valueBets[j].ValuePercent
-	})
+// statusMatchesFilter reports whether a match starting at startTime passes the ?status= filter:
+// "live" (started within the last 3h), "upcoming" (not started); any other status passes all.
+func statusMatchesFilter(startTime time.Time, status string, now time.Time) bool {
+	maxLiveAge := 3 * time.Hour
+	hasStarted := !startTime.IsZero() && (startTime.Before(now) || startTime.Equal(now))
+	notTooOld := !startTime.IsZero() && now.Sub(startTime) <= maxLiveAge
+	switch status {
+	case "live":
+		return hasStarted && notTooOld
+	case "upcoming":
+		return !hasStarted
+	default:
+		return true
+	}
+}
 
-	if limit > len(valueBets) {
-		limit = len(valueBets)
+// filterValueBetsBySport keeps value bets of the given sport; "esports" keeps every esports discipline.
+func filterValueBetsBySport(valueBets []ValueBet, sport string) []ValueBet {
+	filtered := make([]ValueBet, 0, len(valueBets))
+	for _, vb := range valueBets {
+		if sportMatchesFilter(vb.Sport, sport) {
+			filtered = append(filtered, vb)
+		}
 	}
+	return filtered
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	if len(valueBets) > 0 {
-		_ = json.NewEncoder(w).Encode(valueBets[:limit])
-	} else {
-		_ = json.NewEncoder(w).Encode([]ValueBet{})
+// sportMatchesFilter reports whether sport passes the ?sport= filter (empty = any but cyber football,
+// which is only listed on request: ?sport=cyber_football).
+func sportMatchesFilter(sport, filter string) bool {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	sport = strings.ToLower(strings.TrimSpace(sport))
+	switch filter {
+	case "":
+		return sport != string(enums.CyberFootball)
+	case string(enums.Esports):
+		return enums.Sport(sport).IsEsports()
+	default:
+		return sport == filter
 	}
 }
@@ -0,0 +1,365 @@
+package calculator
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestFilterValueBetsBySport(t *testing.T) {
+	bets := []ValueBet{{Sport: "football"}, {Sport: "dota2"}, {Sport: "cs"}, {Sport: "esports"}, {Sport: "cyber_football"}}
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{"", 4}, // cyber football only on request
+		{"cyber_football", 1},
+		{"football", 1},
+		{"CS", 1},
+		{"esports", 3},
+		{"hockey", 0},
+	}
+	for _, tt := range tests {
+		if got := filterValueBetsBySport(bets, tt.filter); len(got) != tt.want {
+			t.Errorf("filter %q: got %d bets, want %d", tt.filter, len(got), tt.want)
+		}
+	}
+}
+
+func TestEsportsDiffAlertFormatting(t *testing.T) {
+	n := &TelegramNotifier{}
+	msg := n.formatDiffAlert("", &DiffBet{MatchName: "Spirit vs NAVI", EventType: "map_1_winner", OutcomeType: "home_win", Sport: "dota2"}, 10, 0)
+	for _, want := range []string{"🎮 Map 1 Winner | Home Win", "🏆 Dota 2"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("alert %q does not contain %q", msg, want)
+		}
+	}
+}
+
+func TestCyberDiffAlertFormatting(t *testing.T) {
+	n := &TelegramNotifier{}
+	msg := n.formatDiffAlert("", &DiffBet{MatchName: "Arsenal (Kray) vs Chelsea (Boss)", EventType: "main_match", OutcomeType: "home_win", Sport: "cyber_football"}, 12, 0)
+	for _, want := range []string{"🎮 <b>Cyber Football Value Alert (12%+)</b>", "🏆 Cyber football"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("alert %q does not contain %q", msg, want)
+		}
+	}
+}
+
+func TestComputeValueBetsReferenceBookmakers(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(bookmaker string, home float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: home},
+			}}}}
+	}
+	matches := []models.Match{match("Pinnacle", 2.0), match("Fonbet", 2.3), match("Olimp", 2.6)}
+	weights := map[string]float64{"Pinnacle": 3.0} // keys are matched case-insensitively
+
+	fairProbOf := func(bets []ValueBet, bookmaker string) float64 {
+		for _, b := range bets {
+			if b.Bookmaker == bookmaker {
+				return b.FairProbability
+			}
+		}
+		t.Fatalf("no value bet at %s in %+v", bookmaker, bets)
+		return 0
+	}
+
+	// Consensus of all bookmakers: soft odds pull the fair line up
+	bets := computeValueBets(matches, weights, nil, valueLimits{minValuePercent: 5}, 10, nil)
+	if want := (3*(1/2.0) + 1/2.3 + 1/2.6) / 5; math.Abs(fairProbOf(bets, "olimp")-want) > 1e-9 {
+		t.Errorf("weighted fair probability = %.6f, want %.6f", fairProbOf(bets, "olimp"), want)
+	}
+
+	// Reference bookmakers only: the fair line is Pinnacle's, soft books are compared against it
+	bets = computeValueBets(matches, weights, []string{"Pinnacle"}, valueLimits{minValuePercent: 5}, 10, nil)
+	if len(bets) != 2 {
+		t.Fatalf("expected value at fonbet and olimp, got %+v", bets)
+	}
+	if p := fairProbOf(bets, "olimp"); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("fair probability = %.6f, want 0.5 (pinnacle only)", p)
+	}
+	if math.Abs(bets[0].ValuePercent-30) > 1e-9 {
+		t.Errorf("top value = %.4f%%, want 30%%", bets[0].ValuePercent)
+	}
+
+	// No reference bookmaker quotes the bet: no fair line
+	if bets := computeValueBets(matches[1:], weights, []string{"pinnacle"}, valueLimits{minValuePercent: 5}, 10, nil); len(bets) != 0 {
+		t.Errorf("without reference odds expected no value bets, got %+v", bets)
+	}
+}
+
+func TestComputeValueBetsRules(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(bookmaker string, home, corners float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{
+				{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{{OutcomeType: "home_win", Odds: home}}},
+				{EventType: "corners", Bookmaker: bookmaker, Outcomes: []models.Outcome{{OutcomeType: "total_over", Parameter: "9.5", Odds: corners}}},
+			}}
+	}
+	// Against Pinnacle: home win at Fonbet +4%, corners over at Fonbet +10%
+	matches := []models.Match{match("Pinnacle", 2.0, 2.0), match("Fonbet", 2.08, 2.2)}
+	reference := []string{"pinnacle"}
+	keys := func(bets []ValueBet) []string {
+		var out []string
+		for _, b := range bets {
+			out = append(out, b.EventType)
+		}
+		return out
+	}
+
+	if bets := computeValueBets(matches, nil, reference, valueLimits{minValuePercent: 5}, 10, nil); len(bets) != 1 || bets[0].EventType != "corners" {
+		t.Fatalf("global 5%%: expected corners only, got %v", keys(bets))
+	}
+
+	rules := []config.ValueRule{
+		{Name: "corners", EventType: "corners", MinValuePercent: 12},
+		{Name: "1x2", Sport: "Football", EventType: "main_match", MinValuePercent: 3},
+	}
+	if bets := computeValueBets(matches, nil, reference, valueLimits{minValuePercent: 5, rules: rules}, 10, nil); len(bets) != 1 || bets[0].EventType != "main_match" {
+		t.Fatalf("rules: expected home win only, got %v", keys(bets))
+	}
+
+	// The first matching rule wins: fair probability 0.5 is above fonbet's limit
+	rules = append([]config.ValueRule{{Bookmaker: "Fonbet", MaxFairProbability: 0.4}}, rules...)
+	if bets := computeValueBets(matches, nil, reference, valueLimits{minValuePercent: 5, rules: rules}, 10, nil); len(bets) != 0 {
+		t.Fatalf("max fair probability: expected nothing, got %v", keys(bets))
+	}
+
+	// Odds range match and per-rule odds limits
+	rules = []config.ValueRule{{OddsFrom: 2.1, OddsTo: 3, MinValuePercent: 1, MaxOdds: 2.1}, {MinValuePercent: 1, MinOdds: 2.1}}
+	if bets := computeValueBets(matches, nil, reference, valueLimits{minValuePercent: 5, rules: rules}, 10, nil); len(bets) != 0 {
+		t.Fatalf("odds limits: expected nothing, got %v", keys(bets))
+	}
+	if r := matchValueRule(rules, "football", "corners", "fonbet", 3); r != &rules[1] {
+		t.Errorf("odds_to is exclusive: got %+v", r)
+	}
+}
+
+func TestComputeValueBetsCoverage(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(bookmaker string, home float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: home},
+			}}}}
+	}
+	// One outlier line against two consistent ones
+	matches := []models.Match{match("Fonbet", 2.0), match("Olimp", 2.0), match("Leon", 2.6)}
+
+	tests := []struct {
+		name   string
+		limits valueLimits
+		want   int
+	}{
+		{"default coverage", valueLimits{}, 1},
+		{"three bookmakers", valueLimits{minBookmakers: 3}, 1},
+		{"four bookmakers", valueLimits{minBookmakers: 4}, 0},
+		{"no sharp quote", valueLimits{minSharpBookmakers: 1, sharpBookmakers: referenceBookmakerSet([]string{"Pinnacle"})}, 0},
+		{"sharp quote", valueLimits{minSharpBookmakers: 1, sharpBookmakers: referenceBookmakerSet([]string{"Olimp"})}, 1},
+	}
+	for _, tt := range tests {
+		if bets := computeValueBets(matches, nil, nil, tt.limits, 10, nil); len(bets) != tt.want {
+			t.Errorf("%s: got %d value bets, want %d", tt.name, len(bets), tt.want)
+		}
+	}
+	// Two bookmakers are always required
+	if bets := computeValueBets(matches[1:], nil, nil, valueLimits{minBookmakers: 1}, 10, nil); len(bets) != 1 {
+		t.Errorf("two bookmakers: got %d value bets, want 1", len(bets))
+	}
+	if bets := computeValueBets(matches[2:], nil, nil, valueLimits{minBookmakers: 1}, 10, nil); len(bets) != 0 {
+		t.Errorf("single bookmaker: got %d value bets, want 0", len(bets))
+	}
+
+	cfg := &config.ValueCalculatorConfig{MinBookmakers: 3, MinSharpBookmakers: 1, ReferenceBookmakers: []string{"Olimp"}}
+	if l := valueLimitsFromConfig(cfg); l.minBookmakers != 3 || !l.sharpBookmakers["olimp"] {
+		t.Errorf("sharp bookmakers should default to reference bookmakers: %+v", l)
+	}
+}
+
+func TestComputeValueBetsFreshness(t *testing.T) {
+	now := time.Now()
+	start := now.Add(2 * time.Hour)
+	match := func(bookmaker string, home float64, seenAgo time.Duration) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: home, UpdatedAt: now.Add(-seenAgo)},
+			}}}}
+	}
+	// Leon's outlier line was last seen 20 minutes ago
+	matches := []models.Match{match("Fonbet", 2.0, time.Minute), match("Olimp", 2.0, time.Minute), match("Leon", 2.6, 20*time.Minute)}
+
+	cfg := &config.ValueCalculatorConfig{}
+	if bets := computeValueBets(matches, nil, nil, valueLimitsFromConfig(cfg), 10, nil); len(bets) != 1 || bets[0].Bookmaker != "leon" {
+		t.Fatalf("without freshness expected value at leon, got %+v", bets)
+	}
+	cfg.Freshness = config.FreshnessConfig{MaxAge: 5 * time.Minute}
+	if bets := computeValueBets(matches, nil, nil, valueLimitsFromConfig(cfg), 10, nil); len(bets) != 0 {
+		t.Fatalf("stale quote should be excluded, got %+v", bets)
+	}
+	cfg.Freshness.Bookmakers = map[string]time.Duration{"Leon": 30 * time.Minute}
+	if bets := computeValueBets(matches, nil, nil, valueLimitsFromConfig(cfg), 10, nil); len(bets) != 1 {
+		t.Fatalf("leon's own window should keep its quote, got %+v", bets)
+	}
+
+	// Stale quotes don't form the fair line either: Olimp's old price leaves Fonbet alone
+	matches = []models.Match{match("Fonbet", 2.0, time.Minute), match("Olimp", 1.5, time.Hour), match("Leon", 2.6, time.Minute)}
+	cfg.Freshness.Bookmakers = nil
+	bets := computeValueBets(matches, nil, nil, valueLimitsFromConfig(cfg), 10, nil)
+	if len(bets) != 1 || math.Abs(bets[0].FairProbability-(1/2.0+1/2.6)/2) > 1e-9 {
+		t.Errorf("fair line should ignore the stale quote, got %+v", bets)
+	}
+}
+
+func TestComputeMatchProbabilities(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(home, bookmaker string, over, under float64) models.Match {
+		return models.Match{HomeTeam: home, AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "total_over", Parameter: "2.5", Odds: over},
+				{OutcomeType: "total_under", Parameter: "2.5", Odds: under},
+			}}}}
+	}
+	matches := []models.Match{match("Arsenal", "Pinnacle", 1.9, 1.9), match("Arsenal", "Fonbet", 2.0, 1.8), match("Everton", "Fonbet", 3.0, 1.3)}
+	matches[1].Events[0].Outcomes = append(matches[1].Events[0].Outcomes, models.Outcome{OutcomeType: "home_win", Odds: 2.5})
+	groupKey := matchGroupKey(matches[0])
+
+	probs := computeMatchProbabilities(matches, groupKey, nil, nil, valueLimits{}, proportionalMargin{})
+	if probs == nil || probs.MatchName != "Arsenal vs Chelsea" || probs.Method != FairOddsProportional || len(probs.Outcomes) != 3 {
+		t.Fatalf("unexpected probabilities: %+v", probs)
+	}
+	// Sorted by bet key: home_win, total_over, total_under
+	home, over := probs.Outcomes[0], probs.Outcomes[1]
+	if bp := home.Bookmakers["fonbet"]; bp.Devigged || bp.FairProbability != 0.4 || home.FairProbability != 0.4 {
+		t.Errorf("a lone outcome is not de-vigged: %+v", home)
+	}
+	fonbetOver := 0.5 / (0.5 + 1/1.8)
+	if bp := over.Bookmakers["fonbet"]; !bp.Devigged || bp.ImpliedProbability != 0.5 || math.Abs(bp.FairProbability-fonbetOver) > 1e-9 {
+		t.Errorf("unexpected Fonbet over: %+v", bp)
+	}
+	if want := (0.5 + fonbetOver) / 2; math.Abs(over.FairProbability-want) > 1e-9 || math.Abs(over.FairOdd-1/want) > 1e-9 {
+		t.Errorf("consensus over: got %v, want %v", over.FairProbability, want)
+	}
+
+	// Reference bookmakers form the consensus alone
+	probs = computeMatchProbabilities(matches, groupKey, nil, []string{"Pinnacle"}, valueLimits{}, proportionalMargin{})
+	if over := probs.Outcomes[1]; math.Abs(over.FairProbability-0.5) > 1e-9 || over.Bookmakers["fonbet"].Reference || !over.Bookmakers["pinnacle"].Reference {
+		t.Errorf("consensus over with reference Pinnacle: %+v", over)
+	}
+	if home := probs.Outcomes[0]; home.FairProbability != 0 || home.FairOdd != 0 {
+		t.Errorf("outcome without a reference quote has no consensus: %+v", home)
+	}
+
+	if computeMatchProbabilities(matches, "football|unknown", nil, nil, valueLimits{}, nil) != nil {
+		t.Error("unknown group key should return nil")
+	}
+
+	// The group key contains "|" and arrives URL-encoded in the path
+	c := &ValueCalculator{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /matches/{group_key}/probabilities", c.handleMatchProbabilities)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/matches/"+url.PathEscape(groupKey)+"/probabilities?method=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown method: got status %d, want 400", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/matches/"+url.PathEscape(groupKey)+"/probabilities", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no parser: got status %d, want 503", rec.Code)
+	}
+}
+
+func TestStakeSizing(t *testing.T) {
+	// p = 0.5 at odd 2.2: edge 0.1, full Kelly 0.1 / 1.2
+	if got, want := kellyPercent(0.5, 2.2), 0.1/1.2*100; math.Abs(got-want) > 1e-9 {
+		t.Errorf("kellyPercent = %.6f, want %.6f", got, want)
+	}
+	if got := kellyPercent(0.4, 2.2); got != 0 {
+		t.Errorf("no edge: kellyPercent = %.6f, want 0", got)
+	}
+
+	c := &ValueCalculator{cfg: &config.ValueCalculatorConfig{Staking: config.StakingConfig{KellyFraction: 0.5, MaxStakePercent: 3}}}
+	bets := []ValueBet{{KellyPercent: 4}, {KellyPercent: 10}}
+	c.sizeStakes(bets)
+	if bets[0].FractionalKellyPercent != 2 || bets[1].FractionalKellyPercent != 3 {
+		t.Errorf("fractional stakes = %.2f, %.2f, want 2 and 3 (capped)", bets[0].FractionalKellyPercent, bets[1].FractionalKellyPercent)
+	}
+	if bets[0].FlatStakePercent != defaultFlatStakePercent {
+		t.Errorf("flat stake = %.2f, want default %.2f", bets[0].FlatStakePercent, defaultFlatStakePercent)
+	}
+}
+
+func TestHandleValueBetOdds(t *testing.T) {
+	c := NewValueCalculator(&config.ValueCalculatorConfig{}, nil, nil)
+	c.bets.remember([]ValueBet{{ID: "3f2a9c01d4e5b6a7", MatchName: "Arsenal vs Chelsea", Bookmaker: "fonbet", BookmakerOdd: 2.2,
+		AllBookmakerOdds: map[string]float64{"fonbet": 2.2, "pinnacle": 2.0}}})
+	mux := http.NewServeMux()
+	c.RegisterHTTP(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/value-bets/3f2a9c01d4e5b6a7/odds", nil))
+	var vb ValueBet
+	if err := json.NewDecoder(rec.Body).Decode(&vb); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, err %v", rec.Code, err)
+	}
+	if vb.MatchName != "Arsenal vs Chelsea" || len(vb.AllBookmakerOdds) != 2 {
+		t.Errorf("unexpected value bet: %+v", vb)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/value-bets/unknown/odds", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown id: status %d, want 404", rec.Code)
+	}
+}
+
+func TestBookmakerURLs(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	match := func(bookmaker, matchURL, outcomeURL string, home float64) models.Match {
+		return models.Match{HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker, URL: matchURL,
+			Events: []models.Event{{EventType: "main_match", Bookmaker: bookmaker, Outcomes: []models.Outcome{
+				{OutcomeType: "home_win", Odds: home, URL: outcomeURL},
+			}}}}
+	}
+	matches := []models.Match{
+		match("Pinnacle", "", "", 2.0),
+		match("Marathonbet", "https://www.marathonbet.ru/su/betting/Football/123", "", 2.6),
+		match("Fonbet", "", "https://fon.bet/sports/football/456", 2.5),
+	}
+
+	bets := computeValueBets(matches, nil, []string{"pinnacle"}, valueLimits{minValuePercent: 5}, 10, nil)
+	urls := map[string]string{}
+	for _, b := range bets {
+		urls[b.Bookmaker] = b.BookmakerURL
+	}
+	want := map[string]string{"marathonbet": "https://www.marathonbet.ru/su/betting/Football/123", "fonbet": "https://fon.bet/sports/football/456"}
+	for bk, u := range want {
+		if urls[bk] != u {
+			t.Errorf("%s value bet url = %q, want %q", bk, urls[bk], u)
+		}
+	}
+
+	diffs := computeTopDiffs(matches, 10)
+	if len(diffs) != 1 || diffs[0].MaxBookmakerURL != want["marathonbet"] {
+		t.Fatalf("expected the marathonbet page on the diff, got %+v", diffs)
+	}
+	msg := (&TelegramNotifier{}).formatDiffAlert("", &diffs[0], 10, 0)
+	if link := `<a href="https://www.marathonbet.ru/su/betting/Football/123">Marathonbet</a>: 2.60`; !strings.Contains(msg, link) {
+		t.Errorf("alert %q does not link the bookmaker: %q", msg, link)
+	}
+	if url, bk := alertURL(queuedMessage{msgType: messageTypeDiff, diff: &diffs[0]}); url != want["marathonbet"] || bk != "Marathonbet" {
+		t.Errorf("alertURL = %q, %q", url, bk)
+	}
+}
@@ -0,0 +1,288 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// GET /value-bets/history page size.
+const (
+	defaultValueHistoryLimit = 50
+	maxValueHistoryLimit     = 500
+)
+
+// valueHistoryTracker follows value bets across async cycles (value_calculator.value_history): a value bet
+// seen in a cycle opens or extends its lifecycle, one gone from a cycle ends it with the reason.
+type valueHistoryTracker struct {
+	mu     sync.Mutex
+	store  storage.ValueHistoryStorage           // nil = value history disabled
+	active map[string]*storage.ValueBetLifecycle // value bet id -> current lifecycle
+	loaded bool                                  // lifecycles left active by the previous run are restored
+}
+
+func newValueHistoryTracker() *valueHistoryTracker {
+	return &valueHistoryTracker{active: map[string]*storage.ValueBetLifecycle{}}
+}
+
+// SetValueHistoryStorage enables value bet lifecycle tracking (value_calculator.value_history.enabled).
+func (c *ValueCalculator) SetValueHistoryStorage(s storage.ValueHistoryStorage) {
+	c.history.store = s
+}
+
+// valueHistoryEnabled reports whether the async cycle should track value bet lifecycles.
+func (c *ValueCalculator) valueHistoryEnabled() bool {
+	return c.cfg != nil && c.cfg.ValueHistory.Enabled && c.history.store != nil && c.httpClient != nil
+}
+
+// processValueHistory computes the current value bets (as GET /value-bets/top) and stores their lifecycles.
+func (c *ValueCalculator) processValueHistory(ctx context.Context) {
+	started := time.Now()
+	if err := c.history.restore(ctx); err != nil {
+		slog.Error("Failed to restore active value bet lifecycles", "error", err)
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(reqCtx)
+	if err != nil {
+		slog.Error("Failed to fetch matches for value history", "error", err)
+		return
+	}
+	valueBets := computeValueBets(matches, c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers, valueLimitsFromConfig(c.cfg), math.MaxInt32, c.fairOdds)
+
+	changed, ended := c.history.update(valueBets, quotedValueBetIDs(matches), time.Now().UTC())
+	if err := c.history.store.UpsertValueBetLifecycles(ctx, changed); err != nil {
+		slog.Error("Failed to store value bet lifecycles", "count", len(changed), "error", err)
+		return
+	}
+	slog.Info("Value history updated", "value_bets", len(valueBets), "active", len(changed)-ended, "ended", ended, "duration_sec", time.Since(started).Seconds())
+}
+
+// restore loads the lifecycles the previous run left active, once: the first cycle continues or ends them.
+func (t *valueHistoryTracker) restore(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.loaded {
+		return nil
+	}
+	ls, _, err := t.store.GetValueBetHistory(ctx, storage.ValueBetHistoryFilter{Status: "active"})
+	if err != nil {
+		return err
+	}
+	for i := range ls {
+		l := ls[i]
+		t.active[l.ValueBetID] = &l
+	}
+	t.loaded = true
+	if len(ls) > 0 {
+		slog.Info("Restored active value bet lifecycles", "count", len(ls))
+	}
+	return nil
+}
+
+// reset forgets the active lifecycles, so the next cycle restores them from storage: another calculator
+// replica may have continued or ended them since (value_calculator.leader_election).
+func (t *valueHistoryTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active = map[string]*storage.ValueBetLifecycle{}
+	t.loaded = false
+}
+
+// update applies the value bets of one cycle: quoted holds the value bet ids of every (match, bet, bookmaker)
+// quoted in the cycle. Value bets of started matches are not tracked. Returns the lifecycles to store
+// (active ones first) and how many of them ended.
+func (t *valueHistoryTracker) update(valueBets []ValueBet, quoted map[string]bool, now time.Time) (changed []storage.ValueBetLifecycle, ended int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seen := make(map[string]bool, len(valueBets))
+	for _, vb := range valueBets {
+		if matchStarted(vb.StartTime, now) || seen[vb.ID] {
+			continue
+		}
+		seen[vb.ID] = true
+		l, ok := t.active[vb.ID]
+		if !ok {
+			l = &storage.ValueBetLifecycle{
+				ValueBetID:        vb.ID,
+				MatchGroupKey:     vb.MatchGroupKey,
+				Sport:             vb.Sport,
+				EventType:         vb.EventType,
+				OutcomeType:       vb.OutcomeType,
+				Parameter:         vb.Parameter,
+				BetKey:            vb.BetKey,
+				Bookmaker:         vb.Bookmaker,
+				FirstSeen:         now,
+				FirstValuePercent: vb.ValuePercent,
+				MaxValuePercent:   vb.ValuePercent,
+				MaxValueOdd:       vb.BookmakerOdd,
+			}
+			t.active[vb.ID] = l
+		}
+		l.MatchName, l.StartTime = vb.MatchName, vb.StartTime
+		l.LastSeen = now
+		l.LastValuePercent, l.LastOdd, l.LastFairOdd = vb.ValuePercent, vb.BookmakerOdd, vb.FairOdd
+		if vb.ValuePercent > l.MaxValuePercent {
+			l.MaxValuePercent, l.MaxValueOdd = vb.ValuePercent, vb.BookmakerOdd
+		}
+		changed = append(changed, *l)
+	}
+
+	for id, l := range t.active {
+		if seen[id] {
+			continue
+		}
+		switch {
+		case matchStarted(l.StartTime, now):
+			l.EndReason = storage.ValueEndMatchStarted
+		case quoted[id]:
+			l.EndReason = storage.ValueEndValueGone
+		default:
+			l.EndReason = storage.ValueEndQuoteRemoved
+		}
+		endedAt := now
+		l.EndedAt = &endedAt
+		changed = append(changed, *l)
+		ended++
+		delete(t.active, id)
+	}
+	return changed, ended
+}
+
+// matchStarted reports whether a match starting at startTime has kicked off by now.
+func matchStarted(startTime, now time.Time) bool {
+	return !startTime.IsZero() && !startTime.After(now)
+}
+
+// quotedValueBetIDs returns the value bet ids of every (match, bet, bookmaker) quoted in matches.
+func quotedValueBetIDs(matches []models.Match) map[string]bool {
+	quoted := map[string]bool{}
+	for _, m := range matches {
+		gk := matchGroupKey(m)
+		if gk == "" {
+			continue
+		}
+		for _, ev := range m.Events {
+			for _, out := range ev.Outcomes {
+				bk := firstNonEmpty(out.Bookmaker, ev.Bookmaker, m.Bookmaker)
+				if bk == "" || !isFinitePositiveOdd(out.Odds) {
+					continue
+				}
+				betKey := models.OutcomeBetKey(ev, out).String()
+				quoted[valueBetID(gk, betKey, bk)] = true
+			}
+		}
+	}
+	return quoted
+}
+
+// firstNonEmpty returns the first of values that isn't blank, trimmed.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// GET /value-bets/history?limit=50&offset=0[&sport=football][&bookmaker=fonbet][&match_group_key=...]
+// [&status=active|ended][&end_reason=value_gone][&min_value=5][&since=24h | &from=RFC3339&to=RFC3339]
+func (c *ValueCalculator) handleValueBetHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeWebAppJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed, use GET"})
+		return
+	}
+	if c.history.store == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "value history is disabled (value_calculator.value_history.enabled)"})
+		return
+	}
+	f, err := parseValueHistoryFilter(r, time.Now().UTC())
+	if err != nil {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	items, total, err := c.history.store.GetValueBetHistory(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to get value bet history", "error", err)
+		writeWebAppJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get value bet history", "details": err.Error()})
+		return
+	}
+	if items == nil {
+		items = []storage.ValueBetLifecycle{}
+	}
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{"items": items, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// parseValueHistoryFilter reads the query of GET /value-bets/history.
+func parseValueHistoryFilter(r *http.Request, now time.Time) (storage.ValueBetHistoryFilter, error) {
+	q := r.URL.Query()
+	f := storage.ValueBetHistoryFilter{
+		Sport:         strings.TrimSpace(q.Get("sport")),
+		Bookmaker:     strings.TrimSpace(q.Get("bookmaker")),
+		MatchGroupKey: strings.TrimSpace(q.Get("match_group_key")),
+		Status:        q.Get("status"),
+		EndReason:     q.Get("end_reason"),
+		Limit:         defaultValueHistoryLimit,
+	}
+	if f.Status != "" && f.Status != "active" && f.Status != "ended" {
+		return f, fmt.Errorf("status must be active or ended")
+	}
+	switch f.EndReason {
+	case "", storage.ValueEndMatchStarted, storage.ValueEndValueGone, storage.ValueEndQuoteRemoved:
+	default:
+		return f, fmt.Errorf("end_reason must be %s, %s or %s", storage.ValueEndMatchStarted, storage.ValueEndValueGone, storage.ValueEndQuoteRemoved)
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return f, fmt.Errorf("invalid limit")
+		}
+		f.Limit = min(n, maxValueHistoryLimit)
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("invalid offset")
+		}
+		f.Offset = n
+	}
+	if v := q.Get("min_value"); v != "" {
+		x, err := strconv.ParseFloat(v, 64)
+		if err != nil || x < 0 {
+			return f, fmt.Errorf("invalid min_value")
+		}
+		f.MinValuePercent = x
+	}
+	if v := q.Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return f, fmt.Errorf("invalid since, use a duration like 24h")
+		}
+		f.From = now.Add(-d)
+	}
+	for name, dst := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s, use RFC3339", name)
+			}
+			*dst = t
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return f, fmt.Errorf("from must be before to")
+	}
+	return f, nil
+}
@@ -0,0 +1,84 @@
+package calculator
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestValueHistoryTracker(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	kickoff := now.Add(2 * time.Hour)
+	vb := func(gk, bookmaker string, odd, value float64) ValueBet {
+		return ValueBet{
+			ID: valueBetID(gk, "main_match|home_win|", bookmaker), MatchGroupKey: gk, MatchName: gk, StartTime: kickoff,
+			BetKey: "main_match|home_win|", Bookmaker: bookmaker, BookmakerOdd: odd, ValuePercent: value, FairOdd: 2,
+		}
+	}
+	tr := newValueHistoryTracker()
+	changed, ended := tr.update([]ValueBet{vb("a", "fonbet", 2.2, 10), vb("b", "leon", 2.1, 5), vb("c", "pinnacle", 2.1, 5)}, nil, now)
+	if len(changed) != 3 || ended != 0 {
+		t.Fatalf("first cycle: %d changed, %d ended", len(changed), ended)
+	}
+
+	// a grows then shrinks, b loses its value but is still quoted, c is no longer quoted
+	quoted := map[string]bool{vb("b", "leon", 0, 0).ID: true}
+	if _, ended = tr.update([]ValueBet{vb("a", "fonbet", 2.3, 15)}, quoted, now.Add(time.Minute)); ended != 2 {
+		t.Fatalf("second cycle: %d ended, want 2", ended)
+	}
+	changed, ended = tr.update([]ValueBet{vb("a", "fonbet", 2.25, 12)}, quoted, now.Add(2*time.Minute))
+	if len(changed) != 1 || ended != 0 {
+		t.Fatalf("third cycle: %d changed, %d ended", len(changed), ended)
+	}
+	a := changed[0]
+	if !a.FirstSeen.Equal(now) || !a.LastSeen.Equal(now.Add(2*time.Minute)) || a.FirstValuePercent != 10 ||
+		a.MaxValuePercent != 15 || a.MaxValueOdd != 2.3 || a.LastValuePercent != 12 || a.LastOdd != 2.25 || a.EndedAt != nil {
+		t.Errorf("unexpected active lifecycle: %+v", a)
+	}
+
+	// The match kicks off: its value bet is no longer tracked
+	changed, ended = tr.update([]ValueBet{vb("a", "fonbet", 2.25, 12)}, quoted, kickoff)
+	if len(changed) != 1 || ended != 1 || changed[0].EndReason != storage.ValueEndMatchStarted || !changed[0].EndedAt.Equal(kickoff) {
+		t.Errorf("kick-off: %+v", changed)
+	}
+	if len(tr.active) != 0 {
+		t.Errorf("%d lifecycles still active", len(tr.active))
+	}
+}
+
+func TestValueHistoryEndReasons(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tr := newValueHistoryTracker()
+	tr.active["gone"] = &storage.ValueBetLifecycle{ValueBetID: "gone", StartTime: now.Add(time.Hour)}
+	tr.active["removed"] = &storage.ValueBetLifecycle{ValueBetID: "removed", StartTime: now.Add(time.Hour)}
+	changed, _ := tr.update(nil, map[string]bool{"gone": true}, now)
+	reasons := map[string]string{}
+	for _, l := range changed {
+		reasons[l.ValueBetID] = l.EndReason
+	}
+	if reasons["gone"] != storage.ValueEndValueGone || reasons["removed"] != storage.ValueEndQuoteRemoved {
+		t.Errorf("unexpected end reasons: %v", reasons)
+	}
+}
+
+func TestParseValueHistoryFilter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f, err := parseValueHistoryFilter(httptest.NewRequest("GET", "/value-bets/history?sport=football&status=ended&end_reason=value_gone&since=24h&limit=1000&offset=50&min_value=4.5", nil), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Sport != "football" || f.Status != "ended" || f.EndReason != storage.ValueEndValueGone || !f.From.Equal(now.Add(-24*time.Hour)) ||
+		f.Limit != maxValueHistoryLimit || f.Offset != 50 || f.MinValuePercent != 4.5 {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+	if f, _ := parseValueHistoryFilter(httptest.NewRequest("GET", "/value-bets/history", nil), now); f.Limit != defaultValueHistoryLimit {
+		t.Errorf("default limit: %d", f.Limit)
+	}
+	for _, q := range []string{"status=open", "end_reason=x", "limit=0", "offset=-1", "since=x", "from=yesterday", "from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z"} {
+		if _, err := parseValueHistoryFilter(httptest.NewRequest("GET", "/value-bets/history?"+q, nil), now); err == nil {
+			t.Errorf("%s: expected an error", q)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package calculator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Bookmaker coverage default (value_calculator.min_bookmakers): a fair line needs a second opinion.
+const defaultMinBookmakers = 2
+
+// valueLimits are the value_calculator settings that decide which value bets are emitted.
+type valueLimits struct {
+	minValuePercent float64            // default: 5
+	maxOdds         float64            // 0 = no limit
+	rules           []config.ValueRule // per-market / per-bookmaker overrides of minValuePercent and maxOdds
+
+	// Coverage: bookmakers quoting the bet key, of which sharp
+	minBookmakers      int             // default: 2
+	minSharpBookmakers int             // 0 = no requirement
+	sharpBookmakers    map[string]bool // lowercased names
+
+	// Freshness: max age of a quote, per lowercased bookmaker (0 = no limit)
+	maxAge            time.Duration
+	maxAgeByBookmaker map[string]time.Duration
+}
+
+// valueLimitsFromConfig returns the limits of value_calculator (sharp bookmakers default to reference_bookmakers).
+func valueLimitsFromConfig(cfg *config.ValueCalculatorConfig) valueLimits {
+	if cfg == nil {
+		return valueLimits{}
+	}
+	var maxAgeByBookmaker map[string]time.Duration
+	if len(cfg.Freshness.Bookmakers) > 0 {
+		maxAgeByBookmaker = make(map[string]time.Duration, len(cfg.Freshness.Bookmakers))
+		for bk, age := range cfg.Freshness.Bookmakers {
+			maxAgeByBookmaker[strings.ToLower(strings.TrimSpace(bk))] = age
+		}
+	}
+	sharp := cfg.SharpBookmakers
+	if len(sharp) == 0 {
+		sharp = cfg.ReferenceBookmakers
+	}
+	return valueLimits{
+		minValuePercent:    cfg.MinValuePercent,
+		maxOdds:            cfg.MaxOdds,
+		rules:              cfg.Rules,
+		minBookmakers:      cfg.MinBookmakers,
+		minSharpBookmakers: cfg.MinSharpBookmakers,
+		sharpBookmakers:    referenceBookmakerSet(sharp),
+		maxAge:             cfg.Freshness.MaxAge,
+		maxAgeByBookmaker:  maxAgeByBookmaker,
+	}
+}
+
+// fresh reports whether a quote of bookmaker (lowercased) last seen at seenAt is recent enough at now.
+func (l valueLimits) fresh(bookmaker string, seenAt, now time.Time) bool {
+	maxAge := l.maxAge
+	if age, ok := l.maxAgeByBookmaker[bookmaker]; ok {
+		maxAge = age
+	}
+	if maxAge <= 0 || seenAt.IsZero() {
+		return true
+	}
+	return now.Sub(seenAt) <= maxAge
+}
+
+// covers reports whether enough bookmakers (and enough sharp ones) quote a bet: byBook is bookmaker -> odd.
+func (l valueLimits) covers(byBook map[string]float64) bool {
+	minBookmakers := l.minBookmakers
+	if minBookmakers < defaultMinBookmakers {
+		minBookmakers = defaultMinBookmakers
+	}
+	if len(byBook) < minBookmakers {
+		return false
+	}
+	if l.minSharpBookmakers <= 0 {
+		return true
+	}
+	sharp := 0
+	for bk := range byBook {
+		if l.sharpBookmakers[bk] {
+			sharp++
+		}
+	}
+	return sharp >= l.minSharpBookmakers
+}
+
+// valueThresholds are the limits a value bet must pass.
+type valueThresholds struct {
+	minValuePercent    float64
+	minOdds            float64 // 0 = no limit
+	maxOdds            float64 // 0 = no limit
+	maxFairProbability float64 // 0 = no limit
+}
+
+// allows reports whether a value bet at odd with fairProb and valuePercent passes the thresholds.
+func (t valueThresholds) allows(odd, fairProb, valuePercent float64) bool {
+	switch {
+	case valuePercent < t.minValuePercent:
+		return false
+	case t.minOdds > 0 && odd < t.minOdds:
+		return false
+	case t.maxOdds > 0 && odd > t.maxOdds:
+		return false
+	case t.maxFairProbability > 0 && fairProb > t.maxFairProbability:
+		return false
+	}
+	return true
+}
+
+// matchValueRule returns the first rule matching the value bet, or nil.
+func matchValueRule(rules []config.ValueRule, sport, eventType, bookmaker string, odd float64) *config.ValueRule {
+	for i := range rules {
+		r := &rules[i]
+		if r.Sport != "" && !strings.EqualFold(r.Sport, sport) {
+			continue
+		}
+		if r.EventType != "" && !strings.EqualFold(r.EventType, eventType) {
+			continue
+		}
+		if r.Bookmaker != "" && !strings.EqualFold(r.Bookmaker, bookmaker) {
+			continue
+		}
+		if r.OddsFrom > 0 && odd < r.OddsFrom {
+			continue
+		}
+		if r.OddsTo > 0 && odd >= r.OddsTo {
+			continue
+		}
+		return r
+	}
+	return nil
+}
+
+// thresholds returns the thresholds of a value bet: the matching rule's, with the global
+// min_value_percent and max_odds for what the rule leaves unset.
+func (l valueLimits) thresholds(sport, eventType, bookmaker string, odd float64) valueThresholds {
+	t := valueThresholds{minValuePercent: l.minValuePercent, maxOdds: l.maxOdds}
+	r := matchValueRule(l.rules, sport, eventType, bookmaker, odd)
+	if r == nil {
+		return t
+	}
+	if r.MinValuePercent > 0 {
+		t.minValuePercent = r.MinValuePercent
+	}
+	if r.MaxOdds > 0 {
+		t.maxOdds = r.MaxOdds
+	}
+	t.minOdds = r.MinOdds
+	t.maxFairProbability = r.MaxFairProbability
+	return t
+}
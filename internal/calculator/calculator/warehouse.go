@@ -0,0 +1,85 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// defaultWarehouseRunAt is the nightly ETL time (UTC) when value_calculator.warehouse.run_at is empty.
+const defaultWarehouseRunAt = "03:00"
+
+// warehouseETLTimeout bounds one ETL run.
+const warehouseETLTimeout = 10 * time.Minute
+
+// SetWarehouse enables the research warehouse ETL: nightly at warehouse.run_at and before every
+// full DB cleanup (the operational odds tables are truncated, so history must be exported first).
+func (c *ValueCalculator) SetWarehouse(w storage.WarehouseStorage) {
+	c.warehouse = w
+}
+
+// nextDailyRun returns the first runAt ("HH:MM", UTC) strictly after now.
+func nextDailyRun(now time.Time, runAt string) (time.Time, error) {
+	t, err := time.Parse("15:04", runAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, want HH:MM: %w", runAt, err)
+	}
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
+
+// runNightlyWarehouseETL runs the ETL every day at warehouse.run_at until ctx is done.
+func (c *ValueCalculator) runNightlyWarehouseETL(ctx context.Context) {
+	runAt := defaultWarehouseRunAt
+	if c.cfg != nil && c.cfg.Warehouse.RunAt != "" {
+		runAt = c.cfg.Warehouse.RunAt
+	}
+	if _, err := nextDailyRun(time.Now(), runAt); err != nil {
+		slog.Warn("Warehouse ETL: invalid run_at, using default", "error", err, "default", defaultWarehouseRunAt)
+		runAt = defaultWarehouseRunAt
+	}
+	for {
+		next, _ := nextDailyRun(time.Now(), runAt)
+		slog.Info("Warehouse ETL: next nightly run scheduled", "at", next.Format(time.RFC3339))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if c.isLeader() {
+				c.exportWarehouse(ctx, "nightly")
+			}
+		}
+	}
+}
+
+// exportWarehouse runs one ETL into the research schema; reason is logged ("nightly", "before_cleanup").
+func (c *ValueCalculator) exportWarehouse(ctx context.Context, reason string) {
+	if c.warehouse == nil {
+		return
+	}
+	etlCtx, cancel := context.WithTimeout(ctx, warehouseETLTimeout)
+	defer cancel()
+	start := time.Now()
+	res, err := c.warehouse.RunWarehouseETL(etlCtx, start)
+	if err != nil {
+		slog.Error("Warehouse ETL failed", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("Warehouse ETL complete",
+		"reason", reason,
+		"from", res.From.Format(time.RFC3339),
+		"to", res.To.Format(time.RFC3339),
+		"bets_rows", res.Rows,
+		"matches", res.Matches,
+		"bookmakers", res.Bookmakers,
+		"duration", time.Since(start))
+}
@@ -0,0 +1,32 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextWarehouseRun(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	tests := []struct {
+		now   time.Time
+		runAt string
+		want  time.Time
+	}{
+		{time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC), "03:00", time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC), "03:00", time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 12, 31, 23, 30, 0, 0, time.UTC), "00:15", time.Date(2027, 1, 1, 0, 15, 0, 0, time.UTC)},
+		{time.Date(2026, 10, 16, 5, 0, 0, 0, msk), "03:00", time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)}, // 02:00 UTC
+	}
+	for _, tt := range tests {
+		got, err := nextDailyRun(tt.now, tt.runAt)
+		if err != nil {
+			t.Fatalf("nextDailyRun(%v, %q): %v", tt.now, tt.runAt, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("nextDailyRun(%v, %q) = %v, want %v", tt.now, tt.runAt, got, tt.want)
+		}
+	}
+	if _, err := nextDailyRun(time.Now(), "3am"); err == nil {
+		t.Error("invalid run_at must fail")
+	}
+}
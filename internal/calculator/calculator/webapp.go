@@ -0,0 +1,225 @@
+package calculator
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// webAppIndex is the Telegram WebApp page: sortable/filterable value bets and odds matrices.
+//
+//go:embed webapp/index.html
+var webAppIndex []byte
+
+// webAppInitDataHeader carries Telegram.WebApp.initData on /webapp/api/* requests.
+const webAppInitDataHeader = "X-Telegram-Init-Data"
+
+// registerWebApp registers the Telegram WebApp endpoints (value_calculator.webapp.enabled).
+func (c *ValueCalculator) registerWebApp(mux *http.ServeMux) {
+	mux.HandleFunc("/webapp/", c.handleWebAppIndex)
+	mux.HandleFunc("/webapp/api/value-bets", c.handleWebAppValueBets)
+	mux.HandleFunc("/webapp/api/matrix", c.handleWebAppMatrix)
+	slog.Info("Telegram WebApp enabled", "path", "/webapp/", "allowed_users", len(c.cfg.WebApp.AllowedUserIDs))
+}
+
+// handleWebAppIndex serves the WebApp page. The page has no data of its own; it calls
+// /webapp/api/* with its initData.
+func (c *ValueCalculator) handleWebAppIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/webapp/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(webAppIndex)
+}
+
+// handleWebAppValueBets returns value bets for the WebApp (sorting and filtering happen in the page).
+// GET /webapp/api/value-bets?status=live|upcoming
+func (c *ValueCalculator) handleWebAppValueBets(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.authWebApp(w, r); !ok {
+		return
+	}
+	matches, ok := c.webAppMatches(w, r)
+	if !ok {
+		return
+	}
+
+	valueBets := computeValueBets(matches, c.cfg.BookmakerWeights, c.cfg.ReferenceBookmakers, valueLimitsFromConfig(c.cfg), 300, c.fairOdds)
+	c.bets.remember(valueBets)
+	valueBets = filterValueBetsByStatus(valueBets, r.URL.Query().Get("status"), time.Now().UTC())
+	c.sizeStakes(valueBets)
+	sort.Slice(valueBets, func(i, j int) bool {
+		return valueBets[i].ValuePercent > valueBets[j].ValuePercent
+	})
+	if valueBets == nil {
+		valueBets = []ValueBet{}
+	}
+
+	writeWebAppJSON(w, http.StatusOK, map[string]interface{}{
+		"value_bets":    valueBets,
+		"count":         len(valueBets),
+		"calculated_at": time.Now().UTC(),
+	})
+}
+
+// handleWebAppMatrix returns the odds matrix (bets x bookmakers) of one match.
+// GET /webapp/api/matrix?match=<match_group_key>
+func (c *ValueCalculator) handleWebAppMatrix(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.authWebApp(w, r); !ok {
+		return
+	}
+	groupKey := r.URL.Query().Get("match")
+	if groupKey == "" {
+		writeWebAppJSON(w, http.StatusBadRequest, map[string]string{"error": "match is required"})
+		return
+	}
+	matches, ok := c.webAppMatches(w, r)
+	if !ok {
+		return
+	}
+	matrix := computeOddsMatrix(matches, groupKey)
+	if matrix == nil {
+		writeWebAppJSON(w, http.StatusNotFound, map[string]string{"error": "match not found"})
+		return
+	}
+	writeWebAppJSON(w, http.StatusOK, matrix)
+}
+
+// authWebApp validates the initData of the request and the allowed users list.
+func (c *ValueCalculator) authWebApp(w http.ResponseWriter, r *http.Request) (*webAppUser, bool) {
+	if c.cfg.TelegramBotToken == "" {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "telegram_bot_token is not configured"})
+		return nil, false
+	}
+	user, err := validateInitData(r.Header.Get(webAppInitDataHeader), c.cfg.TelegramBotToken, c.cfg.WebApp.AuthMaxAge, time.Now())
+	if err != nil {
+		slog.Warn("WebApp auth failed", "remote_addr", r.RemoteAddr, "error", err)
+		writeWebAppJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized: open the app from the Telegram bot"})
+		return nil, false
+	}
+	if allowed := c.cfg.WebApp.AllowedUserIDs; len(allowed) > 0 {
+		found := false
+		for _, id := range allowed {
+			if id == user.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			slog.Warn("WebApp access denied", "user_id", user.ID, "username", user.Username)
+			writeWebAppJSON(w, http.StatusForbidden, map[string]string{"error": "access denied"})
+			return nil, false
+		}
+	}
+	return user, true
+}
+
+func (c *ValueCalculator) webAppMatches(w http.ResponseWriter, r *http.Request) ([]models.Match, bool) {
+	if c.httpClient == nil {
+		writeWebAppJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "parser URL is not configured"})
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	matches, err := c.httpClient.GetMatchesAll(ctx)
+	if err != nil {
+		slog.Error("Failed to load matches for WebApp", "error", err)
+		writeWebAppJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to fetch matches from parser", "details": err.Error()})
+		return nil, false
+	}
+	return matches, true
+}
+
+func writeWebAppJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// computeOddsMatrix collects the best odd of every bookmaker for each bet of the match group
+// (same grouping as computeValueBets). Returns nil if no match has that group key.
+func computeOddsMatrix(matches []models.Match, groupKey string) *OddsMatrix {
+	var matrix *OddsMatrix
+	rows := map[string]*OddsMatrixRow{}
+	bookmakers := map[string]bool{}
+
+	for i := range matches {
+		m := matches[i]
+		if matchGroupKey(m) != groupKey {
+			continue
+		}
+		if matrix == nil {
+			matrix = &OddsMatrix{
+				MatchGroupKey: groupKey,
+				MatchName:     strings.TrimSpace(m.HomeTeam) + " vs " + strings.TrimSpace(m.AwayTeam),
+				StartTime:     m.StartTime,
+				Sport:         m.Sport,
+				Tournament:    m.Tournament,
+			}
+		}
+		if matrix.HomeMeta == nil {
+			matrix.HomeMeta = clientTeamMeta(m.HomeMeta)
+		}
+		if matrix.AwayMeta == nil {
+			matrix.AwayMeta = clientTeamMeta(m.AwayMeta)
+		}
+		if matrix.LeagueMeta == nil {
+			matrix.LeagueMeta = clientLeagueMeta(m.LeagueMeta)
+		}
+		for _, ev := range m.Events {
+			for _, out := range ev.Outcomes {
+				bk := strings.TrimSpace(out.Bookmaker)
+				if bk == "" {
+					bk = strings.TrimSpace(ev.Bookmaker)
+				}
+				if bk == "" {
+					bk = strings.TrimSpace(m.Bookmaker)
+				}
+				eventType := strings.TrimSpace(ev.EventType)
+				outcomeType := strings.TrimSpace(out.OutcomeType)
+				if bk == "" || eventType == "" || outcomeType == "" || !isFinitePositiveOdd(out.Odds) {
+					continue
+				}
+				param := strings.TrimSpace(out.Parameter)
+				betKey := models.OutcomeBetKey(ev, out).String()
+				row, ok := rows[betKey]
+				if !ok {
+					row = &OddsMatrixRow{BetKey: betKey, EventType: eventType, OutcomeType: outcomeType, Parameter: param, Participant: strings.TrimSpace(out.Participant), Odds: map[string]float64{}}
+					rows[betKey] = row
+				}
+				bk = strings.ToLower(bk)
+				bookmakers[bk] = true
+				if prev, ok := row.Odds[bk]; !ok || out.Odds > prev {
+					row.Odds[bk] = out.Odds
+				}
+				if out.Odds > row.BestOdd {
+					row.BestOdd = out.Odds
+					row.BestBookmaker = bk
+				}
+			}
+		}
+	}
+	if matrix == nil {
+		return nil
+	}
+
+	matrix.Bookmakers = make([]string, 0, len(bookmakers))
+	for bk := range bookmakers {
+		matrix.Bookmakers = append(matrix.Bookmakers, bk)
+	}
+	sort.Strings(matrix.Bookmakers)
+	matrix.Rows = make([]OddsMatrixRow, 0, len(rows))
+	for _, row := range rows {
+		matrix.Rows = append(matrix.Rows, *row)
+	}
+	sort.Slice(matrix.Rows, func(i, j int) bool { return matrix.Rows[i].BetKey < matrix.Rows[j].BetKey })
+	return matrix
+}
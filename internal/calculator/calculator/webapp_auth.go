@@ -0,0 +1,82 @@
+package calculator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultWebAppAuthMaxAge = 24 * time.Hour
+
+// webAppUser is the "user" field of Telegram WebApp initData.
+type webAppUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+// validateInitData checks Telegram WebApp initData (Telegram.WebApp.initData) signed with botToken
+// and returns the user who opened the WebApp.
+// See https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
+func validateInitData(initData, botToken string, maxAge time.Duration, now time.Time) (*webAppUser, error) {
+	if initData == "" {
+		return nil, fmt.Errorf("initData is empty")
+	}
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, fmt.Errorf("parse initData: %w", err)
+	}
+	hash := values.Get("hash")
+	if hash == "" {
+		return nil, fmt.Errorf("initData has no hash")
+	}
+
+	// data_check_string: all fields except hash, sorted by key, as key=value joined by \n
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k != "hash" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+"="+values.Get(k))
+	}
+
+	secret := hmacSHA256([]byte("WebAppData"), []byte(botToken))
+	expected := hex.EncodeToString(hmacSHA256(secret, []byte(strings.Join(lines, "\n"))))
+	if !hmac.Equal([]byte(expected), []byte(hash)) {
+		return nil, fmt.Errorf("initData signature mismatch")
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("initData has invalid auth_date")
+	}
+	if maxAge <= 0 {
+		maxAge = defaultWebAppAuthMaxAge
+	}
+	if age := now.Sub(time.Unix(authDate, 0)); age > maxAge {
+		return nil, fmt.Errorf("initData expired (%s old)", age.Round(time.Second))
+	}
+
+	var user webAppUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return nil, fmt.Errorf("initData has no user")
+	}
+	return &user, nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package calculator
+
+import (
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// signInitData builds initData the way Telegram does for the given fields.
+func signInitData(botToken string, fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	values := url.Values{}
+	for _, k := range keys {
+		lines = append(lines, k+"="+fields[k])
+		values.Set(k, fields[k])
+	}
+	secret := hmacSHA256([]byte("WebAppData"), []byte(botToken))
+	values.Set("hash", hex.EncodeToString(hmacSHA256(secret, []byte(strings.Join(lines, "\n")))))
+	return values.Encode()
+}
+
+func TestValidateInitData(t *testing.T) {
+	const token = "123456:TEST"
+	now := time.Unix(1_800_000_000, 0)
+	fields := map[string]string{
+		"auth_date": strconv.FormatInt(now.Add(-time.Minute).Unix(), 10),
+		"query_id":  "AAH",
+		"user":      `{"id":42,"first_name":"Test","username":"tester"}`,
+	}
+	initData := signInitData(token, fields)
+
+	user, err := validateInitData(initData, token, time.Hour, now)
+	if err != nil {
+		t.Fatalf("valid initData rejected: %v", err)
+	}
+	if user.ID != 42 || user.Username != "tester" {
+		t.Fatalf("user = %+v", user)
+	}
+
+	if _, err := validateInitData(initData, "654321:OTHER", time.Hour, now); err == nil {
+		t.Error("initData signed with another token accepted")
+	}
+	if _, err := validateInitData(initData, token, time.Hour, now.Add(2*time.Hour)); err == nil {
+		t.Error("expired initData accepted")
+	}
+	tampered := strings.Replace(initData, "42", "43", 1)
+	if _, err := validateInitData(tampered, token, time.Hour, now); err == nil {
+		t.Error("tampered initData accepted")
+	}
+	if _, err := validateInitData("", token, time.Hour, now); err == nil {
+		t.Error("empty initData accepted")
+	}
+}
+
+func TestComputeOddsMatrix(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	match := func(bookmaker string, home, draw float64) models.Match {
+		return models.Match{
+			HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start, Sport: "football", Bookmaker: bookmaker,
+			Events: []models.Event{{
+				EventType: "main_match",
+				Outcomes: []models.Outcome{
+					{OutcomeType: "home_win", Odds: home},
+					{OutcomeType: "draw", Odds: draw},
+				},
+			}},
+		}
+	}
+	matches := []models.Match{match("Fonbet", 2.10, 3.4), match("xbet1", 2.25, 0)}
+	matches[1].HomeMeta = &models.TeamMeta{Name: "Arsenal", Country: "England"}
+
+	m := computeOddsMatrix(matches, matchGroupKey(matches[0]))
+	if m == nil {
+		t.Fatal("matrix not found")
+	}
+	if strings.Join(m.Bookmakers, ",") != "fonbet,xbet1" {
+		t.Fatalf("bookmakers = %v", m.Bookmakers)
+	}
+	if len(m.Rows) != 2 || m.Rows[0].BetKey != "main_match|draw|" || m.Rows[1].BetKey != "main_match|home_win|" {
+		t.Fatalf("rows = %+v", m.Rows)
+	}
+	if m.HomeMeta == nil || m.HomeMeta.Name != "Arsenal" || m.AwayMeta != nil {
+		t.Errorf("home meta = %+v, away meta = %+v", m.HomeMeta, m.AwayMeta)
+	}
+	if _, ok := m.Rows[0].Odds["xbet1"]; ok {
+		t.Error("invalid odd included")
+	}
+	if home := m.Rows[1]; home.BestBookmaker != "xbet1" || home.BestOdd != 2.25 || home.Odds["fonbet"] != 2.10 {
+		t.Errorf("home_win row = %+v", home)
+	}
+
+	if computeOddsMatrix(matches, "football|unknown") != nil {
+		t.Error("matrix for unknown match")
+	}
+}
@@ -1,17 +1,22 @@
 package fonbet
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/performance"
 )
 
@@ -30,6 +35,14 @@ type BatchProcessor struct {
 	maxBatchSize    int
 	// lastProcessedCount — количество матчей, обработанных в последнем вызове ProcessSportEvents
 	lastProcessedCount atomic.Int64
+	// includeOutrights — сохранять долгосрочные события (победитель турнира) в /outrights
+	includeOutrights bool
+	// limits — ограничения текущего цикла (max_leagues / max_events_per_cycle); nil = без ограничений
+	limits *parserutil.CycleLimits
+	// leagues — фильтр турниров parser.fonbet.leagues (по названию сегмента); nil = все лиги
+	leagues *parserutil.LeagueFilter
+	// eventURL — шаблон страницы события на сайте parser.fonbet.event_url ({id} = id события); "" = без ссылок
+	eventURL string
 }
 
 // NewBatchProcessor creates a new batch processor
@@ -99,7 +112,7 @@ func (p *BatchProcessor) normalizeMainEventTeams(event *FonbetAPIEvent) {
 }
 
 // ProcessSportEvents processes events for a specific sport using batch operations
-func (p *BatchProcessor) ProcessSportEvents(sport string) error {
+func (p *BatchProcessor) ProcessSportEvents(ctx context.Context, sport string) error {
 	startTime := time.Now()
 	tracker := performance.GetTracker()
 
@@ -107,7 +120,7 @@ func (p *BatchProcessor) ProcessSportEvents(sport string) error {
 
 	// Fetch events for the sport (single HTTP request)
 	fetchStart := time.Now()
-	eventsData, err := p.eventFetcher.FetchEvents(sport)
+	eventsData, err := p.eventFetcher.FetchEvents(ctx, sport)
 	if err != nil {
 		return fmt.Errorf("failed to fetch events for sport %s: %w", sport, err)
 	}
@@ -144,9 +157,13 @@ func (p *BatchProcessor) ProcessSportEvents(sport string) error {
 
 	slog.Info(fmt.Sprintf("Fonbet: Found main matches %d", len(eventsByMatch)))
 
+	if p.includeOutrights {
+		p.extractOutrights(eventsByMatch, factorsByEventID, apiResponse.Sports, sport)
+	}
+
 	// Process matches in batches with parallel workers
 	processStart := time.Now()
-	processedCount, totalEvents, totalOutcomes, ydbWriteTime := p.processMatchesInBatches(eventsByMatch, factorsByEventID, sport)
+	processedCount, totalEvents, totalOutcomes, ydbWriteTime := p.processMatchesInBatches(eventsByMatch, factorsByEventID, apiResponse.Sports, sport)
 	processDuration := time.Since(processStart)
 
 	totalDuration := time.Since(startTime)
@@ -182,6 +199,7 @@ func (p *BatchProcessor) LastProcessedCount() int {
 func (p *BatchProcessor) processMatchesInBatches(
 	eventsByMatch map[string][]FonbetAPIEvent,
 	factorsByEventID map[int64]FonbetFactorGroup,
+	sports []FonbetSport,
 	sport string,
 ) (int, int, int, time.Duration) {
 	// Convert to slice for batch processing with filtering
@@ -234,6 +252,7 @@ func (p *BatchProcessor) processMatchesInBatches(
 	}
 
 	slog.Debug("Filtered out matches", "count", filteredCount, "reason", "invalid teams/name")
+	matches = p.limitMatches(matches, sports)
 
 	slog.Debug("Processing matches in batches", "total", len(matches), "batch_size", p.batchSize, "workers", p.workers)
 
@@ -269,6 +288,42 @@ func (p *BatchProcessor) processMatchesInBatches(
 	return processedCount, totalEvents, totalOutcomes, totalYDBWriteTime
 }
 
+// limitMatches applies parser.fonbet.leagues and the cycle guardrails (max_leagues / max_events_per_cycle) to one sport's matches.
+// A league is the Fonbet segment (FonbetAPIEvent.SportID); leagues are taken in ID order, matches
+// within a league by start time.
+func (p *BatchProcessor) limitMatches(matches []MatchData, sports []FonbetSport) []MatchData {
+	if p.limits == nil && p.leagues == nil {
+		return matches
+	}
+	names := make(map[int64]string, len(sports))
+	for _, s := range sports {
+		names[int64(s.ID)] = s.Name
+	}
+	byLeague := make(map[int64][]MatchData)
+	var leagueIDs []int64
+	for _, m := range matches {
+		id := m.MainEvent.SportID
+		if _, ok := byLeague[id]; !ok {
+			leagueIDs = append(leagueIDs, id)
+		}
+		byLeague[id] = append(byLeague[id], m)
+	}
+	sort.Slice(leagueIDs, func(i, j int) bool { return leagueIDs[i] < leagueIDs[j] })
+	leagueIDs = parserutil.FilterLeagues(p.leagues, leagueIDs, func(id int64) []string { return []string{names[id]} })
+
+	limited := make([]MatchData, 0, len(matches))
+	for _, id := range parserutil.LimitLeagues(p.limits, leagueIDs) {
+		league := byLeague[id]
+		sort.Slice(league, func(i, j int) bool { return league[i].MainEvent.StartTime < league[j].MainEvent.StartTime })
+		name := names[id]
+		if name == "" {
+			name = strconv.FormatInt(id, 10)
+		}
+		limited = append(limited, parserutil.LimitEvents(p.limits, name, league)...)
+	}
+	return limited
+}
+
 // isValidMatch проверяет, является ли матч валидным для обработки
 func (p *BatchProcessor) isValidMatch(event FonbetAPIEvent) bool {
 	// Фильтр 1: Пропускаем матчи с пустыми командами
@@ -427,15 +482,8 @@ func (p *BatchProcessor) worker(
 		var err error
 
 		// Киберспорт (dota2, cs, valorant, lol, kog, crossfire, callofduty) → отдельная модель EsportsMatch, не футбольная
-		if isEsportSport(match.Sport) {
-			var mainFactors []FonbetFactor
-			for _, g := range match.FactorGroups {
-				if g.EventID == match.MainEvent.ID {
-					mainFactors = g.Factors
-					break
-				}
-			}
-			lineMatch := BuildEsportsLineMatch(match.MainEvent, mainFactors, match.Sport, "Unknown Tournament", "fonbet")
+		if enums.Sport(match.Sport).IsEsports() {
+			lineMatch := BuildEsportsLineMatch(match.MainEvent, match.StatisticalEvents, match.FactorGroups, match.Sport, "Unknown Tournament", "fonbet")
 			if lineMatch != nil {
 				em := lineMatch.ToEsportsMatch()
 				if em != nil {
@@ -534,12 +582,6 @@ func isFonbetEsportCategoryID(sportCategoryID int) bool {
 		sportCategoryID == 22 || sportCategoryID == 78 || sportCategoryID == 148 || sportCategoryID == 169
 }
 
-// isEsportSport checks if sport string is an esports sport
-func isEsportSport(sport string) bool {
-	return sport == "dota2" || sport == "cs" || sport == "valorant" || 
-		sport == "lol" || sport == "kog" || sport == "crossfire" || sport == "callofduty"
-}
-
 func (p *BatchProcessor) getAllowedSportIDs(sports []FonbetSport, sportAlias string) map[int64]struct{} {
 	// Find top-level sport category id by alias (football, hockey, etc.)
 	sportCategoryID := 0
@@ -632,7 +674,7 @@ func (p *BatchProcessor) buildMatchWithEventsAndFactors(
 		Name:       mainEvent.Name,
 		HomeTeam:   mainEvent.Team1,
 		AwayTeam:   mainEvent.Team2,
-		StartTime:  time.Unix(mainEvent.StartTime, 0),
+		StartTime:  time.Unix(mainEvent.StartTime, 0).UTC(),
 		Category:   "football",
 		Tournament: "Unknown Tournament",
 		Kind:       mainEvent.Kind,
@@ -649,7 +691,7 @@ func (p *BatchProcessor) buildMatchWithEventsAndFactors(
 			Name:       event.Name,
 			HomeTeam:   event.Team1,
 			AwayTeam:   event.Team2,
-			StartTime:  time.Unix(event.StartTime, 0),
+			StartTime:  time.Unix(event.StartTime, 0).UTC(),
 			Category:   "football",
 			Tournament: "Unknown Tournament",
 			Kind:       event.Kind,
@@ -674,6 +716,14 @@ func (p *BatchProcessor) buildMatchWithEventsAndFactors(
 	}
 
 	if matchModel, ok := (*match).(*models.Match); ok {
+		// Строка матча общая для контор, поэтому ссылка хранится на исходах, как и букмекер
+		if u := models.EventURL(p.eventURL, mainFonbetEvent.ID); u != "" {
+			for i := range matchModel.Events {
+				for j := range matchModel.Events[i].Outcomes {
+					matchModel.Events[i].Outcomes[j].URL = u
+				}
+			}
+		}
 		return matchModel, nil
 	}
 
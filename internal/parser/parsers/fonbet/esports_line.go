@@ -2,15 +2,18 @@ package fonbet
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/line"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
-// BuildEsportsLineMatch builds line.Match from Fonbet main event and its factors (for esports: dota2, cs, valorant, lol, kog, crossfire, callofduty).
+// BuildEsportsLineMatch builds line.Match from Fonbet main event, its child events and factors (for esports: dota2, cs, valorant, lol, kog, crossfire, callofduty).
+// Main event factors give match winner, maps/rounds totals and handicaps; child events named "1-я карта" / "1st map" give map winners.
 // Used to feed AddEsportsMatch via line.Match.ToEsportsMatch().
-func BuildEsportsLineMatch(mainEvent FonbetAPIEvent, mainFactors []FonbetFactor, sport, league, bookmaker string) *line.Match {
+func BuildEsportsLineMatch(mainEvent FonbetAPIEvent, childEvents []FonbetAPIEvent, factorGroups []FonbetFactorGroup, sport, league, bookmaker string) *line.Match {
 	if mainEvent.Team1 == "" || mainEvent.Team2 == "" {
 		return nil
 	}
@@ -22,7 +25,21 @@ func BuildEsportsLineMatch(mainEvent FonbetAPIEvent, mainFactors []FonbetFactor,
 		bookmaker = "fonbet"
 	}
 
-	markets := buildEsportsMarketsFromFactors(mainFactors)
+	factorsByEvent := make(map[int64][]FonbetFactor, len(factorGroups))
+	for _, g := range factorGroups {
+		factorsByEvent[g.EventID] = g.Factors
+	}
+
+	markets := buildEsportsMarketsFromFactors(factorsByEvent[mainEvent.ID])
+	for _, child := range childEvents {
+		n, ok := models.ParseEsportsMapNumber(child.Name)
+		if !ok {
+			continue
+		}
+		if m, ok := buildEsportsMapWinnerMarket(n, factorsByEvent[child.ID]); ok {
+			markets = append(markets, m)
+		}
+	}
 	if len(markets) == 0 {
 		return nil
 	}
@@ -38,179 +55,152 @@ func BuildEsportsLineMatch(mainEvent FonbetAPIEvent, mainFactors []FonbetFactor,
 	}
 }
 
+// esportsTotalFactors are alternative total F IDs (CS esports uses different F for different total lines): F -> line, over.
+var esportsTotalFactors = map[int]struct {
+	param string
+	over  bool
+}{
+	1733: {"46.5", true}, 1734: {"46.5", false},
+	1727: {"47.5", true}, 1728: {"47.5", false},
+	1696: {"49.5", true}, 1697: {"49.5", false},
+	1730: {"50.5", true}, 1731: {"50.5", false},
+	1736: {"51.5", true}, 1737: {"51.5", false},
+	1739: {"52.5", true}, 1791: {"52.5", false},
+	3274: {"2.5", true}, 3275: {"2.5", false}, // total maps 2.5
+}
+
+// esportsWinnerOutcome maps a match/map winner factor to an outcome type.
+// F=910/912 carry a handicap when they have a parameter; F=921/922/923 are always the result.
+func esportsWinnerOutcome(f FonbetFactor) (string, bool) {
+	switch f.F {
+	case 921:
+		return string(models.OutcomeTypeHomeWin), true
+	case 922:
+		return string(models.OutcomeTypeDraw), true
+	case 923:
+		return string(models.OutcomeTypeAwayWin), true
+	case 910:
+		return string(models.OutcomeTypeHomeWin), f.Pt == ""
+	case 912:
+		return string(models.OutcomeTypeDraw), f.Pt == ""
+	}
+	return "", false
+}
+
 func buildEsportsMarketsFromFactors(factors []FonbetFactor) []line.Market {
-	var mainMarket line.Market
-	mainMarket.EventType = string(models.StandardEventMainMatch)
-	mainMarket.MarketName = models.GetMarketName(models.StandardEventMainMatch)
-	
+	mainMarket := line.Market{
+		EventType:  models.EsportsMarketMatchWinner,
+		MarketName: models.GetEsportsMarketName(models.EsportsMarketMatchWinner),
+	}
+
 	// Group totals by parameter to deduplicate (only one pair per line)
 	type totalPair struct {
 		overOdds  float64
 		underOdds float64
 	}
 	totalsByParam := make(map[string]*totalPair)
-	
+	setTotal := func(param string, over bool, odds float64) {
+		if param == "" || param[0] == '+' || param[0] == '-' {
+			return
+		}
+		t := totalsByParam[param]
+		if t == nil {
+			t = &totalPair{}
+			totalsByParam[param] = t
+		}
+		if over && t.overOdds == 0 {
+			t.overOdds = odds
+		}
+		if !over && t.underOdds == 0 {
+			t.underOdds = odds
+		}
+	}
+	var handicaps []line.Outcome
+
 	for _, f := range factors {
+		if outcomeType, ok := esportsWinnerOutcome(f); ok {
+			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: outcomeType, Odds: f.V})
+			continue
+		}
 		switch f.F {
-		case 910, 921:
-			// F=910 can be handicap if it has parameter; F=921 is always result
-			if f.F == 910 && f.Pt != "" {
-				// Skip - this is handicap, not result
-				continue
-			}
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "home_win", Parameter: "", Odds: f.V})
-		case 912, 922:
-			// F=912 can be handicap if it has parameter; F=922 is always draw
-			if f.F == 912 && f.Pt != "" {
-				// Skip - this is handicap, not draw
-				continue
-			}
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "draw", Parameter: "", Odds: f.V})
-		case 923:
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "away_win", Parameter: "", Odds: f.V})
-		case 930:
-			// Total rounds over (standard)
-			param := f.Pt
-			if param == "" && f.P != 0 {
-				param = fmt.Sprintf("%.1f", float64(f.P)/100.0)
-			}
-			if param != "" && param[0] != '+' && param[0] != '-' {
-				if totalsByParam[param] == nil {
-					totalsByParam[param] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 931:
-			// Total rounds under (standard)
+		case 930, 931:
+			// Standard total over/under: maps or rounds depending on the line
 			param := f.Pt
 			if param == "" && f.P != 0 {
 				param = fmt.Sprintf("%.1f", float64(f.P)/100.0)
 			}
-			if param != "" && param[0] != '+' && param[0] != '-' {
-				if totalsByParam[param] == nil {
-					totalsByParam[param] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam[param].underOdds = f.V
-				}
+			setTotal(param, f.F == 930, f.V)
+		case 910, 989, 927:
+			// Same IDs as football handicaps (see addHandicap)
+			if f.Pt != "" {
+				handicaps = append(handicaps, line.Outcome{OutcomeType: "handicap_home", Parameter: f.Pt, Odds: f.V})
 			}
-		// Total rounds: alternative F IDs (CS esports uses different F for different total lines)
-		case 1733: // 46.5 over
-			if f.Pt == "46.5" {
-				if totalsByParam["46.5"] == nil {
-					totalsByParam["46.5"] = &totalPair{overOdds: f.V}
-				}
+		case 912, 991, 928:
+			if f.Pt != "" {
+				handicaps = append(handicaps, line.Outcome{OutcomeType: "handicap_away", Parameter: f.Pt, Odds: f.V})
 			}
-		case 1734: // 46.5 under
-			if f.Pt == "46.5" {
-				if totalsByParam["46.5"] == nil {
-					totalsByParam["46.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["46.5"].underOdds = f.V
-				}
+		default:
+			if t, ok := esportsTotalFactors[f.F]; ok && f.Pt == t.param {
+				setTotal(t.param, t.over, f.V)
 			}
-		case 1727: // 47.5 over
-			if f.Pt == "47.5" {
-				if totalsByParam["47.5"] == nil {
-					totalsByParam["47.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 1728: // 47.5 under
-			if f.Pt == "47.5" {
-				if totalsByParam["47.5"] == nil {
-					totalsByParam["47.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["47.5"].underOdds = f.V
-				}
-			}
-		case 1696: // 49.5 over
-			if f.Pt == "49.5" {
-				if totalsByParam["49.5"] == nil {
-					totalsByParam["49.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 1697: // 49.5 under
-			if f.Pt == "49.5" {
-				if totalsByParam["49.5"] == nil {
-					totalsByParam["49.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["49.5"].underOdds = f.V
-				}
-			}
-		case 1730: // 50.5 over
-			if f.Pt == "50.5" {
-				if totalsByParam["50.5"] == nil {
-					totalsByParam["50.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 1731: // 50.5 under
-			if f.Pt == "50.5" {
-				if totalsByParam["50.5"] == nil {
-					totalsByParam["50.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["50.5"].underOdds = f.V
-				}
-			}
-		case 1736: // 51.5 over
-			if f.Pt == "51.5" {
-				if totalsByParam["51.5"] == nil {
-					totalsByParam["51.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 1737: // 51.5 under
-			if f.Pt == "51.5" {
-				if totalsByParam["51.5"] == nil {
-					totalsByParam["51.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["51.5"].underOdds = f.V
-				}
-			}
-		case 1739: // 52.5 over
-			if f.Pt == "52.5" {
-				if totalsByParam["52.5"] == nil {
-					totalsByParam["52.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 1791: // 52.5 under
-			if f.Pt == "52.5" {
-				if totalsByParam["52.5"] == nil {
-					totalsByParam["52.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["52.5"].underOdds = f.V
-				}
-			}
-		// Total maps 2.5 (CS esports)
-		case 3274: // Total maps 2.5 over
-			if f.Pt == "2.5" {
-				if totalsByParam["2.5"] == nil {
-					totalsByParam["2.5"] = &totalPair{overOdds: f.V}
-				}
-			}
-		case 3275: // Total maps 2.5 under
-			if f.Pt == "2.5" {
-				if totalsByParam["2.5"] == nil {
-					totalsByParam["2.5"] = &totalPair{underOdds: f.V}
-				} else {
-					totalsByParam["2.5"].underOdds = f.V
-				}
-			}
-		case 927, 928, 989, 991:
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "handicap_home", Parameter: f.Pt, Odds: f.V})
 		}
 	}
-	
-	// Add totals from totalsByParam (only pairs with both over and under)
-	for param, t := range totalsByParam {
-		if t.overOdds > 0 && t.underOdds > 0 {
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "total_over", Parameter: param, Odds: t.overOdds})
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "total_under", Parameter: param, Odds: t.underOdds})
-		} else if t.overOdds > 0 {
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "total_over", Parameter: param, Odds: t.overOdds})
-		} else if t.underOdds > 0 {
-			mainMarket.Outcomes = append(mainMarket.Outcomes, line.Outcome{OutcomeType: "total_under", Parameter: param, Odds: t.underOdds})
+
+	var markets []line.Market
+	if len(mainMarket.Outcomes) > 0 {
+		markets = append(markets, mainMarket)
+	}
+
+	// Handicaps and totals: maps (2.5, -1.5) and rounds/kills (46.5, -4.5) are separate markets
+	byType := map[string]*line.Market{}
+	var order []string
+	add := func(eventType string, o line.Outcome) {
+		m := byType[eventType]
+		if m == nil {
+			m = &line.Market{EventType: eventType, MarketName: models.GetEsportsMarketName(eventType)}
+			byType[eventType] = m
+			order = append(order, eventType)
+		}
+		m.Outcomes = append(m.Outcomes, o)
+	}
+	for _, h := range handicaps {
+		add(models.EsportsHandicapMarket(h.Parameter), h)
+	}
+	params := make([]string, 0, len(totalsByParam))
+	for param := range totalsByParam {
+		params = append(params, param)
+	}
+	sort.Slice(params, func(i, j int) bool {
+		a, _ := strconv.ParseFloat(params[i], 64)
+		b, _ := strconv.ParseFloat(params[j], 64)
+		return a < b
+	})
+	for _, param := range params {
+		t := totalsByParam[param]
+		eventType := models.EsportsTotalMarket(param)
+		if t.overOdds > 0 {
+			add(eventType, line.Outcome{OutcomeType: "total_over", Parameter: param, Odds: t.overOdds})
+		}
+		if t.underOdds > 0 {
+			add(eventType, line.Outcome{OutcomeType: "total_under", Parameter: param, Odds: t.underOdds})
 		}
 	}
-	
-	if len(mainMarket.Outcomes) == 0 {
-		return nil
+	for _, eventType := range order {
+		markets = append(markets, *byType[eventType])
+	}
+	return markets
+}
+
+// buildEsportsMapWinnerMarket builds the winner market of map n from its child event factors.
+func buildEsportsMapWinnerMarket(n int, factors []FonbetFactor) (line.Market, bool) {
+	eventType := models.EsportsMapWinnerMarket(n)
+	m := line.Market{EventType: eventType, MarketName: models.GetEsportsMarketName(eventType)}
+	for _, f := range factors {
+		outcomeType, ok := esportsWinnerOutcome(f)
+		if !ok || outcomeType == string(models.OutcomeTypeDraw) {
+			continue // a map can't end in a draw
+		}
+		m.Outcomes = append(m.Outcomes, line.Outcome{OutcomeType: outcomeType, Odds: f.V})
 	}
-	return []line.Market{mainMarket}
+	return m, len(m.Outcomes) > 0
 }
@@ -0,0 +1,73 @@
+package fonbet
+
+import (
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/line"
+)
+
+func marketsByType(markets []line.Market) map[string][]line.Outcome {
+	out := map[string][]line.Outcome{}
+	for _, m := range markets {
+		out[m.EventType] = append(out[m.EventType], m.Outcomes...)
+	}
+	return out
+}
+
+func TestBuildEsportsLineMatch(t *testing.T) {
+	main := FonbetAPIEvent{ID: 1, Team1: "Team Spirit", Team2: "NAVI", StartTime: 1767261600, Level: 1}
+	children := []FonbetAPIEvent{
+		{ID: 2, Name: "1-я карта", Level: 2, ParentID: 1},
+		{ID: 3, Name: "2nd map", Level: 2, ParentID: 1},
+		{ID: 4, Name: "Угловые", Level: 2, ParentID: 1},
+	}
+	groups := []FonbetFactorGroup{
+		{EventID: 1, Factors: []FonbetFactor{
+			{F: 921, V: 1.6}, {F: 923, V: 2.3},
+			{F: 930, V: 1.9, Pt: "2.5"}, {F: 931, V: 1.85, Pt: "2.5"},
+			{F: 1733, V: 1.8, Pt: "46.5"}, {F: 1734, V: 1.95, Pt: "46.5"},
+			{F: 927, V: 2.4, Pt: "-1.5"}, {F: 928, V: 1.5, Pt: "+1.5"},
+		}},
+		{EventID: 2, Factors: []FonbetFactor{{F: 921, V: 1.7}, {F: 922, V: 9}, {F: 923, V: 2.1}}},
+		{EventID: 3, Factors: []FonbetFactor{{F: 921, V: 1.5}, {F: 923, V: 2.5}}},
+		{EventID: 4, Factors: []FonbetFactor{{F: 921, V: 1.1}}},
+	}
+
+	lm := BuildEsportsLineMatch(main, children, groups, "dota2", "", "")
+	if lm == nil {
+		t.Fatal("nil match")
+	}
+	if lm.Sport != "dota2" || lm.Bookmaker != "fonbet" {
+		t.Errorf("sport/bookmaker = %s/%s", lm.Sport, lm.Bookmaker)
+	}
+	got := marketsByType(lm.Markets)
+
+	want := map[string]int{
+		"main_match":    2,
+		"total_maps":    2,
+		"total_rounds":  2,
+		"handicap_maps": 2,
+		"map_1_winner":  2, // the map draw factor is dropped
+		"map_2_winner":  2,
+	}
+	for eventType, n := range want {
+		if len(got[eventType]) != n {
+			t.Errorf("%s: %d outcomes, want %d (%+v)", eventType, len(got[eventType]), n, got[eventType])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("unexpected markets: %v", got)
+	}
+	for _, o := range got["handicap_maps"] {
+		if (o.OutcomeType == "handicap_home") != (o.Parameter == "-1.5") {
+			t.Errorf("handicap side mismatch: %+v", o)
+		}
+	}
+}
+
+func TestBuildEsportsLineMatchNoMarkets(t *testing.T) {
+	main := FonbetAPIEvent{ID: 1, Team1: "A", Team2: "B"}
+	if lm := BuildEsportsLineMatch(main, nil, nil, "cs", "", ""); lm != nil {
+		t.Errorf("expected nil without factors, got %+v", lm)
+	}
+}
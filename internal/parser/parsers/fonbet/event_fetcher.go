@@ -2,16 +2,20 @@ package fonbet
 
 import (
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums/fonbet"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 // EventFetcher handles fetching events from Fonbet API
@@ -19,6 +23,8 @@ type EventFetcher struct {
 	client  *http.Client
 	config  *config.Config
 	baseURL string
+	limiter *ratelimit.Limiter
+	fp      *fingerprint.Profile
 }
 
 // NewEventFetcher creates a new event fetcher with connection pooling
@@ -34,22 +40,24 @@ func NewEventFetcher(config *config.Config) interfaces.EventFetcher {
 	return &EventFetcher{
 		client: &http.Client{
 			Timeout:   config.Parser.Timeout,
-			Transport: transport,
+			Transport: circuitbreaker.WrapParser("Fonbet", transport),
 		},
 		config:  config,
 		baseURL: config.Parser.Fonbet.BaseURL,
+		limiter: newRateLimiter(config),
+		fp:      newFingerprint(config),
 	}
 }
 
 // FetchEvents fetches events for a specific sport with retry logic
-func (f *EventFetcher) FetchEvents(sport string) ([]byte, error) {
+func (f *EventFetcher) FetchEvents(ctx context.Context, sport string) ([]byte, error) {
 	var lastErr error
 	maxRetries := 3
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		slog.Debug("HTTP fetch attempt", "attempt", attempt, "max_retries", maxRetries, "sport", sport)
 
-		req, err := http.NewRequest("GET", f.baseURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", f.baseURL, nil)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to create request: %w", err)
 			continue
@@ -66,11 +74,14 @@ func (f *EventFetcher) FetchEvents(sport string) ([]byte, error) {
 		}
 		req.URL.RawQuery = q.Encode()
 
-		req.Header.Set("User-Agent", f.config.Parser.UserAgent)
 		for key, value := range f.config.Parser.Headers {
 			req.Header.Set(key, value)
 		}
+		f.fp.Apply(req)
 
+		if err := f.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
 		resp, err := f.client.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to make request (attempt %d): %w", attempt, err)
@@ -100,9 +111,9 @@ func (f *EventFetcher) FetchEvents(sport string) ([]byte, error) {
 }
 
 // FetchEventFactors fetches factors for a specific event
-func (f *EventFetcher) FetchEventFactors(eventID int64) ([]byte, error) {
+func (f *EventFetcher) FetchEventFactors(ctx context.Context, eventID int64) ([]byte, error) {
 	eventURL := "https://line52w.bk6bba-resources.com/events/event"
-	req, err := http.NewRequest("GET", eventURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", eventURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -114,11 +125,14 @@ func (f *EventFetcher) FetchEventFactors(eventID int64) ([]byte, error) {
 	q.Set("scopeMarket", "1600") // Football scope market
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("User-Agent", f.config.Parser.UserAgent)
 	for key, value := range f.config.Parser.Headers {
 		req.Header.Set(key, value)
 	}
+	f.fp.Apply(req)
 
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := f.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -49,7 +49,7 @@ func (p *EventProcessor) ProcessEvent(event interface{}) error {
 	}
 
 	// Get factors for this specific event
-	factorData, err := p.eventFetcher.FetchEventFactors(eventID)
+	factorData, err := p.eventFetcher.FetchEventFactors(context.Background(), eventID)
 	if err != nil {
 		return fmt.Errorf("failed to get factors for event %s: %w", fonbetEvent.ID, err)
 	}
@@ -115,9 +115,9 @@ func (p *EventProcessor) ProcessEvents(events []interface{}) error {
 }
 
 // ProcessSportEvents processes events for a specific sport
-func (p *EventProcessor) ProcessSportEvents(sport string) error {
+func (p *EventProcessor) ProcessSportEvents(ctx context.Context, sport string) error {
 	// Fetch events for the sport
-	eventsData, err := p.eventFetcher.FetchEvents(sport)
+	eventsData, err := p.eventFetcher.FetchEvents(ctx, sport)
 	if err != nil {
 		return fmt.Errorf("failed to fetch events for sport %s: %w", sport, err)
 	}
@@ -142,7 +142,7 @@ func (p *EventProcessor) ProcessSportEvents(sport string) error {
 		statisticalEvents := matchEvents[1:] // All other events are statistical
 
 		// Process the match with all its events
-		if err := p.processMatchWithEvents(mainEvent, statisticalEvents); err != nil {
+		if err := p.processMatchWithEvents(ctx, mainEvent, statisticalEvents); err != nil {
 			slog.Error("Failed to process match", "match_id", matchID, "error", err)
 			continue
 		}
@@ -183,7 +183,7 @@ func (p *EventProcessor) groupEventsByMatch(events []interface{}) map[string][]i
 }
 
 // processMatchWithEvents processes a main match with all its statistical events
-func (p *EventProcessor) processMatchWithEvents(mainEvent interface{}, statisticalEvents []interface{}) error {
+func (p *EventProcessor) processMatchWithEvents(ctx context.Context, mainEvent interface{}, statisticalEvents []interface{}) error {
 	// Convert to FonbetEvent for processing
 	mainFonbetEvent, ok := mainEvent.(FonbetAPIEvent)
 	if !ok {
@@ -191,7 +191,7 @@ func (p *EventProcessor) processMatchWithEvents(mainEvent interface{}, statistic
 	}
 
 	// Get factors for main event
-	mainFactors, err := p.getEventFactors(mainFonbetEvent.ID)
+	mainFactors, err := p.getEventFactors(ctx, mainFonbetEvent.ID)
 	if err != nil {
 		slog.Error("Failed to get factors for main event", "event_id", mainFonbetEvent.ID, "error", err)
 		mainFactors = []FonbetFactor{}
@@ -208,7 +208,7 @@ func (p *EventProcessor) processMatchWithEvents(mainEvent interface{}, statistic
 	if p.storage == nil {
 		return nil
 	}
-	if err := p.storage.StoreMatch(context.Background(), match); err != nil {
+	if err := p.storage.StoreMatch(ctx, match); err != nil {
 		return fmt.Errorf("failed to store match: %w", err)
 	}
 
@@ -217,8 +217,8 @@ func (p *EventProcessor) processMatchWithEvents(mainEvent interface{}, statistic
 }
 
 // getEventFactors gets factors for a specific event
-func (p *EventProcessor) getEventFactors(eventID int64) ([]FonbetFactor, error) {
-	factorData, err := p.eventFetcher.FetchEventFactors(eventID)
+func (p *EventProcessor) getEventFactors(ctx context.Context, eventID int64) ([]FonbetFactor, error) {
+	factorData, err := p.eventFetcher.FetchEventFactors(ctx, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get factors: %w", err)
 	}
@@ -248,7 +248,7 @@ func (p *EventProcessor) buildHierarchicalMatch(mainEvent FonbetAPIEvent, statis
 		Name:       mainEvent.Name,
 		HomeTeam:   mainEvent.Team1,
 		AwayTeam:   mainEvent.Team2,
-		StartTime:  time.Unix(mainEvent.StartTime, 0),
+		StartTime:  time.Unix(mainEvent.StartTime, 0).UTC(),
 		Category:   "football",
 		Tournament: "Unknown Tournament",
 		Kind:       mainEvent.Kind,
@@ -266,7 +266,7 @@ func (p *EventProcessor) buildHierarchicalMatch(mainEvent FonbetAPIEvent, statis
 				Name:       apiEvent.Name,
 				HomeTeam:   apiEvent.Team1,
 				AwayTeam:   apiEvent.Team2,
-				StartTime:  time.Unix(apiEvent.StartTime, 0),
+				StartTime:  time.Unix(apiEvent.StartTime, 0).UTC(),
 				Category:   "football",
 				Tournament: "Unknown Tournament",
 				Kind:       apiEvent.Kind,
@@ -2,33 +2,56 @@ package fonbet
 
 import (
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums/fonbet"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 type HTTPClient struct {
 	client  *http.Client
 	config  *config.Config
 	baseURL string
+	limiter *ratelimit.Limiter
+	fp      *fingerprint.Profile
 }
 
 func NewHTTPClient(config *config.Config) *HTTPClient {
 	return &HTTPClient{
 		client: &http.Client{
-			Timeout: config.Parser.Timeout,
+			Timeout:   config.Parser.Timeout,
+			Transport: circuitbreaker.WrapParser("Fonbet", nil),
 		},
 		config:  config,
 		baseURL: config.Parser.Fonbet.BaseURL,
+		limiter: newRateLimiter(config),
+		fp:      newFingerprint(config),
 	}
 }
 
-func (c *HTTPClient) GetEvents(sport enums.Sport) ([]byte, error) {
-	req, err := http.NewRequest("GET", c.baseURL, nil)
+// newRateLimiter returns the shared Fonbet limiter (parser.fonbet.rate_limit; unlimited by default).
+func newRateLimiter(cfg *config.Config) *ratelimit.Limiter {
+	return ratelimit.ForParser("fonbet", cfg.Parser.Fonbet.RateLimit, config.RateLimitConfig{})
+}
+
+// newFingerprint returns the shared Fonbet header profile (parser.fonbet.fingerprint; parser.user_agent by default).
+func newFingerprint(cfg *config.Config) *fingerprint.Profile {
+	var def config.FingerprintConfig
+	if cfg.Parser.UserAgent != "" {
+		def.UserAgents = []string{cfg.Parser.UserAgent}
+	}
+	return fingerprint.ForParser("fonbet", cfg.Parser.Fingerprint, cfg.Parser.Fonbet.Fingerprint, def)
+}
+
+func (c *HTTPClient) GetEvents(ctx context.Context, sport enums.Sport) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -41,11 +64,14 @@ func (c *HTTPClient) GetEvents(sport enums.Sport) ([]byte, error) {
 	q.Set("scopeMarket", scopeMarket.String())
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("User-Agent", c.config.Parser.UserAgent)
 	for key, value := range c.config.Parser.Headers {
 		req.Header.Set(key, value)
 	}
+	c.fp.Apply(req)
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -79,9 +105,9 @@ func (c *HTTPClient) GetEvents(sport enums.Sport) ([]byte, error) {
 }
 
 // GetEventFactors gets factors for a specific event
-func (c *HTTPClient) GetEventFactors(eventID int64) ([]byte, error) {
+func (c *HTTPClient) GetEventFactors(ctx context.Context, eventID int64) ([]byte, error) {
 	eventURL := "https://line52w.bk6bba-resources.com/events/event"
-	req, err := http.NewRequest("GET", eventURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", eventURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -93,11 +119,14 @@ func (c *HTTPClient) GetEventFactors(eventID int64) ([]byte, error) {
 	q.Set("scopeMarket", "1600") // Football scope market
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("User-Agent", c.config.Parser.UserAgent)
 	for key, value := range c.config.Parser.Headers {
 		req.Header.Set(key, value)
 	}
+	c.fp.Apply(req)
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -63,7 +63,7 @@ func (p *JSONParser) ParseEvents(jsonData []byte) ([]FonbetEvent, error) {
 				Name:       event.Name,
 				HomeTeam:   homeTeam,
 				AwayTeam:   awayTeam,
-				StartTime:  time.Unix(event.StartTime, 0),
+				StartTime:  time.Unix(event.StartTime, 0).UTC(),
 				Category:   "football",
 				Tournament: "Unknown Tournament",
 				Kind:       event.Kind,
@@ -70,14 +70,12 @@ func (b *MatchBuilder) BuildMatch(mainEvent interface{}, statisticalEvents []int
 		UpdatedAt:  now,
 	}
 	
-	// Add main match event
-	mainEventModel, err := b.buildMainEvent(fonbetEvent, factorGroups)
+	// Add main match event (and the markets quoted with it: both teams to score, correct score)
+	mainEvents, err := b.buildMainEvents(fonbetEvent, factorGroups)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build main event: %w", err)
 	}
-	if mainEventModel != nil {
-		match.Events = append(match.Events, *mainEventModel)
-	}
+	match.Events = append(match.Events, mainEvents...)
 	
 	// Add statistical events
 	for _, statEvent := range statEvents {
@@ -120,8 +118,9 @@ func (b *MatchBuilder) BuildEvent(eventData interface{}, odds map[string]float64
 	return &result, nil
 }
 
-// buildMainEvent builds the main match event
-func (b *MatchBuilder) buildMainEvent(fonbetEvent FonbetEvent, factorGroups []FonbetFactorGroup) (*models.Event, error) {
+// buildMainEvents builds the main match event and one event per market Fonbet quotes among the main
+// match factors under a different standard event type (mainMarketEventType).
+func (b *MatchBuilder) buildMainEvents(fonbetEvent FonbetEvent, factorGroups []FonbetFactorGroup) ([]models.Event, error) {
 	// Parse odds for main event
 	eventID, err := strconv.ParseInt(fonbetEvent.ID, 10, 64)
 	if err != nil {
@@ -135,7 +134,44 @@ func (b *MatchBuilder) buildMainEvent(fonbetEvent FonbetEvent, factorGroups []Fo
 		return nil, nil
 	}
 
-	return b.buildEventModel(fonbetEvent, mainOdds)
+	other := map[models.StandardEventType]map[string]float64{}
+	for outcome, odd := range mainOdds {
+		if eventType, ok := mainMarketEventType(outcome); ok {
+			if other[eventType] == nil {
+				other[eventType] = map[string]float64{}
+			}
+			other[eventType][outcome] = odd
+			delete(mainOdds, outcome)
+		}
+	}
+
+	var events []models.Event
+	if len(mainOdds) > 0 {
+		event, err := b.buildEventModel(fonbetEvent, mainOdds)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+	for _, eventType := range []models.StandardEventType{models.StandardEventBothTeamsToScore, models.StandardEventCorrectScore} {
+		if odds := other[eventType]; len(odds) > 0 {
+			events = append(events, *b.newEventModel(fonbetEvent, eventType, odds))
+		}
+	}
+	return events, nil
+}
+
+// mainMarketEventType returns the event type of a main match outcome that belongs to another market.
+func mainMarketEventType(outcome string) (models.StandardEventType, bool) {
+	switch {
+	case strings.HasPrefix(outcome, "btts_"):
+		return models.StandardEventBothTeamsToScore, true
+	case strings.HasPrefix(outcome, "score_"):
+		return models.StandardEventCorrectScore, true
+	}
+	return "", false
 }
 
 // buildStatisticalEvent builds a statistical event
@@ -153,14 +189,18 @@ func (b *MatchBuilder) buildStatisticalEvent(fonbetEvent FonbetEvent, factors []
 
 // buildEventModel creates a models.Event from FonbetEvent and odds
 func (b *MatchBuilder) buildEventModel(fonbetEvent FonbetEvent, odds map[string]float64) (*models.Event, error) {
-	now := time.Now()
-	
 	// Determine event type
 	eventType, ok := b.getStandardEventType(fonbetEvent)
 	if !ok {
 		// Do not downgrade unknown statistical events into main_match.
 		return nil, nil
 	}
+	return b.newEventModel(fonbetEvent, eventType, odds), nil
+}
+
+// newEventModel creates a models.Event of eventType from FonbetEvent and odds
+func (b *MatchBuilder) newEventModel(fonbetEvent FonbetEvent, eventType models.StandardEventType, odds map[string]float64) *models.Event {
+	now := time.Now()
 	marketName := models.GetMarketName(eventType)
 
 	matchID := models.CanonicalMatchID(fonbetEvent.HomeTeam, fonbetEvent.AwayTeam, fonbetEvent.StartTime)
@@ -199,7 +239,7 @@ func (b *MatchBuilder) buildEventModel(fonbetEvent FonbetEvent, odds map[string]
 		event.Outcomes = append(event.Outcomes, *outcome)
 	}
 	
-	return event, nil
+	return event
 }
 
 // getStandardEventType maps Fonbet event Kind/Level to standard event type.
@@ -250,8 +290,12 @@ func (b *MatchBuilder) getStandardOutcomeType(outcome string) models.StandardOut
 		return models.OutcomeTypeAltTotalOver
 	case strings.HasPrefix(outcome, "alt_total_under_"):
 		return models.OutcomeTypeAltTotalUnder
+	case strings.HasPrefix(outcome, "interval_"):
+		return models.OutcomeTypeTotalInterval
 	case strings.HasPrefix(outcome, "exact_"):
 		return models.OutcomeTypeExactCount
+	case strings.HasPrefix(outcome, "score_"):
+		return models.OutcomeTypeCorrectScore
 	default:
 		return models.StandardOutcomeType(outcome)
 	}
@@ -277,9 +321,15 @@ func (b *MatchBuilder) getParameterFromOutcome(outcome string) string {
 	if strings.HasPrefix(outcome, "alt_total_under_") {
 		return strings.TrimPrefix(outcome, "alt_total_under_")
 	}
+	if strings.HasPrefix(outcome, "interval_") {
+		return strings.TrimPrefix(outcome, "interval_")
+	}
 	if strings.HasPrefix(outcome, "exact_") {
 		return strings.TrimPrefix(outcome, "exact_")
 	}
+	if strings.HasPrefix(outcome, "score_") {
+		return strings.TrimPrefix(outcome, "score_")
+	}
 	return ""
 }
 
@@ -3,6 +3,7 @@ package fonbet
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
@@ -147,7 +148,20 @@ func (p *OddsParser) parseMainMatchOdds(factors []FonbetFactor) map[string]float
 		case 931: // Total under
 			addTotalFromFactor(odds, "total_under_", factor)
 
+		// Both teams to score: yes / no (split into their own event by the match builder).
+		case 4241:
+			odds["btts_yes"] = factor.V
+		case 4242:
+			odds["btts_no"] = factor.V
+
 		default:
+			// Correct score factors carry the score in pt ("2:1"); handicaps and intervals never contain ':'.
+			if strings.Contains(factor.Pt, ":") {
+				if home, away, ok := models.ParseScoreParam(factor.Pt); ok {
+					odds["score_"+models.ScoreParam(home, away)] = factor.V
+				}
+				continue
+			}
 			addHandicap(odds, factor)
 		}
 	}
@@ -175,6 +189,11 @@ func (p *OddsParser) parseCornerOdds(factors []FonbetFactor) map[string]float64
 		case 931:
 			addTotalFromFactor(odds, "total_under_", factor)
 		default:
+			// Interval totals ("9-11", "12+") don't fit over/under: keep them as a separate outcome type.
+			if _, _, ok := models.ParseIntervalParam(factor.Pt); ok {
+				odds["interval_"+strings.ReplaceAll(factor.Pt, " ", "")] = factor.V
+				continue
+			}
 			// Corners handicap uses the same signed pt mechanism.
 			addHandicap(odds, factor)
 		}
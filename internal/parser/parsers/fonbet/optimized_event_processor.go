@@ -36,13 +36,13 @@ func NewOptimizedEventProcessor(
 }
 
 // ProcessSportEvents processes events for a specific sport using data from main API response
-func (p *OptimizedEventProcessor) ProcessSportEvents(sport string) error {
+func (p *OptimizedEventProcessor) ProcessSportEvents(ctx context.Context, sport string) error {
 	startTime := time.Now()
 	slog.Info(fmt.Sprintf("Fonbet: Processing sport %s", sport))
 
 	// Fetch events for the sport (single HTTP request)
 	fetchStart := time.Now()
-	eventsData, err := p.eventFetcher.FetchEvents(sport)
+	eventsData, err := p.eventFetcher.FetchEvents(ctx, sport)
 	if err != nil {
 		return fmt.Errorf("failed to fetch events for sport %s: %w", sport, err)
 	}
@@ -179,7 +179,7 @@ func (p *OptimizedEventProcessor) processMatchWithEventsAndFactors(
 		Name:       mainEvent.Name,
 		HomeTeam:   mainEvent.Team1,
 		AwayTeam:   mainEvent.Team2,
-		StartTime:  time.Unix(mainEvent.StartTime, 0),
+		StartTime:  time.Unix(mainEvent.StartTime, 0).UTC(),
 		Category:   "football",
 		Tournament: "Unknown Tournament",
 		Kind:       mainEvent.Kind,
@@ -196,7 +196,7 @@ func (p *OptimizedEventProcessor) processMatchWithEventsAndFactors(
 			Name:       event.Name,
 			HomeTeam:   event.Team1,
 			AwayTeam:   event.Team2,
-			StartTime:  time.Unix(event.StartTime, 0),
+			StartTime:  time.Unix(event.StartTime, 0).UTC(),
 			Category:   "football",
 			Tournament: "Unknown Tournament",
 			Kind:       event.Kind,
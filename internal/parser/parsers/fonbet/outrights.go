@@ -0,0 +1,91 @@
+package fonbet
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// extractOutrights stores long-term events (tournament winner etc.) as Outright.
+// Such events are Level 1 without a team pair, so isValidMatch drops them as matches;
+// Fonbet puts the participant name of each factor into its pt field.
+// Returns the number of outrights stored.
+func (p *BatchProcessor) extractOutrights(
+	eventsByMatch map[string][]FonbetAPIEvent,
+	factorsByEventID map[int64]FonbetFactorGroup,
+	sports []FonbetSport,
+	sport string,
+) int {
+	segmentNames := make(map[int64]string, len(sports))
+	for _, s := range sports {
+		segmentNames[int64(s.ID)] = strings.TrimSpace(s.Name)
+	}
+
+	now := time.Now()
+	stored := 0
+	for _, matchEvents := range eventsByMatch {
+		if len(matchEvents) == 0 {
+			continue
+		}
+		mainEvent := matchEvents[0]
+		p.normalizeMainEventTeams(&mainEvent)
+		if mainEvent.Team1 != "" || mainEvent.Team2 != "" {
+			continue
+		}
+		group, ok := factorsByEventID[mainEvent.ID]
+		if !ok {
+			continue
+		}
+		if o := buildFonbetOutright(mainEvent, group.Factors, segmentNames[mainEvent.SportID], sport, now); o != nil {
+			health.AddOutright(o)
+			stored++
+		}
+	}
+	if stored > 0 {
+		slog.Info("Fonbet: outrights processed", "sport", sport, "count", stored)
+	}
+	return stored
+}
+
+// buildFonbetOutright converts one outright event into models.Outright. Returns nil if no factor names a participant.
+func buildFonbetOutright(event FonbetAPIEvent, factors []FonbetFactor, tournament, sport string, now time.Time) *models.Outright {
+	var outcomes []models.OutrightOutcome
+	for _, f := range factors {
+		participant := strings.TrimSpace(f.Pt)
+		if participant == "" || f.V <= 1 {
+			continue
+		}
+		outcomes = append(outcomes, models.OutrightOutcome{
+			Participant: participant,
+			Odds:        f.V,
+			Bookmaker:   "Fonbet",
+			UpdatedAt:   now,
+		})
+	}
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	market := strings.TrimSpace(event.Name)
+	if market == "" {
+		market = "Winner"
+	}
+	var closeTime time.Time
+	if event.StartTime > 0 {
+		closeTime = time.Unix(event.StartTime, 0).UTC()
+	}
+	return &models.Outright{
+		ID:         models.OutrightID("fonbet", sport, tournament, market),
+		Sport:      sport,
+		Tournament: tournament,
+		MarketName: market,
+		Bookmaker:  "Fonbet",
+		CloseTime:  closeTime,
+		Outcomes:   outcomes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
@@ -2,8 +2,10 @@ package fonbet
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
@@ -21,6 +23,9 @@ type Parser struct {
 	eventProcessor interfaces.EventProcessor
 	storage        interfaces.Storage
 	config         *config.Config
+
+	// runMu serializes runOnce and ParseSport: they share the batch processor's limits and counters
+	runMu sync.Mutex
 	
 	// Incremental parsing state
 	incState *parserutil.IncrementalParserState
@@ -32,6 +37,11 @@ func NewParser(config *config.Config) *Parser {
 	oddsParser := NewOddsParser()
 	matchBuilder := NewMatchBuilder("fonbet")
 	eventProcessor := NewBatchProcessor(nil, eventFetcher, oddsParser, matchBuilder)
+	if bp, ok := eventProcessor.(*BatchProcessor); ok {
+		bp.includeOutrights = config.Parser.Fonbet.IncludeOutrights
+		bp.leagues = parserutil.NewLeagueFilter("Fonbet", config.Parser.Fonbet.Leagues)
+		bp.eventURL = config.Parser.Fonbet.EventURL
+	}
 
 	return &Parser{
 		eventFetcher:   eventFetcher,
@@ -43,17 +53,32 @@ func NewParser(config *config.Config) *Parser {
 	}
 }
 
+// startCycleLimits gives the batch processor fresh max_leagues / max_events_per_cycle budgets for a cycle.
+func (p *Parser) startCycleLimits() *parserutil.CycleLimits {
+	limits := parserutil.NewCycleLimits("Fonbet", p.config.Parser.Fonbet.MaxEventsPerCycle, p.config.Parser.Fonbet.MaxLeagues)
+	if bp, ok := p.eventProcessor.(*BatchProcessor); ok {
+		bp.limits = limits
+	}
+	return limits
+}
+
 // runOnce performs a single parsing run for all configured sports
 func (p *Parser) runOnce(ctx context.Context) error {
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
 	start := time.Now()
 	var totalMatches int
 	defer func() {
 		slog.Info("Fonbet: цикл парсинга завершён", "matches", totalMatches, "duration", time.Since(start))
 	}()
+	defer p.startCycleLimits().LogSummary()
+	// On shutdown drain the sport in progress is stored, no new sport is fetched
+	sportsCtx, stopSports := parserutil.DrainContext(ctx)
+	defer stopSports()
 
 	for _, sportStr := range p.config.ValueCalculator.Sports {
 		select {
-		case <-ctx.Done():
+		case <-sportsCtx.Done():
 			return ctx.Err()
 		default:
 		}
@@ -64,7 +89,7 @@ func (p *Parser) runOnce(ctx context.Context) error {
 			continue
 		}
 
-		if err := p.eventProcessor.ProcessSportEvents(sportStr); err != nil {
+		if err := p.eventProcessor.ProcessSportEvents(ctx, sportStr); err != nil {
 			slog.Error("Failed to parse events", "sport", sport, "error", err)
 			continue
 		}
@@ -100,6 +125,30 @@ func (p *Parser) ParseOnce(ctx context.Context) error {
 	return p.runOnce(ctx)
 }
 
+// ParseSport runs a single parsing pass for one sport (parser.sports polls each sport on its own interval).
+func (p *Parser) ParseSport(ctx context.Context, sportStr string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, valid := enums.ParseSport(sportStr); !valid {
+		return fmt.Errorf("unsupported sport %q", sportStr)
+	}
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
+	start := time.Now()
+	defer p.startCycleLimits().LogSummary()
+
+	if err := p.eventProcessor.ProcessSportEvents(ctx, sportStr); err != nil {
+		return fmt.Errorf("%s: %w", sportStr, err)
+	}
+	var matches int
+	if bp, ok := p.eventProcessor.(*BatchProcessor); ok {
+		matches = bp.LastProcessedCount()
+	}
+	slog.Info("Fonbet: sport parsed", "sport", sportStr, "matches", matches, "duration", time.Since(start))
+	return nil
+}
+
 func (p *Parser) Stop() error {
 	if p.incState != nil {
 		p.incState.Stop("Fonbet")
@@ -146,10 +195,30 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("Fonbet")
 }
 
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("Fonbet")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("Fonbet")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
 // incrementalLoop is now handled by parserutil.RunIncrementalLoop
 
 // runIncrementalCycle runs one full incremental parsing cycle
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	start := time.Now()
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("Fonbet", cycleID, timeout)
@@ -162,13 +231,21 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 		parserutil.LogCycleFinish("Fonbet", cycleID, duration)
 	}()
 	
+	var total int
+	var errs []error
+	defer p.startCycleLimits().LogSummary()
+
+	// On shutdown drain the sport in progress is stored, no new sport is fetched
+	sportsCtx, stopSports := parserutil.DrainContext(cycleCtx)
+	defer stopSports()
+
 	// Process all configured sports incrementally
 	// Data is saved incrementally after each batch in BatchProcessor
 	for _, sportStr := range p.config.ValueCalculator.Sports {
 		select {
-		case <-cycleCtx.Done():
+		case <-sportsCtx.Done():
 			slog.Warn("Fonbet: incremental cycle interrupted", "sport", sportStr, "cycle_id", cycleID)
-			return
+			return total, errors.Join(errs...)
 		default:
 		}
 		
@@ -179,13 +256,18 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 		}
 		
 		slog.Info("Fonbet: processing sport incrementally", "sport", sportStr, "cycle_id", cycleID)
-		if err := p.eventProcessor.ProcessSportEvents(sportStr); err != nil {
+		if err := p.eventProcessor.ProcessSportEvents(cycleCtx, sportStr); err != nil {
 			slog.Error("Failed to parse events", "sport", sport, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", sportStr, err))
 			continue
 		}
+		if bp, ok := p.eventProcessor.(*BatchProcessor); ok {
+			total += bp.LastProcessedCount()
+		}
 		slog.Info("Fonbet: sport processed incrementally", "sport", sportStr, "cycle_id", cycleID)
 		
 		// Print performance summary after each sport
 		performance.GetTracker().PrintSummary()
 	}
+	return total, errors.Join(errs...)
 }
@@ -45,6 +45,11 @@ func (p *ParserWrapper) ParseOnce(ctx context.Context) error {
 	return p.parser.ParseOnce(ctx)
 }
 
+// ParseSport implements interfaces.SportParser
+func (p *ParserWrapper) ParseSport(ctx context.Context, sport string) error {
+	return p.parser.ParseSport(ctx, sport)
+}
+
 // StartIncremental implements interfaces.IncrementalParser
 func (p *ParserWrapper) StartIncremental(ctx context.Context, timeout time.Duration) error {
 	return p.parser.StartIncremental(ctx, timeout)
@@ -54,6 +59,10 @@ func (p *ParserWrapper) StartIncremental(ctx context.Context, timeout time.Durat
 func (p *ParserWrapper) TriggerNewCycle() error {
 	return p.parser.TriggerNewCycle()
 }
+func (p *ParserWrapper) LastCycle() interfaces.CycleSummary { return p.parser.LastCycle() }
+func (p *ParserWrapper) Pause() error                        { return p.parser.Pause() }
+func (p *ParserWrapper) Resume() error                       { return p.parser.Resume() }
+func (p *ParserWrapper) IsPaused() bool                      { return p.parser.IsPaused() }
 
 // Ensure ParserWrapper implements IncrementalParser interface
 var _ interfaces.IncrementalParser = (*ParserWrapper)(nil)
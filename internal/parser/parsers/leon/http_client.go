@@ -9,6 +9,11 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const defaultBaseURL = "https://leon.ru"
@@ -16,13 +21,22 @@ const defaultCtag = "ru-RU"
 const eventsFlags = "reg,urlv2,orn2,mm2,rrc,nodup"
 const eventFlags = "reg,urlv2,orn2,mm2,rrc,nodup,smgv2,outv2,wd3"
 
+// DefaultFingerprint is used when parser.leon.fingerprint is not set: the public betline API is
+// queried openly with the bot User-Agent.
+var DefaultFingerprint = config.FingerprintConfig{
+	UserAgents: []string{"ValueBetBot/1.0 (https://github.com/Vodeneev/vodeneevbet)"},
+}
+
 type Client struct {
 	baseURL    string
 	ctag      string
 	client    *http.Client
+	limiter   *ratelimit.Limiter
+	fp        *fingerprint.Profile
 }
 
-func NewClient(baseURL string, timeout time.Duration) *Client {
+// NewClient creates a Leon betline client. limiter may be nil (no rate limiting); fp may be nil (bot User-Agent).
+func NewClient(baseURL string, timeout time.Duration, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	if baseURL == "" {
 		baseURL = defaultBaseURL
 	}
@@ -30,10 +44,15 @@ func NewClient(baseURL string, timeout time.Duration) *Client {
 	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
+	if fp == nil {
+		fp = fingerprint.New("leon", DefaultFingerprint)
+	}
 	return &Client{
 		baseURL: baseURL,
 		ctag:   defaultCtag,
-		client: &http.Client{Timeout: timeout},
+		client: &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser(bookmakerName, nil)},
+		limiter: limiter,
+		fp:      fp,
 	}
 }
 
@@ -88,13 +107,16 @@ func (c *Client) GetEvent(ctx context.Context, eventID int64) (*LeonEvent, error
 }
 
 func (c *Client) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("new request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "ValueBetBot/1.0 (https://github.com/Vodeneev/vodeneevbet)")
 	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	c.fp.Apply(req)
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
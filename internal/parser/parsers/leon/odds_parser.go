@@ -51,8 +51,16 @@ var yellowCardsMainMarketTypeIDs = map[int64]bool{
 
 const yellowCardsWhoMoreMarketTypeID int64 = 1970324836978515 // Кто получит больше желтых карточек
 
+// «Обе забьют» и «Точный счёт» по матчу: marketTypeId неизвестны, ищем по названию маркета целиком
+// (так маркеты таймов и «Обе забьют + тотал» не попадают).
+var (
+	bttsMarketNames         = map[string]bool{"обе забьют": true, "обе команды забьют": true, "both teams to score": true}
+	correctScoreMarketNames = map[string]bool{"точный счет": true, "correct score": true}
+)
+
 // LeonEventToMatch конвертирует LeonEvent (полный ответ event/all или элемент из events) в models.Match.
-// Включает: main_match (1X2, тотал, фора), corners (тотал угловых, фора, кто больше), fouls (тотал фолов, фора, кто больше, количество по команде).
+// Включает: main_match (1X2, тотал, фора), corners (тотал угловых, фора, кто больше), fouls (тотал фолов, фора, кто больше, количество по команде),
+// both_teams_to_score и correct_score.
 // Названия команд всегда берутся из ev.NameDefault (англ.) при наличии — для матчинга с другими конторами.
 func LeonEventToMatch(ev *LeonEvent, leagueName string) *models.Match {
 	if ev == nil {
@@ -94,6 +102,12 @@ func LeonEventToMatch(ev *LeonEvent, leagueName string) *models.Match {
 	if yellowCardsEvent := buildStatisticalEvent(matchID, ev, now, models.StandardEventYellowCards, yellowCardsMainMarketTypeIDs); len(yellowCardsEvent.Outcomes) > 0 {
 		match.Events = append(match.Events, yellowCardsEvent)
 	}
+	if bttsEvent := buildBTTSEvent(matchID, ev, now); len(bttsEvent.Outcomes) > 0 {
+		match.Events = append(match.Events, bttsEvent)
+	}
+	if scoreEvent := buildCorrectScoreEvent(matchID, ev, now); len(scoreEvent.Outcomes) > 0 {
+		match.Events = append(match.Events, scoreEvent)
+	}
 	return match
 }
 
@@ -259,6 +273,74 @@ func leonHandicapOutcomeType(r LeonRunner) string {
 	return ""
 }
 
+// marketNameKey приводит название маркета к виду для сравнения: нижний регистр, ё → е.
+func marketNameKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "ё", "е")
+}
+
+func newMarketEvent(matchID string, eventType models.StandardEventType, now time.Time) models.Event {
+	return models.Event{
+		ID:         matchID + "_leon_" + string(eventType),
+		MatchID:    matchID,
+		EventType:  string(eventType),
+		MarketName: models.GetMarketName(eventType),
+		Bookmaker:  bookmakerName,
+		Outcomes:   []models.Outcome{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// buildBTTSEvent собирает «Обе забьют» (да/нет) по матчу.
+func buildBTTSEvent(matchID string, ev *LeonEvent, now time.Time) models.Event {
+	e := newMarketEvent(matchID, models.StandardEventBothTeamsToScore, now)
+	for _, m := range ev.Markets {
+		if !m.Open || !bttsMarketNames[marketNameKey(m.Name)] {
+			continue
+		}
+		for _, r := range m.Runners {
+			if !r.Open {
+				continue
+			}
+			var ot models.StandardOutcomeType
+			switch marketNameKey(r.Name) {
+			case "да", "yes":
+				ot = models.OutcomeTypeBTTSYes
+			case "нет", "no":
+				ot = models.OutcomeTypeBTTSNo
+			default:
+				continue
+			}
+			e.Outcomes = append(e.Outcomes, newOutcome(e.ID, string(ot), "", r.Price, now))
+		}
+		break
+	}
+	return e
+}
+
+// buildCorrectScoreEvent собирает «Точный счёт» по матчу; parameter — счёт "2:1" (models.ScoreParam).
+// Раннеры вида «Другой счёт» пропускаются.
+func buildCorrectScoreEvent(matchID string, ev *LeonEvent, now time.Time) models.Event {
+	e := newMarketEvent(matchID, models.StandardEventCorrectScore, now)
+	for _, m := range ev.Markets {
+		if !m.Open || !correctScoreMarketNames[marketNameKey(m.Name)] {
+			continue
+		}
+		for _, r := range m.Runners {
+			if !r.Open {
+				continue
+			}
+			home, away, ok := models.ParseScoreParam(r.Name)
+			if !ok {
+				continue
+			}
+			e.Outcomes = append(e.Outcomes, newOutcome(e.ID, string(models.OutcomeTypeCorrectScore), models.ScoreParam(home, away), r.Price, now))
+		}
+		break
+	}
+	return e
+}
+
 func newOutcome(eventID, outcomeType, param string, odds float64, now time.Time) models.Outcome {
 	id := fmt.Sprintf("%s_%s_%s", eventID, outcomeType, param)
 	return models.Outcome{
@@ -294,6 +376,26 @@ func CollectLeagueIDs(sports []SportItem, family string) []int64 {
 	return ids
 }
 
+// LeagueNames returns "Region. League" names of each league of family (localized and default),
+// matched by parser.leon.leagues.
+func LeagueNames(sports []SportItem, family string) map[int64][]string {
+	if family == "" {
+		family = "Soccer"
+	}
+	names := make(map[int64][]string)
+	for _, s := range sports {
+		if s.Family != family {
+			continue
+		}
+		for _, r := range s.Regions {
+			for _, l := range r.Leagues {
+				names[l.ID] = []string{r.Name + ". " + l.Name, r.NameDefault + ". " + l.NameDefault}
+			}
+		}
+	}
+	return names
+}
+
 // ParseFloat безопасно парсит строку в float64 (для handicap/total).
 func ParseFloat(s string) float64 {
 	s = strings.TrimSpace(s)
@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 var runOnceMu sync.Mutex
@@ -18,6 +21,7 @@ var runOnceMu sync.Mutex
 type Parser struct {
 	cfg      *config.Config
 	client   *Client
+	leagues  *parserutil.LeagueFilter // parser.leon.leagues
 	incState *parserutil.IncrementalParserState
 }
 
@@ -30,12 +34,18 @@ func NewParser(cfg *config.Config) *Parser {
 	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
-	client := NewClient(c.BaseURL, timeout)
-	return &Parser{cfg: cfg, client: client}
+	client := NewClient(c.BaseURL, timeout,
+		ratelimit.ForParser("leon", c.RateLimit, config.RateLimitConfig{}),
+		fingerprint.ForParser("leon", cfg.Parser.Fingerprint, c.Fingerprint, DefaultFingerprint))
+	return &Parser{cfg: cfg, client: client, leagues: parserutil.NewLeagueFilter("Leon", c.Leagues)}
 }
 
 // processSingleLeague fetches one league's events and details, adds matches to health store. Returns match count.
-func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64) int {
+// Events beyond the cycle's max_events_per_cycle budget are not fetched.
+func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64, limits *parserutil.CycleLimits) int {
+	if limits.Exhausted() {
+		return 0
+	}
 	eventsResp, err := p.client.GetLeagueEvents(ctx, leagueID)
 	if err != nil {
 		slog.Warn("Leon: GetLeagueEvents failed", "league_id", leagueID, "error", err)
@@ -45,6 +55,7 @@ func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64) int {
 	if len(eventsResp.Events) > 0 && eventsResp.Events[0].League.Name != "" {
 		leagueName = eventsResp.Events[0].League.Name
 	}
+	events := parserutil.LimitEvents(limits, leagueName, eventsResp.Events)
 
 	maxConcurrentEvents := p.cfg.Parser.Leon.MaxConcurrentEventsPerLeague
 	if maxConcurrentEvents < 1 {
@@ -54,7 +65,7 @@ func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64) int {
 
 	var count int
 	if maxConcurrentEvents == 1 {
-		for _, ev := range eventsResp.Events {
+		for _, ev := range events {
 			select {
 			case <-ctx.Done():
 				return count
@@ -83,7 +94,7 @@ func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64) int {
 	sem := make(chan struct{}, maxConcurrentEvents)
 	var wg sync.WaitGroup
 	var countMu sync.Mutex
-	for _, ev := range eventsResp.Events {
+	for _, ev := range events {
 		if ctx.Err() != nil {
 			break
 		}
@@ -117,7 +128,7 @@ func (p *Parser) processSingleLeague(ctx context.Context, leagueID int64) int {
 	return count
 }
 
-func (p *Parser) runOnce(ctx context.Context) error {
+func (p *Parser) runOnce(ctx context.Context) (int, error) {
 	runOnceMu.Lock()
 	defer runOnceMu.Unlock()
 	start := time.Now()
@@ -128,17 +139,17 @@ func (p *Parser) runOnce(ctx context.Context) error {
 
 	sports, err := p.client.GetSports(ctx)
 	if err != nil {
-		return fmt.Errorf("GetSports: %w", err)
+		return int(matchesTotal), fmt.Errorf("GetSports: %w", err)
 	}
 	family := p.cfg.Parser.Leon.SportFamily
 	if family == "" {
 		family = "Soccer"
 	}
-	leagueIDs := CollectLeagueIDs(sports, family)
-	maxLeagues := p.cfg.Parser.Leon.MaxLeagues
-	if maxLeagues > 0 && len(leagueIDs) > maxLeagues {
-		leagueIDs = leagueIDs[:maxLeagues]
-	}
+	limits := parserutil.NewCycleLimits("Leon", p.cfg.Parser.Leon.MaxEventsPerCycle, p.cfg.Parser.Leon.MaxLeagues)
+	defer limits.LogSummary()
+	names := LeagueNames(sports, family)
+	leagueIDs := parserutil.FilterLeagues(p.leagues, CollectLeagueIDs(sports, family), func(id int64) []string { return names[id] })
+	leagueIDs = parserutil.LimitLeagues(limits, leagueIDs)
 	totalLeagues := len(leagueIDs)
 	slog.Info("Leon: лиги к обработке", "count", totalLeagues)
 
@@ -147,15 +158,18 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		maxConcurrentLeagues = 1
 	}
 	delayLeague := p.cfg.Parser.Leon.DelayPerLeague
+	// On shutdown drain the leagues in progress finish, no new league starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 
 	if maxConcurrentLeagues == 1 {
 		for li, leagueID := range leagueIDs {
 			select {
-			case <-ctx.Done():
-				return nil
+			case <-leaguesCtx.Done():
+				return int(matchesTotal), nil
 			default:
 			}
-			n := p.processSingleLeague(ctx, leagueID)
+			n := p.processSingleLeague(ctx, leagueID, limits)
 			matchesTotal += int64(n)
 			if (li+1)%20 == 0 {
 				slog.Info("Leon: прогресс лиг", "processed", li+1, "total", totalLeagues, "matches", matchesTotal)
@@ -164,7 +178,7 @@ func (p *Parser) runOnce(ctx context.Context) error {
 				time.Sleep(delayLeague)
 			}
 		}
-		return nil
+		return int(matchesTotal), nil
 	}
 
 	// Parallel leagues: worker pool (like xbet1 MaxConcurrentChampionships)
@@ -180,10 +194,10 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		go func() {
 			defer wg.Done()
 			for leagueID := range ch {
-				if ctx.Err() != nil {
+				if leaguesCtx.Err() != nil {
 					return
 				}
-				n := p.processSingleLeague(ctx, leagueID)
+				n := p.processSingleLeague(ctx, leagueID, limits)
 				atomic.AddInt64(&matchesTotal, int64(n))
 				done := completed.Add(1)
 				if done%20 == 0 {
@@ -194,12 +208,12 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		}()
 	}
 	wg.Wait()
-	return nil
+	return int(matchesTotal), nil
 }
 
 func (p *Parser) Start(ctx context.Context) error {
 	slog.Info("Starting Leon parser (background mode)...")
-	if err := p.runOnce(ctx); err != nil {
+	if _, err := p.runOnce(ctx); err != nil {
 		return err
 	}
 	<-ctx.Done()
@@ -207,7 +221,8 @@ func (p *Parser) Start(ctx context.Context) error {
 }
 
 func (p *Parser) ParseOnce(ctx context.Context) error {
-	return p.runOnce(ctx)
+	_, err := p.runOnce(ctx)
+	return err
 }
 
 func (p *Parser) Stop() error {
@@ -242,12 +257,32 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("Leon")
 }
 
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("Leon")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("Leon")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("Leon", cycleID, timeout)
 	cycleCtx, cancel := parserutil.CreateCycleContext(ctx, timeout)
 	defer cancel()
 	start := time.Now()
 	defer func() { parserutil.LogCycleFinish("Leon", cycleID, time.Since(start)) }()
-	_ = p.runOnce(cycleCtx)
+	return p.runOnce(cycleCtx)
 }
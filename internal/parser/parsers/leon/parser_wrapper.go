@@ -35,5 +35,9 @@ func (p *ParserWrapper) StartIncremental(ctx context.Context, timeout time.Durat
 	return p.parser.StartIncremental(ctx, timeout)
 }
 func (p *ParserWrapper) TriggerNewCycle() error { return p.parser.TriggerNewCycle() }
+func (p *ParserWrapper) LastCycle() interfaces.CycleSummary { return p.parser.LastCycle() }
+func (p *ParserWrapper) Pause() error                        { return p.parser.Pause() }
+func (p *ParserWrapper) Resume() error                       { return p.parser.Resume() }
+func (p *ParserWrapper) IsPaused() bool                      { return p.parser.IsPaused() }
 
 var _ interfaces.IncrementalParser = (*ParserWrapper)(nil)
@@ -7,43 +7,40 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 
-// Global rate limiting (similar to Pinnacle888 oddsRateLimit)
-var (
-	marathonReqMu   sync.Mutex
-	marathonLastReq time.Time
-)
-
-// marathonMinDelay enforces minimum delay between requests to avoid 429 rate limiting.
-const marathonMinDelay = 500 * time.Millisecond
+// DefaultRateLimit is used when parser.marathonbet.rate_limit is not set (500ms between requests).
+var DefaultRateLimit = config.RateLimitConfig{RPS: 2, Burst: 1}
 
 // Client fetches Marathonbet HTML pages.
 type Client struct {
 	baseURL           string
-	userAgent         string
 	timeout           time.Duration
 	client            *http.Client
-	proxyList         []string
-	currentProxyIndex int
-	proxyMu           sync.Mutex
+	proxies           *proxypool.Pool
+	limiter           *ratelimit.Limiter
+	fp                *fingerprint.Profile
 }
 
-// NewClient creates a Marathonbet HTTP client.
-func NewClient(baseURL, userAgent string, timeout time.Duration, proxyList []string) *Client {
+// NewClient creates a Marathonbet HTTP client. limiter may be nil (no rate limiting); fp may be nil (defaultUserAgent).
+func NewClient(baseURL string, timeout time.Duration, proxyList []string, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	if baseURL == "" {
 		baseURL = "https://www.marathonbet.ru"
 	}
 	baseURL = strings.TrimSuffix(baseURL, "/")
-	if userAgent == "" {
-		userAgent = defaultUserAgent
+	if fp == nil {
+		fp = fingerprint.New(bookmakerName, config.FingerprintConfig{UserAgents: []string{defaultUserAgent}})
 	}
 	if timeout <= 0 {
 		timeout = 30 * time.Second
@@ -63,20 +60,20 @@ func NewClient(baseURL, userAgent string, timeout time.Duration, proxyList []str
 
 	return &Client{
 		baseURL:           baseURL,
-		userAgent:         userAgent,
 		timeout:           timeout,
-		client:            &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:         proxyList,
-		currentProxyIndex: 0,
+		client:            &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser(bookmakerName, transport)},
+		proxies:           proxypool.New(bookmakerName, proxyList),
+		limiter:           limiter,
+		fp:                fp,
 	}
 }
 
 // Get fetches a path (e.g. /su/all-events/11) and returns the response body.
-// Includes global rate limiting (500ms minimum delay) and handles 429 with forced backoff.
-// If proxyList is configured, tries proxies in order before falling back to direct connection.
+// Requests go through the parser's rate limiter; 429 responses trigger a forced backoff.
+// If proxyList is configured, tries proxies from the shared pool before falling back to direct connection.
 func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
-	// Try proxies in order if available, fallback to direct connection
-	if len(c.proxyList) > 0 {
+	// Try proxies from the pool if available, fallback to direct connection
+	if c.proxies.Len() > 0 {
 		return c.getWithProxyRetry(ctx, path)
 	}
 
@@ -85,20 +82,9 @@ func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
 
 // getDirect performs a direct HTTP request without proxy
 func (c *Client) getDirect(ctx context.Context, path string) ([]byte, error) {
-	// Rate limit: wait if last request was too recent
-	marathonReqMu.Lock()
-	sinceLastReq := time.Since(marathonLastReq)
-	if sinceLastReq < marathonMinDelay {
-		wait := marathonMinDelay - sinceLastReq
-		marathonReqMu.Unlock()
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(wait):
-		}
-		marathonReqMu.Lock()
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
 	}
-	marathonReqMu.Unlock()
 
 	requestURL := c.baseURL + path
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
@@ -118,63 +104,20 @@ func (c *Client) getDirect(ctx context.Context, path string) ([]byte, error) {
 		return nil, err
 	}
 
-	// Update last request time
-	marathonReqMu.Lock()
-	marathonLastReq = time.Now()
-	marathonReqMu.Unlock()
-
 	return c.handleResponse(resp, body, path)
 }
 
-// getWithProxyRetry tries each proxy in the list until one works
+// getWithProxyRetry tries proxies from the pool until one works
 func (c *Client) getWithProxyRetry(ctx context.Context, path string) ([]byte, error) {
 	requestURL := c.baseURL + path
 
-	// Rate limit: wait if last request was too recent
-	marathonReqMu.Lock()
-	sinceLastReq := time.Since(marathonLastReq)
-	if sinceLastReq < marathonMinDelay {
-		wait := marathonMinDelay - sinceLastReq
-		marathonReqMu.Unlock()
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(wait):
-		}
-		marathonReqMu.Lock()
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
 	}
-	marathonReqMu.Unlock()
-
-	// Try each proxy in the list
-	c.proxyMu.Lock()
-	startIndex := c.currentProxyIndex
-	c.proxyMu.Unlock()
-
-	for attempt := 0; attempt < len(c.proxyList); attempt++ {
-		c.proxyMu.Lock()
-		proxyIndex := (startIndex + attempt) % len(c.proxyList)
-		proxyURLStr := c.proxyList[proxyIndex]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
 
-		// Create transport with this proxy
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		}
-		if os.Getenv("MARATHONBET_INSECURE_TLS") == "1" {
-			transport.TLSClientConfig.InsecureSkipVerify = true
-		}
-		transport.Proxy = http.ProxyURL(proxyURL)
-
-		client := &http.Client{
-			Timeout:   c.timeout,
-			Transport: transport,
-		}
+	insecureTLS := os.Getenv("MARATHONBET_INSECURE_TLS") == "1"
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.timeout, proxypool.TransportOptions{InsecureTLS: insecureTLS, Parser: bookmakerName})
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 		if err != nil {
@@ -183,14 +126,20 @@ func (c *Client) getWithProxyRetry(ctx context.Context, path string) ([]byte, er
 
 		c.setHeaders(req)
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
@@ -200,21 +149,12 @@ func (c *Client) getWithProxyRetry(ctx context.Context, path string) ([]byte, er
 		isBlocked := strings.Contains(string(body), "TEMPLATE_NAME") && strings.Contains(string(body), "denied")
 
 		if resp.StatusCode == http.StatusOK && isHTML && !isBlocked {
-			// Success! Update current proxy index
-			c.proxyMu.Lock()
-			c.currentProxyIndex = proxyIndex
-			c.proxyMu.Unlock()
-			slog.Info("Marathonbet: Using working proxy", "proxy", maskProxyURL(proxyURLStr))
-
-			// Update last request time
-			marathonReqMu.Lock()
-			marathonLastReq = time.Now()
-			marathonReqMu.Unlock()
-
+			c.proxies.Success(px, "", time.Since(start))
 			return body, nil
 		}
 
 		// Not valid HTML or blocked - try next proxy
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d, html=%t, blocked=%t", resp.StatusCode, isHTML, isBlocked))
 	}
 
 	// All proxies failed, try direct connection as last resort
@@ -224,9 +164,9 @@ func (c *Client) getWithProxyRetry(ctx context.Context, path string) ([]byte, er
 
 // setHeaders sets HTTP headers for requests
 func (c *Client) setHeaders(req *http.Request) {
-	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	c.fp.Apply(req)
 }
 
 // handleResponse processes HTTP response and returns body or error
@@ -237,9 +177,7 @@ func (c *Client) handleResponse(resp *http.Response, body []byte, path string) (
 
 	// On 429, force 3s backoff before next request
 	if resp.StatusCode == http.StatusTooManyRequests {
-		marathonReqMu.Lock()
-		marathonLastReq = time.Now().Add(3 * time.Second) // force 3s pause before next request
-		marathonReqMu.Unlock()
+		c.limiter.Backoff(3 * time.Second) // force 3s pause before next request
 		slog.Warn("Marathonbet: rate limited (429), backing off 3s", "path", path)
 		return nil, fmt.Errorf("marathonbet: GET %s: status %d", path, resp.StatusCode)
 	}
@@ -256,18 +194,3 @@ func (c *Client) handleResponse(resp *http.Response, body []byte, path string) (
 
 	return nil, fmt.Errorf("marathonbet: GET %s: status %d", path, resp.StatusCode)
 }
-
-// maskProxyURL masks password in proxy URL for logging
-func maskProxyURL(proxyURL string) string {
-	parsed, err := url.Parse(proxyURL)
-	if err != nil {
-		return "***"
-	}
-	if parsed.User != nil {
-		if password, ok := parsed.User.Password(); ok {
-			masked := strings.Repeat("*", len(password))
-			parsed.User = url.UserPassword(parsed.User.Username(), masked)
-		}
-	}
-	return parsed.String()
-}
@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"html"
 	"log/slog"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const bookmakerName = "Marathonbet"
@@ -80,6 +86,16 @@ var mostCornersSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*
 // mostCornersHandicapSelectionKeyRegex matches Most_Corners_With_Handicap*.HB_H or HB_A (угловые с учётом форы)
 var mostCornersHandicapSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*Most_Corners_With_Handicap[^"]*\.(HB_H|HB_A)"`)
 
+// teamTotalSelectionKeyRegex matches team totals of corners and cards (Т1/Т2 угловых и карточек):
+// First_Team_Total_Corners.Under_4.5, Second_Team_Total_Yellow_Cards.Over_1.5
+var teamTotalSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*(First|Second)_Team_Total_(Corners|Yellow_Cards|Cards)[^"]*\.(Under|Over)_(\d+\.?\d*)"`)
+
+// bttsSelectionKeyRegex matches Both_Teams_To_Score.yes / .no (обе забьют); halves are skipped by the caller
+var bttsSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*Both_Teams_To_Score[^"]*\.(yes|no|Yes|No)"`)
+
+// correctScoreSelectionKeyRegex matches Correct_Score.2_1 / .2:1 (точный счёт); halves are skipped by the caller
+var correctScoreSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*Correct_Score[^"]*\.(\d+)[_:](\d+)"`)
+
 // resultSelectionKeyRegex matches Result (1X2) in data-selection-key: Result.S_0_1 / .S_0_2 / .S_0_3 or .home / .draw / .away
 var resultSelectionKeyRegex = regexp.MustCompile(`data-selection-key="[^"]*Result[^"]*\.(S_0_1|S_0_2|S_0_3|home|draw|away)"`)
 
@@ -361,6 +377,7 @@ func (s *selJSON) UnmarshalJSON(data []byte) error {
 type Parser struct {
 	cfg      *config.Config
 	client   *Client
+	leagues  *parserutil.LeagueFilter // parser.marathonbet.leagues
 	incState *parserutil.IncrementalParserState
 }
 
@@ -383,12 +400,17 @@ func NewParser(cfg *config.Config) *Parser {
 	if userAgent == "" {
 		userAgent = cfg.Parser.UserAgent
 	}
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
 	proxyList := mc.ProxyList
 	if len(proxyList) > 0 {
 		slog.Info("Marathonbet: Using proxy list from config", "proxy_count", len(proxyList))
 	}
-	client := NewClient(baseURL, userAgent, timeout, proxyList)
-	return &Parser{cfg: cfg, client: client}
+	limiter := ratelimit.ForParser("marathonbet", mc.RateLimit, DefaultRateLimit)
+	fp := fingerprint.ForParser("marathonbet", cfg.Parser.Fingerprint, mc.Fingerprint, config.FingerprintConfig{UserAgents: []string{userAgent}})
+	client := NewClient(baseURL, timeout, proxyList, limiter, fp)
+	return &Parser{cfg: cfg, client: client, leagues: parserutil.NewLeagueFilter("Marathonbet", mc.Leagues)}
 }
 
 // Start runs one ParseOnce then blocks until context is done.
@@ -425,13 +447,14 @@ func (p *Parser) StartIncremental(ctx context.Context, timeout time.Duration) er
 }
 
 // runIncrementalCycle runs one full parse cycle.
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	cycleCtx, cancel := parserutil.CreateCycleContext(ctx, timeout)
 	defer cancel()
-	err := p.ParseOnce(cycleCtx)
+	n, err := p.parseOnce(cycleCtx)
 	if err != nil {
 		slog.Error("Marathonbet: parse cycle failed", "error", err)
 	}
+	return n, err
 }
 
 // TriggerNewCycle signals start of a new parsing cycle.
@@ -442,8 +465,34 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle(bookmakerName)
 }
 
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause(bookmakerName)
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume(bookmakerName)
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
 // ParseOnce runs one full parse: all-events → leagues → event pages → AddMatch.
 func (p *Parser) ParseOnce(ctx context.Context) error {
+	_, err := p.parseOnce(ctx)
+	return err
+}
+
+// parseOnce does the work of ParseOnce and returns the number of matches stored.
+func (p *Parser) parseOnce(ctx context.Context) (int, error) {
 	start := time.Now()
 	var totalMatches int
 	defer func() {
@@ -457,54 +506,123 @@ func (p *Parser) ParseOnce(ctx context.Context) error {
 	path := fmt.Sprintf("/su/all-events/%d", sportID)
 	body, err := p.client.Get(ctx, path)
 	if err != nil {
-		return fmt.Errorf("marathonbet all-events: %w", err)
+		return totalMatches, fmt.Errorf("marathonbet all-events: %w", err)
 	}
 	leaguePaths := extractLeaguePaths(body)
 	slog.Info("Marathonbet: found leagues", "count", len(leaguePaths), "sport_id", sportID)
-
-	// Rate limiting is handled globally in http_client.go (500ms minimum delay between all requests)
-	// No need for additional delays here - the global mutex ensures proper spacing
+	limits := parserutil.NewCycleLimits("Marathonbet", p.cfg.Parser.Marathonbet.MaxEventsPerCycle, p.cfg.Parser.Marathonbet.MaxLeagues)
+	defer limits.LogSummary()
+	leaguePaths = parserutil.FilterLeagues(p.leagues, leaguePaths, func(path string) []string {
+		return []string{leagueNameFromPath(path)}
+	})
+	leaguePaths = parserutil.LimitLeagues(limits, leaguePaths)
+
+	// Leagues and event pages are fetched by a bounded worker pool (max_concurrent_leagues ×
+	// max_concurrent_events_per_league); request spacing is still enforced by the shared limiter
+	// in http_client.go (parser.marathonbet.rate_limit), so concurrency only overlaps slow responses.
+	maxConcurrentLeagues := p.cfg.Parser.Marathonbet.MaxConcurrentLeagues
+	if maxConcurrentLeagues < 1 {
+		maxConcurrentLeagues = 1
+	}
+	ch := make(chan string, len(leaguePaths))
 	for _, leaguePath := range leaguePaths {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		events, err := p.fetchLeagueEvents(ctx, leaguePath)
-		if err != nil {
-			slog.Warn("Marathonbet: league failed", "path", leaguePath, "error", err)
-			continue
-		}
-		slog.Info("Marathonbet: found events in league", "league", leaguePath, "count", len(events))
-		for _, eventPath := range events {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-			match, err := p.fetchEventMatch(ctx, eventPath)
-			if err != nil {
-				slog.Warn("Marathonbet: event failed", "path", eventPath, "error", err)
-				continue
-			}
-			if match != nil {
-				// Strictly exclude live matches (matches that have already started)
-				if !match.StartTime.IsZero() {
-					matchStartTime := match.StartTime.UTC()
-					now := time.Now().UTC()
-					if !matchStartTime.After(now) {
-						// Match has already started, skip it
-						slog.Debug("Marathonbet: filtered live match", "match_id", match.ID, "start", matchStartTime.Format(time.RFC3339), "now", now.Format(time.RFC3339))
-						continue
-					}
+		ch <- leaguePath
+	}
+	close(ch)
+	// On shutdown drain the leagues in progress finish, no new league starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
+	var added atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < min(maxConcurrentLeagues, len(leaguePaths)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for leaguePath := range ch {
+				if leaguesCtx.Err() != nil || limits.Exhausted() {
+					return
 				}
-				health.AddMatch(match)
-				totalMatches++
-				slog.Info("Marathonbet: match added", "match", match.Name, "home", match.HomeTeam, "away", match.AwayTeam, "events", len(match.Events))
+				added.Add(int64(p.processLeague(ctx, leaguePath, limits)))
+			}
+		}()
+	}
+	wg.Wait()
+	totalMatches = int(added.Load())
+	return totalMatches, ctx.Err()
+}
+
+// processLeague fetches one league's event pages (up to max_concurrent_events_per_league at once)
+// and adds pre-match matches to the health store. Returns the number of matches added.
+func (p *Parser) processLeague(ctx context.Context, leaguePath string, limits *parserutil.CycleLimits) int {
+	events, err := p.fetchLeagueEvents(ctx, leaguePath)
+	if err != nil {
+		slog.Warn("Marathonbet: league failed", "path", leaguePath, "error", err)
+		return 0
+	}
+	slog.Info("Marathonbet: found events in league", "league", leaguePath, "count", len(events))
+	events = parserutil.LimitEvents(limits, leaguePath, events)
+
+	maxConcurrentEvents := p.cfg.Parser.Marathonbet.MaxConcurrentEventsPerLeague
+	if maxConcurrentEvents < 1 {
+		maxConcurrentEvents = 1
+	}
+	sem := make(chan struct{}, maxConcurrentEvents)
+	var count atomic.Int64
+	var wg sync.WaitGroup
+	for _, eventPath := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		eventPath := eventPath
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if p.processEvent(ctx, eventPath) {
+				count.Add(1)
 			}
+		}()
+	}
+	wg.Wait()
+	return int(count.Load())
+}
+
+// processEvent fetches one event page and adds the match unless it has already started.
+func (p *Parser) processEvent(ctx context.Context, eventPath string) bool {
+	match, err := p.fetchEventMatch(ctx, eventPath)
+	if err != nil {
+		slog.Warn("Marathonbet: event failed", "path", eventPath, "error", err)
+		return false
+	}
+	if match == nil {
+		return false
+	}
+	// Strictly exclude live matches (matches that have already started)
+	if !match.StartTime.IsZero() {
+		matchStartTime := match.StartTime.UTC()
+		now := time.Now().UTC()
+		if !matchStartTime.After(now) {
+			// Match has already started, skip it
+			slog.Debug("Marathonbet: filtered live match", "match_id", match.ID, "start", matchStartTime.Format(time.RFC3339), "now", now.Format(time.RFC3339))
+			return false
 		}
 	}
-	return nil
+	health.AddMatch(match)
+	slog.Info("Marathonbet: match added", "match", match.Name, "home", match.HomeTeam, "away", match.AwayTeam, "events", len(match.Events))
+	return true
+}
+
+var leagueIDSuffixRegex = regexp.MustCompile(`\s+-\s+\d+$`)
+
+// leagueNameFromPath turns a league link into the name matched by parser.marathonbet.leagues:
+// "/su/betting/Football/England/Premier+League+-+21520" → "England/Premier League".
+func leagueNameFromPath(path string) string {
+	name := strings.TrimPrefix(path, "/su/betting/Football/")
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		name = unescaped
+	}
+	return leagueIDSuffixRegex.ReplaceAllString(name, "")
 }
 
 func extractLeaguePaths(htmlBody []byte) []string {
@@ -547,7 +665,13 @@ func (p *Parser) fetchEventMatch(ctx context.Context, eventPath string) (*models
 	if err != nil {
 		return nil, err
 	}
-	return parseEventPage(body, eventPath)
+	match, err := parseEventPage(body, eventPath)
+	if err != nil {
+		return nil, err
+	}
+	// Event page at the site, opened from the alerts
+	match.URL = p.client.baseURL + eventPath
+	return match, nil
 }
 
 // parseDateTimeFromHTML extracts date and time from HTML page
@@ -607,11 +731,8 @@ func parseDateTimeFromHTML(htmlBody string) time.Time {
 	dateStr := fmt.Sprintf("%d-%s-%02d %s:00", year, month, dayInt, timeStr)
 	
 	// Parse with Moscow timezone (UTC+3)
-	loc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		loc = time.FixedZone("MSK", 3*60*60) // UTC+3
-	}
-	
+	loc := moscowLocation()
+
 	if t, err := time.ParseInLocation("2006-01-02 15:04:05", dateStr, loc); err == nil {
 		// If parsed date is in the past, try next year
 		if t.Before(now.Add(-24 * time.Hour)) {
@@ -627,6 +748,15 @@ func parseDateTimeFromHTML(htmlBody string) time.Time {
 	return time.Time{}
 }
 
+// moscowLocation is the time zone of the times Marathonbet shows without an offset.
+func moscowLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		loc = time.FixedZone("MSK", 3*60*60) // UTC+3
+	}
+	return loc
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -960,6 +1090,9 @@ func parseCornersTotalsFromSelectionKey(htmlBody string) map[string]struct{ Unde
 	}
 	byParam := make(map[string][]pair)
 	for _, sub := range totalCornersSelectionKeyRegex.FindAllStringSubmatchIndex(htmlBody, -1) {
+		if strings.Contains(htmlBody[sub[0]:sub[1]], "_Team_Total_") {
+			continue // Т1/Т2: parseTeamTotalsFromSelectionKey
+		}
 		outcome := htmlBody[sub[2]:sub[3]]
 		param := htmlBody[sub[4]:sub[5]]
 		keyPos := sub[0]
@@ -1028,6 +1161,143 @@ func parseCornersTotalsFromSelectionKey(htmlBody string) map[string]struct{ Unde
 	return out
 }
 
+// parseBTTSFromSelectionKey returns the full-time "Both teams to score" odds (0 when not quoted).
+func parseBTTSFromSelectionKey(htmlBody string) (yes, no float64) {
+	for _, sub := range bttsSelectionKeyRegex.FindAllStringSubmatchIndex(htmlBody, -1) {
+		if fullKey := htmlBody[sub[0]:sub[1]]; strings.Contains(fullKey, "1st_Half") || strings.Contains(fullKey, "2nd_Half") {
+			continue
+		}
+		odds := selectionOdds(htmlBody, sub[0])
+		if odds <= 0 {
+			continue
+		}
+		if strings.EqualFold(htmlBody[sub[2]:sub[3]], "yes") {
+			if yes == 0 {
+				yes = odds
+			}
+		} else if no == 0 {
+			no = odds
+		}
+	}
+	return yes, no
+}
+
+// parseCorrectScoreFromSelectionKey returns the full-time correct score odds by score ("2:1", models.ScoreParam);
+// first occurrence per score.
+func parseCorrectScoreFromSelectionKey(htmlBody string) map[string]float64 {
+	out := make(map[string]float64)
+	for _, sub := range correctScoreSelectionKeyRegex.FindAllStringSubmatchIndex(htmlBody, -1) {
+		if fullKey := htmlBody[sub[0]:sub[1]]; strings.Contains(fullKey, "1st_Half") || strings.Contains(fullKey, "2nd_Half") {
+			continue
+		}
+		home, away, ok := models.ParseScoreParam(htmlBody[sub[2]:sub[3]] + ":" + htmlBody[sub[4]:sub[5]])
+		if !ok {
+			continue
+		}
+		score := models.ScoreParam(home, away)
+		if _, seen := out[score]; seen {
+			continue
+		}
+		if odds := selectionOdds(htmlBody, sub[0]); odds > 0 {
+			out[score] = odds
+		}
+	}
+	return out
+}
+
+// teamTotal is one line of a team total of corners or cards (Т1/Т2).
+type teamTotal struct {
+	EventType   models.StandardEventType
+	Participant string // models.ParticipantHome or models.ParticipantAway
+	Param       string
+	Under, Over float64
+}
+
+// parseTeamTotalsFromSelectionKey returns the full-time team totals of corners and yellow cards
+// (First_Team_Total_* / Second_Team_Total_*); first occurrence per team, market, line and side.
+func parseTeamTotalsFromSelectionKey(htmlBody string) []teamTotal {
+	byLine := make(map[teamTotal]*teamTotal) // line without odds -> odds
+	for _, sub := range teamTotalSelectionKeyRegex.FindAllStringSubmatchIndex(htmlBody, -1) {
+		fullKey := htmlBody[sub[0]:sub[1]]
+		if strings.Contains(fullKey, "1st_Half") || strings.Contains(fullKey, "2nd_Half") {
+			continue
+		}
+		odds := selectionOdds(htmlBody, sub[0])
+		if odds <= 0 {
+			continue
+		}
+		line := teamTotal{EventType: models.StandardEventCorners, Participant: models.ParticipantHome, Param: htmlBody[sub[8]:sub[9]]}
+		if htmlBody[sub[2]:sub[3]] == "Second" {
+			line.Participant = models.ParticipantAway
+		}
+		if htmlBody[sub[4]:sub[5]] != "Corners" {
+			line.EventType = models.StandardEventYellowCards
+		}
+		t := byLine[line]
+		if t == nil {
+			t = &teamTotal{EventType: line.EventType, Participant: line.Participant, Param: line.Param}
+			byLine[line] = t
+		}
+		if htmlBody[sub[6]:sub[7]] == "Under" {
+			if t.Under == 0 {
+				t.Under = odds
+			}
+		} else if t.Over == 0 {
+			t.Over = odds
+		}
+	}
+	var totals []teamTotal
+	for _, t := range byLine {
+		if t.Under > 0 && t.Over > 0 {
+			totals = append(totals, *t)
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		a, b := totals[i], totals[j]
+		if a.EventType != b.EventType {
+			return a.EventType < b.EventType
+		}
+		if a.Participant != b.Participant {
+			return a.Participant > b.Participant // home first
+		}
+		return a.Param < b.Param
+	})
+	return totals
+}
+
+// selectionOdds returns the odds of the data-sel cell of the data-selection-key at keyPos, 0 if none.
+func selectionOdds(htmlBody string, keyPos int) float64 {
+	cellStart := 0
+	if tdMatches := openTdRegex.FindAllStringIndex(htmlBody[:keyPos], -1); len(tdMatches) > 0 {
+		cellStart = tdMatches[len(tdMatches)-1][0]
+	}
+	cellEnd := min(len(htmlBody), keyPos+50)
+	searchArea := htmlBody[cellStart:cellEnd]
+	relKeyPos := keyPos - cellStart
+	selMatches := dataSelRegex.FindAllStringSubmatchIndex(searchArea, -1)
+	if len(selMatches) == 0 {
+		return 0
+	}
+	selMatch := selMatches[0]
+	for i := len(selMatches) - 1; i >= 0; i-- {
+		if selMatches[i][1] <= relKeyPos {
+			selMatch = selMatches[i]
+			break
+		}
+	}
+	raw := ""
+	if selMatch[2] != -1 {
+		raw = searchArea[selMatch[2]:selMatch[3]]
+	} else if selMatch[4] != -1 {
+		raw = searchArea[selMatch[4]:selMatch[5]]
+	}
+	var sel selJSON
+	if raw == "" || json.Unmarshal([]byte(html.UnescapeString(raw)), &sel) != nil {
+		return 0
+	}
+	return sel.Epr
+}
+
 // parseMostCorners1X2FromSelectionKey returns home/draw/away odds for "Кто подаст больше угловых" (Most_Corners.home/.draw/.away).
 func parseMostCorners1X2FromSelectionKey(htmlBody string) (home, draw, away float64) {
 	byOutcome := make(map[string]float64)
@@ -1453,7 +1723,8 @@ func parseEventPage(htmlBody []byte, eventPath string) (*models.Match, error) {
 		// Marathonbet may use milliseconds or ISO
 		if t, err := time.Parse(time.RFC3339, ej.StartTime); err == nil {
 			startTime = t.UTC()
-		} else if t, err := time.Parse("02.01.2006 15:04", ej.StartTime); err == nil {
+		} else if t, err := time.ParseInLocation("02.01.2006 15:04", ej.StartTime, moscowLocation()); err == nil {
+			// Local times are Moscow time like the site's HTML (see parseDateTimeFromHTML)
 			startTime = t.UTC()
 		}
 	}
@@ -1834,6 +2105,65 @@ func parseEventPage(htmlBody []byte, eventPath string) (*models.Match, error) {
 		})
 	}
 	
+	// Team totals of corners and cards (Т1/Т2): the same lines as the match totals, keyed by the team
+	for _, t := range parseTeamTotalsFromSelectionKey(bodyStr) {
+		eventID := matchID + "_" + bookmakerKey + "_" + string(t.EventType) + "_" + t.Participant + "_total_" + strings.ReplaceAll(t.Param, ".", "_")
+		team := "Home"
+		if t.Participant == models.ParticipantAway {
+			team = "Away"
+		}
+		match.Events = append(match.Events, models.Event{
+			ID:         eventID,
+			MatchID:    matchID,
+			EventType:  string(t.EventType),
+			MarketName: models.GetMarketName(t.EventType) + " " + team + " Total " + t.Param,
+			Bookmaker:  bookmakerName,
+			Outcomes: []models.Outcome{
+				{ID: eventID + "_under", EventID: eventID, OutcomeType: string(models.OutcomeTypeTotalUnder), Parameter: t.Param, Participant: t.Participant, Odds: t.Under, Bookmaker: bookmakerName, CreatedAt: now, UpdatedAt: now},
+				{ID: eventID + "_over", EventID: eventID, OutcomeType: string(models.OutcomeTypeTotalOver), Parameter: t.Param, Participant: t.Participant, Odds: t.Over, Bookmaker: bookmakerName, CreatedAt: now, UpdatedAt: now},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	// Both teams to score (Обе забьют)
+	if yes, no := parseBTTSFromSelectionKey(bodyStr); yes > 0 && no > 0 {
+		eventID := matchID + "_" + bookmakerKey + "_" + string(models.StandardEventBothTeamsToScore)
+		match.Events = append(match.Events, models.Event{
+			ID:         eventID,
+			MatchID:    matchID,
+			EventType:  string(models.StandardEventBothTeamsToScore),
+			MarketName: models.GetMarketName(models.StandardEventBothTeamsToScore),
+			Bookmaker:  bookmakerName,
+			Outcomes: []models.Outcome{
+				{ID: eventID + "_yes", EventID: eventID, OutcomeType: string(models.OutcomeTypeBTTSYes), Odds: yes, Bookmaker: bookmakerName, CreatedAt: now, UpdatedAt: now},
+				{ID: eventID + "_no", EventID: eventID, OutcomeType: string(models.OutcomeTypeBTTSNo), Odds: no, Bookmaker: bookmakerName, CreatedAt: now, UpdatedAt: now},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	// Correct score (Точный счёт)
+	if scores := parseCorrectScoreFromSelectionKey(bodyStr); len(scores) > 0 {
+		eventID := matchID + "_" + bookmakerKey + "_" + string(models.StandardEventCorrectScore)
+		event := models.Event{
+			ID:         eventID,
+			MatchID:    matchID,
+			EventType:  string(models.StandardEventCorrectScore),
+			MarketName: models.GetMarketName(models.StandardEventCorrectScore),
+			Bookmaker:  bookmakerName,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		for score, odds := range scores {
+			event.Outcomes = append(event.Outcomes, models.Outcome{ID: eventID + "_" + strings.ReplaceAll(score, ":", "_"), EventID: eventID, OutcomeType: string(models.OutcomeTypeCorrectScore), Parameter: score, Odds: odds, Bookmaker: bookmakerName, CreatedAt: now, UpdatedAt: now})
+		}
+		sort.Slice(event.Outcomes, func(i, j int) bool { return event.Outcomes[i].Parameter < event.Outcomes[j].Parameter })
+		match.Events = append(match.Events, event)
+	}
+
 	// Parse remaining markets using old method as fallback (for markets without preference-id)
 	// Find all remaining data-sel that weren't processed
 	var remainingOdds []oddWithContext
@@ -14,8 +14,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const defaultBaseURL = "https://www.olimp.bet/api/v4/0/line"
@@ -26,12 +31,13 @@ type Client struct {
 	sportID           int
 	referer           string
 	client            *http.Client
-	proxyList         []string
-	currentProxyIndex int
-	proxyMu           sync.Mutex
+	proxies           *proxypool.Pool
+	limiter           *ratelimit.Limiter
+	fp                *fingerprint.Profile
 }
 
-func NewClient(baseURL string, sportID int, timeout time.Duration, referer string, proxyList []string) *Client {
+// NewClient creates an Olimp line client. limiter may be nil (no rate limiting); fp may be nil (built-in User-Agent pool).
+func NewClient(baseURL string, sportID int, timeout time.Duration, referer string, proxyList []string, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	if baseURL == "" {
 		baseURL = defaultBaseURL
 	}
@@ -45,6 +51,9 @@ func NewClient(baseURL string, sportID int, timeout time.Duration, referer strin
 	if referer == "" {
 		referer = defaultReferer
 	}
+	if fp == nil {
+		fp = fingerprint.New("olimp", config.FingerprintConfig{})
+	}
 
 	insecureTLS := os.Getenv("OLIMP_INSECURE_TLS") == "1"
 
@@ -67,9 +76,10 @@ func NewClient(baseURL string, sportID int, timeout time.Duration, referer strin
 		baseURL:           baseURL,
 		sportID:           sportID,
 		referer:           referer,
-		client:            &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:         proxyList,
-		currentProxyIndex: 0,
+		client:            &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser(bookmakerName, transport)},
+		proxies:           proxypool.New("Olimp", proxyList),
+		limiter:           limiter,
+		fp:                fp,
 	}
 }
 
@@ -119,9 +129,13 @@ func (c *Client) GetEventLine(ctx context.Context, eventID string) (*OlimpEvent,
 }
 
 func (c *Client) do(ctx context.Context, rawURL, referer string) ([]byte, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Try proxies in order if available, fallback to direct connection
-	if len(c.proxyList) > 0 {
-		slog.Debug("Olimp: Using proxy list", "proxy_count", len(c.proxyList), "url", rawURL)
+	if c.proxies.Len() > 0 {
+		slog.Debug("Olimp: Using proxy list", "proxy_count", c.proxies.Len(), "url", rawURL)
 		return c.doWithProxyRetry(ctx, rawURL, referer)
 	}
 
@@ -144,36 +158,9 @@ func (c *Client) doDirect(ctx context.Context, rawURL, referer string) ([]byte,
 }
 
 func (c *Client) doWithProxyRetry(ctx context.Context, rawURL, referer string) ([]byte, error) {
-	// Try each proxy in the list
-	c.proxyMu.Lock()
-	startIndex := c.currentProxyIndex
-	c.proxyMu.Unlock()
-
-	for attempt := 0; attempt < len(c.proxyList); attempt++ {
-		c.proxyMu.Lock()
-		proxyIndex := (startIndex + attempt) % len(c.proxyList)
-		proxyURLStr := c.proxyList[proxyIndex]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
-
-		// Create transport with this proxy
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		}
-		if os.Getenv("OLIMP_INSECURE_TLS") == "1" {
-			transport.TLSClientConfig.InsecureSkipVerify = true
-		}
-		transport.Proxy = http.ProxyURL(proxyURL)
-
-		client := &http.Client{
-			Timeout:   c.client.Timeout,
-			Transport: transport,
-		}
+	insecureTLS := os.Getenv("OLIMP_INSECURE_TLS") == "1"
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.client.Timeout, proxypool.TransportOptions{InsecureTLS: insecureTLS, Parser: bookmakerName})
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 		if err != nil {
@@ -182,12 +169,18 @@ func (c *Client) doWithProxyRetry(ctx context.Context, rawURL, referer string) (
 
 		c.setHeaders(req, referer)
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
 		// Check if response is valid JSON (not HTML blocking page)
+		// Peek at body to check if it's JSON or HTML
 		contentType := resp.Header.Get("Content-Type")
 		bodyPeek := make([]byte, 100)
 		n, _ := resp.Body.Read(bodyPeek)
@@ -198,16 +191,8 @@ func (c *Client) doWithProxyRetry(ctx context.Context, rawURL, referer string) (
 
 		if resp.StatusCode == http.StatusOK && (strings.Contains(contentType, "application/json") || isJSON) && !isHTML {
 			// Success! Create a new reader that includes the peeked bytes
-			bodyReader := io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body)
-
-			// Create a new response with the combined body
-			resp.Body = io.NopCloser(bodyReader)
-
-			// Update current proxy index
-			c.proxyMu.Lock()
-			c.currentProxyIndex = proxyIndex
-			c.proxyMu.Unlock()
-			slog.Info("Olimp: Using working proxy", "proxy_index", proxyIndex+1, "proxy", maskProxyURL(proxyURLStr), "url", rawURL)
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body))
+			c.proxies.Success(px, "", time.Since(start))
 
 			body, err := c.handleResponse(resp)
 			resp.Body.Close()
@@ -217,10 +202,11 @@ func (c *Client) doWithProxyRetry(ctx context.Context, rawURL, referer string) (
 		// Not JSON - read and close body
 		io.ReadAll(resp.Body)
 		resp.Body.Close()
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d, content-type %q", resp.StatusCode, contentType))
 	}
 
 	// All proxies failed, try direct connection as last resort
-	slog.Warn("Olimp: All proxies failed, trying direct connection", "url", rawURL, "total_proxies_tried", len(c.proxyList))
+	slog.Warn("Olimp: All proxies failed, trying direct connection", "url", rawURL, "total_proxies_tried", c.proxies.Len())
 	return c.doDirect(ctx, rawURL, referer)
 }
 
@@ -228,8 +214,8 @@ func (c *Client) setHeaders(req *http.Request, referer string) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 	req.Header.Set("Referer", referer)
+	c.fp.Apply(req)
 }
 
 func (c *Client) handleResponse(resp *http.Response) ([]byte, error) {
@@ -249,17 +235,3 @@ func (c *Client) handleResponse(resp *http.Response) ([]byte, error) {
 	return io.ReadAll(r)
 }
 
-func maskProxyURL(proxyURL string) string {
-	// Mask password in proxy URL for logging
-	parsed, err := url.Parse(proxyURL)
-	if err != nil {
-		return "***"
-	}
-	if parsed.User != nil {
-		password, _ := parsed.User.Password()
-		if password != "" {
-			parsed.User = url.UserPassword(parsed.User.Username(), "***")
-		}
-	}
-	return parsed.String()
-}
@@ -2,6 +2,7 @@ package olimp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -9,8 +10,11 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const delayPerLeague = 400 * time.Millisecond
@@ -21,6 +25,7 @@ var runOnceMu sync.Mutex
 type Parser struct {
 	cfg      *config.Config
 	client   *Client
+	leagues  *parserutil.LeagueFilter // parser.olimp.leagues
 	incState *parserutil.IncrementalParserState
 }
 
@@ -33,43 +38,59 @@ func NewParser(cfg *config.Config) *Parser {
 	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
-	client := NewClient(o.BaseURL, o.SportID, timeout, o.Referer, o.ProxyList)
-	return &Parser{cfg: cfg, client: client}
+	client := NewClient(o.BaseURL, o.SportID, timeout, o.Referer, o.ProxyList,
+		ratelimit.ForParser("olimp", o.RateLimit, config.RateLimitConfig{}),
+		fingerprint.ForParser("olimp", cfg.Parser.Fingerprint, o.Fingerprint, config.FingerprintConfig{}))
+	return &Parser{cfg: cfg, client: client, leagues: parserutil.NewLeagueFilter("olimp", o.Leagues)}
 }
 
-func (p *Parser) runOnce(ctx context.Context) error {
+// runOnce parses all leagues; failed leagues are returned as errors.Join of parserutil.LeagueFailed
+// while the other leagues are still parsed.
+func (p *Parser) runOnce(ctx context.Context) (int, error) {
 	if p.cfg.Parser.Olimp.Referer == "" {
 		slog.Warn("olimp: referer not set, skipping (set parser.olimp.referer)")
-		return nil
+		return 0, nil
 	}
 	runOnceMu.Lock()
 	defer runOnceMu.Unlock()
 	start := time.Now()
 	var totalMatches int
+	var leagueErrs []error
 	defer func() {
 		slog.Info("olimp: cycle finished", "matches", totalMatches, "duration", time.Since(start))
 	}()
 
 	sports, err := p.client.GetSportsWithCompetitions(ctx)
 	if err != nil {
-		return fmt.Errorf("sports-with-competitions: %w", err)
+		return totalMatches, fmt.Errorf("sports-with-competitions: %w", err)
 	}
-	competitionIDs := extractCompetitionIDs(sports, p.cfg.Parser.Olimp.SportID)
+	competitionIDs, names := extractCompetitionIDs(sports, p.cfg.Parser.Olimp.SportID)
+	competitionIDs = parserutil.FilterLeagues(p.leagues, competitionIDs, func(id string) []string { return names[id] })
 	if len(competitionIDs) == 0 {
 		slog.Info("olimp: no football competitions")
-		return nil
+		return totalMatches, nil
 	}
+	limits := parserutil.NewCycleLimits("olimp", p.cfg.Parser.Olimp.MaxEventsPerCycle, p.cfg.Parser.Olimp.MaxLeagues)
+	defer limits.LogSummary()
+	competitionIDs = parserutil.LimitLeagues(limits, competitionIDs)
 	slog.Info("olimp: leagues to process", "count", len(competitionIDs))
+	// On shutdown drain the league in progress finishes, no new league starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 
 	for _, compID := range competitionIDs {
 		select {
-		case <-ctx.Done():
-			return nil
+		case <-leaguesCtx.Done():
+			return totalMatches, errors.Join(leagueErrs...)
 		default:
 		}
+		if limits.Exhausted() {
+			break
+		}
 		resp, err := p.client.GetCompetitionsWithEvents(ctx, compID)
 		if err != nil {
 			slog.Warn("olimp: competitions-with-events failed", "competition_id", compID, "error", err)
+			leagueErrs = append(leagueErrs, parserutil.LeagueFailed(compID, err))
 			time.Sleep(delayPerLeague)
 			continue
 		}
@@ -81,10 +102,10 @@ func (p *Parser) runOnce(ctx context.Context) error {
 			if resp[i].Payload == nil {
 				continue
 			}
-			for _, ev := range resp[i].Payload.Events {
+			for _, ev := range parserutil.LimitEvents(limits, leagueName, resp[i].Payload.Events) {
 				select {
 				case <-ctx.Done():
-					return nil
+					return totalMatches, errors.Join(leagueErrs...)
 				default:
 				}
 				// Step 3: full line per match (corners, fouls, yellow cards, offsides, etc.)
@@ -104,12 +125,15 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		}
 		time.Sleep(delayPerLeague)
 	}
-	return nil
+	return totalMatches, errors.Join(leagueErrs...)
 }
 
-func extractCompetitionIDs(sports SportsWithCompetitionsResponse, sportID int) []string {
+// extractCompetitionIDs returns the competition IDs of sportID and their names (all languages),
+// matched by parser.olimp.leagues.
+func extractCompetitionIDs(sports SportsWithCompetitionsResponse, sportID int) ([]string, map[string][]string) {
 	seen := make(map[string]bool)
 	var ids []string
+	names := make(map[string][]string)
 	for _, item := range sports {
 		if item.Payload == nil {
 			continue
@@ -125,11 +149,15 @@ func extractCompetitionIDs(sports SportsWithCompetitionsResponse, sportID int) [
 				if c.ID != "" && !seen[c.ID] {
 					seen[c.ID] = true
 					ids = append(ids, c.ID)
+					names[c.ID] = append(names[c.ID], c.Name)
+					for _, name := range c.Names {
+						names[c.ID] = append(names[c.ID], name)
+					}
 				}
 			}
 		}
 	}
-	return ids
+	return ids, names
 }
 
 func parseInt(s string) (int, bool) {
@@ -139,7 +167,7 @@ func parseInt(s string) (int, bool) {
 
 func (p *Parser) Start(ctx context.Context) error {
 	slog.Info("Starting Olimp parser (background mode)...")
-	if err := p.runOnce(ctx); err != nil {
+	if err := p.ParseOnce(ctx); err != nil {
 		return err
 	}
 	<-ctx.Done()
@@ -147,7 +175,20 @@ func (p *Parser) Start(ctx context.Context) error {
 }
 
 func (p *Parser) ParseOnce(ctx context.Context) error {
-	return p.runOnce(ctx)
+	_, err := p.ParseOnceWithReport(ctx)
+	return err
+}
+
+// ParseOnceWithReport runs one pass and reports failed leagues; the error is returned only when
+// nothing could be parsed (e.g. sports-with-competitions is down).
+func (p *Parser) ParseOnceWithReport(ctx context.Context) (interfaces.ParseReport, error) {
+	started := time.Now()
+	n, err := p.runOnce(ctx)
+	report := parserutil.NewParseReport(bookmakerName, started, n, err)
+	if report.Status != interfaces.ParseStatusFailed {
+		return report, nil
+	}
+	return report, err
 }
 
 func (p *Parser) Stop() error {
@@ -182,12 +223,32 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("olimp")
 }
 
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("olimp")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("olimp")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("olimp", cycleID, timeout)
 	cycleCtx, cancel := parserutil.CreateCycleContext(ctx, timeout)
 	defer cancel()
 	start := time.Now()
 	defer func() { parserutil.LogCycleFinish("olimp", cycleID, time.Since(start)) }()
-	_ = p.runOnce(cycleCtx)
+	return p.runOnce(cycleCtx)
 }
@@ -32,6 +32,11 @@ func (p *ParserWrapper) Stop() error                      { return p.parser.Stop
 func (p *ParserWrapper) GetName() string                  { return p.name }
 func (p *ParserWrapper) ParseOnce(ctx context.Context) error { return p.parser.ParseOnce(ctx) }
 
+// ParseOnceWithReport implements interfaces.ReportingParser
+func (p *ParserWrapper) ParseOnceWithReport(ctx context.Context) (interfaces.ParseReport, error) {
+	return p.parser.ParseOnceWithReport(ctx)
+}
+
 func (p *ParserWrapper) StartIncremental(ctx context.Context, timeout time.Duration) error {
 	return p.parser.StartIncremental(ctx, timeout)
 }
@@ -39,5 +44,10 @@ func (p *ParserWrapper) StartIncremental(ctx context.Context, timeout time.Durat
 func (p *ParserWrapper) TriggerNewCycle() error {
 	return p.parser.TriggerNewCycle()
 }
+func (p *ParserWrapper) LastCycle() interfaces.CycleSummary { return p.parser.LastCycle() }
+func (p *ParserWrapper) Pause() error                        { return p.parser.Pause() }
+func (p *ParserWrapper) Resume() error                       { return p.parser.Resume() }
+func (p *ParserWrapper) IsPaused() bool                      { return p.parser.IsPaused() }
 
 var _ interfaces.IncrementalParser = (*ParserWrapper)(nil)
+var _ interfaces.ReportingParser = (*ParserWrapper)(nil)
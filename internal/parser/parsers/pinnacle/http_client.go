@@ -3,17 +3,22 @@ package pinnacle
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 type Client struct {
@@ -21,12 +26,20 @@ type Client struct {
 	apiKey            string
 	deviceUUID        string
 	httpClient        *http.Client
-	proxyList         []string
-	currentProxyIndex int
-	proxyMu           sync.Mutex
+	proxies           *proxypool.Pool
+	limiter           *ratelimit.Limiter
+	fp                *fingerprint.Profile
+}
+
+// DefaultFingerprint is used when parser.pinnacle.fingerprint is not set: the browser the guest API
+// requests were captured from (YaBrowser on macOS, with client hints).
+var DefaultFingerprint = config.FingerprintConfig{
+	UserAgents:  []string{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36"},
+	ClientHints: fingerprint.Bool(true),
 }
 
-func NewClient(baseURL, apiKey, deviceUUID string, timeout time.Duration, proxyList []string) *Client {
+// NewClient creates a Pinnacle guest API client. limiter may be nil (no rate limiting); fp may be nil (DefaultFingerprint).
+func NewClient(baseURL, apiKey, deviceUUID string, timeout time.Duration, proxyList []string, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	// Allow env overrides to avoid committing secrets into configs.
 	if apiKey == "" {
 		apiKey = os.Getenv("PINNACLE_API_KEY")
@@ -35,6 +48,10 @@ func NewClient(baseURL, apiKey, deviceUUID string, timeout time.Duration, proxyL
 		deviceUUID = os.Getenv("PINNACLE_DEVICE_UUID")
 	}
 
+	if fp == nil {
+		fp = fingerprint.New("pinnacle", DefaultFingerprint)
+	}
+
 	insecureTLS := os.Getenv("PINNACLE_INSECURE_TLS") == "1"
 
 	// Use proxy list from config
@@ -60,9 +77,10 @@ func NewClient(baseURL, apiKey, deviceUUID string, timeout time.Duration, proxyL
 		baseURL:           baseURL,
 		apiKey:            apiKey,
 		deviceUUID:        deviceUUID,
-		httpClient:        &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:         proxyList,
-		currentProxyIndex: 0,
+		httpClient:        &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser("Pinnacle", transport)},
+		proxies:           proxypool.New("Pinnacle", proxyList),
+		limiter:           limiter,
+		fp:                fp,
 	}
 }
 
@@ -107,9 +125,13 @@ func (c *Client) GetSportStraightMarkets(sportID int64) ([]Market, error) {
 }
 
 func (c *Client) getJSON(path string, out any) error {
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
 	// Try proxies in order if available, fallback to direct connection
-	if len(c.proxyList) > 0 {
-		slog.Debug("Pinnacle: Using proxy list", "proxy_count", len(c.proxyList), "path", path)
+	if c.proxies.Len() > 0 {
+		slog.Debug("Pinnacle: Using proxy list", "proxy_count", c.proxies.Len(), "path", path)
 		return c.getJSONWithProxyRetry(path, out)
 	}
 
@@ -147,36 +169,9 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 	}
 	requestURL := c.baseURL + path
 
-	// Try each proxy in the list
-	c.proxyMu.Lock()
-	startIndex := c.currentProxyIndex
-	c.proxyMu.Unlock()
-
-		for attempt := 0; attempt < len(c.proxyList); attempt++ {
-		c.proxyMu.Lock()
-		proxyIndex := (startIndex + attempt) % len(c.proxyList)
-		proxyURLStr := c.proxyList[proxyIndex]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
-
-		// Create transport with this proxy
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		}
-		if os.Getenv("PINNACLE_INSECURE_TLS") == "1" {
-			transport.TLSClientConfig.InsecureSkipVerify = true
-		}
-		transport.Proxy = http.ProxyURL(proxyURL)
-
-		client := &http.Client{
-			Timeout:   c.httpClient.Timeout,
-			Transport: transport,
-		}
+	insecureTLS := os.Getenv("PINNACLE_INSECURE_TLS") == "1"
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.httpClient.Timeout, proxypool.TransportOptions{InsecureTLS: insecureTLS, Parser: "Pinnacle"})
 
 		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 		if err != nil {
@@ -185,8 +180,10 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 
 		c.setHeaders(req)
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
@@ -202,17 +199,8 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 
 		if resp.StatusCode == http.StatusOK && (strings.Contains(contentType, "application/json") || isJSON) && !isHTML {
 			// Success! Create a new reader that includes the peeked bytes
-			bodyReader := io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body)
-
-			// Create a new response with the combined body
-			// We need to wrap the body reader
-			resp.Body = io.NopCloser(bodyReader)
-
-			// Update current proxy index
-			c.proxyMu.Lock()
-			c.currentProxyIndex = proxyIndex
-			c.proxyMu.Unlock()
-			slog.Info("Pinnacle: Using working proxy", "proxy_index", proxyIndex+1, "proxy", maskProxyURL(proxyURLStr), "path", path)
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body))
+			c.proxies.Success(px, "", time.Since(start))
 
 			err := c.handleResponse(resp, out)
 			resp.Body.Close()
@@ -222,10 +210,11 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 		// Not JSON - read and close body
 		io.ReadAll(resp.Body)
 		resp.Body.Close()
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d, content-type %q", resp.StatusCode, contentType))
 	}
 
 	// All proxies failed, try direct connection as last resort
-	slog.Warn("Pinnacle: All proxies failed, trying direct connection", "path", path, "total_proxies_tried", len(c.proxyList))
+	slog.Warn("Pinnacle: All proxies failed, trying direct connection", "path", path, "total_proxies_tried", c.proxies.Len())
 	return c.getJSONDirect(path, out)
 }
 
@@ -235,14 +224,10 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Accept-Language", "ru,en;q=0.9")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
 	req.Header.Set("Content-Type", "application/json")
-	// Use realistic browser User-Agent to match browser requests
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36")
 	// Add Referer header - it helps bypass blocking (as seen in working browser requests)
 	req.Header.Set("Referer", "https://www.pinnacle.com/")
-	// Add Sec-CH-UA headers to mimic browser
-	req.Header.Set("Sec-CH-UA", `"Chromium";v="142", "YaBrowser";v="25.12", "Not_A Brand";v="99", "Yowser";v="2.5"`)
-	req.Header.Set("Sec-CH-UA-Mobile", "?0")
-	req.Header.Set("Sec-CH-UA-Platform", `"macOS"`)
+	// Realistic browser User-Agent and matching Sec-CH-UA headers
+	c.fp.Apply(req)
 	// Note: Origin header may cause 401 errors, so we don't send it
 
 	// Pinnacle guest API expects these headers (captured from browser).
@@ -355,20 +340,6 @@ func (c *Client) handleResponse(resp *http.Response, out any) error {
 	return nil
 }
 
-func maskProxyURL(proxyURL string) string {
-	// Mask password in proxy URL for logging
-	parsed, err := url.Parse(proxyURL)
-	if err != nil {
-		return "***"
-	}
-	if parsed.User != nil {
-		password, _ := parsed.User.Password()
-		if password != "" {
-			parsed.User = url.UserPassword(parsed.User.Username(), "***")
-		}
-	}
-	return parsed.String()
-}
 
 func readBodyMaybeGzip(resp *http.Response) ([]byte, error) {
 	if resp.Header.Get("Content-Encoding") == "gzip" {
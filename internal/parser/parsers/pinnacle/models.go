@@ -13,9 +13,15 @@ type RelatedMatchup struct {
 	ID        int64  `json:"id"`
 	ParentID  *int64 `json:"parentId,omitempty"`
 	StartTime string `json:"startTime"` // RFC3339
-	Type      string `json:"type"`      // "matchup"
+	Type      string `json:"type"`      // "matchup" | "special"
 	Units     string `json:"units"`
 
+	// Special is set for type=special matchups (futures like "Premier League - Winner").
+	Special *struct {
+		Category    string `json:"category"` // "Futures", ...
+		Description string `json:"description"`
+	} `json:"special,omitempty"`
+
 	League struct {
 		Name  string `json:"name"`
 		Sport struct {
@@ -28,7 +34,8 @@ type RelatedMatchup struct {
 }
 
 type Participant struct {
-	Alignment string `json:"alignment"` // "home" | "away"
+	ID        int64  `json:"id"`
+	Alignment string `json:"alignment"` // "home" | "away" (empty for specials)
 	Name      string `json:"name"`
 }
 
@@ -43,8 +50,8 @@ type Market struct {
 }
 
 type Price struct {
-	Designation string   `json:"designation"` // home/away/draw OR over/under
-	Points      *float64 `json:"points,omitempty"`
-	Price       int      `json:"price"` // American odds
+	Designation   string   `json:"designation"`             // home/away/draw OR over/under
+	ParticipantID *int64   `json:"participantId,omitempty"` // specials: price refers to a participant
+	Points        *float64 `json:"points,omitempty"`
+	Price         int      `json:"price"` // American odds
 }
-
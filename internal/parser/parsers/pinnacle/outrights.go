@@ -0,0 +1,92 @@
+package pinnacle
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// isFuturesSpecial reports whether a matchup is a tournament-level special (futures)
+// rather than a fixture or a fixture-linked special (those have a parent matchup).
+func isFuturesSpecial(mu RelatedMatchup) bool {
+	if mu.Type != "special" || mu.Special == nil {
+		return false
+	}
+	if mu.ParentID != nil && *mu.ParentID > 0 {
+		return false
+	}
+	return strings.EqualFold(mu.Special.Category, "futures")
+}
+
+// buildOutrightFromPinnacle converts a futures special of sport (our tag: "football", "hockey") and its
+// moneyline market into an Outright. Prices reference participants by participantId. Returns nil if the
+// market is closed or has no prices.
+func buildOutrightFromPinnacle(mu RelatedMatchup, markets []Market, sport string, now time.Time) *models.Outright {
+	names := make(map[int64]string, len(mu.Participants))
+	for _, p := range mu.Participants {
+		names[p.ID] = strings.TrimSpace(p.Name)
+	}
+
+	var outcomes []models.OutrightOutcome
+	for _, m := range markets {
+		if m.Type != "moneyline" {
+			continue
+		}
+		for _, pr := range m.Prices {
+			if pr.ParticipantID == nil {
+				continue
+			}
+			name := names[*pr.ParticipantID]
+			odds := americanToDecimal(pr.Price)
+			if name == "" || odds <= 1 {
+				continue
+			}
+			outcomes = append(outcomes, models.OutrightOutcome{
+				Participant: name,
+				Odds:        odds,
+				Bookmaker:   "Pinnacle",
+				UpdatedAt:   now,
+			})
+		}
+	}
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	market := strings.TrimSpace(mu.Special.Description)
+	tournament := strings.TrimSpace(mu.League.Name)
+	// Descriptions look like "English Premier League - Winner"; keep only the market part.
+	if i := strings.LastIndex(market, " - "); i > 0 {
+		if tournament == "" {
+			tournament = strings.TrimSpace(market[:i])
+		}
+		market = strings.TrimSpace(market[i+3:])
+	}
+
+	var closeTime time.Time
+	if st, err := time.Parse(time.RFC3339, mu.StartTime); err == nil {
+		closeTime = st.UTC()
+	}
+
+	return &models.Outright{
+		ID:         models.OutrightID("pinnacle", sport, tournament, market),
+		Sport:      sport,
+		Tournament: tournament,
+		MarketName: market,
+		Bookmaker:  "Pinnacle",
+		CloseTime:  closeTime,
+		Outcomes:   outcomes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// pinnacleSportTag returns our sport tag of a Pinnacle sport name: "Soccer" -> "football", "Hockey" -> "hockey".
+func pinnacleSportTag(sportName string) string {
+	if strings.EqualFold(sportName, "Soccer") {
+		return string(enums.Football)
+	}
+	return strings.ToLower(strings.TrimSpace(sportName))
+}
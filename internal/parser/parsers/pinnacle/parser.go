@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 var (
@@ -60,7 +62,9 @@ func NewParser(cfg *config.Config) *Parser {
 		baseURL = "https://guest.api.arcadia.pinnacle.com"
 	}
 
-	client := NewClient(baseURL, cfg.Parser.Pinnacle.APIKey, cfg.Parser.Pinnacle.DeviceUUID, cfg.Parser.Timeout, cfg.Parser.Pinnacle.ProxyList)
+	limiter := ratelimit.ForParser("pinnacle", cfg.Parser.Pinnacle.RateLimit, config.RateLimitConfig{})
+	client := NewClient(baseURL, cfg.Parser.Pinnacle.APIKey, cfg.Parser.Pinnacle.DeviceUUID, cfg.Parser.Timeout, cfg.Parser.Pinnacle.ProxyList, limiter,
+		fingerprint.ForParser("pinnacle", cfg.Parser.Fingerprint, cfg.Parser.Pinnacle.Fingerprint, DefaultFingerprint))
 
 	return &Parser{
 		cfg:     cfg,
@@ -166,10 +170,30 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("Pinnacle")
 }
 
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("Pinnacle")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("Pinnacle")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
 // incrementalLoop is now handled by parserutil.RunIncrementalLoop
 
 // runIncrementalCycle runs one full incremental parsing cycle
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	start := time.Now()
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("Pinnacle", cycleID, timeout)
@@ -184,9 +208,11 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 	
 	// Process all matchups incrementally
 	// Data is saved incrementally after each match in processAll
-	if _, err := p.processAll(cycleCtx); err != nil {
+	n, err := p.processAll(cycleCtx)
+	if err != nil {
 		slog.Error("Pinnacle: incremental cycle failed", "cycle_id", cycleID, "error", err)
 	}
+	return n, err
 }
 
 func (p *Parser) processAll(ctx context.Context) (int, error) {
@@ -220,8 +246,15 @@ func (p *Parser) processAll(ctx context.Context) (int, error) {
 	now := time.Now().UTC()
 	maxStart := now.Add(48 * time.Hour)
 
+	limits := parserutil.NewCycleLimits("Pinnacle", p.cfg.Parser.Pinnacle.MaxEventsPerCycle, p.cfg.Parser.Pinnacle.MaxLeagues)
+	defer limits.LogSummary()
+
 	var totalAddedCount int
 	for _, sportName := range targetSportNames {
+		// On shutdown drain the sport in progress finishes, no new sport starts
+		if parserutil.Draining() {
+			break
+		}
 		sportID, ok := nameToID[sportName]
 		if !ok || sportID == 0 {
 			continue
@@ -263,7 +296,13 @@ func (p *Parser) processAll(ctx context.Context) (int, error) {
 		// Group matchups by main matchup (parentId or self)
 		group := map[int64][]RelatedMatchup{}
 		filteredByTime := 0
+		var futures []RelatedMatchup
 		for _, mu := range matchups {
+			// Futures (league winner etc.) have no team pair: they never form a Match.
+			if isFuturesSpecial(mu) {
+				futures = append(futures, mu)
+				continue
+			}
 
 			st, err := time.Parse(time.RFC3339, mu.StartTime)
 			if err != nil {
@@ -286,6 +325,7 @@ func (p *Parser) processAll(ctx context.Context) (int, error) {
 			}
 			group[mainID] = append(group[mainID], mu)
 		}
+		group = limitMatchups(limits, group)
 
 		// Process each main matchup as a match.
 		var addedCount int
@@ -353,11 +393,50 @@ func (p *Parser) processAll(ctx context.Context) (int, error) {
 			addedCount++
 		}
 		totalAddedCount += addedCount
+
+		if p.cfg.Parser.Pinnacle.IncludeOutrights {
+			outrightCount := 0
+			for _, mu := range futures {
+				if o := buildOutrightFromPinnacle(mu, marketsByMatchup[mu.ID], pinnacleSportTag(sportName), now); o != nil {
+					health.AddOutright(o)
+					outrightCount++
+				}
+			}
+			slog.Info("Pinnacle: outrights processed", "sport", sportName, "futures", len(futures), "added", outrightCount)
+		}
 	}
 
 	return totalAddedCount, nil
 }
 
+// limitMatchups applies max_leagues / max_events_per_cycle to grouped matchups. Leagues
+// (League.Name of the main matchup) are taken in order of their first matchup ID.
+func limitMatchups(limits *parserutil.CycleLimits, group map[int64][]RelatedMatchup) map[int64][]RelatedMatchup {
+	mainIDs := make([]int64, 0, len(group))
+	for id := range group {
+		mainIDs = append(mainIDs, id)
+	}
+	sort.Slice(mainIDs, func(i, j int) bool { return mainIDs[i] < mainIDs[j] })
+
+	var leagues []string
+	byLeague := map[string][]int64{}
+	for _, id := range mainIDs {
+		league := group[id][0].League.Name
+		if _, ok := byLeague[league]; !ok {
+			leagues = append(leagues, league)
+		}
+		byLeague[league] = append(byLeague[league], id)
+	}
+
+	limited := make(map[int64][]RelatedMatchup, len(group))
+	for _, league := range parserutil.LimitLeagues(limits, leagues) {
+		for _, id := range parserutil.LimitEvents(limits, league, byLeague[league]) {
+			limited[id] = group[id]
+		}
+	}
+	return limited
+}
+
 func (p *Parser) processMatchup(ctx context.Context, matchupID int64) error {
 	related, err := p.client.GetRelatedMatchups(matchupID)
 	if err != nil {
@@ -425,6 +504,7 @@ func buildMatchFromPinnacle(matchupID int64, related []RelatedMatchup, markets [
 	if err != nil {
 		return nil, fmt.Errorf("parse startTime: %w", err)
 	}
+	startTime = startTime.UTC()
 
 	matchID := models.CanonicalMatchID(home, away, startTime)
 	bookmakerKey := "pinnacle"
@@ -18,10 +18,15 @@ import (
 	"time"
 
 	"github.com/chromedp/chromedp"
-)
 
-// chromeMu serializes all Chrome usage so only one instance runs at a time (avoids SingletonLock "File exists" when live and prematch resolve in parallel).
-var chromeMu sync.Mutex
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
+)
 
 type Client struct {
 	baseURL           string
@@ -29,9 +34,7 @@ type Client struct {
 	apiKey            string
 	deviceUUID        string
 	httpClient        *http.Client
-	proxyList         []string
-	currentProxyIndex int
-	proxyMu           sync.Mutex
+	proxies           *proxypool.Pool
 	resolvedURL       string // Cached resolved URL
 	oddsDomain        string // Cached odds domain (resolved from mirror)
 	resolvedMu        sync.RWMutex
@@ -41,16 +44,22 @@ type Client struct {
 	resolveMu         sync.Mutex    // Serializes "who runs resolve"; waiters block until one resolve finishes
 	resolveCond       *sync.Cond    // Signalled when resolve finishes so waiting goroutines can proceed
 	resolving         bool          // True while one goroutine is running resolveMirror()
+	usingOverride     bool          // resolvedURL is the manual override from the mirror registry
 	// Authentication headers for logged-in user (for live matches with actual odds)
 	cookies         string
 	xAppData        string
 	xCustID         string
 	useAuthHeaders  bool // Enable authenticated headers for odds requests
+	limiter         *ratelimit.Limiter // Shared per-parser rate limiter (nil = unlimited)
+	fp              *fingerprint.Profile // User-Agent rotation and browser headers
 }
 
+// mirrorName is the mirror registry key of Pinnacle888 (internal/pkg/mirrors).
+const mirrorName = "pinnacle888"
+
 // resolveMirror resolves the actual URL from mirror link
 // First tries HTTP redirects, then falls back to JavaScript execution via headless browser
-func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
+func resolveMirror(mirrorURL string, timeout time.Duration, userAgent string) (string, error) {
 	// First, try simple HTTP redirect
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if transport.TLSClientConfig == nil {
@@ -62,7 +71,7 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 
 	client := &http.Client{
 		Timeout:   timeout,
-		Transport: transport,
+		Transport: circuitbreaker.WrapParser("Pinnacle888", transport),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Follow redirects automatically
 			return nil
@@ -75,12 +84,12 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 		return "", fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	resp, err := client.Do(req)
 	if err != nil {
 		// If HEAD fails, try JavaScript resolution
-		return resolveMirrorWithJS(mirrorURL, timeout)
+		return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 	}
 	defer resp.Body.Close()
 
@@ -96,7 +105,7 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 			}
 			if isIPAddress(domain) {
 				slog.Debug("HTTP redirect leads to IP address, using JavaScript resolution", "domain", domain)
-				return resolveMirrorWithJS(mirrorURL, timeout)
+				return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 			}
 		}
 		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "HTTP redirect")
@@ -106,14 +115,14 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 	// If HEAD didn't redirect, try GET
 	req, err = http.NewRequest(http.MethodGet, mirrorURL, nil)
 	if err != nil {
-		return resolveMirrorWithJS(mirrorURL, timeout)
+		return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	resp, err = client.Do(req)
 	if err != nil {
-		return resolveMirrorWithJS(mirrorURL, timeout)
+		return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 	}
 	defer resp.Body.Close()
 
@@ -129,7 +138,7 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 			}
 			if isIPAddress(domain) {
 				slog.Debug("HTTP redirect leads to IP address, using JavaScript resolution", "domain", domain)
-				return resolveMirrorWithJS(mirrorURL, timeout)
+				return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 			}
 		}
 		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "HTTP redirect")
@@ -147,93 +156,66 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 			if strings.Contains(bodyStr, "<script") || strings.Contains(bodyStr, "window.location") ||
 				strings.Contains(bodyStr, "location.href") || strings.Contains(bodyStr, "document.location") {
 				slog.Debug("Detected JavaScript redirect, using headless browser")
-				return resolveMirrorWithJS(mirrorURL, timeout)
+				return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 			}
 		}
 	}
 
 	// If still same URL, try JavaScript resolution
 	slog.Debug("Pinnacle888: HTTP redirect didn't work, trying JavaScript resolution...\n")
-	return resolveMirrorWithJS(mirrorURL, timeout)
+	return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 }
 
 // resolveMirrorWithJS uses headless browser to execute JavaScript and get final URL
-func resolveMirrorWithJS(mirrorURL string, timeout time.Duration) (string, error) {
-	chromeMu.Lock()
-	defer chromeMu.Unlock()
-
-	// Unique temp dir per run so we never remove a dir that Chrome still has open (avoids ENOTEMPTY / "stale lock" warnings).
-	chromeDir, err := os.MkdirTemp("", "pinnacle888_chrome_")
-	if err != nil {
-		return "", fmt.Errorf("create chrome temp dir: %w", err)
-	}
-	defer os.RemoveAll(chromeDir)
-
-	// Create context with timeout
+func resolveMirrorWithJS(mirrorURL string, timeout time.Duration, userAgent string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.UserDataDir(chromeDir),
-		chromedp.UserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	// Create chrome instance
-	ctx, cancel = chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, v ...interface{}) {
-		// Suppress chromedp logs unless debugging
-		if os.Getenv("PINNACLE888_DEBUG") == "1" {
-			slog.Debug("chromedp", "message", fmt.Sprintf(format, v...))
-		}
-	}))
-	defer cancel()
-
 	var finalURL string
-
-	// Navigate and wait for page to load (including JavaScript redirects)
-	// Use longer wait times to ensure JavaScript redirects complete
-	err = chromedp.Run(ctx,
-		chromedp.Navigate(mirrorURL),
-		chromedp.Sleep(3*time.Second), // Wait for initial page load
-		chromedp.Location(&finalURL),
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("chromedp navigation: %w", err)
-	}
-
-	// Check if URL changed
-	if finalURL != "" && finalURL != mirrorURL {
-		// Wait a bit more and check again to ensure we got the final redirect
-		var checkURL string
-		err = chromedp.Run(ctx,
-			chromedp.Sleep(2*time.Second),
-			chromedp.Location(&checkURL),
+	changed := false
+	err := browserpool.Run(ctx, userAgent, func(ctx context.Context) error {
+		// Navigate and wait for page to load (including JavaScript redirects)
+		// Use longer wait times to ensure JavaScript redirects complete
+		err := chromedp.Run(ctx,
+			chromedp.Navigate(mirrorURL),
+			chromedp.Sleep(3*time.Second), // Wait for initial page load
+			chromedp.Location(&finalURL),
 		)
-		if err == nil && checkURL != "" && checkURL != finalURL {
-			// URL changed again, use the new one
-			finalURL = checkURL
+		if err != nil {
+			return fmt.Errorf("chromedp navigation: %w", err)
 		}
 
-		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "JavaScript redirect")
-		return finalURL, nil
-	}
+		// Check if URL changed
+		if finalURL != "" && finalURL != mirrorURL {
+			changed = true
+			// Wait a bit more and check again to ensure we got the final redirect
+			var checkURL string
+			err = chromedp.Run(ctx,
+				chromedp.Sleep(2*time.Second),
+				chromedp.Location(&checkURL),
+			)
+			if err == nil && checkURL != "" && checkURL != finalURL {
+				// URL changed again, use the new one
+				finalURL = checkURL
+			}
+			return nil
+		}
 
-	// If URL didn't change, try waiting longer
-	if finalURL == "" || finalURL == mirrorURL {
-		err = chromedp.Run(ctx,
+		// If URL didn't change, try waiting longer
+		if err := chromedp.Run(ctx,
 			chromedp.Sleep(5*time.Second),
 			chromedp.Location(&finalURL),
-		)
-		if err != nil {
-			return "", fmt.Errorf("chromedp wait: %w", err)
+		); err != nil {
+			return fmt.Errorf("chromedp wait: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if changed {
+		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "JavaScript redirect")
+		return finalURL, nil
 	}
 
 	if finalURL != "" && finalURL != mirrorURL {
@@ -250,9 +232,15 @@ func resolveMirrorWithJS(mirrorURL string, timeout time.Duration) (string, error
 	return "", fmt.Errorf("failed to resolve mirror URL: %s", mirrorURL)
 }
 
+// ResolveMirror resolves a mirror URL to the final URL (HTTP redirects, then headless Chrome).
+// Used by cmd/test-mirror.
+func ResolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
+	return resolveMirror(mirrorURL, timeout, fingerprint.DefaultUserAgents[0])
+}
+
 // getFinalDomainFromResolved tries to get the final domain after JavaScript redirects
 // This is used to find the actual odds domain from the resolved mirror URL
-func getFinalDomainFromResolved(resolvedURL string, timeout time.Duration) (string, error) {
+func getFinalDomainFromResolved(resolvedURL string, timeout time.Duration, userAgent string) (string, error) {
 	// First, check if resolvedURL is already a domain (not an IP address)
 	parsed, err := url.Parse(resolvedURL)
 	if err == nil {
@@ -267,66 +255,43 @@ func getFinalDomainFromResolved(resolvedURL string, timeout time.Duration) (stri
 		}
 	}
 
-	chromeMu.Lock()
-	defer chromeMu.Unlock()
-
-	chromeDir, createErr := os.MkdirTemp("", "pinnacle888_chrome_")
-	if createErr != nil {
-		return "", fmt.Errorf("create chrome temp dir: %w", createErr)
-	}
-	defer os.RemoveAll(chromeDir)
-
 	// If it's an IP address, try JavaScript resolution to get final URL after all redirects
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.UserDataDir(chromeDir),
-		chromedp.UserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	ctx, cancel = chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, v ...interface{}) {
-		// Suppress chromedp logs unless debugging
-		if os.Getenv("PINNACLE888_DEBUG") == "1" {
-			slog.Debug("chromedp", "message", fmt.Sprintf(format, v...))
-		}
-	}))
-	defer cancel()
-
 	var finalURL string
 	var pageHTML string
+	err = browserpool.Run(ctx, userAgent, func(ctx context.Context) error {
+		// Navigate and wait for page to load (including JavaScript redirects)
+		err := chromedp.Run(ctx,
+			chromedp.Navigate(resolvedURL),
+			chromedp.Sleep(5*time.Second), // Wait longer for JavaScript redirects
+			chromedp.Location(&finalURL),
+			chromedp.OuterHTML("html", &pageHTML),
+		)
+		if err != nil {
+			return fmt.Errorf("chromedp navigation: %w", err)
+		}
 
-	// Navigate and wait for page to load (including JavaScript redirects)
-	err = chromedp.Run(ctx,
-		chromedp.Navigate(resolvedURL),
-		chromedp.Sleep(5*time.Second), // Wait longer for JavaScript redirects
-		chromedp.Location(&finalURL),
-		chromedp.OuterHTML("html", &pageHTML),
-	)
-
+		// Check if URL changed - wait a bit more to ensure final redirect
+		if finalURL != "" && finalURL != resolvedURL {
+			var checkURL string
+			err = chromedp.Run(ctx,
+				chromedp.Sleep(2*time.Second),
+				chromedp.Location(&checkURL),
+			)
+			if err == nil && checkURL != "" && checkURL != finalURL {
+				// URL changed again, use the new one
+				finalURL = checkURL
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("chromedp navigation: %w", err)
+		return "", err
 	}
 
-	// Check if URL changed - wait a bit more to ensure final redirect
 	if finalURL != "" && finalURL != resolvedURL {
-		var checkURL string
-		err = chromedp.Run(ctx,
-			chromedp.Sleep(2*time.Second),
-			chromedp.Location(&checkURL),
-		)
-		if err == nil && checkURL != "" && checkURL != finalURL {
-			// URL changed again, use the new one
-			finalURL = checkURL
-		}
-
 		// Parse final URL to extract domain
 		parsed, err := url.Parse(finalURL)
 		if err != nil {
@@ -407,7 +372,7 @@ func isIPAddress(s string) bool {
 	return net.ParseIP(s) != nil
 }
 
-func NewClient(baseURL, mirrorURL, apiKey, deviceUUID string, timeout time.Duration, proxyList []string, authHeaders *AuthHeaders) *Client {
+func NewClient(baseURL, mirrorURL, apiKey, deviceUUID string, timeout time.Duration, proxyList []string, authHeaders *AuthHeaders, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	// Allow env overrides to avoid committing secrets into configs.
 	if apiKey == "" {
 		apiKey = os.Getenv("PINNACLE888_API_KEY")
@@ -415,6 +380,9 @@ func NewClient(baseURL, mirrorURL, apiKey, deviceUUID string, timeout time.Durat
 	if deviceUUID == "" {
 		deviceUUID = os.Getenv("PINNACLE888_DEVICE_UUID")
 	}
+	if fp == nil {
+		fp = fingerprint.New("pinnacle888", DefaultFingerprint)
+	}
 
 	insecureTLS := os.Getenv("PINNACLE888_INSECURE_TLS") == "1"
 
@@ -442,11 +410,12 @@ func NewClient(baseURL, mirrorURL, apiKey, deviceUUID string, timeout time.Durat
 		mirrorURL:         mirrorURL,
 		apiKey:            apiKey,
 		deviceUUID:        deviceUUID,
-		httpClient:        &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:         proxyList,
-		currentProxyIndex: 0,
+		httpClient:        &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser("Pinnacle888", transport)},
+		proxies:           proxypool.New("Pinnacle888", proxyList),
 		resolveTimeout:    timeout,
 		resolveInterval:   2 * time.Hour, // Re-resolve mirror at most once every 2 hours (Chrome used only when needed)
+		limiter:           limiter,
+		fp:                fp,
 	}
 	
 	// Set auth headers if provided
@@ -479,7 +448,7 @@ func (c *Client) checkURLHealth(urlStr string) bool {
 	if err != nil {
 		return false
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	c.fp.Apply(req)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -502,6 +471,9 @@ func (c *Client) ensureResolved() error {
 	if c.mirrorURL == "" {
 		return nil
 	}
+	if c.applyOverride() {
+		return nil
+	}
 
 	c.resolveMu.Lock()
 	for c.resolving {
@@ -530,11 +502,28 @@ func (c *Client) ensureResolved() error {
 		slog.Debug("Pinnacle888: Cached URL %s is not responding, re-resolving mirror...\n", resolvedURL)
 	}
 
+	// After a restart: continue with the mirror persisted by the registry (health-checked after resolveInterval)
+	if !hasResolved {
+		if cached, attrs, resolvedAt, ok := mirrors.Cached(mirrorName, c.mirrorURL); ok {
+			c.resolvedMu.Lock()
+			c.resolvedURL = cached
+			c.oddsDomain = attrs["odds_domain"]
+			c.lastResolveTime = resolvedAt
+			c.baseURL = cached
+			c.resolvedMu.Unlock()
+			c.resolveMu.Unlock()
+			slog.Info("Pinnacle888: using persisted mirror", "mirror_url", c.mirrorURL, "resolved_base_url", cached,
+				"odds_domain", attrs["odds_domain"], "resolved_at", resolvedAt)
+			return nil
+		}
+	}
+
 	// This goroutine runs resolve; others block on resolveCond until we're done
 	c.resolving = true
 	c.resolveMu.Unlock()
 
-	resolved, err := resolveMirror(c.mirrorURL, c.resolveTimeout)
+	start := time.Now()
+	resolved, err := resolveMirror(c.mirrorURL, c.resolveTimeout, c.fp.UserAgent())
 
 	c.resolveMu.Lock()
 	c.resolving = false
@@ -544,6 +533,7 @@ func (c *Client) ensureResolved() error {
 	}()
 
 	if err != nil {
+		mirrors.Failed(mirrorName, c.mirrorURL, err, time.Since(start))
 		if hasResolved {
 			slog.Warn("Pinnacle888: mirror re-resolve failed, keeping cached URL", "mirror_url", c.mirrorURL, "error", err, "error_msg", err.Error(), "cached_url", resolvedURL)
 			return nil
@@ -568,7 +558,7 @@ func (c *Client) ensureResolved() error {
 		}
 		if isIPAddress(domain) {
 			slog.Debug("Pinnacle888: Resolved URL is IP address %s, attempting to resolve domain via JavaScript...\n", domain)
-			finalDomain, err := getFinalDomainFromResolved(resolved, c.resolveTimeout)
+			finalDomain, err := getFinalDomainFromResolved(resolved, c.resolveTimeout, c.fp.UserAgent())
 			if err != nil {
 				slog.Debug("Pinnacle888: Failed to resolve domain from IP via JavaScript: %v, using IP address directly\n", err)
 				c.resolvedMu.Lock()
@@ -593,6 +583,11 @@ func (c *Client) ensureResolved() error {
 	c.resolvedMu.RLock()
 	oddsDomain := c.oddsDomain
 	c.resolvedMu.RUnlock()
+	var attrs map[string]string
+	if oddsDomain != "" {
+		attrs = map[string]string{"odds_domain": oddsDomain}
+	}
+	mirrors.Resolved(mirrorName, c.mirrorURL, resolved, attrs, time.Since(start))
 	if oddsDomain == "" {
 		oddsDomain = "(empty)"
 	}
@@ -627,11 +622,42 @@ func (c *Client) clearResolvedURL() {
 	defer c.resolvedMu.Unlock()
 	if c.resolvedURL != "" {
 		slog.Debug("Pinnacle888: Clearing cached URL %s to force re-resolution\n", c.resolvedURL)
+		if !c.usingOverride {
+			mirrors.Invalidate(mirrorName, "requests to the mirror failed")
+		}
 		c.resolvedURL = ""
 		c.oddsDomain = ""
 	}
 }
 
+// applyOverride switches to the manual mirror override (POST /mirrors/override) while one is set;
+// the odds domain is taken from the override host. Returns false when there is no override;
+// a just-cleared override is dropped so the mirror resolves again.
+func (c *Client) applyOverride() bool {
+	override := mirrors.Override(mirrorName)
+	c.resolvedMu.Lock()
+	defer c.resolvedMu.Unlock()
+	if override == "" {
+		if c.usingOverride {
+			c.usingOverride = false
+			c.resolvedURL = ""
+			c.oddsDomain = ""
+		}
+		return false
+	}
+	if !c.usingOverride || c.resolvedURL != override {
+		slog.Info("Pinnacle888: using mirror override", "mirror_url", c.mirrorURL, "override", override)
+	}
+	c.usingOverride = true
+	c.resolvedURL = override
+	c.baseURL = override
+	if u, err := url.Parse(override); err == nil {
+		c.oddsDomain = u.Hostname()
+	}
+	c.lastResolveTime = time.Now()
+	return true
+}
+
 // getResolvedBaseURL returns the resolved base URL (from mirror or direct)
 // It ensures the URL is resolved before returning
 func (c *Client) getResolvedBaseURL() string {
@@ -736,46 +762,21 @@ func (c *Client) buildOddsRequestURL(oddsPath string, pathSuffix string, query u
 	return u, nil
 }
 
-// oddsRequestCount tracks requests for rate limiting and User-Agent rotation.
-var (
-	oddsReqMu    sync.Mutex
-	oddsReqCount int64
-	oddsLastReq  time.Time
-)
-
-// oddsUserAgents is a pool of realistic browser User-Agents to rotate between requests.
-// Rotation helps avoid fingerprint-based rate limiting by Cloudflare.
-var oddsUserAgents = []string{
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.3 Safari/605.1.15",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0",
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:135.0) Gecko/20100101 Firefox/135.0",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36 Edg/142.0.0.0",
-}
+// DefaultFingerprint is used when parser.pinnacle888.fingerprint is not set: the built-in browser
+// User-Agent pool rotated per request (avoids fingerprint-based rate limiting by Cloudflare),
+// with client hints matching each User-Agent.
+var DefaultFingerprint = config.FingerprintConfig{ClientHints: fingerprint.Bool(true)}
 
-// oddsRateLimit enforces a minimum delay between requests to avoid Cloudflare 429.
-const oddsMinDelay = 500 * time.Millisecond
+// DefaultRateLimit is used when parser.pinnacle888.rate_limit is not set (500ms between requests, avoids Cloudflare 429).
+var DefaultRateLimit = config.RateLimitConfig{RPS: 2, Burst: 1}
 
 // doOddsRequest performs GET with common headers for odds domain.
-// Includes rate limiting (800ms between requests) and User-Agent rotation.
+// Includes rate limiting (shared parser limiter) and User-Agent rotation.
 // If useAuthHeaders is enabled, adds authentication headers for logged-in user.
 func (c *Client) doOddsRequest(u *url.URL, refererPath string) ([]byte, error) {
-	// Rate limit: wait if last request was too recent
-	oddsReqMu.Lock()
-	sinceLastReq := time.Since(oddsLastReq)
-	if sinceLastReq < oddsMinDelay {
-		wait := oddsMinDelay - sinceLastReq
-		oddsReqMu.Unlock()
-		time.Sleep(wait)
-		oddsReqMu.Lock()
-	}
-	reqNum := oddsReqCount
-	oddsReqCount++
-	oddsLastReq = time.Now()
-	oddsReqMu.Unlock()
-
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
@@ -793,10 +794,6 @@ func (c *Client) doOddsRequest(u *url.URL, refererPath string) ([]byte, error) {
 		req.Header.Set("Accept-Language", "en,en-US;q=0.9")
 	}
 	
-	// Rotate User-Agent to reduce fingerprint-based rate limiting
-	ua := oddsUserAgents[int(reqNum)%len(oddsUserAgents)]
-	req.Header.Set("User-Agent", ua)
-	
 	// Set Referer - use provided path or default to root
 	if refererPath != "" {
 		req.Header.Set("Referer", u.Scheme+"://"+u.Host+refererPath)
@@ -815,16 +812,15 @@ func (c *Client) doOddsRequest(u *url.URL, refererPath string) ([]byte, error) {
 		if c.xCustID != "" {
 			req.Header.Set("x-custid", c.xCustID)
 		}
-		// Add sec-ch-ua headers to mimic browser
-		req.Header.Set("sec-ch-ua", `"Chromium";v="142", "YaBrowser";v="25.12", "Not_A Brand";v="99", "Yowser";v="2.5"`)
-		req.Header.Set("sec-ch-ua-mobile", "?0")
-		req.Header.Set("sec-ch-ua-platform", `"macOS"`)
 		req.Header.Set("sec-fetch-dest", "empty")
 		req.Header.Set("sec-fetch-mode", "cors")
 		req.Header.Set("sec-fetch-site", "same-origin")
 		req.Header.Set("priority", "u=1, i")
 	}
 
+	// Rotate User-Agent (with matching sec-ch-ua) to reduce fingerprint-based rate limiting
+	c.fp.Apply(req)
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		if c.shouldReResolve(err, 0) {
@@ -837,9 +833,7 @@ func (c *Client) doOddsRequest(u *url.URL, refererPath string) ([]byte, error) {
 	if resp.StatusCode == 429 {
 		b, _ := io.ReadAll(resp.Body)
 		// On rate limit, add extra backoff before next request
-		oddsReqMu.Lock()
-		oddsLastReq = time.Now().Add(3 * time.Second) // force 3s pause before next request
-		oddsReqMu.Unlock()
+		c.limiter.Backoff(3 * time.Second) // force 3s pause before next request
 		slog.Warn("Pinnacle888: rate limited (429), backing off 3s", "url", u.Path)
 		return nil, fmt.Errorf("unexpected status 429: %s", string(b))
 	}
@@ -1007,9 +1001,12 @@ func (c *Client) GetOddsEvents(oddsPath string, sportID int64, isLive bool) ([]b
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Accept-Language", "en,en-US;q=0.9")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 	req.Header.Set("Referer", u.Scheme+"://"+u.Host+"/")
+	c.fp.Apply(req)
 
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		// If request failed, check if we should re-resolve mirror
@@ -1047,8 +1044,12 @@ func (c *Client) GetOddsEvents(oddsPath string, sportID int64, isLive bool) ([]b
 }
 
 func (c *Client) getJSON(path string, out any) error {
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
 	// Try proxies in order if available, fallback to direct connection
-	if len(c.proxyList) > 0 {
+	if c.proxies.Len() > 0 {
 		return c.getJSONWithProxyRetry(path, out)
 	}
 
@@ -1099,36 +1100,9 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 	}
 	requestURL := baseURL + path
 
-	// Try each proxy in the list
-	c.proxyMu.Lock()
-	startIndex := c.currentProxyIndex
-	c.proxyMu.Unlock()
-
-	for attempt := 0; attempt < len(c.proxyList); attempt++ {
-		c.proxyMu.Lock()
-		proxyIndex := (startIndex + attempt) % len(c.proxyList)
-		proxyURLStr := c.proxyList[proxyIndex]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
-
-		// Create transport with this proxy
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		}
-		if os.Getenv("PINNACLE888_INSECURE_TLS") == "1" {
-			transport.TLSClientConfig.InsecureSkipVerify = true
-		}
-		transport.Proxy = http.ProxyURL(proxyURL)
-
-		client := &http.Client{
-			Timeout:   c.httpClient.Timeout,
-			Transport: transport,
-		}
+	insecureTLS := os.Getenv("PINNACLE888_INSECURE_TLS") == "1"
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.httpClient.Timeout, proxypool.TransportOptions{InsecureTLS: insecureTLS, Parser: "Pinnacle888"})
 
 		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 		if err != nil {
@@ -1137,8 +1111,10 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 
 		c.setHeaders(req)
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
@@ -1154,17 +1130,8 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 
 		if resp.StatusCode == http.StatusOK && (strings.Contains(contentType, "application/json") || isJSON) && !isHTML {
 			// Success! Create a new reader that includes the peeked bytes
-			bodyReader := io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body)
-
-			// Create a new response with the combined body
-			// We need to wrap the body reader
-			resp.Body = io.NopCloser(bodyReader)
-
-			// Update current proxy index
-			c.proxyMu.Lock()
-			c.currentProxyIndex = proxyIndex
-			c.proxyMu.Unlock()
-			slog.Debug("Pinnacle888: Using working proxy %s\n", maskProxyURL(proxyURLStr))
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body))
+			c.proxies.Success(px, "", time.Since(start))
 
 			err := c.handleResponse(resp, out)
 			resp.Body.Close()
@@ -1174,6 +1141,7 @@ func (c *Client) getJSONWithProxyRetry(path string, out any) error {
 		// Not JSON - read and close body
 		io.ReadAll(resp.Body)
 		resp.Body.Close()
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d, content-type %q", resp.StatusCode, contentType))
 	}
 
 	// All proxies failed, try direct connection as last resort
@@ -1188,14 +1156,10 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Accept-Language", "en,en-US;q=0.9")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
 	req.Header.Set("Content-Type", "application/json")
-	// Use realistic browser User-Agent to match browser requests
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36")
 	// Add Referer header - it helps bypass blocking (as seen in working browser requests)
 	req.Header.Set("Referer", "https://www.pinnacle.com/")
-	// Add Sec-CH-UA headers to mimic browser
-	req.Header.Set("Sec-CH-UA", `"Chromium";v="142", "YaBrowser";v="25.12", "Not_A Brand";v="99", "Yowser";v="2.5"`)
-	req.Header.Set("Sec-CH-UA-Mobile", "?0")
-	req.Header.Set("Sec-CH-UA-Platform", `"macOS"`)
+	// Realistic browser User-Agent and matching Sec-CH-UA headers
+	c.fp.Apply(req)
 	// Note: Origin header may cause 401 errors, so we don't send it
 
 	// Pinnacle guest API expects these headers (captured from browser).
@@ -1308,20 +1272,6 @@ func (c *Client) handleResponse(resp *http.Response, out any) error {
 	return nil
 }
 
-func maskProxyURL(proxyURL string) string {
-	// Mask password in proxy URL for logging
-	parsed, err := url.Parse(proxyURL)
-	if err != nil {
-		return "***"
-	}
-	if parsed.User != nil {
-		password, _ := parsed.User.Password()
-		if password != "" {
-			parsed.User = url.UserPassword(parsed.User.Username(), "***")
-		}
-	}
-	return parsed.String()
-}
 
 func readBodyMaybeGzip(resp *http.Response) ([]byte, error) {
 	if resp.Header.Get("Content-Encoding") == "gzip" {
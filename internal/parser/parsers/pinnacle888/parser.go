@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 var runOnceMu sync.Mutex
@@ -24,6 +26,7 @@ type Parser struct {
 	cfg     *config.Config
 	client  *Client
 	storage interfaces.Storage
+	leagues *parserutil.LeagueFilter // parser.pinnacle888.leagues
 	
 	// Incremental parsing state
 	incState *parserutil.IncrementalParserState
@@ -50,12 +53,15 @@ func NewParser(cfg *config.Config) *Parser {
 		}
 	}
 
-	client := NewClient(baseURL, mirrorURL, cfg.Parser.Pinnacle888.APIKey, cfg.Parser.Pinnacle888.DeviceUUID, cfg.Parser.Timeout, cfg.Parser.Pinnacle888.ProxyList, authHeaders)
+	limiter := ratelimit.ForParser("pinnacle888", cfg.Parser.Pinnacle888.RateLimit, DefaultRateLimit)
+	client := NewClient(baseURL, mirrorURL, cfg.Parser.Pinnacle888.APIKey, cfg.Parser.Pinnacle888.DeviceUUID, cfg.Parser.Timeout, cfg.Parser.Pinnacle888.ProxyList, authHeaders, limiter,
+		fingerprint.ForParser("pinnacle888", cfg.Parser.Fingerprint, cfg.Parser.Pinnacle888.Fingerprint, DefaultFingerprint))
 
 	return &Parser{
 		cfg:     cfg,
 		client:  client,
 		storage: nil, // No external storage - data served from memory
+		leagues: parserutil.NewLeagueFilter("Pinnacle888", cfg.Parser.Pinnacle888.Leagues),
 	}
 }
 
@@ -110,14 +116,10 @@ func (p *Parser) runOnce(ctx context.Context) error {
 			}
 		} else {
 			// Add matches to in-memory store
-			for _, match := range matches {
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-				}
-				health.AddMatch(match)
+			if ctx.Err() != nil {
+				return nil
 			}
+			health.AddMatches(matches)
 			totalMatches = len(matches)
 			slog.Info("Pinnacle888: pre-match matches processed", "count", totalMatches)
 		}
@@ -197,10 +199,30 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("Pinnacle888")
 }
 
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("Pinnacle888")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("Pinnacle888")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
 // incrementalLoop is now handled by parserutil.RunIncrementalLoop
 
 // runIncrementalCycle runs one full parsing cycle incrementally (by leagues)
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	start := time.Now()
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("Pinnacle888", cycleID, timeout)
@@ -227,17 +249,19 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 	// Process pre-match matches incrementally (continuously, no pauses)
 	if p.cfg.Parser.Pinnacle888.IncludePrematch && p.cfg.Parser.Pinnacle888.OddsURL != "" {
 		slog.Info("Pinnacle888: starting pre-match incremental processing", "cycle_id", cycleID)
-		p.processOddsLeaguesFlowIncremental(cycleCtx, false)
+		n, err := p.processOddsLeaguesFlowIncremental(cycleCtx, false)
 		slog.Info("Pinnacle888: pre-match incremental processing completed", "cycle_id", cycleID)
+		return n, err
 	}
+	return 0, nil
 }
 
 // processOddsLeaguesFlowIncremental processes leagues incrementally, updating storage after each league
 // Processes leagues continuously without pauses between them
-func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive bool) {
+func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive bool) (int, error) {
 	oddsURL := p.cfg.Parser.Pinnacle888.OddsURL
 	if oddsURL == "" {
-		return
+		return 0, nil
 	}
 	sportID := int64(29) // Soccer
 	
@@ -250,7 +274,7 @@ func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive b
 	leagues, err := p.client.GetLeagues(oddsURL, sportID)
 	if err != nil {
 		slog.Error("Pinnacle888: failed to get leagues", "mode", mode, "error", err)
-		return
+		return 0, fmt.Errorf("get leagues: %w", err)
 	}
 	slog.Info("Pinnacle888: fetched leagues", "mode", mode, "count", len(leagues))
 	
@@ -263,18 +287,28 @@ func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive b
 	}
 	slog.Info("Pinnacle888: filtering leagues with events", "mode", mode, "total", len(leagues), "with_events", len(leaguesWithEvents))
 	
+	limits := parserutil.NewCycleLimits("Pinnacle888", p.cfg.Parser.Pinnacle888.MaxEventsPerCycle, p.cfg.Parser.Pinnacle888.MaxLeagues)
+	defer limits.LogSummary()
+	leaguesWithEvents = parserutil.FilterLeagues(p.leagues, leaguesWithEvents, leagueNames)
+	leaguesWithEvents = parserutil.LimitLeagues(limits, leaguesWithEvents)
 	totalLeagues := len(leaguesWithEvents)
 	
 	// Process leagues one by one continuously, updating storage incrementally
 	// No pauses between leagues - just continuous parsing until timeout or all leagues processed
 	matchesTotal := 0
+	// On shutdown drain the league in progress is stored, no new league starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 	for idx, league := range leaguesWithEvents {
 		select {
-		case <-ctx.Done():
+		case <-leaguesCtx.Done():
 			slog.Warn("Pinnacle888: incremental processing interrupted", "mode", mode, "leagues_processed", idx, "leagues_total", totalLeagues)
-			return
+			return matchesTotal, nil
 		default:
 		}
+		if limits.Exhausted() {
+			break
+		}
 		
 		leagueIdx := idx + 1
 		leagueStart := time.Now()
@@ -286,13 +320,11 @@ func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive b
 			"percent", fmt.Sprintf("%.1f%%", float64(leagueIdx)/float64(totalLeagues)*100))
 		
 		// Process single league and update storage immediately
-		matches := p.processSingleLeague(ctx, oddsURL, league, sportID, isLive)
+		matches := p.processSingleLeague(ctx, oddsURL, league, sportID, isLive, limits)
 		
 		// Update storage incrementally after each league
 		// These matches are immediately available via /matches endpoint
-		for _, match := range matches {
-			health.AddMatch(match)
-		}
+		health.AddMatches(matches)
 		slog.Debug("Pinnacle888: matches saved to store", "mode", mode, "league", league.Name, "matches_count", len(matches))
 		
 		matchesTotal += len(matches)
@@ -311,10 +343,12 @@ func (p *Parser) processOddsLeaguesFlowIncremental(ctx context.Context, isLive b
 		"mode", mode, 
 		"leagues_processed", len(leaguesWithEvents),
 		"matches_total", matchesTotal)
+	return matchesTotal, nil
 }
 
-// processSingleLeague processes a single league and returns matches
-func (p *Parser) processSingleLeague(ctx context.Context, oddsURL string, league LeagueListItem, sportID int64, isLive bool) []*models.Match {
+// processSingleLeague processes a single league and returns matches.
+// Main events beyond the cycle's max_events_per_cycle budget are not fetched.
+func (p *Parser) processSingleLeague(ctx context.Context, oddsURL string, league LeagueListItem, sportID int64, isLive bool, limits *parserutil.CycleLimits) []*models.Match {
 	var matches []*models.Match
 	leagueStart := time.Now()
 	
@@ -391,6 +425,7 @@ func (p *Parser) processSingleLeague(ctx context.Context, oddsURL string, league
 		"main_events", len(mainEvents),
 		"statistical_events", eventsWithParentID,
 		"matches_with_stats", len(eventsByParent))
+	mainEvents = parserutil.LimitEvents(limits, league.Name, mainEvents)
 	
 	// Build referer path for this league
 	refererPath := fmt.Sprintf("/en/standard/soccer/%s", league.LeagueCode)
@@ -744,16 +779,26 @@ func (p *Parser) processOddsLeaguesFlow(ctx context.Context, isLive bool) ([]*mo
 		}
 	}
 	slog.Info("Pinnacle888: filtering leagues with events", "total", len(leagues), "with_events", len(leaguesWithEvents))
+	limits := parserutil.NewCycleLimits("Pinnacle888", p.cfg.Parser.Pinnacle888.MaxEventsPerCycle, p.cfg.Parser.Pinnacle888.MaxLeagues)
+	defer limits.LogSummary()
+	leaguesWithEvents = parserutil.FilterLeagues(p.leagues, leaguesWithEvents, leagueNames)
+	leaguesWithEvents = parserutil.LimitLeagues(limits, leaguesWithEvents)
 
 	var allMatches []*models.Match
 	totalLeagues := len(leaguesWithEvents)
+	// On shutdown drain the league in progress finishes, no new league starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 
 	for idx, league := range leaguesWithEvents {
 		select {
-		case <-ctx.Done():
+		case <-leaguesCtx.Done():
 			return allMatches, ctx.Err()
 		default:
 		}
+		if limits.Exhausted() {
+			break
+		}
 
 		leagueIdx := idx + 1
 		slog.Info(fmt.Sprintf("Pinnacle888: processing league: %s (%d/%d)", league.Name, leagueIdx, totalLeagues))
@@ -790,6 +835,8 @@ func (p *Parser) processOddsLeaguesFlow(ctx context.Context, isLive bool) ([]*mo
 			}
 		}
 		
+		mainEvents = parserutil.LimitEvents(limits, league.Name, mainEvents)
+
 		var eventsTotal, getEventErr, parseErr, skipped, matchesAdded int
 		var firstGetErrMsg string
 		// Build referer path for this league
@@ -995,6 +1042,7 @@ func buildMatchFromPinnacle(matchupID int64, related []RelatedMatchup, markets [
 	if err != nil {
 		return nil, fmt.Errorf("parse startTime: %w", err)
 	}
+	startTime = startTime.UTC()
 
 	matchID := models.CanonicalMatchID(home, away, startTime)
 	bookmakerKey := "pinnacle888"
@@ -1390,3 +1438,8 @@ func newOutcome(eventID, outcomeType, param string, odds float64) models.Outcome
 		UpdatedAt:   now,
 	}
 }
+
+// leagueNames returns the names matched by parser.pinnacle888.leagues.
+func leagueNames(l LeagueListItem) []string {
+	return []string{l.Name, l.EnglishName}
+}
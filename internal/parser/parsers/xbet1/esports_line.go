@@ -1,13 +1,17 @@
 package xbet1
 
 import (
+	"log/slog"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/line"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
 // BuildLineMatchFromGameDetails builds line.Match from xbet GameDetails (для sport_id=40, киберспорт).
+// Empty discipline is detected from the league name ("Dota 2. DreamLeague" → dota2, "CS2. BLAST" → cs),
+// so xbet matches group with Fonbet's; unknown disciplines stay under the generic "esports" tag.
 func BuildLineMatchFromGameDetails(game *GameDetails, leagueName, discipline, bookmaker string) *line.Match {
 	if game == nil {
 		return nil
@@ -23,9 +27,6 @@ func BuildLineMatchFromGameDetails(game *GameDetails, leagueName, discipline, bo
 	if homeTeam == "" || awayTeam == "" {
 		return nil
 	}
-	if discipline == "" {
-		discipline = "esports"
-	}
 	if bookmaker == "" {
 		bookmaker = "1xbet"
 	}
@@ -37,6 +38,9 @@ func BuildLineMatchFromGameDetails(game *GameDetails, leagueName, discipline, bo
 	if league == "" {
 		league = game.L
 	}
+	if discipline == "" {
+		discipline = detectDiscipline(league, game.L, game.SE, game.SN)
+	}
 
 	markets := buildMarketsFromGroupEvents(game.GE)
 	if len(markets) == 0 {
@@ -54,62 +58,131 @@ func BuildLineMatchFromGameDetails(game *GameDetails, leagueName, discipline, bo
 	}
 }
 
+// detectDiscipline returns the first discipline recognized in names, else the generic esports tag.
+func detectDiscipline(names ...string) string {
+	for _, name := range names {
+		if sport, ok := enums.DetectEsportsDiscipline(name); ok {
+			return string(sport)
+		}
+	}
+	return string(enums.Esports)
+}
+
 func buildMarketsFromGroupEvents(ge []GroupEvent) []line.Market {
-	var markets []line.Market
-	mainEventType := string(models.StandardEventMainMatch)
-	mainName := models.GetMarketName(models.StandardEventMainMatch)
+	byType := map[string]*line.Market{}
+	var order []string
+	add := func(eventType string, o line.Outcome) {
+		m := byType[eventType]
+		if m == nil {
+			m = &line.Market{EventType: eventType, MarketName: models.GetEsportsMarketName(eventType)}
+			byType[eventType] = m
+			order = append(order, eventType)
+		}
+		m.Outcomes = append(m.Outcomes, o)
+	}
 
 	for _, g := range ge {
-		var outcomes []line.Outcome
-		switch g.G {
-		case 1:
-			for _, eventArray := range g.E {
-				for _, e := range eventArray {
+		for _, eventArray := range g.E {
+			for _, e := range eventArray {
+				switch g.G {
+				case 1:
+					// Match winner
 					switch e.T {
 					case 1:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "home_win", Parameter: "", Odds: e.C})
+						add(models.EsportsMarketMatchWinner, line.Outcome{OutcomeType: "home_win", Odds: e.C})
 					case 2:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "draw", Parameter: "", Odds: e.C})
+						add(models.EsportsMarketMatchWinner, line.Outcome{OutcomeType: "draw", Odds: e.C})
 					case 3:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "away_win", Parameter: "", Odds: e.C})
+						add(models.EsportsMarketMatchWinner, line.Outcome{OutcomeType: "away_win", Odds: e.C})
 					}
-				}
-			}
-			if len(outcomes) > 0 {
-				markets = append(markets, line.Market{EventType: mainEventType, MarketName: mainName, Outcomes: outcomes})
-			}
-		case 2:
-			for _, eventArray := range g.E {
-				for _, e := range eventArray {
+				case 2:
+					// Handicap: maps (±1.5) or rounds/kills (±4.5)
+					param := formatSignedLine(e.P)
 					switch e.T {
 					case 7:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "handicap_home", Parameter: formatSignedLine(e.P), Odds: e.C})
+						add(models.EsportsHandicapMarket(param), line.Outcome{OutcomeType: "handicap_home", Parameter: param, Odds: e.C})
 					case 8:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "handicap_away", Parameter: formatSignedLine(e.P), Odds: e.C})
+						add(models.EsportsHandicapMarket(param), line.Outcome{OutcomeType: "handicap_away", Parameter: param, Odds: e.C})
 					}
-				}
-			}
-			if len(outcomes) > 0 {
-				markets = append(markets, line.Market{EventType: mainEventType, MarketName: mainName, Outcomes: outcomes})
-			}
-		case 17:
-			for _, eventArray := range g.E {
-				for _, e := range eventArray {
+				case 17:
+					// Total: maps (2.5) or rounds/kills (46.5)
 					param := formatLine(e.P)
 					switch e.T {
 					case 9:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "total_over", Parameter: param, Odds: e.C})
+						add(models.EsportsTotalMarket(param), line.Outcome{OutcomeType: "total_over", Parameter: param, Odds: e.C})
 					case 10:
-						outcomes = append(outcomes, line.Outcome{OutcomeType: "total_under", Parameter: param, Odds: e.C})
+						add(models.EsportsTotalMarket(param), line.Outcome{OutcomeType: "total_under", Parameter: param, Odds: e.C})
 					}
 				}
 			}
-			if len(outcomes) > 0 {
-				markets = append(markets, line.Market{EventType: mainEventType, MarketName: mainName, Outcomes: outcomes})
-			}
 		}
 	}
+
+	markets := make([]line.Market, 0, len(order))
+	for _, eventType := range order {
+		markets = append(markets, *byType[eventType])
+	}
 	return markets
 }
 
+// esportsMapSubGame is a per-map sub-game of an esports match (SG with PN "1-я карта").
+type esportsMapSubGame struct {
+	mapNumber int
+	id        int64
+}
+
+// esportsMapSubGames picks the main sub-game of each map; titled sub-games (TG) are side markets.
+func esportsMapSubGames(subGames []SubGame) []esportsMapSubGame {
+	seen := map[int]bool{}
+	var out []esportsMapSubGame
+	for _, sg := range subGames {
+		if sg.TG != "" || sg.CI == 0 {
+			continue
+		}
+		n, ok := models.ParseEsportsMapNumber(sg.PN)
+		if !ok || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, esportsMapSubGame{mapNumber: n, id: sg.CI})
+	}
+	return out
+}
+
+// buildMapWinnerMarket builds the winner market of map n from its sub-game (G=1: T=1 home, T=3 away).
+func buildMapWinnerMarket(n int, ge []GroupEvent) (line.Market, bool) {
+	eventType := models.EsportsMapWinnerMarket(n)
+	m := line.Market{EventType: eventType, MarketName: models.GetEsportsMarketName(eventType)}
+	for _, g := range ge {
+		if g.G != 1 {
+			continue
+		}
+		for _, eventArray := range g.E {
+			for _, e := range eventArray {
+				switch e.T {
+				case 1:
+					m.Outcomes = append(m.Outcomes, line.Outcome{OutcomeType: "home_win", Odds: e.C})
+				case 3:
+					m.Outcomes = append(m.Outcomes, line.Outcome{OutcomeType: "away_win", Odds: e.C})
+				}
+			}
+		}
+	}
+	return m, len(m.Outcomes) > 0
+}
+
+// addMapWinnerMarkets fetches the per-map sub-games of an esports match and adds their winner markets.
+func (p *Parser) addMapWinnerMarkets(lm *line.Match, game *GameDetails) {
+	for _, sg := range esportsMapSubGames(game.SG) {
+		sub, err := p.client.GetSubGame(sg.id)
+		if err != nil {
+			slog.Debug("1xbet: esports map sub-game fetch failed", "match_id", game.I, "map", sg.mapNumber, "sub_game_id", sg.id, "error", err)
+			continue
+		}
+		if m, ok := buildMapWinnerMarket(sg.mapNumber, sub.GE); ok {
+			lm.Markets = append(lm.Markets, m)
+		}
+	}
+}
+
 // formatSignedLine and formatLine are in odds_parser.go (same package)
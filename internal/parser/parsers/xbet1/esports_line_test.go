@@ -0,0 +1,60 @@
+package xbet1
+
+import (
+	"testing"
+)
+
+func TestBuildLineMatchFromGameDetails(t *testing.T) {
+	game := &GameDetails{
+		O1E: "FaZe", O2E: "Vitality", S: 1767261600, LE: "Counter-Strike 2. BLAST Premier",
+		GE: []GroupEvent{
+			{G: 1, E: [][]Event{{{T: 1, C: 2.1}}, {{T: 3, C: 1.7}}}},
+			{G: 2, E: [][]Event{{{T: 7, P: 1.5, C: 1.4}, {T: 7, P: -4.5, C: 2.2}}, {{T: 8, P: -1.5, C: 2.8}}}},
+			{G: 17, E: [][]Event{{{T: 9, P: 2.5, C: 2.0}, {T: 9, P: 26.5, C: 1.9}}, {{T: 10, P: 2.5, C: 1.8}}}},
+		},
+	}
+	lm := BuildLineMatchFromGameDetails(game, "", "", "")
+	if lm == nil {
+		t.Fatal("nil match")
+	}
+	if lm.Sport != "cs" || lm.Bookmaker != "1xbet" {
+		t.Errorf("sport/bookmaker = %s/%s", lm.Sport, lm.Bookmaker)
+	}
+	counts := map[string]int{}
+	for _, m := range lm.Markets {
+		counts[m.EventType] += len(m.Outcomes)
+	}
+	want := map[string]int{"main_match": 2, "handicap_maps": 2, "handicap_rounds": 1, "total_maps": 2, "total_rounds": 1}
+	for k, n := range want {
+		if counts[k] != n {
+			t.Errorf("%s: %d outcomes, want %d", k, counts[k], n)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("unexpected markets: %v", counts)
+	}
+
+	game.LE = "Cyber Arena"
+	if lm := BuildLineMatchFromGameDetails(game, "", "", ""); lm == nil || lm.Sport != "esports" {
+		t.Errorf("unknown discipline should stay generic, got %+v", lm)
+	}
+}
+
+func TestEsportsMapSubGames(t *testing.T) {
+	sgs := []SubGame{
+		{CI: 11, PN: "1-я карта"},
+		{CI: 12, PN: "1-я карта", TG: "Убийства"},
+		{CI: 13, PN: "2-я карта"},
+		{CI: 14, PN: "1-я карта"}, // duplicate map
+		{CI: 15, TG: "Угловые"},
+	}
+	got := esportsMapSubGames(sgs)
+	if len(got) != 2 || got[0] != (esportsMapSubGame{mapNumber: 1, id: 11}) || got[1] != (esportsMapSubGame{mapNumber: 2, id: 13}) {
+		t.Errorf("got %+v", got)
+	}
+
+	m, ok := buildMapWinnerMarket(2, []GroupEvent{{G: 1, E: [][]Event{{{T: 1, C: 1.9}}, {{T: 3, C: 1.9}}}}})
+	if !ok || m.EventType != "map_2_winner" || len(m.Outcomes) != 2 {
+		t.Errorf("map winner = %+v, %v", m, ok)
+	}
+}
@@ -20,10 +20,18 @@ import (
 	"github.com/andybalholm/brotli"
 	"github.com/chromedp/chromedp"
 	"github.com/klauspost/compress/zstd"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
-// chromeMu serializes all Chrome usage so only one instance runs at a time
-var chromeMu sync.Mutex
+// mirrorName is the mirror registry key of 1xbet (internal/pkg/mirrors).
+const mirrorName = "xbet1"
 
 // fallbackBaseURL is used when mirror resolution fails
 const fallbackBaseURL = "https://1xlite-6173396.bar"
@@ -32,9 +40,7 @@ type Client struct {
 	baseURL        string
 	mirrorURL      string // Mirror URL to resolve actual baseURL
 	httpClient     *http.Client
-	proxyList      []string
-	currentProxyIndex int
-	proxyMu        sync.Mutex
+	proxies        *proxypool.Pool
 	resolvedURL    string // Cached resolved URL
 	resolvedMu     sync.RWMutex
 	resolveTimeout time.Duration
@@ -43,11 +49,14 @@ type Client struct {
 	resolveMu      sync.Mutex
 	resolveCond    *sync.Cond
 	resolving      bool
+	usingOverride  bool // resolvedURL is the manual override from the mirror registry
+	limiter        *ratelimit.Limiter // Shared per-parser rate limiter (nil = unlimited)
+	fp             *fingerprint.Profile // User-Agent and browser headers
 }
 
 // resolveMirror resolves the actual URL from mirror link
 // First tries HTTP redirects, then falls back to JavaScript execution via headless browser
-func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
+func resolveMirror(mirrorURL string, timeout time.Duration, userAgent string) (string, error) {
 	// First, try simple HTTP redirect
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if transport.TLSClientConfig == nil {
@@ -70,7 +79,7 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 
 	client := &http.Client{
 		Timeout:   resolveTimeout,
-		Transport: transport,
+		Transport: circuitbreaker.WrapParser("1xbet", transport),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return nil
 		},
@@ -82,7 +91,7 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 		return "", fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -100,15 +109,15 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 	// If HEAD didn't redirect or failed, try GET
 	req, err = http.NewRequest(http.MethodGet, mirrorURL, nil)
 	if err != nil {
-		return resolveMirrorWithJS(mirrorURL, timeout)
+		return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	resp, err = client.Do(req)
 	if err != nil {
 		slog.Info("HTTP GET request failed, falling back to JavaScript resolution", "error", err)
-		return resolveMirrorWithJS(mirrorURL, timeout)
+		return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 	}
 	defer resp.Body.Close()
 
@@ -128,82 +137,59 @@ func resolveMirror(mirrorURL string, timeout time.Duration) (string, error) {
 			if strings.Contains(bodyStr, "<script") || strings.Contains(bodyStr, "window.location") ||
 				strings.Contains(bodyStr, "location.href") || strings.Contains(bodyStr, "document.location") {
 				slog.Debug("Detected JavaScript redirect, using headless browser")
-				return resolveMirrorWithJS(mirrorURL, timeout)
+				return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 			}
 		}
 	}
 
 	slog.Debug("1xbet: HTTP redirect didn't work, trying JavaScript resolution...")
-	return resolveMirrorWithJS(mirrorURL, timeout)
+	return resolveMirrorWithJS(mirrorURL, timeout, userAgent)
 }
 
 // resolveMirrorWithJS uses headless browser to execute JavaScript and get final URL
-func resolveMirrorWithJS(mirrorURL string, timeout time.Duration) (string, error) {
-	chromeMu.Lock()
-	defer chromeMu.Unlock()
-
-	chromeDir, err := os.MkdirTemp("", "xbet1_chrome_")
-	if err != nil {
-		return "", fmt.Errorf("create chrome temp dir: %w", err)
-	}
-	defer os.RemoveAll(chromeDir)
-
+func resolveMirrorWithJS(mirrorURL string, timeout time.Duration, userAgent string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.UserDataDir(chromeDir),
-		chromedp.UserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	ctx, cancel = chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, v ...interface{}) {
-		if os.Getenv("1XBET_DEBUG") == "1" {
-			slog.Debug("chromedp", "message", fmt.Sprintf(format, v...))
-		}
-	}))
-	defer cancel()
-
 	var finalURL string
-
-	err = chromedp.Run(ctx,
-		chromedp.Navigate(mirrorURL),
-		chromedp.Sleep(3*time.Second),
-		chromedp.Location(&finalURL),
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("chromedp navigation: %w", err)
-	}
-
-	if finalURL != "" && finalURL != mirrorURL {
-		var checkURL string
-		err = chromedp.Run(ctx,
-			chromedp.Sleep(2*time.Second),
-			chromedp.Location(&checkURL),
+	changed := false
+	err := browserpool.Run(ctx, userAgent, func(ctx context.Context) error {
+		err := chromedp.Run(ctx,
+			chromedp.Navigate(mirrorURL),
+			chromedp.Sleep(3*time.Second),
+			chromedp.Location(&finalURL),
 		)
-		if err == nil && checkURL != "" && checkURL != finalURL {
-			finalURL = checkURL
+		if err != nil {
+			return fmt.Errorf("chromedp navigation: %w", err)
 		}
 
-		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "JavaScript redirect")
-		return finalURL, nil
-	}
+		if finalURL != "" && finalURL != mirrorURL {
+			changed = true
+			var checkURL string
+			err = chromedp.Run(ctx,
+				chromedp.Sleep(2*time.Second),
+				chromedp.Location(&checkURL),
+			)
+			if err == nil && checkURL != "" && checkURL != finalURL {
+				finalURL = checkURL
+			}
+			return nil
+		}
 
-	if finalURL == "" || finalURL == mirrorURL {
-		err = chromedp.Run(ctx,
+		if err := chromedp.Run(ctx,
 			chromedp.Sleep(5*time.Second),
 			chromedp.Location(&finalURL),
-		)
-		if err != nil {
-			return "", fmt.Errorf("chromedp wait: %w", err)
+		); err != nil {
+			return fmt.Errorf("chromedp wait: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if changed {
+		slog.Debug("Resolved mirror", "from", mirrorURL, "to", finalURL, "method", "JavaScript redirect")
+		return finalURL, nil
 	}
 
 	if finalURL != "" && finalURL != mirrorURL {
@@ -244,14 +230,25 @@ func normalizeResolvedBaseURL(resolved string) string {
 // ResolveMirrorToBaseURL resolves mirror URL to the actual 1xbet base URL (scheme://host).
 // Can be used by scripts/cron to get a fixed base_url for XBET1_BASE_URL env.
 func ResolveMirrorToBaseURL(mirrorURL string, timeout time.Duration) (baseURL string, err error) {
-	resolved, err := resolveMirror(mirrorURL, timeout)
+	resolved, err := resolveMirror(mirrorURL, timeout, fingerprint.DefaultUserAgents[0])
 	if err != nil {
 		return "", err
 	}
 	return normalizeResolvedBaseURL(resolved), nil
 }
 
-func NewClient(baseURL, mirrorURL string, timeout time.Duration, proxyList []string) *Client {
+// DefaultFingerprint is used when parser.xbet1.fingerprint is not set: the browser the API
+// requests were captured from (YaBrowser on macOS, with client hints).
+var DefaultFingerprint = config.FingerprintConfig{
+	UserAgents:  []string{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36"},
+	ClientHints: fingerprint.Bool(true),
+}
+
+// NewClient creates a 1xbet client. limiter may be nil (no rate limiting); fp may be nil (DefaultFingerprint).
+func NewClient(baseURL, mirrorURL string, timeout time.Duration, proxyList []string, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
+	if fp == nil {
+		fp = fingerprint.New("xbet1", DefaultFingerprint)
+	}
 	insecureTLS := os.Getenv("1XBET_INSECURE_TLS") == "1"
 
 	transport := http.DefaultTransport.(*http.Transport).Clone()
@@ -268,11 +265,12 @@ func NewClient(baseURL, mirrorURL string, timeout time.Duration, proxyList []str
 	client := &Client{
 		baseURL:           baseURL,
 		mirrorURL:         mirrorURL,
-		httpClient:        &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:         proxyList,
-		currentProxyIndex: 0,
+		httpClient:        &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser("1xbet", transport)},
+		proxies:           proxypool.New("1xbet", proxyList),
 		resolveTimeout:    timeout,
 		resolveInterval:   2 * time.Hour,
+		limiter:           limiter,
+		fp:                fp,
 	}
 	
 	client.resolveCond = sync.NewCond(&client.resolveMu)
@@ -285,6 +283,9 @@ func (c *Client) ensureResolved() error {
 	if c.mirrorURL == "" {
 		return nil
 	}
+	if c.applyOverride() {
+		return nil
+	}
 
 	c.resolveMu.Lock()
 	for c.resolving {
@@ -312,10 +313,25 @@ func (c *Client) ensureResolved() error {
 		slog.Debug("1xbet: Cached URL is not responding, re-resolving mirror", "cached_url", resolvedURL)
 	}
 
+	// After a restart: continue with the mirror persisted by the registry (health-checked after resolveInterval)
+	if !hasResolved {
+		if cached, _, resolvedAt, ok := mirrors.Cached(mirrorName, c.mirrorURL); ok {
+			c.resolvedMu.Lock()
+			c.resolvedURL = cached
+			c.lastResolveTime = resolvedAt
+			c.baseURL = cached
+			c.resolvedMu.Unlock()
+			c.resolveMu.Unlock()
+			slog.Info("1xbet: using persisted mirror", "mirror_url", c.mirrorURL, "resolved_base", cached, "resolved_at", resolvedAt)
+			return nil
+		}
+	}
+
 	c.resolving = true
 	c.resolveMu.Unlock()
 
-	resolved, err := resolveMirror(c.mirrorURL, c.resolveTimeout)
+	start := time.Now()
+	resolved, err := resolveMirror(c.mirrorURL, c.resolveTimeout, c.fp.UserAgent())
 
 	c.resolveMu.Lock()
 	c.resolving = false
@@ -325,6 +341,7 @@ func (c *Client) ensureResolved() error {
 	}()
 
 	if err != nil {
+		mirrors.Failed(mirrorName, c.mirrorURL, err, time.Since(start))
 		if hasResolved {
 			slog.Warn("1xbet: mirror re-resolve failed, keeping cached URL", "mirror_url", c.mirrorURL, "error", err, "cached_url", resolvedURL)
 			return nil
@@ -342,6 +359,7 @@ func (c *Client) ensureResolved() error {
 	}
 
 	base := normalizeResolvedBaseURL(resolved)
+	mirrors.Resolved(mirrorName, c.mirrorURL, base, nil, time.Since(start))
 	c.resolvedMu.Lock()
 	c.resolvedURL = base
 	c.lastResolveTime = time.Now()
@@ -358,7 +376,7 @@ func (c *Client) checkURLHealth(urlStr string) bool {
 	if err != nil {
 		return false
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	c.fp.Apply(req)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -379,10 +397,36 @@ func (c *Client) clearResolvedURL() {
 	defer c.resolvedMu.Unlock()
 	if c.resolvedURL != "" {
 		slog.Debug("1xbet: Clearing cached URL to force re-resolution", "url", c.resolvedURL)
+		if !c.usingOverride {
+			mirrors.Invalidate(mirrorName, "requests to the mirror failed")
+		}
 		c.resolvedURL = ""
 	}
 }
 
+// applyOverride switches to the manual mirror override (POST /mirrors/override) while one is set.
+// Returns false when there is no override; a just-cleared override is dropped so the mirror resolves again.
+func (c *Client) applyOverride() bool {
+	override := mirrors.Override(mirrorName)
+	c.resolvedMu.Lock()
+	defer c.resolvedMu.Unlock()
+	if override == "" {
+		if c.usingOverride {
+			c.usingOverride = false
+			c.resolvedURL = ""
+		}
+		return false
+	}
+	if !c.usingOverride || c.resolvedURL != override {
+		slog.Info("1xbet: using mirror override", "mirror_url", c.mirrorURL, "override", override)
+	}
+	c.usingOverride = true
+	c.resolvedURL = override
+	c.baseURL = override
+	c.lastResolveTime = time.Now()
+	return true
+}
+
 // getResolvedBaseURL returns the resolved base URL (from mirror or direct)
 func (c *Client) getResolvedBaseURL() string {
 	if err := c.ensureResolved(); err != nil {
@@ -544,8 +588,12 @@ func (c *Client) GetSubGame(subGameID int64) (*GameDetails, error) {
 // doRequest performs HTTP GET request with proper headers
 // If proxyList is configured, tries proxies in order before falling back to direct connection.
 func (c *Client) doRequest(urlStr string) ([]byte, error) {
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+
 	// If proxyList is configured, try proxies first
-	if len(c.proxyList) > 0 {
+	if c.proxies.Len() > 0 {
 		return c.doRequestWithProxyRetry(urlStr)
 	}
 	
@@ -585,40 +633,15 @@ func (c *Client) doRequestDirect(urlStr string) ([]byte, error) {
 	return readBodyDecode(resp)
 }
 
-// doRequestWithProxyRetry tries each proxy in the list until one works
+// doRequestWithProxyRetry tries proxies from the pool until one works
 func (c *Client) doRequestWithProxyRetry(urlStr string) ([]byte, error) {
-	// Try each proxy in the list
-	c.proxyMu.Lock()
-	startIndex := c.currentProxyIndex
-	c.proxyMu.Unlock()
-
-	for attempt := 0; attempt < len(c.proxyList); attempt++ {
-		c.proxyMu.Lock()
-		proxyIndex := (startIndex + attempt) % len(c.proxyList)
-		proxyURLStr := c.proxyList[proxyIndex]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
-
-		// Create transport with this proxy
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		transport.DisableCompression = true // we send Accept-Encoding and decode in readBodyDecode
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		}
-		insecureTLS := os.Getenv("1XBET_INSECURE_TLS") == "1"
-		if insecureTLS {
-			transport.TLSClientConfig.InsecureSkipVerify = true
-		}
-		transport.Proxy = http.ProxyURL(proxyURL)
-
-		client := &http.Client{
-			Timeout:   c.httpClient.Timeout,
-			Transport: transport,
-		}
+	opts := proxypool.TransportOptions{
+		InsecureTLS:        os.Getenv("1XBET_INSECURE_TLS") == "1",
+		DisableCompression: true, // we send Accept-Encoding and decode in readBodyDecode
+		Parser:             "1xbet",
+	}
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.httpClient.Timeout, opts)
 
 		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, urlStr, nil)
 		if err != nil {
@@ -627,8 +650,10 @@ func (c *Client) doRequestWithProxyRetry(urlStr string) ([]byte, error) {
 
 		c.setHeaders(req)
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 
@@ -643,15 +668,8 @@ func (c *Client) doRequestWithProxyRetry(urlStr string) ([]byte, error) {
 
 		if resp.StatusCode == http.StatusOK && (strings.Contains(contentType, "application/json") || isJSON) && !isHTML {
 			// Success! Create a new reader that includes the peeked bytes
-			bodyReader := io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body)
-
-			// Create a new response with the combined body
-			resp.Body = io.NopCloser(bodyReader)
-
-			// Update current proxy index
-			c.proxyMu.Lock()
-			c.currentProxyIndex = proxyIndex
-			c.proxyMu.Unlock()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyPeek[:n]), resp.Body))
+			c.proxies.Success(px, "", time.Since(start))
 
 			body, err := readBodyDecode(resp)
 			resp.Body.Close()
@@ -661,18 +679,14 @@ func (c *Client) doRequestWithProxyRetry(urlStr string) ([]byte, error) {
 			return body, nil
 		}
 
-		// Not valid JSON or error status - read and close body
+		// Not valid JSON or error status - read and close body, try next proxy
 		io.ReadAll(resp.Body)
 		resp.Body.Close()
-
-		// If status indicates server error, try next proxy
-		if resp.StatusCode == 502 || resp.StatusCode == 503 {
-			continue
-		}
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d, content-type %q", resp.StatusCode, contentType))
 	}
 
 	// All proxies failed, try direct connection as last resort
-	slog.Error("1xbet: no proxy available (all proxies failed)", "url", urlStr, "total_proxies_tried", len(c.proxyList))
+	slog.Error("1xbet: no proxy available (all proxies failed)", "url", urlStr, "total_proxies", c.proxies.Len())
 	return c.doRequestDirect(urlStr)
 }
 
@@ -682,7 +696,6 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Accept-Language", "ru,en;q=0.9")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36")
 	if baseURL != "" {
 		req.Header.Set("Referer", baseURL+"/ru/line")
 		req.Header.Set("Origin", baseURL)
@@ -691,9 +704,6 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("is-srv", "false")
 	req.Header.Set("priority", "u=1, i")
-	req.Header.Set("sec-ch-ua", `"Chromium";v="142", "YaBrowser";v="25.12", "Not_A Brand";v="99", "Yowser";v="2.5"`)
-	req.Header.Set("sec-ch-ua-mobile", "?0")
-	req.Header.Set("sec-ch-ua-platform", `"macOS"`)
 	req.Header.Set("sec-fetch-dest", "empty")
 	req.Header.Set("sec-fetch-mode", "cors")
 	req.Header.Set("sec-fetch-site", "same-origin")
@@ -702,21 +712,10 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("x-svc-source", "__BETTING_APP__")
 	req.Header.Set("x-requested-with", "XMLHttpRequest")
 	req.Header.Set("x-mobile-project-id", "0")
+	// User-Agent and matching sec-ch-ua headers
+	c.fp.Apply(req)
 }
 
-// maskProxyURL masks password in proxy URL for logging
-func maskProxyURL(proxyURL string) string {
-	parsed, err := url.Parse(proxyURL)
-	if err != nil {
-		return "***"
-	}
-	if parsed.User != nil {
-		username := parsed.User.Username()
-		parsed.User = url.User(username)
-		return parsed.String()
-	}
-	return proxyURL
-}
 
 // readBodyDecode reads response body and decompresses it based on Content-Encoding (gzip, br, zstd).
 func readBodyDecode(resp *http.Response) ([]byte, error) {
@@ -2,6 +2,7 @@ package xbet1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -9,9 +10,13 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 var runOnceMu sync.Mutex
@@ -20,6 +25,7 @@ type Parser struct {
 	cfg     *config.Config
 	client  *Client
 	storage interface{} // No external storage - data served from memory
+	leagues *parserutil.LeagueFilter // parser.xbet1.leagues
 	
 	// Incremental parsing state
 	incState *parserutil.IncrementalParserState
@@ -42,18 +48,26 @@ func NewParser(cfg *config.Config) *Parser {
 		slog.Info("1xbet: using mirror (resolve at runtime)", "mirror_url", mirrorURL)
 	}
 
-	client := NewClient(baseURL, mirrorURL, cfg.Parser.Timeout, cfg.Parser.Xbet1.ProxyList)
+	limiter := ratelimit.ForParser("xbet1", cfg.Parser.Xbet1.RateLimit, config.RateLimitConfig{})
+	client := NewClient(baseURL, mirrorURL, cfg.Parser.Timeout, cfg.Parser.Xbet1.ProxyList, limiter,
+		fingerprint.ForParser("xbet1", cfg.Parser.Fingerprint, cfg.Parser.Xbet1.Fingerprint, DefaultFingerprint))
 	slog.Info("1xbet: parser init", "base_url", baseURL, "mirror_url", mirrorURL)
 
 	return &Parser{
 		cfg:     cfg,
 		client:  client,
 		storage: nil,
+		leagues: parserutil.NewLeagueFilter("1xbet", cfg.Parser.Xbet1.Leagues),
 	}
 }
 
 // runOnce performs a single parsing run
 func (p *Parser) runOnce(ctx context.Context) error {
+	return p.runSportIDs(ctx, p.getSportIDsToProcess())
+}
+
+// runSportIDs performs a single parsing run over the given sport IDs
+func (p *Parser) runSportIDs(ctx context.Context, sportIDs []int) error {
 	runOnceMu.Lock()
 	defer runOnceMu.Unlock()
 	start := time.Now()
@@ -69,19 +83,25 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		}
 	}
 
-	sportIDs := p.getSportIDsToProcess()
 	slog.Info("1xbet: runOnce started", "include_prematch", p.cfg.Parser.Xbet1.IncludePrematch, "sport_ids", sportIDs)
 
+	limits := parserutil.NewCycleLimits("1xbet", p.cfg.Parser.Xbet1.MaxEventsPerCycle, p.cfg.Parser.Xbet1.MaxLeagues)
+	defer limits.LogSummary()
+
+	// On shutdown drain the championships in progress finish and are stored, no new one starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
+
 	// Process pre-match matches (по каждому sport_id из списка)
 	if p.cfg.Parser.Xbet1.IncludePrematch {
 		for _, sportID := range sportIDs {
 			select {
-			case <-ctx.Done():
+			case <-leaguesCtx.Done():
 				return nil
 			default:
 			}
 			slog.Info("1xbet: starting pre-match matches processing", "sport_id", sportID)
-			matches, err := p.processLeaguesFlowWithSportID(ctx, sportID)
+			matches, err := p.processLeaguesFlowWithSportID(ctx, sportID, limits)
 			if err != nil {
 				if ctx.Err() != nil {
 					slog.Warn("1xbet: pre-match matches processing stopped (time limit or context canceled)", "error", err)
@@ -90,14 +110,10 @@ func (p *Parser) runOnce(ctx context.Context) error {
 				}
 				continue
 			}
-			for _, match := range matches {
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-				}
-				health.AddMatch(match)
+			if ctx.Err() != nil {
+				return nil
 			}
+			health.AddMatches(matches)
 			totalMatches += len(matches)
 			slog.Info("1xbet: pre-match matches processed", "sport_id", sportID, "count", len(matches))
 		}
@@ -109,6 +125,40 @@ func (p *Parser) runOnce(ctx context.Context) error {
 	return nil
 }
 
+// champNames returns the names matched by parser.xbet1.leagues (Russian and English).
+func champNames(c ChampItem) []string {
+	return []string{c.L, c.LE}
+}
+
+// esportsSportID is the 1xbet sport ID of all esports disciplines (discipline is detected from the league).
+const esportsSportID = 40
+
+// sportIDFor maps an enums.Sport value to its 1xbet sport ID.
+func sportIDFor(sport string) (int, bool) {
+	switch s := enums.Sport(sport); {
+	case s == enums.Football:
+		return 1, true
+	case s.IsEsports():
+		return esportsSportID, true
+	}
+	return 0, false
+}
+
+// ParseSport runs a single parsing pass for one sport (parser.sports polls each sport on its own interval).
+// All esports disciplines share sport_id=40, so polling "dota2" also refreshes CS matches.
+func (p *Parser) ParseSport(ctx context.Context, sport string) error {
+	sportID, ok := sportIDFor(sport)
+	if !ok {
+		return fmt.Errorf("unsupported sport %q", sport)
+	}
+	for _, id := range p.getSportIDsToProcess() {
+		if id == sportID {
+			return p.runSportIDs(ctx, []int{sportID})
+		}
+	}
+	return fmt.Errorf("sport %q (sport_id=%d) is not in parser.xbet1.sport_ids", sport, sportID)
+}
+
 // getSportIDsToProcess returns list of sport IDs to parse (SportIDs if set, else [SportID] or [1])
 func (p *Parser) getSportIDsToProcess() []int {
 	if len(p.cfg.Parser.Xbet1.SportIDs) > 0 {
@@ -122,7 +172,7 @@ func (p *Parser) getSportIDsToProcess() []int {
 }
 
 // processLeaguesFlowWithSportID processes all leagues for one sport and returns matches
-func (p *Parser) processLeaguesFlowWithSportID(ctx context.Context, sportID int) ([]*models.Match, error) {
+func (p *Parser) processLeaguesFlowWithSportID(ctx context.Context, sportID int, limits *parserutil.CycleLimits) ([]*models.Match, error) {
 	countryID := p.cfg.Parser.Xbet1.CountryID
 	if countryID == 0 {
 		countryID = 1
@@ -145,15 +195,19 @@ func (p *Parser) processLeaguesFlowWithSportID(ctx context.Context, sportID int)
 		}
 	}
 	slog.Info("1xbet: filtering championships with matches", "total", len(champs), "with_matches", len(champsWithMatches))
+	champsWithMatches = parserutil.FilterLeagues(p.leagues, champsWithMatches, champNames)
+	champsWithMatches = parserutil.LimitLeagues(limits, champsWithMatches)
 
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 	var allMatches []*models.Match
 	for _, champ := range champsWithMatches {
 		select {
-		case <-ctx.Done():
+		case <-leaguesCtx.Done():
 			return allMatches, ctx.Err()
 		default:
 		}
-		matches := p.processSingleChampionship(ctx, champ)
+		matches := p.processSingleChampionship(ctx, champ, limits)
 		allMatches = append(allMatches, matches...)
 	}
 	slog.Info("1xbet: leagues flow finished", "sport_id", sportID, "matches", len(allMatches))
@@ -222,8 +276,28 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("1xbet")
 }
 
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("1xbet")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("1xbet")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
 // runIncrementalCycle runs one full parsing cycle incrementally (by leagues)
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	start := time.Now()
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("1xbet", cycleID, timeout)
@@ -249,24 +323,33 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 	// Process pre-match matches incrementally
 	if p.cfg.Parser.Xbet1.IncludePrematch {
 		slog.Info("1xbet: starting pre-match incremental processing", "cycle_id", cycleID)
-		p.processLeaguesFlowIncremental(cycleCtx)
+		n, err := p.processLeaguesFlowIncremental(cycleCtx)
 		slog.Info("1xbet: pre-match incremental processing completed", "cycle_id", cycleID)
+		return n, err
 	}
+	return 0, nil
 }
 
 // processLeaguesFlowIncremental processes leagues incrementally, updating storage after each league (all sport_ids)
-func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
+func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) (int, error) {
 	sportIDs := p.getSportIDsToProcess()
 	countryID := p.cfg.Parser.Xbet1.CountryID
 	if countryID == 0 {
 		countryID = 1
 	}
 	virtualSports := p.cfg.Parser.Xbet1.VirtualSports
+	var cycleTotal int
+	var errs []error
+	limits := parserutil.NewCycleLimits("1xbet", p.cfg.Parser.Xbet1.MaxEventsPerCycle, p.cfg.Parser.Xbet1.MaxLeagues)
+	defer limits.LogSummary()
+	// On shutdown drain the championships in progress finish and are stored, no new one starts
+	leaguesCtx, stopLeagues := parserutil.DrainContext(ctx)
+	defer stopLeagues()
 
 	for _, sportID := range sportIDs {
 		select {
-		case <-ctx.Done():
-			return
+		case <-leaguesCtx.Done():
+			return cycleTotal, errors.Join(errs...)
 		default:
 		}
 		if sportID == 40 {
@@ -277,6 +360,7 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 		champs, err := p.client.GetChamps(sportID, countryID, virtualSports)
 		if err != nil {
 			slog.Error("1xbet: failed to get championships", "sport_id", sportID, "error", err)
+			errs = append(errs, fmt.Errorf("sport %d: get championships: %w", sportID, err))
 			if sportID == 40 {
 				slog.Warn("1xbet: esports (sport_id=40) GetChamps failed — no esports from xbet", "error", err)
 			}
@@ -297,6 +381,8 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 			}
 		}
 		slog.Info("1xbet: filtering championships with matches", "sport_id", sportID, "total", len(champs), "with_matches", len(champsWithMatches))
+		champsWithMatches = parserutil.FilterLeagues(p.leagues, champsWithMatches, champNames)
+		champsWithMatches = parserutil.LimitLeagues(limits, champsWithMatches)
 
 		totalChamps := len(champsWithMatches)
 		maxConcurrentChamps := p.cfg.Parser.Xbet1.MaxConcurrentChampionships
@@ -309,9 +395,9 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 			// Sequential (original behaviour)
 			for idx, champ := range champsWithMatches {
 				select {
-				case <-ctx.Done():
+				case <-leaguesCtx.Done():
 					slog.Warn("1xbet: incremental processing interrupted", "champs_processed", idx, "champs_total", totalChamps)
-					return
+					return cycleTotal + int(matchesTotal), errors.Join(errs...)
 				default:
 				}
 				champIdx := idx + 1
@@ -321,10 +407,8 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 					"championship_id", champ.LI,
 					"progress", fmt.Sprintf("%d/%d", champIdx, totalChamps),
 					"percent", fmt.Sprintf("%.1f%%", float64(champIdx)/float64(totalChamps)*100))
-				matches := p.processSingleChampionship(ctx, champ)
-				for _, match := range matches {
-					health.AddMatch(match)
-				}
+				matches := p.processSingleChampionship(ctx, champ, limits)
+				health.AddMatches(matches)
 				slog.Debug("1xbet: matches saved to store", "championship", champ.LE, "matches_count", len(matches))
 				matchesTotal += int64(len(matches))
 				champDuration := time.Since(champStart)
@@ -350,7 +434,7 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 				go func() {
 					defer wg.Done()
 					for champ := range ch {
-						if ctx.Err() != nil {
+						if leaguesCtx.Err() != nil {
 							return
 						}
 						champStart := time.Now()
@@ -358,10 +442,8 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 							"championship", champ.LE,
 							"championship_id", champ.LI,
 							"progress", fmt.Sprintf("…/%d", totalChamps))
-						matches := p.processSingleChampionship(ctx, champ)
-						for _, match := range matches {
-							health.AddMatch(match)
-						}
+						matches := p.processSingleChampionship(ctx, champ, limits)
+						health.AddMatches(matches)
 						slog.Debug("1xbet: matches saved to store", "championship", champ.LE, "matches_count", len(matches))
 						done := completed.Add(1)
 						total := atomic.AddInt64(&matchesTotal, int64(len(matches)))
@@ -378,6 +460,7 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 			}
 			wg.Wait()
 		}
+		cycleTotal += int(matchesTotal)
 
 		slog.Info("1xbet: incremental leagues flow finished for sport",
 			"sport_id", sportID,
@@ -387,11 +470,16 @@ func (p *Parser) processLeaguesFlowIncremental(ctx context.Context) {
 			slog.Info("1xbet: esports (sport_id=40) flow finished", "championships", len(champsWithMatches), "football_matches_in_run", matchesTotal)
 		}
 	}
+	return cycleTotal, errors.Join(errs...)
 }
 
-// processSingleChampionship processes a single championship and returns matches
-func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem) []*models.Match {
+// processSingleChampionship processes a single championship and returns matches.
+// Matches beyond the cycle's max_events_per_cycle budget are not fetched.
+func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem, limits *parserutil.CycleLimits) []*models.Match {
 	var matches []*models.Match
+	if limits.Exhausted() {
+		return matches
+	}
 	champStart := time.Now()
 	
 	slog.Debug("1xbet: fetching championship matches", "championship", champ.LE, "championship_id", champ.LI)
@@ -421,6 +509,7 @@ func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem)
 	}
 
 	slog.Info("1xbet: fetched championship matches", "championship", champ.LE, "sport_id", sportID, "matches_count", len(matchList))
+	matchList = parserutil.LimitEvents(limits, champ.LE, matchList)
 
 	maxConcurrentGames := p.cfg.Parser.Xbet1.MaxConcurrentGamesPerChamp
 	if maxConcurrentGames <= 0 {
@@ -450,8 +539,9 @@ func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem)
 				continue
 			}
 			if sportID == 40 {
-				lineMatch := BuildLineMatchFromGameDetails(gameDetails, champ.LE, "esports", "1xbet")
+				lineMatch := BuildLineMatchFromGameDetails(gameDetails, champ.LE, "", "1xbet")
 				if lineMatch != nil {
+					p.addMapWinnerMarkets(lineMatch, gameDetails)
 					em := lineMatch.ToEsportsMatch()
 					if em != nil {
 						health.AddEsportsMatch(em)
@@ -497,8 +587,9 @@ func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem)
 					return
 				}
 				if sportID == 40 {
-					lineMatch := BuildLineMatchFromGameDetails(gameDetails, champ.LE, "esports", "1xbet")
+					lineMatch := BuildLineMatchFromGameDetails(gameDetails, champ.LE, "", "1xbet")
 					if lineMatch != nil {
+						p.addMapWinnerMarkets(lineMatch, gameDetails)
 						em := lineMatch.ToEsportsMatch()
 						if em != nil {
 							health.AddEsportsMatch(em)
@@ -539,5 +630,5 @@ func (p *Parser) processSingleChampionship(ctx context.Context, champ ChampItem)
 // processLeaguesFlow processes all leagues for the first configured sport (backward compatibility).
 func (p *Parser) processLeaguesFlow(ctx context.Context) ([]*models.Match, error) {
 	ids := p.getSportIDsToProcess()
-	return p.processLeaguesFlowWithSportID(ctx, ids[0])
+	return p.processLeaguesFlowWithSportID(ctx, ids[0], nil)
 }
@@ -9,8 +9,13 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const (
@@ -25,12 +30,13 @@ type Client struct {
 	frontVersion string
 	sportID      int
 	httpClient   *http.Client
-	proxyList    []string
-	proxyIndex   int
-	proxyMu      sync.Mutex
+	proxies      *proxypool.Pool
+	limiter      *ratelimit.Limiter
+	fp           *fingerprint.Profile
 }
 
-func NewClient(baseURL, imprintHash, frontVersion string, sportID int, timeout time.Duration, proxyList []string) *Client {
+// NewClient creates a Zenit line client. limiter may be nil (no rate limiting); fp may be nil (built-in User-Agent pool).
+func NewClient(baseURL, imprintHash, frontVersion string, sportID int, timeout time.Duration, proxyList []string, limiter *ratelimit.Limiter, fp *fingerprint.Profile) *Client {
 	if baseURL == "" {
 		baseURL = "https://zenitnow549.top"
 	}
@@ -43,14 +49,19 @@ func NewClient(baseURL, imprintHash, frontVersion string, sportID int, timeout t
 	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
+	if fp == nil {
+		fp = fingerprint.New("zenit", config.FingerprintConfig{})
+	}
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	client := &Client{
 		baseURL:      strings.TrimSuffix(baseURL, "/"),
 		imprintHash:  imprintHash,
 		frontVersion: frontVersion,
 		sportID:      sportID,
-		httpClient:   &http.Client{Timeout: timeout, Transport: transport},
-		proxyList:    proxyList,
+		httpClient:   &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser(bookmakerName, transport)},
+		proxies:      proxypool.New("zenit", proxyList),
+		limiter:      limiter,
+		fp:           fp,
 	}
 	return client
 }
@@ -131,13 +142,16 @@ func (c *Client) GetMatch(ctx context.Context, tournamentRegion, tournament, lea
 }
 
 func (c *Client) doRequest(ctx context.Context, rawURL, referer string) ([]byte, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req, referer)
 
-	if len(c.proxyList) > 0 {
+	if c.proxies.Len() > 0 {
 		return c.doRequestWithProxies(ctx, req, referer)
 	}
 
@@ -154,38 +168,32 @@ func (c *Client) doRequest(ctx context.Context, rawURL, referer string) ([]byte,
 }
 
 func (c *Client) doRequestWithProxies(ctx context.Context, req *http.Request, referer string) ([]byte, error) {
-	for i := 0; i < len(c.proxyList); i++ {
-		c.proxyMu.Lock()
-		idx := (c.proxyIndex + i) % len(c.proxyList)
-		proxyURLStr := c.proxyList[idx]
-		c.proxyMu.Unlock()
-
-		proxyURL, err := url.Parse(proxyURLStr)
-		if err != nil {
-			continue
-		}
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		transport.Proxy = http.ProxyURL(proxyURL)
-		client := &http.Client{Timeout: c.httpClient.Timeout, Transport: transport}
+	for _, px := range c.proxies.Candidates("") {
+		client := px.Client(c.httpClient.Timeout, proxypool.TransportOptions{Parser: bookmakerName})
 
 		r2, _ := http.NewRequestWithContext(ctx, req.Method, req.URL.String(), nil)
 		c.setHeaders(r2, referer)
 
+		start := time.Now()
 		resp, err := client.Do(r2)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
+			c.proxies.Failure(px, "", err.Error())
 			continue
 		}
 		if resp.StatusCode == http.StatusOK {
-			c.proxyMu.Lock()
-			c.proxyIndex = idx
-			c.proxyMu.Unlock()
+			c.proxies.Success(px, "", time.Since(start))
 			return body, nil
 		}
+		c.proxies.Failure(px, "", fmt.Sprintf("status %d", resp.StatusCode))
 	}
 	return c.doRequestDirect(ctx, req, referer)
 }
@@ -208,8 +216,8 @@ func (c *Client) doRequestDirect(ctx context.Context, req *http.Request, referer
 
 func (c *Client) setHeaders(req *http.Request, referer string) {
 	req.Header.Set("Accept", "application/json, text/plain, */*")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 	req.Header.Set("Referer", referer)
 	req.Header.Set("imprinthash", c.imprintHash)
 	req.Header.Set("frontversion", c.frontVersion)
+	c.fp.Apply(req)
 }
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/fingerprint"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/parserutil"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
 )
 
 const delayPerMatch = 300 * time.Millisecond
@@ -31,17 +34,19 @@ func NewParser(cfg *config.Config) *Parser {
 	if timeout <= 0 {
 		timeout = 30 * time.Second
 	}
-	client := NewClient(z.BaseURL, z.ImprintHash, z.FrontVersion, z.SportID, timeout, z.ProxyList)
+	client := NewClient(z.BaseURL, z.ImprintHash, z.FrontVersion, z.SportID, timeout, z.ProxyList,
+		ratelimit.ForParser("zenit", z.RateLimit, config.RateLimitConfig{}),
+		fingerprint.ForParser("zenit", cfg.Parser.Fingerprint, z.Fingerprint, config.FingerprintConfig{}))
 	return &Parser{
 		cfg:    cfg,
 		client: client,
 	}
 }
 
-func (p *Parser) runOnce(ctx context.Context) error {
+func (p *Parser) runOnce(ctx context.Context) (int, error) {
 	if p.cfg.Parser.Zenit.ImprintHash == "" {
 		slog.Warn("zenit: imprint_hash not set, skipping (set parser.zenit.imprint_hash from browser DevTools)")
-		return nil
+		return 0, nil
 	}
 	runOnceMu.Lock()
 	defer runOnceMu.Unlock()
@@ -52,24 +57,33 @@ func (p *Parser) runOnce(ctx context.Context) error {
 	}()
 
 	slog.Info("zenit: runOnce started")
+	limits := parserutil.NewCycleLimits("zenit", p.cfg.Parser.Zenit.MaxEventsPerCycle, p.cfg.Parser.Zenit.MaxLeagues)
+	defer limits.LogSummary()
+
+	// On shutdown drain the page in progress finishes, no new page is fetched
+	pagesCtx, stopPages := parserutil.DrainContext(ctx)
+	defer stopPages()
 
 	offset := 0
 	for {
 		select {
-		case <-ctx.Done():
-			return nil
+		case <-pagesCtx.Done():
+			return totalMatches, nil
 		default:
 		}
 
 		page, err := p.client.GetLinePage(ctx, offset)
 		if err != nil {
-			return fmt.Errorf("get line page offset %d: %w", offset, err)
+			return totalMatches, fmt.Errorf("get line page offset %d: %w", offset, err)
 		}
 
 		// Collect (gameID, lid, rid, tid) from league -> games
 		var gameIDs []gameRef
-		for _, league := range page.League {
-			for _, gid := range league.Games {
+		for key, league := range page.League {
+			if limits.Leagues(1) == 0 {
+				continue
+			}
+			for _, gid := range parserutil.LimitEvents(limits, key, league.Games) {
 				gameIDs = append(gameIDs, gameRef{
 					gameID: gid,
 					lid:    league.ID,
@@ -80,6 +94,7 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		}
 
 		if len(gameIDs) == 0 {
+			// Also reached once max_leagues / max_events_per_cycle is used up
 			break
 		}
 
@@ -88,7 +103,7 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		for _, ref := range gameIDs {
 			select {
 			case <-ctx.Done():
-				return nil
+				return totalMatches, nil
 			default:
 			}
 
@@ -115,7 +130,7 @@ func (p *Parser) runOnce(ctx context.Context) error {
 		offset += 50
 	}
 
-	return nil
+	return totalMatches, nil
 }
 
 type gameRef struct {
@@ -127,7 +142,7 @@ type gameRef struct {
 
 func (p *Parser) Start(ctx context.Context) error {
 	slog.Info("Starting Zenit parser (background mode)...")
-	if err := p.runOnce(ctx); err != nil {
+	if _, err := p.runOnce(ctx); err != nil {
 		return err
 	}
 	<-ctx.Done()
@@ -135,7 +150,8 @@ func (p *Parser) Start(ctx context.Context) error {
 }
 
 func (p *Parser) ParseOnce(ctx context.Context) error {
-	return p.runOnce(ctx)
+	_, err := p.runOnce(ctx)
+	return err
 }
 
 func (p *Parser) Stop() error {
@@ -170,7 +186,27 @@ func (p *Parser) TriggerNewCycle() error {
 	return p.incState.TriggerNewCycle("zenit")
 }
 
-func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) {
+// LastCycle returns the summary of the last finished incremental cycle.
+func (p *Parser) LastCycle() interfaces.CycleSummary {
+	return p.incState.LastCycle()
+}
+
+// Pause stops new incremental cycles until Resume; the running cycle finishes normally.
+func (p *Parser) Pause() error {
+	return p.incState.Pause("zenit")
+}
+
+// Resume continues incremental parsing and starts a new cycle immediately.
+func (p *Parser) Resume() error {
+	return p.incState.Resume("zenit")
+}
+
+// IsPaused reports whether incremental parsing is paused.
+func (p *Parser) IsPaused() bool {
+	return p.incState.IsPaused()
+}
+
+func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration) (int, error) {
 	cycleID := time.Now().Unix()
 	parserutil.LogCycleStart("zenit", cycleID, timeout)
 	cycleCtx, cancel := parserutil.CreateCycleContext(ctx, timeout)
@@ -178,5 +214,5 @@ func (p *Parser) runIncrementalCycle(ctx context.Context, timeout time.Duration)
 	start := time.Now()
 	defer func() { parserutil.LogCycleFinish("zenit", cycleID, time.Since(start)) }()
 
-	_ = p.runOnce(cycleCtx)
+	return p.runOnce(cycleCtx)
 }
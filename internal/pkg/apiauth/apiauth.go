@@ -0,0 +1,334 @@
+// Package apiauth guards HTTP APIs with API keys and roles (api_auth). A request carries its key as
+// X-API-Key or Authorization: Bearer <key>; the role of the key must cover the request:
+//
+//   - read: GET, HEAD and OPTIONS requests
+//   - operator: also the other methods (async controls, ignores, subscriptions, bets) and the parser
+//     controls (/parse, /parsers/pause, /parsers/resume, /mirrors/override, /mirrors/invalidate)
+//   - admin: also /admin/*, /db/clear and /debug/*
+//
+// Disabled unless api_auth.enabled is set. The calculator, the parser and the bookmaker services wrap
+// their muxes with Middleware (the calculator gRPC API uses UnaryServerInterceptor); Configure decides
+// whether this service checks keys.
+package apiauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Role is the access level of an API key.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleRead
+	RoleOperator
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleRead:
+		return "read"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+// ParseRole parses read, operator or admin.
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "read", "readonly", "read-only":
+		return RoleRead, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	}
+	return RoleNone, fmt.Errorf("unknown api role %q (want read, operator or admin)", s)
+}
+
+var (
+	defaultServices    = []string{"calculator", "parser", "bookmaker-service"}
+	defaultPublicPaths = []string{"/ping", "/health", "/readyz", "/metrics", "/webapp/"}
+	// adminPaths need RoleAdmin whatever the method; /api/v1 is stripped before matching.
+	// /debug/ covers pprof (profiles, cmdline) and /debug/runtime.
+	adminPaths = []string{"/admin/", "/db/clear", "/debug/"}
+	// operatorPaths need RoleOperator whatever the method (exact paths): the parser controls.
+	operatorPaths = []string{"/parse", "/parsers/pause", "/parsers/resume", "/mirrors/override", "/mirrors/invalidate"}
+)
+
+// keysEnv adds keys to api_auth.keys: name:role:key, comma-separated.
+const keysEnv = "API_KEYS"
+
+type apiKey struct {
+	name string
+	key  []byte
+	role Role
+}
+
+type guard struct {
+	keys        []apiKey
+	publicPaths []string
+}
+
+// current is nil while the service doesn't check keys.
+var current atomic.Pointer[guard]
+
+// Configure enables key checks for service when api_auth.enabled is set and service matches one of
+// api_auth.services (by prefix). Keys come from api_auth.keys and the API_KEYS env var.
+func Configure(service string, cfg config.APIAuthConfig) error {
+	services := cfg.Services
+	if len(services) == 0 {
+		services = defaultServices
+	}
+	if !cfg.Enabled || !serviceSelected(service, services) {
+		current.Store(nil)
+		return nil
+	}
+
+	keys := cfg.Keys
+	if env := os.Getenv(keysEnv); env != "" {
+		fromEnv, err := ParseKeys(env)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keysEnv, err)
+		}
+		keys = append(append([]config.APIKeyConfig(nil), keys...), fromEnv...)
+	}
+	g := &guard{publicPaths: cfg.PublicPaths}
+	if len(g.publicPaths) == 0 {
+		g.publicPaths = defaultPublicPaths
+	}
+	for i, k := range keys {
+		role, err := ParseRole(k.Role)
+		if err != nil {
+			return fmt.Errorf("api_auth.keys[%d] (%s): %w", i, k.Name, err)
+		}
+		if strings.TrimSpace(k.Key) == "" {
+			return fmt.Errorf("api_auth.keys[%d] (%s): key is empty", i, k.Name)
+		}
+		g.keys = append(g.keys, apiKey{name: k.Name, key: []byte(strings.TrimSpace(k.Key)), role: role})
+	}
+	if len(g.keys) == 0 {
+		return fmt.Errorf("api_auth is enabled but has no keys (api_auth.keys or %s)", keysEnv)
+	}
+	current.Store(g)
+	slog.Info("API key authentication enabled", "service", service, "keys", len(g.keys), "public_paths", g.publicPaths)
+	return nil
+}
+
+// ParseKeys parses name:role:key entries separated by commas (the key itself may contain colons).
+func ParseKeys(s string) ([]config.APIKeyConfig, error) {
+	var keys []config.APIKeyConfig
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid key entry %q, want name:role:key", parts[0])
+		}
+		keys = append(keys, config.APIKeyConfig{Name: parts[0], Role: parts[1], Key: parts[2]})
+	}
+	return keys, nil
+}
+
+func serviceSelected(service string, services []string) bool {
+	for _, s := range services {
+		if s = strings.TrimSpace(s); s != "" && strings.HasPrefix(service, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredRole returns the role a request needs.
+func RequiredRole(r *http.Request) Role {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	for _, p := range adminPaths {
+		if strings.HasPrefix(path, p) {
+			return RoleAdmin
+		}
+	}
+	for _, p := range operatorPaths {
+		if strings.TrimSuffix(path, "/") == p {
+			return RoleOperator
+		}
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleRead
+	}
+	return RoleOperator
+}
+
+// Middleware wraps next with the key check: 401 without a known key, 403 when its role is too low.
+// The configuration is read per request, so the order of Configure and Middleware calls doesn't matter.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g := current.Load()
+		if g == nil || r.Method == http.MethodOptions || g.public(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		k := g.lookup(requestKey(r))
+		if k == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="vodeneevbet"`)
+			writeError(w, http.StatusUnauthorized, "api key required (X-API-Key or Authorization: Bearer)")
+			return
+		}
+		if need := RequiredRole(r); k.role < need {
+			slog.Warn("API request denied", "key", k.name, "role", k.role, "required", need, "method", r.Method, "path", r.URL.Path)
+			writeError(w, http.StatusForbidden, fmt.Sprintf("role %s required", need))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor applies the key check to gRPC calls (x-api-key or authorization: Bearer metadata).
+// The gRPC APIs are read-only, so any known key will do.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		g := current.Load()
+		if g == nil {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		header := func(name string) string {
+			if v := md.Get(name); len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		}
+		if g.lookup(headerKey(header("x-api-key"), header("authorization"))) == nil {
+			return nil, status.Error(codes.Unauthenticated, "api key required (x-api-key or authorization: Bearer metadata)")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (g *guard) public(path string) bool {
+	for _, p := range g.publicPaths {
+		if p != "" && strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup compares key with every configured key in constant time.
+func (g *guard) lookup(key string) *apiKey {
+	if key == "" {
+		return nil
+	}
+	var found *apiKey
+	for i := range g.keys {
+		if subtle.ConstantTimeCompare(g.keys[i].key, []byte(key)) == 1 {
+			found = &g.keys[i]
+		}
+	}
+	return found
+}
+
+func requestKey(r *http.Request) string {
+	return headerKey(r.Header.Get("X-API-Key"), r.Header.Get("Authorization"))
+}
+
+// headerKey returns the key of an X-API-Key or an Authorization: Bearer header.
+func headerKey(apiKey, auth string) string {
+	if apiKey != "" {
+		return strings.TrimSpace(apiKey)
+	}
+	if len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(auth[len("Bearer "):])
+	}
+	return ""
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// KeyEnv is the env var with the API key a service sends to the others (calculator to parser, telegram-bot
+// and dashboard to both).
+const KeyEnv = "API_KEY"
+
+// keyTransport is the transport installed by UseKey (nil before).
+var keyTransport atomic.Pointer[Transport]
+
+// UseKey makes every client on http.DefaultTransport send key to the services at baseURLs. Call it early in
+// main: clients that wrap the default transport (tracing.Transport) capture it when they are created.
+func UseKey(key string, baseURLs ...string) {
+	if key == "" {
+		return
+	}
+	t := &Transport{Base: http.DefaultTransport, Key: key, BaseURLs: baseURLs}
+	keyTransport.Store(t)
+	http.DefaultTransport = t
+}
+
+// AddKeyTargets makes the transport installed by UseKey send the key to baseURLs too (bookmaker services
+// found by discovery after start). Does nothing without UseKey.
+func AddKeyTargets(baseURLs ...string) {
+	if t := keyTransport.Load(); t != nil {
+		t.mu.Lock()
+		t.BaseURLs = append(t.BaseURLs, baseURLs...)
+		t.mu.Unlock()
+	}
+}
+
+// Transport adds Key to the requests for the services at BaseURLs (scheme://host[:port]), so a client
+// talking to other hosts (Telegram) through the same transport never sends the key there.
+type Transport struct {
+	Base     http.RoundTripper // nil = http.DefaultTransport
+	Key      string
+	BaseURLs []string
+
+	mu sync.RWMutex // guards BaseURLs against AddKeyTargets
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Key == "" || r.Header.Get("X-API-Key") != "" || !t.targets(r) {
+		return base.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("X-API-Key", t.Key)
+	return base.RoundTrip(r)
+}
+
+func (t *Transport) targets(r *http.Request) bool {
+	origin := r.URL.Scheme + "://" + r.URL.Host
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, u := range t.BaseURLs {
+		if u != "" && strings.HasPrefix(strings.TrimSuffix(u, "/")+"/", origin+"/") {
+			return true
+		}
+	}
+	return false
+}
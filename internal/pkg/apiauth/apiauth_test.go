@@ -0,0 +1,155 @@
+package apiauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Setenv(keysEnv, "ops:operator:op-secret")
+	err := Configure("calculator", config.APIAuthConfig{Enabled: true, Keys: []config.APIKeyConfig{
+		{Name: "dashboard", Key: "read-secret", Role: "read"},
+		{Name: "bot", Key: "admin-secret", Role: "admin"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { current.Store(nil) })
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		method, path, header, key string
+		want                      int
+	}{
+		{"GET", "/health", "", "", http.StatusOK},
+		{"GET", "/value-bets/top", "", "", http.StatusUnauthorized},
+		{"GET", "/value-bets/top", "X-API-Key", "wrong", http.StatusUnauthorized},
+		{"GET", "/api/v1/value-bets/top", "X-API-Key", "read-secret", http.StatusOK},
+		{"POST", "/async/stop", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"POST", "/api/v1/async/stop", "Authorization", "Bearer op-secret", http.StatusOK},
+		{"POST", "/db/clear", "Authorization", "Bearer op-secret", http.StatusForbidden},
+		{"GET", "/admin/restart-parser", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/debug/pprof/", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/debug/runtime", "Authorization", "Bearer op-secret", http.StatusForbidden},
+		{"GET", "/debug/pprof/profile", "X-API-Key", "admin-secret", http.StatusOK},
+		{"POST", "/api/v1/db/clear", "X-API-Key", "admin-secret", http.StatusOK},
+		{"GET", "/parse", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/api/v1/parsers/pause", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"HEAD", "/parsers/resume/", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/mirrors/override", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/mirrors/invalidate", "X-API-Key", "read-secret", http.StatusForbidden},
+		{"GET", "/parsers", "X-API-Key", "read-secret", http.StatusOK},
+		{"GET", "/mirrors", "X-API-Key", "read-secret", http.StatusOK},
+		{"POST", "/parse", "X-API-Key", "op-secret", http.StatusOK},
+		{"POST", "/mirrors/invalidate", "X-API-Key", "op-secret", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s %s (%s: %s): status %d, want %d", tc.method, tc.path, tc.header, tc.key, rec.Code, tc.want)
+		}
+	}
+
+	// Bookmaker services are selected by default; services not selected don't check keys
+	if err := Configure("bookmaker-service-fonbet", config.APIAuthConfig{Enabled: true}); err != nil || current.Load() == nil {
+		t.Errorf("bookmaker-service: err %v, guard %v, want keys checked", err, current.Load())
+	}
+	if err := Configure("bookmaker-service-fonbet", config.APIAuthConfig{Enabled: true, Services: []string{"calculator"}}); err != nil || current.Load() != nil {
+		t.Errorf("bookmaker-service not in services: err %v, guard %v", err, current.Load())
+	}
+	if err := Configure("parser", config.APIAuthConfig{Enabled: true, Keys: []config.APIKeyConfig{{Key: "k", Role: "root"}}}); err == nil {
+		t.Error("unknown role: expected an error")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-API-Key"))
+	}))
+	defer srv.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-API-Key"))
+	}))
+	defer other.Close()
+
+	client := &http.Client{Transport: &Transport{Key: "secret", BaseURLs: []string{srv.URL + "/"}}}
+	for _, u := range []string{srv.URL + "/value-bets/top", other.URL + "/bot123/sendMessage"} {
+		resp, err := client.Get(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if len(got) != 2 || got[0] != "secret" || got[1] != "" {
+		t.Errorf("keys sent = %q, want the key to the service only", got)
+	}
+}
+
+func TestAddKeyTargets(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-API-Key"))
+	}))
+	defer srv.Close()
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+		keyTransport.Store(nil)
+	})
+
+	UseKey("secret")
+	client := &http.Client{Transport: http.DefaultTransport}
+	for _, add := range []bool{false, true} {
+		if add {
+			AddKeyTargets(srv.URL)
+		}
+		resp, err := client.Get(srv.URL + "/parse")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if len(got) != 2 || got[0] != "" || got[1] != "secret" {
+		t.Errorf("keys sent = %q, want the key only after the service was added", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	if err := Configure("calculator", config.APIAuthConfig{Enabled: true, Keys: []config.APIKeyConfig{{Name: "bot", Key: "read-secret", Role: "read"}}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { current.Store(nil) })
+	intercept := UnaryServerInterceptor()
+	handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+
+	cases := []struct {
+		md   metadata.MD
+		want codes.Code
+	}{
+		{nil, codes.Unauthenticated},
+		{metadata.Pairs("x-api-key", "wrong"), codes.Unauthenticated},
+		{metadata.Pairs("x-api-key", "read-secret"), codes.OK},
+		{metadata.Pairs("authorization", "Bearer read-secret"), codes.OK},
+	}
+	for _, tc := range cases {
+		ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+		if _, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/x/Y"}, handler); status.Code(err) != tc.want {
+			t.Errorf("%v: %v, want %s", tc.md, err, tc.want)
+		}
+	}
+}
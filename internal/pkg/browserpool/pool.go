@@ -0,0 +1,362 @@
+// Package browserpool is the headless Chrome pool shared by all parsers.
+//
+// Chrome instances are started lazily, up to a bounded number, and reused between jobs: each
+// job (Run) gets a fresh tab of an idle browser and the tab is closed afterwards. Jobs beyond
+// the pool size wait in a queue. A browser is restarted after max_jobs_per_browser jobs or when
+// it breaks, and closed after idle_timeout without jobs, so Chrome does not hold memory between
+// the rare mirror resolutions. Each browser keeps its own profile directory for its lifetime.
+package browserpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+const (
+	DefaultSize              = 1
+	DefaultMaxJobsPerBrowser = 50
+	DefaultIdleTimeout       = 5 * time.Minute
+	DefaultAcquireTimeout    = 2 * time.Minute
+)
+
+// ErrClosed is returned by Run after Close.
+var ErrClosed = errors.New("browser pool closed")
+
+// Stats is a snapshot of the pool metrics (GET /browsers).
+type Stats struct {
+	Size          int     `json:"size"`            // max concurrent browsers
+	Running       int     `json:"running"`         // started browsers (busy + idle)
+	Busy          int     `json:"busy"`            // browsers running a job
+	Queued        int     `json:"queued"`          // jobs waiting for a browser
+	Launched      int64   `json:"launched"`        // browsers started since process start
+	Jobs          int64   `json:"jobs"`            // jobs finished
+	Failures      int64   `json:"failures"`        // jobs that returned an error
+	AvgWaitMs     float64 `json:"avg_wait_ms"`     // average queue wait
+	AvgDurationMs float64 `json:"avg_duration_ms"` // average job duration (without queue wait)
+}
+
+// browser is one running Chrome instance.
+type browser struct {
+	id    int
+	ctx   context.Context // chromedp browser context; tabs are created from it
+	close func()
+	jobs  int
+	idle  *time.Timer
+}
+
+// Pool is a bounded set of reusable Chrome instances. Safe for concurrent use.
+type Pool struct {
+	size           int
+	maxJobs        int
+	idleTimeout    time.Duration
+	acquireTimeout time.Duration
+
+	// launch starts a browser; openTab opens a tab with the User-Agent (replaced in tests).
+	launch  func(id int) (context.Context, func(), error)
+	openTab func(browserCtx context.Context, userAgent string) (context.Context, context.CancelFunc, error)
+
+	slots chan struct{}
+
+	mu        sync.Mutex
+	idle      []*browser
+	running   int
+	busy      int
+	queued    int
+	nextID    int
+	launched  int64
+	jobs      int64
+	failures  int64
+	waitTotal time.Duration
+	runTotal  time.Duration
+	closed    bool
+}
+
+// New creates a pool from cfg (zero fields use the defaults). No browser is started until the first Run.
+func New(cfg config.BrowserPoolConfig) *Pool {
+	p := &Pool{
+		size:           cfg.Size,
+		maxJobs:        cfg.MaxJobsPerBrowser,
+		idleTimeout:    cfg.IdleTimeout,
+		acquireTimeout: cfg.AcquireTimeout,
+		launch:         launchChrome,
+		openTab:        openChromeTab,
+	}
+	if p.size <= 0 {
+		p.size = DefaultSize
+	}
+	if p.maxJobs <= 0 {
+		p.maxJobs = DefaultMaxJobsPerBrowser
+	}
+	if p.idleTimeout <= 0 {
+		p.idleTimeout = DefaultIdleTimeout
+	}
+	if p.acquireTimeout <= 0 {
+		p.acquireTimeout = DefaultAcquireTimeout
+	}
+	p.slots = make(chan struct{}, p.size)
+	return p
+}
+
+// Run executes fn in a new tab of a pooled browser. The tab context is cancelled when ctx is done
+// and the tab is closed when fn returns. userAgent overrides the browser User-Agent for the tab
+// (empty = Chrome's own). Waits for a free browser at most acquire_timeout.
+func (p *Pool) Run(ctx context.Context, userAgent string, fn func(ctx context.Context) error) error {
+	queuedAt := time.Now()
+	b, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	wait := time.Since(queuedAt)
+
+	started := time.Now()
+	err = p.runInTab(ctx, b, userAgent, fn)
+	p.release(b, wait, time.Since(started), err)
+	return err
+}
+
+func (p *Pool) runInTab(ctx context.Context, b *browser, userAgent string, fn func(ctx context.Context) error) error {
+	tabCtx, cancel, err := p.openTab(b.ctx, userAgent)
+	if err != nil {
+		return fmt.Errorf("open tab: %w", err)
+	}
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancelDeadline context.CancelFunc
+		tabCtx, cancelDeadline = context.WithDeadline(tabCtx, deadline)
+		defer cancelDeadline()
+	}
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	return fn(tabCtx)
+}
+
+// acquire waits for a slot and returns an idle browser or starts a new one.
+func (p *Pool) acquire(ctx context.Context) (*browser, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrClosed
+	}
+	p.queued++
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.acquireTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = fmt.Errorf("no free browser within %s (pool size %d)", p.acquireTimeout, p.size)
+	}
+
+	p.mu.Lock()
+	p.queued--
+	if err == nil && p.closed {
+		err = ErrClosed
+		<-p.slots
+	}
+	if err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	p.busy++
+	if n := len(p.idle); n > 0 {
+		b := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		b.idle.Stop()
+		p.mu.Unlock()
+		return b, nil
+	}
+	p.nextID++
+	id := p.nextID
+	p.mu.Unlock()
+
+	bctx, closeFn, err := p.launch(id)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.busy--
+		<-p.slots
+		return nil, fmt.Errorf("start chrome: %w", err)
+	}
+	p.running++
+	p.launched++
+	slog.Info("Browser pool: started chrome", "browser_id", id, "running", p.running, "size", p.size)
+	return &browser{id: id, ctx: bctx, close: closeFn}, nil
+}
+
+// release returns the browser to the pool, restarting it after max_jobs_per_browser jobs
+// or when its context is gone (Chrome crashed or was killed).
+func (p *Pool) release(b *browser, wait, took time.Duration, jobErr error) {
+	b.jobs++
+	p.mu.Lock()
+	p.busy--
+	p.jobs++
+	p.waitTotal += wait
+	p.runTotal += took
+	if jobErr != nil {
+		p.failures++
+	}
+	retire := p.closed || b.jobs >= p.maxJobs || b.ctx.Err() != nil
+	if retire {
+		p.running--
+	} else {
+		b.idle = time.AfterFunc(p.idleTimeout, func() { p.closeIdle(b) })
+		p.idle = append(p.idle, b)
+	}
+	p.mu.Unlock()
+	<-p.slots
+
+	if retire {
+		slog.Info("Browser pool: closing chrome", "browser_id", b.id, "jobs", b.jobs)
+		b.close()
+	}
+}
+
+// closeIdle closes b if it is still idle (called by its idle timer).
+func (p *Pool) closeIdle(b *browser) {
+	p.mu.Lock()
+	found := false
+	for i, ib := range p.idle {
+		if ib == b {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			p.running--
+			found = true
+			break
+		}
+	}
+	p.mu.Unlock()
+	if found {
+		slog.Info("Browser pool: closing idle chrome", "browser_id", b.id, "jobs", b.jobs)
+		b.close()
+	}
+}
+
+// Stats returns the current pool metrics.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := Stats{
+		Size:     p.size,
+		Running:  p.running,
+		Busy:     p.busy,
+		Queued:   p.queued,
+		Launched: p.launched,
+		Jobs:     p.jobs,
+		Failures: p.failures,
+	}
+	if p.jobs > 0 {
+		s.AvgWaitMs = float64(p.waitTotal.Milliseconds()) / float64(p.jobs)
+		s.AvgDurationMs = float64(p.runTotal.Milliseconds()) / float64(p.jobs)
+	}
+	return s
+}
+
+// Close closes idle browsers; busy ones are closed when their job finishes. Run fails afterwards.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.running -= len(idle)
+	p.mu.Unlock()
+	for _, b := range idle {
+		b.idle.Stop()
+		b.close()
+	}
+}
+
+// launchChrome starts a headless Chrome with its own profile directory (removed on close).
+func launchChrome(id int) (context.Context, func(), error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("browserpool_%d_", id))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create profile dir: %w", err)
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.UserDataDir(dir),
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, v ...interface{}) {
+		if os.Getenv("BROWSERPOOL_DEBUG") == "1" {
+			slog.Debug("chromedp", "message", fmt.Sprintf(format, v...))
+		}
+	}))
+	closeFn := func() {
+		cancelBrowser()
+		cancelAlloc()
+		os.RemoveAll(dir)
+	}
+	// Run with no actions starts the browser
+	if err := chromedp.Run(browserCtx); err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+	return browserCtx, closeFn, nil
+}
+
+// openChromeTab opens a new tab in the browser and sets its User-Agent.
+func openChromeTab(browserCtx context.Context, userAgent string) (context.Context, context.CancelFunc, error) {
+	tabCtx, cancel := chromedp.NewContext(browserCtx)
+	var actions []chromedp.Action
+	if userAgent != "" {
+		actions = append(actions, emulation.SetUserAgentOverride(userAgent))
+	}
+	if err := chromedp.Run(tabCtx, actions...); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return tabCtx, cancel, nil
+}
+
+var (
+	defaultMu   sync.Mutex
+	defaultPool *Pool
+)
+
+// Configure sets up the shared pool (parser.browser_pool). Call once at startup, before parsers run.
+func Configure(cfg config.BrowserPoolConfig) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultPool != nil {
+		defaultPool.Close()
+	}
+	defaultPool = New(cfg)
+	slog.Info("Browser pool configured", "size", defaultPool.size, "max_jobs_per_browser", defaultPool.maxJobs,
+		"idle_timeout", defaultPool.idleTimeout, "acquire_timeout", defaultPool.acquireTimeout)
+}
+
+// Default returns the shared pool (with default settings if Configure was not called).
+func Default() *Pool {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultPool == nil {
+		defaultPool = New(config.BrowserPoolConfig{})
+	}
+	return defaultPool
+}
+
+// Run executes fn in a tab of the shared pool (see Pool.Run).
+func Run(ctx context.Context, userAgent string, fn func(ctx context.Context) error) error {
+	return Default().Run(ctx, userAgent, fn)
+}
+
+// CurrentStats returns the metrics of the shared pool.
+func CurrentStats() Stats {
+	return Default().Stats()
+}
@@ -0,0 +1,116 @@
+package browserpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// fakePool returns a pool whose browsers are plain contexts; closed counts closed browsers.
+func fakePool(cfg config.BrowserPoolConfig, closed *atomic.Int32) *Pool {
+	p := New(cfg)
+	p.launch = func(id int) (context.Context, func(), error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, func() { cancel(); closed.Add(1) }, nil
+	}
+	p.openTab = func(browserCtx context.Context, userAgent string) (context.Context, context.CancelFunc, error) {
+		ctx, cancel := context.WithCancel(browserCtx)
+		return ctx, cancel, nil
+	}
+	return p
+}
+
+func TestPool_QueuesJobsAndReusesBrowsers(t *testing.T) {
+	var closed atomic.Int32
+	p := fakePool(config.BrowserPoolConfig{Size: 2, MaxJobsPerBrowser: 3}, &closed)
+	defer p.Close()
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.Run(context.Background(), "UA", func(ctx context.Context) error {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Run: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxRunning.Load(); got > 2 {
+		t.Fatalf("at most 2 jobs may run at once, got %d", got)
+	}
+	s := p.Stats()
+	if s.Jobs != 6 || s.Busy != 0 || s.Queued != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	// 6 jobs on 2 browsers with max 3 jobs each: every browser retired once
+	if s.Launched < 2 || s.Launched > 4 || int(closed.Load()) != int(s.Launched)-s.Running {
+		t.Fatalf("unexpected browser lifecycle: %+v, closed %d", s, closed.Load())
+	}
+}
+
+func TestPool_FailuresCancellationAndIdleClose(t *testing.T) {
+	var closed atomic.Int32
+	p := fakePool(config.BrowserPoolConfig{Size: 1, IdleTimeout: 30 * time.Millisecond, AcquireTimeout: 50 * time.Millisecond}, &closed)
+	defer p.Close()
+
+	jobErr := errors.New("navigation failed")
+	if err := p.Run(context.Background(), "", func(ctx context.Context) error { return jobErr }); !errors.Is(err, jobErr) {
+		t.Fatalf("expected job error, got %v", err)
+	}
+
+	// The tab context follows the caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	err := p.Run(ctx, "", func(tab context.Context) error {
+		cancel()
+		<-tab.Done()
+		return tab.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled tab, got %v", err)
+	}
+
+	// A busy pool makes other jobs time out in the queue
+	release := make(chan struct{})
+	go p.Run(context.Background(), "", func(ctx context.Context) error { <-release; return nil })
+	time.Sleep(10 * time.Millisecond)
+	if err := p.Run(context.Background(), "", func(ctx context.Context) error { return nil }); err == nil {
+		t.Fatal("expected acquire timeout while the only browser is busy")
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for closed.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("idle browser not closed: %+v", p.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s := p.Stats(); s.Failures != 2 || s.Launched != 1 || s.Running != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	p.Close()
+	if err := p.Run(context.Background(), "", func(ctx context.Context) error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package bus fans parsed matches out over NATS. Publishers (parser, bookmaker-service) send every
+// stored match to <prefix>.<bookmaker>; consumers (calculator, analytics, archivers) subscribe to
+// <prefix>.> and keep the latest match of every bookmaker in a MatchCache.
+package bus
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// DefaultSubjectPrefix is the subject prefix when bus.subject_prefix is empty.
+const DefaultSubjectPrefix = "vodeneevbet.matches"
+
+// Bus is a NATS connection publishing and consuming matches under one subject prefix.
+type Bus struct {
+	conn   *nats.Conn
+	prefix string
+	queue  string
+	failed atomic.Int64 // publish errors (buffer full while reconnecting), logged every 1000th
+}
+
+// Connect connects to bus.url (env NATS_URL overrides it) as client name (the service name).
+// The connection reconnects forever; messages published while disconnected are buffered by the client.
+func Connect(cfg config.BusConfig, name string) (*Bus, error) {
+	url := cfg.URL
+	if env := os.Getenv("NATS_URL"); env != "" {
+		url = env
+	}
+	if url == "" {
+		return nil, fmt.Errorf("bus.url is not set")
+	}
+	conn, err := nats.Connect(url,
+		nats.Name(name),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			slog.Warn("Bus disconnected", "error", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			slog.Info("Bus reconnected", "url", c.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", url, err)
+	}
+	prefix := strings.Trim(cfg.SubjectPrefix, ".")
+	if prefix == "" {
+		prefix = DefaultSubjectPrefix
+	}
+	slog.Info("Bus connected", "url", conn.ConnectedUrl(), "subject_prefix", prefix)
+	return &Bus{conn: conn, prefix: prefix, queue: cfg.QueueGroup}, nil
+}
+
+// Subject returns the subject of a bookmaker's matches: <prefix>.<bookmaker>.
+func (b *Bus) Subject(bookmaker string) string {
+	return subject(b.prefix, bookmaker)
+}
+
+func subject(prefix, bookmaker string) string {
+	return prefix + "." + subjectToken(bookmaker)
+}
+
+// subjectToken makes a bookmaker name a single subject token: lower case, anything but
+// letters, digits, '-' and '_' replaced by '_' ("1xBet.com" → "1xbet_com").
+func subjectToken(bookmaker string) string {
+	token := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.ToLower(strings.TrimSpace(bookmaker)))
+	if token == "" {
+		return "unknown"
+	}
+	return token
+}
+
+// matchBookmaker returns the bookmaker of a match, falling back to its first event.
+func matchBookmaker(m *models.Match) string {
+	if m.Bookmaker != "" {
+		return m.Bookmaker
+	}
+	for _, ev := range m.Events {
+		if ev.Bookmaker != "" {
+			return ev.Bookmaker
+		}
+	}
+	return ""
+}
+
+// PublishMatch publishes match as JSON on the subject of its bookmaker (implements health.MatchPublisher).
+func (b *Bus) PublishMatch(match *models.Match) {
+	data, err := json.Marshal(match)
+	if err != nil {
+		slog.Warn("Bus: failed to encode match", "match_id", match.ID, "error", err)
+		return
+	}
+	if err := b.conn.Publish(b.Subject(matchBookmaker(match)), data); err != nil {
+		if n := b.failed.Add(1); n == 1 || n%1000 == 0 {
+			slog.Warn("Bus: failed to publish match", "match_id", match.ID, "failed_total", n, "error", err)
+		}
+	}
+}
+
+// SubscribeMatches calls handle for every match published under the prefix (from the client's
+// callback goroutine, one message at a time). With bus.queue_group set, consumers of the group share messages.
+func (b *Bus) SubscribeMatches(handle func(models.Match)) error {
+	all := b.prefix + ".>"
+	cb := func(msg *nats.Msg) {
+		var m models.Match
+		if err := json.Unmarshal(msg.Data, &m); err != nil {
+			slog.Warn("Bus: failed to decode match", "subject", msg.Subject, "error", err)
+			return
+		}
+		handle(m)
+	}
+	var err error
+	if b.queue != "" {
+		_, err = b.conn.QueueSubscribe(all, b.queue, cb)
+	} else {
+		_, err = b.conn.Subscribe(all, cb)
+	}
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", all, err)
+	}
+	slog.Info("Bus: subscribed to matches", "subject", all, "queue_group", b.queue)
+	return nil
+}
+
+// Close flushes pending messages and closes the connection.
+func (b *Bus) Close() {
+	if err := b.conn.Drain(); err != nil {
+		b.conn.Close()
+	}
+}
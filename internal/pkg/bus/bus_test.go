@@ -0,0 +1,105 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestSubject(t *testing.T) {
+	tests := []struct{ bookmaker, want string }{
+		{"fonbet", "vodeneevbet.matches.fonbet"},
+		{"1xBet.com", "vodeneevbet.matches.1xbet_com"},
+		{"Pinnacle 888", "vodeneevbet.matches.pinnacle_888"},
+		{"", "vodeneevbet.matches.unknown"},
+	}
+	for _, tt := range tests {
+		if got := subject(DefaultSubjectPrefix, tt.bookmaker); got != tt.want {
+			t.Errorf("subject(%q) = %q, want %q", tt.bookmaker, got, tt.want)
+		}
+	}
+}
+
+func busMatch(id, bookmaker string, odds map[string]float64) models.Match {
+	ev := models.Event{ID: id + "|main", EventType: "main_match", Bookmaker: bookmaker}
+	for outcome, o := range odds {
+		ev.Outcomes = append(ev.Outcomes, models.Outcome{ID: ev.ID + "|" + outcome, OutcomeType: outcome, Odds: o, Bookmaker: bookmaker})
+	}
+	return models.Match{ID: id, HomeTeam: "Arsenal", AwayTeam: "Chelsea", Events: []models.Event{ev}}
+}
+
+func TestMatchCache(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMatchCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Add(busMatch("m1", "fonbet", map[string]float64{"home_win": 2.1}))
+	c.Add(busMatch("m1", "pinnacle", map[string]float64{"home_win": 2.3}))
+	c.Add(busMatch("m1", "fonbet", map[string]float64{"home_win": 2.0, "away_win": 3.5}))
+	if c.Len() != 2 {
+		t.Fatalf("cache has %d matches, want one per bookmaker", c.Len())
+	}
+
+	var fonbet models.Match
+	for _, m := range c.Matches() {
+		if matchBookmaker(&m) == "fonbet" {
+			fonbet = m
+		}
+	}
+	if len(fonbet.Events) != 1 || len(fonbet.Events[0].Outcomes) != 2 {
+		t.Fatalf("fonbet match not merged: %+v", fonbet.Events)
+	}
+	for _, o := range fonbet.Events[0].Outcomes {
+		if o.OutcomeType == "home_win" && o.Odds != 2.0 {
+			t.Errorf("home_win = %v, want the latest 2.0", o.Odds)
+		}
+	}
+
+	// returned matches are copies
+	fonbet.Events[0].Outcomes[0].Odds = 99
+	for _, m := range c.Matches() {
+		for _, o := range m.Events[0].Outcomes {
+			if o.Odds == 99 {
+				t.Fatal("Matches returned the cached outcomes")
+			}
+		}
+	}
+
+	now = now.Add(45 * time.Second)
+	c.Add(busMatch("m1", "pinnacle", map[string]float64{"home_win": 2.25}))
+	now = now.Add(30 * time.Second)
+	got := c.Matches()
+	if len(got) != 1 || matchBookmaker(&got[0]) != "pinnacle" {
+		t.Fatalf("after TTL got %+v, want only the republished pinnacle match", got)
+	}
+	if c.Len() != 1 {
+		t.Errorf("expired match not dropped, len = %d", c.Len())
+	}
+}
+
+func TestLocal(t *testing.T) {
+	l := NewLocal(1)
+	defer l.Close()
+	c := NewMatchCache(time.Minute)
+	received := make(chan struct{}, 2)
+
+	m := busMatch("m1", "fonbet", map[string]float64{"home_win": 2.1})
+	l.PublishMatch(&m)
+	l.PublishMatch(&m) // buffer full: dropped
+	m.Events[0].Outcomes[0].Odds = 99
+	if err := l.SubscribeMatches(func(m models.Match) { c.Add(m); received <- struct{}{} }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("match not delivered")
+	}
+	if got := c.Matches(); len(got) != 1 || got[0].Events[0].Outcomes[0].Odds != 2.1 {
+		t.Errorf("cache = %+v, want the match as published", got)
+	}
+	if n := l.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+}
@@ -0,0 +1,84 @@
+package bus
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// defaultMatchTTL is how long a consumer keeps a bookmaker's match that is not republished
+// (the match was removed from the line, or its bookmaker stopped publishing).
+const defaultMatchTTL = 10 * time.Minute
+
+// MatchCache is the consumer side of the bus: the latest match of every (match ID, bookmaker).
+// Matches of one bookmaker are merged like AddMatch does; different bookmakers stay separate
+// matches, the calculator groups them by fixture.
+type MatchCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]*cachedMatch // match ID|bookmaker -> match
+	now     func() time.Time
+}
+
+type cachedMatch struct {
+	match    models.Match
+	received time.Time
+}
+
+// NewMatchCache returns an empty cache; ttl <= 0 means 10 minutes.
+func NewMatchCache(ttl time.Duration) *MatchCache {
+	if ttl <= 0 {
+		ttl = defaultMatchTTL
+	}
+	return &MatchCache{ttl: ttl, entries: map[string]*cachedMatch{}, now: time.Now}
+}
+
+// Add stores m, merging it into the match of the same ID and bookmaker received before.
+func (c *MatchCache) Add(m models.Match) {
+	key := m.ID + "|" + matchBookmaker(&m)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prev, ok := c.entries[key]; ok {
+		m = health.MergeMatchLists([][]models.Match{{prev.match}, {m}})[0]
+	}
+	c.entries[key] = &cachedMatch{match: m, received: c.now()}
+}
+
+// Matches returns copies of the cached matches received within the TTL, most recently updated first;
+// expired matches are dropped.
+func (c *MatchCache) Matches() []models.Match {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]models.Match, 0, len(c.entries))
+	for key, e := range c.entries {
+		if now.Sub(e.received) > c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		out = append(out, copyMatch(e.match))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out
+}
+
+// Len returns the number of cached matches, expired ones included.
+func (c *MatchCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// copyMatch copies m with its events and outcomes, so callers may modify the result.
+func copyMatch(m models.Match) models.Match {
+	events := make([]models.Event, len(m.Events))
+	for i, ev := range m.Events {
+		ev.Outcomes = append([]models.Outcome(nil), ev.Outcomes...)
+		events[i] = ev
+	}
+	m.Events = events
+	return m
+}
@@ -0,0 +1,68 @@
+package bus
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// defaultLocalBuffer is the number of matches a Local bus queues when the buffer size is not set.
+const defaultLocalBuffer = 10000
+
+// Local is the in-process bus of cmd/allinone: matches go from the parsers to the calculator through a
+// buffered channel instead of NATS, with the PublishMatch and SubscribeMatches of Bus. Subscribers share
+// the messages like a queue group.
+type Local struct {
+	ch      chan models.Match
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64 // matches dropped while the buffer was full, logged every 1000th
+}
+
+// NewLocal returns a Local bus queuing up to buffer matches; buffer <= 0 means 10000.
+func NewLocal(buffer int) *Local {
+	if buffer <= 0 {
+		buffer = defaultLocalBuffer
+	}
+	return &Local{ch: make(chan models.Match, buffer), done: make(chan struct{})}
+}
+
+// PublishMatch queues a copy of match (implements health.MatchPublisher). It never blocks the parser:
+// with the buffer full the match is dropped, the next cycle publishes it again.
+func (l *Local) PublishMatch(match *models.Match) {
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+	select {
+	case l.ch <- copyMatch(*match):
+	default:
+		if n := l.dropped.Add(1); n == 1 || n%1000 == 0 {
+			slog.Warn("Bus: local buffer full, match dropped", "match_id", match.ID, "dropped_total", n)
+		}
+	}
+}
+
+// SubscribeMatches calls handle for every published match from its own goroutine, one match at a time,
+// until Close.
+func (l *Local) SubscribeMatches(handle func(models.Match)) error {
+	go func() {
+		for {
+			select {
+			case m := <-l.ch:
+				handle(m)
+			case <-l.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops the subscribers; matches published afterwards are discarded.
+func (l *Local) Close() {
+	l.once.Do(func() { close(l.done) })
+}
@@ -0,0 +1,159 @@
+// Package chaos injects faults into HTTP servers to validate retries, timeouts and circuit
+// breakers before real outages do: random latency, 5xx responses instead of the handler and
+// truncated bodies (the connection is dropped mid-body, so clients see an unexpected EOF).
+//
+// It is test only and disabled unless chaos.enabled is set. Bookmaker services and the calculator
+// wrap their muxes with Middleware; Configure decides whether this service injects anything.
+package chaos
+
+import (
+	"bytes"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+var (
+	defaultExcludePaths  = []string{"/ping", "/health", "/readyz", "/metrics"}
+	defaultErrorStatuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+)
+
+type injector struct {
+	service string
+	cfg     config.ChaosConfig
+}
+
+// current is nil while injection is disabled for this service.
+var current atomic.Pointer[injector]
+
+// Configure enables injection for service when chaos.enabled is set and service matches one of
+// chaos.services (by prefix, so "bookmaker-service" covers every bookmaker-service-<parser>).
+func Configure(service string, cfg config.ChaosConfig) {
+	if !cfg.Enabled || !serviceSelected(service, cfg.Services) {
+		current.Store(nil)
+		return
+	}
+	if len(cfg.ExcludePaths) == 0 {
+		cfg.ExcludePaths = defaultExcludePaths
+	}
+	if len(cfg.ErrorStatuses) == 0 {
+		cfg.ErrorStatuses = defaultErrorStatuses
+	}
+	if cfg.MaxLatency < cfg.MinLatency {
+		cfg.MaxLatency = cfg.MinLatency
+	}
+	current.Store(&injector{service: service, cfg: cfg})
+	slog.Warn("Chaos injection enabled (test only)",
+		"service", service,
+		"latency_probability", cfg.LatencyProbability,
+		"min_latency", cfg.MinLatency,
+		"max_latency", cfg.MaxLatency,
+		"error_probability", cfg.ErrorProbability,
+		"truncate_probability", cfg.TruncateProbability,
+		"exclude_paths", cfg.ExcludePaths)
+}
+
+func serviceSelected(service string, services []string) bool {
+	if len(services) == 0 {
+		return true
+	}
+	for _, s := range services {
+		if s = strings.TrimSpace(s); s != "" && strings.HasPrefix(service, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware wraps next with fault injection. The configuration is read per request, so the
+// order of Configure and Middleware calls doesn't matter.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inj := current.Load()
+		if inj == nil || inj.excluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		inj.serve(next, w, r)
+	})
+}
+
+func (inj *injector) excluded(path string) bool {
+	for _, p := range inj.cfg.ExcludePaths {
+		if p != "" && strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (inj *injector) serve(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	cfg := inj.cfg
+	if hit(cfg.LatencyProbability) {
+		delay := cfg.MinLatency
+		if span := cfg.MaxLatency - cfg.MinLatency; span > 0 {
+			delay += time.Duration(rand.Int64N(int64(span) + 1))
+		}
+		slog.Info("Chaos: injecting latency", "service", inj.service, "path", r.URL.Path, "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if hit(cfg.ErrorProbability) {
+		status := cfg.ErrorStatuses[rand.IntN(len(cfg.ErrorStatuses))]
+		slog.Info("Chaos: injecting error", "service", inj.service, "path", r.URL.Path, "status", status)
+		http.Error(w, "chaos: injected "+strconv.Itoa(status), status)
+		return
+	}
+
+	if !hit(cfg.TruncateProbability) {
+		next.ServeHTTP(w, r)
+		return
+	}
+	tw := &truncatingWriter{ResponseWriter: w, status: http.StatusOK}
+	next.ServeHTTP(tw, r)
+	tw.flushTruncated(inj.service, r.URL.Path)
+}
+
+func hit(probability float64) bool {
+	return probability > 0 && rand.Float64() < probability
+}
+
+// truncatingWriter buffers the handler's response so that only half of it is sent.
+type truncatingWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (tw *truncatingWriter) WriteHeader(status int) { tw.status = status }
+
+func (tw *truncatingWriter) Write(p []byte) (int, error) { return tw.buf.Write(p) }
+
+// flushTruncated announces the full Content-Length, sends the first half of the body and aborts
+// the connection, which is what a proxy or server dying mid-response looks like to the client.
+func (tw *truncatingWriter) flushTruncated(service, path string) {
+	body := tw.buf.Bytes()
+	if len(body) < 2 {
+		tw.ResponseWriter.WriteHeader(tw.status)
+		_, _ = tw.ResponseWriter.Write(body)
+		return
+	}
+	slog.Info("Chaos: truncating response", "service", service, "path", path, "bytes", len(body), "sent", len(body)/2)
+	tw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	tw.ResponseWriter.WriteHeader(tw.status)
+	_, _ = tw.ResponseWriter.Write(body[:len(body)/2])
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	panic(http.ErrAbortHandler)
+}
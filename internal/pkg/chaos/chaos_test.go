@@ -0,0 +1,101 @@
+package chaos
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"matches":[` + strings.Repeat(`{"id":"m"},`, 50) + `{"id":"last"}]}`))
+	})))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { Configure("", config.ChaosConfig{}) })
+	return srv
+}
+
+func fetch(t *testing.T, url string) (int, string, error) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
+
+func TestDisabledPassesThrough(t *testing.T) {
+	srv := newServer(t)
+	Configure("calculator", config.ChaosConfig{ErrorProbability: 1}) // not enabled
+	status, body, err := fetch(t, srv.URL+"/matches")
+	if err != nil || status != http.StatusOK || !strings.HasSuffix(body, `"last"}]}`) {
+		t.Fatalf("got %d %q %v", status, body, err)
+	}
+}
+
+func TestServiceSelection(t *testing.T) {
+	srv := newServer(t)
+	cfg := config.ChaosConfig{Enabled: true, Services: []string{"bookmaker-service"}, ErrorProbability: 1}
+
+	Configure("calculator", cfg)
+	if status, _, _ := fetch(t, srv.URL+"/matches"); status != http.StatusOK {
+		t.Errorf("calculator not selected, got status %d", status)
+	}
+	Configure("bookmaker-service-fonbet", cfg)
+	if status, _, _ := fetch(t, srv.URL+"/matches"); status < 500 {
+		t.Errorf("bookmaker-service-fonbet selected, got status %d", status)
+	}
+}
+
+func TestErrorInjection(t *testing.T) {
+	srv := newServer(t)
+	Configure("calculator", config.ChaosConfig{Enabled: true, ErrorProbability: 1, ErrorStatuses: []int{http.StatusBadGateway}})
+
+	status, body, err := fetch(t, srv.URL+"/diffs/top")
+	if err != nil || status != http.StatusBadGateway || !strings.Contains(body, "chaos") {
+		t.Fatalf("got %d %q %v", status, body, err)
+	}
+	// Excluded paths are never injected.
+	if status, _, _ := fetch(t, srv.URL+"/health"); status != http.StatusOK {
+		t.Errorf("/health got status %d", status)
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	srv := newServer(t)
+	Configure("calculator", config.ChaosConfig{Enabled: true, TruncateProbability: 1})
+
+	status, body, err := fetch(t, srv.URL+"/matches")
+	if status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if err == nil {
+		t.Fatalf("expected a read error for a truncated body, got %q", body)
+	}
+	if strings.HasSuffix(body, `"last"}]}`) || len(body) == 0 {
+		t.Errorf("body not truncated: %q", body)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv := newServer(t)
+	Configure("calculator", config.ChaosConfig{Enabled: true, LatencyProbability: 1, MinLatency: 50 * time.Millisecond, MaxLatency: 60 * time.Millisecond})
+
+	start := time.Now()
+	status, _, err := fetch(t, srv.URL+"/matches")
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("got %d %v", status, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("no latency injected: %v", elapsed)
+	}
+}
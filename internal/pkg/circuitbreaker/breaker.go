@@ -0,0 +1,289 @@
+// Package circuitbreaker stops parsers from hammering a bookmaker host that is blocking them.
+//
+// Parser HTTP clients wrap their transports with Wrap. All requests to the same host (direct or
+// via any proxy) share one breaker: after N consecutive failures (network errors, 403, 429, 5xx)
+// it opens and requests fail fast with ErrOpen until the cooldown expires. Then one probe request
+// is let through (half-open): success closes the breaker, failure re-opens it for another cooldown.
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/replay"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
+)
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 2 * time.Minute
+)
+
+// ErrOpen is returned (wrapped) for requests rejected by an open breaker.
+var ErrOpen = errors.New("circuit breaker open")
+
+// State of a breaker.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Breaker tracks consecutive failures for one endpoint (host). A nil *Breaker always allows.
+type Breaker struct {
+	endpoint  string
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+}
+
+// New creates a breaker that opens after threshold consecutive failures and stays open for cooldown.
+// Returns nil (disabled) when threshold <= 0.
+func New(endpoint string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Breaker{endpoint: endpoint, threshold: threshold, cooldown: cooldown, state: StateClosed}
+}
+
+// Allow reports whether a request may be sent now. When the cooldown of an open breaker
+// has expired, exactly one caller gets through as a probe.
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		if remaining := b.cooldown - time.Since(b.openedAt); remaining > 0 {
+			return fmt.Errorf("%w for %s (retry in %s, last error: %s)", ErrOpen, b.endpoint, remaining.Round(time.Second), b.lastError)
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		slog.Info("Circuit breaker half-open, probing", "endpoint", b.endpoint)
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w for %s (probe in flight)", ErrOpen, b.endpoint)
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Success records a successful request and closes the breaker.
+func (b *Breaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateClosed {
+		slog.Info("Circuit breaker closed", "endpoint", b.endpoint)
+	}
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a failed request; opens the breaker after threshold consecutive failures
+// or immediately when a half-open probe fails.
+func (b *Breaker) Failure(reason string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastError = reason
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.threshold) {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.probing = false
+		slog.Warn("Circuit breaker opened", "endpoint", b.endpoint, "failures", b.failures, "cooldown", b.cooldown, "last_error", reason)
+	}
+}
+
+// Status is a point-in-time view of a breaker (exposed on /health).
+type Status struct {
+	Endpoint  string    `json:"endpoint"`
+	State     string    `json:"state"`
+	Failures  int       `json:"consecutive_failures"`
+	OpenedAt  time.Time `json:"opened_at,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Status returns the current state of the breaker.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := Status{Endpoint: b.endpoint, State: b.state, Failures: b.failures, LastError: b.lastError}
+	if b.state != StateClosed {
+		s.OpenedAt = b.openedAt
+	}
+	return s
+}
+
+// isFailureStatus reports whether a response status means the bookmaker is blocking or failing us.
+func isFailureStatus(code int) bool {
+	return code == http.StatusForbidden || code == http.StatusTooManyRequests || code >= 500
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Breaker{}
+	threshold  = DefaultFailureThreshold
+	cooldown   = DefaultCooldown
+)
+
+// Configure sets threshold and cooldown for breakers created afterwards (parser.circuit_breaker).
+// FailureThreshold < 0 disables breakers; zero values keep the defaults.
+func Configure(cfg config.CircuitBreakerConfig) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if cfg.FailureThreshold != 0 {
+		threshold = cfg.FailureThreshold
+	}
+	if cfg.Cooldown > 0 {
+		cooldown = cfg.Cooldown
+	}
+	slog.Info("Circuit breaker configured", "failure_threshold", threshold, "cooldown", cooldown)
+}
+
+// ForEndpoint returns the shared breaker for a host, creating it on first use.
+func ForEndpoint(host string) *Breaker {
+	host = strings.ToLower(host)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if b, ok := registry[host]; ok {
+		return b
+	}
+	b := New(host, threshold, cooldown)
+	if b != nil {
+		registry[host] = b
+	}
+	return b
+}
+
+// Statuses returns the state of all breakers, sorted by endpoint.
+func Statuses() []Status {
+	registryMu.Lock()
+	list := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		list = append(list, b)
+	}
+	registryMu.Unlock()
+
+	out := make([]Status, 0, len(list))
+	for _, b := range list {
+		out = append(out, b.Status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+// transport is an http.RoundTripper guarded by per-host breakers.
+type transport struct {
+	base http.RoundTripper
+	// parser gets the sent requests counted in its cycle statistics (cyclestats); "" = not counted
+	parser string
+	// proxy is the Proxy of the base *http.Transport (nil = direct)
+	proxy func(*http.Request) (*url.URL, error)
+}
+
+// Wrap returns a RoundTripper that checks the breaker of the request host before sending
+// and records the outcome. base == nil means http.DefaultTransport. The base is also wrapped
+// with replay.Wrap, so parser traffic can be recorded and replayed (cmd/parser-replay), and with
+// tracing.ExternalTransport, so requests show up in the span of their parse cycle.
+func Wrap(base http.RoundTripper) http.RoundTripper {
+	return WrapParser("", base)
+}
+
+// WrapParser is Wrap for the requests of parser: they are also counted in the statistics of its current
+// cycle (requests, latency, proxy; see cyclestats).
+func WrapParser(parser string, base http.RoundTripper) http.RoundTripper {
+	t := &transport{parser: parser}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if tr, ok := base.(*http.Transport); ok {
+		t.proxy = tr.Proxy
+	}
+	t.base = tracing.ExternalTransport(replay.Wrap(base))
+	return t
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := ForEndpoint(req.URL.Host)
+	if err := b.Allow(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	done := cyclestats.InFlight(t.parser)
+	resp, err := t.base.RoundTrip(req)
+	done()
+	if t.parser != "" && !errors.Is(err, context.Canceled) {
+		cyclestats.Request(t.parser, t.proxyHost(req), time.Since(start), err != nil || isFailureStatus(resp.StatusCode))
+	}
+	switch {
+	case err != nil:
+		if errors.Is(err, context.Canceled) {
+			// Our own cancellation says nothing about the bookmaker; release a probe if we held one.
+			b.release()
+			return nil, err
+		}
+		b.Failure(err.Error())
+		metrics.BookmakerHTTPErrors.WithLabelValues(strings.ToLower(req.URL.Host), "error").Inc()
+	case isFailureStatus(resp.StatusCode):
+		b.Failure(fmt.Sprintf("status %d", resp.StatusCode))
+		metrics.BookmakerHTTPErrors.WithLabelValues(strings.ToLower(req.URL.Host), strconv.Itoa(resp.StatusCode)).Inc()
+	default:
+		b.Success()
+	}
+	return resp, err
+}
+
+// proxyHost returns the host of the proxy req is sent through ("" = direct).
+func (t *transport) proxyHost(req *http.Request) string {
+	if t.proxy == nil {
+		return ""
+	}
+	u, err := t.proxy(req)
+	if err != nil || u == nil {
+		return ""
+	}
+	return u.Host
+}
+
+// release gives up a half-open probe without recording a result.
+func (b *Breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
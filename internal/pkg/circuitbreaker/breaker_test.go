@@ -0,0 +1,83 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+func TestBreaker_OpensAfterThresholdAndProbesAfterCooldown(t *testing.T) {
+	b := New("example.com", 2, 50*time.Millisecond)
+
+	b.Failure("status 403")
+	if err := b.Allow(); err != nil {
+		t.Fatalf("breaker should still be closed after 1 failure: %v", err)
+	}
+	b.Failure("status 403")
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected ErrOpen after threshold, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed after cooldown: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected second caller to be rejected while probe is in flight, got %v", err)
+	}
+	b.Success()
+	if s := b.Status(); s.State != StateClosed || s.Failures != 0 {
+		t.Fatalf("expected closed breaker after successful probe, got %+v", s)
+	}
+}
+
+func TestWrap_FailsFastWhenHostIsBlocking(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Wrap(nil)}
+	for i := 0; i < DefaultFailureThreshold+3; i++ {
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+	if got := hits.Load(); got != DefaultFailureThreshold {
+		t.Fatalf("server hit %d times, want %d (breaker should stop further requests)", got, DefaultFailureThreshold)
+	}
+}
+
+func TestWrapParser_CountsCycleRequests(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 2 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	cyclestats.Begin("wrap-test")
+	client := &http.Client{Transport: WrapParser("wrap-test", &http.Transport{})}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	r := interfaces.ParseReport{Parser: "Wrap-Test"}
+	cyclestats.Fill(&r)
+	if r.HTTPRequests != 2 || r.HTTPErrors != 1 || r.Proxy != cyclestats.Direct || r.AvgLatency == "" {
+		t.Fatalf("report %+v, want 2 direct requests with 1 error", r)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -10,16 +11,59 @@ import (
 
 type Config struct {
 	Postgres        PostgresConfig        `yaml:"postgres"`
+	Storage         StorageConfig         `yaml:"storage"`
 	Parser          ParserConfig          `yaml:"parser"`
 	ValueCalculator ValueCalculatorConfig `yaml:"value_calculator"`
 	Health          HealthConfig          `yaml:"health"`
 	Logging         LoggingConfig         `yaml:"logging"`
+	EventLog        EventLogConfig        `yaml:"event_log"`
+	Chaos           ChaosConfig           `yaml:"chaos"`
+	Odds            OddsConfig            `yaml:"odds"`
+	Bus             BusConfig             `yaml:"bus"`
+	Telegram        TelegramConfig        `yaml:"telegram"`
+	Tracing         TracingConfig         `yaml:"tracing"`
+	APIAuth         APIAuthConfig         `yaml:"api_auth"`
+	AllInOne        AllInOneConfig        `yaml:"all_in_one"`
+	TeamAliases     TeamAliasesConfig     `yaml:"team_aliases"`
+	LeagueAliases   LeagueAliasesConfig   `yaml:"league_aliases"`
 }
 
 type PostgresConfig struct {
 	DSN string `yaml:"dsn"`
 }
 
+// Storage backends of the calculator (storage.backend).
+const (
+	StorageBackendPostgres = "postgres"
+	StorageBackendSQLite   = "sqlite"
+)
+
+// StorageConfig selects where the calculator keeps diff_bets. With sqlite everything else that needs a
+// database (odds history, line movements, ignores, subscriptions, bets, warehouse, leader election) is off,
+// so the stack runs on one small VM or a laptop without Postgres.
+type StorageConfig struct {
+	Backend    string `yaml:"backend"`     // postgres (default) or sqlite (binary built with -tags sqlite)
+	SQLitePath string `yaml:"sqlite_path"` // Database file of backend sqlite (default: data/vodeneevbet.db)
+
+	// Long-term archive of every odds snapshot of the line movement cycle (requires line_movement_enabled)
+	ClickHouse ClickHouseConfig `yaml:"clickhouse"`
+}
+
+// ClickHouseConfig is storage.clickhouse. Snapshots are buffered and inserted in batches of batch_size or
+// every flush_interval; the database and table are created on startup.
+type ClickHouseConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	URL           string        `yaml:"url"`            // HTTP interface, e.g. http://clickhouse:8123
+	Database      string        `yaml:"database"`       // default: vodeneevbet
+	Table         string        `yaml:"table"`          // default: odds_archive
+	User          string        `yaml:"user"`           // default: the server's default user
+	Password      string        `yaml:"password"`       // CLICKHOUSE_PASSWORD env var overrides it
+	BatchSize     int           `yaml:"batch_size"`     // Rows per INSERT (default: 50000)
+	FlushInterval time.Duration `yaml:"flush_interval"` // Max time rows wait in the buffer (default: 30s)
+	MaxBuffered   int           `yaml:"max_buffered"`   // Rows kept while ClickHouse is unreachable, oldest dropped beyond (default: 10 batches)
+	TTL           time.Duration `yaml:"ttl"`            // Rows older than this are deleted by ClickHouse (default: 0 = kept forever)
+}
+
 type ParserConfig struct {
 	EnabledParsers    []string          `yaml:"enabled_parsers"`
 	Interval          time.Duration     `yaml:"interval"`
@@ -29,10 +73,40 @@ type ParserConfig struct {
 	// BookmakerServices: name -> base URL. If set, parser runs in orchestrator mode:
 	// no local parsers, /matches aggregates from these URLs, /parse proxies to them.
 	BookmakerServices map[string]string `yaml:"bookmaker_services"`
+	// Discovery finds bookmaker services at runtime (DNS SRV or a directory file) in addition to bookmaker_services
+	Discovery DiscoveryConfig `yaml:"discovery"`
+	// GRPC streams matches from bookmaker services to the orchestrator (api/proto/matches/v1); HTTP /matches stays as fallback
+	GRPC GRPCConfig `yaml:"grpc"`
 	// IncrementalParsing enables continuous incremental parsing for bookmaker services
 	// When enabled, parsers work in background, parsing data in batches and updating storage incrementally
 	// This allows /matches endpoint to return partially ready data without blocking
 	IncrementalParsing IncrementalParsingConfig `yaml:"incremental_parsing"`
+	// CircuitBreaker stops requests to a bookmaker host after consecutive failures (403/429/5xx/network)
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	// ProxyPool: shared rotation of proxies from all proxy_list entries (stats, temporary bans, sticky sessions)
+	ProxyPool ProxyPoolConfig `yaml:"proxy_pool"`
+	// BrowserPool: shared headless Chrome instances for mirror resolution (pinnacle888, xbet1)
+	BrowserPool BrowserPoolConfig `yaml:"browser_pool"`
+	// MirrorRegistry persists resolved mirror URLs (pinnacle888, xbet1) across restarts; overrides via /mirrors/override
+	MirrorRegistry MirrorRegistryConfig `yaml:"mirror_registry"`
+	// Fingerprint: User-Agent pool, Accept-Language and Sec-CH-UA headers for all parsers (parser.<name>.fingerprint overrides)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"`
+	// Snapshot persists the in-memory matches to disk so a restarted bookmaker-service serves them (marked stale) until the first cycle completes
+	Snapshot SnapshotConfig `yaml:"snapshot"`
+	// ShutdownGracePeriod: on SIGTERM the bookmaker-service starts no new league or cycle and waits up to this
+	// long for the leagues in progress to finish and be stored before canceling the parsers (0 = 30s)
+	ShutdownGracePeriod time.Duration `yaml:"shutdown_grace_period"`
+	// StartTimeTolerance buckets start times in match IDs (models.CanonicalMatchID) and the calculator's match
+	// groups, rounded to the nearest bucket, so a kickoff moved by a few minutes between cycles or reported
+	// 17:59 vs 18:05 keeps one ID (0 = 30m, negative = exact times)
+	StartTimeTolerance time.Duration `yaml:"start_time_tolerance"`
+	// DryRun runs each parser's ParseOnce once and writes the matches to a file/stdout instead of serving them (-dry-run)
+	DryRun DryRunConfig `yaml:"dry_run"`
+	// TeamInfo: team/league metadata (country, logo) added to matches by the match API (see internal/pkg/teaminfo)
+	TeamInfo TeamInfoConfig `yaml:"team_info"`
+	// Sports: parser name -> sports polled independently, each with its own interval ("default" = parsers not listed).
+	// Empty = one global ticker (interval) running ParseOnce on all parsers
+	Sports map[string][]SportScheduleConfig `yaml:"sports"`
 	Fonbet            FonbetConfig      `yaml:"fonbet"`
 	Pinnacle          PinnacleConfig    `yaml:"pinnacle"`
 	Pinnacle888       Pinnacle888Config `yaml:"pinnacle888"`
@@ -43,6 +117,19 @@ type ParserConfig struct {
 	Leon              LeonConfig        `yaml:"leon"`
 }
 
+// LeagueFilterConfig is parser.<name>.leagues: which tournaments a parser scrapes.
+// Patterns are case-insensitive regular expressions matched anywhere in the league name.
+type LeagueFilterConfig struct {
+	Include []string `yaml:"include"` // Only leagues matching any of these (empty = all leagues)
+	Exclude []string `yaml:"exclude"` // Leagues matching any of these are skipped (wins over include)
+}
+
+// SportScheduleConfig is one sport of parser.sports with its poll interval.
+type SportScheduleConfig struct {
+	Sport    string        `yaml:"sport"`    // enums.Sport value: football, tennis, dota2, cs; "esports" = all esports disciplines
+	Interval time.Duration `yaml:"interval"` // Poll interval of this sport (default: parser.interval)
+}
+
 // LeonConfig configures Leon (leon.ru) betline API parser.
 // API: sports → events/all per league → event/all per match (full line with corners, fouls).
 type LeonConfig struct {
@@ -50,11 +137,15 @@ type LeonConfig struct {
 	Timeout          time.Duration `yaml:"timeout"`             // HTTP timeout (default: use Parser.Timeout)
 	SportFamily      string        `yaml:"sport_family"`       // "Soccer" (default)
 	MaxLeagues       int           `yaml:"max_leagues"`        // 0 = all football leagues; >0 = limit for one cycle (e.g. 50)
+	MaxEventsPerCycle int          `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues          LeagueFilterConfig `yaml:"leagues"`    // Tournament include/exclude regexes (default: all leagues)
 	DelayPerLeague   time.Duration `yaml:"delay_per_league"`   // delay after each league (default: 0)
 	DelayPerEvent    time.Duration `yaml:"delay_per_event"`   // delay after each event (default: 0)
 	// Concurrency: like xbet1 (max_concurrent_championships + max_concurrent_games_per_champ)
 	MaxConcurrentLeagues        int `yaml:"max_concurrent_leagues"`         // leagues processed in parallel (default: 1)
 	MaxConcurrentEventsPerLeague int `yaml:"max_concurrent_events_per_league"` // GetEvent requests in parallel per league (default: 1)
+	RateLimit RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: unlimited)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
 }
 
 // OlimpConfig configures Olimp (olimp.bet) line API parser.
@@ -65,6 +156,11 @@ type OlimpConfig struct {
 	Timeout   time.Duration `yaml:"timeout"`   // HTTP timeout (default: use Parser.Timeout)
 	Referer   string        `yaml:"referer"`    // Referer for competitions-with-events (required; e.g. "https://www.olimp.bet/line/futbol-1/")
 	ProxyList []string      `yaml:"proxy_list"` // List of proxies to try in order
+	MaxLeagues int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues LeagueFilterConfig `yaml:"leagues"` // Tournament include/exclude regexes (default: all leagues)
+	RateLimit RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: unlimited)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
 }
 
 // ZenitConfig configures Zenit (zenitnow549.top) line API parser.
@@ -75,6 +171,10 @@ type ZenitConfig struct {
 	SportID      int           `yaml:"sport_id"`      // Sport ID (1 = Football, default: 1)
 	Timeout      time.Duration `yaml:"timeout"`       // HTTP timeout (default: use Parser.Timeout)
 	ProxyList    []string      `yaml:"proxy_list"`    // Optional: list of proxies to try in order
+	MaxLeagues   int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	RateLimit    RateLimitConfig `yaml:"rate_limit"`    // Request rate limit (default: unlimited)
+	Fingerprint  FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
 }
 
 // MarathonbetConfig configures Marathonbet HTML parser (all-events → leagues → event pages).
@@ -84,6 +184,30 @@ type MarathonbetConfig struct {
 	Timeout   time.Duration `yaml:"timeout"`    // HTTP timeout (default: 30s)
 	UserAgent string        `yaml:"user_agent"` // Override from Parser.UserAgent if empty
 	ProxyList []string      `yaml:"proxy_list"` // List of proxies to try in order
+	MaxLeagues int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues LeagueFilterConfig `yaml:"leagues"` // Tournament include/exclude regexes (default: all leagues)
+	// Concurrency: like leon; requests are still spaced by rate_limit, so this only overlaps slow pages
+	MaxConcurrentLeagues         int `yaml:"max_concurrent_leagues"`           // League pages processed in parallel (default: 1)
+	MaxConcurrentEventsPerLeague int `yaml:"max_concurrent_events_per_league"` // Event pages fetched in parallel per league (default: 1)
+	RateLimit RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: 2 rps, burst 1)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
+}
+
+// RateLimitConfig configures a token-bucket limiter for one parser's HTTP requests (parser.<name>.rate_limit).
+type RateLimitConfig struct {
+	RPS   float64 `yaml:"rps"`   // Requests per second across all clients of the parser (0 = parser default)
+	Burst int     `yaml:"burst"` // Max requests allowed back-to-back (default: 1)
+}
+
+// FingerprintConfig configures the browser identity of parser HTTP requests (see internal/pkg/fingerprint).
+// Pointer fields distinguish "not set" from false so parser.<name>.fingerprint can override parser.fingerprint.
+type FingerprintConfig struct {
+	UserAgents              []string          `yaml:"user_agents"`               // Rotated round-robin per request (default: parser's own UA or a built-in browser pool)
+	AcceptLanguages         []string          `yaml:"accept_languages"`          // Accept-Language values (empty = keep the parser's own)
+	RandomizeAcceptLanguage *bool             `yaml:"randomize_accept_language"` // Pick a random accept_languages entry per request instead of the first
+	ClientHints             *bool             `yaml:"client_hints"`              // Send Sec-CH-UA headers matching the User-Agent
+	Headers                 map[string]string `yaml:"headers"`                   // Extra headers set on every request
 }
 
 // IncrementalParsingConfig configures incremental parsing for each parser
@@ -100,6 +224,193 @@ type FonbetConfig struct {
 	BaseURL string `yaml:"base_url"`
 	Lang    string `yaml:"lang"`
 	Version string `yaml:"version"`
+	MaxLeagues int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues LeagueFilterConfig `yaml:"leagues"` // Tournament include/exclude regexes (default: all leagues)
+	RateLimit RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: unlimited)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
+	IncludeOutrights bool `yaml:"include_outrights"` // Parse tournament-winner events (no team pair) into /outrights instead of dropping them (default: false)
+	EventURL string `yaml:"event_url"` // Event page on the site linked from alerts, {id} = Fonbet event id, e.g. "https://fon.bet/sports/football/{id}" (empty = no links)
+}
+
+// ProxyPoolConfig configures the shared proxy pool used by parsers with proxy_list.
+type ProxyPoolConfig struct {
+	FailuresBeforeBan int           `yaml:"failures_before_ban"` // consecutive failures before a proxy is banned (default: 3)
+	BanDuration       time.Duration `yaml:"ban_duration"`        // how long a banned proxy is skipped (default: 5m)
+	DisableSticky     bool          `yaml:"disable_sticky"`      // don't prefer the proxy that worked last
+}
+
+// BrowserPoolConfig configures the shared headless Chrome pool (see internal/pkg/browserpool).
+type BrowserPoolConfig struct {
+	Size              int           `yaml:"size"`                 // max Chrome instances at once; more jobs wait in a queue (default: 1)
+	MaxJobsPerBrowser int           `yaml:"max_jobs_per_browser"` // restart Chrome after this many jobs (default: 50)
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`         // close Chrome after this long without jobs (default: 5m)
+	AcquireTimeout    time.Duration `yaml:"acquire_timeout"`      // max wait for a free browser (default: 2m)
+}
+
+// MirrorRegistryConfig configures the registry of resolved mirror URLs (see internal/pkg/mirrors).
+type MirrorRegistryConfig struct {
+	Dir         string        `yaml:"dir"`          // Directory for <parser>.json entries (empty = in memory only, lost on restart)
+	MaxAge      time.Duration `yaml:"max_age"`      // Persisted URLs older than this are resolved again at startup (default: 24h)
+	HistorySize int           `yaml:"history_size"` // Resolution history records kept per mirror (default: 20)
+}
+
+// DryRunConfig configures the parser dry-run mode of cmd/parser and cmd/bookmaker-service.
+type DryRunConfig struct {
+	Enabled bool          `yaml:"enabled"` // Parse once, write the result and exit (same as -dry-run)
+	Output  string        `yaml:"output"`  // JSON output file (empty or "-" = stdout; -dry-run-output overrides)
+	Timeout time.Duration `yaml:"timeout"` // Max duration of ParseOnce per parser (default: 10m)
+}
+
+// TeamInfoConfig configures the team/league metadata dataset (internal/pkg/teaminfo).
+type TeamInfoConfig struct {
+	File        string `yaml:"file"`          // Extra JSON dataset merged over the embedded one (same format; empty = embedded only)
+	LogoBaseURL string `yaml:"logo_base_url"` // Prefix for relative logo paths (empty = relative logos are omitted)
+}
+
+// EventLogConfig configures the append-only event log served at /events (see internal/pkg/eventlog).
+type EventLogConfig struct {
+	Dir           string        `yaml:"dir"`            // Directory for <service>.jsonl (empty = in memory only)
+	Capacity      int           `yaml:"capacity"`       // Events kept in memory, i.e. queryable (default: 10000)
+	MaxFileSizeMB int           `yaml:"max_file_size_mb"` // The file is rotated to <service>.jsonl.1 above this size (default: 50)
+	RemoteTimeout time.Duration `yaml:"remote_timeout"` // Timeout for merging /events of upstream services (default: 5s)
+}
+
+// TracingConfig configures OpenTelemetry tracing (see internal/pkg/tracing): spans of parse cycles, bookmaker
+// requests, the internal HTTP calls, value cycles, Postgres queries and Telegram sends, exported over OTLP/HTTP.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector, host:port (default: localhost:4318; env OTEL_EXPORTER_OTLP_ENDPOINT)
+	Insecure    bool    `yaml:"insecure"`     // Plain HTTP to the collector
+	SampleRatio float64 `yaml:"sample_ratio"` // Share of traces kept, 0..1 (default: 1); continued traces follow the caller
+}
+
+// AllInOneConfig is the single-process mode of cmd/allinone (profile configs/allinone.yaml): the local
+// parsers, their aggregated /matches, the calculator and the Telegram bot run in one process. Matches go to
+// the calculator over an in-memory bus and the calculator and bot call the other APIs in process; the
+// parser health server (health.port) and the calculator API (calculator_port) still listen for others.
+type AllInOneConfig struct {
+	CalculatorPort int  `yaml:"calculator_port"` // Calculator API and WebApp (default: 8080)
+	Bot            bool `yaml:"bot"`             // Run the Telegram bot in the process too (needs TELEGRAM_BOT_TOKEN)
+	MatchBuffer    int  `yaml:"match_buffer"`    // Matches queued from the parsers to the calculator (default: 10000)
+}
+
+// APIAuthConfig protects the HTTP APIs of the calculator, the parser (orchestrator) and the bookmaker services
+// with API keys, sent as X-API-Key or Authorization: Bearer <key> (see internal/pkg/apiauth). Roles: read (GET
+// requests), operator (also POST/DELETE: async controls, ignores, subscriptions, bets; parser controls), admin
+// (also /admin/*, /db/clear and /debug/*).
+type APIAuthConfig struct {
+	Enabled     bool           `yaml:"enabled"`
+	Services    []string       `yaml:"services"`     // Service name prefixes that require keys (default: calculator, parser, bookmaker-service)
+	Keys        []APIKeyConfig `yaml:"keys"`         // Env API_KEYS=name:role:key,... adds keys
+	PublicPaths []string       `yaml:"public_paths"` // Path prefixes served without a key (default: /ping, /health, /readyz, /metrics, /webapp/)
+}
+
+// APIKeyConfig is one API key of api_auth.keys.
+type APIKeyConfig struct {
+	Name string `yaml:"name"` // Who uses the key, for logs (e.g. telegram-bot)
+	Key  string `yaml:"key"`
+	Role string `yaml:"role"` // read, operator or admin
+}
+
+// ChaosConfig configures fault injection into the HTTP servers of bookmaker services and the
+// calculator (see internal/pkg/chaos). Test only: never enable it in production.
+type ChaosConfig struct {
+	Enabled             bool          `yaml:"enabled"`
+	Services            []string      `yaml:"services"`             // Service name prefixes to inject into, e.g. bookmaker-service, calculator (empty = all)
+	ExcludePaths        []string      `yaml:"exclude_paths"`        // Path prefixes left alone (default: /ping, /health, /readyz, /metrics)
+	LatencyProbability  float64       `yaml:"latency_probability"`  // Share of requests delayed, 0..1
+	MinLatency          time.Duration `yaml:"min_latency"`          // Injected delay is random in [min_latency, max_latency]
+	MaxLatency          time.Duration `yaml:"max_latency"`
+	ErrorProbability    float64       `yaml:"error_probability"`    // Share of requests answered with a 5xx instead of the handler
+	ErrorStatuses       []int         `yaml:"error_statuses"`       // Statuses to pick from (default: 500, 502, 503)
+	TruncateProbability float64       `yaml:"truncate_probability"` // Share of responses cut in half (the client sees an unexpected EOF)
+}
+
+// BusConfig is the message-bus fan-out of parsed matches (see internal/pkg/bus): every AddMatch of a
+// parser or bookmaker-service is published to NATS subject <subject_prefix>.<bookmaker>, and any number
+// of consumers (calculator, analytics, archiver) subscribe to <subject_prefix>.>.
+type BusConfig struct {
+	URL           string        `yaml:"url"`            // NATS server URL(s), comma-separated, e.g. "nats://nats:4222" (env NATS_URL)
+	SubjectPrefix string        `yaml:"subject_prefix"` // Subject prefix (default: "vodeneevbet.matches")
+	Publish       bool          `yaml:"publish"`        // parser / bookmaker-service publish every stored match
+	Consume       bool          `yaml:"consume"`        // Calculator takes football matches from the bus instead of GET parser_url/matches
+	QueueGroup    string        `yaml:"queue_group"`    // Consumers in one queue group share the messages (empty = every consumer gets all)
+	MatchTTL      time.Duration `yaml:"match_ttl"`      // Consumer drops a bookmaker's match not republished for this long (default: 10m)
+}
+
+// OddsConfig is the odds precision policy (see models.SetOddsPrecision): odds are stored as quoted,
+// compared with epsilon (line movements, history dedup, value diffs) and shown with decimals.
+type OddsConfig struct {
+	Epsilon  float64 `yaml:"epsilon"`  // Odds differing less are equal: 1.952 vs 1.95 (default: 0.005)
+	Decimals int     `yaml:"decimals"` // Decimals in alerts and bot messages, 2 or 3 (default: 2)
+}
+
+// TeamAliasesConfig is the team name alias dictionary consulted when matches are keyed (see
+// models.CanonicalTeamName). The embedded seed is always loaded; the calculator also loads the aliases added
+// through POST /admin/team-aliases (table team_aliases).
+type TeamAliasesConfig struct {
+	File string `yaml:"file"` // Extra JSON aliases merged over the embedded seed (same format; empty = embedded only)
+}
+
+// LeagueAliasesConfig is the tournament name alias dictionary (see models.CanonicalLeagueName): the calculator
+// replaces the tournaments of the bookmakers with the canonical names for filters, grouping and alerts.
+// The embedded seed is always loaded; the calculator also loads the aliases added through
+// POST /admin/league-aliases (table league_aliases).
+type LeagueAliasesConfig struct {
+	File string `yaml:"file"` // Extra JSON aliases merged over the embedded seed (same format; empty = embedded only)
+}
+
+// TelegramConfig is the formatting of Telegram messages (see internal/pkg/tgformat), shared by the calculator
+// alerts and the bot: every message type is rendered by a text/template per language in HTML parse mode.
+// Templates given here replace the built-in ones, so formatting changes without recompiling: "<type>" in
+// every language, "<language>/<type>" in one.
+type TelegramConfig struct {
+	Language     string            `yaml:"language"`      // Language of chats that didn't choose one with /lang: "en" or "ru" (default: "en")
+	TemplatesDir string            `yaml:"templates_dir"` // Directory of <type>.tmpl and <language>/<type>.tmpl files, e.g. ru/value_alert.tmpl (empty = none)
+	Templates    map[string]string `yaml:"templates"`     // "<type>" or "<language>/<type>" -> template text, takes precedence over templates_dir
+
+	Delivery TelegramDeliveryConfig `yaml:"delivery"`
+}
+
+// TelegramDeliveryConfig is telegram.delivery: rate limits and retries of outgoing messages (calculator alerts
+// and bot replies). Zero values take the defaults, which keep within the Bot API limits.
+type TelegramDeliveryConfig struct {
+	RPS             float64       `yaml:"rps"`               // Messages per second of the bot to all chats (default: 25, Telegram allows ~30)
+	PerChatInterval time.Duration `yaml:"per_chat_interval"` // Min interval between messages to one chat (default: 1s; groups allow 20/min, 3s never hits 429)
+	MaxRetries      int           `yaml:"max_retries"`       // Retries of a message after 429, 5xx or network errors (default: 3, -1 = none)
+	RetryBackoff    time.Duration `yaml:"retry_backoff"`     // First retry delay without retry_after, doubled every retry (default: 1s)
+}
+
+// DiscoveryConfig is parser.discovery: the orchestrator re-reads its bookmaker services every interval,
+// so new bookmaker-service VMs are picked up without a redeploy. Entries of bookmaker_services always stay.
+type DiscoveryConfig struct {
+	Mode     string        `yaml:"mode"`     // "dns" (SRV records), "file" (directory file) or empty = off
+	SRV      string        `yaml:"srv"`      // SRV record name for mode dns, e.g. "_bookmaker._tcp.bookmakers.internal"
+	Scheme   string        `yaml:"scheme"`   // URL scheme of SRV targets (default: "http")
+	File     string        `yaml:"file"`     // Directory file for mode file: YAML/JSON map name -> base URL
+	Interval time.Duration `yaml:"interval"` // How often services are re-read (default: 30s)
+}
+
+// GRPCConfig is parser.grpc: gRPC transport of matches between bookmaker services and the orchestrator.
+type GRPCConfig struct {
+	Enabled      bool              `yaml:"enabled"`       // Orchestrator subscribes to match streams instead of polling GET /matches (false = HTTP only)
+	Port         int               `yaml:"port"`          // bookmaker-service gRPC listen port (0 = no gRPC server); orchestrator dials host of bookmaker_services URL with this port
+	Services     map[string]string `yaml:"services"`      // Optional name -> host:port overriding the derived address
+	PushInterval time.Duration     `yaml:"push_interval"` // How often a bookmaker-service checks for changed odds to stream (default: 2s)
+}
+
+// SnapshotConfig configures warm-up snapshots of bookmaker-service (one file per parser: <dir>/<parser>.json).
+type SnapshotConfig struct {
+	Dir          string        `yaml:"dir"`           // Directory for snapshot files (empty = disabled)
+	SaveInterval time.Duration `yaml:"save_interval"` // How often the snapshot is written (default: 1m)
+	MaxAge       time.Duration `yaml:"max_age"`       // Older snapshots are ignored at startup (default: 6h)
+}
+
+// CircuitBreakerConfig configures per-host circuit breakers in parser HTTP clients.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"` // consecutive failures to open (default: 5; <0 = disabled)
+	Cooldown         time.Duration `yaml:"cooldown"`          // how long to stay open before a probe request (default: 2m)
 }
 
 type PinnacleConfig struct {
@@ -108,6 +419,11 @@ type PinnacleConfig struct {
 	DeviceUUID string   `yaml:"device_uuid"`
 	MatchupIDs []int64  `yaml:"matchup_ids"`
 	ProxyList  []string `yaml:"proxy_list"` // List of proxies to try in order
+	MaxLeagues int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	RateLimit  RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: unlimited)
+	Fingerprint FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
+	IncludeOutrights bool `yaml:"include_outrights"` // Also parse futures specials (league winner etc.) into /outrights (default: false)
 }
 
 type Pinnacle888Config struct {
@@ -125,6 +441,11 @@ type Pinnacle888Config struct {
 	XAppData        string `yaml:"x_app_data"`      // x-app-data header
 	XCustID         string `yaml:"x_custid"`         // x-custid header
 	UseAuthHeaders  bool   `yaml:"use_auth_headers"` // Enable authenticated headers for odds requests (default: false)
+	MaxLeagues      int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues         LeagueFilterConfig `yaml:"leagues"` // Tournament include/exclude regexes (default: all leagues)
+	RateLimit       RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: 2 rps, burst 1)
+	Fingerprint     FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
 }
 
 type Xbet1Config struct {
@@ -139,39 +460,317 @@ type Xbet1Config struct {
 	// Concurrency: 1 = sequential (safe for rate limits). Increase to speed up full cycle (risk of 429).
 	MaxConcurrentChampionships int `yaml:"max_concurrent_championships"` // Max championships processed in parallel (default: 1)
 	MaxConcurrentGamesPerChamp int `yaml:"max_concurrent_games_per_champ"` // Max GetGame requests in parallel per championship (default: 1)
+	MaxLeagues                 int `yaml:"max_leagues"` // 0 = all leagues; >0 = leagues processed per cycle
+	MaxEventsPerCycle          int `yaml:"max_events_per_cycle"` // 0 = unlimited; events processed per cycle across all leagues
+	Leagues                    LeagueFilterConfig `yaml:"leagues"` // Tournament include/exclude regexes (default: all leagues)
+	RateLimit                  RateLimitConfig `yaml:"rate_limit"` // Request rate limit (default: unlimited)
+	Fingerprint                FingerprintConfig `yaml:"fingerprint"` // Header profile overrides of parser.fingerprint
 }
 
 type ValueCalculatorConfig struct {
-	MinValuePercent  float64            `yaml:"min_value_percent"` // Minimum value percent for value bets (default: 5.0)
-	Sports           []string           `yaml:"sports"`            // Sports to parse (used by parsers)
-	BookmakerWeights map[string]float64 `yaml:"bookmaker_weights"` // Optional: weights for reference bookmakers (default: 1.0 for all)
-	ParserURL        string             `yaml:"parser_url"`        // URL to parser's /matches endpoint
+	MinValuePercent     float64            `yaml:"min_value_percent"`    // Minimum value percent for value bets (default: 5.0)
+	Sports              []string           `yaml:"sports"`               // Sports to parse (used by parsers)
+	BookmakerWeights    map[string]float64 `yaml:"bookmaker_weights"`    // Optional: weights for reference bookmakers (default: 1.0 for all)
+	ReferenceBookmakers []string           `yaml:"reference_bookmakers"` // Optional: only these bookmakers form the fair line; others are only compared against it (default: all)
+	FairOddsMethod      string             `yaml:"fair_odds_method"`     // Margin removal before averaging: none (default), equal, proportional, shin, power, logarithmic
+	ParserURL           string             `yaml:"parser_url"`           // URL to parser's /matches endpoint
 
 	// Async processing settings
-	AsyncEnabled         bool    `yaml:"async_enabled"`          // Enable async processing
-	AsyncInterval        string  `yaml:"async_interval"`         // Interval for async processing (e.g., "30s")
-	AlertThreshold       float64 `yaml:"alert_threshold"`        // Single alert threshold in percent (preferred)
-	AlertThreshold10     float64 `yaml:"alert_threshold_10"`     // Alert threshold for 10% diffs (backward compatibility)
-	AlertThreshold20     float64 `yaml:"alert_threshold_20"`     // Alert threshold for 20% diffs (backward compatibility)
-	AlertCooldownMinutes int     `yaml:"alert_cooldown_minutes"` // Minutes to wait before sending duplicate alerts for same diff (default: 60)
-	AlertMinIncrease     float64 `yaml:"alert_min_increase"`     // Minimum diff_percent increase to send alert again (default: 5.0)
-	MaxOdds              float64 `yaml:"max_odds"`               // Max odds for alerts and value bets; 0 = no limit (high odds have more variance)
-	TelegramBotToken     string  `yaml:"telegram_bot_token"`     // Telegram bot token for notifications
-	TelegramChatID       int64   `yaml:"telegram_chat_id"`       // Telegram chat ID to send notifications
+	AsyncEnabled             bool    `yaml:"async_enabled"`              // Enable async processing
+	AsyncInterval            string  `yaml:"async_interval"`             // Interval for async processing (e.g., "30s")
+	AlertThreshold           float64 `yaml:"alert_threshold"`            // Single alert threshold in percent (preferred)
+	AlertThreshold10         float64 `yaml:"alert_threshold_10"`         // Alert threshold for 10% diffs (backward compatibility)
+	AlertThreshold20         float64 `yaml:"alert_threshold_20"`         // Alert threshold for 20% diffs (backward compatibility)
+	AlertCooldownMinutes     int     `yaml:"alert_cooldown_minutes"`     // Minutes before the same value (bet + bookmaker) is alerted again while it persists or reappears (default: 60)
+	AlertMinIncrease         float64 `yaml:"alert_min_increase"`         // diff_percent increase since the last alert that re-alerts within the cooldown (default: 5.0)
+	AlertClosedNotifications *bool   `yaml:"alert_closed_notifications"` // Notify when an alerted value disappears (default: true)
+	MaxOdds                  float64 `yaml:"max_odds"`                   // Max odds for alerts and value bets; 0 = no limit (high odds have more variance)
+	TelegramBotToken         string  `yaml:"telegram_bot_token"`         // Telegram bot token for notifications
+	TelegramChatID           int64   `yaml:"telegram_chat_id"`           // Telegram chat ID of the default alert stream (0 = only /subscriptions get alerts)
 
 	// Line movement: track any odds change within same bookmaker
 	LineMovementEnabled           bool    `yaml:"line_movement_enabled"`             // Enable tracking of odds changes in same bookmaker
 	LineMovementAlertThreshold    float64 `yaml:"line_movement_alert_threshold"`     // Min change in % to alert, e.g. 5.0 for 5%
+	LineMovementAlertThresholdPP  float64 `yaml:"line_movement_alert_threshold_pp"`  // Min implied probability shift in percentage points to alert, e.g. 3.0 (0 = off); either threshold is enough
 	LineMovementTelegramAlerts    bool    `yaml:"line_movement_telegram_alerts"`     // Send line movement alerts to Telegram (default: false to avoid spam; tracking still runs if line_movement_enabled)
+	LineMovementCharts            bool    `yaml:"line_movement_charts"`              // Reply to line movement alerts with an odds-over-time chart (PNG)
+
+	// Steam: the same outcome moving at several bookmakers at once (from odds history; requires line_movement_enabled)
+	Steam SteamConfig `yaml:"steam"`
+
+	// Ignored matches (POST /ignores, "🚫 Ignore match" button under alerts): excluded from calculation until expiry
+	IgnoreTTL time.Duration `yaml:"ignore_ttl"` // Expiry when the request sets neither expires_at nor ttl (default: 24h)
 
-	// DB full cleanup: truncate diff_bets, odds_snapshots, odds_snapshot_history periodically (only actual data needed)
+	// DB full cleanup: truncate diff_bets, odds_snapshots periodically (only actual data needed)
 	DBFullCleanupInterval string `yaml:"db_full_cleanup_interval"` // e.g. "2h"; default: "2h"; empty = disabled
+
+	// Odds history (odds_snapshot_history): day partitions dropped after the retention, periodic full snapshots
+	OddsHistory OddsHistoryConfig `yaml:"odds_history"`
+
+	// Telegram WebApp (MiniApp) with value bets and odds matrices, served at /webapp/ and opened from the bot menu button
+	WebApp WebAppConfig `yaml:"webapp"`
+
+	// Publishing of alerts to Telegram channels and groups besides telegram_chat_id and /subscriptions
+	Telegram CalculatorTelegramConfig `yaml:"telegram"`
+
+	// Research warehouse: nightly ETL of odds history into the denormalized research schema (bets_wide + dimensions)
+	Warehouse WarehouseConfig `yaml:"warehouse"`
+
+	// Cyber football (FIFA / eFootball leagues tagged cyber_football): opt-in value pipeline kept apart from real football
+	Cyber CyberConfig `yaml:"cyber"`
+
+	// Arbitrage (surebets): complementary outcomes whose best odds across bookmakers guarantee a profit
+	Arbitrage ArbitrageConfig `yaml:"arbitrage"`
+
+	// Per-market / per-bookmaker value thresholds: the first matching rule overrides min_value_percent and max_odds
+	Rules []ValueRule `yaml:"rules"`
+
+	// Bookmaker coverage of a bet key before a value bet is emitted: one stale outlier line must not make alerts
+	MinBookmakers      int      `yaml:"min_bookmakers"`       // Bookmakers quoting the bet, the value bookmaker included (default: 2)
+	MinSharpBookmakers int      `yaml:"min_sharp_bookmakers"` // Of which sharp (default: 0)
+	SharpBookmakers    []string `yaml:"sharp_bookmakers"`     // Sharp bookmakers (default: reference_bookmakers)
+
+	// Stale odds: quotes not refreshed by the parser within the window are left out of value bets
+	Freshness FreshnessConfig `yaml:"freshness"`
+
+	// Recommended stakes in value bet output (percent of bankroll)
+	Staking StakingConfig `yaml:"staking"`
+
+	// Bet tracking: POST /bets, GET /bets, bot /bet and /mybets; settled from match results
+	Bets BetsConfig `yaml:"bets"`
+
+	// Results source for bet settlement
+	Results ResultsConfig `yaml:"results"`
+
+	// Value bet lifecycles (first seen, last seen, max value, why it ended): GET /value-bets/history
+	ValueHistory ValueHistoryConfig `yaml:"value_history"`
+
+	// Daily/weekly digest of value bets and alerts to telegram_chat_id and the subscriptions receiving "digest"
+	Digest DigestConfig `yaml:"digest"`
+
+	// Cross-market consistency of a bookmaker's own lines (1X2 vs double chance vs Asian 0): GET /diagnostics/inconsistencies
+	Consistency ConsistencyConfig `yaml:"consistency"`
+
+	// Why each diff of an async value cycle was or wasn't alerted, per chat: GET /diagnostics/decisions
+	DecisionLog DecisionLogConfig `yaml:"decision_log"`
+
+	// Several calculator replicas on one database: only the leader runs the async cycle and sends alerts
+	LeaderElection LeaderElectionConfig `yaml:"leader_election"`
+
+	// Redis cache of /value-bets/top, /line-movements/top and the merged match snapshot, shared by the replicas
+	RedisCache RedisCacheConfig `yaml:"redis_cache"`
+
+	// gRPC read API (api/proto/calculator/v1): value bets, line movements and match odds
+	GRPC CalculatorGRPCConfig `yaml:"grpc"`
+}
+
+// CalculatorGRPCConfig configures the gRPC server of the calculator's read API. It serves the same data as
+// GET /value-bets/top and /line-movements/top with typed models; api_auth keys apply to it as well.
+type CalculatorGRPCConfig struct {
+	Port int `yaml:"port"` // Listen port (0 = no gRPC server)
+}
+
+// RedisCacheConfig configures the optional Redis cache of hot calculator queries. Responses are cached per
+// path and query string, so the bot and the dashboard polling the same top lists hit Postgres and the
+// parser once per ttl. Redis errors are logged and the query is served uncached.
+type RedisCacheConfig struct {
+	Addr       string        `yaml:"addr"`        // host:port; empty = cache disabled
+	Password   string        `yaml:"password"`    // AUTH password (optional)
+	DB         int           `yaml:"db"`          // Database number (default: 0)
+	KeyPrefix  string        `yaml:"key_prefix"`  // Prefix of the cache keys (default: "vodeneevbet:")
+	TTL        time.Duration `yaml:"ttl"`         // Lifetime of cached /value-bets/top and /line-movements/top responses (default: 10s)
+	MatchesTTL time.Duration `yaml:"matches_ttl"` // Lifetime of the cached merged match snapshot (default: 5s; <0 = not cached)
+}
+
+// LeaderElectionConfig configures leader election between calculator replicas. The leader holds a Postgres
+// advisory lock and saves the cycle state (alert state machines, steam alert cooldowns) after every cycle;
+// a replica taking the lock over restores it, so failover neither repeats nor loses alerts. Followers keep
+// serving the HTTP API.
+type LeaderElectionConfig struct {
+	Enabled       bool          `yaml:"enabled"`        // Run the async cycle only on the replica holding the lock (requires postgres)
+	LockKey       int64         `yaml:"lock_key"`       // Advisory lock key shared by the replicas (default: 7351001)
+	RenewInterval time.Duration `yaml:"renew_interval"` // How often the lock is checked and followers try to take it (default: 10s)
+}
+
+// ConsistencyConfig configures the cross-market consistency validator. Double chance and draw no bet
+// (Asian handicap 0) prices are derived from the bookmaker's own 1X2; a quoted price that deviates more
+// than max_deviation_percent is most likely a parsing bug (swapped outcomes, wrong market).
+type ConsistencyConfig struct {
+	MaxDeviationPercent float64 `yaml:"max_deviation_percent"` // Allowed deviation of a quoted price from the derived one (default: 10)
+	Drop                *bool   `yaml:"drop"`                  // Leave the bookmaker's 1X2, double chance and Asian 0 lines of the match out of calculation (default: true)
+}
+
+// DecisionLogConfig configures the audit log of alert decisions: every diff of an async value cycle with what
+// was decided for each chat — alert queued, below threshold, filtered by the subscription, stale quote, cooldown.
+type DecisionLogConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Backend        string        `yaml:"backend"`          // postgres (default) or file
+	Dir            string        `yaml:"dir"`              // Directory of alert_decisions-YYYY-MM-DD.jsonl for backend file
+	Retention      time.Duration `yaml:"retention"`        // Decisions are deleted after (default: 72h)
+	MinDiffPercent float64       `yaml:"min_diff_percent"` // Diffs below it are not logged (default: 0 = all)
+}
+
+// ValueHistoryConfig configures value bet lifecycle tracking. Every async cycle computes the value bets
+// of GET /value-bets/top and stores one row per continuous stretch in Postgres (table value_bet_history).
+type ValueHistoryConfig struct {
+	Enabled bool `yaml:"enabled"` // Track value bets on every async cycle (requires postgres)
+}
+
+// DigestConfig configures the scheduled digests: value bets first seen in the last 24 hours or 7 days (count,
+// top 5 by value, average CLV against the closing fair odd) and the alerts sent per bookmaker. The value bets
+// come from value_history, which must be enabled.
+type DigestConfig struct {
+	Daily     bool   `yaml:"daily"`      // Digest of the last 24 hours every day at At
+	Weekly    bool   `yaml:"weekly"`     // Digest of the last 7 days on WeeklyDay at At
+	At        string `yaml:"at"`         // Time of the digests, "HH:MM" UTC (default: "09:00")
+	WeeklyDay string `yaml:"weekly_day"` // Day of the weekly digest: monday ... sunday (default: monday)
+}
+
+// BetsConfig configures bet tracking. Bets are stored in Postgres (table bets) and settled by a
+// background job once the results source knows the final score.
+type BetsConfig struct {
+	Enabled        bool          `yaml:"enabled"`         // Enable /bets (requires postgres)
+	SettleInterval time.Duration `yaml:"settle_interval"` // How often open bets are checked (default: 15m)
+	SettleDelay    time.Duration `yaml:"settle_delay"`    // Time after kick-off before a match is looked up (default: 2h)
+}
+
+// ResultsConfig configures the results source (package results). Final results are cached in
+// Postgres (table match_results) when cache is on.
+type ResultsConfig struct {
+	Provider string        `yaml:"provider"` // "http" (JSON feed at url) or "football_data" (football-data.org); default: http when url is set
+	URL      string        `yaml:"url"`      // JSON results feed, or football-data API base URL (default: https://api.football-data.org/v4)
+	APIKey   string        `yaml:"api_key"`  // football-data.org token (or FOOTBALL_DATA_API_KEY env var)
+	Timeout  time.Duration `yaml:"timeout"`  // Request timeout (default: 15s)
+	Cache    bool          `yaml:"cache"`    // Cache final results in Postgres (table match_results)
+}
+
+// SteamConfig configures line-movement analytics over odds history: velocity per window, line classes
+// (opener, shortening, drifting) and steam, GET /line-movements/steam.
+type SteamConfig struct {
+	Window          time.Duration   `yaml:"window"`           // Moves within this window count as simultaneous (default: 10m)
+	MinBookmakers   int             `yaml:"min_bookmakers"`   // Bookmakers moving the same way (default: 2)
+	MinShiftPP      float64         `yaml:"min_shift_pp"`     // Min implied probability shift per bookmaker in pp (default: 2.0)
+	VelocityWindows []time.Duration `yaml:"velocity_windows"` // Windows of the velocity (pp per minute) of every leg (default: 5m, 15m, 1h)
+	OpenerAge       time.Duration   `yaml:"opener_age"`       // A line first quoted less than this ago is an opener (default: 30m)
+	TelegramAlerts  bool            `yaml:"telegram_alerts"`  // Send steam alerts to Telegram
+}
+
+// ValueRule sets the value thresholds of the value bets it matches (value_calculator.rules).
+// Rules are checked in order and the first match wins; empty match fields match anything.
+// A value bet no rule matches uses min_value_percent and max_odds.
+type ValueRule struct {
+	Name string `yaml:"name"` // For logs and docs only
+
+	// Match
+	Sport     string  `yaml:"sport"`      // e.g. football, dota2
+	EventType string  `yaml:"event_type"` // e.g. main_match, corners
+	Bookmaker string  `yaml:"bookmaker"`  // e.g. fonbet
+	OddsFrom  float64 `yaml:"odds_from"`  // Bookmaker odd >= odds_from (0 = any)
+	OddsTo    float64 `yaml:"odds_to"`    // Bookmaker odd < odds_to (0 = any)
+
+	// Thresholds
+	MinValuePercent    float64 `yaml:"min_value_percent"`    // default: min_value_percent
+	MinOdds            float64 `yaml:"min_odds"`             // default: no limit
+	MaxOdds            float64 `yaml:"max_odds"`             // default: max_odds
+	MaxFairProbability float64 `yaml:"max_fair_probability"` // Skip outcomes more likely than this, e.g. 0.8 (default: no limit)
+}
+
+// FreshnessConfig excludes stale quotes from the fair line and from value detection. A quote's age is
+// the time since the parser last saw it (outcome updated_at); quotes without a timestamp are kept.
+type FreshnessConfig struct {
+	MaxAge     time.Duration            `yaml:"max_age"`    // Default window, e.g. 5m (0 = no limit)
+	Bookmakers map[string]time.Duration `yaml:"bookmakers"` // Per-bookmaker windows for slow parsers, e.g. {marathonbet: 15m}
+}
+
+// StakingConfig configures the recommended stakes of value bets: full Kelly is always reported,
+// fractional Kelly scales it down (full Kelly is too aggressive for noisy fair odds).
+type StakingConfig struct {
+	KellyFraction   float64 `yaml:"kelly_fraction"`    // Fraction of full Kelly, e.g. 0.25 for quarter Kelly (default: 0.25)
+	FlatPercent     float64 `yaml:"flat_percent"`      // Flat stake in percent of bankroll (default: 1.0)
+	MaxStakePercent float64 `yaml:"max_stake_percent"` // Cap for the fractional Kelly stake in percent (default: 0 = no cap)
+}
+
+// ArbitrageConfig configures surebet detection. GET /arbs/top always works on fresh matches;
+// when enabled, the async cycle also stores found arbitrages in Postgres (table arbitrages).
+type ArbitrageConfig struct {
+	Enabled          bool    `yaml:"enabled"`            // Store arbitrages on every async cycle (requires postgres)
+	MinProfitPercent float64 `yaml:"min_profit_percent"` // Minimum guaranteed profit in percent (default: 0 = any arbitrage)
+	MaxProfitPercent float64 `yaml:"max_profit_percent"` // Above this an "arbitrage" is most likely a mapping error (default: 15; <0 = no limit)
+}
+
+// CyberConfig configures the cyber football value pipeline. Cyber matches are always excluded from
+// the main pipeline; when enabled they are priced on their own faster cycle and alerted separately.
+type CyberConfig struct {
+	Enabled        bool    `yaml:"enabled"`         // Run the cyber football pipeline
+	Interval       string  `yaml:"interval"`        // Cycle interval, e.g. "10s" (default: 10s; cyber matches last ~8-12 minutes)
+	AlertThreshold float64 `yaml:"alert_threshold"` // Alert threshold in percent (default: value_calculator.alert_threshold)
+	MaxOdds        float64 `yaml:"max_odds"`        // Max odds for cyber alerts; 0 = value_calculator.max_odds
+}
+
+// OddsHistoryConfig is value_calculator.odds_history. History only gets a point when an odd changes; every
+// keyframe_interval all current odds are written (keyframe) so any moment can be rebuilt from the last keyframe.
+type OddsHistoryConfig struct {
+	Retention        time.Duration `yaml:"retention"`         // Day partitions older than this are dropped (default: 168h; <0 = keep all)
+	KeyframeInterval time.Duration `yaml:"keyframe_interval"` // How often a full snapshot is written (default: 1h; <0 = only changes)
+}
+
+// WarehouseConfig configures the ETL into the research schema (see storage.PostgresWarehouseStorage).
+// The ETL also runs right before every db_full_cleanup, while odds_snapshots still has the match names.
+type WarehouseConfig struct {
+	Enabled bool   `yaml:"enabled"` // Create the research schema and run the ETL (requires postgres)
+	RunAt   string `yaml:"run_at"`  // Time of the nightly run, "HH:MM" UTC (default: "03:00")
+}
+
+// CalculatorTelegramConfig is value_calculator.telegram.
+type CalculatorTelegramConfig struct {
+	Channels []TelegramChannelConfig `yaml:"channels"` // Channels and groups the alerts are published to, e.g. one per alert type
+	// DedupWindow: a user who got an alert of a bet, directly or in a channel or group they are a member of,
+	// does not get it again in their private chat within this window (default: 30m; negative = off)
+	DedupWindow time.Duration `yaml:"dedup_window"`
+}
+
+// TelegramChannelConfig is a channel or group the calculator publishes alerts to. The bot must be an admin
+// allowed to post messages (and to pin them for the daily summary). Alerts of the chat go through the same
+// filters as a /subscriptions entry with these settings.
+type TelegramChannelConfig struct {
+	Name       string   `yaml:"name"`        // Shown in the daily summary and logs
+	ChatID     int64    `yaml:"chat_id"`     // Channel or group id, e.g. -1001234567890
+	ThreadID   int      `yaml:"thread_id"`   // Topic of a forum group (message_thread_id; 0 = the general chat)
+	AlertTypes []string `yaml:"alert_types"` // value, value_closed, line_movement, steam, digest (empty = all)
+
+	MinDiffPercent         float64  `yaml:"min_diff_percent"`          // Value alerts from this diff (0 = alert_threshold)
+	MinLineMovementPercent float64  `yaml:"min_line_movement_percent"` // Line movement alerts from this change (0 = any alerted movement)
+	Sports                 []string `yaml:"sports"`                    // Only these sports (empty = all)
+	Leagues                []string `yaml:"leagues"`                   // Only tournaments containing one of them (empty = all)
+	Bookmakers             []string `yaml:"bookmakers"`                // Only alerts of these bookmakers (empty = all)
+	MinOdds                float64  `yaml:"min_odds"`                  // Odds range of the bet (0 = no limit)
+	MaxOdds                float64  `yaml:"max_odds"`
+
+	DailySummary bool   `yaml:"daily_summary"` // Post the day's alerts of the chat once a day and pin the post
+	SummaryAt    string `yaml:"summary_at"`    // Time of the summary, "HH:MM" UTC (default: "23:00")
+}
+
+// WebAppConfig configures the Telegram WebApp of the calculator. Requests are authenticated by
+// Telegram initData signed with telegram_bot_token.
+type WebAppConfig struct {
+	Enabled        bool          `yaml:"enabled"`          // Serve /webapp/ and /webapp/api/*
+	AllowedUserIDs []int64       `yaml:"allowed_user_ids"` // Telegram user IDs allowed to open the WebApp (empty = any user of the bot; env ALLOWED_USERS)
+	AuthMaxAge     time.Duration `yaml:"auth_max_age"`     // Max age of initData (auth_date) (default: 24h)
 }
 
 type HealthConfig struct {
 	ReadHeaderTimeout   time.Duration `yaml:"read_header_timeout"`   // HTTP server read header timeout (default: 5s)
 	Port                int           `yaml:"port"`                  // HTTP server listen port (default: 8080)
 	AsyncParsingTimeout time.Duration `yaml:"async_parsing_timeout"` // Timeout for async parsing triggered by /matches endpoint (default: 10s)
+	// MatchStore bounds the in-memory matches of the parser and bookmaker-service between restarts
+	MatchStore MatchStoreConfig `yaml:"match_store"`
+}
+
+// MatchStoreConfig limits the in-memory match store (health.StartMatchEviction).
+type MatchStoreConfig struct {
+	MaxMatches    int           `yaml:"max_matches"`    // Least recently updated matches are evicted above this (default: 100000; <0 = unlimited)
+	Retention     time.Duration `yaml:"retention"`      // Matches are evicted this long after their start time (default: 4h; <0 = never)
+	EvictInterval time.Duration `yaml:"evict_interval"` // How often expired matches are evicted (default: 1m)
 }
 
 type LoggingConfig struct {
@@ -186,6 +785,17 @@ type LoggingConfig struct {
 	ProjectLabel string `yaml:"project_label"` // Название проекта (по умолчанию "vodeneevbet")
 	ServiceLabel string `yaml:"service_label"` // Название сервиса (по умолчанию имя сервиса из кода)
 	ClusterLabel string `yaml:"cluster_label"` // Название кластера/каталога (по умолчанию "production")
+	// Модуль лога — атрибут "logger" или префикс сообщения до ":" ("Marathonbet: match added" → "marathonbet")
+	Modules  map[string]string            `yaml:"modules"`  // Уровень по модулям: marathonbet: WARN, 1xbet: DEBUG
+	Sampling map[string]LogSamplingConfig `yaml:"sampling"` // Сэмплирование по модулям ("*" — для остальных)
+}
+
+// LogSamplingConfig limits how often the same message of a module is logged: within each interval the
+// first First records of a message (and level) pass, then every Thereafter-th; 0 drops the rest.
+type LogSamplingConfig struct {
+	First      int           `yaml:"first"`
+	Thereafter int           `yaml:"thereafter"`
+	Interval   time.Duration `yaml:"interval"` // default: 1m
 }
 
 func Load(configPath string) (*Config, error) {
@@ -201,3 +811,26 @@ func Load(configPath string) (*Config, error) {
 
 	return &config, nil
 }
+
+// ProfilePath returns the file of a config profile: profiles/<profile>.yaml next to configPath.
+func ProfilePath(configPath, profile string) string {
+	return filepath.Join(filepath.Dir(configPath), "profiles", profile+".yaml")
+}
+
+// LoadProfile loads configPath with the profile file (ProfilePath) on top: keys set in the profile replace
+// those of configPath, maps are merged key by key (null clears one). An empty profile is just Load.
+func LoadProfile(configPath, profile string) (*Config, error) {
+	config, err := Load(configPath)
+	if err != nil || profile == "" {
+		return config, err
+	}
+	path := ProfilePath(configPath, profile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config profile %q: %w", profile, err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config profile %s: %w", path, err)
+	}
+	return config, nil
+}
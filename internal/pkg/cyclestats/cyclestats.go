@@ -0,0 +1,148 @@
+// Package cyclestats counts what the current parsing cycle of each parser did — leagues fetched,
+// requests to the bookmaker, their latency and proxies — for the parse reports on /stats.
+//
+// Counters are kept by parser name (case-insensitive): parserutil calls Begin when a cycle starts
+// and Fill on its report, the bookmaker transports (circuitbreaker.WrapParser) call Request and
+// parserutil.CycleLimits calls Leagues. Overlapping runs of one parser share the counters.
+// The transports also count the requests in flight (InFlight), across cycles.
+package cyclestats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+// Direct is the proxy of requests sent without one.
+const Direct = "direct"
+
+type counters struct {
+	leagues  int
+	requests int
+	errors   int
+	latency  time.Duration // sum over requests
+	proxies  map[string]int
+}
+
+var (
+	mu       sync.Mutex
+	current  = map[string]*counters{}
+	inFlight = map[string]int{}
+)
+
+// countersLocked returns the counters of parser, creating them; mu must be held.
+func countersLocked(parser string) *counters {
+	key := strings.ToLower(parser)
+	c := current[key]
+	if c == nil {
+		c = &counters{proxies: map[string]int{}}
+		current[key] = c
+	}
+	return c
+}
+
+// Begin starts the counters of a new cycle of parser.
+func Begin(parser string) {
+	if parser == "" {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current[strings.ToLower(parser)] = &counters{proxies: map[string]int{}}
+}
+
+// Request counts a request of parser sent through proxy (Direct or "" without one) that took latency;
+// failed is a network error or a 403, 429 or 5xx response.
+func Request(parser, proxy string, latency time.Duration, failed bool) {
+	if parser == "" {
+		return
+	}
+	if proxy == "" {
+		proxy = Direct
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	c := countersLocked(parser)
+	c.requests++
+	c.latency += latency
+	if failed {
+		c.errors++
+	}
+	c.proxies[proxy]++
+}
+
+// InFlight counts a request of parser as in flight until the returned done is called.
+func InFlight(parser string) (done func()) {
+	if parser == "" {
+		return func() {}
+	}
+	key := strings.ToLower(parser)
+	mu.Lock()
+	inFlight[key]++
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if inFlight[key]--; inFlight[key] <= 0 {
+			delete(inFlight, key)
+		}
+	}
+}
+
+// InFlightRequests returns the requests in flight by parser (parsers without any are left out).
+func InFlightRequests() map[string]int {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make(map[string]int, len(inFlight))
+	for parser, n := range inFlight {
+		out[parser] = n
+	}
+	return out
+}
+
+// Leagues counts n leagues the cycle of parser goes on to fetch.
+func Leagues(parser string, n int) {
+	if parser == "" || n <= 0 {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	countersLocked(parser).leagues += n
+}
+
+// Fill copies the counters of the current cycle of r.Parser into r.
+func Fill(r *interfaces.ParseReport) {
+	mu.Lock()
+	defer mu.Unlock()
+	c := current[strings.ToLower(r.Parser)]
+	if c == nil {
+		return
+	}
+	r.LeaguesFetched = c.leagues
+	r.HTTPRequests = c.requests
+	r.HTTPErrors = c.errors
+	r.AvgLatency = ""
+	if c.requests > 0 {
+		r.AvgLatency = (c.latency / time.Duration(c.requests)).Round(time.Millisecond).String()
+	}
+	r.Proxy = mostUsed(c.proxies)
+}
+
+// mostUsed returns the proxy of most requests (the first by name on a tie).
+func mostUsed(proxies map[string]int) string {
+	names := make([]string, 0, len(proxies))
+	for name := range proxies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best := ""
+	for _, name := range names {
+		if best == "" || proxies[name] > proxies[best] {
+			best = name
+		}
+	}
+	return best
+}
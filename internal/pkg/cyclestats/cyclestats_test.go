@@ -0,0 +1,49 @@
+package cyclestats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+func TestCycleCounters(t *testing.T) {
+	Begin("Fonbet")
+	Leagues("fonbet", 3)
+	Leagues("Fonbet", 2)
+	Request("Fonbet", "10.0.0.1:3128", 100*time.Millisecond, false)
+	Request("Fonbet", "10.0.0.1:3128", 300*time.Millisecond, true)
+	Request("Fonbet", "", 200*time.Millisecond, false)
+	Request("Leon", "", time.Second, false)
+
+	r := interfaces.ParseReport{Parser: "fonbet"}
+	Fill(&r)
+	if r.LeaguesFetched != 5 || r.HTTPRequests != 3 || r.HTTPErrors != 1 {
+		t.Errorf("counts %+v, want 5 leagues, 3 requests, 1 error", r)
+	}
+	if r.AvgLatency != "200ms" || r.Proxy != "10.0.0.1:3128" {
+		t.Errorf("avg latency %q, proxy %q; want 200ms through 10.0.0.1:3128", r.AvgLatency, r.Proxy)
+	}
+
+	// A new cycle starts from zero
+	Begin("Fonbet")
+	r = interfaces.ParseReport{Parser: "Fonbet"}
+	Fill(&r)
+	if r.HTTPRequests != 0 || r.LeaguesFetched != 0 || r.AvgLatency != "" || r.Proxy != "" {
+		t.Errorf("after Begin: %+v, want empty counters", r)
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	done1 := InFlight("Marathonbet")
+	done2 := InFlight("marathonbet")
+	InFlight("")()
+	if got := InFlightRequests()["marathonbet"]; got != 2 {
+		t.Errorf("in flight = %d, want 2", got)
+	}
+	done1()
+	done2()
+	if _, ok := InFlightRequests()["marathonbet"]; ok {
+		t.Error("parser without requests in flight is listed")
+	}
+}
@@ -0,0 +1,191 @@
+// Package dryrun validates parsers offline: each parser runs ParseOnce once and everything it
+// publishes via health.AddMatch (and the esports/outright variants) is written to a JSON file or
+// stdout instead of the in-memory stores, so no health server, calculator or storage is needed.
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+const DefaultTimeout = 10 * time.Minute
+
+// ParserResult is the outcome of one parser's ParseOnce.
+type ParserResult struct {
+	Name           string `json:"name"`
+	Duration       string `json:"duration"`
+	Matches        int    `json:"matches"` // AddMatch calls (before merging by match ID)
+	Events         int    `json:"events"`
+	Outcomes       int    `json:"outcomes"`
+	EsportsMatches int    `json:"esports_matches"`
+	Outrights      int    `json:"outrights"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Result is the JSON document written by Run. Matches are merged by ID like in the health store.
+type Result struct {
+	StartedAt      time.Time             `json:"started_at"`
+	Parsers        []ParserResult        `json:"parsers"`
+	Matches        []models.Match        `json:"matches"`
+	EsportsMatches []models.EsportsMatch `json:"esports_matches,omitempty"`
+	Outrights      []models.Outright     `json:"outrights,omitempty"`
+}
+
+// collector is the health.Sink of a dry run.
+type collector struct {
+	mu        sync.Mutex
+	matches   []models.Match
+	esports   []models.EsportsMatch
+	outrights []models.Outright
+}
+
+func (c *collector) AddMatch(match *models.Match) {
+	m := *match
+	m.Events = append([]models.Event(nil), match.Events...)
+	c.mu.Lock()
+	c.matches = append(c.matches, m)
+	c.mu.Unlock()
+}
+
+func (c *collector) AddEsportsMatch(match *models.EsportsMatch) {
+	m := *match
+	m.Markets = append([]models.EsportsMarket(nil), match.Markets...)
+	c.mu.Lock()
+	c.esports = append(c.esports, m)
+	c.mu.Unlock()
+}
+
+func (c *collector) AddOutright(o *models.Outright) {
+	oc := *o
+	oc.Outcomes = append([]models.OutrightOutcome(nil), o.Outcomes...)
+	c.mu.Lock()
+	c.outrights = append(c.outrights, oc)
+	c.mu.Unlock()
+}
+
+// counts returns the number of collected items (used to attribute them to the parser that just ran).
+func (c *collector) counts() (matches, events, outcomes, esports, outrights int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.matches {
+		events += len(m.Events)
+		for _, ev := range m.Events {
+			outcomes += len(ev.Outcomes)
+		}
+	}
+	return len(c.matches), events, outcomes, len(c.esports), len(c.outrights)
+}
+
+// Run runs ParseOnce of each parser in turn (at most cfg.Timeout each) and writes the Result to
+// cfg.Output. Returns an error if any parser failed; the output is written anyway.
+func Run(ctx context.Context, parsers []interfaces.Parser, cfg config.DryRunConfig) error {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	c := &collector{}
+	health.SetSink(c)
+	defer health.SetSink(nil)
+
+	res := Result{StartedAt: time.Now().UTC(), Parsers: make([]ParserResult, 0, len(parsers))}
+	failed := 0
+	for _, p := range parsers {
+		m0, e0, o0, es0, or0 := c.counts()
+		slog.Info("Dry run: parsing", "parser", p.GetName(), "timeout", timeout)
+
+		parseCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := p.ParseOnce(parseCtx)
+		took := time.Since(start)
+		cancel()
+
+		m1, e1, o1, es1, or1 := c.counts()
+		pr := ParserResult{
+			Name:           p.GetName(),
+			Duration:       took.Round(time.Millisecond).String(),
+			Matches:        m1 - m0,
+			Events:         e1 - e0,
+			Outcomes:       o1 - o0,
+			EsportsMatches: es1 - es0,
+			Outrights:      or1 - or0,
+		}
+		if err != nil {
+			failed++
+			pr.Error = err.Error()
+			slog.Error("Dry run: parser failed", "parser", pr.Name, "duration", pr.Duration, "error", err)
+		} else {
+			slog.Info("Dry run: parser finished", "parser", pr.Name, "duration", pr.Duration, "matches", pr.Matches,
+				"events", pr.Events, "outcomes", pr.Outcomes, "esports_matches", pr.EsportsMatches, "outrights", pr.Outrights)
+		}
+		res.Parsers = append(res.Parsers, pr)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	res.Matches = health.MergeMatchLists([][]models.Match{c.matches})
+	if len(c.esports) > 0 {
+		res.EsportsMatches = health.MergeEsportsMatchLists([][]models.EsportsMatch{c.esports})
+	}
+	if len(c.outrights) > 0 {
+		res.Outrights = health.MergeOutrightLists([][]models.Outright{c.outrights})
+	}
+	c.mu.Unlock()
+
+	if err := write(cfg.Output, &res); err != nil {
+		return fmt.Errorf("write dry-run output: %w", err)
+	}
+	slog.Info("Dry run complete", "output", outputName(cfg.Output), "matches", len(res.Matches),
+		"esports_matches", len(res.EsportsMatches), "outrights", len(res.Outrights), "failed_parsers", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d parsers failed", failed, len(res.Parsers))
+	}
+	return nil
+}
+
+// LogToStderr replaces the default logger with a plain stderr logger, so logs don't mix with the
+// JSON when the output is stdout. Dry runs are local, so Yandex Cloud Logging is not needed.
+func LogToStderr() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})))
+}
+
+func write(output string, res *Result) error {
+	var w io.Writer = os.Stdout
+	if output != "" && output != "-" {
+		if dir := filepath.Dir(output); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+		}
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func outputName(output string) string {
+	if output == "" || output == "-" {
+		return "stdout"
+	}
+	return output
+}
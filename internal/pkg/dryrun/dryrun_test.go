@@ -0,0 +1,95 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+type fakeParser struct {
+	name  string
+	parse func() error
+}
+
+func (p *fakeParser) Start(ctx context.Context) error     { return nil }
+func (p *fakeParser) Stop() error                         { return nil }
+func (p *fakeParser) GetName() string                     { return p.name }
+func (p *fakeParser) ParseOnce(ctx context.Context) error { return p.parse() }
+
+func match(id, bookmaker string, outcomes int) *models.Match {
+	ev := models.Event{ID: id + "|main", EventType: "main_match", Bookmaker: bookmaker}
+	types := []string{"home_win", "draw", "away_win"}
+	for i := 0; i < outcomes; i++ {
+		ev.Outcomes = append(ev.Outcomes, models.Outcome{ID: string(rune('a' + i)), OutcomeType: types[i], Odds: 2.9})
+	}
+	return &models.Match{ID: id, Bookmaker: bookmaker, Events: []models.Event{ev}}
+}
+
+func TestRun_WritesMatchesInsteadOfStore(t *testing.T) {
+	health.ClearMatches()
+	out := filepath.Join(t.TempDir(), "out", "dry.json")
+	ps := []*fakeParser{
+		{name: "fonbet", parse: func() error {
+			health.AddMatch(match("m1", "fonbet", 2))
+			health.AddMatch(match("m2", "fonbet", 1))
+			return nil
+		}},
+		{name: "xbet1", parse: func() error {
+			health.AddMatch(match("m1", "xbet1", 3))
+			health.AddOutright(&models.Outright{ID: "o1"})
+			return errors.New("league 42: timeout")
+		}},
+	}
+
+	err := Run(context.Background(), toParsers(ps), config.DryRunConfig{Output: out})
+	if err == nil {
+		t.Fatal("expected error for failed parser")
+	}
+	if n := len(health.GetMatches()); n != 0 {
+		t.Fatalf("dry run stored %d matches in the health store", n)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res Result
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Parsers) != 2 {
+		t.Fatalf("parsers = %+v", res.Parsers)
+	}
+	if p := res.Parsers[0]; p.Name != "fonbet" || p.Matches != 2 || p.Outcomes != 3 || p.Error != "" {
+		t.Errorf("fonbet result = %+v", p)
+	}
+	if p := res.Parsers[1]; p.Matches != 1 || p.Outrights != 1 || p.Error != "league 42: timeout" {
+		t.Errorf("xbet1 result = %+v", p)
+	}
+	if len(res.Matches) != 2 || len(res.Outrights) != 1 {
+		t.Fatalf("matches = %d, outrights = %d", len(res.Matches), len(res.Outrights))
+	}
+
+	// Sink is removed after the run
+	health.AddMatch(match("m3", "fonbet", 1))
+	if n := len(health.GetMatches()); n != 1 {
+		t.Fatalf("store has %d matches after dry run, want 1", n)
+	}
+	health.ClearMatches()
+}
+
+func toParsers(ps []*fakeParser) []interfaces.Parser {
+	out := make([]interfaces.Parser, len(ps))
+	for i, p := range ps {
+		out[i] = p
+	}
+	return out
+}
@@ -1,5 +1,7 @@
 package enums
 
+import "strings"
+
 // Sport represents supported sports types
 type Sport string
 
@@ -18,6 +20,14 @@ const (
 	KOG        Sport = "kog"
 	CrossFire  Sport = "crossfire"
 	CallOfDuty Sport = "callofduty"
+
+	// Esports is the catch-all tag for esports whose discipline is unknown (xbet sports=40 when
+	// the league name doesn't tell). Not a parser sport: IsValid is false, IsEsports is true.
+	Esports Sport = "esports"
+
+	// CyberFootball tags football matches played in a video game (FIFA / EA FC, eFootball) that bookmakers
+	// list under football. Kept apart from real football; not a parser sport (IsValid is false).
+	CyberFootball Sport = "cyber_football"
 )
 
 // SportInfo contains additional information about a sport
@@ -94,6 +104,11 @@ func (s Sport) GetSportInfo() SportInfo {
 			Name:  "Call of Duty",
 			Alias: "callofduty",
 		}
+	case CyberFootball:
+		return SportInfo{
+			Name:  "Cyber football",
+			Alias: "cyber_football",
+		}
 	default:
 		return SportInfo{
 			Name:  "Unknown",
@@ -141,3 +156,107 @@ func ParseSport(s string) (Sport, bool) {
 	sport := Sport(s)
 	return sport, sport.IsValid()
 }
+
+// IsEsports reports whether s is an esports discipline (or the generic Esports tag).
+func (s Sport) IsEsports() bool {
+	switch s {
+	case Dota2, CS, Valorant, LOL, KOG, CrossFire, CallOfDuty, Esports:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetEsportsDisciplines returns the supported esports disciplines.
+func GetEsportsDisciplines() []Sport {
+	return []Sport{Dota2, CS, Valorant, LOL, KOG, CrossFire, CallOfDuty}
+}
+
+// esportsNamePatterns maps substrings of league/sport names to disciplines ("Dota 2. DreamLeague",
+// "Counter-Strike 2. ESL Pro League"). Checked before esportsNameTokens.
+var esportsNamePatterns = []struct {
+	substr string
+	sport  Sport
+}{
+	{"dota", Dota2},
+	{"дота", Dota2},
+	{"counter-strike", CS},
+	{"counter strike", CS},
+	{"контр-страйк", CS},
+	{"valorant", Valorant},
+	{"валорант", Valorant},
+	{"league of legends", LOL},
+	{"king of glory", KOG},
+	{"honor of kings", KOG},
+	{"crossfire", CrossFire},
+	{"call of duty", CallOfDuty},
+}
+
+// esportsNameTokens are short names matched as whole words only ("cs" must not match "physics").
+var esportsNameTokens = map[string]Sport{
+	"cs":   CS,
+	"cs2":  CS,
+	"csgo": CS,
+	"lol":  LOL,
+	"kog":  KOG,
+	"cod":  CallOfDuty,
+}
+
+// DetectEsportsDiscipline guesses the esports discipline from a league or sport name.
+func DetectEsportsDiscipline(name string) (Sport, bool) {
+	lower := strings.ToLower(name)
+	for _, p := range esportsNamePatterns {
+		if strings.Contains(lower, p.substr) {
+			return p.sport, true
+		}
+	}
+	// "CS:GO", "CS 2" -> tokens "cs", "go" / "cs", "2"
+	tokens := strings.FieldsFunc(lower, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for i, t := range tokens {
+		if t == "cs" && i+1 < len(tokens) && (tokens[i+1] == "go" || tokens[i+1] == "2") {
+			return CS, true
+		}
+		if sport, ok := esportsNameTokens[t]; ok {
+			return sport, true
+		}
+	}
+	return "", false
+}
+
+// cyberFootballPatterns are substrings of league names of cyber football ("FIFA. Cyber League",
+// "Esoccer Battle - 8 mins play", "Кибер футбол. Лига Про").
+var cyberFootballPatterns = []string{
+	"fifa",
+	"ea sports fc",
+	"ea fc",
+	"efootball",
+	"e-football",
+	"esoccer",
+	"e-soccer",
+	"esports battle",
+	"cyber",
+	"кибер",
+	"gt leagues",
+	"gt nations",
+	"volta",
+	"h2h gg",
+}
+
+// IsCyberFootballLeague reports whether a football league name is cyber football.
+// FIFA tournaments ("FIFA Club World Cup", "FIFA Arab Cup") are real football, so "fifa" only counts
+// without "cup" (a cyber cup still matches through "cyber" or "esports battle").
+func IsCyberFootballLeague(name string) bool {
+	lower := strings.ToLower(name)
+	for _, p := range cyberFootballPatterns {
+		if !strings.Contains(lower, p) {
+			continue
+		}
+		if p == "fifa" && strings.Contains(lower, "cup") {
+			continue
+		}
+		return true
+	}
+	return false
+}
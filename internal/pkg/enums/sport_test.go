@@ -0,0 +1,70 @@
+package enums
+
+import "testing"
+
+func TestDetectEsportsDiscipline(t *testing.T) {
+	tests := []struct {
+		name string
+		want Sport
+		ok   bool
+	}{
+		{"Dota 2. DreamLeague Season 24", Dota2, true},
+		{"Counter-Strike 2. ESL Pro League", CS, true},
+		{"CS2. BLAST Premier", CS, true},
+		{"CS:GO. Regional League", CS, true},
+		{"CS 2. PGL Major", CS, true},
+		{"League of Legends. LEC", LOL, true},
+		{"LoL. LCK", LOL, true},
+		{"Valorant. VCT EMEA", Valorant, true},
+		{"Call of Duty League", CallOfDuty, true},
+		{"Physics Cup", "", false},
+		{"FIFA. Cyber League", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := DetectEsportsDiscipline(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DetectEsportsDiscipline(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsEsports(t *testing.T) {
+	for _, s := range GetEsportsDisciplines() {
+		if !s.IsEsports() || !s.IsValid() {
+			t.Errorf("%s: IsEsports=%v IsValid=%v", s, s.IsEsports(), s.IsValid())
+		}
+	}
+	if !Esports.IsEsports() || Esports.IsValid() {
+		t.Error("generic Esports tag must be esports but not a parser sport")
+	}
+	if Football.IsEsports() {
+		t.Error("football is not esports")
+	}
+}
+
+func TestIsCyberFootballLeague(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"FIFA. Cyber League", true},
+		{"FIFA 24. 8 min", true},
+		{"Esoccer Battle - 8 mins play", true},
+		{"EA Sports FC 25. Volta", true},
+		{"eFootball. GT Leagues", true},
+		{"Кибер футбол. Лига Про", true},
+		{"FIFA. Esports Battle Cup", true},
+		{"FIFA Club World Cup", false},
+		{"FIFA World Cup. Qualification", false},
+		{"England. Premier League", false},
+		{"Spain. La Liga", false},
+	}
+	for _, tt := range tests {
+		if got := IsCyberFootballLeague(tt.name); got != tt.want {
+			t.Errorf("IsCyberFootballLeague(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if CyberFootball.IsValid() || CyberFootball.IsEsports() {
+		t.Error("cyber_football must be neither a parser sport nor esports")
+	}
+}
@@ -0,0 +1,337 @@
+// Package eventlog is the append-only log of what happened when: parser cycles, detected values,
+// sent and retracted alerts, match starts. It makes post-mortem questions like "why didn't I get
+// alerted" answerable without digging through service logs.
+//
+// Each service keeps the last event_log.capacity events in memory and appends every event to
+// <event_log.dir>/<service>.jsonl, reloading the tail on restart. GET /events queries them and
+// merges the /events of upstream services (SetRemotes): the calculator merges the parser, the
+// parser orchestrator merges its bookmaker services, so one request shows the whole pipeline.
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+const (
+	DefaultCapacity      = 10000
+	DefaultMaxFileSizeMB = 50
+	DefaultRemoteTimeout = 5 * time.Second
+)
+
+// Event types.
+const (
+	TypeParserCycle    = "parser_cycle_completed"
+	TypeParserRestart  = "parser_restarted"
+	TypeValueDetected  = "value_detected"
+	TypeAlertSent      = "alert_sent"
+	TypeAlertFailed    = "alert_failed"
+	TypeAlertRetracted = "alert_retracted"
+	TypeMatchStarted   = "match_started"
+)
+
+// Event is one log record. Only the fields relevant to the type are set.
+type Event struct {
+	Seq       int64                  `json:"seq"` // per service, increasing
+	At        time.Time              `json:"at"`
+	Type      string                 `json:"type"`
+	Service   string                 `json:"service,omitempty"`
+	Parser    string                 `json:"parser,omitempty"`
+	MatchKey  string                 `json:"match_key,omitempty"`
+	Match     string                 `json:"match,omitempty"`
+	BetKey    string                 `json:"bet_key,omitempty"`
+	Bookmaker string                 `json:"bookmaker,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Filter selects events for Query.
+type Filter struct {
+	Since time.Time       // events at or after Since (zero = all)
+	Types map[string]bool // nil = all types
+	Match string          // case-insensitive substring of Match or MatchKey
+	Limit int             // max events (<= 0 = no limit)
+}
+
+type eventLog struct {
+	mu       sync.Mutex
+	service  string
+	capacity int
+	events   []Event // ring buffer in chronological order once full: events[next:] + events[:next]
+	next     int
+	full     bool
+	seq      int64
+
+	path     string
+	file     *os.File
+	size     int64
+	maxBytes int64
+
+	remotes       map[string]string
+	remoteTimeout time.Duration
+}
+
+var (
+	defaultMu sync.Mutex
+	current   = newLog("", config.EventLogConfig{})
+)
+
+func newLog(service string, cfg config.EventLogConfig) *eventLog {
+	capacity := cfg.Capacity
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	maxMB := cfg.MaxFileSizeMB
+	if maxMB <= 0 {
+		maxMB = DefaultMaxFileSizeMB
+	}
+	timeout := cfg.RemoteTimeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+	return &eventLog{
+		service:       service,
+		capacity:      capacity,
+		events:        make([]Event, 0, min(capacity, 1024)),
+		maxBytes:      int64(maxMB) << 20,
+		remoteTimeout: timeout,
+	}
+}
+
+// Configure sets up the log of a service (event_log). With a dir the last events are reloaded
+// from <dir>/<service>.jsonl; a file that can't be opened leaves the log in memory only.
+func Configure(service string, cfg config.EventLogConfig) {
+	l := newLog(service, cfg)
+	if cfg.Dir != "" {
+		l.path = filepath.Join(cfg.Dir, service+".jsonl")
+		if err := l.open(); err != nil {
+			slog.Error("Event log: file unavailable, keeping events in memory only", "path", l.path, "error", err)
+			l.path = ""
+		}
+	}
+
+	defaultMu.Lock()
+	old := current
+	old.mu.Lock()
+	l.remotes = old.remotes // SetRemotes may run before Configure
+	old.mu.Unlock()
+	current = l
+	defaultMu.Unlock()
+	old.close()
+	slog.Info("Event log configured", "service", service, "path", l.path, "capacity", l.capacity, "loaded", l.len())
+}
+
+func get() *eventLog {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return current
+}
+
+// SetRemotes sets the upstream services (name -> base URL) whose /events are merged by Handle.
+func SetRemotes(remotes map[string]string) {
+	l := get()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.remotes = make(map[string]string, len(remotes))
+	for name, u := range remotes {
+		if u = strings.TrimRight(strings.TrimSpace(u), "/"); u != "" {
+			l.remotes[name] = u
+		}
+	}
+}
+
+// Record appends an event; At and Service default to now and the configured service.
+func Record(e Event) {
+	get().record(e)
+}
+
+// Query returns the local events matching f in chronological order. If more than f.Limit match,
+// the earliest ones are returned when f.Since is set (paging forward), otherwise the latest ones.
+// truncated reports whether events were left out.
+func Query(f Filter) (events []Event, truncated bool) {
+	return get().query(f)
+}
+
+func (l *eventLog) record(e Event) {
+	if e.At.IsZero() {
+		e.At = time.Now().UTC()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.Service == "" {
+		e.Service = l.service
+	}
+	l.seq++
+	e.Seq = l.seq
+	l.append(e)
+	if l.file != nil {
+		l.write(e)
+	}
+}
+
+func (l *eventLog) append(e Event) {
+	if len(l.events) < l.capacity {
+		l.events = append(l.events, e)
+		return
+	}
+	l.events[l.next] = e
+	l.next = (l.next + 1) % l.capacity
+	l.full = true
+}
+
+// ordered returns the events in chronological (recording) order. Caller holds l.mu.
+func (l *eventLog) ordered() []Event {
+	if !l.full {
+		return l.events
+	}
+	out := make([]Event, 0, len(l.events))
+	out = append(out, l.events[l.next:]...)
+	return append(out, l.events[:l.next]...)
+}
+
+func (l *eventLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.events)
+}
+
+func (l *eventLog) query(f Filter) ([]Event, bool) {
+	l.mu.Lock()
+	all := l.ordered()
+	out := make([]Event, 0, 64)
+	for _, e := range all {
+		if f.matches(e) {
+			out = append(out, e)
+		}
+	}
+	l.mu.Unlock()
+	return f.limit(out)
+}
+
+func (f Filter) matches(e Event) bool {
+	if !f.Since.IsZero() && e.At.Before(f.Since) {
+		return false
+	}
+	if f.Types != nil && !f.Types[e.Type] {
+		return false
+	}
+	if f.Match != "" {
+		q := strings.ToLower(f.Match)
+		if !strings.Contains(strings.ToLower(e.Match), q) && !strings.Contains(strings.ToLower(e.MatchKey), q) {
+			return false
+		}
+	}
+	return true
+}
+
+// limit cuts chronologically sorted events to f.Limit (see Query).
+func (f Filter) limit(events []Event) ([]Event, bool) {
+	if f.Limit <= 0 || len(events) <= f.Limit {
+		return events, false
+	}
+	if !f.Since.IsZero() {
+		return events[:f.Limit], true
+	}
+	return events[len(events)-f.Limit:], true
+}
+
+// sortEvents orders merged events of several services by time.
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].At.Equal(events[j].At) {
+			return events[i].At.Before(events[j].At)
+		}
+		if events[i].Service != events[j].Service {
+			return events[i].Service < events[j].Service
+		}
+		return events[i].Seq < events[j].Seq
+	})
+}
+
+// open reloads the tail of the file and opens it for appending.
+func (l *eventLog) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	if f, err := os.Open(l.path); err == nil {
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for sc.Scan() {
+			var e Event
+			if json.Unmarshal(sc.Bytes(), &e) != nil {
+				continue // torn last line after a crash
+			}
+			l.append(e)
+			if e.Seq > l.seq {
+				l.seq = e.Seq
+			}
+		}
+		f.Close()
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, st.Size()
+	return nil
+}
+
+// write appends e to the file, rotating it above max_file_size_mb. Caller holds l.mu.
+func (l *eventLog) write(e Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	if l.size+int64(len(data)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			slog.Error("Event log: rotation failed", "path", l.path, "error", err)
+		}
+	}
+	if l.file == nil {
+		return
+	}
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	if err != nil {
+		slog.Error("Event log: write failed", "path", l.path, "error", err)
+	}
+}
+
+func (l *eventLog) rotate() error {
+	l.file.Close()
+	l.file = nil
+	if err := os.Rename(l.path, l.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("reopen: %w", err)
+	}
+	l.file, l.size = f, 0
+	return nil
+}
+
+func (l *eventLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
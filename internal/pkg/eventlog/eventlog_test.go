@@ -0,0 +1,172 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func TestRingKeepsLatestEvents(t *testing.T) {
+	Configure("test", config.EventLogConfig{Capacity: 3})
+	for i := 0; i < 5; i++ {
+		Record(Event{Type: TypeParserCycle, Parser: string(rune('a' + i))})
+	}
+	events, truncated := Query(Filter{})
+	if truncated {
+		t.Fatal("unexpected truncation")
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	for i, want := range []string{"c", "d", "e"} {
+		if events[i].Parser != want || events[i].Seq != int64(i+3) || events[i].Service != "test" {
+			t.Errorf("event %d = %+v, want parser %s seq %d", i, events[i], want, i+3)
+		}
+	}
+}
+
+func TestQueryFilterAndLimit(t *testing.T) {
+	Configure("test", config.EventLogConfig{})
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		typ := TypeValueDetected
+		if i%2 == 1 {
+			typ = TypeAlertSent
+		}
+		Record(Event{At: base.Add(time.Duration(i) * time.Minute), Type: typ, Match: "Arsenal vs Chelsea", MatchKey: "k"})
+	}
+	Record(Event{At: base, Type: TypeValueDetected, Match: "Real vs Barcelona"})
+
+	events, _ := Query(Filter{Types: map[string]bool{TypeAlertSent: true}})
+	if len(events) != 3 {
+		t.Fatalf("type filter: got %d events, want 3", len(events))
+	}
+	events, _ = Query(Filter{Match: "REAL"})
+	if len(events) != 1 {
+		t.Fatalf("match filter: got %d events, want 1", len(events))
+	}
+
+	// With since the earliest events are returned (paging forward), otherwise the latest.
+	events, truncated := Query(Filter{Since: base.Add(2 * time.Minute), Limit: 2})
+	if !truncated || len(events) != 2 || !events[0].At.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("since+limit: got %+v truncated=%v", events, truncated)
+	}
+	events, truncated = Query(Filter{Limit: 2})
+	if !truncated || len(events) != 2 || events[1].Match != "Real vs Barcelona" {
+		t.Fatalf("limit: got %+v truncated=%v", events, truncated)
+	}
+}
+
+func TestFileReloadAndRotation(t *testing.T) {
+	dir := t.TempDir()
+	Configure("svc", config.EventLogConfig{Dir: dir})
+	Record(Event{Type: TypeMatchStarted, Match: "A vs B"})
+	Record(Event{Type: TypeMatchStarted, Match: "C vs D"})
+
+	// Restart: the tail is reloaded and seq continues.
+	Configure("svc", config.EventLogConfig{Dir: dir})
+	Record(Event{Type: TypeMatchStarted, Match: "E vs F"})
+	events, _ := Query(Filter{})
+	if len(events) != 3 || events[0].Match != "A vs B" || events[2].Seq != 3 {
+		t.Fatalf("after reload got %+v", events)
+	}
+
+	l := get()
+	l.mu.Lock()
+	l.maxBytes = 1 // rotate on every write
+	l.mu.Unlock()
+	Record(Event{Type: TypeMatchStarted, Match: "G vs H"})
+	if _, err := os.Stat(filepath.Join(dir, "svc.jsonl.1")); err != nil {
+		t.Fatalf("rotated file missing: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "svc.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil || e.Match != "G vs H" {
+		t.Fatalf("current file = %q (%v)", data, err)
+	}
+	Configure("", config.EventLogConfig{})
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-01-01T10:00:00Z", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"1767261600", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2h", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Error("expected error for invalid since")
+	}
+}
+
+func TestHandleMergesRemotes(t *testing.T) {
+	base := time.Now().UTC().Add(-time.Minute)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(hopsHeader) != "1" {
+			t.Errorf("hops header = %q", r.Header.Get(hopsHeader))
+		}
+		if r.URL.Query().Get("type") != TypeParserCycle+","+TypeValueDetected {
+			t.Errorf("query not forwarded: %s", r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode(Response{
+			Events:  []Event{{Seq: 1, At: base.Add(10 * time.Second), Type: TypeParserCycle, Parser: "fonbet"}},
+			Sources: map[string]string{"bookmaker-service-fonbet": "ok"},
+		})
+	}))
+	defer upstream.Close()
+
+	Configure("calculator", config.EventLogConfig{})
+	SetRemotes(map[string]string{"parser": upstream.URL + "/", "down": "http://127.0.0.1:1"})
+	defer SetRemotes(nil)
+	Record(Event{At: base, Type: TypeValueDetected, Match: "A vs B"})
+	Record(Event{At: base.Add(20 * time.Second), Type: TypeValueDetected, Match: "C vs D"})
+	Record(Event{At: base.Add(30 * time.Second), Type: TypeAlertSent, Match: "C vs D"})
+
+	q := url.Values{"type": {TypeParserCycle + "," + TypeValueDetected}}
+	rec := httptest.NewRecorder()
+	Handle(rec, httptest.NewRequest(http.MethodGet, "/events?"+q.Encode(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 3 {
+		t.Fatalf("got %d events, want 3: %+v", resp.Count, resp.Events)
+	}
+	if resp.Events[1].Parser != "fonbet" || resp.Events[1].Service != "parser" {
+		t.Errorf("remote event not merged in time order: %+v", resp.Events)
+	}
+	if resp.Sources["calculator"] != "ok" || resp.Sources["bookmaker-service-fonbet"] != "ok" {
+		t.Errorf("sources = %v", resp.Sources)
+	}
+	if resp.Sources["down"] == "ok" || resp.Sources["down"] == "" {
+		t.Errorf("unreachable remote not reported: %v", resp.Sources)
+	}
+
+	rec = httptest.NewRecorder()
+	Handle(rec, httptest.NewRequest(http.MethodGet, "/events?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit: status %d", rec.Code)
+	}
+}
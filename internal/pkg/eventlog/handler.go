@@ -0,0 +1,193 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueryLimit = 500
+	// hopsHeader counts nested /events merges so misconfigured remotes can't loop.
+	hopsHeader = "X-Event-Log-Hops"
+	maxHops    = 3
+)
+
+// Response is the JSON body of GET /events.
+type Response struct {
+	Events    []Event           `json:"events"`
+	Count     int               `json:"count"`
+	Truncated bool              `json:"truncated,omitempty"`
+	Sources   map[string]string `json:"sources"` // service -> "ok" or the error of fetching its events
+}
+
+// Handle serves GET /events?since=&type=&match=&limit=&local=:
+//   - since: RFC3339 time, unix seconds or a duration back from now ("2h"); default: all kept events
+//   - type: comma-separated event types (parser_cycle_completed, value_detected, alert_sent, ...)
+//   - match: substring of the match name or key
+//   - limit: max events (default 500); with since the earliest are returned, otherwise the latest
+//   - local=1: don't merge upstream services
+func Handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	q := r.URL.Query()
+	f, err := parseFilter(q, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	l := get()
+	events, truncated := l.query(f)
+	resp := Response{Sources: map[string]string{l.service: "ok"}}
+
+	hops, _ := strconv.Atoi(r.Header.Get(hopsHeader))
+	if q.Get("local") != "1" && hops < maxHops {
+		remote, remoteTruncated, sources := l.fetchRemotes(r.Context(), q, hops+1)
+		events = append(events, remote...)
+		truncated = truncated || remoteTruncated
+		for name, st := range sources {
+			resp.Sources[name] = st
+		}
+		sortEvents(events)
+		var cut bool
+		events, cut = f.limit(events)
+		truncated = truncated || cut
+	}
+	if events == nil {
+		events = []Event{}
+	}
+	resp.Events, resp.Count, resp.Truncated = events, len(events), truncated
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode events", "error", err)
+	}
+}
+
+func parseFilter(q url.Values, now time.Time) (Filter, error) {
+	f := Filter{Match: strings.TrimSpace(q.Get("match")), Limit: defaultQueryLimit}
+	if s := strings.TrimSpace(q.Get("since")); s != "" {
+		since, err := parseSince(s, now)
+		if err != nil {
+			return f, err
+		}
+		f.Since = since
+	}
+	if s := strings.TrimSpace(q.Get("type")); s != "" {
+		f.Types = map[string]bool{}
+		for _, t := range strings.Split(s, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.Types[t] = true
+			}
+		}
+	}
+	if s := strings.TrimSpace(q.Get("limit")); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return f, fmt.Errorf("invalid limit %q", s)
+		}
+		f.Limit = n
+	}
+	return f, nil
+}
+
+// parseSince accepts RFC3339, unix seconds or a duration back from now.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q: want RFC3339, unix seconds or a duration like 2h", s)
+}
+
+// fetchRemotes queries /events of the upstream services in parallel with the same parameters.
+func (l *eventLog) fetchRemotes(ctx context.Context, q url.Values, hops int) ([]Event, bool, map[string]string) {
+	l.mu.Lock()
+	remotes := make(map[string]string, len(l.remotes))
+	for name, u := range l.remotes {
+		remotes[name] = u
+	}
+	timeout := l.remoteTimeout
+	l.mu.Unlock()
+	if len(remotes) == 0 {
+		return nil, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	client := &http.Client{Timeout: timeout}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		events    []Event
+		truncated bool
+		sources   = make(map[string]string, len(remotes))
+	)
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name, baseURL string) {
+			defer wg.Done()
+			resp, err := fetchRemote(ctx, client, baseURL, q, hops)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				sources[name] = "error: " + err.Error()
+				return
+			}
+			for i := range resp.Events {
+				if resp.Events[i].Service == "" {
+					resp.Events[i].Service = name
+				}
+			}
+			events = append(events, resp.Events...)
+			truncated = truncated || resp.Truncated
+			for src, st := range resp.Sources {
+				sources[src] = st
+			}
+		}(name, remotes[name])
+	}
+	wg.Wait()
+	return events, truncated, sources
+}
+
+func fetchRemote(ctx context.Context, client *http.Client, baseURL string, q url.Values, hops int) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/events?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(hopsHeader, strconv.Itoa(hops))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var out Response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	return &out, nil
+}
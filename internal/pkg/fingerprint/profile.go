@@ -0,0 +1,217 @@
+// Package fingerprint provides the browser identity (User-Agent, Accept-Language, Sec-CH-UA)
+// that parser HTTP clients present to bookmakers.
+//
+// Each parser gets one Profile (see ForParser), configured via parser.fingerprint (defaults for
+// all parsers) and parser.<name>.fingerprint (overrides). User-Agents rotate round-robin between
+// requests; Sec-CH-UA client hints are derived from the chosen User-Agent so they never contradict it.
+package fingerprint
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// DefaultUserAgents is the pool used when neither config nor the parser defaults list User-Agents.
+var DefaultUserAgents = []string{
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.3 Safari/605.1.15",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:135.0) Gecko/20100101 Firefox/135.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36 Edg/142.0.0.0",
+}
+
+// Profile is the header profile of one parser. Safe for concurrent use; a nil *Profile changes nothing.
+type Profile struct {
+	name            string
+	userAgents      []string
+	acceptLanguages []string
+	randomLanguage  bool
+	clientHints     bool
+	headers         map[string]string
+
+	next atomic.Uint64
+	mu   sync.Mutex
+	rnd  *rand.Rand
+}
+
+// New builds a profile from cfg. DefaultUserAgents is used when cfg lists no User-Agents.
+func New(name string, cfg config.FingerprintConfig) *Profile {
+	p := &Profile{
+		name:            name,
+		userAgents:      nonEmpty(cfg.UserAgents),
+		acceptLanguages: nonEmpty(cfg.AcceptLanguages),
+		randomLanguage:  cfg.RandomizeAcceptLanguage != nil && *cfg.RandomizeAcceptLanguage,
+		clientHints:     cfg.ClientHints != nil && *cfg.ClientHints,
+		headers:         cfg.Headers,
+		rnd:             rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if len(p.userAgents) == 0 {
+		p.userAgents = DefaultUserAgents
+	}
+	return p
+}
+
+// UserAgent returns the next User-Agent of the rotation (e.g. for headless Chrome).
+func (p *Profile) UserAgent() string {
+	if p == nil {
+		return DefaultUserAgents[0]
+	}
+	i := p.next.Add(1) - 1
+	return p.userAgents[i%uint64(len(p.userAgents))]
+}
+
+// Apply sets the profile headers on req, overriding what the parser set:
+//   - User-Agent: next one from the pool;
+//   - Accept-Language: first of accept_languages (random one with randomize_accept_language);
+//     the parser's own value is kept when the list is empty;
+//   - Sec-CH-UA, Sec-CH-UA-Mobile, Sec-CH-UA-Platform: derived from the User-Agent when client_hints
+//     is on or the parser already sends them; removed for non-Chromium User-Agents;
+//   - extra headers from the profile.
+func (p *Profile) Apply(req *http.Request) {
+	if p == nil || req == nil {
+		return
+	}
+	ua := p.UserAgent()
+	req.Header.Set("User-Agent", ua)
+
+	if len(p.acceptLanguages) > 0 {
+		lang := p.acceptLanguages[0]
+		if p.randomLanguage && len(p.acceptLanguages) > 1 {
+			p.mu.Lock()
+			lang = p.acceptLanguages[p.rnd.Intn(len(p.acceptLanguages))]
+			p.mu.Unlock()
+		}
+		req.Header.Set("Accept-Language", lang)
+	}
+
+	if p.clientHints || req.Header.Get("Sec-CH-UA") != "" {
+		req.Header.Del("Sec-CH-UA")
+		req.Header.Del("Sec-CH-UA-Mobile")
+		req.Header.Del("Sec-CH-UA-Platform")
+		if hints := ClientHints(ua); hints != nil {
+			for k, v := range hints {
+				req.Header.Set(k, v)
+			}
+		}
+	}
+
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+}
+
+var (
+	chromeVersion = regexp.MustCompile(`Chrome/(\d+)`)
+	yaVersion     = regexp.MustCompile(`YaBrowser/(\d+\.\d+)`)
+	edgeVersion   = regexp.MustCompile(`Edg/(\d+)`)
+)
+
+// ClientHints returns the Sec-CH-UA headers a browser with this User-Agent sends,
+// or nil for browsers without client hints (Firefox, Safari, bots).
+func ClientHints(ua string) map[string]string {
+	m := chromeVersion.FindStringSubmatch(ua)
+	if m == nil {
+		return nil
+	}
+	major := m[1]
+	brands := `"Chromium";v="` + major + `", "Google Chrome";v="` + major + `", "Not_A Brand";v="99"`
+	if y := yaVersion.FindStringSubmatch(ua); y != nil {
+		brands = `"Chromium";v="` + major + `", "YaBrowser";v="` + y[1] + `", "Not_A Brand";v="99", "Yowser";v="2.5"`
+	} else if e := edgeVersion.FindStringSubmatch(ua); e != nil {
+		brands = `"Chromium";v="` + major + `", "Microsoft Edge";v="` + e[1] + `", "Not_A Brand";v="99"`
+	}
+
+	mobile, platform := "?0", `"Unknown"`
+	switch {
+	case strings.Contains(ua, "Android"):
+		mobile, platform = "?1", `"Android"`
+	case strings.Contains(ua, "Macintosh"):
+		platform = `"macOS"`
+	case strings.Contains(ua, "Windows"):
+		platform = `"Windows"`
+	case strings.Contains(ua, "Linux"):
+		platform = `"Linux"`
+	}
+	return map[string]string{
+		"Sec-CH-UA":          brands,
+		"Sec-CH-UA-Mobile":   mobile,
+		"Sec-CH-UA-Platform": platform,
+	}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Profile{}
+)
+
+// ForParser returns the shared profile for a parser, creating it on first use.
+// Settings are merged field by field: def (the parser's built-in headers) < global (parser.fingerprint)
+// < own (parser.<name>.fingerprint). All HTTP clients of the same parser share one profile.
+func ForParser(name string, global, own, def config.FingerprintConfig) *Profile {
+	n := strings.ToLower(strings.TrimSpace(name))
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if p, ok := registry[n]; ok {
+		return p
+	}
+
+	p := New(n, merge(merge(def, global), own))
+	slog.Info("Fingerprint profile configured", "parser", n, "user_agents", len(p.userAgents),
+		"accept_languages", len(p.acceptLanguages), "client_hints", p.clientHints)
+	registry[n] = p
+	return p
+}
+
+// merge returns base with the fields set in own replacing it (headers are combined).
+func merge(base, own config.FingerprintConfig) config.FingerprintConfig {
+	out := base
+	if len(own.UserAgents) > 0 {
+		out.UserAgents = own.UserAgents
+	}
+	if len(own.AcceptLanguages) > 0 {
+		out.AcceptLanguages = own.AcceptLanguages
+	}
+	if own.RandomizeAcceptLanguage != nil {
+		out.RandomizeAcceptLanguage = own.RandomizeAcceptLanguage
+	}
+	if own.ClientHints != nil {
+		out.ClientHints = own.ClientHints
+	}
+	if len(own.Headers) > 0 {
+		out.Headers = make(map[string]string, len(base.Headers)+len(own.Headers))
+		for k, v := range base.Headers {
+			out.Headers[k] = v
+		}
+		for k, v := range own.Headers {
+			out.Headers[k] = v
+		}
+	}
+	return out
+}
+
+// Bool returns a pointer to v, for the optional fields of parser default FingerprintConfig values.
+func Bool(v bool) *bool {
+	return &v
+}
+
+func nonEmpty(list []string) []string {
+	var out []string
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
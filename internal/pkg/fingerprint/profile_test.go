@@ -0,0 +1,56 @@
+package fingerprint
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func TestProfile_RotatesUserAgentWithMatchingClientHints(t *testing.T) {
+	p := New("test", config.FingerprintConfig{
+		UserAgents: []string{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 YaBrowser/25.12.0.0 Safari/537.36",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0",
+		},
+		ClientHints: Bool(true),
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	p.Apply(req)
+	if got := req.Header.Get("Sec-CH-UA"); got != `"Chromium";v="142", "YaBrowser";v="25.12", "Not_A Brand";v="99", "Yowser";v="2.5"` {
+		t.Fatalf("unexpected Sec-CH-UA %q", got)
+	}
+	if got := req.Header.Get("Sec-CH-UA-Platform"); got != `"macOS"` {
+		t.Fatalf("unexpected platform %q", got)
+	}
+
+	// Firefox sends no client hints: stale ones must be removed
+	p.Apply(req)
+	if ua := req.Header.Get("User-Agent"); ua != p.userAgents[1] {
+		t.Fatalf("expected second User-Agent, got %q", ua)
+	}
+	if got := req.Header.Get("Sec-CH-UA"); got != "" {
+		t.Fatalf("client hints must be removed for Firefox, got %q", got)
+	}
+}
+
+func TestForParser_MergesDefaultsGlobalAndOwn(t *testing.T) {
+	def := config.FingerprintConfig{UserAgents: []string{"Bot/1.0"}, ClientHints: Bool(true)}
+	global := config.FingerprintConfig{AcceptLanguages: []string{"ru"}, Headers: map[string]string{"DNT": "1"}}
+	own := config.FingerprintConfig{ClientHints: Bool(false), Headers: map[string]string{"X-Test": "1"}}
+	p := ForParser("merge-test", global, own, def)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	req.Header.Set("Accept-Language", "en")
+	p.Apply(req)
+	if req.Header.Get("User-Agent") != "Bot/1.0" || req.Header.Get("Accept-Language") != "ru" {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+	if req.Header.Get("DNT") != "1" || req.Header.Get("X-Test") != "1" || p.clientHints {
+		t.Fatalf("expected combined extra headers and client hints disabled, got %v", req.Header)
+	}
+	if ForParser("MERGE-TEST", config.FingerprintConfig{}, config.FingerprintConfig{}, config.FingerprintConfig{}) != p {
+		t.Fatal("profile must be shared per parser")
+	}
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+// newChecker returns the dependency checks of the health server: a successful parse cycle gates
+// readiness, open circuit breakers of bookmaker endpoints only degrade the service.
+func newChecker(service string) *healthcheck.Checker {
+	c := healthcheck.New(service)
+	c.Require("parse_cycle", checkParseCycle)
+	c.Observe("bookmaker_endpoints", checkCircuitBreakers)
+	return c
+}
+
+// checkParseCycle passes once a parser of the service finished a cycle that parsed something, or when the
+// service has no parsers (cmd/import serves loaded matches).
+func checkParseCycle(context.Context) error {
+	parsers := GetParsers()
+	if len(parsers) == 0 {
+		return nil
+	}
+	for _, st := range ParseStats() {
+		if st.LastSuccess != nil {
+			return nil
+		}
+	}
+	for _, p := range parsers {
+		cp, ok := p.(interfaces.ControllableParser)
+		if !ok {
+			continue
+		}
+		if c := cp.LastCycle(); !c.Finished.IsZero() && (c.Errors == 0 || c.Matches > 0) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no successful parse cycle yet (%d parsers)", len(parsers))
+}
+
+// checkCircuitBreakers fails while the breaker of a bookmaker endpoint is open.
+func checkCircuitBreakers(context.Context) error {
+	var open []string
+	for _, s := range circuitbreaker.Statuses() {
+		if s.State == circuitbreaker.StateOpen {
+			open = append(open, s.Endpoint)
+		}
+	}
+	if len(open) > 0 {
+		return errors.New("circuit breaker open: " + strings.Join(open, ", "))
+	}
+	return nil
+}
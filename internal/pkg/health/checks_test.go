@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+type cycleParser struct {
+	interfaces.ControllableParser
+	last interfaces.CycleSummary
+}
+
+func (p *cycleParser) Start(context.Context) error        { return nil }
+func (p *cycleParser) Stop() error                        { return nil }
+func (p *cycleParser) GetName() string                    { return "checks-test" }
+func (p *cycleParser) ParseOnce(context.Context) error    { return nil }
+func (p *cycleParser) LastCycle() interfaces.CycleSummary { return p.last }
+
+func TestCheckParseCycle(t *testing.T) {
+	parseStatsMu.Lock()
+	saved := parseStats
+	parseStats = make(map[string]*handlers.ParserStats)
+	parseStatsMu.Unlock()
+	defer func() {
+		parseStatsMu.Lock()
+		parseStats = saved
+		parseStatsMu.Unlock()
+		RegisterParsers(nil)
+	}()
+
+	RegisterParsers(nil)
+	if err := checkParseCycle(context.Background()); err != nil {
+		t.Errorf("no parsers: %v", err)
+	}
+
+	p := &cycleParser{}
+	RegisterParsers([]interfaces.Parser{p})
+	if err := checkParseCycle(context.Background()); err == nil {
+		t.Error("ready before any cycle")
+	}
+	p.last = interfaces.CycleSummary{Finished: time.Now(), Errors: 3}
+	if err := checkParseCycle(context.Background()); err == nil {
+		t.Error("ready after a cycle with errors only")
+	}
+	p.last.Matches = 10
+	if err := checkParseCycle(context.Background()); err != nil {
+		t.Errorf("after a cycle: %v", err)
+	}
+
+	p.last = interfaces.CycleSummary{}
+	RecordParseReport(interfaces.ParseReport{Parser: "checks-test", Status: interfaces.ParseStatusPartial, Finished: time.Now()})
+	if err := checkParseCycle(context.Background()); err != nil {
+		t.Errorf("after a parse report: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+)
+
+var (
+	debugEndpoints atomic.Bool
+	startedAt      = time.Now()
+)
+
+// EnableDebugEndpoints serves /debug/pprof/ and /debug/runtime on the health server started after it
+// (-debug-endpoints). Off by default: profiles expose internals and cost CPU while they are taken.
+func EnableDebugEndpoints() {
+	debugEndpoints.Store(true)
+}
+
+// registerDebug adds the debug endpoints to mux when they are enabled.
+func registerDebug(mux *http.ServeMux) {
+	if !debugEndpoints.Load() {
+		return
+	}
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", handleRuntime)
+	slog.Warn("Debug endpoints enabled: /debug/pprof/, /debug/runtime")
+}
+
+// RuntimeStats is the response of /debug/runtime.
+type RuntimeStats struct {
+	Uptime     string `json:"uptime"`
+	Goroutines int    `json:"goroutines"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+
+	HeapAllocMB   float64 `json:"heap_alloc_mb"` // live heap objects
+	HeapInuseMB   float64 `json:"heap_inuse_mb"` // heap spans in use
+	HeapObjects   uint64  `json:"heap_objects"`
+	HeapReleaseMB float64 `json:"heap_released_mb"` // returned to the OS
+	SysMB         float64 `json:"sys_mb"`           // obtained from the OS in total
+	NextGCMB      float64 `json:"next_gc_mb"`       // heap size of the next GC
+
+	NumGC         uint32    `json:"num_gc"`
+	LastGC        time.Time `json:"last_gc,omitempty"`
+	LastPause     string    `json:"last_pause"`
+	PauseTotal    string    `json:"pause_total"`
+	GCCPUFraction float64   `json:"gc_cpu_fraction"`
+
+	// Requests to the bookmakers awaiting a response, by parser
+	InFlightRequests map[string]int `json:"in_flight_requests"`
+}
+
+func readRuntimeStats() RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	mb := func(b uint64) float64 { return float64(b*100/(1<<20)) / 100 }
+	s := RuntimeStats{
+		Uptime:           time.Since(startedAt).Round(time.Second).String(),
+		Goroutines:       runtime.NumGoroutine(),
+		GOMAXPROCS:       runtime.GOMAXPROCS(0),
+		HeapAllocMB:      mb(m.HeapAlloc),
+		HeapInuseMB:      mb(m.HeapInuse),
+		HeapObjects:      m.HeapObjects,
+		HeapReleaseMB:    mb(m.HeapReleased),
+		SysMB:            mb(m.Sys),
+		NextGCMB:         mb(m.NextGC),
+		NumGC:            m.NumGC,
+		PauseTotal:       time.Duration(m.PauseTotalNs).String(),
+		GCCPUFraction:    m.GCCPUFraction,
+		InFlightRequests: cyclestats.InFlightRequests(),
+	}
+	if m.NumGC > 0 {
+		s.LastGC = time.Unix(0, int64(m.LastGC)).UTC()
+		s.LastPause = time.Duration(m.PauseNs[(m.NumGC+255)%256]).String()
+	}
+	return s
+}
+
+// handleRuntime returns goroutine, memory and GC statistics and the requests in flight.
+// GET /debug/runtime
+func handleRuntime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(readRuntimeStats()); err != nil {
+		slog.Error("Failed to encode runtime stats", "error", err)
+	}
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+)
+
+func TestDebugEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	registerDebug(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/runtime", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("disabled /debug/runtime: status = %d, want 404", rec.Code)
+	}
+
+	EnableDebugEndpoints()
+	defer debugEndpoints.Store(false)
+	mux = http.NewServeMux()
+	registerDebug(mux)
+
+	done := cyclestats.InFlight("DebugTest")
+	defer done()
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/runtime", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/debug/runtime: status = %d", rec.Code)
+	}
+	var stats RuntimeStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Goroutines <= 0 || stats.HeapAllocMB <= 0 || stats.InFlightRequests["debugtest"] != 1 {
+		t.Errorf("unexpected runtime stats: %+v", stats)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/debug/pprof/: status = %d", rec.Code)
+	}
+}
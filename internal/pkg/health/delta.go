@@ -0,0 +1,141 @@
+package health
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// maxDeltaRemoved bounds the log of removed match IDs; on overflow the log is dropped
+// and clients with older cursors get a full response.
+const maxDeltaRemoved = 20000
+
+// matchesDeltaTracker detects matches whose odds changed between /matches?since=<cursor> requests.
+// Every match has a fingerprint of its bet keys and odds; a changed fingerprint bumps the match to the
+// next version. Cursors are "<epoch>:<version>", the epoch changes on restart so old cursors get a full set.
+type matchesDeltaTracker struct {
+	mu      sync.Mutex
+	epoch   string
+	version uint64
+	hashes  map[string]uint64 // match ID -> odds fingerprint
+	changed map[string]uint64 // match ID -> version of its last change
+	removed map[string]uint64 // match ID -> version it disappeared at
+	floor   uint64            // removals up to floor were dropped from the log
+}
+
+func newMatchesDeltaTracker() *matchesDeltaTracker {
+	return &matchesDeltaTracker{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		hashes:  make(map[string]uint64),
+		changed: make(map[string]uint64),
+		removed: make(map[string]uint64),
+	}
+}
+
+var globalDeltaTracker = newMatchesDeltaTracker()
+
+// GetMatchesDelta returns matches of the in-memory store whose odds changed after cursor since
+// (all matches when since is empty, from another process or too old).
+func GetMatchesDelta(since string) handlers.MatchesDelta {
+	return globalDeltaTracker.delta(GetMatches(), since)
+}
+
+// matchFingerprint hashes the bet keys (event ID + outcome ID) of m with their odds.
+// Timestamps are left out: a re-parsed match with the same odds is not a change.
+func matchFingerprint(m *models.Match) uint64 {
+	keys := make([]string, 0, len(m.Events)*3)
+	for _, ev := range m.Events {
+		for _, o := range ev.Outcomes {
+			keys = append(keys, ev.ID+"|"+o.ID+"|"+strconv.FormatFloat(o.Odds, 'f', -1, 64))
+		}
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	if m.Stale {
+		_, _ = h.Write([]byte("stale"))
+	}
+	return h.Sum64()
+}
+
+// delta records the current matches and returns what changed after cursor since.
+func (t *matchesDeltaTracker) delta(matches []models.Match, since string) handlers.MatchesDelta {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	next := t.version + 1
+	bumped := false
+	present := make(map[string]bool, len(matches))
+	for i := range matches {
+		m := &matches[i]
+		present[m.ID] = true
+		h := matchFingerprint(m)
+		if old, ok := t.hashes[m.ID]; ok && old == h {
+			continue
+		}
+		t.hashes[m.ID] = h
+		t.changed[m.ID] = next
+		delete(t.removed, m.ID)
+		bumped = true
+	}
+	for id := range t.hashes {
+		if present[id] {
+			continue
+		}
+		delete(t.hashes, id)
+		delete(t.changed, id)
+		t.removed[id] = next
+		bumped = true
+	}
+	if bumped {
+		t.version = next
+	}
+	if len(t.removed) > maxDeltaRemoved {
+		t.removed = make(map[string]uint64)
+		t.floor = t.version
+	}
+
+	out := handlers.MatchesDelta{Cursor: fmt.Sprintf("%s:%d", t.epoch, t.version)}
+	from, ok := t.parseCursor(since)
+	if !ok {
+		out.Full = true
+		out.Matches = matches
+		return out
+	}
+	out.Matches = make([]models.Match, 0)
+	for i := range matches {
+		if t.changed[matches[i].ID] > from {
+			out.Matches = append(out.Matches, matches[i])
+		}
+	}
+	for id, v := range t.removed {
+		if v > from {
+			out.Removed = append(out.Removed, id)
+		}
+	}
+	sort.Strings(out.Removed)
+	return out
+}
+
+// parseCursor returns the version of a cursor issued by this tracker that is still answerable with a delta.
+func (t *matchesDeltaTracker) parseCursor(since string) (uint64, bool) {
+	epoch, version, ok := strings.Cut(since, ":")
+	if !ok || epoch != t.epoch {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(version, 10, 64)
+	if err != nil || v < t.floor || v > t.version {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func deltaMatch(id string, odds float64) models.Match {
+	return models.Match{ID: id, Name: id, Events: []models.Event{{
+		ID:       id + "_main",
+		Outcomes: []models.Outcome{{ID: id + "_home", Odds: odds}},
+	}}}
+}
+
+func deltaIDs(matches []models.Match) []string {
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestMatchesDeltaTracker(t *testing.T) {
+	tr := newMatchesDeltaTracker()
+
+	first := tr.delta([]models.Match{deltaMatch("a", 1.5), deltaMatch("b", 2.0)}, "")
+	if !first.Full || len(first.Matches) != 2 || first.Cursor == "" {
+		t.Fatalf("empty cursor must return the full set, got %+v", first)
+	}
+
+	// Same odds with a newer timestamp is not a change
+	same := deltaMatch("a", 1.5)
+	same.UpdatedAt = time.Now()
+	d := tr.delta([]models.Match{same, deltaMatch("b", 2.0)}, first.Cursor)
+	if d.Full || len(d.Matches) != 0 || len(d.Removed) != 0 || d.Cursor != first.Cursor {
+		t.Fatalf("expected empty delta, got %+v", d)
+	}
+
+	// b changed, a gone, c added
+	d = tr.delta([]models.Match{deltaMatch("b", 2.1), deltaMatch("c", 3.0)}, first.Cursor)
+	if d.Full || len(d.Removed) != 1 || d.Removed[0] != "a" {
+		t.Fatalf("expected a removed, got %+v", d)
+	}
+	if got := deltaIDs(d.Matches); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Fatalf("expected b and c changed, got %v", got)
+	}
+
+	// An older cursor still sees everything after it
+	if again := tr.delta([]models.Match{deltaMatch("b", 2.1), deltaMatch("c", 3.0)}, first.Cursor); len(again.Matches) != 2 {
+		t.Fatalf("older cursor lost changes: %+v", again)
+	}
+	if none := tr.delta([]models.Match{deltaMatch("b", 2.1), deltaMatch("c", 3.0)}, d.Cursor); len(none.Matches) != 0 || len(none.Removed) != 0 {
+		t.Fatalf("latest cursor must get an empty delta, got %+v", none)
+	}
+
+	// Cursor of another process (restart) gets the full set
+	if other := tr.delta([]models.Match{deltaMatch("b", 2.1)}, "other:1"); !other.Full || len(other.Matches) != 1 {
+		t.Fatalf("foreign cursor must return the full set, got %+v", other)
+	}
+}
+
+func TestMatchesDeltaCache_Aggregate(t *testing.T) {
+	tr := newMatchesDeltaTracker()
+	current := []models.Match{deltaMatch("a", 1.5), deltaMatch("b", 2.0)}
+	handlers.SetGetMatchesDeltaFunc(func(since string) handlers.MatchesDelta { return tr.delta(current, since) })
+	defer handlers.SetGetMatchesDeltaFunc(GetMatchesDelta)
+
+	var sent []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		handlers.HandleMatches(rec, r)
+		sent = append(sent, rec.Body.Len())
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		_, _ = w.Write(rec.Body.Bytes())
+	}))
+	defer srv.Close()
+
+	cache := NewMatchesDeltaCache()
+	services := map[string]string{"fonbet": srv.URL}
+	if got := deltaIDs(cache.Aggregate(context.Background(), services, time.Second)); len(got) != 2 {
+		t.Fatalf("expected 2 matches, got %v", got)
+	}
+
+	current = []models.Match{deltaMatch("b", 2.2), deltaMatch("c", 3.0)}
+	got := cache.Aggregate(context.Background(), services, time.Second)
+	if ids := deltaIDs(got); len(ids) != 2 || ids[0] != "b" || ids[1] != "c" {
+		t.Fatalf("expected b and c after delta, got %v", ids)
+	}
+	for _, m := range got {
+		if m.ID == "b" && m.Events[0].Outcomes[0].Odds != 2.2 {
+			t.Fatalf("b odds not updated: %+v", m)
+		}
+	}
+
+	// Nothing changed: the response carries no matches and the cache still serves all of them
+	if ids := deltaIDs(cache.Aggregate(context.Background(), services, time.Second)); len(ids) != 2 {
+		t.Fatalf("expected cached matches, got %v", ids)
+	}
+	if len(sent) != 3 || sent[2] >= sent[0] {
+		t.Fatalf("empty delta must be smaller than the full response, sizes %v", sent)
+	}
+}
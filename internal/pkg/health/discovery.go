@@ -0,0 +1,198 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultDiscoveryInterval is how often discovered services are re-read (parser.discovery.interval).
+const defaultDiscoveryInterval = 30 * time.Second
+
+// ServiceDiscoverer lists the bookmaker services of the orchestrator (name -> base URL).
+type ServiceDiscoverer interface {
+	Discover(ctx context.Context) (map[string]string, error)
+}
+
+// FileDiscoverer reads services from a directory file: a YAML (or JSON) map name -> base URL,
+// the same shape as parser.bookmaker_services. The file is re-read on every Discover.
+type FileDiscoverer struct {
+	Path string
+}
+
+// Discover implements ServiceDiscoverer.
+func (d FileDiscoverer) Discover(ctx context.Context) (map[string]string, error) {
+	data, err := os.ReadFile(d.Path)
+	if err != nil {
+		return nil, err
+	}
+	var services map[string]string
+	if err := yaml.Unmarshal(data, &services); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", d.Path, err)
+	}
+	return services, nil
+}
+
+// SRVDiscoverer finds services in the DNS SRV records of Name ("_bookmaker._tcp.bookmakers.internal").
+// Every target becomes a service named after the first label of its host ("fonbet.bookmakers.internal."
+// → "fonbet") with base URL scheme://host:port; of several targets with one name the first
+// (lowest priority) wins.
+type SRVDiscoverer struct {
+	Name   string
+	Scheme string // default "http"
+
+	lookupSRV func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) // nil = net.DefaultResolver
+}
+
+// Discover implements ServiceDiscoverer.
+func (d SRVDiscoverer) Discover(ctx context.Context) (map[string]string, error) {
+	lookup := d.lookupSRV
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupSRV
+	}
+	_, records, err := lookup(ctx, "", "", d.Name)
+	if err != nil {
+		return nil, err
+	}
+	scheme := d.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	services := make(map[string]string, len(records))
+	for _, r := range records {
+		host := strings.TrimSuffix(r.Target, ".")
+		name, _, _ := strings.Cut(host, ".")
+		if name == "" {
+			continue
+		}
+		if _, ok := services[name]; ok {
+			continue
+		}
+		services[name] = scheme + "://" + net.JoinHostPort(host, fmt.Sprint(r.Port))
+	}
+	return services, nil
+}
+
+// ServiceDirectory is the current set of bookmaker services of the orchestrator: parser.bookmaker_services
+// plus the services found by a ServiceDiscoverer (a static entry wins over a discovered one of the same name).
+type ServiceDirectory struct {
+	mu         sync.RWMutex
+	static     map[string]string
+	discovered map[string]string
+	watchers   []func(added, removed map[string]string)
+}
+
+// NewServiceDirectory returns a directory of the static services.
+func NewServiceDirectory(static map[string]string) *ServiceDirectory {
+	d := &ServiceDirectory{static: map[string]string{}, discovered: map[string]string{}}
+	for name, baseURL := range static {
+		if name != "" && baseURL != "" {
+			d.static[name] = baseURL
+		}
+	}
+	return d
+}
+
+// Services returns a copy of the current services.
+func (d *ServiceDirectory) Services() map[string]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.servicesLocked()
+}
+
+func (d *ServiceDirectory) servicesLocked() map[string]string {
+	out := make(map[string]string, len(d.static)+len(d.discovered))
+	maps.Copy(out, d.discovered)
+	maps.Copy(out, d.static)
+	return out
+}
+
+// OnChange calls fn after every change of the services; a service whose URL changed is both removed and added.
+func (d *ServiceDirectory) OnChange(fn func(added, removed map[string]string)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.watchers = append(d.watchers, fn)
+}
+
+// Refresh replaces the discovered services with the result of disc. On error the previous
+// services are kept, so a DNS hiccup doesn't drop every bookmaker.
+func (d *ServiceDirectory) Refresh(ctx context.Context, disc ServiceDiscoverer) error {
+	found, err := disc.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	before := d.servicesLocked()
+	d.discovered = make(map[string]string, len(found))
+	for name, baseURL := range found {
+		if name = strings.TrimSpace(name); name != "" && strings.TrimSpace(baseURL) != "" {
+			d.discovered[name] = strings.TrimSpace(baseURL)
+		}
+	}
+	after := d.servicesLocked()
+	watchers := append([]func(added, removed map[string]string){}, d.watchers...)
+	d.mu.Unlock()
+
+	added, removed := diffServices(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	slog.Info("Bookmaker services changed", "added", sortedKeys(added), "removed", sortedKeys(removed), "total", len(after))
+	for _, fn := range watchers {
+		fn(added, removed)
+	}
+	return nil
+}
+
+// Watch refreshes the directory from disc every interval (default 30s) until ctx is done.
+func (d *ServiceDirectory) Watch(ctx context.Context, disc ServiceDiscoverer, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultDiscoveryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Refresh(ctx, disc); err != nil && ctx.Err() == nil {
+				slog.Warn("Bookmaker service discovery failed, keeping the previous services", "error", err)
+			}
+		}
+	}
+}
+
+// diffServices returns the services of after missing from before (or with another URL) and vice versa.
+func diffServices(before, after map[string]string) (added, removed map[string]string) {
+	added, removed = map[string]string{}, map[string]string{}
+	for name, baseURL := range after {
+		if before[name] != baseURL {
+			added[name] = baseURL
+		}
+	}
+	for name, baseURL := range before {
+		if after[name] != baseURL {
+			removed[name] = baseURL
+		}
+	}
+	return added, removed
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type fakeDiscoverer struct {
+	services map[string]string
+	err      error
+}
+
+func (f *fakeDiscoverer) Discover(context.Context) (map[string]string, error) {
+	return f.services, f.err
+}
+
+func TestFileDiscoverer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	if err := os.WriteFile(path, []byte("fonbet: http://fonbet:8081\npinnacle: http://pinnacle:8082\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FileDiscoverer{Path: path}.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"fonbet": "http://fonbet:8081", "pinnacle": "http://pinnacle:8082"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+
+	if _, err := (FileDiscoverer{Path: filepath.Join(t.TempDir(), "missing.yaml")}).Discover(context.Background()); err == nil {
+		t.Error("missing file: want error")
+	}
+}
+
+func TestSRVDiscoverer(t *testing.T) {
+	d := SRVDiscoverer{
+		Name: "_bookmaker._tcp.bookmakers.internal",
+		lookupSRV: func(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			return "", []*net.SRV{
+				{Target: "fonbet.bookmakers.internal.", Port: 8081},
+				{Target: "pinnacle.bookmakers.internal.", Port: 8082},
+				{Target: "fonbet.backup.internal.", Port: 9081},
+			}, nil
+		},
+	}
+	got, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"fonbet":   "http://fonbet.bookmakers.internal:8081",
+		"pinnacle": "http://pinnacle.bookmakers.internal:8082",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+}
+
+func TestServiceDirectoryRefresh(t *testing.T) {
+	dir := NewServiceDirectory(map[string]string{"fonbet": "http://fonbet:8081"})
+	var added, removed []map[string]string
+	dir.OnChange(func(a, r map[string]string) {
+		added = append(added, a)
+		removed = append(removed, r)
+	})
+
+	disc := &fakeDiscoverer{services: map[string]string{
+		"fonbet":   "http://other:1", // static entry wins
+		"pinnacle": "http://pinnacle:8082",
+	}}
+	if err := dir.Refresh(context.Background(), disc); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"fonbet": "http://fonbet:8081", "pinnacle": "http://pinnacle:8082"}
+	if got := dir.Services(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Services() = %v, want %v", got, want)
+	}
+	if len(added) != 1 || !reflect.DeepEqual(added[0], map[string]string{"pinnacle": "http://pinnacle:8082"}) || len(removed[0]) != 0 {
+		t.Fatalf("first change = %v / %v, want pinnacle added", added, removed)
+	}
+
+	// unchanged result: no notification
+	if err := dir.Refresh(context.Background(), disc); err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 {
+		t.Fatalf("watchers notified %d times, want 1", len(added))
+	}
+
+	// discovery error keeps the previous services
+	disc.err = errors.New("dns timeout")
+	if err := dir.Refresh(context.Background(), disc); err == nil {
+		t.Fatal("want error")
+	}
+	if got := dir.Services(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after error Services() = %v, want %v", got, want)
+	}
+
+	// URL change is a removal plus an addition; a vanished service is removed
+	disc.err = nil
+	disc.services = map[string]string{"marathon": "http://marathon:8083"}
+	if err := dir.Refresh(context.Background(), disc); err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 2 {
+		t.Fatalf("watchers notified %d times, want 2", len(added))
+	}
+	if !reflect.DeepEqual(added[1], map[string]string{"marathon": "http://marathon:8083"}) ||
+		!reflect.DeepEqual(removed[1], map[string]string{"pinnacle": "http://pinnacle:8082"}) {
+		t.Errorf("second change = %v / %v", added[1], removed[1])
+	}
+
+	disc.services = map[string]string{"marathon": "http://marathon:9083"}
+	if err := dir.Refresh(context.Background(), disc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(added[2], map[string]string{"marathon": "http://marathon:9083"}) ||
+		!reflect.DeepEqual(removed[2], map[string]string{"marathon": "http://marathon:8083"}) {
+		t.Errorf("URL change = %v / %v", added[2], removed[2])
+	}
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Match store limit defaults (health.match_store).
+const (
+	defaultMaxMatches     = 100000
+	defaultMatchRetention = 4 * time.Hour
+	defaultEvictInterval  = time.Minute
+)
+
+// Eviction reasons (label of vodeneevbet_match_store_evictions_total).
+const (
+	evictExpired  = "expired"
+	evictCapacity = "capacity"
+)
+
+// StartMatchEviction bounds the in-memory match store: matches that started more than retention ago
+// are evicted every interval, and once the store holds maxMatches the least recently updated match
+// makes room for a new one. 0 = default (100000 matches, 4h, 1m); a negative maxMatches or retention
+// disables that limit. Matches without a start time never expire.
+func StartMatchEviction(ctx context.Context, maxMatches int, retention, interval time.Duration) {
+	if maxMatches == 0 {
+		maxMatches = defaultMaxMatches
+	}
+	if retention == 0 {
+		retention = defaultMatchRetention
+	}
+	if interval <= 0 {
+		interval = defaultEvictInterval
+	}
+
+	s := globalMatchStore
+	s.mu.Lock()
+	s.maxMatches = max(maxMatches, 0)
+	evicted := s.evictOverCapacity()
+	s.mu.Unlock()
+	if evicted > 0 {
+		slog.Info("Evicted matches over the match store capacity", "evicted", evicted, "max_matches", maxMatches)
+	}
+	slog.Info("Match store limits", "max_matches", maxMatches, "retention", retention, "evict_interval", interval)
+	if retention < 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if n := EvictExpiredMatches(now, retention); n > 0 {
+					slog.Info("Evicted matches past their start time", "evicted", n, "retention", retention)
+				}
+			}
+		}
+	}()
+}
+
+// EvictExpiredMatches removes the matches that started before now-retention. Returns the number removed.
+func EvictExpiredMatches(now time.Time, retention time.Duration) int {
+	s := globalMatchStore
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := now.Add(-retention)
+	evicted := 0
+	for id, m := range s.matches {
+		if !m.StartTime.IsZero() && m.StartTime.Before(cutoff) {
+			s.remove(id)
+			evicted++
+		}
+	}
+	if evicted > 0 {
+		metrics.MatchStoreEvictions.WithLabelValues(evictExpired).Add(float64(evicted))
+		s.updateSize()
+	}
+	return evicted
+}
+
+// touch marks id as the most recently updated match. Caller holds s.mu.
+func (s *InMemoryMatchStore) touch(id string) {
+	if el, ok := s.recent[id]; ok {
+		s.lru.MoveToFront(el)
+		return
+	}
+	s.recent[id] = s.lru.PushFront(id)
+}
+
+// remove deletes match id from the store. Caller holds s.mu.
+func (s *InMemoryMatchStore) remove(id string) {
+	delete(s.matches, id)
+	delete(s.stale, id)
+	if el, ok := s.recent[id]; ok {
+		s.lru.Remove(el)
+		delete(s.recent, id)
+	}
+}
+
+// reset empties the store. Caller holds s.mu.
+func (s *InMemoryMatchStore) reset() {
+	s.matches = make(map[string]*models.Match)
+	s.stale = make(map[string]bool)
+	s.recent = make(map[string]*list.Element)
+	s.lru = list.New()
+	s.updateSize()
+}
+
+// evictOverCapacity removes the least recently updated matches above maxMatches. Caller holds s.mu.
+func (s *InMemoryMatchStore) evictOverCapacity() int {
+	if s.maxMatches <= 0 {
+		return 0
+	}
+	evicted := 0
+	for len(s.matches) > s.maxMatches {
+		el := s.lru.Back()
+		if el == nil {
+			break
+		}
+		s.remove(el.Value.(string))
+		evicted++
+	}
+	if evicted > 0 {
+		metrics.MatchStoreEvictions.WithLabelValues(evictCapacity).Add(float64(evicted))
+		s.updateSize()
+	}
+	return evicted
+}
+
+// updateSize exposes the number of stored matches. Caller holds s.mu.
+func (s *InMemoryMatchStore) updateSize() {
+	metrics.MatchStoreMatches.Set(float64(len(s.matches)))
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMatchStore_EvictsLeastRecentlyUpdated(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartMatchEviction(ctx, 2, -1, 0)
+	defer StartMatchEviction(ctx, -1, -1, 0)
+
+	before := testutil.ToFloat64(metrics.MatchStoreEvictions.WithLabelValues(evictCapacity))
+	AddMatch(&models.Match{ID: "a"})
+	AddMatch(&models.Match{ID: "b"})
+	AddMatch(&models.Match{ID: "a"}) // a updated again: b is now the least recent
+	AddMatch(&models.Match{ID: "c"})
+
+	got := map[string]bool{}
+	for _, m := range GetMatches() {
+		got[m.ID] = true
+	}
+	if len(got) != 2 || !got["a"] || !got["c"] {
+		t.Fatalf("expected a and c to stay, got %v", got)
+	}
+	if n := testutil.ToFloat64(metrics.MatchStoreEvictions.WithLabelValues(evictCapacity)) - before; n != 1 {
+		t.Errorf("capacity evictions = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(metrics.MatchStoreMatches); n != 2 {
+		t.Errorf("match store size = %v, want 2", n)
+	}
+}
+
+func TestEvictExpiredMatches(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	AddMatch(&models.Match{ID: "finished", StartTime: now.Add(-5 * time.Hour)})
+	AddMatch(&models.Match{ID: "live", StartTime: now.Add(-time.Hour)})
+	AddMatch(&models.Match{ID: "upcoming", StartTime: now.Add(2 * time.Hour)})
+	AddMatch(&models.Match{ID: "no_start"})
+
+	if n := EvictExpiredMatches(now, 4*time.Hour); n != 1 {
+		t.Fatalf("evicted %d, want 1", n)
+	}
+	for _, m := range GetMatches() {
+		if m.ID == "finished" {
+			t.Fatal("match past its start time + retention must be evicted")
+		}
+	}
+	if n := testutil.ToFloat64(metrics.MatchStoreMatches); n != 3 {
+		t.Errorf("match store size = %v, want 3", n)
+	}
+	// An evicted match can be stored again and is tracked by the LRU
+	AddMatch(&models.Match{ID: "finished"})
+	if got := len(GetMatches()); got != 4 {
+		t.Errorf("got %d matches, want 4", got)
+	}
+}
+
+func TestAddMatches(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartMatchEviction(ctx, 2, -1, 0)
+	defer StartMatchEviction(ctx, -1, -1, 0)
+
+	pub := &recordingPublisher{}
+	SetMatchPublisher(pub)
+	defer SetMatchPublisher(nil)
+
+	AddMatches([]*models.Match{
+		{ID: "a", Events: []models.Event{{ID: "e1", Bookmaker: "fonbet"}}},
+		{ID: "b"},
+		{ID: "a", Events: []models.Event{{ID: "e2", Bookmaker: "leon"}}},
+		{ID: "c"},
+	})
+	if len(pub.matches) != 4 {
+		t.Errorf("published %d matches, want 4", len(pub.matches))
+	}
+	got := map[string]models.Match{}
+	for _, m := range GetMatches() {
+		got[m.ID] = m
+	}
+	// The batch is evicted once, least recent first: b
+	if len(got) != 2 || got["c"].ID == "" {
+		t.Fatalf("expected a and c to stay, got %v", got)
+	}
+	if n := len(got["a"].Events); n != 2 {
+		t.Errorf("a has %d events, want both bookmakers merged", n)
+	}
+	if n := testutil.ToFloat64(metrics.MatchStoreMatches); n != 2 {
+		t.Errorf("match store size = %v, want 2", n)
+	}
+}
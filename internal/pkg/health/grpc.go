@@ -0,0 +1,296 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	matchesv1 "github.com/Vodeneev/vodeneevbet/api/proto/matches/v1"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+const (
+	// defaultGRPCPushInterval is how often a subscription checks the store for changed odds (parser.grpc.push_interval).
+	defaultGRPCPushInterval = 2 * time.Second
+	// maxGRPCMessageSize fits the first (full) update of a large store.
+	maxGRPCMessageSize = 256 << 20
+	// maxGRPCReconnectDelay caps the backoff between reconnects of a broken stream.
+	maxGRPCReconnectDelay = 30 * time.Second
+)
+
+// matchesGRPCServer implements MatchesService on top of the delta tracker of /matches?since=.
+type matchesGRPCServer struct {
+	matchesv1.UnimplementedMatchesServiceServer
+	interval time.Duration
+	delta    handlers.GetMatchesDeltaFunc
+}
+
+// Subscribe sends the full set (or the delta after req.since) and then every change until the client leaves.
+// The first update is sent even when empty, so the client knows it is in sync.
+func (s *matchesGRPCServer) Subscribe(req *matchesv1.SubscribeRequest, stream grpc.ServerStreamingServer[matchesv1.MatchesUpdate]) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	cursor := req.GetSince()
+	first := true
+	for {
+		d := s.delta(cursor)
+		if first || d.Full || len(d.Matches) > 0 || len(d.Removed) > 0 {
+			if err := stream.Send(toProtoUpdate(d)); err != nil {
+				return err
+			}
+			first = false
+		}
+		cursor = d.Cursor
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// GRPCPushInterval returns the push interval of the gRPC subscriptions for parser.grpc.push_interval.
+func GRPCPushInterval(configured time.Duration) time.Duration {
+	if configured <= 0 {
+		return defaultGRPCPushInterval
+	}
+	return configured
+}
+
+// RunGRPC starts serving MatchesService on addr until ctx is done (bookmaker-service, parser.grpc.port).
+// Responses are gzip-compressed for clients that ask for it.
+func RunGRPC(ctx context.Context, addr string, pushInterval time.Duration) error {
+	pushInterval = GRPCPushInterval(pushInterval)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+	srv := grpc.NewServer(grpc.MaxSendMsgSize(maxGRPCMessageSize))
+	matchesv1.RegisterMatchesServiceServer(srv, &matchesGRPCServer{interval: pushInterval, delta: GetMatchesDelta})
+	go func() {
+		<-ctx.Done()
+		srv.Stop()
+	}()
+	go func() {
+		slog.Info("gRPC matches server listening", "addr", addr, "push_interval", pushInterval)
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("gRPC matches server error", "addr", addr, "error", err)
+		}
+	}()
+	return nil
+}
+
+// GRPCTargets returns name -> host:port of the gRPC listener of every bookmaker service:
+// overrides[name] if set, else the host of its bookmaker_services URL with port.
+// Services without an address are left out (served over HTTP).
+func GRPCTargets(services, overrides map[string]string, port int) map[string]string {
+	out := make(map[string]string, len(services))
+	for name, baseURL := range services {
+		if target := overrides[name]; target != "" {
+			out[name] = target
+			continue
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Hostname() == "" || port <= 0 {
+			slog.Warn("No gRPC address for bookmaker service, using HTTP", "name", name, "url", baseURL)
+			continue
+		}
+		out[name] = net.JoinHostPort(u.Hostname(), fmt.Sprint(port))
+	}
+	return out
+}
+
+// SubscribeGRPC keeps the cache of every service in targets (name -> host:port) up to date from its
+// MatchesService stream until ctx is done. While a stream is down the service is fetched over HTTP.
+func (c *MatchesDeltaCache) SubscribeGRPC(ctx context.Context, targets map[string]string) error {
+	for name, target := range targets {
+		conn, err := grpc.NewClient(target,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name), grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)),
+		)
+		if err != nil {
+			return fmt.Errorf("gRPC client for %s (%s): %w", name, target, err)
+		}
+		s := c.service(name)
+		client := matchesv1.NewMatchesServiceClient(conn)
+		go func() {
+			defer conn.Close()
+			s.follow(ctx, client, name, target)
+		}()
+	}
+	return nil
+}
+
+// follow reads the stream of one service, reconnecting with backoff until ctx is done.
+func (s *serviceMatches) follow(ctx context.Context, client matchesv1.MatchesServiceClient, name, target string) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		err := s.stream(ctx, client)
+		s.mu.Lock()
+		wasLive := s.live
+		s.live = false
+		s.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		if wasLive {
+			delay = time.Second
+		}
+		slog.Warn("gRPC matches stream broken, falling back to HTTP until reconnect", "name", name, "target", target, "error", err, "retry_in", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxGRPCReconnectDelay)
+	}
+}
+
+// stream subscribes from the cached cursor and applies updates until the stream fails.
+func (s *serviceMatches) stream(ctx context.Context, client matchesv1.MatchesServiceClient) error {
+	s.mu.Lock()
+	since := s.cursor
+	s.mu.Unlock()
+	st, err := client.Subscribe(ctx, &matchesv1.SubscribeRequest{Since: since})
+	if err != nil {
+		return err
+	}
+	for {
+		u, err := st.Recv()
+		if err != nil {
+			return err
+		}
+		matches := make([]models.Match, 0, len(u.GetMatches()))
+		for _, m := range u.GetMatches() {
+			matches = append(matches, fromProtoMatch(m))
+		}
+		s.mu.Lock()
+		s.applyLocked(matches, u.GetRemoved(), u.GetCursor(), u.GetFull())
+		s.live = true
+		s.mu.Unlock()
+	}
+}
+
+func toProtoUpdate(d handlers.MatchesDelta) *matchesv1.MatchesUpdate {
+	u := &matchesv1.MatchesUpdate{Removed: d.Removed, Cursor: d.Cursor, Full: d.Full}
+	u.Matches = make([]*matchesv1.Match, 0, len(d.Matches))
+	for i := range d.Matches {
+		u.Matches = append(u.Matches, ToProtoMatch(&d.Matches[i]))
+	}
+	return u
+}
+
+// ToProtoMatch converts a match to its protobuf model (MatchesService, the calculator gRPC API).
+func ToProtoMatch(m *models.Match) *matchesv1.Match {
+	pm := &matchesv1.Match{
+		Id:         m.ID,
+		Name:       m.Name,
+		HomeTeam:   m.HomeTeam,
+		AwayTeam:   m.AwayTeam,
+		StartTime:  protoTime(m.StartTime),
+		Sport:      m.Sport,
+		Tournament: m.Tournament,
+		Bookmaker:  m.Bookmaker,
+		CreatedAt:  protoTime(m.CreatedAt),
+		UpdatedAt:  protoTime(m.UpdatedAt),
+		Stale:      m.Stale,
+		Url:        m.URL,
+	}
+	pm.Events = make([]*matchesv1.Event, 0, len(m.Events))
+	for _, ev := range m.Events {
+		pe := &matchesv1.Event{
+			Id:         ev.ID,
+			MatchId:    ev.MatchID,
+			EventType:  ev.EventType,
+			MarketName: ev.MarketName,
+			Bookmaker:  ev.Bookmaker,
+			CreatedAt:  protoTime(ev.CreatedAt),
+			UpdatedAt:  protoTime(ev.UpdatedAt),
+		}
+		pe.Outcomes = make([]*matchesv1.Outcome, 0, len(ev.Outcomes))
+		for _, o := range ev.Outcomes {
+			pe.Outcomes = append(pe.Outcomes, &matchesv1.Outcome{
+				Id:          o.ID,
+				EventId:     o.EventID,
+				OutcomeType: o.OutcomeType,
+				Parameter:   o.Parameter,
+				Odds:        o.Odds,
+				Bookmaker:   o.Bookmaker,
+				Url:         o.URL,
+				CreatedAt:   protoTime(o.CreatedAt),
+				UpdatedAt:   protoTime(o.UpdatedAt),
+			})
+		}
+		pm.Events = append(pm.Events, pe)
+	}
+	return pm
+}
+
+func fromProtoMatch(pm *matchesv1.Match) models.Match {
+	m := models.Match{
+		ID:         pm.GetId(),
+		Name:       pm.GetName(),
+		HomeTeam:   pm.GetHomeTeam(),
+		AwayTeam:   pm.GetAwayTeam(),
+		StartTime:  goTime(pm.GetStartTime()),
+		Sport:      pm.GetSport(),
+		Tournament: pm.GetTournament(),
+		Bookmaker:  pm.GetBookmaker(),
+		CreatedAt:  goTime(pm.GetCreatedAt()),
+		UpdatedAt:  goTime(pm.GetUpdatedAt()),
+		Stale:      pm.GetStale(),
+		URL:        pm.GetUrl(),
+	}
+	m.Events = make([]models.Event, 0, len(pm.GetEvents()))
+	for _, pe := range pm.GetEvents() {
+		ev := models.Event{
+			ID:         pe.GetId(),
+			MatchID:    pe.GetMatchId(),
+			EventType:  pe.GetEventType(),
+			MarketName: pe.GetMarketName(),
+			Bookmaker:  pe.GetBookmaker(),
+			CreatedAt:  goTime(pe.GetCreatedAt()),
+			UpdatedAt:  goTime(pe.GetUpdatedAt()),
+		}
+		ev.Outcomes = make([]models.Outcome, 0, len(pe.GetOutcomes()))
+		for _, o := range pe.GetOutcomes() {
+			ev.Outcomes = append(ev.Outcomes, models.Outcome{
+				ID:          o.GetId(),
+				EventID:     o.GetEventId(),
+				OutcomeType: o.GetOutcomeType(),
+				Parameter:   o.GetParameter(),
+				Odds:        o.GetOdds(),
+				Bookmaker:   o.GetBookmaker(),
+				URL:         o.GetUrl(),
+				CreatedAt:   goTime(o.GetCreatedAt()),
+				UpdatedAt:   goTime(o.GetUpdatedAt()),
+			})
+		}
+		m.Events = append(m.Events, ev)
+	}
+	return m
+}
+
+// protoTime keeps zero times unset, so they round-trip as zero time.Time.
+func protoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func goTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	matchesv1 "github.com/Vodeneev/vodeneevbet/api/proto/matches/v1"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestProtoMatchRoundTrip(t *testing.T) {
+	now := time.Date(2026, 5, 1, 18, 30, 0, 0, time.UTC)
+	m := models.Match{
+		ID: "m1", Name: "A - B", HomeTeam: "A", AwayTeam: "B", StartTime: now, Sport: "football",
+		Tournament: "Premier League", Bookmaker: "fonbet", UpdatedAt: now, Stale: true,
+		Events: []models.Event{{
+			ID: "m1_main", MatchID: "m1", EventType: "main_match", MarketName: "Match Result", Bookmaker: "fonbet",
+			Outcomes: []models.Outcome{{ID: "o1", EventID: "m1_main", OutcomeType: "home_win", Odds: 1.95, Bookmaker: "fonbet", UpdatedAt: now}},
+		}},
+	}
+	if got := fromProtoMatch(ToProtoMatch(&m)); !reflect.DeepEqual(got, m) {
+		t.Fatalf("round trip changed the match:\ngot  %+v\nwant %+v", got, m)
+	}
+}
+
+func TestMatchesDeltaCache_SubscribeGRPC(t *testing.T) {
+	tr := newMatchesDeltaTracker()
+	var mu sync.Mutex
+	current := []models.Match{deltaMatch("a", 1.5), deltaMatch("b", 2.0)}
+	delta := func(since string) handlers.MatchesDelta {
+		mu.Lock()
+		defer mu.Unlock()
+		return tr.delta(current, since)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	matchesv1.RegisterMatchesServiceServer(srv, &matchesGRPCServer{interval: 10 * time.Millisecond, delta: delta})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache := NewMatchesDeltaCache()
+	if err := cache.SubscribeGRPC(ctx, map[string]string{"fonbet": lis.Addr().String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// HTTP is never called for a live service: an unreachable URL must not matter
+	services := map[string]string{"fonbet": "http://127.0.0.1:1"}
+	waitFor := func(what string, ok func([]models.Match) bool) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			s := cache.service("fonbet")
+			s.mu.Lock()
+			live := s.live
+			s.mu.Unlock()
+			if live {
+				if got := cache.Aggregate(ctx, services, time.Second); ok(got) {
+					return
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("full set", func(got []models.Match) bool { return len(got) == 2 })
+
+	mu.Lock()
+	current = []models.Match{deltaMatch("b", 2.2), deltaMatch("c", 3.0)}
+	mu.Unlock()
+	waitFor("streamed delta", func(got []models.Match) bool {
+		ids := deltaIDs(got)
+		if len(ids) != 2 || ids[0] != "b" || ids[1] != "c" {
+			return false
+		}
+		for _, m := range got {
+			if m.ID == "b" {
+				return m.Events[0].Outcomes[0].Odds == 2.2
+			}
+		}
+		return false
+	})
+}
+
+func TestGRPCTargets(t *testing.T) {
+	got := GRPCTargets(map[string]string{
+		"fonbet": "http://10.0.0.1:8081",
+		"xbet1":  "http://10.0.0.2:8085",
+	}, map[string]string{"xbet1": "xbet1-grpc:9000"}, 9090)
+	want := map[string]string{"fonbet": "10.0.0.1:9090", "xbet1": "xbet1-grpc:9000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GRPCTargets = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+)
+
+type GetBrowserStatsFunc func() browserpool.Stats
+
+var getBrowserStatsFunc GetBrowserStatsFunc
+
+func SetGetBrowserStatsFunc(fn GetBrowserStatsFunc) {
+	getBrowserStatsFunc = fn
+}
+
+// HandleBrowsers returns the metrics of the shared headless Chrome pool.
+// GET /browsers
+func HandleBrowsers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	var stats browserpool.Stats
+	if getBrowserStatsFunc != nil {
+		stats = getBrowserStatsFunc()
+	}
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		slog.Error("Failed to encode browsers response", "error", err)
+	}
+}
@@ -39,7 +39,7 @@ func HandleMatchByName(w http.ResponseWriter, r *http.Request) {
 	if getMatchesByNameFunc != nil {
 		matches = getMatchesByNameFunc(name)
 	} else if getMatchesFunc != nil {
-		all := getMatchesFunc()
+		all := getMatchesFunc(r.Context())
 		q := strings.ToLower(name)
 		for i := range all {
 			m := &all[i]
@@ -52,6 +52,9 @@ func HandleMatchByName(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if enrichMatchesFunc != nil {
+		matches = enrichMatchesFunc(matches)
+	}
 
 	duration := time.Since(startTime)
 	w.Header().Set("X-Query-Duration", duration.String())
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -10,7 +11,8 @@ import (
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
-type GetMatchesFunc func() []models.Match
+// GetMatchesFunc returns all matches; ctx is the request context (traced through to the bookmaker services).
+type GetMatchesFunc func(ctx context.Context) []models.Match
 
 var getMatchesFunc GetMatchesFunc
 
@@ -18,6 +20,23 @@ func SetGetMatchesFunc(fn GetMatchesFunc) {
 	getMatchesFunc = fn
 }
 
+// MatchesDelta is the answer to /matches?since=<cursor>: matches whose odds changed after the cursor
+// and IDs of matches gone since. Full means Matches is the whole set (empty, unknown or expired cursor).
+type MatchesDelta struct {
+	Matches []models.Match
+	Removed []string
+	Cursor  string
+	Full    bool
+}
+
+type GetMatchesDeltaFunc func(since string) MatchesDelta
+
+var getMatchesDeltaFunc GetMatchesDeltaFunc
+
+func SetGetMatchesDeltaFunc(fn GetMatchesDeltaFunc) {
+	getMatchesDeltaFunc = fn
+}
+
 type GetEsportsMatchesFunc func() []models.EsportsMatch
 
 var getEsportsMatchesFunc GetEsportsMatchesFunc
@@ -26,6 +45,31 @@ func SetGetEsportsMatchesFunc(fn GetEsportsMatchesFunc) {
 	getEsportsMatchesFunc = fn
 }
 
+type GetOutrightsFunc func() []models.Outright
+
+var getOutrightsFunc GetOutrightsFunc
+
+func SetGetOutrightsFunc(fn GetOutrightsFunc) {
+	getOutrightsFunc = fn
+}
+
+// EnrichMatchesFunc adds team/league metadata to matches before they are served (teaminfo).
+type EnrichMatchesFunc func([]models.Match) []models.Match
+
+var enrichMatchesFunc EnrichMatchesFunc
+
+func SetEnrichMatchesFunc(fn EnrichMatchesFunc) {
+	enrichMatchesFunc = fn
+}
+
+type EnrichEsportsMatchesFunc func([]models.EsportsMatch) []models.EsportsMatch
+
+var enrichEsportsMatchesFunc EnrichEsportsMatchesFunc
+
+func SetEnrichEsportsMatchesFunc(fn EnrichEsportsMatchesFunc) {
+	enrichEsportsMatchesFunc = fn
+}
+
 // HandleMatches returns cached matches (parsing runs continuously in background)
 func HandleMatches(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -41,24 +85,40 @@ func HandleMatches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Has("since") && getMatchesDeltaFunc != nil {
+		handleMatchesDelta(w, r.URL.Query().Get("since"), startTime)
+		return
+	}
+
 	var matches []models.Match
 	if getMatchesFunc != nil {
-		matches = getMatchesFunc()
+		matches = getMatchesFunc(r.Context())
+	}
+	if enrichMatchesFunc != nil {
+		matches = enrichMatchesFunc(matches)
 	}
 
 	duration := time.Since(startTime)
 	matchCount := len(matches)
+	staleCount := 0
+	for i := range matches {
+		if matches[i].Stale {
+			staleCount++
+		}
+	}
 
 	w.Header().Set("X-Query-Duration", duration.String())
 	w.Header().Set("X-Matches-Count", fmt.Sprintf("%d", matchCount))
 	w.Header().Set("X-Source", "memory")
+	w.Header().Set("X-Stale-Count", fmt.Sprintf("%d", staleCount))
 
-	slog.Info("Retrieved matches from memory", "count", matchCount, "duration", duration)
+	slog.Info("Retrieved matches from memory", "count", matchCount, "stale", staleCount, "duration", duration)
 
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
 		"matches": matches,
 		"meta": map[string]interface{}{
 			"count":    matchCount,
+			"stale":    staleCount, // served from the warm-up snapshot
 			"duration": duration.String(),
 			"source":   "memory",
 		},
@@ -69,6 +129,41 @@ func HandleMatches(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleMatchesDelta serves /matches?since=<cursor>: only matches whose odds changed after the cursor,
+// plus "removed" IDs and the cursor for the next request (meta.cursor). Used by the orchestrator.
+func handleMatchesDelta(w http.ResponseWriter, since string, startTime time.Time) {
+	delta := getMatchesDeltaFunc(since)
+	if enrichMatchesFunc != nil && len(delta.Matches) > 0 {
+		delta.Matches = enrichMatchesFunc(delta.Matches)
+	}
+	if delta.Removed == nil {
+		delta.Removed = []string{}
+	}
+
+	duration := time.Since(startTime)
+	w.Header().Set("X-Query-Duration", duration.String())
+	w.Header().Set("X-Matches-Count", fmt.Sprintf("%d", len(delta.Matches)))
+	w.Header().Set("X-Source", "memory")
+
+	slog.Debug("Retrieved matches delta from memory", "changed", len(delta.Matches), "removed", len(delta.Removed), "full", delta.Full, "cursor", delta.Cursor)
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"matches": delta.Matches,
+		"removed": delta.Removed,
+		"meta": map[string]interface{}{
+			"count":    len(delta.Matches),
+			"duration": duration.String(),
+			"source":   "memory",
+			"cursor":   delta.Cursor,
+			"full":     delta.Full,
+		},
+	}); err != nil {
+		slog.Error("Failed to encode matches delta", "error", err)
+		http.Error(w, fmt.Sprintf("Failed to encode matches: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
 // HandleEsportsMatches returns cached esports matches (киберспорт, отдельно от футбола)
 func HandleEsportsMatches(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -79,6 +174,9 @@ func HandleEsportsMatches(w http.ResponseWriter, r *http.Request) {
 	if getEsportsMatchesFunc != nil {
 		matches = getEsportsMatchesFunc()
 	}
+	if enrichEsportsMatchesFunc != nil {
+		matches = enrichEsportsMatchesFunc(matches)
+	}
 	duration := time.Since(startTime)
 	w.Header().Set("X-Query-Duration", duration.String())
 	w.Header().Set("X-Matches-Count", fmt.Sprintf("%d", len(matches)))
@@ -97,3 +195,31 @@ func HandleEsportsMatches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// HandleOutrights returns cached outright (futures) markets, e.g. league winner
+func HandleOutrights(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	var outrights []models.Outright
+	if getOutrightsFunc != nil {
+		outrights = getOutrightsFunc()
+	}
+	duration := time.Since(startTime)
+	w.Header().Set("X-Query-Duration", duration.String())
+	w.Header().Set("X-Source", "memory")
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"outrights": outrights,
+		"meta": map[string]interface{}{
+			"count":    len(outrights),
+			"duration": duration.String(),
+			"source":   "memory",
+		},
+	}); err != nil {
+		slog.Error("Failed to encode outrights", "error", err)
+		http.Error(w, fmt.Sprintf("Failed to encode outrights: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/performance"
 )
 
-// HandleMetrics handles /metrics endpoint
+// HandleMetrics returns the timings of the performance tracker as JSON (/metrics/performance)
 func HandleMetrics(w http.ResponseWriter, r *http.Request) {
 	tracker := performance.GetTracker()
 	metrics := tracker.GetMetrics()
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+)
+
+type ListMirrorsFunc func() []mirrors.Entry
+type SetMirrorOverrideFunc func(name, url string) error
+type InvalidateMirrorFunc func(name, reason string)
+
+var (
+	listMirrorsFunc       ListMirrorsFunc
+	setMirrorOverrideFunc SetMirrorOverrideFunc
+	invalidateMirrorFunc  InvalidateMirrorFunc
+)
+
+func SetListMirrorsFunc(fn ListMirrorsFunc) {
+	listMirrorsFunc = fn
+}
+
+func SetSetMirrorOverrideFunc(fn SetMirrorOverrideFunc) {
+	setMirrorOverrideFunc = fn
+}
+
+func SetInvalidateMirrorFunc(fn InvalidateMirrorFunc) {
+	invalidateMirrorFunc = fn
+}
+
+// MirrorsResponse is the JSON response of /mirrors.
+type MirrorsResponse struct {
+	Mirrors []mirrors.Entry `json:"mirrors"`
+	Count   int             `json:"count"`
+}
+
+// HandleMirrors returns the mirror registry: resolved URLs, overrides and resolution history.
+// GET /mirrors
+func HandleMirrors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	resp := MirrorsResponse{Mirrors: []mirrors.Entry{}}
+	if listMirrorsFunc != nil {
+		resp.Mirrors = listMirrorsFunc()
+	}
+	resp.Count = len(resp.Mirrors)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode mirrors response", "error", err)
+	}
+}
+
+// HandleMirrorOverride sets or clears the manual URL of a mirror (used instead of resolution).
+// POST /mirrors/override?name=xbet1&url=https://1xbet.example - set override
+// POST /mirrors/override?name=xbet1 - clear override (the mirror is resolved again)
+func HandleMirrorOverride(w http.ResponseWriter, r *http.Request) {
+	if !mirrorControlAllowed(w, r) {
+		return
+	}
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	rawURL := strings.TrimSpace(r.URL.Query().Get("url"))
+	if !validMirrorName(w, name) {
+		return
+	}
+	if setMirrorOverrideFunc == nil {
+		http.Error(w, `{"error": "mirror registry not available"}`, http.StatusInternalServerError)
+		return
+	}
+	if err := setMirrorOverrideFunc(name, rawURL); err != nil {
+		http.Error(w, fmt.Sprintf(`{"error": %q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+	response := map[string]interface{}{
+		"name":     name,
+		"override": rawURL,
+		"cleared":  rawURL == "",
+		"success":  true,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode mirror override response", "error", err)
+	}
+}
+
+// HandleMirrorInvalidate drops the persisted resolved URL of a mirror, so it is resolved again
+// after the cached URL stops working or on the next restart.
+// POST /mirrors/invalidate?name=pinnacle888
+func HandleMirrorInvalidate(w http.ResponseWriter, r *http.Request) {
+	if !mirrorControlAllowed(w, r) {
+		return
+	}
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if !validMirrorName(w, name) {
+		return
+	}
+	if invalidateMirrorFunc == nil {
+		http.Error(w, `{"error": "mirror registry not available"}`, http.StatusInternalServerError)
+		return
+	}
+	invalidateMirrorFunc(name, "invalidated via /mirrors/invalidate")
+	response := map[string]interface{}{
+		"name":    name,
+		"success": true,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode mirror invalidate response", "error", err)
+	}
+}
+
+func mirrorControlAllowed(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return true
+}
+
+// validMirrorName rejects an empty name or one that is not a mirror name (it names the entry file
+// of the registry) before the registry is touched.
+func validMirrorName(w http.ResponseWriter, name string) bool {
+	if name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return false
+	}
+	if !mirrors.ValidName(name) {
+		http.Error(w, fmt.Sprintf(`{"error": %q}`, fmt.Sprintf("invalid name %q", name)), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
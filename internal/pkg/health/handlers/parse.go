@@ -14,15 +14,19 @@ import (
 
 var getParsersFunc func() []interfaces.Parser
 
+// sportParseTimeout bounds one background /parse?sport= run.
+const sportParseTimeout = 10 * time.Minute
+
 func SetGetParsersFunc(fn func() []interfaces.Parser) {
 	getParsersFunc = fn
 }
 
 // HandleParse triggers parsing for a specific parser or all parsers
-// GET /parse?parser=pinnacle888 - parse specific parser
-// GET /parse - parse all parsers
+// POST /parse?parser=pinnacle888 - parse specific parser
+// POST /parse - parse all parsers
+// POST /parse?sport=cs - parse one sport (parsers without single-sport parsing run as usual)
 func HandleParse(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -30,6 +34,7 @@ func HandleParse(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
 	parserName := r.URL.Query().Get("parser")
+	sport := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sport")))
 	var parsers []interfaces.Parser
 	if getParsersFunc != nil {
 		parsers = getParsersFunc()
@@ -71,9 +76,22 @@ func HandleParse(w http.ResponseWriter, r *http.Request) {
 		var err error
 		var duration time.Duration
 		var triggered bool
-
-		// Check if parser supports incremental parsing
-		if incParser, ok := parser.(interfaces.IncrementalParser); ok {
+		var report *interfaces.ParseReport
+
+		sportParser, perSport := parser.(interfaces.SportParser)
+		if sport != "" && perSport {
+			// Single sport: run in background like an incremental cycle (sport runs can take minutes)
+			slog.Info("Triggering sport parsing via /parse endpoint", "parser", parser.GetName(), "sport", sport)
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), sportParseTimeout)
+				defer cancel()
+				if err := sportParser.ParseSport(ctx, sport); err != nil {
+					slog.Error("Sport parsing failed", "parser", sportParser.GetName(), "sport", sport, "error", err)
+				}
+			}()
+			duration = time.Since(startTime)
+			triggered = true
+		} else if incParser, ok := parser.(interfaces.IncrementalParser); ok {
 			// For incremental parsers, just trigger new cycle (non-blocking)
 			slog.Info("Triggering new incremental parsing cycle via /parse endpoint", "parser", parser.GetName())
 			err = incParser.TriggerNewCycle()
@@ -87,7 +105,13 @@ func HandleParse(w http.ResponseWriter, r *http.Request) {
 		} else {
 			// For regular parsers, run ParseOnce with timeout
 			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-			err = parser.ParseOnce(ctx)
+			if rp, ok := parser.(interfaces.ReportingParser); ok {
+				var r interfaces.ParseReport
+				r, err = rp.ParseOnceWithReport(ctx)
+				report = &r
+			} else {
+				err = parser.ParseOnce(ctx)
+			}
 			duration = time.Since(startTime)
 			cancel()
 		}
@@ -98,6 +122,12 @@ func HandleParse(w http.ResponseWriter, r *http.Request) {
 			"success":   err == nil,
 			"incremental": triggered,
 		}
+		if sport != "" && perSport {
+			result["sport"] = sport
+		}
+		if report != nil {
+			result["report"] = report
+		}
 		if err != nil {
 			result["error"] = err.Error()
 			if triggered {
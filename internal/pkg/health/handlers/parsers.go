@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+// RestartParserFunc stops a registered parser, drops its client state and starts a fresh instance.
+type RestartParserFunc func(name string) error
+
+var restartParserFunc RestartParserFunc
+
+// SetRestartParserFunc enables POST /admin/restart-parser (nil disables it).
+func SetRestartParserFunc(fn RestartParserFunc) {
+	restartParserFunc = fn
+}
+
+// ParserStatus is one entry of the /parsers response.
+type ParserStatus struct {
+	Name        string                   `json:"name"`
+	Incremental bool                     `json:"incremental"`
+	Paused      bool                     `json:"paused"`
+	LastCycle   *interfaces.CycleSummary `json:"last_cycle,omitempty"`
+}
+
+// ParsersResponse is the JSON response of /parsers (also decoded by the orchestrator's RemoteParser).
+type ParsersResponse struct {
+	Parsers []ParserStatus `json:"parsers"`
+	Count   int            `json:"count"`
+}
+
+// selectParsers returns registered parsers filtered by ?parser= (all when empty).
+// Writes an error response and returns nil when nothing matches.
+func selectParsers(w http.ResponseWriter, r *http.Request) []interfaces.Parser {
+	var parsers []interfaces.Parser
+	if getParsersFunc != nil {
+		parsers = getParsersFunc()
+	}
+	if len(parsers) == 0 {
+		http.Error(w, `{"error": "no parsers registered"}`, http.StatusInternalServerError)
+		return nil
+	}
+	name := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("parser")))
+	if name == "" {
+		return parsers
+	}
+	for _, p := range parsers {
+		if strings.ToLower(p.GetName()) == name {
+			return []interfaces.Parser{p}
+		}
+	}
+	http.Error(w, fmt.Sprintf(`{"error": "parser '%s' not found"}`, name), http.StatusNotFound)
+	return nil
+}
+
+// HandleParsers lists registered parsers with their pause state and last cycle summary.
+// GET /parsers[?parser=fonbet]
+func HandleParsers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	parsers := selectParsers(w, r)
+	if parsers == nil {
+		return
+	}
+
+	resp := ParsersResponse{Parsers: make([]ParserStatus, 0, len(parsers))}
+	for _, p := range parsers {
+		st := ParserStatus{Name: p.GetName()}
+		if _, ok := p.(interfaces.IncrementalParser); ok {
+			st.Incremental = true
+		}
+		if cp, ok := p.(interfaces.ControllableParser); ok {
+			st.Paused = cp.IsPaused()
+			if last := cp.LastCycle(); !last.Started.IsZero() {
+				st.LastCycle = &last
+			}
+		}
+		resp.Parsers = append(resp.Parsers, st)
+	}
+	resp.Count = len(resp.Parsers)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode parsers response", "error", err)
+	}
+}
+
+// HandlePauseParsers pauses incremental parsing.
+// POST /parsers/pause?parser=fonbet - pause one parser
+// POST /parsers/pause - pause all parsers
+func HandlePauseParsers(w http.ResponseWriter, r *http.Request) {
+	handleParserControl(w, r, "pause", interfaces.ControllableParser.Pause)
+}
+
+// HandleResumeParsers resumes incremental parsing (a new cycle starts immediately).
+// POST /parsers/resume?parser=fonbet - resume one parser
+// POST /parsers/resume - resume all parsers
+func HandleResumeParsers(w http.ResponseWriter, r *http.Request) {
+	handleParserControl(w, r, "resume", interfaces.ControllableParser.Resume)
+}
+
+func handleParserControl(w http.ResponseWriter, r *http.Request, action string, fn func(interfaces.ControllableParser) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	parsers := selectParsers(w, r)
+	if parsers == nil {
+		return
+	}
+
+	var results []map[string]interface{}
+	for _, p := range parsers {
+		result := map[string]interface{}{"parser": p.GetName()}
+		cp, ok := p.(interfaces.ControllableParser)
+		if !ok {
+			result["success"] = false
+			result["error"] = "parser does not support " + action
+			results = append(results, result)
+			continue
+		}
+		err := fn(cp)
+		result["success"] = err == nil
+		result["paused"] = cp.IsPaused()
+		if err != nil {
+			result["error"] = err.Error()
+			slog.Error("Parser control failed", "parser", p.GetName(), "action", action, "error", err)
+		} else {
+			slog.Info("Parser control applied", "parser", p.GetName(), "action", action)
+		}
+		results = append(results, result)
+	}
+
+	response := map[string]interface{}{
+		"action":  action,
+		"results": results,
+		"count":   len(results),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode parser control response", "error", err)
+	}
+}
+
+// HandleRestartParser restarts one parser without restarting the process: its incremental loop is
+// stopped, cached client state (resolved mirror, sessions) is dropped and a new instance starts.
+// POST /admin/restart-parser?parser=pinnacle888
+func HandleRestartParser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if strings.TrimSpace(r.URL.Query().Get("parser")) == "" {
+		http.Error(w, `{"error": "parser is required"}`, http.StatusBadRequest)
+		return
+	}
+	if restartParserFunc == nil {
+		http.Error(w, `{"error": "parser restart not available"}`, http.StatusInternalServerError)
+		return
+	}
+	parsers := selectParsers(w, r)
+	if parsers == nil {
+		return
+	}
+
+	name := parsers[0].GetName()
+	if err := restartParserFunc(name); err != nil {
+		slog.Error("Parser restart failed", "parser", name, "error", err)
+		http.Error(w, fmt.Sprintf(`{"error": %q}`, err.Error()), http.StatusInternalServerError)
+		return
+	}
+	response := map[string]interface{}{
+		"parser":       name,
+		"success":      true,
+		"restarted_at": time.Now().UTC(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to encode parser restart response", "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+)
+
+type GetProxyStatusesFunc func() []proxypool.Status
+
+var getProxyStatusesFunc GetProxyStatusesFunc
+
+func SetGetProxyStatusesFunc(fn GetProxyStatusesFunc) {
+	getProxyStatusesFunc = fn
+}
+
+// ProxiesResponse is the JSON response of /proxies (decoded by cmd/check-proxies -service).
+type ProxiesResponse struct {
+	Proxies []proxypool.Status `json:"proxies"`
+	Count   int                `json:"count"`
+	Banned  int                `json:"banned"`
+}
+
+// HandleProxies returns the live state of the shared proxy pool.
+// GET /proxies
+func HandleProxies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	resp := ProxiesResponse{Proxies: []proxypool.Status{}}
+	if getProxyStatusesFunc != nil {
+		resp.Proxies = getProxyStatusesFunc()
+	}
+	resp.Count = len(resp.Proxies)
+	for _, s := range resp.Proxies {
+		if s.Banned {
+			resp.Banned++
+		}
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode proxies response", "error", err)
+	}
+}
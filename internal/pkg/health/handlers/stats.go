@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+// ParserStats aggregates the parse reports of one parser since the service started.
+type ParserStats struct {
+	Parser        string                 `json:"parser"`
+	Runs          int                    `json:"runs"`
+	PartialRuns   int                    `json:"partial_runs"`   // some leagues failed
+	FailedRuns    int                    `json:"failed_runs"`    // nothing parsed
+	LeaguesFailed int                    `json:"leagues_failed"` // over all runs
+	MatchesAdded  int                    `json:"matches_added"`  // over all runs
+	LastSuccess   *time.Time             `json:"last_success,omitempty"`
+	LastReport    interfaces.ParseReport `json:"last_report"`
+	// Outcomes dropped by the validation in health.AddMatch (broken odds, lines, duplicates, margins)
+	Quarantined       int            `json:"quarantined"`
+	QuarantineReasons map[string]int `json:"quarantine_reasons,omitempty"`
+}
+
+// StatsResponse is the JSON response of /stats (also decoded by the orchestrator).
+type StatsResponse struct {
+	Parsers   []ParserStats `json:"parsers"`
+	Count     int           `json:"count"`
+	StartedAt time.Time     `json:"started_at"` // when the service process started
+	Uptime    string        `json:"uptime"`
+}
+
+// startedAt is the start time of the process, reported by /stats.
+var startedAt = time.Now()
+
+type GetParseStatsFunc func() []ParserStats
+
+var getParseStatsFunc GetParseStatsFunc
+
+func SetGetParseStatsFunc(fn GetParseStatsFunc) {
+	getParseStatsFunc = fn
+}
+
+// HandleStats returns per-parser run statistics with the last parse report, so a bookmaker that is
+// down (failed runs) can be told apart from single failing leagues (partial runs), and the service uptime.
+// The report of the last cycle also has its leagues, bookmaker requests, their latency and the proxy.
+// GET /stats
+func HandleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	resp := StatsResponse{
+		Parsers:   []ParserStats{},
+		StartedAt: startedAt.UTC(),
+		Uptime:    time.Since(startedAt).Round(time.Second).String(),
+	}
+	if getParseStatsFunc != nil {
+		resp.Parsers = getParseStatsFunc()
+	}
+	resp.Count = len(resp.Parsers)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode stats response", "error", err)
+	}
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+)
+
+func TestMirrorControl(t *testing.T) {
+	var calls []string
+	handlers.SetSetMirrorOverrideFunc(func(name, url string) error {
+		calls = append(calls, "override "+name)
+		return nil
+	})
+	handlers.SetInvalidateMirrorFunc(func(name, reason string) {
+		calls = append(calls, "invalidate "+name)
+	})
+	defer handlers.SetSetMirrorOverrideFunc(nil)
+	defer handlers.SetInvalidateMirrorFunc(nil)
+
+	override, invalidate := handlers.HandleMirrorOverride, handlers.HandleMirrorInvalidate
+	for _, tc := range []struct {
+		h              http.HandlerFunc
+		method, target string
+		want           int
+	}{
+		{override, http.MethodGet, "/mirrors/override?name=xbet1&url=https://1xbet.example", http.StatusMethodNotAllowed},
+		{invalidate, http.MethodGet, "/mirrors/invalidate?name=xbet1", http.StatusMethodNotAllowed},
+		{override, http.MethodPost, "/mirrors/override?name=../../etc/evil&url=https://1xbet.example", http.StatusBadRequest},
+		{invalidate, http.MethodPost, "/mirrors/invalidate?name=..%2Fevil", http.StatusBadRequest},
+		{invalidate, http.MethodPost, "/mirrors/invalidate", http.StatusBadRequest},
+		{override, http.MethodPost, "/mirrors/override?name=xbet1&url=https://1xbet.example", http.StatusOK},
+		{invalidate, http.MethodPost, "/mirrors/invalidate?name=pinnacle888", http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		tc.h(rec, httptest.NewRequest(tc.method, tc.target, nil))
+		if rec.Code != tc.want {
+			t.Errorf("%s %s: status = %d, want %d", tc.method, tc.target, rec.Code, tc.want)
+		}
+	}
+	if len(calls) != 2 || calls[0] != "override xbet1" || calls[1] != "invalidate pinnacle888" {
+		t.Errorf("registry calls = %v, want only the valid POSTs", calls)
+	}
+}
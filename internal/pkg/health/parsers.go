@@ -3,6 +3,7 @@ package health
 import (
 	"sync"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 )
 
@@ -19,6 +20,25 @@ func RegisterParsers(parsers []interfaces.Parser) {
 	globalParsers = parsers
 }
 
+// ReplaceParser swaps a registered parser for its restarted instance.
+func ReplaceParser(old, p interfaces.Parser) {
+	globalParsersMu.Lock()
+	defer globalParsersMu.Unlock()
+	for i, registered := range globalParsers {
+		if registered == old {
+			globalParsers[i] = p
+			return
+		}
+	}
+	globalParsers = append(globalParsers, p)
+}
+
+// SetParserRestarter enables POST /admin/restart-parser with fn restarting the named parser
+// (bookmaker-service: its parsers run in process; the orchestrator has nothing to restart).
+func SetParserRestarter(fn func(name string) error) {
+	handlers.SetRestartParserFunc(fn)
+}
+
 // GetParsers returns a copy of registered parsers (thread-safe)
 func GetParsers() []interfaces.Parser {
 	globalParsersMu.RLock()
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 // RemoteParser calls a bookmaker service's /parse endpoint (implements interfaces.Parser for orchestrator).
@@ -34,7 +36,8 @@ func NewRemoteParser(name, baseURL string, timeout time.Duration) *RemoteParser
 		name:    name,
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -54,13 +57,25 @@ func (p *RemoteParser) GetName() string {
 	return p.name
 }
 
-// ParseOnce triggers GET baseURL/parse on the bookmaker service.
+// ParseOnce triggers POST baseURL/parse on the bookmaker service.
 func (p *RemoteParser) ParseOnce(ctx context.Context) error {
+	return p.parse(ctx, "")
+}
+
+// ParseSport triggers POST baseURL/parse?sport=... on the bookmaker service (parser.sports).
+func (p *RemoteParser) ParseSport(ctx context.Context, sport string) error {
+	return p.parse(ctx, sport)
+}
+
+func (p *RemoteParser) parse(ctx context.Context, sport string) error {
 	u, err := url.Parse(p.baseURL + "/parse")
 	if err != nil {
 		return fmt.Errorf("invalid base URL: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if sport != "" {
+		u.RawQuery = url.Values{"sport": {sport}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
 	if err != nil {
 		return err
 	}
@@ -76,13 +91,98 @@ func (p *RemoteParser) ParseOnce(ctx context.Context) error {
 	return nil
 }
 
+// get performs GET baseURL+path and decodes a JSON body into out (when non-nil).
+func (p *RemoteParser) get(ctx context.Context, path string, out interface{}) error {
+	return p.do(ctx, http.MethodGet, path, out)
+}
+
+// do performs a method request to baseURL+path and decodes a JSON body into out (when non-nil).
+func (p *RemoteParser) do(ctx context.Context, method, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch %s%s: %w", p.baseURL, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s%s returned %d: %s", p.baseURL, path, resp.StatusCode, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// remoteStatus fetches /parsers of the bookmaker service (one service runs one parser).
+func (p *RemoteParser) remoteStatus() (handlers.ParsersResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var resp handlers.ParsersResponse
+	err := p.get(ctx, "/parsers", &resp)
+	return resp, err
+}
+
+// LastCycle returns the last cycle summary reported by the bookmaker service (zero value on error).
+func (p *RemoteParser) LastCycle() interfaces.CycleSummary {
+	resp, err := p.remoteStatus()
+	if err != nil {
+		slog.Warn("Failed to fetch remote parser status", "parser", p.name, "error", err)
+		return interfaces.CycleSummary{}
+	}
+	for _, st := range resp.Parsers {
+		if st.LastCycle != nil {
+			return *st.LastCycle
+		}
+	}
+	return interfaces.CycleSummary{}
+}
+
+// IsPaused reports whether the bookmaker service has paused its parser.
+func (p *RemoteParser) IsPaused() bool {
+	resp, err := p.remoteStatus()
+	if err != nil {
+		return false
+	}
+	for _, st := range resp.Parsers {
+		if st.Paused {
+			return true
+		}
+	}
+	return false
+}
+
+// Pause calls POST baseURL/parsers/pause on the bookmaker service.
+func (p *RemoteParser) Pause() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.do(ctx, http.MethodPost, "/parsers/pause", nil)
+}
+
+// Resume calls POST baseURL/parsers/resume on the bookmaker service.
+func (p *RemoteParser) Resume() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.do(ctx, http.MethodPost, "/parsers/resume", nil)
+}
+
+var _ interfaces.ControllableParser = (*RemoteParser)(nil)
+var _ interfaces.SportParser = (*RemoteParser)(nil)
+
 // matchesResponse is the JSON response from /matches endpoint.
+// Removed, Cursor and Full are only set in answers to /matches?since=<cursor>.
 type matchesResponse struct {
 	Matches []models.Match `json:"matches"`
+	Removed []string       `json:"removed"`
 	Meta    struct {
 		Count    int    `json:"count"`
 		Duration string `json:"duration"`
 		Source   string `json:"source"`
+		Cursor   string `json:"cursor"`
+		Full     bool   `json:"full"`
 	} `json:"meta"`
 }
 
@@ -92,7 +192,7 @@ func AggregateMatches(ctx context.Context, services map[string]string, timeout t
 	if len(services) == 0 {
 		return nil
 	}
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: tracing.Transport(nil)}
 	var mu sync.Mutex
 	var lists [][]models.Match
 	var wg sync.WaitGroup
@@ -101,13 +201,13 @@ func AggregateMatches(ctx context.Context, services map[string]string, timeout t
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			matches, err := fetchMatches(ctx, client, baseURL)
+			mr, err := fetchMatches(ctx, client, baseURL, nil)
 			if err != nil {
 				slog.Warn("Failed to fetch matches from bookmaker service", "name", name, "url", baseURL, "error", err)
 				return
 			}
 			mu.Lock()
-			lists = append(lists, matches)
+			lists = append(lists, mr.Matches)
 			mu.Unlock()
 		}()
 	}
@@ -115,11 +215,15 @@ func AggregateMatches(ctx context.Context, services map[string]string, timeout t
 	return MergeMatchLists(lists)
 }
 
-func fetchMatches(ctx context.Context, client *http.Client, baseURL string) ([]models.Match, error) {
+// fetchMatches calls baseURL/matches; with a non-nil since it asks for the delta after that cursor.
+func fetchMatches(ctx context.Context, client *http.Client, baseURL string, since *string) (*matchesResponse, error) {
 	u, err := url.Parse(baseURL + "/matches")
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
+	if since != nil {
+		u.RawQuery = url.Values{"since": {*since}}.Encode()
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
@@ -138,7 +242,119 @@ func fetchMatches(ctx context.Context, client *http.Client, baseURL string) ([]m
 	if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
 		return nil, err
 	}
-	return mr.Matches, nil
+	return &mr, nil
+}
+
+// serviceMatches is the last known match set of one bookmaker service.
+type serviceMatches struct {
+	mu      sync.Mutex // serializes fetches, so deltas apply in order
+	cursor  string     // empty until the service answered with a cursor
+	matches map[string]models.Match
+	live    bool // kept up to date by a gRPC stream, no HTTP fetch needed
+}
+
+// MatchesDeltaCache keeps the match set of every bookmaker service between aggregations:
+// after the first full answer only matches whose odds changed (/matches?since=<cursor>) are fetched.
+// Services that don't support deltas answer with the full set every time.
+type MatchesDeltaCache struct {
+	mu       sync.Mutex
+	services map[string]*serviceMatches
+}
+
+// NewMatchesDeltaCache creates an empty cache.
+func NewMatchesDeltaCache() *MatchesDeltaCache {
+	return &MatchesDeltaCache{services: make(map[string]*serviceMatches)}
+}
+
+func (c *MatchesDeltaCache) service(name string) *serviceMatches {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.services[name]
+	if !ok {
+		s = &serviceMatches{}
+		c.services[name] = s
+	}
+	return s
+}
+
+// Aggregate refreshes every service from its delta in parallel and merges the cached sets.
+// Services with a live gRPC stream are served from the cache; the others are fetched over HTTP.
+// A service that fails is left out and fetched in full next time (like AggregateMatches).
+func (c *MatchesDeltaCache) Aggregate(ctx context.Context, services map[string]string, timeout time.Duration) []models.Match {
+	if len(services) == 0 {
+		return nil
+	}
+	client := &http.Client{Timeout: timeout, Transport: tracing.Transport(nil)}
+	var mu sync.Mutex
+	var lists [][]models.Match
+	var wg sync.WaitGroup
+	for name, baseURL := range services {
+		name, baseURL := name, strings.TrimSuffix(baseURL, "/")
+		s := c.service(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matches, err := s.refresh(ctx, client, baseURL)
+			if err != nil {
+				slog.Warn("Failed to fetch matches from bookmaker service", "name", name, "url", baseURL, "error", err)
+				return
+			}
+			mu.Lock()
+			lists = append(lists, matches)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return MergeMatchLists(lists)
+}
+
+// refresh fetches the delta after s.cursor, applies it and returns the service's current matches.
+func (s *serviceMatches) refresh(ctx context.Context, client *http.Client, baseURL string) ([]models.Match, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live {
+		return s.snapshotLocked(), nil
+	}
+	since := s.cursor
+	mr, err := fetchMatches(ctx, client, baseURL, &since)
+	if err != nil {
+		s.cursor, s.matches = "", nil
+		return nil, err
+	}
+	s.applyLocked(mr.Matches, mr.Removed, mr.Meta.Cursor, mr.Meta.Full)
+	slog.Debug("Matches from bookmaker service", "url", baseURL, "received", len(mr.Matches), "removed", len(mr.Removed),
+		"full", mr.Meta.Full || mr.Meta.Cursor == "", "total", len(s.matches))
+	return s.snapshotLocked(), nil
+}
+
+// applyLocked applies an update; without a cursor (service without deltas) matches is the full set.
+func (s *serviceMatches) applyLocked(matches []models.Match, removed []string, cursor string, full bool) {
+	if full || cursor == "" || s.matches == nil {
+		s.matches = make(map[string]models.Match, len(matches))
+	}
+	for _, m := range matches {
+		s.matches[m.ID] = m
+	}
+	for _, id := range removed {
+		delete(s.matches, id)
+	}
+	s.cursor = cursor
+}
+
+// snapshotLocked returns copies of the cached matches.
+func (s *serviceMatches) snapshotLocked() []models.Match {
+	// Deep copies: MergeMatchLists updates outcomes in place and must not touch the cache
+	out := make([]models.Match, 0, len(s.matches))
+	for _, m := range s.matches {
+		events := make([]models.Event, len(m.Events))
+		for i, ev := range m.Events {
+			ev.Outcomes = append([]models.Outcome(nil), ev.Outcomes...)
+			events[i] = ev
+		}
+		m.Events = events
+		out = append(out, m)
+	}
+	return out
 }
 
 // RemoteParsers builds a slice of interfaces.Parser for orchestrator from bookmaker_services config.
@@ -153,21 +369,71 @@ func RemoteParsers(services map[string]string, timeout time.Duration) []interfac
 	return out
 }
 
-// SetMatchesAggregator sets GetMatchesFunc to fetch from bookmaker services and merge (orchestrator mode).
-func SetMatchesAggregator(services map[string]string, timeout time.Duration) {
+// SetMatchesAggregator sets GetMatchesFunc to fetch from the current services of dir and merge (orchestrator mode).
+// The returned cache can be kept up to date over gRPC with SubscribeGRPC.
+func SetMatchesAggregator(dir *ServiceDirectory, timeout time.Duration) *MatchesDeltaCache {
 	if timeout <= 0 {
 		timeout = 90 * time.Second
 	}
-	handlers.SetGetMatchesFunc(func() []models.Match {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	cache := NewMatchesDeltaCache()
+	handlers.SetGetMatchesFunc(func(ctx context.Context) []models.Match {
+		// Keep the trace of the request, not its cancellation: the cache is shared by all requests
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
 		defer cancel()
-		return AggregateMatches(ctx, services, timeout)
+		return cache.Aggregate(ctx, dir.Services(), timeout)
 	})
+	// The orchestrator's own store is empty: ?since= falls back to the full aggregated list
+	handlers.SetGetMatchesDeltaFunc(nil)
 	handlers.SetGetEsportsMatchesFunc(func() []models.EsportsMatch {
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		return AggregateEsportsMatches(ctx, services, timeout)
+		return AggregateEsportsMatches(ctx, dir.Services(), timeout)
+	})
+	handlers.SetGetOutrightsFunc(func() []models.Outright {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return AggregateOutrights(ctx, dir.Services(), timeout)
+	})
+	handlers.SetGetParseStatsFunc(func() []handlers.ParserStats {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return AggregateParseStats(ctx, dir.Services())
 	})
+	return cache
+}
+
+// AggregateParseStats collects /stats of all bookmaker services, sorted by parser name.
+// Unreachable services are listed as a failed run, so they stand out like a down bookmaker.
+func AggregateParseStats(ctx context.Context, services map[string]string) []handlers.ParserStats {
+	var mu sync.Mutex
+	var out []handlers.ParserStats
+	var wg sync.WaitGroup
+	for name, baseURL := range services {
+		if name == "" || baseURL == "" {
+			continue
+		}
+		p := NewRemoteParser(name, baseURL, 10*time.Second)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp handlers.StatsResponse
+			err := p.get(ctx, "/stats", &resp)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Warn("Failed to fetch stats from bookmaker service", "name", p.name, "url", p.baseURL, "error", err)
+				out = append(out, handlers.ParserStats{
+					Parser:     p.name,
+					LastReport: interfaces.ParseReport{Parser: p.name, Status: interfaces.ParseStatusFailed, Errors: []string{err.Error()}},
+				})
+				return
+			}
+			out = append(out, resp.Parsers...)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(out, func(i, j int) bool { return out[i].Parser < out[j].Parser })
+	return out
 }
 
 // esportsMatchesResponse is the JSON response from /esports/matches endpoint
@@ -185,7 +451,7 @@ func AggregateEsportsMatches(ctx context.Context, services map[string]string, ti
 	if len(services) == 0 {
 		return nil
 	}
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: tracing.Transport(nil)}
 	var mu sync.Mutex
 	// name -> matches, to log per-service counts and merge
 	byService := make(map[string][]models.EsportsMatch)
@@ -246,3 +512,62 @@ func fetchEsportsMatches(ctx context.Context, client *http.Client, baseURL strin
 	}
 	return mr.Matches, nil
 }
+
+// outrightsResponse is the JSON response from /outrights endpoint
+type outrightsResponse struct {
+	Outrights []models.Outright `json:"outrights"`
+}
+
+// AggregateOutrights fetches /outrights from each bookmaker service in parallel and merges.
+func AggregateOutrights(ctx context.Context, services map[string]string, timeout time.Duration) []models.Outright {
+	if len(services) == 0 {
+		return nil
+	}
+	client := &http.Client{Timeout: timeout, Transport: tracing.Transport(nil)}
+	var mu sync.Mutex
+	var lists [][]models.Outright
+	var wg sync.WaitGroup
+	for name, baseURL := range services {
+		name, baseURL := name, strings.TrimSuffix(baseURL, "/")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outrights, err := fetchOutrights(ctx, client, baseURL)
+			if err != nil {
+				slog.Warn("Failed to fetch outrights from bookmaker service", "name", name, "url", baseURL, "error", err)
+				return
+			}
+			mu.Lock()
+			lists = append(lists, outrights)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return MergeOutrightLists(lists)
+}
+
+func fetchOutrights(ctx context.Context, client *http.Client, baseURL string) ([]models.Outright, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/outrights", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// Older bookmaker services don't expose outrights yet.
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	var or outrightsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&or); err != nil {
+		return nil, err
+	}
+	return or.Outrights, nil
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+)
+
+func TestRemoteParser_PauseResumePost(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	p := NewRemoteParser("fonbet", srv.URL, time.Second)
+	if err := p.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "POST /parsers/pause" || got[1] != "POST /parsers/resume" {
+		t.Errorf("requests = %v, want POST /parsers/pause and POST /parsers/resume", got)
+	}
+}
+
+func TestParserControl_RejectsGet(t *testing.T) {
+	for path, h := range map[string]http.HandlerFunc{
+		"/parsers/pause":  handlers.HandlePauseParsers,
+		"/parsers/resume": handlers.HandleResumeParsers,
+	} {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("GET %s: status = %d, want 405", path, rec.Code)
+		}
+	}
+}
@@ -8,28 +8,48 @@ import (
 	"os"
 	"time"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/apiauth"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/browserpool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/chaos"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/mirrors"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/teaminfo"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 func init() {
-	handlers.SetGetMatchesFunc(GetMatches)
+	handlers.SetGetMatchesFunc(func(context.Context) []models.Match { return GetMatches() })
+	handlers.SetGetMatchesDeltaFunc(GetMatchesDelta)
 	handlers.SetGetMatchesByNameFunc(GetMatchesByName)
 	handlers.SetGetEsportsMatchesFunc(GetEsportsMatches)
+	handlers.SetGetOutrightsFunc(GetOutrights)
 	handlers.SetGetParsersFunc(GetParsers)
+	handlers.SetGetParseStatsFunc(ParseStats)
+	handlers.SetGetProxyStatusesFunc(proxypool.Statuses)
+	handlers.SetGetBrowserStatsFunc(browserpool.CurrentStats)
+	handlers.SetListMirrorsFunc(mirrors.List)
+	handlers.SetSetMirrorOverrideFunc(mirrors.SetOverride)
+	handlers.SetInvalidateMirrorFunc(mirrors.Invalidate)
+	handlers.SetEnrichMatchesFunc(teaminfo.EnrichMatches)
+	handlers.SetEnrichEsportsMatchesFunc(teaminfo.EnrichEsportsMatches)
 }
 
-func Run(ctx context.Context, addr string, service string, storage interfaces.Storage, readHeaderTimeout time.Duration, parsingTimeout time.Duration) {
-	// parsingTimeout parameter kept for backward compatibility but not used
-	// (parsing now runs continuously in background, not triggered by requests)
+// Handler returns the endpoints of the health server without the auth, chaos and tracing middleware
+// (cmd/allinone serves them to the calculator and the bot in process).
+func Handler(service string) http.Handler {
 	mux := http.NewServeMux()
 
-	// Health endpoints
-	mux.HandleFunc("/ping", handlers.HandlePing)
-	mux.HandleFunc("/health", handlers.HandleHealth)
+	// Health endpoints: /healthz, /readyz (a successful parse cycle), /health/dependencies; /ping and /health are aliases of /healthz
+	newChecker(service).Register(mux)
 
-	// Metrics endpoint
-	mux.HandleFunc("/metrics", handlers.HandleMetrics)
+	// Prometheus metrics; the JSON timings of the performance tracker moved to /metrics/performance
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/metrics/performance", handlers.HandleMetrics)
 
 	// Matches endpoint (football)
 	mux.HandleFunc("/matches", handlers.HandleMatches)
@@ -37,12 +57,46 @@ func Run(ctx context.Context, addr string, service string, storage interfaces.St
 	// Esports matches (киберспорт, отдельная модель)
 	mux.HandleFunc("/esports/matches", handlers.HandleEsportsMatches)
 
+	// Outrights (долгосрочные ставки на турнир, отдельная модель)
+	mux.HandleFunc("/outrights", handlers.HandleOutrights)
+
 	// Match by name (for testing): returns matches with full events and coefficients
 	mux.HandleFunc("/match-by-name", handlers.HandleMatchByName)
 
 	// Manual parse endpoint
 	mux.HandleFunc("/parse", handlers.HandleParse)
 
+	// Parser control: статус циклов и пауза/возобновление инкрементального парсинга
+	mux.HandleFunc("/parsers", handlers.HandleParsers)
+	mux.HandleFunc("/parsers/pause", handlers.HandlePauseParsers)
+	mux.HandleFunc("/parsers/resume", handlers.HandleResumeParsers)
+	mux.HandleFunc("/admin/restart-parser", handlers.HandleRestartParser)
+
+	// Parse stats: отчёты запусков парсеров (ok / partial / failed, упавшие лиги)
+	mux.HandleFunc("/stats", handlers.HandleStats)
+
+	// Proxy pool: статистика и баны прокси (cmd/check-proxies -service)
+	mux.HandleFunc("/proxies", handlers.HandleProxies)
+
+	// Browser pool: очередь и метрики headless Chrome (резолв зеркал)
+	mux.HandleFunc("/browsers", handlers.HandleBrowsers)
+
+	// Mirror registry: сохранённые зеркала, история резолва и ручная подмена URL
+	mux.HandleFunc("/mirrors", handlers.HandleMirrors)
+	mux.HandleFunc("/mirrors/override", handlers.HandleMirrorOverride)
+	mux.HandleFunc("/mirrors/invalidate", handlers.HandleMirrorInvalidate)
+
+	// Event log: циклы парсеров и остальные события сервиса (и вышестоящих при оркестраторе)
+	mux.HandleFunc("/events", eventlog.Handle)
+
+	// pprof и статистика рантайма (-debug-endpoints)
+	registerDebug(mux)
+	return mux
+}
+
+func Run(ctx context.Context, addr string, service string, storage interfaces.Storage, readHeaderTimeout time.Duration, parsingTimeout time.Duration) {
+	// parsingTimeout parameter kept for backward compatibility but not used
+	// (parsing now runs continuously in background, not triggered by requests)
 	if readHeaderTimeout <= 0 {
 		slog.Error("read_header_timeout must be specified in config")
 		os.Exit(1)
@@ -50,7 +104,7 @@ func Run(ctx context.Context, addr string, service string, storage interfaces.St
 
 	srv := &http.Server{
 		Addr:              addr,
-		Handler:           mux,
+		Handler:           tracing.Handler(apiauth.Middleware(chaos.Middleware(Handler(service))), "health"),
 		ReadHeaderTimeout: readHeaderTimeout,
 	}
 
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"sync"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Sink receives the data parsers publish via AddMatch, AddEsportsMatch and AddOutright instead of
+// the in-memory stores (parser dry-run writes it to a file rather than serving it).
+type Sink interface {
+	AddMatch(match *models.Match)
+	AddEsportsMatch(match *models.EsportsMatch)
+	AddOutright(o *models.Outright)
+}
+
+var (
+	sinkMu sync.RWMutex
+	sink   Sink
+)
+
+// SetSink redirects parser output to s; nil restores the in-memory stores.
+func SetSink(s Sink) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sink = s
+}
+
+func currentSink() Sink {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+	return sink
+}
+
+// MatchPublisher receives every match passed to AddMatch in addition to the store or sink
+// (message-bus fan-out, see internal/pkg/bus).
+type MatchPublisher interface {
+	PublishMatch(match *models.Match)
+}
+
+var (
+	publisherMu sync.RWMutex
+	publisher   MatchPublisher
+)
+
+// SetMatchPublisher publishes every added match to p; nil stops publishing.
+func SetMatchPublisher(p MatchPublisher) {
+	publisherMu.Lock()
+	defer publisherMu.Unlock()
+	publisher = p
+}
+
+func currentPublisher() MatchPublisher {
+	publisherMu.RLock()
+	defer publisherMu.RUnlock()
+	return publisher
+}
@@ -0,0 +1,40 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+type recordingPublisher struct{ matches []models.Match }
+
+func (p *recordingPublisher) PublishMatch(m *models.Match) { p.matches = append(p.matches, *m) }
+
+type recordingSink struct{ recordingPublisher }
+
+func (s *recordingSink) AddMatch(m *models.Match)               { s.PublishMatch(m) }
+func (s *recordingSink) AddEsportsMatch(m *models.EsportsMatch) {}
+func (s *recordingSink) AddOutright(o *models.Outright)         {}
+
+func TestAddMatchPublishes(t *testing.T) {
+	pub := &recordingPublisher{}
+	sink := &recordingSink{}
+	SetMatchPublisher(pub)
+	SetSink(sink)
+	defer SetMatchPublisher(nil)
+	defer SetSink(nil)
+
+	AddMatch(&models.Match{ID: "a|b|t", Sport: "football", Tournament: "FIFA. Cyber League", Bookmaker: "fonbet"})
+	if len(pub.matches) != 1 || len(sink.matches) != 1 {
+		t.Fatalf("published %d, stored %d matches; want 1 each", len(pub.matches), len(sink.matches))
+	}
+	if pub.matches[0].Sport != "cyber_football" {
+		t.Errorf("published sport = %q, want the cyber_football tag", pub.matches[0].Sport)
+	}
+
+	SetMatchPublisher(nil)
+	AddMatch(&models.Match{ID: "c|d|t", Sport: "football", Bookmaker: "fonbet"})
+	if len(pub.matches) != 1 {
+		t.Error("match published after SetMatchPublisher(nil)")
+	}
+}
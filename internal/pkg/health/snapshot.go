@@ -0,0 +1,144 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Snapshot is the on-disk copy of the match store used to warm up a restarted bookmaker-service.
+type Snapshot struct {
+	SavedAt time.Time      `json:"saved_at"`
+	Matches []models.Match `json:"matches"`
+}
+
+// SaveSnapshot writes the fresh (non-stale) matches to path atomically (temp file + rename).
+// Nothing is written while the store holds no fresh matches, so a restart before the first
+// cycle completes keeps the previous snapshot. Returns the number of matches saved.
+func SaveSnapshot(path string) (int, error) {
+	all := GetMatches()
+	snap := Snapshot{SavedAt: time.Now(), Matches: make([]models.Match, 0, len(all))}
+	for _, m := range all {
+		if !m.Stale {
+			snap.Matches = append(snap.Matches, m)
+		}
+	}
+	if len(snap.Matches) == 0 {
+		return 0, nil
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return 0, fmt.Errorf("marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("create snapshot dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return 0, fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, fmt.Errorf("rename snapshot: %w", err)
+	}
+	return len(snap.Matches), nil
+}
+
+// LoadSnapshot puts the matches from path into the store marked stale (Match.Stale) until a
+// parser stores them again or DropStaleMatches is called. A missing file or a snapshot older
+// than maxAge (0 = no limit) is not an error. Returns the number of matches loaded.
+func LoadSnapshot(path string, maxAge time.Duration) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return 0, fmt.Errorf("decode snapshot: %w", err)
+	}
+	if age := time.Since(snap.SavedAt); maxAge > 0 && age > maxAge {
+		slog.Info("Snapshot is too old, not loading", "path", path, "age", age.Round(time.Second), "max_age", maxAge)
+		return 0, nil
+	}
+
+	globalMatchStore.mu.Lock()
+	defer globalMatchStore.mu.Unlock()
+	loaded := 0
+	for i := range snap.Matches {
+		m := &snap.Matches[i]
+		if _, exists := globalMatchStore.matches[m.ID]; exists {
+			continue // already refreshed by a parser
+		}
+		m.Stale = false
+		mergeMatchInto(globalMatchStore.matches, m)
+		globalMatchStore.touch(m.ID)
+		globalMatchStore.stale[m.ID] = true
+		loaded++
+	}
+	globalMatchStore.evictOverCapacity()
+	globalMatchStore.updateSize()
+	return loaded, nil
+}
+
+// pendingFresh holds the parsers that haven't finished a cycle since the snapshot was loaded.
+// A multi-parser bookmaker-service keeps stale matches until all of them have (nil = don't wait).
+var (
+	pendingFreshMu sync.Mutex
+	pendingFresh   map[string]bool
+)
+
+// ExpectFreshCycles makes ParserCycleDone keep stale matches until each of parsers finished a cycle.
+func ExpectFreshCycles(parsers []string) {
+	pendingFreshMu.Lock()
+	defer pendingFreshMu.Unlock()
+	pendingFresh = make(map[string]bool, len(parsers))
+	for _, name := range parsers {
+		pendingFresh[strings.ToLower(name)] = true
+	}
+}
+
+// ParserCycleDone records a fresh cycle of parser and drops the stale matches once no parser
+// registered with ExpectFreshCycles is still pending. Returns the number of matches dropped.
+func ParserCycleDone(parser string) int {
+	pendingFreshMu.Lock()
+	if pendingFresh != nil {
+		delete(pendingFresh, strings.ToLower(parser))
+		if len(pendingFresh) > 0 {
+			pendingFreshMu.Unlock()
+			return 0
+		}
+		pendingFresh = nil
+	}
+	pendingFreshMu.Unlock()
+	return DropStaleMatches()
+}
+
+// DropStaleMatches removes snapshot matches that no parsing cycle has refreshed.
+// Called once a fresh cycle completes; no-op when nothing is stale.
+func DropStaleMatches() int {
+	if globalMatchStore == nil {
+		return 0
+	}
+	globalMatchStore.mu.Lock()
+	defer globalMatchStore.mu.Unlock()
+	dropped := len(globalMatchStore.stale)
+	if dropped == 0 {
+		return 0
+	}
+	for id := range globalMatchStore.stale {
+		globalMatchStore.remove(id)
+	}
+	globalMatchStore.updateSize()
+	slog.Info("Dropped stale snapshot matches not refreshed by the first cycle", "dropped", dropped, "total_matches_in_store", len(globalMatchStore.matches))
+	return dropped
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestSnapshot_ServedStaleUntilRefreshed(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	path := filepath.Join(t.TempDir(), "fonbet.json")
+
+	AddMatch(&models.Match{ID: "a", Name: "A - B", Events: []models.Event{{ID: "a_main"}}})
+	AddMatch(&models.Match{ID: "c", Name: "C - D"})
+	if n, err := SaveSnapshot(path); err != nil || n != 2 {
+		t.Fatalf("SaveSnapshot = %d, %v", n, err)
+	}
+
+	// Restart: empty store, snapshot loaded
+	ClearMatches()
+	if n, err := LoadSnapshot(path, time.Hour); err != nil || n != 2 {
+		t.Fatalf("LoadSnapshot = %d, %v", n, err)
+	}
+	for _, m := range GetMatches() {
+		if !m.Stale {
+			t.Fatalf("match %s loaded from snapshot must be stale", m.ID)
+		}
+	}
+	// Only stale matches: nothing to save, the previous snapshot is kept
+	if n, _ := SaveSnapshot(path); n != 0 {
+		t.Fatalf("stale matches must not be saved again, saved %d", n)
+	}
+
+	// Fresh copy replaces the snapshot one (old events are not merged in)
+	AddMatch(&models.Match{ID: "a", Name: "A - B", Events: []models.Event{{ID: "a_corners"}}})
+	if got := GetMatchesByName("A - B"); len(got) != 1 || got[0].Stale || len(got[0].Events) != 1 || got[0].Events[0].ID != "a_corners" {
+		t.Fatalf("expected fresh match with only new events, got %+v", got)
+	}
+
+	if dropped := DropStaleMatches(); dropped != 1 {
+		t.Fatalf("expected 1 stale match dropped, got %d", dropped)
+	}
+	if got := GetMatches(); len(got) != 1 || got[0].ID != "a" {
+		t.Fatalf("expected only the refreshed match, got %+v", got)
+	}
+}
+
+func TestLoadSnapshot_MissingOrTooOld(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	path := filepath.Join(t.TempDir(), "pinnacle.json")
+
+	if n, err := LoadSnapshot(path, time.Hour); err != nil || n != 0 {
+		t.Fatalf("missing snapshot: got %d, %v", n, err)
+	}
+	AddMatch(&models.Match{ID: "x"})
+	if _, err := SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	ClearMatches()
+	time.Sleep(5 * time.Millisecond)
+	if n, err := LoadSnapshot(path, time.Millisecond); err != nil || n != 0 {
+		t.Fatalf("too old snapshot must be ignored: got %d, %v", n, err)
+	}
+}
+
+func TestParserCycleDone_WaitsForAllParsers(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	path := filepath.Join(t.TempDir(), "fonbet+leon.json")
+
+	AddMatch(&models.Match{ID: "f", Name: "F - G"})
+	AddMatch(&models.Match{ID: "l", Name: "L - M"})
+	if _, err := SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	ClearMatches()
+	if n, err := LoadSnapshot(path, time.Hour); err != nil || n != 2 {
+		t.Fatalf("LoadSnapshot = %d, %v", n, err)
+	}
+	ExpectFreshCycles([]string{"fonbet", "Leon"})
+
+	if dropped := ParserCycleDone("Fonbet"); dropped != 0 {
+		t.Fatalf("dropped %d stale matches before leon finished a cycle", dropped)
+	}
+	if got := GetMatches(); len(got) != 2 {
+		t.Fatalf("expected both stale matches kept, got %d", len(got))
+	}
+	if dropped := ParserCycleDone("leon"); dropped != 2 {
+		t.Fatalf("expected 2 stale matches dropped after all parsers, got %d", dropped)
+	}
+	// Nothing pending: later cycles drop immediately (no-op without stale matches)
+	if dropped := ParserCycleDone("fonbet"); dropped != 0 {
+		t.Fatalf("unexpected drop %d", dropped)
+	}
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+)
+
+// Parse reports of this process by lowercase parser name (/stats)
+var (
+	parseStatsMu sync.Mutex
+	parseStats   = make(map[string]*handlers.ParserStats)
+)
+
+// RecordParseReport adds one parsing run to the statistics served on /stats.
+func RecordParseReport(r interfaces.ParseReport) {
+	key := strings.ToLower(r.Parser)
+	parseStatsMu.Lock()
+	defer parseStatsMu.Unlock()
+	st := parseStats[key]
+	if st == nil {
+		st = &handlers.ParserStats{Parser: r.Parser}
+		parseStats[key] = st
+	}
+	st.Runs++
+	switch r.Status {
+	case interfaces.ParseStatusPartial:
+		st.PartialRuns++
+	case interfaces.ParseStatusFailed:
+		st.FailedRuns++
+	}
+	if r.Status != interfaces.ParseStatusFailed {
+		finished := r.Finished
+		st.LastSuccess = &finished
+	}
+	st.LeaguesFailed += r.LeaguesFailed
+	st.MatchesAdded += r.MatchesAdded
+	st.LastReport = r
+
+	if !r.Started.IsZero() && !r.Finished.IsZero() {
+		metrics.ParseCycleDuration.WithLabelValues(key, r.Status).Observe(r.Finished.Sub(r.Started).Seconds())
+	}
+	metrics.MatchesParsed.WithLabelValues(key).Add(float64(r.MatchesAdded))
+}
+
+// recordQuarantine adds the outcomes quarantined by validateMatch in a match of bookmaker to the statistics
+// of the parser with the same name.
+func recordQuarantine(bookmaker string, quarantined map[string]int) {
+	key := strings.ToLower(bookmaker)
+	parseStatsMu.Lock()
+	defer parseStatsMu.Unlock()
+	st := parseStats[key]
+	if st == nil {
+		st = &handlers.ParserStats{Parser: bookmaker}
+		parseStats[key] = st
+	}
+	if st.QuarantineReasons == nil {
+		st.QuarantineReasons = make(map[string]int)
+	}
+	for reason, n := range quarantined {
+		st.Quarantined += n
+		st.QuarantineReasons[reason] += n
+		metrics.OutcomesQuarantined.WithLabelValues(key, reason).Add(float64(n))
+	}
+}
+
+// ParseStats returns the statistics of every parser that reported a run, sorted by name.
+func ParseStats() []handlers.ParserStats {
+	parseStatsMu.Lock()
+	defer parseStatsMu.Unlock()
+	out := make([]handlers.ParserStats, 0, len(parseStats))
+	for _, st := range parseStats {
+		stCopy := *st
+		if st.QuarantineReasons != nil {
+			stCopy.QuarantineReasons = make(map[string]int, len(st.QuarantineReasons))
+			for reason, n := range st.QuarantineReasons {
+				stCopy.QuarantineReasons[reason] = n
+			}
+		}
+		out = append(out, stCopy)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Parser < out[j].Parser })
+	return out
+}
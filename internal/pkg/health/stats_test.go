@@ -0,0 +1,38 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+)
+
+func TestRecordParseReport(t *testing.T) {
+	now := time.Now()
+	RecordParseReport(interfaces.ParseReport{Parser: "StatsTest", Status: interfaces.ParseStatusOK, Finished: now, MatchesAdded: 5})
+	RecordParseReport(interfaces.ParseReport{Parser: "statstest", Status: interfaces.ParseStatusPartial, Finished: now.Add(time.Minute), MatchesAdded: 3, LeaguesFailed: 2})
+	RecordParseReport(interfaces.ParseReport{Parser: "StatsTest", Status: interfaces.ParseStatusFailed, Finished: now.Add(2 * time.Minute)})
+
+	for _, st := range ParseStats() {
+		if st.Parser != "StatsTest" {
+			continue
+		}
+		if st.Runs != 3 || st.PartialRuns != 1 || st.FailedRuns != 1 || st.LeaguesFailed != 2 || st.MatchesAdded != 8 {
+			t.Fatalf("unexpected stats: %+v", st)
+		}
+		if st.LastSuccess == nil || !st.LastSuccess.Equal(now.Add(time.Minute)) {
+			t.Errorf("last success = %v, want the partial run", st.LastSuccess)
+		}
+		if st.LastReport.Status != interfaces.ParseStatusFailed {
+			t.Errorf("last report = %+v", st.LastReport)
+		}
+		if n := testutil.ToFloat64(metrics.MatchesParsed.WithLabelValues("statstest")); n != 8 {
+			t.Errorf("matches_parsed_total = %v, want 8", n)
+		}
+		return
+	}
+	t.Fatal("StatsTest not in ParseStats")
+}
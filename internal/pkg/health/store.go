@@ -1,11 +1,13 @@
 package health
 
 import (
+	"container/list"
 	"log/slog"
 	"sort"
 	"strings"
 	"sync"
 
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
@@ -60,6 +62,9 @@ func mergeMatchInto(byID map[string]*models.Match, match *models.Match) {
 					if existingOutcome, outcomeExists := existingOutcomes[newOutcome.ID]; outcomeExists {
 						existingOutcome.Odds = newOutcome.Odds
 						existingOutcome.UpdatedAt = newOutcome.UpdatedAt
+						if newOutcome.URL != "" {
+							existingOutcome.URL = newOutcome.URL
+						}
 					} else {
 						existingEvent.Outcomes = append(existingEvent.Outcomes, newOutcome)
 					}
@@ -79,6 +84,10 @@ func mergeMatchInto(byID map[string]*models.Match, match *models.Match) {
 		if match.AwayTeam != "" {
 			existing.AwayTeam = match.AwayTeam
 		}
+		if match.URL != "" {
+			existing.URL = match.URL
+		}
+		existing.Stale = existing.Stale && match.Stale
 		// Set bookmaker from events if match.Bookmaker is empty
 		if existing.Bookmaker == "" {
 			existing.Bookmaker = getBookmakerFromEvents(existing.Events)
@@ -100,6 +109,12 @@ func mergeMatchInto(byID map[string]*models.Match, match *models.Match) {
 type InMemoryMatchStore struct {
 	mu      sync.RWMutex
 	matches map[string]*models.Match // key: match_id
+	stale   map[string]bool          // match IDs loaded from the startup snapshot and not refreshed yet
+
+	// Least recently updated match IDs at the back; evicted first above maxMatches (see StartMatchEviction)
+	lru        *list.List
+	recent     map[string]*list.Element
+	maxMatches int // 0 = unlimited
 }
 
 var globalMatchStore *InMemoryMatchStore
@@ -107,34 +122,90 @@ var globalMatchStore *InMemoryMatchStore
 func init() {
 	globalMatchStore = &InMemoryMatchStore{
 		matches: make(map[string]*models.Match),
+		stale:   make(map[string]bool),
+		lru:     list.New(),
+		recent:  make(map[string]*list.Element),
 	}
 	initEsportsStore()
 }
 
 // AddMatch adds or updates a match in the in-memory store
 func AddMatch(match *models.Match) {
+	AddMatches([]*models.Match{match})
+}
+
+// AddMatches adds or updates a batch of matches (e.g. one league or championship) under a single lock
+// of the store, with one eviction pass and size update for the whole batch.
+func AddMatches(matches []*models.Match) {
+	if len(matches) == 0 {
+		return
+	}
+	for _, match := range matches {
+		tagCyberFootball(match)
+		// Parsers convert to UTC; a local zone left here would show up as an offset in the match API
+		match.StartTime = match.StartTime.UTC()
+		if quarantined := validateMatch(match); quarantined != nil {
+			bookmaker := match.Bookmaker
+			if bookmaker == "" {
+				bookmaker = getBookmakerFromEvents(match.Events)
+			}
+			recordQuarantine(bookmaker, quarantined)
+		}
+	}
+	if p := currentPublisher(); p != nil {
+		for _, match := range matches {
+			p.PublishMatch(match)
+		}
+	}
+	if s := currentSink(); s != nil {
+		for _, match := range matches {
+			s.AddMatch(match)
+		}
+		return
+	}
 	if globalMatchStore == nil {
 		return
 	}
 	globalMatchStore.mu.Lock()
 	defer globalMatchStore.mu.Unlock()
 
-	// Detect bookmaker from events
-	bookmakers := make(map[string]bool)
-	for _, ev := range match.Events {
-		if ev.Bookmaker != "" {
-			bookmakers[ev.Bookmaker] = true
+	debug := slog.Default().Enabled(nil, slog.LevelDebug)
+	for _, match := range matches {
+		// A fresh match replaces its snapshot copy instead of merging into it
+		if globalMatchStore.stale[match.ID] {
+			globalMatchStore.remove(match.ID)
+		}
+		mergeMatchInto(globalMatchStore.matches, match)
+		globalMatchStore.touch(match.ID)
+		if debug {
+			slog.Debug("Stored match", "match_id", match.ID, "bookmakers", eventBookmakers(match.Events))
 		}
 	}
-	bookmakerList := make([]string, 0, len(bookmakers))
-	for bk := range bookmakers {
-		bookmakerList = append(bookmakerList, bk)
+	globalMatchStore.evictOverCapacity()
+	globalMatchStore.updateSize()
+	if debug {
+		slog.Debug("Stored matches", "count", len(matches), "total_matches_in_store", len(globalMatchStore.matches))
 	}
+}
 
-	mergeMatchInto(globalMatchStore.matches, match)
-	totalMatches := len(globalMatchStore.matches)
-	if slog.Default().Enabled(nil, slog.LevelDebug) {
-		slog.Debug("Stored match", "match_id", match.ID, "bookmakers", bookmakerList, "total_matches_in_store", totalMatches)
+// eventBookmakers returns the distinct bookmakers of events.
+func eventBookmakers(events []models.Event) []string {
+	seen := make(map[string]bool)
+	var bookmakers []string
+	for _, ev := range events {
+		if ev.Bookmaker != "" && !seen[ev.Bookmaker] {
+			seen[ev.Bookmaker] = true
+			bookmakers = append(bookmakers, ev.Bookmaker)
+		}
+	}
+	return bookmakers
+}
+
+// tagCyberFootball moves football matches of cyber leagues (FIFA, eFootball) to the cyber_football tag,
+// so they never mix with real football and can be priced by the separate cyber pipeline.
+func tagCyberFootball(match *models.Match) {
+	if match.Sport == string(enums.Football) && enums.IsCyberFootballLeague(match.Tournament) {
+		match.Sport = string(enums.CyberFootball)
 	}
 }
 
@@ -155,6 +226,7 @@ func GetMatches() []models.Match {
 		eventsCopy := make([]models.Event, len(match.Events))
 		copy(eventsCopy, match.Events)
 		matchCopy.Events = eventsCopy
+		matchCopy.Stale = globalMatchStore.stale[match.ID]
 		matches = append(matches, matchCopy)
 	}
 
@@ -193,6 +265,7 @@ func GetMatchesByName(nameQuery string) []models.Match {
 			eventsCopy := make([]models.Event, len(match.Events))
 			copy(eventsCopy, match.Events)
 			matchCopy.Events = eventsCopy
+			matchCopy.Stale = globalMatchStore.stale[match.ID]
 			out = append(out, matchCopy)
 		}
 	}
@@ -214,7 +287,7 @@ func ClearMatches() {
 	defer globalMatchStore.mu.Unlock()
 
 	clearedCount := len(globalMatchStore.matches)
-	globalMatchStore.matches = make(map[string]*models.Match)
+	globalMatchStore.reset()
 	slog.Info("Cleared matches from in-memory store", "cleared_count", clearedCount)
 }
 
@@ -302,6 +375,10 @@ func mergeEsportsMatchInto(byID map[string]*models.EsportsMatch, m *models.Espor
 
 // AddEsportsMatch adds or updates an esports match in the in-memory store
 func AddEsportsMatch(match *models.EsportsMatch) {
+	if s := currentSink(); s != nil {
+		s.AddEsportsMatch(match)
+		return
+	}
 	initEsportsStore()
 	globalEsportsStore.mu.Lock()
 	defer globalEsportsStore.mu.Unlock()
@@ -329,3 +406,83 @@ func GetEsportsMatches() []models.EsportsMatch {
 	})
 	return matches
 }
+
+// --- Outright store (долгосрочные ставки на турнир, отдельно от матчей) ---
+
+var (
+	outrightsMu sync.RWMutex
+	outrights   = make(map[string]*models.Outright)
+)
+
+// MergeOutrightLists merges multiple outright lists by ID (later lists override outcome odds).
+func MergeOutrightLists(lists [][]models.Outright) []models.Outright {
+	byID := make(map[string]*models.Outright)
+	for _, list := range lists {
+		for i := range list {
+			mergeOutrightInto(byID, &list[i])
+		}
+	}
+	return sortedOutrights(byID)
+}
+
+func mergeOutrightInto(byID map[string]*models.Outright, o *models.Outright) {
+	existing, ok := byID[o.ID]
+	if !ok {
+		oCopy := *o
+		oCopy.Outcomes = make([]models.OutrightOutcome, len(o.Outcomes))
+		copy(oCopy.Outcomes, o.Outcomes)
+		byID[o.ID] = &oCopy
+		return
+	}
+	idx := make(map[string]int, len(existing.Outcomes))
+	for i := range existing.Outcomes {
+		idx[existing.Outcomes[i].Participant] = i
+	}
+	for _, oc := range o.Outcomes {
+		if i, found := idx[oc.Participant]; found {
+			existing.Outcomes[i].Odds = oc.Odds
+			existing.Outcomes[i].UpdatedAt = oc.UpdatedAt
+		} else {
+			existing.Outcomes = append(existing.Outcomes, oc)
+		}
+	}
+	if !o.CloseTime.IsZero() {
+		existing.CloseTime = o.CloseTime
+	}
+	existing.UpdatedAt = o.UpdatedAt
+}
+
+func sortedOutrights(byID map[string]*models.Outright) []models.Outright {
+	out := make([]models.Outright, 0, len(byID))
+	for _, o := range byID {
+		oCopy := *o
+		oCopy.Outcomes = make([]models.OutrightOutcome, len(o.Outcomes))
+		copy(oCopy.Outcomes, o.Outcomes)
+		out = append(out, oCopy)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].UpdatedAt.After(out[j].UpdatedAt)
+	})
+	return out
+}
+
+// AddOutright adds or updates an outright market in the in-memory store
+func AddOutright(o *models.Outright) {
+	if s := currentSink(); s != nil {
+		s.AddOutright(o)
+		return
+	}
+	outrightsMu.Lock()
+	defer outrightsMu.Unlock()
+	mergeOutrightInto(outrights, o)
+	if slog.Default().Enabled(nil, slog.LevelDebug) {
+		slog.Debug("Stored outright", "id", o.ID, "outcomes", len(o.Outcomes), "total", len(outrights))
+	}
+}
+
+// GetOutrights returns all outright markets from in-memory store
+func GetOutrights() []models.Outright {
+	outrightsMu.RLock()
+	defer outrightsMu.RUnlock()
+	return sortedOutrights(outrights)
+}
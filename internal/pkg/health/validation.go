@@ -0,0 +1,169 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Outcome validation limits: outside them an outcome is a parsing error, not a price.
+const (
+	maxValidOdds  = 1000.0 // odds must be above 1 and at most this
+	minMarketBook = 0.90   // sum of the implied probabilities of a complete market (1 + margin)
+	maxMarketBook = 1.30
+)
+
+// Quarantine reasons (ParserStats.QuarantineReasons, label of vodeneevbet_outcomes_quarantined_total).
+const (
+	quarantineOddsRange        = "odds_range"        // odds <= 1 or > 1000
+	quarantineMissingParameter = "missing_parameter" // total, handicap or score without its line
+	quarantineDuplicate        = "duplicate"         // the same bet key twice in one match
+	quarantineMargin           = "margin"            // a complete market below 90% or above 130%
+)
+
+// validateMatch quarantines the outcomes of match that are obviously broken data before they reach the
+// store, the bus and the calculator: a misparsed price or line would otherwise show up as a value bet or
+// an arbitrage. Duplicates keep the first outcome; a market with an impossible margin loses all its
+// outcomes, as there is no telling which of them is wrong. Events left without outcomes are dropped.
+// Returns the number of quarantined outcomes by reason (nil when the match is clean).
+func validateMatch(match *models.Match) map[string]int {
+	var quarantined map[string]int
+	debug := slog.Default().Enabled(context.Background(), slog.LevelDebug)
+	quarantine := func(reason string, ev models.Event, out models.Outcome) {
+		if quarantined == nil {
+			quarantined = make(map[string]int)
+		}
+		quarantined[reason]++
+		if debug {
+			slog.Debug("Quarantined outcome", "reason", reason, "match_id", match.ID, "bookmaker", ev.Bookmaker,
+				"bet_key", models.OutcomeBetKey(ev, out).String(), "odds", out.Odds)
+		}
+	}
+
+	seen := make(map[string]bool)
+	events := make([]models.Event, 0, len(match.Events))
+	for _, ev := range match.Events {
+		outcomes := make([]models.Outcome, 0, len(ev.Outcomes))
+		for _, out := range ev.Outcomes {
+			if out.Odds <= 1 || out.Odds > maxValidOdds {
+				quarantine(quarantineOddsRange, ev, out)
+				continue
+			}
+			if needsParameter(out.OutcomeType) && strings.TrimSpace(out.Parameter) == "" {
+				quarantine(quarantineMissingParameter, ev, out)
+				continue
+			}
+			key := ev.Bookmaker + "|" + models.OutcomeBetKey(ev, out).String()
+			if seen[key] {
+				quarantine(quarantineDuplicate, ev, out)
+				continue
+			}
+			seen[key] = true
+			outcomes = append(outcomes, out)
+		}
+
+		broken := make(map[int]bool)
+		for _, market := range completeMarkets(ev, outcomes) {
+			var book float64
+			for _, i := range market {
+				book += 1 / outcomes[i].Odds
+			}
+			if book < minMarketBook || book > maxMarketBook {
+				for _, i := range market {
+					broken[i] = true
+				}
+			}
+		}
+		if len(broken) > 0 {
+			kept := outcomes[:0]
+			for i, out := range outcomes {
+				if broken[i] {
+					quarantine(quarantineMargin, ev, out)
+					continue
+				}
+				kept = append(kept, out)
+			}
+			outcomes = kept
+		}
+
+		if len(outcomes) == 0 && len(ev.Outcomes) > 0 {
+			continue
+		}
+		ev.Outcomes = outcomes
+		events = append(events, ev)
+	}
+	if quarantined != nil {
+		match.Events = events
+	}
+	return quarantined
+}
+
+// needsParameter reports whether an outcome type is meaningless without its line: totals, handicaps,
+// exact counts and correct scores.
+func needsParameter(outcomeType string) bool {
+	switch models.StandardOutcomeType(outcomeType) {
+	case models.OutcomeTypeExactCount, models.OutcomeTypeCorrectScore:
+		return true
+	}
+	return strings.Contains(outcomeType, "total") || strings.HasPrefix(outcomeType, "handicap")
+}
+
+// completeMarkets returns the complete markets of one event as indices into outcomes: 1X2 with the draw,
+// both teams to score, over/under of the same line and handicaps of opposite fractional lines. The outcomes
+// of a complete market cover every result, so their implied probabilities sum to 1 plus the margin. Whole
+// lines are skipped as in the calculator's arbitrage markets: some bookmakers price them as European
+// three-way handicaps, whose two sides sum well below 1.
+func completeMarkets(ev models.Event, outcomes []models.Outcome) [][]int {
+	index := make(map[string]int, len(outcomes))
+	awayHandicaps := make(map[string]int) // market without the line + "|" + line -> handicap_*_away outcome
+	for i, out := range outcomes {
+		k := models.OutcomeBetKey(ev, out)
+		index[k.String()] = i
+		if prefix, ok := strings.CutSuffix(k.OutcomeType, "_away"); ok && strings.HasPrefix(prefix, "handicap") {
+			if line, err := strconv.ParseFloat(k.Parameter, 64); err == nil {
+				k.Parameter = ""
+				awayHandicaps[k.WithOutcome(prefix).String()+"|"+lineKey(line)] = i
+			}
+		}
+	}
+
+	var markets [][]int
+	for i, out := range outcomes {
+		k := models.OutcomeBetKey(ev, out)
+		switch t := k.OutcomeType; {
+		case t == string(models.OutcomeTypeHomeWin) && k.Parameter == "":
+			draw, okDraw := index[k.WithOutcome(string(models.OutcomeTypeDraw)).String()]
+			away, okAway := index[k.WithOutcome(string(models.OutcomeTypeAwayWin)).String()]
+			if okDraw && okAway {
+				markets = append(markets, []int{i, draw, away})
+			}
+		case t == string(models.OutcomeTypeBTTSYes) && k.Parameter == "":
+			if no, ok := index[k.WithOutcome(string(models.OutcomeTypeBTTSNo)).String()]; ok {
+				markets = append(markets, []int{i, no})
+			}
+		case strings.HasSuffix(t, "_over"):
+			if under, ok := index[k.WithOutcome(strings.TrimSuffix(t, "_over")+"_under").String()]; ok {
+				markets = append(markets, []int{i, under})
+			}
+		case strings.HasPrefix(t, "handicap") && strings.HasSuffix(t, "_home"):
+			line, err := strconv.ParseFloat(k.Parameter, 64)
+			if err != nil || line == math.Trunc(line) {
+				continue
+			}
+			k.Parameter = ""
+			if away, ok := awayHandicaps[k.WithOutcome(strings.TrimSuffix(t, "_home")).String()+"|"+lineKey(-line)]; ok {
+				markets = append(markets, []int{i, away})
+			}
+		}
+	}
+	return markets
+}
+
+// lineKey formats a handicap line for lookups: "+1.5", "1.5" and "1.50" are the same line.
+func lineKey(line float64) string {
+	return strconv.FormatFloat(line, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestValidateMatch(t *testing.T) {
+	match := &models.Match{ID: "m", Bookmaker: "Fonbet", Events: []models.Event{
+		{EventType: "main_match", Bookmaker: "Fonbet", Outcomes: []models.Outcome{
+			{OutcomeType: "home_win", Odds: 2.1},
+			{OutcomeType: "draw", Odds: 3.4},
+			{OutcomeType: "away_win", Odds: 3.6},
+			{OutcomeType: "home_win", Odds: 2.2},                      // duplicate
+			{OutcomeType: "total_over", Parameter: "2.5", Odds: 1.01}, // over/under at 148%
+			{OutcomeType: "total_under", Parameter: "2.5", Odds: 2.0},
+			{OutcomeType: "total_over", Odds: 1.9}, // no line
+			{OutcomeType: "handicap_home", Parameter: "-1.5", Odds: 3.0},
+			{OutcomeType: "handicap_away", Parameter: "+1.5", Odds: 1.4},
+			{OutcomeType: "handicap_home", Parameter: "0", Odds: 1.0}, // odds
+		}},
+		{EventType: "corners", Bookmaker: "Fonbet", Outcomes: []models.Outcome{
+			{OutcomeType: "total_over", Parameter: "9.5", Odds: 1500},
+		}},
+	}}
+
+	got := validateMatch(match)
+	want := map[string]int{quarantineDuplicate: 1, quarantineMargin: 2, quarantineMissingParameter: 1, quarantineOddsRange: 2}
+	if len(got) != len(want) {
+		t.Fatalf("quarantined = %v, want %v", got, want)
+	}
+	for reason, n := range want {
+		if got[reason] != n {
+			t.Errorf("quarantined[%s] = %d, want %d", reason, got[reason], n)
+		}
+	}
+	if len(match.Events) != 1 {
+		t.Fatalf("event without outcomes left should be dropped, got %d events", len(match.Events))
+	}
+	var kept []string
+	for _, out := range match.Events[0].Outcomes {
+		kept = append(kept, out.OutcomeType+out.Parameter)
+	}
+	if len(kept) != 5 || match.Events[0].Outcomes[0].Odds != 2.1 {
+		t.Errorf("kept outcomes %v, want 1X2 (first home win) and the -1.5/+1.5 handicap", kept)
+	}
+
+	clean := &models.Match{ID: "c", Events: []models.Event{{EventType: "main_match", Outcomes: []models.Outcome{
+		{OutcomeType: "handicap_home", Parameter: "0", Odds: 1.9},
+		{OutcomeType: "handicap_away", Parameter: "0", Odds: 1.9},
+	}}}}
+	if got := validateMatch(clean); got != nil {
+		t.Errorf("clean match quarantined %v", got)
+	}
+
+	// A European -1/+1 handicap leaves out the draw of the three-way market: not a complete market
+	european := &models.Match{ID: "e", Events: []models.Event{{EventType: "main_match", Outcomes: []models.Outcome{
+		{OutcomeType: "handicap_home", Parameter: "-1", Odds: 3.2},
+		{OutcomeType: "handicap_away", Parameter: "+1", Odds: 2.6},
+	}}}}
+	if got := validateMatch(european); got != nil {
+		t.Errorf("European handicap pair (book %.2f) quarantined %v", 1/3.2+1/2.6, got)
+	}
+}
+
+func TestAddMatch_QuarantineStats(t *testing.T) {
+	ClearMatches()
+	defer ClearMatches()
+	AddMatch(&models.Match{ID: "q", Bookmaker: "QuarantineTest", Events: []models.Event{{EventType: "main_match", Outcomes: []models.Outcome{
+		{OutcomeType: "home_win", Odds: 0.5},
+		{OutcomeType: "away_win", Odds: 1.8},
+	}}}})
+
+	for _, st := range ParseStats() {
+		if st.Parser != "QuarantineTest" {
+			continue
+		}
+		if st.Quarantined != 1 || st.QuarantineReasons[quarantineOddsRange] != 1 {
+			t.Errorf("unexpected quarantine stats: %+v", st)
+		}
+		return
+	}
+	t.Fatal("QuarantineTest not in ParseStats")
+}
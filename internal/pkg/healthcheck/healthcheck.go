@@ -0,0 +1,202 @@
+// Package healthcheck serves the health endpoints shared by the services:
+//
+//	GET /healthz              liveness: 200 while the process serves HTTP, no dependency is checked
+//	GET /readyz               readiness: 200 when every required check passes, 503 otherwise
+//	GET /health/dependencies  JSON status of every check, required or not (503 when a required one fails)
+//
+// /ping and /health answer like /healthz for the existing probes.
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Statuses of a check and of a Report.
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDegraded = "degraded" // Report only: an optional check fails, the service is still ready
+)
+
+const (
+	// defaultTimeout bounds one check.
+	defaultTimeout = 3 * time.Second
+	// defaultCacheFor reuses the last report so frequent probes don't hammer the dependencies
+	// (Telegram getMe, the database).
+	defaultCacheFor = 5 * time.Second
+)
+
+// CheckFunc checks one dependency; a nil error means it is healthy.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	required bool
+	fn       CheckFunc
+}
+
+// Result is the status of one check.
+type Result struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`   // ok or fail
+	Required  bool      `json:"required"` // a failure makes the service not ready
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the JSON response of /health/dependencies.
+type Report struct {
+	Service string   `json:"service"`
+	Status  string   `json:"status"` // ok, degraded (optional check failing) or fail (required check failing)
+	Checks  []Result `json:"checks"`
+}
+
+// Ready reports whether every required check passed.
+func (r Report) Ready() bool {
+	return r.Status != StatusFail
+}
+
+// Checker runs the dependency checks of a service.
+type Checker struct {
+	service  string
+	timeout  time.Duration
+	cacheFor time.Duration
+
+	mu     sync.Mutex
+	checks []check
+	last   Report
+	lastAt time.Time
+}
+
+// New returns a Checker without checks: ready until some are added.
+func New(service string) *Checker {
+	return &Checker{service: service, timeout: defaultTimeout, cacheFor: defaultCacheFor}
+}
+
+// Require adds a check the service can't work without: a failure fails /readyz.
+func (c *Checker) Require(name string, fn CheckFunc) {
+	c.add(check{name: name, required: true, fn: fn})
+}
+
+// Observe adds a check that is only reported on /health/dependencies: a failure degrades the service
+// but keeps it ready.
+func (c *Checker) Observe(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn})
+}
+
+func (c *Checker) add(ch check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, ch)
+	c.lastAt = time.Time{}
+}
+
+// Run runs every check in parallel, each bounded by the check timeout. A report younger than the cache
+// period is returned as is.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.lastAt.IsZero() && time.Since(c.lastAt) < c.cacheFor {
+		return c.last
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ch := range c.checks {
+		wg.Add(1)
+		go func(i int, ch check) {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	report := Report{Service: c.service, Status: StatusOK, Checks: results}
+	for _, r := range results {
+		if r.Status == StatusOK {
+			continue
+		}
+		if r.Required {
+			report.Status = StatusFail
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	c.last, c.lastAt = report, time.Now()
+	return report
+}
+
+// runCheck runs one check. A check ignoring its context is abandoned at the timeout.
+func (c *Checker) runCheck(ctx context.Context, ch check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- ch.fn(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+	r := Result{Name: ch.name, Status: StatusOK, Required: ch.required, LatencyMS: time.Since(start).Milliseconds(), CheckedAt: start.UTC()}
+	if err != nil {
+		r.Status, r.Error = StatusFail, err.Error()
+	}
+	return r
+}
+
+// Register serves /healthz, /readyz and /health/dependencies on mux, plus /ping and /health as aliases
+// of /healthz.
+func (c *Checker) Register(mux *http.ServeMux) {
+	for _, path := range []string{"/healthz", "/ping", "/health"} {
+		mux.HandleFunc("GET "+path, handleLive)
+	}
+	mux.HandleFunc("GET /readyz", c.handleReady)
+	mux.HandleFunc("GET /health/dependencies", c.handleDependencies)
+}
+
+// handleLive answers "ok" while the process serves HTTP.
+// GET /healthz
+func handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// handleReady answers "ok", or 503 with the failing required checks, one per line.
+// GET /readyz
+func (c *Checker) handleReady(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if report.Ready() {
+		_, _ = w.Write([]byte("ok\n"))
+		return
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write([]byte("not ready\n"))
+	for _, res := range report.Checks {
+		if res.Required && res.Status != StatusOK {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", res.Name, res.Error)
+		}
+	}
+}
+
+// handleDependencies answers the Report of every check.
+// GET /health/dependencies
+func (c *Checker) handleDependencies(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if !report.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.Error("Failed to encode health report", "error", err)
+	}
+}
@@ -0,0 +1,79 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	c := New("test")
+	if r := c.Run(context.Background()); r.Status != StatusOK || !r.Ready() {
+		t.Errorf("no checks: %+v", r)
+	}
+
+	calls := 0
+	c.Require("db", func(context.Context) error { calls++; return nil })
+	c.Observe("parser", func(context.Context) error { return errors.New("connection refused") })
+	r := c.Run(context.Background())
+	if r.Status != StatusDegraded || !r.Ready() || len(r.Checks) != 2 || r.Checks[1].Error != "connection refused" {
+		t.Errorf("optional failing: %+v", r)
+	}
+	c.Run(context.Background())
+	if calls != 1 {
+		t.Errorf("report not cached: %d calls", calls)
+	}
+
+	c.Require("telegram", func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() })
+	c.timeout = 10 * time.Millisecond
+	r = c.Run(context.Background())
+	if r.Status != StatusFail || r.Ready() || r.Checks[2].Error != "timed out after 10ms" {
+		t.Errorf("required timing out: %+v", r)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	c := New("test")
+	ready := false
+	c.Require("parse_cycle", func(context.Context) error {
+		if !ready {
+			return errors.New("no successful parse cycle yet")
+		}
+		return nil
+	})
+	c.cacheFor = 0
+	mux := http.NewServeMux()
+	c.Register(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	for _, path := range []string{"/healthz", "/ping", "/health"} {
+		if w := get(path); w.Code != http.StatusOK || w.Body.String() != "ok\n" {
+			t.Errorf("%s = %d %q", path, w.Code, w.Body)
+		}
+	}
+	if w := get("/readyz"); w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "parse_cycle: no successful parse cycle yet") {
+		t.Errorf("/readyz = %d %q", w.Code, w.Body)
+	}
+	w := get("/health/dependencies")
+	var r Report
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusServiceUnavailable || r.Service != "test" || r.Status != StatusFail || !r.Checks[0].Required {
+		t.Errorf("/health/dependencies = %d %+v", w.Code, r)
+	}
+
+	ready = true
+	if w := get("/readyz"); w.Code != http.StatusOK {
+		t.Errorf("/readyz = %d %q", w.Code, w.Body)
+	}
+}
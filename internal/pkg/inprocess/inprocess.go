@@ -0,0 +1,97 @@
+// Package inprocess serves the HTTP requests between services running in one process (cmd/allinone)
+// by calling their handlers directly: the calculator and the bot keep their HTTP clients, given a
+// Transport, but nothing goes through the network.
+package inprocess
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Transport serves the requests for Handlers in process and sends the others to Base. A response is
+// returned once the handler returns, so streaming endpoints (SSE) can't be used through it.
+type Transport struct {
+	Base     http.RoundTripper       // nil = http.DefaultTransport
+	Handlers map[string]http.Handler // base URL (scheme://host[:port]) -> handler
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	h, ok := t.Handlers[r.URL.Scheme+"://"+r.URL.Host]
+	if !ok {
+		base := t.Base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base.RoundTrip(r)
+	}
+	// The handler sees a server request: RequestURI and RemoteAddr set, never a nil body
+	in := r.Clone(r.Context())
+	in.RequestURI = r.URL.RequestURI()
+	in.RemoteAddr = "127.0.0.1:0"
+	if in.Body == nil {
+		in.Body = http.NoBody
+	}
+	w := &responseBuffer{header: make(http.Header)}
+	h.ServeHTTP(w, in)
+	return w.response(r), nil
+}
+
+// responseBuffer is the http.ResponseWriter of an in-process request: it keeps the whole response.
+type responseBuffer struct {
+	header http.Header
+	status int // 0 until WriteHeader or the first Write
+	wrote  bool
+	body   bytes.Buffer
+}
+
+func (w *responseBuffer) Header() http.Header { return w.header }
+
+func (w *responseBuffer) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseBuffer) Write(p []byte) (int, error) {
+	if !w.wrote {
+		// As a server does: the content type is sniffed from the first write when the handler set none
+		if w.header.Get("Content-Type") == "" {
+			w.header.Set("Content-Type", http.DetectContentType(p))
+		}
+		w.wrote = true
+	}
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// Flush is a no-op: the response is returned once the handler returns.
+func (w *responseBuffer) Flush() {}
+
+// response returns the buffered response to r.
+func (w *responseBuffer) response(r *http.Request) *http.Response {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := w.header.Clone()
+	header.Set("Content-Length", strconv.Itoa(w.body.Len()))
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(w.body.Bytes())),
+		ContentLength: int64(w.body.Len()),
+		Request:       r,
+	}
+}
+
+// BaseURL returns the base URL of an in-process service: http://<name>.inprocess.
+func BaseURL(name string) string {
+	return "http://" + strings.ToLower(name) + ".inprocess"
+}
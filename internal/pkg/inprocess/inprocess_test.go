@@ -0,0 +1,47 @@
+package inprocess
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "remote")
+	}))
+	defer remote.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, r.URL.Query().Get("q")+":"+string(body))
+	})
+	c := &http.Client{Transport: &Transport{Handlers: map[string]http.Handler{BaseURL("Calculator"): mux}}}
+
+	resp, err := c.Post("http://calculator.inprocess/echo?q=a", "text/plain", strings.NewReader("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || string(body) != "a:b" {
+		t.Errorf("in process = %d %q", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") || resp.ContentLength != 3 {
+		t.Errorf("in process content type %q, length %d", ct, resp.ContentLength)
+	}
+
+	resp, err = c.Get(remote.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "remote" {
+		t.Errorf("other host = %q", body)
+	}
+}
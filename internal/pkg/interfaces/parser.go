@@ -33,15 +33,89 @@ type IncrementalParser interface {
 	// TriggerNewCycle signals the parser to start a new parsing cycle
 	// This is non-blocking - it just triggers the start, doesn't wait for completion
 	TriggerNewCycle() error
+
+	ControllableParser
+}
+
+// SportParser is implemented by parsers that can poll a single sport, so parser.sports can give
+// each sport its own interval. Other parsers are polled as a whole with ParseOnce.
+type SportParser interface {
+	Parser
+
+	// ParseSport runs one parsing pass for sport only (enums.Sport value, e.g. "football", "cs")
+	ParseSport(ctx context.Context, sport string) error
+}
+
+// ReportingParser is implemented by parsers that report failed leagues separately from a failure
+// of the whole run, so "bookmaker down" and "one league 404" look different on /stats.
+type ReportingParser interface {
+	Parser
+
+	// ParseOnceWithReport runs one parsing pass and describes it; the error is returned only when
+	// the whole run failed (report.Status == ParseStatusFailed)
+	ParseOnceWithReport(ctx context.Context) (ParseReport, error)
+}
+
+// ParseReport.Status values
+const (
+	ParseStatusOK      = "ok"      // no errors
+	ParseStatusPartial = "partial" // some leagues failed, the rest was parsed
+	ParseStatusFailed  = "failed"  // nothing parsed: bookmaker down, blocked, league list unavailable
+)
+
+// ParseReport describes one parsing run (ParseOnce or an incremental cycle)
+type ParseReport struct {
+	Parser        string    `json:"parser"`
+	Status        string    `json:"status"`
+	Started       time.Time `json:"started"`
+	Finished      time.Time `json:"finished"`
+	Duration      string    `json:"duration"`
+	MatchesAdded  int       `json:"matches_added"`
+	LeaguesFailed int       `json:"leagues_failed"`
+	Errors        []string  `json:"errors,omitempty"`
+
+	// Counted during the run (see internal/pkg/cyclestats)
+	LeaguesFetched int    `json:"leagues_fetched"` // leagues the run went on to fetch (after max_leagues), failed ones included
+	HTTPRequests   int    `json:"http_requests"`   // requests to the bookmaker
+	HTTPErrors     int    `json:"http_errors"`     // of them network errors and 403, 429, 5xx responses
+	AvgLatency     string `json:"avg_latency,omitempty"`
+	Proxy          string `json:"proxy,omitempty"` // proxy host of most requests, "direct" without one
+}
+
+// ControllableParser lets the orchestrator and health endpoints introspect and pause parsers uniformly.
+// Implemented by every IncrementalParser and by the orchestrator's RemoteParser.
+type ControllableParser interface {
+	// LastCycle returns the summary of the last finished parsing cycle (zero value if none yet)
+	LastCycle() CycleSummary
+
+	// Pause stops starting new cycles; the cycle in progress finishes normally
+	Pause() error
+
+	// Resume allows new cycles again and triggers one immediately
+	Resume() error
+
+	// IsPaused reports whether the parser is paused
+	IsPaused() bool
+}
+
+// CycleSummary describes one finished parsing cycle
+type CycleSummary struct {
+	CycleID   int64     `json:"cycle_id"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Duration  string    `json:"duration"`
+	Matches   int       `json:"matches"`
+	Errors    int       `json:"errors"`
+	LastError string    `json:"last_error,omitempty"`
 }
 
 // EventFetcher interface for fetching events from bookmaker APIs
 type EventFetcher interface {
 	// FetchEvents fetches events for a specific sport
-	FetchEvents(sport string) ([]byte, error)
+	FetchEvents(ctx context.Context, sport string) ([]byte, error)
 	
 	// FetchEventFactors fetches factors for a specific event
-	FetchEventFactors(eventID int64) ([]byte, error)
+	FetchEventFactors(ctx context.Context, eventID int64) ([]byte, error)
 }
 
 // OddsParser interface for parsing odds from bookmaker data
@@ -71,5 +145,5 @@ type EventProcessor interface {
 	ProcessEvents(events []interface{}) error
 	
 	// ProcessSportEvents processes events for a specific sport
-	ProcessSportEvents(sport string) error
+	ProcessSportEvents(ctx context.Context, sport string) error
 }
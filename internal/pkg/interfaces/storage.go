@@ -5,7 +5,10 @@ import (
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
 )
 
-// Storage interface for working with match data storage
+// Storage interface for working with match data storage.
+// There is no implementation in this tree: the YDB clients are gone, matches live in the in-memory
+// match store of the parser / bookmaker-service (health.AddMatch, /matches) and parsers run with a nil
+// Storage. Calculator data is in Postgres behind the storage package interfaces.
 type Storage interface {
 	// StoreMatch stores a complete match with all its events and outcomes
 	StoreMatch(ctx context.Context, match *models.Match) error
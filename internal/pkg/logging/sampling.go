@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// LoggerKey — атрибут с именем модуля лога (slog.Default().With(logging.LoggerKey, "fonbet")).
+// Без него модуль — префикс сообщения до ":" в нижнем регистре: "Marathonbet: match added" → "marathonbet".
+const LoggerKey = "logger"
+
+// anyModule — настройки сэмплирования для модулей без своих
+const anyModule = "*"
+
+// maxModulePrefix — префикс длиннее уже не имя модуля, а часть текста
+const maxModulePrefix = 32
+
+const defaultSamplingInterval = time.Minute
+
+// ModuleHandler фильтрует записи по уровню модуля (logging.modules) и сэмплирует частые сообщения
+// (logging.sampling) до передачи в next. Уровни next должны пропускать всё: фильтрует этот handler.
+type ModuleHandler struct {
+	next     slog.Handler
+	level    slog.Level            // уровень модулей без своего
+	modules  map[string]slog.Level // ключи в нижнем регистре
+	samplers map[string]*sampler   // общие для всех копий из WithAttrs/WithGroup
+	minLevel slog.Level            // самый низкий из уровней: ниже не включено ни для одного модуля
+	module   string                // из атрибута LoggerKey ("" — по сообщению)
+}
+
+// NewModuleHandler оборачивает next уровнями по модулям и сэмплированием из cfg.
+func NewModuleHandler(next slog.Handler, cfg *config.LoggingConfig) *ModuleHandler {
+	h := &ModuleHandler{
+		next:     next,
+		level:    parseLevel(cfg.Level),
+		modules:  make(map[string]slog.Level, len(cfg.Modules)),
+		samplers: make(map[string]*sampler, len(cfg.Sampling)),
+	}
+	h.minLevel = h.level
+	for name, level := range cfg.Modules {
+		l := parseLevel(level)
+		h.modules[strings.ToLower(name)] = l
+		if l < h.minLevel {
+			h.minLevel = l
+		}
+	}
+	for name, s := range cfg.Sampling {
+		if s.Interval <= 0 {
+			s.Interval = defaultSamplingInterval
+		}
+		h.samplers[strings.ToLower(name)] = &sampler{cfg: s, counts: make(map[sampleKey]int)}
+	}
+	return h
+}
+
+// parseLevel разбирает DEBUG / INFO / WARN / ERROR (по умолчанию INFO)
+func parseLevel(level string) slog.Level {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return slog.LevelDebug
+	case "WARN", "WARNING":
+		return slog.LevelWarn
+	case "ERROR":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func (h *ModuleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	lowest := h.minLevel
+	if h.module != "" {
+		lowest = h.moduleLevel(h.module)
+	}
+	return level >= lowest && h.next.Enabled(ctx, level)
+}
+
+func (h *ModuleHandler) Handle(ctx context.Context, record slog.Record) error {
+	module := h.module
+	if module == "" {
+		module = moduleOf(record)
+	}
+	if record.Level < h.moduleLevel(module) {
+		return nil
+	}
+	s, ok := h.samplers[module]
+	if !ok {
+		s = h.samplers[anyModule]
+	}
+	if s != nil && !s.allow(record.Level, record.Message, record.Time) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *ModuleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	for _, a := range attrs {
+		if a.Key == LoggerKey {
+			c.module = strings.ToLower(a.Value.String())
+		}
+	}
+	c.next = h.next.WithAttrs(attrs)
+	return &c
+}
+
+func (h *ModuleHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.next = h.next.WithGroup(name)
+	return &c
+}
+
+func (h *ModuleHandler) moduleLevel(module string) slog.Level {
+	if l, ok := h.modules[module]; ok {
+		return l
+	}
+	return h.level
+}
+
+// moduleOf возвращает модуль записи: атрибут LoggerKey или префикс сообщения до ":"
+func moduleOf(record slog.Record) string {
+	var module string
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == LoggerKey {
+			module = strings.ToLower(a.Value.String())
+			return false
+		}
+		return true
+	})
+	if module != "" {
+		return module
+	}
+	if i := strings.IndexByte(record.Message, ':'); i > 0 && i <= maxModulePrefix {
+		return strings.ToLower(record.Message[:i])
+	}
+	return ""
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+// sampler считает одинаковые сообщения (уровень + текст) модуля в текущем интервале
+type sampler struct {
+	cfg config.LogSamplingConfig
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[sampleKey]int
+}
+
+// allow сообщает, пишется ли очередное сообщение: первые First в интервале, затем каждое Thereafter-е
+func (s *sampler) allow(level slog.Level, message string, now time.Time) bool {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.start) >= s.cfg.Interval {
+		s.start = now
+		clear(s.counts)
+	}
+	key := sampleKey{level, message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.cfg.First {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0
+}
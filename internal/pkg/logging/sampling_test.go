@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func newTestLogger(cfg *config.LoggingConfig) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	text := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(NewModuleHandler(text, cfg)), &buf
+}
+
+func TestModuleHandler_Levels(t *testing.T) {
+	logger, buf := newTestLogger(&config.LoggingConfig{
+		Level:   "INFO",
+		Modules: map[string]string{"marathonbet": "WARN", "fonbet": "DEBUG"},
+	})
+
+	logger.Info("Marathonbet: match added")
+	logger.Warn("Marathonbet: league failed")
+	logger.Debug("Fonbet: event parsed")
+	logger.Debug("Calculator: iteration")
+	logger.Info("Calculator: iteration done")
+	logger.With(LoggerKey, "marathonbet").Info("no prefix")
+
+	out := buf.String()
+	for _, want := range []string{"league failed", "event parsed", "iteration done"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"match added", "msg=\"Calculator: iteration\"", "no prefix"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("unexpected %q in:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestModuleHandler_Sampling(t *testing.T) {
+	logger, buf := newTestLogger(&config.LoggingConfig{
+		Sampling: map[string]config.LogSamplingConfig{
+			"marathonbet": {First: 2, Thereafter: 5, Interval: time.Hour},
+		},
+	})
+
+	for i := 0; i < 12; i++ {
+		logger.Info("Marathonbet: match added", "i", i)
+		logger.Info("Leon: match added", "i", i)
+	}
+	logger.Warn("Marathonbet: match added")
+
+	out := buf.String()
+	// First 2, then every 5th: records 1, 2, 7, 12
+	if got := strings.Count(out, "Marathonbet: match added\" i="); got != 4 {
+		t.Errorf("marathonbet info records = %d, want 4:\n%s", got, out)
+	}
+	if got := strings.Count(out, "Leon: match added"); got != 12 {
+		t.Errorf("leon records = %d, want 12 (no sampling)", got)
+	}
+	if !strings.Contains(out, "level=WARN msg=\"Marathonbet: match added\"") {
+		t.Errorf("warn record sampled together with info ones:\n%s", out)
+	}
+}
+
+func TestSampler_Interval(t *testing.T) {
+	s := &sampler{cfg: config.LogSamplingConfig{First: 1, Interval: time.Minute}, counts: make(map[sampleKey]int)}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !s.allow(slog.LevelInfo, "m", now) {
+		t.Fatal("first record dropped")
+	}
+	if s.allow(slog.LevelInfo, "m", now.Add(time.Second)) {
+		t.Error("second record in the interval passed with thereafter 0")
+	}
+	if !s.allow(slog.LevelInfo, "m", now.Add(time.Minute)) {
+		t.Error("first record of the next interval dropped")
+	}
+}
@@ -16,7 +16,7 @@ func SetupLogger(cfg *config.LoggingConfig, serviceName string) (*slog.Logger, e
 		GroupName:     cfg.GroupName,
 		GroupID:       cfg.GroupID,
 		FolderID:      cfg.FolderID,
-		Level:         cfg.Level,
+		Level:         "DEBUG", // уровни (cfg.Level и cfg.Modules) проверяет ModuleHandler
 		BatchSize:     cfg.BatchSize,
 		FlushInterval: cfg.FlushInterval,
 		ProjectLabel:  cfg.ProjectLabel,
@@ -26,15 +26,15 @@ func SetupLogger(cfg *config.LoggingConfig, serviceName string) (*slog.Logger, e
 
 	// НЕ устанавливаем ServiceLabel здесь - пусть NewYandexLoggingHandler сначала проверит
 	// переменные окружения, а потом использует serviceName как fallback
-	return setupLoggerWithConfig(loggingConfig, serviceName)
+	return setupLoggerWithConfig(loggingConfig, cfg, serviceName)
 }
 
-func setupLoggerWithConfig(config YandexLoggingConfig, serviceName string) (*slog.Logger, error) {
+func setupLoggerWithConfig(config YandexLoggingConfig, cfg *config.LoggingConfig, serviceName string) (*slog.Logger, error) {
 	var handlers []slog.Handler
 
 	// Всегда добавляем handler для stdout/stderr
 	textHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level: slog.LevelDebug,
 	})
 	handlers = append(handlers, textHandler)
 
@@ -58,7 +58,8 @@ func setupLoggerWithConfig(config YandexLoggingConfig, serviceName string) (*slo
 		handlers: handlers,
 	}
 
-	logger := slog.New(multiHandler)
+	// Уровни по модулям и сэмплирование частых сообщений — перед всеми handlers
+	logger := slog.New(NewModuleHandler(multiHandler, cfg))
 	logger = logger.With("service", serviceName)
 
 	// Устанавливаем как глобальный logger
@@ -0,0 +1,113 @@
+// Package metrics holds the Prometheus metrics of the services, served on /metrics in the text format.
+//
+// The metrics are registered in the default registry when the package is loaded, so every service exposes
+// the ones it updates (the parser and bookmaker-service the parsing metrics, the calculator the value and alert
+// metrics, the calculator and telegram-bot the Telegram delivery metrics) next to the Go runtime metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "vodeneevbet"
+
+var (
+	// ParseCycleDuration is the duration of parsing runs and incremental cycles by parser and status
+	// (ok, partial, failed).
+	ParseCycleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "parse_cycle_duration_seconds",
+		Help:      "Duration of parsing cycles by parser and status.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{"parser", "status"})
+
+	// MatchesParsed counts the matches parsed by each parser.
+	MatchesParsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "matches_parsed_total",
+		Help:      "Matches parsed by parser.",
+	}, []string{"parser"})
+
+	// BookmakerHTTPErrors counts failed requests to bookmaker hosts: network errors ("error") and
+	// 403, 429 and 5xx responses (the status code).
+	BookmakerHTTPErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bookmaker_http_errors_total",
+		Help:      "Failed requests to bookmaker hosts by host and reason.",
+	}, []string{"endpoint", "reason"})
+
+	// ProxyFailures counts failed requests through the proxies of a pool.
+	ProxyFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "proxy_failures_total",
+		Help:      "Failed requests through proxies by pool.",
+	}, []string{"pool"})
+
+	// ValueBetsDetected counts bets that rose above the alert threshold.
+	ValueBetsDetected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "value_bets_detected_total",
+		Help:      "Bets that rose above the alert threshold, by sport.",
+	}, []string{"sport"})
+
+	// AlertsSent counts the delivered Telegram alerts by alert type.
+	AlertsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_sent_total",
+		Help:      "Telegram alerts delivered by alert type.",
+	}, []string{"type"})
+
+	// MatchStoreMatches is the number of matches in the in-memory match store of the parser or bookmaker-service.
+	MatchStoreMatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "match_store_matches",
+		Help:      "Matches in the in-memory match store.",
+	})
+
+	// MatchStoreEvictions counts matches evicted from the match store by reason: "expired" (past the start
+	// time plus health.match_store.retention) or "capacity" (least recently updated above max_matches).
+	MatchStoreEvictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "match_store_evictions_total",
+		Help:      "Matches evicted from the in-memory match store by reason.",
+	}, []string{"reason"})
+
+	// OutcomesQuarantined counts the outcomes dropped by the validation of parsed matches by parser and
+	// reason: "odds_range", "missing_parameter", "duplicate", "margin".
+	OutcomesQuarantined = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outcomes_quarantined_total",
+		Help:      "Parsed outcomes dropped as broken data by parser and reason.",
+	}, []string{"parser", "reason"})
+
+	// CacheRequests counts lookups of the calculator's Redis cache by cache (value_bets_top,
+	// line_movements_top, matches) and result (hit, miss, error).
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Redis cache lookups of the calculator by cache and result.",
+	}, []string{"cache", "result"})
+
+	// TelegramMessagesSent counts the messages delivered to Telegram.
+	TelegramMessagesSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_messages_sent_total",
+		Help:      "Messages delivered to Telegram.",
+	})
+
+	// TelegramSendFailures counts the messages given up after the retries.
+	TelegramSendFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_send_failures_total",
+		Help:      "Messages that could not be delivered to Telegram.",
+	})
+)
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,274 @@
+// Package mirrors is the registry of resolved bookmaker mirror URLs shared by mirror-based
+// parsers (pinnacle888, xbet1).
+//
+// A resolved URL is persisted to <dir>/<name>.json, so a restarted service starts from the last
+// known mirror instead of resolving from scratch (which may need headless Chrome). Every
+// resolution, failure and manual change is kept in a short history. A manual override (set via
+// POST /mirrors/override) wins over resolution until it is cleared.
+package mirrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+const (
+	DefaultMaxAge      = 24 * time.Hour
+	DefaultHistorySize = 20
+)
+
+// History event types.
+const (
+	EventResolved        = "resolved"
+	EventFailed          = "failed"
+	EventOverride        = "override"
+	EventOverrideCleared = "override_cleared"
+	EventInvalidated     = "invalidated"
+)
+
+// Resolution is one history record of a mirror.
+type Resolution struct {
+	At       time.Time `json:"at"`
+	Event    string    `json:"event"`
+	URL      string    `json:"url,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+}
+
+// Entry is the registry state of one parser's mirror.
+type Entry struct {
+	Name        string            `json:"name"`
+	MirrorURL   string            `json:"mirror_url"`
+	ResolvedURL string            `json:"resolved_url,omitempty"`
+	Attrs       map[string]string `json:"attrs,omitempty"` // parser-specific values derived with the URL (e.g. odds_domain)
+	ResolvedAt  time.Time         `json:"resolved_at,omitempty"`
+	Override    string            `json:"override,omitempty"`
+	History     []Resolution      `json:"history,omitempty"`
+}
+
+var (
+	mu          sync.Mutex
+	dir         string
+	maxAge      = DefaultMaxAge
+	historySize = DefaultHistorySize
+	entries     = map[string]*Entry{}
+)
+
+// nameRe matches the mirror names an entry file <dir>/<name>.json may be named after.
+var nameRe = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ValidName reports whether name is a mirror name (letters, digits, '_' and '-'; case-insensitive),
+// not a path.
+func ValidName(name string) bool {
+	return nameRe.MatchString(normalizeName(name))
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Configure sets the registry options (parser.mirror_registry). Empty dir keeps the registry in memory only.
+func Configure(cfg config.MirrorRegistryConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = cfg.Dir
+	maxAge = DefaultMaxAge
+	if cfg.MaxAge > 0 {
+		maxAge = cfg.MaxAge
+	}
+	historySize = DefaultHistorySize
+	if cfg.HistorySize > 0 {
+		historySize = cfg.HistorySize
+	}
+	entries = map[string]*Entry{}
+	slog.Info("Mirror registry configured", "dir", dir, "max_age", maxAge, "history_size", historySize)
+}
+
+// Cached returns the persisted URL resolved for mirrorURL if it is younger than max_age.
+// Entries saved for another mirror_url are ignored.
+func Cached(name, mirrorURL string) (resolvedURL string, attrs map[string]string, resolvedAt time.Time, ok bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	e := load(name)
+	if e.ResolvedURL == "" || e.MirrorURL != mirrorURL || time.Since(e.ResolvedAt) > maxAge {
+		return "", nil, time.Time{}, false
+	}
+	return e.ResolvedURL, copyAttrs(e.Attrs), e.ResolvedAt, true
+}
+
+// Override returns the manual override URL of a mirror ("" if none).
+func Override(name string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	return load(name).Override
+}
+
+// Resolved records a successful resolution and persists it.
+func Resolved(name, mirrorURL, resolvedURL string, attrs map[string]string, took time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	e := load(name)
+	e.MirrorURL = mirrorURL
+	e.ResolvedURL = resolvedURL
+	e.Attrs = copyAttrs(attrs)
+	e.ResolvedAt = time.Now()
+	record(e, Resolution{Event: EventResolved, URL: resolvedURL, Duration: took.Round(time.Millisecond).String()})
+}
+
+// Failed records a failed resolution (the persisted URL is kept).
+func Failed(name, mirrorURL string, err error, took time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	e := load(name)
+	e.MirrorURL = mirrorURL
+	r := Resolution{Event: EventFailed, Duration: took.Round(time.Millisecond).String()}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	record(e, r)
+}
+
+// Invalidate drops the resolved URL (it stopped working), so the next start resolves again.
+func Invalidate(name, reason string) {
+	mu.Lock()
+	defer mu.Unlock()
+	e := load(name)
+	if e.ResolvedURL == "" {
+		return
+	}
+	old := e.ResolvedURL
+	e.ResolvedURL = ""
+	e.Attrs = nil
+	e.ResolvedAt = time.Time{}
+	record(e, Resolution{Event: EventInvalidated, URL: old, Error: reason})
+}
+
+// SetOverride sets a manual URL for a mirror; empty rawURL clears the override.
+func SetOverride(name, rawURL string) error {
+	name = normalizeName(name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("invalid name %q: want letters, digits, '_' or '-'", name)
+	}
+	rawURL = strings.TrimRight(strings.TrimSpace(rawURL), "/")
+	if rawURL != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url %q: want http(s)://host", rawURL)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	e := load(name)
+	if rawURL == "" {
+		if e.Override == "" {
+			return nil
+		}
+		record(e, Resolution{Event: EventOverrideCleared, URL: e.Override})
+		e.Override = ""
+		slog.Info("Mirror override cleared", "name", name)
+		return nil
+	}
+	e.Override = rawURL
+	record(e, Resolution{Event: EventOverride, URL: rawURL})
+	slog.Info("Mirror override set", "name", name, "url", rawURL)
+	return nil
+}
+
+// List returns all entries known to this process, sorted by name.
+func List() []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		c := *e
+		c.Attrs = copyAttrs(e.Attrs)
+		c.History = append([]Resolution(nil), e.History...)
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// load returns the entry of name, reading it from disk on first use. Caller holds mu.
+func load(name string) *Entry {
+	name = normalizeName(name)
+	if e, ok := entries[name]; ok {
+		return e
+	}
+	e := &Entry{Name: name}
+	if dir != "" && nameRe.MatchString(name) {
+		data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if err == nil {
+			if err := json.Unmarshal(data, e); err != nil {
+				slog.Warn("Mirror registry: ignoring unreadable entry", "name", name, "error", err)
+				e = &Entry{Name: name}
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Mirror registry: failed to read entry", "name", name, "error", err)
+		}
+	}
+	e.Name = name
+	entries[name] = e
+	return e
+}
+
+// record appends r to the history (trimmed to history_size) and persists the entry. Caller holds mu.
+func record(e *Entry, r Resolution) {
+	r.At = time.Now()
+	e.History = append(e.History, r)
+	if len(e.History) > historySize {
+		e.History = e.History[len(e.History)-historySize:]
+	}
+	if err := save(e); err != nil {
+		slog.Warn("Mirror registry: failed to persist entry", "name", e.Name, "error", err)
+	}
+}
+
+// save writes the entry atomically (temp file + rename). Caller holds mu.
+func save(e *Entry) error {
+	if dir == "" {
+		return nil
+	}
+	if !nameRe.MatchString(e.Name) {
+		return fmt.Errorf("invalid name %q", e.Name)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	path := filepath.Join(dir, e.Name+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write entry: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func copyAttrs(attrs map[string]string) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package mirrors
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func TestRegistry_PersistsAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	Configure(config.MirrorRegistryConfig{Dir: dir})
+
+	Resolved("pinnacle888", "https://mirror.example", "https://1.2.3.4", map[string]string{"odds_domain": "odds.example"}, time.Second)
+	if _, err := os.Stat(filepath.Join(dir, "pinnacle888.json")); err != nil {
+		t.Fatalf("entry not persisted: %v", err)
+	}
+
+	// New process: in-memory state is gone, the entry is read from disk
+	Configure(config.MirrorRegistryConfig{Dir: dir})
+	url, attrs, resolvedAt, ok := Cached("pinnacle888", "https://mirror.example")
+	if !ok || url != "https://1.2.3.4" || attrs["odds_domain"] != "odds.example" || resolvedAt.IsZero() {
+		t.Fatalf("Cached = %q %v %v %v", url, attrs, resolvedAt, ok)
+	}
+
+	// Entry saved for another mirror_url is ignored
+	if _, _, _, ok := Cached("pinnacle888", "https://other.example"); ok {
+		t.Fatal("Cached returned entry of another mirror_url")
+	}
+}
+
+func TestRegistry_MaxAge(t *testing.T) {
+	Configure(config.MirrorRegistryConfig{MaxAge: time.Hour})
+	Resolved("xbet1", "https://mirror.example", "https://1xbet.example", nil, 0)
+	entries["xbet1"].ResolvedAt = time.Now().Add(-2 * time.Hour)
+
+	if _, _, _, ok := Cached("xbet1", "https://mirror.example"); ok {
+		t.Fatal("expired entry returned")
+	}
+}
+
+func TestRegistry_Invalidate(t *testing.T) {
+	Configure(config.MirrorRegistryConfig{Dir: t.TempDir()})
+	Resolved("xbet1", "https://mirror.example", "https://1xbet.example", nil, 0)
+	Invalidate("xbet1", "broken")
+
+	if _, _, _, ok := Cached("xbet1", "https://mirror.example"); ok {
+		t.Fatal("invalidated entry returned")
+	}
+	h := List()[0].History
+	if last := h[len(h)-1]; last.Event != EventInvalidated || last.URL != "https://1xbet.example" || last.Error != "broken" {
+		t.Fatalf("last history record = %+v", last)
+	}
+}
+
+func TestRegistry_Override(t *testing.T) {
+	dir := t.TempDir()
+	Configure(config.MirrorRegistryConfig{Dir: dir})
+
+	if err := SetOverride("xbet1", "ftp://bad"); err == nil {
+		t.Fatal("expected error for non-http url")
+	}
+	if err := SetOverride("", "https://1xbet.example"); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	if err := SetOverride("XBet1", "https://1xbet.example/"); err != nil {
+		t.Fatal(err)
+	}
+
+	Configure(config.MirrorRegistryConfig{Dir: dir})
+	if got := Override("xbet1"); got != "https://1xbet.example" {
+		t.Fatalf("Override after restart = %q", got)
+	}
+
+	if err := SetOverride("xbet1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := Override("xbet1"); got != "" {
+		t.Fatalf("Override after clear = %q", got)
+	}
+	h := List()[0].History
+	if len(h) != 2 || h[0].Event != EventOverride || h[1].Event != EventOverrideCleared {
+		t.Fatalf("history = %+v", h)
+	}
+}
+
+func TestRegistry_RejectsPathNames(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "mirrors")
+	Configure(config.MirrorRegistryConfig{Dir: dir})
+
+	for _, name := range []string{"../evil", "a/b", "..", "x.json", "pinnacle 888"} {
+		if ValidName(name) {
+			t.Errorf("ValidName(%q) = true", name)
+		}
+		if err := SetOverride(name, "https://evil.example"); err == nil {
+			t.Errorf("SetOverride(%q): expected an error", name)
+		}
+		Resolved(name, "https://mirror.example", "https://evil.example", nil, 0)
+	}
+	if _, err := os.Stat(filepath.Join(root, "evil.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("entry written outside the registry dir: %v", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("files written for invalid names: %v", files)
+	}
+	if !ValidName("Pinnacle888") || !ValidName("xbet_1-a") {
+		t.Error("parser names must be valid")
+	}
+}
+
+func TestRegistry_HistoryTrimmed(t *testing.T) {
+	Configure(config.MirrorRegistryConfig{HistorySize: 3})
+	for i := 0; i < 5; i++ {
+		Failed("pinnacle888", "https://mirror.example", errors.New("timeout"), time.Second)
+	}
+	Resolved("pinnacle888", "https://mirror.example", "https://ok.example", nil, time.Second)
+
+	list := List()
+	if len(list) != 1 {
+		t.Fatalf("List len = %d", len(list))
+	}
+	h := list[0].History
+	if len(h) != 3 || h[2].Event != EventResolved || h[0].Error != "timeout" {
+		t.Fatalf("history = %+v", h)
+	}
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sources of an alias (NameAlias.Source).
+const (
+	AliasSourceSeed = "seed" // embedded JSON or the configured file
+	AliasSourceAPI  = "api"  // added through the calculator API (stored in Postgres)
+)
+
+// NameAlias maps a team or tournament name used by some bookmaker to the canonical name.
+type NameAlias struct {
+	Alias     string `json:"alias"`
+	Canonical string `json:"canonical"`
+	Source    string `json:"source"`
+}
+
+// AliasKey is the lookup key of a name in the alias dictionaries: lower case, hyphens as spaces,
+// without apostrophes and dots, "ё" as "е", single spaces.
+func AliasKey(name string) string {
+	s := strings.ToLower(strings.TrimSpace(name))
+	s = strings.NewReplacer("-", " ", "'", "", "’", "", ".", "", "ё", "е").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// aliasDictionary maps names to canonical names: the seed (embedded JSON merged with an optional file,
+// {"<section>": {"<canonical>": ["<alias>", ...]}}) and the aliases added at runtime, which win over the seed.
+type aliasDictionary struct {
+	section  string
+	embedded []byte
+	once     sync.Once
+	mu       sync.RWMutex
+	seed     map[string]NameAlias // alias key -> alias
+	added    map[string]NameAlias
+}
+
+func newAliasDictionary(section string, embedded []byte) *aliasDictionary {
+	return &aliasDictionary{section: section, embedded: embedded, added: map[string]NameAlias{}}
+}
+
+func (d *aliasDictionary) parse(data []byte) (map[string]NameAlias, error) {
+	var f map[string]map[string][]string
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	out := make(map[string]NameAlias)
+	for canonical, aliases := range f[d.section] {
+		canonical = strings.TrimSpace(canonical)
+		if canonical == "" {
+			continue
+		}
+		// The canonical name maps to itself, so its spellings ("england - premier league") resolve too
+		out[AliasKey(canonical)] = NameAlias{Alias: canonical, Canonical: canonical, Source: AliasSourceSeed}
+		for _, alias := range aliases {
+			if key := AliasKey(alias); key != "" && key != AliasKey(canonical) {
+				out[key] = NameAlias{Alias: strings.TrimSpace(alias), Canonical: canonical, Source: AliasSourceSeed}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (d *aliasDictionary) loadSeed() {
+	d.once.Do(func() {
+		seed, err := d.parse(d.embedded)
+		if err != nil {
+			panic(fmt.Sprintf("models: invalid embedded %s aliases: %v", d.section, err))
+		}
+		d.mu.Lock()
+		d.seed = seed
+		d.mu.Unlock()
+	})
+}
+
+// configure merges the aliases of file over the embedded seed and returns the number of seed entries
+// (aliases and canonical names).
+func (d *aliasDictionary) configure(file string) (int, error) {
+	d.loadSeed()
+	seed, err := d.parse(d.embedded)
+	if err != nil {
+		return 0, err
+	}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("read %s aliases: %w", d.section, err)
+		}
+		extra, err := d.parse(data)
+		if err != nil {
+			return 0, fmt.Errorf("parse %s aliases %s: %w", d.section, file, err)
+		}
+		for k, a := range extra {
+			seed[k] = a
+		}
+	}
+	d.mu.Lock()
+	d.seed = seed
+	d.mu.Unlock()
+	return len(seed), nil
+}
+
+func (d *aliasDictionary) add(alias, canonical string) error {
+	key := AliasKey(alias)
+	canonical = strings.TrimSpace(canonical)
+	if key == "" || canonical == "" {
+		return fmt.Errorf("alias and canonical are required")
+	}
+	if key == AliasKey(canonical) {
+		return fmt.Errorf("alias %q is the canonical name itself", alias)
+	}
+	d.loadSeed()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.added[key] = NameAlias{Alias: strings.TrimSpace(alias), Canonical: canonical, Source: AliasSourceAPI}
+	return nil
+}
+
+func (d *aliasDictionary) remove(alias string) bool {
+	key := AliasKey(alias)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.added[key]
+	delete(d.added, key)
+	return ok
+}
+
+func (d *aliasDictionary) canonical(name string) string {
+	key := AliasKey(name)
+	if key == "" {
+		return name
+	}
+	d.loadSeed()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if a, ok := d.added[key]; ok {
+		return a.Canonical
+	}
+	if a, ok := d.seed[key]; ok {
+		return a.Canonical
+	}
+	return name
+}
+
+func (d *aliasDictionary) list() []NameAlias {
+	d.loadSeed()
+	d.mu.RLock()
+	out := make([]NameAlias, 0, len(d.seed)+len(d.added))
+	for k, a := range d.seed {
+		if _, ok := d.added[k]; !ok && a.Alias != a.Canonical {
+			out = append(out, a)
+		}
+	}
+	for _, a := range d.added {
+		out = append(out, a)
+	}
+	d.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Canonical != out[j].Canonical {
+			return out[i].Canonical < out[j].Canonical
+		}
+		return out[i].Alias < out[j].Alias
+	})
+	return out
+}
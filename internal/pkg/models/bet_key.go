@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BetKey identifies a market outcome across bookmakers within a match group: the calculator compares the
+// odds of the same BetKey, and stores history, snapshots and alerts under its string form.
+//
+// Encoding: "event_type|outcome_type|parameter" followed by optional "name=value" segments for the
+// qualifiers that are set, in a fixed order: "corners|total_over|9.5|period=1h". Keys without
+// qualifiers keep the format stored before qualifiers existed, so a new qualifier doesn't change the
+// keys of the existing markets.
+type BetKey struct {
+	Sport       string // optional: the match group key already carries it
+	EventType   string // StandardEventType: main_match, corners, ...
+	OutcomeType string // StandardOutcomeType: home_win, total_over, ...
+	Parameter   string // line or value: "2.5", "-1", "9-11"; "" for 1X2
+	Period      string // scope of the market: "" = full match, "1h", "2h", "q1", "map1", ...
+	Participant string // team or player of the market (ParticipantKey): "home", "away", "c ronaldo"; "" = whole match
+}
+
+// Qualifier names of the BetKey encoding.
+const (
+	betKeySport       = "sport"
+	betKeyPeriod      = "period"
+	betKeyParticipant = "participant"
+)
+
+// NewBetKey returns the key of a full-match outcome; fields are trimmed.
+func NewBetKey(eventType, outcomeType, parameter string) BetKey {
+	return BetKey{
+		EventType:   strings.TrimSpace(eventType),
+		OutcomeType: strings.TrimSpace(outcomeType),
+		Parameter:   strings.TrimSpace(parameter),
+	}
+}
+
+// OutcomeBetKey returns the key of out in ev. Team and player markets are keyed by their participant, so
+// the odds of different players are never compared.
+func OutcomeBetKey(ev Event, out Outcome) BetKey {
+	k := NewBetKey(ev.EventType, out.OutcomeType, out.Parameter)
+	k.Participant = ParticipantKey(out.Participant)
+	return k
+}
+
+// ParticipantKey normalizes Outcome.Participant for BetKey: ParticipantHome and ParticipantAway as is, a
+// player name to "initial surname" in lower case, so "Cristiano Ronaldo", "C. Ronaldo" and
+// "Ronaldo, Cristiano" are the same player.
+func ParticipantKey(participant string) string {
+	if surname, name, ok := strings.Cut(participant, ","); ok {
+		participant = name + " " + surname
+	}
+	key := AliasKey(strings.NewReplacer("|", " ", "=", " ").Replace(participant))
+	words := strings.Fields(key)
+	if len(words) < 2 {
+		return key
+	}
+	initial := []rune(words[0])[0]
+	return string(initial) + " " + words[len(words)-1]
+}
+
+// Valid reports whether the key names a market and an outcome.
+func (k BetKey) Valid() bool {
+	return k.EventType != "" && k.OutcomeType != ""
+}
+
+// WithOutcome returns the key of another outcome of the same market (the line is kept).
+func (k BetKey) WithOutcome(outcomeType string) BetKey {
+	k.OutcomeType = outcomeType
+	return k
+}
+
+// String returns the canonical encoding of the key.
+func (k BetKey) String() string {
+	var b strings.Builder
+	b.WriteString(k.EventType)
+	b.WriteByte('|')
+	b.WriteString(k.OutcomeType)
+	b.WriteByte('|')
+	b.WriteString(k.Parameter)
+	if k.Sport != "" {
+		b.WriteString("|" + betKeySport + "=" + k.Sport)
+	}
+	if k.Period != "" {
+		b.WriteString("|" + betKeyPeriod + "=" + k.Period)
+	}
+	if k.Participant != "" {
+		b.WriteString("|" + betKeyParticipant + "=" + k.Participant)
+	}
+	return b.String()
+}
+
+// ParseBetKey parses the encoding of BetKey.String. Missing trailing fields are empty ("main_match|draw"
+// is accepted); unknown qualifiers are an error.
+func ParseBetKey(s string) (BetKey, error) {
+	parts := strings.Split(s, "|")
+	var k BetKey
+	for i, p := range parts {
+		switch i {
+		case 0:
+			k.EventType = p
+		case 1:
+			k.OutcomeType = p
+		case 2:
+			k.Parameter = p
+		default:
+			name, value, ok := strings.Cut(p, "=")
+			if !ok {
+				return BetKey{}, fmt.Errorf("bet key %q: segment %q is not name=value", s, p)
+			}
+			switch name {
+			case betKeySport:
+				k.Sport = value
+			case betKeyPeriod:
+				k.Period = value
+			case betKeyParticipant:
+				k.Participant = value
+			default:
+				return BetKey{}, fmt.Errorf("bet key %q: unknown qualifier %q", s, name)
+			}
+		}
+	}
+	if !k.Valid() {
+		return BetKey{}, fmt.Errorf("bet key %q: event type and outcome type are required", s)
+	}
+	return k, nil
+}
+
+// SplitBetKey returns the event type, outcome type and parameter of an encoded key, empty for the missing
+// ones. Unlike ParseBetKey it never fails: for display and grouping of stored keys.
+func SplitBetKey(s string) (eventType, outcomeType, parameter string) {
+	parts := strings.SplitN(s, "|", 4)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return parts[0], parts[1], parts[2]
+}
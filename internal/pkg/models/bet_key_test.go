@@ -0,0 +1,77 @@
+package models
+
+import "testing"
+
+func TestBetKey_StringKeepsLegacyFormat(t *testing.T) {
+	tests := []struct {
+		key  BetKey
+		want string
+	}{
+		{NewBetKey("main_match", "home_win", ""), "main_match|home_win|"},
+		{NewBetKey(" corners ", "total_over", " 9.5 "), "corners|total_over|9.5"},
+		{BetKey{EventType: "corners", OutcomeType: "total_over", Parameter: "4.5", Period: "1h"}, "corners|total_over|4.5|period=1h"},
+		{BetKey{Sport: "hockey", EventType: "main_match", OutcomeType: "draw", Period: "p1"}, "main_match|draw||sport=hockey|period=p1"},
+	}
+	for _, tt := range tests {
+		if got := tt.key.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseBetKey_RoundTrip(t *testing.T) {
+	keys := []BetKey{
+		NewBetKey("main_match", "home_win", ""),
+		NewBetKey("yellow_cards", "total_interval", "3-5"),
+		{Sport: "football", EventType: "corners", OutcomeType: "handicap_home", Parameter: "-1.5", Period: "2h"},
+		{EventType: "player_shots", OutcomeType: "total_over", Parameter: "0.5", Participant: "l yamal"},
+	}
+	for _, k := range keys {
+		got, err := ParseBetKey(k.String())
+		if err != nil {
+			t.Fatalf("ParseBetKey(%q): %v", k.String(), err)
+		}
+		if got != k {
+			t.Errorf("ParseBetKey(%q) = %+v, want %+v", k.String(), got, k)
+		}
+	}
+}
+
+func TestParseBetKey_Invalid(t *testing.T) {
+	for _, s := range []string{"", "main_match", "|home_win|", "corners|total_over|9.5|1h", "corners|total_over|9.5|half=1"} {
+		if _, err := ParseBetKey(s); err == nil {
+			t.Errorf("ParseBetKey(%q) = nil error, want error", s)
+		}
+	}
+}
+
+func TestSplitBetKey(t *testing.T) {
+	ev, out, param := SplitBetKey("corners|total_over|9.5|period=1h")
+	if ev != "corners" || out != "total_over" || param != "9.5" {
+		t.Errorf("SplitBetKey = %q, %q, %q", ev, out, param)
+	}
+	ev, out, param = SplitBetKey("main_match|draw")
+	if ev != "main_match" || out != "draw" || param != "" {
+		t.Errorf("SplitBetKey(short) = %q, %q, %q", ev, out, param)
+	}
+}
+
+func TestOutcomeBetKey_Participant(t *testing.T) {
+	ev := Event{EventType: string(StandardEventPlayerShots)}
+	a := OutcomeBetKey(ev, Outcome{OutcomeType: "total_over", Parameter: "1.5", Participant: "Cristiano Ronaldo"})
+	b := OutcomeBetKey(ev, Outcome{OutcomeType: "total_over", Parameter: "1.5", Participant: "Ronaldo, Cristiano"})
+	c := OutcomeBetKey(ev, Outcome{OutcomeType: "total_over", Parameter: "1.5", Participant: "C. Ronaldo"})
+	if a != b || a != c {
+		t.Errorf("spellings of one player give different keys: %q, %q, %q", a, b, c)
+	}
+	if want := "player_shots|total_over|1.5|participant=c ronaldo"; a.String() != want {
+		t.Errorf("key = %q, want %q", a, want)
+	}
+	team := OutcomeBetKey(Event{EventType: "corners"}, Outcome{OutcomeType: "total_over", Parameter: "4.5", Participant: ParticipantHome})
+	if want := "corners|total_over|4.5|participant=home"; team.String() != want {
+		t.Errorf("team key = %q, want %q", team, want)
+	}
+	if whole := OutcomeBetKey(Event{EventType: "corners"}, Outcome{OutcomeType: "total_over", Parameter: "9.5"}); whole.String() != "corners|total_over|9.5" {
+		t.Errorf("whole match key = %q", whole)
+	}
+}
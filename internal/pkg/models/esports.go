@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // EsportsMatch — модель матча по киберспорту (Dota 2, CS и т.д.).
 // Отдельная от футбольной Match: футбол остаётся в Match/Event/Outcome.
@@ -16,6 +22,8 @@ type EsportsMatch struct {
 	Markets    []EsportsMarket  `json:"markets"`
 	CreatedAt  time.Time        `json:"created_at"`
 	UpdatedAt  time.Time        `json:"updated_at"`
+	HomeMeta   *TeamMeta        `json:"home_meta,omitempty"` // из датасета teaminfo (логотип, страна)
+	AwayMeta   *TeamMeta        `json:"away_meta,omitempty"`
 }
 
 // EsportsMarket — один рынок по киберспорту (исход матча, тотал карт, фора и т.д.).
@@ -41,3 +49,83 @@ type EsportsOutcome struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// Типы рынков киберспорта (EsportsMarket.MarketType → Event.EventType в калькуляторе).
+const (
+	EsportsMarketMatchWinner    = string(StandardEventMainMatch) // победитель матча (home_win / draw / away_win)
+	EsportsMarketTotalMaps      = "total_maps"                   // тотал карт (total_over / total_under)
+	EsportsMarketHandicapMaps   = "handicap_maps"                // фора по картам (handicap_home / handicap_away)
+	EsportsMarketTotalRounds    = "total_rounds"                 // тотал раундов (CS) / убийств (Dota 2) — всё, что больше тотала карт
+	EsportsMarketHandicapRounds = "handicap_rounds"              // фора по раундам / убийствам
+)
+
+// maxMapsLine — линии тотала и форы до этого значения относятся к картам (Bo5: 4.5 / ±2.5),
+// остальные — к раундам (CS) или убийствам (Dota 2).
+const maxMapsLine = 5.5
+
+// EsportsMapWinnerMarket returns the market type of the n-th map winner: "map_1_winner".
+func EsportsMapWinnerMarket(n int) string {
+	return fmt.Sprintf("map_%d_winner", n)
+}
+
+// EsportsTotalMarket classifies a total line: maps (2.5) or rounds/kills (46.5).
+func EsportsTotalMarket(param string) string {
+	if v, err := strconv.ParseFloat(strings.TrimSpace(param), 64); err == nil && v > maxMapsLine {
+		return EsportsMarketTotalRounds
+	}
+	return EsportsMarketTotalMaps
+}
+
+// EsportsHandicapMarket classifies a handicap line: maps (-1.5) or rounds/kills (-4.5).
+func EsportsHandicapMarket(param string) string {
+	v, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(param), "+"), 64)
+	if err == nil && (v > maxMapsLine/2 || v < -maxMapsLine/2) {
+		return EsportsMarketHandicapRounds
+	}
+	return EsportsMarketHandicapMaps
+}
+
+// GetEsportsMarketName returns a human-readable name of an esports market type.
+func GetEsportsMarketName(marketType string) string {
+	switch marketType {
+	case EsportsMarketMatchWinner:
+		return "Match Winner"
+	case EsportsMarketTotalMaps:
+		return "Total Maps"
+	case EsportsMarketHandicapMaps:
+		return "Maps Handicap"
+	case EsportsMarketTotalRounds:
+		return "Total Rounds"
+	case EsportsMarketHandicapRounds:
+		return "Rounds Handicap"
+	}
+	var n int
+	if _, err := fmt.Sscanf(marketType, "map_%d_winner", &n); err == nil {
+		return fmt.Sprintf("Map %d Winner", n)
+	}
+	return "Unknown Market"
+}
+
+// esportsMaxMapIndex bounds parsed map numbers (Bo7 at most).
+const esportsMaxMapIndex = 7
+
+var (
+	esportsMapNameRe  = regexp.MustCompile(`(?i)^\s*(\d+)\s*(?:-?я|st|nd|rd|th)?\s*(?:карта|map)(?:\s|$)`)
+	esportsMapNameAlt = regexp.MustCompile(`(?i)^\s*(?:карта|map)\s*(\d+)\s*$`)
+)
+
+// ParseEsportsMapNumber parses map names of child events / sub-games: "1-я карта", "2nd map", "Map 3".
+func ParseEsportsMapNumber(name string) (int, bool) {
+	m := esportsMapNameRe.FindStringSubmatch(name)
+	if m == nil {
+		m = esportsMapNameAlt.FindStringSubmatch(name)
+	}
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 || n > esportsMaxMapIndex {
+		return 0, false
+	}
+	return n, true
+}
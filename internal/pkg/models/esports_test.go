@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestEsportsMarketClassification(t *testing.T) {
+	totals := map[string]string{"2.5": EsportsMarketTotalMaps, "4.5": EsportsMarketTotalMaps, "26.5": EsportsMarketTotalRounds, "": EsportsMarketTotalMaps}
+	for param, want := range totals {
+		if got := EsportsTotalMarket(param); got != want {
+			t.Errorf("EsportsTotalMarket(%q) = %s, want %s", param, got, want)
+		}
+	}
+	handicaps := map[string]string{"+1.5": EsportsMarketHandicapMaps, "-2.5": EsportsMarketHandicapMaps, "-4.5": EsportsMarketHandicapRounds, "+6.5": EsportsMarketHandicapRounds}
+	for param, want := range handicaps {
+		if got := EsportsHandicapMarket(param); got != want {
+			t.Errorf("EsportsHandicapMarket(%q) = %s, want %s", param, got, want)
+		}
+	}
+	if got := GetEsportsMarketName(EsportsMapWinnerMarket(3)); got != "Map 3 Winner" {
+		t.Errorf("map winner name = %q", got)
+	}
+}
+
+func TestParseEsportsMapNumber(t *testing.T) {
+	tests := map[string]int{"1-я карта": 1, "2 карта": 2, "3rd map": 3, "Map 2": 2, "Карта 4": 4}
+	for name, want := range tests {
+		if got, ok := ParseEsportsMapNumber(name); !ok || got != want {
+			t.Errorf("ParseEsportsMapNumber(%q) = %d, %v; want %d", name, got, ok, want)
+		}
+	}
+	for _, name := range []string{"Угловые", "1-я половина", "10-я карта", "mapping"} {
+		if n, ok := ParseEsportsMapNumber(name); ok {
+			t.Errorf("ParseEsportsMapNumber(%q) = %d, want no match", name, n)
+		}
+	}
+}
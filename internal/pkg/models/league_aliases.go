@@ -0,0 +1,38 @@
+package models
+
+import _ "embed"
+
+//go:embed league_aliases.json
+var leagueAliasesJSON []byte
+
+// leagueAliases maps the tournament names of the bookmakers to one name ("EPL", "Англия. Премьер-лига"
+// → "England. Premier League") for filtering, grouping and display in alerts.
+// Format of league_aliases.json and league_aliases.file: {"leagues": {"<canonical>": ["<alias>", ...]}}.
+var leagueAliases = newAliasDictionary("leagues", leagueAliasesJSON)
+
+// ConfigureLeagueAliases merges the aliases of file over the embedded seed and returns the number of seed
+// entries. Empty file = embedded seed only.
+func ConfigureLeagueAliases(file string) (int, error) {
+	return leagueAliases.configure(file)
+}
+
+// AddLeagueAlias makes alias resolve to canonical (replacing an existing runtime alias).
+func AddLeagueAlias(alias, canonical string) error {
+	return leagueAliases.add(alias, canonical)
+}
+
+// RemoveLeagueAlias removes a runtime alias; reports whether it existed. Seed aliases can't be removed.
+func RemoveLeagueAlias(alias string) bool {
+	return leagueAliases.remove(alias)
+}
+
+// CanonicalLeagueName returns the canonical name of a tournament, or name unchanged if it is not an alias.
+func CanonicalLeagueName(name string) string {
+	return leagueAliases.canonical(name)
+}
+
+// LeagueAliases returns the dictionary sorted by canonical name and alias; a runtime alias hides the seed
+// alias with the same key.
+func LeagueAliases() []NameAlias {
+	return leagueAliases.list()
+}
@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestCanonicalLeagueName(t *testing.T) {
+	for _, name := range []string{"EPL", "England. Premier League", "Англия. Премьер-лига", "england - premier league"} {
+		if got := CanonicalLeagueName(name); got != "England. Premier League" {
+			t.Errorf("CanonicalLeagueName(%q) = %q", name, got)
+		}
+	}
+	if got := CanonicalLeagueName("Scotland. Premiership"); got != "Scotland. Premiership" {
+		t.Errorf("unknown tournament changed to %q", got)
+	}
+
+	defer RemoveLeagueAlias("Шотландия. Премьершип")
+	if err := AddLeagueAlias("Шотландия. Премьершип", "Scotland. Premiership"); err != nil {
+		t.Fatal(err)
+	}
+	if got := CanonicalLeagueName("шотландия премьершип"); got != "Scotland. Premiership" {
+		t.Errorf("added alias = %q", got)
+	}
+	if got := CanonicalTeamName("Шотландия. Премьершип"); got != "Шотландия. Премьершип" {
+		t.Errorf("league alias leaked into team aliases: %q", got)
+	}
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Match represents a main match with all its events
 type Match struct {
@@ -15,6 +20,13 @@ type Match struct {
 	Events       []Event   `json:"events"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	Stale        bool      `json:"stale,omitempty"` // Served from the startup snapshot, not yet refreshed by a parsing cycle
+	URL          string    `json:"url,omitempty"`   // Event page at the bookmaker (empty = unknown); see OutcomeURL
+
+	// Team/league metadata (logo, country) added by the match API from the teaminfo dataset; nil if unknown
+	HomeMeta   *TeamMeta   `json:"home_meta,omitempty"`
+	AwayMeta   *TeamMeta   `json:"away_meta,omitempty"`
+	LeagueMeta *LeagueMeta `json:"league_meta,omitempty"`
 }
 
 // Event represents a specific event type within a match (corners, yellow cards, etc.)
@@ -35,12 +47,31 @@ type Outcome struct {
 	EventID     string  `json:"event_id"`
 	OutcomeType string  `json:"outcome_type"` // total_over, total_under, exact_count, etc.
 	Parameter   string  `json:"parameter"`    // "2.5", "3", "4-6", etc.
+	Participant string  `json:"participant,omitempty"` // team or player of the market: ParticipantHome, ParticipantAway or a player name; "" = whole match
 	Odds        float64 `json:"odds"`
 	Bookmaker   string  `json:"bookmaker"`
+	URL         string  `json:"url,omitempty"` // Bookmaker page of the outcome when the match row has none (shared rows, e.g. Fonbet)
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// OutcomeURL returns the bookmaker page to open for out: its own URL, else the match's ("" = unknown).
+func (m *Match) OutcomeURL(out Outcome) string {
+	if out.URL != "" {
+		return out.URL
+	}
+	return m.URL
+}
+
+// EventURL fills the "{id}" of a bookmaker event page template (parser.<bookmaker>.event_url) with the
+// bookmaker's event id. Returns "" when the template or id is empty.
+func EventURL(template, id string) string {
+	if template == "" || id == "" {
+		return ""
+	}
+	return strings.ReplaceAll(template, "{id}", url.PathEscape(id))
+}
+
 // StandardEventType represents standardized event types across all bookmakers
 type StandardEventType string
 
@@ -52,6 +83,19 @@ const (
 	StandardEventShotsOnTarget  StandardEventType = "shots_on_target"
 	StandardEventOffsides       StandardEventType = "offsides"
 	StandardEventThrowIns       StandardEventType = "throw_ins"
+
+	StandardEventBothTeamsToScore StandardEventType = "both_teams_to_score"
+	StandardEventCorrectScore     StandardEventType = "correct_score"
+
+	// Player props: Outcome.Participant is the player
+	StandardEventPlayerShots         StandardEventType = "player_shots"
+	StandardEventPlayerShotsOnTarget StandardEventType = "player_shots_on_target"
+)
+
+// Participants of team markets (Outcome.Participant): team corners, team cards, team totals.
+const (
+	ParticipantHome = "home"
+	ParticipantAway = "away"
 )
 
 // StandardOutcomeType represents standardized outcome types
@@ -73,8 +117,64 @@ const (
 	// Alternative totals
 	OutcomeTypeAltTotalOver  StandardOutcomeType = "alt_total_over"
 	OutcomeTypeAltTotalUnder StandardOutcomeType = "alt_total_under"
+
+	// Interval totals: the count falls in a range, parameter "9-11" or open-ended "12+"
+	OutcomeTypeTotalInterval StandardOutcomeType = "total_interval"
+
+	// Both teams to score
+	OutcomeTypeBTTSYes StandardOutcomeType = "btts_yes"
+	OutcomeTypeBTTSNo  StandardOutcomeType = "btts_no"
+
+	// Correct score: parameter "home:away" (ScoreParam), e.g. "2:1"
+	OutcomeTypeCorrectScore StandardOutcomeType = "correct_score"
 )
 
+// ScoreParam formats a correct score parameter: (2, 1) -> "2:1".
+func ScoreParam(home, away int) string {
+	return strconv.Itoa(home) + ":" + strconv.Itoa(away)
+}
+
+// ParseScoreParam parses a correct score as the bookmakers write it: "2:1", "2-1", "2 : 1" -> (2, 1, true).
+func ParseScoreParam(param string) (home, away int, ok bool) {
+	param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+	a, b, found := strings.Cut(param, ":")
+	if !found {
+		a, b, found = strings.Cut(param, "-")
+	}
+	if !found {
+		return 0, 0, false
+	}
+	home, err1 := strconv.Atoi(a)
+	away, err2 := strconv.Atoi(b)
+	if err1 != nil || err2 != nil || home < 0 || away < 0 {
+		return 0, 0, false
+	}
+	return home, away, true
+}
+
+// ParseIntervalParam parses an interval parameter: "9-11" -> (9, 11, true), "12+" -> (12, -1, true).
+// hi == -1 means the interval is open-ended.
+func ParseIntervalParam(param string) (lo, hi int, ok bool) {
+	param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+	if rest, found := strings.CutSuffix(param, "+"); found {
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		return n, -1, true
+	}
+	a, b, found := strings.Cut(param, "-")
+	if !found {
+		return 0, 0, false
+	}
+	lo, err1 := strconv.Atoi(a)
+	hi, err2 := strconv.Atoi(b)
+	if err1 != nil || err2 != nil || lo < 0 || hi < lo {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
 // GetMarketName returns the market name for a standard event type
 func GetMarketName(eventType StandardEventType) string {
 	switch eventType {
@@ -92,6 +192,14 @@ func GetMarketName(eventType StandardEventType) string {
 		return "Offsides"
 	case StandardEventThrowIns:
 		return "Throw-ins"
+	case StandardEventBothTeamsToScore:
+		return "Both Teams To Score"
+	case StandardEventCorrectScore:
+		return "Correct Score"
+	case StandardEventPlayerShots:
+		return "Player Shots"
+	case StandardEventPlayerShotsOnTarget:
+		return "Player Shots on Target"
 	default:
 		return "Unknown Market"
 	}
@@ -116,6 +224,14 @@ func GetOutcomeTypeName(outcomeType StandardOutcomeType) string {
 		return "Alternative Total Over"
 	case OutcomeTypeAltTotalUnder:
 		return "Alternative Total Under"
+	case OutcomeTypeTotalInterval:
+		return "Total Interval"
+	case OutcomeTypeBTTSYes:
+		return "Both Teams To Score - Yes"
+	case OutcomeTypeBTTSNo:
+		return "Both Teams To Score - No"
+	case OutcomeTypeCorrectScore:
+		return "Correct Score"
 	default:
 		return "Unknown Outcome"
 	}
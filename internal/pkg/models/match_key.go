@@ -16,10 +16,41 @@ var (
 	teamPatterns     map[string]string
 )
 
+// DefaultStartTimeTolerance is the bucket of start times when parser.start_time_tolerance is not set.
+const DefaultStartTimeTolerance = 30 * time.Minute
+
+// startTimeTolerance is the bucket of start times in CanonicalMatchID (parser.start_time_tolerance; 0 = exact).
+var startTimeTolerance = DefaultStartTimeTolerance
+
+// SetStartTimeTolerance sets the bucket of start times in CanonicalMatchID: start times rounded to the same
+// multiple of d (UTC) give the same ID, so 17:55, 18:00 and 18:05 stay one match with d = 30m.
+// Zero applies DefaultStartTimeTolerance, negative keeps exact times. Call once at startup.
+func SetStartTimeTolerance(d time.Duration) {
+	switch {
+	case d == 0:
+		startTimeTolerance = DefaultStartTimeTolerance
+	case d < 0:
+		startTimeTolerance = 0
+	default:
+		startTimeTolerance = d
+	}
+}
+
+// StartTimeBucket returns t in UTC rounded to the nearest multiple of the start time tolerance. Rounding rather
+// than truncating keeps a kickoff reported a minute either side of the hour (17:59 vs 18:01) in one bucket.
+func StartTimeBucket(t time.Time) time.Time {
+	t = t.UTC()
+	if startTimeTolerance > 0 {
+		t = t.Round(startTimeTolerance)
+	}
+	return t
+}
+
 // CanonicalMatchID builds a stable cross-bookmaker match identifier.
 //
 // IMPORTANT: this assumes team names are in the same language/format across sources.
-// For best results, keep both parsers in English (e.g. Fonbet lang=en, Pinnacle is English).
+// For best results, keep both parsers in English (e.g. Fonbet lang=en, Pinnacle is English);
+// names that differ anyway are mapped by the team alias dictionary (team_aliases.go).
 // Format: team1|team2|time (sport removed as we only work with football)
 func CanonicalMatchID(homeTeam, awayTeam string, startTime time.Time) string {
 	return CanonicalMatchIDWithBookmaker(homeTeam, awayTeam, startTime, "")
@@ -30,16 +61,18 @@ func CanonicalMatchIDWithBookmaker(homeTeam, awayTeam string, startTime time.Tim
 	home := normalizeKeyPart(homeTeam, bookmaker)
 	away := normalizeKeyPart(awayTeam, bookmaker)
 
-	// Use exact time without rounding
+	// Nearest tolerance bucket (exact time when parser.start_time_tolerance is negative)
 	ts := "unknown-time"
 	if !startTime.IsZero() {
-		ts = startTime.UTC().Format(time.RFC3339)
+		ts = StartTimeBucket(startTime).Format(time.RFC3339)
 	}
 
 	return home + "|" + away + "|" + ts
 }
 
 func normalizeKeyPart(s string, bookmaker string) string {
+	// Aliases first: "Man Utd" and "Манчестер Юнайтед" don't normalize to "manchester united"
+	s = CanonicalTeamName(s)
 	s = strings.ToLower(strings.TrimSpace(s))
 	if s == "" {
 		return ""
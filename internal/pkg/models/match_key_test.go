@@ -73,3 +73,32 @@ func TestCanonicalMatchID_CrossBookmakerMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalMatchID_StartTimeTolerance(t *testing.T) {
+	defer SetStartTimeTolerance(0)
+	at := time.Date(2026, 10, 25, 18, 0, 0, 0, time.UTC)
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// Unset: the default 30m bucket, rounded to the nearest one
+	SetStartTimeTolerance(0)
+	if got, want := CanonicalMatchID("Arsenal", "Chelsea", at.Add(-time.Minute)), CanonicalMatchID("Arsenal", "Chelsea", at.Add(time.Minute)); got != want {
+		t.Errorf("17:59 = %q, 18:01 = %q, want one ID", got, want)
+	}
+	if got, want := CanonicalMatchID("Arsenal", "Chelsea", at.Add(5*time.Minute).In(moscow)), CanonicalMatchID("Arsenal", "Chelsea", at); got != want {
+		t.Errorf("18:05 MSK-zoned = %q, want %q", got, want)
+	}
+
+	SetStartTimeTolerance(-1)
+	if CanonicalMatchID("Arsenal", "Chelsea", at) == CanonicalMatchID("Arsenal", "Chelsea", at.Add(5*time.Minute)) {
+		t.Error("exact times merged with a negative tolerance")
+	}
+	if CanonicalMatchID("Arsenal", "Chelsea", at) != CanonicalMatchID("Arsenal", "Chelsea", at.In(moscow)) {
+		t.Error("same instant in another zone gives another ID")
+	}
+
+	SetStartTimeTolerance(15 * time.Minute)
+	if !StartTimeBucket(at.Add(-7*time.Minute)).Equal(at) || !StartTimeBucket(at.Add(7*time.Minute)).Equal(at) ||
+		StartTimeBucket(at.Add(8*time.Minute)).Equal(at) {
+		t.Error("bucket boundaries wrong")
+	}
+}
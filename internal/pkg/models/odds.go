@@ -0,0 +1,67 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// Политика точности коэффициентов: храним как прислала контора (1.952), сравниваем с допуском
+// (1.952 и 1.95 — не изменение линии), показываем с округлением до 2–3 знаков.
+const (
+	DefaultOddsEpsilon  = 0.005 // коэффициенты, отличающиеся меньше, считаются равными
+	DefaultOddsDecimals = 2     // знаков после запятой при выводе
+)
+
+var (
+	oddsEpsilon  = DefaultOddsEpsilon
+	oddsDecimals = DefaultOddsDecimals
+)
+
+// SetOddsPrecision sets the comparison epsilon and display decimals (config odds.epsilon / odds.decimals).
+// Zero or negative values keep the defaults; decimals are clamped to 2–3. Call once at startup.
+func SetOddsPrecision(epsilon float64, decimals int) {
+	oddsEpsilon = DefaultOddsEpsilon
+	if epsilon > 0 {
+		oddsEpsilon = epsilon
+	}
+	oddsDecimals = DefaultOddsDecimals
+	if decimals > 0 {
+		oddsDecimals = min(max(decimals, 2), 3)
+	}
+}
+
+// OddsEqual reports whether two odds differ by less than the configured epsilon.
+func OddsEqual(a, b float64) bool {
+	return math.Abs(a-b) < oddsEpsilon
+}
+
+// FormatOdds formats an odd with the configured number of decimals: 1.952 → "1.95".
+func FormatOdds(odd float64) string {
+	return strconv.FormatFloat(odd, 'f', oddsDecimals, 64)
+}
+
+// ImpliedProbability returns the implied probability of an odd (1/odd), 0 for odds ≤ 0.
+func ImpliedProbability(odd float64) float64 {
+	if odd <= 0 {
+		return 0
+	}
+	return 1 / odd
+}
+
+// OddsChangePercent returns the change from prev to cur in % of prev: 2.0 → 1.8 = -10.
+func OddsChangePercent(prev, cur float64) float64 {
+	if prev <= 0 {
+		return 0
+	}
+	return (cur - prev) / prev * 100
+}
+
+// ProbShiftPP returns the change of implied probability from prev to cur in percentage points:
+// 2.0 → 1.8 = +5.56 pp (50% → 55.6%). Unlike OddsChangePercent it doesn't exaggerate moves
+// on high odds: 10 → 8 is -20% but only +2.5 pp.
+func ProbShiftPP(prev, cur float64) float64 {
+	if prev <= 0 || cur <= 0 {
+		return 0
+	}
+	return (ImpliedProbability(cur) - ImpliedProbability(prev)) * 100
+}
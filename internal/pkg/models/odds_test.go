@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestOddsEqual(t *testing.T) {
+	defer SetOddsPrecision(0, 0)
+
+	if !OddsEqual(1.952, 1.95) {
+		t.Error("1.952 and 1.95 must be equal with the default epsilon")
+	}
+	if OddsEqual(1.96, 1.95) {
+		t.Error("1.96 and 1.95 must differ with the default epsilon")
+	}
+
+	SetOddsPrecision(0.02, 0)
+	if !OddsEqual(1.96, 1.95) {
+		t.Error("1.96 and 1.95 must be equal with epsilon 0.02")
+	}
+}
+
+func TestFormatOdds(t *testing.T) {
+	defer SetOddsPrecision(0, 0)
+
+	tests := []struct {
+		decimals int
+		odd      float64
+		want     string
+	}{
+		{0, 1.952, "1.95"},
+		{0, 2, "2.00"},
+		{3, 1.952, "1.952"},
+		{5, 1.95249, "1.952"}, // clamped to 3
+		{1, 1.952, "1.95"},    // clamped to 2
+	}
+	for _, tt := range tests {
+		SetOddsPrecision(0, tt.decimals)
+		if got := FormatOdds(tt.odd); got != tt.want {
+			t.Errorf("decimals %d: FormatOdds(%v) = %q, want %q", tt.decimals, tt.odd, got, tt.want)
+		}
+	}
+}
+
+func TestOddsMovementUnits(t *testing.T) {
+	near := func(a, b float64) bool { return a-b < 0.01 && b-a < 0.01 }
+	tests := []struct {
+		prev, cur        float64
+		percent, shiftPP float64
+	}{
+		{2.0, 1.8, -10, 5.56},
+		{10, 8, -20, 2.5}, // big % move on a high odd is a small probability shift
+		{1.5, 1.35, -10, 7.41},
+		{1.8, 2.0, 11.11, -5.56},
+		{0, 1.8, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := OddsChangePercent(tt.prev, tt.cur); !near(got, tt.percent) {
+			t.Errorf("OddsChangePercent(%v, %v) = %.2f, want %.2f", tt.prev, tt.cur, got, tt.percent)
+		}
+		if got := ProbShiftPP(tt.prev, tt.cur); !near(got, tt.shiftPP) {
+			t.Errorf("ProbShiftPP(%v, %v) = %.2f, want %.2f", tt.prev, tt.cur, got, tt.shiftPP)
+		}
+	}
+	if p := ImpliedProbability(4); p != 0.25 {
+		t.Errorf("ImpliedProbability(4) = %v, want 0.25", p)
+	}
+}
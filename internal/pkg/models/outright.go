@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Outright — долгосрочная ставка на турнир (победитель лиги, выход в плей-офф и т.д.).
+// Отдельная от Match модель: здесь нет пары команд, исходы — участники турнира.
+type Outright struct {
+	ID         string            `json:"id"`
+	Sport      string            `json:"sport"`
+	Tournament string            `json:"tournament"`
+	MarketName string            `json:"market_name"` // "Winner", "Top 4", ...
+	Bookmaker  string            `json:"bookmaker"`
+	CloseTime  time.Time         `json:"close_time"` // when the market stops accepting bets (zero if unknown)
+	Outcomes   []OutrightOutcome `json:"outcomes"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// OutrightOutcome — один участник outright-рынка с коэффициентом.
+type OutrightOutcome struct {
+	Participant string    `json:"participant"`
+	Odds        float64   `json:"odds"`
+	Bookmaker   string    `json:"bookmaker"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OutrightID builds a stable per-bookmaker ID for an outright market.
+// Markets from different bookmakers are matched later by tournament, not by ID.
+func OutrightID(bookmaker, sport, tournament, market string) string {
+	norm := func(s string) string {
+		return strings.Join(strings.Fields(strings.ToLower(s)), "_")
+	}
+	return norm(bookmaker) + "|" + norm(sport) + "|" + norm(tournament) + "|" + norm(market)
+}
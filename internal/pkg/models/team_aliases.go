@@ -0,0 +1,38 @@
+package models
+
+import _ "embed"
+
+//go:embed team_aliases.json
+var teamAliasesJSON []byte
+
+// teamAliases is consulted by CanonicalMatchID and the calculator's match grouping before the string
+// normalization: "Man Utd" and "Манчестер Юнайтед" both become "Manchester United".
+// Format of team_aliases.json and team_aliases.file: {"teams": {"<canonical>": ["<alias>", ...]}}.
+var teamAliases = newAliasDictionary("teams", teamAliasesJSON)
+
+// ConfigureTeamAliases merges the aliases of file over the embedded seed and returns the number of seed
+// entries. Empty file = embedded seed only.
+func ConfigureTeamAliases(file string) (int, error) {
+	return teamAliases.configure(file)
+}
+
+// AddTeamAlias makes alias resolve to canonical (replacing an existing runtime alias).
+func AddTeamAlias(alias, canonical string) error {
+	return teamAliases.add(alias, canonical)
+}
+
+// RemoveTeamAlias removes a runtime alias; reports whether it existed. Seed aliases can't be removed.
+func RemoveTeamAlias(alias string) bool {
+	return teamAliases.remove(alias)
+}
+
+// CanonicalTeamName returns the canonical name of a team alias, or name unchanged if it is not an alias.
+func CanonicalTeamName(name string) string {
+	return teamAliases.canonical(name)
+}
+
+// TeamAliases returns the dictionary sorted by canonical name and alias; a runtime alias hides the seed
+// alias with the same key.
+func TeamAliases() []NameAlias {
+	return teamAliases.list()
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCanonicalMatchID_TeamAliases(t *testing.T) {
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	want := CanonicalMatchID("Manchester United", "Liverpool", start)
+	for _, home := range []string{"Man Utd", "Manchester Utd", "Манчестер Юнайтед"} {
+		if got := CanonicalMatchID(home, "Ливерпуль", start); got != want {
+			t.Errorf("CanonicalMatchID(%q, Ливерпуль) = %q, want %q", home, got, want)
+		}
+	}
+}
+
+func TestTeamAliases_AddRemove(t *testing.T) {
+	defer RemoveTeamAlias("Шахтёр Д")
+
+	if err := AddTeamAlias("Шахтёр Д", "Shakhtar Donetsk"); err != nil {
+		t.Fatal(err)
+	}
+	if got := CanonicalTeamName("шахтер д."); got != "Shakhtar Donetsk" {
+		t.Errorf("CanonicalTeamName = %q, want Shakhtar Donetsk", got)
+	}
+	if err := AddTeamAlias("Shakhtar-Donetsk", "Shakhtar Donetsk"); err == nil {
+		t.Error("alias equal to its canonical name accepted")
+	}
+	if !RemoveTeamAlias("Шахтёр Д") || RemoveTeamAlias("Man Utd") {
+		t.Error("RemoveTeamAlias must remove added aliases only")
+	}
+	if got := CanonicalTeamName("Шахтёр Д"); got != "Шахтёр Д" {
+		t.Errorf("removed alias still resolves to %q", got)
+	}
+}
+
+func TestConfigureTeamAliases_File(t *testing.T) {
+	defer ConfigureTeamAliases("") //nolint:errcheck // restores the embedded seed
+
+	file := filepath.Join(t.TempDir(), "aliases.json")
+	if err := os.WriteFile(file, []byte(`{"teams": {"Shakhtar Donetsk": ["Шахтёр Донецк"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConfigureTeamAliases(file); err != nil {
+		t.Fatal(err)
+	}
+	if got := CanonicalTeamName("Шахтер Донецк"); got != "Shakhtar Donetsk" {
+		t.Errorf("file alias = %q", got)
+	}
+	if got := CanonicalTeamName("Man Utd"); got != "Manchester United" {
+		t.Errorf("embedded alias lost after loading the file: %q", got)
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+// TeamMeta is static metadata of a team from the enrichment dataset (internal/pkg/teaminfo),
+// attached to matches by the match API so clients can show logos and countries.
+type TeamMeta struct {
+	Name    string `json:"name"` // canonical name from the dataset
+	Country string `json:"country,omitempty"`
+	League  string `json:"league,omitempty"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
+
+// LeagueMeta is static metadata of a league/tournament from the enrichment dataset.
+type LeagueMeta struct {
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
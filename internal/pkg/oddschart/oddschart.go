@@ -0,0 +1,230 @@
+// Package oddschart renders odds-over-time charts as PNG images for Telegram: one line per bookmaker,
+// time (UTC) on the x axis and the odd on the y axis. Odds are drawn as steps, since a recorded odd
+// holds until the next record. Only the standard image packages and the x/image basic font are used, so
+// text outside Latin-1 (Cyrillic team names) is not drawn: keep titles to ids and bookmaker names.
+package oddschart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of the rendered image in pixels.
+const (
+	Width  = 800
+	Height = 400
+)
+
+const (
+	marginLeft   = 56
+	marginRight  = 16
+	marginTop    = 40
+	marginBottom = 32
+	ticks        = 5
+)
+
+// ErrNoData is returned when the series have no points to draw.
+var ErrNoData = errors.New("no odds to chart")
+
+// Point is an odd recorded at a time.
+type Point struct {
+	At  time.Time
+	Odd float64
+}
+
+// Series is the odds of one bookmaker, oldest first.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColor  = color.RGBA{0xe4, 0xe4, 0xe4, 0xff}
+	axisColor  = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	textColor  = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	// Line colors of the series, repeated when there are more
+	palette = []color.RGBA{
+		{0x1f, 0x77, 0xb4, 0xff},
+		{0xd6, 0x27, 0x28, 0xff},
+		{0x2c, 0xa0, 0x2c, 0xff},
+		{0xff, 0x7f, 0x0e, 0xff},
+		{0x94, 0x67, 0xbd, 0xff},
+		{0x8c, 0x56, 0x4b, 0xff},
+		{0xe3, 0x77, 0xc2, 0xff},
+		{0x17, 0xbe, 0xcf, 0xff},
+	}
+)
+
+// Render draws the series under title and returns the PNG.
+func Render(title string, series []Series) ([]byte, error) {
+	tMin, tMax, oMin, oMax, ok := bounds(series)
+	if !ok {
+		return nil, ErrNoData
+	}
+	if !tMax.After(tMin) {
+		tMin, tMax = tMin.Add(-time.Minute), tMax.Add(time.Minute)
+	}
+	pad := (oMax - oMin) * 0.1
+	if pad == 0 {
+		pad = 0.05
+	}
+	oMin, oMax = oMin-pad, oMax+pad
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	plot := image.Rect(marginLeft, marginTop, Width-marginRight, Height-marginBottom)
+	x := func(t time.Time) int {
+		return plot.Min.X + int(math.Round(float64(plot.Dx())*float64(t.Sub(tMin))/float64(tMax.Sub(tMin))))
+	}
+	y := func(odd float64) int {
+		return plot.Max.Y - int(math.Round(float64(plot.Dy())*(odd-oMin)/(oMax-oMin)))
+	}
+
+	// Grid and tick labels
+	timeLayout := "15:04"
+	if tMax.Sub(tMin) > 24*time.Hour {
+		timeLayout = "01-02 15:04"
+	}
+	for i := 0; i <= ticks; i++ {
+		odd := oMin + (oMax-oMin)*float64(i)/ticks
+		py := y(odd)
+		hline(img, plot.Min.X, plot.Max.X, py, gridColor)
+		label := fmt.Sprintf("%.2f", odd)
+		text(img, plot.Min.X-6-textWidth(label), py+4, label, textColor)
+
+		t := tMin.Add(time.Duration(float64(tMax.Sub(tMin)) * float64(i) / ticks))
+		px := x(t)
+		vline(img, px, plot.Min.Y, plot.Max.Y, gridColor)
+		label = t.UTC().Format(timeLayout)
+		text(img, px-textWidth(label)/2, plot.Max.Y+18, label, textColor)
+	}
+	hline(img, plot.Min.X, plot.Max.X, plot.Max.Y, axisColor)
+	vline(img, plot.Min.X, plot.Min.Y, plot.Max.Y, axisColor)
+
+	// Title on the left, legend on the right
+	text(img, marginLeft, 18, title, textColor)
+	legendX := Width - marginRight
+	for i := len(series) - 1; i >= 0; i-- {
+		if len(series[i].Points) == 0 {
+			continue
+		}
+		legendX -= textWidth(series[i].Name) + 24
+		c := palette[i%len(palette)]
+		draw.Draw(img, image.Rect(legendX, 27, legendX+12, 33), image.NewUniform(c), image.Point{}, draw.Src)
+		text(img, legendX+16, 34, series[i].Name, textColor)
+	}
+
+	// Steps: a recorded odd holds until the next record
+	for i, s := range series {
+		c := palette[i%len(palette)]
+		for j, p := range s.Points {
+			px, py := x(p.At), y(p.Odd)
+			if j > 0 {
+				prev := s.Points[j-1]
+				line(img, x(prev.At), y(prev.Odd), px, y(prev.Odd), c)
+				line(img, px, y(prev.Odd), px, py, c)
+			}
+			draw.Draw(img, image.Rect(px-2, py-2, px+3, py+3), image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bounds returns the time and odds range of all points (ok = false without points).
+func bounds(series []Series) (tMin, tMax time.Time, oMin, oMax float64, ok bool) {
+	for _, s := range series {
+		for _, p := range s.Points {
+			if !ok {
+				tMin, tMax, oMin, oMax, ok = p.At, p.At, p.Odd, p.Odd, true
+				continue
+			}
+			if p.At.Before(tMin) {
+				tMin = p.At
+			}
+			if p.At.After(tMax) {
+				tMax = p.At
+			}
+			oMin, oMax = math.Min(oMin, p.Odd), math.Max(oMax, p.Odd)
+		}
+	}
+	return
+}
+
+func hline(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	for x := x0; x <= x1; x++ {
+		img.SetRGBA(x, y, c)
+	}
+}
+
+func vline(img *image.RGBA, x, y0, y1 int, c color.RGBA) {
+	for y := y0; y <= y1; y++ {
+		img.SetRGBA(x, y, c)
+	}
+}
+
+// line draws a 2 px wide line from (x0, y0) to (x1, y1).
+func line(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	err := dx + dy
+	for {
+		img.SetRGBA(x0, y0, c)
+		img.SetRGBA(x0+1, y0, c)
+		img.SetRGBA(x0, y0+1, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func text(img *image.RGBA, x, y int, s string, c color.RGBA) {
+	d := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: basicfont.Face7x13, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+func textWidth(s string) int {
+	return font.MeasureString(basicfont.Face7x13, s).Round()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package oddschart
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	series := []Series{
+		{Name: "fonbet", Points: []Point{{start, 2.1}, {start.Add(10 * time.Minute), 1.95}, {start.Add(30 * time.Minute), 1.8}}},
+		{Name: "pinnacle", Points: []Point{{start.Add(5 * time.Minute), 2.05}, {start.Add(25 * time.Minute), 1.85}}},
+	}
+	data, err := Render("main_match|home_win|", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Errorf("size %v", b)
+	}
+	// The first point of the first series: top left of the plot (highest odd, earliest time)
+	if got := img.At(marginLeft, marginTop+(Height-marginTop-marginBottom)/12); !sameColor(got, palette[0]) {
+		t.Errorf("no first series at its first point, got %v", got)
+	}
+
+	// A single point still renders
+	if _, err := Render("x", []Series{{Name: "fonbet", Points: []Point{{start, 2}}}}); err != nil {
+		t.Errorf("single point: %v", err)
+	}
+	if _, err := Render("x", []Series{{Name: "fonbet"}}); !errors.Is(err, ErrNoData) {
+		t.Errorf("no points: got %v, want ErrNoData", err)
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
@@ -0,0 +1,91 @@
+package parserutil
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Shutdown drain (parser.shutdown_grace_period): on SIGTERM the bookmaker-service stops starting cycles
+// and leagues, lets the leagues in progress finish and store their matches, and only then cancels the
+// parsers, so a restart doesn't lose the league being parsed.
+
+// drainPoll is how often Drain checks for running cycles.
+const drainPoll = 100 * time.Millisecond
+
+var (
+	drainMu sync.Mutex
+	drainCh = make(chan struct{}) // closed by BeginDrain
+
+	// activeCycles counts the incremental cycles and ParseOnce runs in progress
+	activeCycles atomic.Int64
+)
+
+func drainSignal() <-chan struct{} {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	return drainCh
+}
+
+// BeginDrain starts draining: RunIncrementalLoop starts no new cycle and the league loops of the parsers
+// (DrainContext) stop before their next league.
+func BeginDrain() {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	select {
+	case <-drainCh:
+	default:
+		close(drainCh)
+	}
+}
+
+// Draining reports whether BeginDrain was called.
+func Draining() bool {
+	select {
+	case <-drainSignal():
+		return true
+	default:
+		return false
+	}
+}
+
+// DrainContext returns a context done when ctx is done or draining begins. League loops select on it
+// between leagues, while the requests of the league in progress keep using ctx and complete.
+func DrainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	loopCtx, cancel := context.WithCancel(ctx)
+	drain := drainSignal()
+	go func() {
+		select {
+		case <-drain:
+			cancel()
+		case <-loopCtx.Done():
+		}
+	}()
+	return loopCtx, cancel
+}
+
+// Drain begins draining and waits until no cycle is running, at most grace. Reports whether every cycle
+// finished in time.
+func Drain(grace time.Duration) bool {
+	BeginDrain()
+	deadline := time.Now().Add(grace)
+	for activeCycles.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(drainPoll)
+	}
+	return true
+}
+
+// ActiveCycles returns the number of cycles in progress.
+func ActiveCycles() int {
+	return int(activeCycles.Load())
+}
+
+// trackCycle counts a cycle as running until the returned func is called.
+func trackCycle() func() {
+	activeCycles.Add(1)
+	return func() { activeCycles.Add(-1) }
+}
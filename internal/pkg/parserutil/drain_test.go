@@ -0,0 +1,45 @@
+package parserutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func resetDrain() {
+	drainMu.Lock()
+	drainCh = make(chan struct{})
+	drainMu.Unlock()
+}
+
+func TestDrain(t *testing.T) {
+	defer resetDrain()
+
+	loopCtx, stop := DrainContext(context.Background())
+	defer stop()
+	if Draining() || loopCtx.Err() != nil {
+		t.Fatal("draining before BeginDrain")
+	}
+
+	done := trackCycle()
+	go func() {
+		time.Sleep(2 * drainPoll)
+		done()
+	}()
+	if !Drain(time.Second) {
+		t.Error("cycle not drained")
+	}
+	if !Draining() || ActiveCycles() != 0 {
+		t.Errorf("draining = %v, active cycles = %d", Draining(), ActiveCycles())
+	}
+	select {
+	case <-loopCtx.Done():
+	case <-time.After(time.Second):
+		t.Error("league loop context not canceled on drain")
+	}
+
+	defer trackCycle()()
+	if Drain(drainPoll) {
+		t.Error("drained with a cycle still running")
+	}
+}
@@ -0,0 +1,99 @@
+package parserutil
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// LeagueFilter applies parser.<name>.leagues include/exclude to league names, so cycles skip minor
+// leagues before spending requests and proxies on them. A nil *LeagueFilter allows every league.
+type LeagueFilter struct {
+	parser  string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewLeagueFilter compiles the patterns of cfg: case-insensitive regular expressions matched anywhere
+// in the league name ("Premier League", "^England\\."). An invalid pattern is logged and matched
+// literally. Returns nil when cfg has no patterns.
+func NewLeagueFilter(parser string, cfg config.LeagueFilterConfig) *LeagueFilter {
+	if len(cfg.Include) == 0 && len(cfg.Exclude) == 0 {
+		return nil
+	}
+	f := &LeagueFilter{
+		parser:  parser,
+		include: compileLeaguePatterns(parser, cfg.Include),
+		exclude: compileLeaguePatterns(parser, cfg.Exclude),
+	}
+	slog.Info("League filter enabled", "parser", parser, "include", cfg.Include, "exclude", cfg.Exclude)
+	return f
+}
+
+func compileLeaguePatterns(parser string, patterns []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			slog.Warn("Invalid league pattern, matching it literally", "parser", parser, "pattern", p, "error", err)
+			re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(p))
+		}
+		out = append(out, re)
+	}
+	return out
+}
+
+// Allows reports whether a league passes the filter. names are the league's names (e.g. localized
+// and English): the league is dropped if any of them matches exclude, and kept when include is
+// empty or any of them matches include.
+func (f *LeagueFilter) Allows(names ...string) bool {
+	if f == nil {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && matchesAny(f.exclude, name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && matchesAny(f.include, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLeagues returns the leagues allowed by f (in order); names returns the names of a league.
+// Apply it before LimitLeagues so max_leagues counts wanted leagues only.
+func FilterLeagues[T any](f *LeagueFilter, leagues []T, names func(T) []string) []T {
+	if f == nil {
+		return leagues
+	}
+	kept := make([]T, 0, len(leagues))
+	for _, l := range leagues {
+		if f.Allows(names(l)...) {
+			kept = append(kept, l)
+		}
+	}
+	if skipped := len(leagues) - len(kept); skipped > 0 {
+		slog.Info("Leagues skipped by league filter", "parser", f.parser, "leagues", len(leagues), "kept", len(kept), "skipped", skipped)
+	}
+	return kept
+}
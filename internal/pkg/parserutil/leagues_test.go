@@ -0,0 +1,65 @@
+package parserutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+func TestNewLeagueFilter_EmptyAllowsAll(t *testing.T) {
+	f := NewLeagueFilter("test", config.LeagueFilterConfig{})
+	if f != nil {
+		t.Fatalf("empty config: got %+v, want nil", f)
+	}
+	if !f.Allows("Anything") {
+		t.Fatal("nil filter must allow every league")
+	}
+}
+
+func TestLeagueFilter_Allows(t *testing.T) {
+	f := NewLeagueFilter("test", config.LeagueFilterConfig{
+		Include: []string{"premier league", "^Spain\\."},
+		Exclude: []string{"U21", "women"},
+	})
+	tests := []struct {
+		names []string
+		want  bool
+	}{
+		{[]string{"England. Premier League"}, true},
+		{[]string{"Spain. La Liga"}, true},
+		{[]string{"Italy. Serie A"}, false},
+		{[]string{"England. Premier League U21"}, false},                    // exclude wins
+		{[]string{"Англия. Премьер-лига", "England. Premier League"}, true}, // any name matches
+		{[]string{"Spain. Liga F", "Spain. Women"}, false},
+		{[]string{""}, false},
+	}
+	for _, tt := range tests {
+		if got := f.Allows(tt.names...); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestLeagueFilter_ExcludeOnlyAndInvalidPattern(t *testing.T) {
+	f := NewLeagueFilter("test", config.LeagueFilterConfig{Exclude: []string{"Cup (", " "}})
+	if !f.Allows("Premier League") {
+		t.Error("exclude-only filter must keep other leagues")
+	}
+	if f.Allows("FA Cup (Qualification)") {
+		t.Error("invalid pattern must be matched literally")
+	}
+}
+
+func TestFilterLeagues(t *testing.T) {
+	names := map[int]string{1: "Premier League", 2: "Premier League U21", 3: "Championship"}
+	f := NewLeagueFilter("test", config.LeagueFilterConfig{Include: []string{"premier"}, Exclude: []string{"u21"}})
+
+	got := FilterLeagues(f, []int{1, 2, 3}, func(id int) []string { return []string{names[id]} })
+	if want := []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterLeagues = %v, want %v", got, want)
+	}
+	if got := FilterLeagues(nil, []int{1, 2, 3}, nil); len(got) != 3 {
+		t.Errorf("nil filter dropped leagues: %v", got)
+	}
+}
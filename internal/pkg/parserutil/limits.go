@@ -0,0 +1,130 @@
+package parserutil
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+)
+
+// CycleLimits enforces the per-cycle guardrails parser.<name>.max_leagues and
+// parser.<name>.max_events_per_cycle, so one runaway league (e.g. virtuals with thousands
+// of events) can't blow cycle time and memory. Create one per cycle with NewCycleLimits
+// and call LogSummary when the cycle ends. Safe for concurrent use; a nil *CycleLimits
+// (or 0 limits) allows everything.
+type CycleLimits struct {
+	parser     string
+	maxEvents  int
+	maxLeagues int
+
+	mu              sync.Mutex
+	leagues         int // leagues admitted so far
+	events          int // events admitted so far
+	skippedEvents   int
+	skippedLeagues  int
+	truncatedLeague int  // leagues cut partially by the event cap
+	leaguesWarned   bool // truncation warnings already logged this cycle
+	warned          bool
+}
+
+// NewCycleLimits returns limits for one parsing cycle (0 = unlimited).
+func NewCycleLimits(parser string, maxEvents, maxLeagues int) *CycleLimits {
+	return &CycleLimits{parser: parser, maxEvents: maxEvents, maxLeagues: maxLeagues}
+}
+
+// Leagues reserves up to n leagues from the cycle's max_leagues budget and returns how many
+// may be processed. Parsers that page through leagues call it once per page. The kept leagues are
+// the leagues_fetched of the cycle on /stats.
+func (l *CycleLimits) Leagues(n int) int {
+	if l == nil || n <= 0 {
+		return n
+	}
+	if l.maxLeagues <= 0 {
+		cyclestats.Leagues(l.parser, n)
+		return n
+	}
+	l.mu.Lock()
+	kept := min(n, max(l.maxLeagues-l.leagues, 0))
+	l.leagues += kept
+	l.skippedLeagues += n - kept
+	warn := kept < n && !l.leaguesWarned
+	if kept < n {
+		l.leaguesWarned = true
+	}
+	l.mu.Unlock()
+	cyclestats.Leagues(l.parser, kept)
+
+	if warn {
+		slog.Warn("Leagues truncated by max_leagues", "parser", l.parser, "leagues", n, "kept", kept, "max_leagues", l.maxLeagues)
+	}
+	return kept
+}
+
+// Events reserves up to n events of a league from the cycle budget and returns how many
+// may be processed (0 once max_events_per_cycle is used up).
+func (l *CycleLimits) Events(league string, n int) int {
+	if l == nil || l.maxEvents <= 0 || n <= 0 {
+		return n
+	}
+	l.mu.Lock()
+	kept := min(n, max(l.maxEvents-l.events, 0))
+	l.events += kept
+	l.skippedEvents += n - kept
+	warn := kept < n && !l.warned
+	if kept < n {
+		l.warned = true
+		if kept > 0 {
+			l.truncatedLeague++
+		} else {
+			l.skippedLeagues++
+		}
+	}
+	l.mu.Unlock()
+
+	if warn {
+		slog.Warn("League truncated by max_events_per_cycle", "parser", l.parser, "league", league,
+			"events", n, "kept", kept, "max_events_per_cycle", l.maxEvents)
+	} else if kept < n {
+		slog.Debug("League events skipped, max_events_per_cycle reached", "parser", l.parser, "league", league,
+			"events", n, "kept", kept)
+	}
+	return kept
+}
+
+// Exhausted reports whether max_events_per_cycle is used up, so remaining leagues can be skipped
+// without fetching them (such leagues are not counted in the summary).
+func (l *CycleLimits) Exhausted() bool {
+	if l == nil || l.maxEvents <= 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.events >= l.maxEvents
+}
+
+// LogSummary logs what the guardrails cut in this cycle (nothing if the cycle was not truncated).
+func (l *CycleLimits) LogSummary() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.skippedEvents == 0 && l.skippedLeagues == 0 {
+		return
+	}
+	slog.Warn("Cycle truncated by guardrails", "parser", l.parser,
+		"events_processed", l.events, "events_skipped", l.skippedEvents,
+		"leagues_truncated", l.truncatedLeague, "leagues_skipped", l.skippedLeagues,
+		"max_events_per_cycle", l.maxEvents, "max_leagues", l.maxLeagues)
+}
+
+// LimitLeagues returns the leading part of leagues allowed by the remaining max_leagues budget.
+func LimitLeagues[T any](l *CycleLimits, leagues []T) []T {
+	return leagues[:l.Leagues(len(leagues))]
+}
+
+// LimitEvents returns the leading part of one league's events allowed by the remaining
+// max_events_per_cycle budget.
+func LimitEvents[T any](l *CycleLimits, league string, events []T) []T {
+	return events[:l.Events(league, len(events))]
+}
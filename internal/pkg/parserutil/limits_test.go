@@ -0,0 +1,50 @@
+package parserutil
+
+import "testing"
+
+func TestCycleLimits_LeaguesAndEventBudget(t *testing.T) {
+	l := NewCycleLimits("test", 5, 3)
+
+	leagues := LimitLeagues(l, []string{"a", "b"})
+	if len(leagues) != 2 {
+		t.Fatalf("expected 2 leagues, got %v", leagues)
+	}
+	// Second page: only one league left in the max_leagues budget
+	if leagues = LimitLeagues(l, []string{"c", "d"}); len(leagues) != 1 || leagues[0] != "c" {
+		t.Fatalf("expected only league c, got %v", leagues)
+	}
+
+	if n := l.Events("a", 3); n != 3 {
+		t.Fatalf("expected 3 events of a, got %d", n)
+	}
+	if events := LimitEvents(l, "b", []int{1, 2, 3, 4}); len(events) != 2 {
+		t.Fatalf("expected league b truncated to 2 events, got %v", events)
+	}
+	if !l.Exhausted() {
+		t.Fatal("expected event budget exhausted")
+	}
+	if n := l.Events("c", 10); n != 0 {
+		t.Fatalf("expected no events after exhaustion, got %d", n)
+	}
+
+	if l.events != 5 || l.skippedEvents != 12 || l.truncatedLeague != 1 || l.skippedLeagues != 2 {
+		t.Fatalf("unexpected counters: events=%d skipped=%d truncated=%d skipped_leagues=%d",
+			l.events, l.skippedEvents, l.truncatedLeague, l.skippedLeagues)
+	}
+}
+
+func TestCycleLimits_UnlimitedAndNil(t *testing.T) {
+	var nilLimits *CycleLimits
+	if got := LimitEvents(nilLimits, "x", make([]int, 1000)); len(got) != 1000 || nilLimits.Exhausted() {
+		t.Fatal("nil limits must allow everything")
+	}
+	nilLimits.LogSummary()
+
+	l := NewCycleLimits("test", 0, 0)
+	if got := LimitLeagues(l, make([]int, 500)); len(got) != 500 {
+		t.Fatalf("0 = unlimited leagues, got %d", len(got))
+	}
+	if n := l.Events("x", 10000); n != 10000 || l.Exhausted() {
+		t.Fatalf("0 = unlimited events, got %d", n)
+	}
+}
@@ -0,0 +1,105 @@
+package parserutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
+)
+
+// maxReportErrors caps ParseReport.Errors; LeaguesFailed still counts every failed league.
+const maxReportErrors = 20
+
+// LeagueError is the failure of one league; the run goes on with the other leagues.
+type LeagueError struct {
+	League string // league ID or name
+	Err    error
+}
+
+func (e *LeagueError) Error() string { return fmt.Sprintf("league %s: %v", e.League, e.Err) }
+
+func (e *LeagueError) Unwrap() error { return e.Err }
+
+// LeagueFailed wraps err as the failure of one league. Parsers return errors.Join of these
+// from a run (or cycle) that parsed the other leagues.
+func LeagueFailed(league string, err error) error {
+	return &LeagueError{League: league, Err: err}
+}
+
+// NewParseReport describes a run started at started that stored matches and returned err.
+// League errors (LeagueFailed) make the run partial; any other error with no matches stored
+// means the whole run failed.
+func NewParseReport(parser string, started time.Time, matches int, err error) interfaces.ParseReport {
+	finished := time.Now()
+	r := interfaces.ParseReport{
+		Parser:       parser,
+		Status:       interfaces.ParseStatusOK,
+		Started:      started,
+		Finished:     finished,
+		Duration:     finished.Sub(started).String(),
+		MatchesAdded: matches,
+	}
+	if err == nil {
+		return r
+	}
+	runFailed := false
+	for _, e := range flattenErrors(err) {
+		var le *LeagueError
+		if errors.As(e, &le) {
+			r.LeaguesFailed++
+		} else {
+			runFailed = true
+		}
+		if len(r.Errors) < maxReportErrors {
+			r.Errors = append(r.Errors, e.Error())
+		}
+	}
+	r.Status = interfaces.ParseStatusPartial
+	if runFailed && matches == 0 {
+		r.Status = interfaces.ParseStatusFailed
+	}
+	return r
+}
+
+// flattenErrors unpacks errors.Join trees into their leaf errors.
+func flattenErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var out []error
+	for _, e := range joined.Unwrap() {
+		out = append(out, flattenErrors(e)...)
+	}
+	return out
+}
+
+// ParseOnceReport runs one ParseOnce of p and records its report for /stats. Parsers that don't
+// implement interfaces.ReportingParser are described by their ParseOnce error alone. The run counts as a
+// cycle in progress for Drain.
+func ParseOnceReport(ctx context.Context, p interfaces.Parser) (interfaces.ParseReport, error) {
+	defer trackCycle()()
+	ctx, span := tracing.Start(ctx, "parse.once", attribute.String("parser", p.GetName()))
+	cyclestats.Begin(p.GetName())
+	var report interfaces.ParseReport
+	var err error
+	if rp, ok := p.(interfaces.ReportingParser); ok {
+		report, err = rp.ParseOnceWithReport(ctx)
+	} else {
+		started := time.Now()
+		err = p.ParseOnce(ctx)
+		report = NewParseReport(p.GetName(), started, 0, err)
+	}
+	cyclestats.Fill(&report)
+	health.RecordParseReport(report)
+	span.SetAttributes(attribute.Int("matches", report.MatchesAdded), attribute.String("status", report.Status))
+	tracing.End(span, err)
+	return report, err
+}
@@ -0,0 +1,69 @@
+package parserutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+func TestNewParseReport(t *testing.T) {
+	started := time.Now()
+	notFound := errors.New("404")
+
+	ok := NewParseReport("olimp", started, 10, nil)
+	if ok.Status != interfaces.ParseStatusOK || ok.MatchesAdded != 10 || len(ok.Errors) != 0 {
+		t.Fatalf("ok run: %+v", ok)
+	}
+
+	partial := NewParseReport("olimp", started, 10, errors.Join(LeagueFailed("42", notFound), LeagueFailed("43", notFound)))
+	if partial.Status != interfaces.ParseStatusPartial || partial.LeaguesFailed != 2 || len(partial.Errors) != 2 {
+		t.Fatalf("partial run: %+v", partial)
+	}
+	if partial.Errors[0] != "league 42: 404" {
+		t.Errorf("error message %q", partial.Errors[0])
+	}
+	// League failures stay partial even when no league succeeded
+	if r := NewParseReport("olimp", started, 0, LeagueFailed("42", notFound)); r.Status != interfaces.ParseStatusPartial {
+		t.Errorf("league-only failure: %+v", r)
+	}
+
+	down := NewParseReport("olimp", started, 0, fmt.Errorf("sports-with-competitions: %w", errors.New("connection refused")))
+	if down.Status != interfaces.ParseStatusFailed || down.LeaguesFailed != 0 {
+		t.Fatalf("failed run: %+v", down)
+	}
+
+	var many []error
+	for i := 0; i < maxReportErrors+5; i++ {
+		many = append(many, LeagueFailed(fmt.Sprint(i), notFound))
+	}
+	capped := NewParseReport("olimp", started, 1, errors.Join(many...))
+	if capped.LeaguesFailed != maxReportErrors+5 || len(capped.Errors) != maxReportErrors {
+		t.Errorf("capped report: leagues_failed=%d errors=%d", capped.LeaguesFailed, len(capped.Errors))
+	}
+}
+
+type failingParser struct{ fakeParser }
+
+func (p *failingParser) ParseOnce(ctx context.Context) error { return errors.New("down") }
+
+func TestParseOnceReport_RecordsStats(t *testing.T) {
+	p := &failingParser{fakeParser{name: "ReportTestParser"}}
+	report, err := ParseOnceReport(context.Background(), p)
+	if err == nil || report.Status != interfaces.ParseStatusFailed {
+		t.Fatalf("got %+v, %v", report, err)
+	}
+	for _, st := range health.ParseStats() {
+		if st.Parser == "ReportTestParser" {
+			if st.Runs != 1 || st.FailedRuns != 1 || st.LastSuccess != nil {
+				t.Fatalf("unexpected stats: %+v", st)
+			}
+			return
+		}
+	}
+	t.Fatal("report not recorded")
+}
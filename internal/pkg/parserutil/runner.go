@@ -7,8 +7,13 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/cyclestats"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/eventlog"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/health"
 	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
 )
 
 // ParserFunc is a function that runs a parser and returns an error
@@ -87,8 +92,16 @@ type IncrementalParserState struct {
 	Ctx           context.Context
 	Cancel        context.CancelFunc
 	CycleTrigger  chan struct{}
+
+	// resumeCh is non-nil while paused; closed by Resume
+	resumeCh  chan struct{}
+	lastCycle interfaces.CycleSummary
 }
 
+// CycleFunc runs one incremental parsing cycle and returns the number of matches stored.
+// Several independent failures within a cycle can be returned with errors.Join.
+type CycleFunc func(ctx context.Context, timeout time.Duration) (int, error)
+
 // NewIncrementalParserState creates a new incremental parser state
 func NewIncrementalParserState(ctx context.Context) *IncrementalParserState {
 	incCtx, cancel := context.WithCancel(ctx)
@@ -160,6 +173,107 @@ func (s *IncrementalParserState) TriggerNewCycle(parserName string) error {
 	}
 }
 
+// Pause stops the loop from starting new cycles; the cycle in progress finishes normally.
+func (s *IncrementalParserState) Pause(parserName string) error {
+	if s == nil {
+		return fmt.Errorf("incremental parsing not started")
+	}
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if s.resumeCh == nil {
+		s.resumeCh = make(chan struct{})
+		slog.Info("Incremental parsing paused", "parser", parserName)
+	}
+	return nil
+}
+
+// Resume lets the loop start cycles again; a new cycle starts immediately.
+func (s *IncrementalParserState) Resume(parserName string) error {
+	if s == nil {
+		return fmt.Errorf("incremental parsing not started")
+	}
+	s.Mu.Lock()
+	if s.resumeCh != nil {
+		close(s.resumeCh)
+		s.resumeCh = nil
+		slog.Info("Incremental parsing resumed", "parser", parserName)
+	}
+	s.Mu.Unlock()
+	return s.TriggerNewCycle(parserName)
+}
+
+// IsPaused reports whether the loop is paused (false if incremental parsing is not started).
+func (s *IncrementalParserState) IsPaused() bool {
+	if s == nil {
+		return false
+	}
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	return s.resumeCh != nil
+}
+
+// LastCycle returns the summary of the last finished cycle (zero value if none yet).
+func (s *IncrementalParserState) LastCycle() interfaces.CycleSummary {
+	if s == nil {
+		return interfaces.CycleSummary{}
+	}
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	return s.lastCycle
+}
+
+// waitWhilePaused blocks while the loop is paused. Returns false if ctx is done.
+func (s *IncrementalParserState) waitWhilePaused(ctx context.Context) bool {
+	s.Mu.Lock()
+	ch := s.resumeCh
+	s.Mu.Unlock()
+	if ch == nil {
+		return true
+	}
+	select {
+	case <-ch:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// recordCycle stores the summary of a finished cycle.
+func (s *IncrementalParserState) recordCycle(cycleID int64, started time.Time, matches int, err error) {
+	finished := time.Now()
+	summary := interfaces.CycleSummary{
+		CycleID:  cycleID,
+		Started:  started,
+		Finished: finished,
+		Duration: finished.Sub(started).String(),
+		Matches:  matches,
+	}
+	if err != nil {
+		summary.Errors = 1
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			summary.Errors = len(joined.Unwrap())
+		}
+		summary.LastError = err.Error()
+	}
+	s.Mu.Lock()
+	s.lastCycle = summary
+	s.Mu.Unlock()
+}
+
+// recordCycleEvent adds the finished cycle to the event log.
+func recordCycleEvent(parserName string, c interfaces.CycleSummary) {
+	fields := map[string]interface{}{
+		"cycle_id": c.CycleID,
+		"duration": c.Duration,
+		"matches":  c.Matches,
+	}
+	if c.Errors > 0 {
+		fields["errors"] = c.Errors
+		fields["last_error"] = c.LastError
+	}
+	eventlog.Record(eventlog.Event{Type: eventlog.TypeParserCycle, At: c.Finished.UTC(), Parser: parserName, Fields: fields})
+}
+
 // CreateCycleContext creates a context for a parsing cycle with optional timeout
 func CreateCycleContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout > 0 {
@@ -201,7 +315,10 @@ func LogIncrementalLoopStop(parserName string, totalCycles int) {
 // After each cycle completes, immediately triggers the next cycle (no delay)
 // For incremental parsing, matches are not cleared at cycle start - new matches
 // update existing ones via mergeMatchInto, ensuring data remains available during processing
-func RunIncrementalLoop(ctx context.Context, timeout time.Duration, parserName string, state *IncrementalParserState, cycleFunc func(context.Context, time.Duration)) {
+// While paused (see IncrementalParserState.Pause) triggers wait until Resume.
+// Once draining (BeginDrain) the loop finishes the cycle in progress and starts no new one.
+// Each cycle's result is recorded and available via IncrementalParserState.LastCycle.
+func RunIncrementalLoop(ctx context.Context, timeout time.Duration, parserName string, state *IncrementalParserState, cycleFunc CycleFunc) {
 	LogIncrementalLoopStart(parserName, timeout)
 	cycleCount := 0
 	
@@ -219,6 +336,18 @@ func RunIncrementalLoop(ctx context.Context, timeout time.Duration, parserName s
 			LogIncrementalLoopStop(parserName, cycleCount)
 			return
 		case <-state.CycleTrigger:
+			if !state.waitWhilePaused(ctx) {
+				LogIncrementalLoopStop(parserName, cycleCount)
+				return
+			}
+			// Counted before the drain check so Drain can't miss a cycle that is starting
+			cycleDone := trackCycle()
+			if Draining() {
+				cycleDone()
+				slog.Info("Draining, no new cycle", "parser", parserName)
+				LogIncrementalLoopStop(parserName, cycleCount)
+				return
+			}
 			cycleCount++
 			slog.Info("Received cycle trigger", "parser", parserName, "cycle_number", cycleCount)
 			
@@ -228,9 +357,24 @@ func RunIncrementalLoop(ctx context.Context, timeout time.Duration, parserName s
 			// ClearMatchesBeforeCycle is skipped for incremental mode
 			
 			// Run the cycle to process new matches
-			cycleFunc(ctx, timeout)
+			started := time.Now()
+			cyclestats.Begin(parserName)
+			cycleCtx, span := tracing.Start(ctx, "parse.cycle", attribute.String("parser", parserName), attribute.Int("cycle", cycleCount))
+			matches, err := cycleFunc(cycleCtx, timeout)
+			span.SetAttributes(attribute.Int("matches", matches))
+			tracing.End(span, err)
+			state.recordCycle(int64(cycleCount), started, matches, err)
+			recordCycleEvent(parserName, state.LastCycle())
+			report := NewParseReport(parserName, started, matches, err)
+			cyclestats.Fill(&report)
+			health.RecordParseReport(report)
+			// Warm-up snapshot matches are served only until a cycle delivers fresh data
+			if matches > 0 {
+				health.ParserCycleDone(parserName)
+			}
+			cycleDone()
 			
-			slog.Info("Cycle completed, triggering next cycle immediately", "parser", parserName, "cycle_number", cycleCount)
+			slog.Info("Cycle completed, triggering next cycle immediately", "parser", parserName, "cycle_number", cycleCount, "matches", matches, "error", err)
 			
 			// Immediately trigger next cycle (no delay)
 			// Use goroutine to avoid blocking if channel is full
@@ -0,0 +1,73 @@
+package parserutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunIncrementalLoop_PauseResumeAndLastCycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	state := NewIncrementalParserState(ctx)
+	if err := state.Pause("test"); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if !state.IsPaused() {
+		t.Fatal("expected paused state")
+	}
+
+	var calls atomic.Int32
+	cycle := func(ctx context.Context, timeout time.Duration) (int, error) {
+		calls.Add(1)
+		return 7, errors.Join(errors.New("league 1"), errors.New("league 2"))
+	}
+	go RunIncrementalLoop(state.Ctx, 0, "test", state, cycle)
+
+	time.Sleep(50 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("expected no cycles while paused, got %d", n)
+	}
+	if last := state.LastCycle(); !last.Started.IsZero() {
+		t.Fatalf("expected empty last cycle, got %+v", last)
+	}
+
+	if err := state.Resume("test"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for state.LastCycle().Started.IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("no cycle recorded after Resume")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = state.Pause("test")
+
+	last := state.LastCycle()
+	if last.Matches != 7 || last.Errors != 2 || last.LastError == "" || last.CycleID < 1 {
+		t.Fatalf("unexpected last cycle: %+v", last)
+	}
+	if last.Finished.Before(last.Started) {
+		t.Fatalf("finished before started: %+v", last)
+	}
+}
+
+func TestIncrementalParserState_NilSafe(t *testing.T) {
+	var s *IncrementalParserState
+	if s.IsPaused() {
+		t.Fatal("nil state must not be paused")
+	}
+	if err := s.Pause("test"); err == nil {
+		t.Fatal("expected error pausing nil state")
+	}
+	if err := s.Resume("test"); err == nil {
+		t.Fatal("expected error resuming nil state")
+	}
+	if !s.LastCycle().Started.IsZero() {
+		t.Fatal("expected zero summary")
+	}
+}
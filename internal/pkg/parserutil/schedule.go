@@ -0,0 +1,122 @@
+package parserutil
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+// DefaultSportsKey is the parser.sports entry used for parsers that are not listed by name.
+const DefaultSportsKey = "default"
+
+// SportSchedule polls one (parser, sport) pair every Interval.
+// Sport is empty when the parser is polled as a whole with ParseOnce.
+type SportSchedule struct {
+	Name     string // parser key in parser.sports (e.g. "fonbet", "xbet1")
+	Parser   interfaces.Parser
+	Sport    string
+	Interval time.Duration
+}
+
+// BuildSportSchedules expands parser.sports into schedules for parsers (keyed like parser.sports).
+// Parsers without sports entries get one ParseOnce schedule every interval; parsers that can't
+// parse a single sport (not interfaces.SportParser) get one ParseOnce schedule with the shortest
+// interval of their sports.
+func BuildSportSchedules(parsers map[string]interfaces.Parser, sports map[string][]config.SportScheduleConfig, interval time.Duration) []SportSchedule {
+	byName := make(map[string][]config.SportScheduleConfig, len(sports))
+	for name, entries := range sports {
+		byName[strings.ToLower(strings.TrimSpace(name))] = entries
+	}
+
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var schedules []SportSchedule
+	for _, name := range names {
+		p := parsers[name]
+		entries, ok := byName[strings.ToLower(name)]
+		if !ok {
+			entries = byName[DefaultSportsKey]
+		}
+		whole := SportSchedule{Name: name, Parser: p, Interval: interval}
+		if len(entries) == 0 {
+			schedules = append(schedules, whole)
+			continue
+		}
+
+		_, perSport := p.(interfaces.SportParser)
+		seen := make(map[string]bool, len(entries))
+		whole.Interval = 0
+		for _, e := range entries {
+			sport := strings.ToLower(strings.TrimSpace(e.Sport))
+			every := e.Interval
+			if every <= 0 {
+				every = interval
+			}
+			if sport == "" || seen[sport] {
+				continue
+			}
+			seen[sport] = true
+			if perSport {
+				schedules = append(schedules, SportSchedule{Name: name, Parser: p, Sport: sport, Interval: every})
+			} else if whole.Interval == 0 || every < whole.Interval {
+				whole.Interval = every
+			}
+		}
+		if !perSport && whole.Interval > 0 {
+			slog.Info("Parser can't poll single sports, polling all sports with the shortest interval",
+				"parser", name, "interval", whole.Interval)
+			schedules = append(schedules, whole)
+		}
+	}
+	return schedules
+}
+
+// RunSportSchedules polls every schedule on its own ticker until ctx is done; each run is limited by timeout.
+// A run that outlasts its interval delays the next tick of that pair only.
+func RunSportSchedules(ctx context.Context, schedules []SportSchedule, timeout time.Duration) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for _, s := range schedules {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(s.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := RunSportScheduleOnce(ctx, s, timeout); err != nil && ctx.Err() == nil {
+						slog.Error("Periodic parsing failed", "parser", s.Parser.GetName(), "sport", s.Sport, "error", err)
+					}
+				}
+			}
+		}()
+	}
+	return &wg
+}
+
+// RunSportScheduleOnce runs one pass of s: ParseSport for a sport schedule, ParseOnce otherwise.
+func RunSportScheduleOnce(ctx context.Context, s SportSchedule, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if sp, ok := s.Parser.(interfaces.SportParser); ok && s.Sport != "" {
+		return sp.ParseSport(ctx, s.Sport)
+	}
+	_, err := ParseOnceReport(ctx, s.Parser)
+	return err
+}
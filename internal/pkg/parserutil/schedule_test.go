@@ -0,0 +1,121 @@
+package parserutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+)
+
+type fakeParser struct {
+	name string
+	mu   sync.Mutex
+	runs map[string]int // sport -> runs ("" = ParseOnce)
+}
+
+func (p *fakeParser) Start(ctx context.Context) error { return nil }
+func (p *fakeParser) Stop() error                     { return nil }
+func (p *fakeParser) GetName() string                 { return p.name }
+func (p *fakeParser) ParseOnce(ctx context.Context) error {
+	p.record("")
+	return nil
+}
+
+func (p *fakeParser) record(sport string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.runs == nil {
+		p.runs = map[string]int{}
+	}
+	p.runs[sport]++
+}
+
+func (p *fakeParser) count(sport string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.runs[sport]
+}
+
+type fakeSportParser struct{ fakeParser }
+
+func (p *fakeSportParser) ParseSport(ctx context.Context, sport string) error {
+	p.record(sport)
+	return nil
+}
+
+func TestBuildSportSchedules(t *testing.T) {
+	fonbet := &fakeSportParser{fakeParser{name: "fonbet"}}
+	zenit := &fakeParser{name: "zenit"}
+	leon := &fakeParser{name: "leon"}
+	sports := map[string][]config.SportScheduleConfig{
+		"Fonbet": {
+			{Sport: "football", Interval: 2 * time.Minute},
+			{Sport: "cs", Interval: time.Minute},
+			{Sport: "cs", Interval: 5 * time.Minute}, // duplicate, ignored
+			{Sport: "tennis"},                        // parser.interval
+		},
+		"zenit": {
+			{Sport: "football", Interval: 2 * time.Minute},
+			{Sport: "dota2", Interval: time.Minute},
+		},
+	}
+
+	got := BuildSportSchedules(map[string]interfaces.Parser{"fonbet": fonbet, "zenit": zenit, "leon": leon}, sports, 3*time.Minute)
+	want := []struct {
+		name, sport string
+		interval    time.Duration
+	}{
+		{"fonbet", "football", 2 * time.Minute},
+		{"fonbet", "cs", time.Minute},
+		{"fonbet", "tennis", 3 * time.Minute},
+		{"leon", "", 3 * time.Minute}, // not listed, no default
+		{"zenit", "", time.Minute},    // no single-sport parsing: shortest interval
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d schedules, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].Sport != w.sport || got[i].Interval != w.interval {
+			t.Errorf("schedule %d = %s/%s every %v, want %s/%s every %v",
+				i, got[i].Name, got[i].Sport, got[i].Interval, w.name, w.sport, w.interval)
+		}
+	}
+
+	// "default" applies to parsers without their own entry
+	sports[DefaultSportsKey] = []config.SportScheduleConfig{{Sport: "football", Interval: 4 * time.Minute}}
+	got = BuildSportSchedules(map[string]interfaces.Parser{"leon": leon}, sports, 3*time.Minute)
+	if len(got) != 1 || got[0].Sport != "" || got[0].Interval != 4*time.Minute {
+		t.Fatalf("default entry not applied: %+v", got)
+	}
+}
+
+func TestRunSportSchedules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fonbet := &fakeSportParser{fakeParser{name: "fonbet"}}
+	zenit := &fakeParser{name: "zenit"}
+	wg := RunSportSchedules(ctx, []SportSchedule{
+		{Name: "fonbet", Parser: fonbet, Sport: "cs", Interval: 10 * time.Millisecond},
+		{Name: "fonbet", Parser: fonbet, Sport: "football", Interval: time.Hour},
+		{Name: "zenit", Parser: zenit, Interval: 10 * time.Millisecond},
+	}, time.Second)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for fonbet.count("cs") < 3 || zenit.count("") < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("schedules not polled: cs=%d zenit=%d", fonbet.count("cs"), zenit.count(""))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	if n := fonbet.count("football"); n != 0 {
+		t.Errorf("football polled %d times before its interval", n)
+	}
+	if n := fonbet.count(""); n != 0 {
+		t.Errorf("sport parser polled with ParseOnce %d times", n)
+	}
+}
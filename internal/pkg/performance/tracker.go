@@ -284,7 +284,7 @@ func min(a, b int) int {
 	return b
 }
 
-// MetricsResponse represents the JSON response structure for /metrics endpoint
+// MetricsResponse represents the JSON response structure for /metrics/performance endpoint
 type MetricsResponse struct {
 	Overall struct {
 		TotalRuns     int `json:"total_runs"`
@@ -0,0 +1,385 @@
+// Package proxypool is the proxy rotation shared by all parsers.
+//
+// Every proxy URL from any parser's proxy_list is tracked once per process: success rate,
+// latency (moving average) and consecutive failures. A proxy that fails several times in a row
+// is banned for a while. Parsers get proxies in order via Pool.Candidates: the sticky proxy of
+// the session first (the one that worked last), then the rest in weighted random order
+// (weight = success rate / latency), so healthy fast proxies get most of the traffic.
+package proxypool
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/circuitbreaker"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+)
+
+const (
+	DefaultFailuresBeforeBan = 3
+	DefaultBanDuration       = 5 * time.Minute
+
+	// latencyAlpha is the weight of the newest sample in the latency moving average.
+	latencyAlpha = 0.3
+)
+
+// Proxy is one proxy URL with its statistics. Shared between all pools that list it.
+type Proxy struct {
+	raw    string
+	url    *url.URL
+	masked string
+
+	mu          sync.Mutex
+	successes   int64
+	failures    int64
+	consecutive int
+	latency     time.Duration
+	bannedUntil time.Time
+	lastError   string
+	lastUsed    time.Time
+	pools       map[string]bool
+	transports  map[TransportOptions]*http.Transport
+}
+
+// String returns the proxy URL with the password masked (safe for logs).
+func (p *Proxy) String() string {
+	return p.masked
+}
+
+// TransportOptions are the per-parser transport settings; transports are cached per distinct options.
+type TransportOptions struct {
+	InsecureTLS        bool   // skip TLS verification (<PARSER>_INSECURE_TLS=1)
+	DisableCompression bool   // the parser sends Accept-Encoding and decodes bodies itself
+	Parser             string // counts the requests in the parser's cycle statistics (circuitbreaker.WrapParser)
+}
+
+// Client returns an HTTP client sending requests through this proxy. Transports are cached
+// per proxy, so connections are reused between requests; requests still go through circuit breakers.
+func (p *Proxy) Client(timeout time.Duration, opts TransportOptions) *http.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tr, ok := p.transports[opts]
+	if !ok {
+		tr = http.DefaultTransport.(*http.Transport).Clone()
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		tr.TLSClientConfig.InsecureSkipVerify = opts.InsecureTLS
+		tr.DisableCompression = opts.DisableCompression
+		tr.Proxy = http.ProxyURL(p.url)
+		p.transports[opts] = tr
+	}
+	return &http.Client{Timeout: timeout, Transport: circuitbreaker.WrapParser(opts.Parser, tr)}
+}
+
+func (p *Proxy) banned(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.Before(p.bannedUntil)
+}
+
+// weight is the rotation weight: smoothed success rate divided by latency in seconds (min 0.1s).
+func (p *Proxy) weight() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rate := float64(p.successes+1) / float64(p.successes+p.failures+2)
+	lat := p.latency.Seconds()
+	if lat < 0.1 {
+		lat = 0.1
+	}
+	return rate / lat
+}
+
+// Status is a point-in-time view of a proxy (exposed on /proxies).
+type Status struct {
+	Proxy        string    `json:"proxy"`
+	Pools        []string  `json:"pools"`
+	Successes    int64     `json:"successes"`
+	Failures     int64     `json:"failures"`
+	SuccessRate  float64   `json:"success_rate"`
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	Banned       bool      `json:"banned"`
+	BannedUntil  time.Time `json:"banned_until,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastUsed     time.Time `json:"last_used,omitempty"`
+}
+
+// Status returns the current statistics of the proxy.
+func (p *Proxy) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := Status{
+		Proxy:        p.masked,
+		Successes:    p.successes,
+		Failures:     p.failures,
+		AvgLatencyMs: p.latency.Milliseconds(),
+		LastError:    p.lastError,
+		LastUsed:     p.lastUsed,
+	}
+	if total := p.successes + p.failures; total > 0 {
+		s.SuccessRate = float64(p.successes) / float64(total)
+	}
+	if time.Now().Before(p.bannedUntil) {
+		s.Banned = true
+		s.BannedUntil = p.bannedUntil
+	}
+	for name := range p.pools {
+		s.Pools = append(s.Pools, name)
+	}
+	sort.Strings(s.Pools)
+	return s
+}
+
+// Pool is a parser's view of the shared proxies from its proxy_list. A nil *Pool has no proxies.
+type Pool struct {
+	name    string
+	proxies []*Proxy
+
+	mu     sync.Mutex
+	sticky map[string]*Proxy // session -> proxy that worked last
+	rnd    *rand.Rand
+}
+
+// New returns the pool for a parser. Invalid URLs are skipped; returns nil if none are left.
+func New(name string, list []string) *Pool {
+	var proxies []*Proxy
+	seen := make(map[string]bool, len(list))
+	for _, raw := range list {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		px := register(name, raw)
+		if px == nil {
+			slog.Warn("Proxy pool: invalid proxy URL skipped", "pool", name, "proxy", Mask(raw))
+			continue
+		}
+		proxies = append(proxies, px)
+	}
+	if len(proxies) == 0 {
+		return nil
+	}
+	slog.Info("Proxy pool created", "pool", name, "proxy_count", len(proxies))
+	return &Pool{
+		name:    name,
+		proxies: proxies,
+		sticky:  make(map[string]*Proxy),
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Len returns the number of proxies in the pool.
+func (p *Pool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.proxies)
+}
+
+// Candidates returns proxies to try, in order: the sticky proxy of the session (if sticky
+// sessions are enabled and it isn't banned), then the other non-banned proxies in weighted
+// random order. Empty when every proxy is banned — callers fall back to a direct connection.
+func (p *Pool) Candidates(session string) []*Proxy {
+	if p == nil {
+		return nil
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var first *Proxy
+	if stickyEnabled() {
+		if px := p.sticky[session]; px != nil && !px.banned(now) {
+			first = px
+		}
+	}
+
+	type weighted struct {
+		px *Proxy
+		w  float64
+	}
+	rest := make([]weighted, 0, len(p.proxies))
+	for _, px := range p.proxies {
+		if px == first || px.banned(now) {
+			continue
+		}
+		rest = append(rest, weighted{px, px.weight()})
+	}
+
+	out := make([]*Proxy, 0, len(rest)+1)
+	if first != nil {
+		out = append(out, first)
+	}
+	// Weighted sampling without replacement.
+	for len(rest) > 0 {
+		var total float64
+		for _, r := range rest {
+			total += r.w
+		}
+		pick := len(rest) - 1
+		x := p.rnd.Float64() * total
+		for i, r := range rest {
+			if x < r.w {
+				pick = i
+				break
+			}
+			x -= r.w
+		}
+		out = append(out, rest[pick].px)
+		rest = append(rest[:pick], rest[pick+1:]...)
+	}
+	return out
+}
+
+// Success records a working request through px and makes it sticky for the session.
+func (p *Pool) Success(px *Proxy, session string, latency time.Duration) {
+	if p == nil || px == nil {
+		return
+	}
+	px.mu.Lock()
+	px.successes++
+	px.consecutive = 0
+	px.lastUsed = time.Now()
+	if px.latency == 0 {
+		px.latency = latency
+	} else {
+		px.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(px.latency))
+	}
+	px.mu.Unlock()
+
+	p.mu.Lock()
+	if p.sticky[session] != px {
+		slog.Info("Proxy pool: using working proxy", "pool", p.name, "session", session, "proxy", px.masked)
+		p.sticky[session] = px
+	}
+	p.mu.Unlock()
+}
+
+// Failure records a failed request through px (network error, blocked or invalid response).
+// After failures_before_ban consecutive failures the proxy is banned for ban_duration in all pools.
+func (p *Pool) Failure(px *Proxy, session string, reason string) {
+	if p == nil || px == nil {
+		return
+	}
+	failuresBeforeBan, banDuration := banSettings()
+	metrics.ProxyFailures.WithLabelValues(p.name).Inc()
+
+	px.mu.Lock()
+	px.failures++
+	px.consecutive++
+	px.lastError = reason
+	px.lastUsed = time.Now()
+	if px.consecutive >= failuresBeforeBan {
+		px.bannedUntil = time.Now().Add(banDuration)
+		px.consecutive = 0
+		slog.Warn("Proxy pool: proxy banned", "pool", p.name, "proxy", px.masked, "ban_duration", banDuration, "last_error", reason)
+	}
+	px.mu.Unlock()
+
+	p.mu.Lock()
+	if p.sticky[session] == px {
+		delete(p.sticky, session)
+	}
+	p.mu.Unlock()
+}
+
+var (
+	registryMu        sync.Mutex
+	registry          = map[string]*Proxy{}
+	failuresBeforeBan = DefaultFailuresBeforeBan
+	banDuration       = DefaultBanDuration
+	sticky            = true
+)
+
+// Configure applies parser.proxy_pool settings. Zero values keep the defaults.
+func Configure(cfg config.ProxyPoolConfig) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if cfg.FailuresBeforeBan > 0 {
+		failuresBeforeBan = cfg.FailuresBeforeBan
+	}
+	if cfg.BanDuration > 0 {
+		banDuration = cfg.BanDuration
+	}
+	sticky = !cfg.DisableSticky
+	slog.Info("Proxy pool configured", "failures_before_ban", failuresBeforeBan, "ban_duration", banDuration, "sticky", sticky)
+}
+
+func banSettings() (int, time.Duration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return failuresBeforeBan, banDuration
+}
+
+func stickyEnabled() bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return sticky
+}
+
+// register returns the shared Proxy for a URL and records that the pool uses it.
+func register(pool, raw string) *Proxy {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	px, ok := registry[raw]
+	if !ok {
+		px = &Proxy{
+			raw:        raw,
+			url:        u,
+			masked:     Mask(raw),
+			pools:      map[string]bool{},
+			transports: map[TransportOptions]*http.Transport{},
+		}
+		registry[raw] = px
+	}
+	px.mu.Lock()
+	px.pools[pool] = true
+	px.mu.Unlock()
+	return px
+}
+
+// Statuses returns the state of every known proxy, sorted by masked URL.
+func Statuses() []Status {
+	registryMu.Lock()
+	list := make([]*Proxy, 0, len(registry))
+	for _, px := range registry {
+		list = append(list, px)
+	}
+	registryMu.Unlock()
+
+	out := make([]Status, 0, len(list))
+	for _, px := range list {
+		out = append(out, px.Status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Proxy < out[j].Proxy })
+	return out
+}
+
+// Mask hides the password of a proxy URL for logging.
+func Mask(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "***"
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			// url.UserPassword would escape the asterisks, so insert them after encoding.
+			u.User = url.User(u.User.Username())
+			return strings.Replace(u.String(), "@", ":***@", 1)
+		}
+	}
+	return u.String()
+}
@@ -0,0 +1,138 @@
+// Package ratelimit provides token-bucket limiters shared by parser HTTP clients.
+//
+// Each parser gets one limiter (see ForParser), configured via parser.<name>.rate_limit.rps/burst,
+// so load per bookmaker can be tuned from config without code edits.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Limiter is a token-bucket rate limiter. A nil *Limiter is valid and never blocks.
+type Limiter struct {
+	mu           sync.Mutex
+	rps          float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// New creates a limiter allowing rps requests per second with the given burst.
+// Returns nil (unlimited) when rps <= 0. Burst < 1 is treated as 1.
+func New(rps float64, burst int) *Limiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rps:    rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var wait time.Duration
+		if now.Before(l.blockedUntil) {
+			wait = l.blockedUntil.Sub(now)
+		} else {
+			l.refill(now)
+			if l.tokens >= 1 {
+				l.tokens--
+				l.mu.Unlock()
+				return nil
+			}
+			wait = time.Duration((1 - l.tokens) / l.rps * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff blocks all callers for d (e.g. after HTTP 429) and drains accumulated tokens.
+func (l *Limiter) Backoff(d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := time.Now().Add(d)
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+	l.tokens = 0
+	l.last = until
+}
+
+// Rate returns configured requests per second and burst (0, 0 for a nil limiter).
+func (l *Limiter) Rate() (rps float64, burst int) {
+	if l == nil {
+		return 0, 0
+	}
+	return l.rps, int(l.burst)
+}
+
+func (l *Limiter) refill(now time.Time) {
+	if now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rps
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Limiter{}
+)
+
+// ForParser returns the shared limiter for a parser, creating it on first use.
+// cfg comes from parser.<name>.rate_limit; def is used when cfg.RPS is not set.
+// All HTTP clients of the same parser share one limiter so the bookmaker sees a single request rate.
+func ForParser(name string, cfg config.RateLimitConfig, def config.RateLimitConfig) *Limiter {
+	n := strings.ToLower(strings.TrimSpace(name))
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if l, ok := registry[n]; ok {
+		return l
+	}
+
+	if cfg.RPS <= 0 {
+		cfg = def
+	}
+	l := New(cfg.RPS, cfg.Burst)
+	if l != nil {
+		slog.Info("Rate limiter configured", "parser", n, "rps", l.rps, "burst", int(l.burst))
+	}
+	registry[n] = l
+	return l
+}
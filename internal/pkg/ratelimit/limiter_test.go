@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_BurstThenThrottle(t *testing.T) {
+	l := New(20, 2) // 50ms per token after burst
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("third request should wait for refill, elapsed=%v", elapsed)
+	}
+}
+
+func TestLimiter_NilIsUnlimited(t *testing.T) {
+	var l *Limiter
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("nil limiter Wait: %v", err)
+	}
+	l.Backoff(time.Second)
+	if New(0, 5) != nil {
+		t.Error("New with rps=0 should return nil")
+	}
+}
+
+func TestLimiter_BackoffRespectsContext(t *testing.T) {
+	l := New(100, 1)
+	l.Backoff(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("expected context error while backing off")
+	}
+}
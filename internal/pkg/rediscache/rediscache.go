@@ -0,0 +1,192 @@
+// Package rediscache is a minimal Redis client (RESP over TCP) for caching responses shared between
+// calculator replicas: GET, SET with expiry and PING on one reused connection.
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultTimeout bounds a command when the context has no deadline: the cache must never be slower
+// than the query it saves.
+const defaultTimeout = 500 * time.Millisecond
+
+// Client sends commands to one Redis server. Safe for concurrent use; commands are serialized on a
+// single connection, which is re-dialed after any error.
+type Client struct {
+	addr     string
+	password string
+	db       int
+	prefix   string
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// New returns a client of the server at addr (host:port). Keys are prefixed with prefix. The
+// connection is dialed on the first command.
+func New(addr, password string, db int, prefix string) *Client {
+	return &Client{addr: addr, password: password, db: db, prefix: prefix}
+}
+
+// Get returns the value of key; ok is false when the key doesn't exist or has expired.
+func (c *Client) Get(ctx context.Context, key string) (value []byte, ok bool, err error) {
+	reply, err := c.do(ctx, "GET", c.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	b, isBulk := reply.([]byte)
+	if !isBulk {
+		return nil, false, fmt.Errorf("unexpected GET reply %v", reply)
+	}
+	return b, true, nil
+}
+
+// Set stores value under key for ttl.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		return fmt.Errorf("invalid ttl %s", ttl)
+	}
+	_, err := c.do(ctx, "SET", c.prefix+key, string(value), "PX", strconv.FormatInt(ms, 10))
+	return err
+}
+
+// Ping checks that the server is reachable (and the password and db are accepted).
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeConn()
+}
+
+func (c *Client) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.rd = nil, nil
+	return err
+}
+
+// do sends one command and reads its reply: string for simple strings, int64 for integers, []byte for
+// bulk strings, nil for a nil bulk string. A Redis error reply is returned as an error.
+func (c *Client) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if c.conn == nil {
+		if err := c.dial(ctx, deadline); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(deadline, args)
+	var redisErr replyError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection may hold a half-read reply
+		c.closeConn()
+	}
+	return reply, err
+}
+
+func (c *Client) dial(ctx context.Context, deadline time.Time) error {
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("redis dial %s: %w", c.addr, err)
+	}
+	c.conn, c.rd = conn, bufio.NewReader(conn)
+	if c.password != "" {
+		if _, err := c.roundTrip(deadline, []string{"AUTH", c.password}); err != nil {
+			c.closeConn()
+			return fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip(deadline, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			c.closeConn()
+			return fmt.Errorf("redis select %d: %w", c.db, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) roundTrip(deadline time.Time, args []string) (interface{}, error) {
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, a := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(a)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, a...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(c.rd)
+}
+
+// replyError is an error reply of the server ("-ERR ..."); the connection stays usable after it.
+type replyError string
+
+func (e replyError) Error() string { return "redis: " + string(e) }
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, replyError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed redis bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply type %q", kind)
+	}
+}
@@ -0,0 +1,184 @@
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET, SET (PX), PING, AUTH and SELECT from a map.
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	dbs     []string
+}
+
+func startFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, password: password, values: map[string]string{}, expires: map[string]time.Time{}}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			return
+		}
+		var reply string
+		f.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			authed = args[1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "SELECT":
+			f.dbs = append(f.dbs, args[1])
+			reply = "+OK\r\n"
+		case "PING":
+			reply = "+PONG\r\n"
+		case "GET":
+			v, ok := f.values[args[1]]
+			if ok && time.Now().After(f.expires[args[1]]) {
+				ok = false
+			}
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			}
+		case "SET":
+			ms, _ := strconv.Atoi(args[4])
+			f.values[args[1]] = args[2]
+			f.expires[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if !authed && strings.ToUpper(args[0]) != "AUTH" {
+			reply = "-NOAUTH Authentication required.\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+func TestClient(t *testing.T) {
+	f := startFakeRedis(t, "secret")
+	c := New(f.ln.Addr().String(), "secret", 2, "vb:")
+	defer c.Close()
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if _, ok, err := c.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("Get(missing) = ok %v, err %v", ok, err)
+	}
+	value := []byte("{\"a\":1}\r\nbinary\x00")
+	if err := c.Set(ctx, "k", value, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, ok, err := c.Get(ctx, "k")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Fatalf("Get(k) = %q, %v, %v", got, ok, err)
+	}
+	f.mu.Lock()
+	if _, stored := f.values["vb:k"]; !stored {
+		t.Errorf("key is not prefixed: %v", f.values)
+	}
+	f.mu.Unlock()
+	if err := c.Set(ctx, "short", value, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "short"); ok {
+		t.Error("expired key is returned")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.dbs) != 1 || f.dbs[0] != "2" {
+		t.Errorf("SELECT calls = %v, want one of db 2", f.dbs)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	f := startFakeRedis(t, "secret")
+	ctx := context.Background()
+
+	c := New(f.ln.Addr().String(), "wrong", 0, "")
+	if err := c.Ping(ctx); err == nil || !strings.Contains(err.Error(), "auth") {
+		t.Errorf("Ping with a wrong password: err = %v", err)
+	}
+
+	c = New(f.ln.Addr().String(), "secret", 0, "")
+	if _, err := c.do(ctx, "FLUSHALL"); err == nil {
+		t.Error("error reply is not returned")
+	}
+	// An error reply keeps the connection
+	if err := c.Ping(ctx); err != nil {
+		t.Errorf("Ping after an error reply: %v", err)
+	}
+
+	f.ln.Close()
+	c.Close()
+	if err := c.Ping(ctx); err == nil {
+		t.Error("Ping of a closed server succeeded")
+	}
+}
@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Diff is the difference between the matches of a recording (expected) and of its replay.
+type Diff struct {
+	Missing []string    `json:"missing,omitempty"` // expected match IDs the replay did not produce
+	Extra   []string    `json:"extra,omitempty"`   // match IDs produced only by the replay
+	Changed []MatchDiff `json:"changed,omitempty"`
+	// Expected matches that have started since the recording: parsers drop started matches,
+	// so their absence is not a regression.
+	SkippedStarted int `json:"skipped_started,omitempty"`
+}
+
+// MatchDiff lists the outcome differences of one match. Outcome keys are
+// models.BetKey followed by "|bookmaker".
+type MatchDiff struct {
+	MatchID string   `json:"match_id"`
+	Name    string   `json:"name"`
+	Missing []string `json:"missing,omitempty"`
+	Extra   []string `json:"extra,omitempty"`
+	Odds    []string `json:"odds,omitempty"` // "key: expected -> actual"
+}
+
+// Empty reports whether the replay reproduced the recording.
+func (d Diff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// Compare compares matches by ID and their outcomes by key; timestamps are ignored.
+func Compare(expected, actual []models.Match, now time.Time) Diff {
+	var d Diff
+	act := make(map[string]*models.Match, len(actual))
+	for i := range actual {
+		act[actual[i].ID] = &actual[i]
+	}
+	seen := make(map[string]bool, len(expected))
+	for i := range expected {
+		e := &expected[i]
+		seen[e.ID] = true
+		a, ok := act[e.ID]
+		if !ok {
+			if !e.StartTime.IsZero() && e.StartTime.Before(now) {
+				d.SkippedStarted++
+			} else {
+				d.Missing = append(d.Missing, e.ID)
+			}
+			continue
+		}
+		if md := compareOutcomes(e, a); md != nil {
+			d.Changed = append(d.Changed, *md)
+		}
+	}
+	for id := range act {
+		if !seen[id] {
+			d.Extra = append(d.Extra, id)
+		}
+	}
+	sort.Strings(d.Missing)
+	sort.Strings(d.Extra)
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].MatchID < d.Changed[j].MatchID })
+	return d
+}
+
+func compareOutcomes(expected, actual *models.Match) *MatchDiff {
+	e, a := outcomeOdds(expected), outcomeOdds(actual)
+	md := MatchDiff{MatchID: expected.ID, Name: expected.Name}
+	for key, eo := range e {
+		ao, ok := a[key]
+		switch {
+		case !ok:
+			md.Missing = append(md.Missing, key)
+		case !models.OddsEqual(ao, eo):
+			md.Odds = append(md.Odds, fmt.Sprintf("%s: %.3f -> %.3f", key, eo, ao))
+		}
+	}
+	for key := range a {
+		if _, ok := e[key]; !ok {
+			md.Extra = append(md.Extra, key)
+		}
+	}
+	if len(md.Missing) == 0 && len(md.Extra) == 0 && len(md.Odds) == 0 {
+		return nil
+	}
+	sort.Strings(md.Missing)
+	sort.Strings(md.Extra)
+	sort.Strings(md.Odds)
+	return &md
+}
+
+func outcomeOdds(m *models.Match) map[string]float64 {
+	out := map[string]float64{}
+	for _, ev := range m.Events {
+		for _, o := range ev.Outcomes {
+			bk := o.Bookmaker
+			if bk == "" {
+				bk = ev.Bookmaker
+			}
+			if bk == "" {
+				bk = m.Bookmaker
+			}
+			key := models.OutcomeBetKey(ev, o).String() + "|" + strings.ToLower(bk)
+			out[key] = o.Odds
+		}
+	}
+	return out
+}
@@ -0,0 +1,389 @@
+// Package replay records the raw HTTP responses of parsers into a fixtures directory and replays
+// them, so a full ParseOnce can be re-run on captured traffic without network (cmd/parser-replay).
+//
+// Every parser HTTP client goes through circuitbreaker.Wrap, which wraps its transport with Wrap;
+// outside a session the wrapper is a pass-through. A fixtures directory contains manifest.json
+// (one Exchange per request) and bodies/ with the raw response bodies, stored as received
+// (still gzip-encoded if the parser asked for it), so the parser's own decoding is exercised too.
+//
+// Requests are matched by method, URL (query sorted, cache-busting params like "_" ignored) and a
+// hash of the request body. Repeated requests get the recorded responses in order; the last one
+// is served again when they run out. Traffic outside HTTP (headless Chrome mirror resolution) is
+// not recorded: resolved mirrors are stored in the fixtures by the mirror registry instead.
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ManifestFile = "manifest.json"
+	bodiesDir    = "bodies"
+)
+
+// DefaultIgnoreParams are query params that change on every request (timestamps, cache busters).
+var DefaultIgnoreParams = []string{"_", "timestamp", "ts", "rnd"}
+
+// Mode of the current session.
+type Mode string
+
+const (
+	ModeOff    Mode = ""
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// ErrNoFixture is returned by the replaying transport for a request that was not recorded.
+var ErrNoFixture = errors.New("replay: no fixture for request")
+
+// Exchange is one recorded request/response.
+type Exchange struct {
+	Key        string      `json:"key"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	BodyFile   string      `json:"body_file,omitempty"` // relative to the fixtures directory
+	Error      string      `json:"error,omitempty"`     // transport error instead of a response
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+// Manifest is the index of a fixtures directory.
+type Manifest struct {
+	Parser       string     `json:"parser,omitempty"`
+	RecordedAt   time.Time  `json:"recorded_at"`
+	IgnoreParams []string   `json:"ignore_params,omitempty"`
+	Exchanges    []Exchange `json:"exchanges"`
+}
+
+// Options of a session.
+type Options struct {
+	Parser       string   // stored in the manifest
+	IgnoreParams []string // query params left out of request keys (nil = DefaultIgnoreParams; replay uses the manifest's)
+}
+
+// Stats summarizes a finished session.
+type Stats struct {
+	Mode      Mode     `json:"mode"`
+	Dir       string   `json:"dir"`
+	Exchanges int      `json:"exchanges"`        // recorded, or available for replay
+	Served    int      `json:"served,omitempty"` // replay: requests answered from fixtures
+	Unused    int      `json:"unused,omitempty"` // replay: recorded keys never requested
+	Misses    []string `json:"misses,omitempty"` // replay: requests without a fixture
+}
+
+type session struct {
+	mode     Mode
+	dir      string
+	manifest Manifest
+	ignore   map[string]bool
+
+	mu     sync.Mutex
+	byKey  map[string][]int // replay: key -> exchange indexes in recording order
+	served map[string]int   // replay: key -> responses served
+	misses []string
+}
+
+var (
+	mu      sync.RWMutex
+	current *session
+)
+
+// StartRecording starts recording all parser HTTP traffic into dir (created if missing).
+// Stop writes the manifest.
+func StartRecording(dir string, opts Options) error {
+	if err := os.MkdirAll(filepath.Join(dir, bodiesDir), 0o755); err != nil {
+		return fmt.Errorf("create fixtures dir: %w", err)
+	}
+	ignore := opts.IgnoreParams
+	if ignore == nil {
+		ignore = DefaultIgnoreParams
+	}
+	s := &session{
+		mode:     ModeRecord,
+		dir:      dir,
+		manifest: Manifest{Parser: opts.Parser, RecordedAt: time.Now().UTC(), IgnoreParams: ignore},
+		ignore:   paramSet(ignore),
+	}
+	return start(s)
+}
+
+// StartReplay starts answering all parser HTTP requests from the fixtures in dir.
+func StartReplay(dir string, opts Options) error {
+	m, err := LoadManifest(dir)
+	if err != nil {
+		return err
+	}
+	ignore := m.IgnoreParams
+	if opts.IgnoreParams != nil {
+		ignore = opts.IgnoreParams
+	}
+	s := &session{
+		mode:     ModeReplay,
+		dir:      dir,
+		manifest: *m,
+		ignore:   paramSet(ignore),
+		byKey:    map[string][]int{},
+		served:   map[string]int{},
+	}
+	for i, ex := range m.Exchanges {
+		key := ex.Key
+		if opts.IgnoreParams != nil {
+			key = requestKeyFromURL(ex.Method, ex.URL, s.ignore, keyBodyHash(ex.Key))
+		}
+		s.byKey[key] = append(s.byKey[key], i)
+	}
+	return start(s)
+}
+
+func start(s *session) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil {
+		return fmt.Errorf("replay: %s session already active", current.mode)
+	}
+	current = s
+	return nil
+}
+
+// Stop ends the current session (writing the manifest when recording) and returns its stats.
+func Stop() (Stats, error) {
+	mu.Lock()
+	s := current
+	current = nil
+	mu.Unlock()
+	if s == nil {
+		return Stats{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Stats{Mode: s.mode, Dir: s.dir, Exchanges: len(s.manifest.Exchanges)}
+	if s.mode == ModeRecord {
+		data, err := json.MarshalIndent(s.manifest, "", "  ")
+		if err != nil {
+			return st, err
+		}
+		if err := os.WriteFile(filepath.Join(s.dir, ManifestFile), data, 0o644); err != nil {
+			return st, fmt.Errorf("write manifest: %w", err)
+		}
+		return st, nil
+	}
+	for _, n := range s.served {
+		st.Served += n
+	}
+	for key := range s.byKey {
+		if s.served[key] == 0 {
+			st.Unused++
+		}
+	}
+	st.Misses = append([]string(nil), s.misses...)
+	sort.Strings(st.Misses)
+	return st, nil
+}
+
+// Active returns the mode of the current session (ModeOff if none).
+func Active() Mode {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return ModeOff
+	}
+	return current.mode
+}
+
+// LoadManifest reads the manifest of a fixtures directory.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("read fixtures manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse fixtures manifest: %w", err)
+	}
+	return &m, nil
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Wrap returns a RoundTripper that records or replays requests while a session is active and
+// otherwise passes them to base. base == nil means http.DefaultTransport.
+func Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.RLock()
+	s := current
+	mu.RUnlock()
+	if s == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	bodyHash, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := requestKey(req.Method, req.URL, s.ignore, bodyHash)
+	if s.mode == ModeReplay {
+		return s.replay(req, key)
+	}
+	return s.record(t.base, req, key)
+}
+
+func (s *session) record(base http.RoundTripper, req *http.Request, key string) (*http.Response, error) {
+	ex := Exchange{Key: key, Method: req.Method, URL: req.URL.String(), RecordedAt: time.Now().UTC()}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		ex.Error = err.Error()
+		s.add(ex, nil)
+		return nil, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if readErr != nil {
+		// Partial body: record what the parser would have seen as a failed read
+		ex.Error = readErr.Error()
+		s.add(ex, nil)
+		return nil, readErr
+	}
+	ex.Status = resp.StatusCode
+	ex.Header = resp.Header.Clone()
+	s.add(ex, body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (s *session) add(ex Exchange, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if body != nil {
+		ex.BodyFile = filepath.ToSlash(filepath.Join(bodiesDir, fmt.Sprintf("%06d.body", len(s.manifest.Exchanges)+1)))
+		if err := os.WriteFile(filepath.Join(s.dir, ex.BodyFile), body, 0o644); err != nil {
+			ex.Error = "write body: " + err.Error()
+			ex.BodyFile = ""
+		}
+	}
+	s.manifest.Exchanges = append(s.manifest.Exchanges, ex)
+}
+
+func (s *session) replay(req *http.Request, key string) (*http.Response, error) {
+	s.mu.Lock()
+	idx := s.byKey[key]
+	if len(idx) == 0 {
+		s.misses = append(s.misses, req.Method+" "+req.URL.String())
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, req.Method, req.URL.String())
+	}
+	n := s.served[key]
+	s.served[key] = n + 1
+	s.mu.Unlock()
+	if n >= len(idx) {
+		n = len(idx) - 1
+	}
+	ex := s.manifest.Exchanges[idx[n]]
+	if ex.Error != "" {
+		return nil, fmt.Errorf("replayed error: %s", ex.Error)
+	}
+
+	var body []byte
+	if ex.BodyFile != "" {
+		var err error
+		body, err = os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(ex.BodyFile)))
+		if err != nil {
+			return nil, fmt.Errorf("replay: read fixture body: %w", err)
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		StatusCode:    ex.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        ex.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody returns a hash of the request body (empty if none) and restores the body.
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("replay: read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		return "", nil
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// requestKey is "METHOD url" with a sorted query without ignored params, plus "#<body hash>".
+func requestKey(method string, u *url.URL, ignore map[string]bool, bodyHash string) string {
+	q := u.Query()
+	for name := range q {
+		if ignore[strings.ToLower(name)] {
+			q.Del(name)
+		}
+	}
+	nu := *u
+	nu.RawQuery = q.Encode() // Encode sorts by key
+	nu.Fragment = ""
+	key := strings.ToUpper(method) + " " + nu.String()
+	if bodyHash != "" {
+		key += "#" + bodyHash
+	}
+	return key
+}
+
+func requestKeyFromURL(method, rawURL string, ignore map[string]bool, bodyHash string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.ToUpper(method) + " " + rawURL
+	}
+	return requestKey(method, u, ignore, bodyHash)
+}
+
+func keyBodyHash(key string) string {
+	if i := strings.LastIndexByte(key, '#'); i >= 0 {
+		return key[i+1:]
+	}
+	return ""
+}
+
+func paramSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string, error) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":%q,"call":%d}`, r.URL.Path, n)
+	}))
+	client := &http.Client{Transport: Wrap(nil)}
+	dir := t.TempDir()
+
+	if err := StartRecording(dir, Options{Parser: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := StartRecording(dir, Options{}); err == nil {
+		t.Fatal("second session started")
+	}
+	_, first, _ := get(t, client, srv.URL+"/line?sport=1&_=111")
+	_, second, _ := get(t, client, srv.URL+"/line?_=222&sport=1")
+	get(t, client, srv.URL+"/missing")
+	st, err := Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Exchanges != 3 {
+		t.Fatalf("recorded %d exchanges", st.Exchanges)
+	}
+	srv.Close()
+
+	if err := StartReplay(dir, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if Active() != ModeReplay {
+		t.Fatalf("mode = %q", Active())
+	}
+	// Same key (cache buster ignored, query order normalized): responses in recording order, then the last again
+	for i, want := range []string{first, second, second} {
+		code, body, err := get(t, client, srv.URL+"/line?sport=1&_=999")
+		if err != nil || code != http.StatusOK || body != want {
+			t.Fatalf("replay %d: code=%d body=%q err=%v, want %q", i, code, body, err, want)
+		}
+	}
+	if code, _, _ := get(t, client, srv.URL+"/missing"); code != http.StatusNotFound {
+		t.Errorf("replayed status = %d", code)
+	}
+	if _, _, err := get(t, client, srv.URL+"/line?sport=2"); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("unrecorded request: err = %v", err)
+	}
+	st, err = Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Served != 4 || len(st.Misses) != 1 || !strings.Contains(st.Misses[0], "sport=2") || st.Unused != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("server got %d calls, replay must not hit the network", calls)
+	}
+	if Active() != ModeOff {
+		t.Errorf("session still active after Stop")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	match := func(id string, start time.Time, odds ...float64) models.Match {
+		ev := models.Event{EventType: "main_match", Bookmaker: "Zenit"}
+		for i, o := range odds {
+			ev.Outcomes = append(ev.Outcomes, models.Outcome{OutcomeType: []string{"home_win", "draw", "away_win"}[i], Odds: o})
+		}
+		return models.Match{ID: id, Name: id, StartTime: start, Events: []models.Event{ev}}
+	}
+	later := now.Add(time.Hour)
+	expected := []models.Match{
+		match("a", later, 1.5, 4, 6),
+		match("b", later, 2, 3),
+		match("started", now.Add(-time.Hour), 2),
+		match("gone", later, 2),
+	}
+	actual := []models.Match{
+		match("a", later, 1.5, 4, 6),
+		match("b", later, 2.1),
+		match("new", later, 3),
+	}
+
+	d := Compare(expected, actual, now)
+	if d.Empty() {
+		t.Fatal("diff is empty")
+	}
+	if strings.Join(d.Missing, ",") != "gone" || strings.Join(d.Extra, ",") != "new" || d.SkippedStarted != 1 {
+		t.Errorf("diff = %+v", d)
+	}
+	if len(d.Changed) != 1 {
+		t.Fatalf("changed = %+v", d.Changed)
+	}
+	c := d.Changed[0]
+	if c.MatchID != "b" || strings.Join(c.Missing, ",") != "main_match|draw||zenit" ||
+		strings.Join(c.Odds, ",") != "main_match|home_win||zenit: 2.000 -> 2.100" {
+		t.Errorf("changed = %+v", c)
+	}
+	if d := Compare(actual, actual, now); !d.Empty() {
+		t.Errorf("self diff = %+v", d)
+	}
+}
@@ -0,0 +1,49 @@
+package results
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+// CachedProvider keeps final results (finished or cancelled) of another provider in storage,
+// so settlement and calibration ask the results API once per match.
+type CachedProvider struct {
+	provider Provider
+	store    storage.ResultStorage
+}
+
+// NewCachedProvider wraps p with the result cache store.
+func NewCachedProvider(p Provider, store storage.ResultStorage) *CachedProvider {
+	return &CachedProvider{provider: p, store: store}
+}
+
+// GetResult implements Provider.
+func (c *CachedProvider) GetResult(ctx context.Context, f Fixture) (*Result, error) {
+	cached, err := c.store.GetMatchResult(ctx, f.Sport, f.HomeTeam, f.AwayTeam, f.StartTime)
+	if err != nil {
+		slog.Warn("Failed to read cached match result", "home", f.HomeTeam, "away", f.AwayTeam, "error", err)
+	} else if cached != nil {
+		return &Result{Status: Status(cached.Status), HomeScore: cached.HomeScore, AwayScore: cached.AwayScore}, nil
+	}
+
+	r, err := c.provider.GetResult(ctx, f)
+	if err != nil || r == nil || r.Status == StatusScheduled {
+		return r, err
+	}
+	if err := c.store.StoreMatchResult(ctx, storage.MatchResult{
+		Sport:     f.Sport,
+		HomeTeam:  f.HomeTeam,
+		AwayTeam:  f.AwayTeam,
+		MatchDate: f.StartTime,
+		Status:    string(r.Status),
+		HomeScore: r.HomeScore,
+		AwayScore: r.AwayScore,
+		FetchedAt: time.Now(),
+	}); err != nil {
+		slog.Warn("Failed to cache match result", "home", f.HomeTeam, "away", f.AwayTeam, "error", err)
+	}
+	return r, nil
+}
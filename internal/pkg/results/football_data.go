@@ -0,0 +1,194 @@
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// DefaultFootballDataURL is the football-data.org v4 API.
+const DefaultFootballDataURL = "https://api.football-data.org/v4"
+
+// footballDataWindowTTL is how long the matches of one day are reused before asking the API again
+// (the free tier allows 10 requests per minute).
+const footballDataWindowTTL = 10 * time.Minute
+
+// FootballDataProvider reads football results from the football-data.org API (GET /matches by date).
+// Fixtures are matched by normalized team names and kick-off within 6 hours; other sports are unknown.
+type FootballDataProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+
+	mu   sync.Mutex
+	days map[string]footballDataDay // YYYY-MM-DD -> matches around that day
+}
+
+type footballDataDay struct {
+	fetchedAt time.Time
+	matches   []footballDataMatch
+}
+
+type footballDataTeam struct {
+	Name      string `json:"name"`
+	ShortName string `json:"shortName"`
+	TLA       string `json:"tla"`
+}
+
+type footballDataScore struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+type footballDataMatch struct {
+	UTCDate  time.Time        `json:"utcDate"`
+	Status   string           `json:"status"`
+	HomeTeam footballDataTeam `json:"homeTeam"`
+	AwayTeam footballDataTeam `json:"awayTeam"`
+	Score    struct {
+		Duration    string             `json:"duration"`
+		FullTime    footballDataScore  `json:"fullTime"`
+		RegularTime *footballDataScore `json:"regularTime"`
+	} `json:"score"`
+}
+
+// NewFootballDataProvider creates a football-data.org provider (baseURL "" = DefaultFootballDataURL, timeout 0 = 15s).
+func NewFootballDataProvider(baseURL, apiKey string, timeout time.Duration) *FootballDataProvider {
+	if baseURL == "" {
+		baseURL = DefaultFootballDataURL
+	}
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &FootballDataProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+		days:    map[string]footballDataDay{},
+	}
+}
+
+// GetResult implements Provider.
+func (p *FootballDataProvider) GetResult(ctx context.Context, f Fixture) (*Result, error) {
+	if !strings.EqualFold(f.Sport, "football") {
+		return nil, nil
+	}
+	matches, err := p.matchesAround(ctx, f.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		if d := m.UTCDate.Sub(f.StartTime); d > 6*time.Hour || d < -6*time.Hour {
+			continue
+		}
+		if !sameTeam(f.HomeTeam, m.HomeTeam) || !sameTeam(f.AwayTeam, m.AwayTeam) {
+			continue
+		}
+		return m.result(), nil
+	}
+	return nil, nil
+}
+
+// result converts the API match: scores of regular time (bets are settled on 90 minutes).
+func (m footballDataMatch) result() *Result {
+	switch m.Status {
+	case "FINISHED", "AWARDED":
+		score := m.Score.FullTime
+		if m.Score.RegularTime != nil && m.Score.Duration != "" && m.Score.Duration != "REGULAR" {
+			score = *m.Score.RegularTime
+		}
+		if score.Home == nil || score.Away == nil {
+			return &Result{Status: StatusScheduled}
+		}
+		return &Result{Status: StatusFinished, HomeScore: *score.Home, AwayScore: *score.Away}
+	case "POSTPONED", "CANCELLED", "SUSPENDED":
+		return &Result{Status: StatusCancelled}
+	}
+	return &Result{Status: StatusScheduled}
+}
+
+// matchesAround returns matches from the day before to the day after t (kick-off times differ by time zone).
+func (p *FootballDataProvider) matchesAround(ctx context.Context, t time.Time) ([]footballDataMatch, error) {
+	day := t.UTC().Format("2006-01-02")
+	p.mu.Lock()
+	cached, ok := p.days[day]
+	p.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < footballDataWindowTTL {
+		return cached.matches, nil
+	}
+
+	from := t.UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	to := t.UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/matches?dateFrom=%s&dateTo=%s", p.baseURL, from, to), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.apiKey != "" {
+		req.Header.Set("X-Auth-Token", p.apiKey)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("football-data request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("football-data returned status %d", resp.StatusCode)
+	}
+	var body struct {
+		Matches []footballDataMatch `json:"matches"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode football-data matches: %w", err)
+	}
+
+	p.mu.Lock()
+	p.days[day] = footballDataDay{fetchedAt: time.Now(), matches: body.Matches}
+	for d, cached := range p.days {
+		if time.Since(cached.fetchedAt) >= footballDataWindowTTL {
+			delete(p.days, d)
+		}
+	}
+	p.mu.Unlock()
+	return body.Matches, nil
+}
+
+// teamNameNoise are tokens bookmakers and results sources add or drop freely.
+var teamNameNoise = map[string]bool{"fc": true, "cf": true, "afc": true, "sc": true, "ac": true, "fk": true, "sk": true, "cd": true, "club": true}
+
+// normalizeTeamName lowercases name, drops punctuation and club suffixes ("FC", "AFC", ...).
+func normalizeTeamName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := fields[:0]
+	for _, f := range fields {
+		if !teamNameNoise[f] {
+			kept = append(kept, f)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// sameTeam reports whether a bookmaker team name refers to the API team: equal normalized names,
+// or one containing the other ("Arsenal" vs "Arsenal FC", "Wolves" vs "Wolverhampton Wanderers" needs the short name).
+func sameTeam(name string, team footballDataTeam) bool {
+	n := normalizeTeamName(name)
+	if n == "" {
+		return false
+	}
+	for _, candidate := range []string{team.Name, team.ShortName} {
+		c := normalizeTeamName(candidate)
+		if c == "" {
+			continue
+		}
+		if n == c || (len(n) >= 4 && strings.Contains(c, n)) || (len(c) >= 4 && strings.Contains(n, c)) {
+			return true
+		}
+	}
+	return team.TLA != "" && strings.EqualFold(strings.TrimSpace(name), team.TLA)
+}
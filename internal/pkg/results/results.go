@@ -0,0 +1,137 @@
+// Package results provides final scores of finished matches for bet settlement and for checking
+// whether the calculator's fair probabilities are calibrated.
+//
+// A Provider answers for one fixture (sport, teams, kick-off); the calculator's settlement job
+// asks it for every open bet whose match should be over. HTTPProvider reads a plain JSON feed
+// (value_calculator.results.url), so any results source can be plugged in behind a small adapter;
+// FootballDataProvider reads football-data.org. CachedProvider keeps final results in Postgres.
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// Status is the state of a fixture in the results source.
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled" // not finished yet (or unknown to the source)
+	StatusFinished  Status = "finished"  // final score is known
+	StatusCancelled Status = "cancelled" // cancelled, abandoned or postponed: bets are void
+)
+
+// Fixture identifies a match in the results source.
+type Fixture struct {
+	Sport     string
+	HomeTeam  string
+	AwayTeam  string
+	StartTime time.Time
+}
+
+// Result is the outcome of a fixture. Scores are full-time (regular time) goals and are only
+// meaningful when Status is StatusFinished.
+type Result struct {
+	Status    Status `json:"status"`
+	HomeScore int    `json:"home_score"`
+	AwayScore int    `json:"away_score"`
+}
+
+// Provider returns results of fixtures.
+type Provider interface {
+	// GetResult returns the result of f, or nil (and no error) when the source doesn't know the fixture
+	GetResult(ctx context.Context, f Fixture) (*Result, error)
+}
+
+// Provider names (value_calculator.results.provider).
+const (
+	ProviderHTTP         = "http"
+	ProviderFootballData = "football_data"
+)
+
+// New returns the provider configured by cfg, or nil when no results source is configured.
+func New(cfg config.ResultsConfig) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		if cfg.URL == "" {
+			return nil, nil
+		}
+		return NewHTTPProvider(cfg.URL, cfg.Timeout), nil
+	case ProviderHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("results.url is required for provider %q", ProviderHTTP)
+		}
+		return NewHTTPProvider(cfg.URL, cfg.Timeout), nil
+	case ProviderFootballData:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("results.api_key is required for provider %q", ProviderFootballData)
+		}
+		return NewFootballDataProvider(cfg.URL, cfg.APIKey, cfg.Timeout), nil
+	}
+	return nil, fmt.Errorf("unknown results provider %q (want %s or %s)", cfg.Provider, ProviderHTTP, ProviderFootballData)
+}
+
+// HTTPProvider reads results from a JSON feed:
+//
+//	GET <url>?sport=football&home=Arsenal&away=Chelsea&date=2026-03-01
+//	-> {"status": "finished", "home_score": 2, "away_score": 1}
+//
+// 404 means the fixture is unknown.
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider creates a provider for the feed at baseURL (timeout 0 = 15s).
+func NewHTTPProvider(baseURL string, timeout time.Duration) *HTTPProvider {
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &HTTPProvider{url: strings.TrimSpace(baseURL), client: &http.Client{Timeout: timeout}}
+}
+
+// GetResult implements Provider.
+func (p *HTTPProvider) GetResult(ctx context.Context, f Fixture) (*Result, error) {
+	q := url.Values{}
+	q.Set("sport", f.Sport)
+	q.Set("home", f.HomeTeam)
+	q.Set("away", f.AwayTeam)
+	q.Set("date", f.StartTime.UTC().Format("2006-01-02"))
+	sep := "?"
+	if strings.Contains(p.url, "?") {
+		sep = "&"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+sep+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("results request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("results source returned status %d", resp.StatusCode)
+	}
+	var r Result
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	switch r.Status {
+	case StatusFinished, StatusCancelled:
+	default:
+		r.Status = StatusScheduled
+	}
+	return &r, nil
+}
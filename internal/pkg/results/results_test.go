@@ -0,0 +1,88 @@
+package results
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("home") != "Arsenal" || q.Get("away") != "Chelsea" || q.Get("date") != "2026-03-01" || q.Get("sport") != "football" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"finished","home_score":2,"away_score":1}`))
+	}))
+	defer srv.Close()
+
+	p := NewHTTPProvider(srv.URL, time.Second)
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	r, err := p.GetResult(context.Background(), Fixture{Sport: "football", HomeTeam: "Arsenal", AwayTeam: "Chelsea", StartTime: start})
+	if err != nil || r == nil {
+		t.Fatalf("GetResult: %v, %v", r, err)
+	}
+	if r.Status != StatusFinished || r.HomeScore != 2 || r.AwayScore != 1 {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	r, err = p.GetResult(context.Background(), Fixture{Sport: "football", HomeTeam: "Spurs", AwayTeam: "Chelsea", StartTime: start})
+	if err != nil || r != nil {
+		t.Errorf("unknown fixture: got %v, %v; want nil, nil", r, err)
+	}
+}
+
+func TestFootballDataProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "secret" || r.URL.Path != "/matches" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"matches": [
+			{"utcDate": "2026-03-01T17:30:00Z", "status": "FINISHED",
+			 "homeTeam": {"name": "Arsenal FC", "shortName": "Arsenal", "tla": "ARS"},
+			 "awayTeam": {"name": "Chelsea FC", "shortName": "Chelsea", "tla": "CHE"},
+			 "score": {"duration": "REGULAR", "fullTime": {"home": 2, "away": 2}}},
+			{"utcDate": "2026-03-01T20:00:00Z", "status": "FINISHED",
+			 "homeTeam": {"name": "Real Madrid CF", "shortName": "Real Madrid", "tla": "RMA"},
+			 "awayTeam": {"name": "FC Barcelona", "shortName": "Barça", "tla": "FCB"},
+			 "score": {"duration": "EXTRA_TIME", "fullTime": {"home": 3, "away": 2}, "regularTime": {"home": 2, "away": 2}}},
+			{"utcDate": "2026-03-01T15:00:00Z", "status": "POSTPONED",
+			 "homeTeam": {"name": "Everton FC", "shortName": "Everton"},
+			 "awayTeam": {"name": "Fulham FC", "shortName": "Fulham"},
+			 "score": {"fullTime": {"home": null, "away": null}}}
+		]}`))
+	}))
+	defer srv.Close()
+
+	p := NewFootballDataProvider(srv.URL, "secret", time.Second)
+	get := func(home, away string, start time.Time) *Result {
+		t.Helper()
+		r, err := p.GetResult(context.Background(), Fixture{Sport: "football", HomeTeam: home, AwayTeam: away, StartTime: start})
+		if err != nil {
+			t.Fatalf("GetResult(%s, %s): %v", home, away, err)
+		}
+		return r
+	}
+
+	if r := get("Arsenal", "Chelsea", time.Date(2026, 3, 1, 17, 30, 0, 0, time.UTC)); r == nil || r.Status != StatusFinished || r.HomeScore != 2 || r.AwayScore != 2 {
+		t.Errorf("Arsenal vs Chelsea: %+v", r)
+	}
+	// Bets are settled on regular time
+	if r := get("Real Madrid", "Barcelona", time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)); r == nil || r.HomeScore != 2 || r.AwayScore != 2 {
+		t.Errorf("Real Madrid vs Barcelona: %+v", r)
+	}
+	if r := get("Everton", "Fulham", time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)); r == nil || r.Status != StatusCancelled {
+		t.Errorf("Everton vs Fulham: %+v", r)
+	}
+	// Kick-off too far from the API's
+	if r := get("Arsenal", "Chelsea", time.Date(2026, 3, 2, 17, 30, 0, 0, time.UTC)); r != nil {
+		t.Errorf("next day Arsenal vs Chelsea: %+v, want nil", r)
+	}
+	if r, _ := p.GetResult(context.Background(), Fixture{Sport: "dota2", HomeTeam: "Arsenal", AwayTeam: "Chelsea"}); r != nil {
+		t.Errorf("other sports are unknown, got %+v", r)
+	}
+}
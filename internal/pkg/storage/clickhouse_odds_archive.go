@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// ClickHouse archive defaults (storage.clickhouse).
+const (
+	defaultClickHouseDatabase = "vodeneevbet"
+	defaultClickHouseTable    = "odds_archive"
+)
+
+// clickHouseTime is the DateTime64(3) text format of the archive columns.
+const clickHouseTime = "2006-01-02 15:04:05.000"
+
+// formatClickHouseTime formats t for a DateTime64 column; the zero time (unknown start) is the epoch,
+// year 1 is out of the column's range.
+func formatClickHouseTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(clickHouseTime)
+}
+
+var clickHouseIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Ensure ClickHouseOddsArchive implements OddsArchive
+var _ OddsArchive = (*ClickHouseOddsArchive)(nil)
+
+// ClickHouseOddsArchive writes odds snapshots into a ClickHouse MergeTree table over the HTTP interface
+// (port 8123), one INSERT ... FORMAT JSONEachRow per batch. The table is partitioned by month and ordered
+// by (match_group_key, bet_key, bookmaker, recorded_at), so one line's history is a contiguous range.
+type ClickHouseOddsArchive struct {
+	endpoint string
+	database string
+	table    string
+	user     string
+	password string
+	client   *http.Client
+}
+
+// NewClickHouseOddsArchive checks the server and creates the archive database and table if needed.
+// With cfg.TTL > 0 ClickHouse deletes rows older than it.
+func NewClickHouseOddsArchive(cfg *config.ClickHouseConfig) (*ClickHouseOddsArchive, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("clickhouse url is required")
+	}
+	a := &ClickHouseOddsArchive{
+		endpoint: strings.TrimSuffix(cfg.URL, "/") + "/",
+		database: cfg.Database,
+		table:    cfg.Table,
+		user:     cfg.User,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: time.Minute},
+	}
+	if a.database == "" {
+		a.database = defaultClickHouseDatabase
+	}
+	if a.table == "" {
+		a.table = defaultClickHouseTable
+	}
+	for _, id := range []string{a.database, a.table} {
+		if !clickHouseIdentifier.MatchString(id) {
+			return nil, fmt.Errorf("invalid clickhouse identifier %q", id)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := a.ensureSchema(ctx, cfg.TTL); err != nil {
+		return nil, err
+	}
+	slog.Info("ClickHouse odds archive initialized successfully", "database", a.database, "table", a.table, "ttl", cfg.TTL)
+	return a, nil
+}
+
+func (a *ClickHouseOddsArchive) qualifiedTable() string {
+	return a.database + "." + a.table
+}
+
+func (a *ClickHouseOddsArchive) ensureSchema(ctx context.Context, ttl time.Duration) error {
+	stmts := []string{
+		`CREATE DATABASE IF NOT EXISTS ` + a.database,
+		`CREATE TABLE IF NOT EXISTS ` + a.qualifiedTable() + ` (
+			recorded_at DateTime64(3, 'UTC'),
+			match_group_key String,
+			match_name String,
+			sport LowCardinality(String),
+			event_type LowCardinality(String),
+			outcome_type LowCardinality(String),
+			parameter String,
+			bet_key String,
+			bookmaker LowCardinality(String),
+			start_time DateTime64(3, 'UTC'),
+			odd Float64
+		)
+		ENGINE = MergeTree
+		PARTITION BY toYYYYMM(recorded_at)
+		ORDER BY (match_group_key, bet_key, bookmaker, recorded_at)`,
+	}
+	if ttl > 0 {
+		stmts = append(stmts, fmt.Sprintf(`ALTER TABLE %s MODIFY TTL toDateTime(recorded_at) + INTERVAL %d SECOND`,
+			a.qualifiedTable(), int64(ttl.Seconds())))
+	}
+	for _, stmt := range stmts {
+		if err := a.exec(ctx, stmt, nil); err != nil {
+			return fmt.Errorf("failed to initialize clickhouse odds archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// exec runs query with body (the data of an INSERT) and discards the result.
+func (a *ClickHouseOddsArchive) exec(ctx context.Context, query string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint+"?"+url.Values{"query": {query}}.Encode(), body)
+	if err != nil {
+		return err
+	}
+	if a.user != "" {
+		req.Header.Set("X-ClickHouse-User", a.user)
+	}
+	if a.password != "" {
+		req.Header.Set("X-ClickHouse-Key", a.password)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("clickhouse: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+type clickHouseOddsRow struct {
+	RecordedAt    string  `json:"recorded_at"`
+	MatchGroupKey string  `json:"match_group_key"`
+	MatchName     string  `json:"match_name"`
+	Sport         string  `json:"sport"`
+	EventType     string  `json:"event_type"`
+	OutcomeType   string  `json:"outcome_type"`
+	Parameter     string  `json:"parameter"`
+	BetKey        string  `json:"bet_key"`
+	Bookmaker     string  `json:"bookmaker"`
+	StartTime     string  `json:"start_time"`
+	Odd           float64 `json:"odd"`
+}
+
+// ArchiveOddsSnapshots inserts rows in one INSERT.
+func (a *ClickHouseOddsArchive) ArchiveOddsSnapshots(ctx context.Context, rows []OddsSnapshotToStore) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range rows {
+		if err := enc.Encode(clickHouseOddsRow{
+			RecordedAt:    formatClickHouseTime(r.RecordedAt),
+			MatchGroupKey: r.MatchGroupKey,
+			MatchName:     r.MatchName,
+			Sport:         r.Sport,
+			EventType:     r.EventType,
+			OutcomeType:   r.OutcomeType,
+			Parameter:     r.Parameter,
+			BetKey:        r.BetKey,
+			Bookmaker:     r.Bookmaker,
+			StartTime:     formatClickHouseTime(r.StartTime),
+			Odd:           r.Odd,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := a.exec(ctx, `INSERT INTO `+a.qualifiedTable()+` FORMAT JSONEachRow`, &body); err != nil {
+		return fmt.Errorf("failed to archive %d odds snapshots: %w", len(rows), err)
+	}
+	return nil
+}
+
+// Close releases idle connections.
+func (a *ClickHouseOddsArchive) Close() error {
+	a.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ensure FileAlertDecisionStorage implements AlertDecisionStorage
+var _ AlertDecisionStorage = (*FileAlertDecisionStorage)(nil)
+
+const (
+	alertDecisionFilePrefix = "alert_decisions-"
+	alertDecisionFileSuffix = ".jsonl"
+	alertDecisionDayLayout  = "2006-01-02"
+)
+
+// FileAlertDecisionStorage keeps the alert decision log without Postgres: one JSON line per decision in
+// <dir>/alert_decisions-YYYY-MM-DD.jsonl (UTC day of the decision). Retention removes whole days.
+type FileAlertDecisionStorage struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileAlertDecisionStorage creates dir if needed and stores the decision log there.
+func NewFileAlertDecisionStorage(dir string) (*FileAlertDecisionStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("decision log directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create decision log directory: %w", err)
+	}
+	return &FileAlertDecisionStorage{dir: dir}, nil
+}
+
+func (s *FileAlertDecisionStorage) dayPath(day time.Time) string {
+	return filepath.Join(s.dir, alertDecisionFilePrefix+day.UTC().Format(alertDecisionDayLayout)+alertDecisionFileSuffix)
+}
+
+// days returns the days that have a file, newest first.
+func (s *FileAlertDecisionStorage) days() ([]time.Time, error) {
+	names, err := filepath.Glob(filepath.Join(s.dir, alertDecisionFilePrefix+"*"+alertDecisionFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var days []time.Time
+	for _, name := range names {
+		base := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), alertDecisionFilePrefix), alertDecisionFileSuffix)
+		day, err := time.Parse(alertDecisionDayLayout, base)
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].After(days[j]) })
+	return days, nil
+}
+
+// StoreAlertDecisions appends the decisions to the files of their days.
+func (s *FileAlertDecisionStorage) StoreAlertDecisions(ctx context.Context, ds []AlertDecision) error {
+	byPath := map[string][]byte{}
+	var paths []string
+	for _, d := range ds {
+		line, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("failed to encode alert decision: %w", err)
+		}
+		path := s.dayPath(d.At)
+		if _, ok := byPath[path]; !ok {
+			paths = append(paths, path)
+		}
+		byPath[path] = append(append(byPath[path], line...), '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range paths {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open decision log: %w", err)
+		}
+		_, err = f.Write(byPath[path])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write decision log: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetAlertDecisions scans the files from the newest day back to f.Since and returns matching decisions,
+// newest first.
+func (s *FileAlertDecisionStorage) GetAlertDecisions(ctx context.Context, f AlertDecisionFilter) ([]AlertDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+	match := strings.ToLower(f.Match)
+	var out []AlertDecision
+	for _, day := range days {
+		if !f.Since.IsZero() && day.Add(24*time.Hour).Before(f.Since) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var found []AlertDecision
+		err := readAlertDecisions(s.dayPath(day), func(d AlertDecision) {
+			switch {
+			case f.BetKey != "" && d.BetKey != f.BetKey,
+				f.MatchGroupKey != "" && d.MatchGroupKey != f.MatchGroupKey,
+				match != "" && !strings.Contains(strings.ToLower(d.MatchName), match),
+				f.ChatID != 0 && d.ChatID != f.ChatID,
+				f.Decision != "" && d.Decision != f.Decision,
+				!f.Since.IsZero() && d.At.Before(f.Since):
+				return
+			}
+			found = append(found, d)
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Lines are appended in time order: the newest are at the end of the file
+		for i := len(found) - 1; i >= 0; i-- {
+			out = append(out, found[i])
+			if f.Limit > 0 && len(out) >= f.Limit {
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+func readAlertDecisions(path string, fn func(AlertDecision)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var d AlertDecision
+		if json.Unmarshal(scanner.Bytes(), &d) != nil {
+			continue // a line cut by a crash
+		}
+		fn(d)
+	}
+	return scanner.Err()
+}
+
+// DeleteAlertDecisionsBefore removes the files of days that ended before before. The count is of files.
+func (s *FileAlertDecisionStorage) DeleteAlertDecisionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	days, err := s.days()
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, day := range days {
+		if day.Add(24 * time.Hour).After(before) {
+			continue
+		}
+		if err := os.Remove(s.dayPath(day)); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf("failed to delete decision log: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Close is a no-op: files are opened per write.
+func (s *FileAlertDecisionStorage) Close() error {
+	return nil
+}
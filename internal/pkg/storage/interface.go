@@ -27,13 +27,40 @@ type Storage interface {
 	Close() error
 }
 
-// ArbitrageStorage interface for working with arbitrage data
+// ArbitrageLeg is one outcome of an arbitrage: StakePercent of the total stake goes on it at Bookmaker.
+type ArbitrageLeg struct {
+	OutcomeType  string  `json:"outcome_type"`
+	Parameter    string  `json:"parameter"`
+	BetKey       string  `json:"bet_key"`
+	Bookmaker    string  `json:"bookmaker"`
+	Odd          float64 `json:"odd"`
+	StakePercent float64 `json:"stake_percent"`
+}
+
+// Arbitrage (surebet) is a set of complementary outcomes of one market whose best odds across
+// bookmakers have implied probabilities summing below 1: the stake split returns ProfitPercent
+// whichever outcome wins.
+type Arbitrage struct {
+	MatchGroupKey string         `json:"match_group_key"`
+	MatchName     string         `json:"match_name"`
+	StartTime     time.Time      `json:"start_time"`
+	Sport         string         `json:"sport"`
+	EventType     string         `json:"event_type"` // e.g. main_match, corners
+	MarketKey     string         `json:"market_key"` // eventType|market|line, e.g. main_match|1x2|, corners|total|9.5
+	Legs          []ArbitrageLeg `json:"legs"`
+	ImpliedSum    float64        `json:"implied_sum"`    // sum of 1/odd over legs (< 1)
+	ProfitPercent float64        `json:"profit_percent"` // (1/implied_sum - 1) * 100
+	CalculatedAt  time.Time      `json:"calculated_at"`
+}
+
+// ArbitrageStorage stores arbitrages found by the calculator's async cycle.
 type ArbitrageStorage interface {
-	// StoreArbitrage saves found arbitrage
-	StoreArbitrage(ctx context.Context, arb interface{}) error
-	
-	// GetArbitrages gets arbitrages by filters
-	GetArbitrages(ctx context.Context, limit int) ([]interface{}, error)
+	// StoreArbitrages saves the arbitrages of one cycle
+	StoreArbitrages(ctx context.Context, arbs []Arbitrage) error
+	// CleanArbitrages removes all records (part of the full DB cleanup)
+	CleanArbitrages(ctx context.Context) error
+	// Close closes the database connection
+	Close() error
 }
 
 // ValueBetStorage interface for working with value bet data
@@ -73,12 +100,379 @@ type DiffBetStorage interface {
 	Close() error
 }
 
+// IgnoredMatch is an entry of the ignore list: a match (by match_group_key) excluded from calculation
+// until ExpiresAt, e.g. because its team mapping is known to be wrong.
+type IgnoredMatch struct {
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name,omitempty"`
+	Reason        string    `json:"reason"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// IgnoreStorage persists the ignore list so it survives calculator restarts.
+type IgnoreStorage interface {
+	// StoreIgnore adds or replaces the entry for m.MatchGroupKey
+	StoreIgnore(ctx context.Context, m IgnoredMatch) error
+	// DeleteIgnore removes the entry (no error if it doesn't exist)
+	DeleteIgnore(ctx context.Context, matchGroupKey string) error
+	// GetActiveIgnores returns entries that expire after now and deletes the expired ones
+	GetActiveIgnores(ctx context.Context, now time.Time) ([]IgnoredMatch, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// TeamAlias is a team name alias added through the calculator API (table team_aliases); the seed
+// aliases live in models/team_aliases.json.
+type TeamAlias struct {
+	Alias     string    `json:"alias"`
+	Canonical string    `json:"canonical"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamAliasStorage persists the team aliases added at runtime so they survive calculator restarts.
+type TeamAliasStorage interface {
+	// StoreTeamAlias adds or replaces the alias (keyed by models.AliasKey(a.Alias))
+	StoreTeamAlias(ctx context.Context, a TeamAlias) error
+	// DeleteTeamAlias removes the alias (no error if it doesn't exist)
+	DeleteTeamAlias(ctx context.Context, alias string) error
+	// GetTeamAliases returns all aliases
+	GetTeamAliases(ctx context.Context) ([]TeamAlias, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// LeagueAlias is a tournament name alias added through the calculator API (table league_aliases); the
+// seed aliases live in models/league_aliases.json.
+type LeagueAlias struct {
+	Alias     string    `json:"alias"`
+	Canonical string    `json:"canonical"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LeagueAliasStorage persists the tournament aliases added at runtime so they survive calculator restarts.
+type LeagueAliasStorage interface {
+	// StoreLeagueAlias adds or replaces the alias (keyed by models.AliasKey(a.Alias))
+	StoreLeagueAlias(ctx context.Context, a LeagueAlias) error
+	// DeleteLeagueAlias removes the alias (no error if it doesn't exist)
+	DeleteLeagueAlias(ctx context.Context, alias string) error
+	// GetLeagueAliases returns all aliases
+	GetLeagueAliases(ctx context.Context) ([]LeagueAlias, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// Alert types a subscription can receive (AlertSubscription.AlertTypes).
+const (
+	AlertTypeValue        = "value"         // value bets (including "value increased" re-alerts)
+	AlertTypeValueClosed  = "value_closed"  // an alerted value disappeared
+	AlertTypeLineMovement = "line_movement" // odds change at one bookmaker (прогрузы)
+	AlertTypeSteam        = "steam"         // the same outcome moving at several bookmakers
+	AlertTypeDigest       = "digest"        // daily/weekly digest (value_calculator.digest)
+)
+
+// AlertTypes lists every alert type.
+var AlertTypes = []string{AlertTypeValue, AlertTypeValueClosed, AlertTypeLineMovement, AlertTypeSteam, AlertTypeDigest}
+
+// AlertSubscription is the alert stream of one Telegram chat (POST /subscriptions): which alerts it
+// receives and from which thresholds. Zero thresholds fall back to value_calculator's.
+type AlertSubscription struct {
+	ChatID     int64               `json:"chat_id"`
+	Name       string              `json:"name,omitempty"`
+	Enabled    bool                `json:"enabled"`
+	AlertTypes []string            `json:"alert_types"` // empty = all
+	Filters    SubscriptionFilters `json:"filters"`
+
+	MinDiffPercent         float64 `json:"min_diff_percent"`          // value alerts from this diff (0 = alert_threshold)
+	MinLineMovementPercent float64 `json:"min_line_movement_percent"` // line movement alerts from this change (0 = any alerted movement)
+	MinLineMovementPP      float64 `json:"min_line_movement_pp"`      // ...or this implied probability shift (0 = off)
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SubscriptionFilters restrict the alerts of a subscription (empty = no restriction), e.g. only Pinnacle vs
+// Fonbet discrepancies at odds 1.5-3.0 in the top leagues. Steam alerts have no league or odds to filter by.
+type SubscriptionFilters struct {
+	Sports         []string `json:"sports,omitempty"`
+	Leagues        []string `json:"leagues,omitempty"`          // tournament contains one of them (case-insensitive)
+	Bookmakers     []string `json:"bookmakers,omitempty"`       // value: both bookmakers of the diff; line movement: its bookmaker; steam: a moving one
+	MinOdds        float64  `json:"min_odds,omitempty"`         // odd to bet: max_odd of a diff, current_odd of a line movement
+	MaxOdds        float64  `json:"max_odds,omitempty"`         // 0 = no limit
+	MaxDiffPercent float64  `json:"max_diff_percent,omitempty"` // value range upper bound (lower: min_diff_percent); 0 = no limit
+}
+
+// SubscriptionStorage persists alert subscriptions so they survive calculator restarts.
+type SubscriptionStorage interface {
+	// StoreSubscription adds or replaces the subscription of s.ChatID
+	StoreSubscription(ctx context.Context, s AlertSubscription) error
+	// DeleteSubscription removes the subscription (no error if it doesn't exist)
+	DeleteSubscription(ctx context.Context, chatID int64) error
+	// GetSubscriptions returns all subscriptions
+	GetSubscriptions(ctx context.Context) ([]AlertSubscription, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// ChatLanguageStorage persists the language chats chose for their messages and alerts (/lang in the bot).
+type ChatLanguageStorage interface {
+	// StoreChatLanguage sets the language of chatID
+	StoreChatLanguage(ctx context.Context, chatID int64, language string) error
+	// GetChatLanguages returns the language of every chat that chose one
+	GetChatLanguages(ctx context.Context) (map[int64]string, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// Bet statuses: open until settled from the match result.
+const (
+	BetStatusOpen     = "open"
+	BetStatusWon      = "won"
+	BetStatusLost     = "lost"
+	BetStatusVoid     = "void"
+	BetStatusHalfWon  = "half_won"  // Asian quarter line: half the stake won, half refunded
+	BetStatusHalfLost = "half_lost" // Asian quarter line: half the stake lost, half refunded
+)
+
+// Bet is a bet placed by a user on a value bet (POST /bets), settled automatically once the
+// match result is known.
+type Bet struct {
+	ID         int64  `json:"id"`
+	ValueBetID string `json:"value_bet_id"`
+	UserID     int64  `json:"user_id"` // Telegram chat ID of the bettor; 0 = not bound to a user
+
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	HomeTeam      string    `json:"home_team"`
+	AwayTeam      string    `json:"away_team"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	EventType     string    `json:"event_type"`
+	OutcomeType   string    `json:"outcome_type"`
+	Parameter     string    `json:"parameter"`
+	BetKey        string    `json:"bet_key"`
+
+	Bookmaker       string  `json:"bookmaker"`
+	Odd             float64 `json:"odd"`
+	Stake           float64 `json:"stake"`
+	FairProbability float64 `json:"fair_probability"` // at placement
+	ValuePercent    float64 `json:"value_percent"`    // at placement
+
+	Status    string     `json:"status"`
+	Profit    float64    `json:"profit"` // P/L in stake units once settled (0 while open)
+	HomeScore *int       `json:"home_score,omitempty"`
+	AwayScore *int       `json:"away_score,omitempty"`
+	PlacedAt  time.Time  `json:"placed_at"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// BetStorage stores placed bets and their settlement.
+type BetStorage interface {
+	// StoreBet inserts b (status open) and sets b.ID
+	StoreBet(ctx context.Context, b *Bet) error
+	// GetBets returns bets of userID (0 = all users), newest first
+	GetBets(ctx context.Context, userID int64) ([]Bet, error)
+	// GetOpenBets returns open bets of matches that started before startedBefore
+	GetOpenBets(ctx context.Context, startedBefore time.Time) ([]Bet, error)
+	// SettleBet saves status, profit, scores and settled_at of b (by ID)
+	SettleBet(ctx context.Context, b Bet) error
+	// Close closes the database connection
+	Close() error
+}
+
+// Value bet end reasons (ValueBetLifecycle.EndReason).
+const (
+	ValueEndMatchStarted = "match_started" // kick-off: value bets are tracked pre-match only
+	ValueEndValueGone    = "value_gone"    // still quoted, but no longer a value (odds moved here or elsewhere)
+	ValueEndQuoteRemoved = "quote_removed" // the bookmaker stopped quoting the bet (or the match left the feed)
+)
+
+// ValueBetLifecycle is one continuous stretch of a value bet (match, bet, bookmaker): from the cycle it
+// was first detected to the cycle it disappeared. A value that comes back starts a new lifecycle.
+type ValueBetLifecycle struct {
+	ID         int64  `json:"id"`
+	ValueBetID string `json:"value_bet_id"` // stable id of (match, bet, bookmaker), as in /value-bets/top
+
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	EventType     string    `json:"event_type"`
+	OutcomeType   string    `json:"outcome_type"`
+	Parameter     string    `json:"parameter"`
+	BetKey        string    `json:"bet_key"`
+	Bookmaker     string    `json:"bookmaker"`
+
+	FirstSeen         time.Time `json:"first_seen"`
+	LastSeen          time.Time `json:"last_seen"`
+	FirstValuePercent float64   `json:"first_value_percent"`
+	MaxValuePercent   float64   `json:"max_value_percent"`
+	LastValuePercent  float64   `json:"last_value_percent"`
+	MaxValueOdd       float64   `json:"max_value_odd"` // bookmaker odd at the max value
+	LastOdd           float64   `json:"last_odd"`
+	LastFairOdd       float64   `json:"last_fair_odd"`
+
+	EndedAt   *time.Time `json:"ended_at,omitempty"`   // nil while active
+	EndReason string     `json:"end_reason,omitempty"` // one of ValueEnd*
+}
+
+// ValueBetHistoryFilter selects value bet lifecycles; zero fields don't filter.
+type ValueBetHistoryFilter struct {
+	Sport           string
+	Bookmaker       string // case-insensitive
+	MatchGroupKey   string
+	From, To        time.Time // first_seen range: [From, To)
+	Status          string    // "active" or "ended"
+	EndReason       string
+	MinValuePercent float64 // max_value_percent at least
+	Limit, Offset   int
+}
+
+// ValueHistoryStorage stores value bet lifecycles (GET /value-bets/history).
+type ValueHistoryStorage interface {
+	// UpsertValueBetLifecycles adds or updates lifecycles by (value_bet_id, first_seen)
+	UpsertValueBetLifecycles(ctx context.Context, ls []ValueBetLifecycle) error
+	// GetValueBetHistory returns lifecycles matching f, newest first, and the number of all matching
+	GetValueBetHistory(ctx context.Context, f ValueBetHistoryFilter) ([]ValueBetLifecycle, int, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// AlertDecision is one record of the alert decision log: what an async value cycle decided for a diff in a
+// chat (value_calculator.decision_log).
+type AlertDecision struct {
+	At            time.Time `json:"at"`
+	Pipeline      string    `json:"pipeline"`
+	ChatID        int64     `json:"chat_id,omitempty"` // 0 = decided before the chats (stale, max odds, alerts off)
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	BetKey        string    `json:"bet_key"`
+	Bookmaker     string    `json:"bookmaker"` // bookmaker with the best odd
+	MaxOdd        float64   `json:"max_odd"`
+	DiffPercent   float64   `json:"diff_percent"`
+	Threshold     float64   `json:"threshold"` // the chat's threshold (the pipeline's alert_threshold when ChatID is 0)
+	Decision      string    `json:"decision"`
+	Reason        string    `json:"reason,omitempty"` // details, e.g. the last alert of a cooldown skip
+}
+
+// AlertDecisionFilter selects alert decisions; empty fields match all.
+type AlertDecisionFilter struct {
+	BetKey        string
+	MatchGroupKey string
+	Match         string // case-insensitive substring of the match name
+	ChatID        int64
+	Decision      string
+	Since         time.Time
+	Limit         int
+}
+
+// AlertDecisionStorage keeps the alert decision log (GET /diagnostics/decisions).
+type AlertDecisionStorage interface {
+	// StoreAlertDecisions appends the decisions of a cycle
+	StoreAlertDecisions(ctx context.Context, ds []AlertDecision) error
+	// GetAlertDecisions returns decisions matching f, newest first
+	GetAlertDecisions(ctx context.Context, f AlertDecisionFilter) ([]AlertDecision, error)
+	// DeleteAlertDecisionsBefore removes decisions older than before (retention) and returns how many
+	DeleteAlertDecisionsBefore(ctx context.Context, before time.Time) (int64, error)
+	// Close releases the database connection or files
+	Close() error
+}
+
+// LeaderLock elects one leader among calculator replicas sharing a database (value_calculator.leader_election).
+type LeaderLock interface {
+	// TryAcquire takes the lock unless another replica holds it; true while this replica holds it.
+	// A lock lost with its database session returns false and the error.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release gives the lock up so another replica can take over without waiting
+	Release(ctx context.Context) error
+	// Close closes the database connection, releasing the lock
+	Close() error
+}
+
+// CycleStateStorage keeps the state of the calculator's async cycle (alert state machines, steam alert
+// cooldowns) by name, so the replica taking leadership over continues where the previous leader stopped.
+type CycleStateStorage interface {
+	// SaveCycleState replaces the state saved under name
+	SaveCycleState(ctx context.Context, name string, data []byte) error
+	// LoadCycleState returns the state saved under name and when it was saved (nil data if none)
+	LoadCycleState(ctx context.Context, name string) ([]byte, time.Time, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// MatchResult is a cached final result of a match (see results.CachedProvider). Team names are
+// stored as requested by the calculator, lowercased.
+type MatchResult struct {
+	Sport     string    `json:"sport"`
+	HomeTeam  string    `json:"home_team"`
+	AwayTeam  string    `json:"away_team"`
+	MatchDate time.Time `json:"match_date"` // UTC date of kick-off
+	Status    string    `json:"status"`     // finished or cancelled
+	HomeScore int       `json:"home_score"`
+	AwayScore int       `json:"away_score"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// ResultStorage caches final match results so the results API is asked once per match.
+type ResultStorage interface {
+	// GetMatchResult returns the cached result, or nil if there is none
+	GetMatchResult(ctx context.Context, sport, homeTeam, awayTeam string, matchDate time.Time) (*MatchResult, error)
+	// StoreMatchResult adds or replaces the result
+	StoreMatchResult(ctx context.Context, r MatchResult) error
+	// Close closes the database connection
+	Close() error
+}
+
+// WarehouseETLResult summarizes one ETL run into the research schema.
+type WarehouseETLResult struct {
+	From       time.Time // exclusive lower bound of odds_snapshot_history.recorded_at (previous watermark)
+	To         time.Time // inclusive upper bound, the new watermark
+	Rows       int64     // rows added to research.bets_wide
+	Matches    int64     // rows added or updated in research.dim_matches
+	Bookmakers int64     // rows added or updated in research.dim_bookmakers
+}
+
+// WarehouseStorage copies operational odds tables into the denormalized research schema
+// (research.bets_wide, research.dim_matches, research.dim_bookmakers) for notebooks.
+type WarehouseStorage interface {
+	// RunWarehouseETL loads odds history recorded after the last run's watermark up to now
+	RunWarehouseETL(ctx context.Context, now time.Time) (WarehouseETLResult, error)
+	// Close closes the database connection
+	Close() error
+}
+
+// OddsArchive keeps every odds snapshot long-term for analytics and backtesting (storage.clickhouse),
+// unlike odds_snapshot_history which only gets changes and is dropped after the retention.
+type OddsArchive interface {
+	// ArchiveOddsSnapshots writes one batch of snapshots
+	ArchiveOddsSnapshots(ctx context.Context, rows []OddsSnapshotToStore) error
+	// Close closes the connection
+	Close() error
+}
+
 // OddsHistoryPoint is one recorded (odd, time) point for timeline in alerts.
 type OddsHistoryPoint struct {
 	Odd       float64
 	RecordedAt time.Time
 }
 
+// OddsHistoryRangeFilter selects the odds history of one bet (GET /odds/history); empty Bookmaker = all bookmakers.
+type OddsHistoryRangeFilter struct {
+	MatchGroupKey string
+	BetKey        string
+	Bookmaker     string
+	From, To      time.Time     // recorded_at range: [From, To), zero = open
+	Interval      time.Duration // >0: only the last point per bookmaker in each interval (downsampling)
+	Limit, Offset int
+}
+
+// OddsHistorySample is one recorded odd of a bookmaker.
+type OddsHistorySample struct {
+	Bookmaker  string    `json:"bookmaker"`
+	Odd        float64   `json:"odd"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
 // OddsSnapshotKey identifies one snapshot row (match_group_key, bet_key, bookmaker).
 type OddsSnapshotKey struct {
 	MatchGroupKey string
@@ -117,6 +511,27 @@ type OddsHistoryToAppend struct {
 	StartTime     time.Time
 	Odd           float64
 	RecordedAt    time.Time
+	// Keyframe marks an unchanged odd repeated by the periodic full snapshot (value_calculator.odds_history.keyframe_interval):
+	// the state of every key can be rebuilt from the last keyframe instead of the first point
+	Keyframe bool
+}
+
+// OddsHistoryRecord is one recorded odd of the odds history, with the match data needed to replay it.
+type OddsHistoryRecord struct {
+	MatchGroupKey string
+	MatchName     string // "Home vs Away"; empty when the source no longer knows it
+	Sport         string
+	BetKey        string // eventType|outcomeType|parameter
+	Bookmaker     string
+	Odd           float64
+	StartTime     time.Time
+	RecordedAt    time.Time
+}
+
+// OddsHistoryReader streams the odds history in recording order (backtests, research).
+type OddsHistoryReader interface {
+	// ScanOddsHistory calls fn for every odd recorded in [from, to), oldest first; fn's error stops the scan
+	ScanOddsHistory(ctx context.Context, from, to time.Time, fn func(OddsHistoryRecord) error) error
 }
 
 // OddsSnapshotStorage stores odds snapshots for line movement detection.
@@ -128,6 +543,8 @@ type OddsSnapshotStorage interface {
 	AppendOddsHistory(ctx context.Context, matchGroupKey, betKey, bookmaker string, startTime time.Time, odd float64, recordedAt time.Time) error
 	// GetOddsHistory returns recent points (oldest first), at most limit. Used to show "6.70 (12 min ago) → 7.10 (now)".
 	GetOddsHistory(ctx context.Context, matchGroupKey, betKey, bookmaker string, limit int) ([]OddsHistoryPoint, error)
+	// GetOddsHistoryRange returns the points matching f (oldest first) and the number of all matching ones.
+	GetOddsHistoryRange(ctx context.Context, f OddsHistoryRangeFilter) ([]OddsHistorySample, int, error)
 	// GetLastOddsSnapshot returns last odd, max and min seen, and recordedAt (0,0,0,zero time,nil if no row)
 	GetLastOddsSnapshot(ctx context.Context, matchGroupKey, betKey, bookmaker string) (odd, maxOdd, minOdd float64, recordedAt time.Time, err error)
 	// GetLastOddsSnapshotsBatch returns snapshots for many keys in one query (for /line-movements/top performance).
@@ -138,9 +555,14 @@ type OddsSnapshotStorage interface {
 	AppendOddsHistoryBatch(ctx context.Context, history []OddsHistoryToAppend) error
 	// ResetExtremesAfterAlert sets max_odd=odd and min_odd=odd for the row so we don't re-alert on same range
 	ResetExtremesAfterAlert(ctx context.Context, matchGroupKey, betKey, bookmaker string) error
-	// CleanSnapshotsForStartedMatches deletes snapshots and history for matches that have already started (start_time < now)
+	// CleanSnapshotsForStartedMatches deletes snapshots for matches that have already started (start_time < now); history stays
 	CleanSnapshotsForStartedMatches(ctx context.Context) error
-	// CleanAll truncates odds_snapshots and odds_snapshot_history (full clear for periodic DB cleanup).
+	// CleanAll truncates odds_snapshots (full clear for periodic DB cleanup); history is kept until the retention
 	CleanAll(ctx context.Context) error
+	// ApplyOddsHistoryRetention creates the day partitions of odds_snapshot_history ahead of now and drops the
+	// ones that ended before now-retention. Returns the number of partitions dropped.
+	ApplyOddsHistoryRetention(ctx context.Context, now time.Time, retention time.Duration) (int, error)
+	// ScanOddsHistory streams history points with match names (steam detection, backtests).
+	OddsHistoryReader
 	Close() error
 }
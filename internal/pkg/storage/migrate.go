@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// migrationFiles are the schema migrations of the Postgres storages: migrations/NNNN_name.sql, applied in
+// version order and recorded in schema_migrations. A migration is never edited once released; schema
+// changes ship as a new file.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the advisory lock held while migrating, so replicas starting together apply each
+// migration once.
+const migrationLockKey = 0x76626d67 // "vbmg"
+
+// Migration is one embedded schema migration.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationState is a migration with the time it was applied (zero = pending).
+type MigrationState struct {
+	Migration
+	AppliedAt time.Time
+}
+
+// Migrations returns the embedded migrations in version order.
+func Migrations() ([]Migration, error) {
+	return readMigrations(migrationFiles)
+}
+
+// readMigrations reads migrations/NNNN_name.sql of fsys in version order (numeric: 2 before 10).
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	var out []Migration
+	seen := map[int]string{}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".sql")
+		num, title, ok := strings.Cut(name, "_")
+		version, err := strconv.Atoi(num)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %q, want NNNN_name.sql", e.Name())
+		}
+		if prev, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", prev, e.Name())
+		}
+		seen[version] = e.Name()
+		data, err := fs.ReadFile(fsys, path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Migration{Version: version, Name: title, SQL: string(data)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// openPostgres opens and pings the database of cfg (first reachable host of a multi-host DSN).
+func openPostgres(cfg *config.PostgresConfig) (*sql.DB, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+	return db, nil
+}
+
+// MigratePostgres applies the pending migrations to the database of cfg. Returns the migrations applied.
+func MigratePostgres(ctx context.Context, cfg *config.PostgresConfig) ([]Migration, error) {
+	db, err := openPostgres(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return Migrate(ctx, db)
+}
+
+// PostgresMigrationStatus returns every embedded migration with the time it was applied to the database of cfg.
+func PostgresMigrationStatus(ctx context.Context, cfg *config.PostgresConfig) ([]MigrationState, error) {
+	db, err := openPostgres(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return MigrationStatus(ctx, db)
+}
+
+func ensureMigrationsTable(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(200) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, db interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}) (map[int]time.Time, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at.UTC()
+	}
+	return applied, rows.Err()
+}
+
+// Migrate applies the pending migrations in version order, each in its own transaction, under an advisory
+// lock. Tables created before migrations existed are adopted: the first migrations use IF NOT EXISTS.
+func Migrate(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range pendingMigrations(migrations, applied) {
+		start := time.Now()
+		if err := applyMigration(ctx, conn, m); err != nil {
+			return done, err
+		}
+		slog.Info("Migration applied", "version", m.Version, "name", m.Name, "duration", time.Since(start).Round(time.Millisecond))
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// pendingMigrations returns the migrations whose version is not in applied, keeping their order.
+func pendingMigrations(migrations []Migration, applied map[int]time.Time) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+		return fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return tx.Commit()
+}
+
+// MigrationStatus returns every embedded migration with the time it was applied (zero = pending).
+func MigrationStatus(ctx context.Context, db *sql.DB) ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, MigrationState{Migration: m, AppliedAt: applied[m.Version]})
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 || migrations[0].Name != "diff_bets" {
+		t.Fatalf("first embedded migration = %+v, want 0001_diff_bets", migrations)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d has version %d: versions must be consecutive from 1", i, m.Version)
+		}
+		if strings.TrimSpace(m.SQL) == "" {
+			t.Errorf("migration %04d_%s is empty", m.Version, m.Name)
+		}
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/10_bets.sql":     {Data: []byte("CREATE TABLE bets ()")},
+		"migrations/0002_odds.sql":   {Data: []byte("CREATE TABLE odds ()")},
+		"migrations/1_diff_bets.sql": {Data: []byte("CREATE TABLE diff_bets ()")},
+	}
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Name)
+	}
+	if strings.Join(got, ",") != "diff_bets,odds,bets" || migrations[2].Version != 10 || migrations[1].SQL != "CREATE TABLE odds ()" {
+		t.Errorf("migrations = %+v, want diff_bets, odds, bets in numeric version order", migrations)
+	}
+
+	for name, files := range map[string]fstest.MapFS{
+		"no version":     {"migrations/bets.sql": {}},
+		"not a number":   {"migrations/v1_bets.sql": {}},
+		"zero version":   {"migrations/0000_bets.sql": {}},
+		"same version":   {"migrations/0001_bets.sql": {}, "migrations/1_odds.sql": {}},
+		"no directory":   {},
+		"version only":   {"migrations/0001.sql": {}},
+		"negative value": {"migrations/-1_bets.sql": {}},
+	} {
+		if _, err := readMigrations(files); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "a"}, {Version: 2, Name: "b"}, {Version: 3, Name: "c"}, {Version: 4, Name: "d"}}
+	applied := map[int]time.Time{1: time.Now(), 3: time.Now()}
+	pending := pendingMigrations(migrations, applied)
+	if len(pending) != 2 || pending[0].Name != "b" || pending[1].Name != "d" {
+		t.Errorf("pending = %+v, want b and d in order", pending)
+	}
+	if pending := pendingMigrations(migrations, map[int]time.Time{1: {}, 2: {}, 3: {}, 4: {}}); len(pending) != 0 {
+		t.Errorf("all applied: pending = %+v", pending)
+	}
+}
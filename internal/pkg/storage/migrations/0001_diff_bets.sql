@@ -0,0 +1,29 @@
+-- Value diffs of every async cycle (GET /diffs/top); truncated by db_full_cleanup_interval.
+
+CREATE TABLE IF NOT EXISTS diff_bets (
+	id SERIAL PRIMARY KEY,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	sport VARCHAR(100) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	outcome_type VARCHAR(100) NOT NULL,
+	parameter VARCHAR(100) NOT NULL DEFAULT '',
+	bet_key VARCHAR(500) NOT NULL,
+	bookmakers INTEGER NOT NULL,
+	min_bookmaker VARCHAR(100) NOT NULL,
+	min_odd DECIMAL(10, 4) NOT NULL,
+	max_bookmaker VARCHAR(100) NOT NULL,
+	max_odd DECIMAL(10, 4) NOT NULL,
+	diff_abs DECIMAL(10, 4) NOT NULL,
+	diff_percent DECIMAL(10, 4) NOT NULL,
+	calculated_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(match_group_key, bet_key, calculated_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_diff_bets_match_group_key ON diff_bets(match_group_key);
+CREATE INDEX IF NOT EXISTS idx_diff_bets_bet_key ON diff_bets(bet_key);
+CREATE INDEX IF NOT EXISTS idx_diff_bets_calculated_at ON diff_bets(calculated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_diff_bets_diff_percent ON diff_bets(diff_percent DESC);
+CREATE INDEX IF NOT EXISTS idx_diff_bets_unique_check ON diff_bets(match_group_key, bet_key, calculated_at);
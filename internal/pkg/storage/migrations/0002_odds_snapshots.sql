@@ -0,0 +1,29 @@
+-- Last odd and its max/min per (match, bet, bookmaker) for line movement detection.
+
+CREATE TABLE IF NOT EXISTS odds_snapshots (
+	id SERIAL PRIMARY KEY,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	sport VARCHAR(100) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	outcome_type VARCHAR(100) NOT NULL,
+	parameter VARCHAR(100) NOT NULL DEFAULT '',
+	bet_key VARCHAR(500) NOT NULL,
+	bookmaker VARCHAR(100) NOT NULL,
+	odd DECIMAL(10, 4) NOT NULL,
+	max_odd DECIMAL(10, 4),
+	min_odd DECIMAL(10, 4),
+	recorded_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(match_group_key, bet_key, bookmaker)
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_snapshots_match_bet_bk ON odds_snapshots(match_group_key, bet_key, bookmaker);
+CREATE INDEX IF NOT EXISTS idx_odds_snapshots_start_time ON odds_snapshots(start_time);
+
+-- Tables from before max_odd/min_odd were added
+ALTER TABLE odds_snapshots ADD COLUMN IF NOT EXISTS max_odd DECIMAL(10, 4);
+ALTER TABLE odds_snapshots ADD COLUMN IF NOT EXISTS min_odd DECIMAL(10, 4);
+UPDATE odds_snapshots SET max_odd = odd WHERE max_odd IS NULL;
+UPDATE odds_snapshots SET min_odd = odd WHERE min_odd IS NULL;
@@ -0,0 +1,47 @@
+-- Odds history: changed odds plus periodic keyframes, partitioned by day of recorded_at (UTC).
+-- Partitions are named odds_snapshot_history_YYYYMMDD; the calculator creates the next days and drops
+-- the ones past value_calculator.odds_history.retention.
+
+-- A history table from before partitioning is moved into the partitioned one
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relname = 'odds_snapshot_history' AND n.nspname = current_schema() AND c.relkind = 'r') THEN
+		ALTER TABLE odds_snapshot_history RENAME TO odds_snapshot_history_legacy;
+		DROP INDEX IF EXISTS idx_odds_snapshot_history_key;
+		DROP INDEX IF EXISTS idx_odds_snapshot_history_start;
+	END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS odds_snapshot_history (
+	id BIGSERIAL,
+	match_group_key VARCHAR(500) NOT NULL,
+	bet_key VARCHAR(500) NOT NULL,
+	bookmaker VARCHAR(100) NOT NULL,
+	odd DECIMAL(10, 4) NOT NULL,
+	recorded_at TIMESTAMP NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	keyframe BOOLEAN NOT NULL DEFAULT FALSE
+) PARTITION BY RANGE (recorded_at);
+
+CREATE INDEX IF NOT EXISTS idx_odds_snapshot_history_key ON odds_snapshot_history(match_group_key, bet_key, bookmaker, recorded_at);
+CREATE INDEX IF NOT EXISTS idx_odds_snapshot_history_recorded ON odds_snapshot_history(recorded_at);
+
+DO $$
+DECLARE
+	d DATE;
+	last_day DATE;
+BEGIN
+	IF to_regclass('odds_snapshot_history_legacy') IS NULL THEN
+		RETURN;
+	END IF;
+	SELECT MIN(recorded_at)::date, MAX(recorded_at)::date INTO d, last_day FROM odds_snapshot_history_legacy;
+	WHILE d IS NOT NULL AND d <= last_day LOOP
+		EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF odds_snapshot_history FOR VALUES FROM (%L) TO (%L)',
+			'odds_snapshot_history_' || to_char(d, 'YYYYMMDD'), d, d + 1);
+		d := d + 1;
+	END LOOP;
+	INSERT INTO odds_snapshot_history (match_group_key, bet_key, bookmaker, odd, recorded_at, start_time)
+	SELECT match_group_key, bet_key, bookmaker, odd, recorded_at, start_time FROM odds_snapshot_history_legacy;
+	DROP TABLE odds_snapshot_history_legacy;
+END $$;
@@ -0,0 +1,11 @@
+-- Matches excluded from calculation until expires_at (POST /ignores).
+
+CREATE TABLE IF NOT EXISTS ignored_matches (
+	match_group_key VARCHAR(500) PRIMARY KEY,
+	match_name VARCHAR(500) NOT NULL DEFAULT '',
+	reason TEXT NOT NULL DEFAULT '',
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ignored_matches_expires_at ON ignored_matches(expires_at);
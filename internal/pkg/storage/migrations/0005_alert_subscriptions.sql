@@ -0,0 +1,14 @@
+-- Per-chat alert streams (/subscriptions).
+
+CREATE TABLE IF NOT EXISTS alert_subscriptions (
+	chat_id BIGINT PRIMARY KEY,
+	name VARCHAR(200) NOT NULL DEFAULT '',
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	alert_types JSONB NOT NULL DEFAULT '[]',
+	filters JSONB NOT NULL DEFAULT '{}',
+	min_diff_percent DECIMAL(10, 4) NOT NULL DEFAULT 0,
+	min_line_movement_percent DECIMAL(10, 4) NOT NULL DEFAULT 0,
+	min_line_movement_pp DECIMAL(10, 4) NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
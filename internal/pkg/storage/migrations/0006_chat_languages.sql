@@ -0,0 +1,7 @@
+-- Message language chosen by each chat (/chat-language).
+
+CREATE TABLE IF NOT EXISTS chat_languages (
+	chat_id BIGINT PRIMARY KEY,
+	language VARCHAR(8) NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,21 @@
+-- Surebets found by the async cycle (GET /arbs/top).
+
+CREATE TABLE IF NOT EXISTS arbitrages (
+	id SERIAL PRIMARY KEY,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	sport VARCHAR(100) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	market_key VARCHAR(500) NOT NULL,
+	bookmakers VARCHAR(500) NOT NULL,
+	legs JSONB NOT NULL,
+	implied_sum DECIMAL(10, 6) NOT NULL,
+	profit_percent DECIMAL(10, 4) NOT NULL,
+	calculated_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(match_group_key, market_key, calculated_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_arbitrages_calculated_at ON arbitrages(calculated_at);
+CREATE INDEX IF NOT EXISTS idx_arbitrages_match_market ON arbitrages(match_group_key, market_key);
@@ -0,0 +1,55 @@
+-- Research warehouse: denormalized odds history for notebooks, see docs/RESEARCH_WAREHOUSE.md.
+
+CREATE SCHEMA IF NOT EXISTS research;
+
+CREATE TABLE IF NOT EXISTS research.dim_bookmakers (
+	bookmaker_id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL UNIQUE,
+	first_seen TIMESTAMP NOT NULL,
+	last_seen TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS research.dim_matches (
+	match_group_key VARCHAR(500) PRIMARY KEY,
+	match_name VARCHAR(500) NOT NULL DEFAULT '',
+	home_team VARCHAR(250) NOT NULL DEFAULT '',
+	away_team VARCHAR(250) NOT NULL DEFAULT '',
+	sport VARCHAR(100) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	first_seen TIMESTAMP NOT NULL,
+	last_seen TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS research.bets_wide (
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL DEFAULT '',
+	home_team VARCHAR(250) NOT NULL DEFAULT '',
+	away_team VARCHAR(250) NOT NULL DEFAULT '',
+	sport VARCHAR(100) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	bookmaker_id INTEGER NOT NULL REFERENCES research.dim_bookmakers(bookmaker_id),
+	bookmaker VARCHAR(100) NOT NULL,
+	bet_key VARCHAR(500) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	outcome_type VARCHAR(100) NOT NULL,
+	parameter VARCHAR(100) NOT NULL DEFAULT '',
+	odd DECIMAL(10, 4) NOT NULL,
+	implied_probability DECIMAL(10, 6) NOT NULL,
+	recorded_at TIMESTAMP NOT NULL,
+	minutes_to_start DECIMAL(12, 2) NOT NULL,
+	loaded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (match_group_key, bet_key, bookmaker, recorded_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bets_wide_recorded_at ON research.bets_wide(recorded_at);
+CREATE INDEX IF NOT EXISTS idx_bets_wide_start_time ON research.bets_wide(start_time);
+CREATE INDEX IF NOT EXISTS idx_bets_wide_sport_event ON research.bets_wide(sport, event_type);
+
+CREATE TABLE IF NOT EXISTS research.etl_runs (
+	id SERIAL PRIMARY KEY,
+	started_at TIMESTAMP NOT NULL,
+	finished_at TIMESTAMP NOT NULL,
+	watermark_from TIMESTAMP NOT NULL,
+	watermark_to TIMESTAMP NOT NULL,
+	rows_loaded BIGINT NOT NULL
+);
@@ -0,0 +1,13 @@
+-- Cache of match results used to settle bets and backtests.
+
+CREATE TABLE IF NOT EXISTS match_results (
+	sport VARCHAR(100) NOT NULL,
+	home_team VARCHAR(200) NOT NULL,
+	away_team VARCHAR(200) NOT NULL,
+	match_date DATE NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	home_score INT NOT NULL DEFAULT 0,
+	away_score INT NOT NULL DEFAULT 0,
+	fetched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (sport, home_team, away_team, match_date)
+);
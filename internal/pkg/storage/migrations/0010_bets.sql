@@ -0,0 +1,31 @@
+-- Placed bets (/bets) and their settlement.
+
+CREATE TABLE IF NOT EXISTS bets (
+	id BIGSERIAL PRIMARY KEY,
+	value_bet_id VARCHAR(64) NOT NULL,
+	user_id BIGINT NOT NULL DEFAULT 0,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	home_team VARCHAR(200) NOT NULL,
+	away_team VARCHAR(200) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	sport VARCHAR(100) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	outcome_type VARCHAR(100) NOT NULL,
+	parameter VARCHAR(100) NOT NULL DEFAULT '',
+	bet_key VARCHAR(500) NOT NULL,
+	bookmaker VARCHAR(100) NOT NULL,
+	odd DECIMAL(10, 3) NOT NULL,
+	stake DECIMAL(14, 2) NOT NULL,
+	fair_probability DECIMAL(10, 6) NOT NULL DEFAULT 0,
+	value_percent DECIMAL(10, 4) NOT NULL DEFAULT 0,
+	status VARCHAR(20) NOT NULL DEFAULT 'open',
+	profit DECIMAL(14, 2) NOT NULL DEFAULT 0,
+	home_score INT,
+	away_score INT,
+	placed_at TIMESTAMP NOT NULL,
+	settled_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bets_user_id ON bets(user_id, placed_at);
+CREATE INDEX IF NOT EXISTS idx_bets_open ON bets(start_time) WHERE status = 'open';
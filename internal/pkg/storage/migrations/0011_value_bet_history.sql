@@ -0,0 +1,30 @@
+-- Value bet lifecycles (/value-bets/history).
+
+CREATE TABLE IF NOT EXISTS value_bet_history (
+	id BIGSERIAL PRIMARY KEY,
+	value_bet_id VARCHAR(64) NOT NULL,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	start_time TIMESTAMP NOT NULL,
+	sport VARCHAR(100) NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	outcome_type VARCHAR(100) NOT NULL,
+	parameter VARCHAR(100) NOT NULL DEFAULT '',
+	bet_key VARCHAR(500) NOT NULL,
+	bookmaker VARCHAR(100) NOT NULL,
+	first_seen TIMESTAMP NOT NULL,
+	last_seen TIMESTAMP NOT NULL,
+	first_value_percent DECIMAL(10, 4) NOT NULL,
+	max_value_percent DECIMAL(10, 4) NOT NULL,
+	last_value_percent DECIMAL(10, 4) NOT NULL,
+	max_value_odd DECIMAL(10, 3) NOT NULL,
+	last_odd DECIMAL(10, 3) NOT NULL,
+	last_fair_odd DECIMAL(10, 3) NOT NULL,
+	ended_at TIMESTAMP,
+	end_reason VARCHAR(50) NOT NULL DEFAULT '',
+	UNIQUE(value_bet_id, first_seen)
+);
+
+CREATE INDEX IF NOT EXISTS idx_value_bet_history_first_seen ON value_bet_history(first_seen);
+CREATE INDEX IF NOT EXISTS idx_value_bet_history_match ON value_bet_history(match_group_key);
+CREATE INDEX IF NOT EXISTS idx_value_bet_history_active ON value_bet_history(value_bet_id) WHERE ended_at IS NULL;
@@ -0,0 +1,7 @@
+-- Async cycle state, one JSON document per name (value alert trackers survive restarts and leader changes).
+
+CREATE TABLE IF NOT EXISTS calculator_cycle_state (
+	name VARCHAR(100) PRIMARY KEY,
+	data JSONB NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,21 @@
+-- Alert decision log (/diagnostics/decisions); kept by decision_log.retention.
+
+CREATE TABLE IF NOT EXISTS alert_decisions (
+	id BIGSERIAL PRIMARY KEY,
+	at TIMESTAMP NOT NULL,
+	pipeline VARCHAR(50) NOT NULL,
+	chat_id BIGINT NOT NULL DEFAULT 0,
+	match_group_key VARCHAR(500) NOT NULL,
+	match_name VARCHAR(500) NOT NULL,
+	bet_key VARCHAR(500) NOT NULL,
+	bookmaker VARCHAR(100) NOT NULL,
+	max_odd DECIMAL(10, 3) NOT NULL,
+	diff_percent DECIMAL(10, 4) NOT NULL,
+	threshold DECIMAL(10, 4) NOT NULL,
+	decision VARCHAR(50) NOT NULL,
+	reason VARCHAR(500) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_decisions_at ON alert_decisions(at);
+CREATE INDEX IF NOT EXISTS idx_alert_decisions_bet ON alert_decisions(bet_key, at);
+CREATE INDEX IF NOT EXISTS idx_alert_decisions_match ON alert_decisions(match_group_key, at);
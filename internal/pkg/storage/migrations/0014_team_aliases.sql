@@ -0,0 +1,9 @@
+-- Team name aliases added through the calculator API (POST /admin/team-aliases); the seed aliases are
+-- embedded in the binaries. alias_key is models.TeamAliasKey(alias).
+
+CREATE TABLE IF NOT EXISTS team_aliases (
+	alias_key VARCHAR(255) PRIMARY KEY,
+	alias VARCHAR(255) NOT NULL,
+	canonical VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
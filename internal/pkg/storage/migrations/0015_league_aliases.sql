@@ -0,0 +1,9 @@
+-- Tournament name aliases added through the calculator API (POST /admin/league-aliases); the seed aliases
+-- are embedded in the binaries. alias_key is models.AliasKey(alias).
+
+CREATE TABLE IF NOT EXISTS league_aliases (
+	alias_key VARCHAR(255) PRIMARY KEY,
+	alias VARCHAR(255) NOT NULL,
+	canonical VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
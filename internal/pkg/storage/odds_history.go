@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// odds_snapshot_history is partitioned by day of recorded_at (UTC): odds_snapshot_history_YYYYMMDD.
+const (
+	oddsHistoryTable          = "odds_snapshot_history"
+	oddsHistoryPartitionDay   = "20060102"
+	oddsHistoryPartitionAhead = 2 // days after today that always have a partition
+)
+
+// Ensure both odds history tables can be replayed
+var (
+	_ OddsHistoryReader = (*PostgresOddsSnapshotStorage)(nil)
+	_ OddsHistoryReader = (*PostgresWarehouseStorage)(nil)
+)
+
+// ScanOddsHistory streams odds_snapshot_history ($1 <= recorded_at < $2), keyframes included. The operational
+// history only covers value_calculator.odds_history.retention (see ApplyOddsHistoryRetention); replay
+// research.bets_wide (PostgresWarehouseStorage) for older matches.
+func (s *PostgresOddsSnapshotStorage) ScanOddsHistory(ctx context.Context, from, to time.Time, fn func(OddsHistoryRecord) error) error {
+	query := `
+	SELECT h.match_group_key, COALESCE(s.match_name, ''), split_part(h.match_group_key, '|', 1),
+		h.bet_key, h.bookmaker, h.odd, h.start_time, h.recorded_at
+	FROM odds_snapshot_history h
+	LEFT JOIN odds_snapshots s
+		ON s.match_group_key = h.match_group_key AND s.bet_key = h.bet_key AND s.bookmaker = h.bookmaker
+	WHERE h.recorded_at >= $1 AND h.recorded_at < $2 AND h.odd > 0
+	ORDER BY h.recorded_at, h.id
+	`
+	return scanOddsHistoryRows(ctx, s.db, query, from, to, fn)
+}
+
+// ScanOddsHistory streams research.bets_wide ($1 <= recorded_at < $2), which keeps the history of
+// finished matches.
+func (s *PostgresWarehouseStorage) ScanOddsHistory(ctx context.Context, from, to time.Time, fn func(OddsHistoryRecord) error) error {
+	query := `
+	SELECT match_group_key, match_name, sport, bet_key, bookmaker, odd, start_time, recorded_at
+	FROM research.bets_wide
+	WHERE recorded_at >= $1 AND recorded_at < $2
+	ORDER BY recorded_at, match_group_key, bet_key, bookmaker
+	`
+	return scanOddsHistoryRows(ctx, s.db, query, from, to, fn)
+}
+
+func scanOddsHistoryRows(ctx context.Context, db *sql.DB, query string, from, to time.Time, fn func(OddsHistoryRecord) error) error {
+	rows, err := db.QueryContext(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to query odds history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r OddsHistoryRecord
+		if err := rows.Scan(&r.MatchGroupKey, &r.MatchName, &r.Sport, &r.BetKey, &r.Bookmaker, &r.Odd, &r.StartTime, &r.RecordedAt); err != nil {
+			return err
+		}
+		r.StartTime, r.RecordedAt = r.StartTime.UTC(), r.RecordedAt.UTC()
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetOddsHistoryRange returns the odds of one bet recorded in [f.From, f.To), oldest first, and the number
+// of all matching points. Keyframes only repeat an unchanged odd and are skipped. With f.Interval the
+// history is downsampled to the last point of each bookmaker per interval (UTC epoch-aligned buckets).
+func (s *PostgresOddsSnapshotStorage) GetOddsHistoryRange(ctx context.Context, f OddsHistoryRangeFilter) ([]OddsHistorySample, int, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	add("match_group_key = $%d", f.MatchGroupKey)
+	add("bet_key = $%d", f.BetKey)
+	if f.Bookmaker != "" {
+		add("bookmaker = $%d", f.Bookmaker)
+	}
+	if !f.From.IsZero() {
+		add("recorded_at >= $%d", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		add("recorded_at < $%d", f.To.UTC())
+	}
+	where = append(where, "NOT keyframe")
+
+	points := `SELECT bookmaker, odd, recorded_at FROM odds_snapshot_history WHERE ` + strings.Join(where, " AND ")
+	if f.Interval > 0 {
+		args = append(args, f.Interval.Seconds())
+		points = fmt.Sprintf(`SELECT DISTINCT ON (floor(extract(epoch FROM recorded_at) / $%d), bookmaker) bookmaker, odd, recorded_at
+		FROM odds_snapshot_history WHERE %s
+		ORDER BY floor(extract(epoch FROM recorded_at) / $%d), bookmaker, recorded_at DESC`, len(args), strings.Join(where, " AND "), len(args))
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+points+`) p`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count odds history: %w", err)
+	}
+
+	query := `SELECT bookmaker, odd, recorded_at FROM (` + points + `) p ORDER BY recorded_at, bookmaker`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get odds history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OddsHistorySample
+	for rows.Next() {
+		var p OddsHistorySample
+		if err := rows.Scan(&p.Bookmaker, &p.Odd, &p.RecordedAt); err != nil {
+			return nil, 0, err
+		}
+		p.RecordedAt = p.RecordedAt.UTC()
+		out = append(out, p)
+	}
+	return out, total, rows.Err()
+}
+
+// oddsHistoryPartition is the partition holding the UTC day of t.
+func oddsHistoryPartition(t time.Time) string {
+	return oddsHistoryTable + "_" + t.UTC().Format(oddsHistoryPartitionDay)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// createOddsHistoryPartitions creates the missing day partitions from the day of from to the day of to.
+func createOddsHistoryPartitions(ctx context.Context, db execer, from, to time.Time) error {
+	day := from.UTC().Truncate(24 * time.Hour)
+	for ; !day.After(to.UTC()); day = day.AddDate(0, 0, 1) {
+		query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			oddsHistoryPartition(day), oddsHistoryTable, day.Format(time.DateOnly), day.AddDate(0, 0, 1).Format(time.DateOnly))
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to create partition %s: %w", oddsHistoryPartition(day), err)
+		}
+	}
+	return nil
+}
+
+// ApplyOddsHistoryRetention creates the partitions up to oddsHistoryPartitionAhead days after now and drops
+// the partitions whose day ended before now-retention (retention <= 0 = keep everything).
+func (s *PostgresOddsSnapshotStorage) ApplyOddsHistoryRetention(ctx context.Context, now time.Time, retention time.Duration) (int, error) {
+	if err := createOddsHistoryPartitions(ctx, s.db, now, now.AddDate(0, 0, oddsHistoryPartitionAhead)); err != nil {
+		return 0, err
+	}
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT c.relname FROM pg_inherits i
+	JOIN pg_class c ON c.oid = i.inhrelid
+	JOIN pg_class p ON p.oid = i.inhparent
+	JOIN pg_namespace n ON n.oid = p.relnamespace
+	WHERE p.relname = $1 AND n.nspname = current_schema()`, oddsHistoryTable)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list odds history partitions: %w", err)
+	}
+	var partitions []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		partitions = append(partitions, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	cutoff := now.Add(-retention).UTC()
+	dropped := 0
+	for _, name := range partitions {
+		day, err := time.Parse(oddsHistoryPartitionDay, strings.TrimPrefix(name, oddsHistoryTable+"_"))
+		if err != nil || day.AddDate(0, 0, 1).After(cutoff) {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", name, err)
+		}
+		dropped++
+	}
+	return dropped, nil
+}
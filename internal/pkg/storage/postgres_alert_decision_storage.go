@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresAlertDecisionStorage implements AlertDecisionStorage
+var _ AlertDecisionStorage = (*PostgresAlertDecisionStorage)(nil)
+
+// PostgresAlertDecisionStorage stores the alert decision log (table alert_decisions). Like value_bet_history
+// it is not touched by /db/clear and the periodic full cleanup: rows go by value_calculator.decision_log.retention.
+type PostgresAlertDecisionStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresAlertDecisionStorage creates a new PostgreSQL storage for the alert decision log.
+func NewPostgresAlertDecisionStorage(cfg *config.PostgresConfig) (*PostgresAlertDecisionStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresAlertDecisionStorage{db: db}
+
+	slog.Info("PostgreSQL alert decision storage initialized successfully")
+	return s, nil
+}
+
+// StoreAlertDecisions inserts the decisions in one statement per chunk.
+func (s *PostgresAlertDecisionStorage) StoreAlertDecisions(ctx context.Context, ds []AlertDecision) error {
+	const cols = 12
+	const chunkSize = 1000 // 12 params per row
+	for start := 0; start < len(ds); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ds) {
+			end = len(ds)
+		}
+		chunk := ds[start:end]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*cols)
+		for i, d := range chunk {
+			ph := make([]string, cols)
+			for j := range ph {
+				ph[j] = fmt.Sprintf("$%d", i*cols+j+1)
+			}
+			placeholders = append(placeholders, "("+strings.Join(ph, ",")+")")
+			args = append(args,
+				d.At.UTC(), d.Pipeline, d.ChatID, d.MatchGroupKey, d.MatchName, d.BetKey, d.Bookmaker,
+				d.MaxOdd, d.DiffPercent, d.Threshold, d.Decision, d.Reason,
+			)
+		}
+
+		query := `
+		INSERT INTO alert_decisions (
+			at, pipeline, chat_id, match_group_key, match_name, bet_key, bookmaker,
+			max_odd, diff_percent, threshold, decision, reason
+		) VALUES ` + strings.Join(placeholders, ",")
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("StoreAlertDecisions failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetAlertDecisions returns decisions matching f, newest first.
+func (s *PostgresAlertDecisionStorage) GetAlertDecisions(ctx context.Context, f AlertDecisionFilter) ([]AlertDecision, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.BetKey != "" {
+		add("bet_key = $%d", f.BetKey)
+	}
+	if f.MatchGroupKey != "" {
+		add("match_group_key = $%d", f.MatchGroupKey)
+	}
+	if f.Match != "" {
+		add("match_name ILIKE '%%' || $%d || '%%'", f.Match)
+	}
+	if f.ChatID != 0 {
+		add("chat_id = $%d", f.ChatID)
+	}
+	if f.Decision != "" {
+		add("decision = $%d", f.Decision)
+	}
+	if !f.Since.IsZero() {
+		add("at >= $%d", f.Since.UTC())
+	}
+	query := `SELECT at, pipeline, chat_id, match_group_key, match_name, bet_key, bookmaker, max_odd,
+		diff_percent, threshold, decision, reason FROM alert_decisions`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY at DESC, id DESC"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AlertDecision
+	for rows.Next() {
+		var d AlertDecision
+		if err := rows.Scan(&d.At, &d.Pipeline, &d.ChatID, &d.MatchGroupKey, &d.MatchName, &d.BetKey, &d.Bookmaker,
+			&d.MaxOdd, &d.DiffPercent, &d.Threshold, &d.Decision, &d.Reason); err != nil {
+			return nil, err
+		}
+		d.At = d.At.UTC()
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// DeleteAlertDecisionsBefore removes decisions older than before.
+func (s *PostgresAlertDecisionStorage) DeleteAlertDecisionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM alert_decisions WHERE at < $1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete alert decisions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Close closes the database connection.
+func (s *PostgresAlertDecisionStorage) Close() error {
+	return s.db.Close()
+}
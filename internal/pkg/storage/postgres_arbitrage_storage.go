@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresArbitrageStorage implements ArbitrageStorage
+var _ ArbitrageStorage = (*PostgresArbitrageStorage)(nil)
+
+// PostgresArbitrageStorage stores arbitrages (table arbitrages); legs are kept as JSONB.
+type PostgresArbitrageStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresArbitrageStorage creates a new PostgreSQL storage for arbitrages.
+func NewPostgresArbitrageStorage(cfg *config.PostgresConfig) (*PostgresArbitrageStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresArbitrageStorage{db: db}
+
+	slog.Info("PostgreSQL arbitrage storage initialized successfully")
+	return s, nil
+}
+
+// StoreArbitrages saves the arbitrages of one cycle in one statement per chunk.
+func (s *PostgresArbitrageStorage) StoreArbitrages(ctx context.Context, arbs []Arbitrage) error {
+	if len(arbs) == 0 {
+		return nil
+	}
+
+	const chunkSize = 1000 // 11 params per row
+	for start := 0; start < len(arbs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(arbs) {
+			end = len(arbs)
+		}
+		chunk := arbs[start:end]
+
+		var placeholders []string
+		args := make([]interface{}, 0, len(chunk)*11)
+		for i, arb := range chunk {
+			legs, err := json.Marshal(arb.Legs)
+			if err != nil {
+				return fmt.Errorf("failed to encode arbitrage legs: %w", err)
+			}
+			bookmakers := make([]string, 0, len(arb.Legs))
+			for _, leg := range arb.Legs {
+				bookmakers = append(bookmakers, leg.Bookmaker)
+			}
+			base := i * 11
+			placeholders = append(placeholders, fmt.Sprintf(
+				"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+				base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9, base+10, base+11,
+			))
+			args = append(args,
+				arb.MatchGroupKey, arb.MatchName, arb.StartTime.UTC(), arb.Sport,
+				arb.EventType, arb.MarketKey, strings.Join(bookmakers, ","), string(legs),
+				arb.ImpliedSum, arb.ProfitPercent, arb.CalculatedAt.UTC(),
+			)
+		}
+
+		query := `
+		INSERT INTO arbitrages (
+			match_group_key, match_name, start_time, sport,
+			event_type, market_key, bookmakers, legs,
+			implied_sum, profit_percent, calculated_at
+		) VALUES ` + strings.Join(placeholders, ",") + `
+		ON CONFLICT (match_group_key, market_key, calculated_at) DO NOTHING
+		`
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("StoreArbitrages failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// CleanArbitrages removes all records from the arbitrages table.
+func (s *PostgresArbitrageStorage) CleanArbitrages(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM arbitrages`); err != nil {
+		return fmt.Errorf("failed to clean arbitrages table: %w", err)
+	}
+	slog.Info("Cleaned arbitrages table")
+	return nil
+}
+
+// Close closes the database connection.
+func (s *PostgresArbitrageStorage) Close() error {
+	return s.db.Close()
+}
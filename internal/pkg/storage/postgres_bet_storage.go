@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresBetStorage implements BetStorage
+var _ BetStorage = (*PostgresBetStorage)(nil)
+
+// PostgresBetStorage stores placed bets (table bets).
+type PostgresBetStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresBetStorage creates a new PostgreSQL storage for placed bets.
+func NewPostgresBetStorage(cfg *config.PostgresConfig) (*PostgresBetStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresBetStorage{db: db}
+
+	slog.Info("PostgreSQL bet storage initialized successfully")
+	return s, nil
+}
+
+const betColumns = `id, value_bet_id, user_id, match_group_key, match_name, home_team, away_team,
+	start_time, sport, event_type, outcome_type, parameter, bet_key, bookmaker, odd, stake,
+	fair_probability, value_percent, status, profit, home_score, away_score, placed_at, settled_at`
+
+// StoreBet inserts b and sets its ID.
+func (s *PostgresBetStorage) StoreBet(ctx context.Context, b *Bet) error {
+	if b.Status == "" {
+		b.Status = BetStatusOpen
+	}
+	query := `
+	INSERT INTO bets (
+		value_bet_id, user_id, match_group_key, match_name, home_team, away_team,
+		start_time, sport, event_type, outcome_type, parameter, bet_key, bookmaker, odd, stake,
+		fair_probability, value_percent, status, placed_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	RETURNING id
+	`
+	err := s.db.QueryRowContext(ctx, query,
+		b.ValueBetID, b.UserID, b.MatchGroupKey, b.MatchName, b.HomeTeam, b.AwayTeam,
+		b.StartTime.UTC(), b.Sport, b.EventType, b.OutcomeType, b.Parameter, b.BetKey, b.Bookmaker, b.Odd, b.Stake,
+		b.FairProbability, b.ValuePercent, b.Status, b.PlacedAt.UTC(),
+	).Scan(&b.ID)
+	if err != nil {
+		return fmt.Errorf("failed to store bet: %w", err)
+	}
+	return nil
+}
+
+// GetBets returns bets of userID (0 = all users), newest first.
+func (s *PostgresBetStorage) GetBets(ctx context.Context, userID int64) ([]Bet, error) {
+	query := `SELECT ` + betColumns + ` FROM bets`
+	var args []interface{}
+	if userID != 0 {
+		query += ` WHERE user_id = $1`
+		args = append(args, userID)
+	}
+	query += ` ORDER BY placed_at DESC, id DESC`
+	return s.queryBets(ctx, query, args...)
+}
+
+// GetOpenBets returns open bets of matches that started before startedBefore.
+func (s *PostgresBetStorage) GetOpenBets(ctx context.Context, startedBefore time.Time) ([]Bet, error) {
+	query := `SELECT ` + betColumns + ` FROM bets WHERE status = $1 AND start_time < $2 ORDER BY start_time`
+	return s.queryBets(ctx, query, BetStatusOpen, startedBefore.UTC())
+}
+
+func (s *PostgresBetStorage) queryBets(ctx context.Context, query string, args ...interface{}) ([]Bet, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bets: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Bet
+	for rows.Next() {
+		var b Bet
+		var homeScore, awayScore sql.NullInt64
+		var settledAt sql.NullTime
+		if err := rows.Scan(
+			&b.ID, &b.ValueBetID, &b.UserID, &b.MatchGroupKey, &b.MatchName, &b.HomeTeam, &b.AwayTeam,
+			&b.StartTime, &b.Sport, &b.EventType, &b.OutcomeType, &b.Parameter, &b.BetKey, &b.Bookmaker, &b.Odd, &b.Stake,
+			&b.FairProbability, &b.ValuePercent, &b.Status, &b.Profit, &homeScore, &awayScore, &b.PlacedAt, &settledAt,
+		); err != nil {
+			return nil, err
+		}
+		b.StartTime, b.PlacedAt = b.StartTime.UTC(), b.PlacedAt.UTC()
+		if homeScore.Valid && awayScore.Valid {
+			h, a := int(homeScore.Int64), int(awayScore.Int64)
+			b.HomeScore, b.AwayScore = &h, &a
+		}
+		if settledAt.Valid {
+			t := settledAt.Time.UTC()
+			b.SettledAt = &t
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// SettleBet saves the settlement of b.
+func (s *PostgresBetStorage) SettleBet(ctx context.Context, b Bet) error {
+	var settledAt interface{}
+	if b.SettledAt != nil {
+		settledAt = b.SettledAt.UTC()
+	}
+	query := `UPDATE bets SET status = $2, profit = $3, home_score = $4, away_score = $5, settled_at = $6 WHERE id = $1`
+	if _, err := s.db.ExecContext(ctx, query, b.ID, b.Status, b.Profit, b.HomeScore, b.AwayScore, settledAt); err != nil {
+		return fmt.Errorf("failed to settle bet %d: %w", b.ID, err)
+	}
+	return nil
+}
+
+// Close closes the database connection.
+func (s *PostgresBetStorage) Close() error {
+	return s.db.Close()
+}
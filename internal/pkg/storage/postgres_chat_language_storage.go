@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresChatLanguageStorage implements ChatLanguageStorage
+var _ ChatLanguageStorage = (*PostgresChatLanguageStorage)(nil)
+
+// PostgresChatLanguageStorage stores the languages of chats (table chat_languages). The table is not
+// touched by /db/clear and the periodic full cleanup.
+type PostgresChatLanguageStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresChatLanguageStorage creates a new PostgreSQL storage for chat languages.
+func NewPostgresChatLanguageStorage(cfg *config.PostgresConfig) (*PostgresChatLanguageStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresChatLanguageStorage{db: db}
+
+	slog.Info("PostgreSQL chat language storage initialized successfully")
+	return s, nil
+}
+
+// StoreChatLanguage sets the language of chatID.
+func (s *PostgresChatLanguageStorage) StoreChatLanguage(ctx context.Context, chatID int64, language string) error {
+	query := `
+	INSERT INTO chat_languages (chat_id, language, updated_at)
+	VALUES ($1, $2, NOW())
+	ON CONFLICT (chat_id) DO UPDATE SET
+		language = EXCLUDED.language,
+		updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, chatID, language); err != nil {
+		return fmt.Errorf("failed to store chat language: %w", err)
+	}
+	return nil
+}
+
+// GetChatLanguages returns the language of every chat that chose one.
+func (s *PostgresChatLanguageStorage) GetChatLanguages(ctx context.Context) (map[int64]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT chat_id, language FROM chat_languages`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat languages: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[int64]string{}
+	for rows.Next() {
+		var chatID int64
+		var language string
+		if err := rows.Scan(&chatID, &language); err != nil {
+			return nil, err
+		}
+		out[chatID] = language
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresChatLanguageStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresCycleStateStorage implements CycleStateStorage
+var _ CycleStateStorage = (*PostgresCycleStateStorage)(nil)
+
+// PostgresCycleStateStorage stores the async cycle state (table calculator_cycle_state), one JSON document
+// per name. The table is not touched by /db/clear and the periodic full cleanup.
+type PostgresCycleStateStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresCycleStateStorage creates a new PostgreSQL storage for the async cycle state.
+func NewPostgresCycleStateStorage(cfg *config.PostgresConfig) (*PostgresCycleStateStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresCycleStateStorage{db: db}
+
+	slog.Info("PostgreSQL cycle state storage initialized successfully")
+	return s, nil
+}
+
+// SaveCycleState replaces the state saved under name.
+func (s *PostgresCycleStateStorage) SaveCycleState(ctx context.Context, name string, data []byte) error {
+	query := `
+	INSERT INTO calculator_cycle_state (name, data, updated_at) VALUES ($1, $2, $3)
+	ON CONFLICT (name) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at`
+	if _, err := s.db.ExecContext(ctx, query, name, string(data), time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save cycle state %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadCycleState returns the state saved under name and when it was saved; nil data if none.
+func (s *PostgresCycleStateStorage) LoadCycleState(ctx context.Context, name string) ([]byte, time.Time, error) {
+	var data string
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT data, updated_at FROM calculator_cycle_state WHERE name = $1`, name).Scan(&data, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to load cycle state %s: %w", name, err)
+	}
+	return []byte(data), updatedAt.UTC(), nil
+}
+
+// Close closes the database connection.
+func (s *PostgresCycleStateStorage) Close() error {
+	return s.db.Close()
+}
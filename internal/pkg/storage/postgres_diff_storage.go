@@ -56,7 +56,7 @@ func parseDSNForMultipleHosts(dsn string) (string, error) {
 		singleHostDSN := re.ReplaceAllString(dsn, fmt.Sprintf("host=%s", host))
 
 		// Try to connect
-		db, err := sql.Open("postgres", singleHostDSN)
+		db, err := sql.Open(postgresDriver, singleHostDSN)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to open connection to %s: %w", host, err)
 			continue
@@ -92,7 +92,7 @@ func NewPostgresDiffStorage(cfg *config.PostgresConfig) (*PostgresDiffStorage, e
 		return nil, err
 	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open(postgresDriver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
 	}
@@ -106,50 +106,10 @@ func NewPostgresDiffStorage(cfg *config.PostgresConfig) (*PostgresDiffStorage, e
 
 	storage := &PostgresDiffStorage{db: db}
 
-	// Initialize schema
-	if err := storage.initSchema(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
 	slog.Info("PostgreSQL diff storage initialized successfully")
 	return storage, nil
 }
 
-func (s *PostgresDiffStorage) initSchema(ctx context.Context) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS diff_bets (
-		id SERIAL PRIMARY KEY,
-		match_group_key VARCHAR(500) NOT NULL,
-		match_name VARCHAR(500) NOT NULL,
-		start_time TIMESTAMP NOT NULL,
-		sport VARCHAR(100) NOT NULL,
-		event_type VARCHAR(100) NOT NULL,
-		outcome_type VARCHAR(100) NOT NULL,
-		parameter VARCHAR(100) NOT NULL DEFAULT '',
-		bet_key VARCHAR(500) NOT NULL,
-		bookmakers INTEGER NOT NULL,
-		min_bookmaker VARCHAR(100) NOT NULL,
-		min_odd DECIMAL(10, 4) NOT NULL,
-		max_bookmaker VARCHAR(100) NOT NULL,
-		max_odd DECIMAL(10, 4) NOT NULL,
-		diff_abs DECIMAL(10, 4) NOT NULL,
-		diff_percent DECIMAL(10, 4) NOT NULL,
-		calculated_at TIMESTAMP NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		UNIQUE(match_group_key, bet_key, calculated_at)
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_diff_bets_match_group_key ON diff_bets(match_group_key);
-	CREATE INDEX IF NOT EXISTS idx_diff_bets_bet_key ON diff_bets(bet_key);
-	CREATE INDEX IF NOT EXISTS idx_diff_bets_calculated_at ON diff_bets(calculated_at DESC);
-	CREATE INDEX IF NOT EXISTS idx_diff_bets_diff_percent ON diff_bets(diff_percent DESC);
-	CREATE INDEX IF NOT EXISTS idx_diff_bets_unique_check ON diff_bets(match_group_key, bet_key, calculated_at);
-	`
-
-	_, err := s.db.ExecContext(ctx, query)
-	return err
-}
-
 // extractDiffBetFields extracts fields from a DiffBet-like struct using reflection
 func extractDiffBetFields(diffInterface interface{}) (matchGroupKey, matchName, sport, eventType, outcomeType, parameter, betKey, minBookmaker, maxBookmaker string, startTime, calculatedAt time.Time, bookmakers int, minOdd, maxOdd, diffAbs, diffPercent float64, err error) {
 	v := reflect.ValueOf(diffInterface)
@@ -408,6 +368,11 @@ func (s *PostgresDiffStorage) CleanDiffBets(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that the database is reachable (/readyz)
+func (s *PostgresDiffStorage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 // Close closes the database connection
 func (s *PostgresDiffStorage) Close() error {
 	return s.db.Close()
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tracing"
+)
+
+// postgresDriver is the database/sql driver the Postgres storages open: lib/pq with a span for every
+// query run within a trace (value cycles, line movement), so slow cycles show the query that took the time.
+const postgresDriver = "postgres-traced"
+
+func init() {
+	sql.Register(postgresDriver, tracing.WrapDriver(&pq.Driver{}))
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresIgnoreStorage implements IgnoreStorage
+var _ IgnoreStorage = (*PostgresIgnoreStorage)(nil)
+
+// PostgresIgnoreStorage stores the ignored matches list (table ignored_matches).
+// The table is not touched by /db/clear and the periodic full cleanup.
+type PostgresIgnoreStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresIgnoreStorage creates a new PostgreSQL storage for ignored matches.
+func NewPostgresIgnoreStorage(cfg *config.PostgresConfig) (*PostgresIgnoreStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresIgnoreStorage{db: db}
+
+	slog.Info("PostgreSQL ignore storage initialized successfully")
+	return s, nil
+}
+
+// StoreIgnore adds or replaces the entry for m.MatchGroupKey.
+func (s *PostgresIgnoreStorage) StoreIgnore(ctx context.Context, m IgnoredMatch) error {
+	query := `
+	INSERT INTO ignored_matches (match_group_key, match_name, reason, expires_at, created_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (match_group_key) DO UPDATE SET
+		match_name = EXCLUDED.match_name,
+		reason = EXCLUDED.reason,
+		expires_at = EXCLUDED.expires_at,
+		created_at = EXCLUDED.created_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, m.MatchGroupKey, m.MatchName, m.Reason, m.ExpiresAt.UTC(), m.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to store ignored match: %w", err)
+	}
+	return nil
+}
+
+// DeleteIgnore removes the entry for matchGroupKey.
+func (s *PostgresIgnoreStorage) DeleteIgnore(ctx context.Context, matchGroupKey string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM ignored_matches WHERE match_group_key = $1`, matchGroupKey); err != nil {
+		return fmt.Errorf("failed to delete ignored match: %w", err)
+	}
+	return nil
+}
+
+// GetActiveIgnores returns entries that expire after now; expired ones are deleted.
+func (s *PostgresIgnoreStorage) GetActiveIgnores(ctx context.Context, now time.Time) ([]IgnoredMatch, error) {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM ignored_matches WHERE expires_at <= $1`, now.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to clean expired ignored matches: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT match_group_key, match_name, reason, expires_at, created_at
+	FROM ignored_matches ORDER BY expires_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ignored matches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []IgnoredMatch
+	for rows.Next() {
+		var m IgnoredMatch
+		if err := rows.Scan(&m.MatchGroupKey, &m.MatchName, &m.Reason, &m.ExpiresAt, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.ExpiresAt, m.CreatedAt = m.ExpiresAt.UTC(), m.CreatedAt.UTC()
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresIgnoreStorage) Close() error {
+	return s.db.Close()
+}
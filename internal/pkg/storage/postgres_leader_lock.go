@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresLeaderLock implements LeaderLock
+var _ LeaderLock = (*PostgresLeaderLock)(nil)
+
+// defaultLeaderLockKey is the advisory lock key when value_calculator.leader_election.lock_key is unset.
+const defaultLeaderLockKey = 7351001
+
+// PostgresLeaderLock is a session-level advisory lock (pg_try_advisory_lock). The lock belongs to the
+// database session, so it is taken on one pinned connection: Postgres releases it when that session
+// ends, and a replica that lost its connection lets the next one take over.
+type PostgresLeaderLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn // session holding the lock; nil while not held
+}
+
+// NewPostgresLeaderLock creates a leader lock on advisory lock key (0 = default key).
+func NewPostgresLeaderLock(cfg *config.PostgresConfig, key int64) (*PostgresLeaderLock, error) {
+	if key == 0 {
+		key = defaultLeaderLockKey
+	}
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+	// No idle connections: a connection given back ends its session, and with it any lock it held
+	db.SetMaxIdleConns(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	slog.Info("PostgreSQL leader lock initialized successfully", "key", key)
+	return &PostgresLeaderLock{db: db, key: key}, nil
+}
+
+// TryAcquire takes the lock if it is free; while held, checks that the session holding it is alive.
+func (l *PostgresLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err != nil {
+			l.dropConn()
+			return false, fmt.Errorf("leader lock session lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Release unlocks the advisory lock if held.
+func (l *PostgresLeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	l.dropConn()
+	if err != nil {
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
+
+// dropConn ends the session of the lock (no idle connections are kept), which releases the lock on the
+// server if it is still held there.
+func (l *PostgresLeaderLock) dropConn() {
+	_ = l.conn.Close()
+	l.conn = nil
+}
+
+// Close closes the database connection.
+func (l *PostgresLeaderLock) Close() error {
+	l.mu.Lock()
+	if l.conn != nil {
+		l.dropConn()
+	}
+	l.mu.Unlock()
+	return l.db.Close()
+}
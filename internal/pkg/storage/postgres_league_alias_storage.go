@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresLeagueAliasStorage implements LeagueAliasStorage
+var _ LeagueAliasStorage = (*PostgresLeagueAliasStorage)(nil)
+
+// PostgresLeagueAliasStorage stores the league aliases added at runtime (table league_aliases).
+// The table is not touched by /db/clear and the periodic full cleanup.
+type PostgresLeagueAliasStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresLeagueAliasStorage creates a new PostgreSQL storage for league aliases.
+func NewPostgresLeagueAliasStorage(cfg *config.PostgresConfig) (*PostgresLeagueAliasStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresLeagueAliasStorage{db: db}
+
+	slog.Info("PostgreSQL league alias storage initialized successfully")
+	return s, nil
+}
+
+// StoreLeagueAlias adds or replaces the alias.
+func (s *PostgresLeagueAliasStorage) StoreLeagueAlias(ctx context.Context, a LeagueAlias) error {
+	query := `
+	INSERT INTO league_aliases (alias_key, alias, canonical, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (alias_key) DO UPDATE SET
+		alias = EXCLUDED.alias,
+		canonical = EXCLUDED.canonical,
+		created_at = EXCLUDED.created_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, models.AliasKey(a.Alias), a.Alias, a.Canonical, a.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to store league alias: %w", err)
+	}
+	return nil
+}
+
+// DeleteLeagueAlias removes the alias.
+func (s *PostgresLeagueAliasStorage) DeleteLeagueAlias(ctx context.Context, alias string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM league_aliases WHERE alias_key = $1`, models.AliasKey(alias)); err != nil {
+		return fmt.Errorf("failed to delete league alias: %w", err)
+	}
+	return nil
+}
+
+// GetLeagueAliases returns all aliases, oldest first.
+func (s *PostgresLeagueAliasStorage) GetLeagueAliases(ctx context.Context) ([]LeagueAlias, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT alias, canonical, created_at FROM league_aliases ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get league aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []LeagueAlias
+	for rows.Next() {
+		var a LeagueAlias
+		if err := rows.Scan(&a.Alias, &a.Canonical, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.CreatedAt = a.CreatedAt.UTC()
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresLeagueAliasStorage) Close() error {
+	return s.db.Close()
+}
@@ -33,7 +33,7 @@ func NewPostgresOddsSnapshotStorage(cfg *config.PostgresConfig) (*PostgresOddsSn
 		return nil, err
 	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open(postgresDriver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
 	}
@@ -45,65 +45,15 @@ func NewPostgresOddsSnapshotStorage(cfg *config.PostgresConfig) (*PostgresOddsSn
 	}
 
 	s := &PostgresOddsSnapshotStorage{db: db}
-	if err := s.initSchema(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	// Tables come from the migrations; day partitions of the history are created ahead by the storage
+	if err := createOddsHistoryPartitions(ctx, s.db, time.Now(), time.Now().AddDate(0, 0, oddsHistoryPartitionAhead)); err != nil {
+		return nil, err
 	}
 
 	slog.Info("PostgreSQL odds snapshot storage initialized successfully")
 	return s, nil
 }
 
-func (s *PostgresOddsSnapshotStorage) initSchema(ctx context.Context) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS odds_snapshots (
-		id SERIAL PRIMARY KEY,
-		match_group_key VARCHAR(500) NOT NULL,
-		match_name VARCHAR(500) NOT NULL,
-		start_time TIMESTAMP NOT NULL,
-		sport VARCHAR(100) NOT NULL,
-		event_type VARCHAR(100) NOT NULL,
-		outcome_type VARCHAR(100) NOT NULL,
-		parameter VARCHAR(100) NOT NULL DEFAULT '',
-		bet_key VARCHAR(500) NOT NULL,
-		bookmaker VARCHAR(100) NOT NULL,
-		odd DECIMAL(10, 4) NOT NULL,
-		max_odd DECIMAL(10, 4),
-		min_odd DECIMAL(10, 4),
-		recorded_at TIMESTAMP NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		UNIQUE(match_group_key, bet_key, bookmaker)
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_odds_snapshots_match_bet_bk ON odds_snapshots(match_group_key, bet_key, bookmaker);
-	CREATE INDEX IF NOT EXISTS idx_odds_snapshots_start_time ON odds_snapshots(start_time);
-	`
-	if _, err := s.db.ExecContext(ctx, query); err != nil {
-		return err
-	}
-	// Migration: add max_odd/min_odd if table existed without them
-	_, _ = s.db.ExecContext(ctx, `ALTER TABLE odds_snapshots ADD COLUMN IF NOT EXISTS max_odd DECIMAL(10, 4)`)
-	_, _ = s.db.ExecContext(ctx, `ALTER TABLE odds_snapshots ADD COLUMN IF NOT EXISTS min_odd DECIMAL(10, 4)`)
-	_, _ = s.db.ExecContext(ctx, `UPDATE odds_snapshots SET max_odd = odd WHERE max_odd IS NULL`)
-	_, _ = s.db.ExecContext(ctx, `UPDATE odds_snapshots SET min_odd = odd WHERE min_odd IS NULL`)
-
-	// History of (odd, time) per key for timeline in alerts
-	historyQuery := `
-	CREATE TABLE IF NOT EXISTS odds_snapshot_history (
-		id SERIAL PRIMARY KEY,
-		match_group_key VARCHAR(500) NOT NULL,
-		bet_key VARCHAR(500) NOT NULL,
-		bookmaker VARCHAR(100) NOT NULL,
-		odd DECIMAL(10, 4) NOT NULL,
-		recorded_at TIMESTAMP NOT NULL,
-		start_time TIMESTAMP NOT NULL
-	);
-	CREATE INDEX IF NOT EXISTS idx_odds_snapshot_history_key ON odds_snapshot_history(match_group_key, bet_key, bookmaker);
-	CREATE INDEX IF NOT EXISTS idx_odds_snapshot_history_start ON odds_snapshot_history(start_time);
-	`
-	_, _ = s.db.ExecContext(ctx, historyQuery)
-	return nil
-}
-
 // StoreOddsSnapshot saves current odd and updates max_odd/min_odd for (match_group_key, bet_key, bookmaker).
 func (s *PostgresOddsSnapshotStorage) StoreOddsSnapshot(ctx context.Context, matchGroupKey, matchName, sport, eventType, outcomeType, parameter, betKey, bookmaker string, startTime time.Time, odd float64, recordedAt time.Time) error {
 	query := `
@@ -198,7 +148,7 @@ func (s *PostgresOddsSnapshotStorage) GetLastOddsSnapshotsBatch(ctx context.Cont
 // AppendOddsHistory appends one (odd, recordedAt) point for timeline.
 func (s *PostgresOddsSnapshotStorage) AppendOddsHistory(ctx context.Context, matchGroupKey, betKey, bookmaker string, startTime time.Time, odd float64, recordedAt time.Time) error {
 	query := `INSERT INTO odds_snapshot_history (match_group_key, bet_key, bookmaker, odd, recorded_at, start_time) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := s.db.ExecContext(ctx, query, matchGroupKey, betKey, bookmaker, odd, recordedAt, startTime)
+	_, err := s.db.ExecContext(ctx, query, matchGroupKey, betKey, bookmaker, odd, recordedAt.UTC(), startTime)
 	return err
 }
 
@@ -269,7 +219,7 @@ func (s *PostgresOddsSnapshotStorage) AppendOddsHistoryBatch(ctx context.Context
 	}
 	
 	// Process in chunks to avoid parameter limit
-	const chunkSize = 2000 // ~6 params per row = ~12000 params per chunk (safe)
+	const chunkSize = 2000 // ~7 params per row = ~14000 params per chunk (safe)
 	
 	for start := 0; start < len(history); start += chunkSize {
 		end := start + chunkSize
@@ -278,17 +228,17 @@ func (s *PostgresOddsSnapshotStorage) AppendOddsHistoryBatch(ctx context.Context
 		}
 		chunk := history[start:end]
 		
-		// Build VALUES ($1,$2,...,$6), ($7,$8,...,$12), ...
+		// Build VALUES ($1,$2,...,$7), ($8,$9,...,$14), ...
 		var placeholders []string
-		args := make([]interface{}, 0, len(chunk)*6)
+		args := make([]interface{}, 0, len(chunk)*7)
 		for i, h := range chunk {
-			baseIdx := i * 6
-			placeholders = append(placeholders, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d)",
-				baseIdx+1, baseIdx+2, baseIdx+3, baseIdx+4, baseIdx+5, baseIdx+6))
-			args = append(args, h.MatchGroupKey, h.BetKey, h.Bookmaker, h.Odd, h.RecordedAt, h.StartTime)
+			baseIdx := i * 7
+			placeholders = append(placeholders, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+				baseIdx+1, baseIdx+2, baseIdx+3, baseIdx+4, baseIdx+5, baseIdx+6, baseIdx+7))
+			args = append(args, h.MatchGroupKey, h.BetKey, h.Bookmaker, h.Odd, h.RecordedAt.UTC(), h.StartTime, h.Keyframe)
 		}
 		
-		query := `INSERT INTO odds_snapshot_history (match_group_key, bet_key, bookmaker, odd, recorded_at, start_time) VALUES ` +
+		query := `INSERT INTO odds_snapshot_history (match_group_key, bet_key, bookmaker, odd, recorded_at, start_time, keyframe) VALUES ` +
 			strings.Join(placeholders, ",")
 		
 		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
@@ -300,6 +250,7 @@ func (s *PostgresOddsSnapshotStorage) AppendOddsHistoryBatch(ctx context.Context
 }
 
 // GetOddsHistory returns recent points in chronological order (oldest first), at most limit.
+// Keyframe rows only repeat an unchanged odd and are skipped.
 func (s *PostgresOddsSnapshotStorage) GetOddsHistory(ctx context.Context, matchGroupKey, betKey, bookmaker string, limit int) ([]OddsHistoryPoint, error) {
 	if limit <= 0 {
 		limit = 30
@@ -307,7 +258,7 @@ func (s *PostgresOddsSnapshotStorage) GetOddsHistory(ctx context.Context, matchG
 	query := `
 	SELECT odd, recorded_at FROM (
 		SELECT odd, recorded_at FROM odds_snapshot_history
-		WHERE match_group_key = $1 AND bet_key = $2 AND bookmaker = $3
+		WHERE match_group_key = $1 AND bet_key = $2 AND bookmaker = $3 AND NOT keyframe
 		ORDER BY recorded_at DESC
 		LIMIT $4
 	) sub ORDER BY recorded_at ASC
@@ -335,32 +286,26 @@ func (s *PostgresOddsSnapshotStorage) ResetExtremesAfterAlert(ctx context.Contex
 	return err
 }
 
-// CleanSnapshotsForStartedMatches deletes snapshots and history for matches that have already started.
+// CleanSnapshotsForStartedMatches deletes snapshots for matches that have already started.
+// Their history stays until its day partition is past the retention (ApplyOddsHistoryRetention).
 func (s *PostgresOddsSnapshotStorage) CleanSnapshotsForStartedMatches(ctx context.Context) error {
-	res1, err := s.db.ExecContext(ctx, `DELETE FROM odds_snapshots WHERE start_time < NOW()`)
+	res, err := s.db.ExecContext(ctx, `DELETE FROM odds_snapshots WHERE start_time < NOW()`)
 	if err != nil {
 		return fmt.Errorf("failed to clean odds_snapshots: %w", err)
 	}
-	res2, err := s.db.ExecContext(ctx, `DELETE FROM odds_snapshot_history WHERE start_time < NOW()`)
-	if err != nil {
-		return fmt.Errorf("failed to clean odds_snapshot_history: %w", err)
-	}
-	n1, _ := res1.RowsAffected()
-	n2, _ := res2.RowsAffected()
-	if n1 > 0 || n2 > 0 {
-		slog.Info("Cleaned odds snapshots for started matches", "snapshots_deleted", n1, "history_deleted", n2)
+	if n, _ := res.RowsAffected(); n > 0 {
+		slog.Info("Cleaned odds snapshots for started matches", "snapshots_deleted", n)
 	}
 	return nil
 }
 
-// CleanAll truncates odds_snapshots and odds_snapshot_history (full clear for periodic DB cleanup).
+// CleanAll truncates odds_snapshots (full clear for periodic DB cleanup). odds_snapshot_history is
+// not truncated: whole day partitions are dropped by ApplyOddsHistoryRetention.
 func (s *PostgresOddsSnapshotStorage) CleanAll(ctx context.Context) error {
-	for _, table := range []string{"odds_snapshots", "odds_snapshot_history"} {
-		if _, err := s.db.ExecContext(ctx, "TRUNCATE TABLE "+table+" RESTART IDENTITY"); err != nil {
-			return fmt.Errorf("failed to truncate %s: %w", table, err)
-		}
-		slog.Info("Truncated table", "table", table)
+	if _, err := s.db.ExecContext(ctx, "TRUNCATE TABLE odds_snapshots RESTART IDENTITY"); err != nil {
+		return fmt.Errorf("failed to truncate odds_snapshots: %w", err)
 	}
+	slog.Info("Truncated table", "table", "odds_snapshots")
 	return nil
 }
 
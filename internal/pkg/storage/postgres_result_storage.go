@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresResultStorage implements ResultStorage
+var _ ResultStorage = (*PostgresResultStorage)(nil)
+
+// PostgresResultStorage caches final match results (table match_results).
+type PostgresResultStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresResultStorage creates a new PostgreSQL cache of match results.
+func NewPostgresResultStorage(cfg *config.PostgresConfig) (*PostgresResultStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresResultStorage{db: db}
+
+	slog.Info("PostgreSQL result storage initialized successfully")
+	return s, nil
+}
+
+// GetMatchResult returns the cached result, or nil if there is none.
+func (s *PostgresResultStorage) GetMatchResult(ctx context.Context, sport, homeTeam, awayTeam string, matchDate time.Time) (*MatchResult, error) {
+	r := MatchResult{Sport: strings.ToLower(sport), HomeTeam: strings.ToLower(homeTeam), AwayTeam: strings.ToLower(awayTeam)}
+	err := s.db.QueryRowContext(ctx, `
+	SELECT match_date, status, home_score, away_score, fetched_at FROM match_results
+	WHERE sport = $1 AND home_team = $2 AND away_team = $3 AND match_date = $4
+	`, r.Sport, r.HomeTeam, r.AwayTeam, matchDate.UTC().Format("2006-01-02")).Scan(&r.MatchDate, &r.Status, &r.HomeScore, &r.AwayScore, &r.FetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match result: %w", err)
+	}
+	r.MatchDate, r.FetchedAt = r.MatchDate.UTC(), r.FetchedAt.UTC()
+	return &r, nil
+}
+
+// StoreMatchResult adds or replaces the result.
+func (s *PostgresResultStorage) StoreMatchResult(ctx context.Context, r MatchResult) error {
+	query := `
+	INSERT INTO match_results (sport, home_team, away_team, match_date, status, home_score, away_score, fetched_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (sport, home_team, away_team, match_date) DO UPDATE SET
+		status = EXCLUDED.status,
+		home_score = EXCLUDED.home_score,
+		away_score = EXCLUDED.away_score,
+		fetched_at = EXCLUDED.fetched_at
+	`
+	if _, err := s.db.ExecContext(ctx, query,
+		strings.ToLower(r.Sport), strings.ToLower(r.HomeTeam), strings.ToLower(r.AwayTeam), r.MatchDate.UTC().Format("2006-01-02"),
+		r.Status, r.HomeScore, r.AwayScore, r.FetchedAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to store match result: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connection.
+func (s *PostgresResultStorage) Close() error {
+	return s.db.Close()
+}
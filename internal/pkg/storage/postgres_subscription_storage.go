@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresSubscriptionStorage implements SubscriptionStorage
+var _ SubscriptionStorage = (*PostgresSubscriptionStorage)(nil)
+
+// PostgresSubscriptionStorage stores alert subscriptions (table alert_subscriptions); alert types and
+// filters are kept as JSONB. The table is not touched by /db/clear and the periodic full cleanup.
+type PostgresSubscriptionStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresSubscriptionStorage creates a new PostgreSQL storage for alert subscriptions.
+func NewPostgresSubscriptionStorage(cfg *config.PostgresConfig) (*PostgresSubscriptionStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresSubscriptionStorage{db: db}
+
+	slog.Info("PostgreSQL subscription storage initialized successfully")
+	return s, nil
+}
+
+// StoreSubscription adds or replaces the subscription of sub.ChatID.
+func (s *PostgresSubscriptionStorage) StoreSubscription(ctx context.Context, sub AlertSubscription) error {
+	alertTypes, err := json.Marshal(sub.AlertTypes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert types: %w", err)
+	}
+	filters, err := json.Marshal(sub.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filters: %w", err)
+	}
+	query := `
+	INSERT INTO alert_subscriptions (chat_id, name, enabled, alert_types, filters,
+		min_diff_percent, min_line_movement_percent, min_line_movement_pp, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (chat_id) DO UPDATE SET
+		name = EXCLUDED.name,
+		enabled = EXCLUDED.enabled,
+		alert_types = EXCLUDED.alert_types,
+		filters = EXCLUDED.filters,
+		min_diff_percent = EXCLUDED.min_diff_percent,
+		min_line_movement_percent = EXCLUDED.min_line_movement_percent,
+		min_line_movement_pp = EXCLUDED.min_line_movement_pp,
+		updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, sub.ChatID, sub.Name, sub.Enabled, alertTypes, filters,
+		sub.MinDiffPercent, sub.MinLineMovementPercent, sub.MinLineMovementPP, sub.CreatedAt.UTC(), sub.UpdatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to store subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription of chatID.
+func (s *PostgresSubscriptionStorage) DeleteSubscription(ctx context.Context, chatID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_subscriptions WHERE chat_id = $1`, chatID); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
+// GetSubscriptions returns all subscriptions ordered by chat ID.
+func (s *PostgresSubscriptionStorage) GetSubscriptions(ctx context.Context) ([]AlertSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT chat_id, name, enabled, alert_types, filters,
+		min_diff_percent, min_line_movement_percent, min_line_movement_pp, created_at, updated_at
+	FROM alert_subscriptions ORDER BY chat_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AlertSubscription
+	for rows.Next() {
+		var sub AlertSubscription
+		var alertTypes, filters []byte
+		if err := rows.Scan(&sub.ChatID, &sub.Name, &sub.Enabled, &alertTypes, &filters,
+			&sub.MinDiffPercent, &sub.MinLineMovementPercent, &sub.MinLineMovementPP, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(alertTypes, &sub.AlertTypes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert types of chat %d: %w", sub.ChatID, err)
+		}
+		if err := json.Unmarshal(filters, &sub.Filters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filters of chat %d: %w", sub.ChatID, err)
+		}
+		sub.CreatedAt, sub.UpdatedAt = sub.CreatedAt.UTC(), sub.UpdatedAt.UTC()
+		out = append(out, sub)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresSubscriptionStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresTeamAliasStorage implements TeamAliasStorage
+var _ TeamAliasStorage = (*PostgresTeamAliasStorage)(nil)
+
+// PostgresTeamAliasStorage stores the team aliases added at runtime (table team_aliases).
+// The table is not touched by /db/clear and the periodic full cleanup.
+type PostgresTeamAliasStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresTeamAliasStorage creates a new PostgreSQL storage for team aliases.
+func NewPostgresTeamAliasStorage(cfg *config.PostgresConfig) (*PostgresTeamAliasStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresTeamAliasStorage{db: db}
+
+	slog.Info("PostgreSQL team alias storage initialized successfully")
+	return s, nil
+}
+
+// StoreTeamAlias adds or replaces the alias.
+func (s *PostgresTeamAliasStorage) StoreTeamAlias(ctx context.Context, a TeamAlias) error {
+	query := `
+	INSERT INTO team_aliases (alias_key, alias, canonical, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (alias_key) DO UPDATE SET
+		alias = EXCLUDED.alias,
+		canonical = EXCLUDED.canonical,
+		created_at = EXCLUDED.created_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, models.AliasKey(a.Alias), a.Alias, a.Canonical, a.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to store team alias: %w", err)
+	}
+	return nil
+}
+
+// DeleteTeamAlias removes the alias.
+func (s *PostgresTeamAliasStorage) DeleteTeamAlias(ctx context.Context, alias string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM team_aliases WHERE alias_key = $1`, models.AliasKey(alias)); err != nil {
+		return fmt.Errorf("failed to delete team alias: %w", err)
+	}
+	return nil
+}
+
+// GetTeamAliases returns all aliases, oldest first.
+func (s *PostgresTeamAliasStorage) GetTeamAliases(ctx context.Context) ([]TeamAlias, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT alias, canonical, created_at FROM team_aliases ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TeamAlias
+	for rows.Next() {
+		var a TeamAlias
+		if err := rows.Scan(&a.Alias, &a.Canonical, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.CreatedAt = a.CreatedAt.UTC()
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresTeamAliasStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresValueHistoryStorage implements ValueHistoryStorage
+var _ ValueHistoryStorage = (*PostgresValueHistoryStorage)(nil)
+
+// PostgresValueHistoryStorage stores value bet lifecycles (table value_bet_history). The table is history
+// for analysis: it is not touched by /db/clear and the periodic full cleanup.
+type PostgresValueHistoryStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresValueHistoryStorage creates a new PostgreSQL storage for value bet lifecycles.
+func NewPostgresValueHistoryStorage(cfg *config.PostgresConfig) (*PostgresValueHistoryStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresValueHistoryStorage{db: db}
+
+	slog.Info("PostgreSQL value history storage initialized successfully")
+	return s, nil
+}
+
+const valueHistoryColumns = `id, value_bet_id, match_group_key, match_name, start_time, sport, event_type,
+	outcome_type, parameter, bet_key, bookmaker, first_seen, last_seen, first_value_percent, max_value_percent,
+	last_value_percent, max_value_odd, last_odd, last_fair_odd, ended_at, end_reason`
+
+// UpsertValueBetLifecycles adds or updates lifecycles by (value_bet_id, first_seen) in one statement per chunk.
+func (s *PostgresValueHistoryStorage) UpsertValueBetLifecycles(ctx context.Context, ls []ValueBetLifecycle) error {
+	const cols = 20
+	const chunkSize = 1000 // 20 params per row
+	for start := 0; start < len(ls); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ls) {
+			end = len(ls)
+		}
+		chunk := ls[start:end]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*cols)
+		for i, l := range chunk {
+			ph := make([]string, cols)
+			for j := range ph {
+				ph[j] = fmt.Sprintf("$%d", i*cols+j+1)
+			}
+			placeholders = append(placeholders, "("+strings.Join(ph, ",")+")")
+			var endedAt interface{}
+			if l.EndedAt != nil {
+				endedAt = l.EndedAt.UTC()
+			}
+			args = append(args,
+				l.ValueBetID, l.MatchGroupKey, l.MatchName, l.StartTime.UTC(), l.Sport, l.EventType,
+				l.OutcomeType, l.Parameter, l.BetKey, l.Bookmaker, l.FirstSeen.UTC(), l.LastSeen.UTC(),
+				l.FirstValuePercent, l.MaxValuePercent, l.LastValuePercent, l.MaxValueOdd, l.LastOdd, l.LastFairOdd,
+				endedAt, l.EndReason,
+			)
+		}
+
+		query := `
+		INSERT INTO value_bet_history (
+			value_bet_id, match_group_key, match_name, start_time, sport, event_type,
+			outcome_type, parameter, bet_key, bookmaker, first_seen, last_seen,
+			first_value_percent, max_value_percent, last_value_percent, max_value_odd, last_odd, last_fair_odd,
+			ended_at, end_reason
+		) VALUES ` + strings.Join(placeholders, ",") + `
+		ON CONFLICT (value_bet_id, first_seen) DO UPDATE SET
+			match_name = EXCLUDED.match_name,
+			start_time = EXCLUDED.start_time,
+			last_seen = EXCLUDED.last_seen,
+			max_value_percent = EXCLUDED.max_value_percent,
+			last_value_percent = EXCLUDED.last_value_percent,
+			max_value_odd = EXCLUDED.max_value_odd,
+			last_odd = EXCLUDED.last_odd,
+			last_fair_odd = EXCLUDED.last_fair_odd,
+			ended_at = EXCLUDED.ended_at,
+			end_reason = EXCLUDED.end_reason
+		`
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("UpsertValueBetLifecycles failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetValueBetHistory returns lifecycles matching f, newest first, and the number of all matching ones.
+func (s *PostgresValueHistoryStorage) GetValueBetHistory(ctx context.Context, f ValueBetHistoryFilter) ([]ValueBetLifecycle, int, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.Sport != "" {
+		add("sport = $%d", f.Sport)
+	}
+	if f.Bookmaker != "" {
+		add("LOWER(bookmaker) = LOWER($%d)", f.Bookmaker)
+	}
+	if f.MatchGroupKey != "" {
+		add("match_group_key = $%d", f.MatchGroupKey)
+	}
+	if !f.From.IsZero() {
+		add("first_seen >= $%d", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		add("first_seen < $%d", f.To.UTC())
+	}
+	switch f.Status {
+	case "active":
+		where = append(where, "ended_at IS NULL")
+	case "ended":
+		where = append(where, "ended_at IS NOT NULL")
+	}
+	if f.EndReason != "" {
+		add("end_reason = $%d", f.EndReason)
+	}
+	if f.MinValuePercent > 0 {
+		add("max_value_percent >= $%d", f.MinValuePercent)
+	}
+	cond := ""
+	if len(where) > 0 {
+		cond = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM value_bet_history`+cond, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count value bet history: %w", err)
+	}
+
+	query := `SELECT ` + valueHistoryColumns + ` FROM value_bet_history` + cond + ` ORDER BY first_seen DESC, id DESC`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get value bet history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ValueBetLifecycle
+	for rows.Next() {
+		var l ValueBetLifecycle
+		var endedAt sql.NullTime
+		if err := rows.Scan(
+			&l.ID, &l.ValueBetID, &l.MatchGroupKey, &l.MatchName, &l.StartTime, &l.Sport, &l.EventType,
+			&l.OutcomeType, &l.Parameter, &l.BetKey, &l.Bookmaker, &l.FirstSeen, &l.LastSeen, &l.FirstValuePercent, &l.MaxValuePercent,
+			&l.LastValuePercent, &l.MaxValueOdd, &l.LastOdd, &l.LastFairOdd, &endedAt, &l.EndReason,
+		); err != nil {
+			return nil, 0, err
+		}
+		l.StartTime, l.FirstSeen, l.LastSeen = l.StartTime.UTC(), l.FirstSeen.UTC(), l.LastSeen.UTC()
+		if endedAt.Valid {
+			t := endedAt.Time.UTC()
+			l.EndedAt = &t
+		}
+		out = append(out, l)
+	}
+	return out, total, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *PostgresValueHistoryStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	_ "github.com/lib/pq"
+)
+
+// Ensure PostgresWarehouseStorage implements WarehouseStorage
+var _ WarehouseStorage = (*PostgresWarehouseStorage)(nil)
+
+// PostgresWarehouseStorage maintains the research schema: one wide row per bet per bookmaker per
+// recorded odd (research.bets_wide) plus match and bookmaker dimensions, so pandas/SQL analysis
+// doesn't have to reconstruct joins from the operational tables (which are truncated periodically).
+// research.etl_runs keeps the watermark of every run; reruns never duplicate rows.
+type PostgresWarehouseStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresWarehouseStorage creates a new PostgreSQL storage for the research schema.
+func NewPostgresWarehouseStorage(cfg *config.PostgresConfig) (*PostgresWarehouseStorage, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	dsn, err := parseDSNForMultipleHosts(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	s := &PostgresWarehouseStorage{db: db}
+
+	slog.Info("PostgreSQL research warehouse initialized successfully")
+	return s, nil
+}
+
+// warehouseSourceQuery selects the odds history rows of one run ($1 < recorded_at <= $2).
+// event/outcome/parameter come from bet_key ("event|outcome|param") and sport from match_group_key
+// ("sport|fixture"), so rows of started matches (already gone from odds_snapshots) keep them too.
+// Keyframes repeat odds already loaded and are skipped.
+const warehouseSourceQuery = `
+	SELECT h.match_group_key, h.bet_key, h.bookmaker, h.odd, h.recorded_at, h.start_time,
+		COALESCE(s.match_name, '') AS match_name,
+		split_part(COALESCE(s.match_name, ''), ' vs ', 1) AS home_team,
+		split_part(COALESCE(s.match_name, ''), ' vs ', 2) AS away_team,
+		split_part(h.match_group_key, '|', 1) AS sport,
+		split_part(h.bet_key, '|', 1) AS event_type,
+		split_part(h.bet_key, '|', 2) AS outcome_type,
+		split_part(h.bet_key, '|', 3) AS parameter
+	FROM odds_snapshot_history h
+	LEFT JOIN odds_snapshots s
+		ON s.match_group_key = h.match_group_key AND s.bet_key = h.bet_key AND s.bookmaker = h.bookmaker
+	WHERE h.recorded_at > $1 AND h.recorded_at <= $2 AND h.odd > 0 AND NOT h.keyframe
+`
+
+// RunWarehouseETL loads odds_snapshot_history rows recorded after the previous watermark up to now
+// into research.bets_wide and refreshes the dimensions, all in one transaction.
+func (s *PostgresWarehouseStorage) RunWarehouseETL(ctx context.Context, now time.Time) (WarehouseETLResult, error) {
+	started := time.Now()
+	res := WarehouseETLResult{To: now.UTC()}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("failed to begin ETL transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// One ETL at a time (nightly job and pre-cleanup export may overlap)
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE research.etl_runs IN EXCLUSIVE MODE`); err != nil {
+		return res, fmt.Errorf("failed to lock research.etl_runs: %w", err)
+	}
+	var from sql.NullTime
+	if err := tx.QueryRowContext(ctx, `SELECT MAX(watermark_to) FROM research.etl_runs`).Scan(&from); err != nil {
+		return res, fmt.Errorf("failed to read ETL watermark: %w", err)
+	}
+	if from.Valid {
+		res.From = from.Time.UTC()
+	}
+	if !res.To.After(res.From) {
+		return res, nil
+	}
+
+	steps := []struct {
+		name  string
+		query string
+		rows  *int64
+	}{
+		{"dim_bookmakers", `
+		INSERT INTO research.dim_bookmakers (name, first_seen, last_seen)
+		SELECT src.bookmaker, MIN(src.recorded_at), MAX(src.recorded_at)
+		FROM (` + warehouseSourceQuery + `) src
+		GROUP BY src.bookmaker
+		ON CONFLICT (name) DO UPDATE SET
+			last_seen = GREATEST(research.dim_bookmakers.last_seen, EXCLUDED.last_seen)
+		`, &res.Bookmakers},
+		{"dim_matches", `
+		INSERT INTO research.dim_matches (match_group_key, match_name, home_team, away_team, sport, start_time, first_seen, last_seen)
+		SELECT src.match_group_key, MAX(src.match_name), MAX(src.home_team), MAX(src.away_team),
+			MAX(src.sport), MAX(src.start_time), MIN(src.recorded_at), MAX(src.recorded_at)
+		FROM (` + warehouseSourceQuery + `) src
+		GROUP BY src.match_group_key
+		ON CONFLICT (match_group_key) DO UPDATE SET
+			match_name = CASE WHEN EXCLUDED.match_name <> '' THEN EXCLUDED.match_name ELSE research.dim_matches.match_name END,
+			home_team = CASE WHEN EXCLUDED.home_team <> '' THEN EXCLUDED.home_team ELSE research.dim_matches.home_team END,
+			away_team = CASE WHEN EXCLUDED.away_team <> '' THEN EXCLUDED.away_team ELSE research.dim_matches.away_team END,
+			start_time = EXCLUDED.start_time,
+			last_seen = GREATEST(research.dim_matches.last_seen, EXCLUDED.last_seen)
+		`, &res.Matches},
+		{"bets_wide", `
+		INSERT INTO research.bets_wide (
+			match_group_key, match_name, home_team, away_team, sport, start_time,
+			bookmaker_id, bookmaker, bet_key, event_type, outcome_type, parameter,
+			odd, implied_probability, recorded_at, minutes_to_start
+		)
+		SELECT src.match_group_key, COALESCE(m.match_name, src.match_name), COALESCE(m.home_team, src.home_team),
+			COALESCE(m.away_team, src.away_team), src.sport, src.start_time,
+			b.bookmaker_id, src.bookmaker, src.bet_key, src.event_type, src.outcome_type, src.parameter,
+			src.odd, 1.0 / src.odd, src.recorded_at,
+			EXTRACT(EPOCH FROM (src.start_time - src.recorded_at)) / 60.0
+		FROM (` + warehouseSourceQuery + `) src
+		JOIN research.dim_bookmakers b ON b.name = src.bookmaker
+		LEFT JOIN research.dim_matches m ON m.match_group_key = src.match_group_key
+		ON CONFLICT (match_group_key, bet_key, bookmaker, recorded_at) DO NOTHING
+		`, &res.Rows},
+	}
+	for _, step := range steps {
+		r, err := tx.ExecContext(ctx, step.query, res.From, res.To)
+		if err != nil {
+			return res, fmt.Errorf("ETL %s failed: %w", step.name, err)
+		}
+		*step.rows, _ = r.RowsAffected()
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+	INSERT INTO research.etl_runs (started_at, finished_at, watermark_from, watermark_to, rows_loaded)
+	VALUES ($1, $2, $3, $4, $5)
+	`, started.UTC(), time.Now().UTC(), res.From, res.To, res.Rows); err != nil {
+		return res, fmt.Errorf("failed to record ETL run: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return res, fmt.Errorf("failed to commit ETL: %w", err)
+	}
+	return res, nil
+}
+
+// Close closes the database connection.
+func (s *PostgresWarehouseStorage) Close() error {
+	return s.db.Close()
+}
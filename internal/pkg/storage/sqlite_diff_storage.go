@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// sqliteDriver is the database/sql driver of the SQLite storages: the pure-Go glebarez/go-sqlite
+// (modernc.org/sqlite), registered by sqlite_driver.go in builds with -tags sqlite.
+const sqliteDriver = "sqlite"
+
+// Ensure SQLiteDiffStorage implements DiffBetStorage
+var _ DiffBetStorage = (*SQLiteDiffStorage)(nil)
+
+// SQLiteDiffStorage stores DiffBet records in a SQLite file, for single-host deployments without Postgres
+// (storage.backend: sqlite). Times are stored as Unix milliseconds (UTC).
+type SQLiteDiffStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteDiffStorage opens (creating if needed) the SQLite database at path and its diff_bets table.
+func NewSQLiteDiffStorage(path string) (*SQLiteDiffStorage, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		return nil, fmt.Errorf("sqlite support is not compiled in: build with -tags sqlite")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open(sqliteDriver, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// One writer at a time is all SQLite allows; a single connection avoids "database is locked"
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, stmt := range []string{
+		`PRAGMA journal_mode = WAL`,
+		`PRAGMA busy_timeout = 5000`,
+		`CREATE TABLE IF NOT EXISTS diff_bets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			match_group_key TEXT NOT NULL,
+			match_name TEXT NOT NULL,
+			start_time INTEGER NOT NULL,
+			sport TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			outcome_type TEXT NOT NULL,
+			parameter TEXT NOT NULL DEFAULT '',
+			bet_key TEXT NOT NULL,
+			bookmakers INTEGER NOT NULL,
+			min_bookmaker TEXT NOT NULL,
+			min_odd REAL NOT NULL,
+			max_bookmaker TEXT NOT NULL,
+			max_odd REAL NOT NULL,
+			diff_abs REAL NOT NULL,
+			diff_percent REAL NOT NULL,
+			calculated_at INTEGER NOT NULL,
+			created_at INTEGER NOT NULL,
+			UNIQUE(match_group_key, bet_key, calculated_at)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_diff_bets_calculated_at ON diff_bets(calculated_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_diff_bets_diff_percent ON diff_bets(diff_percent DESC)`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize sqlite diff storage: %w", err)
+		}
+	}
+
+	slog.Info("SQLite diff storage initialized successfully", "path", path)
+	return &SQLiteDiffStorage{db: db}, nil
+}
+
+func sqliteTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromSQLiteTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+// StoreDiffBet stores a DiffBet record if it doesn't already exist
+// Returns true if the record was newly inserted, false if it already existed
+func (s *SQLiteDiffStorage) StoreDiffBet(ctx context.Context, diffInterface interface{}) (bool, error) {
+	matchGroupKey, matchName, sport, eventType, outcomeType, parameter, betKey, minBookmaker, maxBookmaker, startTime, calculatedAt, bookmakers, minOdd, maxOdd, diffAbs, diffPercent, err := extractDiffBetFields(diffInterface)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+	INSERT INTO diff_bets (
+		match_group_key, match_name, start_time, sport,
+		event_type, outcome_type, parameter, bet_key,
+		bookmakers, min_bookmaker, min_odd, max_bookmaker, max_odd,
+		diff_abs, diff_percent, calculated_at, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (match_group_key, bet_key, calculated_at) DO NOTHING
+	`,
+		matchGroupKey, matchName, sqliteTime(startTime), sport,
+		eventType, outcomeType, parameter, betKey,
+		bookmakers, minBookmaker, minOdd, maxBookmaker, maxOdd,
+		diffAbs, diffPercent, sqliteTime(calculatedAt), sqliteTime(time.Now()),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to store diff bet: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store diff bet: %w", err)
+	}
+	// 0 rows: the record already exists (conflict)
+	return n > 0, nil
+}
+
+// IsNewDiffBet checks if a diff bet is new (not seen in the last N minutes)
+// Returns true if the diff is new, false if it was already seen recently
+func (s *SQLiteDiffStorage) IsNewDiffBet(ctx context.Context, diffInterface interface{}, withinMinutes int) (bool, error) {
+	matchGroupKey, _, _, _, _, _, betKey, _, _, _, _, _, _, _, _, _, err := extractDiffBetFields(diffInterface)
+	if err != nil {
+		return false, err
+	}
+
+	since := time.Now().Add(-time.Duration(withinMinutes) * time.Minute)
+	var count int
+	err = s.db.QueryRowContext(ctx, `
+	SELECT COUNT(*) FROM diff_bets
+	WHERE match_group_key = ? AND bet_key = ? AND calculated_at > ?
+	`, matchGroupKey, betKey, sqliteTime(since)).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if diff is new: %w", err)
+	}
+	return count == 0, nil
+}
+
+// GetRecentDiffBets gets diff bets from the last N minutes, as maps like PostgresDiffStorage
+func (s *SQLiteDiffStorage) GetRecentDiffBets(ctx context.Context, withinMinutes int, minDiffPercent float64) ([]interface{}, error) {
+	since := time.Now().Add(-time.Duration(withinMinutes) * time.Minute)
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT
+		match_group_key, match_name, start_time, sport,
+		event_type, outcome_type, parameter, bet_key,
+		bookmakers, min_bookmaker, min_odd, max_bookmaker, max_odd,
+		diff_abs, diff_percent, calculated_at
+	FROM diff_bets
+	WHERE calculated_at > ? AND diff_percent >= ?
+	ORDER BY diff_percent DESC, calculated_at DESC
+	`, sqliteTime(since), minDiffPercent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent diff bets: %w", err)
+	}
+	defer rows.Close()
+
+	var diffs []interface{}
+	for rows.Next() {
+		var matchGroupKey, matchName, sport, eventType, outcomeType, parameter, betKey, minBookmaker, maxBookmaker string
+		var startTime, calculatedAt int64
+		var bookmakers int
+		var minOdd, maxOdd, diffAbs, diffPercent float64
+		if err := rows.Scan(
+			&matchGroupKey, &matchName, &startTime, &sport,
+			&eventType, &outcomeType, &parameter, &betKey,
+			&bookmakers, &minBookmaker, &minOdd, &maxBookmaker, &maxOdd,
+			&diffAbs, &diffPercent, &calculatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan diff bet: %w", err)
+		}
+		diffs = append(diffs, map[string]interface{}{
+			"match_group_key": matchGroupKey,
+			"match_name":      matchName,
+			"start_time":      fromSQLiteTime(startTime),
+			"sport":           sport,
+			"event_type":      eventType,
+			"outcome_type":    outcomeType,
+			"parameter":       parameter,
+			"bet_key":         betKey,
+			"bookmakers":      bookmakers,
+			"min_bookmaker":   minBookmaker,
+			"min_odd":         minOdd,
+			"max_bookmaker":   maxBookmaker,
+			"max_odd":         maxOdd,
+			"diff_abs":        diffAbs,
+			"diff_percent":    diffPercent,
+			"calculated_at":   fromSQLiteTime(calculatedAt),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return diffs, nil
+}
+
+// GetLastDiffBet gets the most recent diff bet for a specific match+bet combination
+// Excludes diffs with calculated_at equal to excludeCalculatedAt (to avoid getting the current diff)
+// Returns the diff_percent and calculated_at, or (0, zero time, nil) if not found
+func (s *SQLiteDiffStorage) GetLastDiffBet(ctx context.Context, matchGroupKey, betKey string, excludeCalculatedAt time.Time) (float64, time.Time, error) {
+	query := `
+	SELECT diff_percent, calculated_at
+	FROM diff_bets
+	WHERE match_group_key = ? AND bet_key = ?
+	ORDER BY calculated_at DESC
+	LIMIT 1
+	`
+	args := []interface{}{matchGroupKey, betKey}
+	if !excludeCalculatedAt.IsZero() {
+		query = `
+		SELECT diff_percent, calculated_at
+		FROM diff_bets
+		WHERE match_group_key = ? AND bet_key = ? AND calculated_at != ?
+		ORDER BY calculated_at DESC
+		LIMIT 1
+		`
+		args = append(args, sqliteTime(excludeCalculatedAt))
+	}
+
+	var diffPercent float64
+	var calculatedAt int64
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&diffPercent, &calculatedAt)
+	if err == sql.ErrNoRows {
+		// No previous diff found - this is a new diff
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get last diff bet: %w", err)
+	}
+	return diffPercent, fromSQLiteTime(calculatedAt), nil
+}
+
+// CleanDiffBets removes all records from diff_bets table
+func (s *SQLiteDiffStorage) CleanDiffBets(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM diff_bets`); err != nil {
+		return fmt.Errorf("failed to clean diff_bets table: %w", err)
+	}
+	slog.Info("Cleaned diff_bets table")
+	return nil
+}
+
+// Ping checks that the database is reachable (/readyz)
+func (s *SQLiteDiffStorage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close closes the database connection
+func (s *SQLiteDiffStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDiffBet has the fields extractDiffBetFields reads from the calculator's DiffBet.
+type testDiffBet struct {
+	MatchGroupKey string
+	MatchName     string
+	StartTime     time.Time
+	Sport         string
+	EventType     string
+	OutcomeType   string
+	Parameter     string
+	BetKey        string
+	Bookmakers    int
+	MinBookmaker  string
+	MinOdd        float64
+	MaxBookmaker  string
+	MaxOdd        float64
+	DiffAbs       float64
+	DiffPercent   float64
+	CalculatedAt  time.Time
+}
+
+// openTestSQLiteDiffStorage opens a SQLite diff storage in a temp dir; skipped in builds without -tags sqlite.
+func openTestSQLiteDiffStorage(t *testing.T, path string) *SQLiteDiffStorage {
+	t.Helper()
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		t.Skip("sqlite support is not compiled in: run with -tags sqlite")
+	}
+	s, err := NewSQLiteDiffStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestNewSQLiteDiffStorage_Errors(t *testing.T) {
+	if _, err := NewSQLiteDiffStorage(""); err == nil {
+		t.Error("empty path: expected an error")
+	}
+	if slices.Contains(sql.Drivers(), sqliteDriver) {
+		return
+	}
+	_, err := NewSQLiteDiffStorage(filepath.Join(t.TempDir(), "diffs.db"))
+	if err == nil || !strings.Contains(err.Error(), "-tags sqlite") {
+		t.Errorf("without the driver: err = %v, want a hint to build with -tags sqlite", err)
+	}
+}
+
+func TestSQLiteDiffStorage_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "diffs.db")
+	s := openTestSQLiteDiffStorage(t, path)
+
+	// Times are stored as Unix milliseconds
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	diff := testDiffBet{
+		MatchGroupKey: "football|arsenal|chelsea|2026-03-01T18:00:00Z", MatchName: "Arsenal vs Chelsea",
+		StartTime: now.Add(2 * time.Hour), Sport: "football", EventType: "main_match", OutcomeType: "total_over",
+		Parameter: "2.5", BetKey: "main_match|total_over|2.5", Bookmakers: 3,
+		MinBookmaker: "fonbet", MinOdd: 1.8, MaxBookmaker: "pinnacle", MaxOdd: 2.1,
+		DiffAbs: 0.3, DiffPercent: 16.67, CalculatedAt: now.Add(-time.Minute),
+	}
+
+	if inserted, err := s.StoreDiffBet(ctx, diff); err != nil || !inserted {
+		t.Fatalf("first store: inserted %v, err %v", inserted, err)
+	}
+	if inserted, err := s.StoreDiffBet(ctx, &diff); err != nil || inserted {
+		t.Fatalf("same diff again: inserted %v, err %v, want a conflict", inserted, err)
+	}
+	if isNew, err := s.IsNewDiffBet(ctx, diff, 10); err != nil || isNew {
+		t.Errorf("stored diff: new %v, err %v", isNew, err)
+	}
+	other := diff
+	other.BetKey = "main_match|total_under|2.5"
+	if isNew, err := s.IsNewDiffBet(ctx, other, 10); err != nil || !isNew {
+		t.Errorf("other bet key: new %v, err %v", isNew, err)
+	}
+
+	earlier := diff
+	earlier.DiffPercent = 9.5
+	earlier.CalculatedAt = now.Add(-5 * time.Minute)
+	if _, err := s.StoreDiffBet(ctx, earlier); err != nil {
+		t.Fatal(err)
+	}
+	percent, at, err := s.GetLastDiffBet(ctx, diff.MatchGroupKey, diff.BetKey, diff.CalculatedAt)
+	if err != nil || percent != 9.5 || !at.Equal(earlier.CalculatedAt) {
+		t.Errorf("last diff before the current one = %v at %v (err %v), want 9.5 at %v", percent, at, err, earlier.CalculatedAt)
+	}
+	if percent, _, err := s.GetLastDiffBet(ctx, diff.MatchGroupKey, other.BetKey, time.Time{}); err != nil || percent != 0 {
+		t.Errorf("unknown bet key: %v, err %v", percent, err)
+	}
+
+	// Reopening the file loads what was stored
+	s.Close()
+	s = openTestSQLiteDiffStorage(t, path)
+	diffs, err := s.GetRecentDiffBets(ctx, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("recent diffs above 10%% = %+v, want the 16.67%% one", diffs)
+	}
+	got := diffs[0].(map[string]interface{})
+	if got["bet_key"] != diff.BetKey || got["max_bookmaker"] != "pinnacle" || got["max_odd"] != 2.1 || got["bookmakers"] != 3 {
+		t.Errorf("loaded diff = %+v", got)
+	}
+	if st, _ := got["start_time"].(time.Time); !st.Equal(diff.StartTime) {
+		t.Errorf("start_time = %v, want %v", got["start_time"], diff.StartTime)
+	}
+	if ca, _ := got["calculated_at"].(time.Time); !ca.Equal(diff.CalculatedAt) {
+		t.Errorf("calculated_at = %v, want %v", got["calculated_at"], diff.CalculatedAt)
+	}
+
+	if err := s.CleanDiffBets(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diffs, err := s.GetRecentDiffBets(ctx, 10, 0); err != nil || len(diffs) != 0 {
+		t.Errorf("after cleanup: %d diffs, err %v", len(diffs), err)
+	}
+	if isNew, err := s.IsNewDiffBet(ctx, diff, 10); err != nil || !isNew {
+		t.Errorf("after cleanup: new %v, err %v", isNew, err)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package storage
+
+// Registers database/sql driver "sqlite" (pure Go, no cgo). Not part of the default build so that Postgres
+// deployments don't carry the SQLite engine: build with -tags sqlite (make test runs the storage tests with it).
+import _ "github.com/glebarez/go-sqlite"
@@ -0,0 +1,271 @@
+// Package teaminfo maps team and league names to static metadata (country, league, logo) so the
+// match API and the WebApp can show matches in a visually identifiable way.
+//
+// The dataset is embedded (dataset.json) and can be extended with an external file of the same
+// format (parser.team_info.file); its entries win over the embedded ones. Names are matched by a
+// light normalization (case, punctuation, diacritics, club suffixes like "FC") plus aliases, so
+// "FC Zenit", "Zenit St. Petersburg" and "Зенит" resolve to the same team.
+package teaminfo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+//go:embed dataset.json
+var embeddedDataset []byte
+
+// SportEsports is the dataset sport of esports teams; it matches every esports discipline.
+const SportEsports = "esports"
+
+// Dataset is the JSON format of dataset.json and of parser.team_info.file.
+type Dataset struct {
+	Teams   []TeamEntry   `json:"teams"`
+	Leagues []LeagueEntry `json:"leagues"`
+}
+
+// TeamEntry is one team of the dataset. Empty Sport matches any sport.
+type TeamEntry struct {
+	Name    string   `json:"name"`
+	Sport   string   `json:"sport,omitempty"`
+	Country string   `json:"country,omitempty"`
+	League  string   `json:"league,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	Logo    string   `json:"logo,omitempty"` // absolute URL or path relative to logo_base_url
+}
+
+// LeagueEntry is one league/tournament of the dataset.
+type LeagueEntry struct {
+	Name    string   `json:"name"`
+	Country string   `json:"country,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	Logo    string   `json:"logo,omitempty"`
+}
+
+// index is the loaded dataset keyed by normalized names.
+type index struct {
+	teams   map[string]map[string]*models.TeamMeta // sport -> name -> meta
+	leagues map[string]*models.LeagueMeta
+}
+
+var (
+	mu      sync.RWMutex
+	current *index
+)
+
+// Configure loads the embedded dataset and merges cfg.File over it. An unreadable file is logged
+// and ignored, so enrichment never blocks startup.
+func Configure(cfg config.TeamInfoConfig) {
+	ds := []Dataset{mustParse(embeddedDataset)}
+	if cfg.File != "" {
+		extra, err := loadFile(cfg.File)
+		if err != nil {
+			slog.Error("Team info: failed to load dataset, using embedded only", "file", cfg.File, "error", err)
+		} else {
+			ds = append(ds, extra)
+		}
+	}
+	idx := build(ds, cfg.LogoBaseURL)
+	mu.Lock()
+	current = idx
+	mu.Unlock()
+	slog.Info("Team info configured", "teams", idx.teamCount(), "leagues", len(idx.leagues), "file", cfg.File)
+}
+
+func get() *index {
+	mu.RLock()
+	idx := current
+	mu.RUnlock()
+	if idx != nil {
+		return idx
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		current = build([]Dataset{mustParse(embeddedDataset)}, "")
+	}
+	return current
+}
+
+// Team returns the metadata of a team of the given sport, or nil if the team is unknown.
+// Esports disciplines (dota2, cs, ...) also match teams with sport "esports".
+func Team(sport, name string) *models.TeamMeta {
+	key := normalize(name)
+	if key == "" {
+		return nil
+	}
+	idx := get()
+	for _, s := range sportCandidates(sport) {
+		if meta, ok := idx.teams[s][key]; ok {
+			return meta
+		}
+	}
+	return nil
+}
+
+// League returns the metadata of a league/tournament, or nil if it is unknown.
+func League(name string) *models.LeagueMeta {
+	key := normalize(name)
+	if key == "" {
+		return nil
+	}
+	idx := get()
+	if l := idx.leagues[key]; l != nil {
+		return l
+	}
+	// Names the dataset doesn't list are tried by their canonical tournament name (models.CanonicalLeagueName)
+	return idx.leagues[normalize(models.CanonicalLeagueName(name))]
+}
+
+// EnrichMatches sets HomeMeta/AwayMeta/LeagueMeta of the matches in place and returns them.
+// The metadata pointers are shared and must not be modified by callers.
+func EnrichMatches(matches []models.Match) []models.Match {
+	for i := range matches {
+		m := &matches[i]
+		m.HomeMeta = Team(m.Sport, m.HomeTeam)
+		m.AwayMeta = Team(m.Sport, m.AwayTeam)
+		m.LeagueMeta = League(m.Tournament)
+	}
+	return matches
+}
+
+// EnrichEsportsMatches sets HomeMeta/AwayMeta of the esports matches in place and returns them.
+func EnrichEsportsMatches(matches []models.EsportsMatch) []models.EsportsMatch {
+	for i := range matches {
+		m := &matches[i]
+		m.HomeMeta = Team(m.Discipline, m.HomeTeam)
+		m.AwayMeta = Team(m.Discipline, m.AwayTeam)
+	}
+	return matches
+}
+
+func sportCandidates(sport string) []string {
+	sport = strings.ToLower(strings.TrimSpace(sport))
+	switch sport {
+	case "dota2", "cs", "cs2", "lol", "valorant", "kog", "crossfire", "callofduty", SportEsports:
+		return []string{sport, SportEsports, ""}
+	case "":
+		return []string{""}
+	default:
+		return []string{sport, ""}
+	}
+}
+
+func build(datasets []Dataset, logoBaseURL string) *index {
+	idx := &index{
+		teams:   map[string]map[string]*models.TeamMeta{},
+		leagues: map[string]*models.LeagueMeta{},
+	}
+	for _, ds := range datasets {
+		for _, t := range ds.Teams {
+			if t.Name == "" {
+				continue
+			}
+			sport := strings.ToLower(strings.TrimSpace(t.Sport))
+			meta := &models.TeamMeta{Name: t.Name, Country: t.Country, League: t.League, LogoURL: logoURL(logoBaseURL, t.Logo)}
+			if idx.teams[sport] == nil {
+				idx.teams[sport] = map[string]*models.TeamMeta{}
+			}
+			for _, n := range append([]string{t.Name}, t.Aliases...) {
+				if key := normalize(n); key != "" {
+					idx.teams[sport][key] = meta
+				}
+			}
+		}
+		for _, l := range ds.Leagues {
+			if l.Name == "" {
+				continue
+			}
+			meta := &models.LeagueMeta{Name: l.Name, Country: l.Country, LogoURL: logoURL(logoBaseURL, l.Logo)}
+			for _, n := range append([]string{l.Name}, l.Aliases...) {
+				if key := normalize(n); key != "" {
+					idx.leagues[key] = meta
+				}
+			}
+		}
+	}
+	return idx
+}
+
+func (idx *index) teamCount() int {
+	seen := map[*models.TeamMeta]struct{}{}
+	for _, byName := range idx.teams {
+		for _, meta := range byName {
+			seen[meta] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// logoURL resolves a dataset logo: absolute URLs are kept, relative paths need a base URL.
+func logoURL(base, logo string) string {
+	if logo == "" || strings.HasPrefix(logo, "http://") || strings.HasPrefix(logo, "https://") {
+		return logo
+	}
+	if base == "" {
+		return ""
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(logo, "/")
+}
+
+func loadFile(path string) (Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Dataset{}, err
+	}
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return Dataset{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return ds, nil
+}
+
+func mustParse(data []byte) Dataset {
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		panic("teaminfo: invalid embedded dataset: " + err.Error())
+	}
+	return ds
+}
+
+// clubTokens are dropped from names: "FC Barcelona" and "Barcelona" are the same team.
+var clubTokens = map[string]struct{}{
+	"fc": {}, "cf": {}, "afc": {}, "sc": {}, "fk": {}, "ac": {}, "as": {}, "cd": {},
+	"sk": {}, "bk": {}, "if": {}, "ff": {}, "club": {}, "фк": {},
+}
+
+var nameReplacer = strings.NewReplacer(
+	"-", " ", ".", " ", "'", " ", "’", " ", "/", " ", ",", " ", "(", " ", ")", " ",
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c", "ß", "ss", "ё", "е",
+)
+
+// normalize is deliberately lighter than the match key normalization in models: it must not map
+// one team to another, only fold spelling differences between bookmakers.
+func normalize(name string) string {
+	s := nameReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	words := strings.Fields(s)
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, ok := clubTokens[w]; ok {
+			continue
+		}
+		out = append(out, w)
+	}
+	if len(out) == 0 {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(out, " ")
+}
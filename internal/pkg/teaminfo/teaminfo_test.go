@@ -0,0 +1,75 @@
+package teaminfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+func TestTeam(t *testing.T) {
+	Configure(config.TeamInfoConfig{LogoBaseURL: "https://cdn.example.com/logos/"})
+	defer Configure(config.TeamInfoConfig{})
+
+	for _, name := range []string{"Zenit", "FC Zenit", "Zenit St. Petersburg", "зенит"} {
+		meta := Team("football", name)
+		if meta == nil || meta.Name != "Zenit" || meta.Country != "Russia" {
+			t.Fatalf("Team(%q) = %+v", name, meta)
+		}
+		if meta.LogoURL != "https://cdn.example.com/logos/football/zenit.png" {
+			t.Errorf("LogoURL = %q", meta.LogoURL)
+		}
+	}
+	if meta := Team("hockey", "Zenit"); meta != nil {
+		t.Errorf("football team matched for hockey: %+v", meta)
+	}
+	if meta := Team("dota2", "Team Spirit"); meta == nil || meta.Name != "Team Spirit" {
+		t.Errorf("esports team not matched for dota2: %+v", meta)
+	}
+	if meta := Team("football", "Chelsea U21"); meta != nil {
+		t.Errorf("youth team matched: %+v", meta)
+	}
+	if meta := League("England. Premier League"); meta == nil || meta.Name != "Premier League" {
+		t.Errorf("League = %+v", meta)
+	}
+}
+
+func TestConfigure_FileOverridesEmbedded(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "teams.json")
+	data := `{"teams":[{"name":"Zenit","sport":"football","country":"RU","logo":"https://img.example.com/zenit.svg"},
+		{"name":"Akhmat","sport":"football","country":"Russia","aliases":["Ахмат"]}]}`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	Configure(config.TeamInfoConfig{File: file})
+	defer Configure(config.TeamInfoConfig{})
+
+	if meta := Team("football", "Зенит"); meta == nil || meta.Country != "Russia" {
+		t.Errorf("embedded alias lost: %+v", meta)
+	}
+	if meta := Team("football", "Zenit"); meta == nil || meta.Country != "RU" || meta.LogoURL != "https://img.example.com/zenit.svg" {
+		t.Errorf("file entry did not override: %+v", meta)
+	}
+	if meta := Team("football", "Ахмат"); meta == nil || meta.Name != "Akhmat" || meta.LogoURL != "" {
+		t.Errorf("file-only team = %+v", meta)
+	}
+}
+
+func TestEnrichMatches(t *testing.T) {
+	Configure(config.TeamInfoConfig{})
+	matches := EnrichMatches([]models.Match{
+		{HomeTeam: "Arsenal", AwayTeam: "Unknown FC", Sport: "football", Tournament: "EPL"},
+	})
+	m := matches[0]
+	if m.HomeMeta == nil || m.HomeMeta.Name != "Arsenal" || m.AwayMeta != nil {
+		t.Fatalf("home = %+v, away = %+v", m.HomeMeta, m.AwayMeta)
+	}
+	if m.HomeMeta.LogoURL != "" {
+		t.Errorf("relative logo without base URL: %q", m.HomeMeta.LogoURL)
+	}
+	if m.LeagueMeta == nil || m.LeagueMeta.Name != "Premier League" {
+		t.Errorf("league = %+v", m.LeagueMeta)
+	}
+}
@@ -0,0 +1,120 @@
+package tgformat
+
+// Text keys of the catalog: short messages sent outside the templates.
+const (
+	TextNoValueBets         = "no_value_bets"
+	TextNoLiveValueBets     = "no_live_value_bets"
+	TextNoUpcomingValueBets = "no_upcoming_value_bets"
+	TextNoCyberValueBets    = "no_cyber_value_bets"
+	TextNoLineMovements     = "no_line_movements"
+	TextNoArbs              = "no_arbs"
+	TextNoBets              = "no_bets"
+	TextNoMatches           = "no_matches" // %s = query
+	TextMatchUsage          = "match_usage"
+	TextChartUsage          = "chart_usage"
+	TextNoChart             = "no_chart"
+	TextIgnoreMatch         = "ignore_match"   // button under calculator alerts
+	TextOpenBookmaker       = "open_bookmaker" // button under calculator alerts, %s = bookmaker
+	TextLanguageSet         = "language_set"
+	TextLanguageUsage       = "language_usage" // %s = current language
+	TextAdminOnly           = "admin_only"
+	TextRestartParserUsage  = "restart_parser_usage"
+)
+
+// catalog holds the texts by language, and the names of event types ("event.<type>"), outcome types
+// ("outcome.<type>"), participants ("participant.<home|away>"), sports ("sport.<tag>") and line classes
+// ("line_class.<class>") where they differ from the English name derived from the tag.
+var catalog = map[string]map[string]string{
+	English: {
+		TextNoValueBets:         "📊 No value bets found.",
+		TextNoLiveValueBets:     "📊 No live value bets found.",
+		TextNoUpcomingValueBets: "📊 No upcoming value bets found.",
+		TextNoCyberValueBets:    "📊 No cyber football value bets found.",
+		TextNoLineMovements:     "📊 No line movements right now.",
+		TextNoArbs:              "📊 No surebets right now.",
+		TextNoBets:              "📒 No bets yet. Use /bet <id> <stake>.",
+		TextNoMatches:           "🔍 No current matches found for \"%s\".",
+		TextMatchUsage:          "Usage: /match <team>, e.g. /match arsenal chelsea",
+		TextChartUsage:          "Usage: /chart <n>, n is the number of a line movement in your last /overlays",
+		TextNoChart:             "📈 No odds history for this line movement yet.",
+		TextIgnoreMatch:         "🚫 Ignore match",
+		TextOpenBookmaker:       "🔗 Open at %s",
+		TextLanguageSet:         "✅ Language: English",
+		TextLanguageUsage:       "Usage: /lang ru|en (current: %s)",
+
+		"participant.home": "Home team",
+		"participant.away": "Away team",
+
+		"outcome.btts_yes": "Yes",
+		"outcome.btts_no":  "No",
+	},
+	Russian: {
+		TextNoValueBets:         "📊 Валуев не найдено.",
+		TextNoLiveValueBets:     "📊 Валуев в лайве не найдено.",
+		TextNoUpcomingValueBets: "📊 Валуев в прематче не найдено.",
+		TextNoCyberValueBets:    "📊 Валуев в киберфутболе не найдено.",
+		TextNoLineMovements:     "📊 Нет актуальных прогрузов.",
+		TextNoArbs:              "📊 Вилок сейчас нет.",
+		TextNoBets:              "📒 Ставок пока нет. Используйте /bet <id> <сумма>.",
+		TextNoMatches:           "🔍 Текущих матчей по запросу \"%s\" не найдено.",
+		TextMatchUsage:          "Использование: /match <команда>, например /match arsenal chelsea",
+		TextChartUsage:          "Использование: /chart <n>, n - номер прогруза в последнем /overlays",
+		TextNoChart:             "📈 Истории коэффициентов по этому прогрузу пока нет.",
+		TextIgnoreMatch:         "🚫 Игнорировать матч",
+		TextOpenBookmaker:       "🔗 Открыть в %s",
+		TextLanguageSet:         "✅ Язык: русский",
+		TextLanguageUsage:       "Использование: /lang ru|en (сейчас: %s)",
+
+		"event.main_match":      "Исход матча",
+		"event.corners":         "Угловые",
+		"event.yellow_cards":    "Жёлтые карточки",
+		"event.fouls":           "Фолы",
+		"event.shots_on_target": "Удары в створ",
+		"event.offsides":        "Офсайды",
+		"event.throw_ins":       "Ауты",
+
+		"event.player_shots":           "Удары игрока",
+		"event.player_shots_on_target": "Удары игрока в створ",
+		"event.both_teams_to_score":    "Обе забьют",
+		"event.correct_score":          "Точный счёт",
+
+		"participant.home": "Хозяева",
+		"participant.away": "Гости",
+
+		"outcome.home_win":         "П1",
+		"outcome.draw":             "X",
+		"outcome.away_win":         "П2",
+		"outcome.total_over":       "ТБ",
+		"outcome.total_under":      "ТМ",
+		"outcome.alt_total_over":   "ТБ (альт.)",
+		"outcome.alt_total_under":  "ТМ (альт.)",
+		"outcome.exact_count":      "Точное число",
+		"outcome.total_interval":   "Интервал",
+		"outcome.handicap_home":    "Ф1",
+		"outcome.handicap_away":    "Ф2",
+		"outcome.double_chance_1x": "1X",
+		"outcome.double_chance_12": "12",
+		"outcome.double_chance_x2": "X2",
+		"outcome.double_chance_2x": "X2",
+		"outcome.btts_yes":         "Да",
+		"outcome.btts_no":          "Нет",
+		"outcome.correct_score":    "Счёт",
+
+		"sport.football":       "Футбол",
+		"sport.cyber_football": "Киберфутбол",
+
+		"line_class.opener":     "открытие",
+		"line_class.shortening": "падает",
+		"line_class.drifting":   "растёт",
+		"line_class.stable":     "на открытии",
+	},
+}
+
+// lookup returns the catalog entry key of lang, falling back to English.
+func lookup(lang, key string) (string, bool) {
+	if s, ok := catalog[lang][key]; ok {
+		return s, true
+	}
+	s, ok := catalog[English][key]
+	return s, ok
+}
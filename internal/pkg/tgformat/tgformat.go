@@ -0,0 +1,349 @@
+// Package tgformat renders Telegram messages of the calculator alerts and the bot in HTML parse mode.
+// Every message type has a text/template per language: the built-in ones are embedded from
+// templates/<language>/*.tmpl, and telegram.templates_dir / telegram.templates in the config replace them
+// without recompiling. Short texts outside the templates and market names come from the catalog.
+//
+// Templates escape text values with esc ({{esc .MatchName}}); the other helpers (link, odds, market, datetime,
+// sport, sportIcon, lineClass) return HTML-safe text in the language of the template.
+package tgformat
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/enums"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+)
+
+// Message types, each rendered by the template of the same name.
+const (
+	ValueAlert        = "value_alert"         // calculator: value bet alert, repeated when the value grew
+	ValueClosed       = "value_closed"        // calculator: an alerted value disappeared
+	LineMovementAlert = "line_movement_alert" // calculator: odds change at one bookmaker
+	SteamAlert        = "steam_alert"         // calculator: outcome moving at several bookmakers
+	TestAlert         = "test_alert"          // calculator: POST /test-alert
+	ChannelSummary    = "channel_summary"     // calculator: pinned daily summary of a channel (value_calculator.telegram.channels)
+	Digest            = "digest"              // calculator: daily/weekly digest (value_calculator.digest)
+
+	ValueBetsHeader     = "value_bets_header" // bot: /top, /live, /upcoming, /cyber
+	ValueBet            = "value_bet"
+	LineMovementsHeader = "line_movements_header" // bot: /overlays
+	LineMovement        = "line_movement"
+	ArbsHeader          = "arbs_header" // bot: /arbs
+	Arb                 = "arb"
+	MyBetsHeader        = "my_bets_header" // bot: /mybets
+	MyBet               = "my_bet"
+	MatchOddsHeader     = "match_odds_header" // bot: /match, one message per match
+	MatchOddsRow        = "match_odds_row"
+	Help                = "help" // bot: /help
+)
+
+// Languages of the messages, chosen per chat (/lang in the bot).
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// Languages lists the supported languages.
+var Languages = []string{English, Russian}
+
+// ParseLanguage returns the language code of s ("RU", " en") and whether it is supported.
+func ParseLanguage(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, l := range Languages {
+		if s == l {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+//go:embed templates
+var builtinFS embed.FS
+
+var (
+	builtinOnce sync.Once
+	builtin     *Templates
+)
+
+// Templates renders the message types in every language. A nil *Templates renders the built-in templates.
+type Templates struct {
+	language string                        // default language: chats that didn't choose one
+	sets     map[string]*template.Template // language -> templates
+	fallback map[string]*template.Template // built-in templates, used when a configured one fails (nil for the built-ins)
+}
+
+// Builtin returns the embedded templates with English as the default language.
+func Builtin() *Templates {
+	builtinOnce.Do(func() {
+		builtin = &Templates{language: English, sets: map[string]*template.Template{}}
+		for _, lang := range Languages {
+			dir := path.Join("templates", lang)
+			entries, err := builtinFS.ReadDir(dir)
+			if err != nil {
+				panic(err)
+			}
+			set := template.New("").Funcs(funcs(lang))
+			for _, e := range entries {
+				text, err := builtinFS.ReadFile(path.Join(dir, e.Name()))
+				if err != nil {
+					panic(err)
+				}
+				template.Must(set.New(strings.TrimSuffix(e.Name(), ".tmpl")).Parse(string(text)))
+			}
+			builtin.sets[lang] = set
+		}
+	})
+	return builtin
+}
+
+// New returns the built-in templates with the ones of cfg in their place and cfg.Language as the default
+// language. Templates named "<type>" replace the type in every language, "<language>/<type>" in one:
+// <type>.tmpl and <language>/<type>.tmpl files of templates_dir, then templates. Unsupported languages,
+// unknown message types and templates that don't parse are errors.
+func New(cfg *config.TelegramConfig) (*Templates, error) {
+	def := Builtin()
+	if cfg == nil {
+		return def, nil
+	}
+	language := English
+	if cfg.Language != "" {
+		l, ok := ParseLanguage(cfg.Language)
+		if !ok {
+			return nil, fmt.Errorf("telegram.language %q is not supported (want %s)", cfg.Language, strings.Join(Languages, ", "))
+		}
+		language = l
+	}
+	if cfg.TemplatesDir == "" && len(cfg.Templates) == 0 {
+		return &Templates{language: language, sets: def.sets}, nil
+	}
+
+	// name ("value_alert" or "ru/value_alert") -> text, applied in order
+	var names []string
+	texts := map[string]string{}
+	if cfg.TemplatesDir != "" {
+		for _, pattern := range []string{"*.tmpl", "*/*.tmpl"} {
+			files, err := filepath.Glob(filepath.Join(cfg.TemplatesDir, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("telegram.templates_dir: %w", err)
+			}
+			for _, file := range files {
+				text, err := os.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("telegram.templates_dir: %w", err)
+				}
+				rel, _ := filepath.Rel(cfg.TemplatesDir, file)
+				name := strings.TrimSuffix(filepath.ToSlash(rel), ".tmpl")
+				names = append(names, name)
+				texts[name] = string(text)
+			}
+		}
+	}
+	configured := make([]string, 0, len(cfg.Templates))
+	for name := range cfg.Templates {
+		configured = append(configured, name)
+	}
+	// Every language before a single one, so "ru/value_alert" wins over "value_alert"
+	sort.Slice(configured, func(i, j int) bool {
+		a, b := strings.Contains(configured[i], "/"), strings.Contains(configured[j], "/")
+		if a != b {
+			return b
+		}
+		return configured[i] < configured[j]
+	})
+	for _, name := range configured {
+		names = append(names, name)
+		texts[name] = cfg.Templates[name]
+	}
+
+	t := &Templates{language: language, sets: map[string]*template.Template{}, fallback: def.sets}
+	for _, lang := range Languages {
+		set, err := def.sets[lang].Clone()
+		if err != nil {
+			return nil, err
+		}
+		t.sets[lang] = set
+	}
+	for _, name := range names {
+		langs, typ := Languages, name
+		if l, rest, ok := strings.Cut(name, "/"); ok {
+			lang, supported := ParseLanguage(l)
+			if !supported {
+				return nil, fmt.Errorf("telegram template %s: language %q is not supported", name, l)
+			}
+			langs, typ = []string{lang}, rest
+		}
+		if def.sets[English].Lookup(typ) == nil {
+			return nil, fmt.Errorf("unknown telegram message type %q", typ)
+		}
+		for _, lang := range langs {
+			if _, err := t.sets[lang].New(typ).Parse(texts[name]); err != nil {
+				return nil, fmt.Errorf("telegram template %s: %w", name, err)
+			}
+		}
+	}
+	slog.Info("Telegram templates loaded", "language", language, "configured", names)
+	return t, nil
+}
+
+// Language returns the default language.
+func (t *Templates) Language() string {
+	if t == nil {
+		return Builtin().language
+	}
+	return t.language
+}
+
+// resolve returns lang if supported, otherwise the default language.
+func (t *Templates) resolve(lang string) string {
+	if l, ok := ParseLanguage(lang); ok {
+		return l
+	}
+	return t.Language()
+}
+
+// Render executes the template of message type name in lang ("" or unsupported = the default language).
+// A configured template failing on data (e.g. a missing field) is logged and the built-in one is used.
+func (t *Templates) Render(lang, name string, data any) string {
+	if t == nil {
+		t = Builtin()
+	}
+	lang = t.resolve(lang)
+	text, err := execute(t.sets[lang], name, data)
+	if err != nil && t.fallback != nil {
+		slog.Error("Telegram template failed, using the built-in one", "template", name, "language", lang, "error", err)
+		text, err = execute(t.fallback[lang], name, data)
+	}
+	if err != nil {
+		slog.Error("Telegram template failed", "template", name, "language", lang, "error", err)
+	}
+	return text
+}
+
+// Text returns the catalog text key in lang ("" or unsupported = the default language), formatted with
+// args when given. Texts are plain, not HTML.
+func (t *Templates) Text(lang, key string, args ...any) string {
+	s, ok := lookup(t.resolve(lang), key)
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(s, args...)
+	}
+	return s
+}
+
+func execute(set *template.Template, name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// funcs returns the template helpers of lang.
+func funcs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"esc":  Escape,
+		"link": Link,
+		"odds": models.FormatOdds,
+		"market": func(eventType, outcomeType, parameter string) string {
+			return Market(lang, eventType, outcomeType, parameter)
+		},
+		"participant": func(participant string) string { return Participant(lang, participant) },
+		"datetime":    DateTime,
+		"sport":       func(sport string) string { return Sport(lang, sport) },
+		"sportIcon":   SportIcon,
+		"lineClass":   func(class string) string { return catalogName(lang, "line_class.", class, class) },
+	}
+}
+
+// catalogName returns the catalog name prefix+tag of lang, or def (escaped) when there is none.
+func catalogName(lang, prefix, tag, def string) string {
+	if s, ok := catalog[lang][prefix+strings.ToLower(tag)]; ok {
+		return Escape(s)
+	}
+	return Escape(def)
+}
+
+// Escape escapes text for HTML parse mode: Telegram requires <, > and & outside tags and entities as entities.
+func Escape(text string) string {
+	return html.EscapeString(text)
+}
+
+// Link returns text as a link to url ("" = plain text), both escaped: bookmaker names in alerts open
+// the event page at the bookmaker.
+func Link(url, text string) string {
+	if url == "" {
+		return Escape(text)
+	}
+	return `<a href="` + Escape(url) + `">` + Escape(text) + `</a>`
+}
+
+// Market returns "Main Match | Home Win (2.5)" in lang ("Исход матча | П1 (2.5)") for an event type, outcome
+// type and optional parameter.
+func Market(lang, eventType, outcomeType, parameter string) string {
+	s := catalogName(lang, "event.", eventType, TitleCase(eventType)) + " | " + catalogName(lang, "outcome.", outcomeType, TitleCase(outcomeType))
+	if parameter != "" {
+		s += " (" + Escape(parameter) + ")"
+	}
+	return s
+}
+
+// Participant returns the team ("participant.home", "participant.away") or player of a team/player market in
+// lang: "home" → "Home team", "c ronaldo" → "C Ronaldo".
+func Participant(lang, participant string) string {
+	words := strings.Fields(participant)
+	for i, w := range words {
+		r := []rune(w)
+		words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	return catalogName(lang, "participant.", participant, strings.Join(words, " "))
+}
+
+// TitleCase converts snake_case to Title Case: "main_match" -> "Main Match".
+func TitleCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// DateTime formats a kick-off time in UTC ("N/A" when unknown).
+func DateTime(t time.Time) string {
+	if t.IsZero() {
+		return "N/A"
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// Sport returns the display name of a sport in lang ("Dota 2", "Киберфутбол"), or the tag as is.
+func Sport(lang, sport string) string {
+	if s := enums.Sport(strings.ToLower(sport)); s.IsValid() || s == enums.CyberFootball {
+		return catalogName(lang, "sport.", sport, s.GetSportInfo().Name)
+	}
+	return Escape(sport)
+}
+
+// SportIcon returns the market line icon: a gamepad for esports and cyber football, a ball otherwise.
+func SportIcon(sport string) string {
+	if s := enums.Sport(strings.ToLower(sport)); s.IsEsports() || s == enums.CyberFootball {
+		return "🎮"
+	}
+	return "⚽"
+}
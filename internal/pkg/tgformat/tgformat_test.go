@@ -0,0 +1,173 @@
+package tgformat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+type testArb struct {
+	N             int
+	MatchName     string
+	EventType     string
+	ProfitPercent float64
+	StartTime     time.Time
+	Legs          []struct {
+		OutcomeType, Parameter, Bookmaker string
+		Odd, StakePercent                 float64
+	}
+}
+
+func TestRenderEscapesText(t *testing.T) {
+	arb := testArb{N: 1, MatchName: "Al-Ahli <U21> vs Al_Nassr & Co (Res.)", EventType: "total_goals", ProfitPercent: 2.345}
+	arb.Legs = append(arb.Legs, struct {
+		OutcomeType, Parameter, Bookmaker string
+		Odd, StakePercent                 float64
+	}{"total_over", "2.5", "Bet<365>", 2.1, 48.2})
+
+	got := Builtin().Render("", Arb, arb)
+	for _, want := range []string{
+		"<b>1. Al-Ahli &lt;U21&gt; vs Al_Nassr &amp; Co (Res.)</b>",
+		"💰 Profit: <b>2.35%</b>",
+		"🎯 Total Goals | Total Over (2.5) — Bet&lt;365&gt;: <b>2.10</b>, stake 48.2%",
+		"🕐 Start: N/A",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("arb %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestNewOverridesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "arbs_header.tmpl"), []byte("from dir {{.Count}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "arb.tmpl"), []byte("from dir"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := New(&config.TelegramConfig{TemplatesDir: dir, Templates: map[string]string{
+		Arb:          "<b>{{esc .MatchName}}</b> {{.Missing}}",
+		MyBetsHeader: "config",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tmpl.Render(English, ArbsHeader, struct{ Count int }{3}); got != "from dir 3" {
+		t.Errorf("templates_dir: got %q", got)
+	}
+	if got := tmpl.Render(English, MyBetsHeader, nil); got != "config" {
+		t.Errorf("templates: got %q", got)
+	}
+	// A configured template failing on the data falls back to the built-in one
+	if got := tmpl.Render(English, Arb, testArb{N: 2, MatchName: "A vs B"}); !strings.Contains(got, "<b>2. A vs B</b>") {
+		t.Errorf("fallback: got %q", got)
+	}
+	// The built-in templates are left alone
+	if got := Builtin().Render(English, ArbsHeader, struct{ Count int }{3}); got != "🔀 <b>Top 3 surebets</b>\n\n" {
+		t.Errorf("built-in: got %q", got)
+	}
+
+	if _, err := New(&config.TelegramConfig{Templates: map[string]string{"value_alrt": "x"}}); err == nil {
+		t.Error("unknown message type must be an error")
+	}
+	if _, err := New(&config.TelegramConfig{Templates: map[string]string{ValueAlert: "{{if}}"}}); err == nil {
+		t.Error("template that doesn't parse must be an error")
+	}
+}
+
+func TestNilTemplatesRenderBuiltin(t *testing.T) {
+	var tmpl *Templates
+	if got := tmpl.Render("", Help, nil); !strings.Contains(got, "/bet &lt;id&gt; &lt;stake&gt; [odd]") {
+		t.Errorf("help is not escaped for HTML: %q", got)
+	}
+}
+
+func TestRenderLanguages(t *testing.T) {
+	arb := testArb{N: 1, MatchName: "A vs B", EventType: "main_match"}
+	arb.Legs = append(arb.Legs, struct {
+		OutcomeType, Parameter, Bookmaker string
+		Odd, StakePercent                 float64
+	}{"home_win", "", "Pinnacle", 2.1, 48.2})
+
+	got := Builtin().Render(Russian, Arb, arb)
+	for _, want := range []string{"💰 Прибыль:", "🎯 Исход матча | П1 — Pinnacle"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ru arb %q does not contain %q", got, want)
+		}
+	}
+	// Unsupported languages render in the default one
+	if got := Builtin().Render("de", Arb, arb); !strings.Contains(got, "🎯 Main Match | Home Win — Pinnacle") {
+		t.Errorf("de arb: got %q", got)
+	}
+
+	tmpl, err := New(&config.TelegramConfig{Language: "RU", Templates: map[string]string{
+		ArbsHeader:                   "all {{.Count}}",
+		Russian + "/" + ArbsHeader:   "ru {{.Count}}",
+		English + "/" + MyBetsHeader: "en",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tmpl.Render("", ArbsHeader, struct{ Count int }{3}); got != "ru 3" {
+		t.Errorf("default language ru: got %q", got)
+	}
+	if got := tmpl.Render(English, ArbsHeader, struct{ Count int }{3}); got != "all 3" {
+		t.Errorf("en: got %q", got)
+	}
+	if got := tmpl.Text("", TextNoArbs); got != "📊 Вилок сейчас нет." {
+		t.Errorf("ru text: got %q", got)
+	}
+	if got := tmpl.Text(English, TextLanguageUsage, "ru"); got != "Usage: /lang ru|en (current: ru)" {
+		t.Errorf("en text: got %q", got)
+	}
+
+	if _, err := New(&config.TelegramConfig{Language: "de"}); err == nil {
+		t.Error("unsupported language must be an error")
+	}
+	if _, err := New(&config.TelegramConfig{Templates: map[string]string{"de/" + Arb: "x"}}); err == nil {
+		t.Error("template of an unsupported language must be an error")
+	}
+}
+
+func TestRenderMatchOddsRow(t *testing.T) {
+	type odd struct {
+		Bookmaker string
+		Odd       float64
+	}
+	row := struct {
+		EventType, OutcomeType, Parameter string
+		BestOdd                           float64
+		Odds                              []odd
+	}{"total_goals", "total_over", "2.5", 1.95, []odd{{"pinnacle", 1.95}, {"fonbet", 1.9}}}
+
+	want := "🎯 Total Goals | Total Over (2.5)\n<b>pinnacle 1.95</b> | fonbet 1.90\n"
+	if got := Builtin().Render(English, MatchOddsRow, row); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLink(t *testing.T) {
+	if got, want := Link("https://example.com/e?a=1&b=2", "Bet<365>"), `<a href="https://example.com/e?a=1&amp;b=2">Bet&lt;365&gt;</a>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := Link("", "Fonbet & Co"); got != "Fonbet &amp; Co" {
+		t.Errorf("without url: got %q", got)
+	}
+}
+
+func TestParticipant(t *testing.T) {
+	if got := Participant(Russian, "home"); got != "Хозяева" {
+		t.Errorf("Participant(ru, home) = %q", got)
+	}
+	if got := Participant(English, "away"); got != "Away team" {
+		t.Errorf("Participant(en, away) = %q", got)
+	}
+	if got := Participant(English, "c ronaldo"); got != "C Ronaldo" {
+		t.Errorf("Participant(en, c ronaldo) = %q", got)
+	}
+}
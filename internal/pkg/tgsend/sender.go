@@ -0,0 +1,300 @@
+// Package tgsend delivers Telegram messages within the Bot API limits: about 30 messages per second per bot
+// and about one per second per chat (20 per minute in groups). Bursts of alerts or long replies wait for
+// their turn instead of failing with 429 Too Many Requests; a 429 that still happens is retried after its
+// retry_after, network and 5xx errors after an exponential backoff. Delivery counters are kept for the
+// status endpoints and logs.
+//
+// The calculator alerts send through a Sender, the bot through a Bot (a *tgbotapi.BotAPI whose Send
+// goes through a Sender), configured by telegram.delivery.
+package tgsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/ratelimit"
+)
+
+// Defaults of telegram.delivery.
+const (
+	DefaultRPS             = 25
+	DefaultPerChatInterval = time.Second
+	DefaultMaxRetries      = 3
+	DefaultRetryBackoff    = time.Second
+
+	maxBackoff = 30 * time.Second
+	// Per-chat limiters unused this long are dropped once there are more than maxIdleChats
+	chatIdleTTL  = time.Minute
+	maxIdleChats = 1000
+)
+
+// API sends requests to the Bot API; *tgbotapi.BotAPI implements it.
+type API interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// Stats are the delivery counters since start.
+type Stats struct {
+	Sent        int64   `json:"sent"`
+	Failed      int64   `json:"failed"`       // given up: error that is not retried or out of retries
+	Retries     int64   `json:"retries"`      // resends after an error
+	RateLimited int64   `json:"rate_limited"` // 429 responses
+	Waiting     int64   `json:"waiting"`      // messages waiting for the limits or a retry now
+	WaitSeconds float64 `json:"wait_seconds"` // total time messages waited for the limits
+}
+
+// Sender sends messages through the global and per-chat limits with retries. Safe for concurrent use.
+type Sender struct {
+	api        API
+	global     *ratelimit.Limiter
+	perChat    time.Duration
+	maxRetries int
+	backoff    time.Duration
+
+	mu    sync.Mutex
+	chats map[int64]*chatLimiter
+
+	sent, failed, retries, rateLimited, waiting atomic.Int64
+	waitNanos                                   atomic.Int64
+}
+
+type chatLimiter struct {
+	limiter  *ratelimit.Limiter
+	lastUsed time.Time
+}
+
+// New returns a Sender sending through api with the limits of cfg (zero values = defaults).
+func New(api API, cfg config.TelegramDeliveryConfig) *Sender {
+	if cfg.RPS <= 0 {
+		cfg.RPS = DefaultRPS
+	}
+	if cfg.PerChatInterval <= 0 {
+		cfg.PerChatInterval = DefaultPerChatInterval
+	}
+	switch {
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
+	case cfg.MaxRetries == 0:
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	return &Sender{
+		api:        api,
+		global:     ratelimit.New(cfg.RPS, 1),
+		perChat:    cfg.PerChatInterval,
+		maxRetries: cfg.MaxRetries,
+		backoff:    cfg.RetryBackoff,
+		chats:      map[int64]*chatLimiter{},
+	}
+}
+
+// Send sends c once the limits allow, retrying 429, 5xx and network errors up to max_retries times.
+// It blocks until the message is sent, given up or ctx is done.
+func (s *Sender) Send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.do(ctx, ChatID(c), func() (tgbotapi.Message, error) {
+		return s.api.Send(c)
+	})
+}
+
+// SendTopicMessage sends msg to the topic threadID of a forum group (0 = the general chat) like Send.
+// tgbotapi v5 has no message_thread_id, so the request is built here.
+func (s *Sender) SendTopicMessage(ctx context.Context, msg tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	if threadID == 0 {
+		return s.Send(ctx, msg)
+	}
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", msg.ChatID)
+	params.AddNonZero("message_thread_id", threadID)
+	params["text"] = msg.Text
+	params.AddNonEmpty("parse_mode", msg.ParseMode)
+	params.AddBool("disable_web_page_preview", msg.DisableWebPagePreview)
+	params.AddBool("disable_notification", msg.DisableNotification)
+	if err := params.AddInterface("reply_markup", msg.ReplyMarkup); err != nil {
+		return tgbotapi.Message{}, err
+	}
+	return s.do(ctx, msg.ChatID, func() (tgbotapi.Message, error) {
+		resp, err := s.api.MakeRequest("sendMessage", params)
+		if err != nil {
+			return tgbotapi.Message{}, err
+		}
+		var sent tgbotapi.Message
+		err = json.Unmarshal(resp.Result, &sent)
+		return sent, err
+	})
+}
+
+// do runs send for a message to chatID through the limits and retries.
+func (s *Sender) do(ctx context.Context, chatID int64, send func() (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	s.waiting.Add(1)
+	defer s.waiting.Add(-1)
+
+	chat := s.chat(chatID)
+	for attempt := 0; ; attempt++ {
+		waitStart := time.Now()
+		if err := chat.Wait(ctx); err != nil {
+			s.fail()
+			return tgbotapi.Message{}, err
+		}
+		if err := s.global.Wait(ctx); err != nil {
+			s.fail()
+			return tgbotapi.Message{}, err
+		}
+		s.waitNanos.Add(int64(time.Since(waitStart)))
+
+		msg, err := send()
+		if err == nil {
+			s.sent.Add(1)
+			metrics.TelegramMessagesSent.Inc()
+			return msg, nil
+		}
+		delay, retry := s.retryDelay(err, attempt)
+		if !retry || attempt >= s.maxRetries {
+			s.fail()
+			return msg, err
+		}
+		s.retries.Add(1)
+		slog.Warn("Telegram send failed, retrying", "chat_id", chatID, "attempt", attempt+1, "delay", delay, "error", err)
+		if chat == nil {
+			if err := sleep(ctx, delay); err != nil {
+				s.fail()
+				return msg, err
+			}
+			continue
+		}
+		// The next messages to the chat wait as well: a 429 applies to every message, not only this one
+		chat.Backoff(delay)
+	}
+}
+
+// fail counts a message that is given up.
+func (s *Sender) fail() {
+	s.failed.Add(1)
+	metrics.TelegramSendFailures.Inc()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryDelay returns how long to wait before resending after err and whether to resend at all: 429 after its
+// retry_after, 5xx and network errors after the backoff doubled per attempt; other API errors (400 bad request,
+// 403 bot blocked) are final. A network error may hide a delivered message, which is then sent twice.
+func (s *Sender) retryDelay(err error, attempt int) (time.Duration, bool) {
+	backoff := s.backoff << attempt
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return backoff, true
+	}
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests:
+		s.rateLimited.Add(1)
+		if apiErr.RetryAfter > 0 {
+			return time.Duration(apiErr.RetryAfter) * time.Second, true
+		}
+		return backoff, true
+	case apiErr.Code >= http.StatusInternalServerError:
+		return backoff, true
+	}
+	return 0, false
+}
+
+// chat returns the limiter of chatID (0 = unknown chat: only the global limit applies).
+func (s *Sender) chat(chatID int64) *ratelimit.Limiter {
+	if chatID == 0 {
+		return nil
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.chats) > maxIdleChats {
+		for id, c := range s.chats {
+			if now.Sub(c.lastUsed) > chatIdleTTL {
+				delete(s.chats, id)
+			}
+		}
+	}
+	c, ok := s.chats[chatID]
+	if !ok {
+		c = &chatLimiter{limiter: ratelimit.New(1/s.perChat.Seconds(), 1)}
+		s.chats[chatID] = c
+	}
+	c.lastUsed = now
+	return c.limiter
+}
+
+// Stats returns the delivery counters.
+func (s *Sender) Stats() Stats {
+	if s == nil {
+		return Stats{}
+	}
+	return Stats{
+		Sent:        s.sent.Load(),
+		Failed:      s.failed.Load(),
+		Retries:     s.retries.Load(),
+		RateLimited: s.rateLimited.Load(),
+		Waiting:     s.waiting.Load(),
+		WaitSeconds: time.Duration(s.waitNanos.Load()).Seconds(),
+	}
+}
+
+// ChatID returns the chat a message, edit or media goes to (0 for other requests).
+func ChatID(c tgbotapi.Chattable) int64 {
+	switch m := c.(type) {
+	case tgbotapi.MessageConfig:
+		return m.ChatID
+	case tgbotapi.PhotoConfig:
+		return m.ChatID
+	case tgbotapi.DocumentConfig:
+		return m.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return m.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return m.ChatID
+	}
+	return 0
+}
+
+// Bot is a *tgbotapi.BotAPI whose Send goes through a Sender, so bot code gets the limits and retries
+// without changes. The other methods (Request, GetUpdatesChan, ...) are the API's own.
+type Bot struct {
+	*tgbotapi.BotAPI
+	sender *Sender
+}
+
+// NewBot wraps api with the limits of cfg.
+func NewBot(api *tgbotapi.BotAPI, cfg config.TelegramDeliveryConfig) *Bot {
+	return &Bot{BotAPI: api, sender: New(api, cfg)}
+}
+
+// Send sends c through the limits, blocking until it is sent or given up.
+func (b *Bot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return b.sender.Send(context.Background(), c)
+}
+
+// Stats returns the delivery counters of the bot.
+func (b *Bot) Stats() Stats {
+	return b.sender.Stats()
+}
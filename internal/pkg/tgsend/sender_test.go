@@ -0,0 +1,131 @@
+package tgsend
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+// fakeAPI returns the queued errors first, then succeeds; it records when each chat got a message.
+type fakeAPI struct {
+	mu     sync.Mutex
+	errs   []error
+	calls  int
+	sentAt map[int64][]time.Time
+	params tgbotapi.Params // of the last MakeRequest
+}
+
+func (f *fakeAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return tgbotapi.Message{}, err
+	}
+	if f.sentAt == nil {
+		f.sentAt = map[int64][]time.Time{}
+	}
+	f.sentAt[ChatID(c)] = append(f.sentAt[ChatID(c)], time.Now())
+	return tgbotapi.Message{MessageID: f.calls}, nil
+}
+
+func (f *fakeAPI) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.params = params
+	return &tgbotapi.APIResponse{Ok: true, Result: []byte(`{"message_id": 7}`)}, nil
+}
+
+func TestSenderRetries(t *testing.T) {
+	tooMany := &tgbotapi.Error{Code: 429, Message: "Too Many Requests"}
+	api := &fakeAPI{errs: []error{tooMany, errors.New("connection reset"), &tgbotapi.Error{Code: 502, Message: "Bad Gateway"}}}
+	s := New(api, config.TelegramDeliveryConfig{RPS: 1000, PerChatInterval: time.Millisecond, RetryBackoff: time.Millisecond})
+
+	if _, err := s.Send(context.Background(), tgbotapi.NewMessage(1, "alert")); err != nil {
+		t.Fatalf("expected delivery after 3 retries, got %v", err)
+	}
+	if got := s.Stats(); got.Sent != 1 || got.Retries != 3 || got.RateLimited != 1 || got.Failed != 0 || got.Waiting != 0 {
+		t.Errorf("stats = %+v", got)
+	}
+
+	// Out of retries
+	api.errs = []error{tooMany, tooMany}
+	s = New(api, config.TelegramDeliveryConfig{RPS: 1000, PerChatInterval: time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+	if _, err := s.Send(context.Background(), tgbotapi.NewMessage(1, "alert")); err == nil {
+		t.Fatal("expected the 429 after the last retry")
+	}
+	if got := s.Stats(); got.Failed != 1 || got.Retries != 1 || got.RateLimited != 2 {
+		t.Errorf("stats = %+v", got)
+	}
+
+	// Errors of the request itself are not retried
+	api.errs = []error{&tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}}
+	api.calls = 0
+	s = New(api, config.TelegramDeliveryConfig{RetryBackoff: time.Millisecond})
+	if _, err := s.Send(context.Background(), tgbotapi.NewMessage(1, "alert")); err == nil || api.calls != 1 {
+		t.Errorf("403: err %v after %d calls, want an error after 1", err, api.calls)
+	}
+}
+
+func TestSenderPerChatInterval(t *testing.T) {
+	api := &fakeAPI{}
+	s := New(api, config.TelegramDeliveryConfig{RPS: 1000, PerChatInterval: 50 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for _, chatID := range []int64{1, 1, 1, 2} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Send(context.Background(), tgbotapi.NewMessage(chatID, "reply")); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	chat1 := api.sentAt[1]
+	if len(chat1) != 3 || len(api.sentAt[2]) != 1 {
+		t.Fatalf("sent %v", api.sentAt)
+	}
+	// Token bucket: allow a little jitter below the interval
+	if gap := chat1[2].Sub(chat1[0]); gap < 90*time.Millisecond {
+		t.Errorf("3 messages to one chat within %v, want >= 2 intervals", gap)
+	}
+	if d := api.sentAt[2][0].Sub(chat1[0]); d > 40*time.Millisecond {
+		t.Errorf("other chat waited %v for chat 1", d)
+	}
+}
+
+func TestSenderCancelled(t *testing.T) {
+	s := New(&fakeAPI{errs: []error{&tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 30}}}}, config.TelegramDeliveryConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Send(ctx, tgbotapi.NewMessage(1, "alert")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline while waiting retry_after, got %v", err)
+	}
+}
+
+func TestSendTopicMessage(t *testing.T) {
+	api := &fakeAPI{}
+	s := New(api, config.TelegramDeliveryConfig{})
+	msg := tgbotapi.NewMessage(-100123, "<b>alert</b>")
+	msg.ParseMode = tgbotapi.ModeHTML
+	sent, err := s.SendTopicMessage(context.Background(), msg, 42)
+	if err != nil || sent.MessageID != 7 {
+		t.Fatalf("sent %+v, err %v", sent, err)
+	}
+	for key, want := range map[string]string{"chat_id": "-100123", "message_thread_id": "42", "text": "<b>alert</b>", "parse_mode": "HTML"} {
+		if api.params[key] != want {
+			t.Errorf("%s = %q, want %q", key, api.params[key], want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLength is the length of the SQL kept in a query span.
+const maxStatementLength = 500
+
+// WrapDriver returns d with a span for every query and statement run on its connections within a trace
+// ("db.query", with the SQL). Register it with sql.Register and open the database by that name.
+func WrapDriver(d driver.Driver) driver.Driver {
+	return tracedDriver{d}
+}
+
+type tracedDriver struct {
+	driver.Driver
+}
+
+func (d tracedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{c}, nil
+}
+
+// tracedConn passes everything through to the driver connection; Exec and Query get a span.
+type tracedConn struct {
+	driver.Conn
+}
+
+var (
+	_ driver.ExecerContext      = (*tracedConn)(nil)
+	_ driver.QueryerContext     = (*tracedConn)(nil)
+	_ driver.ConnPrepareContext = (*tracedConn)(nil)
+	_ driver.ConnBeginTx        = (*tracedConn)(nil)
+	_ driver.Pinger             = (*tracedConn)(nil)
+	_ driver.SessionResetter    = (*tracedConn)(nil)
+	_ driver.Validator          = (*tracedConn)(nil)
+)
+
+// startQuery starts the span of query when ctx is traced.
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil, false
+	}
+	statement := strings.Join(strings.Fields(query), " ")
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+	op, _, _ := strings.Cut(statement, " ")
+	ctx, span := Start(ctx, "db.query", attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", strings.ToUpper(op)), attribute.String("db.statement", statement))
+	return ctx, span, true
+}
+
+func endQuery(span trace.Span, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		err = nil
+	}
+	End(span, err)
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span, traced := startQuery(ctx, query)
+	res, err := ec.ExecContext(ctx, query, args)
+	if traced {
+		endQuery(span, err)
+	}
+	return res, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span, traced := startQuery(ctx, query)
+	rows, err := qc.QueryContext(ctx, query, args)
+	if traced {
+		endQuery(span, err)
+	}
+	return rows, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return pc.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback of drivers without BeginTx
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
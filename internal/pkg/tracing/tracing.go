@@ -0,0 +1,110 @@
+// Package tracing sets up OpenTelemetry tracing of the pipeline: parser → health aggregator → orchestrator →
+// calculator → Telegram.
+//
+// Setup installs the global tracer provider, exporting over OTLP/HTTP (tracing in the config), and the W3C trace
+// context propagator. Spans of the internal HTTP calls come from Transport (client) and Handler (server), which
+// carry the trace between the services; requests to bookmakers go through ExternalTransport, which records them
+// inside a cycle but never sends trace headers to the bookmaker. Without Setup every span is a no-op.
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+)
+
+const instrumentationName = "github.com/Vodeneev/vodeneevbet"
+
+// Setup starts exporting the spans of service when cfg.Enabled. The returned shutdown flushes the spans
+// that are still buffered; it is a no-op when tracing is off.
+func Setup(ctx context.Context, cfg *config.TracingConfig, service string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if cfg == nil || !cfg.Enabled {
+		return noop, nil
+	}
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return noop, err
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service)))
+	if err != nil {
+		return noop, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	slog.Info("Tracing enabled", "service", service, "endpoint", cfg.Endpoint, "sample_ratio", ratio)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the pipeline as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it failed with err when err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport traces the requests of base (nil = http.DefaultTransport) to the other services and passes the
+// trace on to them.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// ExternalTransport traces the requests of base (nil = http.DefaultTransport) to a bookmaker made within a
+// traced operation (a parse cycle); no trace headers are sent and requests outside a trace are not recorded.
+func ExternalTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base,
+		otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return trace.SpanContextFromContext(r.Context()).IsValid()
+		}),
+	)
+}
+
+// Handler traces the requests served by h, continuing the trace of the calling service.
+func Handler(h http.Handler, service string) http.Handler {
+	return otelhttp.NewHandler(h, service, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Path
+	}))
+}
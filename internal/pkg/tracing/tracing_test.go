@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTransports(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	get := func(ctx context.Context, rt http.RoundTripper) {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		resp, err := (&http.Client{Transport: rt}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	ctx, span := Start(context.Background(), "parse.cycle")
+	get(ctx, Transport(nil))
+	if traceparent == "" {
+		t.Error("Transport: no traceparent sent to the service")
+	}
+	get(ctx, ExternalTransport(nil))
+	if traceparent != "" {
+		t.Errorf("ExternalTransport: traceparent %q sent to the bookmaker", traceparent)
+	}
+	span.End()
+	if n := len(recorder.Ended()); n != 3 {
+		t.Errorf("%d spans, want 3 (the cycle and both requests)", n)
+	}
+
+	// Outside a trace bookmaker requests are not recorded
+	get(context.Background(), ExternalTransport(nil))
+	if n := len(recorder.Ended()); n != 3 {
+		t.Errorf("%d spans after an untraced bookmaker request, want 3", n)
+	}
+}
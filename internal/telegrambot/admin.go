@@ -0,0 +1,289 @@
+package telegrambot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// adminRequestTimeout bounds one request to the parser services (a restart waits for the parser to stop).
+const adminRequestTimeout = 30 * time.Second
+
+// isAdminUser reports whether userID may use the admin commands (nobody when AdminUserIDs is empty).
+func isAdminUser(config BotConfig, userID int64) bool {
+	for _, id := range config.AdminUserIDs {
+		if userID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAdminCommand runs /status, /parsers, /proxies and /restart_parser for admins.
+func handleAdminCommand(bot *tgsend.Bot, message *tgbotapi.Message, config BotConfig, command string, args []string) {
+	chatID := message.Chat.ID
+	lang := chatLanguage(config, chatID)
+	if !isAdminUser(config, message.From.ID) {
+		slog.Warn("Admin command from non-admin user", "user_id", message.From.ID, "command", command)
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextAdminOnly)))
+		return
+	}
+	if config.ParserURL == "" && len(config.BookmakerServices) == 0 {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ Parser URL is not configured (-parser-url or PARSER_URL)."))
+		return
+	}
+	if _, err := bot.Request(tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminRequestTimeout)
+	defer cancel()
+	var text string
+	var err error
+	switch command {
+	case "/status":
+		text = formatServiceStatus(fetchServiceStats(ctx, config))
+	case "/parsers":
+		text, err = fetchParsersStatus(ctx, config)
+	case "/proxies":
+		text = formatProxies(fetchProxies(ctx, config))
+	case "/restart_parser":
+		if len(args) == 0 {
+			_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextRestartParserUsage)))
+			return
+		}
+		text, err = restartParser(ctx, config, strings.ToLower(args[0]))
+		slog.Info("Parser restart requested from Telegram", "user_id", message.From.ID, "parser", args[0], "error", err)
+	}
+	if err != nil {
+		slog.Warn("Admin command failed", "command", command, "error", err)
+		text = "❌ Error: " + tgformat.Escape(err.Error())
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send admin command response", "chat_id", chatID, "command", command, "error", err)
+	}
+}
+
+// adminServices returns the services the admin commands query: the orchestrator (parser-url) first,
+// then the bookmaker services of parser.bookmaker_services by name.
+func adminServices(config BotConfig) []adminService {
+	var out []adminService
+	if config.ParserURL != "" {
+		out = append(out, newAdminService(config, "orchestrator", config.ParserURL))
+	}
+	names := make([]string, 0, len(config.BookmakerServices))
+	for name := range config.BookmakerServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, newAdminService(config, name, config.BookmakerServices[name]))
+	}
+	return out
+}
+
+type adminService struct {
+	Name string
+	URL  string
+	API  *client.Orchestrator
+}
+
+func newAdminService(config BotConfig, name, baseURL string) adminService {
+	return adminService{Name: name, URL: baseURL, API: newOrchestrator(config, baseURL)}
+}
+
+// newOrchestrator returns the client of the parser or bookmaker service at baseURL.
+func newOrchestrator(config BotConfig, baseURL string) *client.Orchestrator {
+	return client.NewOrchestrator(baseURL, client.WithHTTPClient(config.httpClient(adminRequestTimeout)))
+}
+
+// serviceStats is the /stats answer of one service (Err set when it is unreachable).
+type serviceStats struct {
+	Service adminService
+	Stats   client.ParserStats
+	Err     error
+}
+
+// fetchServiceStats queries /stats of all admin services in parallel.
+func fetchServiceStats(ctx context.Context, config BotConfig) []serviceStats {
+	services := adminServices(config)
+	out := make([]serviceStats, len(services))
+	var wg sync.WaitGroup
+	for i, s := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i].Service = s
+			out[i].Stats, out[i].Err = s.API.Stats(ctx)
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+// formatServiceStatus renders /status: uptime of every service and the last parse of its parsers.
+// The orchestrator reports the parsers of all bookmaker services, which are then not repeated.
+func formatServiceStatus(stats []serviceStats) string {
+	var b strings.Builder
+	b.WriteString("🩺 <b>Status</b>\n")
+	seen := make(map[string]bool)
+	for _, s := range stats {
+		b.WriteString("\n")
+		if s.Err != nil {
+			fmt.Fprintf(&b, "🔴 <b>%s</b>: unreachable\n   %s\n", tgformat.Escape(s.Service.Name), tgformat.Escape(s.Err.Error()))
+			continue
+		}
+		fmt.Fprintf(&b, "🟢 <b>%s</b>: up %s\n", tgformat.Escape(s.Service.Name), s.Stats.Uptime)
+		for _, p := range s.Stats.Parsers {
+			if seen[p.Parser] {
+				continue
+			}
+			seen[p.Parser] = true
+			r := p.LastReport
+			fmt.Fprintf(&b, "   %s %s: %s, %d matches in %s (runs %d, failed %d)\n", parseStatusIcon(r.Status),
+				tgformat.Escape(p.Parser), r.Status, r.MatchesAdded, r.Duration, p.Runs, p.FailedRuns)
+		}
+	}
+	return b.String()
+}
+
+func parseStatusIcon(status string) string {
+	switch status {
+	case interfaces.ParseStatusOK:
+		return "✅"
+	case interfaces.ParseStatusPartial:
+		return "⚠️"
+	case "":
+		return "⏳"
+	}
+	return "❌"
+}
+
+// fetchParsersStatus renders /parsers from the orchestrator (or from each bookmaker service without it):
+// pause state and the last cycle of every parser.
+func fetchParsersStatus(ctx context.Context, config BotConfig) (string, error) {
+	services := adminServices(config)
+	if config.ParserURL != "" {
+		services = services[:1]
+	}
+	var b strings.Builder
+	b.WriteString("⚙️ <b>Parsers</b>\n")
+	for _, s := range services {
+		resp, err := s.API.Parsers(ctx)
+		if err != nil {
+			if config.ParserURL != "" {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\n🔴 <b>%s</b>: %s\n", tgformat.Escape(s.Name), tgformat.Escape(err.Error()))
+			continue
+		}
+		for _, p := range resp.Parsers {
+			state := "running"
+			if p.Paused {
+				state = "paused"
+			}
+			fmt.Fprintf(&b, "\n<b>%s</b> (%s)\n", tgformat.Escape(p.Name), state)
+			c := p.LastCycle
+			if c == nil {
+				b.WriteString("   no cycle yet\n")
+				continue
+			}
+			fmt.Fprintf(&b, "   last cycle #%d: %s, %d matches, %d errors, %s ago\n",
+				c.CycleID, c.Duration, c.Matches, c.Errors, time.Since(c.Finished).Round(time.Second))
+			if c.LastError != "" {
+				fmt.Fprintf(&b, "   last error: %s\n", tgformat.Escape(c.LastError))
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// serviceProxies is the /proxies answer of one service.
+type serviceProxies struct {
+	Service adminService
+	Proxies client.Proxies
+	Err     error
+}
+
+// fetchProxies queries /proxies of all admin services in parallel (each bookmaker service has its own pool).
+func fetchProxies(ctx context.Context, config BotConfig) []serviceProxies {
+	services := adminServices(config)
+	out := make([]serviceProxies, len(services))
+	var wg sync.WaitGroup
+	for i, s := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i].Service = s
+			out[i].Proxies, out[i].Err = s.API.Proxies(ctx)
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+// formatProxies renders /proxies: pool size, banned count and the success rate of every proxy.
+// Services without proxies are left out.
+func formatProxies(proxies []serviceProxies) string {
+	var b strings.Builder
+	b.WriteString("🌐 <b>Proxies</b>\n")
+	empty := true
+	for _, s := range proxies {
+		if s.Err != nil {
+			fmt.Fprintf(&b, "\n🔴 <b>%s</b>: %s\n", tgformat.Escape(s.Service.Name), tgformat.Escape(s.Err.Error()))
+			empty = false
+			continue
+		}
+		if s.Proxies.Count == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "\n<b>%s</b>: %d proxies, %d banned\n", tgformat.Escape(s.Service.Name), s.Proxies.Count, s.Proxies.Banned)
+		for _, p := range s.Proxies.Proxies {
+			icon := "🟢"
+			if p.Banned {
+				icon = "🔴"
+			}
+			fmt.Fprintf(&b, "   %s %s: %.0f%% ok, %d ms", icon, tgformat.Escape(p.Proxy), p.SuccessRate*100, p.AvgLatencyMs)
+			if p.Banned && !p.BannedUntil.IsZero() {
+				fmt.Fprintf(&b, ", banned for %s", time.Until(p.BannedUntil).Round(time.Second))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if empty {
+		b.WriteString("\nNo proxies configured.")
+	}
+	return b.String()
+}
+
+// restartParser restarts a parser in its bookmaker service (POST /admin/restart-parser). The service is
+// found in parser.bookmaker_services; without it the parser runs in the parser-url process.
+func restartParser(ctx context.Context, config BotConfig, name string) (string, error) {
+	baseURL, ok := config.BookmakerServices[name]
+	if !ok {
+		if config.ParserURL == "" {
+			return "", fmt.Errorf("unknown parser %q", name)
+		}
+		baseURL = config.ParserURL
+	}
+	restartedAt, err := newOrchestrator(config, baseURL).RestartParser(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔄 Parser <b>%s</b> restarted at %s UTC", tgformat.Escape(name), restartedAt.UTC().Format("15:04:05")), nil
+}
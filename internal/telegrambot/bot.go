@@ -0,0 +1,912 @@
+// Package telegrambot is the interactive Telegram bot: value bets, line movements, surebets, settings
+// and the admin commands, all read from the calculator API (pkg/client). cmd/telegram-bot runs it as a
+// service, cmd/allinone in the process of the calculator.
+package telegrambot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/config"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/healthcheck"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/metrics"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// How often the delivery counters (sent, retries, 429s) are logged
+const deliveryStatsInterval = 15 * time.Minute
+
+type BotConfig struct {
+	Token          string
+	CalculatorURL  string
+	Calculator     *client.Calculator // client of CalculatorURL
+	UpdateTimeout  int
+	AllowedUserIDs []int64             // Optional: restrict access to specific users
+	AdminUserIDs   []int64             // Optional: users allowed to run /status, /parsers, /proxies, /restart_parser
+	ParserURL      string              // Optional: parser (orchestrator) health server for the admin commands
+	WebAppURL      string              // Optional: public https URL of the calculator WebApp (/webapp/)
+	Templates      *tgformat.Templates // Message templates (telegram.* of the config file, built-in without it)
+	// Bookmaker services of parser.bookmaker_services (config file): name -> base URL, for the admin commands
+	BookmakerServices map[string]string
+
+	Delivery    config.TelegramDeliveryConfig // Rate limits and retries of the replies (telegram.delivery)
+	MetricsAddr string                        // Optional: listen address of /metrics and the health endpoints
+	// Optional: transport of the calculator and parser requests (nil = http.DefaultTransport); cmd/allinone
+	// serves them in process
+	Transport http.RoundTripper
+}
+
+// httpClient returns a client of the calculator and parser requests.
+func (c BotConfig) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: c.Transport}
+}
+
+// Run serves the bot until ctx is done. Fails when the token is rejected.
+func Run(ctx context.Context, botConfig BotConfig) error {
+	slog.Info("Starting Telegram bot...")
+	slog.Info("Calculator URL", "url", botConfig.CalculatorURL)
+
+	api, err := tgbotapi.NewBotAPI(botConfig.Token)
+	if err != nil {
+		return fmt.Errorf("failed to create bot: %w", err)
+	}
+	// Replies are sent within the Telegram rate limits and retried on 429 (telegram.delivery)
+	bot := tgsend.NewBot(api, botConfig.Delivery)
+
+	bot.Debug = false
+
+	// Test bot connection by getting bot info
+	botInfo, err := bot.GetMe()
+	if err != nil {
+		return fmt.Errorf("failed to get bot info (token might be invalid): %w", err)
+	}
+
+	slog.Info("Authorized on account", "username", botInfo.UserName, "id", botInfo.ID)
+	slog.Info("Bot is ready to receive messages")
+	if botConfig.WebAppURL != "" {
+		setupWebAppMenuButton(bot, botConfig.WebAppURL)
+	}
+	slog.Debug("Bot token", "token_preview", fmt.Sprintf("%s...%s", botConfig.Token[:10], botConfig.Token[len(botConfig.Token)-4:]))
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = botConfig.UpdateTimeout
+
+	// Prometheus metrics (Telegram delivery) and the health endpoints: the bot token gates readiness
+	if botConfig.MetricsAddr != "" {
+		checks := healthcheck.New("telegram-bot")
+		checks.Require("telegram", func(context.Context) error {
+			_, err := bot.GetMe()
+			return err
+		})
+		checks.Observe("calculator", botConfig.Calculator.Ping)
+		go serveMetrics(ctx, botConfig.MetricsAddr, checks)
+	}
+
+	// Delivery counters in the logs
+	go func() {
+		ticker := time.NewTicker(deliveryStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				logDeliveryStats(bot)
+			}
+		}
+	}()
+
+	// Start bot handler
+	slog.Info("Starting updates channel...")
+	updates := bot.GetUpdatesChan(u)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("PANIC in bot handler", "error", r)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Stopping bot updates...")
+				logDeliveryStats(bot)
+				bot.StopReceivingUpdates()
+				return
+			case update := <-updates:
+				// Handle each update in a separate goroutine to prevent one error from blocking others
+				go func(upd tgbotapi.Update) {
+					defer func() {
+						if r := recover(); r != nil {
+							slog.Error("PANIC handling update", "update_id", upd.UpdateID, "error", r)
+						}
+					}()
+
+					// Inline buttons under calculator alerts ("🚫 Ignore match")
+					if upd.CallbackQuery != nil {
+						handleCallbackQuery(bot, upd.CallbackQuery, botConfig)
+						return
+					}
+
+					if upd.Message == nil {
+						return
+					}
+
+					slog.Debug("Received message", "user_id", upd.Message.From.ID, "text", upd.Message.Text)
+
+					// Check if user is allowed (if restrictions are set)
+					if len(botConfig.AllowedUserIDs) > 0 {
+						if !isAllowedUser(botConfig, upd.Message.From.ID) {
+							// In groups: do not reply at all, so only the owner sees their own replies
+							if upd.Message.Chat.IsGroup() || upd.Message.Chat.IsSuperGroup() {
+								slog.Debug("Ignoring message from non-allowed user in group", "user_id", upd.Message.From.ID, "chat_id", upd.Message.Chat.ID)
+								return
+							}
+							msg := tgbotapi.NewMessage(upd.Message.Chat.ID, "Access denied. You are not authorized to use this bot.")
+							if _, err := bot.Send(msg); err != nil {
+								slog.Error("Failed to send access denied message", "user_id", upd.Message.From.ID, "error", err)
+							}
+							return
+						}
+					}
+
+					handleMessage(bot, upd.Message, botConfig)
+				}(update)
+			}
+		}
+	}()
+
+	// Wait for context cancellation
+	<-ctx.Done()
+	slog.Info("Telegram bot stopped")
+	return nil
+}
+
+// logDeliveryStats logs the delivery counters of the replies.
+func logDeliveryStats(bot *tgsend.Bot) {
+	s := bot.Stats()
+	slog.Info("Telegram delivery stats", "sent", s.Sent, "failed", s.Failed, "retries", s.Retries, "rate_limited", s.RateLimited, "wait_seconds", s.WaitSeconds)
+}
+
+// serveMetrics serves /metrics and the health endpoints of checks on addr until ctx is done.
+func serveMetrics(ctx context.Context, addr string, checks *healthcheck.Checker) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	checks.Register(mux)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	slog.Info("Metrics server listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Error("Metrics server error", "error", err)
+	}
+}
+
+// isAllowedUser reports whether userID may use the bot (any user when AllowedUserIDs is empty).
+func isAllowedUser(config BotConfig, userID int64) bool {
+	if len(config.AllowedUserIDs) == 0 {
+		return true
+	}
+	for _, id := range config.AllowedUserIDs {
+		if userID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func handleMessage(bot *tgsend.Bot, message *tgbotapi.Message, config BotConfig) {
+	text := strings.TrimSpace(message.Text)
+	if text == "" {
+		return
+	}
+
+	// Reply to a /settings prompt: the new value of the field
+	if field, ok := settingsPromptField(bot, message); ok && !strings.HasPrefix(text, "/") {
+		updateSetting(bot, message, config, field)
+		return
+	}
+
+	// Reply to a "📝 Track" prompt: <stake> [odd]
+	if id, ok := trackPromptValueBetID(bot, message); ok && !strings.HasPrefix(text, "/") {
+		placeBet(bot, message.Chat.ID, config, append([]string{id}, strings.Fields(text)...))
+		return
+	}
+
+	// Handle commands
+	if strings.HasPrefix(text, "/") {
+		parts := strings.Fields(text)
+		command := strings.ToLower(parts[0])
+
+		switch command {
+		case "/start":
+			startAsyncProcessing(bot, message.Chat.ID, config)
+		case "/help":
+			sendHelpMessage(bot, message.Chat.ID, config)
+		case "/app":
+			sendWebAppButton(bot, message.Chat.ID, config)
+		case "/menu":
+			sendMainMenu(bot, message.Chat.ID)
+		case "/settings":
+			sendSettings(bot, message.Chat.ID, config)
+		case "/lang":
+			setChatLanguage(bot, message.Chat.ID, config, parts[1:])
+		case "/top":
+			limit := 5
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "", "")
+		case "/live":
+			limit := 5
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "live", "")
+		case "/upcoming":
+			limit := 5
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "upcoming", "")
+		case "/cyber":
+			limit := 5
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "", "cyber_football")
+		case "/overlays":
+			limit := 10
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendLineMovements(bot, message.Chat.ID, config, limit)
+		case "/arbs":
+			limit := 5
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+					limit = n
+				}
+			}
+			fetchAndSendArbitrages(bot, message.Chat.ID, config, limit)
+		case "/bet":
+			placeBet(bot, message.Chat.ID, config, parts[1:])
+		case "/mybets":
+			fetchAndSendMyBets(bot, message.Chat.ID, config)
+		case "/match":
+			sendMatchOdds(bot, message.Chat.ID, config, parts[1:])
+		case "/chart":
+			sendLineMovementChart(bot, message.Chat.ID, config, parts[1:])
+		case "/stop":
+			stopAsyncProcessing(bot, message.Chat.ID, config)
+		case "/stop_values":
+			stopAlertType(bot, message.Chat.ID, config, "values", "Алерты по валуям отключены.")
+		case "/stop_overlays":
+			stopAlertType(bot, message.Chat.ID, config, "overlays", "Алерты по прогрузам отключены.")
+		case "/cleardb":
+			clearDBAndSendResult(bot, message.Chat.ID, config)
+		case "/status", "/parsers", "/proxies", "/restart_parser":
+			handleAdminCommand(bot, message, config, command, parts[1:])
+		default:
+			msg := tgbotapi.NewMessage(message.Chat.ID, "Unknown command. Use /help to see available commands.")
+			if _, err := bot.Send(msg); err != nil {
+				slog.Error("Failed to send unknown command message", "user_id", message.From.ID, "error", err)
+			}
+		}
+	} else {
+		// Try to parse as inline query for diffs
+		// Format: "top 10" or "live 5" or "upcoming 3"
+		parts := strings.Fields(strings.ToLower(text))
+		if len(parts) >= 1 {
+			switch parts[0] {
+			case "top":
+				limit := 5
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "", "")
+			case "live":
+				limit := 5
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "live", "")
+			case "upcoming":
+				limit := 5
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "upcoming", "")
+			case "cyber":
+				limit := 5
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendDiffs(bot, message.Chat.ID, config, limit, "", "cyber_football")
+			case "overlays":
+				limit := 10
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendLineMovements(bot, message.Chat.ID, config, limit)
+			case "arbs":
+				limit := 5
+				if len(parts) > 1 {
+					if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 && n <= 50 {
+						limit = n
+					}
+				}
+				fetchAndSendArbitrages(bot, message.Chat.ID, config, limit)
+			case "match":
+				sendMatchOdds(bot, message.Chat.ID, config, parts[1:])
+			case "chart":
+				sendLineMovementChart(bot, message.Chat.ID, config, parts[1:])
+			case "menu":
+				sendMainMenu(bot, message.Chat.ID)
+			default:
+				sendHelpMessage(bot, message.Chat.ID, config)
+			}
+		}
+	}
+}
+
+func sendHelpMessage(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	helpText := config.Templates.Render(chatLanguage(config, chatID), tgformat.Help, nil)
+
+	msg := tgbotapi.NewMessage(chatID, helpText)
+	msg.ParseMode = tgbotapi.ModeHTML
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send help message", "chat_id", chatID, "error", err)
+	}
+}
+
+func clearDBAndSendResult(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	_, _ = bot.Request(typing)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 65*time.Second)
+	defer cancel()
+	m, err := config.Calculator.ClearDB(ctx)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка: "+err.Error())
+		_, _ = bot.Send(msg)
+		return
+	}
+	if m == "" {
+		m = "Таблицы БД очищены."
+	}
+	msg := tgbotapi.NewMessage(chatID, "✅ "+m)
+	_, _ = bot.Send(msg)
+}
+
+// ignoreCallbackPrefix matches the callback data of the calculator's "🚫 Ignore match" button: "ignore:<token>".
+const ignoreCallbackPrefix = "ignore:"
+
+// handleCallbackQuery handles inline buttons under calculator alerts and the bot's own messages.
+func handleCallbackQuery(bot *tgsend.Bot, cq *tgbotapi.CallbackQuery, config BotConfig) {
+	answer := func(text string) {
+		if _, err := bot.Request(tgbotapi.NewCallback(cq.ID, text)); err != nil {
+			slog.Debug("Failed to answer callback query", "user_id", cq.From.ID, "error", err)
+		}
+	}
+	if !isAllowedUser(config, cq.From.ID) {
+		answer("Access denied.")
+		return
+	}
+	// Buttons under value bets and menus of the bot itself
+	if handleKeyboardCallback(bot, cq, config, answer) {
+		return
+	}
+	token, ok := strings.CutPrefix(cq.Data, ignoreCallbackPrefix)
+	if !ok || token == "" {
+		answer("Unknown button.")
+		return
+	}
+	answer(ignoreMatch(config, client.IgnoreRequest{Token: token}, cq.From))
+}
+
+// ignoreMatch adds a match to the calculator's ignore list (POST /ignores) and returns the text for the
+// callback answer. req holds the token of an alert's ignore button or match_group_key and match_name.
+func ignoreMatch(config BotConfig, req client.IgnoreRequest, user *tgbotapi.User) string {
+	req.Reason = "ignored from Telegram by " + user.String()
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	m, err := config.Calculator.IgnoreMatch(ctx, req)
+	if err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) {
+			return "❌ " + apiErr.Error()
+		}
+		slog.Error("Failed to ignore match", "match_group_key", req.MatchGroupKey, "token", req.Token, "error", err)
+		return "❌ Не удалось связаться с калькулятором"
+	}
+	slog.Info("Match ignored via bot", "match", m.MatchName, "expires_at", m.ExpiresAt, "user_id", user.ID)
+	return fmt.Sprintf("🚫 %s игнорируется до %s", m.MatchName, formatTime(m.ExpiresAt))
+}
+
+func fetchAndSendDiffs(bot *tgsend.Bot, chatID int64, config BotConfig, limit int, status, sport string) {
+	// Show "typing..." indicator
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	if _, err := bot.Request(typing); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	page, err := config.Calculator.ValueBets(ctx, client.ValueBetsQuery{ListQuery: client.ListQuery{Limit: limit, Sport: sport}, Status: status})
+	if err != nil {
+		slog.Error("Failed to fetch value bets from calculator", "error", err)
+		sendError(bot, chatID, err)
+		return
+	}
+	valueBets := page.Items
+
+	slog.Info("Received value bets from calculator", "count", len(valueBets))
+
+	// Debug: log first value bet structure if available
+	if len(valueBets) > 0 {
+		slog.Debug("First value bet", "match_name", valueBets[0].MatchName, "bookmaker", valueBets[0].Bookmaker, "odds", valueBets[0].AllBookmakerOdds)
+	}
+
+	if len(valueBets) == 0 {
+		key := tgformat.TextNoValueBets
+		if sport == "cyber_football" {
+			key = tgformat.TextNoCyberValueBets
+		} else if status == "live" {
+			key = tgformat.TextNoLiveValueBets
+		} else if status == "upcoming" {
+			key = tgformat.TextNoUpcomingValueBets
+		}
+		msgText := config.Templates.Text(chatLanguage(config, chatID), key)
+		slog.Debug("Sending empty result message", "chat_id", chatID, "message", msgText)
+		msg := tgbotapi.NewMessage(chatID, msgText)
+		if _, sendErr := bot.Send(msg); sendErr != nil {
+			slog.Error("Failed to send empty result message", "chat_id", chatID, "error", sendErr)
+		} else {
+			slog.Debug("Successfully sent empty result message", "chat_id", chatID)
+		}
+		return
+	}
+
+	// Format and send results
+	// Telegram has a message length limit of 4096 characters
+	// Split into multiple messages if needed
+	var builder strings.Builder
+	// Use limit instead of len(valueBets) for header, but show actual count
+	actualCount := len(valueBets)
+	if actualCount > limit {
+		actualCount = limit
+	}
+	lang := chatLanguage(config, chatID)
+	header := config.Templates.Render(lang, tgformat.ValueBetsHeader, struct {
+		Count  int
+		Cyber  bool
+		Status string
+	}{actualCount, sport == "cyber_football", status})
+
+	builder.WriteString(header)
+
+	partStart := 0 // first value bet of the message being built, for its buttons
+	for i, vb := range valueBets {
+		if i >= limit {
+			break
+		}
+
+		entry := config.Templates.Render(lang, tgformat.ValueBet, struct {
+			client.ValueBet
+			N                      int
+			Cyber                  bool
+			FairProbabilityPercent float64
+		}{vb, i + 1, sport == "cyber_football", vb.FairProbability * 100})
+
+		// Check if adding this entry would exceed message limit
+		if builder.Len()+len(entry) > 4000 {
+			// Send current message and start new one
+			msg := tgbotapi.NewMessage(chatID, builder.String())
+			msg.ParseMode = tgbotapi.ModeHTML
+			if keyboard, ok := valueBetsKeyboard(valueBets, partStart, i); ok {
+				msg.ReplyMarkup = keyboard
+			}
+			if _, err := bot.Send(msg); err != nil {
+				slog.Error("Failed to send message part", "chat_id", chatID, "error", err)
+				return
+			}
+			builder.Reset()
+			builder.WriteString(header)
+			partStart = i
+		}
+
+		builder.WriteString(entry)
+	}
+
+	// Send remaining message
+	if builder.Len() > len(header) {
+		msgText := builder.String()
+		slog.Debug("Sending value bets message", "chat_id", chatID, "chars", len(msgText), "count", len(valueBets))
+		msg := tgbotapi.NewMessage(chatID, msgText)
+		msg.ParseMode = tgbotapi.ModeHTML
+		if keyboard, ok := valueBetsKeyboard(valueBets, partStart, limit); ok {
+			msg.ReplyMarkup = keyboard
+		}
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send final message", "chat_id", chatID, "error", err)
+		} else {
+			slog.Debug("Successfully sent value bets", "chat_id", chatID, "count", len(valueBets))
+		}
+	} else {
+		slog.Debug("Message builder is empty or only contains header, not sending", "chat_id", chatID)
+	}
+}
+
+func fetchAndSendLineMovements(bot *tgsend.Bot, chatID int64, config BotConfig, limit int) {
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	if _, err := bot.Request(typing); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	page, err := config.Calculator.LineMovements(ctx, client.ListQuery{Limit: limit})
+	if err != nil {
+		slog.Error("Failed to fetch line movements from calculator", "error", err)
+		sendError(bot, chatID, err)
+		return
+	}
+	movements := page.Items
+
+	if len(movements) == 0 {
+		msg := tgbotapi.NewMessage(chatID, config.Templates.Text(chatLanguage(config, chatID), tgformat.TextNoLineMovements))
+		if _, sendErr := bot.Send(msg); sendErr != nil {
+			slog.Error("Failed to send empty result message", "chat_id", chatID, "error", sendErr)
+		}
+		return
+	}
+
+	var builder strings.Builder
+	actualCount := len(movements)
+	if actualCount > limit {
+		actualCount = limit
+	}
+	rememberOverlays(chatID, movements[:actualCount])
+	lang := chatLanguage(config, chatID)
+	header := config.Templates.Render(lang, tgformat.LineMovementsHeader, struct{ Count int }{actualCount})
+	builder.WriteString(header)
+
+	for i, lm := range movements {
+		if i >= limit {
+			break
+		}
+		leagueLine := strings.TrimSpace(lm.Sport)
+		if lm.Tournament != "" {
+			if leagueLine != "" {
+				leagueLine += " • "
+			}
+			leagueLine += strings.TrimSpace(lm.Tournament)
+		}
+		entry := config.Templates.Render(lang, tgformat.LineMovement, struct {
+			client.LineMovement
+			N      int
+			League string
+		}{lm, i + 1, leagueLine})
+
+		if builder.Len()+len(entry) > 4000 {
+			msg := tgbotapi.NewMessage(chatID, builder.String())
+			msg.ParseMode = tgbotapi.ModeHTML
+			if _, err := bot.Send(msg); err != nil {
+				slog.Error("Failed to send line movements message part", "chat_id", chatID, "error", err)
+				return
+			}
+			builder.Reset()
+			builder.WriteString(header)
+		}
+		builder.WriteString(entry)
+	}
+
+	if builder.Len() > len(header) {
+		msg := tgbotapi.NewMessage(chatID, builder.String())
+		msg.ParseMode = tgbotapi.ModeHTML
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send line movements message", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+func fetchAndSendArbitrages(bot *tgsend.Bot, chatID int64, config BotConfig, limit int) {
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	if _, err := bot.Request(typing); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	arbs, err := config.Calculator.Arbitrages(ctx, limit)
+	if err != nil {
+		slog.Error("Failed to fetch arbitrages from calculator", "error", err)
+		sendError(bot, chatID, err)
+		return
+	}
+
+	if len(arbs) == 0 {
+		msg := tgbotapi.NewMessage(chatID, config.Templates.Text(chatLanguage(config, chatID), tgformat.TextNoArbs))
+		if _, sendErr := bot.Send(msg); sendErr != nil {
+			slog.Error("Failed to send empty result message", "chat_id", chatID, "error", sendErr)
+		}
+		return
+	}
+
+	var builder strings.Builder
+	actualCount := len(arbs)
+	if actualCount > limit {
+		actualCount = limit
+	}
+	lang := chatLanguage(config, chatID)
+	header := config.Templates.Render(lang, tgformat.ArbsHeader, struct{ Count int }{actualCount})
+	builder.WriteString(header)
+
+	for i, arb := range arbs {
+		if i >= limit {
+			break
+		}
+		entry := config.Templates.Render(lang, tgformat.Arb, struct {
+			client.Arbitrage
+			N int
+		}{arb, i + 1})
+
+		if builder.Len()+len(entry) > 4000 {
+			msg := tgbotapi.NewMessage(chatID, builder.String())
+			msg.ParseMode = tgbotapi.ModeHTML
+			if _, err := bot.Send(msg); err != nil {
+				slog.Error("Failed to send arbitrages message part", "chat_id", chatID, "error", err)
+				return
+			}
+			builder.Reset()
+			builder.WriteString(header)
+		}
+		builder.WriteString(entry)
+	}
+
+	if builder.Len() > len(header) {
+		msg := tgbotapi.NewMessage(chatID, builder.String())
+		msg.ParseMode = tgbotapi.ModeHTML
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send arbitrages message", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+// placeBet records a bet on a value bet: /bet <value_bet_id> <stake> [odd] (POST /bets).
+func placeBet(bot *tgsend.Bot, chatID int64, config BotConfig, args []string) {
+	reply := func(text string) {
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send bet reply", "chat_id", chatID, "error", err)
+		}
+	}
+	if len(args) < 2 {
+		reply("Usage: /bet <id> <stake> [odd]\nThe id is shown under each value bet in /top.")
+		return
+	}
+	stake, err := strconv.ParseFloat(strings.ReplaceAll(args[1], ",", "."), 64)
+	if err != nil || stake <= 0 {
+		reply("❌ Stake must be a positive number.")
+		return
+	}
+	req := client.PlaceBetRequest{ValueBetID: args[0], Stake: stake, UserID: chatID}
+	if len(args) > 2 {
+		odd, err := strconv.ParseFloat(strings.ReplaceAll(args[2], ",", "."), 64)
+		if err != nil || odd <= 1 {
+			reply("❌ Odd must be a number above 1.")
+			return
+		}
+		req.Odd = odd
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	result, err := config.Calculator.PlaceBet(ctx, req)
+	if err != nil {
+		slog.Error("Failed to record bet", "value_bet_id", req.ValueBetID, "error", err)
+		reply("❌ " + err.Error())
+		return
+	}
+	slog.Info("Bet recorded via bot", "id", result.ID, "chat_id", chatID, "match", result.MatchName)
+	reply(fmt.Sprintf("✅ Bet #%d: %s\n%s | %s %s @ %s at %s, stake %.2f (value %.1f%%)",
+		result.ID, result.MatchName, formatEventType(result.EventType), formatOutcomeType(result.OutcomeType), result.Parameter,
+		models.FormatOdds(result.Odd), result.Bookmaker, result.Stake, result.ValuePercent))
+}
+
+// fetchAndSendMyBets sends the chat's bets with P/L (GET /bets?user_id=...).
+func fetchAndSendMyBets(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := config.Calculator.Bets(ctx, chatID)
+	if err != nil {
+		slog.Error("Failed to fetch bets from calculator", "error", err)
+		sendError(bot, chatID, err)
+		return
+	}
+	if len(result.Bets) == 0 {
+		msg := tgbotapi.NewMessage(chatID, config.Templates.Text(chatLanguage(config, chatID), tgformat.TextNoBets))
+		if _, sendErr := bot.Send(msg); sendErr != nil {
+			slog.Error("Failed to send empty result message", "chat_id", chatID, "error", sendErr)
+		}
+		return
+	}
+
+	statusIcons := map[string]string{"open": "⏳", "won": "✅", "half_won": "✅½", "lost": "❌", "half_lost": "❌½", "void": "↩️"}
+	var builder strings.Builder
+	lang := chatLanguage(config, chatID)
+	header := config.Templates.Render(lang, tgformat.MyBetsHeader, result.Summary)
+	builder.WriteString(header)
+	for i, b := range result.Bets {
+		if i >= 20 {
+			break
+		}
+		entry := config.Templates.Render(lang, tgformat.MyBet, struct {
+			client.Bet
+			Icon string
+		}{b, statusIcons[b.Status]})
+		if builder.Len()+len(entry) > 4000 {
+			break
+		}
+		builder.WriteString(entry)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, builder.String())
+	msg.ParseMode = tgbotapi.ModeHTML
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send bets message", "chat_id", chatID, "error", err)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "N/A"
+	}
+	return t.Format("2006-01-02 15:04 UTC")
+}
+
+func formatEventType(eventType string) string {
+	// Convert snake_case to Title Case
+	parts := strings.Split(eventType, "_")
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatOutcomeType(outcomeType string) string {
+	// Convert snake_case to Title Case
+	parts := strings.Split(outcomeType, "_")
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func startAsyncProcessing(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	result, ok := controlAsync(bot, chatID, config, client.AsyncStart)
+	if !ok {
+		return
+	}
+	statusMsg := "✅ " + result.Message
+	if result.Status == "already_running" {
+		statusMsg = "ℹ️ " + result.Message
+	}
+	msg := tgbotapi.NewMessage(chatID, statusMsg)
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send start confirmation", "chat_id", chatID, "error", err)
+	} else {
+		slog.Info("Successfully started async processing via bot")
+	}
+}
+
+func stopAsyncProcessing(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	result, ok := controlAsync(bot, chatID, config, client.AsyncStop)
+	if !ok {
+		return
+	}
+	statusMsg := "✅ " + result.Message
+	if result.Status == "already_stopped" {
+		statusMsg = "ℹ️ " + result.Message
+	}
+	msg := tgbotapi.NewMessage(chatID, statusMsg)
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send stop confirmation", "chat_id", chatID, "error", err)
+	} else {
+		slog.Info("Successfully stopped async processing via bot")
+	}
+}
+
+// stopAlertType disables only one type of alerts (values or overlays) via calculator API.
+func stopAlertType(bot *tgsend.Bot, chatID int64, config BotConfig, alertType string, defaultMsg string) {
+	var action string
+	switch alertType {
+	case "values":
+		action = client.AsyncStopValues
+	case "overlays":
+		action = client.AsyncStopOverlays
+	default:
+		msg := tgbotapi.NewMessage(chatID, "❌ Unknown alert type.")
+		_, _ = bot.Send(msg)
+		return
+	}
+	result, ok := controlAsync(bot, chatID, config, action)
+	if !ok {
+		return
+	}
+	if result.Message == "" {
+		result.Message = defaultMsg
+	}
+	msg := tgbotapi.NewMessage(chatID, "✅ "+result.Message)
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send stop alert type confirmation", "chat_id", chatID, "error", err)
+	} else {
+		slog.Info("Stopped alert type via bot", "type", alertType)
+	}
+}
+
+// controlAsync sends an async control action to the calculator (POST /async/<action>); a failure is
+// reported to the chat.
+func controlAsync(bot *tgsend.Bot, chatID int64, config BotConfig, action string) (client.AsyncStatus, bool) {
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	if _, err := bot.Request(typing); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := config.Calculator.AsyncControl(ctx, action)
+	if err != nil {
+		slog.Error("Async control failed", "action", action, "error", err)
+		sendError(bot, chatID, err)
+		return result, false
+	}
+	return result, true
+}
+
+// sendError reports a failed calculator call to the chat.
+func sendError(bot *tgsend.Bot, chatID int64, err error) {
+	msg := tgbotapi.NewMessage(chatID, "❌ Error: "+err.Error())
+	if _, sendErr := bot.Send(msg); sendErr != nil {
+		slog.Error("Failed to send error message", "chat_id", chatID, "error", sendErr)
+	}
+}
@@ -0,0 +1,100 @@
+package telegrambot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// lastOverlays keeps the line movements of the last /overlays of each chat, so /chart <n> can refer
+// to them by their number.
+var lastOverlays = struct {
+	sync.Mutex
+	byChat map[int64][]client.LineMovement
+}{byChat: map[int64][]client.LineMovement{}}
+
+func rememberOverlays(chatID int64, movements []client.LineMovement) {
+	lastOverlays.Lock()
+	lastOverlays.byChat[chatID] = append([]client.LineMovement(nil), movements...)
+	lastOverlays.Unlock()
+}
+
+// sendLineMovementChart handles /chart <n>: the odds-over-time chart of line movement n of the chat's
+// last /overlays (calculator GET /line-movements/chart), with the match and market as the caption.
+func sendLineMovementChart(bot *tgsend.Bot, chatID int64, config BotConfig, args []string) {
+	lang := chatLanguage(config, chatID)
+	lastOverlays.Lock()
+	movements := lastOverlays.byChat[chatID]
+	lastOverlays.Unlock()
+	n := 0
+	if len(args) > 0 {
+		n, _ = strconv.Atoi(args[0])
+	}
+	if n < 1 || n > len(movements) {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextChartUsage)))
+		return
+	}
+	lm := movements[n-1]
+
+	if _, err := bot.Request(tgbotapi.NewChatAction(chatID, tgbotapi.ChatUploadPhoto)); err != nil {
+		slog.Debug("Failed to send upload indicator", "chat_id", chatID, "error", err)
+	}
+	png, err := fetchLineMovementChart(config, lm)
+	if err != nil {
+		slog.Warn("Failed to get line movement chart", "match", lm.MatchName, "bet_key", lm.BetKey, "error", err)
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ Error: "+err.Error()))
+		return
+	}
+	if png == nil {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextNoChart)))
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "chart.png", Bytes: png})
+	photo.Caption = fmt.Sprintf("<b>%s</b>\n%s — %s: %s → %s",
+		tgformat.Escape(lm.MatchName), tgformat.Market(lang, lm.EventType, lm.OutcomeType, lm.Parameter),
+		tgformat.Escape(lm.Bookmaker), models.FormatOdds(lm.PreviousOdd), models.FormatOdds(lm.CurrentOdd))
+	photo.ParseMode = tgbotapi.ModeHTML
+	if _, err := bot.Send(photo); err != nil {
+		slog.Error("Failed to send line movement chart", "chat_id", chatID, "error", err)
+	}
+}
+
+// fetchLineMovementChart returns the PNG chart of lm's bet at its bookmaker (nil without odds history).
+func fetchLineMovementChart(config BotConfig, lm client.LineMovement) ([]byte, error) {
+	q := url.Values{"match_group_key": {lm.MatchGroupKey}, "bet_key": {lm.BetKey}, "bookmaker": {lm.Bookmaker}}
+	u := strings.TrimSuffix(config.CalculatorURL, "/") + "/line-movements/chart?" + q.Encode()
+	hc := config.httpClient(30 * time.Second)
+	resp, err := hc.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	var result struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if result.Error == "" {
+		result.Error = fmt.Sprintf("status %d", resp.StatusCode)
+	}
+	return nil, fmt.Errorf("%s", result.Error)
+}
@@ -0,0 +1,124 @@
+package telegrambot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// /lang ru|en sets the language of the chat's messages. It is stored in the calculator (GET/POST
+// /chat-language), which renders the chat's alerts in it too; the bot caches it per chat.
+
+// chatLanguages caches the languages of chats ("" = not chosen, telegram.language).
+var chatLanguages = struct {
+	sync.Mutex
+	byChat map[int64]string
+}{byChat: map[int64]string{}}
+
+// chatLanguage returns the language of chatID, asking the calculator on first use. A failed request
+// is not cached, so the next message asks again.
+func chatLanguage(config BotConfig, chatID int64) string {
+	chatLanguages.Lock()
+	lang, ok := chatLanguages.byChat[chatID]
+	chatLanguages.Unlock()
+	if ok {
+		return lang
+	}
+	lang, err := fetchChatLanguage(config, chatID)
+	if err != nil {
+		slog.Warn("Failed to get chat language", "chat_id", chatID, "error", err)
+		return ""
+	}
+	chatLanguages.Lock()
+	chatLanguages.byChat[chatID] = lang
+	chatLanguages.Unlock()
+	return lang
+}
+
+// fetchChatLanguage returns the language stored for chatID in the calculator ("" = not chosen).
+func fetchChatLanguage(config BotConfig, chatID int64) (string, error) {
+	url := fmt.Sprintf("%s/chat-language?chat_id=%d", strings.TrimSuffix(config.CalculatorURL, "/"), chatID)
+	client := config.httpClient(10 * time.Second)
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("calculator returned status %d", resp.StatusCode)
+	}
+	var result struct {
+		Language string `json:"language"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.Language, nil
+}
+
+// storeChatLanguage sets the language of chatID in the calculator (POST /chat-language).
+func storeChatLanguage(config BotConfig, chatID int64, lang string) error {
+	payload, _ := json.Marshal(map[string]interface{}{"chat_id": chatID, "language": lang})
+	url := strings.TrimSuffix(config.CalculatorURL, "/") + "/chat-language"
+	client := config.httpClient(10 * time.Second)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// setChatLanguage handles /lang [ru|en]: without a supported language it shows the usage and the current one.
+func setChatLanguage(bot *tgsend.Bot, chatID int64, config BotConfig, args []string) {
+	reply := func(text string) {
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send language reply", "chat_id", chatID, "error", err)
+		}
+	}
+	current := chatLanguage(config, chatID)
+	lang, ok := "", false
+	if len(args) > 0 {
+		lang, ok = tgformat.ParseLanguage(args[0])
+	}
+	if !ok {
+		shown := current
+		if shown == "" {
+			shown = config.Templates.Language()
+		}
+		reply(config.Templates.Text(current, tgformat.TextLanguageUsage, shown))
+		return
+	}
+
+	if err := storeChatLanguage(config, chatID, lang); err != nil {
+		slog.Error("Failed to set chat language", "chat_id", chatID, "language", lang, "error", err)
+		reply(fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+	chatLanguages.Lock()
+	chatLanguages.byChat[chatID] = lang
+	chatLanguages.Unlock()
+	slog.Info("Chat language set via bot", "chat_id", chatID, "language", lang)
+	reply(config.Templates.Text(lang, tgformat.TextLanguageSet))
+}
@@ -0,0 +1,266 @@
+package telegrambot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Callback data of the bot's own inline buttons (Telegram allows at most 64 bytes): "<prefix><argument>".
+const (
+	oddsCallbackPrefix  = "odds:"  // odds:<value bet id> - odds of every bookmaker
+	muteCallbackPrefix  = "mute:"  // mute:<value bet id> - ignore the match (POST /ignores)
+	trackCallbackPrefix = "track:" // track:<value bet id> - ask for the stake, then record the bet (/bet)
+	menuCallbackPrefix  = "menu:"  // menu:<list> - limit selection; "menu:" - main menu
+	listCallbackPrefix  = "list:"  // list:<list>:<limit> - send the list
+)
+
+// trackPromptIDPrefix starts the line of a "📝 Track" prompt holding the value bet id; a reply to the prompt
+// is read as "<stake> [odd]".
+const trackPromptIDPrefix = "Value bet: "
+
+// menuList is a list of the main menu (/menu).
+type menuList struct {
+	name         string // callback argument and command name
+	label        string
+	defaultLimit int
+}
+
+var menuLists = []menuList{
+	{"top", "📊 Top", 5},
+	{"live", "🔴 Live", 5},
+	{"upcoming", "🕐 Upcoming", 5},
+	{"cyber", "🎮 Cyber", 5},
+	{"overlays", "📈 Overlays", 10},
+	{"arbs", "💱 Arbs", 5},
+}
+
+// menuLimits are the limits offered after choosing a list.
+var menuLimits = []int{5, 10, 20, 50}
+
+func findMenuList(name string) (menuList, bool) {
+	for _, l := range menuLists {
+		if l.name == name {
+			return l, true
+		}
+	}
+	return menuList{}, false
+}
+
+// mainMenuKeyboard has a button per list, three in a row.
+func mainMenuKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := 0; i < len(menuLists); i += 3 {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, l := range menuLists[i:min(i+3, len(menuLists))] {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(l.label, menuCallbackPrefix+l.name))
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// limitKeyboard offers the limits of list l and a way back to the main menu.
+func limitKeyboard(l menuList) tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, n := range menuLimits {
+		label := strconv.Itoa(n)
+		if n == l.defaultLimit {
+			label = "• " + label + " •"
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("%s%s:%d", listCallbackPrefix, l.name, n)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(row, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Back", menuCallbackPrefix),
+	))
+}
+
+// sendMainMenu sends the main menu (/menu).
+func sendMainMenu(bot *tgsend.Bot, chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, "📋 What to show?")
+	msg.ReplyMarkup = mainMenuKeyboard()
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send main menu", "chat_id", chatID, "error", err)
+	}
+}
+
+// sendList sends list name with limit, as its command does.
+func sendList(bot *tgsend.Bot, chatID int64, config BotConfig, name string, limit int) {
+	switch name {
+	case "top":
+		fetchAndSendDiffs(bot, chatID, config, limit, "", "")
+	case "live":
+		fetchAndSendDiffs(bot, chatID, config, limit, "live", "")
+	case "upcoming":
+		fetchAndSendDiffs(bot, chatID, config, limit, "upcoming", "")
+	case "cyber":
+		fetchAndSendDiffs(bot, chatID, config, limit, "", "cyber_football")
+	case "overlays":
+		fetchAndSendLineMovements(bot, chatID, config, limit)
+	case "arbs":
+		fetchAndSendArbitrages(bot, chatID, config, limit)
+	}
+}
+
+// valueBetsKeyboard has a row of buttons per value bet valueBets[from:to] (numbered from from+1):
+// more odds, mute the match and track a bet. ok is false when none of them has an id.
+func valueBetsKeyboard(valueBets []client.ValueBet, from, to int) (markup tgbotapi.InlineKeyboardMarkup, ok bool) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := from; i < to && i < len(valueBets); i++ {
+		id := valueBets[i].ID
+		if id == "" {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d 📈 Odds", i+1), oddsCallbackPrefix+id),
+			tgbotapi.NewInlineKeyboardButtonData("🔇 Mute", muteCallbackPrefix+id),
+			tgbotapi.NewInlineKeyboardButtonData("📝 Track", trackCallbackPrefix+id),
+		))
+	}
+	if len(rows) == 0 {
+		return markup, false
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// handleKeyboardCallback handles the bot's own buttons (value bets, menus and /settings) and reports whether cq was
+// one of them. answer answers the callback query.
+func handleKeyboardCallback(bot *tgsend.Bot, cq *tgbotapi.CallbackQuery, config BotConfig, answer func(string)) bool {
+	if cq.Message == nil {
+		return false
+	}
+	chatID, messageID := cq.Message.Chat.ID, cq.Message.MessageID
+	data := cq.Data
+	switch {
+	case strings.HasPrefix(data, oddsCallbackPrefix):
+		answer("")
+		sendValueBetOdds(bot, chatID, config, strings.TrimPrefix(data, oddsCallbackPrefix))
+	case strings.HasPrefix(data, muteCallbackPrefix):
+		answer(muteValueBetMatch(config, strings.TrimPrefix(data, muteCallbackPrefix), cq.From))
+	case strings.HasPrefix(data, trackCallbackPrefix):
+		answer("")
+		sendTrackPrompt(bot, chatID, strings.TrimPrefix(data, trackCallbackPrefix))
+	case strings.HasPrefix(data, menuCallbackPrefix):
+		answer("")
+		text, markup := "📋 What to show?", mainMenuKeyboard()
+		if l, ok := findMenuList(strings.TrimPrefix(data, menuCallbackPrefix)); ok {
+			text, markup = l.label+": how many?", limitKeyboard(l)
+		}
+		if _, err := bot.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, markup)); err != nil {
+			slog.Debug("Failed to update menu", "chat_id", chatID, "error", err)
+		}
+	case strings.HasPrefix(data, settingsCallbackPrefix):
+		handleSettingsCallback(bot, cq, config, answer)
+	case strings.HasPrefix(data, listCallbackPrefix):
+		name, limitStr, _ := strings.Cut(strings.TrimPrefix(data, listCallbackPrefix), ":")
+		l, ok := findMenuList(name)
+		limit, err := strconv.Atoi(limitStr)
+		if !ok || err != nil || limit <= 0 || limit > 50 {
+			answer("Unknown button.")
+			return true
+		}
+		answer("")
+		sendList(bot, chatID, config, l.name, limit)
+	default:
+		return false
+	}
+	return true
+}
+
+// fetchValueBet gets a value bet by id from the calculator (GET /value-bets/{id}/odds).
+func fetchValueBet(config BotConfig, id string) (client.ValueBet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	return config.Calculator.ValueBet(ctx, id)
+}
+
+// sendValueBetOdds sends the odds of every bookmaker for a value bet, best first ("📈 Odds" button).
+func sendValueBetOdds(bot *tgsend.Bot, chatID int64, config BotConfig, id string) {
+	vb, err := fetchValueBet(config, id)
+	if err != nil {
+		slog.Warn("Failed to fetch value bet odds", "value_bet_id", id, "error", err)
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+	_, _ = bot.Send(tgbotapi.NewMessage(chatID, formatValueBetOdds(vb)))
+}
+
+// formatValueBetOdds lists the bookmaker odds of vb, best first, with their value over the fair odd.
+func formatValueBetOdds(vb client.ValueBet) string {
+	bookmakers := make([]string, 0, len(vb.AllBookmakerOdds))
+	for bk := range vb.AllBookmakerOdds {
+		bookmakers = append(bookmakers, bk)
+	}
+	sort.Slice(bookmakers, func(i, j int) bool {
+		oi, oj := vb.AllBookmakerOdds[bookmakers[i]], vb.AllBookmakerOdds[bookmakers[j]]
+		if oi != oj {
+			return oi > oj
+		}
+		return bookmakers[i] < bookmakers[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📈 %s\n%s | %s", vb.MatchName, formatEventType(vb.EventType), formatOutcomeType(vb.OutcomeType))
+	if vb.Parameter != "" {
+		fmt.Fprintf(&b, " (%s)", vb.Parameter)
+	}
+	fmt.Fprintf(&b, "\n📊 Fair odd: %s\n\n", models.FormatOdds(vb.FairOdd))
+	for _, bk := range bookmakers {
+		odd := vb.AllBookmakerOdds[bk]
+		marker := "  "
+		if strings.EqualFold(bk, vb.Bookmaker) {
+			marker = "🎯"
+		}
+		fmt.Fprintf(&b, "%s %s: %s", marker, bk, models.FormatOdds(odd))
+		if vb.FairOdd > 0 {
+			fmt.Fprintf(&b, " (%+.1f%%)", (odd/vb.FairOdd-1)*100)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\n🕐 Start: %s", formatTime(vb.StartTime))
+	return b.String()
+}
+
+// muteValueBetMatch ignores the match of a value bet ("🔇 Mute" button) and returns the text for the
+// callback answer.
+func muteValueBetMatch(config BotConfig, id string, user *tgbotapi.User) string {
+	vb, err := fetchValueBet(config, id)
+	if err != nil {
+		slog.Warn("Failed to resolve value bet to mute", "value_bet_id", id, "error", err)
+		return "❌ " + err.Error()
+	}
+	return ignoreMatch(config, client.IgnoreRequest{MatchGroupKey: vb.MatchGroupKey, MatchName: vb.MatchName}, user)
+}
+
+// sendTrackPrompt asks for the stake of a bet on a value bet ("📝 Track" button); the reply is handled by
+// trackPromptValueBetID.
+func sendTrackPrompt(bot *tgsend.Bot, chatID int64, id string) {
+	msg := tgbotapi.NewMessage(chatID, "📝 Reply to this message with the stake and, optionally, the odd you got: 100 or 100 2.15\n"+trackPromptIDPrefix+id)
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, InputFieldPlaceholder: "100 2.15", Selective: true}
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send track prompt", "chat_id", chatID, "error", err)
+	}
+}
+
+// trackPromptValueBetID returns the value bet id of the track prompt message replies to.
+func trackPromptValueBetID(bot *tgsend.Bot, message *tgbotapi.Message) (string, bool) {
+	prompt := message.ReplyToMessage
+	if prompt == nil || prompt.From == nil || prompt.From.ID != bot.Self.ID {
+		return "", false
+	}
+	for _, line := range strings.Split(prompt.Text, "\n") {
+		if id, ok := strings.CutPrefix(line, trackPromptIDPrefix); ok && id != "" && !strings.ContainsAny(id, " `") {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,105 @@
+package telegrambot
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgformat"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	"github.com/Vodeneev/vodeneevbet/pkg/client"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// matchSearchLimit is the number of matches /match shows at most.
+const matchSearchLimit = 3
+
+// bookmakerOdd is an odd of an OddsMatrixRow for the templates, which list them best first.
+type bookmakerOdd struct {
+	Bookmaker string
+	Odd       float64
+}
+
+// sortedOdds returns the odds of the row, best first.
+func sortedOdds(r client.OddsMatrixRow) []bookmakerOdd {
+	out := make([]bookmakerOdd, 0, len(r.Odds))
+	for bk, odd := range r.Odds {
+		out = append(out, bookmakerOdd{bk, odd})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Odd != out[j].Odd {
+			return out[i].Odd > out[j].Odd
+		}
+		return out[i].Bookmaker < out[j].Bookmaker
+	})
+	return out
+}
+
+// searchMatches finds current matches by team name (GET /matches/search).
+func searchMatches(config BotConfig, query string) ([]client.OddsMatrix, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	return config.Calculator.SearchMatches(ctx, query, matchSearchLimit)
+}
+
+// sendMatchOdds handles /match <query>: one message per found match with the odds of every bookmaker for each
+// market, the best odd in bold. Long matches are split into several messages.
+func sendMatchOdds(bot *tgsend.Bot, chatID int64, config BotConfig, args []string) {
+	lang := chatLanguage(config, chatID)
+	query := strings.Join(args, " ")
+	if len([]rune(query)) < 2 {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextMatchUsage)))
+		return
+	}
+
+	typing := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	if _, err := bot.Request(typing); err != nil {
+		slog.Debug("Failed to send typing indicator", "chat_id", chatID, "error", err)
+	}
+
+	matches, err := searchMatches(config, query)
+	if err != nil {
+		slog.Warn("Failed to search matches", "query", query, "error", err)
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ Error: "+err.Error()))
+		return
+	}
+	if len(matches) == 0 {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, config.Templates.Text(lang, tgformat.TextNoMatches, query)))
+		return
+	}
+
+	send := func(text string) bool {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send match odds", "chat_id", chatID, "error", err)
+			return false
+		}
+		return true
+	}
+	for _, m := range matches {
+		header := config.Templates.Render(lang, tgformat.MatchOddsHeader, m)
+		var builder strings.Builder
+		builder.WriteString(header)
+		for _, row := range m.Rows {
+			entry := config.Templates.Render(lang, tgformat.MatchOddsRow, struct {
+				client.OddsMatrixRow
+				Odds []bookmakerOdd
+			}{row, sortedOdds(row)})
+			// Telegram has a message length limit of 4096 characters
+			if builder.Len()+len(entry) > 4000 {
+				if !send(builder.String()) {
+					return
+				}
+				builder.Reset()
+				builder.WriteString(header)
+			}
+			builder.WriteString(entry)
+		}
+		if !send(builder.String()) {
+			return
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package telegrambot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// /settings edits the chat's alert subscription in the calculator (GET/POST/DELETE /subscriptions): the
+// chat then gets its own alerts, independent of the global /stop_values and /stop_overlays.
+
+// settingsCallbackPrefix starts the callback data of /settings buttons:
+// set:enabled, set:type:<alert type>, set:ask:<field>, set:reset.
+const settingsCallbackPrefix = "set:"
+
+// settingsPromptPrefix starts the line of a settings prompt naming the field; a reply to the prompt is the new value.
+const settingsPromptPrefix = "Setting: "
+
+// Text fields of /settings asked with a prompt.
+const (
+	settingsFieldMinValue   = "min_value"
+	settingsFieldOdds       = "odds"
+	settingsFieldBookmakers = "bookmakers"
+	settingsFieldLeagues    = "leagues"
+)
+
+// settingsPrompts are the prompts of the text fields.
+var settingsPrompts = map[string]string{
+	settingsFieldMinValue:   "💰 Min value %: e.g. 7.5 (0 = calculator default)",
+	settingsFieldOdds:       "🎯 Odds range: e.g. 1.5-3 or 1.5 (min only); 0 = any",
+	settingsFieldBookmakers: "🏦 Bookmakers, comma-separated: e.g. pinnacle, fonbet; - = all",
+	settingsFieldLeagues:    "🏆 Leagues (part of the tournament name), comma-separated: e.g. premier league, la liga; - = all",
+}
+
+// alertTypeLabels are the /settings labels of the calculator's alert types, in display order.
+var alertTypeLabels = []struct{ alertType, label string }{
+	{"value", "💰 Values"},
+	{"value_closed", "🔚 Closed"},
+	{"line_movement", "📈 Overlays"},
+	{"steam", "🔥 Steam"},
+	{"digest", "🗞 Digests"},
+}
+
+// Subscription is the alert subscription of a chat (matches the calculator /subscriptions response).
+type Subscription struct {
+	ChatID                 int64               `json:"chat_id"`
+	Name                   string              `json:"name,omitempty"`
+	Enabled                bool                `json:"enabled"`
+	AlertTypes             []string            `json:"alert_types"` // empty = all
+	Filters                SubscriptionFilters `json:"filters"`
+	MinDiffPercent         float64             `json:"min_diff_percent"`
+	MinLineMovementPercent float64             `json:"min_line_movement_percent"`
+	MinLineMovementPP      float64             `json:"min_line_movement_pp"`
+}
+
+// SubscriptionFilters restrict the alerts of a subscription (matches the calculator response).
+type SubscriptionFilters struct {
+	Sports         []string `json:"sports,omitempty"`
+	Leagues        []string `json:"leagues,omitempty"`
+	Bookmakers     []string `json:"bookmakers,omitempty"`
+	MinOdds        float64  `json:"min_odds,omitempty"`
+	MaxOdds        float64  `json:"max_odds,omitempty"`
+	MaxDiffPercent float64  `json:"max_diff_percent,omitempty"`
+}
+
+// wants reports whether the subscription receives alertType.
+func (s Subscription) wants(alertType string) bool {
+	if len(s.AlertTypes) == 0 {
+		return true
+	}
+	for _, t := range s.AlertTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleAlertType switches alertType on or off. All types on is stored as none (= all); the last type
+// can't be switched off (switch alerts off instead).
+func (s *Subscription) toggleAlertType(alertType string) error {
+	var types []string
+	for _, l := range alertTypeLabels {
+		on := s.wants(l.alertType)
+		if l.alertType == alertType {
+			on = !on
+		}
+		if on {
+			types = append(types, l.alertType)
+		}
+	}
+	switch len(types) {
+	case 0:
+		return fmt.Errorf("keep at least one alert type, or switch alerts off")
+	case len(alertTypeLabels):
+		types = nil
+	}
+	s.AlertTypes = types
+	return nil
+}
+
+// applySettingsInput sets field of s from the reply to its prompt.
+func applySettingsInput(s *Subscription, field, text string) error {
+	text = strings.TrimSpace(text)
+	parseNumber := func(v string) (float64, error) {
+		return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", "."), 64)
+	}
+	parseList := func(v string) []string {
+		if v == "-" || v == "" {
+			return nil
+		}
+		var out []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	switch field {
+	case settingsFieldMinValue:
+		v, err := parseNumber(strings.TrimSuffix(text, "%"))
+		if err != nil || v < 0 {
+			return fmt.Errorf("min value must be a number, 0 or above")
+		}
+		if s.Filters.MaxDiffPercent > 0 && v > s.Filters.MaxDiffPercent {
+			return fmt.Errorf("min value is above the max value %.1f%%", s.Filters.MaxDiffPercent)
+		}
+		s.MinDiffPercent = v
+	case settingsFieldOdds:
+		bounds := strings.Fields(strings.ReplaceAll(text, "-", " "))
+		if len(bounds) == 0 || len(bounds) > 2 {
+			return fmt.Errorf("odds range must look like 1.5-3")
+		}
+		minOdds, err := parseNumber(bounds[0])
+		if err != nil || minOdds < 0 {
+			return fmt.Errorf("odds range must look like 1.5-3")
+		}
+		var maxOdds float64
+		if len(bounds) == 2 {
+			if maxOdds, err = parseNumber(bounds[1]); err != nil || maxOdds < 0 {
+				return fmt.Errorf("odds range must look like 1.5-3")
+			}
+		}
+		if maxOdds > 0 && minOdds > maxOdds {
+			return fmt.Errorf("min odd is above max odd")
+		}
+		s.Filters.MinOdds, s.Filters.MaxOdds = minOdds, maxOdds
+	case settingsFieldBookmakers:
+		s.Filters.Bookmakers = parseList(strings.ToLower(text))
+	case settingsFieldLeagues:
+		s.Filters.Leagues = parseList(text)
+	default:
+		return fmt.Errorf("unknown setting %q", field)
+	}
+	return nil
+}
+
+// fetchSubscription returns the chat's subscription, or a new one (enabled, all alert types) if it has none.
+func fetchSubscription(config BotConfig, chatID int64) (Subscription, error) {
+	url := fmt.Sprintf("%s/subscriptions?chat_id=%d", strings.TrimSuffix(config.CalculatorURL, "/"), chatID)
+	client := config.httpClient(10 * time.Second)
+	resp, err := client.Get(url)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Subscription{ChatID: chatID, Enabled: true}, nil
+	}
+	var result struct {
+		Subscription
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return Subscription{}, fmt.Errorf("%s", result.Error)
+	}
+	return result.Subscription, nil
+}
+
+// storeSubscription adds or replaces the chat's subscription (POST /subscriptions) and returns it as stored.
+func storeSubscription(config BotConfig, s Subscription) (Subscription, error) {
+	payload, _ := json.Marshal(s)
+	url := strings.TrimSuffix(config.CalculatorURL, "/") + "/subscriptions"
+	client := config.httpClient(10 * time.Second)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Subscription
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return Subscription{}, fmt.Errorf("%s", result.Error)
+	}
+	slog.Info("Subscription updated via bot", "chat_id", s.ChatID, "enabled", result.Enabled, "alert_types", result.AlertTypes)
+	return result.Subscription, nil
+}
+
+// deleteSubscription removes the chat's subscription (DELETE /subscriptions): the chat is back to the
+// calculator defaults.
+func deleteSubscription(config BotConfig, chatID int64) error {
+	url := fmt.Sprintf("%s/subscriptions?chat_id=%d", strings.TrimSuffix(config.CalculatorURL, "/"), chatID)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	client := config.httpClient(10 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to calculator service: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("calculator returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// formatSettings describes the subscription for the /settings message.
+func formatSettings(s Subscription) string {
+	all := func(list []string) string {
+		if len(list) == 0 {
+			return "all"
+		}
+		return strings.Join(list, ", ")
+	}
+	var b strings.Builder
+	b.WriteString("⚙️ Alert settings of this chat\n\n")
+	if s.Enabled {
+		b.WriteString("🔔 Alerts: on\n")
+	} else {
+		b.WriteString("🔕 Alerts: off\n")
+	}
+	if s.MinDiffPercent > 0 {
+		fmt.Fprintf(&b, "💰 Min value: %.1f%%\n", s.MinDiffPercent)
+	} else {
+		b.WriteString("💰 Min value: calculator default\n")
+	}
+	switch f := s.Filters; {
+	case f.MinOdds > 0 && f.MaxOdds > 0:
+		fmt.Fprintf(&b, "🎯 Odds: %s-%s\n", models.FormatOdds(f.MinOdds), models.FormatOdds(f.MaxOdds))
+	case f.MinOdds > 0:
+		fmt.Fprintf(&b, "🎯 Odds: from %s\n", models.FormatOdds(f.MinOdds))
+	case f.MaxOdds > 0:
+		fmt.Fprintf(&b, "🎯 Odds: up to %s\n", models.FormatOdds(f.MaxOdds))
+	default:
+		b.WriteString("🎯 Odds: any\n")
+	}
+	fmt.Fprintf(&b, "🏦 Bookmakers: %s\n", all(s.Filters.Bookmakers))
+	fmt.Fprintf(&b, "🏆 Leagues: %s\n", all(s.Filters.Leagues))
+	var types []string
+	for _, l := range alertTypeLabels {
+		if s.wants(l.alertType) {
+			types = append(types, l.label)
+		}
+	}
+	fmt.Fprintf(&b, "📬 Alert types: %s\n", strings.Join(types, ", "))
+	return b.String()
+}
+
+// settingsKeyboard has the /settings buttons for s.
+func settingsKeyboard(s Subscription) tgbotapi.InlineKeyboardMarkup {
+	enabled := "🔕 Switch alerts off"
+	if !s.Enabled {
+		enabled = "🔔 Switch alerts on"
+	}
+	var types []tgbotapi.InlineKeyboardButton
+	for _, l := range alertTypeLabels {
+		mark := "❌ "
+		if s.wants(l.alertType) {
+			mark = "✅ "
+		}
+		types = append(types, tgbotapi.NewInlineKeyboardButtonData(mark+l.label, settingsCallbackPrefix+"type:"+l.alertType))
+	}
+	ask := func(label, field string) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(label, settingsCallbackPrefix+"ask:"+field)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(enabled, settingsCallbackPrefix+"enabled")),
+		tgbotapi.NewInlineKeyboardRow(ask("💰 Min value", settingsFieldMinValue), ask("🎯 Odds", settingsFieldOdds)),
+		tgbotapi.NewInlineKeyboardRow(ask("🏦 Bookmakers", settingsFieldBookmakers), ask("🏆 Leagues", settingsFieldLeagues)),
+		types[:2],
+		types[2:],
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("♻️ Reset to defaults", settingsCallbackPrefix+"reset")),
+	)
+}
+
+// sendSettings sends the chat's settings with their buttons (/settings).
+func sendSettings(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	s, err := fetchSubscription(config, chatID)
+	if err != nil {
+		slog.Error("Failed to fetch subscription", "chat_id", chatID, "error", err)
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, formatSettings(s))
+	msg.ReplyMarkup = settingsKeyboard(s)
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send settings", "chat_id", chatID, "error", err)
+	}
+}
+
+// handleSettingsCallback handles the /settings buttons: switches are stored at once and the settings
+// message is updated, text fields are asked with a prompt.
+func handleSettingsCallback(bot *tgsend.Bot, cq *tgbotapi.CallbackQuery, config BotConfig, answer func(string)) {
+	chatID, messageID := cq.Message.Chat.ID, cq.Message.MessageID
+	action := strings.TrimPrefix(cq.Data, settingsCallbackPrefix)
+
+	if field, ok := strings.CutPrefix(action, "ask:"); ok {
+		prompt, known := settingsPrompts[field]
+		if !known {
+			answer("Unknown button.")
+			return
+		}
+		answer("")
+		msg := tgbotapi.NewMessage(chatID, prompt+"\nReply to this message with the new value.\n"+settingsPromptPrefix+field)
+		msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+		if _, err := bot.Send(msg); err != nil {
+			slog.Error("Failed to send settings prompt", "chat_id", chatID, "error", err)
+		}
+		return
+	}
+
+	if action == "reset" {
+		if err := deleteSubscription(config, chatID); err != nil {
+			answer("❌ " + err.Error())
+			return
+		}
+		answer("♻️ Settings reset")
+		s := Subscription{ChatID: chatID, Enabled: true}
+		_, _ = bot.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, formatSettings(s), settingsKeyboard(s)))
+		return
+	}
+
+	s, err := fetchSubscription(config, chatID)
+	if err != nil {
+		answer("❌ " + err.Error())
+		return
+	}
+	if s.Name == "" {
+		s.Name = cq.From.String()
+	}
+	switch {
+	case action == "enabled":
+		s.Enabled = !s.Enabled
+	case strings.HasPrefix(action, "type:"):
+		if err := s.toggleAlertType(strings.TrimPrefix(action, "type:")); err != nil {
+			answer("❌ " + err.Error())
+			return
+		}
+	default:
+		answer("Unknown button.")
+		return
+	}
+	if s, err = storeSubscription(config, s); err != nil {
+		answer("❌ " + err.Error())
+		return
+	}
+	answer("✅ Saved")
+	if _, err := bot.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, formatSettings(s), settingsKeyboard(s))); err != nil {
+		slog.Debug("Failed to update settings message", "chat_id", chatID, "error", err)
+	}
+}
+
+// settingsPromptField returns the field of the settings prompt message replies to.
+func settingsPromptField(bot *tgsend.Bot, message *tgbotapi.Message) (string, bool) {
+	prompt := message.ReplyToMessage
+	if prompt == nil || prompt.From == nil || prompt.From.ID != bot.Self.ID {
+		return "", false
+	}
+	for _, line := range strings.Split(prompt.Text, "\n") {
+		if field, ok := strings.CutPrefix(line, settingsPromptPrefix); ok {
+			if _, known := settingsPrompts[field]; known {
+				return field, true
+			}
+		}
+	}
+	return "", false
+}
+
+// updateSetting stores the reply to a settings prompt and sends the updated settings.
+func updateSetting(bot *tgsend.Bot, message *tgbotapi.Message, config BotConfig, field string) {
+	chatID := message.Chat.ID
+	s, err := fetchSubscription(config, chatID)
+	if err == nil {
+		if s.Name == "" {
+			s.Name = message.From.String()
+		}
+		err = applySettingsInput(&s, field, message.Text)
+	}
+	if err == nil {
+		s, err = storeSubscription(config, s)
+	}
+	if err != nil {
+		_, _ = bot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, "✅ Saved\n\n"+formatSettings(s))
+	msg.ReplyMarkup = settingsKeyboard(s)
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send settings", "chat_id", chatID, "error", err)
+	}
+}
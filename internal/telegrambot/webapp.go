@@ -0,0 +1,68 @@
+package telegrambot
+
+import (
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/tgsend"
+)
+
+// The bot library predates Telegram WebApps, so the web_app buttons are sent as raw JSON.
+
+type webAppInfo struct {
+	URL string `json:"url"`
+}
+
+type webAppButton struct {
+	Text   string     `json:"text"`
+	WebApp webAppInfo `json:"web_app"`
+}
+
+type webAppKeyboard struct {
+	InlineKeyboard [][]webAppButton `json:"inline_keyboard"`
+}
+
+type webAppMenuButton struct {
+	Type   string     `json:"type"`
+	Text   string     `json:"text"`
+	WebApp webAppInfo `json:"web_app"`
+}
+
+const webAppButtonText = "Валуи"
+
+// setupWebAppMenuButton sets the default menu button of the bot to open the calculator WebApp
+// (value_calculator.webapp). webAppURL must be a public https URL of /webapp/.
+func setupWebAppMenuButton(bot *tgsend.Bot, webAppURL string) {
+	params := tgbotapi.Params{}
+	if err := params.AddInterface("menu_button", webAppMenuButton{
+		Type:   "web_app",
+		Text:   webAppButtonText,
+		WebApp: webAppInfo{URL: webAppURL},
+	}); err != nil {
+		slog.Error("Failed to encode WebApp menu button", "error", err)
+		return
+	}
+	if _, err := bot.MakeRequest("setChatMenuButton", params); err != nil {
+		slog.Error("Failed to set WebApp menu button", "url", webAppURL, "error", err)
+		return
+	}
+	slog.Info("WebApp menu button set", "url", webAppURL)
+}
+
+// sendWebAppButton replies with an inline button that opens the WebApp (/app).
+func sendWebAppButton(bot *tgsend.Bot, chatID int64, config BotConfig) {
+	if config.WebAppURL == "" {
+		msg := tgbotapi.NewMessage(chatID, "WebApp не настроен (WEBAPP_URL).")
+		_, _ = bot.Send(msg)
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, "Валуи и матрицы коэффициентов с сортировкой и фильтрами:")
+	msg.ReplyMarkup = webAppKeyboard{InlineKeyboard: [][]webAppButton{{{
+		Text:   "Открыть " + webAppButtonText,
+		WebApp: webAppInfo{URL: config.WebAppURL},
+	}}}}
+	if _, err := bot.Send(msg); err != nil {
+		slog.Error("Failed to send WebApp button", "chat_id", chatID, "error", err)
+	}
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Paging headers of the calculator list endpoints (the bodies are plain JSON arrays).
+const (
+	nextCursorHeader = "X-Next-Cursor"
+	totalCountHeader = "X-Total-Count"
+)
+
+// Calculator is a client of the calculator API.
+type Calculator struct {
+	base
+}
+
+// NewCalculator returns a client of the calculator at baseURL, e.g. "http://calculator:8080".
+func NewCalculator(baseURL string, opts ...Option) *Calculator {
+	return &Calculator{base: newBase(baseURL, opts)}
+}
+
+// ListQuery pages, sorts and filters /value-bets/top and /line-movements/top. Zero fields keep the server
+// defaults.
+type ListQuery struct {
+	Limit      int    // page size, at most 50
+	Cursor     string // NextCursor of the previous page; "" = first page
+	Sort       string // value_percent, expected_value or start_time; change_percent, prob_shift_pp or start_time
+	Order      string // asc or desc
+	Sport      string
+	Bookmakers []string
+	League     string // tournament substring
+	MinOdds    float64
+	MaxOdds    float64
+}
+
+func (q ListQuery) values() url.Values {
+	v := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			v.Set(name, value)
+		}
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	set("cursor", q.Cursor)
+	set("sort", q.Sort)
+	set("order", q.Order)
+	set("sport", q.Sport)
+	set("bookmaker", strings.Join(q.Bookmakers, ","))
+	set("league", q.League)
+	if q.MinOdds != 0 {
+		v.Set("min_odds", strconv.FormatFloat(q.MinOdds, 'f', -1, 64))
+	}
+	if q.MaxOdds != 0 {
+		v.Set("max_odds", strconv.FormatFloat(q.MaxOdds, 'f', -1, 64))
+	}
+	return v
+}
+
+// ValueBetsQuery is the query of ValueBets.
+type ValueBetsQuery struct {
+	ListQuery
+	Status string // live or upcoming; "" = all
+	Method string // margin removal method; "" = value_calculator.fair_odds_method
+}
+
+// Page is one page of a list endpoint.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // ListQuery.Cursor of the next page; "" on the last one
+	Total      int    // items passing the filters, all pages
+}
+
+func newPage[T any](items []T, h http.Header) Page[T] {
+	p := Page[T]{Items: items, NextCursor: h.Get(nextCursorHeader)}
+	p.Total, _ = strconv.Atoi(h.Get(totalCountHeader))
+	return p
+}
+
+// ValueBets returns a page of the current value bets (GET /value-bets/top).
+func (c *Calculator) ValueBets(ctx context.Context, q ValueBetsQuery) (Page[ValueBet], error) {
+	v := q.values()
+	if q.Status != "" {
+		v.Set("status", q.Status)
+	}
+	if q.Method != "" {
+		v.Set("method", q.Method)
+	}
+	var items []ValueBet
+	h, err := c.do(ctx, http.MethodGet, "/value-bets/top", v, nil, &items)
+	if err != nil {
+		return Page[ValueBet]{}, err
+	}
+	return newPage(items, h), nil
+}
+
+// ValueBet returns a current or recently served value bet by id with the odds of every bookmaker
+// (GET /value-bets/{id}/odds).
+func (c *Calculator) ValueBet(ctx context.Context, id string) (ValueBet, error) {
+	var vb ValueBet
+	err := c.getJSON(ctx, "/value-bets/"+url.PathEscape(id)+"/odds", &vb)
+	return vb, err
+}
+
+// LineMovements returns a page of the current line movements (GET /line-movements/top).
+func (c *Calculator) LineMovements(ctx context.Context, q ListQuery) (Page[LineMovement], error) {
+	var items []LineMovement
+	h, err := c.do(ctx, http.MethodGet, "/line-movements/top", q.values(), nil, &items)
+	if err != nil {
+		return Page[LineMovement]{}, err
+	}
+	return newPage(items, h), nil
+}
+
+// Arbitrages returns the best current arbitrages, at most limit (GET /arbs/top; 0 = server default).
+func (c *Calculator) Arbitrages(ctx context.Context, limit int) ([]Arbitrage, error) {
+	v := url.Values{}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	var arbs []Arbitrage
+	_, err := c.do(ctx, http.MethodGet, "/arbs/top", v, nil, &arbs)
+	return arbs, err
+}
+
+// SearchMatches finds current matches by team name, with the odds of all bookmakers for every market
+// (GET /matches/search; limit 0 = server default).
+func (c *Calculator) SearchMatches(ctx context.Context, query string, limit int) ([]OddsMatrix, error) {
+	v := url.Values{"q": {query}}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	var resp struct {
+		Matches []OddsMatrix `json:"matches"`
+	}
+	_, err := c.do(ctx, http.MethodGet, "/matches/search", v, nil, &resp)
+	return resp.Matches, err
+}
+
+// PlaceBetRequest is the body of POST /bets. Odd 0 = the odd of the value bet.
+type PlaceBetRequest struct {
+	ValueBetID string  `json:"value_bet_id"`
+	Stake      float64 `json:"stake"`
+	Odd        float64 `json:"odd,omitempty"`
+	UserID     int64   `json:"user_id"`
+}
+
+// PlaceBet records a bet on a value bet (POST /bets).
+func (c *Calculator) PlaceBet(ctx context.Context, req PlaceBetRequest) (Bet, error) {
+	var bet Bet
+	_, err := c.do(ctx, http.MethodPost, "/bets", nil, req, &bet)
+	return bet, err
+}
+
+// Bets returns the bets of userID (0 = all users), newest first, with their P/L (GET /bets).
+func (c *Calculator) Bets(ctx context.Context, userID int64) (Bets, error) {
+	v := url.Values{}
+	if userID != 0 {
+		v.Set("user_id", strconv.FormatInt(userID, 10))
+	}
+	var bets Bets
+	_, err := c.do(ctx, http.MethodGet, "/bets", v, nil, &bets)
+	return bets, err
+}
+
+// IgnoreRequest is the body of POST /ignores: the token of an alert's ignore button or the match group key.
+type IgnoreRequest struct {
+	MatchGroupKey string `json:"match_group_key,omitempty"`
+	Token         string `json:"token,omitempty"`
+	MatchName     string `json:"match_name,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	TTL           string `json:"ttl,omitempty"` // duration, e.g. 12h; "" = value_calculator.ignore_ttl
+}
+
+// IgnoreMatch excludes a match from calculation and alerts (POST /ignores).
+func (c *Calculator) IgnoreMatch(ctx context.Context, req IgnoreRequest) (IgnoredMatch, error) {
+	var m IgnoredMatch
+	_, err := c.do(ctx, http.MethodPost, "/ignores", nil, req, &m)
+	return m, err
+}
+
+// Async control actions of AsyncControl.
+const (
+	AsyncStart        = "start"
+	AsyncStop         = "stop"
+	AsyncStopValues   = "stop_values"   // value bet alerts only
+	AsyncStopOverlays = "stop_overlays" // line movement alerts only
+)
+
+// AsyncControl starts or stops async processing or one type of its alerts (POST /async/<action>).
+func (c *Calculator) AsyncControl(ctx context.Context, action string) (AsyncStatus, error) {
+	var s AsyncStatus
+	_, err := c.do(ctx, http.MethodPost, "/async/"+action, nil, nil, &s)
+	return s, err
+}
+
+// ClearDB truncates the operational tables (POST /db/clear) and returns the calculator's message.
+func (c *Calculator) ClearDB(ctx context.Context) (string, error) {
+	var resp struct {
+		Message string `json:"message"`
+	}
+	_, err := c.do(ctx, http.MethodPost, "/db/clear", nil, nil, &resp)
+	return resp.Message, err
+}
+
+// GetJSON decodes the answer of GET path (with its query) into out, for the endpoints without a typed
+// method.
+func (c *Calculator) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return c.getJSON(ctx, path, out)
+}
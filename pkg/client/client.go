@@ -0,0 +1,137 @@
+// Package client is the Go client of the vodeneevbet HTTP APIs: Calculator for the calculator service and
+// Orchestrator for the parser orchestrator (and bookmaker services, which serve the same health API). The
+// response types are the ones the services encode, so clients and services can't drift apart.
+//
+//	calc := client.NewCalculator("http://calculator:8080", client.WithAPIKey(os.Getenv("API_KEY")))
+//	page, err := calc.ValueBets(ctx, client.ValueBetsQuery{ListQuery: client.ListQuery{Limit: 10, Sport: "football"}})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultTimeout bounds a request of a client without WithHTTPClient: value bets and line movements are
+// computed on request and may take tens of seconds.
+const defaultTimeout = 60 * time.Second
+
+// Option configures a Calculator or an Orchestrator.
+type Option func(*base)
+
+// WithHTTPClient sends the requests through hc (timeouts, tracing or api key transports).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(b *base) { b.http = hc }
+}
+
+// WithAPIKey sends key as X-API-Key (api_auth); "" sends none.
+func WithAPIKey(key string) Option {
+	return func(b *base) { b.apiKey = key }
+}
+
+// APIError is a non-2xx answer of a service.
+type APIError struct {
+	StatusCode int
+	Err        string // "error" of the JSON body, or the body itself
+	Message    string // "message" of the JSON body, if any
+	Details    string // "details" of the JSON body, if any
+}
+
+func (e *APIError) Error() string {
+	msg := e.Err
+	if msg == "" {
+		msg = fmt.Sprintf("status %d", e.StatusCode)
+	}
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	return msg
+}
+
+// base is the HTTP plumbing shared by the clients.
+type base struct {
+	url    string
+	http   *http.Client
+	apiKey string
+}
+
+func newBase(baseURL string, opts []Option) base {
+	b := base{url: strings.TrimSuffix(baseURL, "/"), http: &http.Client{Timeout: defaultTimeout}}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return b
+}
+
+// do sends a request with in as the JSON body (nil = none) and decodes the JSON answer into out (nil =
+// discarded). It returns the response headers for the paging of the list endpoints.
+func (b *base) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) (http.Header, error) {
+	u := b.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.apiKey != "" {
+		req.Header.Set("X-API-Key", b.apiKey)
+	}
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", b.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var e struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(data, &e) == nil && (e.Error != "" || e.Message != "") {
+			apiErr.Err, apiErr.Message, apiErr.Details = e.Error, e.Message, e.Details
+		} else {
+			apiErr.Err = strings.TrimSpace(string(data))
+		}
+		return resp.Header, apiErr
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("decode %s response: %w", path, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// Ping checks that the service is up (GET /healthz).
+func (b *base) Ping(ctx context.Context) error {
+	_, err := b.do(ctx, http.MethodGet, "/healthz", nil, nil, nil)
+	return err
+}
+
+// getJSON decodes the answer of GET path into out, for the endpoints without a typed method.
+func (b *base) getJSON(ctx context.Context, path string, out interface{}) error {
+	_, err := b.do(ctx, http.MethodGet, path, nil, nil, out)
+	return err
+}
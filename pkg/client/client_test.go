@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/health/handlers"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/interfaces"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/models"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/proxypool"
+	"github.com/Vodeneev/vodeneevbet/internal/pkg/storage"
+)
+
+func TestCalculatorValueBets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/value-bets/top" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("X-API-Key"); got != "secret" {
+			t.Errorf("X-API-Key = %q", got)
+		}
+		if got, want := r.URL.RawQuery, "bookmaker=fonbet%2Cpinnacle&cursor=abc&limit=2&min_odds=1.5&sport=football&status=upcoming"; got != want {
+			t.Errorf("query = %s, want %s", got, want)
+		}
+		w.Header().Set("X-Next-Cursor", "def")
+		w.Header().Set("X-Total-Count", "7")
+		_ = json.NewEncoder(w).Encode([]ValueBet{{ID: "a", ValuePercent: 9}, {ID: "b", ValuePercent: 8}})
+	}))
+	defer srv.Close()
+
+	c := NewCalculator(srv.URL+"/", WithAPIKey("secret"))
+	page, err := c.ValueBets(context.Background(), ValueBetsQuery{
+		ListQuery: ListQuery{Limit: 2, Cursor: "abc", Sport: "football", Bookmakers: []string{"fonbet", "pinnacle"}, MinOdds: 1.5},
+		Status:    "upcoming",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != "a" || page.NextCursor != "def" || page.Total != 7 {
+		t.Errorf("page = %+v", page)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/line-movements/top":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid order \"up\" (want asc or desc)"}`))
+		case "/stats":
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewCalculator(srv.URL).LineMovements(context.Background(), ListQuery{Order: "up"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || err.Error() != `invalid order "up" (want asc or desc)` {
+		t.Errorf("err = %v", err)
+	}
+	_, err = NewOrchestrator(srv.URL).Stats(context.Background())
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || err.Error() != "bad gateway" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestOrchestratorRestartParser(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/restart-parser" || r.URL.Query().Get("parser") != "fonbet" {
+			t.Errorf("request = %s %s", r.Method, r.URL)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"parser": "fonbet", "restarted_at": at})
+	}))
+	defer srv.Close()
+
+	got, err := NewOrchestrator(srv.URL).RestartParser(context.Background(), "fonbet")
+	if err != nil || !got.Equal(at) {
+		t.Errorf("RestartParser = %v, %v", got, err)
+	}
+}
+
+// The calculator serves these straight from storage; the client mirrors them.
+func TestStorageTypesMatch(t *testing.T) {
+	for _, tc := range []struct{ served, client interface{} }{
+		{storage.Arbitrage{}, Arbitrage{}},
+		{storage.ArbitrageLeg{}, ArbitrageLeg{}},
+		{storage.Bet{}, Bet{}},
+		{storage.IgnoredMatch{}, IgnoredMatch{}},
+	} {
+		served, client := jsonKeys(t, tc.served), jsonKeys(t, tc.client)
+		if !reflect.DeepEqual(served, client) {
+			t.Errorf("%T fields %v, client %T has %v", tc.served, served, tc.client, client)
+		}
+	}
+}
+
+// The match API and the parser health API encode these internal types; the client mirrors them.
+func TestServedTypesMatch(t *testing.T) {
+	for _, tc := range []struct{ served, client interface{} }{
+		{models.Match{}, Match{}},
+		{models.Event{}, Event{}},
+		{models.Outcome{}, Outcome{}},
+		{models.TeamMeta{}, TeamMeta{}},
+		{models.LeagueMeta{}, LeagueMeta{}},
+		{handlers.StatsResponse{}, ParserStats{}},
+		{handlers.ParserStats{}, ParserRuns{}},
+		{interfaces.ParseReport{}, ParseReport{}},
+		{handlers.ParsersResponse{}, Parsers{}},
+		{handlers.ParserStatus{}, ParserStatus{}},
+		{interfaces.CycleSummary{}, CycleSummary{}},
+		{handlers.ProxiesResponse{}, Proxies{}},
+		{proxypool.Status{}, ProxyStatus{}},
+	} {
+		served, client := jsonKeys(t, tc.served), jsonKeys(t, tc.client)
+		if !reflect.DeepEqual(served, client) {
+			t.Errorf("%T fields %v, client %T has %v", tc.served, served, tc.client, client)
+		}
+	}
+}
+
+// jsonKeys returns the JSON keys of v with every field set.
+func jsonKeys(t *testing.T, v interface{}) []string {
+	t.Helper()
+	rv := reflect.New(reflect.TypeOf(v)).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if f := rv.Field(i); f.Kind() == reflect.Ptr {
+			f.Set(reflect.New(f.Type().Elem()))
+		} else if f.Kind() == reflect.String {
+			f.SetString("x")
+		}
+	}
+	data, err := json.Marshal(rv.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
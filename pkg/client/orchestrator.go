@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Match is a match of one bookmaker with all its events (GET /matches).
+type Match struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	HomeTeam   string    `json:"home_team"`
+	AwayTeam   string    `json:"away_team"`
+	StartTime  time.Time `json:"start_time"`
+	Sport      string    `json:"sport"`
+	Tournament string    `json:"tournament"`
+	Bookmaker  string    `json:"bookmaker"`
+	Events     []Event   `json:"events"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Stale      bool      `json:"stale,omitempty"` // served from the startup snapshot, not yet refreshed by a parsing cycle
+	URL        string    `json:"url,omitempty"`   // event page at the bookmaker, "" if unknown
+
+	HomeMeta   *TeamMeta   `json:"home_meta,omitempty"`
+	AwayMeta   *TeamMeta   `json:"away_meta,omitempty"`
+	LeagueMeta *LeagueMeta `json:"league_meta,omitempty"`
+}
+
+// Event is one event type of a match (main_match, corners, yellow_cards, ...).
+type Event struct {
+	ID         string    `json:"id"`
+	MatchID    string    `json:"match_id"`
+	EventType  string    `json:"event_type"`
+	MarketName string    `json:"market_name"`
+	Bookmaker  string    `json:"bookmaker"`
+	Outcomes   []Outcome `json:"outcomes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Outcome is one betting outcome of an event.
+type Outcome struct {
+	ID          string    `json:"id"`
+	EventID     string    `json:"event_id"`
+	OutcomeType string    `json:"outcome_type"`          // e.g. total_over, home_win
+	Parameter   string    `json:"parameter"`             // e.g. 2.5, +1.5
+	Participant string    `json:"participant,omitempty"` // team or player of a team/player market
+	Odds        float64   `json:"odds"`
+	Bookmaker   string    `json:"bookmaker"`
+	URL         string    `json:"url,omitempty"` // page of the outcome when the match has none
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ParserStats is the answer of GET /stats.
+type ParserStats struct {
+	Parsers   []ParserRuns `json:"parsers"`
+	Count     int          `json:"count"`
+	StartedAt time.Time    `json:"started_at"` // when the service process started
+	Uptime    string       `json:"uptime"`
+}
+
+// ParserRuns is the parse runs of one parser since the service started.
+type ParserRuns struct {
+	Parser        string      `json:"parser"`
+	Runs          int         `json:"runs"`
+	PartialRuns   int         `json:"partial_runs"`   // some leagues failed
+	FailedRuns    int         `json:"failed_runs"`    // nothing parsed
+	LeaguesFailed int         `json:"leagues_failed"` // over all runs
+	MatchesAdded  int         `json:"matches_added"`  // over all runs
+	LastSuccess   *time.Time  `json:"last_success,omitempty"`
+	LastReport    ParseReport `json:"last_report"`
+
+	Quarantined       int            `json:"quarantined"`
+	QuarantineReasons map[string]int `json:"quarantine_reasons,omitempty"`
+}
+
+// ParseReport is the result of one parse run.
+type ParseReport struct {
+	Parser        string    `json:"parser"`
+	Status        string    `json:"status"` // ok, partial or failed; "" before the first run
+	Started       time.Time `json:"started"`
+	Finished      time.Time `json:"finished"`
+	Duration      string    `json:"duration"`
+	MatchesAdded  int       `json:"matches_added"`
+	LeaguesFailed int       `json:"leagues_failed"`
+	Errors        []string  `json:"errors,omitempty"`
+
+	LeaguesFetched int    `json:"leagues_fetched"`
+	HTTPRequests   int    `json:"http_requests"`
+	HTTPErrors     int    `json:"http_errors"`
+	AvgLatency     string `json:"avg_latency,omitempty"`
+	Proxy          string `json:"proxy,omitempty"` // proxy host of most requests, "direct" without one
+}
+
+// Parsers is the answer of GET /parsers.
+type Parsers struct {
+	Parsers []ParserStatus `json:"parsers"`
+	Count   int            `json:"count"`
+}
+
+// ParserStatus is the pause state and the last cycle of one parser.
+type ParserStatus struct {
+	Name        string        `json:"name"`
+	Incremental bool          `json:"incremental"`
+	Paused      bool          `json:"paused"`
+	LastCycle   *CycleSummary `json:"last_cycle,omitempty"` // nil before the first cycle
+}
+
+// CycleSummary is the last parsing cycle of a parser.
+type CycleSummary struct {
+	CycleID   int64     `json:"cycle_id"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Duration  string    `json:"duration"`
+	Matches   int       `json:"matches"`
+	Errors    int       `json:"errors"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Proxies is the answer of GET /proxies.
+type Proxies struct {
+	Proxies []ProxyStatus `json:"proxies"`
+	Count   int           `json:"count"`
+	Banned  int           `json:"banned"`
+}
+
+// ProxyStatus is the statistics of one proxy of the pool.
+type ProxyStatus struct {
+	Proxy        string    `json:"proxy"` // masked URL
+	Pools        []string  `json:"pools"`
+	Successes    int64     `json:"successes"`
+	Failures     int64     `json:"failures"`
+	SuccessRate  float64   `json:"success_rate"`
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	Banned       bool      `json:"banned"`
+	BannedUntil  time.Time `json:"banned_until,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastUsed     time.Time `json:"last_used,omitempty"`
+}
+
+// Orchestrator is a client of the parser orchestrator API. A bookmaker service serves the same API for
+// its own parsers.
+type Orchestrator struct {
+	base
+}
+
+// NewOrchestrator returns a client of the parser orchestrator (or bookmaker service) at baseURL,
+// e.g. "http://parser:8080".
+func NewOrchestrator(baseURL string, opts ...Option) *Orchestrator {
+	return &Orchestrator{base: newBase(baseURL, opts)}
+}
+
+// Matches returns the current matches of every bookmaker (GET /matches).
+func (o *Orchestrator) Matches(ctx context.Context) ([]Match, error) {
+	var resp struct {
+		Matches []Match `json:"matches"`
+	}
+	err := o.getJSON(ctx, "/matches", &resp)
+	return resp.Matches, err
+}
+
+// Stats returns the uptime and the parse runs of every parser (GET /stats).
+func (o *Orchestrator) Stats(ctx context.Context) (ParserStats, error) {
+	var s ParserStats
+	err := o.getJSON(ctx, "/stats", &s)
+	return s, err
+}
+
+// Parsers returns the pause state and the last cycle of every parser (GET /parsers).
+func (o *Orchestrator) Parsers(ctx context.Context) (Parsers, error) {
+	var p Parsers
+	err := o.getJSON(ctx, "/parsers", &p)
+	return p, err
+}
+
+// Proxies returns the proxy pool of the service (GET /proxies).
+func (o *Orchestrator) Proxies(ctx context.Context) (Proxies, error) {
+	var p Proxies
+	err := o.getJSON(ctx, "/proxies", &p)
+	return p, err
+}
+
+// RestartParser restarts a parser of the service and returns when it was restarted
+// (POST /admin/restart-parser).
+func (o *Orchestrator) RestartParser(ctx context.Context, name string) (time.Time, error) {
+	var resp struct {
+		RestartedAt time.Time `json:"restarted_at"`
+	}
+	_, err := o.do(ctx, http.MethodPost, "/admin/restart-parser", url.Values{"parser": {name}}, nil, &resp)
+	return resp.RestartedAt, err
+}
+
+// GetJSON decodes the answer of GET path (with its query) into out, for the endpoints without a typed
+// method.
+func (o *Orchestrator) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return o.getJSON(ctx, path, out)
+}
@@ -0,0 +1,199 @@
+package client
+
+import "time"
+
+// ValueBet represents a value bet found using weighted average of reference bookmakers
+// (GET /value-bets/top, /value-bets/{id}/odds).
+type ValueBet struct {
+	ID            string    `json:"id"` // stable id of (match, bet, bookmaker) to record a bet on it (POST /bets)
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	Tournament    string    `json:"tournament,omitempty"`
+
+	EventType   string `json:"event_type"`            // e.g. main_match, corners
+	OutcomeType string `json:"outcome_type"`          // e.g. total_over, home_win
+	Parameter   string `json:"parameter"`             // e.g. 2.5, +1.5
+	Participant string `json:"participant,omitempty"` // team or player of a team/player market: "home", "away", "c ronaldo"
+	BetKey      string `json:"bet_key"`               // eventType|outcomeType|parameter[|qualifiers], see models.BetKey
+
+	// Reference data (средневзвешенное от всех контор)
+	AllBookmakerOdds map[string]float64 `json:"all_bookmaker_odds"` // все коэффициенты от всех контор для этого исхода
+	FairOdd          float64            `json:"fair_odd"`           // справедливый коэффициент (1 / avg_probability)
+	FairProbability  float64            `json:"fair_probability"`   // справедливая вероятность (средневзвешенная)
+
+	// Value bet data
+	Bookmaker     string  `json:"bookmaker"`               // контора с валуем
+	BookmakerURL  string  `json:"bookmaker_url,omitempty"` // страница события в конторе ("" — неизвестна)
+	BookmakerOdd  float64 `json:"bookmaker_odd"`           // её коэффициент
+	ValuePercent  float64 `json:"value_percent"`           // процент валуя: (bookmaker_odd / fair_odd - 1) * 100
+	ExpectedValue float64 `json:"expected_value"`          // математическое ожидание: (bookmaker_odd * fair_probability) - 1
+
+	// Recommended stakes in percent of bankroll (value_calculator.staking)
+	KellyPercent           float64 `json:"kelly_percent"`            // полный Келли: expected_value / (bookmaker_odd - 1) * 100
+	FractionalKellyPercent float64 `json:"fractional_kelly_percent"` // доля Келли (kelly_fraction), с ограничением max_stake_percent
+	FlatStakePercent       float64 `json:"flat_stake_percent"`       // фиксированная ставка (flat_percent)
+
+	CalculatedAt time.Time `json:"calculated_at"`
+
+	// Team/league metadata (logo, country) from the match API; nil if unknown
+	HomeMeta   *TeamMeta   `json:"home_meta,omitempty"`
+	AwayMeta   *TeamMeta   `json:"away_meta,omitempty"`
+	LeagueMeta *LeagueMeta `json:"league_meta,omitempty"`
+}
+
+// TeamMeta is static metadata of a team (logo, country) attached to matches by the match API.
+type TeamMeta struct {
+	Name    string `json:"name"` // canonical name from the dataset
+	Country string `json:"country,omitempty"`
+	League  string `json:"league,omitempty"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
+
+// LeagueMeta is static metadata of a league/tournament attached to matches by the match API.
+type LeagueMeta struct {
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
+
+// LineMovement represents a significant odds change in the same bookmaker for the same bet
+// (GET /line-movements/top).
+type LineMovement struct {
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	Tournament    string    `json:"tournament"` // league/championship name for identification (e.g. when match is "Home vs Away")
+
+	EventType     string    `json:"event_type"`
+	OutcomeType   string    `json:"outcome_type"`
+	Parameter     string    `json:"parameter"`
+	BetKey        string    `json:"bet_key"`
+	Bookmaker     string    `json:"bookmaker"`
+	BookmakerURL  string    `json:"bookmaker_url,omitempty"` // event page at the bookmaker, "" if unknown
+	PreviousOdd   float64   `json:"previous_odd"`
+	CurrentOdd    float64   `json:"current_odd"`
+	ChangeAbs     float64   `json:"change_abs"`     // current - previous (signed)
+	ChangePercent float64   `json:"change_percent"` // (current - previous) / previous * 100
+	ProbShiftPP   float64   `json:"prob_shift_pp"`  // implied probability change in percentage points: (1/current - 1/previous) * 100
+	RecordedAt    time.Time `json:"recorded_at"`
+}
+
+// OddsMatrix is the odds of all bookmakers for every bet of one match (GET /matches/search, Telegram WebApp).
+type OddsMatrix struct {
+	MatchGroupKey string          `json:"match_group_key"`
+	MatchName     string          `json:"match_name"`
+	StartTime     time.Time       `json:"start_time"`
+	Sport         string          `json:"sport"`
+	Tournament    string          `json:"tournament"`
+	Bookmakers    []string        `json:"bookmakers"` // matrix columns, sorted
+	Rows          []OddsMatrixRow `json:"rows"`
+
+	HomeMeta   *TeamMeta   `json:"home_meta,omitempty"`
+	AwayMeta   *TeamMeta   `json:"away_meta,omitempty"`
+	LeagueMeta *LeagueMeta `json:"league_meta,omitempty"`
+}
+
+// OddsMatrixRow is one bet of OddsMatrix: odds by bookmaker and the best one.
+type OddsMatrixRow struct {
+	BetKey        string             `json:"bet_key"`
+	EventType     string             `json:"event_type"`
+	OutcomeType   string             `json:"outcome_type"`
+	Parameter     string             `json:"parameter"`
+	Participant   string             `json:"participant,omitempty"` // team or player of a team/player market
+	Odds          map[string]float64 `json:"odds"`                  // bookmaker -> best odd
+	BestBookmaker string             `json:"best_bookmaker"`
+	BestOdd       float64            `json:"best_odd"`
+}
+
+// ArbitrageLeg is one outcome of an arbitrage: StakePercent of the total stake goes on it at Bookmaker.
+type ArbitrageLeg struct {
+	OutcomeType  string  `json:"outcome_type"`
+	Parameter    string  `json:"parameter"`
+	BetKey       string  `json:"bet_key"`
+	Bookmaker    string  `json:"bookmaker"`
+	Odd          float64 `json:"odd"`
+	StakePercent float64 `json:"stake_percent"`
+}
+
+// Arbitrage (surebet) is a set of complementary outcomes of one market whose best odds across bookmakers
+// return ProfitPercent whichever outcome wins (GET /arbs/top).
+type Arbitrage struct {
+	MatchGroupKey string         `json:"match_group_key"`
+	MatchName     string         `json:"match_name"`
+	StartTime     time.Time      `json:"start_time"`
+	Sport         string         `json:"sport"`
+	EventType     string         `json:"event_type"` // e.g. main_match, corners
+	MarketKey     string         `json:"market_key"` // eventType|market|line, e.g. main_match|1x2|, corners|total|9.5
+	Legs          []ArbitrageLeg `json:"legs"`
+	ImpliedSum    float64        `json:"implied_sum"`    // sum of 1/odd over legs (< 1)
+	ProfitPercent float64        `json:"profit_percent"` // (1/implied_sum - 1) * 100
+	CalculatedAt  time.Time      `json:"calculated_at"`
+}
+
+// Bet is a bet placed on a value bet (POST /bets), settled once the match result is known.
+type Bet struct {
+	ID         int64  `json:"id"`
+	ValueBetID string `json:"value_bet_id"`
+	UserID     int64  `json:"user_id"` // Telegram chat ID of the bettor; 0 = not bound to a user
+
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name"`
+	HomeTeam      string    `json:"home_team"`
+	AwayTeam      string    `json:"away_team"`
+	StartTime     time.Time `json:"start_time"`
+	Sport         string    `json:"sport"`
+	EventType     string    `json:"event_type"`
+	OutcomeType   string    `json:"outcome_type"`
+	Parameter     string    `json:"parameter"`
+	BetKey        string    `json:"bet_key"`
+
+	Bookmaker       string  `json:"bookmaker"`
+	Odd             float64 `json:"odd"`
+	Stake           float64 `json:"stake"`
+	FairProbability float64 `json:"fair_probability"` // at placement
+	ValuePercent    float64 `json:"value_percent"`    // at placement
+
+	Status    string     `json:"status"` // open, won, half_won, lost, half_lost or void
+	Profit    float64    `json:"profit"` // P/L in stake units once settled (0 while open)
+	HomeScore *int       `json:"home_score,omitempty"`
+	AwayScore *int       `json:"away_score,omitempty"`
+	PlacedAt  time.Time  `json:"placed_at"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// BetsSummary is the P/L of a list of bets.
+type BetsSummary struct {
+	Count      int     `json:"count"`
+	Open       int     `json:"open"`
+	Won        int     `json:"won"`
+	Lost       int     `json:"lost"`
+	Void       int     `json:"void"`
+	Staked     float64 `json:"staked"` // settled bets only
+	Profit     float64 `json:"profit"`
+	ROIPercent float64 `json:"roi_percent"`
+}
+
+// Bets is the answer of GET /bets.
+type Bets struct {
+	Bets    []Bet       `json:"bets"`
+	Summary BetsSummary `json:"summary"`
+}
+
+// IgnoredMatch is a match excluded from calculation until ExpiresAt (POST /ignores).
+type IgnoredMatch struct {
+	MatchGroupKey string    `json:"match_group_key"`
+	MatchName     string    `json:"match_name,omitempty"`
+	Reason        string    `json:"reason"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AsyncStatus is the answer of the async controls (POST /async/start, /async/stop, /async/stop_values,
+// /async/stop_overlays).
+type AsyncStatus struct {
+	Status  string `json:"status"` // e.g. started, already_running, stopped, already_stopped
+	Message string `json:"message"`
+}
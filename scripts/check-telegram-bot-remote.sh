@@ -26,7 +26,7 @@ sudo docker exec "${CONTAINER}" env | grep -E "TELEGRAM_BOT_TOKEN|CALCULATOR_URL
 echo ""
 
 echo "5. Calculator connectivity test:"
-sudo docker exec "${CONTAINER}" wget -q --spider --timeout=3 http://calculator:8080/health 2>&1 && echo "✓ Calculator reachable" || echo "✗ Calculator NOT reachable"
+sudo docker exec "${CONTAINER}" wget -q --spider --timeout=3 http://calculator:8080/healthz 2>&1 && echo "✓ Calculator reachable" || echo "✗ Calculator NOT reachable"
 echo ""
 
 echo "=== Done ==="
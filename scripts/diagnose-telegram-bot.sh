@@ -33,7 +33,7 @@ sudo docker inspect "${CONTAINER_NAME}" 2>/dev/null | grep -A 10 "Env" || echo "
 echo ""
 
 echo "5. Checking if calculator service is accessible from bot container..."
-if sudo docker exec "${CONTAINER_NAME}" wget -q --spider --timeout=5 http://calculator:8080/health 2>/dev/null; then
+if sudo docker exec "${CONTAINER_NAME}" wget -q --spider --timeout=5 http://calculator:8080/healthz 2>/dev/null; then
     echo "✓ Calculator service is accessible"
 else
     echo "✗ Calculator service is NOT accessible"
@@ -47,7 +47,7 @@ CALC_CONTAINER="vodeneevbet-calculator"
 if sudo docker ps -q -f "name=${CALC_CONTAINER}" -f "status=running" | grep -q .; then
     echo "✓ Calculator container is running"
     echo "Calculator health check:"
-    sudo docker exec "${CALC_CONTAINER}" wget -q -O- http://localhost:8080/health 2>&1 || echo "Health check failed"
+    sudo docker exec "${CALC_CONTAINER}" wget -q -O- http://localhost:8080/health/dependencies 2>&1 || echo "Health check failed"
 else
     echo "✗ Calculator container is NOT running"
 fi